@@ -0,0 +1,287 @@
+// An accessor for acquired memory.
+
+// The memory accessor presents the address space stored in a memory
+// image as a sparse file, so it can be scanned with yara() or read
+// with read_file() just like the process accessor. Minidumps are
+// mapped by virtual address. Other files are treated as raw physical
+// memory images. When reading /dev/mem only the System RAM ranges
+// from /proc/iomem are mapped.
+
+package memory
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/accessors/zip"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// A range of the address space and where its data is in the image.
+type Region struct {
+	Start      uint64
+	Size       uint64
+	FileOffset int64
+}
+
+type AddressSpace struct {
+	mu     sync.Mutex
+	offset int64
+
+	handle  accessors.ReadSeekCloser
+	reader  io.ReaderAt
+	regions []*Region
+
+	path *accessors.OSPath
+}
+
+func NewAddressSpace(handle accessors.ReadSeekCloser,
+	regions []*Region) *AddressSpace {
+	return &AddressSpace{
+		handle:  handle,
+		reader:  utils.MakeReaderAtter(handle),
+		regions: regions,
+	}
+}
+
+// Find the region containing the offset or the next region after it.
+func (self *AddressSpace) findRegion(offset uint64) (*Region, *Region) {
+	for _, region := range self.regions {
+		if offset < region.Start {
+			return nil, region
+		}
+		if offset-region.Start < region.Size {
+			return region, nil
+		}
+	}
+	return nil, nil
+}
+
+// Like the process accessor, reads from unmapped memory return
+// zeros.
+func (self *AddressSpace) Read(buf []byte) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	current, next := self.findRegion(uint64(self.offset))
+	if current != nil {
+		to_read := current.Start + current.Size - uint64(self.offset)
+		if to_read > uint64(len(buf)) {
+			to_read = uint64(len(buf))
+		}
+
+		file_offset := current.FileOffset +
+			int64(uint64(self.offset)-current.Start)
+		n, _ := self.reader.ReadAt(buf[:to_read], file_offset)
+		for i := n; i < int(to_read); i++ {
+			buf[i] = 0
+		}
+
+		self.offset += int64(to_read)
+		return int(to_read), nil
+	}
+
+	if next != nil {
+		to_read := next.Start - uint64(self.offset)
+		if to_read > uint64(len(buf)) {
+			to_read = uint64(len(buf))
+		}
+
+		for i := range buf[:to_read] {
+			buf[i] = 0
+		}
+		self.offset += int64(to_read)
+		return int(to_read), nil
+	}
+
+	return 0, io.EOF
+}
+
+func (self *AddressSpace) Seek(offset int64, whence int) (int64, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	switch whence {
+	case 0:
+		self.offset = offset
+	case 1:
+		self.offset += offset
+	case 2:
+		self.offset = self.size()
+	}
+
+	return self.offset, nil
+}
+
+func (self *AddressSpace) size() int64 {
+	if len(self.regions) == 0 {
+		return 0
+	}
+	last := self.regions[len(self.regions)-1]
+	return int64(last.Start + last.Size)
+}
+
+func (self *AddressSpace) Ranges() []uploads.Range {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := []uploads.Range{}
+	size := int64(0)
+	for _, region := range self.regions {
+		start := int64(region.Start)
+		if start > size {
+			result = append(result, uploads.Range{
+				Offset:   size,
+				Length:   start - size,
+				IsSparse: true,
+			})
+		}
+
+		result = append(result, uploads.Range{
+			Offset: start,
+			Length: int64(region.Size),
+		})
+		size = start + int64(region.Size)
+	}
+	return result
+}
+
+func (self *AddressSpace) Close() error {
+	return self.handle.Close()
+}
+
+func (self *AddressSpace) LStat() (accessors.FileInfo, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return &accessors.VirtualFileInfo{
+		Path:  self.path,
+		Size_: self.size(),
+	}, nil
+}
+
+// Lines look like "00100000-bffdffff : System RAM". Nested resources
+// are indented and are ignored.
+var iomemRegex = regexp.MustCompile(
+	`^([0-9a-fA-F]+)-([0-9a-fA-F]+) : System RAM$`)
+
+func ParseIOMem(reader io.Reader) []*Region {
+	result := []*Region{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		match := iomemRegex.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		start, err := strconv.ParseUint(match[1], 16, 64)
+		if err != nil {
+			continue
+		}
+
+		end, err := strconv.ParseUint(match[2], 16, 64)
+		if err != nil || end < start {
+			continue
+		}
+
+		result = append(result, &Region{
+			Start:      start,
+			Size:       end - start + 1,
+			FileOffset: int64(start),
+		})
+	}
+	return result
+}
+
+// Work out the memory regions in the image.
+func getRegions(delegate *accessors.OSPath,
+	reader io.ReaderAt, size int64) ([]*Region, error) {
+	minidump, err := ParseMinidump(reader)
+	if err == nil {
+		return minidump.Regions, nil
+	}
+
+	if delegate.String() == "/dev/mem" {
+		fd, err := os.Open("/proc/iomem")
+		if err != nil {
+			return nil, err
+		}
+		defer fd.Close()
+
+		return ParseIOMem(fd), nil
+	}
+
+	return []*Region{{Size: uint64(size)}}, nil
+}
+
+func GetAddressSpace(full_path *accessors.OSPath, scope vfilter.Scope) (
+	zip.ReaderStat, error) {
+	pathspec := full_path.PathSpec()
+
+	// If no delegate is given the path is the image.
+	if pathspec.DelegateAccessor == "" && pathspec.GetDelegatePath() == "" {
+		pathspec.DelegatePath = pathspec.Path
+		pathspec.DelegateAccessor = "auto"
+	}
+
+	err := vql_subsystem.CheckFilesystemAccess(scope, pathspec.DelegateAccessor)
+	if err != nil {
+		return nil, err
+	}
+
+	accessor, err := accessors.GetAccessor(pathspec.DelegateAccessor, scope)
+	if err != nil {
+		scope.Log("%v: did you provide a URL or PathSpec?", err)
+		return nil, err
+	}
+
+	delegate, err := accessor.ParsePath(pathspec.GetDelegatePath())
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := accessor.OpenWithOSPath(delegate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Devices can not be stat'ed
+	size := int64(0)
+	stat, err := accessor.LstatWithOSPath(delegate)
+	if err == nil {
+		size = stat.Size()
+	}
+
+	result := NewAddressSpace(fd, nil)
+	result.path = full_path
+	result.regions, err = getRegions(delegate, result.reader, size)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func init() {
+	accessors.Register("memory", zip.NewGzipFileSystemAccessor(
+		accessors.MustNewPathspecOSPath(""), GetAddressSpace),
+		`Read the address space stored in a memory image.
+
+Minidumps are mapped by virtual address and other files are treated as raw physical memory images. Unmapped memory reads as zeros.
+
+For Example
+
+SELECT * FROM yara(
+   accessor="memory", rules=Rules,
+   files=pathspec(DelegateAccessor="fs", DelegatePath=DumpPath))
+`)
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (self nopCloser) Close() error {
+	return nil
+}
+
+// Build a minimal full memory minidump with a single module, a
+// single thread and two memory regions.
+func buildMinidump() []byte {
+	le := binary.LittleEndian
+	buf := make([]byte, 0x400)
+
+	// Header
+	le.PutUint32(buf, MINIDUMP_SIGNATURE)
+	le.PutUint32(buf[8:], 5)     // Number of streams
+	le.PutUint32(buf[12:], 0x20) // Stream directory
+
+	stream := func(i int, stream_type, size, rva uint32) {
+		entry := buf[0x20+i*12:]
+		le.PutUint32(entry, stream_type)
+		le.PutUint32(entry[4:], size)
+		le.PutUint32(entry[8:], rva)
+	}
+	stream(0, moduleListStream, 4+minidumpModuleSize, 0x100)
+	stream(1, threadListStream, 4+minidumpThreadSize, 0x180)
+	stream(2, memory64ListStream, 16+2*16, 0x1c0)
+	stream(3, systemInfoStream, 56, 0x200)
+	stream(4, miscInfoStream, 24, 0x240)
+
+	// Module list
+	le.PutUint32(buf[0x100:], 1)
+	module := buf[0x104:]
+	le.PutUint64(module, 0x400000)
+	le.PutUint32(module[8:], 0x2000)
+	le.PutUint32(module[12:], 0x1234)
+	le.PutUint32(module[16:], 1600000000)
+	le.PutUint32(module[20:], 0x280)
+
+	// Module name
+	name := utf16.Encode([]rune(`C:\Windows\notepad.exe`))
+	le.PutUint32(buf[0x280:], uint32(len(name)*2))
+	for i, c := range name {
+		le.PutUint16(buf[0x284+i*2:], c)
+	}
+
+	// Thread list
+	le.PutUint32(buf[0x180:], 1)
+	le.PutUint32(buf[0x184:], 42)
+	le.PutUint64(buf[0x184+16:], 0x7ffde000)
+
+	// Memory64 list - the data follows at 0x300.
+	le.PutUint64(buf[0x1c0:], 2)
+	le.PutUint64(buf[0x1c8:], 0x300)
+	le.PutUint64(buf[0x1d0:], 0x1000)
+	le.PutUint64(buf[0x1d8:], 0x10)
+	le.PutUint64(buf[0x1e0:], 0x1020)
+	le.PutUint64(buf[0x1e8:], 0x10)
+	copy(buf[0x300:], "first region....second region...")
+
+	// System info: amd64, Windows 10.0.19041
+	le.PutUint16(buf[0x200:], 9)
+	le.PutUint32(buf[0x208:], 10)
+	le.PutUint32(buf[0x20c:], 0)
+	le.PutUint32(buf[0x210:], 19041)
+
+	// Misc info
+	le.PutUint32(buf[0x240:], 24)
+	le.PutUint32(buf[0x244:], miscInfoProcessId|miscInfoProcessTimes)
+	le.PutUint32(buf[0x248:], 1234)
+	le.PutUint32(buf[0x24c:], 1600000000)
+
+	return buf
+}
+
+func TestMinidump(t *testing.T) {
+	dump, err := ParseMinidump(bytes.NewReader(buildMinidump()))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(1234), dump.ProcessId)
+	assert.Equal(t, int64(1600000000), dump.ProcessCreateTime.Unix())
+	assert.Equal(t, "amd64", dump.Architecture)
+	assert.Equal(t, "10.0.19041", dump.OSVersion)
+	assert.Equal(t, `C:\Windows\notepad.exe`, dump.ImagePath())
+
+	require.Equal(t, 1, len(dump.Modules))
+	assert.Equal(t, uint64(0x400000), dump.Modules[0].BaseAddress)
+	assert.Equal(t, uint32(0x2000), dump.Modules[0].Size)
+	assert.Equal(t, uint32(0x1234), dump.Modules[0].Checksum)
+
+	require.Equal(t, 1, len(dump.Threads))
+	assert.Equal(t, uint32(42), dump.Threads[0].ThreadId)
+	assert.Equal(t, uint64(0x7ffde000), dump.Threads[0].Teb)
+
+	require.Equal(t, 2, len(dump.Regions))
+	assert.Equal(t, int64(0x310), dump.Regions[1].FileOffset)
+
+	_, err = ParseMinidump(bytes.NewReader(make([]byte, 100)))
+	assert.Equal(t, NotMinidumpError, err)
+}
+
+func TestAddressSpace(t *testing.T) {
+	data := buildMinidump()
+	dump, err := ParseMinidump(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	space := NewAddressSpace(
+		nopCloser{bytes.NewReader(data)}, dump.Regions)
+
+	// Read across the gap between the regions.
+	_, err = space.Seek(0x1008, io.SeekStart)
+	require.NoError(t, err)
+
+	buf := make([]byte, 0x20)
+	_, err = io.ReadFull(space, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "gion....\x00\x00\x00\x00\x00\x00\x00\x00"+
+		"\x00\x00\x00\x00\x00\x00\x00\x00second r", string(buf))
+
+	ranges := space.Ranges()
+	require.Equal(t, 4, len(ranges))
+	assert.True(t, ranges[0].IsSparse)
+	assert.Equal(t, int64(0x1000), ranges[1].Offset)
+	assert.True(t, ranges[2].IsSparse)
+	assert.Equal(t, int64(0x1020), ranges[3].Offset)
+
+	// Reading past the last region is EOF.
+	_, err = space.Seek(0x1030, io.SeekStart)
+	require.NoError(t, err)
+	_, err = space.Read(buf)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestIOMem(t *testing.T) {
+	regions := ParseIOMem(strings.NewReader(`00000000-00000fff : Reserved
+00001000-0009fbff : System RAM
+000a0000-000bffff : PCI Bus 0000:00
+00100000-bffdffff : System RAM
+  01000000-01e0366f : Kernel code
+`))
+
+	require.Equal(t, 2, len(regions))
+	assert.Equal(t, uint64(0x1000), regions[0].Start)
+	assert.Equal(t, uint64(0x9ec00), regions[0].Size)
+	assert.Equal(t, int64(0x100000), regions[1].FileOffset)
+}
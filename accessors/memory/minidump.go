@@ -0,0 +1,336 @@
+package memory
+
+// A parser for Windows minidump files (as written by
+// MiniDumpWriteDump(), procdump etc). A minidump holds the memory of
+// a single process together with the list of loaded modules and
+// threads. See
+// https://learn.microsoft.com/en-us/windows/win32/api/minidumpapiset/
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	MINIDUMP_SIGNATURE = 0x504d444d // MDMP
+
+	threadListStream   = 3
+	moduleListStream   = 4
+	memoryListStream   = 5
+	systemInfoStream   = 7
+	memory64ListStream = 9
+	miscInfoStream     = 15
+
+	minidumpModuleSize = 108
+	minidumpThreadSize = 48
+
+	// Bounds on the number of items we read from corrupt files.
+	maxStreams = 1000
+	maxItems   = 1000000
+
+	miscInfoProcessId    = 0x1
+	miscInfoProcessTimes = 0x2
+)
+
+var (
+	NotMinidumpError = errors.New("Not a minidump")
+)
+
+type MinidumpModule struct {
+	Name          string
+	BaseAddress   uint64
+	Size          uint32
+	Checksum      uint32
+	TimeDateStamp time.Time
+}
+
+type MinidumpThread struct {
+	ThreadId      uint32
+	SuspendCount  uint32
+	PriorityClass uint32
+	Priority      uint32
+	Teb           uint64
+}
+
+type Minidump struct {
+	reader io.ReaderAt
+
+	// Only set when the dump contains a MiscInfo stream.
+	ProcessId         uint32
+	ProcessCreateTime time.Time
+
+	Architecture string
+	OSVersion    string
+
+	Modules []*MinidumpModule
+	Threads []*MinidumpThread
+
+	// Memory regions sorted by address.
+	Regions []*Region
+}
+
+// The process image is the first module in the module list.
+func (self *Minidump) ImagePath() string {
+	if len(self.Modules) == 0 {
+		return ""
+	}
+	return self.Modules[0].Name
+}
+
+func ParseMinidump(reader io.ReaderAt) (*Minidump, error) {
+	header := make([]byte, 32)
+	_, err := reader.ReadAt(header, 0)
+	if err != nil {
+		return nil, NotMinidumpError
+	}
+
+	if binary.LittleEndian.Uint32(header) != MINIDUMP_SIGNATURE {
+		return nil, NotMinidumpError
+	}
+
+	count := binary.LittleEndian.Uint32(header[8:])
+	directory_rva := binary.LittleEndian.Uint32(header[12:])
+	if count > maxStreams {
+		return nil, fmt.Errorf("Minidump: too many streams (%v)", count)
+	}
+
+	directory := make([]byte, 12*count)
+	_, err = reader.ReadAt(directory, int64(directory_rva))
+	if err != nil {
+		return nil, fmt.Errorf("Minidump: reading stream directory: %w", err)
+	}
+
+	result := &Minidump{reader: reader}
+	for i := uint32(0); i < count; i++ {
+		entry := directory[i*12:]
+		stream_type := binary.LittleEndian.Uint32(entry)
+		size := binary.LittleEndian.Uint32(entry[4:])
+		rva := int64(binary.LittleEndian.Uint32(entry[8:]))
+
+		switch stream_type {
+		case moduleListStream:
+			err = result.parseModules(rva)
+		case threadListStream:
+			err = result.parseThreads(rva)
+		case memoryListStream:
+			err = result.parseMemoryList(rva)
+		case memory64ListStream:
+			err = result.parseMemory64List(rva)
+		case systemInfoStream:
+			err = result.parseSystemInfo(rva)
+		case miscInfoStream:
+			err = result.parseMiscInfo(rva, size)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(result.Regions, func(i, j int) bool {
+		return result.Regions[i].Start < result.Regions[j].Start
+	})
+
+	return result, nil
+}
+
+func (self *Minidump) read(offset int64, size int) ([]byte, error) {
+	buf := make([]byte, size)
+	n, err := self.reader.ReadAt(buf, offset)
+	if n < size {
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("Minidump: reading %v bytes at %#x: %w",
+			size, offset, err)
+	}
+	return buf, nil
+}
+
+// Read a counted list of fixed size items.
+func (self *Minidump) readList(rva int64, count_size, item_size int) (
+	[]byte, int, error) {
+	header, err := self.read(rva, count_size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count := uint64(binary.LittleEndian.Uint32(header))
+	if count_size == 8 {
+		count = binary.LittleEndian.Uint64(header)
+	}
+
+	if count > maxItems {
+		return nil, 0, fmt.Errorf("Minidump: too many items (%v)", count)
+	}
+
+	data, err := self.read(rva+int64(count_size), int(count)*item_size)
+	return data, int(count), err
+}
+
+// MINIDUMP_STRING is a 32 bit length in bytes followed by UTF16.
+func (self *Minidump) readString(rva int64) string {
+	header, err := self.read(rva, 4)
+	if err != nil {
+		return ""
+	}
+
+	length := binary.LittleEndian.Uint32(header)
+	if length > 0x10000 {
+		return ""
+	}
+
+	data, err := self.read(rva+4, int(length))
+	if err != nil {
+		return ""
+	}
+
+	runes := make([]uint16, 0, length/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		runes = append(runes, binary.LittleEndian.Uint16(data[i:]))
+	}
+	return string(utf16.Decode(runes))
+}
+
+func (self *Minidump) parseModules(rva int64) error {
+	data, count, err := self.readList(rva, 4, minidumpModuleSize)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		item := data[i*minidumpModuleSize:]
+		self.Modules = append(self.Modules, &MinidumpModule{
+			BaseAddress: binary.LittleEndian.Uint64(item),
+			Size:        binary.LittleEndian.Uint32(item[8:]),
+			Checksum:    binary.LittleEndian.Uint32(item[12:]),
+			TimeDateStamp: time.Unix(
+				int64(binary.LittleEndian.Uint32(item[16:])), 0).UTC(),
+			Name: self.readString(
+				int64(binary.LittleEndian.Uint32(item[20:]))),
+		})
+	}
+	return nil
+}
+
+func (self *Minidump) parseThreads(rva int64) error {
+	data, count, err := self.readList(rva, 4, minidumpThreadSize)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		item := data[i*minidumpThreadSize:]
+		self.Threads = append(self.Threads, &MinidumpThread{
+			ThreadId:      binary.LittleEndian.Uint32(item),
+			SuspendCount:  binary.LittleEndian.Uint32(item[4:]),
+			PriorityClass: binary.LittleEndian.Uint32(item[8:]),
+			Priority:      binary.LittleEndian.Uint32(item[12:]),
+			Teb:           binary.LittleEndian.Uint64(item[16:]),
+		})
+	}
+	return nil
+}
+
+// Minidumps without full memory store a list of descriptors, each
+// pointing at its own data.
+func (self *Minidump) parseMemoryList(rva int64) error {
+	data, count, err := self.readList(rva, 4, 16)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		item := data[i*16:]
+		self.Regions = append(self.Regions, &Region{
+			Start:      binary.LittleEndian.Uint64(item),
+			Size:       uint64(binary.LittleEndian.Uint32(item[8:])),
+			FileOffset: int64(binary.LittleEndian.Uint32(item[12:])),
+		})
+	}
+	return nil
+}
+
+// Full memory dumps store the data of all regions consecutively
+// starting at a base offset.
+func (self *Minidump) parseMemory64List(rva int64) error {
+	header, err := self.read(rva, 16)
+	if err != nil {
+		return err
+	}
+
+	count := binary.LittleEndian.Uint64(header)
+	offset := int64(binary.LittleEndian.Uint64(header[8:]))
+	if count > maxItems {
+		return fmt.Errorf("Minidump: too many memory ranges (%v)", count)
+	}
+
+	data, err := self.read(rva+16, int(count)*16)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < int(count); i++ {
+		item := data[i*16:]
+		region := &Region{
+			Start:      binary.LittleEndian.Uint64(item),
+			Size:       binary.LittleEndian.Uint64(item[8:]),
+			FileOffset: offset,
+		}
+		self.Regions = append(self.Regions, region)
+		offset += int64(region.Size)
+	}
+	return nil
+}
+
+func (self *Minidump) parseSystemInfo(rva int64) error {
+	data, err := self.read(rva, 24)
+	if err != nil {
+		return err
+	}
+
+	switch binary.LittleEndian.Uint16(data) {
+	case 0:
+		self.Architecture = "x86"
+	case 5:
+		self.Architecture = "arm"
+	case 9:
+		self.Architecture = "amd64"
+	case 12:
+		self.Architecture = "arm64"
+	default:
+		self.Architecture = "unknown"
+	}
+
+	self.OSVersion = fmt.Sprintf("%d.%d.%d",
+		binary.LittleEndian.Uint32(data[8:]),
+		binary.LittleEndian.Uint32(data[12:]),
+		binary.LittleEndian.Uint32(data[16:]))
+	return nil
+}
+
+func (self *Minidump) parseMiscInfo(rva int64, size uint32) error {
+	if size < 24 {
+		return nil
+	}
+
+	data, err := self.read(rva, 24)
+	if err != nil {
+		return err
+	}
+
+	flags := binary.LittleEndian.Uint32(data[4:])
+	if flags&miscInfoProcessId != 0 {
+		self.ProcessId = binary.LittleEndian.Uint32(data[8:])
+	}
+
+	if flags&miscInfoProcessTimes != 0 {
+		self.ProcessCreateTime = time.Unix(
+			int64(binary.LittleEndian.Uint32(data[12:])), 0).UTC()
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package process
+
+import (
+	"strings"
+)
+
+// A memory region of a process.
+type Region struct {
+	Address uint64
+	Size    uint64
+
+	// One of private, image or mapped.
+	Type string
+
+	// Permissions in the form "rwx" with "-" for missing ones.
+	Protection  string
+	MappingName string
+}
+
+// Selects the regions worth scanning. Empty fields match all
+// regions.
+type RegionFilter struct {
+	Types []string
+
+	// All these permissions must be present (e.g. "x" or "rw").
+	Protection string
+
+	MinSize uint64
+	MaxSize uint64
+}
+
+func (self RegionFilter) Matches(region *Region) bool {
+	// Unreadable regions can never be scanned.
+	if !strings.Contains(region.Protection, "r") {
+		return false
+	}
+
+	if len(self.Types) > 0 {
+		matched := false
+		for _, t := range self.Types {
+			if strings.EqualFold(t, region.Type) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for _, p := range strings.ToLower(self.Protection) {
+		if !strings.ContainsRune(region.Protection, p) {
+			return false
+		}
+	}
+
+	if region.Size < self.MinSize {
+		return false
+	}
+
+	if self.MaxSize > 0 && region.Size > self.MaxSize {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,70 @@
+// +build linux
+
+package process
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Parse the regions from /proc/<pid>/maps. File backed executable
+// regions are reported as images.
+func GetRegions(pid uint64) ([]*Region, error) {
+	fd, err := os.Open(fmt.Sprintf("/proc/%d/maps", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	var result []*Region
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		// 00400000-00452000 r-xp 00000000 08:02 173521 /usr/bin/dbus
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || len(fields[1]) < 4 {
+			continue
+		}
+
+		addresses := strings.SplitN(fields[0], "-", 2)
+		if len(addresses) != 2 {
+			continue
+		}
+
+		start, err := strconv.ParseUint(addresses[0], 16, 64)
+		if err != nil {
+			continue
+		}
+
+		end, err := strconv.ParseUint(addresses[1], 16, 64)
+		if err != nil || end < start {
+			continue
+		}
+
+		perms := fields[1]
+		mapping_name := strings.Join(fields[5:], " ")
+
+		region_type := "private"
+		if perms[3] == 's' {
+			region_type = "mapped"
+		} else if strings.HasPrefix(mapping_name, "/") {
+			region_type = "mapped"
+			if perms[2] == 'x' {
+				region_type = "image"
+			}
+		}
+
+		result = append(result, &Region{
+			Address:     start,
+			Size:        end - start,
+			Type:        region_type,
+			Protection:  perms[:3],
+			MappingName: mapping_name,
+		})
+	}
+
+	return result, scanner.Err()
+}
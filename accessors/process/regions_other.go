@@ -0,0 +1,12 @@
+// +build !linux
+// +build !windows !amd64 !cgo
+
+package process
+
+import (
+	"errors"
+)
+
+func GetRegions(pid uint64) ([]*Region, error) {
+	return nil, errors.New("Process regions are not supported on this platform")
+}
@@ -0,0 +1,45 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegionFilter(t *testing.T) {
+	regions := []*Region{
+		{Address: 0x1000, Size: 0x1000, Type: "private", Protection: "rwx"},
+		{Address: 0x2000, Size: 0x10000, Type: "image", Protection: "r-x"},
+		{Address: 0x20000, Size: 0x1000, Type: "mapped", Protection: "r--"},
+		{Address: 0x30000, Size: 0x1000, Type: "private", Protection: "---"},
+	}
+
+	matching := func(filter RegionFilter) []uint64 {
+		result := []uint64{}
+		for _, region := range regions {
+			if filter.Matches(region) {
+				result = append(result, region.Address)
+			}
+		}
+		return result
+	}
+
+	// Unreadable regions are never scanned.
+	assert.Equal(t, []uint64{0x1000, 0x2000, 0x20000},
+		matching(RegionFilter{}))
+
+	assert.Equal(t, []uint64{0x1000, 0x20000},
+		matching(RegionFilter{Types: []string{"Private", "mapped"}}))
+
+	assert.Equal(t, []uint64{0x1000, 0x2000},
+		matching(RegionFilter{Protection: "x"}))
+
+	assert.Equal(t, []uint64{0x1000},
+		matching(RegionFilter{Protection: "WX"}))
+
+	assert.Equal(t, []uint64{0x2000},
+		matching(RegionFilter{MinSize: 0x2000}))
+
+	assert.Equal(t, []uint64{0x1000, 0x20000},
+		matching(RegionFilter{MaxSize: 0x1000}))
+}
@@ -0,0 +1,63 @@
+// +build windows,amd64,cgo
+
+package process
+
+import (
+	"www.velocidex.com/golang/velociraptor/vql/windows"
+	"www.velocidex.com/golang/velociraptor/vql/windows/process"
+)
+
+// Query the regions with VirtualQueryEx. Only committed regions are
+// returned.
+func GetRegions(pid uint64) ([]*Region, error) {
+	vads, handle, err := process.GetVads(uint32(pid))
+	if err != nil {
+		return nil, err
+	}
+	windows.CloseHandle(handle)
+
+	result := make([]*Region, 0, len(vads))
+	for _, vad := range vads {
+		if vad.State != "MEM_COMMIT" {
+			continue
+		}
+
+		region_type := ""
+		switch vad.Type {
+		case "MEM_IMAGE":
+			region_type = "image"
+		case "MEM_MAPPED":
+			region_type = "mapped"
+		case "MEM_PRIVATE":
+			region_type = "private"
+		}
+
+		result = append(result, &Region{
+			Address:     vad.Address,
+			Size:        vad.Size,
+			Type:        region_type,
+			Protection:  getProtection(vad.ProtectionRaw),
+			MappingName: vad.MappingName,
+		})
+	}
+
+	return result, nil
+}
+
+func getProtection(p uint32) string {
+	switch {
+	case p&windows.PAGE_EXECUTE_READWRITE > 0,
+		p&windows.PAGE_EXECUTE_WRITECOPY > 0:
+		return "rwx"
+	case p&windows.PAGE_EXECUTE_READ > 0:
+		return "r-x"
+	case p&windows.PAGE_EXECUTE > 0:
+		return "--x"
+	case p&windows.PAGE_READWRITE > 0, p&windows.PAGE_WRITECOPY > 0:
+		return "rw-"
+	case p&windows.PAGE_READONLY > 0:
+		return "r--"
+	default:
+		return "---"
+	}
+}
@@ -114,6 +114,22 @@ const (
 	// Allowed raw datastore access
 	DATASTORE_ACCESS
 
+	// Allowed to kill processes on endpoints using the response
+	// console.
+	RESPONSE_KILL_PROCESS
+
+	// Allowed to delete files on endpoints using the response
+	// console.
+	RESPONSE_DELETE_FILE
+
+	// Allowed to block executables by hash on endpoints using the
+	// response console.
+	RESPONSE_BLOCK_HASH
+
+	// Allowed to disable user accounts on endpoints using the
+	// response console.
+	RESPONSE_DISABLE_USER
+
 	// When adding new permission - update CheckAccess,
 	// GetRolePermissions and acl.proto
 )
@@ -160,6 +176,14 @@ func (self ACL_PERMISSION) String() string {
 		return "PREPARE_RESULTS"
 	case DATASTORE_ACCESS:
 		return "DATASTORE_ACCESS"
+	case RESPONSE_KILL_PROCESS:
+		return "RESPONSE_KILL_PROCESS"
+	case RESPONSE_DELETE_FILE:
+		return "RESPONSE_DELETE_FILE"
+	case RESPONSE_BLOCK_HASH:
+		return "RESPONSE_BLOCK_HASH"
+	case RESPONSE_DISABLE_USER:
+		return "RESPONSE_DISABLE_USER"
 
 	}
 	return fmt.Sprintf("%d", self)
@@ -208,6 +232,14 @@ func GetPermission(name string) ACL_PERMISSION {
 		return PREPARE_RESULTS
 	case "DATASTORE_ACCESS":
 		return DATASTORE_ACCESS
+	case "RESPONSE_KILL_PROCESS":
+		return RESPONSE_KILL_PROCESS
+	case "RESPONSE_DELETE_FILE":
+		return RESPONSE_DELETE_FILE
+	case "RESPONSE_BLOCK_HASH":
+		return RESPONSE_BLOCK_HASH
+	case "RESPONSE_DISABLE_USER":
+		return RESPONSE_DISABLE_USER
 
 	}
 	return NO_PERMISSIONS
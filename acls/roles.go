@@ -32,6 +32,10 @@ var (
 		"MACHINE_STATE",
 		"PREPARE_RESULTS",
 		"DATASTORE_ACCESS",
+		"RESPONSE_KILL_PROCESS",
+		"RESPONSE_DELETE_FILE",
+		"RESPONSE_BLOCK_HASH",
+		"RESPONSE_DISABLE_USER",
 	}
 )
 
@@ -100,6 +104,22 @@ func DescribePermissions(token *acl_proto.ApiClientACL) []string {
 		result = append(result, "DATASTORE_ACCESS")
 	}
 
+	if token.ResponseKillProcess {
+		result = append(result, "RESPONSE_KILL_PROCESS")
+	}
+
+	if token.ResponseDeleteFile {
+		result = append(result, "RESPONSE_DELETE_FILE")
+	}
+
+	if token.ResponseBlockHash {
+		result = append(result, "RESPONSE_BLOCK_HASH")
+	}
+
+	if token.ResponseDisableUser {
+		result = append(result, "RESPONSE_DISABLE_USER")
+	}
+
 	return result
 }
 
@@ -143,6 +163,14 @@ func SetTokenPermission(
 			token.PrepareResults = true
 		case "DATASTORE_ACCESS":
 			token.DatastoreAccess = true
+		case "RESPONSE_KILL_PROCESS":
+			token.ResponseKillProcess = true
+		case "RESPONSE_DELETE_FILE":
+			token.ResponseDeleteFile = true
+		case "RESPONSE_BLOCK_HASH":
+			token.ResponseBlockHash = true
+		case "RESPONSE_DISABLE_USER":
+			token.ResponseDisableUser = true
 
 		default:
 			return errors.New("Unknown permission")
@@ -180,6 +208,10 @@ func GetRolePermissions(
 			result.FilesystemWrite = true
 			result.MachineState = true
 			result.PrepareResults = true
+			result.ResponseKillProcess = true
+			result.ResponseDeleteFile = true
+			result.ResponseBlockHash = true
+			result.ResponseDisableUser = true
 
 			// An administrator for the root org is allowed to
 			// manipulate orgs.
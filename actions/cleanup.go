@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/responder"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	// Maximum time the cleanup queries may run for.
+	cleanupTimeout = 60 * time.Second
+)
+
+// Run the artifact's cleanup queries after the collection was
+// cancelled or timed out. Artifacts may leave debris behind when
+// they are interrupted (e.g. tool copies in the temp directory,
+// started services or scheduled tasks), so they can declare queries
+// to remove it. The collection's context and scope are already gone
+// at this point so we run in a fresh scope with only the artifact
+// parameters.
+func runCleanup(
+	config_obj *config_proto.Config,
+	responder *responder.Responder,
+	manager services.RepositoryManager,
+	builder services.ScopeBuilder,
+	queries []*actions_proto.VQLRequest) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	// Make sure the status of the cleanup reaches the server
+	// even though the collection is already cancelled.
+	defer responder.Close(ctx)
+
+	scope := manager.BuildScope(builder)
+	defer scope.Close()
+
+	responder.Log(ctx, logging.DEFAULT, fmt.Sprintf(
+		"Collection was interrupted: running %v cleanup queries.",
+		len(queries)))
+
+	start := time.Now()
+	for _, query := range queries {
+		vql, err := vfilter.Parse(query.VQL)
+		if err != nil {
+			responder.Log(ctx, logging.ERROR,
+				fmt.Sprintf("Cleanup failed: %v", err))
+			return
+		}
+
+		for row := range vql.Eval(ctx, scope) {
+			responder.Log(ctx, logging.DEFAULT,
+				fmt.Sprintf("Cleanup: %v", json.MustMarshalString(row)))
+		}
+
+		if ctx.Err() != nil {
+			responder.Log(ctx, logging.ERROR, fmt.Sprintf(
+				"Cleanup timed out after %v", time.Since(start)))
+			return
+		}
+	}
+
+	responder.Log(ctx, logging.DEFAULT, fmt.Sprintf(
+		"Cleanup completed after %v", time.Since(start)))
+}
@@ -0,0 +1,48 @@
+/*
+  Report a lightweight load score to the server at check in so hunts
+  collecting heavy artifacts can be deferred on busy clients.
+*/
+
+package actions
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+var (
+	load_mu sync.Mutex
+
+	// The last computed load and when it was computed.
+	last_load      uint64
+	last_load_time time.Time
+
+	// The load is only recomputed this often.
+	load_interval = 10 * time.Second
+)
+
+// The total CPU utilization of the system in percent since the last
+// time the load was computed. This is cheap enough to call on every
+// check in.
+func CurrentLoad() uint64 {
+	load_mu.Lock()
+	defer load_mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(last_load_time) < load_interval {
+		return last_load
+	}
+
+	// An interval of 0 compares with the previous call without
+	// blocking.
+	percent, err := cpu.Percent(0, false)
+	if err != nil || len(percent) == 0 {
+		return last_load
+	}
+
+	last_load = uint64(percent[0])
+	last_load_time = now
+	return last_load
+}
@@ -0,0 +1,37 @@
+package actions
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+)
+
+// Results are signed with the same key the client enrolled with so
+// the server can attribute them to the client's key pair.
+func getSigningKey(config_obj *config_proto.Config) (*rsa.PrivateKey, error) {
+	writeback, err := config.GetWriteback(config_obj.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto_utils.ParseRsaPrivateKeyFromPemStr(
+		[]byte(writeback.PrivateKey))
+}
+
+func signResponse(
+	key *rsa.PrivateKey, response *actions_proto.VQLResponse) error {
+	sum := sha256.Sum256([]byte(response.JSONLResponse))
+	response.Sha256 = hex.EncodeToString(sum[:])
+
+	signature, err := crypto_utils.SignSha256(key, response.Sha256)
+	if err != nil {
+		return err
+	}
+	response.Signature = signature
+	return nil
+}
@@ -0,0 +1,76 @@
+package actions
+
+import (
+	"net/url"
+	"strings"
+
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+// Tools served by the server are downloaded from its public
+// directory. When Client.fetch_tools_via_frontend is set, the client
+// downloads them from the frontend it connects to instead (usually a
+// gateway which caches them). The tool's hash is added to the URL so
+// caches fetch new versions of the tool.
+func rewriteToolUrls(config_obj *config_proto.Config,
+	env []*actions_proto.VQLEnv) []*actions_proto.VQLEnv {
+	if config_obj.Client == nil || !config_obj.Client.FetchToolsViaFrontend {
+		return env
+	}
+
+	// Server pushed URLs take precedence over the client config.
+	frontends := config_obj.Client.ServerUrls
+	writeback, err := config.GetWriteback(config_obj.Client)
+	if err == nil && len(writeback.ServerUrls) > 0 {
+		frontends = writeback.ServerUrls
+	}
+
+	if len(frontends) == 0 {
+		return env
+	}
+
+	hashes := make(map[string]string)
+	for _, item := range env {
+		if strings.HasPrefix(item.Key, "Tool_") &&
+			strings.HasSuffix(item.Key, "_HASH") {
+			hashes[strings.TrimSuffix(item.Key, "_HASH")] = item.Value
+		}
+	}
+
+	result := make([]*actions_proto.VQLEnv, 0, len(env))
+	for _, item := range env {
+		if strings.HasPrefix(item.Key, "Tool_") &&
+			strings.HasSuffix(item.Key, "_URL") {
+			name := strings.TrimSuffix(item.Key, "_URL")
+			item = &actions_proto.VQLEnv{
+				Key: item.Key,
+				Value: rewriteToolUrl(
+					frontends[0], item.Value, hashes[name]),
+			}
+		}
+		result = append(result, item)
+	}
+
+	return result
+}
+
+func rewriteToolUrl(frontend, tool_url, hash string) string {
+	parsed, err := url.Parse(tool_url)
+	if err != nil {
+		return tool_url
+	}
+
+	// Only tools served from the server's public directory.
+	idx := strings.Index(parsed.Path, "/public/")
+	if idx < 0 {
+		return tool_url
+	}
+
+	result := frontend + parsed.Path[idx+1:]
+	if hash != "" {
+		result += "?sha256=" + url.QueryEscape(hash)
+	}
+	return result
+}
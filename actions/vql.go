@@ -25,6 +25,7 @@ import (
 	"os"
 	"runtime"
 	"runtime/debug"
+	"sort"
 	"strings"
 	"time"
 
@@ -135,6 +136,16 @@ func (self VQLClientAction) StartQuery(
 		Responder: responder,
 	}
 
+	// The server asked us to sign the results and uploads.
+	if arg.SignResults {
+		uploader.SigningKey, err = getSigningKey(config_obj)
+		if err != nil {
+			responder.RaiseError(ctx, fmt.Sprintf(
+				"Unable to load signing key: %v", err))
+			return
+		}
+	}
+
 	builder := services.ScopeBuilder{
 		Config: &config_proto.Config{
 			Remappings: config_obj.Remappings,
@@ -150,13 +161,21 @@ func (self VQLClientAction) StartQuery(
 		Logger:     log.New(&LogWriter{config_obj, responder, ctx}, "", 0),
 	}
 
-	for _, env_spec := range arg.Env {
+	for _, env_spec := range rewriteToolUrls(config_obj, arg.Env) {
 		builder.Env.Set(env_spec.Key, env_spec.Value)
 	}
 
 	scope := manager.BuildScope(builder)
 	defer scope.Close()
 
+	// If the collection is cancelled or times out give the artifact
+	// a chance to clean up after itself.
+	defer func() {
+		if sub_ctx.Err() != nil && len(arg.Cleanup) > 0 {
+			runCleanup(config_obj, responder, manager, builder, arg.Cleanup)
+		}
+	}()
+
 	if runtime.GOARCH == "386" &&
 		os.Getenv("PROCESSOR_ARCHITEW6432") == "AMD64" {
 		scope.Log("You are running a 32 bit built binary on Windows x64. " +
@@ -177,6 +196,16 @@ func (self VQLClientAction) StartQuery(
 	}
 	scope.SetThrottler(throttler)
 
+	// Abort the query if it holds too much memory.
+	var budget *vql_subsystem.MemoryBudget
+	if arg.MaxMemory > 0 {
+		budget = vql_subsystem.NewMemoryBudget(arg.MaxMemory, func(err error) {
+			scope.Log("ERROR:%v", err)
+			cancel()
+		})
+		vql_subsystem.InstallMemoryBudget(scope, budget)
+	}
+
 	start := time.Now()
 
 	// If we panic we need to recover and report this to the
@@ -220,8 +249,9 @@ func (self VQLClientAction) StartQuery(
 			return
 		}
 
+		column_types := vql_subsystem.NewColumnTypeTracker()
 		result_chan := EncodeIntoResponsePackets(
-			vql, sub_ctx, scope,
+			vql, sub_ctx, scope, column_types,
 			int(max_row),
 			int(max_wait))
 	run_query:
@@ -269,6 +299,7 @@ func (self VQLClientAction) StartQuery(
 					TotalRows:     uint64(result.TotalRows),
 					QueryStartRow: row_tracker.GetStartRow(query),
 					Timestamp:     uint64(time.Now().UTC().UnixNano() / 1000),
+					Types:         encodeColumnTypes(column_types),
 				}
 
 				row_tracker.AddRows(query, uint64(result.TotalRows))
@@ -287,10 +318,23 @@ func (self VQLClientAction) StartQuery(
 						))
 				}
 				response.Columns = result.Columns
+
+				if uploader.SigningKey != nil {
+					err := signResponse(uploader.SigningKey, response)
+					if err != nil {
+						scope.Log("ERROR:Unable to sign response: %v", err)
+					}
+				}
+
 				responder.AddResponse(ctx, &crypto_proto.VeloMessage{
 					VQLResponse: response})
 			}
 		}
+
+		if budget != nil && budget.Err() != nil {
+			responder.RaiseError(ctx, budget.Err().Error())
+			return
+		}
 	}
 
 	if uploader.Count > 0 {
@@ -324,10 +368,32 @@ func CheckPreconditions(
 	return false, nil
 }
 
+// Convert the column types seen so far for sending to the server.
+func encodeColumnTypes(
+	column_types *vql_subsystem.ColumnTypeTracker) []*actions_proto.VQLTypeMap {
+	var result []*actions_proto.VQLTypeMap
+	for column, column_type := range column_types.Types() {
+		if column_type == "" {
+			continue
+		}
+		result = append(result, &actions_proto.VQLTypeMap{
+			Column: column,
+			Type:   column_type,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Column < result[j].Column
+	})
+	return result
+}
+
 func EncodeIntoResponsePackets(
 	vql *vfilter.VQL,
 	ctx context.Context,
 	scope types.Scope,
+	// Records the types of the columns as rows are encoded.
+	column_types *vql_subsystem.ColumnTypeTracker,
 	maxrows int,
 	// Max time to wait before returning some results.
 	max_wait int) <-chan *vfilter.VFilterJsonResult {
@@ -393,6 +459,10 @@ func EncodeIntoResponsePackets(
 					columns = value.Keys()
 				}
 
+				if column_types != nil {
+					column_types.Observe(value)
+				}
+
 				// Encode the row into bytes ASAP so we can reclaim
 				// memory.
 				s, err := encoder([]types.Row{value})
@@ -1,6 +1,7 @@
 package actions_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/alecthomas/assert"
@@ -83,6 +84,42 @@ func (self *ClientVQLTestSuite) TestDependentArtifacts() {
 	assert.Equal(self.T(), "{\"X\":1,\"_Source\":\"Custom.Foo.Bar.Baz.A\"}\n", getVQLResponse(resp))
 }
 
+// Cleanup queries run when the query is cancelled.
+func (self *ClientVQLTestSuite) TestCleanupOnCancellation() {
+	request := &actions_proto.VQLCollectorArgs{
+		Env: []*actions_proto.VQLEnv{{Key: "Marker", Value: "TmpFile"}},
+		Query: []*actions_proto.VQLRequest{
+			{
+				Name: "Query",
+				VQL:  "SELECT * FROM clock(period=1)",
+			},
+		},
+		Cleanup: []*actions_proto.VQLRequest{
+			{
+				VQL: "SELECT log(message='Removing ' + Marker) FROM scope()",
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(self.Sm.Ctx)
+	cancel()
+
+	resp := responder.TestResponder()
+	actions.VQLClientAction{}.StartQuery(self.ConfigObj, ctx, resp, request)
+	resp.Close(self.Ctx)
+
+	logs := getLogs(resp)
+	assert.Contains(self.T(), logs, "Removing TmpFile")
+	assert.Contains(self.T(), logs, "Cleanup completed")
+
+	// Queries that complete normally do not run the cleanup.
+	request.Query[0].VQL = "SELECT * FROM scope()"
+	resp = responder.TestResponder()
+	actions.VQLClientAction{}.StartQuery(self.ConfigObj, self.Sm.Ctx, resp, request)
+	resp.Close(self.Ctx)
+	assert.NotContains(self.T(), getLogs(resp), "Removing TmpFile")
+}
+
 func getLogs(resp *responder.Responder) string {
 	result := ""
 	responses := responder.GetTestResponses(resp)
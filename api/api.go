@@ -196,6 +196,14 @@ func (self *ApiServer) CollectArtifact(
 	if err != nil {
 		return nil, Status(self.verbose, err)
 	}
+
+	// Artifacts running arbitrary commands (e.g. the shell viewer)
+	// may require step-up authentication.
+	err = checkArtifactsStepUp(ctx, org_config_obj, user_record, in.Artifacts)
+	if err != nil {
+		return nil, Status(self.verbose, err)
+	}
+
 	launcher, err := services.GetLauncher(org_config_obj)
 	if err != nil {
 		return nil, Status(self.verbose, err)
@@ -250,6 +258,20 @@ func (self *ApiServer) ListClients(
 		return nil, Status(self.verbose, err)
 	}
 
+	// Record which clients the user saw for access reports.
+	if len(result.Items) > 0 {
+		client_ids := make([]string, 0, len(result.Items))
+		for _, item := range result.Items {
+			client_ids = append(client_ids, item.ClientId)
+		}
+
+		logging.LogAudit(org_config_obj, principal, "SearchClients",
+			logrus.Fields{
+				"query":      in.Query,
+				"client_ids": client_ids,
+			})
+	}
+
 	// Warm up the cache pre-emptively so we have fresh connected
 	// status
 	notifier, err := services.GetNotifier(org_config_obj)
@@ -377,6 +399,13 @@ func (self *ApiServer) GetFlowDetails(
 	if err != nil {
 		return nil, Status(self.verbose, err)
 	}
+
+	logging.LogAudit(org_config_obj, principal, "ViewFlow",
+		logrus.Fields{
+			"client_id": in.ClientId,
+			"flow_id":   in.FlowId,
+		})
+
 	return result, nil
 }
 
@@ -787,6 +816,11 @@ func (self *ApiServer) SetArtifactFile(
 			"User is not allowed to modify artifacts (%v).", permissions))
 	}
 
+	err = CheckStepUp(ctx, org_config_obj, user_record, STEP_UP_ARTIFACT_EDIT)
+	if err != nil {
+		return nil, Status(self.verbose, err)
+	}
+
 	definition, err := setArtifactFile(org_config_obj, principal, in, "")
 	if err != nil {
 		message := &api_proto.APIResponse{
@@ -1170,6 +1204,7 @@ func StartMonitoringService(
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/metrics/clients", server.ClientMetricsHandler())
 	server := &http.Server{
 		Addr:     bind_addr,
 		Handler:  mux,
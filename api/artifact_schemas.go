@@ -0,0 +1,105 @@
+package api
+
+// Lists the output schema versions of an artifact. Callers querying
+// results across collections of different artifact versions (e.g.
+// old and new hunts) use the merged columns to line the results up.
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+type ArtifactSchemas struct {
+	Artifact string                      `json:"artifact"`
+	Schemas  []*api_proto.ArtifactSchema `json:"schemas"`
+
+	// The columns of all versions, newest first.
+	Columns []string `json:"columns"`
+}
+
+func GetArtifactSchemas(config_obj *config_proto.Config,
+	principal, artifact string) (*ArtifactSchemas, error) {
+	err := checkArtifactVersionAccess(config_obj, principal, acls.READ_RESULTS)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := manager.ListResultSchemas(config_obj, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArtifactSchemas{
+		Artifact: artifact,
+		Schemas:  schemas,
+		Columns:  services.MergeSchemaColumns(schemas),
+	}, nil
+}
+
+// URL format: /api/v1/ArtifactSchemas?artifact=Windows.System.Pslist
+func artifactSchemasHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		artifact := r.URL.Query().Get("artifact")
+		if artifact == "" {
+			returnError(w, http.StatusBadRequest, "artifact is required")
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		result, err := GetArtifactSchemas(org_config_obj, userinfo.Name, artifact)
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, services.InvalidArtifactNameError) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("artifactSchemasHandler: %v", err)
+		}
+	})
+}
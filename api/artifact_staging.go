@@ -0,0 +1,172 @@
+package api
+
+// Stage custom artifacts to canary clients, then promote them to the
+// whole fleet or roll the staged change back.
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	InvalidArtifactStagingRequest = errors.New("InvalidArtifactStagingRequest")
+)
+
+type ArtifactStagingRequest struct {
+	Name string `json:"name"`
+
+	// One of stage, promote or rollback
+	Action string `json:"action"`
+
+	// Only used when staging. Defaults come from the config.
+	Label   string `json:"label"`
+	BakeSec uint64 `json:"bake_sec"`
+}
+
+// List the artifacts which are currently staged.
+func ListStagedArtifacts(config_obj *config_proto.Config,
+	principal string) ([]*api_proto.ArtifactStaging, error) {
+	err := checkArtifactVersionAccess(config_obj, principal, acls.READ_RESULTS)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.ListStagedArtifacts(config_obj)
+}
+
+func UpdateArtifactStaging(config_obj *config_proto.Config,
+	principal string, request *ArtifactStagingRequest) (
+	*api_proto.ArtifactStaging, error) {
+	err := checkArtifactVersionAccess(config_obj, principal, acls.ARTIFACT_WRITER)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.ArtifactStaging{Name: request.Name}
+	switch request.Action {
+	case "stage":
+		result, err = manager.StageArtifact(config_obj, principal,
+			request.Name, request.Label, request.BakeSec)
+
+	case "promote":
+		err = manager.PromoteArtifact(config_obj, principal, request.Name)
+
+	case "rollback":
+		err = manager.RollbackStagedArtifact(config_obj, principal, request.Name)
+
+	default:
+		return nil, fmt.Errorf("%w: Unknown action %v",
+			InvalidArtifactStagingRequest, request.Action)
+	}
+
+	if errors.Is(err, services.ArtifactNotStagedError) ||
+		errors.Is(err, services.ArtifactVersionNotFoundError) {
+		return nil, fmt.Errorf("%w: %v", InvalidArtifactStagingRequest, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logging.LogAudit(config_obj, principal, "ArtifactStaging",
+		logrus.Fields{
+			"artifact": request.Name,
+			"action":   request.Action,
+		})
+
+	return result, nil
+}
+
+func artifactStagingHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+
+		var result interface{}
+		switch r.Method {
+		case "GET":
+			result, err = ListStagedArtifacts(org_config_obj, userinfo.Name)
+
+		case "POST":
+			var data []byte
+			data, err = io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			request := &ArtifactStagingRequest{}
+			err = json.Unmarshal(data, request)
+			if err != nil || request.Name == "" {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			err = CheckStepUp(r.Context(), org_config_obj,
+				userinfo, STEP_UP_ARTIFACT_EDIT)
+			if err == nil {
+				result, err = UpdateArtifactStaging(
+					org_config_obj, userinfo.Name, request)
+			}
+
+		default:
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, InvalidArtifactStagingRequest) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("artifactStagingHandler: %v", err)
+		}
+	})
+}
@@ -0,0 +1,318 @@
+package api
+
+// Browse the version history of custom artifacts, compare versions
+// and roll back to an earlier version.
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	InvalidArtifactVersionRequest = errors.New("InvalidArtifactVersionRequest")
+)
+
+type RollbackArtifactRequest struct {
+	Name    string `json:"name"`
+	Version uint64 `json:"version"`
+}
+
+type ArtifactVersionDiff struct {
+	Name string `json:"name"`
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+
+	// A unified diff of the definitions.
+	Diff string `json:"diff"`
+}
+
+// List the versions of the artifact. Definitions are omitted to keep
+// the listing small.
+func ListArtifactVersions(config_obj *config_proto.Config,
+	principal, name string) ([]*api_proto.ArtifactVersion, error) {
+	err := checkArtifactVersionAccess(config_obj, principal, acls.READ_RESULTS)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := manager.ListArtifactVersions(config_obj, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*api_proto.ArtifactVersion, 0, len(versions))
+	for _, version := range versions {
+		version = proto.Clone(version).(*api_proto.ArtifactVersion)
+		version.Definition = ""
+		result = append(result, version)
+	}
+
+	return result, nil
+}
+
+func GetArtifactVersion(config_obj *config_proto.Config,
+	principal, name string, version uint64) (*api_proto.ArtifactVersion, error) {
+	err := checkArtifactVersionAccess(config_obj, principal, acls.READ_RESULTS)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return getArtifactVersion(config_obj, manager, name, version)
+}
+
+func DiffArtifactVersions(config_obj *config_proto.Config,
+	principal, name string, from, to uint64) (*ArtifactVersionDiff, error) {
+	err := checkArtifactVersionAccess(config_obj, principal, acls.READ_RESULTS)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	from_version, err := getArtifactVersion(config_obj, manager, name, from)
+	if err != nil {
+		return nil, err
+	}
+
+	to_version, err := getArtifactVersion(config_obj, manager, name, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from_version.Definition),
+		B:        difflib.SplitLines(to_version.Definition),
+		FromFile: fmt.Sprintf("%v@%v", name, from),
+		ToFile:   fmt.Sprintf("%v@%v", name, to),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArtifactVersionDiff{
+		Name: name,
+		From: from,
+		To:   to,
+		Diff: diff,
+	}, nil
+}
+
+// Rolling back needs the same permission as saving the artifact.
+func RollbackArtifact(config_obj *config_proto.Config,
+	principal string, request *RollbackArtifactRequest) (
+	*artifacts_proto.Artifact, error) {
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := getArtifactVersion(
+		config_obj, manager, request.Name, request.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user must be allowed to write both the artifact as it is
+	// now and as it will be after the rollback.
+	artifact, err := manager.NewRepository().LoadYaml(version.Definition,
+		!services.ValidateArtifact, !services.ArtifactIsBuiltIn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidArtifactVersionRequest, err)
+	}
+
+	err = checkArtifactVersionAccess(config_obj, principal,
+		artifactWritePermission(artifact))
+	if err != nil {
+		return nil, err
+	}
+
+	global_repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	current, pres := global_repository.Get(config_obj, request.Name)
+	if pres {
+		err = checkArtifactVersionAccess(config_obj, principal,
+			artifactWritePermission(current))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	artifact, err = manager.RollbackArtifact(
+		config_obj, principal, request.Name, request.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidArtifactVersionRequest, err)
+	}
+
+	logging.LogAudit(config_obj, principal, "RollbackArtifact",
+		logrus.Fields{
+			"artifact": request.Name,
+			"version":  request.Version,
+		})
+
+	return artifact, nil
+}
+
+// Server artifacts need a stronger permission to modify.
+func artifactWritePermission(
+	artifact *artifacts_proto.Artifact) acls.ACL_PERMISSION {
+	switch strings.ToUpper(artifact.Type) {
+	case "SERVER", "SERVER_EVENT":
+		return acls.SERVER_ARTIFACT_WRITER
+	}
+	return acls.ARTIFACT_WRITER
+}
+
+func checkArtifactVersionAccess(config_obj *config_proto.Config,
+	principal string, permission acls.ACL_PERMISSION) error {
+	ok, err := services.CheckAccess(config_obj, principal, permission)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: User is not allowed to access artifact "+
+			"versions (%v).", acls.PermissionDenied, permission)
+	}
+	return nil
+}
+
+func getArtifactVersion(config_obj *config_proto.Config,
+	manager services.RepositoryManager,
+	name string, version uint64) (*api_proto.ArtifactVersion, error) {
+	record, err := manager.GetArtifactVersion(config_obj, name, version)
+	if errors.Is(err, services.ArtifactVersionNotFoundError) {
+		return nil, fmt.Errorf("%w: %v", InvalidArtifactVersionRequest, err)
+	}
+	return record, err
+}
+
+func artifactVersionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+
+		var result interface{}
+		switch r.Method {
+		case "GET":
+			params := r.URL.Query()
+			name := params.Get("name")
+			if name == "" {
+				returnError(w, http.StatusBadRequest, "name is required")
+				return
+			}
+
+			parse := func(field string) uint64 {
+				value, _ := strconv.ParseUint(params.Get(field), 10, 64)
+				return value
+			}
+
+			switch {
+			case params.Has("from") || params.Has("to"):
+				result, err = DiffArtifactVersions(org_config_obj,
+					userinfo.Name, name, parse("from"), parse("to"))
+
+			case params.Has("version"):
+				result, err = GetArtifactVersion(org_config_obj,
+					userinfo.Name, name, parse("version"))
+
+			default:
+				result, err = ListArtifactVersions(
+					org_config_obj, userinfo.Name, name)
+			}
+
+		case "POST":
+			var data []byte
+			data, err = io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			request := &RollbackArtifactRequest{}
+			err = json.Unmarshal(data, request)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			err = CheckStepUp(r.Context(), org_config_obj,
+				userinfo, STEP_UP_ARTIFACT_EDIT)
+			if err == nil {
+				result, err = RollbackArtifact(
+					org_config_obj, userinfo.Name, request)
+			}
+
+		default:
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, InvalidArtifactVersionRequest) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("artifactVersionsHandler: %v", err)
+		}
+	})
+}
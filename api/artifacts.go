@@ -215,7 +215,15 @@ func searchArtifact(
 
 	result := &artifacts_proto.ArtifactDescriptors{}
 	regexes := []*regexp.Regexp{}
+	techniques := []string{}
 	for _, term := range terms {
+		// Terms like technique:T1003 match artifacts covering the
+		// ATT&CK technique.
+		if strings.HasPrefix(strings.ToLower(term), "technique:") {
+			techniques = append(techniques, term[len("technique:"):])
+			continue
+		}
+
 		if len(term) <= 2 {
 			continue
 		}
@@ -226,7 +234,7 @@ func searchArtifact(
 		}
 	}
 
-	if len(regexes) == 0 {
+	if len(regexes) == 0 && len(techniques) == 0 {
 		return result, nil
 	}
 
@@ -266,6 +274,17 @@ func searchArtifact(
 				continue
 			}
 
+			covered := true
+			for _, technique := range techniques {
+				if !services.HasAttackTechnique(artifact, technique) {
+					covered = false
+					break
+				}
+			}
+			if !covered {
+				continue
+			}
+
 			if matcher(artifact.Description, regexes) ||
 				matcher(artifact.Name, regexes) {
 				if fields == nil {
@@ -285,6 +304,10 @@ func searchArtifact(
 					if fields.Type {
 						new_item.Type = artifact.Type
 					}
+					if fields.AttackTechniques {
+						new_item.AttackTechniques = artifact.AttackTechniques
+						new_item.Reference = artifact.Reference
+					}
 
 					result.Items = append(result.Items, new_item)
 				}
@@ -318,6 +341,11 @@ func (self *ApiServer) LoadArtifactPack(
 			"User is not allowed to upload artifact packs.")
 	}
 
+	err = CheckStepUp(ctx, org_config_obj, user_record, STEP_UP_ARTIFACT_EDIT)
+	if err != nil {
+		return nil, Status(self.verbose, err)
+	}
+
 	prefix := constants.ARTIFACT_PACK_NAME_PREFIX
 
 	result := &api_proto.LoadArtifactPackResponse{}
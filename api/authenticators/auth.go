@@ -120,9 +120,11 @@ func init() {
 	RegisterAuthenticator("basic", func(config_obj *config_proto.Config,
 		auth_config *config_proto.Authenticator) (Authenticator, error) {
 		return &BasicAuthenticator{
-			config_obj: config_obj,
-			base:       getBasePath(config_obj),
-			public_url: getPublicURL(config_obj),
+			config_obj:    config_obj,
+			authenticator: auth_config,
+			base:          getBasePath(config_obj),
+			public_url:    getPublicURL(config_obj),
+			challenges:    newWebAuthnChallenges(),
 		}, nil
 	})
 
@@ -3,6 +3,8 @@ package authenticators
 import (
 	"context"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gorilla/csrf"
 	"github.com/sirupsen/logrus"
@@ -18,11 +20,16 @@ import (
 // Implement basic authentication.
 type BasicAuthenticator struct {
 	config_obj       *config_proto.Config
+	authenticator    *config_proto.Authenticator
 	base, public_url string
+
+	// Outstanding WebAuthn challenges.
+	challenges *webAuthnChallenges
 }
 
-// Basic auth does not need any special handlers.
+// Basic auth only needs the WebAuthn ceremony handlers.
 func (self *BasicAuthenticator) AddHandlers(mux *http.ServeMux) error {
+	self.addWebAuthnHandlers(mux)
 	return nil
 }
 
@@ -30,6 +37,16 @@ func (self *BasicAuthenticator) AddLogoff(mux *http.ServeMux) error {
 	homepage := self.base + "app/index.html"
 	mux.Handle(self.base+"app/logoff.html",
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Clear any WebAuthn login.
+			http.SetCookie(w, &http.Cookie{
+				Name:     "VelociraptorAuth",
+				Path:     self.base,
+				MaxAge:   -1,
+				Expires:  time.Unix(0, 0),
+				Secure:   true,
+				HttpOnly: true,
+			})
+
 			username, _, ok := r.BasicAuth()
 			if !ok {
 				w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
@@ -69,6 +86,15 @@ func (self *BasicAuthenticator) AuthenticateUserHandler(
 
 		username, password, ok := r.BasicAuth()
 		if !ok {
+			// A passkey login sets the cookie without a password.
+			if self.passkeyLogin() {
+				claims, err := getDetailsFromCookie(self.config_obj, r)
+				if err == nil {
+					self.authenticateCookie(parent, w, r, claims)
+					return
+				}
+			}
+
 			http.Error(w, "Not authorized", http.StatusUnauthorized)
 			return
 		}
@@ -98,6 +124,25 @@ func (self *BasicAuthenticator) AuthenticateUserHandler(
 			return
 		}
 
+		err = self.checkSecondFactor(r, username)
+		if err != nil {
+			logging.LogAudit(self.config_obj, username, "Second factor required",
+				logrus.Fields{
+					"remote": r.RemoteAddr,
+					"status": http.StatusForbidden,
+				})
+
+			if r.Method == "GET" &&
+				strings.HasPrefix(r.URL.Path, self.base+"app/") {
+				http.Redirect(w, r, self.base+"app/webauthn/login.html",
+					http.StatusTemporaryRedirect)
+				return
+			}
+
+			http.Error(w, "Second factor required", http.StatusForbidden)
+			return
+		}
+
 		// Does the user have access to the specified org?
 		err = CheckOrgAccess(r, user_record)
 		if err != nil {
@@ -111,11 +156,41 @@ func (self *BasicAuthenticator) AuthenticateUserHandler(
 			return
 		}
 
+		err = CheckNetworkAccess(r, user_record)
+		if err != nil {
+			logging.LogAudit(self.config_obj, username, "Network not allowed",
+				logrus.Fields{
+					"remote": r.RemoteAddr,
+					"status": http.StatusUnauthorized,
+				})
+
+			http.Error(w, "authorization failed", http.StatusUnauthorized)
+			return
+		}
+
+		// Basic auth has no session cookie so the session is tied
+		// to the credentials and expires when idle.
+		err = checkSession(self.config_obj, r, username, "basic",
+			r.Header.Get("Authorization"), 0)
+		if err != nil {
+			logging.LogAudit(self.config_obj, username, "Revoked session",
+				logrus.Fields{
+					"remote": r.RemoteAddr,
+					"status": http.StatusUnauthorized,
+				})
+
+			http.Error(w, "authorization failed", http.StatusUnauthorized)
+			return
+		}
+
 		// Checking is successful - user authorized. Here we
 		// build a token to pass to the underlying GRPC
 		// service with metadata about the user.
 		user_info := &api_proto.VelociraptorUser{
-			Name: username,
+			Name:          username,
+			RemoteAddress: r.RemoteAddr,
+			SessionId: GetSessionId(self.config_obj,
+				r.Header.Get("Authorization")),
 		}
 
 		// Must use json encoding because grpc can not handle
@@ -130,3 +205,64 @@ func (self *BasicAuthenticator) AuthenticateUserHandler(
 			w, r.WithContext(ctx))
 	})
 }
+
+// Authenticate a passkey login from the VelociraptorAuth cookie.
+func (self *BasicAuthenticator) authenticateCookie(parent http.Handler,
+	w http.ResponseWriter, r *http.Request, claims *Claims) {
+	username := claims.Username
+
+	users_manager := services.GetUserManager()
+	user_record, err := users_manager.GetUser(r.Context(), username)
+	if err != nil || user_record.Name != username || user_record.Locked {
+		logging.LogAudit(self.config_obj, username, "Unknown username",
+			logrus.Fields{
+				"remote": r.RemoteAddr,
+				"status": http.StatusUnauthorized,
+			})
+
+		http.Error(w, "authorization failed", http.StatusUnauthorized)
+		return
+	}
+
+	err = CheckOrgAccess(r, user_record)
+	if err == nil {
+		err = CheckNetworkAccess(r, user_record)
+	}
+	if err != nil {
+		logging.LogAudit(self.config_obj, username, "Unauthorized username",
+			logrus.Fields{
+				"remote": r.RemoteAddr,
+				"status": http.StatusUnauthorized,
+			})
+
+		http.Error(w, "authorization failed", http.StatusUnauthorized)
+		return
+	}
+
+	auth_cookie, _ := r.Cookie("VelociraptorAuth")
+	err = checkSession(self.config_obj, r, username, "webauthn",
+		auth_cookie.Value, uint64(claims.Expires))
+	if err != nil {
+		logging.LogAudit(self.config_obj, username, "Revoked session",
+			logrus.Fields{
+				"remote": r.RemoteAddr,
+				"status": http.StatusUnauthorized,
+			})
+
+		http.Error(w, "authorization failed", http.StatusUnauthorized)
+		return
+	}
+
+	user_info := &api_proto.VelociraptorUser{
+		Name:          username,
+		RemoteAddress: r.RemoteAddr,
+		SessionId:     GetSessionId(self.config_obj, auth_cookie.Value),
+	}
+
+	serialized, _ := json.Marshal(user_info)
+	ctx := context.WithValue(
+		r.Context(), constants.GRPC_USER_CONTEXT, string(serialized))
+
+	GetLoggingHandler(self.config_obj)(parent).ServeHTTP(
+		w, r.WithContext(ctx))
+}
@@ -242,6 +242,14 @@ func installLogoff(config_obj *config_proto.Config, mux *http.ServeMux) {
 				username = old_username[0]
 			}
 
+			// Revoke the session so the cookie can not be replayed.
+			auth_cookie, err := r.Cookie("VelociraptorAuth")
+			session_manager := services.GetSessionManager()
+			if err == nil && session_manager != nil {
+				_ = session_manager.RevokeSession(r.Context(), username,
+					GetSessionId(config_obj, auth_cookie.Value))
+			}
+
 			// Clear the cookie
 			http.SetCookie(w, &http.Cookie{
 				Name:     "VelociraptorAuth",
@@ -288,12 +296,29 @@ func authenticateUserHandle(
 			return
 		}
 
+		err = CheckNetworkAccess(r, user_record)
+		if err != nil {
+			reject_cb(w, r, err, username)
+			return
+		}
+
+		// The cookie was already validated above.
+		auth_cookie, _ := r.Cookie("VelociraptorAuth")
+		err = checkSession(config_obj, r, username, "oauth",
+			auth_cookie.Value, uint64(claims.Expires))
+		if err != nil {
+			reject_cb(w, r, err, username)
+			return
+		}
+
 		// Checking is successful - user authorized. Here we
 		// build a token to pass to the underlying GRPC
 		// service with metadata about the user.
 		user_info := &api_proto.VelociraptorUser{
-			Name:    username,
-			Picture: claims.Picture,
+			Name:          username,
+			Picture:       claims.Picture,
+			RemoteAddress: r.RemoteAddr,
+			SessionId:     GetSessionId(config_obj, auth_cookie.Value),
 		}
 
 		// NOTE: This context is NOT the same context that is received
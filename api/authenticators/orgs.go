@@ -8,6 +8,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/acls"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/users"
 )
 
 func GetOrgIdFromRequest(r *http.Request) string {
@@ -95,3 +96,20 @@ func _checkOrgAccess(r *http.Request, org_id string, user_record *api_proto.Velo
 
 	return nil
 }
+
+// Check the user's roles in the requested org may be used from the
+// remote address of the request.
+func CheckNetworkAccess(r *http.Request, user_record *api_proto.VelociraptorUser) error {
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return err
+	}
+
+	org_config_obj, err := org_manager.GetOrgConfig(GetOrgIdFromRequest(r))
+	if err != nil {
+		return err
+	}
+
+	return users.CheckNetworkAccess(
+		org_config_obj, user_record.Name, r.RemoteAddr)
+}
@@ -112,6 +112,21 @@ func (self *SamlAuthenticator) AuthenticateUserHandler(
 		if err == nil && user_record.Name == username {
 			// Does the user have access to the specified org?
 			err = CheckOrgAccess(r, user_record)
+			if err == nil {
+				err = CheckNetworkAccess(r, user_record)
+			}
+		}
+
+		// The SAML session was read from this cookie above.
+		session_id := ""
+		if err == nil {
+			var session_cookie *http.Cookie
+			session_cookie, err = r.Cookie(SAML_SESSION_COOKIE)
+			if err == nil {
+				session_id = GetSessionId(self.config_obj, session_cookie.Value)
+				err = checkSession(self.config_obj, r, username, "saml",
+					session_cookie.Value, 0)
+			}
 		}
 
 		if err != nil {
@@ -136,7 +151,9 @@ Contact your system administrator to get an account, then try again.
 		}
 
 		user_info := &api_proto.VelociraptorUser{
-			Name: username,
+			Name:          username,
+			RemoteAddress: r.RemoteAddr,
+			SessionId:     session_id,
 		}
 
 		serialized, _ := json.Marshal(user_info)
@@ -0,0 +1,60 @@
+package authenticators
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+const (
+	// The session cookie set by the SAML middleware.
+	SAML_SESSION_COOKIE = "token"
+)
+
+// Sessions are identified by a hash of their credentials so the
+// credentials themselves are never stored. The hash is keyed with the
+// server's private key so session ids can not be used to guess
+// credentials (e.g. weak basic auth passwords) offline.
+func GetSessionId(config_obj *config_proto.Config, credentials string) string {
+	mac := hmac.New(sha256.New, []byte(config_obj.Frontend.PrivateKey))
+	_, _ = mac.Write([]byte(credentials))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Report the session to the session manager. Returns
+// services.SessionRevokedError if the session was revoked. Failing to
+// track the session (e.g. the datastore is unavailable) does not
+// lock users out so other errors are only logged.
+func checkSession(config_obj *config_proto.Config,
+	r *http.Request, username, authenticator,
+	credentials string, expires uint64) error {
+	session_manager := services.GetSessionManager()
+	if session_manager == nil {
+		return nil
+	}
+
+	err := session_manager.TouchSession(r.Context(), &services.SessionRequest{
+		SessionId:     GetSessionId(config_obj, credentials),
+		Username:      username,
+		RemoteAddress: r.RemoteAddr,
+		UserAgent:     r.UserAgent(),
+		Authenticator: authenticator,
+		Expires:       expires,
+	})
+	if errors.Is(err, services.SessionRevokedError) {
+		return err
+	}
+
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("checkSession: Unable to track session for %v: %v",
+			username, err)
+	}
+	return nil
+}
@@ -0,0 +1,715 @@
+package authenticators
+
+// WebAuthn support for the basic authenticator.
+
+// Users of the basic authenticator may register security keys or
+// passkeys. Once a user has a key (or when require_webauthn is set)
+// the password alone is no longer enough - the user must also
+// complete a WebAuthn assertion which sets the usual VelociraptorAuth
+// cookie. When webauthn_passkey_login is set the cookie alone is
+// accepted so users may log in with a passkey and no password.
+
+// The ceremony endpoints live under app/webauthn/ so browsers send
+// the cached basic auth credentials with them.
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ttlcache/v2"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/crypto/webauthn"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/users"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	WEBAUTHN_CHALLENGE_TIMEOUT = 5 * time.Minute
+
+	// The ceremony endpoints are reachable before the user is
+	// authenticated so the outstanding challenges are capped and
+	// each source may only request a few per second.
+	MAX_WEBAUTHN_CHALLENGES = 10000
+	MAX_WEBAUTHN_SOURCES    = 10000
+)
+
+var (
+	SecondFactorRequiredError = fmt.Errorf(
+		"%w: Second factor required", acls.PermissionDenied)
+
+	TooManyChallengesError = errors.New("Too many WebAuthn requests")
+)
+
+type webAuthnChallenge struct {
+	// Empty for passkey logins where the user is not known yet.
+	username string
+	ceremony string
+	expires  time.Time
+}
+
+// Outstanding challenges. Each challenge may only be used once.
+type webAuthnChallenges struct {
+	mu         sync.Mutex
+	challenges map[string]*webAuthnChallenge
+
+	// Challenges in the order they were issued. Since they all
+	// have the same timeout this is also the order they expire in.
+	order []string
+
+	// Request limiters keyed by source address.
+	sources *ttlcache.Cache
+}
+
+func newWebAuthnChallenges() *webAuthnChallenges {
+	result := &webAuthnChallenges{
+		challenges: make(map[string]*webAuthnChallenge),
+		sources:    ttlcache.NewCache(),
+	}
+	result.sources.SetCacheSizeLimit(MAX_WEBAUTHN_SOURCES)
+	result.sources.SetTTL(time.Minute)
+	return result
+}
+
+func (self *webAuthnChallenges) allow(source string) bool {
+	var limiter *rate.Limiter
+	cached, err := self.sources.Get(source)
+	if err == nil {
+		limiter = cached.(*rate.Limiter)
+	} else {
+		limiter = rate.NewLimiter(rate.Limit(1), 10)
+		_ = self.sources.Set(source, limiter)
+	}
+	return limiter.Allow()
+}
+
+// Drop expired challenges from the front of the queue, and the
+// oldest ones if we are still over the limit. Challenges already
+// taken are skipped. Must be called with the lock held.
+func (self *webAuthnChallenges) expire(now time.Time) {
+	for len(self.order) > 0 {
+		key := self.order[0]
+		record, pres := self.challenges[key]
+		if pres && !record.expires.Before(now) &&
+			len(self.order) < MAX_WEBAUTHN_CHALLENGES {
+			break
+		}
+
+		delete(self.challenges, key)
+		self.order = self.order[1:]
+	}
+}
+
+func (self *webAuthnChallenges) New(
+	source, username, ceremony string) (string, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if !self.allow(source) {
+		return "", TooManyChallengesError
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+
+	now := utils.GetTime().Now()
+	self.expire(now)
+
+	self.challenges[challenge] = &webAuthnChallenge{
+		username: username,
+		ceremony: ceremony,
+		expires:  now.Add(WEBAUTHN_CHALLENGE_TIMEOUT),
+	}
+	self.order = append(self.order, challenge)
+	return challenge, nil
+}
+
+// Take the challenge echoed in the client data.
+func (self *webAuthnChallenges) Take(
+	client_data_json []byte, ceremony string) (string, *webAuthnChallenge, error) {
+	client_data := &struct {
+		Challenge string `json:"challenge"`
+	}{}
+	err := json.Unmarshal(client_data_json, client_data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	record, pres := self.challenges[client_data.Challenge]
+	if !pres || record.ceremony != ceremony ||
+		record.expires.Before(utils.GetTime().Now()) {
+		return "", nil, errors.New("Unknown or expired challenge")
+	}
+	delete(self.challenges, client_data.Challenge)
+
+	return client_data.Challenge, record, nil
+}
+
+// The relying party is the GUI's public host.
+func (self *BasicAuthenticator) relyingParty() (rp_id, origin string, err error) {
+	public_url, err := url.Parse(self.config_obj.GUI.PublicUrl)
+	if err != nil || public_url.Host == "" {
+		return "", "", errors.New("WebAuthn requires GUI.public_url")
+	}
+
+	rp_id = public_url.Hostname()
+	if self.authenticator != nil && self.authenticator.WebauthnRpId != "" {
+		rp_id = self.authenticator.WebauthnRpId
+	}
+
+	return rp_id, public_url.Scheme + "://" + public_url.Host, nil
+}
+
+// Challenges are rate limited by the host the request came from.
+func (self *BasicAuthenticator) remoteSource(r *http.Request) string {
+	addr := utils.RemoteAddr(r, self.config_obj.Frontend.GetProxyHeader())
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+func (self *BasicAuthenticator) passkeyLogin() bool {
+	return self.authenticator != nil && self.authenticator.WebauthnPasskeyLogin
+}
+
+// Check the basic auth credentials of the request. Returns the
+// username if the password is correct.
+func (self *BasicAuthenticator) checkPassword(r *http.Request) (string, bool) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return "", false
+	}
+
+	users_manager := services.GetUserManager()
+	user_record, err := users_manager.GetUserWithHashes(r.Context(), username)
+	if err != nil || user_record.Name != username || user_record.Locked {
+		return "", false
+	}
+
+	return username, users.VerifyPassword(user_record, password)
+}
+
+// Users with a registered key (or everyone when require_webauthn
+// is set) need a VelociraptorAuth cookie from a WebAuthn assertion
+// as well as the password.
+func (self *BasicAuthenticator) checkSecondFactor(
+	r *http.Request, username string) error {
+	claims, err := getDetailsFromCookie(self.config_obj, r)
+	if err == nil && claims.Username == username {
+		return nil
+	}
+
+	if self.authenticator != nil && self.authenticator.RequireWebauthn {
+		return SecondFactorRequiredError
+	}
+
+	credentials, err := users.ListWebAuthnCredentials(
+		r.Context(), self.config_obj, username)
+	if err != nil {
+		return err
+	}
+
+	if len(credentials) > 0 {
+		return SecondFactorRequiredError
+	}
+	return nil
+}
+
+func (self *BasicAuthenticator) setAuthCookie(
+	w http.ResponseWriter, username string) error {
+	authenticator := self.authenticator
+	if authenticator == nil {
+		authenticator = &config_proto.Authenticator{}
+	}
+
+	cookie, err := getSignedJWTTokenCookie(
+		self.config_obj, authenticator, &Claims{Username: username})
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+func (self *BasicAuthenticator) addWebAuthnHandlers(mux *http.ServeMux) {
+	prefix := self.base + "app/webauthn/"
+
+	mux.Handle(prefix+"login.html", http.HandlerFunc(self.webAuthnPage))
+	mux.Handle(prefix+"login_begin", self.webAuthnHandler(self.loginBegin))
+	mux.Handle(prefix+"login", self.webAuthnHandler(self.login))
+	mux.Handle(prefix+"recover", self.webAuthnHandler(self.recover))
+	mux.Handle(prefix+"register_begin", self.webAuthnHandler(self.registerBegin))
+	mux.Handle(prefix+"register", self.webAuthnHandler(self.register))
+}
+
+// Wraps the JSON ceremony endpoints.
+func (self *BasicAuthenticator) webAuthnHandler(
+	handler func(w http.ResponseWriter, r *http.Request,
+		request map[string]string) (interface{}, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requiring a JSON body prevents cross site form posts.
+		if r.Method != "POST" ||
+			r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "Unsupported request", http.StatusBadRequest)
+			return
+		}
+
+		data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "Unsupported request", http.StatusBadRequest)
+			return
+		}
+
+		request := make(map[string]string)
+		err = json.Unmarshal(data, &request)
+		if err != nil {
+			http.Error(w, "Unsupported request", http.StatusBadRequest)
+			return
+		}
+
+		result, err := handler(w, r, request)
+		if errors.Is(err, acls.PermissionDenied) {
+			// Ask the browser for the basic auth credentials.
+			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if errors.Is(err, TooManyChallengesError) {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(serialized)
+	})
+}
+
+func decodeFields(request map[string]string, fields ...string) (
+	[][]byte, error) {
+	result := make([][]byte, 0, len(fields))
+	for _, field := range fields {
+		value, err := webauthn.DecodeId(request[field])
+		if err != nil || len(value) == 0 {
+			return nil, fmt.Errorf("Invalid %v", field)
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+func (self *BasicAuthenticator) loginBegin(w http.ResponseWriter,
+	r *http.Request, request map[string]string) (interface{}, error) {
+	rp_id, _, err := self.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	// With a password the key is a second factor for a known
+	// user. Otherwise the browser offers the user's passkeys.
+	username, password_ok := self.checkPassword(r)
+	if !password_ok {
+		if !self.passkeyLogin() {
+			return nil, fmt.Errorf("%w: Password required",
+				acls.PermissionDenied)
+		}
+		username = ""
+	}
+
+	allowed := []string{}
+	user_verification := "required"
+	if username != "" {
+		user_verification = "discouraged"
+		credentials, err := users.ListWebAuthnCredentials(
+			r.Context(), self.config_obj, username)
+		if err != nil {
+			return nil, err
+		}
+		for _, credential := range credentials {
+			allowed = append(allowed, credential.CredentialId)
+		}
+	}
+
+	challenge, err := self.challenges.New(
+		self.remoteSource(r), username, webauthn.CEREMONY_GET)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"challenge":         challenge,
+		"rp_id":             rp_id,
+		"allow_credentials": allowed,
+		"user_verification": user_verification,
+	}, nil
+}
+
+func (self *BasicAuthenticator) login(w http.ResponseWriter,
+	r *http.Request, request map[string]string) (interface{}, error) {
+	rp_id, origin, err := self.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := decodeFields(request, "client_data_json",
+		"authenticator_data", "signature")
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, challenge_record, err := self.challenges.Take(
+		fields[0], webauthn.CEREMONY_GET)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := users.GetWebAuthnCredential(
+		r.Context(), self.config_obj, request["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	if challenge_record.username != "" &&
+		challenge_record.username != record.Name {
+		return nil, errors.New("Credential belongs to another user")
+	}
+
+	username, password_ok := self.checkPassword(r)
+	password_ok = password_ok && username == record.Name
+	if !password_ok && !self.passkeyLogin() {
+		return nil, fmt.Errorf("%w: Password required", acls.PermissionDenied)
+	}
+
+	credential := &webauthn.Credential{
+		PublicKey: record.PublicKey,
+		SignCount: uint32(record.SignCount),
+	}
+
+	// A passkey alone must verify the user (e.g. PIN or biometric).
+	count, err := webauthn.VerifyAssertion(credential,
+		fields[0], fields[1], fields[2], challenge, origin, rp_id,
+		!password_ok)
+	if err != nil {
+		logging.LogAudit(self.config_obj, record.Name, "WebAuthnLoginFailed",
+			logrus.Fields{
+				"remote": r.RemoteAddr,
+				"error":  err.Error(),
+			})
+		return nil, err
+	}
+
+	record.SignCount = uint64(count)
+	record.LastUsed = uint64(utils.GetTime().Now().Unix())
+	err = users.SetWebAuthnCredential(r.Context(), self.config_obj, record)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.LogAudit(self.config_obj, record.Name, "WebAuthnLogin",
+		logrus.Fields{
+			"remote":   r.RemoteAddr,
+			"label":    record.Label,
+			"password": password_ok,
+		})
+
+	return map[string]interface{}{}, self.setAuthCookie(w, record.Name)
+}
+
+// A recovery code replaces the key as the second factor - it still
+// needs the password.
+func (self *BasicAuthenticator) recover(w http.ResponseWriter,
+	r *http.Request, request map[string]string) (interface{}, error) {
+	username, password_ok := self.checkPassword(r)
+	if !password_ok {
+		return nil, fmt.Errorf("%w: Password required", acls.PermissionDenied)
+	}
+
+	err := users.UseRecoveryCode(
+		r.Context(), self.config_obj, username, request["code"])
+	if err != nil {
+		logging.LogAudit(self.config_obj, username, "RecoveryCodeFailed",
+			logrus.Fields{
+				"remote": r.RemoteAddr,
+			})
+		return nil, err
+	}
+
+	logging.LogAudit(self.config_obj, username, "RecoveryCodeUsed",
+		logrus.Fields{
+			"remote": r.RemoteAddr,
+			"remaining": users.CountRecoveryCodes(
+				r.Context(), self.config_obj, username),
+		})
+
+	return map[string]interface{}{}, self.setAuthCookie(w, username)
+}
+
+// Registering a key needs the password, and the second factor if
+// the user already has a key.
+func (self *BasicAuthenticator) checkRegistration(r *http.Request) (
+	string, []*api_proto.WebAuthnCredential, error) {
+	username, password_ok := self.checkPassword(r)
+	if !password_ok {
+		return "", nil, fmt.Errorf("%w: Password required",
+			acls.PermissionDenied)
+	}
+
+	credentials, err := users.ListWebAuthnCredentials(
+		r.Context(), self.config_obj, username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(credentials) > 0 {
+		claims, err := getDetailsFromCookie(self.config_obj, r)
+		if err != nil || claims.Username != username {
+			return "", nil, errors.New(
+				"Log in with an existing key to register another")
+		}
+	}
+
+	return username, credentials, nil
+}
+
+func (self *BasicAuthenticator) registerBegin(w http.ResponseWriter,
+	r *http.Request, request map[string]string) (interface{}, error) {
+	rp_id, _, err := self.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	username, credentials, err := self.checkRegistration(r)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := []string{}
+	for _, credential := range credentials {
+		exclude = append(exclude, credential.CredentialId)
+	}
+
+	challenge, err := self.challenges.New(
+		self.remoteSource(r), username, webauthn.CEREMONY_CREATE)
+	if err != nil {
+		return nil, err
+	}
+
+	// A stable opaque user handle.
+	user_id := sha256.Sum256([]byte(username))
+
+	return map[string]interface{}{
+		"challenge":           challenge,
+		"rp_id":               rp_id,
+		"user_id":             webauthn.EncodeId(user_id[:16]),
+		"username":            username,
+		"exclude_credentials": exclude,
+	}, nil
+}
+
+func (self *BasicAuthenticator) register(w http.ResponseWriter,
+	r *http.Request, request map[string]string) (interface{}, error) {
+	rp_id, origin, err := self.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	username, credentials, err := self.checkRegistration(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := decodeFields(request, "client_data_json",
+		"attestation_object")
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, challenge_record, err := self.challenges.Take(
+		fields[0], webauthn.CEREMONY_CREATE)
+	if err != nil {
+		return nil, err
+	}
+
+	if challenge_record.username != username {
+		return nil, errors.New("Challenge was issued to another user")
+	}
+
+	credential, err := webauthn.VerifyRegistration(
+		fields[0], fields[1], challenge, origin, rp_id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uint64(utils.GetTime().Now().Unix())
+	record := &api_proto.WebAuthnCredential{
+		Name:         username,
+		CredentialId: webauthn.EncodeId(credential.Id),
+		PublicKey:    credential.PublicKey,
+		SignCount:    uint64(credential.SignCount),
+		Label:        request["label"],
+		Created:      now,
+		LastUsed:     now,
+	}
+
+	err = users.SetWebAuthnCredential(r.Context(), self.config_obj, record)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.LogAudit(self.config_obj, username, "WebAuthnRegister",
+		logrus.Fields{
+			"remote": r.RemoteAddr,
+			"label":  record.Label,
+		})
+
+	result := map[string]interface{}{}
+
+	// Hand out recovery codes with the first key.
+	if len(credentials) == 0 {
+		codes, err := users.NewRecoveryCodes(
+			r.Context(), self.config_obj, username)
+		if err != nil {
+			return nil, err
+		}
+		result["recovery_codes"] = codes
+	}
+
+	return result, self.setAuthCookie(w, username)
+}
+
+func (self *BasicAuthenticator) webAuthnPage(
+	w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := webAuthnLoginTemplate.Execute(w, map[string]interface{}{
+		"BasePath":     self.base,
+		"PasskeyLogin": self.passkeyLogin(),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+var webAuthnLoginTemplate = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>Velociraptor Login</title>
+<style>
+body { font-family: sans-serif; max-width: 30em; margin: 4em auto; }
+button, input { display: block; width: 100%; margin: 0.5em 0; padding: 0.5em; }
+#message { color: #a00; }
+</style>
+</head>
+<body>
+<h2>Velociraptor</h2>
+<p id="message"></p>
+<button id="login">Sign in with a security key{{if .PasskeyLogin}} or passkey{{end}}</button>
+<hr>
+<input id="label" placeholder="Name for a new key (e.g. Yubikey)">
+<button id="register">Register a new security key</button>
+<hr>
+<input id="code" placeholder="Recovery code">
+<button id="recover">Use a recovery code</button>
+<pre id="codes"></pre>
+<script>
+const base = {{.BasePath}} + "app/";
+const b64 = buf => btoa(String.fromCharCode(...new Uint8Array(buf)))
+  .replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+const unb64 = s => Uint8Array.from(
+  atob(s.replace(/-/g, "+").replace(/_/g, "/")), c => c.charCodeAt(0));
+const ids = list => list.map(id => ({type: "public-key", id: unb64(id)}));
+
+async function post(path, body) {
+  const response = await fetch(base + "webauthn/" + path, {
+    method: "POST", credentials: "same-origin",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify(body)});
+  if (!response.ok) {
+    throw new Error(await response.text());
+  }
+  return response.json();
+}
+
+function done() { window.location = base + "index.html"; }
+function run(fn) {
+  return () => fn().catch(e => {
+    document.getElementById("message").textContent = e.message;
+  });
+}
+
+document.getElementById("login").onclick = run(async () => {
+  const opts = await post("login_begin", {});
+  const cred = await navigator.credentials.get({publicKey: {
+    challenge: unb64(opts.challenge), rpId: opts.rp_id,
+    userVerification: opts.user_verification,
+    allowCredentials: ids(opts.allow_credentials)}});
+  await post("login", {
+    id: b64(cred.rawId),
+    client_data_json: b64(cred.response.clientDataJSON),
+    authenticator_data: b64(cred.response.authenticatorData),
+    signature: b64(cred.response.signature)});
+  done();
+});
+
+document.getElementById("register").onclick = run(async () => {
+  const opts = await post("register_begin", {});
+  const cred = await navigator.credentials.create({publicKey: {
+    challenge: unb64(opts.challenge),
+    rp: {id: opts.rp_id, name: "Velociraptor"},
+    user: {id: unb64(opts.user_id), name: opts.username,
+           displayName: opts.username},
+    pubKeyCredParams: [{type: "public-key", alg: -7},
+                       {type: "public-key", alg: -257}],
+    excludeCredentials: ids(opts.exclude_credentials),
+    authenticatorSelection: {residentKey: "preferred",
+                             userVerification: "preferred"},
+    attestation: "none"}});
+  const result = await post("register", {
+    label: document.getElementById("label").value,
+    client_data_json: b64(cred.response.clientDataJSON),
+    attestation_object: b64(cred.response.attestationObject)});
+  if (!result.recovery_codes) {
+    return done();
+  }
+  document.getElementById("codes").textContent =
+    "Store these recovery codes somewhere safe - each can be used " +
+    "once if you lose your key:\n\n" + result.recovery_codes.join("\n");
+  document.getElementById("login").textContent = "Continue";
+  document.getElementById("login").onclick = done;
+});
+
+document.getElementById("recover").onclick = run(async () => {
+  await post("recover", {code: document.getElementById("code").value});
+  done();
+});
+</script>
+</body>
+</html>
+`))
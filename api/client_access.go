@@ -0,0 +1,233 @@
+package api
+
+// Compiles a report of every user access to a client's data from
+// the audit log. This is needed for privacy audits which must show
+// who looked at a specific endpoint (e.g. an employee's laptop) and
+// when.
+
+// Access is recorded by audit events which refer to the client: the
+// client appearing in search results, viewing its flows or
+// notebooks and downloading its files or tables.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	InvalidClientAccessRequest = errors.New("InvalidClientAccessRequest")
+)
+
+type ClientAccessReport struct {
+	ClientId  string `json:"client_id"`
+	Hostname  string `json:"hostname"`
+	Start     int64  `json:"start"`
+	End       int64  `json:"end"`
+	Generated int64  `json:"generated"`
+
+	// Number of accesses by each user and of each type.
+	Users      map[string]int `json:"users"`
+	Operations map[string]int `json:"operations"`
+
+	Events []*logging.AuditEvent `json:"events"`
+}
+
+// Does the audit event refer to the client? Client ids may be
+// nested inside the logged request.
+func referencesClient(value interface{}, client_id string) bool {
+	switch t := value.(type) {
+	case map[string]interface{}:
+		for k, v := range t {
+			switch k {
+			case "client_id", "ClientId", "clientId":
+				if v == client_id {
+					return true
+				}
+
+			case "client_ids":
+				items, _ := v.([]interface{})
+				for _, item := range items {
+					if item == client_id {
+						return true
+					}
+				}
+
+			default:
+				if referencesClient(v, client_id) {
+					return true
+				}
+			}
+		}
+
+	case []interface{}:
+		for _, item := range t {
+			if referencesClient(item, client_id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func GetClientAccessReport(ctx context.Context,
+	config_obj *config_proto.Config, principal, client_id string,
+	start, end time.Time) (*ClientAccessReport, error) {
+	permissions := acls.SERVER_ADMIN
+	perm, err := services.CheckAccess(config_obj, principal, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if !perm {
+		return nil, fmt.Errorf("%w: User %v is not allowed to view access reports",
+			acls.PermissionDenied, principal)
+	}
+
+	if client_id == "" {
+		return nil, fmt.Errorf("%w: client_id is required",
+			InvalidClientAccessRequest)
+	}
+
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: end is before start",
+			InvalidClientAccessRequest)
+	}
+
+	result := &ClientAccessReport{
+		ClientId:   client_id,
+		Hostname:   services.GetHostname(ctx, config_obj, client_id),
+		Start:      start.Unix(),
+		End:        end.Unix(),
+		Generated:  utils.GetTime().Now().Unix(),
+		Users:      make(map[string]int),
+		Operations: make(map[string]int),
+		Events:     []*logging.AuditEvent{},
+	}
+
+	err = logging.ReadAuditLog(ctx, config_obj, start, end,
+		func(event *logging.AuditEvent) {
+			if !referencesClient(event.Details, client_id) {
+				return
+			}
+
+			result.Users[event.Principal]++
+			result.Operations[event.Operation]++
+			result.Events = append(result.Events, event)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result.Events, func(i, j int) bool {
+		return result.Events[i].Time.Before(result.Events[j].Time)
+	})
+
+	// Compiling the report is itself an access.
+	logging.LogAudit(config_obj, principal, "ClientAccessReport",
+		logrus.Fields{
+			"client_id": client_id,
+			"start":     result.Start,
+			"end":       result.End,
+			"events":    len(result.Events),
+		})
+
+	return result, nil
+}
+
+// Times are given in seconds since the epoch. The range defaults
+// to the last 30 days.
+func parseReportTime(value string, default_time time.Time) (time.Time, error) {
+	if value == "" {
+		return default_time, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid time %v",
+			InvalidClientAccessRequest, value)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// URL format: /api/v1/ClientAccessReport?client_id=C.123&start=&end=
+func clientAccessReportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		params := r.URL.Query()
+		now := utils.GetTime().Now()
+
+		start, err := parseReportTime(
+			params.Get("start"), now.Add(-30*24*time.Hour))
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		end, err := parseReportTime(params.Get("end"), now)
+		if err != nil {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		result, err := GetClientAccessReport(r.Context(), org_config_obj,
+			userinfo.Name, params.Get("client_id"), start, end)
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, InvalidClientAccessRequest) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		w.Header().Set("Content-Disposition", "attachment; filename="+
+			url.PathEscape("access_report_"+result.ClientId+".json"))
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("clientAccessReportHandler: %v", err)
+		}
+	})
+}
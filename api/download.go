@@ -169,6 +169,21 @@ func vfsFileDownloadHandler() http.Handler {
 			return
 		}
 
+		// Client files are under clients/<client_id>/
+		client_id := request.ClientId
+		components := path_spec.Components()
+		if client_id == "" && len(components) > 1 && components[0] == "clients" {
+			client_id = components[1]
+		}
+
+		logging.LogAudit(org_config_obj,
+			GetUserInfo(r.Context(), org_config_obj).Name, "DownloadFile",
+			logrus.Fields{
+				"client_id": client_id,
+				"path":      path_spec.AsClientPath(),
+				"remote":    r.RemoteAddr,
+			})
+
 		var reader_at io.ReaderAt = utils.MakeReaderAtter(file)
 
 		index, err := getIndex(org_config_obj, path_spec)
@@ -389,6 +404,16 @@ func downloadTable() http.Handler {
 
 		transform := getTransformer(r.Context(), org_config_obj, request)
 
+		var normalized_columns []string
+		if request.NormalizeSchema && request.Artifact != "" {
+			normalized_columns, err = tables.GetNormalizedColumns(
+				org_config_obj, request.Artifact)
+			if err != nil {
+				returnError(w, 500, err.Error())
+				return
+			}
+		}
+
 		download_name := request.DownloadFilename
 		if download_name == "" {
 			download_name = strings.Replace(log_path.Base(), "\"", "", -1)
@@ -436,7 +461,8 @@ func downloadTable() http.Handler {
 				csv.WriteHeaders, opts)
 			for row := range row_chan {
 				csv_writer.Write(
-					filterColumns(request.Columns, transform(row)))
+					filterColumns(request.Columns,
+						normalizeRow(normalized_columns, transform(row))))
 			}
 			csv_writer.Close()
 
@@ -461,7 +487,8 @@ func downloadTable() http.Handler {
 
 			for row := range row_chan {
 				serialized, err := json.MarshalWithOptions(
-					filterColumns(request.Columns, transform(row)),
+					filterColumns(request.Columns,
+						normalizeRow(normalized_columns, transform(row))),
 					json.GetJsonOptsForTimezone(request.Timezone))
 				if err != nil {
 					return
@@ -540,6 +567,16 @@ func getIndex(config_obj *config_proto.Config,
 	return index, nil
 }
 
+// Add the normalized columns missing from the row as null so rows
+// written by all schema versions have the same columns. Columns
+// which are not in any schema version are kept.
+func normalizeRow(columns []string, row *ordereddict.Dict) *ordereddict.Dict {
+	if len(columns) == 0 {
+		return row
+	}
+	return filterColumns(utils.MergeStringSlices(columns, row.Keys()), row)
+}
+
 func filterColumns(columns []string, row *ordereddict.Dict) *ordereddict.Dict {
 	if len(columns) == 0 {
 		return row
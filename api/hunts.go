@@ -114,6 +114,14 @@ func (self *ApiServer) CreateHunt(
 			"User is not allowed to launch hunts.")
 	}
 
+	// Hunting with artifacts running arbitrary commands needs the
+	// same step-up as collecting them.
+	err = checkArtifactsStepUp(ctx, org_config_obj, user_record,
+		in.StartRequest.GetArtifacts())
+	if err != nil {
+		return nil, Status(self.verbose, err)
+	}
+
 	// Require the Org Admin permission to launch hunts in a differen
 	// org.
 	orgs := in.OrgIds
@@ -0,0 +1,359 @@
+package api
+
+// Streams the monitoring events of a set of clients as they arrive.
+
+// While hosts are being contained, responders need to watch what
+// happens on them. Rather than repeatedly polling the event result
+// sets, the live tail merges the selected client event artifacts for
+// the selected clients into a single stream of JSON lines. An
+// optional VQL filter is evaluated on the server so only the
+// interesting rows are sent. Since the filter is arbitrary VQL it
+// needs the same permission as running VQL in a notebook.
+
+// The stream ends when the request is cancelled or when the server's
+// write timeout expires, so callers should simply reconnect.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	InvalidLiveTailRequest = errors.New("InvalidLiveTailRequest")
+)
+
+type LiveTailRequest struct {
+	ClientIds []string `json:"client_ids"`
+
+	// Client event artifacts (or artifact/source) to watch.
+	Artifacts []string `json:"artifacts"`
+
+	// A VQL expression evaluated against each row. The row's
+	// columns are available as variables, and the whole row as
+	// Row. For example: Name =~ "powershell". Requires the
+	// NOTEBOOK_EDITOR permission.
+	Filter string `json:"filter"`
+}
+
+type LiveTailEvent struct {
+	Timestamp int64             `json:"timestamp"`
+	Artifact  string            `json:"artifact"`
+	ClientId  string            `json:"client_id"`
+	Hostname  string            `json:"hostname"`
+	Row       *ordereddict.Dict `json:"row"`
+}
+
+type LiveTail struct {
+	config_obj *config_proto.Config
+	principal  string
+	request    *LiveTailRequest
+
+	// Hostnames of the selected clients.
+	clients map[string]string
+	filter  *vfilter.Lambda
+}
+
+// Validate the request before any events are streamed so errors can
+// still be reported with the right status.
+func NewLiveTail(ctx context.Context,
+	config_obj *config_proto.Config, principal string,
+	request *LiveTailRequest) (*LiveTail, error) {
+	permissions := acls.READ_RESULTS
+	perm, err := services.CheckAccess(config_obj, principal, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if !perm {
+		return nil, fmt.Errorf("%w: User %v is not allowed to view results",
+			acls.PermissionDenied, principal)
+	}
+
+	if request.Filter != "" {
+		perm, err := services.CheckAccess(
+			config_obj, principal, acls.NOTEBOOK_EDITOR)
+		if err != nil {
+			return nil, err
+		}
+
+		if !perm {
+			return nil, fmt.Errorf(
+				"%w: User %v is not allowed to run VQL filters",
+				acls.PermissionDenied, principal)
+		}
+	}
+
+	if len(request.ClientIds) == 0 {
+		return nil, fmt.Errorf("%w: client_id is required",
+			InvalidLiveTailRequest)
+	}
+
+	if len(request.Artifacts) == 0 {
+		return nil, fmt.Errorf("%w: artifact is required",
+			InvalidLiveTailRequest)
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range request.Artifacts {
+		artifact_name, _ := paths.SplitFullSourceName(name)
+		artifact_type, err := repository.GetArtifactType(
+			config_obj, artifact_name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", InvalidLiveTailRequest, err)
+		}
+
+		if artifact_type != "client_event" {
+			return nil, fmt.Errorf("%w: %v is not a client event artifact",
+				InvalidLiveTailRequest, name)
+		}
+	}
+
+	result := &LiveTail{
+		config_obj: config_obj,
+		principal:  principal,
+		request:    request,
+		clients:    make(map[string]string),
+	}
+
+	if request.Filter != "" {
+		result.filter, err = vfilter.ParseLambda("Row=>" + request.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid filter: %v",
+				InvalidLiveTailRequest, err)
+		}
+	}
+
+	for _, client_id := range request.ClientIds {
+		if !strings.HasPrefix(client_id, "C.") {
+			return nil, fmt.Errorf("%w: invalid client id %v",
+				InvalidLiveTailRequest, client_id)
+		}
+		result.clients[client_id] = services.GetHostname(
+			ctx, config_obj, client_id)
+	}
+
+	return result, nil
+}
+
+// Calls emit with each matching event until the context is done or
+// emit fails.
+func (self *LiveTail) Run(ctx context.Context,
+	emit func(event *LiveTailEvent) error) error {
+	journal, err := services.GetJournal(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	// The filter runs with the user's own permissions.
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     self.config_obj,
+		ACLManager: acl_managers.NewServerACLManager(self.config_obj, self.principal),
+		Logger:     logging.NewPlainLogger(self.config_obj, &logging.GUIComponent),
+	})
+	defer scope.Close()
+
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logging.LogAudit(self.config_obj, self.principal, "LiveTail",
+		logrus.Fields{
+			"client_ids": self.request.ClientIds,
+			"artifacts":  self.request.Artifacts,
+			"filter":     self.request.Filter,
+		})
+
+	// Merge all the watched queues into a single stream. Rows are
+	// matched here so the filter scope is only used by one
+	// goroutine.
+	type watchedRow struct {
+		artifact string
+		row      *ordereddict.Dict
+	}
+
+	merged := make(chan watchedRow)
+	for _, artifact := range self.request.Artifacts {
+		rows, watch_cancel := journal.Watch(sub_ctx, artifact,
+			"LiveTail "+self.principal)
+		defer watch_cancel()
+
+		go func(artifact string, rows <-chan *ordereddict.Dict) {
+			for {
+				select {
+				case <-sub_ctx.Done():
+					return
+
+				case row, ok := <-rows:
+					if !ok {
+						return
+					}
+
+					select {
+					case <-sub_ctx.Done():
+						return
+					case merged <- watchedRow{artifact: artifact, row: row}:
+					}
+				}
+			}
+		}(artifact, rows)
+	}
+
+	for {
+		select {
+		case <-sub_ctx.Done():
+			return nil
+
+		case item := <-merged:
+			event := self.match(sub_ctx, scope, item.artifact, item.row)
+			if event == nil {
+				continue
+			}
+
+			err := emit(event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Returns nil if the row is not from a selected client or is
+// rejected by the filter.
+func (self *LiveTail) match(ctx context.Context, scope vfilter.Scope,
+	artifact string, row *ordereddict.Dict) *LiveTailEvent {
+	client_id, _ := row.GetString("ClientId")
+	hostname, pres := self.clients[client_id]
+	if !pres {
+		return nil
+	}
+
+	if self.filter != nil {
+		subscope := scope.Copy().AppendVars(row)
+		defer subscope.Close()
+
+		if !scope.Bool(self.filter.Reduce(
+			ctx, subscope, []vfilter.Any{row})) {
+			return nil
+		}
+	}
+
+	return &LiveTailEvent{
+		Timestamp: utils.GetTime().Now().Unix(),
+		Artifact:  artifact,
+		ClientId:  client_id,
+		Hostname:  hostname,
+		Row:       row,
+	}
+}
+
+// URL format: /api/v1/LiveTail?client_id=C.1&client_id=C.2&artifact=Windows.Events.ProcessCreation&filter=
+//
+// Each event is sent as a line of JSON.
+func liveTailHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			returnError(w, http.StatusInternalServerError,
+				"Streaming not supported")
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+		params := r.URL.Query()
+
+		tail, err := NewLiveTail(r.Context(), org_config_obj,
+			userinfo.Name, &LiveTailRequest{
+				ClientIds: params["client_id"],
+				Artifacts: params["artifact"],
+				Filter:    params.Get("filter"),
+			})
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, InvalidLiveTailRequest) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Cache-Control", "no-cache")
+
+		// Stop nginx from buffering the stream.
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		err = tail.Run(r.Context(), func(event *LiveTailEvent) error {
+			serialized, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			_, err = w.Write(append(serialized, '\n'))
+			if err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("liveTailHandler: %v", err)
+		}
+	})
+}
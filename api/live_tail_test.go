@@ -0,0 +1,170 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/acls"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/api"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type LiveTailTest struct {
+	test_utils.TestSuite
+}
+
+func (self *LiveTailTest) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for _, client_id := range []string{"C.1", "C.2"} {
+		client_path_manager := paths.NewClientPathManager(client_id)
+		err = db.SetSubject(self.ConfigObj, client_path_manager.Path(),
+			&actions_proto.ClientInfo{
+				ClientId: client_id,
+				Hostname: "Host" + client_id,
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	assert.NoError(self.T(),
+		services.GrantRoles(self.ConfigObj, "reader", []string{"reader"}))
+	assert.NoError(self.T(),
+		services.GrantRoles(self.ConfigObj, "analyst", []string{"analyst"}))
+}
+
+func (self *LiveTailTest) TestNewLiveTail() {
+	request := &api.LiveTailRequest{
+		ClientIds: []string{"C.1"},
+		Artifacts: []string{"Generic.Client.Stats"},
+	}
+
+	// Users need to be able to read results.
+	_, err := api.NewLiveTail(self.Ctx, self.ConfigObj, "UserX", request)
+	assert.True(self.T(), errors.Is(err, acls.PermissionDenied))
+
+	_, err = api.NewLiveTail(self.Ctx, self.ConfigObj, "reader", request)
+	assert.NoError(self.T(), err)
+
+	// The filter is arbitrary VQL so readers may not use it.
+	request.Filter = "Name =~ 'powershell'"
+	_, err = api.NewLiveTail(self.Ctx, self.ConfigObj, "reader", request)
+	assert.True(self.T(), errors.Is(err, acls.PermissionDenied))
+
+	_, err = api.NewLiveTail(self.Ctx, self.ConfigObj, "analyst", request)
+	assert.NoError(self.T(), err)
+
+	for _, invalid := range []*api.LiveTailRequest{
+		// No clients or artifacts
+		{Artifacts: []string{"Generic.Client.Stats"}},
+		{ClientIds: []string{"C.1"}},
+
+		// Only client event artifacts can be watched.
+		{ClientIds: []string{"C.1"},
+			Artifacts: []string{"Generic.Client.Info"}},
+		{ClientIds: []string{"C.1"},
+			Artifacts: []string{"Server.Monitor.Health"}},
+		{ClientIds: []string{"C.1"},
+			Artifacts: []string{"Unknown.Artifact"}},
+
+		// Not a client id
+		{ClientIds: []string{"server"},
+			Artifacts: []string{"Generic.Client.Stats"}},
+
+		// The filter does not parse.
+		{ClientIds: []string{"C.1"},
+			Artifacts: []string{"Generic.Client.Stats"},
+			Filter:    "Name =~"},
+	} {
+		_, err = api.NewLiveTail(self.Ctx, self.ConfigObj, "analyst", invalid)
+		assert.True(self.T(), errors.Is(err, api.InvalidLiveTailRequest),
+			"Expected an invalid request error for %v", invalid)
+	}
+}
+
+func (self *LiveTailTest) TestLiveTailMatch() {
+	tail, err := api.NewLiveTail(self.Ctx, self.ConfigObj, "analyst",
+		&api.LiveTailRequest{
+			ClientIds: []string{"C.1"},
+			Artifacts: []string{"Generic.Client.Stats"},
+			Filter:    "Name =~ 'powershell'",
+		})
+	assert.NoError(self.T(), err)
+
+	ctx, cancel := context.WithCancel(self.Ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var events []*api.LiveTailEvent
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		err := tail.Run(ctx, func(event *api.LiveTailEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+			return nil
+		})
+		assert.NoError(self.T(), err)
+	}()
+
+	journal, err := services.GetJournal(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Keep pushing events until the tail is watching the queue.
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		err := journal.PushRowsToArtifact(self.ConfigObj,
+			[]*ordereddict.Dict{
+				ordereddict.NewDict().
+					Set("ClientId", "C.1").
+					Set("Name", "powershell.exe"),
+
+				// Rejected by the filter
+				ordereddict.NewDict().
+					Set("ClientId", "C.1").
+					Set("Name", "cmd.exe"),
+
+				// Not a selected client
+				ordereddict.NewDict().
+					Set("ClientId", "C.2").
+					Set("Name", "powershell.exe"),
+			}, "Generic.Client.Stats", "C.1", "")
+		assert.NoError(self.T(), err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) > 0
+	})
+
+	cancel()
+	wg.Wait()
+
+	for _, event := range events {
+		assert.Equal(self.T(), "Generic.Client.Stats", event.Artifact)
+		assert.Equal(self.T(), "C.1", event.ClientId)
+		assert.Equal(self.T(), "HostC.1", event.Hostname)
+
+		name, _ := event.Row.GetString("Name")
+		assert.Equal(self.T(), "powershell.exe", name)
+	}
+}
+
+func TestLiveTail(t *testing.T) {
+	suite.Run(t, &LiveTailTest{})
+}
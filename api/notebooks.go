@@ -86,6 +86,15 @@ func (self *ApiServer) GetNotebooks(
 			return nil, InvalidStatus("User has no access to this notebook")
 		}
 
+		if notebook_metadata.Context != nil &&
+			notebook_metadata.Context.ClientId != "" {
+			logging.LogAudit(org_config_obj, principal, "ViewNotebook",
+				logrus.Fields{
+					"notebook":  in.NotebookId,
+					"client_id": notebook_metadata.Context.ClientId,
+				})
+		}
+
 		result.Items = append(result.Items, notebook_metadata)
 		return result, nil
 	}
@@ -153,6 +162,11 @@ func (self *ApiServer) NewNotebookCell(
 			"User is not allowed to edit notebooks.")
 	}
 
+	err = checkNotebookStepUp(ctx, org_config_obj, user_record, in.Input)
+	if err != nil {
+		return nil, Status(self.verbose, err)
+	}
+
 	notebook_manager, err := services.GetNotebookManager(org_config_obj)
 	if err != nil {
 		return nil, Status(self.verbose, err)
@@ -297,6 +311,11 @@ func (self *ApiServer) UpdateNotebookCell(
 			"User is not allowed to edit notebooks.")
 	}
 
+	err = checkNotebookStepUp(ctx, org_config_obj, user_record, in.Input)
+	if err != nil {
+		return nil, Status(self.verbose, err)
+	}
+
 	notebook_manager, err := services.GetNotebookManager(org_config_obj)
 	if err != nil {
 		return nil, Status(self.verbose, err)
@@ -255,6 +255,265 @@ func (x *ListChildrenResponse) GetChildren() []*DSPathSpec {
 	return nil
 }
 
+// Records a filestore file that was moved to the cold tier.
+type FilestoreTierRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Components   []string `protobuf:"bytes,1,rep,name=components,proto3" json:"components,omitempty"`
+	PathType     int64    `protobuf:"varint,2,opt,name=path_type,json=pathType,proto3" json:"path_type,omitempty"`
+	Size         int64    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime      int64    `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	MigratedTime int64    `protobuf:"varint,5,opt,name=migrated_time,json=migratedTime,proto3" json:"migrated_time,omitempty"`
+}
+
+func (x *FilestoreTierRecord) Reset() {
+	*x = FilestoreTierRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_datastore_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilestoreTierRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilestoreTierRecord) ProtoMessage() {}
+
+func (x *FilestoreTierRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_datastore_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilestoreTierRecord.ProtoReflect.Descriptor instead.
+func (*FilestoreTierRecord) Descriptor() ([]byte, []int) {
+	return file_datastore_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FilestoreTierRecord) GetComponents() []string {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *FilestoreTierRecord) GetPathType() int64 {
+	if x != nil {
+		return x.PathType
+	}
+	return 0
+}
+
+func (x *FilestoreTierRecord) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FilestoreTierRecord) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+func (x *FilestoreTierRecord) GetMigratedTime() int64 {
+	if x != nil {
+		return x.MigratedTime
+	}
+	return 0
+}
+
+// The latest crash of a client running a particular version. Stored
+// in the crash index so crashing clients can be listed by version.
+type CrashIndexRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClientId    string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Version     string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Count       uint64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	FirstCrash  uint64 `protobuf:"varint,4,opt,name=first_crash,json=firstCrash,proto3" json:"first_crash,omitempty"`
+	LastCrash   uint64 `protobuf:"varint,5,opt,name=last_crash,json=lastCrash,proto3" json:"last_crash,omitempty"`
+	LastReason  string `protobuf:"bytes,6,opt,name=last_reason,json=lastReason,proto3" json:"last_reason,omitempty"`
+	LastCrashId string `protobuf:"bytes,7,opt,name=last_crash_id,json=lastCrashId,proto3" json:"last_crash_id,omitempty"`
+}
+
+func (x *CrashIndexRecord) Reset() {
+	*x = CrashIndexRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_datastore_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CrashIndexRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrashIndexRecord) ProtoMessage() {}
+
+func (x *CrashIndexRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_datastore_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrashIndexRecord.ProtoReflect.Descriptor instead.
+func (*CrashIndexRecord) Descriptor() ([]byte, []int) {
+	return file_datastore_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CrashIndexRecord) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *CrashIndexRecord) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *CrashIndexRecord) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CrashIndexRecord) GetFirstCrash() uint64 {
+	if x != nil {
+		return x.FirstCrash
+	}
+	return 0
+}
+
+func (x *CrashIndexRecord) GetLastCrash() uint64 {
+	if x != nil {
+		return x.LastCrash
+	}
+	return 0
+}
+
+func (x *CrashIndexRecord) GetLastReason() string {
+	if x != nil {
+		return x.LastReason
+	}
+	return ""
+}
+
+func (x *CrashIndexRecord) GetLastCrashId() string {
+	if x != nil {
+		return x.LastCrashId
+	}
+	return ""
+}
+
+// Bytes of uploads stored in the filestore by a client or a hunt.
+type FilestoreQuotaRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Either "client" or "hunt".
+	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Bytes uint64 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// Bytes we did not store because the quota was exceeded.
+	RefusedBytes uint64 `protobuf:"varint,4,opt,name=refused_bytes,json=refusedBytes,proto3" json:"refused_bytes,omitempty"`
+	Updated      int64  `protobuf:"varint,5,opt,name=updated,proto3" json:"updated,omitempty"`
+}
+
+func (x *FilestoreQuotaRecord) Reset() {
+	*x = FilestoreQuotaRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_datastore_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilestoreQuotaRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilestoreQuotaRecord) ProtoMessage() {}
+
+func (x *FilestoreQuotaRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_datastore_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilestoreQuotaRecord.ProtoReflect.Descriptor instead.
+func (*FilestoreQuotaRecord) Descriptor() ([]byte, []int) {
+	return file_datastore_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FilestoreQuotaRecord) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *FilestoreQuotaRecord) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FilestoreQuotaRecord) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *FilestoreQuotaRecord) GetRefusedBytes() uint64 {
+	if x != nil {
+		return x.RefusedBytes
+	}
+	return 0
+}
+
+func (x *FilestoreQuotaRecord) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
 var File_datastore_proto protoreflect.FileDescriptor
 
 var file_datastore_proto_rawDesc = []byte{
@@ -281,10 +540,45 @@ var file_datastore_proto_rawDesc = []byte{
 	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65,
 	0x6e, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
 	0x44, 0x53, 0x50, 0x61, 0x74, 0x68, 0x53, 0x70, 0x65, 0x63, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c,
-	0x64, 0x72, 0x65, 0x6e, 0x42, 0x31, 0x5a, 0x2f, 0x77, 0x77, 0x77, 0x2e, 0x76, 0x65, 0x6c, 0x6f,
-	0x63, 0x69, 0x64, 0x65, 0x78, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67,
-	0x2f, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x72, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2f, 0x61, 0x70,
-	0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x64, 0x72, 0x65, 0x6e, 0x22, 0xa6, 0x01, 0x0a, 0x13, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x54, 0x69, 0x65, 0x72, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x61, 0x74, 0x68, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x70, 0x61, 0x74, 0x68, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x19, 0x0a,
+	0x08, 0x6d, 0x6f, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x6d, 0x6f, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x69, 0x67, 0x72,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0c, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xe4, 0x01,
+	0x0a, 0x10, 0x43, 0x72, 0x61, 0x73, 0x68, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x63, 0x72, 0x61, 0x73, 0x68, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x43, 0x72, 0x61, 0x73, 0x68,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x63, 0x72, 0x61, 0x73, 0x68, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x72, 0x61, 0x73, 0x68, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x22, 0x0a, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x63, 0x72, 0x61, 0x73, 0x68, 0x5f, 0x69,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x72, 0x61,
+	0x73, 0x68, 0x49, 0x64, 0x22, 0x93, 0x01, 0x0a, 0x14, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x72,
+	0x65, 0x66, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x73, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x42, 0x31, 0x5a, 0x2f, 0x77, 0x77,
+	0x77, 0x2e, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x64, 0x65, 0x78, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2f, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x72, 0x61, 0x70,
+	0x74, 0x6f, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -299,12 +593,15 @@ func file_datastore_proto_rawDescGZIP() []byte {
 	return file_datastore_proto_rawDescData
 }
 
-var file_datastore_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_datastore_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_datastore_proto_goTypes = []interface{}{
 	(*DSPathSpec)(nil),           // 0: proto.DSPathSpec
 	(*DataRequest)(nil),          // 1: proto.DataRequest
 	(*DataResponse)(nil),         // 2: proto.DataResponse
 	(*ListChildrenResponse)(nil), // 3: proto.ListChildrenResponse
+	(*FilestoreTierRecord)(nil),  // 4: proto.FilestoreTierRecord
+	(*CrashIndexRecord)(nil),     // 5: proto.CrashIndexRecord
+	(*FilestoreQuotaRecord)(nil), // 6: proto.FilestoreQuotaRecord
 }
 var file_datastore_proto_depIdxs = []int32{
 	0, // 0: proto.DataRequest.pathspec:type_name -> proto.DSPathSpec
@@ -370,6 +667,42 @@ func file_datastore_proto_init() {
 				return nil
 			}
 		}
+		file_datastore_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilestoreTierRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_datastore_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CrashIndexRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_datastore_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilestoreQuotaRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -377,7 +710,7 @@ func file_datastore_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_datastore_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -150,6 +150,36 @@ func PrepareGUIMux(
 	mux.Handle(base+"/api/v1/UploadFormFile", csrfProtect(config_obj,
 		auther.AuthenticateUserHandler(formUploadHandler())))
 
+	mux.Handle(base+"/api/v1/ResponseAction", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(responseActionHandler())))
+
+	mux.Handle(base+"/api/v1/Sessions", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(sessionsHandler())))
+
+	mux.Handle(base+"/api/v1/ServerJobs", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(serverJobsHandler())))
+
+	mux.Handle(base+"/api/v1/ArtifactVersions", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(artifactVersionsHandler())))
+
+	mux.Handle(base+"/api/v1/ArtifactStaging", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(artifactStagingHandler())))
+
+	mux.Handle(base+"/api/v1/ArtifactSchemas", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(artifactSchemasHandler())))
+
+	mux.Handle(base+"/api/v1/StepUp", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(stepUpHandler())))
+
+	mux.Handle(base+"/api/v1/WebAuthn", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(webAuthnHandler())))
+
+	mux.Handle(base+"/api/v1/ClientAccessReport", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(clientAccessReportHandler())))
+
+	mux.Handle(base+"/api/v1/LiveTail", csrfProtect(config_obj,
+		auther.AuthenticateUserHandler(liveTailHandler())))
+
 	// Serve prepared zip files.
 	mux.Handle(base+"/downloads/", csrfProtect(config_obj,
 		auther.AuthenticateUserHandler(
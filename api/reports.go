@@ -61,10 +61,8 @@ func getReport(ctx context.Context,
 	var template_data string
 
 	if in.Type == "" {
-		definition, pres := repository.Get(config_obj, "Custom."+in.Artifact)
-		if !pres {
-			definition, pres = repository.Get(config_obj, in.Artifact)
-		}
+		definition, pres := services.ResolveArtifact(
+			config_obj, repository, in.Artifact, true)
 		if pres {
 			for _, report := range definition.Reports {
 				in.Type = strings.ToUpper(report.Type)
@@ -0,0 +1,348 @@
+package api
+
+// The response console offers a curated set of response actions
+// (kill a process, delete a file, block a hash, disable a user).
+
+// Each action launches a vetted built in artifact on behalf of the
+// user and is gated by its own permission, so analysts are able to
+// respond under pressure without being granted COLLECT_CLIENT or
+// EXECVE and without hand writing VQL. Every action taken or denied
+// is recorded in the audit log.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+var (
+	InvalidResponseAction = errors.New("InvalidResponseAction")
+)
+
+type responseAction struct {
+	description string
+	permission  acls.ACL_PERMISSION
+
+	// The vetted artifact to launch for each client OS.
+	artifacts map[services.ClientOS]string
+
+	// The artifact parameters the request may set.
+	parameters []string
+	required   []string
+}
+
+var responseActions = map[string]*responseAction{
+	"kill_process": {
+		description: "Kill a process.",
+		permission:  acls.RESPONSE_KILL_PROCESS,
+		artifacts: map[services.ClientOS]string{
+			services.Windows: "Generic.Response.KillProcess",
+			services.Linux:   "Generic.Response.KillProcess",
+			services.MacOS:   "Generic.Response.KillProcess",
+		},
+		parameters: []string{"Pid", "ProcessName", "KillTree"},
+		required:   []string{"Pid"},
+	},
+	"delete_file": {
+		description: "Delete a file, keeping a copy on the server.",
+		permission:  acls.RESPONSE_DELETE_FILE,
+		artifacts: map[services.ClientOS]string{
+			services.Windows: "Generic.Response.DeleteFile",
+			services.Linux:   "Generic.Response.DeleteFile",
+			services.MacOS:   "Generic.Response.DeleteFile",
+		},
+		parameters: []string{"Path", "ExpectedHash", "Backup"},
+		required:   []string{"Path"},
+	},
+	"block_hash": {
+		description: "Block an executable by hash with an AppLocker or WDAC policy.",
+		permission:  acls.RESPONSE_BLOCK_HASH,
+		artifacts: map[services.ClientOS]string{
+			services.Windows: "Windows.Response.BlockHash",
+		},
+		parameters: []string{"Hash", "FileName", "Policy", "RuleName"},
+		required:   []string{"Hash"},
+	},
+	"disable_user": {
+		description: "Disable a local user account.",
+		permission:  acls.RESPONSE_DISABLE_USER,
+		artifacts: map[services.ClientOS]string{
+			services.Windows: "Windows.Response.DisableUser",
+			services.Linux:   "Linux.Response.DisableUser",
+		},
+		parameters: []string{"Username"},
+		required:   []string{"Username"},
+	},
+}
+
+type ResponseActionRequest struct {
+	ClientId   string            `json:"client_id"`
+	Action     string            `json:"action"`
+	Parameters map[string]string `json:"parameters"`
+
+	// Recorded in the audit log to explain why the action was taken.
+	Reason string `json:"reason"`
+}
+
+type ResponseActionResponse struct {
+	ClientId string `json:"client_id"`
+	Action   string `json:"action"`
+	Artifact string `json:"artifact"`
+	FlowId   string `json:"flow_id"`
+}
+
+// Describes an action to the response console.
+type ResponseActionDescription struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permission  string   `json:"permission"`
+	Parameters  []string `json:"parameters"`
+	Required    []string `json:"required"`
+
+	// Is the user allowed to run this action?
+	Allowed bool `json:"allowed"`
+}
+
+// List the response actions and whether the principal may run them.
+func ListResponseActions(
+	config_obj *config_proto.Config,
+	principal string) []*ResponseActionDescription {
+
+	result := []*ResponseActionDescription{}
+	for name, action := range responseActions {
+		allowed, _ := services.CheckAccess(
+			config_obj, principal, action.permission)
+
+		result = append(result, &ResponseActionDescription{
+			Name:        name,
+			Description: action.description,
+			Permission:  action.permission.String(),
+			Parameters:  action.parameters,
+			Required:    action.required,
+			Allowed:     allowed,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// Launch the vetted artifact for the response action on the client.
+func ScheduleResponseAction(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	principal string,
+	request *ResponseActionRequest) (*ResponseActionResponse, error) {
+
+	action, pres := responseActions[request.Action]
+	if !pres {
+		return nil, fmt.Errorf("%w: Unknown action %v",
+			InvalidResponseAction, request.Action)
+	}
+
+	perm, err := services.CheckAccess(config_obj, principal, action.permission)
+	if !perm || err != nil {
+		logging.LogAudit(config_obj, principal, "ResponseActionDenied",
+			logrus.Fields{
+				"client": request.ClientId,
+				"action": request.Action,
+			})
+		return nil, fmt.Errorf("%w: User is not allowed to %v (requires %v)",
+			acls.PermissionDenied, request.Action, action.permission)
+	}
+
+	if !strings.HasPrefix(request.ClientId, "C.") {
+		return nil, fmt.Errorf("%w: Response actions can only run on clients",
+			InvalidResponseAction)
+	}
+
+	for name := range request.Parameters {
+		if !utils.InString(action.parameters, name) {
+			return nil, fmt.Errorf("%w: Unknown parameter %v for %v",
+				InvalidResponseAction, name, request.Action)
+		}
+	}
+
+	for _, name := range action.required {
+		if request.Parameters[name] == "" {
+			return nil, fmt.Errorf("%w: Parameter %v is required for %v",
+				InvalidResponseAction, name, request.Action)
+		}
+	}
+
+	env := []*actions_proto.VQLEnv{}
+	for _, name := range action.parameters {
+		value, pres := request.Parameters[name]
+		if pres {
+			env = append(env, &actions_proto.VQLEnv{Key: name, Value: value})
+		}
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	client_info, err := client_info_manager.Get(ctx, request.ClientId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Unknown client %v",
+			InvalidResponseAction, request.ClientId)
+	}
+
+	artifact_name, pres := action.artifacts[client_info.OS()]
+	if !pres {
+		return nil, fmt.Errorf("%w: %v is not supported on %v",
+			InvalidResponseAction, request.Action, client_info.System)
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only launch the built in artifact - a custom artifact of the
+	// same name has not been vetted.
+	artifact, pres := repository.Get(config_obj, artifact_name)
+	if !pres || !artifact.BuiltIn {
+		return nil, fmt.Errorf("Vetted artifact %v is not available",
+			artifact_name)
+	}
+
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user is not necessarily allowed to collect the artifact
+	// directly, but it is launched on their behalf.
+	flow_id, err := launcher.ScheduleArtifactCollection(
+		ctx, config_obj, acl_managers.NullACLManager{}, repository,
+		&flows_proto.ArtifactCollectorArgs{
+			Creator:  principal,
+			ClientId: request.ClientId,
+			Urgent:   true,
+			Specs: []*flows_proto.ArtifactSpec{{
+				Artifact: artifact_name,
+				Parameters: &flows_proto.ArtifactParameters{
+					Env: env,
+				},
+			}},
+		}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.LogAudit(config_obj, principal, "ResponseAction",
+		logrus.Fields{
+			"client":     request.ClientId,
+			"action":     request.Action,
+			"artifact":   artifact_name,
+			"flow_id":    flow_id,
+			"parameters": json.MustMarshalString(request.Parameters),
+			"reason":     request.Reason,
+		})
+
+	return &ResponseActionResponse{
+		ClientId: request.ClientId,
+		Action:   request.Action,
+		Artifact: artifact_name,
+		FlowId:   flow_id,
+	}, nil
+}
+
+// GET lists the available actions, POST runs an action.
+func responseActionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+
+		var result interface{}
+		switch r.Method {
+		case "GET":
+			result = ListResponseActions(org_config_obj, userinfo.Name)
+
+		case "POST":
+			data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			request := &ResponseActionRequest{}
+			err = json.Unmarshal(data, request)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			err = CheckStepUp(r.Context(), org_config_obj,
+				userinfo, STEP_UP_RESPONSE_ACTION)
+			if err == nil {
+				result, err = ScheduleResponseAction(
+					r.Context(), org_config_obj, userinfo.Name, request)
+			}
+			if errors.Is(err, acls.PermissionDenied) {
+				returnError(w, http.StatusForbidden, err.Error())
+				return
+			}
+
+			if errors.Is(err, InvalidResponseAction) {
+				returnError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			if err != nil {
+				returnError(w, http.StatusInternalServerError,
+					fmt.Sprintf("Error: %v", err))
+				return
+			}
+
+		default:
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("responseActionHandler: %v", err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/acls"
+	acl_proto "www.velocidex.com/golang/velociraptor/acls/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/api"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+)
+
+type ResponseActionTest struct {
+	test_utils.TestSuite
+}
+
+func (self *ResponseActionTest) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.LoadArtifactFiles(
+		"../artifacts/definitions/Generic/Response/KillProcess.yaml",
+		"../artifacts/definitions/Linux/Response/DisableUser.yaml",
+		"../artifacts/definitions/Windows/Response/BlockHash.yaml")
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for client_id, system := range map[string]string{
+		"C.1234": "linux",
+		"C.5678": "windows",
+	} {
+		client_path_manager := paths.NewClientPathManager(client_id)
+		err = db.SetSubject(self.ConfigObj, client_path_manager.Path(),
+			&actions_proto.ClientInfo{
+				ClientId: client_id,
+				Hostname: "Hostname",
+				System:   system,
+			})
+		assert.NoError(self.T(), err)
+	}
+}
+
+func (self *ResponseActionTest) TestResponseActions() {
+	ctx := context.Background()
+
+	request := &api.ResponseActionRequest{
+		ClientId: "C.1234",
+		Action:   "kill_process",
+		Parameters: map[string]string{
+			"Pid":         "1234",
+			"ProcessName": "evil",
+		},
+		Reason: "Incident 42",
+	}
+
+	// Not allowed without the permission.
+	_, err := api.ScheduleResponseAction(ctx, self.ConfigObj, "UserX", request)
+	assert.Error(self.T(), err)
+	assert.True(self.T(), errors.Is(err, acls.PermissionDenied))
+
+	// The response permission is sufficient - the user does not need
+	// COLLECT_CLIENT or EXECVE.
+	err = services.SetPolicy(self.ConfigObj, "UserX",
+		&acl_proto.ApiClientACL{
+			ResponseKillProcess: true,
+			ResponseBlockHash:   true,
+		})
+	assert.NoError(self.T(), err)
+
+	response, err := api.ScheduleResponseAction(
+		ctx, self.ConfigObj, "UserX", request)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Generic.Response.KillProcess", response.Artifact)
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	details, err := launcher.GetFlowDetails(
+		self.ConfigObj, "C.1234", response.FlowId)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "UserX", details.Context.Request.Creator)
+
+	// Other actions still need their own permission.
+	_, err = api.ScheduleResponseAction(ctx, self.ConfigObj, "UserX",
+		&api.ResponseActionRequest{
+			ClientId:   "C.1234",
+			Action:     "disable_user",
+			Parameters: map[string]string{"Username": "bob"},
+		})
+	assert.True(self.T(), errors.Is(err, acls.PermissionDenied))
+
+	// Parameters are checked.
+	_, err = api.ScheduleResponseAction(ctx, self.ConfigObj, "UserX",
+		&api.ResponseActionRequest{
+			ClientId: "C.1234",
+			Action:   "kill_process",
+			Parameters: map[string]string{
+				"Pid":     "1234",
+				"Command": "rm -rf /",
+			},
+		})
+	assert.True(self.T(), errors.Is(err, api.InvalidResponseAction))
+
+	_, err = api.ScheduleResponseAction(ctx, self.ConfigObj, "UserX",
+		&api.ResponseActionRequest{
+			ClientId: "C.1234",
+			Action:   "kill_process",
+		})
+	assert.True(self.T(), errors.Is(err, api.InvalidResponseAction))
+
+	// Blocking a hash is only supported on Windows.
+	block_request := &api.ResponseActionRequest{
+		ClientId: "C.1234",
+		Action:   "block_hash",
+		Parameters: map[string]string{
+			"Hash": "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855",
+		},
+	}
+	_, err = api.ScheduleResponseAction(
+		ctx, self.ConfigObj, "UserX", block_request)
+	assert.True(self.T(), errors.Is(err, api.InvalidResponseAction))
+
+	block_request.ClientId = "C.5678"
+	response, err = api.ScheduleResponseAction(
+		ctx, self.ConfigObj, "UserX", block_request)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Windows.Response.BlockHash", response.Artifact)
+
+	// The listing shows which actions the user may run.
+	allowed := []string{}
+	for _, action := range api.ListResponseActions(self.ConfigObj, "UserX") {
+		if action.Allowed {
+			allowed = append(allowed, action.Name)
+		}
+	}
+	assert.Equal(self.T(), []string{"block_hash", "kill_process"}, allowed)
+}
+
+func TestResponseActions(t *testing.T) {
+	suite.Run(t, &ResponseActionTest{})
+}
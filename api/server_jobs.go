@@ -0,0 +1,167 @@
+package api
+
+// Lists and cancels long running server jobs (e.g. bulk client
+// operations) so the GUI can show their progress.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	InvalidServerJobRequest = errors.New("InvalidServerJobRequest")
+)
+
+type CancelServerJobRequest struct {
+	JobId string `json:"job_id"`
+}
+
+func checkServerJobAccess(config_obj *config_proto.Config,
+	principal string, permission acls.ACL_PERMISSION) error {
+	ok, err := services.CheckAccess(config_obj, principal, permission)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: User %v does not have %v permission",
+			acls.PermissionDenied, principal, permission)
+	}
+	return nil
+}
+
+// List all current and past jobs, or just the job with job_id.
+func ListServerJobs(ctx context.Context,
+	config_obj *config_proto.Config,
+	principal, job_id string) ([]*api_proto.ServerJob, error) {
+	err := checkServerJobAccess(config_obj, principal, acls.READ_RESULTS)
+	if err != nil {
+		return nil, err
+	}
+
+	job_manager, err := services.GetJobManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if job_id == "" {
+		return job_manager.ListJobs(ctx)
+	}
+
+	job, err := job_manager.GetJob(ctx, job_id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidServerJobRequest, err)
+	}
+	return []*api_proto.ServerJob{job}, nil
+}
+
+func CancelServerJob(ctx context.Context,
+	config_obj *config_proto.Config,
+	principal string, request *CancelServerJobRequest) (
+	*api_proto.ServerJob, error) {
+	err := checkServerJobAccess(config_obj, principal, acls.SERVER_ADMIN)
+	if err != nil {
+		return nil, err
+	}
+
+	job_manager, err := services.GetJobManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.LogAudit(config_obj, principal, "cancel_server_job",
+		logrus.Fields{
+			"job_id": request.JobId,
+		})
+
+	err = job_manager.CancelJob(ctx, request.JobId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidServerJobRequest, err)
+	}
+
+	return job_manager.GetJob(ctx, request.JobId)
+}
+
+// GET /api/v1/ServerJobs?job_id= lists jobs, POST cancels a job.
+func serverJobsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+
+		var result interface{}
+		switch r.Method {
+		case "GET":
+			result, err = ListServerJobs(r.Context(), org_config_obj,
+				userinfo.Name, r.URL.Query().Get("job_id"))
+
+		case "POST":
+			var data []byte
+			data, err = io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			request := &CancelServerJobRequest{}
+			err = json.Unmarshal(data, request)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			result, err = CancelServerJob(r.Context(), org_config_obj,
+				userinfo.Name, request)
+
+		default:
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, InvalidServerJobRequest) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("serverJobsHandler: %v", err)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package api
+
+// Lists and revokes authenticated GUI and API sessions.
+
+// Users may always see and revoke their own sessions. Managing other
+// users' sessions requires SERVER_ADMIN in the root org because
+// sessions are not specific to an org.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	InvalidSessionRequest = errors.New("InvalidSessionRequest")
+)
+
+type RevokeSessionsRequest struct {
+	// Revoke a single session.
+	SessionId string `json:"session_id"`
+
+	// Revoke all the sessions of this user.
+	Username string `json:"username"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+func checkSessionAccess(principal, username string) error {
+	if username != "" && username == principal {
+		return nil
+	}
+
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return err
+	}
+
+	root_config_obj, err := org_manager.GetOrgConfig(services.ROOT_ORG_ID)
+	if err != nil {
+		return err
+	}
+
+	permissions := acls.SERVER_ADMIN
+	ok, err := services.CheckAccess(root_config_obj, principal, permissions)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: User %v is not allowed to manage sessions of %v",
+			acls.PermissionDenied, principal, username)
+	}
+	return nil
+}
+
+func getSessionManager() (services.SessionManager, error) {
+	session_manager := services.GetSessionManager()
+	if session_manager == nil {
+		return nil, errors.New("Session manager not running")
+	}
+	return session_manager, nil
+}
+
+// List the active sessions of the user (or all users if username is
+// empty).
+func ListSessions(ctx context.Context,
+	principal, username string) ([]*api_proto.GUISession, error) {
+	err := checkSessionAccess(principal, username)
+	if err != nil {
+		return nil, err
+	}
+
+	session_manager, err := getSessionManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return session_manager.ListSessions(ctx, username)
+}
+
+func RevokeSessions(ctx context.Context, principal string,
+	request *RevokeSessionsRequest) (*RevokeSessionsResponse, error) {
+	session_manager, err := getSessionManager()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case request.SessionId != "":
+		session, err := session_manager.GetSession(ctx, request.SessionId)
+		if errors.Is(err, services.SessionNotFoundError) {
+			return nil, fmt.Errorf("%w: %v", InvalidSessionRequest, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkSessionAccess(principal, session.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		err = session_manager.RevokeSession(ctx, principal, request.SessionId)
+		if err != nil {
+			return nil, err
+		}
+		return &RevokeSessionsResponse{Revoked: 1}, nil
+
+	case request.Username != "":
+		err := checkSessionAccess(principal, request.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := session_manager.RevokeUserSessions(
+			ctx, principal, request.Username)
+		if err != nil {
+			return nil, err
+		}
+		return &RevokeSessionsResponse{Revoked: count}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: session_id or username is required",
+			InvalidSessionRequest)
+	}
+}
+
+func sessionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+
+		var result interface{}
+		switch r.Method {
+		case "GET":
+			result, err = ListSessions(r.Context(), userinfo.Name,
+				r.URL.Query().Get("username"))
+
+		case "POST":
+			var data []byte
+			data, err = io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			request := &RevokeSessionsRequest{}
+			err = json.Unmarshal(data, request)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			result, err = RevokeSessions(r.Context(), userinfo.Name, request)
+
+		default:
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, InvalidSessionRequest) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("sessionsHandler: %v", err)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	errors "github.com/go-errors/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"www.velocidex.com/golang/velociraptor/acls"
 )
 
 // Convert from various errors into gRPC status errors. This will be
@@ -17,6 +18,10 @@ func Status(verbose bool, err error) error {
 		return err
 	}
 
+	if errors.Is(err, acls.PermissionDenied) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
 	// With the verbose flag give more detailed errors to the browser.
 	if verbose {
 		if errors.Is(err, os.ErrNotExist) {
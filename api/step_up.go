@@ -0,0 +1,428 @@
+package api
+
+// Step-up authentication for sensitive operations.
+
+// Operations listed in GUI.step_up_operations require the user to
+// re-authenticate with a TOTP code in their current session within
+// the last GUI.step_up_validity seconds. API clients authenticating
+// with certificates have no session and are not prompted.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/users"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// Collecting artifacts that require EXECVE (e.g. the shell
+	// viewer).
+	STEP_UP_SHELL = "shell"
+
+	// Saving, deleting or rolling back custom artifacts.
+	STEP_UP_ARTIFACT_EDIT = "artifact_edit"
+
+	STEP_UP_RESPONSE_ACTION = "response_action"
+
+	DEFAULT_STEP_UP_VALIDITY = 300
+)
+
+var (
+	// Wraps PermissionDenied so callers reject the request but the
+	// GUI can tell it should prompt for a code.
+	StepUpRequiredError = fmt.Errorf("%w: StepUpRequired", acls.PermissionDenied)
+
+	InvalidStepUpRequest = errors.New("InvalidStepUpRequest")
+)
+
+type StepUpRequest struct {
+	// One of enroll, verify or reset
+	Action string `json:"action"`
+	Code   string `json:"code"`
+
+	// The user to enroll or reset (defaults to the principal).
+	Username string `json:"username"`
+}
+
+type StepUpStatus struct {
+	Enrolled   bool     `json:"enrolled"`
+	Operations []string `json:"operations"`
+
+	// Time in seconds since epoch the current step-up expires.
+	ValidUntil uint64 `json:"valid_until"`
+
+	// Only returned when enrolling.
+	Secret string `json:"secret,omitempty"`
+	Url    string `json:"url,omitempty"`
+}
+
+func getStepUpValidity(config_obj *config_proto.Config) uint64 {
+	if config_obj.GUI != nil && config_obj.GUI.StepUpValidity > 0 {
+		return config_obj.GUI.StepUpValidity
+	}
+	return DEFAULT_STEP_UP_VALIDITY
+}
+
+// Users and their MFA enrollments live in the root org.
+func getRootConfig() (*config_proto.Config, error) {
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return org_manager.GetOrgConfig(services.ROOT_ORG_ID)
+}
+
+func getStepUpValidUntil(ctx context.Context,
+	config_obj *config_proto.Config, session_id string) (uint64, error) {
+	session_manager, err := getSessionManager()
+	if err != nil {
+		return 0, err
+	}
+
+	session, err := session_manager.GetSession(ctx, session_id)
+	if err != nil {
+		return 0, err
+	}
+
+	if session.StepUpTime == 0 {
+		return 0, nil
+	}
+	return session.StepUpTime + getStepUpValidity(config_obj), nil
+}
+
+// Returns StepUpRequiredError if the operation requires step-up and
+// the user did not recently re-authenticate in this session.
+func CheckStepUp(ctx context.Context, config_obj *config_proto.Config,
+	user_record *api_proto.VelociraptorUser, operation string) error {
+	if config_obj.GUI == nil ||
+		!utils.InString(config_obj.GUI.StepUpOperations, operation) {
+		return nil
+	}
+
+	return checkSteppedUp(ctx, config_obj, user_record, operation)
+}
+
+func checkSteppedUp(ctx context.Context, config_obj *config_proto.Config,
+	user_record *api_proto.VelociraptorUser, operation string) error {
+	if user_record.SessionId == "" {
+		return nil
+	}
+
+	valid_until, err := getStepUpValidUntil(
+		ctx, config_obj, user_record.SessionId)
+	if err != nil {
+		return err
+	}
+
+	if valid_until < uint64(utils.GetTime().Now().Unix()) {
+		return fmt.Errorf("%w: %v requires a TOTP code",
+			StepUpRequiredError, operation)
+	}
+	return nil
+}
+
+// Returns StepUpRequiredError if any of the artifacts require EXECVE
+// and the shell operation requires step-up.
+func checkArtifactsStepUp(ctx context.Context,
+	config_obj *config_proto.Config,
+	user_record *api_proto.VelociraptorUser, artifacts []string) error {
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range artifacts {
+		artifact, pres := repository.Get(config_obj, name)
+		if pres && utils.InString(artifact.RequiredPermissions, "EXECVE") {
+			return CheckStepUp(ctx, config_obj, user_record, STEP_UP_SHELL)
+		}
+	}
+	return nil
+}
+
+var (
+	notebookShellRegex        = regexp.MustCompile(`(?i)\bexecve\s*\(`)
+	notebookArtifactEditRegex = regexp.MustCompile(
+		`(?i)\bartifact_(set|delete)\s*\(`)
+)
+
+// Notebook cells run arbitrary VQL on the server so cells calling
+// execve() or modifying artifacts need the same step-up as the
+// equivalent GUI operations. Markdown cells may embed queries too so
+// all cell types are checked.
+func checkNotebookStepUp(ctx context.Context,
+	config_obj *config_proto.Config,
+	user_record *api_proto.VelociraptorUser, input string) error {
+	if notebookShellRegex.MatchString(input) {
+		err := CheckStepUp(ctx, config_obj, user_record, STEP_UP_SHELL)
+		if err != nil {
+			return err
+		}
+	}
+
+	if notebookArtifactEditRegex.MatchString(input) {
+		return CheckStepUp(ctx, config_obj, user_record, STEP_UP_ARTIFACT_EDIT)
+	}
+	return nil
+}
+
+func GetStepUpStatus(ctx context.Context, config_obj *config_proto.Config,
+	user_record *api_proto.VelociraptorUser) (*StepUpStatus, error) {
+	root_config_obj, err := getRootConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StepUpStatus{Operations: []string{}}
+	if config_obj.GUI != nil {
+		result.Operations = config_obj.GUI.StepUpOperations
+	}
+
+	record, err := users.GetMFA(ctx, root_config_obj, user_record.Name)
+	if err == nil {
+		result.Enrolled = record.Confirmed
+	}
+
+	if user_record.SessionId != "" {
+		result.ValidUntil, _ = getStepUpValidUntil(
+			ctx, config_obj, user_record.SessionId)
+	}
+
+	return result, nil
+}
+
+// Only the first enrollment may be started from the session alone.
+// Replacing an existing (possibly pending) enrollment needs a code
+// from the existing authenticator, and enrolling someone else needs
+// SERVER_ADMIN. Otherwise a stolen session could register its own
+// device.
+func checkEnrollAccess(ctx context.Context,
+	config_obj, root_config_obj *config_proto.Config,
+	user_record *api_proto.VelociraptorUser, username string) error {
+	principal := user_record.Name
+	if username != principal {
+		return checkSessionAccess(principal, username)
+	}
+
+	_, err := users.GetMFA(ctx, root_config_obj, username)
+	if errors.Is(err, users.MFANotEnrolledError) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = checkSteppedUp(ctx, config_obj, user_record, "enroll")
+	if err == nil {
+		return nil
+	}
+
+	// Administrators may always re-enroll.
+	if checkSessionAccess(principal, "") == nil {
+		return nil
+	}
+	return err
+}
+
+func StepUp(ctx context.Context, config_obj *config_proto.Config,
+	user_record *api_proto.VelociraptorUser,
+	request *StepUpRequest) (*StepUpStatus, error) {
+	root_config_obj, err := getRootConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	principal := user_record.Name
+
+	switch request.Action {
+	case "enroll":
+		username := request.Username
+		if username == "" {
+			username = principal
+		}
+
+		err = checkEnrollAccess(ctx, config_obj, root_config_obj,
+			user_record, username)
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := users.EnrollTOTP(ctx, root_config_obj, username)
+		if errors.Is(err, users.MFAAlreadyEnrolledError) {
+			return nil, fmt.Errorf("%w: %v", InvalidStepUpRequest, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		logging.LogAudit(config_obj, principal, "EnrollMFA",
+			logrus.Fields{
+				"username": username,
+			})
+
+		return &StepUpStatus{
+			Operations: []string{},
+			Secret:     record.TotpSecret,
+			Url:        users.GetTOTPUrl(record),
+		}, nil
+
+	case "verify":
+		if user_record.SessionId == "" {
+			return nil, fmt.Errorf("%w: No session to step up",
+				InvalidStepUpRequest)
+		}
+
+		err := users.VerifyTOTP(ctx, root_config_obj, principal, request.Code)
+		if errors.Is(err, users.InvalidTOTPCodeError) ||
+			errors.Is(err, users.MFANotEnrolledError) ||
+			errors.Is(err, users.MFALockedError) {
+			logging.LogAudit(config_obj, principal, "StepUpFailed",
+				logrus.Fields{
+					"remote": user_record.RemoteAddress,
+				})
+			return nil, fmt.Errorf("%w: %v", InvalidStepUpRequest, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		session_manager, err := getSessionManager()
+		if err != nil {
+			return nil, err
+		}
+
+		err = session_manager.StepUpSession(ctx, user_record.SessionId)
+		if err != nil {
+			return nil, err
+		}
+
+		logging.LogAudit(config_obj, principal, "StepUp",
+			logrus.Fields{
+				"remote": user_record.RemoteAddress,
+			})
+
+		return GetStepUpStatus(ctx, config_obj, user_record)
+
+	case "reset":
+		username := request.Username
+		if username == "" {
+			username = principal
+		}
+
+		// Resetting your own enrollment needs a current step-up so
+		// a stolen session can not replace the device. Resetting
+		// someone else's needs SERVER_ADMIN.
+		if username == principal {
+			err = checkSteppedUp(ctx, config_obj, user_record, "reset")
+		} else {
+			err = checkSessionAccess(principal, username)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = users.ResetMFA(ctx, root_config_obj, username)
+		if err != nil {
+			return nil, err
+		}
+
+		logging.LogAudit(config_obj, principal, "ResetMFA",
+			logrus.Fields{
+				"username": username,
+			})
+
+		return GetStepUpStatus(ctx, config_obj, user_record)
+
+	default:
+		return nil, fmt.Errorf("%w: Unknown action %v",
+			InvalidStepUpRequest, request.Action)
+	}
+}
+
+func stepUpHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+
+		var result interface{}
+		switch r.Method {
+		case "GET":
+			result, err = GetStepUpStatus(r.Context(), org_config_obj, userinfo)
+
+		case "POST":
+			var data []byte
+			data, err = io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			request := &StepUpRequest{}
+			err = json.Unmarshal(data, request)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			result, err = StepUp(r.Context(), org_config_obj, userinfo, request)
+
+		default:
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, InvalidStepUpRequest) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("stepUpHandler: %v", err)
+		}
+	})
+}
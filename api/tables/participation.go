@@ -0,0 +1,87 @@
+package tables
+
+import (
+	"context"
+	"time"
+
+	vjson "github.com/Velocidex/json"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// The hunt participation report is not stored in a result set - it
+// is built on demand by the hunt dispatcher. The "stragglers" table
+// only shows clients which did not complete the hunt.
+func getHuntParticipation(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	in *api_proto.GetTableRequest) (
+	*api_proto.GetTableResponse, error) {
+
+	if in.Rows == 0 {
+		in.Rows = 2000
+	}
+
+	hunt_dispatcher, err := services.GetHuntDispatcher(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := hunt_dispatcher.GetParticipation(
+		ctx, config_obj, in.HuntId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.GetTableResponse{
+		Columns: []string{
+			"ClientId", "Hostname", "State", "FlowId",
+			"ScheduledTime", "CompletedTime", "LastSeen", "Error",
+		},
+		ColumnTypes: []*artifacts_proto.ColumnType{
+			{Name: "ClientId", Type: "client_id"},
+			{Name: "ScheduledTime", Type: "timestamp"},
+			{Name: "CompletedTime", Type: "timestamp"},
+			{Name: "LastSeen", Type: "timestamp"},
+		},
+	}
+
+	opts := json.GetJsonOptsForTimezone(in.Timezone)
+
+	for _, participant := range participants {
+		if in.Type == "stragglers" && !participant.IsStraggler() {
+			continue
+		}
+
+		result.TotalRows++
+		if result.TotalRows <= int64(in.StartRow) ||
+			uint64(len(result.Rows)) >= in.Rows {
+			continue
+		}
+
+		result.Rows = append(result.Rows, &api_proto.Row{
+			Cell: []string{
+				participant.ClientId,
+				participant.Hostname,
+				participant.State,
+				participant.FlowId,
+				formatTime(participant.ScheduledTime, opts),
+				formatTime(participant.CompletedTime, opts),
+				formatTime(participant.LastSeen, opts),
+				participant.Error,
+			}})
+	}
+
+	return result, nil
+}
+
+// Times that are not known are left empty.
+func formatTime(sec int64, opts *vjson.EncOpts) string {
+	if sec == 0 {
+		return ""
+	}
+	return json.AnyToString(time.Unix(sec, 0), opts)
+}
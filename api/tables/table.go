@@ -19,6 +19,7 @@ package tables
 
 import (
 	"regexp"
+	"sort"
 	"time"
 
 	errors "github.com/go-errors/errors"
@@ -32,6 +33,8 @@ import (
 	"www.velocidex.com/golang/velociraptor/result_sets"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/timelines"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
@@ -57,6 +60,10 @@ func GetTable(
 	} else if in.Type == "CLIENT_EVENT" || in.Type == "SERVER_EVENT" {
 		result, err = getEventTable(ctx, config_obj, in)
 
+	} else if in.HuntId != "" &&
+		(in.Type == "participation" || in.Type == "stragglers") {
+		result, err = getHuntParticipation(ctx, config_obj, in)
+
 	} else {
 		result, err = getTable(ctx, config_obj, in)
 	}
@@ -78,7 +85,8 @@ func GetTable(
 
 		artifact, pres := repository.Get(config_obj, in.Artifact)
 		if pres {
-			result.ColumnTypes = artifact.ColumnTypes
+			result.ColumnTypes = mergeColumnTypes(
+				artifact.ColumnTypes, result.ColumnTypes)
 		}
 	}
 
@@ -108,6 +116,23 @@ func getTable(
 
 	file_store_factory := file_store.GetFileStore(config_obj)
 
+	// Add the column types inferred when the result set was
+	// written.
+	result.ColumnTypes = mergeColumnTypes(result.ColumnTypes,
+		getInferredColumnTypes(file_store_factory, path_spec))
+
+	schema, err := result_sets.ReadSchema(file_store_factory, path_spec)
+	if err == nil {
+		result.SchemaVersion = schema.Version
+	}
+
+	if in.NormalizeSchema && in.Artifact != "" {
+		result.Columns, err = GetNormalizedColumns(config_obj, in.Artifact)
+		if err != nil {
+			return result, err
+		}
+	}
+
 	options, err := getTableOptions(in)
 	if err != nil {
 		return result, err
@@ -145,8 +170,11 @@ func getTable(
 
 	// Unpack the rows into the output protobuf
 	for row := range rs_reader.Rows(ctx) {
-		if result.Columns == nil {
-			result.Columns = row.Keys()
+		// Result sets written before schemas were tracked may
+		// have columns which no schema version knows about.
+		if rows == 0 {
+			result.Columns = utils.MergeStringSlices(
+				result.Columns, row.Keys())
 		}
 
 		row_data := make([]string, 0, len(result.Columns))
@@ -167,6 +195,22 @@ func getTable(
 	return result, nil
 }
 
+// The union of the columns of all the artifact's schema versions.
+func GetNormalizedColumns(
+	config_obj *config_proto.Config, artifact string) ([]string, error) {
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := manager.ListResultSchemas(config_obj, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.MergeSchemaColumns(schemas), nil
+}
+
 // The GUI is requesting table data. This function tries to figure out
 // the column types.
 func getColumnTypes(
@@ -212,6 +256,50 @@ func getColumnTypes(
 	return nil
 }
 
+// Column types stored alongside the result set. Columns without a
+// specific type are skipped.
+func getInferredColumnTypes(
+	file_store_factory api.FileStore,
+	path_spec api.FSPathSpec) []*artifacts_proto.ColumnType {
+	var result []*artifacts_proto.ColumnType
+
+	for name, column_type := range result_sets.ReadColumnTypes(
+		file_store_factory, path_spec) {
+		if column_type == "" || column_type == vql_subsystem.COLUMN_TYPE_ANY {
+			continue
+		}
+		result = append(result, &artifacts_proto.ColumnType{
+			Name: name,
+			Type: column_type,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Explicitly declared column types take precedence over inferred
+// ones.
+func mergeColumnTypes(
+	declared, inferred []*artifacts_proto.ColumnType) []*artifacts_proto.ColumnType {
+	result := append([]*artifacts_proto.ColumnType{}, declared...)
+	for _, column_type := range inferred {
+		found := false
+		for _, existing := range declared {
+			if existing.Name == column_type.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, column_type)
+		}
+	}
+	return result
+}
+
 // Get the relevant pathspec for the table needed. Basically a big
 // switch to figure out where the result set we want to look at is
 // stored.
@@ -237,6 +325,9 @@ func GetPathSpec(
 
 		case "uploads":
 			return flow_path_manager.UploadMetadata(), nil
+
+		case "signatures":
+			return flow_path_manager.Signatures(), nil
 		}
 
 	} else if in.HuntId != "" && in.Type == "clients" {
@@ -328,8 +419,11 @@ func getEventTableWithPathManager(
 
 	// Unpack the rows into the output protobuf
 	for row := range rs_reader.Rows(ctx) {
-		if result.Columns == nil {
-			result.Columns = row.Keys()
+		// Result sets written before schemas were tracked may
+		// have columns which no schema version knows about.
+		if rows == 0 {
+			result.Columns = utils.MergeStringSlices(
+				result.Columns, row.Keys())
 		}
 
 		row_data := make([]string, 0, len(result.Columns))
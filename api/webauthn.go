@@ -0,0 +1,226 @@
+package api
+
+// Manages the WebAuthn credentials and recovery codes of users of
+// the basic authenticator. Keys are registered through the login
+// page since registration needs the browser.
+
+// Users may manage their own keys. Resetting another user's keys
+// (e.g. when they lost them) requires SERVER_ADMIN in the root org.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/api/authenticators"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/users"
+)
+
+var (
+	InvalidWebAuthnRequest = errors.New("InvalidWebAuthnRequest")
+)
+
+type WebAuthnStatus struct {
+	Username      string                          `json:"username"`
+	Credentials   []*api_proto.WebAuthnCredential `json:"credentials"`
+	RecoveryCodes int                             `json:"recovery_codes"`
+}
+
+type WebAuthnRequest struct {
+	// One of delete, recovery_codes or reset
+	Action string `json:"action"`
+
+	// Defaults to the principal.
+	Username     string `json:"username"`
+	CredentialId string `json:"credential_id"`
+}
+
+type WebAuthnResponse struct {
+	// New recovery codes - only shown once.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+func GetWebAuthnStatus(ctx context.Context,
+	principal, username string) (*WebAuthnStatus, error) {
+	if username == "" {
+		username = principal
+	}
+
+	err := checkSessionAccess(principal, username)
+	if err != nil {
+		return nil, err
+	}
+
+	config_obj, err := getRootConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := users.ListWebAuthnCredentials(ctx, config_obj, username)
+	if err != nil {
+		return nil, err
+	}
+
+	// Never send the public keys.
+	for _, credential := range credentials {
+		credential.PublicKey = nil
+	}
+
+	return &WebAuthnStatus{
+		Username:      username,
+		Credentials:   credentials,
+		RecoveryCodes: users.CountRecoveryCodes(ctx, config_obj, username),
+	}, nil
+}
+
+func UpdateWebAuthn(ctx context.Context, principal string,
+	request *WebAuthnRequest) (*WebAuthnResponse, error) {
+	username := request.Username
+	if username == "" {
+		username = principal
+	}
+
+	config_obj, err := getRootConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch request.Action {
+	case "delete":
+		err = checkSessionAccess(principal, username)
+		if err != nil {
+			return nil, err
+		}
+
+		err = users.DeleteWebAuthnCredential(ctx, config_obj,
+			username, request.CredentialId)
+		if errors.Is(err, users.WebAuthnCredentialNotFoundError) {
+			return nil, fmt.Errorf("%w: %v", InvalidWebAuthnRequest, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		logging.LogAudit(config_obj, principal, "DeleteWebAuthnCredential",
+			logrus.Fields{
+				"username":      username,
+				"credential_id": request.CredentialId,
+			})
+		return &WebAuthnResponse{}, nil
+
+	case "recovery_codes":
+		// Only the user should ever see their codes.
+		if username != principal {
+			return nil, fmt.Errorf("%w: Recovery codes can only be generated by %v",
+				acls.PermissionDenied, username)
+		}
+
+		codes, err := users.NewRecoveryCodes(ctx, config_obj, username)
+		if err != nil {
+			return nil, err
+		}
+
+		logging.LogAudit(config_obj, principal, "NewRecoveryCodes",
+			logrus.Fields{})
+		return &WebAuthnResponse{RecoveryCodes: codes}, nil
+
+	case "reset":
+		err = checkSessionAccess(principal, username)
+		if err != nil {
+			return nil, err
+		}
+
+		err = users.ResetWebAuthn(ctx, config_obj, username)
+		if err != nil {
+			return nil, err
+		}
+
+		logging.LogAudit(config_obj, principal, "ResetWebAuthn",
+			logrus.Fields{
+				"username": username,
+			})
+		return &WebAuthnResponse{}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: Unknown action %v",
+			InvalidWebAuthnRequest, request.Action)
+	}
+}
+
+func webAuthnHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org_id := authenticators.GetOrgIdFromRequest(r)
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(org_id)
+		if err != nil {
+			returnError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userinfo := GetUserInfo(r.Context(), org_config_obj)
+
+		var result interface{}
+		switch r.Method {
+		case "GET":
+			result, err = GetWebAuthnStatus(r.Context(), userinfo.Name,
+				r.URL.Query().Get("username"))
+
+		case "POST":
+			var data []byte
+			data, err = io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			request := &WebAuthnRequest{}
+			err = json.Unmarshal(data, request)
+			if err != nil {
+				returnError(w, http.StatusBadRequest, "Unsupported params")
+				return
+			}
+
+			result, err = UpdateWebAuthn(r.Context(), userinfo.Name, request)
+
+		default:
+			returnError(w, http.StatusMethodNotAllowed, "Unsupported method")
+			return
+		}
+
+		if errors.Is(err, acls.PermissionDenied) {
+			returnError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if errors.Is(err, InvalidWebAuthnRequest) {
+			returnError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err != nil {
+			returnError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		serialized, _ := json.Marshal(result)
+		_, err = w.Write(serialized)
+		if err != nil {
+			logger := logging.GetLogger(org_config_obj, &logging.GUIComponent)
+			logger.Error("webAuthnHandler: %v", err)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+// Helpers for converters which generate artifact definitions from
+// other formats (e.g. KAPE files and Sigma rules).
+
+package generate
+
+import (
+	"strings"
+
+	"github.com/Velocidex/yaml/v2"
+)
+
+type Artifact struct {
+	Name string
+
+	// The artifact definition as YAML.
+	Yaml string
+}
+
+// Quote a string for VQL. Backslashes and quotes are escaped.
+func QuoteString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// Quote a string as a YAML scalar.
+func QuoteYaml(value string) string {
+	serialized, err := yaml.Marshal(value)
+	if err != nil {
+		return value
+	}
+	return strings.TrimSpace(string(serialized))
+}
+
+// Indent each non empty line of text by count spaces.
+func Indent(text string, count int) string {
+	prefix := strings.Repeat(" ", count)
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -27,8 +27,12 @@ func Obfuscate(
 		return nil
 	}
 
+	// Cleanup queries are sent to the client as well.
+	queries := append([]*actions_proto.VQLRequest{}, result.Query...)
+	queries = append(queries, result.Cleanup...)
+
 	scope := vql_subsystem.MakeScope()
-	for _, query := range result.Query {
+	for _, query := range queries {
 		if query.Name != "" {
 			query.Name, err = obfuscator.Encrypt(config_obj, query.Name)
 			if err != nil {
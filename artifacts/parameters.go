@@ -0,0 +1,236 @@
+package artifacts
+
+// Parameters may depend on other parameters. A parameter's
+// depends_on expression is a list of conditions joined by && (or
+// AND), each condition is one of:
+//
+//   Name              - Name is set (not empty and not false)
+//   !Name             - Name is not set
+//   Name == "value"   - Name has this value
+//   Name != "value"   - Name does not have this value
+//
+// Parameters sharing an exclusive_group are mutually exclusive: at
+// most one of them may be set. The expressions are simple enough for
+// both the CLI and the GUI to evaluate them.
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+)
+
+var (
+	conditionRegex = regexp.MustCompile(
+		`^\s*(!)?\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(?:(==|!=|=)\s*(.*?))?\s*$`)
+	andRegex = regexp.MustCompile(`&&|\s+(?i:and)\s+`)
+)
+
+type ParameterCondition struct {
+	Name     string
+	Operator string
+	Value    string
+}
+
+func (self ParameterCondition) Eval(values map[string]string) bool {
+	value := values[strings.ToLower(self.Name)]
+	switch self.Operator {
+	case "!":
+		return !IsParameterSet(value)
+	case "==":
+		return value == self.Value
+	case "!=":
+		return value != self.Value
+	default:
+		return IsParameterSet(value)
+	}
+}
+
+// Whether the parameter value counts as set for dependencies and
+// exclusive groups.
+func IsParameterSet(value string) bool {
+	switch strings.ToUpper(value) {
+	case "", "N", "NO", "FALSE":
+		return false
+	}
+	return true
+}
+
+func ParseParameterDependency(expression string) ([]ParameterCondition, error) {
+	var result []ParameterCondition
+	if strings.TrimSpace(expression) == "" {
+		return nil, nil
+	}
+
+	for _, part := range andRegex.Split(expression, -1) {
+		match := conditionRegex.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("Invalid condition %q", strings.TrimSpace(part))
+		}
+
+		condition := ParameterCondition{Name: match[2]}
+		switch {
+		case match[3] != "" && match[1] != "":
+			return nil, fmt.Errorf("Invalid condition %q", strings.TrimSpace(part))
+
+		case match[3] == "=" || match[3] == "==":
+			condition.Operator = "=="
+
+		case match[3] == "!=":
+			condition.Operator = "!="
+
+		case match[1] != "":
+			condition.Operator = "!"
+		}
+
+		if match[3] != "" {
+			condition.Value = unquote(match[4])
+		}
+		result = append(result, condition)
+	}
+
+	return result, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if first == last && (first == '"' || first == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// The values of all the parameters, taking defaults from the
+// artifact. Parameter names are case insensitive.
+func ParameterValues(
+	artifact *artifacts_proto.Artifact, env map[string]string) map[string]string {
+	result := make(map[string]string)
+	for _, parameter := range artifact.Parameters {
+		result[strings.ToLower(parameter.Name)] = parameter.Default
+	}
+
+	for k, v := range env {
+		result[strings.ToLower(k)] = v
+	}
+	return result
+}
+
+// Is the parameter relevant given the values of the other
+// parameters?
+func IsParameterRelevant(parameter *artifacts_proto.ArtifactParameter,
+	values map[string]string) (bool, error) {
+	conditions, err := ParseParameterDependency(parameter.DependsOn)
+	if err != nil {
+		return false, fmt.Errorf("Parameter %v: %w", parameter.Name, err)
+	}
+
+	for _, condition := range conditions {
+		if !condition.Eval(values) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Check the artifact's parameter definitions are consistent.
+func ValidateParameterDependencies(artifact *artifacts_proto.Artifact) error {
+	known := make(map[string]bool)
+	for _, parameter := range artifact.Parameters {
+		known[strings.ToLower(parameter.Name)] = true
+	}
+
+	for _, parameter := range artifact.Parameters {
+		conditions, err := ParseParameterDependency(parameter.DependsOn)
+		if err != nil {
+			return fmt.Errorf("Parameter %v: %w", parameter.Name, err)
+		}
+
+		for _, condition := range conditions {
+			if !known[strings.ToLower(condition.Name)] {
+				return fmt.Errorf("Parameter %v depends on unknown parameter %v",
+					parameter.Name, condition.Name)
+			}
+
+			if strings.EqualFold(condition.Name, parameter.Name) {
+				return fmt.Errorf("Parameter %v depends on itself",
+					parameter.Name)
+			}
+		}
+	}
+
+	return checkExclusiveGroups(artifact, ParameterValues(artifact, nil))
+}
+
+// Enforce the dependencies for a collection. env contains the
+// parameters given by the user - parameters which are not relevant
+// may not be changed from their default and at most one parameter
+// of each exclusive group may be set.
+func CheckParameterDependencies(
+	artifact *artifacts_proto.Artifact, env map[string]string) error {
+	values := ParameterValues(artifact, env)
+
+	given := make(map[string]string)
+	for k, v := range env {
+		given[strings.ToLower(k)] = v
+	}
+
+	for _, parameter := range artifact.Parameters {
+		if parameter.DependsOn == "" {
+			continue
+		}
+
+		value, pres := given[strings.ToLower(parameter.Name)]
+		if !pres || value == parameter.Default {
+			continue
+		}
+
+		relevant, err := IsParameterRelevant(parameter, values)
+		if err != nil {
+			return fmt.Errorf("%v: %w", artifact.Name, err)
+		}
+
+		if !relevant {
+			return fmt.Errorf("%v: Parameter %v may only be set when %v",
+				artifact.Name, parameter.Name, parameter.DependsOn)
+		}
+	}
+
+	err := checkExclusiveGroups(artifact, values)
+	if err != nil {
+		return fmt.Errorf("%v: %w", artifact.Name, err)
+	}
+	return nil
+}
+
+func checkExclusiveGroups(
+	artifact *artifacts_proto.Artifact, values map[string]string) error {
+	groups := make(map[string][]string)
+	for _, parameter := range artifact.Parameters {
+		if parameter.ExclusiveGroup == "" ||
+			!IsParameterSet(values[strings.ToLower(parameter.Name)]) {
+			continue
+		}
+
+		groups[parameter.ExclusiveGroup] = append(
+			groups[parameter.ExclusiveGroup], parameter.Name)
+	}
+
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if len(groups[name]) > 1 {
+			return fmt.Errorf("Parameters %v are mutually exclusive",
+				strings.Join(groups[name], ", "))
+		}
+	}
+
+	return nil
+}
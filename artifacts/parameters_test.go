@@ -0,0 +1,56 @@
+package artifacts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+)
+
+func TestParameterDependencies(t *testing.T) {
+	artifact := &artifacts_proto.Artifact{
+		Name: "Test.Artifact",
+		Parameters: []*artifacts_proto.ArtifactParameter{
+			{Name: "Mode", Default: "fast"},
+			{Name: "Depth", DependsOn: `Mode == "deep"`},
+			{Name: "Recurse", Type: "bool", DependsOn: "!Depth and Mode != fast"},
+			{Name: "Hash", ExclusiveGroup: "what"},
+			{Name: "Name", ExclusiveGroup: "what"},
+		},
+	}
+
+	assert.NoError(t, ValidateParameterDependencies(artifact))
+
+	values := ParameterValues(artifact, map[string]string{"mode": "deep"})
+	relevant, err := IsParameterRelevant(artifact.Parameters[1], values)
+	assert.NoError(t, err)
+	assert.True(t, relevant)
+
+	relevant, err = IsParameterRelevant(artifact.Parameters[2], values)
+	assert.NoError(t, err)
+	assert.True(t, relevant)
+
+	values["depth"] = "3"
+	relevant, err = IsParameterRelevant(artifact.Parameters[2], values)
+	assert.NoError(t, err)
+	assert.False(t, relevant)
+
+	// Setting an irrelevant parameter is an error.
+	assert.NoError(t, CheckParameterDependencies(artifact,
+		map[string]string{"Mode": "deep", "Depth": "3"}))
+	assert.Error(t, CheckParameterDependencies(artifact,
+		map[string]string{"Depth": "3"}))
+
+	// Only one parameter in an exclusive group may be set.
+	assert.NoError(t, CheckParameterDependencies(artifact,
+		map[string]string{"Hash": "X", "Name": ""}))
+	assert.Error(t, CheckParameterDependencies(artifact,
+		map[string]string{"Hash": "X", "Name": "Y"}))
+
+	// Dependencies must refer to known parameters.
+	artifact.Parameters[1].DependsOn = "Unknown"
+	assert.Error(t, ValidateParameterDependencies(artifact))
+
+	_, err = ParseParameterDependency("!Mode == 1")
+	assert.Error(t, err)
+}
@@ -20,6 +20,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"regexp"
 	"strings"
@@ -28,14 +29,20 @@ import (
 
 	"github.com/Velocidex/ordereddict"
 	"github.com/Velocidex/yaml/v2"
+	"www.velocidex.com/golang/velociraptor/actions"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
 	"www.velocidex.com/golang/velociraptor/config"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/executor"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
 	logging "www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/services"
+	repository_impl "www.velocidex.com/golang/velociraptor/services/repository"
 	"www.velocidex.com/golang/velociraptor/startup"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vql/tools/collector"
+	"www.velocidex.com/golang/vfilter"
 )
 
 var (
@@ -59,6 +66,10 @@ var (
 		"details", "Show more details (Use -d -dd for even more)").
 		Short('d').Counter()
 
+	artifact_command_list_technique = artifact_command_list.Flag(
+		"technique", "Only list artifacts covering this MITRE ATT&CK "+
+			"technique (e.g. T1003)").Strings()
+
 	artifact_command_collect = artifact_command.Command(
 		"collect", "Collect all artifacts")
 
@@ -79,6 +90,15 @@ var (
 		"cpu_limit", "A number between 0 to 100 representing maximum CPU utilization.").
 		Default("0").Int64()
 
+	artifact_command_collect_workers = artifact_command_collect.Flag(
+		"workers", "Collect up to this many artifacts concurrently.").
+		Default("1").Int64()
+
+	artifact_command_collect_checkpoint = artifact_command_collect.Flag(
+		"checkpoint", "Record the collection progress in this file. "+
+			"If the collection is interrupted, running the same "+
+			"command again resumes it.").String()
+
 	artifact_command_collect_output_compression = artifact_command_collect.Flag(
 		"output_level", "Compression level for zip output.").
 		Default("5").Int64()
@@ -99,8 +119,8 @@ var (
 		Default("").String()
 
 	artifact_command_collect_format = artifact_command_collect.Flag(
-		"format", "Output format to use  (text,json,csv,jsonl).").
-		Default("json").Enum("text", "json", "csv", "jsonl")
+		"format", "Output format to use  (text,json,csv,jsonl,sqlite).").
+		Default("json").Enum("text", "json", "csv", "jsonl", "sqlite")
 
 	artifact_command_collect_names = artifact_command_collect.Arg(
 		"artifact_name", "The artifact name to collect.").
@@ -111,6 +131,27 @@ var (
 
 	artifact_command_collect_hardmemory = artifact_command_collect.Flag(
 		"hard_memory_limit", "If we reach this memory limit in bytes we exit.").Uint64()
+
+	artifact_command_collect_interactive = artifact_command_collect.Flag(
+		"interactive", "Prompt for the artifact parameters which "+
+			"were not given with --args.").Bool()
+
+	artifact_command_collect_dry_run = artifact_command_collect.Flag(
+		"dry_run", "Print the compiled VQL and evaluate preconditions "+
+			"without collecting anything.").Bool()
+
+	artifact_command_lint = artifact_command.Command(
+		"lint", "Check artifact definitions for problems.")
+
+	artifact_command_lint_files = artifact_command_lint.Arg(
+		"files", "Artifact definition files to check.").
+		Required().ExistingFiles()
+
+	artifact_command_lint_json = artifact_command_lint.Flag(
+		"json", "Report the issues as JSON.").Bool()
+
+	artifact_command_lint_strict = artifact_command_lint.Flag(
+		"strict", "Also fail when there are warnings.").Bool()
 )
 
 func listArtifactsHint() []string {
@@ -145,6 +186,38 @@ func getRepository(config_obj *config_proto.Config) (services.Repository, error)
 	return repository, nil
 }
 
+// Build a spec of artifact names to their parameters from the
+// command line args. Args are given as name=value and apply to all
+// artifacts unless prefixed with the artifact name and a ::
+// separator. An arg without a value is set to "Y".
+func buildArtifactSpec(names []string, args []string) *ordereddict.Dict {
+	spec := ordereddict.NewDict()
+	for _, name := range names {
+		collect_args := ordereddict.NewDict()
+		for _, item := range args {
+			namespaces := strings.SplitN(item, "::", 2)
+			if len(namespaces) == 2 {
+				if namespaces[0] != name {
+					continue
+				}
+				item = namespaces[1]
+			}
+
+			parts := strings.SplitN(item, "=", 2)
+			arg_name := parts[0]
+
+			if len(parts) < 2 {
+				collect_args.Set(arg_name, "Y")
+			} else {
+				collect_args.Set(arg_name, parts[1])
+			}
+		}
+
+		spec.Set(name, collect_args)
+	}
+	return spec
+}
+
 func doArtifactCollect() error {
 	if *artificat_command_collect_admin_flag {
 		err := checkAdmin()
@@ -169,33 +242,8 @@ func doArtifactCollect() error {
 		return err
 	}
 
-	spec := ordereddict.NewDict()
-	for _, name := range *artifact_command_collect_names {
-		collect_args := ordereddict.NewDict()
-		for _, item := range *artifact_command_collect_args {
-			// If an arg has to apply only to a single
-			// artifact then the user may prepend the name
-			// of the artifact with a :: separator.
-			namespaces := strings.SplitN(item, "::", 2)
-			if len(namespaces) == 2 {
-				if namespaces[0] != name {
-					continue
-				}
-				item = namespaces[1]
-			}
-
-			parts := strings.SplitN(item, "=", 2)
-			arg_name := parts[0]
-
-			if len(parts) < 2 {
-				collect_args.Set(arg_name, "Y")
-			} else {
-				collect_args.Set(arg_name, parts[1])
-			}
-		}
-
-		spec.Set(name, collect_args)
-	}
+	spec := buildArtifactSpec(
+		*artifact_command_collect_names, *artifact_command_collect_args)
 
 	manager, err := services.GetRepositoryManager(config_obj)
 	if err != nil {
@@ -219,10 +267,23 @@ func doArtifactCollect() error {
 			Set("Format", *artifact_command_collect_format).
 			Set("Timeout", *artifact_command_collect_timeout).
 			Set("ProgressTimeout", *artifact_command_collect_progress_timeout).
-			Set("CpuLimit", *artifact_command_collect_cpu_limit),
+			Set("CpuLimit", *artifact_command_collect_cpu_limit).
+			Set("Workers", *artifact_command_collect_workers).
+			Set("Checkpoint", *artifact_command_collect_checkpoint),
 	})
 	defer scope.Close()
 
+	if *artifact_command_collect_interactive {
+		err = promptArtifactParameters(config_obj, scope, spec)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *artifact_command_collect_dry_run {
+		return doArtifactCollectDryRun(sm.Ctx, config_obj, scope, spec)
+	}
+
 	// Stick around until the query completes so it gets a chance to
 	// close the collection zip.
 	sm.Wg.Add(1)
@@ -262,11 +323,87 @@ func doArtifactCollect() error {
   SELECT * FROM collect(artifacts=Artifacts, output=Output, report=Report,
                         level=Level, template=Template,
                         timeout=Timeout, progress_timeout=ProgressTimeout,
-                        cpu_limit=CpuLimit,
+                        cpu_limit=CpuLimit, workers=Workers,
+                        checkpoint=Checkpoint,
                         password=Password, args=Args, format=Format)`
+	// The sqlite format only applies to the collection container.
+	output_format := *artifact_command_collect_format
+	if output_format == "sqlite" {
+		output_format = "json"
+	}
+
 	return eval_local_query(
-		sm.Ctx, config_obj,
-		*artifact_command_collect_format, query, scope)
+		sm.Ctx, config_obj, output_format, query, scope)
+}
+
+// Compile the collection exactly as the collect() plugin would and
+// print the resulting requests. Only the preconditions are evaluated
+// (since they decide which sources run) - no other queries are run.
+func doArtifactCollectDryRun(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	scope vfilter.Scope, spec *ordereddict.Dict) error {
+	repository, err := getRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		Artifacts: *artifact_command_collect_names,
+	}
+	err = collector.AddSpecProtobuf(
+		config_obj, repository, scope, spec, request)
+	if err != nil {
+		return err
+	}
+
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return err
+	}
+
+	vql_requests, err := launcher.CompileCollectorArgs(
+		ctx, config_obj, acl_managers.NullACLManager{}, repository,
+		services.CompilerOptions{}, request)
+	if err != nil {
+		return fmt.Errorf("Unable to compile artifact: %w", err)
+	}
+
+	for idx, vql_request := range vql_requests {
+		res, err := yaml.Marshal(vql_request)
+		if err != nil {
+			return fmt.Errorf("Unable to encode request: %w", err)
+		}
+
+		fmt.Printf("VQLCollectorArgs %d:\n***********\n%v\n",
+			idx, string(res))
+
+		if vql_request.Precondition == "" {
+			continue
+		}
+
+		// Evaluate the precondition with the request's parameters.
+		env := ordereddict.NewDict()
+		for _, env_spec := range vql_request.Env {
+			env.Set(env_spec.Key, env_spec.Value)
+		}
+
+		subscope := scope.Copy()
+		subscope.AppendVars(env)
+		ok, err := actions.CheckPreconditions(ctx, subscope, vql_request)
+		subscope.Close()
+
+		switch {
+		case err != nil:
+			fmt.Printf("Precondition error: %v\n\n", err)
+		case ok:
+			fmt.Printf("Precondition: passed - this request would run.\n\n")
+		default:
+			fmt.Printf("Precondition: failed - this request would be skipped.\n\n")
+		}
+	}
+
+	return nil
 }
 
 func getFilterRegEx(pattern string) (*regexp.Regexp, error) {
@@ -360,16 +497,27 @@ func doArtifactList() error {
 			continue
 		}
 
-		if *artifact_command_list_verbose_count == 0 {
-			fmt.Println(name)
-			continue
-		}
-
 		artifact, pres := repository.Get(config_obj, name)
 		if !pres {
 			return fmt.Errorf("Artifact %s not found", name)
 		}
 
+		covered := true
+		for _, technique := range *artifact_command_list_technique {
+			if !services.HasAttackTechnique(artifact, technique) {
+				covered = false
+				break
+			}
+		}
+		if !covered {
+			continue
+		}
+
+		if *artifact_command_list_verbose_count == 0 {
+			fmt.Println(name)
+			continue
+		}
+
 		fmt.Println(artifact.Raw)
 
 		if *artifact_command_list_verbose_count <= 1 {
@@ -404,6 +552,111 @@ func doArtifactList() error {
 	return nil
 }
 
+func doArtifactLint() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	global_repository, err := getRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return err
+	}
+
+	// Load the artifacts into a copy of the global repository so
+	// they can depend on each other and on the built in artifacts.
+	repository := global_repository.Copy()
+	issues := []*repository_impl.LintIssue{}
+	var artifacts []*artifacts_proto.Artifact
+	var filenames []string
+	for _, filename := range *artifact_command_lint_files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		artifact, err := repository.LoadYaml(
+			string(data), true /* validate */, true /* built_in */)
+		if err != nil {
+			issues = append(issues, &repository_impl.LintIssue{
+				File:     filename,
+				Severity: repository_impl.LINT_ERROR,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		artifacts = append(artifacts, artifact)
+		filenames = append(filenames, filename)
+	}
+
+	for idx, artifact := range artifacts {
+		filename := filenames[idx]
+
+		_, err := launcher.CompileCollectorArgs(
+			sm.Ctx, config_obj, acl_managers.NullACLManager{}, repository,
+			services.CompilerOptions{
+				DisablePrecondition: true,
+			},
+			&flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{artifact.Name},
+			})
+		if err != nil {
+			issues = append(issues, &repository_impl.LintIssue{
+				File:     filename,
+				Artifact: artifact.Name,
+				Severity: repository_impl.LINT_ERROR,
+				Message:  fmt.Sprintf("Unable to compile artifact: %v", err),
+			})
+		}
+
+		for _, issue := range repository_impl.LintArtifact(artifact) {
+			issue.File = filename
+			issues = append(issues, issue)
+		}
+	}
+
+	error_count, warning_count := 0, 0
+	for _, issue := range issues {
+		if issue.Severity == repository_impl.LINT_ERROR {
+			error_count++
+		} else {
+			warning_count++
+		}
+
+		if !*artifact_command_lint_json {
+			fmt.Printf("%v: %v: %v: %v\n", issue.File, issue.Severity,
+				issue.Artifact, issue.Message)
+		}
+	}
+
+	if *artifact_command_lint_json {
+		fmt.Println(string(json.MustMarshalIndent(issues)))
+	}
+
+	if error_count > 0 ||
+		(*artifact_command_lint_strict && warning_count > 0) {
+		return fmt.Errorf("Found %v errors and %v warnings",
+			error_count, warning_count)
+	}
+	return nil
+}
+
 func maybeAddDefinitionsDirectory(config_obj *config_proto.Config) error {
 	if *artifact_definitions_dir != "" {
 		if config_obj.Defaults == nil {
@@ -429,6 +682,9 @@ func init() {
 		case artifact_command_collect.FullCommand():
 			FatalIfError(artifact_command_collect, doArtifactCollect)
 
+		case artifact_command_lint.FullCommand():
+			FatalIfError(artifact_command_lint, doArtifactLint)
+
 		default:
 			return false
 		}
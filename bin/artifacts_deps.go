@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/startup"
+)
+
+var (
+	artifact_command_deps = artifact_command.Command(
+		"deps", "Show the artifacts and tools an artifact depends on.")
+
+	artifact_command_deps_name = artifact_command_deps.Arg(
+		"name", "The artifact to resolve.").Required().
+		HintAction(listArtifactsHint).String()
+
+	artifact_command_deps_dot = artifact_command_deps.Flag(
+		"dot", "Print the dependencies as a DOT graph.").Bool()
+)
+
+func printDependencyTree(out io.Writer,
+	node *launcher.ArtifactDependency, indent string) {
+	prefix := ""
+	if node.Kind == launcher.DEPENDENCY_IMPORT {
+		prefix = "import "
+	}
+
+	fmt.Fprintf(out, "%s%s%s\n", indent, prefix, node.Name)
+	for _, tool := range node.Tools {
+		fmt.Fprintf(out, "%s  tool %s\n", indent, tool)
+	}
+
+	for _, dep := range node.Dependencies {
+		printDependencyTree(out, dep, indent+"  ")
+	}
+}
+
+func printDependencyGraph(out io.Writer, root *launcher.ArtifactDependency) {
+	fmt.Fprintf(out, "digraph %s {\n", strconv.Quote(root.Name))
+
+	// The same dependency may be reached through many paths but
+	// each edge is only printed once.
+	seen := make(map[string]bool)
+	emit := func(line string) {
+		if !seen[line] {
+			seen[line] = true
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+
+	var walk func(node *launcher.ArtifactDependency)
+	walk = func(node *launcher.ArtifactDependency) {
+		for _, tool := range node.Tools {
+			emit(fmt.Sprintf("%s [shape=box];", strconv.Quote("tool:"+tool)))
+			emit(fmt.Sprintf("%s -> %s [style=dashed];",
+				strconv.Quote(node.Name), strconv.Quote("tool:"+tool)))
+		}
+
+		for _, dep := range node.Dependencies {
+			edge := fmt.Sprintf("%s -> %s", strconv.Quote(node.Name),
+				strconv.Quote(dep.Name))
+			if dep.Kind == launcher.DEPENDENCY_IMPORT {
+				edge += ` [label="import"]`
+			}
+			emit(edge + ";")
+			walk(dep)
+		}
+	}
+	walk(root)
+
+	fmt.Fprintln(out, "}")
+}
+
+func doArtifactDeps() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	repository, err := getRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	tree, err := launcher.GetArtifactDependencyTree(
+		config_obj, repository, *artifact_command_deps_name)
+	if err != nil {
+		return err
+	}
+
+	if *artifact_command_deps_dot {
+		printDependencyGraph(os.Stdout, tree)
+		return nil
+	}
+
+	printDependencyTree(os.Stdout, tree, "")
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case artifact_command_deps.FullCommand():
+			FatalIfError(artifact_command_deps, doArtifactDeps)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
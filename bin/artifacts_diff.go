@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Velocidex/yaml/v2"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/artifacts/assets"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+var (
+	artifact_command_diff = artifact_command.Command(
+		"diff", "Compare an artifact loaded with --definitions with the built in version.")
+
+	artifact_command_diff_name = artifact_command_diff.Arg(
+		"name", "The artifact to compare.").Required().
+		HintAction(listArtifactsHint).String()
+
+	artifact_command_diff_context = artifact_command_diff.Flag(
+		"context", "Number of context lines to show.").
+		Default("3").Int()
+)
+
+// Build a repository with only the artifacts compiled into the
+// binary.
+func getBuiltInRepository(
+	config_obj *config_proto.Config) (services.Repository, error) {
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	repository := manager.NewRepository()
+
+	assets.Init()
+	files, err := assets.WalkDirs("", false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if !strings.HasPrefix(file, "artifacts/definitions") ||
+			!strings.HasSuffix(file, "yaml") {
+			continue
+		}
+
+		data, err := assets.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = repository.LoadYaml(string(data),
+			services.ValidateArtifact, services.ArtifactIsBuiltIn)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", file, err)
+		}
+	}
+
+	return repository, nil
+}
+
+// Compile the artifact as it would be sent to the client.
+func compileArtifactForDiff(ctx context.Context,
+	config_obj *config_proto.Config,
+	repository services.Repository, name string) (string, error) {
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	requests, err := launcher.CompileCollectorArgs(
+		ctx, config_obj, acl_managers.NullACLManager{}, repository,
+		services.CompilerOptions{
+			DisablePrecondition: true,
+		},
+		&flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{name},
+		})
+	if err != nil {
+		return "", fmt.Errorf("Unable to compile artifact: %w", err)
+	}
+
+	var result []string
+	for _, request := range requests {
+		for _, query := range request.Query {
+			if query.Name != "" {
+				result = append(result, "-- "+query.Name)
+			}
+			result = append(result, query.VQL, "")
+		}
+	}
+
+	// Artifact dependencies are included with the requests.
+	for _, request := range requests {
+		for _, artifact := range request.Artifacts {
+			// The raw YAML is already compared above.
+			artifact = proto.Clone(artifact).(*artifacts_proto.Artifact)
+			artifact.Raw = ""
+
+			serialized, err := yaml.Marshal(artifact)
+			if err != nil {
+				return "", err
+			}
+			result = append(result, "-- Dependency "+artifact.Name,
+				string(serialized))
+		}
+	}
+
+	return strings.Join(result, "\n"), nil
+}
+
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// Format a line by line diff of a and b in the unified diff format
+// with the given number of context lines.
+func unifiedDiff(a, b, from, to string, context int) string {
+	// Diff the lines by mapping each unique line to a rune. Runes
+	// start in the private use area to stay clear of the surrogates.
+	var unique []string
+	index := make(map[string]rune)
+	encode := func(text string) []rune {
+		var result []rune
+		for _, line := range strings.SplitAfter(text, "\n") {
+			if line == "" {
+				continue
+			}
+			r, pres := index[line]
+			if !pres {
+				r = rune(0xE000 + len(unique))
+				index[line] = r
+				unique = append(unique, line)
+			}
+			result = append(result, r)
+		}
+		return result
+	}
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMainRunes(encode(a), encode(b), false)
+
+	var lines []diffLine
+	var changes []int
+	for _, diff := range diffs {
+		op := byte(' ')
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+
+		for _, r := range diff.Text {
+			if op != ' ' {
+				changes = append(changes, len(lines))
+			}
+			lines = append(lines, diffLine{op: op, text: unique[r-0xE000]})
+		}
+	}
+
+	if len(changes) == 0 {
+		return ""
+	}
+
+	result := &strings.Builder{}
+	fmt.Fprintf(result, "--- %s\n+++ %s\n", from, to)
+
+	// Changes closer than twice the context are in the same hunk.
+	for i := 0; i < len(changes); {
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*context+1 {
+			j++
+		}
+
+		start := changes[i] - context
+		if start < 0 {
+			start = 0
+		}
+		end := changes[j] + context + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		// Line numbers are 1 based.
+		a_start, b_start := 1, 1
+		for _, line := range lines[:start] {
+			if line.op != '+' {
+				a_start++
+			}
+			if line.op != '-' {
+				b_start++
+			}
+		}
+
+		a_count, b_count := 0, 0
+		for _, line := range lines[start:end] {
+			if line.op != '+' {
+				a_count++
+			}
+			if line.op != '-' {
+				b_count++
+			}
+		}
+
+		// An empty range refers to the line before it.
+		if a_count == 0 {
+			a_start--
+		}
+		if b_count == 0 {
+			b_start--
+		}
+
+		fmt.Fprintf(result, "@@ -%d,%d +%d,%d @@\n",
+			a_start, a_count, b_start, b_count)
+		for _, line := range lines[start:end] {
+			result.WriteByte(line.op)
+			result.WriteString(line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				result.WriteString("\n")
+			}
+		}
+
+		i = j + 1
+	}
+
+	return result.String()
+}
+
+func doArtifactDiff() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	name := *artifact_command_diff_name
+
+	// The global repository includes artifacts loaded with
+	// --definitions which replace the built in ones.
+	local_repository, err := getRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	local, pres := local_repository.Get(config_obj, name)
+	if !pres {
+		return fmt.Errorf("Artifact %s not found", name)
+	}
+
+	builtin_repository, err := getBuiltInRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	builtin, pres := builtin_repository.Get(config_obj, name)
+	if !pres {
+		return fmt.Errorf("Artifact %s is not a built in artifact", name)
+	}
+
+	yaml_diff := unifiedDiff(builtin.Raw, local.Raw,
+		"built-in/"+name+".yaml", "local/"+name+".yaml",
+		*artifact_command_diff_context)
+
+	builtin_vql, err := compileArtifactForDiff(
+		sm.Ctx, config_obj, builtin_repository, name)
+	if err != nil {
+		return fmt.Errorf("Built in %v: %w", name, err)
+	}
+
+	local_vql, err := compileArtifactForDiff(
+		sm.Ctx, config_obj, local_repository, name)
+	if err != nil {
+		return fmt.Errorf("Local %v: %w", name, err)
+	}
+
+	vql_diff := unifiedDiff(builtin_vql, local_vql,
+		"built-in/"+name+".vql", "local/"+name+".vql",
+		*artifact_command_diff_context)
+
+	if yaml_diff == "" && vql_diff == "" {
+		fmt.Printf("No differences in %v\n", name)
+		return nil
+	}
+
+	fmt.Print(yaml_diff)
+	if vql_diff != "" {
+		fmt.Printf("\nCompiled VQL:\n%s", vql_diff)
+	}
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case artifact_command_diff.FullCommand():
+			FatalIfError(artifact_command_diff, doArtifactDiff)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
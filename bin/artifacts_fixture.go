@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/Velocidex/yaml/v2"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/actions"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vql/remapping"
+	"www.velocidex.com/golang/velociraptor/vql/tools/collector"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	artifact_command_test = artifact_command.Command(
+		"test", "Run artifacts against fixtures and compare the "+
+			"results with golden files.")
+
+	artifact_command_test_directory = artifact_command_test.Arg(
+		"directory", "A directory containing test cases (*.test.yaml).").
+		Required().ExistingDir()
+
+	artifact_command_test_filter = artifact_command_test.Flag(
+		"filter", "Only run test cases whose name starts with this.").
+		String()
+
+	artifact_command_test_update = artifact_command_test.Flag(
+		"update", "Write the results to the golden files instead of "+
+			"comparing them.").Bool()
+)
+
+// An artifact test case is stored in a <name>.test.yaml file and its
+// expected results in <name>.out.json next to it. Paths are relative
+// to the test case file.
+type artifactTestCase struct {
+	// The artifact to collect and its parameters.
+	Artifact   string            `json:"Artifact"`
+	Parameters map[string]string `json:"Parameters"`
+
+	// The host to impersonate (OS is one of windows, linux or
+	// darwin).
+	OS       string `json:"OS"`
+	Hostname string `json:"Hostname"`
+
+	// A directory mounted as the root filesystem (the C: drive on
+	// Windows).
+	Filesystem string `json:"Filesystem"`
+
+	// Registry hive files mounted on registry keys.
+	Registry []*artifactTestHive `json:"Registry"`
+
+	// Files with the rows (JSON or JSONL) returned by plugins
+	// (e.g. pslist) instead of running them.
+	Plugins map[string]string `json:"Plugins"`
+}
+
+type artifactTestHive struct {
+	// The key to mount the hive on
+	// (e.g. HKEY_LOCAL_MACHINE\Software).
+	Key string `json:"Key"`
+
+	// The hive file.
+	Hive string `json:"Hive"`
+
+	// The path inside the hive to mount (default /).
+	KeyPath string `json:"KeyPath"`
+}
+
+// Build the remapping configuration which presents the fixture to
+// the artifact.
+func getArtifactTestRemappings(
+	config_obj *config_proto.Config,
+	test_case *artifactTestCase, directory string) error {
+
+	addCommonPermissions(config_obj)
+	addCommonShadowAccessors(config_obj)
+	impersonationClause(config_obj, test_case.OS, test_case.Hostname)
+
+	if test_case.Filesystem != "" {
+		root := filepath.Join(directory, test_case.Filesystem)
+		from := &config_proto.MountPoint{
+			Accessor: "file",
+			Prefix:   root,
+		}
+
+		var mounts []*config_proto.MountPoint
+		switch test_case.OS {
+		case "windows":
+			mounts = []*config_proto.MountPoint{
+				{Accessor: "file", Prefix: "C:", PathType: "windows"},
+				{Accessor: "auto", Prefix: "C:", PathType: "windows"},
+				{Accessor: "ntfs", Prefix: "\\\\.\\C:", PathType: "ntfs"},
+			}
+		default:
+			mounts = []*config_proto.MountPoint{
+				{Accessor: "file", Prefix: "/", PathType: "linux"},
+				{Accessor: "auto", Prefix: "/", PathType: "linux"},
+			}
+		}
+
+		for _, on := range mounts {
+			config_obj.Remappings = append(config_obj.Remappings,
+				&config_proto.RemappingConfig{
+					Type: "mount",
+					Description: fmt.Sprintf(
+						"Mount the fixture %v on %v (%v accessor)",
+						root, on.Prefix, on.Accessor),
+					From: from,
+					On:   on,
+				})
+		}
+	}
+
+	for _, hive := range test_case.Registry {
+		if hive.Key == "" || hive.Hive == "" {
+			return errors.New("Registry mounts need both a Key and a Hive")
+		}
+
+		key_path := hive.KeyPath
+		if key_path == "" {
+			key_path = "/"
+		}
+
+		hive_path := filepath.Join(directory, hive.Hive)
+		config_obj.Remappings = append(config_obj.Remappings,
+			&config_proto.RemappingConfig{
+				Type: "mount",
+				Description: fmt.Sprintf(
+					"Map the %s Registry hive on %s (Prefixed at %v)",
+					hive_path, hive.Key, key_path),
+				From: &config_proto.MountPoint{
+					Accessor: "raw_reg",
+					Prefix: fmt.Sprintf(`{
+  "Path": %q,
+  "DelegateAccessor": "file",
+  "DelegatePath": %q
+}`, key_path, hive_path),
+					PathType: "registry",
+				},
+				On: &config_proto.MountPoint{
+					Accessor: "registry",
+					Prefix:   hive.Key,
+					PathType: "registry",
+				},
+			})
+	}
+
+	return nil
+}
+
+// Collect the artifact in the test case and return the rows of each
+// source.
+func runArtifactTest(
+	ctx context.Context, config_obj *config_proto.Config,
+	test_case *artifactTestCase, directory string) (*ordereddict.Dict, error) {
+
+	if test_case.Artifact == "" {
+		return nil, errors.New("No Artifact specified")
+	}
+
+	if test_case.OS == "" {
+		test_case.OS = "windows"
+	}
+
+	if test_case.Hostname == "" {
+		test_case.Hostname = "TestHost"
+	}
+
+	// Remappings apply to all scopes built from the config so each
+	// test case gets its own copy.
+	test_config := proto.Clone(config_obj).(*config_proto.Config)
+	test_config.Remappings = nil
+	err := getArtifactTestRemappings(test_config, test_case, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	log_writer := &MemoryLogWriter{config_obj: config_obj}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     test_config,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger:     log.New(log_writer, "", 0),
+		Env: ordereddict.NewDict().
+			Set(constants.SCOPE_MOCK, &remapping.MockingScopeContext{}),
+	})
+	defer scope.Close()
+
+	for name, filename := range test_case.Plugins {
+		data, err := ioutil.ReadFile(filepath.Join(directory, filename))
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := utils.ParseJsonToDicts(data)
+		if err != nil {
+			return nil, fmt.Errorf("Reading rows for %v: %w", name, err)
+		}
+
+		scope.AppendPlugins(remapping.NewMockerPluginFromRows(name, rows))
+	}
+
+	spec := ordereddict.NewDict()
+	for k, v := range test_case.Parameters {
+		spec.Set(k, v)
+	}
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		Artifacts: []string{test_case.Artifact},
+	}
+	err = collector.AddSpecProtobuf(config_obj, repository, scope,
+		ordereddict.NewDict().Set(test_case.Artifact, spec), request)
+	if err != nil {
+		return nil, err
+	}
+
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	vql_requests, err := launcher.CompileCollectorArgs(
+		ctx, config_obj, acl_managers.NullACLManager{}, repository,
+		services.CompilerOptions{}, request)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to compile artifact: %w", err)
+	}
+
+	result := ordereddict.NewDict()
+	for _, vql_request := range vql_requests {
+		env := ordereddict.NewDict()
+		for _, env_spec := range vql_request.Env {
+			env.Set(env_spec.Key, env_spec.Value)
+		}
+
+		subscope := scope.Copy()
+		subscope.AppendVars(env)
+
+		err := runArtifactTestRequest(ctx, subscope, vql_request, result)
+		subscope.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, msg := range fatalLogMessagesRegex {
+		matches, err := log_writer.Matches(msg)
+		if matches || err != nil {
+			return nil, fmt.Errorf("Log out matches %q", msg)
+		}
+	}
+
+	return result, nil
+}
+
+// Run the queries of a request the same way the client does and
+// store the rows of the named queries in result.
+func runArtifactTestRequest(
+	ctx context.Context, scope vfilter.Scope,
+	vql_request *actions_proto.VQLCollectorArgs, result *ordereddict.Dict) error {
+
+	ok, err := actions.CheckPreconditions(ctx, scope, vql_request)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		scope.Log("Skipping query due to preconditions")
+		return nil
+	}
+
+	for _, query := range vql_request.Query {
+		vql, err := vfilter.Parse(query.VQL)
+		if err != nil {
+			return err
+		}
+
+		rows := []*ordereddict.Dict{}
+		for row := range vql.Eval(ctx, scope) {
+			rows = append(rows, vfilter.RowToDict(ctx, scope, row))
+		}
+
+		if query.Name != "" {
+			result.Set(query.Name, rows)
+		}
+	}
+
+	return nil
+}
+
+func doArtifactTest() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to create config: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	// Mounts need absolute paths.
+	directory, err := filepath.Abs(*artifact_command_test_directory)
+	if err != nil {
+		return err
+	}
+
+	test_cases, err := filepath.Glob(filepath.Join(directory, "*.test.yaml"))
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	failures := []string{}
+	count := 0
+
+	for _, test_path := range test_cases {
+		name := strings.TrimSuffix(filepath.Base(test_path), ".test.yaml")
+		if !strings.HasPrefix(name, *artifact_command_test_filter) {
+			continue
+		}
+		count++
+
+		data, err := ioutil.ReadFile(test_path)
+		if err != nil {
+			return err
+		}
+
+		test_case := &artifactTestCase{}
+		err = yaml.UnmarshalStrict(data, test_case)
+		if err != nil {
+			return fmt.Errorf("Reading test case %v: %w", test_path, err)
+		}
+
+		results, err := runArtifactTest(sm.Ctx, config_obj, test_case, directory)
+		if err != nil {
+			fmt.Printf("Failed %v: %v\n", name, err)
+			failures = append(failures, name)
+			continue
+		}
+
+		serialized, err := json.MarshalIndent(results)
+		if err != nil {
+			return err
+		}
+
+		golden_path := strings.TrimSuffix(test_path, ".test.yaml") + ".out.json"
+		if *artifact_command_test_update {
+			err = ioutil.WriteFile(golden_path, serialized, 0644)
+			if err != nil {
+				return fmt.Errorf("Unable to write golden file: %w", err)
+			}
+			logger.Info("Updated %v", golden_path)
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(golden_path)
+		if err != nil {
+			fmt.Printf("Failed %v: No golden file - run with --update "+
+				"to create it.\n", name)
+			failures = append(failures, name)
+			continue
+		}
+
+		if strings.TrimSpace(string(expected)) != strings.TrimSpace(string(serialized)) {
+			dmp := diffmatchpatch.New()
+			diffs := dmp.DiffMain(string(expected), string(serialized), false)
+			fmt.Printf("Failed %v:\n", name)
+			fmt.Println(dmp.DiffPrettyText(diffs))
+			failures = append(failures, name)
+			continue
+		}
+
+		logger.Info("Passed %v", name)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("No test cases found in %v", directory)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%v of %v test cases failed: %v",
+			len(failures), count, failures)
+	}
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case artifact_command_test.FullCommand():
+			FatalIfError(artifact_command_test, doArtifactTest)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Velocidex/ordereddict"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/profiles"
+	"www.velocidex.com/golang/velociraptor/startup"
+)
+
+var (
+	artifact_command_profile = artifact_command.Command(
+		"profile", "Compile artifacts and their parameters into a "+
+			"triage profile for offline collectors and hunts.")
+
+	artifact_command_profile_output = artifact_command_profile.Arg(
+		"output", "Where to write the profile.").Required().String()
+
+	artifact_command_profile_artifacts = artifact_command_profile.Flag(
+		"artifact", "An artifact to collect. May be given multiple times.").
+		Required().HintAction(listArtifactsHint).Strings()
+
+	artifact_command_profile_args = artifact_command_profile.Flag(
+		"args", "Artifact parameters as name=value. Prefix with the "+
+			"artifact name and :: to only apply to one artifact.").Strings()
+
+	artifact_command_profile_name = artifact_command_profile.Flag(
+		"name", "The name of the profile.").Default("Triage").String()
+
+	artifact_command_profile_description = artifact_command_profile.Flag(
+		"description", "A description of the profile.").String()
+
+	artifact_command_profile_timeout = artifact_command_profile.Flag(
+		"timeout", "Cancel the collection after this many seconds.").
+		Uint64()
+
+	artifact_command_profile_cpu_limit = artifact_command_profile.Flag(
+		"cpu_limit", "A number between 0 to 100 representing maximum "+
+			"CPU utilization.").Float64()
+
+	artifact_command_profile_max_rows = artifact_command_profile.Flag(
+		"max_rows", "Max number of rows to collect.").Uint64()
+
+	artifact_command_profile_max_bytes = artifact_command_profile.Flag(
+		"max_bytes", "Max number of bytes to upload.").Uint64()
+)
+
+// Convert an artifact spec to the profile's parameters.
+func specToParameters(spec *ordereddict.Dict) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, name := range spec.Keys() {
+		args_any, _ := spec.Get(name)
+		args, ok := args_any.(*ordereddict.Dict)
+		if !ok || args.Len() == 0 {
+			continue
+		}
+
+		parameters := make(map[string]string)
+		for _, k := range args.Keys() {
+			parameters[k], _ = args.GetString(k)
+		}
+		result[name] = parameters
+	}
+	return result
+}
+
+func doArtifactProfile() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	repository, err := getRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	profile, err := profiles.Compile(config_obj, repository,
+		profiles.CompileOptions{
+			Name:        *artifact_command_profile_name,
+			Description: *artifact_command_profile_description,
+			Artifacts:   *artifact_command_profile_artifacts,
+			Parameters: specToParameters(buildArtifactSpec(
+				*artifact_command_profile_artifacts,
+				*artifact_command_profile_args)),
+			Resources: profiles.Resources{
+				Timeout:  *artifact_command_profile_timeout,
+				CpuLimit: *artifact_command_profile_cpu_limit,
+				MaxRows:  *artifact_command_profile_max_rows,
+				MaxBytes: *artifact_command_profile_max_bytes,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	data, err := profile.Marshal()
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(*artifact_command_profile_output, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Wrote profile %v with %v artifact definitions to %v",
+		profile.Name, len(profile.Definitions),
+		*artifact_command_profile_output)
+
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case artifact_command_profile.FullCommand():
+			FatalIfError(artifact_command_profile, doArtifactProfile)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/functions"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Extra options for all the prompts. Tests use this to answer the
+// prompts from a console.
+var promptOptions []survey.AskOpt
+
+// Ask the user for the artifact parameters which were not given
+// with --args. The answers are added to the spec.
+func promptArtifactParameters(
+	config_obj *config_proto.Config,
+	scope vfilter.Scope, spec *ordereddict.Dict) error {
+	repository, err := getRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return promptRepositoryParameters(config_obj, repository, scope, spec)
+}
+
+func promptRepositoryParameters(
+	config_obj *config_proto.Config, repository services.Repository,
+	scope vfilter.Scope, spec *ordereddict.Dict) error {
+	for _, name := range spec.Keys() {
+		artifact, pres := repository.Get(config_obj, name)
+		if !pres {
+			return fmt.Errorf("Artifact %v not found", name)
+		}
+
+		collect_args_any, _ := spec.Get(name)
+		collect_args, ok := collect_args_any.(*ordereddict.Dict)
+		if !ok {
+			continue
+		}
+
+		if len(artifact.Parameters) > 0 {
+			fmt.Printf("\nParameters for %v\n", artifact.Name)
+		}
+
+		given := make(map[string]string)
+		for _, k := range collect_args.Keys() {
+			v, _ := collect_args.Get(k)
+			given[k] = utils.ToString(v)
+		}
+		values := artifacts.ParameterValues(artifact, given)
+
+		for _, parameter := range artifact.Parameters {
+			_, pres := collect_args.Get(parameter.Name)
+			if pres || parameter.Type == "hidden" {
+				continue
+			}
+
+			// Skip parameters which do not apply given the
+			// answers so far.
+			relevant, err := artifacts.IsParameterRelevant(parameter, values)
+			if err != nil {
+				return err
+			}
+
+			if !relevant || exclusiveGroupIsSet(artifact, parameter, values) {
+				continue
+			}
+
+			value, err := promptParameter(scope, parameter)
+			if err != nil {
+				return err
+			}
+			values[strings.ToLower(parameter.Name)] = value
+
+			// Leave the default to the artifact.
+			if value != parameter.Default {
+				collect_args.Set(parameter.Name, value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Is another parameter in the same exclusive group already set?
+func exclusiveGroupIsSet(artifact *artifacts_proto.Artifact,
+	parameter *artifacts_proto.ArtifactParameter,
+	values map[string]string) bool {
+	if parameter.ExclusiveGroup == "" {
+		return false
+	}
+
+	for _, other := range artifact.Parameters {
+		if other != parameter &&
+			other.ExclusiveGroup == parameter.ExclusiveGroup &&
+			artifacts.IsParameterSet(values[strings.ToLower(other.Name)]) {
+			return true
+		}
+	}
+	return false
+}
+
+func promptParameter(scope vfilter.Scope,
+	parameter *artifacts_proto.ArtifactParameter) (string, error) {
+	message := parameter.Name
+	if parameter.Type != "" {
+		message += " (" + parameter.Type + ")"
+	}
+
+	help := parameter.Description
+	if help != "" {
+		message += ": " + strings.Split(help, "\n")[0]
+	}
+
+	switch parameter.Type {
+	case "bool":
+		value := false
+		switch strings.ToUpper(parameter.Default) {
+		case "Y", "TRUE", "YES", "OK":
+			value = true
+		}
+		err := survey.AskOne(&survey.Confirm{
+			Message: message,
+			Default: value,
+			Help:    help,
+		}, &value, promptOptions...)
+		if err != nil {
+			return "", err
+		}
+		if value {
+			return "Y", nil
+		}
+		return "N", nil
+
+	case "choices":
+		if len(parameter.Choices) == 0 {
+			break
+		}
+
+		value := ""
+		question := &survey.Select{
+			Message: message,
+			Options: parameter.Choices,
+			Help:    help,
+		}
+		for _, choice := range parameter.Choices {
+			if choice == parameter.Default {
+				question.Default = choice
+			}
+		}
+		err := survey.AskOne(question, &value, promptOptions...)
+		return value, err
+
+	case "multichoice":
+		if len(parameter.Choices) == 0 {
+			break
+		}
+
+		var defaults []string
+		_ = json.Unmarshal([]byte(parameter.Default), &defaults)
+
+		var value []string
+		err := survey.AskOne(&survey.MultiSelect{
+			Message: message,
+			Options: parameter.Choices,
+			Default: defaults,
+			Help:    help,
+		}, &value, promptOptions...)
+		if err != nil {
+			return "", err
+		}
+
+		serialized, err := json.Marshal(value)
+		return string(serialized), err
+	}
+
+	value := ""
+	err := survey.AskOne(&survey.Input{
+		Message: message,
+		Default: parameter.Default,
+		Help:    help,
+	}, &value, append([]survey.AskOpt{
+		survey.WithValidator(parameterValidator(scope, parameter)),
+	}, promptOptions...)...)
+	return value, err
+}
+
+// Check the value can be converted to the parameter's type.
+func parameterValidator(scope vfilter.Scope,
+	parameter *artifacts_proto.ArtifactParameter) survey.Validator {
+	return func(val interface{}) error {
+		value, ok := val.(string)
+		if !ok || value == "" {
+			return nil
+		}
+
+		if parameter.ValidatingRegex != "" {
+			re, err := regexp.Compile(parameter.ValidatingRegex)
+			if err == nil && !re.MatchString(value) {
+				return fmt.Errorf("Value must match %v",
+					parameter.ValidatingRegex)
+			}
+		}
+
+		switch parameter.Type {
+		case "int", "int64", "integer":
+			_, err := strconv.ParseInt(value, 0, 64)
+			if err != nil {
+				return fmt.Errorf("%v is not an integer", value)
+			}
+
+		case "float":
+			_, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%v is not a number", value)
+			}
+
+		case "timestamp":
+			_, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				return nil
+			}
+			_, err = functions.ParseTimeFromString(scope, value)
+			if err != nil {
+				return fmt.Errorf("%v is not a valid time", value)
+			}
+
+		case "choices":
+			if len(parameter.Choices) > 0 &&
+				!utils.InString(parameter.Choices, value) {
+				return fmt.Errorf("%v is not one of %v",
+					value, strings.Join(parameter.Choices, ", "))
+			}
+
+		case "regex":
+			_, err := regexp.Compile(value)
+			if err != nil {
+				return err
+			}
+
+		case "json", "json_array", "regex_array":
+			var result interface{}
+			err := json.Unmarshal([]byte(value), &result)
+			if err != nil {
+				return fmt.Errorf("Invalid JSON: %v", err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,126 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	expect "github.com/Netflix/go-expect"
+	"github.com/Velocidex/ordereddict"
+	"github.com/creack/pty"
+	"github.com/hinshun/vt10x"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+var promptTestArtifact = `
+name: Test.Prompt
+parameters:
+  - name: Given
+  - name: Count
+    type: int
+    default: "10"
+  - name: Verbose
+    type: bool
+  - name: Mode
+    type: choices
+    default: Fast
+    choices:
+      - Fast
+      - Slow
+  - name: Secret
+    type: hidden
+sources:
+  - query: SELECT * FROM scope()
+`
+
+type PromptTestSuite struct {
+	test_utils.TestSuite
+}
+
+// Answer the prompts from a virtual terminal.
+func (self *PromptTestSuite) newConsole() *expect.Console {
+	pty_file, tty, err := pty.Open()
+	require.NoError(self.T(), err)
+
+	console, err := expect.NewConsole(
+		expect.WithStdin(pty_file),
+		expect.WithStdout(vt10x.New(vt10x.WithWriter(tty))),
+		expect.WithCloser(pty_file, tty),
+		expect.WithDefaultTimeout(10*time.Second))
+	require.NoError(self.T(), err)
+
+	return console
+}
+
+func (self *PromptTestSuite) TestPromptParameters() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	repository := manager.NewRepository()
+	_, err = repository.LoadYaml(promptTestArtifact, true, true)
+	assert.NoError(self.T(), err)
+
+	console := self.newConsole()
+	defer console.Close()
+
+	promptOptions = []survey.AskOpt{
+		survey.WithStdio(console.Tty(), console.Tty(), console.Tty())}
+	defer func() { promptOptions = nil }()
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+
+		// Given parameters are not asked for again.
+		_, err := console.ExpectString("Count (int)")
+		assert.NoError(self.T(), err)
+
+		// Invalid answers are rejected.
+		console.SendLine("abc")
+		_, err = console.ExpectString("abc is not an integer")
+		assert.NoError(self.T(), err)
+		console.SendLine("42")
+
+		_, err = console.ExpectString("Verbose (bool)")
+		assert.NoError(self.T(), err)
+		console.SendLine("y")
+
+		_, err = console.ExpectString("Mode (choices)")
+		assert.NoError(self.T(), err)
+		console.Send(string(terminal.KeyArrowDown))
+		console.SendLine("")
+
+		// Hidden parameters are never asked for.
+		_, err = console.ExpectEOF()
+		assert.NoError(self.T(), err)
+	}()
+
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	spec := ordereddict.NewDict().
+		Set("Test.Prompt", ordereddict.NewDict().Set("Given", "Yes"))
+	err = promptRepositoryParameters(self.ConfigObj, repository, scope, spec)
+	assert.NoError(self.T(), err)
+
+	console.Tty().Close()
+	<-done
+
+	assert.Equal(self.T(),
+		`{"Test.Prompt":{"Given":"Yes","Count":"42","Verbose":"Y","Mode":"Slow"}}`,
+		json.MustMarshalString(spec))
+}
+
+func TestPromptParameters(t *testing.T) {
+	suite.Run(t, &PromptTestSuite{})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+func TestParameterValidator(t *testing.T) {
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	for _, test_case := range []struct {
+		parameter *artifacts_proto.ArtifactParameter
+		valid     []string
+		invalid   []string
+	}{
+		{&artifacts_proto.ArtifactParameter{Type: "int"},
+			[]string{"10", "-5", "0x10", ""},
+			[]string{"abc", "1.5"}},
+		{&artifacts_proto.ArtifactParameter{Type: "float"},
+			[]string{"1.5", "10", "-2e3"},
+			[]string{"abc", "1,5"}},
+		{&artifacts_proto.ArtifactParameter{Type: "timestamp"},
+			[]string{"1600000000", "2020-01-01", "2020-01-01T10:20:30Z"},
+			[]string{"notatime"}},
+		{&artifacts_proto.ArtifactParameter{Type: "regex"},
+			[]string{".", "^(foo|bar)$"},
+			[]string{"(", "[a-"}},
+		{&artifacts_proto.ArtifactParameter{Type: "json"},
+			[]string{"{}", `{"A": 1}`, "[1, 2]"},
+			[]string{"{", "A: 1"}},
+		{&artifacts_proto.ArtifactParameter{
+			Type: "choices", Choices: []string{"Fast", "Slow"}},
+			[]string{"Fast", "Slow"},
+			[]string{"fast", "Medium"}},
+		{&artifacts_proto.ArtifactParameter{ValidatingRegex: "^[A-Z]+$"},
+			[]string{"ABC"},
+			[]string{"abc", "A1"}},
+	} {
+		validator := parameterValidator(scope, test_case.parameter)
+		for _, value := range test_case.valid {
+			assert.NoError(t, validator(value),
+				"%v should be a valid %v", value, test_case.parameter)
+		}
+		for _, value := range test_case.invalid {
+			assert.Error(t, validator(value),
+				"%v should not be a valid %v", value, test_case.parameter)
+		}
+	}
+}
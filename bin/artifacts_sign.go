@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"www.velocidex.com/golang/velociraptor/bundles"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/services/repository"
+)
+
+var (
+	artifact_command_sign = artifact_command.Command(
+		"sign", "Sign artifact definitions in place.")
+
+	artifact_command_sign_files = artifact_command_sign.Arg(
+		"files", "The artifact files to sign.").Required().Strings()
+
+	artifact_command_sign_key = artifact_command_sign.Flag(
+		"signing_key", "A PEM private key to sign with (default "+
+			"the CA private key in the config)").String()
+
+	artifact_command_verify = artifact_command.Command(
+		"verify", "Verify the signatures of artifact definitions.")
+
+	artifact_command_verify_files = artifact_command_verify.Arg(
+		"files", "The artifact files to verify.").Required().Strings()
+
+	artifact_command_verify_key = artifact_command_verify.Flag(
+		"public_key", "A PEM certificate or public key to verify with "+
+			"(default the artifact signing keys in the config)").String()
+)
+
+func getArtifactSigningKey(config_obj *config_proto.Config) (*rsa.PrivateKey, error) {
+	if *artifact_command_sign_key != "" {
+		data, err := os.ReadFile(*artifact_command_sign_key)
+		if err != nil {
+			return nil, err
+		}
+		return crypto_utils.ParseRsaPrivateKeyFromPemStr(data)
+	}
+
+	if config_obj.CA == nil || config_obj.CA.PrivateKey == "" {
+		return nil, fmt.Errorf("No CA private key in config - use --signing_key")
+	}
+	return crypto_utils.ParseRsaPrivateKeyFromPemStr([]byte(config_obj.CA.PrivateKey))
+}
+
+func getArtifactVerificationKeys(
+	config_obj *config_proto.Config) ([]*rsa.PublicKey, error) {
+	if *artifact_command_verify_key != "" {
+		data, err := os.ReadFile(*artifact_command_verify_key)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := bundles.ParsePublicKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*rsa.PublicKey{key}, nil
+	}
+
+	return repository.GetArtifactSigningKeys(config_obj)
+}
+
+func doArtifactSign() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	key, err := getArtifactSigningKey(config_obj)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range *artifact_command_sign_files {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		signed, err := repository.SignArtifact(string(data), key)
+		if err != nil {
+			return fmt.Errorf("%v: %w", filename, err)
+		}
+
+		err = os.WriteFile(filename, []byte(signed), 0644)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Signed %v\n", filename)
+	}
+
+	return nil
+}
+
+func doArtifactVerify() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	keys, err := getArtifactVerificationKeys(config_obj)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, filename := range *artifact_command_verify_files {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		err = repository.VerifyArtifact(string(data), keys)
+		if err != nil {
+			fmt.Printf("%v: %v\n", filename, err)
+			failed++
+			continue
+		}
+
+		fmt.Printf("%v: OK\n", filename)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%v of %v artifacts failed verification",
+			failed, len(*artifact_command_verify_files))
+	}
+
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case artifact_command_sign.FullCommand():
+			FatalIfError(artifact_command_sign, doArtifactSign)
+
+		case artifact_command_verify.FullCommand():
+			FatalIfError(artifact_command_verify, doArtifactVerify)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/datastore/backup"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+)
+
+var (
+	backup_command = app.Command(
+		"backup", "Backup and restore the datastore and filestore.")
+
+	backup_create = backup_command.Command(
+		"create", "Create a backup. If a previous backup into the same "+
+			"directory was interrupted it is resumed.")
+
+	backup_create_output = backup_create.Arg(
+		"output", "Directory to write the backup to").Required().String()
+
+	backup_create_base = backup_create.Flag(
+		"base", "A previous backup to make an incremental backup "+
+			"against").ExistingDir()
+
+	backup_create_prefix = backup_create.Flag(
+		"prefix", "Filestore prefixes to include "+
+			"(e.g. /clients, /downloads). May be given multiple times.").
+		Strings()
+
+	backup_create_chunk_size = backup_create.Flag(
+		"chunk_size", "Start a new chunk when the current one is "+
+			"larger than this many bytes").
+		Default(fmt.Sprintf("%d", backup.DEFAULT_CHUNK_SIZE)).Int64()
+
+	backup_restore = backup_command.Command(
+		"restore", "Restore a backup into the datastore and filestore.")
+
+	backup_restore_input = backup_restore.Arg(
+		"input", "Directory containing the backup").Required().ExistingDir()
+
+	backup_verify = backup_command.Command(
+		"verify", "Check all the items in a backup can be read.")
+
+	backup_verify_input = backup_verify.Arg(
+		"input", "Directory containing the backup").Required().ExistingDir()
+)
+
+func doBackupCreate() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	config_obj.Frontend.ServerServices = services.GenericToolServices()
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	err = sm.Start(datastore.StartMemcacheFileService)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := backup.Create(ctx, config_obj, db,
+		file_store.GetFileStore(config_obj), backup.Options{
+			Directory: *backup_create_output,
+			Base:      *backup_create_base,
+			Prefixes:  *backup_create_prefix,
+			ChunkSize: *backup_create_chunk_size,
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(json.MustMarshalIndent(manifest)))
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Backed up %v datastore and %v filestore items to %v",
+		manifest.DatastoreItems, manifest.FilestoreItems,
+		*backup_create_output)
+
+	return nil
+}
+
+func doBackupRestore() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	config_obj.Frontend.ServerServices = services.GenericToolServices()
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	err = sm.Start(datastore.StartMemcacheFileService)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the backup is sound before we start overwriting
+	// the datastore.
+	_, err = backup.Verify(ctx, *backup_restore_input)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := backup.Restore(ctx, config_obj, db,
+		file_store.GetFileStore(config_obj), *backup_restore_input)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Restored %v datastore and %v filestore items from %v",
+		manifest.DatastoreItems, manifest.FilestoreItems,
+		*backup_restore_input)
+
+	return nil
+}
+
+func doBackupVerify() error {
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	manifest, err := backup.Verify(ctx, *backup_verify_input)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(json.MustMarshalIndent(manifest)))
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case backup_create.FullCommand():
+			FatalIfError(backup_create, doBackupCreate)
+
+		case backup_restore.FullCommand():
+			FatalIfError(backup_restore, doBackupRestore)
+
+		case backup_verify.FullCommand():
+			FatalIfError(backup_verify, doBackupVerify)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
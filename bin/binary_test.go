@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -236,6 +237,105 @@ func TestCPULimit(t *testing.T) {
 	assert.Regexp(t, "Will throttle query to 5 percent of", string(out))
 }
 
+const dryRunDefinitions = `
+autoexec:
+  artifact_definitions:
+  - name: DryRun
+    parameters:
+    - name: Wanted
+      default: "No"
+    precondition: SELECT * FROM scope() WHERE Wanted =~ "^Yes"
+    sources:
+    - query: SELECT * FROM info()
+`
+
+func TestArtifactsCollectDryRun(t *testing.T) {
+	binary, _ := SetupTest(t)
+
+	// A temp file for the config.
+	config_file, err := ioutil.TempFile("", "config")
+	assert.NoError(t, err)
+
+	defer os.Remove(config_file.Name())
+	config_file.Write([]byte(dryRunDefinitions))
+	config_file.Close()
+
+	// The parameter is substituted into the compiled request and
+	// the precondition passes.
+	cmd := exec.Command(binary,
+		"--config", config_file.Name(),
+		"artifacts", "collect", "DryRun", "--dry_run",
+		"--args", "Wanted=YesPlease")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Contains(t, string(out), "VQLCollectorArgs 0:")
+	assert.Contains(t, string(out), "YesPlease")
+	assert.Contains(t, string(out), "Precondition: passed")
+
+	// Nothing is collected.
+	assert.NotContains(t, string(out), "Starting collection")
+
+	// With the default the precondition fails.
+	cmd = exec.Command(binary,
+		"--config", config_file.Name(),
+		"artifacts", "collect", "DryRun", "--dry_run")
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.NotContains(t, string(out), "YesPlease")
+	assert.Contains(t, string(out), "Precondition: failed")
+}
+
+func TestArtifactsDiff(t *testing.T) {
+	binary, _ := SetupTest(t)
+
+	definitions, err := ioutil.TempDir("", "definitions")
+	assert.NoError(t, err)
+	defer os.RemoveAll(definitions)
+
+	// Override a built in artifact with a new default and query.
+	builtin, err := ioutil.ReadFile(filepath.Join(
+		"..", "artifacts", "definitions", "Linux", "Sys", "Pslist.yaml"))
+	require.NoError(t, err)
+
+	override := strings.Replace(string(builtin),
+		"default: .", "default: velociraptor", 1)
+	override = strings.Replace(override,
+		"WHERE Name =~ processRegex", "WHERE Name =~ processRegex AND Pid > 1", 1)
+	require.NotEqual(t, string(builtin), override)
+
+	err = ioutil.WriteFile(filepath.Join(definitions, "Pslist.yaml"),
+		[]byte(override), 0600)
+	assert.NoError(t, err)
+
+	cmd := exec.Command(binary, "--definitions", definitions,
+		"artifacts", "diff", "Linux.Sys.Pslist")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	// The YAML is compared first, then the compiled VQL.
+	parts := strings.SplitN(string(out), "Compiled VQL:", 2)
+	require.Equal(t, 2, len(parts), string(out))
+
+	yaml_diff := parts[0]
+	assert.Contains(t, yaml_diff, "--- built-in/Linux.Sys.Pslist.yaml")
+	assert.Contains(t, yaml_diff, "+++ local/Linux.Sys.Pslist.yaml")
+	assert.Contains(t, yaml_diff, "\n-    default: .\n")
+	assert.Contains(t, yaml_diff, "\n+    default: velociraptor\n")
+
+	vql_diff := parts[1]
+	assert.Contains(t, vql_diff, "--- built-in/Linux.Sys.Pslist.vql")
+	assert.Contains(t, vql_diff, "+++ local/Linux.Sys.Pslist.vql")
+	assert.Regexp(t, "(?m)^-.*processRegex", vql_diff)
+	assert.Regexp(t, "(?m)^\\+.*Pid > 1", vql_diff)
+
+	// An artifact which is not overridden has no differences.
+	cmd = exec.Command(binary, "--definitions", definitions,
+		"artifacts", "diff", "Generic.Client.Info")
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Contains(t, string(out), "No differences in Generic.Client.Info")
+}
+
 func TestBuildDeb(t *testing.T) {
 	binary, _ := SetupTest(t)
 
@@ -475,3 +575,64 @@ func TestShowConfigWithMergePatch(t *testing.T) {
 func init() {
 	cwd, _ = os.Getwd()
 }
+
+func TestSelfTest(t *testing.T) {
+	binary, _ := SetupTest(t)
+
+	cmd := exec.Command(binary, "selftest", "--json")
+	out, err := cmd.Output()
+	require.NoError(t, err, string(out))
+
+	results := []struct {
+		Name   string `json:"name"`
+		Passed bool   `json:"passed"`
+		Error  string `json:"error"`
+	}{}
+	require.NoError(t, json.Unmarshal(out, &results))
+	require.True(t, len(results) > 0)
+
+	for _, result := range results {
+		assert.True(t, result.Passed, "%v: %v", result.Name, result.Error)
+	}
+}
+
+func TestArtifactsTest(t *testing.T) {
+	binary, _ := SetupTest(t)
+
+	dir, err := ioutil.TempDir("", "artifact_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	passwd := filepath.Join(dir, "fs", "etc", "passwd")
+	require.NoError(t, os.MkdirAll(filepath.Dir(passwd), 0700))
+	require.NoError(t, ioutil.WriteFile(passwd, []byte(
+		"alice:x:1000:1000:Alice:/home/alice:/bin/bash\n"), 0600))
+
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "users.test.yaml"), []byte(`
+Artifact: Linux.Sys.Users
+OS: linux
+Filesystem: fs
+`), 0600))
+
+	// Create the golden file.
+	cmd := exec.Command(binary, "artifacts", "test", dir, "--update")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	golden, err := ioutil.ReadFile(filepath.Join(dir, "users.out.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(golden), "/home/alice")
+
+	cmd = exec.Command(binary, "artifacts", "test", dir)
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	// Changing the fixture fails the test.
+	require.NoError(t, ioutil.WriteFile(passwd, []byte(
+		"bob:x:1001:1001:Bob:/home/bob:/bin/bash\n"), 0600))
+
+	cmd = exec.Command(binary, "artifacts", "test", dir)
+	out, err = cmd.CombinedOutput()
+	require.Error(t, err, string(out))
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	"www.velocidex.com/golang/velociraptor/bundles"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+)
+
+var (
+	bundle_command = app.Command(
+		"bundle", "Update air-gapped servers using signed bundles.")
+
+	bundle_make = bundle_command.Command(
+		"make-update", "Package binaries, artifacts, tools and GeoIP "+
+			"databases into a signed update bundle.")
+
+	bundle_make_output = bundle_make.Arg(
+		"output", "Path to write the bundle to").Required().String()
+
+	bundle_make_binaries = bundle_make.Flag(
+		"binary", "Add a binary as a tool (NAME=PATH, "+
+			"e.g. VelociraptorWindows=velociraptor.exe)").Strings()
+
+	bundle_make_artifacts = bundle_make.Flag(
+		"artifacts", "Add artifact definitions from a yaml file, a "+
+			"directory or an artifact pack zip file").Strings()
+
+	bundle_make_geoip = bundle_make.Flag(
+		"geoip", "Add a GeoIP database").Strings()
+
+	bundle_make_tools = bundle_make.Flag(
+		"tools", "Add all tools in the server's inventory").Bool()
+
+	bundle_make_description = bundle_make.Flag(
+		"description", "A description of the bundle").String()
+
+	bundle_make_key = bundle_make.Flag(
+		"signing_key", "A PEM private key to sign the bundle with "+
+			"(default the CA key in the config)").String()
+
+	bundle_apply = bundle_command.Command(
+		"apply-update", "Verify and apply an update bundle.")
+
+	bundle_apply_path = bundle_apply.Arg(
+		"bundle", "Path to the bundle").Required().ExistingFile()
+
+	bundle_apply_key = bundle_apply.Flag(
+		"public_key", "A PEM certificate or public key to verify the "+
+			"bundle with (default the CA certificate in the config)").String()
+
+	bundle_apply_geoip_dir = bundle_apply.Flag(
+		"geoip_dir", "Where to write GeoIP databases (default "+
+			"<datastore>/geoip)").String()
+
+	bundle_apply_verify_only = bundle_apply.Flag(
+		"verify_only", "Only verify the bundle and show its manifest").Bool()
+
+	bundle_apply_allow_older = bundle_apply.Flag(
+		"allow_older", "Apply the bundle even if it is older than the "+
+			"last applied bundle").Bool()
+)
+
+func getBundleSigningKey(config_obj *config_proto.Config) (*rsa.PrivateKey, error) {
+	if *bundle_make_key != "" {
+		data, err := os.ReadFile(*bundle_make_key)
+		if err != nil {
+			return nil, err
+		}
+		return crypto_utils.ParseRsaPrivateKeyFromPemStr(data)
+	}
+
+	if config_obj.CA == nil || config_obj.CA.PrivateKey == "" {
+		return nil, fmt.Errorf("No CA private key in config - use --signing_key")
+	}
+	return crypto_utils.ParseRsaPrivateKeyFromPemStr([]byte(config_obj.CA.PrivateKey))
+}
+
+func getBundleVerificationKey(config_obj *config_proto.Config) (*rsa.PublicKey, error) {
+	data := []byte(config_obj.Client.CaCertificate)
+	if *bundle_apply_key != "" {
+		var err error
+		data, err = os.ReadFile(*bundle_apply_key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bundles.ParsePublicKey(data)
+}
+
+func addBundleArtifacts(builder *bundles.Builder, root string) error {
+	stat, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+
+	if !stat.IsDir() {
+		if strings.HasSuffix(strings.ToLower(root), ".zip") {
+			return bundles.AddArtifactPack(builder, root)
+		}
+
+		data, err := os.ReadFile(root)
+		if err != nil {
+			return err
+		}
+		return builder.AddArtifact(filepath.Base(root), data)
+	}
+
+	return filepath.Walk(root,
+		func(file_path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !isYamlFile(file_path) {
+				return nil
+			}
+
+			data, err := os.ReadFile(file_path)
+			if err != nil {
+				return err
+			}
+
+			// Keep the directory structure to avoid name clashes.
+			name, err := filepath.Rel(root, file_path)
+			if err != nil {
+				return err
+			}
+			return builder.AddArtifact(filepath.ToSlash(name), data)
+		})
+}
+
+func isYamlFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func doBundleMake() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	key, err := getBundleSigningKey(config_obj)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(*bundle_make_output,
+		os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	builder := bundles.NewBuilder(out, key, *bundle_make_description)
+
+	for _, binary := range *bundle_make_binaries {
+		parts := strings.SplitN(binary, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("Invalid binary %v: should be NAME=PATH", binary)
+		}
+
+		fd, err := os.Open(parts[1])
+		if err != nil {
+			return err
+		}
+
+		err = builder.AddTool(&artifacts_proto.Tool{
+			Name:     parts[0],
+			Filename: filepath.Base(parts[1]),
+		}, fd)
+		fd.Close()
+		if err != nil {
+			return err
+		}
+		logger.Info("Added binary %v as %v", parts[1], parts[0])
+	}
+
+	for _, path := range *bundle_make_artifacts {
+		err = addBundleArtifacts(builder, path)
+		if err != nil {
+			return fmt.Errorf("Adding artifacts from %v: %w", path, err)
+		}
+	}
+
+	for _, path := range *bundle_make_geoip {
+		fd, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = builder.AddGeoIP(filepath.Base(path), fd)
+		fd.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if *bundle_make_tools {
+		sm, err := startup.StartToolServices(ctx, config_obj)
+		defer sm.Close()
+		if err != nil {
+			return err
+		}
+
+		inventory, err := services.GetInventory(config_obj)
+		if err != nil {
+			return err
+		}
+
+		file_store_factory := file_store.GetFileStore(config_obj)
+		for _, item := range inventory.Get().Tools {
+			// Make sure the tool is downloaded.
+			tool, err := inventory.GetToolInfo(ctx, config_obj, item.Name)
+			if err != nil {
+				logger.Info("Skipping tool %v: %v", item.Name, err)
+				continue
+			}
+
+			path_manager := paths.NewInventoryPathManager(config_obj, tool)
+			fd, err := file_store_factory.ReadFile(path_manager.Path())
+			if err != nil {
+				logger.Info("Skipping tool %v: not available locally",
+					tool.Name)
+				continue
+			}
+
+			err = builder.AddTool(tool, fd)
+			fd.Close()
+			if err != nil {
+				return err
+			}
+			logger.Info("Added tool %v", tool.Name)
+		}
+	}
+
+	return builder.Close()
+}
+
+func doBundleApply() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	key, err := getBundleVerificationKey(config_obj)
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.Open(*bundle_apply_path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	stat, err := fd.Stat()
+	if err != nil {
+		return err
+	}
+
+	bundle, err := bundles.Open(fd, stat.Size(), key)
+	if err != nil {
+		return err
+	}
+
+	if *bundle_apply_verify_only {
+		fmt.Println(string(json.MustMarshalIndent(bundle.Manifest)))
+		return nil
+	}
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+	if err != nil {
+		return err
+	}
+
+	report, err := bundles.Apply(ctx, config_obj, bundle, bundles.ApplyOptions{
+		Principal:      config_obj.Client.PinnedServerName,
+		GeoIPDirectory: *bundle_apply_geoip_dir,
+		AllowOlder:     *bundle_apply_allow_older,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(json.MustMarshalIndent(report)))
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Applied bundle: %v artifacts, %v tools, %v GeoIP "+
+		"databases, %v errors", len(report.Artifacts), len(report.Tools),
+		len(report.GeoIP), len(report.Errors))
+
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case bundle_make.FullCommand():
+			FatalIfError(bundle_make, doBundleMake)
+
+		case bundle_apply.FullCommand():
+			FatalIfError(bundle_apply, doBundleApply)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
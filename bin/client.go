@@ -48,9 +48,6 @@ func doClient() error {
 		}
 	}
 
-	ctx, cancel := install_sig_handler()
-	defer cancel()
-
 	// Include the writeback in the client's configuratio.
 	config_obj, err := makeDefaultConfigLoader().
 		WithRequiredClient().
@@ -61,6 +58,16 @@ func doClient() error {
 		return fmt.Errorf("Unable to load config file: %w", err)
 	}
 
+	if !config_obj.Client.DisableCrashReports {
+		err = writeCrashReportOnPanic(config_obj)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
 	return RunClient(ctx, config_obj)
 }
 
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/Velocidex/ordereddict"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/profiles"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	collector_command = app.Command(
+		"collector", "Build offline collectors.")
+
+	collector_build = collector_command.Command(
+		"build", "Build a preconfigured offline collector binary.")
+
+	collector_build_output = collector_build.Arg(
+		"output", "Where to write the collector binary.").Required().String()
+
+	collector_build_os = collector_build.Flag(
+		"os", "The OS the collector will run on.").
+		Default(defaultCollectorOS()).
+		Enum("Windows", "Windows_x86", "Linux", "MacOS")
+
+	collector_build_artifacts = collector_build.Flag(
+		"artifact", "An artifact to collect. May be given multiple times.").
+		HintAction(listArtifactsHint).Strings()
+
+	collector_build_profile = collector_build.Flag(
+		"profile", "Collect the artifacts and parameters of a triage "+
+			"profile (see artifacts profile).").ExistingFile()
+
+	collector_build_args = collector_build.Flag(
+		"args", "Artifact parameters as name=value. Prefix with the "+
+			"artifact name and :: to only apply to one artifact.").Strings()
+
+	collector_build_format = collector_build.Flag(
+		"format", "Output format for the results.").
+		Default("jsonl").Enum("jsonl", "csv")
+
+	collector_build_level = collector_build.Flag(
+		"level", "Compression level (0=no compression).").
+		Default("4").Int64()
+
+	collector_build_output_directory = collector_build.Flag(
+		"output_directory", "An optional output directory prefix.").String()
+
+	collector_build_target = collector_build.Flag(
+		"target", "Where the collector stores the collection.").
+		Default("ZIP").Enum("ZIP", "S3", "GCS", "SFTP")
+
+	collector_build_target_args = collector_build.Flag(
+		"target_args", "Target specific args as name=value "+
+			"(e.g. bucket=my_bucket).").StringMap()
+
+	collector_build_encryption = collector_build.Flag(
+		"encryption", "Encryption scheme for the collection.").
+		Default("None").Enum("None", "Password", "X509", "PGP")
+
+	collector_build_password = collector_build.Flag(
+		"password", "The password for the Password scheme.").String()
+
+	collector_build_public_key = collector_build.Flag(
+		"public_key", "A file with the certificate (X509) or public "+
+			"key (PGP). X509 uses the server certificate by default.").
+		ExistingFile()
+
+	collector_build_timeout = collector_build.Flag(
+		"timeout", "Cancel the collection after this many seconds.").
+		Default("0").Int64()
+
+	collector_build_progress_timeout = collector_build.Flag(
+		"progress_timeout", "Terminate the collector if it makes no "+
+			"progress in this many seconds.").
+		Default("1800").Int64()
+
+	collector_build_cpu_limit = collector_build.Flag(
+		"cpu_limit", "A number between 0 to 100 representing maximum "+
+			"CPU utilization.").
+		Default("0").Int64()
+
+	collector_build_tempdir = collector_build.Flag(
+		"tempdir", "A directory on the endpoint to write tempfiles in.").
+		String()
+
+	collector_build_require_admin = collector_build.Flag(
+		"require_admin", "Require administrator privilege when running.").
+		Default("true").Bool()
+
+	collector_build_prompt = collector_build.Flag(
+		"prompt", "Wait for a prompt before closing.").Bool()
+
+	collector_build_nobanner = collector_build.Flag(
+		"nobanner", "Do not show the Velociraptor banner.").Bool()
+
+	collector_build_quiet = collector_build.Flag(
+		"quiet", "Do not show verbose progress.").Bool()
+
+	collector_build_exe = collector_build.Flag(
+		"exe", "A Velociraptor binary for the target OS to repack. "+
+			"By default the latest release is fetched from GitHub.").
+		ExistingFile()
+
+	collector_build_tools = collector_build.Flag(
+		"tool", "Use a local file for a tool as NAME=PATH. May be "+
+			"given multiple times.").StringMap()
+)
+
+// The tools Server.Utils.CreateCollector repacks for each OS.
+var collectorBinaryTools = map[string]string{
+	"Windows":     "VelociraptorWindows",
+	"Windows_x86": "VelociraptorWindows_x86",
+	"Linux":       "VelociraptorLinux",
+	"MacOS":       "VelociraptorDarwin",
+}
+
+func defaultCollectorOS() string {
+	switch runtime.GOOS {
+	case "windows":
+		if runtime.GOARCH == "386" {
+			return "Windows_x86"
+		}
+		return "Windows"
+	case "darwin":
+		return "MacOS"
+	}
+	return "Linux"
+}
+
+func boolToParameter(value bool) string {
+	if value {
+		return "Y"
+	}
+	return "N"
+}
+
+// Register a local file as a tool so it is used instead of
+// downloading it.
+func addLocalTool(config_obj *config_proto.Config, name, path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Unable to read tool %v: %w", name, err)
+	}
+	defer fd.Close()
+
+	sha_sum := sha256.New()
+	_, err = io.Copy(sha_sum, fd)
+	if err != nil {
+		return fmt.Errorf("Unable to read tool %v: %w", name, err)
+	}
+
+	inventory, err := services.GetInventory(config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = inventory.AddTool(config_obj, &artifacts_proto.Tool{
+		Name:      name,
+		Filename:  filepath.Base(path),
+		ServePath: path,
+		Hash:      hex.EncodeToString(sha_sum.Sum(nil)),
+	}, services.ToolOptions{
+		AdminOverride: true,
+	})
+	if err != nil {
+		return fmt.Errorf("Adding tool %s: %w", name, err)
+	}
+	return nil
+}
+
+func getCollectorEncryptionArgs() (*ordereddict.Dict, error) {
+	result := ordereddict.NewDict().
+		Set("public_key", "").
+		Set("password", "")
+
+	switch *collector_build_encryption {
+	case "Password":
+		if *collector_build_password == "" {
+			return nil, errors.New("The Password scheme requires --password")
+		}
+		result.Set("password", *collector_build_password)
+
+	case "X509", "PGP":
+		if *collector_build_public_key == "" {
+			if *collector_build_encryption == "PGP" {
+				return nil, errors.New("The PGP scheme requires --public_key")
+			}
+			break
+		}
+
+		data, err := ioutil.ReadFile(*collector_build_public_key)
+		if err != nil {
+			return nil, err
+		}
+		result.Set("public_key", string(data))
+	}
+
+	return result, nil
+}
+
+// Load the profile's definitions into the repository so the
+// collector uses exactly the profiled artifacts.
+func loadProfile(config_obj *config_proto.Config,
+	filename string) (*profiles.Profile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := profiles.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := getRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, profile.LoadInto(repository)
+}
+
+func doCollectorBuild() error {
+	encryption_args, err := getCollectorEncryptionArgs()
+	if err != nil {
+		return err
+	}
+
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to create config: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	if *collector_build_exe != "" {
+		err = addLocalTool(config_obj,
+			collectorBinaryTools[*collector_build_os], *collector_build_exe)
+		if err != nil {
+			return err
+		}
+	}
+
+	for name, path := range *collector_build_tools {
+		err = addLocalTool(config_obj, name, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	artifacts := *collector_build_artifacts
+	parameters := buildArtifactSpec(artifacts, *collector_build_args)
+	timeout := *collector_build_timeout
+	cpu_limit := *collector_build_cpu_limit
+
+	if *collector_build_profile != "" {
+		if len(artifacts) > 0 {
+			return errors.New("Use either --artifact or --profile")
+		}
+
+		profile, err := loadProfile(config_obj, *collector_build_profile)
+		if err != nil {
+			return err
+		}
+
+		artifacts = profile.Artifacts
+		parameters = profile.Spec()
+		if timeout == 0 {
+			timeout = int64(profile.Resources.Timeout)
+		}
+		if cpu_limit == 0 {
+			cpu_limit = int64(profile.Resources.CpuLimit)
+		}
+	}
+
+	if len(artifacts) == 0 {
+		return errors.New("No artifacts specified - use --artifact or --profile")
+	}
+
+	// The collector binary is uploaded into here.
+	tmpdir, err := ioutil.TempDir("", "collector")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	target_args := ordereddict.NewDict()
+	for k, v := range *collector_build_target_args {
+		target_args.Set(k, v)
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	// Parameters are passed as strings just like the GUI does.
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     config_obj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger:     log.New(&LogWriter{config_obj}, "", 0),
+		Uploader:   &uploads.FileBasedUploader{UploadDir: tmpdir},
+		Env: ordereddict.NewDict().
+			Set("OS", *collector_build_os).
+			Set("Artifacts", json.MustMarshalString(artifacts)).
+			Set("Parameters", json.MustMarshalString(parameters)).
+			Set("Target", *collector_build_target).
+			Set("TargetArgs", json.MustMarshalString(target_args)).
+			Set("EncryptionScheme", *collector_build_encryption).
+			Set("EncryptionArgs", json.MustMarshalString(encryption_args)).
+			Set("Verbose", boolToParameter(!*collector_build_quiet)).
+			Set("Banner", boolToParameter(!*collector_build_nobanner)).
+			Set("Prompt", boolToParameter(*collector_build_prompt)).
+			Set("Admin", boolToParameter(*collector_build_require_admin)).
+			Set("Tempdir", *collector_build_tempdir).
+			Set("Level", fmt.Sprintf("%d", *collector_build_level)).
+			Set("Format", *collector_build_format).
+			Set("OutputDirectory", *collector_build_output_directory).
+			Set("CpuLimit", fmt.Sprintf("%d", cpu_limit)).
+			Set("ProgressTimeout", fmt.Sprintf("%d",
+				*collector_build_progress_timeout)).
+			Set("Timeout", fmt.Sprintf("%d", timeout)),
+	})
+	defer scope.Close()
+
+	if *trace_vql_flag {
+		scope.SetTracer(logging.NewPlainLogger(config_obj,
+			&logging.ToolComponent))
+	}
+
+	query := `
+  SELECT * FROM Artifact.Server.Utils.CreateCollector(
+     OS=OS, artifacts=Artifacts, parameters=Parameters,
+     target=Target, target_args=TargetArgs,
+     encryption_scheme=EncryptionScheme, encryption_args=EncryptionArgs,
+     opt_verbose=Verbose, opt_banner=Banner, opt_prompt=Prompt,
+     opt_admin=Admin, opt_tempdir=Tempdir, opt_level=Level,
+     opt_format=Format, opt_output_directory=OutputDirectory,
+     opt_cpu_limit=CpuLimit, opt_progress_timeout=ProgressTimeout,
+     opt_timeout=Timeout)`
+
+	vql, err := vfilter.Parse(query)
+	if err != nil {
+		return err
+	}
+
+	collector_path := ""
+	for row := range vql.Eval(sm.Ctx, scope) {
+		binary, _ := scope.Associative(row, "Binary")
+		response, ok := binary.(*uploads.UploadResponse)
+		if ok && response.Error == "" {
+			collector_path = response.Path
+		}
+	}
+
+	if collector_path == "" {
+		return errors.New("Unable to build the collector - check the logs for details")
+	}
+
+	err = utils.CopyFile(ctx, collector_path, *collector_build_output, 0755)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Wrote %v collector to %v", *collector_build_os,
+		*collector_build_output)
+
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case collector_build.FullCommand():
+			FatalIfError(collector_build, doCollectorBuild)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
@@ -504,6 +504,57 @@ func (self *CollectorTestSuite) TestCollectorEncrypted() {
 	assert.Equal(t, []string{"metadata.json", "data.zip"}, names)
 }
 
+// Check that the collector build command produces a working
+// collector from a local binary.
+func (self *CollectorTestSuite) TestCollectorBuild() {
+	t := self.T()
+
+	// Change into the tmpdir
+	old_dir, _ := os.Getwd()
+	defer os.Chdir(old_dir)
+
+	os.Chdir(self.tmpdir)
+
+	output_executable := filepath.Join(
+		self.tmpdir, "built_collector"+self.extension)
+
+	cmd := exec.Command(self.binary, "--config", self.config_file, "-v",
+		"collector", "build", output_executable,
+		"--os", self.OS_TYPE,
+		"--artifact", "Custom.TestHello",
+		"--exe", self.binary,
+		"--no-require_admin")
+	out, err := cmd.CombinedOutput()
+	fmt.Println(string(out))
+	require.NoError(t, err)
+
+	// Run the executable and see what it collects.
+	cmd = exec.Command(output_executable)
+	out, err = cmd.CombinedOutput()
+	fmt.Println(string(out))
+	require.NoError(t, err)
+
+	zip_files, err := filepath.Glob("Collection-*.zip")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(zip_files))
+
+	defer func() {
+		err := os.Remove(zip_files[0])
+		assert.NoError(t, err)
+	}()
+
+	r, err := zip.OpenReader(zip_files[0])
+	assert.NoError(t, err)
+
+	defer r.Close()
+
+	names := []string{}
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	assert.Contains(t, names, "results/Custom.TestHello.json")
+}
+
 func TestCollector(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
@@ -0,0 +1,124 @@
+package main
+
+// Shared by the commands converting other formats (e.g. KAPE files
+// and Sigma rules) into artifacts.
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/artifacts/generate"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Expand directories into the files they contain with one of the
+// extensions.
+func findFilesWithExtension(
+	paths []string, extensions []string) ([]string, error) {
+	result := []string{}
+	for _, path := range paths {
+		err := filepath.Walk(path,
+			func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+
+				extension := strings.ToLower(filepath.Ext(path))
+				if !info.IsDir() && utils.InString(extensions, extension) {
+					result = append(result, path)
+				}
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Convert each file and write the artifacts into the output
+// directory. Files which can not be converted are skipped.
+func convertToArtifacts(
+	kind string, paths []string, extensions []string, output string,
+	convert func(filename string, data []byte) (*generate.Artifact, error)) error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithNullLoader().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	files, err := findFilesWithExtension(paths, extensions)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(output, 0700)
+	if err != nil {
+		return err
+	}
+
+	// Generated artifacts are loaded into a scratch repository to
+	// verify they are valid.
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+	repository := manager.NewRepository()
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	converted := 0
+	for _, filename := range files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		artifact, err := convert(filename, data)
+		if err != nil {
+			logger.Warn("Skipping %v: %v", filename, err)
+			continue
+		}
+
+		_, pres := repository.Get(config_obj, artifact.Name)
+		if pres {
+			logger.Warn("Skipping %v: artifact %v already converted",
+				filename, artifact.Name)
+			continue
+		}
+
+		_, err = repository.LoadYaml(artifact.Yaml,
+			services.ValidateArtifact, !services.ArtifactIsBuiltIn)
+		if err != nil {
+			logger.Warn("Skipping %v: %v", filename, err)
+			continue
+		}
+
+		err = ioutil.WriteFile(filepath.Join(output,
+			artifact.Name+".yaml"), []byte(artifact.Yaml), 0644)
+		if err != nil {
+			return err
+		}
+		converted++
+	}
+
+	logger.Info("Converted %v of %v %v into %v", converted,
+		len(files), kind, output)
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	datastore_command = app.Command(
+		"datastore", "Manipulate the datastore.")
+
+	datastore_export = datastore_command.Command(
+		"export", "Export the datastore into a single archive.")
+
+	datastore_export_output = datastore_export.Arg(
+		"output", "Path to the archive to write").Required().String()
+
+	datastore_export_path = datastore_export.Flag(
+		"path", "Only export the subtree at this datastore path "+
+			"(e.g. /clients/C.123)").String()
+
+	datastore_import = datastore_command.Command(
+		"import", "Import an archive created by datastore export.")
+
+	datastore_import_input = datastore_import.Arg(
+		"input", "Path to the archive to read").Required().ExistingFile()
+
+	datastore_verify = datastore_command.Command(
+		"verify", "Check the file based datastore for corrupted subjects.")
+
+	datastore_verify_repair = datastore_verify.Flag(
+		"repair", "Remove broken subjects and empty directories").Bool()
+
+	datastore_verify_quarantine = datastore_verify.Flag(
+		"quarantine", "When repairing, move broken subjects into "+
+			"this directory instead of deleting them").String()
+
+	datastore_verify_report = datastore_verify.Flag(
+		"report", "Write the report to this file instead of stdout").String()
+
+	datastore_rebalance = datastore_command.Command(
+		"rebalance", "Move client records into their datastore shard "+
+			"after the shard locations changed. Stop the server first.")
+
+	datastore_rebalance_dry_run = datastore_rebalance.Flag(
+		"dry_run", "Only report the clients which need to move").Bool()
+)
+
+func doDatastoreExport() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	config_obj.Frontend.ServerServices = services.GenericToolServices()
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	err = sm.Start(datastore.StartMemcacheFileService)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	out_fd, err := os.OpenFile(*datastore_export_output,
+		os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer out_fd.Close()
+
+	root := path_specs.NewUnsafeDatastorePath(
+		utils.SplitComponents(*datastore_export_path)...)
+
+	count, err := datastore.Export(ctx, config_obj, db, root, out_fd)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Exported %v subjects to %v", count, *datastore_export_output)
+
+	return nil
+}
+
+func doDatastoreImport() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	config_obj.Frontend.ServerServices = services.GenericToolServices()
+	sm, err := startup.StartToolServices(ctx, config_obj)
+	defer sm.Close()
+
+	if err != nil {
+		return err
+	}
+
+	err = sm.Start(datastore.StartMemcacheFileService)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	in_fd, err := os.Open(*datastore_import_input)
+	if err != nil {
+		return err
+	}
+	defer in_fd.Close()
+
+	stat, err := in_fd.Stat()
+	if err != nil {
+		return err
+	}
+
+	count, err := datastore.Import(ctx, config_obj, db, in_fd, stat.Size())
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Imported %v subjects from %v", count, *datastore_import_input)
+
+	return nil
+}
+
+func doDatastoreVerify() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Verifying datastore at %v", config_obj.Datastore.Location)
+
+	report, err := datastore.Verify(ctx, config_obj, datastore.VerifyOptions{
+		Repair:              *datastore_verify_repair,
+		QuarantineDirectory: *datastore_verify_quarantine,
+	})
+	if err != nil {
+		return err
+	}
+
+	serialized := json.MustMarshalIndent(report)
+	if *datastore_verify_report != "" {
+		err = os.WriteFile(*datastore_verify_report, serialized, 0600)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(serialized))
+	}
+
+	logger.Info("Checked %v subjects in %v directories: %v issues, %v repaired",
+		report.Subjects, report.Directories, len(report.Issues),
+		report.Repaired)
+
+	return nil
+}
+
+func doDatastoreRebalance() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Rebalancing datastore across %v shards",
+		len(config_obj.Datastore.ShardLocations)+1)
+
+	report, err := datastore.Rebalance(ctx, config_obj, datastore.RebalanceOptions{
+		DryRun: *datastore_rebalance_dry_run,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(json.MustMarshalIndent(report)))
+
+	logger.Info("Checked %v clients: %v need to move, %v moved",
+		report.Clients, len(report.Moves), report.Moved)
+
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case datastore_export.FullCommand():
+			FatalIfError(datastore_export, doDatastoreExport)
+
+		case datastore_import.FullCommand():
+			FatalIfError(datastore_import, doDatastoreImport)
+
+		case datastore_verify.FullCommand():
+			FatalIfError(datastore_verify, doDatastoreVerify)
+
+		case datastore_rebalance.FullCommand():
+			FatalIfError(datastore_rebalance, doDatastoreRebalance)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"www.velocidex.com/golang/velociraptor/file_store/dedup"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+)
+
+var (
+	filestore_command = app.Command(
+		"filestore", "Manipulate the filestore.")
+
+	filestore_dedup = filestore_command.Command(
+		"dedup", "Store identical uploads only once using hard links.")
+
+	filestore_dedup_min_size = filestore_dedup.Flag(
+		"min_size", "Do not deduplicate files smaller than this "+
+			"(default Datastore.dedup_min_size or 4096)").Int64()
+
+	filestore_dedup_dry_run = filestore_dedup.Flag(
+		"dry_run", "Only report how much space would be saved").Bool()
+
+	filestore_dedup_gc = filestore_dedup.Flag(
+		"gc", "Also remove stored copies which are no longer "+
+			"used by any collection").Bool()
+
+	filestore_dedup_report = filestore_dedup.Flag(
+		"report", "Write the report to this file instead of stdout").String()
+)
+
+func doFilestoreDedup() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredFrontend().
+		WithRequiredLogging().LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	options := dedup.DefaultOptions(config_obj)
+	options.DryRun = *filestore_dedup_dry_run
+	if *filestore_dedup_min_size > 0 {
+		options.MinSize = *filestore_dedup_min_size
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.ToolComponent)
+	logger.Info("Deduplicating uploads in %v",
+		config_obj.Datastore.FilestoreDirectory)
+
+	report, err := dedup.DedupUploads(ctx, config_obj, options)
+	if err != nil {
+		return err
+	}
+
+	if *filestore_dedup_gc {
+		gc_report, err := dedup.GarbageCollect(ctx, config_obj, options)
+		if err != nil {
+			return err
+		}
+		report.BlobsRemoved = gc_report.BlobsRemoved
+		report.BytesSaved += gc_report.BytesSaved
+		report.Errors = append(report.Errors, gc_report.Errors...)
+	}
+
+	serialized := json.MustMarshalIndent(report)
+	if *filestore_dedup_report != "" {
+		err = os.WriteFile(*filestore_dedup_report, serialized, 0600)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(serialized))
+	}
+
+	logger.Info("Checked %v uploads: %v deduplicated saving %v bytes, "+
+		"%v unused copies removed, %v errors", report.Files,
+		report.Deduplicated, report.BytesSaved, report.BlobsRemoved,
+		len(report.Errors))
+
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case filestore_dedup.FullCommand():
+			FatalIfError(filestore_dedup, doFilestoreDedup)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
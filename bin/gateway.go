@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"www.velocidex.com/golang/velociraptor/gateway"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+)
+
+var (
+	gateway_cmd = app.Command("gateway",
+		"Run a gateway which relays clients in a restricted network to the frontends.")
+)
+
+// The gateway is configured with a client config (to verify the
+// frontends in Client.server_urls) and a Gateway section.
+func doGateway() error {
+	config_obj, err := makeDefaultConfigLoader().
+		WithRequiredClient().
+		WithRequiredLogging().
+		WithFileLoader(*config_path).LoadAndValidate()
+	if err != nil {
+		return fmt.Errorf("Unable to load config file: %w", err)
+	}
+
+	if config_obj.Gateway == nil {
+		return errors.New("Config file has no Gateway section")
+	}
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	gw, err := gateway.NewGateway(config_obj)
+	if err != nil {
+		return err
+	}
+
+	wg := &sync.WaitGroup{}
+	err = gw.Start(ctx, wg)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.ClientComponent)
+	logger.Info("<green>Starting</> gateway.")
+
+	wg.Wait()
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		if command == gateway_cmd.FullCommand() {
+			FatalIfError(gateway_cmd, doGateway)
+			return true
+		}
+		return false
+	})
+}
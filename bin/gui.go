@@ -32,6 +32,72 @@ var (
 		"noclient", "Do not bring up a client").Bool()
 )
 
+// Create a new config for a server and a client running on the local
+// host with everything stored in the datastore directory. This config
+// is not suitable for a proper deployment.
+func newLocalServerConfig(datastore_directory string,
+	frontend_port, gui_port uint32) (*config_proto.Config, error) {
+	config_obj := config.GetDefaultConfig()
+	err := generateNewKeys(config_obj)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create config: %w", err)
+	}
+
+	// GUI Configuration - hard coded username/password
+	// and no SSL are suitable for local deployment only!
+	config_obj.GUI.BindAddress = "127.0.0.1"
+	config_obj.GUI.BindPort = gui_port
+
+	// Frontend only suitable for local client
+	config_obj.Frontend.BindAddress = "127.0.0.1"
+	config_obj.Frontend.BindPort = frontend_port
+
+	// Client configuration.
+	config_obj.Client.ServerUrls = []string{
+		fmt.Sprintf("https://localhost:%d/", frontend_port)}
+	config_obj.Client.UseSelfSignedSsl = true
+
+	write_back := filepath.Join(datastore_directory, "Velociraptor.writeback.yaml")
+	config_obj.Client.WritebackWindows = write_back
+	config_obj.Client.WritebackLinux = write_back
+	config_obj.Client.WritebackDarwin = write_back
+
+	// Do not use a local buffer file since there is no
+	// point - we are by definition directly connected.
+	config_obj.Client.LocalBuffer.DiskSize = 0
+	config_obj.Client.LocalBuffer.FilenameWindows = ""
+	config_obj.Client.LocalBuffer.FilenameLinux = ""
+	config_obj.Client.LocalBuffer.FilenameDarwin = ""
+
+	// Make the client use the datastore_directory for tempfiles as well.
+	tmpdir := filepath.Join(datastore_directory, "temp")
+	err = os.MkdirAll(tmpdir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create temp directory: %w", err)
+	}
+
+	config_obj.Client.TempdirLinux = tmpdir
+	config_obj.Client.TempdirWindows = tmpdir
+	config_obj.Client.TempdirDarwin = tmpdir
+
+	config_obj.Datastore.Location = datastore_directory
+	config_obj.Datastore.FilestoreDirectory = datastore_directory
+
+	// Make events run much faster in this configuration
+	config_obj.Defaults.EventMaxWait = 1
+	config_obj.Defaults.EventMaxWaitJitter = 1
+	config_obj.Defaults.EventChangeNotifyAllClients = true
+
+	// Load the "fs" accessor this time (It will be loaded
+	// automatically after restart).
+	err = initFilestoreAccessor(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return config_obj, nil
+}
+
 func doGUI() error {
 	// Start from a clean slate
 	os.Setenv("VELOCIRAPTOR_CONFIG", "")
@@ -70,59 +136,7 @@ func doGUI() error {
 		logging.Prelog("No valid config found - " +
 			"will generare a new one at <green>" + server_config_path)
 
-		config_obj = config.GetDefaultConfig()
-		err := generateNewKeys(config_obj)
-		if err != nil {
-			return fmt.Errorf("Unable to create config: %w", err)
-		}
-
-		// GUI Configuration - hard coded username/password
-		// and no SSL are suitable for local deployment only!
-		config_obj.GUI.BindAddress = "127.0.0.1"
-		config_obj.GUI.BindPort = 8889
-
-		// Frontend only suitable for local client
-		config_obj.Frontend.BindAddress = "127.0.0.1"
-		config_obj.Frontend.BindPort = 8000
-
-		// Client configuration.
-		config_obj.Client.ServerUrls = []string{"https://localhost:8000/"}
-		config_obj.Client.UseSelfSignedSsl = true
-
-		write_back := filepath.Join(datastore_directory, "Velociraptor.writeback.yaml")
-		config_obj.Client.WritebackWindows = write_back
-		config_obj.Client.WritebackLinux = write_back
-		config_obj.Client.WritebackDarwin = write_back
-
-		// Do not use a local buffer file since there is no
-		// point - we are by definition directly connected.
-		config_obj.Client.LocalBuffer.DiskSize = 0
-		config_obj.Client.LocalBuffer.FilenameWindows = ""
-		config_obj.Client.LocalBuffer.FilenameLinux = ""
-		config_obj.Client.LocalBuffer.FilenameDarwin = ""
-
-		// Make the client use the datastore_directory for tempfiles as well.
-		tmpdir := filepath.Join(datastore_directory, "temp")
-		err = os.MkdirAll(tmpdir, 0700)
-		if err != nil {
-			return fmt.Errorf("Unable to create temp directory: %w", err)
-		}
-
-		config_obj.Client.TempdirLinux = tmpdir
-		config_obj.Client.TempdirWindows = tmpdir
-		config_obj.Client.TempdirDarwin = tmpdir
-
-		config_obj.Datastore.Location = datastore_directory
-		config_obj.Datastore.FilestoreDirectory = datastore_directory
-
-		// Make events run much faster in this configuration
-		config_obj.Defaults.EventMaxWait = 1
-		config_obj.Defaults.EventMaxWaitJitter = 1
-		config_obj.Defaults.EventChangeNotifyAllClients = true
-
-		// Load the "fs" accessor this time (It will be loaded
-		// automatically after restart).
-		err = initFilestoreAccessor(config_obj)
+		config_obj, err = newLocalServerConfig(datastore_directory, 8000, 8889)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"www.velocidex.com/golang/velociraptor/artifacts/generate"
+	"www.velocidex.com/golang/velociraptor/kape"
+)
+
+var (
+	kape_command = app.Command("kape", "Work with KAPE targets and modules")
+
+	kape_command_convert = kape_command.Command(
+		"convert", "Convert KAPE targets and modules into artifacts.")
+
+	kape_command_convert_paths = kape_command_convert.Arg(
+		"paths", ".tkape and .mkape files or directories of them "+
+			"(e.g. a KapeFiles checkout).").
+		Required().Strings()
+
+	kape_command_convert_output = kape_command_convert.Flag(
+		"output", "Directory to write the artifacts to.").
+		Required().String()
+
+	kape_command_convert_prefix = kape_command_convert.Flag(
+		"prefix", "A prefix for the artifact names (e.g. Custom.)").
+		String()
+)
+
+func doKapeConvert() error {
+	options := kape.Options{Prefix: *kape_command_convert_prefix}
+
+	return convertToArtifacts("KAPE files", *kape_command_convert_paths,
+		[]string{".tkape", ".mkape"}, *kape_command_convert_output,
+		func(filename string, data []byte) (*generate.Artifact, error) {
+			return kape.Convert(filename, data, options)
+		})
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case kape_command_convert.FullCommand():
+			FatalIfError(kape_command_convert, doKapeConvert)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
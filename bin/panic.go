@@ -5,11 +5,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/mitchellh/panicwrap"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/crashes"
 )
 
 func writeLogOnPanic() error {
@@ -59,6 +62,46 @@ func writeLogOnPanic() error {
 	return nil
 }
 
+// Run the client under a panic wrapper so crashes are captured as
+// crash reports. The reports are uploaded to the server the next time
+// the client starts.
+func writeCrashReportOnPanic(config_obj *config_proto.Config) error {
+	crash_directory, err := crashes.CrashDirectory(config_obj)
+	if err != nil {
+		return err
+	}
+
+	panic_output := ""
+	exitStatus, err := panicwrap.Wrap(&panicwrap.WrapConfig{
+		Handler: func(output string) {
+			panic_output = output
+		},
+
+		// Let the client shut down cleanly when the wrapper is
+		// asked to stop.
+		ForwardSignals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+	})
+	if err != nil {
+		return err
+	}
+
+	// We are the child - continue running the client.
+	if exitStatus < 0 {
+		return nil
+	}
+
+	if panic_output != "" {
+		report := crashes.NewCrashReport(config_obj, panic_output, exitStatus)
+		err = crashes.WriteCrashReport(crash_directory, report)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to write crash report: %v\n", err)
+		}
+	}
+
+	os.Exit(exitStatus)
+	return nil
+}
+
 func FatalIfError(command *kingpin.CmdClause, cb func() error) {
 	err := cb()
 	kingpin.FatalIfError(err, command.FullCommand())
@@ -0,0 +1,547 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	logging "www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/startup"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+var (
+	selftest_command = app.Command(
+		"selftest", "Run an ephemeral server and client to check "+
+			"the deployment environment.")
+
+	selftest_command_datastore = selftest_command.Flag(
+		"datastore", "Create the temporary datastore in this "+
+			"directory (defaults to temp)").ExistingDir()
+
+	selftest_command_keep = selftest_command.Flag(
+		"keep", "Do not remove the temporary datastore when done").Bool()
+
+	selftest_command_timeout = selftest_command.Flag(
+		"timeout", "Fail each check after this many seconds").
+		Default("120").Int64()
+
+	selftest_command_frontend_port = selftest_command.Flag(
+		"frontend_port", "Port for the frontend (default a free port)").
+		Default("0").Uint32()
+
+	selftest_command_gui_port = selftest_command.Flag(
+		"gui_port", "Port for the GUI (default a free port)").
+		Default("0").Uint32()
+
+	selftest_command_json = selftest_command.Flag(
+		"json", "Report the results as JSON").Bool()
+)
+
+const (
+	selftestPrincipal = "selftest"
+	selftestArtifact  = "Generic.Client.Info"
+)
+
+type selfTestResult struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+type selfTest struct {
+	config_obj *config_proto.Config
+	timeout    time.Duration
+	client_id  string
+	results    []*selfTestResult
+}
+
+// Run a single check and record its result.
+func (self *selfTest) run(ctx context.Context, name string,
+	check func(ctx context.Context) error) bool {
+	sub_ctx, cancel := context.WithTimeout(ctx, self.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(sub_ctx)
+
+	result := &selfTestResult{
+		Name:     name,
+		Passed:   err == nil,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	self.results = append(self.results, result)
+
+	logger := logging.GetLogger(self.config_obj, &logging.ToolComponent)
+	if err != nil {
+		result.Error = err.Error()
+		logger.Error("selftest: <red>FAIL</> %v: %v", name, err)
+		return false
+	}
+
+	logger.Info("selftest: <green>PASS</> %v (%v)", name, result.Duration)
+	return true
+}
+
+func (self *selfTest) skip(name, reason string) {
+	self.results = append(self.results, &selfTestResult{
+		Name:  name,
+		Error: "Skipped: " + reason,
+	})
+}
+
+func (self *selfTest) failed() int {
+	count := 0
+	for _, result := range self.results {
+		if !result.Passed {
+			count++
+		}
+	}
+	return count
+}
+
+func (self *selfTest) report() {
+	if *selftest_command_json {
+		fmt.Println(string(json.MustMarshalIndent(self.results)))
+		return
+	}
+
+	for _, result := range self.results {
+		if result.Passed {
+			fmt.Printf("PASS  %-20s %v\n", result.Name, result.Duration)
+		} else {
+			fmt.Printf("FAIL  %-20s %v\n", result.Name, result.Error)
+		}
+	}
+}
+
+// Call cb every second until it is done, fails or the context
+// expires.
+func waitFor(ctx context.Context, what string,
+	cb func() (bool, error)) error {
+	for {
+		done, err := cb()
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Timed out waiting for %v", what)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Use the requested port if it is available or any free port.
+func getSelftestPort(port uint32) (uint32, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return 0, fmt.Errorf("Port %d is not available: %w", port, err)
+	}
+	defer listener.Close()
+
+	return uint32(listener.Addr().(*net.TCPAddr).Port), nil
+}
+
+func (self *selfTest) checkPorts(ctx context.Context) error {
+	var err error
+
+	config_obj := self.config_obj
+	config_obj.Frontend.BindPort, err = getSelftestPort(
+		*selftest_command_frontend_port)
+	if err != nil {
+		return err
+	}
+
+	config_obj.GUI.BindPort, err = getSelftestPort(*selftest_command_gui_port)
+	if err != nil {
+		return err
+	}
+
+	// The API and monitoring ports are not needed by the test.
+	config_obj.API.BindAddress = "127.0.0.1"
+	config_obj.API.BindPort, err = getSelftestPort(0)
+	if err != nil {
+		return err
+	}
+
+	config_obj.Monitoring.BindAddress = "127.0.0.1"
+	config_obj.Monitoring.BindPort, err = getSelftestPort(0)
+	if err != nil {
+		return err
+	}
+
+	config_obj.Client.ServerUrls = []string{
+		fmt.Sprintf("https://localhost:%d/", config_obj.Frontend.BindPort)}
+
+	return nil
+}
+
+func (self *selfTest) checkListening(ctx context.Context) error {
+	for _, port := range []uint32{
+		self.config_obj.Frontend.BindPort, self.config_obj.GUI.BindPort} {
+		address := fmt.Sprintf("127.0.0.1:%d", port)
+		err := waitFor(ctx, address, func() (bool, error) {
+			conn, err := net.Dial("tcp", address)
+			if err != nil {
+				return false, nil
+			}
+			conn.Close()
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write to the datastore and filestore and read the data back.
+func (self *selfTest) checkStorage(ctx context.Context) error {
+	config_obj := self.config_obj
+	value := time.Now().String()
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	urn := path_specs.NewUnsafeDatastorePath("selftest")
+	err = db.SetSubjectWithCompletion(config_obj, urn,
+		&api_proto.Env{Key: "selftest", Value: value}, utils.SyncCompleter)
+	if err != nil {
+		return fmt.Errorf("Writing datastore: %w", err)
+	}
+
+	record := &api_proto.Env{}
+	err = db.GetSubject(config_obj, urn, record)
+	if err != nil {
+		return fmt.Errorf("Reading datastore: %w", err)
+	}
+
+	if record.Value != value {
+		return fmt.Errorf("Datastore returned %q instead of %q",
+			record.Value, value)
+	}
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	fs_urn := path_specs.NewUnsafeFilestorePath("selftest.txt").
+		SetType(api.PATH_TYPE_FILESTORE_ANY)
+
+	writer, err := file_store_factory.WriteFileWithCompletion(
+		fs_urn, utils.SyncCompleter)
+	if err != nil {
+		return fmt.Errorf("Writing filestore: %w", err)
+	}
+
+	err = writer.Truncate()
+	if err == nil {
+		_, err = writer.Write([]byte(value))
+	}
+	writer.Close()
+	if err != nil {
+		return fmt.Errorf("Writing filestore: %w", err)
+	}
+
+	reader, err := file_store_factory.ReadFile(fs_urn)
+	if err != nil {
+		return fmt.Errorf("Reading filestore: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("Reading filestore: %w", err)
+	}
+
+	if string(data) != value {
+		return fmt.Errorf("Filestore returned %q instead of %q",
+			string(data), value)
+	}
+
+	return nil
+}
+
+func (self *selfTest) checkEnrolment(ctx context.Context) error {
+	indexer, err := services.GetIndexer(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	return waitFor(ctx, "the client to enrol", func() (bool, error) {
+		result, err := indexer.SearchClients(ctx, self.config_obj,
+			&api_proto.SearchClientsRequest{Query: "all", Limit: 1},
+			selftestPrincipal)
+		if err != nil || len(result.Items) == 0 {
+			return false, nil
+		}
+
+		self.client_id = result.Items[0].ClientId
+		return true, nil
+	})
+}
+
+func (self *selfTest) checkCollection(ctx context.Context) error {
+	config_obj := self.config_obj
+
+	launcher, err := services.GetLauncher(config_obj)
+	if err != nil {
+		return err
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	flow_id, err := launcher.ScheduleArtifactCollection(ctx, config_obj,
+		acl_managers.NewServerACLManager(config_obj, selftestPrincipal),
+		repository, &flows_proto.ArtifactCollectorArgs{
+			Creator:   selftestPrincipal,
+			ClientId:  self.client_id,
+			Artifacts: []string{selftestArtifact},
+		}, nil)
+	if err != nil {
+		return err
+	}
+
+	return waitFor(ctx, "collection "+flow_id, func() (bool, error) {
+		details, err := launcher.GetFlowDetails(
+			config_obj, self.client_id, flow_id)
+		if err != nil || details.Context == nil {
+			return false, nil
+		}
+
+		switch details.Context.State {
+		case flows_proto.ArtifactCollectorContext_FINISHED:
+			return true, nil
+		case flows_proto.ArtifactCollectorContext_ERROR:
+			return false, fmt.Errorf("Collection %v failed: %v",
+				flow_id, details.Context.Status)
+		}
+		return false, nil
+	})
+}
+
+func (self *selfTest) checkHunt(ctx context.Context) error {
+	config_obj := self.config_obj
+
+	hunt_dispatcher, err := services.GetHuntDispatcher(config_obj)
+	if err != nil {
+		return err
+	}
+
+	hunt_id, err := hunt_dispatcher.CreateHunt(ctx, config_obj,
+		acl_managers.NewServerACLManager(config_obj, selftestPrincipal),
+		&api_proto.Hunt{
+			HuntDescription: "Selftest hunt",
+			Creator:         selftestPrincipal,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Creator:   selftestPrincipal,
+				Artifacts: []string{selftestArtifact},
+			},
+			State:   api_proto.Hunt_RUNNING,
+			Expires: uint64(time.Now().Add(time.Hour).UnixNano() / 1000),
+		})
+	if err != nil {
+		return err
+	}
+
+	return waitFor(ctx, "hunt "+hunt_id, func() (bool, error) {
+		hunt, pres := hunt_dispatcher.GetHunt(hunt_id)
+		if !pres || hunt.Stats == nil {
+			return false, nil
+		}
+
+		if hunt.Stats.TotalClientsWithErrors > 0 {
+			return false, fmt.Errorf("Hunt %v failed on the client", hunt_id)
+		}
+
+		return hunt.Stats.TotalClientsWithResults > 0, nil
+	})
+}
+
+func (self *selfTest) checkNotebook(ctx context.Context) error {
+	notebook_manager, err := services.GetNotebookManager(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	notebook, err := notebook_manager.NewNotebook(ctx, selftestPrincipal,
+		&api_proto.NotebookMetadata{
+			Name:        "Selftest",
+			Description: "A notebook created by the selftest",
+		})
+	if err != nil {
+		return err
+	}
+
+	notebook, err = notebook_manager.NewNotebookCell(ctx,
+		&api_proto.NotebookCellRequest{
+			NotebookId: notebook.NotebookId,
+			Input:      "SELECT * FROM info()",
+			Type:       "VQL",
+		}, selftestPrincipal)
+	if err != nil {
+		return err
+	}
+
+	return waitFor(ctx, "the notebook cell", func() (bool, error) {
+		cell, err := notebook_manager.GetNotebookCell(ctx,
+			notebook.NotebookId, notebook.LatestCellId)
+		if err != nil || cell.Calculating {
+			return false, nil
+		}
+
+		for _, message := range cell.Messages {
+			if strings.HasPrefix(message, "ERROR:") {
+				return false, fmt.Errorf("Notebook cell failed: %v", message)
+			}
+		}
+		return true, nil
+	})
+}
+
+func (self *selfTest) startClient(
+	ctx context.Context, wg *sync.WaitGroup) error {
+	client_config := getClientConfig(self.config_obj)
+
+	// Include the writeback in the client's configuration.
+	config_obj, err := new(config.Loader).
+		WithVerbose(*verbose_flag).
+		WithCustomLoader(func(*config.Loader) (*config_proto.Config, error) {
+			return client_config, nil
+		}).
+		WithRequiredClient().
+		WithWriteback().LoadAndValidate()
+	if err != nil {
+		return err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		err := RunClient(ctx, config_obj)
+		if err != nil {
+			logger := logging.GetLogger(config_obj, &logging.ClientComponent)
+			logger.Error("selftest: client: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func doSelfTest() error {
+	// Start from a clean slate
+	os.Setenv("VELOCIRAPTOR_CONFIG", "")
+
+	datastore_directory, err := ioutil.TempDir(
+		*selftest_command_datastore, "velociraptor_selftest")
+	if err != nil {
+		return fmt.Errorf("Unable to create datastore: %w", err)
+	}
+
+	if !*selftest_command_keep {
+		defer os.RemoveAll(datastore_directory)
+	}
+
+	config_obj, err := newLocalServerConfig(datastore_directory, 0, 0)
+	if err != nil {
+		return err
+	}
+	config_obj.Frontend.ServerServices = services.AllServerServicesSpec()
+
+	test := &selfTest{
+		config_obj: config_obj,
+		timeout:    time.Duration(*selftest_command_timeout) * time.Second,
+	}
+	defer test.report()
+
+	ctx, cancel := install_sig_handler()
+	defer cancel()
+
+	if !test.run(ctx, "Ports", test.checkPorts) {
+		return fmt.Errorf("Selftest failed")
+	}
+
+	sm, err := startup.StartFrontendServices(ctx, config_obj)
+	defer sm.Close()
+
+	test.run(ctx, "Server", func(ctx context.Context) error {
+		if err != nil {
+			return err
+		}
+		return services.GrantRoles(config_obj, selftestPrincipal,
+			[]string{"administrator"})
+	})
+	if err != nil {
+		return fmt.Errorf("Selftest failed")
+	}
+
+	test.run(ctx, "Listening", test.checkListening)
+	test.run(ctx, "Storage", test.checkStorage)
+
+	client_ctx, client_cancel := context.WithCancel(ctx)
+	client_wg := &sync.WaitGroup{}
+	defer client_wg.Wait()
+	defer client_cancel()
+
+	client_started := test.run(ctx, "Client", func(ctx context.Context) error {
+		return test.startClient(client_ctx, client_wg)
+	})
+
+	if client_started && test.run(ctx, "Client enrolment", test.checkEnrolment) {
+		test.run(ctx, "Client collection", test.checkCollection)
+		test.run(ctx, "Hunt", test.checkHunt)
+	} else {
+		test.skip("Client collection", "no client")
+		test.skip("Hunt", "no client")
+	}
+
+	test.run(ctx, "Notebook", test.checkNotebook)
+
+	failed := test.failed()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d selftest checks failed",
+			failed, len(test.results))
+	}
+
+	return nil
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case selftest_command.FullCommand():
+			FatalIfError(selftest_command, doSelfTest)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
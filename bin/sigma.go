@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"www.velocidex.com/golang/velociraptor/artifacts/generate"
+	"www.velocidex.com/golang/velociraptor/sigma"
+)
+
+var (
+	sigma_command = app.Command("sigma", "Work with Sigma rules")
+
+	sigma_command_convert = sigma_command.Command(
+		"convert", "Convert Sigma rules into monitoring artifacts.")
+
+	sigma_command_convert_rules = sigma_command_convert.Arg(
+		"rules", "Sigma rule files or directories of rules.").
+		Required().Strings()
+
+	sigma_command_convert_output = sigma_command_convert.Flag(
+		"output", "Directory to write the artifacts to.").
+		Required().String()
+
+	sigma_command_convert_profile = sigma_command_convert.Flag(
+		"profile", "A field mapping profile (defaults to the built in "+
+			"Windows profile).").String()
+)
+
+func doSigmaConvert() error {
+	profile := sigma.DefaultProfile()
+	if *sigma_command_convert_profile != "" {
+		data, err := ioutil.ReadFile(*sigma_command_convert_profile)
+		if err != nil {
+			return err
+		}
+
+		profile, err = sigma.ParseProfile(data)
+		if err != nil {
+			return fmt.Errorf("%v: %w", *sigma_command_convert_profile, err)
+		}
+	}
+
+	return convertToArtifacts("Sigma rules", *sigma_command_convert_rules,
+		[]string{".yml", ".yaml"}, *sigma_command_convert_output,
+		func(filename string, data []byte) (*generate.Artifact, error) {
+			rule, err := sigma.ParseRule(data)
+			if err != nil {
+				return nil, err
+			}
+			return sigma.Convert(rule, profile)
+		})
+}
+
+func init() {
+	command_handlers = append(command_handlers, func(command string) bool {
+		switch command {
+		case sigma_command_convert.FullCommand():
+			FatalIfError(sigma_command_convert, doSigmaConvert)
+
+		default:
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,245 @@
+package bundles
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/Velocidex/ordereddict"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+type ApplyOptions struct {
+	// The principal recorded as setting the artifacts.
+	Principal string
+
+	// Where GeoIP databases are written. Defaults to the geoip
+	// directory in the datastore.
+	GeoIPDirectory string
+
+	// Allow applying a bundle older than the last applied bundle
+	// (e.g. to deliberately roll back).
+	AllowOlder bool
+}
+
+type Report struct {
+	Artifacts []string `json:"artifacts"`
+	Tools     []string `json:"tools"`
+	GeoIP     []string `json:"geoip"`
+
+	// Errors applying individual members - the rest of the bundle
+	// is still applied.
+	Errors []*ordereddict.Dict `json:"errors"`
+}
+
+func (self *Report) addError(member *Member, err error) {
+	self.Errors = append(self.Errors, ordereddict.NewDict().
+		Set("Member", member.Name).
+		Set("Error", err.Error()))
+}
+
+// Apply a verified bundle to the server. Requires the repository and
+// inventory services.
+func Apply(ctx context.Context,
+	config_obj *config_proto.Config,
+	bundle *Bundle, options ApplyOptions) (*Report, error) {
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory, err := services.GetInventory(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	err = CheckNotReplayed(file_store_factory, paths.BUNDLE_STATE,
+		bundle.Manifest, options.AllowOlder)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	for _, member := range bundle.Manifest.Members {
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		default:
+		}
+
+		switch member.Type {
+		case MEMBER_ARTIFACT:
+			definition, err := applyArtifact(config_obj, manager,
+				bundle, member, options)
+			if err != nil {
+				report.addError(member, err)
+				continue
+			}
+			report.Artifacts = append(report.Artifacts, definition.Name)
+
+		case MEMBER_TOOL:
+			err := applyTool(config_obj, inventory, bundle, member)
+			if err != nil {
+				report.addError(member, err)
+				continue
+			}
+			report.Tools = append(report.Tools, member.Tool.Name)
+
+		case MEMBER_GEOIP:
+			filename, err := applyGeoIP(config_obj, bundle, member, options)
+			if err != nil {
+				report.addError(member, err)
+				continue
+			}
+			report.GeoIP = append(report.GeoIP, filename)
+
+		default:
+			report.addError(member, fmt.Errorf(
+				"Unknown member type %v", member.Type))
+		}
+	}
+
+	// Only newer bundles may be applied after this one.
+	last, err := GetLastApplied(file_store_factory, paths.BUNDLE_STATE)
+	if err == nil && (last == nil || last.Created < bundle.Manifest.Created) {
+		err = RecordApplied(file_store_factory, paths.BUNDLE_STATE,
+			bundle.Manifest)
+	}
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func readAll(bundle *Bundle, member *Member) ([]byte, error) {
+	fd, err := bundle.Open(member)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	return io.ReadAll(fd)
+}
+
+func applyArtifact(config_obj *config_proto.Config,
+	manager services.RepositoryManager,
+	bundle *Bundle, member *Member,
+	options ApplyOptions) (*artifacts_proto.Artifact, error) {
+	data, err := readAll(bundle, member)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.SetArtifactFile(
+		config_obj, options.Principal, string(data), "")
+}
+
+func applyTool(config_obj *config_proto.Config,
+	inventory services.Inventory,
+	bundle *Bundle, member *Member) error {
+	if member.Tool == nil || member.Tool.Name == "" {
+		return fmt.Errorf("No tool definition")
+	}
+
+	tool := &artifacts_proto.Tool{
+		Name:         member.Tool.Name,
+		Filename:     member.Tool.Filename,
+		ServeLocally: true,
+		Hash:         member.Sha256,
+	}
+
+	fd, err := bundle.Open(member)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	// Tools are always served from the root org's public directory
+	// (see the inventory service).
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return err
+	}
+
+	root_org_config, err := org_manager.GetOrgConfig(services.ROOT_ORG_ID)
+	if err != nil {
+		return err
+	}
+
+	path_manager := paths.NewInventoryPathManager(config_obj, tool)
+	file_store_factory := file_store.GetFileStore(root_org_config)
+	writer, err := file_store_factory.WriteFile(path_manager.Path())
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	err = writer.Truncate()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(writer, fd)
+	if err != nil {
+		return err
+	}
+
+	return inventory.AddTool(config_obj, tool, services.ToolOptions{
+		AdminOverride: true,
+	})
+}
+
+func applyGeoIP(config_obj *config_proto.Config,
+	bundle *Bundle, member *Member, options ApplyOptions) (string, error) {
+	directory := options.GeoIPDirectory
+	if directory == "" {
+		if config_obj.Datastore == nil || config_obj.Datastore.Location == "" {
+			return "", fmt.Errorf("No GeoIP directory specified")
+		}
+		directory = filepath.Join(config_obj.Datastore.Location, "geoip")
+	}
+
+	err := os.MkdirAll(directory, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	fd, err := bundle.Open(member)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+
+	// Write to a temp file and rename so queries using the old
+	// database never see a partial file.
+	filename := filepath.Join(directory, path.Base(member.Name))
+	out, err := os.CreateTemp(directory, ".geoip")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(out, fd)
+	out.Close()
+	if err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+
+	err = os.Rename(out.Name(), filename)
+	if err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+
+	return filename, nil
+}
@@ -0,0 +1,345 @@
+// Self contained update bundles for air-gapped servers.
+
+// A bundle is a zip file containing:
+//   manifest.json  - describes every other member with its sha256.
+//   manifest.sig   - RSA PKCS1v15 signature over the manifest's sha256.
+//   tools/*        - binaries and third party tools with their
+//                    inventory definitions in the manifest.
+//   artifacts/*    - artifact definitions.
+//   geoip/*        - GeoIP databases.
+
+// Bundles are made on a server with internet access using
+// `velociraptor bundle make-update` and applied on the air-gapped
+// server with `velociraptor bundle apply-update`. By default bundles
+// are signed with the deployment's CA key and verified against the
+// CA certificate in the config.
+
+package bundles
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	MANIFEST_NAME  = "manifest.json"
+	SIGNATURE_NAME = "manifest.sig"
+
+	MEMBER_TOOL     = "tool"
+	MEMBER_ARTIFACT = "artifact"
+	MEMBER_GEOIP    = "geoip"
+
+	BUNDLE_VERSION = 1
+)
+
+var (
+	invalidSignatureError = errors.New("Bundle signature is invalid")
+)
+
+type Member struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Sha256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+
+	// The inventory definition for tools.
+	Tool *artifacts_proto.Tool `json:"tool,omitempty"`
+}
+
+type Manifest struct {
+	Version     int       `json:"version"`
+	Created     int64     `json:"created"`
+	Description string    `json:"description,omitempty"`
+	Members     []*Member `json:"members"`
+}
+
+// Writes a bundle. Callers add the members then Close() the builder
+// to write the signed manifest.
+type Builder struct {
+	zip      *zip.Writer
+	key      *rsa.PrivateKey
+	manifest *Manifest
+	names    map[string]bool
+}
+
+func NewBuilder(out io.Writer, key *rsa.PrivateKey,
+	description string) *Builder {
+	return &Builder{
+		zip: zip.NewWriter(out),
+		key: key,
+		manifest: &Manifest{
+			Version:     BUNDLE_VERSION,
+			Created:     utils.GetTime().Now().Unix(),
+			Description: description,
+		},
+		names: make(map[string]bool),
+	}
+}
+
+func (self *Builder) addMember(member_type, name string,
+	reader io.Reader) (*Member, error) {
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	if self.names[name] {
+		return nil, fmt.Errorf("Bundle already contains %v", name)
+	}
+	self.names[name] = true
+
+	writer, err := self.zip.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	sha_sum := sha256.New()
+	n, err := io.Copy(writer, io.TeeReader(reader, sha_sum))
+	if err != nil {
+		return nil, err
+	}
+
+	member := &Member{
+		Name:   name,
+		Type:   member_type,
+		Sha256: hex.EncodeToString(sha_sum.Sum(nil)),
+		Size:   n,
+	}
+	self.manifest.Members = append(self.manifest.Members, member)
+	return member, nil
+}
+
+func (self *Builder) AddArtifact(name string, definition []byte) error {
+	_, err := self.addMember(MEMBER_ARTIFACT,
+		path.Join("artifacts", name), bytes.NewReader(definition))
+	return err
+}
+
+// Adds all the artifact definitions in an artifact pack zip file.
+func AddArtifactPack(builder *Builder, filename string) error {
+	zip_reader, err := zip.OpenReader(filename)
+	if err != nil {
+		return err
+	}
+	defer zip_reader.Close()
+
+	pack_name := strings.TrimSuffix(path.Base(filename), path.Ext(filename))
+	for _, member := range zip_reader.File {
+		if !strings.HasSuffix(member.Name, ".yaml") &&
+			!strings.HasSuffix(member.Name, ".yml") {
+			continue
+		}
+
+		fd, err := member.Open()
+		if err != nil {
+			return err
+		}
+
+		data, err := io.ReadAll(fd)
+		fd.Close()
+		if err != nil {
+			return err
+		}
+
+		err = builder.AddArtifact(path.Join(pack_name, member.Name), data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *Builder) AddGeoIP(name string, reader io.Reader) error {
+	_, err := self.addMember(MEMBER_GEOIP,
+		path.Join("geoip", path.Base(name)), reader)
+	return err
+}
+
+// Adds a tool and its file. The tool definition is stored in the
+// manifest so the tool can be added to the inventory as is.
+func (self *Builder) AddTool(tool *artifacts_proto.Tool, reader io.Reader) error {
+	member, err := self.addMember(MEMBER_TOOL,
+		path.Join("tools", tool.Name), reader)
+	if err != nil {
+		return err
+	}
+
+	// The air-gapped server can only serve the tool from its own
+	// filestore.
+	member.Tool = &artifacts_proto.Tool{
+		Name:         tool.Name,
+		Filename:     tool.Filename,
+		ServeLocally: true,
+		Hash:         member.Sha256,
+	}
+	if member.Tool.Filename == "" {
+		member.Tool.Filename = tool.Name
+	}
+	return nil
+}
+
+func (self *Builder) Close() error {
+	serialized, err := json.MarshalIndent(self.manifest)
+	if err != nil {
+		return err
+	}
+
+	writer, err := self.zip.Create(MANIFEST_NAME)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(serialized)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(serialized)
+	signature, err := crypto_utils.SignSha256(
+		self.key, hex.EncodeToString(hash[:]))
+	if err != nil {
+		return err
+	}
+
+	writer, err = self.zip.Create(SIGNATURE_NAME)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(signature)
+	if err != nil {
+		return err
+	}
+
+	return self.zip.Close()
+}
+
+// Parse the key to verify bundles with from a PEM encoded public key
+// or certificate.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	if strings.Contains(string(data), "PUBLIC KEY") {
+		return crypto_utils.PemToPublicKey(data)
+	}
+
+	cert, err := crypto_utils.ParseX509CertFromPemStr(data)
+	if err != nil {
+		return nil, err
+	}
+
+	public_key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("Certificate does not have an RSA key")
+	}
+	return public_key, nil
+}
+
+// A verified bundle.
+type Bundle struct {
+	Manifest *Manifest
+	members  map[string]*zip.File
+}
+
+// Open a bundle and verify its signature and the hashes of all the
+// members. Nothing in the bundle is trusted before this succeeds.
+func Open(reader io.ReaderAt, size int64, key *rsa.PublicKey) (*Bundle, error) {
+	zip_reader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Bundle{
+		Manifest: &Manifest{},
+		members:  make(map[string]*zip.File),
+	}
+	for _, member := range zip_reader.File {
+		result.members[member.Name] = member
+	}
+
+	manifest_data, err := result.readMember(MANIFEST_NAME)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := result.readMember(SIGNATURE_NAME)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(manifest_data)
+	err = crypto_utils.VerifySha256(key, hex.EncodeToString(hash[:]), signature)
+	if err != nil {
+		return nil, invalidSignatureError
+	}
+
+	err = json.Unmarshal(manifest_data, result.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid bundle manifest: %w", err)
+	}
+
+	if result.Manifest.Version > BUNDLE_VERSION {
+		return nil, fmt.Errorf("Unsupported bundle version %v",
+			result.Manifest.Version)
+	}
+
+	for _, member := range result.Manifest.Members {
+		err = result.verifyMember(member)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (self *Bundle) readMember(name string) ([]byte, error) {
+	member, pres := self.members[name]
+	if !pres {
+		return nil, fmt.Errorf("Bundle member %v not found", name)
+	}
+
+	fd, err := member.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	return io.ReadAll(fd)
+}
+
+func (self *Bundle) verifyMember(member *Member) error {
+	fd, err := self.Open(member)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	sha_sum := sha256.New()
+	n, err := io.Copy(sha_sum, fd)
+	if err != nil {
+		return err
+	}
+
+	if n != member.Size ||
+		hex.EncodeToString(sha_sum.Sum(nil)) != member.Sha256 {
+		return fmt.Errorf("Bundle member %v: hash mismatch", member.Name)
+	}
+	return nil
+}
+
+// Open the content of a member.
+func (self *Bundle) Open(member *Member) (io.ReadCloser, error) {
+	zip_member, pres := self.members[member.Name]
+	if !pres {
+		return nil, fmt.Errorf("Bundle member %v not found", member.Name)
+	}
+	return zip_member.Open()
+}
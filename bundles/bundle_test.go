@@ -0,0 +1,152 @@
+package bundles
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/velociraptor/file_store/memory"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+func makeBundle(t *testing.T, key *rsa.PrivateKey) []byte {
+	buffer := &bytes.Buffer{}
+	builder := NewBuilder(buffer, key, "Test bundle")
+
+	require.NoError(t, builder.AddArtifact("Custom.Test.yaml",
+		[]byte("name: Custom.Test\n")))
+	require.NoError(t, builder.AddGeoIP("GeoLite2-City.mmdb",
+		strings.NewReader("geoip data")))
+	require.NoError(t, builder.AddTool(&artifacts_proto.Tool{
+		Name:     "VelociraptorWindows",
+		Filename: "velociraptor.exe",
+		Url:      "https://www.example.com/velociraptor.exe",
+	}, strings.NewReader("binary data")))
+
+	// Names must be unique.
+	assert.Error(t, builder.AddArtifact("Custom.Test.yaml", nil))
+
+	require.NoError(t, builder.Close())
+	return buffer.Bytes()
+}
+
+// Rewrite the bundle replacing the content of one member.
+func replaceMember(t *testing.T, data []byte, name, content string) []byte {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for _, member := range reader.File {
+		out, err := writer.Create(member.Name)
+		require.NoError(t, err)
+
+		if member.Name == name {
+			_, err = out.Write([]byte(content))
+			require.NoError(t, err)
+			continue
+		}
+
+		fd, err := member.Open()
+		require.NoError(t, err)
+		_, err = io.Copy(out, fd)
+		require.NoError(t, err)
+		fd.Close()
+	}
+	require.NoError(t, writer.Close())
+
+	return buffer.Bytes()
+}
+
+func TestBundle(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	data := makeBundle(t, key)
+
+	bundle, err := Open(bytes.NewReader(data), int64(len(data)), &key.PublicKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Test bundle", bundle.Manifest.Description)
+	assert.Equal(t, 3, len(bundle.Manifest.Members))
+
+	names := []string{}
+	for _, member := range bundle.Manifest.Members {
+		names = append(names, member.Name)
+	}
+	assert.Equal(t, []string{"artifacts/Custom.Test.yaml",
+		"geoip/GeoLite2-City.mmdb", "tools/VelociraptorWindows"}, names)
+
+	// Tools are always served from the filestore on the air-gapped
+	// server.
+	tool := bundle.Manifest.Members[2].Tool
+	assert.Equal(t, "velociraptor.exe", tool.Filename)
+	assert.Equal(t, "", tool.Url)
+	assert.True(t, tool.ServeLocally)
+	assert.Equal(t, bundle.Manifest.Members[2].Sha256, tool.Hash)
+
+	content, err := readAll(bundle, bundle.Manifest.Members[2])
+	require.NoError(t, err)
+	assert.Equal(t, "binary data", string(content))
+}
+
+func TestBundleVerification(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	other_key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	data := makeBundle(t, key)
+
+	// Signed by a different key.
+	_, err = Open(bytes.NewReader(data), int64(len(data)), &other_key.PublicKey)
+	assert.Equal(t, invalidSignatureError, err)
+
+	// A modified member.
+	tampered := replaceMember(t, data, "tools/VelociraptorWindows", "evil")
+	_, err = Open(bytes.NewReader(tampered), int64(len(tampered)), &key.PublicKey)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "hash mismatch")
+
+	// A modified manifest.
+	tampered = replaceMember(t, data, MANIFEST_NAME, `{"version": 1}`)
+	_, err = Open(bytes.NewReader(tampered), int64(len(tampered)), &key.PublicKey)
+	assert.Equal(t, invalidSignatureError, err)
+}
+
+func TestBundleReplay(t *testing.T) {
+	memory.ResetMemoryFileStore()
+	defer memory.ResetMemoryFileStore()
+
+	file_store_factory := memory.NewMemoryFileStore(config.GetDefaultConfig())
+
+	older := &Manifest{Version: 1, Created: 1000}
+	newer := &Manifest{Version: 1, Created: 2000}
+
+	// Nothing was applied yet.
+	assert.NoError(t, CheckNotReplayed(file_store_factory,
+		paths.BUNDLE_STATE, older, false))
+	require.NoError(t, RecordApplied(file_store_factory,
+		paths.BUNDLE_STATE, newer))
+
+	// The same bundle can be applied again but an older one can not.
+	assert.NoError(t, CheckNotReplayed(file_store_factory,
+		paths.BUNDLE_STATE, newer, false))
+	err := CheckNotReplayed(file_store_factory,
+		paths.BUNDLE_STATE, older, false)
+	assert.True(t, errors.Is(err, BundleReplayError))
+
+	// Unless explicitly allowed.
+	assert.NoError(t, CheckNotReplayed(file_store_factory,
+		paths.BUNDLE_STATE, older, true))
+}
@@ -0,0 +1,106 @@
+package bundles
+
+// Bundles are signed but a signed bundle stays valid forever, so an
+// old bundle could be applied again to roll back tools or artifacts
+// to versions with known problems. We record the creation time of
+// the last applied bundle and refuse to apply older bundles unless
+// explicitly allowed. Applying the same bundle again is allowed so a
+// failed apply can be retried.
+
+// The same check protects other consumers of bundles (e.g. the
+// remote artifact repository) which keep their own state file.
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	BundleReplayError = errors.New("Bundle is older than the last applied bundle")
+)
+
+// Returns the manifest of the last applied bundle or nil if no
+// bundle was applied yet.
+func GetLastApplied(file_store_factory api.FileStore,
+	state_path api.FSPathSpec) (*Manifest, error) {
+	fd, err := file_store_factory.ReadFile(state_path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	data, err := ioutil.ReadAll(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty file means no bundle was applied.
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	result := &Manifest{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func CheckNotReplayed(file_store_factory api.FileStore,
+	state_path api.FSPathSpec, manifest *Manifest, allow_older bool) error {
+	if allow_older {
+		return nil
+	}
+
+	last, err := GetLastApplied(file_store_factory, state_path)
+	if err != nil {
+		return err
+	}
+
+	if last != nil && manifest.Created < last.Created {
+		return fmt.Errorf("%w: bundle created %v, last applied bundle created %v",
+			BundleReplayError,
+			time.Unix(manifest.Created, 0).UTC().Format(time.RFC3339),
+			time.Unix(last.Created, 0).UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Only the header of the manifest is recorded.
+func RecordApplied(file_store_factory api.FileStore,
+	state_path api.FSPathSpec, manifest *Manifest) error {
+	serialized, err := json.Marshal(&Manifest{
+		Version:     manifest.Version,
+		Created:     manifest.Created,
+		Description: manifest.Description,
+	})
+	if err != nil {
+		return err
+	}
+
+	fd, err := file_store_factory.WriteFileWithCompletion(
+		state_path, utils.SyncCompleter)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return err
+	}
+
+	_, err = fd.Write(serialized)
+	return err
+}
@@ -42,6 +42,13 @@ const (
 	MAX_MEMORY    = 5 * 1024 * 1024
 	MAX_POST_SIZE = 5 * 1024 * 1024
 
+	// Largest payload transferred by the link quality probe.
+	MAX_SPEEDTEST_SIZE = 2 * 1024 * 1024
+
+	// The speedtest endpoint is unauthenticated so the total
+	// bandwidth it may use is limited.
+	SPEEDTEST_BYTES_PER_SECOND = 20 * 1024 * 1024
+
 	// Messages to the client which we dont care about their responses.
 	IgnoreResponseState = uint64(101)
 
@@ -57,6 +64,7 @@ const (
 	SCOPE_ROOT           = "$root"
 	SCOPE_STACK          = "$stack"
 	SCOPE_DEVICE_MANAGER = "$device_manager"
+	SCOPE_MEMORY_BUDGET  = "$memory_budget"
 
 	// Artifact names from packs should start with this
 	ARTIFACT_PACK_NAME_PREFIX   = "Packs."
@@ -83,6 +91,10 @@ const (
 	USN_FREQUENCY       = "USN_FREQUENCY"
 	ZIP_FILE_CACHE_SIZE = "ZIP_FILE_CACHE_SIZE"
 
+	// Set by the launcher to the URL of the client's tool cache
+	// peer.
+	TOOL_CACHE_URL = "TOOL_CACHE_URL"
+
 	// Certain VQL errors represent a failure in artifact
 	// collection. We use this RegExp to determine if log messages
 	// represent failure.
@@ -99,5 +111,7 @@ type key int
 var (
 	HuntIdRegex    = regexp.MustCompile(`^H\.[^.]+$`)
 	ClientIdRegex  = regexp.MustCompile(`^C\.[^\./ ]+$`)
+	JobIdRegex     = regexp.MustCompile(`^J\.[0-9A-V]+$`)
+	SessionIdRegex = regexp.MustCompile(`^[0-9a-f]{32}$`)
 	STOP_ITERATION = errors.New("Stop Iteration")
 )
@@ -0,0 +1,207 @@
+// Client crash reports.
+
+// The client runs under a panic wrapper which captures the Go
+// runtime's panic output when the client crashes. The output is
+// stored as a crash report in the crash directory and uploaded to the
+// server's crash_report handler the next time the client starts.
+package crashes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+const (
+	// The traceback of all goroutines can be very large. We keep
+	// the start of it which contains the panicking goroutine.
+	MAX_DUMP_SIZE = 256 * 1024
+
+	// A client in a crash loop should not fill up the disk - we
+	// only keep the most recent reports.
+	MAX_PENDING_REPORTS = 10
+
+	// The server keeps this many of the most recent reports of each
+	// client. The crash index still counts all of them.
+	MAX_STORED_REPORTS = 20
+
+	CRASH_REPORT_EXTENSION = ".crash.json"
+)
+
+// A crash report waiting in the crash directory to be uploaded.
+type PendingReport struct {
+	Path   string
+	Report *crypto_proto.CrashReport
+}
+
+// Where crash reports are kept until they are uploaded. By default
+// this is the crashes directory next to the writeback file.
+func CrashDirectory(config_obj *config_proto.Config) (string, error) {
+	if config_obj.Client == nil {
+		return "", errors.New("Client not configured")
+	}
+
+	if config_obj.Client.CrashDirectory != "" {
+		return os.ExpandEnv(config_obj.Client.CrashDirectory), nil
+	}
+
+	writeback, err := config.WritebackLocation(config_obj.Client)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(writeback), "crashes"), nil
+}
+
+// Build a crash report from the output of the panicking process.
+func NewCrashReport(
+	config_obj *config_proto.Config,
+	output string, exit_status int) *crypto_proto.CrashReport {
+	version := config.GetVersion()
+	if config_obj.Version != nil {
+		version = config_obj.Version
+	}
+
+	dump := output
+	if len(dump) > MAX_DUMP_SIZE {
+		dump = dump[:MAX_DUMP_SIZE] + "\n... (truncated)\n"
+	}
+
+	result := &crypto_proto.CrashReport{
+		CrashId:    newCrashId(),
+		Timestamp:  uint64(time.Now().UnixNano() / 1000),
+		Version:    version.Version,
+		Commit:     version.Commit,
+		BuildTime:  version.BuildTime,
+		Os:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Reason:     getReason(output),
+		Dump:       dump,
+		ExitStatus: int64(exit_status),
+	}
+
+	// The client id is only known once the client enrolled.
+	writeback, err := config.GetWriteback(config_obj.Client)
+	if err == nil {
+		result.ClientId = writeback.ClientId
+	}
+
+	return result
+}
+
+// The reason is the line where the runtime reports the panic or
+// fatal error. Fallback to the first line of the output.
+func getReason(output string) string {
+	first_line := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "panic: ") ||
+			strings.HasPrefix(line, "fatal error: ") {
+			return line
+		}
+
+		if first_line == "" {
+			first_line = line
+		}
+	}
+	return first_line
+}
+
+func newCrashId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "CR." + hex.EncodeToString(buf)
+}
+
+// Persist the crash report in the crash directory so it can be
+// uploaded when the client starts again.
+func WriteCrashReport(
+	crash_directory string, report *crypto_proto.CrashReport) error {
+	err := os.MkdirAll(crash_directory, 0700)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := json.MarshalIndent(report)
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Join(crash_directory,
+		report.CrashId+CRASH_REPORT_EXTENSION)
+	err = ioutil.WriteFile(filename, serialized, 0600)
+	if err != nil {
+		return fmt.Errorf("WriteFile to %v: %w", filename, err)
+	}
+
+	return pruneCrashReports(crash_directory)
+}
+
+// List the crash reports waiting to be uploaded, oldest first.
+func PendingCrashReports(crash_directory string) ([]*PendingReport, error) {
+	files, err := filepath.Glob(filepath.Join(
+		crash_directory, "*"+CRASH_REPORT_EXTENSION))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*PendingReport, 0, len(files))
+	for _, filename := range files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+
+		report := &crypto_proto.CrashReport{}
+		err = protojson.Unmarshal(data, report)
+		if err != nil || report.CrashId == "" {
+			// Corrupted reports will never upload - just remove
+			// them.
+			os.Remove(filename)
+			continue
+		}
+
+		result = append(result, &PendingReport{
+			Path:   filename,
+			Report: report,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Report.Timestamp < result[j].Report.Timestamp
+	})
+
+	return result, nil
+}
+
+func pruneCrashReports(crash_directory string) error {
+	pending, err := PendingCrashReports(crash_directory)
+	if err != nil {
+		return err
+	}
+
+	for len(pending) > MAX_PENDING_REPORTS {
+		os.Remove(pending[0].Path)
+		pending = pending[1:]
+	}
+
+	return nil
+}
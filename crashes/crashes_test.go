@@ -0,0 +1,143 @@
+package crashes_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/crashes"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+)
+
+var panicOutput = `
+goroutine 1 [running]:
+panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x4a2f6b]
+
+goroutine 1 [running]:
+main.main()
+	/tmp/main.go:7 +0x1b
+`
+
+type CrashesTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *CrashesTestSuite) TestPendingReports() {
+	dir, err := ioutil.TempDir("", "crashes_test")
+	require.NoError(self.T(), err)
+	defer os.RemoveAll(dir)
+
+	report := crashes.NewCrashReport(self.ConfigObj, panicOutput, 2)
+	assert.Equal(self.T(),
+		"panic: runtime error: invalid memory address or nil pointer dereference",
+		report.Reason)
+	assert.Equal(self.T(), int64(2), report.ExitStatus)
+
+	err = crashes.WriteCrashReport(dir, report)
+	require.NoError(self.T(), err)
+
+	pending, err := crashes.PendingCrashReports(dir)
+	require.NoError(self.T(), err)
+	require.Equal(self.T(), 1, len(pending))
+	assert.Equal(self.T(), report.CrashId, pending[0].Report.CrashId)
+	assert.Equal(self.T(), report.Dump, pending[0].Report.Dump)
+
+	// Only the most recent reports are kept.
+	for i := 0; i < crashes.MAX_PENDING_REPORTS+5; i++ {
+		report := crashes.NewCrashReport(self.ConfigObj, panicOutput, 2)
+		report.Timestamp += uint64(i + 1)
+		err = crashes.WriteCrashReport(dir, report)
+		require.NoError(self.T(), err)
+	}
+
+	pending, err = crashes.PendingCrashReports(dir)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), crashes.MAX_PENDING_REPORTS, len(pending))
+}
+
+func (self *CrashesTestSuite) TestCrashIndex() {
+	for i := 0; i < 3; i++ {
+		version := "0.6.7"
+		if i == 2 {
+			version = "0.6.8"
+		}
+
+		err := crashes.StoreCrashReport(self.ConfigObj, "C.1234",
+			&crypto_proto.CrashReport{
+				CrashId:   fmt.Sprintf("CR.%d", i),
+				Timestamp: uint64(100 + i),
+				Version:   version,
+				Reason:    fmt.Sprintf("panic: %d", i),
+			})
+		require.NoError(self.T(), err)
+	}
+
+	// Retransmitted reports are only counted once.
+	err := crashes.StoreCrashReport(self.ConfigObj, "C.1234",
+		&crypto_proto.CrashReport{
+			CrashId:   "CR.1",
+			Timestamp: 101,
+			Version:   "0.6.7",
+		})
+	require.NoError(self.T(), err)
+
+	err = crashes.StoreCrashReport(self.ConfigObj, "C.5678",
+		&crypto_proto.CrashReport{
+			CrashId:   "CR.5",
+			Timestamp: 50,
+			Version:   "0.6.7",
+		})
+	require.NoError(self.T(), err)
+
+	records, err := crashes.ListCrashingClients(self.ConfigObj, "0.6.7")
+	require.NoError(self.T(), err)
+	require.Equal(self.T(), 2, len(records))
+	assert.Equal(self.T(), "C.1234", records[0].ClientId)
+	assert.Equal(self.T(), uint64(2), records[0].Count)
+	assert.Equal(self.T(), "panic: 1", records[0].LastReason)
+	assert.Equal(self.T(), "C.5678", records[1].ClientId)
+
+	records, err = crashes.ListCrashingClients(self.ConfigObj, "")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(records))
+
+	reports, err := crashes.ListClientCrashReports(self.ConfigObj, "C.1234")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(reports))
+	assert.Equal(self.T(), "CR.2", reports[0].CrashId)
+}
+
+// Only the most recent reports of a client are kept.
+func (self *CrashesTestSuite) TestStoredReportsExpire() {
+	for i := 0; i < crashes.MAX_STORED_REPORTS+5; i++ {
+		err := crashes.StoreCrashReport(self.ConfigObj, "C.1234",
+			&crypto_proto.CrashReport{
+				CrashId:   fmt.Sprintf("CR.%d", i),
+				Timestamp: uint64(100 + i),
+				Version:   "0.6.7",
+			})
+		require.NoError(self.T(), err)
+	}
+
+	reports, err := crashes.ListClientCrashReports(self.ConfigObj, "C.1234")
+	require.NoError(self.T(), err)
+	require.Equal(self.T(), crashes.MAX_STORED_REPORTS, len(reports))
+	assert.Equal(self.T(), fmt.Sprintf("CR.%d", crashes.MAX_STORED_REPORTS+4),
+		reports[0].CrashId)
+	assert.Equal(self.T(), "CR.5", reports[len(reports)-1].CrashId)
+
+	records, err := crashes.ListCrashingClients(self.ConfigObj, "0.6.7")
+	require.NoError(self.T(), err)
+	require.Equal(self.T(), 1, len(records))
+	assert.Equal(self.T(), uint64(crashes.MAX_STORED_REPORTS+5), records[0].Count)
+}
+
+func TestCrashes(t *testing.T) {
+	suite.Run(t, &CrashesTestSuite{})
+}
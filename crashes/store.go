@@ -0,0 +1,199 @@
+package crashes
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+var (
+	// Protects the read-modify-write of the crash index.
+	index_mu sync.Mutex
+)
+
+// Store a crash report received from an authenticated client and
+// update the crash index.
+func StoreCrashReport(
+	config_obj *config_proto.Config,
+	client_id string, report *crypto_proto.CrashReport) error {
+	if report.CrashId == "" {
+		return errors.New("Crash report has no crash id")
+	}
+
+	// Only trust the client id we authenticated.
+	report.ClientId = client_id
+	if report.Version == "" {
+		report.Version = "unknown"
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	path_manager := paths.NewCrashPathManager(client_id)
+
+	index_mu.Lock()
+	defer index_mu.Unlock()
+
+	record := &api_proto.CrashIndexRecord{}
+	err = db.GetSubject(config_obj,
+		path_manager.IndexRecord(report.Version), record)
+	if err != nil || record.ClientId == "" {
+		record = &api_proto.CrashIndexRecord{
+			ClientId:   client_id,
+			Version:    report.Version,
+			FirstCrash: report.Timestamp,
+		}
+	}
+
+	// The client retransmits reports it did not see acknowledged.
+	if record.LastCrashId == report.CrashId {
+		return nil
+	}
+
+	err = db.SetSubject(config_obj, path_manager.Report(report.CrashId), report)
+	if err != nil {
+		return err
+	}
+
+	err = expireCrashReports(config_obj, client_id)
+	if err != nil {
+		return err
+	}
+
+	record.Count++
+	if report.Timestamp >= record.LastCrash {
+		record.LastCrash = report.Timestamp
+		record.LastReason = report.Reason
+		record.LastCrashId = report.CrashId
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("Client %v (version %v) crashed: %v",
+		client_id, report.Version, report.Reason)
+
+	return db.SetSubject(config_obj,
+		path_manager.IndexRecord(report.Version), record)
+}
+
+// Remove the oldest reports of the client so a client in a crash
+// loop can not fill up the datastore.
+func expireCrashReports(
+	config_obj *config_proto.Config, client_id string) error {
+	reports, err := ListClientCrashReports(config_obj, client_id)
+	if err != nil || len(reports) <= MAX_STORED_REPORTS {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	path_manager := paths.NewCrashPathManager(client_id)
+	for _, report := range reports[MAX_STORED_REPORTS:] {
+		err = db.DeleteSubject(config_obj, path_manager.Report(report.CrashId))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List the crashing clients running the version, or all versions
+// if version is empty. Most recent crashes come first.
+func ListCrashingClients(
+	config_obj *config_proto.Config,
+	version string) ([]*api_proto.CrashIndexRecord, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []string{version}
+	if version == "" {
+		versions = nil
+		children, err := db.ListChildren(config_obj, paths.CRASH_INDEX_ROOT)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			if child.IsDir() {
+				versions = append(versions, child.Base())
+			}
+		}
+	}
+
+	result := []*api_proto.CrashIndexRecord{}
+	for _, version := range versions {
+		children, err := db.ListChildren(config_obj,
+			paths.CRASH_INDEX_ROOT.AddChild(version))
+		if err != nil {
+			continue
+		}
+
+		for _, child := range children {
+			if child.IsDir() {
+				continue
+			}
+
+			record := &api_proto.CrashIndexRecord{}
+			err = db.GetSubject(config_obj, child, record)
+			if err != nil {
+				continue
+			}
+			result = append(result, record)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastCrash > result[j].LastCrash
+	})
+
+	return result, nil
+}
+
+// List the crash reports uploaded by a client, most recent first.
+func ListClientCrashReports(
+	config_obj *config_proto.Config,
+	client_id string) ([]*crypto_proto.CrashReport, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(config_obj,
+		paths.NewCrashPathManager(client_id).Directory())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*crypto_proto.CrashReport, 0, len(children))
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		report := &crypto_proto.CrashReport{}
+		err = db.GetSubject(config_obj, child, report)
+		if err != nil {
+			continue
+		}
+		result = append(result, report)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp > result[j].Timestamp
+	})
+
+	return result, nil
+}
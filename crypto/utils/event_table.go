@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+)
+
+var EventTableNotSigned = errors.New("Event table is not signed")
+
+// Event tables are signed by the server so clients can start the
+// event queries they cached in their writeback on boot, before they
+// reach the server. The signature is made with the frontend key and
+// verified with the frontend certificate which must be issued by the
+// deployment's CA.
+func eventTableDigest(table *actions_proto.VQLEventTable) (string, error) {
+	unsigned := proto.Clone(table).(*actions_proto.VQLEventTable)
+	unsigned.Signature = nil
+	unsigned.ServerCertificate = ""
+
+	serialized, err := proto.MarshalOptions{Deterministic: true}.Marshal(unsigned)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(serialized)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func SignEventTable(config_obj *config_proto.Config,
+	table *actions_proto.VQLEventTable) error {
+	if config_obj.Frontend == nil || config_obj.Frontend.PrivateKey == "" {
+		return errors.New("SignEventTable: No frontend key configured")
+	}
+
+	key, err := ParseRsaPrivateKeyFromPemStr(
+		[]byte(config_obj.Frontend.PrivateKey))
+	if err != nil {
+		return err
+	}
+
+	digest, err := eventTableDigest(table)
+	if err != nil {
+		return err
+	}
+
+	table.Signature, err = SignSha256(key, digest)
+	if err != nil {
+		return err
+	}
+	table.ServerCertificate = config_obj.Frontend.Certificate
+	return nil
+}
+
+func VerifyEventTable(config_obj *config_proto.Config,
+	table *actions_proto.VQLEventTable) error {
+	if len(table.Signature) == 0 {
+		return EventTableNotSigned
+	}
+
+	if config_obj.Client == nil {
+		return errors.New("No client configuration")
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(config_obj.Client.CaCertificate)) {
+		return errors.New("Failed to parse CA certificate")
+	}
+
+	cert, err := ParseX509CertFromPemStr([]byte(table.ServerCertificate))
+	if err != nil {
+		return err
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots})
+	if err != nil {
+		return err
+	}
+
+	// Only the server may sign event tables.
+	server_name := config_obj.Client.PinnedServerName
+	if server_name == "" {
+		server_name = constants.PinnedServerName
+	}
+
+	if GetSubjectName(cert) != server_name {
+		return fmt.Errorf("Event table signed by %v instead of %v",
+			GetSubjectName(cert), server_name)
+	}
+
+	public_key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("Server certificate is not RSA")
+	}
+
+	digest, err := eventTableDigest(table)
+	if err != nil {
+		return err
+	}
+
+	return VerifySha256(public_key, digest, table.Signature)
+}
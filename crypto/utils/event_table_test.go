@@ -0,0 +1,59 @@
+package utils_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/velociraptor/crypto"
+	"www.velocidex.com/golang/velociraptor/crypto/utils"
+)
+
+func TestEventTableSignature(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+
+	ca_bundle, err := crypto.GenerateCACert(2048)
+	require.NoError(t, err)
+	config_obj.Client.CaCertificate = ca_bundle.Cert
+	config_obj.CA.PrivateKey = ca_bundle.PrivateKey
+
+	frontend_cert, err := crypto.GenerateServerCert(
+		config_obj, config_obj.Client.PinnedServerName)
+	require.NoError(t, err)
+	config_obj.Frontend.Certificate = frontend_cert.Cert
+	config_obj.Frontend.PrivateKey = frontend_cert.PrivateKey
+
+	table := &actions_proto.VQLEventTable{
+		Version: 10,
+		Event: []*actions_proto.VQLCollectorArgs{{
+			Query: []*actions_proto.VQLRequest{{
+				Name: "Generic.Client.Stats",
+				VQL:  "SELECT * FROM info()",
+			}},
+		}},
+	}
+
+	// Unsigned tables are rejected.
+	assert.True(t, errors.Is(utils.VerifyEventTable(config_obj, table),
+		utils.EventTableNotSigned))
+
+	require.NoError(t, utils.SignEventTable(config_obj, table))
+	assert.NoError(t, utils.VerifyEventTable(config_obj, table))
+
+	// Tampering with the queries breaks the signature.
+	table.Event[0].Query[0].VQL = "SELECT * FROM execve(argv='id')"
+	assert.Error(t, utils.VerifyEventTable(config_obj, table))
+
+	// Certificates must be issued by the CA to the server.
+	table.Event[0].Query[0].VQL = "SELECT * FROM info()"
+	other_cert, err := crypto.GenerateServerCert(config_obj, "C.1234")
+	require.NoError(t, err)
+	config_obj.Frontend.Certificate = other_cert.Cert
+	config_obj.Frontend.PrivateKey = other_cert.PrivateKey
+
+	require.NoError(t, utils.SignEventTable(config_obj, table))
+	assert.Error(t, utils.VerifyEventTable(config_obj, table))
+}
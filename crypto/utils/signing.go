@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+)
+
+// Clients sign the sha256 hashes of the data they collect with their
+// client key so the server can attribute the data to the endpoint's
+// key pair.
+func SignSha256(key *rsa.PrivateKey, sha256_hex string) ([]byte, error) {
+	hash, err := hex.DecodeString(sha256_hex)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash)
+}
+
+func VerifySha256(key *rsa.PublicKey, sha256_hex string, signature []byte) error {
+	hash, err := hex.DecodeString(sha256_hex)
+	if err != nil {
+		return err
+	}
+	return rsa.VerifyPKCS1v15(key, crypto.SHA256, hash, signature)
+}
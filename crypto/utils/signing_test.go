@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignSha256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	sum := sha256.Sum256([]byte("Hello world"))
+	sha256_hex := hex.EncodeToString(sum[:])
+
+	signature, err := SignSha256(key, sha256_hex)
+	assert.NoError(t, err)
+
+	err = VerifySha256(&key.PublicKey, sha256_hex, signature)
+	assert.NoError(t, err)
+
+	// A different hash does not verify.
+	sum = sha256.Sum256([]byte("Goodbye world"))
+	err = VerifySha256(&key.PublicKey, hex.EncodeToString(sum[:]), signature)
+	assert.Error(t, err)
+
+	// Neither does a signature from another key.
+	other_key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	err = VerifySha256(&other_key.PublicKey, sha256_hex, signature)
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// A minimal CBOR (RFC 8949) decoder supporting the subset used by
+// WebAuthn attestation objects and COSE keys: integers, byte and
+// text strings, arrays, maps and simple values. Indefinite lengths
+// and floats are not supported.
+
+const (
+	// Guard against hostile nesting.
+	maxCBORDepth = 16
+)
+
+var (
+	truncatedCBORError = errors.New("CBOR: truncated data")
+)
+
+type cborDecoder struct {
+	data   []byte
+	offset int
+}
+
+// Decode a single CBOR item and return the remaining bytes.
+func decodeCBOR(data []byte) (interface{}, []byte, error) {
+	decoder := &cborDecoder{data: data}
+	item, err := decoder.decode(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, data[decoder.offset:], nil
+}
+
+func (self *cborDecoder) read(length uint64) ([]byte, error) {
+	if length > uint64(len(self.data)-self.offset) {
+		return nil, truncatedCBORError
+	}
+	result := self.data[self.offset : self.offset+int(length)]
+	self.offset += int(length)
+	return result, nil
+}
+
+// Read the argument following the initial byte.
+func (self *cborDecoder) readArgument(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		data, err := self.read(1)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(data[0]), nil
+	case info == 25:
+		data, err := self.read(2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(data)), nil
+	case info == 26:
+		data, err := self.read(4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(data)), nil
+	case info == 27:
+		data, err := self.read(8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(data), nil
+	}
+	return 0, fmt.Errorf("CBOR: unsupported additional info %v", info)
+}
+
+func (self *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > maxCBORDepth {
+		return nil, errors.New("CBOR: nesting too deep")
+	}
+
+	header, err := self.read(1)
+	if err != nil {
+		return nil, err
+	}
+
+	major := header[0] >> 5
+	info := header[0] & 0x1f
+
+	// Simple values
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("CBOR: unsupported simple value %v", info)
+	}
+
+	argument, err := self.readArgument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		if argument > 1<<63-1 {
+			return nil, errors.New("CBOR: integer overflow")
+		}
+		return int64(argument), nil
+
+	case 1:
+		if argument > 1<<63-1 {
+			return nil, errors.New("CBOR: integer overflow")
+		}
+		return -1 - int64(argument), nil
+
+	case 2:
+		data, err := self.read(argument)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, data...), nil
+
+	case 3:
+		data, err := self.read(argument)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+
+	case 4:
+		// Each item takes at least a byte.
+		if argument > uint64(len(self.data)) {
+			return nil, truncatedCBORError
+		}
+
+		result := make([]interface{}, 0, argument)
+		for i := uint64(0); i < argument; i++ {
+			item, err := self.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, item)
+		}
+		return result, nil
+
+	case 5:
+		if argument > uint64(len(self.data)) {
+			return nil, truncatedCBORError
+		}
+
+		result := make(map[interface{}]interface{})
+		for i := uint64(0); i < argument; i++ {
+			key, err := self.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errors.New("CBOR: unsupported map key")
+			}
+
+			value, err := self.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = value
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("CBOR: unsupported major type %v", major)
+}
@@ -0,0 +1,309 @@
+// Server side verification of WebAuthn (passkey and security key)
+// registrations and assertions.
+//
+// Only what the built-in authenticator needs is implemented: ES256
+// and RS256 credentials, and "none" attestation - the attestation
+// statement is not verified so any authenticator model is accepted.
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	FLAG_USER_PRESENT  = 0x01
+	FLAG_USER_VERIFIED = 0x04
+	FLAG_ATTESTED_DATA = 0x40
+
+	CEREMONY_CREATE = "webauthn.create"
+	CEREMONY_GET    = "webauthn.get"
+
+	// COSE algorithm identifiers
+	COSE_ALG_ES256 = -7
+	COSE_ALG_RS256 = -257
+)
+
+var (
+	InvalidResponseError = errors.New("Invalid WebAuthn response")
+)
+
+// A registered credential.
+type Credential struct {
+	Id []byte
+
+	// PKIX DER encoded public key
+	PublicKey []byte
+	SignCount uint32
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type authenticatorData struct {
+	RPIdHash  []byte
+	Flags     byte
+	SignCount uint32
+
+	// Only present in registrations.
+	CredentialId []byte
+	PublicKey    []byte
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %v", InvalidResponseError,
+		fmt.Sprintf(format, args...))
+}
+
+// Challenges are sent to the browser as base64url.
+func NewChallenge() (string, error) {
+	challenge := make([]byte, 32)
+	_, err := rand.Read(challenge)
+	if err != nil {
+		return "", err
+	}
+	return EncodeId(challenge), nil
+}
+
+func EncodeId(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeId(data string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(data)
+}
+
+func verifyClientData(data []byte, ceremony, challenge, origin string) error {
+	client_data := &clientData{}
+	err := json.Unmarshal(data, client_data)
+	if err != nil {
+		return invalid("client data: %v", err)
+	}
+
+	if client_data.Type != ceremony {
+		return invalid("unexpected ceremony %v", client_data.Type)
+	}
+
+	if client_data.Challenge != challenge {
+		return invalid("challenge mismatch")
+	}
+
+	if client_data.Origin != origin {
+		return invalid("unexpected origin %v", client_data.Origin)
+	}
+	return nil
+}
+
+func parseAuthenticatorData(data []byte, rp_id string) (
+	*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, invalid("authenticator data too short")
+	}
+
+	result := &authenticatorData{
+		RPIdHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	rp_id_hash := sha256.Sum256([]byte(rp_id))
+	if !bytes.Equal(result.RPIdHash, rp_id_hash[:]) {
+		return nil, invalid("relying party mismatch")
+	}
+
+	if result.Flags&FLAG_USER_PRESENT == 0 {
+		return nil, invalid("user not present")
+	}
+
+	if result.Flags&FLAG_ATTESTED_DATA == 0 {
+		return result, nil
+	}
+
+	// Attested credential data: aaguid (16) id length (2) id and
+	// the COSE public key.
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, invalid("attested credential data too short")
+	}
+
+	id_length := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < id_length {
+		return nil, invalid("credential id truncated")
+	}
+	result.CredentialId = rest[:id_length]
+
+	cose_key, _, err := decodeCBOR(rest[id_length:])
+	if err != nil {
+		return nil, invalid("credential public key: %v", err)
+	}
+
+	result.PublicKey, err = parseCOSEKey(cose_key)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Convert a COSE key to a PKIX DER public key.
+func parseCOSEKey(item interface{}) ([]byte, error) {
+	key, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, invalid("COSE key is not a map")
+	}
+
+	get_bytes := func(label int64) []byte {
+		value, _ := key[label].([]byte)
+		return value
+	}
+
+	alg, _ := key[int64(3)].(int64)
+	switch alg {
+	case COSE_ALG_ES256:
+		x, y := get_bytes(-2), get_bytes(-3)
+		if crv, _ := key[int64(-1)].(int64); crv != 1 ||
+			len(x) != 32 || len(y) != 32 {
+			return nil, invalid("unsupported EC key")
+		}
+
+		public_key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !public_key.Curve.IsOnCurve(public_key.X, public_key.Y) {
+			return nil, invalid("EC point not on curve")
+		}
+		return x509.MarshalPKIXPublicKey(public_key)
+
+	case COSE_ALG_RS256:
+		n, e := get_bytes(-1), get_bytes(-2)
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, invalid("unsupported RSA key")
+		}
+
+		return x509.MarshalPKIXPublicKey(&rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		})
+	}
+
+	return nil, invalid("unsupported algorithm %v", alg)
+}
+
+// Verify a registration (navigator.credentials.create) response and
+// return the new credential.
+func VerifyRegistration(
+	client_data_json, attestation_object []byte,
+	challenge, origin, rp_id string) (*Credential, error) {
+	err := verifyClientData(client_data_json, CEREMONY_CREATE,
+		challenge, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	item, _, err := decodeCBOR(attestation_object)
+	if err != nil {
+		return nil, invalid("attestation object: %v", err)
+	}
+
+	attestation, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, invalid("attestation object is not a map")
+	}
+
+	auth_data_bytes, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, invalid("attestation has no authData")
+	}
+
+	auth_data, err := parseAuthenticatorData(auth_data_bytes, rp_id)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth_data.PublicKey == nil {
+		return nil, invalid("no attested credential")
+	}
+
+	return &Credential{
+		Id:        auth_data.CredentialId,
+		PublicKey: auth_data.PublicKey,
+		SignCount: auth_data.SignCount,
+	}, nil
+}
+
+// Verify an assertion (navigator.credentials.get) response for the
+// credential. Returns the new signature counter.
+func VerifyAssertion(credential *Credential,
+	client_data_json, authenticator_data, signature []byte,
+	challenge, origin, rp_id string,
+	require_user_verification bool) (uint32, error) {
+	err := verifyClientData(client_data_json, CEREMONY_GET,
+		challenge, origin)
+	if err != nil {
+		return 0, err
+	}
+
+	auth_data, err := parseAuthenticatorData(authenticator_data, rp_id)
+	if err != nil {
+		return 0, err
+	}
+
+	if require_user_verification &&
+		auth_data.Flags&FLAG_USER_VERIFIED == 0 {
+		return 0, invalid("user not verified")
+	}
+
+	// Authenticators that do not implement counters always
+	// report 0. Otherwise the counter must increase or the
+	// credential may have been cloned.
+	if (auth_data.SignCount != 0 || credential.SignCount != 0) &&
+		auth_data.SignCount <= credential.SignCount {
+		return 0, invalid("signature counter did not increase")
+	}
+
+	client_data_hash := sha256.Sum256(client_data_json)
+	signed := append(append([]byte{}, authenticator_data...),
+		client_data_hash[:]...)
+	digest := sha256.Sum256(signed)
+
+	public_key, err := x509.ParsePKIXPublicKey(credential.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	switch key := public_key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return 0, invalid("bad signature")
+		}
+
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+		if err != nil {
+			return 0, invalid("bad signature")
+		}
+
+	default:
+		return 0, invalid("unsupported key type")
+	}
+
+	return auth_data.SignCount, nil
+}
@@ -0,0 +1,170 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testOrigin = "https://velociraptor.example.com"
+	testRPId   = "velociraptor.example.com"
+)
+
+// Just enough CBOR encoding to build test responses.
+func cborHeader(major byte, length int) []byte {
+	switch {
+	case length < 24:
+		return []byte{major<<5 | byte(length)}
+	case length < 256:
+		return []byte{major<<5 | 24, byte(length)}
+	}
+	return []byte{major<<5 | 25, byte(length >> 8), byte(length)}
+}
+
+func cborInt(value int64) []byte {
+	if value < 0 {
+		return cborHeader(1, int(-1-value))
+	}
+	return cborHeader(0, int(value))
+}
+
+func cborBytes(value []byte) []byte {
+	return append(cborHeader(2, len(value)), value...)
+}
+
+func cborText(value string) []byte {
+	return append(cborHeader(3, len(value)), value...)
+}
+
+func coseKey(key *ecdsa.PrivateKey) []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+
+	result := cborHeader(5, 5)
+	result = append(result, cborInt(1)...)
+	result = append(result, cborInt(2)...)
+	result = append(result, cborInt(3)...)
+	result = append(result, cborInt(COSE_ALG_ES256)...)
+	result = append(result, cborInt(-1)...)
+	result = append(result, cborInt(1)...)
+	result = append(result, cborInt(-2)...)
+	result = append(result, cborBytes(x)...)
+	result = append(result, cborInt(-3)...)
+	result = append(result, cborBytes(y)...)
+	return result
+}
+
+func authData(flags byte, count uint32, attested []byte) []byte {
+	hash := sha256.Sum256([]byte(testRPId))
+	result := append([]byte{}, hash[:]...)
+	result = append(result, flags)
+	counter := make([]byte, 4)
+	binary.BigEndian.PutUint32(counter, count)
+	result = append(result, counter...)
+	return append(result, attested...)
+}
+
+func clientDataJSON(ceremony, challenge string) []byte {
+	data, _ := json.Marshal(&clientData{
+		Type:      ceremony,
+		Challenge: challenge,
+		Origin:    testOrigin,
+	})
+	return data
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	credential_id := []byte("credential-1")
+	attested := make([]byte, 18)
+	binary.BigEndian.PutUint16(attested[16:], uint16(len(credential_id)))
+	attested = append(attested, credential_id...)
+	attested = append(attested, coseKey(key)...)
+
+	attestation := cborHeader(5, 2)
+	attestation = append(attestation, cborText("fmt")...)
+	attestation = append(attestation, cborText("none")...)
+	attestation = append(attestation, cborText("authData")...)
+	attestation = append(attestation, cborBytes(authData(
+		FLAG_USER_PRESENT|FLAG_ATTESTED_DATA, 0, attested))...)
+
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	credential, err := VerifyRegistration(
+		clientDataJSON(CEREMONY_CREATE, challenge), attestation,
+		challenge, testOrigin, testRPId)
+	require.NoError(t, err)
+	assert.Equal(t, credential_id, credential.Id)
+
+	// A different challenge is rejected.
+	_, err = VerifyRegistration(
+		clientDataJSON(CEREMONY_CREATE, "other"), attestation,
+		challenge, testOrigin, testRPId)
+	assert.True(t, errors.Is(err, InvalidResponseError))
+
+	// A different relying party is rejected.
+	_, err = VerifyRegistration(
+		clientDataJSON(CEREMONY_CREATE, challenge), attestation,
+		challenge, testOrigin, "evil.example.com")
+	assert.True(t, errors.Is(err, InvalidResponseError))
+
+	sign := func(auth_data, client_data []byte) []byte {
+		hash := sha256.Sum256(client_data)
+		digest := sha256.Sum256(append(append([]byte{}, auth_data...), hash[:]...))
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		return signature
+	}
+
+	auth_data := authData(FLAG_USER_PRESENT|FLAG_USER_VERIFIED, 5, nil)
+	client_data := clientDataJSON(CEREMONY_GET, challenge)
+	signature := sign(auth_data, client_data)
+
+	count, err := VerifyAssertion(credential, client_data, auth_data,
+		signature, challenge, testOrigin, testRPId, true)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(5), count)
+
+	// Replaying the counter is rejected.
+	credential.SignCount = count
+	_, err = VerifyAssertion(credential, client_data, auth_data,
+		signature, challenge, testOrigin, testRPId, true)
+	assert.True(t, errors.Is(err, InvalidResponseError))
+
+	// A tampered signature is rejected.
+	credential.SignCount = 0
+	signature[len(signature)-1] ^= 1
+	_, err = VerifyAssertion(credential, client_data, auth_data,
+		signature, challenge, testOrigin, testRPId, true)
+	assert.True(t, errors.Is(err, InvalidResponseError))
+
+	// User verification is required for passkey logins.
+	auth_data = authData(FLAG_USER_PRESENT, 6, nil)
+	_, err = VerifyAssertion(credential, client_data, auth_data,
+		sign(auth_data, client_data), challenge, testOrigin, testRPId, true)
+	assert.True(t, errors.Is(err, InvalidResponseError))
+}
+
+func TestCBORLimits(t *testing.T) {
+	// Truncated byte string
+	_, _, err := decodeCBOR([]byte{0x58, 0x10, 0x01})
+	assert.Error(t, err)
+
+	// Array claiming more items than bytes
+	_, _, err = decodeCBOR([]byte{0x9a, 0xff, 0xff, 0xff, 0xff})
+	assert.Error(t, err)
+}
@@ -0,0 +1,342 @@
+// Chunked, incremental backups of the datastore and the filestore.
+
+// A backup is a directory containing:
+//
+//  chunk-00001.zip ... - Zip files holding the stored data. A chunk
+//                        is closed once it grows over the chunk size.
+//  index.jsonl         - One entry per backed up item, recording the
+//                        chunk the item is stored in and its hash.
+//  manifest.json       - Written when the backup is complete.
+//
+// Chunks are written to a temporary file and renamed when complete,
+// only then their entries are appended to the index. If a backup is
+// interrupted, running it again into the same directory skips all
+// the items already in the index.
+//
+// An incremental backup refers to a previous (base) backup. Items
+// which did not change since the base backup are not stored again,
+// instead the index entry refers to the chunk in the base backup.
+//
+// The datastore is flushed before the backup starts so any writes
+// delayed in the memcache are included. Items written while the
+// backup runs may or may not be included.
+
+package backup
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	BACKUP_VERSION = 1
+
+	TYPE_DATASTORE = "datastore"
+	TYPE_FILESTORE = "filestore"
+
+	MANIFEST_NAME = "manifest.json"
+	INDEX_NAME    = "index.jsonl"
+
+	DEFAULT_CHUNK_SIZE = 100 * 1024 * 1024
+)
+
+var (
+	noRawAccessError = errors.New("Datastore has no raw access")
+)
+
+// An item in the backup.
+type Entry struct {
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Mtime  int64  `json:"mtime,omitempty"`
+	Sha256 string `json:"sha256"`
+	Chunk  string `json:"chunk"`
+
+	// The directory of the backup containing the chunk, relative
+	// to this backup. Empty when the chunk is in this backup.
+	Backup string `json:"backup,omitempty"`
+}
+
+func (self *Entry) key() string {
+	return self.Type + ":" + self.Path
+}
+
+func (self *Entry) memberName() string {
+	return self.Type + "/" + strings.TrimPrefix(self.Path, "/")
+}
+
+type Manifest struct {
+	Version   int       `json:"version"`
+	Started   time.Time `json:"started"`
+	Completed time.Time `json:"completed"`
+
+	// The base backup for incremental backups.
+	Base     string   `json:"base,omitempty"`
+	Prefixes []string `json:"prefixes,omitempty"`
+	Chunks   int      `json:"chunks"`
+
+	DatastoreItems int `json:"datastore_items"`
+	FilestoreItems int `json:"filestore_items"`
+
+	// Items which were unchanged since the base backup.
+	ReusedItems int `json:"reused_items"`
+}
+
+type Options struct {
+	// Write the backup into this directory.
+	Directory string
+
+	// A previous complete backup to make an incremental backup.
+	Base string
+
+	// Filestore prefixes to back up (e.g. /clients). The
+	// datastore is always backed up.
+	Prefixes []string
+
+	// Start a new chunk when the current one is larger than this.
+	ChunkSize int64
+}
+
+// Datastores that delay writes (e.g. the memcache datastore).
+type datastoreFlusher interface {
+	Flush(ctx context.Context) error
+}
+
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, MANIFEST_NAME))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%v is not a complete backup", dir)
+		}
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	err = json.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Reading manifest in %v: %w", dir, err)
+	}
+
+	if manifest.Version != BACKUP_VERSION {
+		return nil, fmt.Errorf("Backup %v has unsupported version %v",
+			dir, manifest.Version)
+	}
+
+	return manifest, nil
+}
+
+// Read the index in the backup directory. A partially written last
+// line (e.g. after a crash) is ignored.
+func ReadIndex(dir string) ([]*Entry, error) {
+	fd, err := os.Open(filepath.Join(dir, INDEX_NAME))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer fd.Close()
+
+	var result []*Entry
+	reader := bufio.NewReader(fd)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &Entry{}
+		err = json.Unmarshal(line, entry)
+		if err != nil {
+			return nil, fmt.Errorf("Corrupted index in %v: %w", dir, err)
+		}
+		result = append(result, entry)
+	}
+}
+
+func hashBytes(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func datastorePath(urn api.DSPathSpec) string {
+	return urn.AsClientPath()
+}
+
+func datastorePathSpec(path string) (api.DSPathSpec, error) {
+	path_type, _ := api.GetDataStorePathTypeFromExtension(path)
+	if path_type == api.PATH_TYPE_DATASTORE_UNKNOWN {
+		return nil, errors.New("Unknown datastore type for " + path)
+	}
+
+	components := utils.SplitComponents(path)
+	if len(components) == 0 {
+		return nil, errors.New("Invalid datastore path " + path)
+	}
+	return path_specs.DSFromGenericComponentList(components), nil
+}
+
+func filestorePath(urn api.FSPathSpec) string {
+	return utils.JoinComponents(
+		path_specs.AsGenericComponentList(urn), "/")
+}
+
+func filestorePathSpec(path string) (api.FSPathSpec, error) {
+	components := utils.SplitComponents(path)
+	if len(components) == 0 {
+		return nil, errors.New("Invalid filestore path " + path)
+	}
+	return path_specs.FromGenericComponentList(components), nil
+}
+
+// Datastore files are backed up from the datastore - skip them if
+// the filestore shares the same directory.
+func isDatastoreFile(urn api.FSPathSpec) bool {
+	switch urn.Type() {
+	case api.PATH_TYPE_FILESTORE_DB, api.PATH_TYPE_FILESTORE_DB_JSON:
+		return true
+	}
+	return false
+}
+
+// Flush pending writes so the backup includes them.
+func flush(ctx context.Context,
+	db datastore.DataStore, file_store api.FileStore) error {
+	flusher, ok := db.(datastoreFlusher)
+	if ok {
+		err := flusher.Flush(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	fs_flusher, ok := file_store.(api.Flusher)
+	if ok {
+		fs_flusher.Flush()
+	}
+	return nil
+}
+
+// Create or resume the backup described by options.
+func Create(ctx context.Context,
+	config_obj *config_proto.Config,
+	db datastore.DataStore, file_store api.FileStore,
+	options Options) (*Manifest, error) {
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, noRawAccessError
+	}
+
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = DEFAULT_CHUNK_SIZE
+	}
+
+	writer, err := newBackupWriter(options)
+	if err != nil {
+		return nil, err
+	}
+	defer writer.Close()
+
+	err = flush(ctx, db, file_store)
+	if err != nil {
+		return nil, fmt.Errorf("Flushing datastore: %w", err)
+	}
+
+	var walk_err error
+	err = datastore.Walk(config_obj, db, path_specs.NewSafeDatastorePath(),
+		false, func(urn api.DSPathSpec) error {
+			select {
+			case <-ctx.Done():
+				walk_err = ctx.Err()
+				return datastore.StopIteration
+			default:
+			}
+
+			entry := &Entry{
+				Type: TYPE_DATASTORE,
+				Path: datastorePath(urn),
+			}
+			if writer.isDone(entry) {
+				return nil
+			}
+
+			data, err := raw_db.GetBuffer(config_obj, urn)
+			if err != nil {
+				// The subject may have been removed while we
+				// walked - just skip it.
+				return nil
+			}
+
+			entry.Size = int64(len(data))
+			entry.Sha256 = hashBytes(data)
+
+			walk_err = writer.addData(entry, data)
+			if walk_err != nil {
+				return datastore.StopIteration
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if walk_err != nil {
+		return nil, walk_err
+	}
+
+	for _, prefix := range options.Prefixes {
+		root := path_specs.NewUnsafeFilestorePath(
+			utils.SplitComponents(prefix)...).
+			SetType(api.PATH_TYPE_FILESTORE_ANY)
+
+		err = api.Walk(file_store, root,
+			func(urn api.FSPathSpec, info os.FileInfo) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+
+				if isDatastoreFile(urn) {
+					return nil
+				}
+
+				entry := &Entry{
+					Type:  TYPE_FILESTORE,
+					Path:  filestorePath(urn),
+					Size:  info.Size(),
+					Mtime: info.ModTime().Unix(),
+				}
+				if writer.isDone(entry) {
+					return nil
+				}
+
+				return writer.addFile(file_store, urn, entry)
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return writer.Finish()
+}
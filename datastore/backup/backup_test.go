@@ -0,0 +1,184 @@
+package backup
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+func writeFile(t *testing.T, file_store api.FileStore,
+	urn api.FSPathSpec, data string) {
+	fd, err := file_store.WriteFile(urn)
+	require.NoError(t, err)
+	defer fd.Close()
+
+	require.NoError(t, fd.Truncate())
+	_, err = fd.Write([]byte(data))
+	require.NoError(t, err)
+}
+
+func readFile(t *testing.T, file_store api.FileStore, urn api.FSPathSpec) string {
+	fd, err := file_store.ReadFile(urn)
+	require.NoError(t, err)
+	defer fd.Close()
+
+	data, err := ioutil.ReadAll(fd)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func setClient(t *testing.T, config_obj *config_proto.Config,
+	db datastore.DataStore, urn api.DSPathSpec, hostname string) {
+	err := db.SetSubject(config_obj, urn, &api_proto.ClientMetadata{
+		ClientId: hostname,
+	})
+	require.NoError(t, err)
+}
+
+func TestBackupRestore(t *testing.T) {
+	dirname, err := ioutil.TempDir("", "backup_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirname)
+
+	store_dir := filepath.Join(dirname, "store")
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.FilestoreDirectory = store_dir
+	config_obj.Datastore.Location = store_dir
+
+	db := &datastore.FileBaseDataStore{}
+	file_store := directory.NewDirectoryFileStore(config_obj)
+	ctx := context.Background()
+
+	client_urn := path_specs.NewUnsafeDatastorePath("clients", "C.123")
+	other_urn := path_specs.NewUnsafeDatastorePath("clients", "C.456")
+	upload_urn := path_specs.NewUnsafeFilestorePath(
+		"clients", "C.123", "uploads", "file.txt").
+		SetType(api.PATH_TYPE_FILESTORE_ANY)
+
+	setClient(t, config_obj, db, client_urn, "Host1")
+	setClient(t, config_obj, db, other_urn, "Host2")
+	writeFile(t, file_store, upload_urn, "Hello")
+
+	// Use a tiny chunk size so every item is in its own chunk.
+	full_dir := filepath.Join(dirname, "full")
+	manifest, err := Create(ctx, config_obj, db, file_store, Options{
+		Directory: full_dir,
+		Prefixes:  []string{"/clients"},
+		ChunkSize: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, manifest.DatastoreItems)
+	assert.Equal(t, 1, manifest.FilestoreItems)
+	assert.Equal(t, 3, manifest.Chunks)
+
+	_, err = Verify(ctx, full_dir)
+	require.NoError(t, err)
+
+	// A complete backup can not be written again.
+	_, err = Create(ctx, config_obj, db, file_store, Options{
+		Directory: full_dir,
+	})
+	assert.Error(t, err)
+
+	// Only the modified client is stored in the incremental backup.
+	setClient(t, config_obj, db, other_urn, "Host3")
+
+	incremental_dir := filepath.Join(dirname, "incremental")
+	manifest, err = Create(ctx, config_obj, db, file_store, Options{
+		Directory: incremental_dir,
+		Base:      full_dir,
+		Prefixes:  []string{"/clients"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, manifest.ReusedItems)
+	assert.Equal(t, 1, manifest.Chunks)
+
+	// Restore into an empty store.
+	require.NoError(t, os.RemoveAll(store_dir))
+
+	_, err = Restore(ctx, config_obj, db, file_store, incremental_dir)
+	require.NoError(t, err)
+
+	record := &api_proto.ClientMetadata{}
+	require.NoError(t, db.GetSubject(config_obj, client_urn, record))
+	assert.Equal(t, "Host1", record.ClientId)
+
+	require.NoError(t, db.GetSubject(config_obj, other_urn, record))
+	assert.Equal(t, "Host3", record.ClientId)
+
+	assert.Equal(t, "Hello", readFile(t, file_store, upload_urn))
+
+	// Corrupting the chunks the incremental backup relies on is
+	// detected.
+	chunks, err := filepath.Glob(filepath.Join(full_dir, "chunk-*.zip"))
+	require.NoError(t, err)
+	for _, chunk := range chunks {
+		require.NoError(t, ioutil.WriteFile(chunk, []byte("X"), 0600))
+	}
+	_, err = Verify(ctx, incremental_dir)
+	assert.Error(t, err)
+}
+
+func TestBackupResume(t *testing.T) {
+	dirname, err := ioutil.TempDir("", "backup_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirname)
+
+	store_dir := filepath.Join(dirname, "store")
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.FilestoreDirectory = store_dir
+	config_obj.Datastore.Location = store_dir
+
+	db := &datastore.FileBaseDataStore{}
+	file_store := directory.NewDirectoryFileStore(config_obj)
+
+	setClient(t, config_obj, db,
+		path_specs.NewUnsafeDatastorePath("clients", "C.123"), "Host1")
+	setClient(t, config_obj, db,
+		path_specs.NewUnsafeDatastorePath("clients", "C.456"), "Host2")
+
+	// Cancel the backup after the first chunk is written.
+	backup_dir := filepath.Join(dirname, "backup")
+	ctx, cancel := context.WithCancel(context.Background())
+	writer, err := newBackupWriter(Options{
+		Directory: backup_dir,
+		ChunkSize: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, writer.addData(&Entry{
+		Type:   TYPE_DATASTORE,
+		Path:   "/clients/C.123.json.db",
+		Size:   7,
+		Sha256: hashBytes([]byte("Partial")),
+	}, []byte("Partial")))
+	writer.Close()
+	cancel()
+
+	_, err = Create(ctx, config_obj, db, file_store, Options{
+		Directory: backup_dir,
+	})
+	assert.Error(t, err)
+
+	// Resuming skips the items already stored.
+	manifest, err := Create(context.Background(), config_obj, db, file_store,
+		Options{Directory: backup_dir, ChunkSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, manifest.DatastoreItems)
+	assert.Equal(t, 2, manifest.Chunks)
+
+	entries, err := ReadIndex(backup_dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(entries))
+}
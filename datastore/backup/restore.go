@@ -0,0 +1,198 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Reads entries from the chunks of a backup and the backups it is
+// based on.
+type backupReader struct {
+	dir    string
+	chunks map[string]*zip.ReadCloser
+}
+
+func newBackupReader(dir string) *backupReader {
+	return &backupReader{
+		dir:    filepath.Clean(dir),
+		chunks: make(map[string]*zip.ReadCloser),
+	}
+}
+
+func (self *backupReader) Close() {
+	for _, chunk := range self.chunks {
+		chunk.Close()
+	}
+}
+
+func (self *backupReader) open(entry *Entry) (io.ReadCloser, error) {
+	path := filepath.Join(self.dir, filepath.FromSlash(entry.Backup),
+		entry.Chunk)
+
+	chunk, pres := self.chunks[path]
+	if !pres {
+		var err error
+		chunk, err = zip.OpenReader(path)
+		if err != nil {
+			return nil, fmt.Errorf("Opening chunk for %v: %w", entry.Path, err)
+		}
+		self.chunks[path] = chunk
+	}
+
+	return chunk.Open(entry.memberName())
+}
+
+// Copy the entry to the writer checking its hash.
+func (self *backupReader) copy(entry *Entry, out io.Writer) error {
+	fd, err := self.open(entry)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, hash), fd)
+	if err != nil {
+		return fmt.Errorf("Reading %v: %w", entry.Path, err)
+	}
+
+	if n != entry.Size || hex.EncodeToString(hash.Sum(nil)) != entry.Sha256 {
+		return fmt.Errorf("Backup of %v is corrupted", entry.Path)
+	}
+	return nil
+}
+
+// Check that every entry in a complete backup is readable and
+// matches its hash.
+func Verify(ctx context.Context, dir string) (*Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ReadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := newBackupReader(dir)
+	defer reader.Close()
+
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		err := reader.copy(entry, io.Discard)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// Load all the entries in a complete backup into the datastore and
+// filestore. Restoring is idempotent so an interrupted restore can
+// simply be started again.
+func Restore(ctx context.Context,
+	config_obj *config_proto.Config,
+	db datastore.DataStore, file_store api.FileStore,
+	dir string) (*Manifest, error) {
+
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return nil, noRawAccessError
+	}
+
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ReadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := newBackupReader(dir)
+	defer reader.Close()
+
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		switch entry.Type {
+		case TYPE_DATASTORE:
+			err = restoreDatastoreEntry(config_obj, raw_db, reader, entry)
+
+		case TYPE_FILESTORE:
+			err = restoreFilestoreEntry(file_store, reader, entry)
+
+		default:
+			err = fmt.Errorf("Unknown backup entry type %v", entry.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+func restoreDatastoreEntry(
+	config_obj *config_proto.Config,
+	raw_db datastore.RawDataStore,
+	reader *backupReader, entry *Entry) error {
+	urn, err := datastorePathSpec(entry.Path)
+	if err != nil {
+		return err
+	}
+
+	// Datastore items are small so verify them before writing.
+	buffer := &bytes.Buffer{}
+	err = reader.copy(entry, buffer)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the data hits the disk before we return.
+	return raw_db.SetBuffer(config_obj, urn, buffer.Bytes(), utils.SyncCompleter)
+}
+
+func restoreFilestoreEntry(file_store api.FileStore,
+	reader *backupReader, entry *Entry) error {
+	urn, err := filestorePathSpec(entry.Path)
+	if err != nil {
+		return err
+	}
+
+	fd, err := file_store.WriteFile(urn)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return err
+	}
+
+	return reader.copy(entry, fd)
+}
@@ -0,0 +1,409 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+type backupWriter struct {
+	dir        string
+	chunk_size int64
+	manifest   *Manifest
+
+	// Items already in the index (from an interrupted run).
+	done map[string]bool
+
+	// Items in the base backup with their Backup field relative
+	// to this backup.
+	base map[string]*Entry
+
+	index_fd   *os.File
+	next_chunk int
+
+	// The chunk currently being written.
+	chunk_fd           *os.File
+	chunk_zip          *zip.Writer
+	chunk_name         string
+	chunk_size_written int64
+
+	// Entries waiting for the current chunk to be committed.
+	pending []*Entry
+}
+
+func newBackupWriter(options Options) (*backupWriter, error) {
+	dir := filepath.Clean(options.Directory)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = os.Stat(filepath.Join(dir, MANIFEST_NAME))
+	if err == nil {
+		return nil, fmt.Errorf("Backup in %v is already complete", dir)
+	}
+
+	// Remove chunks which were not complete when we were
+	// interrupted.
+	tmp_files, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	for _, tmp_file := range tmp_files {
+		os.Remove(tmp_file)
+	}
+
+	self := &backupWriter{
+		dir:        dir,
+		chunk_size: options.ChunkSize,
+		done:       make(map[string]bool),
+		base:       make(map[string]*Entry),
+		next_chunk: 1,
+		manifest: &Manifest{
+			Version:  BACKUP_VERSION,
+			Started:  time.Now().UTC(),
+			Prefixes: options.Prefixes,
+		},
+	}
+
+	err = truncatePartialLine(filepath.Join(dir, INDEX_NAME))
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := ReadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range existing {
+		self.done[entry.key()] = true
+		self.count(entry)
+
+		var chunk_id int
+		if entry.Backup == "" &&
+			scanChunkName(entry.Chunk, &chunk_id) &&
+			chunk_id >= self.next_chunk {
+			self.next_chunk = chunk_id + 1
+		}
+	}
+
+	if options.Base != "" {
+		err = self.loadBase(options.Base)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	self.index_fd, err = os.OpenFile(filepath.Join(dir, INDEX_NAME),
+		os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return self, nil
+}
+
+// Remove a partially written last line so new entries may be
+// appended to the index.
+func truncatePartialLine(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	size := bytes.LastIndexByte(data, '\n') + 1
+	if size == len(data) {
+		return nil
+	}
+	return os.Truncate(path, int64(size))
+}
+
+func chunkName(id int) string {
+	return fmt.Sprintf("chunk-%05d.zip", id)
+}
+
+func scanChunkName(name string, id *int) bool {
+	n, err := fmt.Sscanf(name, "chunk-%05d.zip", id)
+	return err == nil && n == 1
+}
+
+func (self *backupWriter) loadBase(base string) error {
+	base = filepath.Clean(base)
+	if base == self.dir {
+		return fmt.Errorf("Base backup can not be the same as the backup")
+	}
+
+	_, err := ReadManifest(base)
+	if err != nil {
+		return err
+	}
+
+	entries, err := ReadIndex(base)
+	if err != nil {
+		return err
+	}
+
+	base_abs, err := filepath.Abs(base)
+	if err != nil {
+		return err
+	}
+
+	dir_abs, err := filepath.Abs(self.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		// Entries in the base may themselves refer to older
+		// backups.
+		rel, err := filepath.Rel(dir_abs,
+			filepath.Join(base_abs, entry.Backup))
+		if err != nil {
+			return err
+		}
+		entry.Backup = filepath.ToSlash(rel)
+		self.base[entry.key()] = entry
+	}
+
+	self.manifest.Base = base
+	return nil
+}
+
+func (self *backupWriter) isDone(entry *Entry) bool {
+	return self.done[entry.key()]
+}
+
+func (self *backupWriter) count(entry *Entry) {
+	switch entry.Type {
+	case TYPE_DATASTORE:
+		self.manifest.DatastoreItems++
+	case TYPE_FILESTORE:
+		self.manifest.FilestoreItems++
+	}
+
+	if entry.Backup != "" {
+		self.manifest.ReusedItems++
+	}
+}
+
+// Refer to the base backup if the entry did not change.
+func (self *backupWriter) reuseBase(entry *Entry) bool {
+	base_entry, pres := self.base[entry.key()]
+	if !pres || base_entry.Size != entry.Size {
+		return false
+	}
+
+	// Datastore items are compared by hash, filestore items by
+	// modification time since hashing them requires reading them.
+	switch entry.Type {
+	case TYPE_DATASTORE:
+		if base_entry.Sha256 != entry.Sha256 {
+			return false
+		}
+	case TYPE_FILESTORE:
+		if base_entry.Mtime != entry.Mtime {
+			return false
+		}
+		entry.Sha256 = base_entry.Sha256
+	}
+
+	entry.Chunk = base_entry.Chunk
+	entry.Backup = base_entry.Backup
+	self.pending = append(self.pending, entry)
+	return true
+}
+
+func (self *backupWriter) openChunk() error {
+	if self.chunk_zip != nil {
+		return nil
+	}
+
+	self.chunk_name = chunkName(self.next_chunk)
+	self.next_chunk++
+
+	fd, err := os.OpenFile(filepath.Join(self.dir, self.chunk_name+".tmp"),
+		os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	self.chunk_fd = fd
+	self.chunk_zip = zip.NewWriter(fd)
+	self.chunk_size_written = 0
+	return nil
+}
+
+func (self *backupWriter) createMember(entry *Entry) (io.Writer, error) {
+	err := self.openChunk()
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Chunk = self.chunk_name
+	return self.chunk_zip.CreateHeader(&zip.FileHeader{
+		Name:     entry.memberName(),
+		Method:   zip.Deflate,
+		Modified: time.Unix(entry.Mtime, 0),
+	})
+}
+
+func (self *backupWriter) addData(entry *Entry, data []byte) error {
+	if self.reuseBase(entry) {
+		return nil
+	}
+
+	writer, err := self.createMember(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(data)
+	if err != nil {
+		return err
+	}
+
+	return self.added(entry)
+}
+
+func (self *backupWriter) addFile(file_store api.FileStore,
+	urn api.FSPathSpec, entry *Entry) error {
+	if self.reuseBase(entry) {
+		return nil
+	}
+
+	reader, err := file_store.ReadFile(urn)
+	if err != nil {
+		// The file may have been removed while we walked.
+		return nil
+	}
+	defer reader.Close()
+
+	writer, err := self.createMember(entry)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(writer, hash), reader)
+	if err != nil {
+		return fmt.Errorf("Reading %v: %w", entry.Path, err)
+	}
+
+	entry.Size = n
+	entry.Sha256 = hex.EncodeToString(hash.Sum(nil))
+
+	return self.added(entry)
+}
+
+func (self *backupWriter) added(entry *Entry) error {
+	self.pending = append(self.pending, entry)
+	self.chunk_size_written += entry.Size
+
+	if self.chunk_size_written >= self.chunk_size {
+		return self.commit()
+	}
+	return nil
+}
+
+// Close the current chunk and record its entries in the index.
+func (self *backupWriter) commit() error {
+	if self.chunk_zip != nil {
+		err := self.chunk_zip.Close()
+		if err != nil {
+			return err
+		}
+
+		err = self.chunk_fd.Sync()
+		if err != nil {
+			return err
+		}
+
+		err = self.chunk_fd.Close()
+		if err != nil {
+			return err
+		}
+
+		self.chunk_zip = nil
+		self.chunk_fd = nil
+
+		path := filepath.Join(self.dir, self.chunk_name)
+		err = os.Rename(path+".tmp", path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(self.pending) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(self.pending))
+	for _, entry := range self.pending {
+		serialized, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, string(serialized)+"\n")
+		self.done[entry.key()] = true
+		self.count(entry)
+	}
+	self.pending = nil
+
+	_, err := self.index_fd.Write([]byte(strings.Join(lines, "")))
+	if err != nil {
+		return err
+	}
+
+	return self.index_fd.Sync()
+}
+
+// Commit the last chunk and write the manifest.
+func (self *backupWriter) Finish() (*Manifest, error) {
+	err := self.commit()
+	if err != nil {
+		return nil, err
+	}
+
+	self.manifest.Chunks = self.next_chunk - 1
+	self.manifest.Completed = time.Now().UTC()
+	serialized, err := json.MarshalIndent(self.manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(self.dir, MANIFEST_NAME)
+	err = os.WriteFile(path+".tmp", serialized, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.manifest, os.Rename(path+".tmp", path)
+}
+
+// Abandon the current chunk. It will be written again when the
+// backup is resumed.
+func (self *backupWriter) Close() {
+	if self.chunk_fd != nil {
+		self.chunk_fd.Close()
+		os.Remove(self.chunk_fd.Name())
+		self.chunk_fd = nil
+		self.chunk_zip = nil
+	}
+
+	if self.index_fd != nil {
+		self.index_fd.Close()
+		self.index_fd = nil
+	}
+}
@@ -0,0 +1,178 @@
+// Compress large datastore records.
+
+// Some records (e.g. compiled artifact requests or hunt stats) grow
+// to hundreds of KB. When Datastore.compression_threshold is set,
+// records larger than the threshold are compressed before they are
+// written. Compressed records start with a magic header naming the
+// algorithm so uncompressed records written before compression was
+// enabled remain readable. Like the encryption header, the
+// compression header is neither valid JSON nor a valid protobuf.
+
+// Records are compressed before they are encrypted since encrypted
+// data does not compress.
+
+package datastore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+const (
+	COMPRESSION_GZIP byte = 1
+	COMPRESSION_ZSTD byte = 2
+)
+
+var (
+	compressedRecordMagic = []byte("VDSC")
+
+	invalidCompressedRecordError = errors.New(
+		"Invalid compressed datastore record")
+
+	zstd_mu      sync.Mutex
+	zstd_encoder *zstd.Encoder
+	zstd_decoder *zstd.Decoder
+)
+
+func getZstd() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstd_mu.Lock()
+	defer zstd_mu.Unlock()
+
+	if zstd_encoder == nil {
+		encoder, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		decoder, err := zstd.NewReader(nil,
+			zstd.WithDecoderMaxMemory(constants.MAX_MEMORY))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		zstd_encoder = encoder
+		zstd_decoder = decoder
+	}
+
+	return zstd_encoder, zstd_decoder, nil
+}
+
+func getCompressionAlgorithm(config_obj *config_proto.Config) (byte, error) {
+	switch config_obj.Datastore.CompressionAlgorithm {
+	case "", "gzip":
+		return COMPRESSION_GZIP, nil
+	case "zstd":
+		return COMPRESSION_ZSTD, nil
+	default:
+		return 0, fmt.Errorf("Unsupported datastore compression algorithm %v",
+			config_obj.Datastore.CompressionAlgorithm)
+	}
+}
+
+// Compress the record if it is larger than the configured
+// threshold, otherwise return it unchanged.
+func compressContent(config_obj *config_proto.Config, data []byte) ([]byte, error) {
+	if config_obj.Datastore == nil ||
+		config_obj.Datastore.CompressionThreshold == 0 ||
+		uint64(len(data)) < config_obj.Datastore.CompressionThreshold {
+		return data, nil
+	}
+
+	algorithm, err := getCompressionAlgorithm(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &bytes.Buffer{}
+	result.Write(compressedRecordMagic)
+	result.WriteByte(algorithm)
+
+	switch algorithm {
+	case COMPRESSION_GZIP:
+		writer := gzip.NewWriter(result)
+		_, err = writer.Write(data)
+		if err != nil {
+			return nil, err
+		}
+		err = writer.Close()
+		if err != nil {
+			return nil, err
+		}
+
+	case COMPRESSION_ZSTD:
+		encoder, _, err := getZstd()
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodeAll(data, result.Bytes()), nil
+	}
+
+	return result.Bytes(), nil
+}
+
+// Decompress the record if it is compressed. Plain records are
+// returned unchanged.
+func decompressContent(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, compressedRecordMagic) {
+		return data, nil
+	}
+
+	data = data[len(compressedRecordMagic):]
+	if len(data) == 0 {
+		return nil, invalidCompressedRecordError
+	}
+
+	switch data[0] {
+	case COMPRESSION_GZIP:
+		reader, err := gzip.NewReader(bytes.NewReader(data[1:]))
+		if err != nil {
+			return nil, invalidCompressedRecordError
+		}
+		defer reader.Close()
+
+		return ioutil.ReadAll(io.LimitReader(reader, constants.MAX_MEMORY))
+
+	case COMPRESSION_ZSTD:
+		_, decoder, err := getZstd()
+		if err != nil {
+			return nil, err
+		}
+		result, err := decoder.DecodeAll(data[1:], nil)
+		if err != nil {
+			return nil, invalidCompressedRecordError
+		}
+		return result, nil
+	}
+
+	return nil, invalidCompressedRecordError
+}
+
+// Prepare a record for writing to disk.
+func encodeContent(config_obj *config_proto.Config,
+	urn api.DSPathSpec, data []byte) ([]byte, error) {
+	data, err := compressContent(config_obj, data)
+	if err != nil {
+		return nil, err
+	}
+	return encryptContent(config_obj, urn, data)
+}
+
+// Recover the record as read from disk.
+func decodeContent(config_obj *config_proto.Config,
+	urn api.DSPathSpec, data []byte) ([]byte, error) {
+	data, err := decryptContent(config_obj, urn, data)
+	if err != nil {
+		return nil, err
+	}
+	return decompressContent(data)
+}
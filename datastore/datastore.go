@@ -150,7 +150,8 @@ func getImpl(config_obj *config_proto.Config, implementation string) (DataStore,
 		}
 		return memcache_imp, nil
 
-	case "MemcacheFileDataStore":
+	// The S3 filestore keeps the datastore on local disk.
+	case "MemcacheFileDataStore", "S3":
 		if memcache_file_imp == nil {
 			memcache_imp_ := NewMemcacheFileDataStore(config_obj)
 			memcache_file_imp = memcache_imp_
@@ -118,6 +118,84 @@ func (self BaseTestSuite) TestSetSubjectWithCompletion() {
 	assert.Equal(self.T(), result[0], "Done")
 }
 
+// When the delete completion is called the subject must be gone.
+func (self BaseTestSuite) TestDeleteSubjectWithCompletion() {
+	message := &crypto_proto.VeloMessage{Source: "Server"}
+
+	urn := path_specs.NewSafeDatastorePath("a", "b", "deleted").
+		SetType(api.PATH_TYPE_DATASTORE_PROTO)
+	err := self.datastore.SetSubject(self.config_obj, urn, message)
+	assert.NoError(self.T(), err)
+
+	var mu sync.Mutex
+	var read_err error
+	done := false
+
+	err = self.datastore.DeleteSubjectWithCompletion(
+		self.config_obj, urn, func() {
+			read_message := &crypto_proto.VeloMessage{}
+			err := self.datastore.GetSubject(
+				self.config_obj, urn, read_message)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			read_err = err
+			done = true
+		})
+	assert.NoError(self.T(), err)
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return done
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.True(self.T(), errors.Is(read_err, os.ErrNotExist))
+}
+
+// All operations in a transaction are visible once it is committed.
+func (self BaseTestSuite) TestTransaction() {
+	urn := path_specs.NewSafeDatastorePath("txn").
+		SetType(api.PATH_TYPE_DATASTORE_PROTO)
+
+	err := self.datastore.SetSubject(self.config_obj, urn.AddChild("old"),
+		&crypto_proto.VeloMessage{Source: "Old"})
+	assert.NoError(self.T(), err)
+
+	txn := NewTxn(self.config_obj, self.datastore)
+	assert.NoError(self.T(), txn.Set(urn.AddChild("a"),
+		&crypto_proto.VeloMessage{Source: "A"}))
+	assert.NoError(self.T(), txn.Set(urn.AddChild("b").
+		SetType(api.PATH_TYPE_DATASTORE_JSON),
+		&crypto_proto.VeloMessage{Source: "B"}))
+	assert.NoError(self.T(), txn.Delete(urn.AddChild("old")))
+	assert.NoError(self.T(), txn.Commit())
+
+	// A transaction can only be committed once.
+	assert.Error(self.T(), txn.Commit())
+	assert.Error(self.T(), txn.Delete(urn.AddChild("a")))
+
+	read_message := &crypto_proto.VeloMessage{}
+	err = self.datastore.GetSubject(self.config_obj,
+		urn.AddChild("a"), read_message)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "A", read_message.Source)
+
+	err = self.datastore.GetSubject(self.config_obj,
+		urn.AddChild("b").SetType(api.PATH_TYPE_DATASTORE_JSON),
+		read_message)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "B", read_message.Source)
+
+	err = self.datastore.GetSubject(self.config_obj,
+		urn.AddChild("old"), read_message)
+	assert.True(self.T(), errors.Is(err, os.ErrNotExist))
+}
+
 func (self BaseTestSuite) TestSetGetSubject() {
 	message := &crypto_proto.VeloMessage{Source: "Server"}
 
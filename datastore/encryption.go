@@ -0,0 +1,157 @@
+// Encrypt datastore records at rest.
+
+// When Datastore.encryption_secret is set, records are encrypted
+// with AES-GCM before they are written to disk (including the WAL
+// and transaction intent files). Encrypted records start with a
+// magic header which can not be confused with a plain record since
+// it is neither valid JSON nor a valid protobuf. Plain records are
+// refused once encryption is enabled unless
+// Datastore.allow_unencrypted_records is set to migrate an existing
+// datastore.
+
+// The record is bound to the subject it was written for (the org and
+// the path within the datastore) so an encrypted record can not be
+// copied over another subject. The path does not include the
+// datastore location so records remain valid when moved between
+// shards.
+
+package datastore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"sync"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+var (
+	encryptedRecordMagic = []byte("VDSE\x02")
+
+	encryptionNotConfiguredError = errors.New(
+		"Datastore record is encrypted but no encryption_secret is configured")
+	invalidEncryptedRecordError = errors.New("Invalid encrypted datastore record")
+	unencryptedRecordError      = errors.New(
+		"Datastore record is not encrypted (set allow_unencrypted_records to migrate)")
+
+	aead_mu    sync.Mutex
+	aead_cache = make(map[string]cipher.AEAD)
+)
+
+// Derive the record key from the configured secret.
+func getDatastoreAEAD(secret string) (cipher.AEAD, error) {
+	aead_mu.Lock()
+	defer aead_mu.Unlock()
+
+	aead, pres := aead_cache[secret]
+	if pres {
+		return aead, nil
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("Velociraptor datastore encryption"))
+	key := mac.Sum(nil)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	aead_cache[secret] = aead
+	return aead, nil
+}
+
+// The additional authenticated data for a record.
+func getRecordAAD(config_obj *config_proto.Config, urn api.DSPathSpec) []byte {
+	result := make([]byte, 0, 64)
+	result = append(result, encryptedRecordMagic...)
+	result = append(result, config_obj.OrgId...)
+	result = append(result, 0)
+	result = append(result, urn.AsClientPath()...)
+	return result
+}
+
+func getEncryptionSecret(config_obj *config_proto.Config) string {
+	if config_obj.Datastore == nil {
+		return ""
+	}
+	return config_obj.Datastore.EncryptionSecret
+}
+
+// Encrypt the record if encryption is enabled, otherwise return it
+// unchanged.
+func encryptContent(config_obj *config_proto.Config,
+	urn api.DSPathSpec, data []byte) ([]byte, error) {
+	secret := getEncryptionSecret(config_obj)
+	if secret == "" || len(data) == 0 {
+		return data, nil
+	}
+
+	aead, err := getDatastoreAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, len(encryptedRecordMagic)+len(nonce)+
+		len(data)+aead.Overhead())
+	result = append(result, encryptedRecordMagic...)
+	result = append(result, nonce...)
+
+	// The header and the subject are authenticated with the record.
+	return aead.Seal(result, nonce, data, getRecordAAD(config_obj, urn)), nil
+}
+
+// Decrypt the record if it is encrypted. Plain records are returned
+// unchanged if encryption is not enabled or the datastore is being
+// migrated.
+func decryptContent(config_obj *config_proto.Config,
+	urn api.DSPathSpec, data []byte) ([]byte, error) {
+	secret := getEncryptionSecret(config_obj)
+	if !bytes.HasPrefix(data, encryptedRecordMagic) {
+		// Empty records are never encrypted.
+		if secret == "" || len(data) == 0 ||
+			config_obj.Datastore.AllowUnencryptedRecords {
+			return data, nil
+		}
+		return nil, unencryptedRecordError
+	}
+
+	if secret == "" {
+		return nil, encryptionNotConfiguredError
+	}
+
+	aead, err := getDatastoreAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[len(encryptedRecordMagic):]
+	if len(data) < aead.NonceSize() {
+		return nil, invalidEncryptedRecordError
+	}
+
+	nonce := data[:aead.NonceSize()]
+	plain_text, err := aead.Open(nil, nonce, data[aead.NonceSize():],
+		getRecordAAD(config_obj, urn))
+	if err != nil {
+		return nil, invalidEncryptedRecordError
+	}
+	return plain_text, nil
+}
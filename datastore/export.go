@@ -0,0 +1,154 @@
+// Export and import the datastore to a single archive.
+
+// The archive is a zip file with one member per datastore
+// subject. Member names are the subject's client path (the escaped
+// path components followed by the datastore extension .db or
+// .json.db) so the type of the subject is preserved. The raw
+// serialized data is stored without interpretation, so the archive
+// can be imported into any datastore implementation supporting raw
+// access.
+
+package datastore
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	noRawAccessError = errors.New("Datastore has no raw access")
+)
+
+func exportMemberName(urn api.DSPathSpec) string {
+	return strings.TrimPrefix(urn.AsClientPath(), "/")
+}
+
+func importPathSpec(name string) (api.DSPathSpec, error) {
+	path_type, base := api.GetDataStorePathTypeFromExtension(name)
+	if path_type == api.PATH_TYPE_DATASTORE_UNKNOWN {
+		return nil, errors.New("Unknown datastore type for " + name)
+	}
+
+	components := utils.SplitComponents(base)
+	if len(components) == 0 {
+		return nil, errors.New("Invalid archive member " + name)
+	}
+
+	return path_specs.NewUnsafeDatastorePath(components...).
+		SetType(path_type), nil
+}
+
+// Write all subjects under root to a zip archive. Returns the number
+// of subjects exported.
+func Export(ctx context.Context,
+	config_obj *config_proto.Config, db DataStore,
+	root api.DSPathSpec, out io.Writer) (int, error) {
+	raw_db, ok := db.(RawDataStore)
+	if !ok {
+		return 0, noRawAccessError
+	}
+
+	zip_writer := zip.NewWriter(out)
+	defer zip_writer.Close()
+
+	count := 0
+	var walk_err error
+
+	err := Walk(config_obj, db, root, false,
+		func(urn api.DSPathSpec) error {
+			select {
+			case <-ctx.Done():
+				walk_err = ctx.Err()
+				return StopIteration
+			default:
+			}
+
+			data, err := raw_db.GetBuffer(config_obj, urn)
+			if err != nil {
+				// The subject may have been removed while we
+				// walked - just skip it.
+				return nil
+			}
+
+			writer, err := zip_writer.Create(exportMemberName(urn))
+			if err != nil {
+				walk_err = err
+				return StopIteration
+			}
+
+			_, err = writer.Write(data)
+			if err != nil {
+				walk_err = err
+				return StopIteration
+			}
+			count++
+			return nil
+		})
+	if err != nil {
+		return count, err
+	}
+
+	if walk_err != nil {
+		return count, walk_err
+	}
+
+	return count, zip_writer.Close()
+}
+
+// Load all subjects from an archive created by Export() into the
+// datastore. Returns the number of subjects imported.
+func Import(ctx context.Context,
+	config_obj *config_proto.Config, db DataStore,
+	in io.ReaderAt, size int64) (int, error) {
+	raw_db, ok := db.(RawDataStore)
+	if !ok {
+		return 0, noRawAccessError
+	}
+
+	zip_reader, err := zip.NewReader(in, size)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, member := range zip_reader.File {
+		select {
+		case <-ctx.Done():
+			return count, ctx.Err()
+		default:
+		}
+
+		urn, err := importPathSpec(member.Name)
+		if err != nil {
+			return count, err
+		}
+
+		fd, err := member.Open()
+		if err != nil {
+			return count, err
+		}
+
+		data, err := io.ReadAll(fd)
+		fd.Close()
+		if err != nil {
+			return count, err
+		}
+
+		// Make sure the data hits the disk before we return.
+		err = raw_db.SetBuffer(config_obj, urn, data, utils.SyncCompleter)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,65 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+func TestExportImport(t *testing.T) {
+	dirname, err := ioutil.TempDir("", "datastore_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirname)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.FilestoreDirectory = dirname
+	config_obj.Datastore.Location = dirname
+
+	db := &FileBaseDataStore{}
+	ctx := context.Background()
+
+	// Include some paths that need escaping.
+	urns := []api.DSPathSpec{
+		path_specs.NewUnsafeDatastorePath("clients", "C.123"),
+		path_specs.NewUnsafeDatastorePath("a", "b/c", "d\"e"),
+		path_specs.NewUnsafeDatastorePath("clients", "C.123", "x").
+			SetType(api.PATH_TYPE_DATASTORE_PROTO),
+	}
+
+	for _, urn := range urns {
+		err := db.SetSubject(config_obj, urn, &api_proto.ClientMetadata{
+			ClientId: urn.String(),
+		})
+		require.NoError(t, err)
+	}
+
+	buf := &bytes.Buffer{}
+	count, err := Export(ctx, config_obj, db, path_specs.NewSafeDatastorePath(), buf)
+	require.NoError(t, err)
+	assert.Equal(t, len(urns), count)
+
+	// Import into an empty datastore.
+	os.RemoveAll(dirname)
+
+	data := buf.Bytes()
+	count, err = Import(ctx, config_obj, db,
+		bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, len(urns), count)
+
+	for _, urn := range urns {
+		record := &api_proto.ClientMetadata{}
+		err := db.GetSubject(config_obj, urn, record)
+		require.NoError(t, err)
+		assert.Equal(t, urn.String(), record.ClientId)
+	}
+}
@@ -205,13 +205,24 @@ func listChildren(config_obj *config_proto.Config,
 
 	defer InstrumentWithDelay("list", "FileBaseDataStore", urn)()
 
-	children, err := utils.ReadDirUnsorted(
-		urn.AsDatastoreDirectory(config_obj))
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []os.FileInfo{}, nil
+	children := []os.FileInfo{}
+	seen := make(map[string]bool)
+	for _, directory := range getShardDirectories(config_obj, urn) {
+		shard_children, err := utils.ReadDirUnsorted(directory)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrap(err, 0)
+		}
+
+		// Directories like /clients exist in every shard.
+		for _, child := range shard_children {
+			if !seen[child.Name()] {
+				seen[child.Name()] = true
+				children = append(children, child)
+			}
 		}
-		return nil, errors.Wrap(err, 0)
 	}
 
 	max_dir_size := int(config_obj.Datastore.MaxDirSize)
@@ -302,6 +313,11 @@ func writeContentToFile(config_obj *config_proto.Config,
 
 	filename := urn.AsDatastoreFilename(config_obj)
 
+	data, err := encodeContent(config_obj, urn, data)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
 	// Truncate the file immediately so we dont need to make a seocnd
 	// syscall.
 	file, err := os.OpenFile(
@@ -351,6 +367,11 @@ func readContentFromFile(
 			return nil, errors.Wrap(err, 0)
 		}
 
+		result, err = decodeContent(config_obj, urn, result)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+
 		return result, nil
 	}
 
@@ -368,7 +389,15 @@ func FilenameToURN(config_obj *config_proto.Config,
 		filename = strings.TrimPrefix(filename, WINDOWS_LFN_PREFIX)
 	}
 
-	filename = strings.TrimPrefix(filename, config_obj.Datastore.Location)
+	// Strip the longest matching shard (shards may be nested).
+	prefix := ""
+	for _, location := range path_specs.GetDatastoreShards(config_obj) {
+		if len(location) > len(prefix) &&
+			strings.HasPrefix(filename, location) {
+			prefix = location
+		}
+	}
+	filename = strings.TrimPrefix(filename, prefix)
 
 	components := []string{}
 	// DS filenames are always clean so a strings split is fine.
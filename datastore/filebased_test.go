@@ -5,11 +5,17 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"www.velocidex.com/golang/velociraptor/config"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
 )
 
 type FilebasedTestSuite struct {
@@ -37,6 +43,167 @@ func (self FilebasedTestSuite) TestSetGetJSON() {
 	// self.DumpDirectory()
 }
 
+// Transactions interrupted by a crash are replayed on startup.
+func (self FilebasedTestSuite) TestRecoverTransactions() {
+	urn := path_specs.NewSafeDatastorePath("txn", "a").
+		SetType(api.PATH_TYPE_DATASTORE_PROTO)
+	message := &crypto_proto.VeloMessage{Source: "A"}
+	data, err := encodeSubject(urn, message)
+	assert.NoError(self.T(), err)
+
+	// Simulate a crash after the intent file was written.
+	_, err = writeIntentFile(self.config_obj, []*TxnOp{{
+		Op:  MUTATION_OP_SET_SUBJECT,
+		Urn: urn, Message: message, Data: data,
+	}})
+	assert.NoError(self.T(), err)
+
+	read_message := &crypto_proto.VeloMessage{}
+	err = self.datastore.GetSubject(self.config_obj, urn, read_message)
+	assert.Error(self.T(), err)
+
+	err = RecoverTransactions(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	err = self.datastore.GetSubject(self.config_obj, urn, read_message)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "A", read_message.Source)
+
+	// The intent file is removed once applied.
+	names, err := utils.ReadDirNames(txnDirectory(self.config_obj))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(names))
+}
+
+// Records are encrypted on disk when a secret is configured.
+func (self FilebasedTestSuite) TestEncryptionAtRest() {
+	urn := path_specs.NewSafeDatastorePath("encrypted", "a").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+	plain_urn := path_specs.NewSafeDatastorePath("encrypted", "b").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Written before encryption was enabled.
+	err := self.datastore.SetSubject(self.config_obj, plain_urn,
+		&crypto_proto.VeloMessage{Source: "Plain"})
+	assert.NoError(self.T(), err)
+
+	self.config_obj.Datastore.EncryptionSecret = "secret"
+	defer func() { self.config_obj.Datastore.EncryptionSecret = "" }()
+
+	err = self.datastore.SetSubject(self.config_obj, urn,
+		&crypto_proto.VeloMessage{Source: "Secret Source"})
+	assert.NoError(self.T(), err)
+
+	data, err := ioutil.ReadFile(urn.AsDatastoreFilename(self.config_obj))
+	assert.NoError(self.T(), err)
+	assert.NotContains(self.T(), string(data), "Secret Source")
+
+	message := &crypto_proto.VeloMessage{}
+	err = self.datastore.GetSubject(self.config_obj, urn, message)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Secret Source", message.Source)
+
+	// Records which are not encrypted are refused.
+	err = self.datastore.GetSubject(self.config_obj, plain_urn, message)
+	assert.Error(self.T(), err)
+
+	// Unless the datastore is being migrated.
+	self.config_obj.Datastore.AllowUnencryptedRecords = true
+	err = self.datastore.GetSubject(self.config_obj, plain_urn, message)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Plain", message.Source)
+	self.config_obj.Datastore.AllowUnencryptedRecords = false
+
+	// The record is bound to its subject so it can not be copied
+	// over another subject.
+	other_urn := path_specs.NewSafeDatastorePath("encrypted", "other").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+	err = os.MkdirAll(filepath.Dir(
+		other_urn.AsDatastoreFilename(self.config_obj)), 0700)
+	assert.NoError(self.T(), err)
+	err = ioutil.WriteFile(
+		other_urn.AsDatastoreFilename(self.config_obj), data, 0600)
+	assert.NoError(self.T(), err)
+
+	err = self.datastore.GetSubject(self.config_obj, other_urn, message)
+	assert.Error(self.T(), err)
+
+	// Without the secret the record can not be read.
+	self.config_obj.Datastore.EncryptionSecret = "wrong"
+	err = self.datastore.GetSubject(self.config_obj, urn, message)
+	assert.Error(self.T(), err)
+
+	self.config_obj.Datastore.EncryptionSecret = ""
+	err = self.datastore.GetSubject(self.config_obj, urn, message)
+	assert.Error(self.T(), err)
+}
+
+// Large records are compressed on disk.
+func (self FilebasedTestSuite) TestCompression() {
+	self.config_obj.Datastore.CompressionThreshold = 1000
+	defer func() {
+		self.config_obj.Datastore.CompressionThreshold = 0
+		self.config_obj.Datastore.CompressionAlgorithm = ""
+	}()
+
+	source := strings.Repeat("Large Record ", 1000)
+	for _, algorithm := range []string{"gzip", "zstd"} {
+		self.config_obj.Datastore.CompressionAlgorithm = algorithm
+
+		urn := path_specs.NewSafeDatastorePath("compressed", algorithm).
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+		err := self.datastore.SetSubject(self.config_obj, urn,
+			&crypto_proto.VeloMessage{Source: source})
+		assert.NoError(self.T(), err)
+
+		data, err := ioutil.ReadFile(urn.AsDatastoreFilename(self.config_obj))
+		assert.NoError(self.T(), err)
+		assert.True(self.T(), strings.HasPrefix(string(data), "VDSC"))
+		assert.True(self.T(), len(data) < len(source))
+
+		message := &crypto_proto.VeloMessage{}
+		err = self.datastore.GetSubject(self.config_obj, urn, message)
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), source, message.Source)
+	}
+
+	// Small records are not compressed.
+	urn := path_specs.NewSafeDatastorePath("compressed", "small").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+	err := self.datastore.SetSubject(self.config_obj, urn,
+		&crypto_proto.VeloMessage{Source: "Small"})
+	assert.NoError(self.T(), err)
+
+	data, err := ioutil.ReadFile(urn.AsDatastoreFilename(self.config_obj))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), byte('{'), data[0])
+}
+
+func (self FilebasedTestSuite) TestNamespaceMetrics() {
+	snapshot := vtesting.GetMetricsDifference(self.T(), ".", nil)
+
+	urn := path_specs.NewSafeDatastorePath("hunts", "H.1234").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+	err := self.datastore.SetSubject(self.config_obj, urn,
+		&crypto_proto.VeloMessage{Source: "Hunt"})
+	assert.NoError(self.T(), err)
+
+	message := &crypto_proto.VeloMessage{}
+	err = self.datastore.GetSubject(self.config_obj, urn, message)
+	assert.NoError(self.T(), err)
+
+	metrics := vtesting.GetMetricsDifference(
+		self.T(), "datastore_namespace_latency", snapshot)
+
+	// Labels are sorted by name: action, datastore, namespace
+	for _, action := range []string{"read", "write"} {
+		value, _ := metrics.GetInt64(
+			"datastore_namespace_latency__" + action +
+				"_FileBaseDataStore_hunts_inf")
+		assert.Equal(self.T(), int64(1), value, action)
+	}
+}
+
 func (self *FilebasedTestSuite) SetupTest() {
 	var err error
 	self.dirname, err = ioutil.TempDir("", "datastore_test")
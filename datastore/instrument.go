@@ -20,6 +20,18 @@ var (
 		[]string{"tag", "action", "datastore"},
 	)
 
+	// Operators can see which subsystem is accessing the datastore
+	// the most by the first component of the path (e.g. clients,
+	// hunts, notebooks).
+	DatastoreNamespaceHistorgram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "datastore_namespace_latency",
+			Help:    "Latency to access datastore by top level namespace.",
+			Buckets: prometheus.LinearBuckets(0.01, 0.05, 10),
+		},
+		[]string{"namespace", "action", "datastore"},
+	)
+
 	// Simulate running on a very slow filesystem (EFS)
 	inject_time = 0
 )
@@ -27,14 +39,8 @@ var (
 func Instrument(access_type, datastore string,
 	path_spec api.DSPathSpec) func() time.Duration {
 
-	tag := path_spec.Tag()
-	if tag == "" {
-		tag = "Generic"
-	}
-
-	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
-		DatastoreHistorgram.WithLabelValues(tag, access_type, datastore).Observe(v)
-	}))
+	timer := prometheus.NewTimer(
+		getObserver(access_type, datastore, path_spec))
 
 	return timer.ObserveDuration
 }
@@ -42,21 +48,41 @@ func Instrument(access_type, datastore string,
 func InstrumentWithDelay(
 	access_type, datastore string, path_spec api.DSPathSpec) func() time.Duration {
 
+	timer := prometheus.NewTimer(
+		getObserver(access_type, datastore, path_spec))
+
+	// Instrument a delay in API calls.
+	if inject_time > 0 {
+		time.Sleep(time.Duration(inject_time) * time.Millisecond)
+	}
+
+	return timer.ObserveDuration
+}
+
+func getObserver(access_type, datastore string,
+	path_spec api.DSPathSpec) prometheus.Observer {
 	tag := path_spec.Tag()
 	if tag == "" {
 		tag = "Generic"
 	}
 
-	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
+	namespace := getNamespace(path_spec)
+
+	return prometheus.ObserverFunc(func(v float64) {
 		DatastoreHistorgram.WithLabelValues(tag, access_type, datastore).Observe(v)
-	}))
+		DatastoreNamespaceHistorgram.WithLabelValues(
+			namespace, access_type, datastore).Observe(v)
+	})
+}
 
-	// Instrument a delay in API calls.
-	if inject_time > 0 {
-		time.Sleep(time.Duration(inject_time) * time.Millisecond)
+// The top level directory of the path identifies the subsystem
+// which owns it (e.g. clients, hunts, artifact_definitions).
+func getNamespace(path_spec api.DSPathSpec) string {
+	components := path_spec.Components()
+	if len(components) == 0 {
+		return "root"
 	}
-
-	return timer.ObserveDuration
+	return components[0]
 }
 
 func init() {
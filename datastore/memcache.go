@@ -208,6 +208,9 @@ type DirectoryLRUCache struct {
 
 	*ttlcache.Cache
 	max_item_size int
+
+	// Optional per path expiration times.
+	expiration *ExpirationPolicies
 }
 
 func (self *DirectoryLRUCache) Get(path string) (*DirectoryMetadata, bool) {
@@ -228,6 +231,11 @@ func (self *DirectoryLRUCache) Set(
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
+	ttl, ok := self.expiration.TTL(key_path)
+	if ok {
+		return self.Cache.SetWithTTL(key_path, value, ttl)
+	}
+
 	return self.Cache.Set(key_path, value)
 }
 
@@ -403,6 +411,17 @@ func (self *MemcacheDatastore) SetTimeout(duration time.Duration) {
 	self.dir_cache.SetTTL(duration)
 }
 
+func (self *MemcacheDatastore) SetExpirationPolicies(
+	policies *ExpirationPolicies) {
+	self.data_cache.mu.Lock()
+	self.data_cache.expiration = policies
+	self.data_cache.mu.Unlock()
+
+	self.dir_cache.mu.Lock()
+	self.dir_cache.expiration = policies
+	self.dir_cache.mu.Unlock()
+}
+
 func (self *MemcacheDatastore) SetCheckExpirationCallback(
 	callback ttlcache.CheckExpireCallback) {
 	self.data_cache.SetCheckExpirationCallback(callback)
@@ -15,6 +15,12 @@ type DataLRUCache struct {
 
 	// Max size of cached items
 	max_item_size int
+
+	// Optional per path expiration times.
+	expiration *ExpirationPolicies
+
+	// Per namespace accounting and limits.
+	quotas *NamespaceQuotas
 }
 
 // Size total cached items.
@@ -24,7 +30,7 @@ func (self *DataLRUCache) Size() int {
 
 	size := 0
 	for _, key := range self.GetKeys() {
-		bd_any, err := self.Get(key)
+		bd_any, err := self.Cache.Get(key)
 		if err == nil {
 			bd, ok := bd_any.(*BulkData)
 			if ok {
@@ -53,7 +59,38 @@ func (self *DataLRUCache) Set(key string, value *BulkData) error {
 		return nil
 	}
 
-	return self.Cache.Set(key, value)
+	var err error
+	ttl, ok := self.expiration.TTL(key)
+	if ok {
+		err = self.Cache.SetWithTTL(key, value, ttl)
+	} else {
+		err = self.Cache.Set(key, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Make room in the namespace if it is over quota.
+	for _, evicted := range self.quotas.Add(key, value) {
+		_ = self.Cache.Remove(evicted)
+	}
+
+	return nil
+}
+
+// Gets an item from the cache and marks it as recently used.
+func (self *DataLRUCache) Get(key string) (interface{}, error) {
+	value, err := self.Cache.Get(key)
+	if err == nil {
+		self.quotas.Touch(key)
+	}
+	return value, err
+}
+
+// Drop all the items from the cache.
+func (self *DataLRUCache) Purge() error {
+	self.quotas.Reset()
+	return self.Cache.Purge()
 }
 
 func NewDataLRUCache(config_obj *config_proto.Config,
@@ -62,9 +99,17 @@ func NewDataLRUCache(config_obj *config_proto.Config,
 	result := &DataLRUCache{
 		Cache:         ttlcache.NewCache(),
 		max_item_size: data_max_item_size,
+		quotas:        NewNamespaceQuotas(config_obj),
 	}
 
 	result.SetCacheSizeLimit(data_max_size)
 
+	// Keep the namespace accounting in sync when items are
+	// expired or evicted by the cache.
+	result.SetExpirationCallback(func(key string, value interface{}) {
+		bd, _ := value.(*BulkData)
+		result.quotas.Remove(key, bd)
+	})
+
 	return result
 }
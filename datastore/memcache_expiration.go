@@ -0,0 +1,122 @@
+package datastore
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+type expirationRule struct {
+	prefix string
+	regex  *regexp.Regexp
+	ttl    time.Duration
+	pinned bool
+}
+
+// Maps memcache keys to their expiration time. Keys are filesystem
+// paths so they are converted back to datastore paths (e.g
+// /hunts/H.123.db) before matching.
+type ExpirationPolicies struct {
+	locations []string
+	rules     []*expirationRule
+}
+
+// Memcache keys are filesystem paths - convert them back to
+// datastore paths. The cache is shared by all orgs and each org's
+// datastore lives in /orgs/<org_id> so policies apply to all orgs
+// alike. Client records may be in any of the datastore shards so
+// the longest matching location is stripped (shards may be nested).
+func normalizeCacheKey(locations []string, key string) string {
+	key = strings.TrimPrefix(key, WINDOWS_LFN_PREFIX)
+
+	prefix := ""
+	for _, location := range locations {
+		if len(location) > len(prefix) && strings.HasPrefix(key, location) {
+			prefix = location
+		}
+	}
+	key = strings.TrimPrefix(key, prefix)
+	if os.PathSeparator != '/' {
+		key = strings.ReplaceAll(key, string(os.PathSeparator), "/")
+	}
+
+	if strings.HasPrefix(key, "/orgs/") {
+		// ["", "orgs", org_id, rest]
+		parts := strings.SplitN(key, "/", 4)
+		if len(parts) == 4 {
+			key = "/" + parts[3]
+		}
+	}
+	return key
+}
+
+// Returns the rule that applies to the key or nil if no rule
+// applies.
+func (self *ExpirationPolicies) match(key string) *expirationRule {
+	if self == nil || len(self.rules) == 0 {
+		return nil
+	}
+
+	path := normalizeCacheKey(self.locations, key)
+	for _, rule := range self.rules {
+		if rule.prefix != "" && !strings.HasPrefix(path, rule.prefix) {
+			continue
+		}
+
+		if rule.regex != nil && !rule.regex.MatchString(path) {
+			continue
+		}
+
+		return rule
+	}
+	return nil
+}
+
+// Returns the TTL for the key if a policy applies to it.
+func (self *ExpirationPolicies) TTL(key string) (time.Duration, bool) {
+	rule := self.match(key)
+	if rule == nil || rule.ttl <= 0 {
+		return 0, false
+	}
+	return rule.ttl, true
+}
+
+// Returns true if the key should never expire.
+func (self *ExpirationPolicies) IsPinned(key string) bool {
+	rule := self.match(key)
+	return rule != nil && rule.pinned
+}
+
+func NewExpirationPolicies(
+	config_obj *config_proto.Config) (*ExpirationPolicies, error) {
+	result := &ExpirationPolicies{}
+	if config_obj.Datastore == nil {
+		return result, nil
+	}
+
+	result.locations = path_specs.GetDatastoreShards(config_obj)
+
+	for _, policy := range config_obj.Datastore.MemcacheExpirationPolicies {
+		rule := &expirationRule{
+			prefix: policy.Prefix,
+			ttl:    time.Duration(policy.TtlSec) * time.Second,
+			pinned: policy.TtlSec < 0,
+		}
+
+		if policy.Regex != "" {
+			regex, err := regexp.Compile(policy.Regex)
+			if err != nil {
+				return nil, err
+			}
+			rule.regex = regex
+		}
+
+		result.rules = append(result.rules, rule)
+	}
+
+	return result, nil
+}
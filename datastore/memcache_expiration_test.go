@@ -0,0 +1,74 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+func TestExpirationPolicies(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.Location = "/tmp/datastore"
+	config_obj.Datastore.MemcacheExpirationPolicies = []*config_proto.MemcacheExpirationPolicy{
+		{Prefix: "/hunts/", TtlSec: -1},
+		{Prefix: "/clients/", Regex: "/collections/F\\.[^/]+/", TtlSec: 10},
+		{Regex: "\\.json\\.db$", TtlSec: 3600},
+	}
+
+	policies, err := NewExpirationPolicies(config_obj)
+	require.NoError(t, err)
+
+	// Hunts are pinned.
+	hunt := path_specs.NewSafeDatastorePath("hunts", "H.123").
+		AsDatastoreFilename(config_obj)
+	assert.True(t, policies.IsPinned(hunt))
+	_, ok := policies.TTL(hunt)
+	assert.False(t, ok)
+
+	// Flow results expire quickly - the first matching policy wins.
+	flow := path_specs.NewSafeDatastorePath(
+		"clients", "C.123", "collections", "F.123", "stats").
+		AsDatastoreFilename(config_obj)
+	ttl, ok := policies.TTL(flow)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, ttl)
+	assert.False(t, policies.IsPinned(flow))
+
+	// Falls through to the regex policy.
+	client := path_specs.NewSafeDatastorePath("clients", "C.123").
+		AsDatastoreFilename(config_obj)
+	ttl, ok = policies.TTL(client)
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, ttl)
+
+	// Policies apply to the datastores of all orgs.
+	org_hunt := "/tmp/datastore/orgs/O123/hunts/H.123.json.db"
+	assert.True(t, policies.IsPinned(org_hunt))
+
+	// No policy applies - the default timeout is used.
+	_, ok = policies.TTL("/tmp/datastore/clients/C.123.db")
+	assert.False(t, ok)
+
+	// Clients stored in a shard match the same policies.
+	config_obj.Datastore.ShardLocations = []string{"/tmp/datastore/shard1"}
+	policies, err = NewExpirationPolicies(config_obj)
+	require.NoError(t, err)
+
+	ttl, ok = policies.TTL(
+		"/tmp/datastore/shard1/clients/C.123/collections/F.123/stats.db")
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, ttl)
+	config_obj.Datastore.ShardLocations = nil
+
+	// Invalid regex is rejected.
+	config_obj.Datastore.MemcacheExpirationPolicies = []*config_proto.MemcacheExpirationPolicy{
+		{Regex: "(", TtlSec: 10},
+	}
+	_, err = NewExpirationPolicies(config_obj)
+	assert.Error(t, err)
+}
@@ -44,9 +44,11 @@ package datastore
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -88,6 +90,12 @@ var (
 			Help: "LRU for memcache",
 		})
 
+	metricDataLRUPrefetch = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "memcache_lru_data_prefetch",
+			Help: "Number of data files prefetched into the memcache by ListChildren",
+		})
+
 	metricIdleWriters = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "memcache_idle_writers",
@@ -98,6 +106,7 @@ var (
 const (
 	MUTATION_OP_SET_SUBJECT = iota
 	MUTATION_OP_DEL_SUBJECT
+	MUTATION_OP_TXN
 )
 
 // Mark a mutation to be written to the backing data store.
@@ -112,9 +121,21 @@ type Mutation struct {
 
 	// Will run when committed to disk.
 	completion func()
+
+	// The id of the write ahead log entry (0 if not journaled).
+	wal_id uint64
+
+	// For MUTATION_OP_TXN: the operations and the intent file
+	// recording them.
+	txn_ops      []*TxnOp
+	txn_filename string
 }
 
 type MemcacheFileDataStore struct {
+	// Number of mutations queued or currently being written. Must
+	// be first for 64 bit alignment of atomic operations.
+	pending int64
+
 	mu    sync.Mutex
 	cache *MemcacheDatastore
 
@@ -122,6 +143,21 @@ type MemcacheFileDataStore struct {
 	ctx    context.Context
 	cancel func()
 
+	// Optional write ahead log for queued mutations.
+	wal *MemcacheWAL
+
+	// Directories currently being prefetched and the maximum
+	// number of children to prefetch from each (the size of the
+	// data LRU).
+	prefetching  map[string]bool
+	prefetch_max int
+
+	// Per path expiration times configured by the user. This is
+	// read from the cache's expiration callback which may run
+	// while mu is held so it has its own lock.
+	expiration_mu sync.Mutex
+	expiration    *ExpirationPolicies
+
 	started bool
 }
 
@@ -151,20 +187,69 @@ func (self *MemcacheFileDataStore) ExpirationPolicy(
 		return false
 	}
 
-	return true
+	self.expiration_mu.Lock()
+	expiration := self.expiration
+	self.expiration_mu.Unlock()
+
+	return !expiration.IsPinned(key)
 }
 
-func (self *MemcacheFileDataStore) Flush() {
+// Flush drains all queued mutations into the backing store and waits
+// for in-flight writers to finish. Returns an error if the context
+// expires before all mutations are written.
+func (self *MemcacheFileDataStore) Flush(ctx context.Context) error {
+	self.mu.Lock()
+	writer := self.writer
+	self.mu.Unlock()
+
+	if writer == nil {
+		return nil
+	}
+
+	delay := time.NewTicker(10 * time.Millisecond)
+	defer delay.Stop()
+
 	for {
+		// Help the writers drain the queue - they may have already
+		// exited if the datastore is shutting down.
 		select {
-		case mutation, ok := <-self.writer:
-			if !ok {
-				return
+		case <-ctx.Done():
+			return fmt.Errorf("MemcacheFileDataStore: %v mutations not flushed: %w",
+				atomic.LoadInt64(&self.pending), ctx.Err())
+
+		case mutation, ok := <-writer:
+			if ok {
+				self.processMutation(mutation)
 			}
-			self.processMutation(mutation)
+			continue
+
 		default:
-			return
 		}
+
+		if atomic.LoadInt64(&self.pending) <= 0 {
+			return nil
+		}
+
+		// Wait for the in-flight writers.
+		select {
+		case <-ctx.Done():
+		case <-delay.C:
+		}
+	}
+}
+
+// Queue the mutation for the writers. Returns false if the datastore
+// is shutting down and the mutation was not queued.
+func (self *MemcacheFileDataStore) enqueue(mutation *Mutation) bool {
+	atomic.AddInt64(&self.pending, 1)
+
+	select {
+	case <-self.ctx.Done():
+		atomic.AddInt64(&self.pending, -1)
+		return false
+
+	case self.writer <- mutation:
+		return true
 	}
 }
 
@@ -185,11 +270,52 @@ func (self *MemcacheFileDataStore) StartWriter(
 		timeout = 600
 	}
 	self.cache.SetTimeout(time.Duration(timeout) * time.Second)
+
+	expiration, err := NewExpirationPolicies(config_obj)
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("MemcacheFileDataStore: Invalid expiration policy: %v", err)
+	} else {
+		self.expiration_mu.Lock()
+		self.expiration = expiration
+		self.expiration_mu.Unlock()
+
+		self.cache.SetExpirationPolicies(expiration)
+	}
 	self.cache.SetCheckExpirationCallback(self.ExpirationPolicy)
 
 	if buffer_size < 0 {
 		buffer_size = 1000
 	}
+
+	// Replay any transactions and mutations lost in a previous
+	// crash before we accept new ones.
+	err = RecoverTransactions(config_obj)
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("MemcacheFileDataStore: Unable to recover transactions: %v", err)
+	}
+
+	if config_obj.Datastore != nil &&
+		config_obj.Datastore.MemcacheWalEnabled {
+		wal, err := OpenMemcacheWAL(config_obj)
+		if err != nil {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Error("MemcacheFileDataStore: Unable to open WAL: %v", err)
+		} else {
+			self.mu.Lock()
+			self.wal = wal
+			self.mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-ctx.Done()
+				wal.Close()
+			}()
+		}
+	}
+
 	self.mu.Lock()
 	self.writer = make(chan *Mutation, buffer_size)
 	self.ctx = ctx
@@ -250,9 +376,17 @@ func (self *MemcacheFileDataStore) processMutation(mutation *Mutation) {
 		if mutation.completion != nil {
 			mutation.completion()
 		}
+
+	case MUTATION_OP_TXN:
+		self.processTransaction(mutation)
+	}
+
+	if self.wal != nil {
+		self.wal.Commit(mutation.wal_id)
 	}
 
 	metricIdleWriters.Inc()
+	atomic.AddInt64(&self.pending, -1)
 	if mutation.wg != nil {
 		mutation.wg.Done()
 	}
@@ -291,6 +425,20 @@ func (self *MemcacheFileDataStore) GetSubject(
 	return err
 }
 
+// Record the mutation in the write ahead log before it is queued.
+func (self *MemcacheFileDataStore) journal(mutation *Mutation) {
+	if self.wal == nil {
+		return
+	}
+
+	err := self.wal.Append(mutation)
+	if err != nil {
+		logger := logging.GetLogger(
+			mutation.org_config_obj, &logging.FrontendComponent)
+		logger.Error("MemcacheFileDataStore: Unable to journal mutation: %v", err)
+	}
+}
+
 func (self *MemcacheFileDataStore) maybeComplete(c func()) {
 	if c != nil {
 		c()
@@ -353,17 +501,16 @@ func (self *MemcacheFileDataStore) SetSubjectWithCompletion(
 		mutation.completion = wg.Done
 	}
 
+	self.journal(mutation)
+
+	// After we send this to the channel, the writer will
+	// complete.
 	wg.Add(1)
-	select {
-	case <-self.ctx.Done():
+	if !self.enqueue(mutation) {
 		// If we exit this function we need to call the completion,
 		// otherwise let the writer call the completion.
 		self.maybeComplete(completion)
 		return nil
-
-		// After we send this to the channel, the writer will
-		// complete.
-	case self.writer <- mutation:
 	}
 
 	// Config file switches off asynchronous writes, wait here for
@@ -432,12 +579,7 @@ func (self *MemcacheFileDataStore) DeleteSubject(
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 
-	select {
-	case <-self.ctx.Done():
-		completion()
-		break
-
-	case self.writer <- &Mutation{
+	mutation := &Mutation{
 		op: MUTATION_OP_DEL_SUBJECT,
 		wg: wg,
 
@@ -445,7 +587,11 @@ func (self *MemcacheFileDataStore) DeleteSubject(
 		// avoid racing with GetSubject().
 		completion:     completion,
 		urn:            urn,
-		org_config_obj: config_obj}:
+		org_config_obj: config_obj}
+	self.journal(mutation)
+
+	if !self.enqueue(mutation) {
+		completion()
 	}
 
 	if config_obj.Datastore.MemcacheWriteMutationBuffer < 0 {
@@ -473,21 +619,18 @@ func (self *MemcacheFileDataStore) DeleteSubjectWithCompletion(
 		return notInitializedError
 	}
 
-	select {
-	case <-self.ctx.Done():
-		if completion != nil {
-			completion()
-		}
-		break
-
-	case self.writer <- &Mutation{
+	mutation := &Mutation{
 		op: MUTATION_OP_DEL_SUBJECT,
 
 		// When we complete make sure the cache is also invalidated to
 		// avoid racing with GetSubject().
 		completion:     __completion,
 		urn:            urn,
-		org_config_obj: config_obj}:
+		org_config_obj: config_obj}
+	self.journal(mutation)
+
+	if !self.enqueue(mutation) && completion != nil {
+		completion()
 	}
 
 	return nil
@@ -516,12 +659,88 @@ func (self *MemcacheFileDataStore) ListChildren(
 		// Store in the memcache.
 		self.cache.SetChildren(config_obj, urn, children)
 
+		self.prefetchChildren(config_obj, urn, children)
+
 	} else {
 		metricDirLRUHit.Inc()
 	}
 	return children, err
 }
 
+// Callers often list a directory and then fetch each child
+// (e.g. the GUI enumerating clients), causing a cache miss for every
+// child. When Datastore.memcache_prefetch_children is set we load
+// the data files into the cache in the background so those reads are
+// served from memory.
+func (self *MemcacheFileDataStore) prefetchChildren(
+	config_obj *config_proto.Config,
+	urn api.DSPathSpec, children []api.DSPathSpec) {
+	if config_obj.Datastore == nil ||
+		!config_obj.Datastore.MemcachePrefetchChildren {
+		return
+	}
+
+	// Only run one prefetch per directory at the same time.
+	key := urn.AsDatastoreDirectory(config_obj)
+	self.mu.Lock()
+	if self.prefetching == nil {
+		self.prefetching = make(map[string]bool)
+	}
+	if self.prefetching[key] {
+		self.mu.Unlock()
+		return
+	}
+	self.prefetching[key] = true
+	self.mu.Unlock()
+
+	go func() {
+		defer func() {
+			self.mu.Lock()
+			delete(self.prefetching, key)
+			self.mu.Unlock()
+		}()
+
+		count := 0
+		for _, child := range children {
+			if child.IsDir() {
+				continue
+			}
+
+			// There is no point loading more than the cache can
+			// hold - we would just evict the items we loaded.
+			count++
+			if self.prefetch_max > 0 && count > self.prefetch_max {
+				return
+			}
+
+			self.prefetchChild(config_obj, child)
+		}
+	}()
+}
+
+func (self *MemcacheFileDataStore) prefetchChild(
+	config_obj *config_proto.Config, urn api.DSPathSpec) {
+
+	// Hold the lock like GetSubject() does so we do not race
+	// with a concurrent read populating the cache.
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	_, err := self.cache.GetBuffer(config_obj, urn)
+	if err == nil {
+		return
+	}
+
+	serialized_content, err := readContentFromFile(
+		config_obj, urn, true /* must exist */)
+	if err != nil {
+		return
+	}
+
+	metricDataLRUPrefetch.Inc()
+	self.cache.SetData(config_obj, urn, serialized_content)
+}
+
 func (self *MemcacheFileDataStore) Close() {
 	self.cache.Close()
 }
@@ -577,18 +796,32 @@ func (self *MemcacheFileDataStore) SetBuffer(
 
 	var wg sync.WaitGroup
 	wg.Add(1)
-	select {
-	case <-self.ctx.Done():
-		return nil
 
-	case self.writer <- &Mutation{
+	mutation := &Mutation{
 		op:             MUTATION_OP_SET_SUBJECT,
 		urn:            urn,
 		org_config_obj: config_obj,
 		wg:             &wg,
 		data:           data,
 		completion:     completion,
-	}:
+	}
+
+	// If the call is synchronous we need to wait here until it is
+	// flushed to disk.
+	if utils.CompareFuncs(completion, utils.SyncCompleter) {
+		wg.Add(1)
+		defer wg.Wait()
+		mutation.completion = wg.Done
+	}
+
+	self.journal(mutation)
+
+	if !self.enqueue(mutation) {
+		// The writer will never see this mutation so complete it
+		// here.
+		wg.Done()
+		self.maybeComplete(mutation.completion)
+		return nil
 	}
 
 	if config_obj.Datastore.MemcacheWriteMutationBuffer < 0 {
@@ -665,6 +898,8 @@ func NewMemcacheFileDataStore(config_obj *config_proto.Config) *MemcacheFileData
 				data_max_size, dir_max_item_size),
 			get_dir_metadata: get_file_dir_metadata,
 		},
+		prefetching:  make(map[string]bool),
+		prefetch_max: data_max_size,
 	}
 
 	return result
@@ -683,6 +918,13 @@ func StartMemcacheFileService(
 		return err
 	}
 
+	// The file based datastore applies transactions synchronously
+	// but may have crashed half way through one.
+	_, ok := db.(*FileBaseDataStore)
+	if ok {
+		return RecoverTransactions(config_obj)
+	}
+
 	memcache_file_db, ok := db.(*MemcacheFileDataStore)
 	if !ok {
 		// If it not a MemcacheFileDataStore so we dont need to do
@@ -14,6 +14,7 @@ import (
 	"github.com/sebdah/goldie"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/config"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
@@ -282,6 +283,128 @@ func (self MemcacheFileTestSuite) TestDeepSetSubjectAfterListChildren() {
 	assert.Equal(self.T(), len(children), 3)
 }
 
+// Mutations that were journaled but never committed are replayed
+// when the WAL is opened.
+func (self MemcacheFileTestSuite) TestWALReplay() {
+	client_id := "C.1234"
+	client_pathspec := paths.NewClientPathManager(client_id)
+	client_record := &api_proto.ClientMetadata{
+		ClientId: client_id,
+	}
+	serialized, err := proto.Marshal(client_record)
+	assert.NoError(self.T(), err)
+
+	wal, err := OpenMemcacheWAL(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	// Journal the mutation but simulate a crash before the writer
+	// gets to it.
+	err = wal.Append(&Mutation{
+		op:             MUTATION_OP_SET_SUBJECT,
+		urn:            client_pathspec.Path(),
+		org_config_obj: self.config_obj,
+		data:           serialized,
+	})
+	assert.NoError(self.T(), err)
+	wal.Close()
+
+	_, err = os.Stat(client_pathspec.Path().AsDatastoreFilename(self.config_obj))
+	assert.True(self.T(), os.IsNotExist(err))
+
+	// Opening the WAL again replays the mutation.
+	wal, err = OpenMemcacheWAL(self.config_obj)
+	assert.NoError(self.T(), err)
+	defer wal.Close()
+
+	new_record := &api_proto.ClientMetadata{}
+	err = file_based_imp.GetSubject(
+		self.config_obj, client_pathspec.Path(), new_record)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), client_id, new_record.ClientId)
+}
+
+func (self MemcacheFileTestSuite) TestFlush() {
+	client_record := &api_proto.ClientMetadata{
+		ClientId: "C.1234",
+	}
+
+	// Mutations are queued for the writers.
+	for i := 0; i < 20; i++ {
+		urn := path_specs.NewSafeDatastorePath("flush", fmt.Sprintf("c%d", i))
+		err := self.datastore.SetSubjectWithCompletion(
+			self.config_obj, urn, client_record, nil)
+		assert.NoError(self.T(), err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db := self.datastore.(*MemcacheFileDataStore)
+	err := db.Flush(ctx)
+	assert.NoError(self.T(), err)
+
+	// All the mutations hit the disk.
+	for i := 0; i < 20; i++ {
+		urn := path_specs.NewSafeDatastorePath("flush", fmt.Sprintf("c%d", i))
+		new_record := &api_proto.ClientMetadata{}
+		err := file_based_imp.GetSubject(self.config_obj, urn, new_record)
+		assert.NoError(self.T(), err)
+	}
+
+	// Flush drains the queue even when no writers are running
+	// (e.g. they exited during shutdown).
+	stopped := NewMemcacheFileDataStore(self.config_obj)
+	stopped.ctx = self.ctx
+	stopped.writer = make(chan *Mutation, 10)
+
+	urn := path_specs.NewSafeDatastorePath("flush", "stopped")
+	err = stopped.SetSubjectWithCompletion(
+		self.config_obj, urn, client_record, nil)
+	assert.NoError(self.T(), err)
+
+	err = stopped.Flush(ctx)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(0), stopped.pending)
+
+	new_record := &api_proto.ClientMetadata{}
+	err = file_based_imp.GetSubject(self.config_obj, urn, new_record)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "C.1234", new_record.ClientId)
+}
+
+// Listing a directory prefetches the children into the data cache.
+func (self MemcacheFileTestSuite) TestListChildrenPrefetch() {
+	self.config_obj.Datastore.MemcachePrefetchChildren = true
+
+	db, ok := self.datastore.(*MemcacheFileDataStore)
+	assert.True(self.T(), ok)
+
+	client_record := &api_proto.ClientMetadata{ClientId: "C.1234"}
+	for i := 0; i < 5; i++ {
+		err := file_based_imp.SetSubject(self.config_obj,
+			path_specs.NewSafeDatastorePath("a", fmt.Sprintf("%d", i)),
+			client_record)
+		assert.NoError(self.T(), err)
+	}
+
+	children, err := db.ListChildren(self.config_obj,
+		path_specs.NewSafeDatastorePath("a"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 5, len(children))
+
+	// All the children end up in the data cache without reading
+	// them.
+	vtesting.WaitUntil(time.Second, self.T(), func() bool {
+		for _, child := range children {
+			_, err := db.cache.GetBuffer(self.config_obj, child)
+			if err != nil {
+				return false
+			}
+		}
+		return true
+	})
+}
+
 func TestMemCacheFileDatastore(t *testing.T) {
 	suite.Run(t, &MemcacheFileTestSuite{BaseTestSuite: BaseTestSuite{}})
 }
@@ -0,0 +1,250 @@
+package datastore
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+var (
+	metricNamespaceItems = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "memcache_data_lru_namespace_total",
+			Help: "Total files cached per datastore namespace",
+		}, []string{"namespace"})
+
+	metricNamespaceBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "memcache_data_lru_namespace_bytes",
+			Help: "Total bytes cached per datastore namespace",
+		}, []string{"namespace"})
+
+	metricNamespaceEvictions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "memcache_data_lru_namespace_evictions",
+			Help: "Number of items evicted because the namespace exceeded its quota",
+		}, []string{"namespace"})
+)
+
+// Tracks the items cached for a single namespace.
+type namespaceUsage struct {
+	name      string
+	max_items int
+	max_bytes int
+
+	size int
+
+	// Keys in least recently used order (front is oldest).
+	lru *list.List
+}
+
+func (self *namespaceUsage) overQuota() bool {
+	return (self.max_items > 0 && self.lru.Len() > self.max_items) ||
+		(self.max_bytes > 0 && self.size > self.max_bytes)
+}
+
+func (self *namespaceUsage) updateMetrics() {
+	metricNamespaceItems.WithLabelValues(self.name).Set(float64(self.lru.Len()))
+	metricNamespaceBytes.WithLabelValues(self.name).Set(float64(self.size))
+}
+
+type quotaEntry struct {
+	key   string
+	size  int
+	value *BulkData
+	usage *namespaceUsage
+}
+
+// Accounts for the data cache by namespace. Without limits a single
+// large hunt can push every other item out of the cache (e.g. the
+// client ping records which are read all the time). Keys which do
+// not match a configured quota are accounted by their top level
+// directory but are not limited.
+type NamespaceQuotas struct {
+	mu sync.Mutex
+
+	locations []string
+
+	// Configured quotas in the order they appear in the config.
+	quotas []*namespaceUsage
+
+	// All namespaces by name.
+	namespaces map[string]*namespaceUsage
+
+	// key -> *quotaEntry
+	entries map[string]*list.Element
+}
+
+func (self *NamespaceQuotas) getNamespace(key string) *namespaceUsage {
+	path := normalizeCacheKey(self.locations, key)
+	for _, quota := range self.quotas {
+		if strings.HasPrefix(path, quota.name) {
+			return quota
+		}
+	}
+
+	// Account by the top level directory.
+	name := "/"
+	components := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(components) == 2 {
+		name = "/" + components[0] + "/"
+	}
+
+	usage, pres := self.namespaces[name]
+	if !pres {
+		usage = &namespaceUsage{name: name, lru: list.New()}
+		self.namespaces[name] = usage
+	}
+	return usage
+}
+
+// Record the key in its namespace. Returns the keys which need to be
+// evicted from the cache to bring the namespace back under its
+// quota. This may include the key itself if it is too large for the
+// namespace.
+func (self *NamespaceQuotas) Add(key string, value *BulkData) []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	size := value.Len()
+
+	var entry *quotaEntry
+	element, pres := self.entries[key]
+	if pres {
+		entry = element.Value.(*quotaEntry)
+		entry.usage.size += size - entry.size
+		entry.size = size
+		entry.value = value
+		entry.usage.lru.MoveToBack(element)
+
+	} else {
+		entry = &quotaEntry{
+			key:   key,
+			size:  size,
+			value: value,
+			usage: self.getNamespace(key),
+		}
+		entry.usage.size += size
+		self.entries[key] = entry.usage.lru.PushBack(entry)
+	}
+
+	usage := entry.usage
+
+	var evicted []string
+	for usage.overQuota() {
+		oldest := usage.lru.Front()
+		if oldest == nil {
+			break
+		}
+		self.removeElement(oldest)
+		evicted = append(evicted, oldest.Value.(*quotaEntry).key)
+		metricNamespaceEvictions.WithLabelValues(usage.name).Inc()
+	}
+
+	usage.updateMetrics()
+
+	return evicted
+}
+
+// Mark the key as recently used.
+func (self *NamespaceQuotas) Touch(key string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	element, pres := self.entries[key]
+	if pres {
+		element.Value.(*quotaEntry).usage.lru.MoveToBack(element)
+	}
+}
+
+// Called when the key is removed from the cache. Cache callbacks
+// may arrive after the key was set again, so we only remove the
+// entry if it still refers to the same value.
+func (self *NamespaceQuotas) Remove(key string, value *BulkData) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	element, pres := self.entries[key]
+	if !pres {
+		return
+	}
+
+	entry := element.Value.(*quotaEntry)
+	if value != nil && entry.value != value {
+		return
+	}
+
+	self.removeElement(element)
+	entry.usage.updateMetrics()
+}
+
+func (self *NamespaceQuotas) removeElement(element *list.Element) {
+	entry := element.Value.(*quotaEntry)
+	entry.usage.lru.Remove(element)
+	entry.usage.size -= entry.size
+	delete(self.entries, entry.key)
+}
+
+// Returns the number of items and bytes cached in the namespace.
+func (self *NamespaceQuotas) Usage(namespace string) (int, int) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	usage, pres := self.namespaces[namespace]
+	if !pres {
+		return 0, 0
+	}
+	return usage.lru.Len(), usage.size
+}
+
+func (self *NamespaceQuotas) Reset() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.entries = make(map[string]*list.Element)
+	for _, usage := range self.namespaces {
+		usage.lru.Init()
+		usage.size = 0
+		usage.updateMetrics()
+	}
+}
+
+func NewNamespaceQuotas(config_obj *config_proto.Config) *NamespaceQuotas {
+	result := &NamespaceQuotas{
+		namespaces: make(map[string]*namespaceUsage),
+		entries:    make(map[string]*list.Element),
+	}
+
+	if config_obj == nil || config_obj.Datastore == nil {
+		return result
+	}
+
+	result.locations = path_specs.GetDatastoreShards(config_obj)
+
+	for _, quota := range config_obj.Datastore.MemcacheNamespaceQuotas {
+		if quota.Prefix == "" {
+			continue
+		}
+
+		usage, pres := result.namespaces[quota.Prefix]
+		if pres {
+			continue
+		}
+
+		usage = &namespaceUsage{
+			name:      quota.Prefix,
+			max_items: int(quota.MaxItems),
+			max_bytes: int(quota.MaxBytes),
+			lru:       list.New(),
+		}
+		result.quotas = append(result.quotas, usage)
+		result.namespaces[quota.Prefix] = usage
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+func TestNamespaceQuotas(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.Location = "/tmp/datastore"
+	config_obj.Datastore.MemcacheNamespaceQuotas = []*config_proto.MemcacheNamespaceQuota{
+		{Prefix: "/hunts/", MaxItems: 2},
+		{Prefix: "/notebooks/", MaxBytes: 10},
+	}
+
+	quotas := NewNamespaceQuotas(config_obj)
+	key := func(components ...string) string {
+		return path_specs.NewSafeDatastorePath(components...).
+			AsDatastoreFilename(config_obj)
+	}
+	data := func(size int) *BulkData {
+		return &BulkData{data: make([]byte, size)}
+	}
+
+	// Clients are not limited but are accounted by top level
+	// directory.
+	ping := key("clients", "C.123", "ping")
+	assert.Empty(t, quotas.Add(ping, data(5)))
+	assert.Empty(t, quotas.Add(key("clients", "C.456", "ping"), data(5)))
+
+	// The third hunt evicts the least recently used hunt.
+	h1 := key("hunts", "H.1")
+	h2 := key("hunts", "H.2")
+	assert.Empty(t, quotas.Add(h1, data(1)))
+	assert.Empty(t, quotas.Add(h2, data(1)))
+	quotas.Touch(h1)
+	assert.Equal(t, []string{h2}, quotas.Add(key("hunts", "H.3"), data(1)))
+
+	count, _ := quotas.Usage("/hunts/")
+	assert.Equal(t, 2, count)
+
+	// Hunts do not affect clients.
+	count, size := quotas.Usage("/clients/")
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 10, size)
+
+	// Byte limits: replacing an item updates its size.
+	n1 := key("notebooks", "N.1")
+	assert.Empty(t, quotas.Add(n1, data(4)))
+	assert.Empty(t, quotas.Add(n1, data(8)))
+	_, size = quotas.Usage("/notebooks/")
+	assert.Equal(t, 8, size)
+	assert.Equal(t, []string{n1}, quotas.Add(key("notebooks", "N.2"), data(4)))
+
+	// An item that is larger than the quota evicts itself.
+	n3 := key("notebooks", "N.3")
+	assert.Equal(t, []string{key("notebooks", "N.2"), n3},
+		quotas.Add(n3, data(20)))
+
+	// Stale removals are ignored if the key was set again.
+	quotas.Remove(ping, data(5))
+	count, _ = quotas.Usage("/clients/")
+	assert.Equal(t, 2, count)
+
+	quotas.Remove(ping, nil)
+	count, size = quotas.Usage("/clients/")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 5, size)
+
+	quotas.Reset()
+	count, _ = quotas.Usage("/hunts/")
+	assert.Equal(t, 0, count)
+}
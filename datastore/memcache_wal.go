@@ -0,0 +1,232 @@
+// A write ahead log for the MemcacheFileDataStore.
+
+// The MemcacheFileDataStore acknowledges writes as soon as they are
+// placed in the memory cache and queued on the writer channel. If
+// the server crashes before the writers flush the queue, those
+// writes are lost.
+
+// When Datastore.memcache_wal_enabled is set, each mutation is
+// appended to a journal file before it is queued. Once a writer
+// applies the mutation to disk, a commit record is appended. On
+// startup any mutations without a commit record are replayed
+// directly onto the filesystem before the writers start.
+
+// The journal is truncated whenever there are no outstanding
+// mutations so it does not grow without bounds.
+
+package datastore
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+)
+
+const (
+	WAL_FILENAME = "memcache.wal"
+)
+
+type walRecord struct {
+	Id uint64 `json:"id"`
+
+	// Set on commit records.
+	Committed bool `json:"committed,omitempty"`
+
+	Op       int    `json:"op,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+}
+
+type MemcacheWAL struct {
+	mu sync.Mutex
+
+	filename    string
+	fd          *os.File
+	next_id     uint64
+	outstanding map[uint64]bool
+}
+
+// Journal a mutation before it is queued. Sets the mutation's wal_id
+// so the writer can commit it later.
+func (self *MemcacheWAL) Append(mutation *Mutation) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	// The data is journaled exactly as it will be written to disk.
+	data, err := encodeContent(
+		mutation.org_config_obj, mutation.urn, mutation.data)
+	if err != nil {
+		return err
+	}
+
+	self.next_id++
+	record := &walRecord{
+		Id:       self.next_id,
+		Op:       mutation.op,
+		Filename: mutation.urn.AsDatastoreFilename(mutation.org_config_obj),
+		Data:     data,
+	}
+
+	err = self.writeRecord(record)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the record is durable before the write is
+	// acknowledged.
+	err = self.fd.Sync()
+	if err != nil {
+		return err
+	}
+
+	mutation.wal_id = record.Id
+	self.outstanding[record.Id] = true
+	return nil
+}
+
+// Mark the mutation as applied to the backing store.
+func (self *MemcacheWAL) Commit(id uint64) {
+	if id == 0 {
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	delete(self.outstanding, id)
+
+	// Nothing is outstanding so we can start the journal afresh.
+	if len(self.outstanding) == 0 {
+		err := self.fd.Truncate(0)
+		if err == nil {
+			_, err = self.fd.Seek(0, io.SeekStart)
+		}
+		if err == nil {
+			return
+		}
+	}
+
+	_ = self.writeRecord(&walRecord{Id: id, Committed: true})
+}
+
+func (self *MemcacheWAL) writeRecord(record *walRecord) error {
+	serialized, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	serialized = append(serialized, '\n')
+
+	_, err = self.fd.Write(serialized)
+	return err
+}
+
+func (self *MemcacheWAL) Close() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.fd.Close()
+}
+
+// Apply all uncommitted mutations in the journal to the filesystem.
+func (self *MemcacheWAL) replay(config_obj *config_proto.Config) error {
+	_, err := self.fd.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[uint64]*walRecord)
+	scanner := bufio.NewScanner(self.fd)
+	scanner.Buffer(nil, 100*1024*1024)
+	for scanner.Scan() {
+		record := &walRecord{}
+		err := json.Unmarshal(scanner.Bytes(), record)
+		if err != nil {
+			// A torn write at the end of the journal means the
+			// mutation was never acknowledged.
+			continue
+		}
+
+		if record.Committed {
+			delete(pending, record.Id)
+		} else {
+			pending[record.Id] = record
+		}
+	}
+
+	ids := make([]uint64, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	if len(ids) > 0 {
+		logger.Info("MemcacheWAL: Replaying %v unflushed mutations from %v",
+			len(ids), self.filename)
+	}
+
+	for _, id := range ids {
+		record := pending[id]
+		err := applyWALRecord(record)
+		if err != nil {
+			logger.Error("MemcacheWAL: Unable to replay mutation to %v: %v",
+				record.Filename, err)
+		}
+	}
+
+	err = self.fd.Truncate(0)
+	if err != nil {
+		return err
+	}
+	_, err = self.fd.Seek(0, io.SeekStart)
+	return err
+}
+
+func applyWALRecord(record *walRecord) error {
+	switch record.Op {
+	case MUTATION_OP_SET_SUBJECT:
+		err := os.MkdirAll(filepath.Dir(record.Filename), 0700)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(record.Filename, record.Data, 0660)
+
+	case MUTATION_OP_DEL_SUBJECT:
+		err := os.Remove(record.Filename)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Open the journal in the datastore directory and replay any
+// mutations left over from a previous run.
+func OpenMemcacheWAL(config_obj *config_proto.Config) (*MemcacheWAL, error) {
+	filename := filepath.Join(config_obj.Datastore.Location, WAL_FILENAME)
+	fd, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MemcacheWAL{
+		filename:    filename,
+		fd:          fd,
+		outstanding: make(map[uint64]bool),
+	}
+
+	err = result.replay(config_obj)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -48,8 +48,50 @@ func (self *ReadOnlyDataStore) DeleteSubject(
 	return self.cache.DeleteSubject(config_obj, urn)
 }
 
+// Deletes only affect the cache so they are applied immediately.
+func (self *ReadOnlyDataStore) DeleteSubjectWithCompletion(
+	config_obj *config_proto.Config,
+	urn api.DSPathSpec, completion func()) error {
+
+	err := self.cache.DeleteSubject(config_obj, urn)
+	if completion != nil &&
+		!utils.CompareFuncs(completion, utils.SyncCompleter) {
+		completion()
+	}
+	return err
+}
+
 func NewReadOnlyDataStore(config_obj *config_proto.Config) *ReadOnlyDataStore {
 	return &ReadOnlyDataStore{&MemcacheFileDataStore{
 		cache: NewMemcacheDataStore(config_obj),
 	}}
 }
+
+// Transactions only affect the cache.
+func (self *ReadOnlyDataStore) CommitTransaction(
+	config_obj *config_proto.Config,
+	ops []*TxnOp, completion func()) error {
+
+	defer func() {
+		if completion != nil &&
+			!utils.CompareFuncs(completion, utils.SyncCompleter) {
+			completion()
+		}
+	}()
+
+	for _, op := range ops {
+		switch op.Op {
+		case MUTATION_OP_SET_SUBJECT:
+			err := self.cache.SetData(config_obj, op.Urn, op.Data)
+			if err != nil {
+				return err
+			}
+		case MUTATION_OP_DEL_SUBJECT:
+			err := self.cache.DeleteSubject(config_obj, op.Urn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ var (
 	remote_datastopre_imp = NewRemoteDataStore(context.Background())
 	RPC_TIMEOUT           = 100 // Seconds
 	RPC_BACKOFF           = 10.0
+	RPC_MAX_BACKOFF       = 120.0 // Seconds
 	RPC_RETRY             = 10
 	timeoutError          = errors.New("Timeout")
 )
@@ -34,6 +35,7 @@ func Retry(ctx context.Context,
 	config_obj *config_proto.Config, cb func() error) error {
 	var err error
 
+	backoff := RPC_BACKOFF
 	for i := 0; i < RPC_RETRY; i++ {
 		err = cb()
 		if err == nil {
@@ -59,7 +61,14 @@ func Retry(ctx context.Context,
 			select {
 			case <-ctx.Done():
 				return timeoutError
-			case <-time.After(time.Duration(RPC_BACKOFF) * time.Second):
+			case <-time.After(time.Duration(backoff * float64(time.Second))):
+			}
+
+			// Back off exponentially so a restarting master is not
+			// flooded by all the minions at once.
+			backoff *= 2
+			if backoff > RPC_MAX_BACKOFF {
+				backoff = RPC_MAX_BACKOFF
 			}
 
 		case codes.Internal, codes.Unknown:
@@ -97,6 +106,9 @@ func (self *RemoteDataStore) _GetSubject(
 	defer cancel()
 
 	conn, closer, err := grpc_client.Factory.GetAPIClient(ctx, config_obj)
+	if err != nil {
+		return err
+	}
 	defer closer()
 
 	result, err := conn.GetSubject(ctx, &api_proto.DataRequest{
@@ -189,6 +201,9 @@ func (self *RemoteDataStore) _SetSubjectWithCompletion(
 	defer cancel()
 
 	conn, closer, err := grpc_client.Factory.GetAPIClient(ctx, config_obj)
+	if err != nil {
+		return err
+	}
 	defer closer()
 
 	_, err = conn.SetSubject(ctx, &api_proto.DataRequest{
@@ -207,6 +222,15 @@ func (self *RemoteDataStore) _SetSubjectWithCompletion(
 func (self *RemoteDataStore) DeleteSubjectWithCompletion(
 	config_obj *config_proto.Config,
 	urn api.DSPathSpec, completion func()) error {
+
+	// Only call the completion once, even if we needed to retry.
+	defer func() {
+		if completion != nil &&
+			!utils.CompareFuncs(completion, utils.SyncCompleter) {
+			completion()
+		}
+	}()
+
 	return Retry(self.ctx, config_obj, func() error {
 		return self._DeleteSubjectWithCompletion(config_obj, urn, completion)
 	})
@@ -223,6 +247,9 @@ func (self *RemoteDataStore) _DeleteSubjectWithCompletion(
 	defer cancel()
 
 	conn, closer, err := grpc_client.Factory.GetAPIClient(ctx, config_obj)
+	if err != nil {
+		return err
+	}
 	defer closer()
 
 	_, err = conn.DeleteSubject(ctx, &api_proto.DataRequest{
@@ -234,10 +261,6 @@ func (self *RemoteDataStore) _DeleteSubjectWithCompletion(
 			Tag:        urn.Tag(),
 		}})
 
-	if completion != nil {
-		completion()
-	}
-
 	return err
 }
 
@@ -260,6 +283,9 @@ func (self *RemoteDataStore) _DeleteSubject(
 	defer cancel()
 
 	conn, closer, err := grpc_client.Factory.GetAPIClient(ctx, config_obj)
+	if err != nil {
+		return err
+	}
 	defer closer()
 
 	_, err = conn.DeleteSubject(ctx, &api_proto.DataRequest{
@@ -300,6 +326,9 @@ func (self *RemoteDataStore) _ListChildren(
 	defer cancel()
 
 	conn, closer, err := grpc_client.Factory.GetAPIClient(ctx, config_obj)
+	if err != nil {
+		return nil, err
+	}
 	defer closer()
 
 	result, err := conn.ListChildren(ctx, &api_proto.DataRequest{
@@ -1,6 +1,7 @@
 package datastore_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -10,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"www.velocidex.com/golang/velociraptor/api"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/config"
@@ -122,6 +125,36 @@ func (self *RemoteTestSuite) TestRemoteDataStoreMissing() {
 	assert.True(self.T(), len(matches) > 10)
 }
 
+func TestRetryBackoff(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+
+	old_backoff := datastore.RPC_BACKOFF
+	defer func() { datastore.RPC_BACKOFF = old_backoff }()
+
+	datastore.RPC_BACKOFF = 0
+
+	// Transient errors are retried.
+	count := 0
+	err := datastore.Retry(context.Background(), config_obj, func() error {
+		count++
+		if count < 3 {
+			return status.Error(codes.Unavailable, "connection error")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// Other errors are returned immediately.
+	count = 0
+	err = datastore.Retry(context.Background(), config_obj, func() error {
+		count++
+		return status.Error(codes.NotFound, "not found")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, count)
+}
+
 func TestRemoteTestSuite(t *testing.T) {
 	suite.Run(t, &RemoteTestSuite{})
 }
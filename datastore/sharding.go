@@ -0,0 +1,285 @@
+// Shard the file based datastore across multiple directories.
+
+// Very large deployments run into inode and IOPS limits of a single
+// volume because every client keeps many small records. When
+// Datastore.shard_locations is set, the records of each client
+// (everything under /clients/<client_id>) are stored in one of the
+// shards chosen by a hash of the client id (see
+// path_specs.GetClientShard()). All other records remain in the main
+// datastore location.
+
+// Listing /clients merges the client directories of all shards. When
+// the list of shards changes clients may hash to a different shard,
+// so Rebalance() moves their records to the right place. This must
+// be done with the server stopped. Only datastore records are moved -
+// the filestore often shares the main datastore directory and its
+// files are not sharded.
+
+package datastore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type RebalanceOptions struct {
+	// Only report the clients which need to move.
+	DryRun bool
+}
+
+type RebalanceMove struct {
+	ClientId string `json:"client_id"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Error    string `json:"error,omitempty"`
+}
+
+type RebalanceReport struct {
+	Clients int              `json:"clients"`
+	Moved   int              `json:"moved"`
+	Moves   []*RebalanceMove `json:"moves"`
+}
+
+// The directories which may hold the children of urn. The client
+// directories are spread over all the shards so listing /clients
+// (or the root) needs to look at each one.
+func getShardDirectories(
+	config_obj *config_proto.Config, urn api.DSPathSpec) []string {
+	components := urn.Components()
+	if config_obj.Datastore == nil ||
+		len(config_obj.Datastore.ShardLocations) == 0 ||
+		len(components) > 1 ||
+		(len(components) == 1 && components[0] != "clients") {
+		return []string{urn.AsDatastoreDirectory(config_obj)}
+	}
+
+	result := []string{}
+	for _, location := range path_specs.GetDatastoreShards(config_obj) {
+		shard_config := &config_proto.Config{
+			Datastore: &config_proto.DatastoreConfig{Location: location},
+		}
+		result = append(result, urn.AsDatastoreDirectory(shard_config))
+	}
+	return result
+}
+
+// Move the records of each client into the shard it belongs to.
+func Rebalance(ctx context.Context,
+	config_obj *config_proto.Config,
+	options RebalanceOptions) (*RebalanceReport, error) {
+
+	if config_obj.Datastore == nil || config_obj.Datastore.Location == "" {
+		return nil, datastoreNotConfiguredError
+	}
+
+	report := &RebalanceReport{Moves: []*RebalanceMove{}}
+	seen := make(map[string]bool)
+	for _, shard := range path_specs.GetDatastoreShards(config_obj) {
+		clients_dir := filepath.Join(shard, "clients")
+		children, err := utils.ReadDirUnsorted(clients_dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return report, err
+		}
+
+		for _, child := range children {
+			select {
+			case <-ctx.Done():
+				return report, ctx.Err()
+			default:
+			}
+
+			// Each client has a record (e.g. /clients/C.123.json.db)
+			// next to its directory and both are in the same shard.
+			name := child.Name()
+			if !child.IsDir() {
+				spec_type, base := api.GetDataStorePathTypeFromExtension(name)
+				if spec_type == api.PATH_TYPE_DATASTORE_UNKNOWN {
+					continue
+				}
+				name = base
+			}
+
+			client_id := utils.UnsanitizeComponent(name)
+			if !seen[client_id] {
+				seen[client_id] = true
+				report.Clients++
+			}
+
+			dest_shard := path_specs.GetClientShard(config_obj, client_id)
+			if filepath.Clean(dest_shard) == filepath.Clean(shard) {
+				continue
+			}
+
+			src := filepath.Join(clients_dir, child.Name())
+			subjects, err := listSubjects(src)
+			if err != nil {
+				return report, err
+			}
+
+			// Only filestore files are left here.
+			if len(subjects) == 0 {
+				continue
+			}
+
+			move := &RebalanceMove{
+				ClientId: client_id,
+				From:     src,
+				To:       filepath.Join(dest_shard, "clients", child.Name()),
+			}
+			report.Moves = append(report.Moves, move)
+
+			if options.DryRun {
+				continue
+			}
+
+			err = moveSubjects(move.From, move.To, subjects)
+			if err != nil {
+				move.Error = err.Error()
+				continue
+			}
+			report.Moved++
+		}
+	}
+
+	return report, nil
+}
+
+// The datastore records under src relative to src. src may be a
+// directory or a single record.
+func listSubjects(src string) ([]string, error) {
+	result := []string{}
+	err := filepath.WalkDir(src,
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			spec_type, _ := api.GetDataStorePathTypeFromExtension(d.Name())
+			if spec_type == api.PATH_TYPE_DATASTORE_UNKNOWN {
+				return nil
+			}
+
+			rel, err := filepath.Rel(src, path)
+			if err != nil {
+				return err
+			}
+			result = append(result, rel)
+			return nil
+		})
+	return result, err
+}
+
+// Move the records to dest. Shards are usually on different volumes
+// so a rename is not always possible. In that case copy the files
+// and remove the source. Directories left empty are removed.
+func moveSubjects(src, dest string, subjects []string) error {
+	for _, subject := range subjects {
+		from := filepath.Join(src, subject)
+		to := filepath.Join(dest, subject)
+
+		err := os.MkdirAll(filepath.Dir(to), 0700)
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stat(to)
+		if errors.Is(err, os.ErrNotExist) && os.Rename(from, to) == nil {
+			continue
+		}
+
+		err = copyIfNewer(from, to)
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(from)
+		if err != nil {
+			return err
+		}
+	}
+
+	return removeEmptyDirectories(src)
+}
+
+// Remove the empty directories under root (including root). Other
+// files are left alone.
+func removeEmptyDirectories(root string) error {
+	stat, err := os.Stat(root)
+	if err != nil || !stat.IsDir() {
+		return nil
+	}
+
+	var directories []string
+	err = filepath.WalkDir(root,
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				directories = append(directories, path)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	// Children are removed before their parents.
+	for i := len(directories) - 1; i >= 0; i-- {
+		entries, err := os.ReadDir(directories[i])
+		if err == nil && len(entries) == 0 {
+			_ = os.Remove(directories[i])
+		}
+	}
+	return nil
+}
+
+// The destination may already have records for the client
+// (e.g. written after the shards changed) which are newer than the
+// ones we are moving.
+func copyIfNewer(src, dest string) error {
+	src_stat, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	dest_stat, err := os.Stat(dest)
+	if err == nil && !src_stat.ModTime().After(dest_stat.ModTime()) {
+		return nil
+	}
+
+	in_fd, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in_fd.Close()
+
+	out_fd, err := os.OpenFile(dest, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return err
+	}
+	defer out_fd.Close()
+
+	_, err = io.Copy(out_fd, in_fd)
+	if err != nil {
+		return err
+	}
+
+	return os.Chtimes(dest, src_stat.ModTime(), src_stat.ModTime())
+}
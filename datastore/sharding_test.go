@@ -0,0 +1,131 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+func TestSharding(t *testing.T) {
+	dirname, err := ioutil.TempDir("", "datastore_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirname)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.FilestoreDirectory = filepath.Join(dirname, "main")
+	config_obj.Datastore.Location = filepath.Join(dirname, "main")
+	config_obj.Datastore.ShardLocations = []string{
+		filepath.Join(dirname, "shard1"),
+	}
+
+	db := &FileBaseDataStore{}
+	ctx := context.Background()
+
+	var client_ids []string
+	for i := 0; i < 20; i++ {
+		client_id := fmt.Sprintf("C.%04d", i)
+		client_ids = append(client_ids, client_id)
+
+		urn := path_specs.NewSafeDatastorePath("clients", client_id)
+		require.NoError(t, db.SetSubject(config_obj, urn,
+			&api_proto.ClientMetadata{ClientId: client_id}))
+
+		// Each client is stored in its shard.
+		shard := path_specs.GetClientShard(config_obj, client_id)
+		assert.True(t, strings.HasPrefix(
+			urn.AsDatastoreFilename(config_obj), shard))
+	}
+
+	// The filestore shares the main location and is not sharded.
+	filestore_files := []string{}
+	for _, client_id := range client_ids {
+		filename := filepath.Join(config_obj.Datastore.Location,
+			"clients", client_id, "collections", "F.1.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0700))
+		require.NoError(t, ioutil.WriteFile(filename, []byte("{}"), 0600))
+		filestore_files = append(filestore_files, filename)
+	}
+
+	// Other records stay in the main location.
+	index := path_specs.NewSafeDatastorePath("client_index", "all")
+	require.NoError(t, db.SetSubject(config_obj, index,
+		&api_proto.ClientMetadata{ClientId: "index"}))
+	assert.True(t, strings.HasPrefix(
+		index.AsDatastoreFilename(config_obj),
+		config_obj.Datastore.Location))
+
+	listClients := func() []string {
+		children, err := db.ListChildren(config_obj,
+			path_specs.NewSafeDatastorePath("clients"))
+		require.NoError(t, err)
+
+		result := []string{}
+		for _, child := range children {
+			result = append(result, child.Base())
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	// Listing merges all the shards.
+	assert.Equal(t, client_ids, listClients())
+
+	// Add another shard - some clients are now in the wrong place.
+	config_obj.Datastore.ShardLocations = append(
+		config_obj.Datastore.ShardLocations, filepath.Join(dirname, "shard2"))
+
+	// Verifying reports the misplaced records but never repairs
+	// them since they can be rebalanced.
+	verify_report, err := Verify(ctx, config_obj, VerifyOptions{Repair: true})
+	require.NoError(t, err)
+	assert.Equal(t, 0, verify_report.Repaired)
+
+	misplaced := 0
+	for _, issue := range verify_report.Issues {
+		assert.Equal(t, VERIFY_WRONG_SHARD, issue.Problem)
+		misplaced++
+	}
+	assert.True(t, misplaced > 0)
+
+	report, err := Rebalance(ctx, config_obj, RebalanceOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 20, report.Clients)
+	assert.True(t, len(report.Moves) > 0)
+	assert.Equal(t, 0, report.Moved)
+
+	report, err = Rebalance(ctx, config_obj, RebalanceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, len(report.Moves), report.Moved)
+	assert.Equal(t, misplaced, report.Moved)
+
+	// All clients are readable from their new shards.
+	assert.Equal(t, client_ids, listClients())
+	for _, client_id := range client_ids {
+		record := &api_proto.ClientMetadata{}
+		require.NoError(t, db.GetSubject(config_obj,
+			path_specs.NewSafeDatastorePath("clients", client_id), record))
+		assert.Equal(t, client_id, record.ClientId)
+	}
+
+	// Rebalancing only moved datastore records.
+	for _, filename := range filestore_files {
+		_, err := os.Stat(filename)
+		assert.NoError(t, err)
+	}
+
+	// Nothing left to move.
+	report, err = Rebalance(ctx, config_obj, RebalanceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(report.Moves))
+}
@@ -0,0 +1,446 @@
+// Lightweight datastore transactions.
+
+// Some services update several subjects together (e.g. an index
+// record and the subject it refers to). If the server crashes half
+// way through, the datastore is left inconsistent. A Txn batches Set
+// and Delete operations and commits them together:
+
+// 1. The operations are written to an intent file in the
+//    transactions directory and synced to disk.
+// 2. The operations are applied to the datastore files.
+// 3. The intent file is removed.
+
+// On startup any intent files left over from a crash are replayed,
+// so either all or none of the operations in a transaction are
+// applied. Transactions do not provide isolation - readers may see
+// some operations applied before the commit completes.
+
+// Datastores which do not support transactions (e.g. the remote
+// datastore) apply the operations one at a time.
+
+package datastore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	TXN_DIRECTORY = "transactions"
+	TXN_EXTENSION = ".txn"
+)
+
+var (
+	txnCommittedError = errors.New("Transaction already committed")
+
+	txn_id uint64
+)
+
+type TxnOp struct {
+	Op      int
+	Urn     api.DSPathSpec
+	Message proto.Message
+	Data    []byte
+}
+
+// Datastores which can apply a batch of operations atomically.
+type TransactionalDataStore interface {
+	CommitTransaction(config_obj *config_proto.Config,
+		ops []*TxnOp, completion func()) error
+}
+
+type Txn struct {
+	mu sync.Mutex
+
+	db         DataStore
+	config_obj *config_proto.Config
+	ops        []*TxnOp
+	committed  bool
+}
+
+// Add a SetSubject operation to the transaction.
+func (self *Txn) Set(urn api.DSPathSpec, message proto.Message) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.committed {
+		return txnCommittedError
+	}
+
+	data, err := encodeSubject(urn, message)
+	if err != nil {
+		return err
+	}
+
+	self.ops = append(self.ops, &TxnOp{
+		Op:      MUTATION_OP_SET_SUBJECT,
+		Urn:     urn,
+		Message: message,
+		Data:    data,
+	})
+	return nil
+}
+
+// Add a DeleteSubject operation to the transaction.
+func (self *Txn) Delete(urn api.DSPathSpec) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.committed {
+		return txnCommittedError
+	}
+
+	self.ops = append(self.ops, &TxnOp{
+		Op:  MUTATION_OP_DEL_SUBJECT,
+		Urn: urn,
+	})
+	return nil
+}
+
+// Commit the transaction and wait until it is applied.
+func (self *Txn) Commit() error {
+	return self.CommitWithCompletion(utils.SyncCompleter)
+}
+
+// Commit the transaction and call completion when it is applied to
+// the datastore.
+func (self *Txn) CommitWithCompletion(completion func()) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.committed {
+		return txnCommittedError
+	}
+	self.committed = true
+
+	if len(self.ops) == 0 {
+		if completion != nil &&
+			!utils.CompareFuncs(completion, utils.SyncCompleter) {
+			completion()
+		}
+		return nil
+	}
+
+	txn_db, ok := self.db.(TransactionalDataStore)
+	if ok {
+		return txn_db.CommitTransaction(self.config_obj, self.ops, completion)
+	}
+
+	return applyOpsSequentially(self.db, self.config_obj, self.ops, completion)
+}
+
+func NewTxn(config_obj *config_proto.Config, db DataStore) *Txn {
+	return &Txn{
+		db:         db,
+		config_obj: config_obj,
+	}
+}
+
+// Fallback for datastores that do not support transactions.
+func applyOpsSequentially(db DataStore, config_obj *config_proto.Config,
+	ops []*TxnOp, completion func()) (err error) {
+
+	defer func() {
+		if completion != nil &&
+			!utils.CompareFuncs(completion, utils.SyncCompleter) {
+			completion()
+		}
+	}()
+
+	for _, op := range ops {
+		switch op.Op {
+		case MUTATION_OP_SET_SUBJECT:
+			err = db.SetSubject(config_obj, op.Urn, op.Message)
+		case MUTATION_OP_DEL_SUBJECT:
+			err = db.DeleteSubject(config_obj, op.Urn)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeSubject(urn api.DSPathSpec, message proto.Message) ([]byte, error) {
+	if urn.Type() == api.PATH_TYPE_DATASTORE_JSON {
+		return protojson.Marshal(message)
+	}
+	return proto.Marshal(message)
+}
+
+func txnDirectory(config_obj *config_proto.Config) string {
+	return filepath.Join(config_obj.Datastore.Location, TXN_DIRECTORY)
+}
+
+// Durably record the operations in an intent file. Returns the name
+// of the intent file.
+func writeIntentFile(config_obj *config_proto.Config,
+	ops []*TxnOp) (string, error) {
+	if config_obj.Datastore == nil {
+		return "", datastoreNotConfiguredError
+	}
+
+	dir := txnDirectory(config_obj)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%020d_%06d", time.Now().UnixNano(),
+		atomic.AddUint64(&txn_id, 1)%1000000)
+	tmp_filename := filepath.Join(dir, name+".tmp")
+	filename := filepath.Join(dir, name+TXN_EXTENSION)
+
+	fd, err := os.OpenFile(tmp_filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	writer := bufio.NewWriter(fd)
+	for _, op := range ops {
+		data, err := encodeContent(config_obj, op.Urn, op.Data)
+		if err != nil {
+			fd.Close()
+			os.Remove(tmp_filename)
+			return "", err
+		}
+
+		serialized, err := json.Marshal(&walRecord{
+			Op:       op.Op,
+			Filename: op.Urn.AsDatastoreFilename(config_obj),
+			Data:     data,
+		})
+		if err == nil {
+			serialized = append(serialized, '\n')
+			_, err = writer.Write(serialized)
+		}
+		if err != nil {
+			fd.Close()
+			os.Remove(tmp_filename)
+			return "", err
+		}
+	}
+
+	err = writer.Flush()
+	if err == nil {
+		err = fd.Sync()
+	}
+	fd.Close()
+
+	// The transaction is only committed once the intent file is
+	// renamed into place. A partially written intent file is
+	// ignored.
+	if err == nil {
+		err = os.Rename(tmp_filename, filename)
+	}
+	if err != nil {
+		os.Remove(tmp_filename)
+		return "", err
+	}
+
+	return filename, nil
+}
+
+// Apply all the operations in the intent file then remove it.
+func applyIntentFile(filename string) error {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+
+	records := []*walRecord{}
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(nil, 100*1024*1024)
+	for scanner.Scan() {
+		record := &walRecord{}
+		err := json.Unmarshal(scanner.Bytes(), record)
+		if err != nil {
+			fd.Close()
+			return err
+		}
+		records = append(records, record)
+	}
+	fd.Close()
+
+	err = scanner.Err()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		err := applyWALRecord(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(filename)
+}
+
+// Replay any transactions which were committed but not applied when
+// the server stopped.
+func RecoverTransactions(config_obj *config_proto.Config) error {
+	if config_obj.Datastore == nil {
+		return nil
+	}
+
+	dir := txnDirectory(config_obj)
+	names, err := utils.ReadDirNames(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Transactions are named so they sort in commit order.
+	sort.Strings(names)
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for _, name := range names {
+		filename := filepath.Join(dir, name)
+
+		// Left over from a crash before the transaction was
+		// committed.
+		if strings.HasSuffix(name, ".tmp") {
+			os.Remove(filename)
+			continue
+		}
+
+		if !strings.HasSuffix(name, TXN_EXTENSION) {
+			continue
+		}
+
+		logger.Info("Datastore: Replaying transaction %v", filename)
+		err := applyIntentFile(filename)
+		if err != nil {
+			logger.Error("Datastore: Unable to replay transaction %v: %v",
+				filename, err)
+		}
+	}
+
+	return nil
+}
+
+// Commit a transaction on the filesystem.
+func commitFileTransaction(config_obj *config_proto.Config,
+	ops []*TxnOp) error {
+	filename, err := writeIntentFile(config_obj, ops)
+	if err != nil {
+		return err
+	}
+
+	return applyIntentFile(filename)
+}
+
+func (self *FileBaseDataStore) CommitTransaction(
+	config_obj *config_proto.Config,
+	ops []*TxnOp, completion func()) error {
+
+	defer func() {
+		if completion != nil &&
+			!utils.CompareFuncs(completion, utils.SyncCompleter) {
+			completion()
+		}
+	}()
+
+	return commitFileTransaction(config_obj, ops)
+}
+
+// The cache is updated immediately and the intent file is written
+// synchronously so the transaction is durable when we return. The
+// writer pool then applies it to the filesystem.
+func (self *MemcacheFileDataStore) CommitTransaction(
+	config_obj *config_proto.Config,
+	ops []*TxnOp, completion func()) error {
+
+	if self.ctx == nil {
+		return notInitializedError
+	}
+
+	filename, err := writeIntentFile(config_obj, ops)
+	if err != nil {
+		self.maybeComplete(completion)
+		return err
+	}
+
+	for _, op := range ops {
+		switch op.Op {
+		case MUTATION_OP_SET_SUBJECT:
+			_ = self.cache.SetData(config_obj, op.Urn, op.Data)
+		case MUTATION_OP_DEL_SUBJECT:
+			_ = self.cache.DeleteSubject(config_obj, op.Urn)
+		}
+	}
+
+	var wg sync.WaitGroup
+	mutation := &Mutation{
+		op:             MUTATION_OP_TXN,
+		org_config_obj: config_obj,
+		wg:             &wg,
+		completion:     completion,
+		txn_ops:        ops,
+		txn_filename:   filename,
+	}
+
+	if utils.CompareFuncs(mutation.completion, utils.SyncCompleter) {
+		wg.Add(1)
+		defer wg.Wait()
+		mutation.completion = wg.Done
+	}
+
+	wg.Add(1)
+	if !self.enqueue(mutation) {
+		// The transaction will be replayed on the next start.
+		wg.Done()
+		if mutation.completion != nil {
+			mutation.completion()
+		}
+		return nil
+	}
+
+	if config_obj.Datastore.MemcacheWriteMutationBuffer < 0 {
+		wg.Wait()
+	}
+
+	return nil
+}
+
+func (self *MemcacheFileDataStore) processTransaction(mutation *Mutation) {
+	err := applyIntentFile(mutation.txn_filename)
+	if err != nil {
+		logger := logging.GetLogger(
+			mutation.org_config_obj, &logging.FrontendComponent)
+		logger.Error("MemcacheFileDataStore: Unable to apply transaction %v: %v",
+			mutation.txn_filename, err)
+	}
+
+	for _, op := range mutation.txn_ops {
+		switch op.Op {
+		case MUTATION_OP_SET_SUBJECT:
+			self.invalidateDirCache(mutation.org_config_obj, op.Urn)
+		case MUTATION_OP_DEL_SUBJECT:
+			self.invalidateDirCache(mutation.org_config_obj, op.Urn.Dir())
+		}
+	}
+
+	if mutation.completion != nil {
+		mutation.completion()
+	}
+}
@@ -0,0 +1,360 @@
+// Verify the consistency of a file based datastore.
+
+// Crashes or full disks may leave subjects truncated or corrupted. The
+// datastore itself can not tell a broken subject from a missing one,
+// so this is surfaced as obscure errors in the GUI. Verify() walks the
+// datastore directory and checks that every subject can be parsed and
+// that directory listings agree with the files on disk. Broken entries
+// can optionally be removed or moved into a quarantine directory.
+
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	VERIFY_TRUNCATED   = "truncated"
+	VERIFY_CORRUPT     = "corrupt"
+	VERIFY_UNREACHABLE = "unreachable"
+	VERIFY_OVERSIZED   = "oversized directory"
+	VERIFY_EMPTY_DIR   = "empty directory"
+	VERIFY_WRONG_SHARD = "wrong shard"
+
+	VERIFY_ACTION_NONE        = "none"
+	VERIFY_ACTION_DELETED     = "deleted"
+	VERIFY_ACTION_QUARANTINED = "quarantined"
+	VERIFY_ACTION_FAILED      = "failed"
+)
+
+type VerifyOptions struct {
+	// Remove broken entries from the datastore.
+	Repair bool
+
+	// When repairing, move broken subjects into this directory
+	// instead of deleting them.
+	QuarantineDirectory string
+}
+
+type VerifyIssue struct {
+	Path    string `json:"path"`
+	Problem string `json:"problem"`
+	Action  string `json:"action"`
+	Error   string `json:"error,omitempty"`
+}
+
+type VerifyReport struct {
+	Subjects    int            `json:"subjects"`
+	Directories int            `json:"directories"`
+	Repaired    int            `json:"repaired"`
+	Issues      []*VerifyIssue `json:"issues"`
+}
+
+// Walk the datastore directory and all its shards and check all
+// subjects. Only the datastore files (.db and .json.db) are examined
+// - other files may belong to the filestore which often shares the
+// same directory.
+func Verify(ctx context.Context,
+	config_obj *config_proto.Config,
+	options VerifyOptions) (*VerifyReport, error) {
+
+	if config_obj.Datastore == nil || config_obj.Datastore.Location == "" {
+		return nil, datastoreNotConfiguredError
+	}
+
+	report := &VerifyReport{Issues: []*VerifyIssue{}}
+
+	// Client records may be stored in any of the shards. Shards may
+	// be nested inside each other so each walk skips the others.
+	roots := []string{}
+	for _, location := range path_specs.GetDatastoreShards(config_obj) {
+		roots = append(roots, filepath.Clean(location))
+	}
+
+	var empty_dirs []string
+
+	for _, root := range roots {
+		err := filepath.WalkDir(root,
+			func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					// Shards are created when the first client is
+					// written to them.
+					if path == root && errors.Is(err, os.ErrNotExist) {
+						return filepath.SkipDir
+					}
+					return err
+				}
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+
+				if !d.IsDir() {
+					return nil
+				}
+
+				// Do not descend into the quarantine directory or
+				// other shards.
+				if options.QuarantineDirectory != "" &&
+					path == filepath.Clean(options.QuarantineDirectory) {
+					return filepath.SkipDir
+				}
+
+				if path != root && utils.InString(roots, path) {
+					return filepath.SkipDir
+				}
+
+				report.Directories++
+				empty, err := verifyDirectory(
+					config_obj, options, root, path, report)
+				if err != nil {
+					return err
+				}
+				if empty && path != root {
+					empty_dirs = append(empty_dirs, path)
+				}
+				return nil
+			})
+		if err != nil {
+			return report, err
+		}
+	}
+
+	// Empty directories are harmless but are left behind by
+	// deleted subjects.
+	for i := len(empty_dirs) - 1; i >= 0; i-- {
+		issue := &VerifyIssue{
+			Path:    empty_dirs[i],
+			Problem: VERIFY_EMPTY_DIR,
+			Action:  VERIFY_ACTION_NONE,
+		}
+		if options.Repair {
+			err := os.Remove(empty_dirs[i])
+			if err != nil {
+				issue.Action = VERIFY_ACTION_FAILED
+				issue.Error = err.Error()
+			} else {
+				issue.Action = VERIFY_ACTION_DELETED
+				report.Repaired++
+			}
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	return report, nil
+}
+
+// Check that the subject can be parsed. Returns a description of the
+// problem or "" if the subject is ok.
+func verifySubject(config_obj *config_proto.Config, urn api.DSPathSpec,
+	path string, path_type api.PathType) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return VERIFY_CORRUPT
+	}
+
+	// Encrypted or compressed subjects must be decoded before they can
+	// be checked.
+	data, err = decodeContent(config_obj, urn, data)
+	if err != nil {
+		return VERIFY_CORRUPT
+	}
+
+	switch path_type {
+	case api.PATH_TYPE_DATASTORE_JSON:
+		// SetSubject() never writes an empty JSON object.
+		if len(data) == 0 {
+			return VERIFY_TRUNCATED
+		}
+		if !json.Valid(data) {
+			return VERIFY_CORRUPT
+		}
+
+	case api.PATH_TYPE_DATASTORE_PROTO:
+		// An empty protobuf is valid, but the wire format must
+		// parse completely.
+		if !isValidWireFormat(data) {
+			return VERIFY_CORRUPT
+		}
+	}
+
+	return ""
+}
+
+func isValidWireFormat(data []byte) bool {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return false
+		}
+		data = data[n:]
+
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return false
+		}
+		data = data[n:]
+	}
+	return true
+}
+
+// Check all the subjects in the directory. Each subject must parse
+// and must map back to the same file through its path spec as the
+// datastore does in ListChildren(), otherwise it is listed but can
+// never be read. Returns true if the directory is empty.
+func verifyDirectory(
+	config_obj *config_proto.Config, options VerifyOptions,
+	root, path string, report *VerifyReport) (bool, error) {
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false, err
+	}
+
+	if len(entries) == 0 {
+		return true, nil
+	}
+
+	max_dir_size := int(config_obj.Datastore.MaxDirSize)
+	if max_dir_size == 0 {
+		max_dir_size = 50000
+	}
+
+	// ListChildren() truncates large directories so some children
+	// will not be visible.
+	if len(entries) > max_dir_size {
+		report.Issues = append(report.Issues, &VerifyIssue{
+			Path:    path,
+			Problem: VERIFY_OVERSIZED,
+			Action:  VERIFY_ACTION_NONE,
+		})
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false, err
+	}
+
+	components := []string{}
+	for _, component := range strings.Split(rel, string(os.PathSeparator)) {
+		if component != "" && component != "." {
+			components = append(components,
+				utils.UnsanitizeComponent(component))
+		}
+	}
+	urn := path_specs.NewUnsafeDatastorePath(components...)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path_type, name := api.GetDataStorePathTypeFromExtension(entry.Name())
+		if path_type == api.PATH_TYPE_DATASTORE_UNKNOWN {
+			continue
+		}
+
+		report.Subjects++
+		filename := filepath.Join(path, entry.Name())
+
+		child := urn.AddUnsafeChild(utils.UnsanitizeComponent(name)).
+			SetType(path_type)
+		child_filename := strings.TrimPrefix(
+			child.AsDatastoreFilename(config_obj), WINDOWS_LFN_PREFIX)
+		if filepath.Clean(child_filename) != filename {
+			// The client belongs to another shard. The record is
+			// fine but must be moved by rebalancing so it is never
+			// repaired here.
+			if isInOtherShard(config_obj, root, child) {
+				report.Issues = append(report.Issues, &VerifyIssue{
+					Path:    filename,
+					Problem: VERIFY_WRONG_SHARD,
+					Action:  VERIFY_ACTION_NONE,
+				})
+				continue
+			}
+
+			report.addIssue(options, root, filename, VERIFY_UNREACHABLE)
+			continue
+		}
+
+		problem := verifySubject(config_obj, child, filename, path_type)
+		if problem != "" {
+			report.addIssue(options, root, filename, problem)
+		}
+	}
+
+	return false, nil
+}
+
+func isInOtherShard(config_obj *config_proto.Config,
+	root string, urn api.DSPathSpec) bool {
+	components := urn.Components()
+	if len(components) < 2 || components[0] != "clients" {
+		return false
+	}
+
+	shard := path_specs.GetClientShard(config_obj, components[1])
+	return filepath.Clean(shard) != root
+}
+
+func (self *VerifyReport) addIssue(
+	options VerifyOptions, root, path, problem string) {
+	issue := &VerifyIssue{
+		Path:    path,
+		Problem: problem,
+		Action:  VERIFY_ACTION_NONE,
+	}
+	self.Issues = append(self.Issues, issue)
+
+	if !options.Repair {
+		return
+	}
+
+	var err error
+	if options.QuarantineDirectory != "" {
+		err = quarantineFile(options.QuarantineDirectory, root, path)
+		issue.Action = VERIFY_ACTION_QUARANTINED
+	} else {
+		err = os.Remove(path)
+		issue.Action = VERIFY_ACTION_DELETED
+	}
+
+	if err != nil {
+		issue.Action = VERIFY_ACTION_FAILED
+		issue.Error = err.Error()
+		return
+	}
+	self.Repaired++
+}
+
+// Move the file into the quarantine directory, preserving its
+// location relative to the datastore root.
+func quarantineFile(quarantine, root, path string) error {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+
+	dest := filepath.Join(quarantine, rel)
+	err = os.MkdirAll(filepath.Dir(dest), 0700)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(path, dest)
+}
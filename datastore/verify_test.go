@@ -0,0 +1,96 @@
+package datastore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+)
+
+func TestVerify(t *testing.T) {
+	dirname, err := ioutil.TempDir("", "datastore_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dirname)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.FilestoreDirectory = dirname
+	config_obj.Datastore.Location = dirname
+
+	db := &FileBaseDataStore{}
+	ctx := context.Background()
+
+	good := path_specs.NewUnsafeDatastorePath("clients", "C.123", "a\"b")
+	truncated := path_specs.NewUnsafeDatastorePath("clients", "C.123", "truncated")
+	corrupt := path_specs.NewUnsafeDatastorePath("clients", "C.123", "corrupt").
+		SetType(api.PATH_TYPE_DATASTORE_PROTO)
+
+	for _, urn := range []api.DSPathSpec{good, truncated, corrupt} {
+		err := db.SetSubject(config_obj, urn, &api_proto.ClientMetadata{
+			ClientId: "C.123",
+		})
+		require.NoError(t, err)
+	}
+
+	// Simulate a crash during write.
+	require.NoError(t, os.Truncate(truncated.AsDatastoreFilename(config_obj), 0))
+	require.NoError(t, ioutil.WriteFile(
+		corrupt.AsDatastoreFilename(config_obj), []byte{0x0a, 0xff}, 0600))
+
+	// A file with a name the datastore would never produce.
+	unreachable := filepath.Join(dirname, "clients", "C.123", "a%2f.json.db")
+	require.NoError(t, ioutil.WriteFile(unreachable, []byte("{}"), 0600))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dirname, "empty"), 0700))
+
+	// Without repair nothing is changed.
+	report, err := Verify(ctx, config_obj, VerifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Subjects)
+	assert.Equal(t, 0, report.Repaired)
+
+	problems := make(map[string]string)
+	for _, issue := range report.Issues {
+		rel, _ := filepath.Rel(dirname, issue.Path)
+		problems[rel] = issue.Problem
+		assert.Equal(t, VERIFY_ACTION_NONE, issue.Action)
+	}
+	assert.Equal(t, map[string]string{
+		"clients/C.123/truncated.json.db": VERIFY_TRUNCATED,
+		"clients/C.123/corrupt.db":        VERIFY_CORRUPT,
+		"clients/C.123/a%2f.json.db":      VERIFY_UNREACHABLE,
+		"empty":                           VERIFY_EMPTY_DIR,
+	}, problems)
+
+	// Repair by moving the broken subjects into quarantine.
+	quarantine := filepath.Join(dirname, "quarantine")
+	report, err = Verify(ctx, config_obj, VerifyOptions{
+		Repair:              true,
+		QuarantineDirectory: quarantine,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Repaired)
+
+	_, err = os.Stat(filepath.Join(
+		quarantine, "clients", "C.123", "truncated.json.db"))
+	assert.NoError(t, err)
+
+	// The datastore is now clean.
+	report, err = Verify(ctx, config_obj, VerifyOptions{
+		QuarantineDirectory: quarantine,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Subjects)
+	assert.Equal(t, 0, len(report.Issues))
+
+	record := &api_proto.ClientMetadata{}
+	require.NoError(t, db.GetSubject(config_obj, good, record))
+	assert.Equal(t, "C.123", record.ClientId)
+}
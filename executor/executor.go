@@ -64,6 +64,9 @@ type ClientExecutor struct {
 	config_obj *config_proto.Config
 
 	concurrency *utils.Concurrency
+
+	// Shares the outbound channel between concurrent collections.
+	scheduler *responder.Scheduler
 }
 
 func (self *ClientExecutor) ClientId() string {
@@ -141,7 +144,8 @@ func (self *ClientExecutor) processRequestPlugin(
 	// Handle the requests. This used to be a plugin registration
 	// process but there are very few plugins any more and so it
 	// is easier to hard code this.
-	responder_obj := responder.NewResponder(ctx, config_obj, req, self.Outbound)
+	responder_obj := responder.NewScheduledResponder(
+		ctx, config_obj, req, self.scheduler)
 	defer responder_obj.Close(ctx)
 
 	if req.VQLClientAction != nil {
@@ -205,6 +209,7 @@ func NewClientExecutor(
 		concurrency: utils.NewConcurrencyControl(level, time.Hour),
 		config_obj:  config_obj,
 	}
+	result.scheduler = responder.NewScheduler(ctx, result.Outbound)
 
 	// Drain messages from server and execute them, pushing
 	// results to the output channel.
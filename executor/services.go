@@ -2,17 +2,37 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"www.velocidex.com/golang/velociraptor/actions"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	"www.velocidex.com/golang/velociraptor/config"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/constants"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/responder"
 )
 
+// Tables cached by older servers are not signed and are started as
+// before. A table carrying a signature which does not verify was
+// modified after the server sent it, so we wait for the server to
+// send a fresh one instead.
+func verifyCachedEventTable(
+	config_obj *config_proto.Config,
+	table *actions_proto.VQLEventTable) error {
+	err := crypto_utils.VerifyEventTable(config_obj, table)
+	if errors.Is(err, crypto_utils.EventTableNotSigned) {
+		logger := logging.GetLogger(config_obj, &logging.ClientComponent)
+		logger.Info("Starting unsigned cached event queries version %v",
+			table.Version)
+		return nil
+	}
+	return err
+}
+
 func StartEventTableService(
 	ctx context.Context,
 	wg *sync.WaitGroup,
@@ -30,10 +50,17 @@ func StartEventTableService(
 
 	actions.InitializeEventTable(ctx, config_obj, output_chan, wg)
 
+	// Start the event queries cached in the writeback right away
+	// so monitoring does not wait for the server.
 	writeback, _ := config.GetWriteback(config_obj.Client)
 	if writeback != nil && writeback.EventQueries != nil {
-		actions.UpdateEventTable{}.Run(config_obj, ctx,
-			responder, writeback.EventQueries)
+		err := verifyCachedEventTable(config_obj, writeback.EventQueries)
+		if err != nil {
+			logger.Error("Not starting cached event queries: %v", err)
+		} else {
+			actions.UpdateEventTable{}.Run(config_obj, ctx,
+				responder, writeback.EventQueries)
+		}
 	}
 
 	logger.Info("<green>Starting</> event query service with version %v.",
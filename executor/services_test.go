@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/actions"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/crypto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+)
+
+func getEventTableTestConfig(t *testing.T) *config_proto.Config {
+	config_obj := config.GetDefaultConfig()
+
+	ca_bundle, err := crypto.GenerateCACert(2048)
+	require.NoError(t, err)
+	config_obj.Client.CaCertificate = ca_bundle.Cert
+	config_obj.CA.PrivateKey = ca_bundle.PrivateKey
+
+	frontend_cert, err := crypto.GenerateServerCert(
+		config_obj, config_obj.Client.PinnedServerName)
+	require.NoError(t, err)
+	config_obj.Frontend.Certificate = frontend_cert.Cert
+	config_obj.Frontend.PrivateKey = frontend_cert.PrivateKey
+
+	writeback := filepath.Join(t.TempDir(), "writeback.yaml")
+	config_obj.Client.WritebackLinux = writeback
+	config_obj.Client.WritebackDarwin = writeback
+	config_obj.Client.WritebackWindows = writeback
+
+	return config_obj
+}
+
+// Start the event table service with the table cached in the
+// writeback and return the version it started.
+func startCachedEventTable(t *testing.T,
+	config_obj *config_proto.Config,
+	table *actions_proto.VQLEventTable) uint64 {
+	require.NoError(t, config.UpdateWriteback(config_obj.Client,
+		&config_proto.Writeback{EventQueries: table}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	output_chan := make(chan *crypto_proto.VeloMessage, 100)
+	require.NoError(t, StartEventTableService(ctx, wg, config_obj, output_chan))
+
+	return actions.GlobalEventTableVersion()
+}
+
+func TestStartCachedEventTable(t *testing.T) {
+	config_obj := getEventTableTestConfig(t)
+
+	table := &actions_proto.VQLEventTable{
+		Version: 10,
+		Event: []*actions_proto.VQLCollectorArgs{{
+			Query: []*actions_proto.VQLRequest{{
+				Name: "Generic.Client.Stats",
+				VQL:  "SELECT * FROM info()",
+			}},
+		}},
+	}
+
+	// Tables cached by older servers are not signed but still start.
+	assert.Equal(t, uint64(10), startCachedEventTable(t, config_obj, table))
+
+	// Signed tables start.
+	table.Version = 11
+	require.NoError(t, crypto_utils.SignEventTable(config_obj, table))
+	assert.Equal(t, uint64(11), startCachedEventTable(t, config_obj, table))
+
+	// A table modified after it was signed does not start.
+	table.Version = 12
+	table.Event[0].Query[0].VQL = "SELECT * FROM execve(argv='id')"
+	assert.Equal(t, uint64(0), startCachedEventTable(t, config_obj, table))
+
+	// Neither does a table signed by someone other than the server.
+	other_cert, err := crypto.GenerateServerCert(config_obj, "C.1234")
+	require.NoError(t, err)
+	config_obj.Frontend.Certificate = other_cert.Cert
+	config_obj.Frontend.PrivateKey = other_cert.PrivateKey
+
+	require.NoError(t, crypto_utils.SignEventTable(config_obj, table))
+	assert.Equal(t, uint64(0), startCachedEventTable(t, config_obj, table))
+}
@@ -38,6 +38,12 @@ func GetExtensionForFilestore(path_spec FSPathSpec) string {
 	case PATH_TYPE_FILESTORE_JSON_TIME_INDEX:
 		return ".json.tidx"
 
+	case PATH_TYPE_FILESTORE_JSON_TYPES:
+		return ".json.types"
+
+	case PATH_TYPE_FILESTORE_JSON_SCHEMA:
+		return ".json.schema"
+
 	case PATH_TYPE_FILESTORE_SPARSE_IDX:
 		return ".idx"
 
@@ -94,6 +100,14 @@ func GetFileStorePathTypeFromExtension(name string) (PathType, string) {
 		return PATH_TYPE_FILESTORE_JSON_TIME_INDEX, name[:len(name)-10]
 	}
 
+	if strings.HasSuffix(name, ".json.types") {
+		return PATH_TYPE_FILESTORE_JSON_TYPES, name[:len(name)-11]
+	}
+
+	if strings.HasSuffix(name, ".json.schema") {
+		return PATH_TYPE_FILESTORE_JSON_SCHEMA, name[:len(name)-12]
+	}
+
 	if strings.HasSuffix(name, ".json.db") {
 		return PATH_TYPE_FILESTORE_DB_JSON, name[:len(name)-8]
 	}
@@ -106,6 +106,12 @@ const (
 
 	// Arbitrary extensions.
 	PATH_TYPE_FILESTORE_ANY
+
+	// Column types of a result set.
+	PATH_TYPE_FILESTORE_JSON_TYPES
+
+	// Schema version of a result set.
+	PATH_TYPE_FILESTORE_JSON_SCHEMA
 )
 
 type _PathSpec interface {
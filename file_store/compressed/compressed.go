@@ -0,0 +1,537 @@
+// A file store which transparently compresses files.
+
+// Large result sets (e.g. from big hunts) compress very well but
+// readers need to seek into them to page through the rows. The
+// compressed file store wraps the regular file store and writes
+// files of the configured path types in independently compressed
+// chunks (see format.go). Reads and seeks use uncompressed offsets
+// so the result set indexes work unchanged.
+
+// Files are detected as compressed when they are read, so files
+// written before compression was enabled (or of path types no
+// longer configured) are still read as is. Appending to an existing
+// uncompressed file keeps it uncompressed until it is truncated.
+
+package compressed
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ttlcache/v2"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	defaultChunkSize = 64 * 1024
+)
+
+func IsEnabled(config_obj *config_proto.Config) bool {
+	return config_obj.Datastore != nil &&
+		len(config_obj.Datastore.CompressedPathTypes) > 0
+}
+
+type CompressedFileStore struct {
+	api.FileStore
+
+	chunk_size int
+	path_types map[string]bool
+
+	// Sizes of recently written files so appending to them does not
+	// need to index every chunk.
+	sizes *ttlcache.Cache
+}
+
+type fileSize struct {
+	compressed   int64
+	uncompressed int64
+}
+
+func NewCompressedFileStore(
+	config_obj *config_proto.Config, delegate api.FileStore) *CompressedFileStore {
+	result := &CompressedFileStore{
+		FileStore:  delegate,
+		chunk_size: int(config_obj.Datastore.CompressionChunkSize),
+		path_types: make(map[string]bool),
+		sizes:      ttlcache.NewCache(),
+	}
+
+	result.sizes.SetCacheSizeLimit(10000)
+	result.sizes.SetTTL(10 * time.Minute)
+
+	if result.chunk_size <= 0 {
+		result.chunk_size = defaultChunkSize
+	}
+
+	if result.chunk_size > maxChunkSize {
+		result.chunk_size = maxChunkSize
+	}
+
+	for _, path_type := range config_obj.Datastore.CompressedPathTypes {
+		result.path_types[strings.TrimPrefix(path_type, ".")] = true
+	}
+
+	return result
+}
+
+func extension(path api.FSPathSpec) string {
+	return strings.TrimPrefix(api.GetExtensionForFilestore(path), ".")
+}
+
+// Uploads and other untyped files are never touched since their
+// content is arbitrary.
+func (self *CompressedFileStore) mayBeCompressed(path api.FSPathSpec) bool {
+	return path.Type() != api.PATH_TYPE_FILESTORE_ANY
+}
+
+func (self *CompressedFileStore) shouldCompress(path api.FSPathSpec) bool {
+	return self.mayBeCompressed(path) && self.path_types[extension(path)]
+}
+
+func (self *CompressedFileStore) ReadFile(
+	path api.FSPathSpec) (api.FileReader, error) {
+	fd, err := self.FileStore.ReadFile(path)
+	if err != nil || !self.mayBeCompressed(path) {
+		return fd, err
+	}
+
+	return newReader(fd)
+}
+
+func (self *CompressedFileStore) StatFile(
+	path api.FSPathSpec) (api.FileInfo, error) {
+	info, err := self.FileStore.StatFile(path)
+	if err != nil || !self.mayBeCompressed(path) ||
+		info.IsDir() || info.Size() == 0 {
+		return info, err
+	}
+
+	// Report the uncompressed size.
+	fd, err := self.ReadFile(path)
+	if err != nil {
+		return info, nil
+	}
+	defer fd.Close()
+
+	return fd.Stat()
+}
+
+func (self *CompressedFileStore) WriteFile(
+	path api.FSPathSpec) (api.FileWriter, error) {
+	return self.WriteFileWithCompletion(path, utils.BackgroundWriter)
+}
+
+func (self *CompressedFileStore) WriteFileWithCompletion(
+	path api.FSPathSpec, completion func()) (api.FileWriter, error) {
+	if !self.shouldCompress(path) {
+		return self.FileStore.WriteFileWithCompletion(path, completion)
+	}
+
+	result := &CompressedWriter{
+		chunk_size: self.chunk_size,
+		key:        path.AsClientPath(),
+		sizes:      self.sizes,
+	}
+	err := self.getSize(path, result)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := self.FileStore.WriteFileWithCompletion(path, completion)
+	if err != nil {
+		return nil, err
+	}
+	result.fd = fd
+
+	return result, nil
+}
+
+// Find out how much data is already in the file so we can append to
+// it. The size recorded when the file was last closed is used if the
+// file was not changed since, otherwise the file is indexed.
+func (self *CompressedFileStore) getSize(
+	path api.FSPathSpec, writer *CompressedWriter) error {
+	stat, err := self.FileStore.StatFile(path)
+	if err != nil || stat.Size() == 0 {
+		return nil
+	}
+
+	cached, err := self.sizes.Get(writer.key)
+	if err == nil {
+		size := cached.(*fileSize)
+		if size.compressed == stat.Size() {
+			writer.compressed_size = size.compressed
+			writer.size = size.uncompressed
+			return nil
+		}
+	}
+
+	reader, err := self.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	defer reader.Close()
+
+	compressed_reader, ok := reader.(*CompressedReader)
+	if ok {
+		writer.compressed_size = compressed_reader.next_offset
+		writer.size = compressed_reader.size
+	} else {
+		stat, err := reader.Stat()
+		writer.passthrough = err != nil || stat.Size() > 0
+	}
+	return nil
+}
+
+func (self *CompressedFileStore) Delete(path api.FSPathSpec) error {
+	_ = self.sizes.Remove(path.AsClientPath())
+	return self.FileStore.Delete(path)
+}
+
+func (self *CompressedFileStore) Move(src, dest api.FSPathSpec) error {
+	_ = self.sizes.Remove(src.AsClientPath())
+	_ = self.sizes.Remove(dest.AsClientPath())
+	return self.FileStore.Move(src, dest)
+}
+
+func (self *CompressedFileStore) Flush() {
+	flusher, ok := self.FileStore.(api.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+type CompressedWriter struct {
+	mu sync.Mutex
+
+	fd         api.FileWriter
+	chunk_size int
+
+	// Uncompressed data not written yet.
+	buffer []byte
+
+	// The uncompressed size of all the chunks in the file.
+	size int64
+
+	// The size of the file on disk.
+	compressed_size int64
+
+	key   string
+	sizes *ttlcache.Cache
+
+	// Appending to an uncompressed file.
+	passthrough bool
+}
+
+func (self *CompressedWriter) Size() (int64, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.passthrough {
+		return self.fd.Size()
+	}
+	return self.size + int64(len(self.buffer)), nil
+}
+
+func (self *CompressedWriter) Write(data []byte) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.passthrough {
+		return self.fd.Write(data)
+	}
+
+	self.buffer = append(self.buffer, data...)
+	if len(self.buffer) < self.chunk_size {
+		return len(data), nil
+	}
+
+	offset := 0
+	for len(self.buffer)-offset >= self.chunk_size {
+		err := self.writeChunk(self.buffer[offset : offset+self.chunk_size])
+		if err != nil {
+			return 0, err
+		}
+		offset += self.chunk_size
+	}
+
+	self.buffer = append([]byte{}, self.buffer[offset:]...)
+	return len(data), nil
+}
+
+func (self *CompressedWriter) writeChunk(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	chunk, err := compressChunk(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.fd.Write(chunk)
+	if err != nil {
+		return err
+	}
+
+	self.compressed_size += int64(len(chunk))
+	self.size += int64(len(data))
+	return nil
+}
+
+func (self *CompressedWriter) flushBuffer() error {
+	err := self.writeChunk(self.buffer)
+	self.buffer = nil
+	return err
+}
+
+func (self *CompressedWriter) Truncate() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.buffer = nil
+	self.size = 0
+	self.compressed_size = 0
+	self.passthrough = false
+
+	return self.fd.Truncate()
+}
+
+func (self *CompressedWriter) Flush() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	err := self.flushBuffer()
+	if err != nil {
+		return err
+	}
+	return self.fd.Flush()
+}
+
+func (self *CompressedWriter) Close() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	err := self.flushBuffer()
+	close_err := self.fd.Close()
+	if err == nil {
+		err = close_err
+	}
+
+	if err != nil || self.passthrough {
+		_ = self.sizes.Remove(self.key)
+		return err
+	}
+
+	_ = self.sizes.Set(self.key, &fileSize{
+		compressed:   self.compressed_size,
+		uncompressed: self.size,
+	})
+	return nil
+}
+
+type chunkInfo struct {
+	*chunkHeader
+
+	// Offset of the chunk in the compressed file.
+	offset int64
+
+	// Offset of the chunk's data in the uncompressed file.
+	start int64
+}
+
+type CompressedReader struct {
+	fd api.FileReader
+
+	chunks []*chunkInfo
+
+	// Offset of the next chunk to index in the compressed file.
+	next_offset int64
+
+	// Uncompressed size of all indexed chunks.
+	size int64
+
+	// Current uncompressed offset.
+	offset int64
+
+	// The last chunk we decompressed.
+	cached_idx  int
+	cached_data []byte
+}
+
+// Returns a reader for the uncompressed data if fd is compressed,
+// otherwise returns fd.
+func newReader(fd api.FileReader) (api.FileReader, error) {
+	header := make([]byte, headerSize)
+	_, err := io.ReadFull(fd, header)
+	if err != nil || !isCompressed(header) {
+		_, err = fd.Seek(0, io.SeekStart)
+		if err != nil {
+			fd.Close()
+			return nil, err
+		}
+		return fd, nil
+	}
+
+	result := &CompressedReader{fd: fd, cached_idx: -1}
+	err = result.index()
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	return result, nil
+}
+
+// Index any chunks added since we last looked at the file.
+func (self *CompressedReader) index() error {
+	header := make([]byte, headerSize)
+	for {
+		_, err := self.fd.Seek(self.next_offset, io.SeekStart)
+		if err != nil {
+			return err
+		}
+
+		// The last chunk may still be written.
+		_, err = io.ReadFull(self.fd, header)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		parsed, err := parseHeader(header)
+		if err != nil {
+			return err
+		}
+
+		self.chunks = append(self.chunks, &chunkInfo{
+			chunkHeader: parsed,
+			offset:      self.next_offset,
+			start:       self.size,
+		})
+		self.next_offset += parsed.compressed_size
+		self.size += parsed.uncompressed_size
+	}
+}
+
+// Returns the uncompressed data of the chunk containing offset.
+func (self *CompressedReader) getChunk(offset int64) (*chunkInfo, []byte, error) {
+	idx := sort.Search(len(self.chunks), func(i int) bool {
+		chunk := self.chunks[i]
+		return chunk.start+chunk.uncompressed_size > offset
+	})
+	if idx >= len(self.chunks) {
+		return nil, nil, io.EOF
+	}
+
+	chunk := self.chunks[idx]
+	if idx == self.cached_idx {
+		return chunk, self.cached_data, nil
+	}
+
+	_, err := self.fd.Seek(chunk.offset, io.SeekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	compressed := make([]byte, chunk.compressed_size)
+	_, err = io.ReadFull(self.fd, compressed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := decompressChunk(compressed, chunk.chunkHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	self.cached_idx = idx
+	self.cached_data = data
+	return chunk, data, nil
+}
+
+func (self *CompressedReader) Read(buff []byte) (int, error) {
+	// The file may have grown since we indexed it.
+	if self.offset >= self.size {
+		err := self.index()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := 0
+	for n < len(buff) && self.offset < self.size {
+		chunk, data, err := self.getChunk(self.offset)
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		copied := copy(buff[n:], data[self.offset-chunk.start:])
+		n += copied
+		self.offset += int64(copied)
+	}
+
+	if n == 0 && len(buff) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+func (self *CompressedReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		self.offset = offset
+
+	case io.SeekCurrent:
+		self.offset += offset
+
+	case io.SeekEnd:
+		err := self.index()
+		if err != nil {
+			return 0, err
+		}
+		self.offset = self.size + offset
+	}
+
+	if self.offset < 0 {
+		self.offset = 0
+	}
+	return self.offset, nil
+}
+
+func (self *CompressedReader) Stat() (api.FileInfo, error) {
+	info, err := self.fd.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	err = self.index()
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileInfo{FileInfo: info, size: self.size}, nil
+}
+
+func (self *CompressedReader) Close() error {
+	return self.fd.Close()
+}
+
+// Reports the uncompressed size.
+type fileInfo struct {
+	api.FileInfo
+	size int64
+}
+
+func (self *fileInfo) Size() int64 {
+	return self.size
+}
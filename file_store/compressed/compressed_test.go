@@ -0,0 +1,240 @@
+package compressed
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/tests"
+)
+
+func newTestConfig(t *testing.T) *config_proto.Config {
+	dir, err := ioutil.TempDir("", "compressed_file_store_test")
+	assert.NoError(t, err)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.Implementation = "FileBaseDataStore"
+	config_obj.Datastore.Location = dir
+	config_obj.Datastore.FilestoreDirectory = dir
+	config_obj.Datastore.CompressedPathTypes = []string{"json"}
+
+	// Small chunks so reads cross many chunks.
+	config_obj.Datastore.CompressionChunkSize = 4
+	return config_obj
+}
+
+type CompressedTestSuite struct {
+	suite.Suite
+
+	config_obj *config_proto.Config
+	delegate   *directory.DirectoryFileStore
+	file_store *CompressedFileStore
+}
+
+func (self *CompressedTestSuite) SetupTest() {
+	self.config_obj = newTestConfig(self.T())
+	self.delegate = directory.NewDirectoryFileStore(self.config_obj)
+	self.file_store = NewCompressedFileStore(self.config_obj, self.delegate)
+}
+
+func (self *CompressedTestSuite) TearDownTest() {
+	os.RemoveAll(self.config_obj.Datastore.Location)
+}
+
+func (self *CompressedTestSuite) writeFile(
+	file_store api.FileStore, path_spec api.FSPathSpec, data string) {
+	fd, err := file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte(data))
+	assert.NoError(self.T(), err)
+	fd.Close()
+}
+
+func (self *CompressedTestSuite) readAll(
+	file_store api.FileStore, path_spec api.FSPathSpec) string {
+	reader, err := file_store.ReadFile(path_spec)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(self.T(), err)
+	return string(data)
+}
+
+func (self *CompressedTestSuite) osPath(path_spec api.FSPathSpec) string {
+	return path_spec.AsFilestoreFilename(self.config_obj)
+}
+
+// Compressed files are plain gzip files.
+func (self *CompressedTestSuite) TestFormat() {
+	path_spec := path_specs.NewSafeFilestorePath("test", "rows")
+	self.writeFile(self.file_store, path_spec, "Hello world\n")
+	self.writeFile(self.file_store, path_spec, "Goodbye\n")
+
+	fd, err := os.Open(self.osPath(path_spec))
+	assert.NoError(self.T(), err)
+	defer fd.Close()
+
+	reader, err := gzip.NewReader(fd)
+	assert.NoError(self.T(), err)
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Hello world\nGoodbye\n", string(data))
+
+	// The raw file is different.
+	assert.NotEqual(self.T(), "Hello world\nGoodbye\n",
+		self.readAll(self.delegate, path_spec))
+
+	// Stat reports the uncompressed size.
+	stat, err := self.file_store.StatFile(path_spec)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(20), stat.Size())
+}
+
+func (self *CompressedTestSuite) TestPathTypes() {
+	// Only the configured types are compressed.
+	csv_file := path_specs.NewSafeFilestorePath("test", "rows").
+		SetType(api.PATH_TYPE_FILESTORE_CSV)
+	self.writeFile(self.file_store, csv_file, "a,b\n")
+	assert.Equal(self.T(), "a,b\n", self.readAll(self.delegate, csv_file))
+
+	// Uploads are never compressed or decompressed - even when
+	// they happen to look like compressed files.
+	chunk, err := compressChunk([]byte("data"))
+	assert.NoError(self.T(), err)
+
+	upload := path_specs.NewSafeFilestorePath("test", "upload").
+		SetType(api.PATH_TYPE_FILESTORE_ANY)
+	self.writeFile(self.delegate, upload, string(chunk))
+	assert.Equal(self.T(), string(chunk), self.readAll(self.file_store, upload))
+}
+
+// Files written before compression was enabled are still readable
+// and remain uncompressed until truncated.
+func (self *CompressedTestSuite) TestExistingFiles() {
+	path_spec := path_specs.NewSafeFilestorePath("test", "old")
+	self.writeFile(self.delegate, path_spec, "Old data\n")
+	self.writeFile(self.file_store, path_spec, "New data\n")
+
+	assert.Equal(self.T(), "Old data\nNew data\n",
+		self.readAll(self.delegate, path_spec))
+	assert.Equal(self.T(), "Old data\nNew data\n",
+		self.readAll(self.file_store, path_spec))
+
+	fd, err := self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	size, err := fd.Size()
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(18), size)
+
+	assert.NoError(self.T(), fd.Truncate())
+	_, err = fd.Write([]byte("Truncated\n"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	assert.Equal(self.T(), "Truncated\n",
+		self.readAll(self.file_store, path_spec))
+	assert.NotEqual(self.T(), "Truncated\n",
+		self.readAll(self.delegate, path_spec))
+}
+
+func (self *CompressedTestSuite) TestSeek() {
+	path_spec := path_specs.NewSafeFilestorePath("test", "seek")
+	lines := []string{}
+	for i := 0; i < 1000; i++ {
+		lines = append(lines, fmt.Sprintf("Line %04d\n", i))
+	}
+	self.writeFile(self.file_store, path_spec, strings.Join(lines, ""))
+
+	reader, err := self.file_store.ReadFile(path_spec)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	buff := make([]byte, 10)
+	for _, i := range []int{500, 3, 999, 0, 501} {
+		_, err = reader.Seek(int64(i*10), io.SeekStart)
+		assert.NoError(self.T(), err)
+
+		_, err = io.ReadFull(reader, buff)
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), lines[i], string(buff))
+	}
+
+	offset, err := reader.Seek(-10, io.SeekEnd)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(9990), offset)
+}
+
+// Appending to a file uses the size recorded when it was closed
+// unless the file was changed since.
+func (self *CompressedTestSuite) TestAppendSize() {
+	path_spec := path_specs.NewSafeFilestorePath("test", "append")
+	self.writeFile(self.file_store, path_spec, "Hello ")
+
+	cached, err := self.file_store.sizes.Get(path_spec.AsClientPath())
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(6), cached.(*fileSize).uncompressed)
+
+	self.writeFile(self.file_store, path_spec, "world\n")
+	assert.Equal(self.T(), "Hello world\n",
+		self.readAll(self.file_store, path_spec))
+
+	// Append a chunk behind our back - the file is indexed again.
+	chunk, err := compressChunk([]byte("More\n"))
+	assert.NoError(self.T(), err)
+	self.writeFile(self.delegate, path_spec, string(chunk))
+
+	fd, err := self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	size, err := fd.Size()
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(17), size)
+	fd.Close()
+
+	// Deleting the file forgets its size.
+	assert.NoError(self.T(), self.file_store.Delete(path_spec))
+	_, err = self.file_store.sizes.Get(path_spec.AsClientPath())
+	assert.Error(self.T(), err)
+}
+
+func TestCompressedFileStore(t *testing.T) {
+	suite.Run(t, &CompressedTestSuite{})
+}
+
+func TestCompressedFileStoreAPI(t *testing.T) {
+	config_obj := newTestConfig(t)
+	defer os.RemoveAll(config_obj.Datastore.Location)
+
+	file_store := NewCompressedFileStore(config_obj,
+		directory.NewDirectoryFileStore(config_obj))
+
+	suite.Run(t, tests.NewFileStoreTestSuite(config_obj, file_store))
+}
+
+func TestChunkHeader(t *testing.T) {
+	chunk, err := compressChunk([]byte("hello"))
+	assert.NoError(t, err)
+
+	header, err := parseHeader(chunk)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(chunk)), header.compressed_size)
+	assert.Equal(t, int64(5), header.uncompressed_size)
+
+	data, err := decompressChunk(chunk, header)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	assert.False(t, isCompressed([]byte("hello world this is not gzip")))
+}
@@ -0,0 +1,117 @@
+package compressed
+
+// Compressed files are a series of gzip members (chunks), each
+// compressing up to chunk_size bytes of the file independently. A
+// series of gzip members is itself a valid gzip file so compressed
+// files can still be read with standard tools (e.g. zcat).
+
+// To allow seeking, every member carries a gzip extra field (RFC
+// 1952 section 2.3.1.1) with subfield id "VR" holding the compressed
+// size of the member and the uncompressed size of its data. Readers
+// index the file by skipping from header to header without
+// decompressing anything:
+
+//   0  1f 8b 08 04    magic, deflate, FEXTRA
+//   4  mtime, xfl, os
+//  10  xlen = 12
+//  12  'V' 'R' len = 8
+//  16  compressed size of the member (uint32 LE)
+//  20  uncompressed size of the data (uint32 LE)
+//  24  deflate data, crc32, isize
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	headerSize = 24
+
+	// Chunks larger than this could overflow the header fields.
+	maxChunkSize = 16 * 1024 * 1024
+)
+
+var (
+	invalidChunkError = errors.New("Invalid compressed chunk")
+)
+
+type chunkHeader struct {
+	compressed_size   int64
+	uncompressed_size int64
+}
+
+func parseHeader(header []byte) (*chunkHeader, error) {
+	if len(header) < headerSize ||
+		header[0] != 0x1f || header[1] != 0x8b || header[2] != 8 ||
+		header[3]&0x04 == 0 ||
+		binary.LittleEndian.Uint16(header[10:]) != 12 ||
+		header[12] != 'V' || header[13] != 'R' ||
+		binary.LittleEndian.Uint16(header[14:]) != 8 {
+		return nil, invalidChunkError
+	}
+
+	result := &chunkHeader{
+		compressed_size:   int64(binary.LittleEndian.Uint32(header[16:])),
+		uncompressed_size: int64(binary.LittleEndian.Uint32(header[20:])),
+	}
+	if result.compressed_size < headerSize {
+		return nil, invalidChunkError
+	}
+	return result, nil
+}
+
+// Is the start of the file a compressed chunk?
+func isCompressed(header []byte) bool {
+	_, err := parseHeader(header)
+	return err == nil
+}
+
+func compressChunk(data []byte) ([]byte, error) {
+	if len(data) > maxChunkSize {
+		return nil, fmt.Errorf("Chunk too large: %v", len(data))
+	}
+
+	buffer := &bytes.Buffer{}
+	writer, err := gzip.NewWriterLevel(buffer, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Placeholder for the sizes - we only know the compressed
+	// size after compressing.
+	writer.Header.Extra = []byte{'V', 'R', 8, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	_, err = writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	result := buffer.Bytes()
+	binary.LittleEndian.PutUint32(result[16:], uint32(len(result)))
+	binary.LittleEndian.PutUint32(result[20:], uint32(len(data)))
+	return result, nil
+}
+
+func decompressChunk(chunk []byte, header *chunkHeader) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(chunk))
+	if err != nil {
+		return nil, err
+	}
+	reader.Multistream(false)
+
+	result := make([]byte, header.uncompressed_size)
+	_, err = io.ReadFull(reader, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
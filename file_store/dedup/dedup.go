@@ -0,0 +1,385 @@
+// Content deduplication for uploads.
+
+// Hunts often collect the same files (e.g. binaries) from many
+// endpoints. Each collection stores its own copy, wasting a lot of
+// disk space. This package stores each distinct upload once in a
+// content addressed store under the filestore's content directory,
+// named by its sha256 hash. The uploads in each collection are
+// replaced by hard links to the stored copy so readers do not need
+// to know about deduplication at all.
+
+// The directory filestore gives a file its own copy before it is
+// written to, so changing one collection's upload never changes the
+// others. A stored copy which is not linked from any collection any
+// more (e.g. because the collections were deleted) is removed by
+// GarbageCollect().
+
+// Uploads are replaced by renaming a new link over them. The link is
+// staged in a temporary directory under the content directory so a
+// link left behind by a crash is never mistaken for an upload. Such
+// links are removed by GarbageCollect() once they are old enough.
+
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+const (
+	defaultMinSize = 4096
+
+	// Staged links older than this were left behind by a crash.
+	staleTmpAge = time.Hour
+)
+
+var (
+	notSupportedError = errors.New(
+		"Deduplication requires hard links and a local filestore")
+)
+
+type Options struct {
+	// Files smaller than this are not deduplicated.
+	MinSize int64
+
+	// Only report what would be done.
+	DryRun bool
+}
+
+type Report struct {
+	Files        int      `json:"files"`
+	Deduplicated int      `json:"deduplicated"`
+	BytesSaved   int64    `json:"bytes_saved"`
+	BlobsRemoved int      `json:"blobs_removed"`
+	Errors       []string `json:"errors"`
+}
+
+func (self *Report) addError(path string, err error) {
+	self.Errors = append(self.Errors, fmt.Sprintf("%v: %v", path, err))
+}
+
+func NewReport() *Report {
+	return &Report{Errors: []string{}}
+}
+
+func DefaultOptions(config_obj *config_proto.Config) Options {
+	min_size := int64(config_obj.Datastore.DedupMinSize)
+	if min_size == 0 {
+		min_size = defaultMinSize
+	}
+	return Options{MinSize: min_size}
+}
+
+// Hard links only work when the filestore is a local directory.
+func checkSupported(config_obj *config_proto.Config) error {
+	if !directory.SupportsHardLinks || config_obj.Datastore == nil ||
+		config_obj.Datastore.FilestoreDirectory == "" {
+		return notSupportedError
+	}
+
+	implementation, err := datastore.GetImplementationName(config_obj)
+	if err != nil {
+		return err
+	}
+
+	switch implementation {
+	case "FileBaseDataStore", "MemcacheFileDataStore":
+		return nil
+	default:
+		return notSupportedError
+	}
+}
+
+// Deduplicate the uploads of all collections.
+func DedupUploads(ctx context.Context,
+	config_obj *config_proto.Config,
+	options Options) (*Report, error) {
+	err := checkSupported(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	report := NewReport()
+	root := paths.CLIENTS_ROOT.AsFilestorePath().
+		SetType(api.PATH_TYPE_FILESTORE_ANY).AsFilestoreFilename(config_obj)
+
+	err = filepath.WalkDir(root,
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			// Uploads are stored in
+			// <client_id>/collections/<flow_id>/uploads/...
+			rel, _ := filepath.Rel(root, path)
+			components := strings.Split(rel, string(filepath.Separator))
+			if len(components) >= 2 && components[1] != "collections" ||
+				len(components) >= 4 && components[3] != "uploads" {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if d.IsDir() || len(components) < 5 {
+				return nil
+			}
+
+			dedupFile(config_obj, path, options, report)
+			return nil
+		})
+	return report, err
+}
+
+// Deduplicate the uploads of a single collection.
+func DedupFlow(ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id, flow_id string,
+	options Options) (*Report, error) {
+	err := checkSupported(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	report := NewReport()
+	root := paths.NewFlowPathManager(client_id, flow_id).
+		UploadsDirectory().AsFilestoreFilename(config_obj)
+
+	err = filepath.WalkDir(root,
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			dedupFile(config_obj, path, options, report)
+			return nil
+		})
+	return report, err
+}
+
+func dedupFile(config_obj *config_proto.Config,
+	path string, options Options, report *Report) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		report.addError(path, err)
+		return
+	}
+
+	if !info.Mode().IsRegular() || info.Size() < options.MinSize {
+		return
+	}
+
+	report.Files++
+
+	// Already shared with other files.
+	if directory.LinkCount(info) > 1 {
+		return
+	}
+
+	hash, err := hashFile(path)
+	if err != nil {
+		report.addError(path, err)
+		return
+	}
+
+	blob_path := blobFilename(config_obj, hash)
+	blob_info, err := os.Lstat(blob_path)
+
+	// This is the first time we see this content - the file becomes
+	// the stored copy.
+	if errors.Is(err, os.ErrNotExist) {
+		if options.DryRun {
+			return
+		}
+
+		err = os.MkdirAll(filepath.Dir(blob_path), 0700)
+		if err == nil {
+			err = os.Link(path, blob_path)
+		}
+		if err != nil {
+			report.addError(path, err)
+		}
+		return
+	}
+
+	if err != nil {
+		report.addError(path, err)
+		return
+	}
+
+	if os.SameFile(info, blob_info) {
+		return
+	}
+
+	// The hash matched but the size did not - the stored copy must
+	// be corrupted so leave the file alone.
+	if blob_info.Size() != info.Size() {
+		report.addError(path, fmt.Errorf(
+			"Stored copy %v has an unexpected size", blob_path))
+		return
+	}
+
+	report.Deduplicated++
+	report.BytesSaved += info.Size()
+	if options.DryRun {
+		return
+	}
+
+	// Replace the file with a link to the stored copy atomically.
+	tmp_path := tmpFilename(config_obj, hash)
+	err = os.MkdirAll(filepath.Dir(tmp_path), 0700)
+	if err == nil {
+		err = os.Link(blob_path, tmp_path)
+	}
+	if err == nil {
+		err = os.Rename(tmp_path, path)
+	}
+	if err != nil {
+		os.Remove(tmp_path)
+		report.Deduplicated--
+		report.BytesSaved -= info.Size()
+		report.addError(path, err)
+	}
+}
+
+// Remove stored copies which are not linked from any upload.
+func GarbageCollect(ctx context.Context,
+	config_obj *config_proto.Config,
+	options Options) (*Report, error) {
+	err := checkSupported(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	report := NewReport()
+	root := paths.DEDUP_CONTENT_ROOT.AsFilestoreFilename(config_obj)
+	tmp_root := paths.DEDUP_TMP_ROOT.AsFilestoreFilename(config_obj)
+
+	removeStaleLinks(tmp_root, options, report)
+
+	err = filepath.WalkDir(root,
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+
+			if d.IsDir() {
+				if path == tmp_root {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				report.addError(path, err)
+				return nil
+			}
+
+			if directory.LinkCount(info) > 1 {
+				return nil
+			}
+
+			report.BlobsRemoved++
+			report.BytesSaved += info.Size()
+			if options.DryRun {
+				return nil
+			}
+
+			err = os.Remove(path)
+			if err != nil {
+				report.addError(path, err)
+			}
+			return nil
+		})
+	return report, err
+}
+
+// Remove links staged by a run which crashed before it could rename
+// them over the upload. Recent links may still be in use by a
+// concurrent run.
+func removeStaleLinks(tmp_root string, options Options, report *Report) {
+	entries, err := os.ReadDir(tmp_root)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() ||
+			now.Sub(info.ModTime()) < staleTmpAge {
+			continue
+		}
+
+		if options.DryRun {
+			continue
+		}
+
+		path := filepath.Join(tmp_root, entry.Name())
+		err = os.Remove(path)
+		if err != nil {
+			report.addError(path, err)
+		}
+	}
+}
+
+func tmpFilename(config_obj *config_proto.Config, hash string) string {
+	return paths.DEDUP_TMP_ROOT.AddChild(
+		fmt.Sprintf("%s.%d", hash, time.Now().UnixNano())).
+		AsFilestoreFilename(config_obj)
+}
+
+func blobFilename(config_obj *config_proto.Config, hash string) string {
+	return paths.DEDUP_CONTENT_ROOT.AddChild(hash[:2], hash).
+		AsFilestoreFilename(config_obj)
+}
+
+func hashFile(path string) (string, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, fd)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,187 @@
+package dedup
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+var (
+	binary = strings.Repeat("MZ binary ", 1000)
+)
+
+type DedupTestSuite struct {
+	suite.Suite
+
+	config_obj *config_proto.Config
+	file_store *directory.DirectoryFileStore
+}
+
+func (self *DedupTestSuite) SetupTest() {
+	if !directory.SupportsHardLinks {
+		self.T().Skip("Hard links not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "dedup_test")
+	assert.NoError(self.T(), err)
+
+	self.config_obj = config.GetDefaultConfig()
+	self.config_obj.Datastore.Implementation = "FileBaseDataStore"
+	self.config_obj.Datastore.Location = dir
+	self.config_obj.Datastore.FilestoreDirectory = dir
+	self.file_store = directory.NewDirectoryFileStore(self.config_obj)
+}
+
+func (self *DedupTestSuite) TearDownTest() {
+	os.RemoveAll(self.config_obj.Datastore.Location)
+}
+
+func (self *DedupTestSuite) upload(client_id, flow_id, data string) api.FSPathSpec {
+	path_spec := paths.NewFlowPathManager(client_id, flow_id).
+		GetUploadsFile("file", "C:/Windows/notepad.exe").Path()
+
+	fd, err := self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte(data))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	return path_spec
+}
+
+func (self *DedupTestSuite) stat(path_spec api.FSPathSpec) os.FileInfo {
+	info, err := os.Lstat(path_spec.AsFilestoreFilename(self.config_obj))
+	assert.NoError(self.T(), err)
+	return info
+}
+
+func (self *DedupTestSuite) read(path_spec api.FSPathSpec) string {
+	data, err := ioutil.ReadFile(path_spec.AsFilestoreFilename(self.config_obj))
+	assert.NoError(self.T(), err)
+	return string(data)
+}
+
+func (self *DedupTestSuite) TestDedupUploads() {
+	first := self.upload("C.1", "F.1", binary)
+	second := self.upload("C.2", "F.2", binary)
+	different := self.upload("C.3", "F.3", binary+"different")
+	small := self.upload("C.4", "F.4", "small")
+
+	ctx := context.Background()
+	options := DefaultOptions(self.config_obj)
+
+	// A dry run changes nothing.
+	options.DryRun = true
+	report, err := DedupUploads(ctx, self.config_obj, options)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, report.Files)
+	assert.False(self.T(), os.SameFile(self.stat(first), self.stat(second)))
+
+	options.DryRun = false
+	report, err = DedupUploads(ctx, self.config_obj, options)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, report.Files)
+	assert.Equal(self.T(), 1, report.Deduplicated)
+	assert.Equal(self.T(), int64(len(binary)), report.BytesSaved)
+	assert.Equal(self.T(), 0, len(report.Errors))
+
+	// Identical uploads share the same file.
+	assert.True(self.T(), os.SameFile(self.stat(first), self.stat(second)))
+	assert.False(self.T(), os.SameFile(self.stat(first), self.stat(different)))
+	assert.Equal(self.T(), uint64(1), directory.LinkCount(self.stat(small)))
+
+	// Running again finds nothing new.
+	report, err = DedupUploads(ctx, self.config_obj, options)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, report.Deduplicated)
+
+	// Writing to one upload does not change the other.
+	fd, err := self.file_store.WriteFile(second)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("appended"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	assert.Equal(self.T(), binary, self.read(first))
+	assert.Equal(self.T(), binary+"appended", self.read(second))
+
+	// Once no upload uses a stored copy it is removed.
+	assert.NoError(self.T(), self.file_store.Delete(first))
+	report, err = GarbageCollect(ctx, self.config_obj, options)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, report.BlobsRemoved)
+}
+
+func (self *DedupTestSuite) TestDedupFlow() {
+	first := self.upload("C.1", "F.1", binary)
+	second := self.upload("C.2", "F.2", binary)
+	third := self.upload("C.3", "F.3", binary)
+
+	ctx := context.Background()
+	options := DefaultOptions(self.config_obj)
+
+	for _, flow := range [][]string{{"C.1", "F.1"}, {"C.2", "F.2"}} {
+		_, err := DedupFlow(ctx, self.config_obj, flow[0], flow[1], options)
+		assert.NoError(self.T(), err)
+	}
+
+	assert.True(self.T(), os.SameFile(self.stat(first), self.stat(second)))
+	assert.False(self.T(), os.SameFile(self.stat(first), self.stat(third)))
+}
+
+// Links left behind by a crash are removed once they are old.
+func (self *DedupTestSuite) TestStaleLinks() {
+	first := self.upload("C.1", "F.1", binary)
+	second := self.upload("C.2", "F.2", binary)
+
+	ctx := context.Background()
+	options := DefaultOptions(self.config_obj)
+
+	_, err := DedupUploads(ctx, self.config_obj, options)
+	assert.NoError(self.T(), err)
+
+	// Simulate a crash after the link was staged.
+	first_filename := first.AsFilestoreFilename(self.config_obj)
+	stale := tmpFilename(self.config_obj, "stale")
+	recent := tmpFilename(self.config_obj, "recent")
+	for _, path := range []string{stale, recent} {
+		assert.NoError(self.T(), os.Link(first_filename, path))
+	}
+
+	old := time.Now().Add(-2 * staleTmpAge)
+	assert.NoError(self.T(), os.Chtimes(stale, old, old))
+
+	// The staged links are not uploads.
+	report, err := DedupUploads(ctx, self.config_obj, options)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, report.Files)
+
+	// Only the stale link is removed.
+	assert.NoError(self.T(), self.file_store.Delete(first))
+	assert.NoError(self.T(), self.file_store.Delete(second))
+	report, err = GarbageCollect(ctx, self.config_obj, options)
+	assert.NoError(self.T(), err)
+
+	_, err = os.Lstat(stale)
+	assert.True(self.T(), os.IsNotExist(err))
+	_, err = os.Lstat(recent)
+	assert.NoError(self.T(), err)
+
+	// The recent link still holds the stored copy.
+	assert.Equal(self.T(), 0, report.BlobsRemoved)
+}
+
+func TestDedup(t *testing.T) {
+	suite.Run(t, &DedupTestSuite{})
+}
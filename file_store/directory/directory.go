@@ -28,6 +28,7 @@ package directory
 */
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -187,6 +188,11 @@ func (self *DirectoryFileStore) WriteFileWithCompletion(
 		return nil, err
 	}
 
+	err = breakHardLink(file_path)
+	if err != nil {
+		return nil, err
+	}
+
 	file, err := os.OpenFile(file_path, os.O_RDWR|os.O_CREATE, 0700)
 	if err != nil {
 		logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
@@ -229,3 +235,34 @@ func (self *DirectoryFileStore) Delete(filename api.FSPathSpec) error {
 	// At least we succeeded deleting the file
 	return nil
 }
+
+// Deduplicated uploads share their data with identical files through
+// hard links. Writing to one of them must not change the others, so
+// the file gets its own copy first.
+func breakHardLink(file_path string) error {
+	info, err := os.Lstat(file_path)
+	if err != nil || LinkCount(info) <= 1 {
+		return nil
+	}
+
+	src, err := os.Open(file_path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp_path := file_path + ".tmp"
+	dest, err := os.OpenFile(tmp_path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0700)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dest, src)
+	dest.Close()
+	if err != nil {
+		os.Remove(tmp_path)
+		return err
+	}
+
+	return os.Rename(tmp_path, file_path)
+}
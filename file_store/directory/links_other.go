@@ -0,0 +1,13 @@
+// +build !linux,!darwin,!freebsd
+
+package directory
+
+import (
+	"os"
+)
+
+const SupportsHardLinks = false
+
+func LinkCount(info os.FileInfo) uint64 {
+	return 1
+}
@@ -0,0 +1,19 @@
+// +build linux darwin freebsd
+
+package directory
+
+import (
+	"os"
+	"syscall"
+)
+
+// Hard link counts are needed to safely share deduplicated files.
+const SupportsHardLinks = true
+
+func LinkCount(info os.FileInfo) uint64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 1
+	}
+	return uint64(stat.Nlink)
+}
@@ -24,9 +24,14 @@ import (
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/compressed"
 	"www.velocidex.com/golang/velociraptor/file_store/directory"
 	"www.velocidex.com/golang/velociraptor/file_store/memcache"
 	"www.velocidex.com/golang/velociraptor/file_store/memory"
+	"www.velocidex.com/golang/velociraptor/file_store/quota"
+	"www.velocidex.com/golang/velociraptor/file_store/s3"
+	"www.velocidex.com/golang/velociraptor/file_store/tiered"
+	"www.velocidex.com/golang/velociraptor/logging"
 )
 
 var (
@@ -57,12 +62,44 @@ func GetFileStore(config_obj *config_proto.Config) api.FileStore {
 		panic(err)
 	}
 
-	res, _ := getImpl(implementation, config_obj)
+	res, err := getImpl(implementation, config_obj)
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("GetFileStore: %v", err)
+
+		// Do not cache the failure so we can try again later.
+		return nil
+	}
 	g_impl[config_obj.OrgId] = res
 	return res
 }
 
 func getImpl(implementation string,
+	config_obj *config_proto.Config) (api.FileStore, error) {
+	impl, err := getBaseImpl(implementation, config_obj)
+	if err != nil {
+		return impl, err
+	}
+
+	// Old files are moved to the cold tier.
+	if tiered.IsEnabled(config_obj) {
+		impl = tiered.NewTieredFileStore(config_obj, impl)
+	}
+
+	// Compress outside the tiers so files are moved compressed.
+	if compressed.IsEnabled(config_obj) {
+		impl = compressed.NewCompressedFileStore(config_obj, impl)
+	}
+
+	// Account uploads before they are compressed.
+	if quota.IsEnabled(config_obj) {
+		impl = quota.NewQuotaFileStore(config_obj, impl)
+	}
+
+	return impl, nil
+}
+
+func getBaseImpl(implementation string,
 	config_obj *config_proto.Config) (api.FileStore, error) {
 	switch implementation {
 	case "Test":
@@ -74,6 +111,14 @@ func getImpl(implementation string,
 	case "FileBaseDataStore", "ReadOnlyDataStore":
 		return directory.NewDirectoryFileStore(config_obj), nil
 
+	case "S3":
+		// Do not return a typed nil on error.
+		store, err := s3.NewS3FileStore(config_obj)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+
 	default:
 		return nil, fmt.Errorf("Unsupported filestore %v", implementation)
 	}
@@ -114,11 +114,7 @@ func (self DSPathSpec) AsClientPath() string {
 
 func (self DSPathSpec) AsDatastoreDirectory(
 	config_obj *config_proto.Config) string {
-	location := ""
-	if config_obj.Datastore != nil {
-		location = config_obj.Datastore.Location
-	}
-
+	location := getDatastoreLocation(config_obj, self.components)
 	if self.is_safe {
 		return self.asSafeDirWithRoot(location)
 	}
@@ -0,0 +1,47 @@
+package path_specs
+
+import (
+	"hash/fnv"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+// All the directories holding the datastore. The first is always
+// the main datastore location.
+func GetDatastoreShards(config_obj *config_proto.Config) []string {
+	if config_obj.Datastore == nil {
+		return []string{""}
+	}
+
+	return append([]string{config_obj.Datastore.Location},
+		config_obj.Datastore.ShardLocations...)
+}
+
+// The datastore directory which holds the client's records. Clients
+// are assigned to shards by a hash of their client id.
+func GetClientShard(config_obj *config_proto.Config, client_id string) string {
+	shards := GetDatastoreShards(config_obj)
+	if len(shards) == 1 {
+		return shards[0]
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(client_id))
+	return shards[h.Sum32()%uint32(len(shards))]
+}
+
+// Only paths within a client's directory are sharded - everything
+// else lives in the main datastore location.
+func getDatastoreLocation(
+	config_obj *config_proto.Config, components []string) string {
+	if config_obj.Datastore == nil {
+		return ""
+	}
+
+	if len(config_obj.Datastore.ShardLocations) == 0 ||
+		len(components) < 2 || components[0] != "clients" {
+		return config_obj.Datastore.Location
+	}
+
+	return GetClientShard(config_obj, components[1])
+}
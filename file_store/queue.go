@@ -31,7 +31,7 @@ func GetQueueManager(config_obj *config_proto.Config) (api.QueueManager, error)
 		return memory.NewMemoryQueueManager(config_obj, file_store), nil
 
 	case "FileBaseDataStore", "MemcacheFileDataStore",
-		"RemoteFileDataStore", "ReadOnlyDataStore":
+		"RemoteFileDataStore", "ReadOnlyDataStore", "S3":
 		return directory.NewDirectoryQueueManager(config_obj, file_store), nil
 
 	default:
@@ -0,0 +1,480 @@
+// This is a file store which enforces upload quotas.
+
+// Each collection's uploads are stored under
+// clients/<client_id>/collections/<flow_id>/uploads/. The quota file
+// store wraps the regular file store and accounts every byte written
+// under such a path to the client, and to the hunt if the collection
+// was scheduled by a hunt.
+
+// Once a client or hunt reached its quota further uploads are
+// refused. Alternatively (truncate_over_quota), the upload which
+// crosses the quota is truncated at the limit. In both cases the
+// writer returns ErrQuotaExceeded so the collection log records the
+// lost data.
+
+// The usage is kept in memory and persisted in the datastore while
+// uploads are written (at most every persist_interval) and when
+// writers are closed so it survives restarts. Deleting or
+// truncating uploads releases their space.
+
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	QUOTA_TYPE_CLIENT = "client"
+	QUOTA_TYPE_HUNT   = "hunt"
+
+	// Bound the cache of flow to hunt mappings.
+	max_cached_flows = 10000
+
+	// Bound the cache of usage records.
+	max_cached_records = 10000
+
+	// How often usage is written to the datastore while uploads
+	// are in progress.
+	persist_interval = 10 * time.Second
+)
+
+var (
+	ErrQuotaExceeded = errors.New("Filestore quota exceeded")
+)
+
+type QuotaFileStore struct {
+	// Protects usage and hunts
+	mu sync.Mutex
+
+	config_obj *config_proto.Config
+	delegate   api.FileStore
+
+	client_quota uint64
+	hunt_quota   uint64
+	truncate     bool
+
+	// Keyed by type/name
+	usage map[string]*api_proto.FilestoreQuotaRecord
+
+	// When each usage record was last written to the datastore.
+	persisted map[string]time.Time
+
+	// Maps client_id/flow_id to the hunt which scheduled the
+	// flow ("" for flows not scheduled by hunts).
+	hunts map[string]string
+
+	Clock utils.Clock
+}
+
+func IsEnabled(config_obj *config_proto.Config) bool {
+	return config_obj.Datastore != nil &&
+		(config_obj.Datastore.ClientUploadQuota > 0 ||
+			config_obj.Datastore.HuntUploadQuota > 0)
+}
+
+func NewQuotaFileStore(
+	config_obj *config_proto.Config, delegate api.FileStore) *QuotaFileStore {
+	return &QuotaFileStore{
+		config_obj:   config_obj,
+		delegate:     delegate,
+		client_quota: config_obj.Datastore.ClientUploadQuota,
+		hunt_quota:   config_obj.Datastore.HuntUploadQuota,
+		truncate:     config_obj.Datastore.TruncateOverQuota,
+		usage:        make(map[string]*api_proto.FilestoreQuotaRecord),
+		persisted:    make(map[string]time.Time),
+		hunts:        make(map[string]string),
+		Clock:        &utils.RealClock{},
+	}
+}
+
+// Returns the client id and flow id if the path is an upload.
+func getUploadOwner(path api.FSPathSpec) (client_id, flow_id string, ok bool) {
+	components := path.Components()
+	if len(components) < 6 ||
+		components[0] != "clients" ||
+		components[2] != "collections" ||
+		components[4] != "uploads" {
+		return "", "", false
+	}
+	return components[1], components[3], true
+}
+
+// Find the hunt which scheduled the flow. Called with the lock held.
+func (self *QuotaFileStore) getHuntId(client_id, flow_id string) string {
+	key := client_id + "/" + flow_id
+	hunt_id, pres := self.hunts[key]
+	if pres {
+		return hunt_id
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return ""
+	}
+
+	collection_context := &flows_proto.ArtifactCollectorContext{}
+	err = db.GetSubject(self.config_obj,
+		paths.NewFlowPathManager(client_id, flow_id).Path(),
+		collection_context)
+	if err != nil {
+		// The flow may not be written yet - try again next time.
+		return ""
+	}
+
+	if collection_context.Request != nil &&
+		strings.HasPrefix(collection_context.Request.Creator,
+			constants.HUNT_PREFIX) {
+		hunt_id = collection_context.Request.Creator
+	}
+
+	if len(self.hunts) > max_cached_flows {
+		self.hunts = make(map[string]string)
+	}
+	self.hunts[key] = hunt_id
+
+	return hunt_id
+}
+
+// Get the usage record, loading it from the datastore the first
+// time. Called with the lock held.
+func (self *QuotaFileStore) getUsage(
+	quota_type, name string) *api_proto.FilestoreQuotaRecord {
+	key := quota_type + "/" + name
+	record, pres := self.usage[key]
+	if pres {
+		return record
+	}
+
+	// Everything we drop from the cache must be persisted first
+	// so it is loaded again next time.
+	if len(self.usage) > max_cached_records {
+		for _, record := range self.usage {
+			self.persist(record)
+		}
+		self.usage = make(map[string]*api_proto.FilestoreQuotaRecord)
+		self.persisted = make(map[string]time.Time)
+	}
+
+	record = &api_proto.FilestoreQuotaRecord{}
+	db, err := datastore.GetDB(self.config_obj)
+	if err == nil {
+		err = db.GetSubject(self.config_obj,
+			paths.FilestoreQuotaRecord(quota_type, name), record)
+	}
+	if err != nil || record.Name == "" {
+		record = &api_proto.FilestoreQuotaRecord{
+			Type: quota_type,
+			Name: name,
+		}
+	}
+
+	self.usage[key] = record
+	return record
+}
+
+// The records charged for writes by this client and flow.
+func (self *QuotaFileStore) getRecords(client_id, flow_id string) (
+	client_record, hunt_record *api_proto.FilestoreQuotaRecord) {
+	client_record = self.getUsage(QUOTA_TYPE_CLIENT, client_id)
+
+	hunt_id := self.getHuntId(client_id, flow_id)
+	if hunt_id != "" {
+		hunt_record = self.getUsage(QUOTA_TYPE_HUNT, hunt_id)
+	}
+	return client_record, hunt_record
+}
+
+func remaining(record *api_proto.FilestoreQuotaRecord, quota uint64) uint64 {
+	if quota == 0 || record == nil {
+		return ^uint64(0)
+	}
+
+	if record.Bytes >= quota {
+		return 0
+	}
+	return quota - record.Bytes
+}
+
+func (self *QuotaFileStore) isExhausted(client_id, flow_id string) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	client_record, hunt_record := self.getRecords(client_id, flow_id)
+	return remaining(client_record, self.client_quota) == 0 ||
+		remaining(hunt_record, self.hunt_quota) == 0
+}
+
+// Charge a write of length bytes. Returns how many bytes may be
+// written.
+func (self *QuotaFileStore) charge(
+	client_id, flow_id string, length int) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	client_record, hunt_record := self.getRecords(client_id, flow_id)
+
+	allowed := remaining(client_record, self.client_quota)
+	hunt_allowed := remaining(hunt_record, self.hunt_quota)
+	if hunt_allowed < allowed {
+		allowed = hunt_allowed
+	}
+
+	var err error
+	to_write := uint64(length)
+	if to_write > allowed {
+		err = ErrQuotaExceeded
+		to_write = allowed
+
+		// Refuse the whole write unless we truncate.
+		if !self.truncate {
+			to_write = 0
+		}
+	}
+
+	now := self.Clock.Now()
+	for _, record := range []*api_proto.FilestoreQuotaRecord{
+		client_record, hunt_record} {
+		if record == nil {
+			continue
+		}
+		record.Bytes += to_write
+		record.RefusedBytes += uint64(length) - to_write
+		record.Updated = now.Unix()
+
+		// Do not lose the usage of long running uploads if we
+		// restart before they are closed.
+		last_persisted := self.persisted[record.Type+"/"+record.Name]
+		if now.Sub(last_persisted) >= persist_interval {
+			self.persist(record)
+		}
+	}
+
+	return int(to_write), err
+}
+
+// Release space when uploads are deleted.
+func (self *QuotaFileStore) release(client_id, flow_id string, length int64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	client_record, hunt_record := self.getRecords(client_id, flow_id)
+	for _, record := range []*api_proto.FilestoreQuotaRecord{
+		client_record, hunt_record} {
+		if record == nil {
+			continue
+		}
+
+		if uint64(length) > record.Bytes {
+			record.Bytes = 0
+		} else {
+			record.Bytes -= uint64(length)
+		}
+		record.Updated = self.Clock.Now().Unix()
+	}
+
+	self.persist(client_record, hunt_record)
+}
+
+// Write the usage records to the datastore. Called with the lock
+// held.
+func (self *QuotaFileStore) persist(
+	records ...*api_proto.FilestoreQuotaRecord) {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return
+	}
+
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+
+		_ = db.SetSubject(self.config_obj,
+			paths.FilestoreQuotaRecord(record.Type, record.Name), record)
+		self.persisted[record.Type+"/"+record.Name] = self.Clock.Now()
+	}
+}
+
+func (self *QuotaFileStore) flush(client_id, flow_id string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.persist(self.getRecords(client_id, flow_id))
+}
+
+func (self *QuotaFileStore) ReadFile(path api.FSPathSpec) (api.FileReader, error) {
+	return self.delegate.ReadFile(path)
+}
+
+func (self *QuotaFileStore) StatFile(path api.FSPathSpec) (api.FileInfo, error) {
+	return self.delegate.StatFile(path)
+}
+
+func (self *QuotaFileStore) WriteFile(path api.FSPathSpec) (api.FileWriter, error) {
+	return self.WriteFileWithCompletion(path, utils.BackgroundWriter)
+}
+
+func (self *QuotaFileStore) WriteFileWithCompletion(
+	path api.FSPathSpec, completion func()) (api.FileWriter, error) {
+	client_id, flow_id, ok := getUploadOwner(path)
+	if !ok {
+		return self.delegate.WriteFileWithCompletion(path, completion)
+	}
+
+	// Do not even create new files once the quota is used up.
+	if self.isExhausted(client_id, flow_id) {
+		return nil, fmt.Errorf("%w for %v", ErrQuotaExceeded, client_id)
+	}
+
+	writer, err := self.delegate.WriteFileWithCompletion(path, completion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotaFileWriter{
+		FileWriter: writer,
+		store:      self,
+		client_id:  client_id,
+		flow_id:    flow_id,
+	}, nil
+}
+
+func (self *QuotaFileStore) ListDirectory(dirname api.FSPathSpec) (
+	[]api.FileInfo, error) {
+	return self.delegate.ListDirectory(dirname)
+}
+
+func (self *QuotaFileStore) Delete(path api.FSPathSpec) error {
+	client_id, flow_id, ok := getUploadOwner(path)
+	if !ok {
+		return self.delegate.Delete(path)
+	}
+
+	var size int64
+	info, err := self.delegate.StatFile(path)
+	if err == nil {
+		size = info.Size()
+	}
+
+	err = self.delegate.Delete(path)
+	if err != nil {
+		return err
+	}
+
+	if size > 0 {
+		self.release(client_id, flow_id, size)
+	}
+	return nil
+}
+
+func (self *QuotaFileStore) Move(src, dest api.FSPathSpec) error {
+	return self.delegate.Move(src, dest)
+}
+
+func (self *QuotaFileStore) Flush() {
+	flusher, ok := self.delegate.(api.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+func (self *QuotaFileStore) Close() error {
+	return self.delegate.Close()
+}
+
+type QuotaFileWriter struct {
+	api.FileWriter
+
+	store     *QuotaFileStore
+	client_id string
+	flow_id   string
+}
+
+func (self *QuotaFileWriter) Write(data []byte) (int, error) {
+	allowed, quota_err := self.store.charge(
+		self.client_id, self.flow_id, len(data))
+	if allowed == 0 {
+		return 0, quota_err
+	}
+
+	n, err := self.FileWriter.Write(data[:allowed])
+	if err != nil {
+		return n, err
+	}
+	return n, quota_err
+}
+
+// Truncating an upload releases its space.
+func (self *QuotaFileWriter) Truncate() error {
+	size, err := self.FileWriter.Size()
+	if err != nil {
+		return err
+	}
+
+	err = self.FileWriter.Truncate()
+	if err != nil {
+		return err
+	}
+
+	if size > 0 {
+		self.store.release(self.client_id, self.flow_id, size)
+	}
+	return nil
+}
+
+func (self *QuotaFileWriter) Close() error {
+	err := self.FileWriter.Close()
+	self.store.flush(self.client_id, self.flow_id)
+	return err
+}
+
+// List the upload usage of all clients and hunts, largest first.
+func ListUsage(config_obj *config_proto.Config) (
+	[]*api_proto.FilestoreQuotaRecord, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*api_proto.FilestoreQuotaRecord{}
+	for _, quota_type := range []string{QUOTA_TYPE_CLIENT, QUOTA_TYPE_HUNT} {
+		children, err := db.ListChildren(config_obj,
+			paths.FILESTORE_QUOTA_ROOT.AddChild(quota_type))
+		if err != nil {
+			continue
+		}
+
+		for _, child := range children {
+			if child.IsDir() {
+				continue
+			}
+
+			record := &api_proto.FilestoreQuotaRecord{}
+			err = db.GetSubject(config_obj, child, record)
+			if err != nil {
+				continue
+			}
+			result = append(result, record)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Bytes > result[j].Bytes
+	})
+
+	return result, nil
+}
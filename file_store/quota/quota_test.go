@@ -0,0 +1,185 @@
+package quota
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/memory"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type QuotaTestSuite struct {
+	suite.Suite
+
+	config_obj *config_proto.Config
+	file_store *QuotaFileStore
+}
+
+func (self *QuotaTestSuite) SetupTest() {
+	self.config_obj = config.GetDefaultConfig()
+	self.config_obj.Datastore.Implementation = "Test"
+	self.config_obj.Datastore.ClientUploadQuota = 10
+	self.config_obj.Datastore.HuntUploadQuota = 15
+
+	db, err := datastore.GetDB(self.config_obj)
+	require.NoError(self.T(), err)
+	db.(*datastore.MemcacheDatastore).Clear()
+
+	// Two collections scheduled by the same hunt.
+	for _, client_id := range []string{"C.2", "C.3"} {
+		err = db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(client_id, "F.1").Path(),
+			&flows_proto.ArtifactCollectorContext{
+				Request: &flows_proto.ArtifactCollectorArgs{
+					Creator: "H.1",
+				},
+			})
+		require.NoError(self.T(), err)
+	}
+
+	self.file_store = NewQuotaFileStore(self.config_obj,
+		memory.NewMemoryFileStore(self.config_obj))
+}
+
+func (self *QuotaTestSuite) uploadPath(client_id, name string) api.FSPathSpec {
+	return paths.NewFlowPathManager(client_id, "F.1").
+		GetUploadsFile("file", name).Path()
+}
+
+func (self *QuotaTestSuite) write(
+	path api.FSPathSpec, data string) (int, error) {
+	fd, err := self.file_store.WriteFile(path)
+	if err != nil {
+		return 0, err
+	}
+	defer fd.Close()
+
+	return fd.Write([]byte(data))
+}
+
+func (self *QuotaTestSuite) getUsage(quota_type, name string) uint64 {
+	records, err := ListUsage(self.config_obj)
+	require.NoError(self.T(), err)
+
+	for _, record := range records {
+		if record.Type == quota_type && record.Name == name {
+			return record.Bytes
+		}
+	}
+	return 0
+}
+
+func (self *QuotaTestSuite) TestClientQuota() {
+	n, err := self.write(self.uploadPath("C.1", "/a"), "123456")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 6, n)
+
+	// This write would cross the quota so it is refused.
+	n, err = self.write(self.uploadPath("C.1", "/b"), "123456")
+	assert.True(self.T(), errors.Is(err, ErrQuotaExceeded))
+	assert.Equal(self.T(), 0, n)
+	assert.Equal(self.T(), uint64(6), self.getUsage(QUOTA_TYPE_CLIENT, "C.1"))
+
+	// Other files are not accounted.
+	fd, err := self.file_store.WriteFile(
+		paths.NewFlowPathManager("C.1", "F.1").Log())
+	require.NoError(self.T(), err)
+	_, err = fd.Write([]byte("A long log message"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	// Deleting uploads releases their space.
+	err = self.file_store.Delete(self.uploadPath("C.1", "/a"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(0), self.getUsage(QUOTA_TYPE_CLIENT, "C.1"))
+
+	n, err = self.write(self.uploadPath("C.1", "/b"), "1234567890")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 10, n)
+
+	// No new uploads once the quota is used up.
+	_, err = self.file_store.WriteFile(self.uploadPath("C.1", "/c"))
+	assert.True(self.T(), errors.Is(err, ErrQuotaExceeded))
+}
+
+func (self *QuotaTestSuite) TestHuntQuota() {
+	n, err := self.write(self.uploadPath("C.2", "/a"), "12345678")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 8, n)
+
+	// The second client is within its own quota but the hunt is
+	// not.
+	n, err = self.write(self.uploadPath("C.3", "/a"), "12345678")
+	assert.True(self.T(), errors.Is(err, ErrQuotaExceeded))
+	assert.Equal(self.T(), 0, n)
+
+	assert.Equal(self.T(), uint64(8), self.getUsage(QUOTA_TYPE_HUNT, "H.1"))
+}
+
+func (self *QuotaTestSuite) TestTruncateOverQuota() {
+	self.file_store.truncate = true
+
+	n, err := self.write(self.uploadPath("C.2", "/a"), "12345678")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 8, n)
+
+	// Only the bytes up to the hunt quota are written.
+	n, err = self.write(self.uploadPath("C.3", "/a"), "12345678")
+	assert.True(self.T(), errors.Is(err, ErrQuotaExceeded))
+	assert.Equal(self.T(), 7, n)
+
+	info, err := self.file_store.StatFile(self.uploadPath("C.3", "/a"))
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(7), info.Size())
+
+	records, err := ListUsage(self.config_obj)
+	require.NoError(self.T(), err)
+
+	// Largest consumers first.
+	assert.Equal(self.T(), "H.1", records[0].Name)
+	assert.Equal(self.T(), uint64(15), records[0].Bytes)
+	assert.Equal(self.T(), uint64(1), records[0].RefusedBytes)
+}
+
+func (self *QuotaTestSuite) TestTruncateReleases() {
+	clock := &utils.MockClock{MockNow: time.Unix(100, 0)}
+	self.file_store.Clock = clock
+
+	fd, err := self.file_store.WriteFile(self.uploadPath("C.1", "/a"))
+	require.NoError(self.T(), err)
+	defer fd.Close()
+
+	_, err = fd.Write([]byte("1234567890"))
+	assert.NoError(self.T(), err)
+
+	// The usage is persisted before the writer is closed.
+	assert.Equal(self.T(), uint64(10), self.getUsage(QUOTA_TYPE_CLIENT, "C.1"))
+
+	assert.NoError(self.T(), fd.Truncate())
+	assert.Equal(self.T(), uint64(0), self.getUsage(QUOTA_TYPE_CLIENT, "C.1"))
+
+	// Usage is persisted at most every persist_interval.
+	clock.Sleep(persist_interval)
+	_, err = fd.Write([]byte("123456"))
+	assert.NoError(self.T(), err)
+
+	// A new file store (e.g. after a restart) sees the usage.
+	restarted := NewQuotaFileStore(self.config_obj,
+		memory.NewMemoryFileStore(self.config_obj))
+	_, err = restarted.charge("C.1", "F.1", 6)
+	assert.True(self.T(), errors.Is(err, ErrQuotaExceeded))
+}
+
+func TestQuotaFileStore(t *testing.T) {
+	suite.Run(t, &QuotaTestSuite{})
+}
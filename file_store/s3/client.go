@@ -0,0 +1,229 @@
+package s3
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	aws_s3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+type objectInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// The subset of object storage operations the filestore needs. Keys
+// are relative to the bucket.
+type objectStore interface {
+	Upload(ctx context.Context, key string, reader io.Reader) error
+
+	// Read length bytes from offset. May return less data at the
+	// end of the object.
+	ReadRange(ctx context.Context, key string,
+		offset, length int64) ([]byte, error)
+
+	// Returns os.ErrNotExist if the object does not exist.
+	Head(ctx context.Context, key string) (*objectInfo, error)
+
+	// List the objects and common prefixes directly under prefix.
+	List(ctx context.Context, prefix string) (
+		dirs []string, files []*objectInfo, err error)
+
+	Copy(ctx context.Context, src, dest string) error
+	Delete(ctx context.Context, key string) error
+}
+
+type awsObjectStore struct {
+	bucket                 string
+	server_side_encryption string
+
+	client   *aws_s3.S3
+	uploader *s3manager.Uploader
+}
+
+func (self *awsObjectStore) Upload(
+	ctx context.Context, key string, reader io.Reader) error {
+	input := &s3manager.UploadInput{
+		Bucket: aws.String(self.bucket),
+		Key:    aws.String(key),
+		Body:   reader,
+	}
+	if self.server_side_encryption != "" {
+		input.ServerSideEncryption = aws.String(self.server_side_encryption)
+	}
+
+	// The uploader switches to a multipart upload for files larger
+	// than the part size.
+	_, err := self.uploader.UploadWithContext(ctx, input)
+	return err
+}
+
+func (self *awsObjectStore) ReadRange(ctx context.Context, key string,
+	offset, length int64) ([]byte, error) {
+	out, err := self.client.GetObjectWithContext(ctx, &aws_s3.GetObjectInput{
+		Bucket: aws.String(self.bucket),
+		Key:    aws.String(key),
+		Range: aws.String(
+			fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		// Reading past the end of the object.
+		if isAWSError(err, "InvalidRange") {
+			return nil, io.EOF
+		}
+		return nil, translateError(err)
+	}
+	defer out.Body.Close()
+
+	return ioutil.ReadAll(out.Body)
+}
+
+func (self *awsObjectStore) Head(
+	ctx context.Context, key string) (*objectInfo, error) {
+	out, err := self.client.HeadObjectWithContext(ctx, &aws_s3.HeadObjectInput{
+		Bucket: aws.String(self.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &objectInfo{
+		Key:     key,
+		Size:    aws.Int64Value(out.ContentLength),
+		ModTime: aws.TimeValue(out.LastModified),
+	}, nil
+}
+
+func (self *awsObjectStore) List(ctx context.Context, prefix string) (
+	dirs []string, files []*objectInfo, err error) {
+	err = self.client.ListObjectsV2PagesWithContext(ctx,
+		&aws_s3.ListObjectsV2Input{
+			Bucket:    aws.String(self.bucket),
+			Prefix:    aws.String(prefix),
+			Delimiter: aws.String("/"),
+		}, func(page *aws_s3.ListObjectsV2Output, last bool) bool {
+			for _, p := range page.CommonPrefixes {
+				dirs = append(dirs, aws.StringValue(p.Prefix))
+			}
+			for _, o := range page.Contents {
+				files = append(files, &objectInfo{
+					Key:     aws.StringValue(o.Key),
+					Size:    aws.Int64Value(o.Size),
+					ModTime: aws.TimeValue(o.LastModified),
+				})
+			}
+			return true
+		})
+	return dirs, files, translateError(err)
+}
+
+func (self *awsObjectStore) Copy(ctx context.Context, src, dest string) error {
+	_, err := self.client.CopyObjectWithContext(ctx, &aws_s3.CopyObjectInput{
+		Bucket:     aws.String(self.bucket),
+		CopySource: aws.String(self.bucket + "/" + src),
+		Key:        aws.String(dest),
+	})
+	return translateError(err)
+}
+
+func (self *awsObjectStore) Delete(ctx context.Context, key string) error {
+	_, err := self.client.DeleteObjectWithContext(ctx, &aws_s3.DeleteObjectInput{
+		Bucket: aws.String(self.bucket),
+		Key:    aws.String(key),
+	})
+	return translateError(err)
+}
+
+func newAWSObjectStore(s3_config *config_proto.S3FilestoreConfig) (
+	*awsObjectStore, error) {
+	if s3_config.Bucket == "" {
+		return nil, errors.New("S3 filestore: bucket not configured")
+	}
+
+	conf := aws.NewConfig().WithRegion(s3_config.Region)
+	if s3_config.CredentialsKey != "" && s3_config.CredentialsSecret != "" {
+		conf = conf.WithCredentials(credentials.NewStaticCredentials(
+			s3_config.CredentialsKey, s3_config.CredentialsSecret, ""))
+	}
+
+	if s3_config.Endpoint != "" {
+		conf = conf.WithEndpoint(s3_config.Endpoint).WithS3ForcePathStyle(true)
+		if s3_config.NoVerifyCert {
+			conf = conf.WithHTTPClient(&http.Client{
+				Transport: &http.Transport{
+					Proxy:           http.ProxyFromEnvironment,
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+				},
+			})
+		}
+	}
+
+	sess, err := session.NewSession(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	part_size := int64(s3_config.MultipartPartSize)
+	if part_size == 0 {
+		part_size = 16 * 1024 * 1024
+	}
+
+	// S3 does not accept smaller parts.
+	if part_size < s3manager.MinUploadPartSize {
+		part_size = s3manager.MinUploadPartSize
+	}
+
+	concurrency := int(s3_config.UploadConcurrency)
+	if concurrency == 0 {
+		concurrency = s3manager.DefaultUploadConcurrency
+	}
+
+	client := aws_s3.New(sess)
+	return &awsObjectStore{
+		bucket:                 s3_config.Bucket,
+		server_side_encryption: s3_config.ServerSideEncryption,
+		client:                 client,
+		uploader: s3manager.NewUploaderWithClient(client,
+			func(u *s3manager.Uploader) {
+				u.PartSize = part_size
+				u.Concurrency = concurrency
+			}),
+	}, nil
+}
+
+func isAWSError(err error, codes ...string) bool {
+	aws_err, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	for _, code := range codes {
+		if aws_err.Code() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Callers expect os.ErrNotExist for missing files.
+func translateError(err error) error {
+	if err != nil && isAWSError(err, aws_s3.ErrCodeNoSuchKey, "NotFound") {
+		return os.ErrNotExist
+	}
+	return err
+}
@@ -0,0 +1,630 @@
+// This is an implementation of the file store which keeps bulk files
+// (uploads and result sets) in an S3 compatible bucket.
+
+// Objects in S3 can not be appended to, but filestore files are
+// written by appending to them over the life of a collection. We
+// therefore keep files that are being written in a local write back
+// cache (a DirectoryFileStore). A file is uploaded to the bucket once
+// all its writers are closed and it was not written for
+// writeback_delay_sec. Files larger than the part size are uploaded
+// using a multipart upload. While a file is in the cache, all reads
+// are served from the cache. Files which were opened but not written
+// are dropped from the cache without being uploaded again.
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/accessors/file_store_file_info"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// Objects are read in chunks of this size so small reads do not
+	// each cost a round trip.
+	readChunkSize = 1024 * 1024
+)
+
+// A file in the write back cache that is not uploaded yet.
+type dirtyFile struct {
+	path api.FSPathSpec
+
+	// Number of open writers.
+	writers int
+
+	// Incremented on every change so an upload can tell if the file
+	// was written while it was in flight.
+	generation int
+	last_write time.Time
+
+	// Set when the file was written since it was fetched from the
+	// bucket.
+	modified bool
+
+	// Called once the file is uploaded.
+	completions []func()
+}
+
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+type S3FileStore struct {
+	mu sync.Mutex
+
+	config_obj *config_proto.Config
+	prefix     string
+	delay      time.Duration
+
+	objects objectStore
+	cache   *directory.DirectoryFileStore
+
+	// Keyed by object key.
+	dirty map[string]*dirtyFile
+
+	// Serialize fetching, uploading and deleting each object
+	// without holding mu during the transfer.
+	key_locks map[string]*keyLock
+
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+
+	Clock utils.Clock
+}
+
+func NewS3FileStore(config_obj *config_proto.Config) (*S3FileStore, error) {
+	if config_obj.Datastore.S3 == nil {
+		return nil, errors.New("S3 filestore: Datastore.s3 not configured")
+	}
+
+	objects, err := newAWSObjectStore(config_obj.Datastore.S3)
+	if err != nil {
+		return nil, err
+	}
+
+	return newS3FileStore(config_obj, objects), nil
+}
+
+func newS3FileStore(
+	config_obj *config_proto.Config, objects objectStore) *S3FileStore {
+	s3_config := config_obj.Datastore.S3
+
+	cache_directory := s3_config.CacheDirectory
+	if cache_directory == "" {
+		cache_directory = filepath.Join(config_obj.Datastore.Location, "s3_cache")
+	}
+
+	cache_config := proto.Clone(config_obj).(*config_proto.Config)
+	cache_config.Datastore.FilestoreDirectory = cache_directory
+
+	delay := time.Duration(s3_config.WritebackDelaySec) * time.Second
+	if delay == 0 {
+		delay = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := &S3FileStore{
+		config_obj: config_obj,
+		prefix:     strings.Trim(s3_config.Prefix, "/"),
+		delay:      delay,
+		objects:    objects,
+		cache:      directory.NewDirectoryFileStore(cache_config),
+		dirty:      make(map[string]*dirtyFile),
+		key_locks:  make(map[string]*keyLock),
+		ctx:        ctx,
+		cancel:     cancel,
+		Clock:      &utils.RealClock{},
+	}
+
+	// Files left in the cache were not uploaded before the last
+	// shutdown.
+	_ = api.Walk(result.cache, path_specs.NewUnsafeFilestorePath(),
+		func(path api.FSPathSpec, info os.FileInfo) error {
+			result.dirty[result.key(path)] = &dirtyFile{
+				path: path, modified: true}
+			return nil
+		})
+
+	result.wg.Add(1)
+	go result.writeback()
+
+	return result
+}
+
+// Object keys use the same sanitized components as the directory
+// filestore so keys are reversible.
+func (self *S3FileStore) key(path api.FSPathSpec) string {
+	return self.dirKey(path) + api.GetExtensionForFilestore(path)
+}
+
+func (self *S3FileStore) dirKey(path api.FSPathSpec) string {
+	components := path.Components()
+	result := make([]string, 0, len(components)+1)
+	if self.prefix != "" {
+		result = append(result, self.prefix)
+	}
+
+	for _, c := range components {
+		if c != "" {
+			result = append(result, utils.SanitizeString(c))
+		}
+	}
+	return strings.Join(result, "/")
+}
+
+// Lock the object key. Returns a function to unlock it.
+func (self *S3FileStore) lockKey(key string) func() {
+	self.mu.Lock()
+	lock, pres := self.key_locks[key]
+	if !pres {
+		lock = &keyLock{}
+		self.key_locks[key] = lock
+	}
+	lock.refs++
+	self.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		self.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(self.key_locks, key)
+		}
+		self.mu.Unlock()
+	}
+}
+
+func (self *S3FileStore) writeback() {
+	defer self.wg.Done()
+
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+
+		case <-self.Clock.After(time.Second):
+			self.uploadExpired()
+		}
+	}
+}
+
+func (self *S3FileStore) uploadExpired() {
+	now := self.Clock.Now()
+
+	var keys []string
+	self.mu.Lock()
+	for key, dirty := range self.dirty {
+		if dirty.writers == 0 && now.Sub(dirty.last_write) >= self.delay {
+			keys = append(keys, key)
+		}
+	}
+	self.mu.Unlock()
+
+	for _, key := range keys {
+		err := self.upload(self.ctx, key)
+		if err != nil {
+			logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+			logger.Error("S3FileStore: Unable to upload %v: %v", key, err)
+		}
+	}
+}
+
+// Upload the cached file to the bucket and remove it from the cache
+// if it was not written in the meantime.
+func (self *S3FileStore) upload(ctx context.Context, key string) error {
+	unlock := self.lockKey(key)
+	completions, err := self.uploadLocked(ctx, key)
+	unlock()
+
+	for _, completion := range completions {
+		completion()
+	}
+	return err
+}
+
+// Returns the completions to call once the file is uploaded.
+func (self *S3FileStore) uploadLocked(
+	ctx context.Context, key string) ([]func(), error) {
+	self.mu.Lock()
+	dirty, pres := self.dirty[key]
+	if !pres {
+		self.mu.Unlock()
+		return nil, nil
+	}
+	path := dirty.path
+	generation := dirty.generation
+	modified := dirty.modified
+	self.mu.Unlock()
+
+	// Unmodified files are already in the bucket.
+	if modified {
+		defer api.InstrumentWithDelay("upload", "S3FileStore", path)()
+
+		reader, err := self.cache.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		err = self.objects.Upload(ctx, key, reader)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	self.mu.Lock()
+	dirty, pres = self.dirty[key]
+	if !pres || dirty.writers > 0 || dirty.generation != generation {
+		// The file changed while we uploaded it - try again later.
+		self.mu.Unlock()
+		return nil, nil
+	}
+	delete(self.dirty, key)
+	self.mu.Unlock()
+
+	return dirty.completions, self.cache.Delete(path)
+}
+
+// Upload all cached files.
+func (self *S3FileStore) Flush() {
+	self.mu.Lock()
+	keys := make([]string, 0, len(self.dirty))
+	for key := range self.dirty {
+		keys = append(keys, key)
+	}
+	self.mu.Unlock()
+
+	for _, key := range keys {
+		err := self.upload(context.Background(), key)
+		if err != nil {
+			logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+			logger.Error("S3FileStore: Unable to upload %v: %v", key, err)
+		}
+	}
+}
+
+func (self *S3FileStore) Close() error {
+	self.cancel()
+	self.wg.Wait()
+	self.Flush()
+	return nil
+}
+
+func (self *S3FileStore) ReadFile(path api.FSPathSpec) (api.FileReader, error) {
+	defer api.InstrumentWithDelay("open_read", "S3FileStore", path)()
+
+	key := self.key(path)
+	self.mu.Lock()
+	_, pres := self.dirty[key]
+	self.mu.Unlock()
+
+	if pres {
+		reader, err := self.cache.ReadFile(path)
+		// The file may have been uploaded since we checked.
+		if err == nil {
+			return reader, nil
+		}
+	}
+
+	info, err := self.objects.Head(self.ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Reader{
+		ctx:     self.ctx,
+		objects: self.objects,
+		path:    path,
+		info:    info,
+	}, nil
+}
+
+func (self *S3FileStore) WriteFile(path api.FSPathSpec) (api.FileWriter, error) {
+	return self.WriteFileWithCompletion(path, utils.BackgroundWriter)
+}
+
+func (self *S3FileStore) WriteFileWithCompletion(
+	path api.FSPathSpec, completion func()) (api.FileWriter, error) {
+
+	defer api.InstrumentWithDelay("open_write", "S3FileStore", path)()
+
+	key := self.key(path)
+
+	// Other objects can be accessed while we fetch this one.
+	unlock := self.lockKey(key)
+	defer unlock()
+
+	self.mu.Lock()
+	_, pres := self.dirty[key]
+	self.mu.Unlock()
+
+	// New files must be uploaded even if they are not written.
+	modified := false
+	if !pres {
+		// Writers append to the file so we need to start with the
+		// existing object.
+		exists, err := self.fetchToCache(path, key)
+		if err != nil {
+			return nil, err
+		}
+		modified = !exists
+	}
+
+	writer, err := self.cache.WriteFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	self.mu.Lock()
+	dirty, pres := self.dirty[key]
+	if !pres {
+		dirty = &dirtyFile{path: path, modified: modified}
+		self.dirty[key] = dirty
+	}
+	dirty.writers++
+	self.mu.Unlock()
+
+	return &S3FileWriter{
+		FileWriter: writer,
+		store:      self,
+		key:        key,
+		completion: completion,
+	}, nil
+}
+
+// Copy the object into the cache. Called with the key locked. Returns
+// true if the object exists in the bucket.
+func (self *S3FileStore) fetchToCache(
+	path api.FSPathSpec, key string) (bool, error) {
+	writer, err := self.cache.WriteFile(path)
+	if err != nil {
+		return false, err
+	}
+	defer writer.Close()
+
+	err = writer.Truncate()
+	if err != nil {
+		return false, err
+	}
+
+	info, err := self.objects.Head(self.ctx, key)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for offset := int64(0); offset < info.Size; {
+		data, err := self.objects.ReadRange(
+			self.ctx, key, offset, readChunkSize)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, err
+		}
+
+		if len(data) == 0 {
+			break
+		}
+
+		_, err = writer.Write(data)
+		if err != nil {
+			return false, err
+		}
+		offset += int64(len(data))
+	}
+
+	return true, nil
+}
+
+func (self *S3FileStore) touch(key string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	dirty, pres := self.dirty[key]
+	if pres {
+		dirty.generation++
+		dirty.last_write = self.Clock.Now()
+		dirty.modified = true
+	}
+}
+
+// Called when a writer is closed.
+func (self *S3FileStore) release(key string, completion func()) {
+	self.mu.Lock()
+	dirty, pres := self.dirty[key]
+	if !pres {
+		// The file was deleted while the writer was open.
+		self.mu.Unlock()
+		if completion != nil &&
+			!utils.CompareFuncs(completion, utils.SyncCompleter) {
+			completion()
+		}
+		return
+	}
+
+	dirty.writers--
+	dirty.generation++
+	dirty.last_write = self.Clock.Now()
+
+	sync_upload := utils.CompareFuncs(completion, utils.SyncCompleter)
+	if completion != nil && !sync_upload {
+		dirty.completions = append(dirty.completions, completion)
+	}
+	self.mu.Unlock()
+
+	// Synchronous writers wait for the file to hit the bucket.
+	if sync_upload {
+		err := self.upload(self.ctx, key)
+		if err != nil {
+			logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+			logger.Error("S3FileStore: Unable to upload %v: %v", key, err)
+		}
+	}
+}
+
+func (self *S3FileStore) StatFile(path api.FSPathSpec) (api.FileInfo, error) {
+	defer api.Instrument("stat", "S3FileStore", path)()
+
+	key := self.key(path)
+	self.mu.Lock()
+	_, pres := self.dirty[key]
+	self.mu.Unlock()
+
+	if pres {
+		info, err := self.cache.StatFile(path)
+		if err == nil {
+			return file_store_file_info.NewFileStoreFileInfo(
+				self.config_obj, path, info), nil
+		}
+	}
+
+	info, err := self.objects.Head(self.ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return file_store_file_info.NewFileStoreFileInfo(self.config_obj, path,
+		&objectFileInfo{
+			name:     path.Base(),
+			size:     info.Size,
+			mod_time: info.ModTime,
+		}), nil
+}
+
+func (self *S3FileStore) ListDirectory(dirname api.FSPathSpec) (
+	[]api.FileInfo, error) {
+
+	defer api.InstrumentWithDelay("list", "S3FileStore", dirname)()
+
+	prefix := self.dirKey(dirname)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	dirs, files, err := self.objects.List(self.ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var result []api.FileInfo
+
+	add := func(name string, info os.FileInfo) {
+		name_type := api.PATH_TYPE_FILESTORE_ANY
+		if !info.IsDir() {
+			name_type, name = api.GetFileStorePathTypeFromExtension(name)
+		}
+
+		child := dirname.AddUnsafeChild(
+			utils.UnsanitizeComponent(name)).SetType(name_type)
+		key := child.AsClientPath()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		result = append(result, file_store_file_info.NewFileStoreFileInfo(
+			self.config_obj, child, info))
+	}
+
+	for _, dir := range dirs {
+		name := strings.TrimSuffix(strings.TrimPrefix(dir, prefix), "/")
+		add(name, &objectFileInfo{name: name, is_dir: true})
+	}
+
+	for _, file := range files {
+		name := strings.TrimPrefix(file.Key, prefix)
+		add(name, &objectFileInfo{
+			name:     name,
+			size:     file.Size,
+			mod_time: file.ModTime,
+		})
+	}
+
+	// Files that are not uploaded yet are only in the cache.
+	cached, _ := self.cache.ListDirectory(dirname)
+	for _, info := range cached {
+		key := info.PathSpec().AsClientPath()
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, info)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+
+	return result, nil
+}
+
+func (self *S3FileStore) Delete(path api.FSPathSpec) error {
+	defer api.InstrumentWithDelay("delete", "S3FileStore", path)()
+
+	key := self.key(path)
+
+	unlock := self.lockKey(key)
+
+	self.mu.Lock()
+	dirty, pres := self.dirty[key]
+	if pres {
+		delete(self.dirty, key)
+	}
+	self.mu.Unlock()
+
+	if pres {
+		_ = self.cache.Delete(path)
+	}
+
+	err := self.objects.Delete(self.ctx, key)
+	unlock()
+
+	if pres {
+		for _, completion := range dirty.completions {
+			completion()
+		}
+	}
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (self *S3FileStore) Move(src, dest api.FSPathSpec) error {
+	defer api.InstrumentWithDelay("move", "S3FileStore", src)()
+
+	// Make sure the source is in the bucket.
+	src_key := self.key(src)
+	err := self.upload(self.ctx, src_key)
+	if err != nil {
+		return err
+	}
+
+	err = self.objects.Copy(self.ctx, src_key, self.key(dest))
+	if err != nil {
+		return err
+	}
+
+	return self.Delete(src)
+}
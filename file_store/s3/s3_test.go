@@ -0,0 +1,314 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/tests"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// An in memory bucket.
+type fakeObjectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads int
+}
+
+func (self *fakeObjectStore) Upload(
+	ctx context.Context, key string, reader io.Reader) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.objects[key] = data
+	self.uploads++
+	return nil
+}
+
+func (self *fakeObjectStore) ReadRange(ctx context.Context, key string,
+	offset, length int64) ([]byte, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	data, pres := self.objects[key]
+	if !pres {
+		return nil, os.ErrNotExist
+	}
+
+	if offset >= int64(len(data)) {
+		return nil, io.EOF
+	}
+
+	end := offset + length
+	if end > int64(len(data)) {
+		end = int64(len(data))
+	}
+	return utils.CopySlice(data[offset:end]), nil
+}
+
+func (self *fakeObjectStore) Head(
+	ctx context.Context, key string) (*objectInfo, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	data, pres := self.objects[key]
+	if !pres {
+		return nil, os.ErrNotExist
+	}
+	return &objectInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func (self *fakeObjectStore) List(ctx context.Context, prefix string) (
+	dirs []string, files []*objectInfo, err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for key, data := range self.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		rest := strings.TrimPrefix(key, prefix)
+		idx := strings.Index(rest, "/")
+		if idx < 0 {
+			files = append(files, &objectInfo{Key: key, Size: int64(len(data))})
+			continue
+		}
+
+		dir := prefix + rest[:idx+1]
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, files, nil
+}
+
+func (self *fakeObjectStore) Copy(ctx context.Context, src, dest string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	data, pres := self.objects[src]
+	if !pres {
+		return os.ErrNotExist
+	}
+	self.objects[dest] = data
+	return nil
+}
+
+func (self *fakeObjectStore) Delete(ctx context.Context, key string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	delete(self.objects, key)
+	return nil
+}
+
+func (self *fakeObjectStore) Keys() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := make([]string, 0, len(self.objects))
+	for k := range self.objects {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func newFakeObjectStore() *fakeObjectStore {
+	return &fakeObjectStore{objects: make(map[string][]byte)}
+}
+
+func newTestConfig(t *testing.T) *config_proto.Config {
+	dir, err := ioutil.TempDir("", "s3_file_store_test")
+	assert.NoError(t, err)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.Location = dir
+	config_obj.Datastore.FilestoreDirectory = dir
+	config_obj.Datastore.S3 = &config_proto.S3FilestoreConfig{
+		Bucket: "velociraptor",
+		Prefix: "/server/",
+
+		// Uploads are triggered by Flush() in tests.
+		WritebackDelaySec: 3600,
+	}
+	return config_obj
+}
+
+type S3TestSuite struct {
+	suite.Suite
+
+	config_obj *config_proto.Config
+	objects    *fakeObjectStore
+	file_store *S3FileStore
+}
+
+func (self *S3TestSuite) SetupTest() {
+	self.config_obj = newTestConfig(self.T())
+	self.objects = newFakeObjectStore()
+	self.file_store = newS3FileStore(self.config_obj, self.objects)
+}
+
+func (self *S3TestSuite) TearDownTest() {
+	self.file_store.Close()
+	os.RemoveAll(self.config_obj.Datastore.Location)
+}
+
+func (self *S3TestSuite) readAll(path_spec api.FSPathSpec) string {
+	reader, err := self.file_store.ReadFile(path_spec)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(self.T(), err)
+	return string(data)
+}
+
+func (self *S3TestSuite) TestWriteBack() {
+	path_spec := path_specs.NewSafeFilestorePath("clients", "C.123", "upload")
+
+	completed := false
+	fd, err := self.file_store.WriteFileWithCompletion(path_spec, func() {
+		completed = true
+	})
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("Hello "))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	// Nothing is uploaded until the file is flushed, but it is
+	// readable from the cache.
+	assert.Equal(self.T(), 0, len(self.objects.Keys()))
+	assert.False(self.T(), completed)
+	assert.Equal(self.T(), "Hello ", self.readAll(path_spec))
+
+	self.file_store.Flush()
+	assert.True(self.T(), completed)
+	assert.Equal(self.T(), []string{"server/clients/C.123/upload.json"},
+		self.objects.Keys())
+
+	// The cached copy is removed once uploaded.
+	_, err = self.file_store.cache.StatFile(path_spec)
+	assert.True(self.T(), os.IsNotExist(err))
+
+	// Reads now come from the bucket.
+	reader, err := self.file_store.ReadFile(path_spec)
+	assert.NoError(self.T(), err)
+	_, ok := reader.(*S3Reader)
+	assert.True(self.T(), ok)
+	reader.Close()
+
+	// Uploaded files are listed from the bucket.
+	infos, err := self.file_store.ListDirectory(path_spec.Dir())
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(infos))
+	assert.Equal(self.T(), "upload", infos[0].Name())
+	assert.Equal(self.T(), int64(6), infos[0].Size())
+
+	infos, err = self.file_store.ListDirectory(
+		path_specs.NewSafeFilestorePath("clients"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(infos))
+	assert.True(self.T(), infos[0].IsDir())
+
+	// Appending to an uploaded file starts with the uploaded data.
+	fd, err = self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("World"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	self.file_store.Flush()
+	assert.Equal(self.T(), "Hello World", self.readAll(path_spec))
+
+	// Synchronous writers upload on close.
+	fd, err = self.file_store.WriteFileWithCompletion(
+		path_spec, utils.SyncCompleter)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("!"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	assert.Equal(self.T(), "Hello World!",
+		string(self.objects.objects["server/clients/C.123/upload.json"]))
+}
+
+// Opening an uploaded file without writing to it does not upload it
+// again.
+func (self *S3TestSuite) TestUnmodifiedNotUploaded() {
+	path_spec := path_specs.NewSafeFilestorePath("clients", "C.123", "upload")
+
+	fd, err := self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("Hello"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	self.file_store.Flush()
+	assert.Equal(self.T(), 1, self.objects.uploads)
+
+	fd, err = self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	self.file_store.Flush()
+	assert.Equal(self.T(), 1, self.objects.uploads)
+	assert.Equal(self.T(), "Hello", self.readAll(path_spec))
+
+	// The cached copy is still removed.
+	_, err = self.file_store.cache.StatFile(path_spec)
+	assert.True(self.T(), os.IsNotExist(err))
+	assert.Equal(self.T(), 0, len(self.file_store.key_locks))
+}
+
+func (self *S3TestSuite) TestRecoverCache() {
+	path_spec := path_specs.NewSafeFilestorePath("clients", "C.123", "upload")
+
+	fd, err := self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("Hello"))
+	assert.NoError(self.T(), err)
+	fd.Close()
+
+	// Simulate a crash before the file was uploaded - a new
+	// filestore picks up the cached file.
+	file_store := newS3FileStore(self.config_obj, self.objects)
+	file_store.Flush()
+	file_store.Close()
+
+	assert.Equal(self.T(), "Hello",
+		string(self.objects.objects["server/clients/C.123/upload.json"]))
+}
+
+func TestS3FileStore(t *testing.T) {
+	suite.Run(t, &S3TestSuite{})
+}
+
+func TestS3FileStoreAPI(t *testing.T) {
+	config_obj := newTestConfig(t)
+	defer os.RemoveAll(config_obj.Datastore.Location)
+
+	file_store := newS3FileStore(config_obj, newFakeObjectStore())
+	defer file_store.Close()
+
+	suite.Run(t, tests.NewFileStoreTestSuite(config_obj, file_store))
+}
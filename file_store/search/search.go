@@ -0,0 +1,357 @@
+// Search the contents of uploads stored in the filestore.
+
+// Analysts often need to know which of the files collected from a
+// client or a hunt mention an indicator (e.g. a C2 domain). Rather
+// than downloading all the uploads, the Searcher scans them on the
+// server and streams back each match together with its offset in the
+// upload.
+
+// Searches are bounded: they stop after a maximum number of matches
+// and may be limited to the start of each upload. Files are scanned
+// in large buffers which overlap slightly, so matches which span two
+// buffers are found as long as they are shorter than the overlap.
+
+package search
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	default_max_matches = 1000
+	default_context     = 40
+
+	buffer_size = 1024 * 1024
+	overlap     = 4096
+)
+
+var (
+	// Returned internally to stop the search once enough matches
+	// were found.
+	errEnough = errors.New("Enough matches")
+)
+
+type Options struct {
+	// Either a regex or a list of keywords (or both) must be given.
+	Regex    string
+	Keywords []string
+	NoCase   bool
+
+	// Stop the search after this many matches (default 1000).
+	MaxMatches int64
+
+	// Only report this many matches from each upload (0 means no
+	// limit).
+	MaxMatchesPerFile int64
+
+	// Only search this many bytes at the start of each upload (0
+	// means the whole upload).
+	MaxFileSize int64
+
+	// How many bytes to include either side of each match (default
+	// 40).
+	Context int
+}
+
+type Match struct {
+	ClientId string
+	FlowId   string
+
+	// The path of the file on the client.
+	Upload string
+
+	// Where the upload is stored in the filestore.
+	Path api.FSPathSpec
+
+	// The offset of the match in the upload.
+	Offset  int64
+	Hit     string
+	Context string
+}
+
+type Searcher struct {
+	config_obj *config_proto.Config
+	options    Options
+	matcher    *regexp.Regexp
+
+	// Total matches found so far.
+	count int64
+}
+
+func NewSearcher(
+	config_obj *config_proto.Config, options Options) (*Searcher, error) {
+	var alternatives []string
+	if options.Regex != "" {
+		alternatives = append(alternatives, options.Regex)
+	}
+
+	for _, keyword := range options.Keywords {
+		if keyword != "" {
+			alternatives = append(alternatives, regexp.QuoteMeta(keyword))
+		}
+	}
+
+	if len(alternatives) == 0 {
+		return nil, errors.New("Search requires a regex or keywords")
+	}
+
+	expression := strings.Join(alternatives, "|")
+	if options.NoCase {
+		expression = "(?i)" + expression
+	}
+
+	matcher, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.MaxMatches == 0 {
+		options.MaxMatches = default_max_matches
+	}
+
+	if options.Context == 0 {
+		options.Context = default_context
+	}
+
+	return &Searcher{
+		config_obj: config_obj,
+		options:    options,
+		matcher:    matcher,
+	}, nil
+}
+
+// Search all the uploads of a single collection.
+func (self *Searcher) SearchFlow(ctx context.Context,
+	client_id, flow_id string, output chan<- *Match) error {
+	return ignoreEnough(self.searchFlow(ctx, client_id, flow_id, output))
+}
+
+// Search the uploads of all the client's collections, most recent
+// first.
+func (self *Searcher) SearchClient(ctx context.Context,
+	client_id string, output chan<- *Match) error {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	children, err := db.ListChildren(self.config_obj,
+		paths.NewFlowPathManager(client_id, "").ContainerPath())
+	if err != nil {
+		return err
+	}
+
+	// Flow IDs represent timestamps so they are sortable.
+	flow_ids := make([]string, 0, len(children))
+	for _, child := range children {
+		if !child.IsDir() {
+			flow_ids = append(flow_ids, child.Base())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(flow_ids)))
+
+	for _, flow_id := range flow_ids {
+		err := self.searchFlow(ctx, client_id, flow_id, output)
+		if err != nil {
+			return ignoreEnough(err)
+		}
+	}
+
+	return nil
+}
+
+// Search the uploads of all collections scheduled by the hunt.
+func (self *Searcher) SearchHunt(ctx context.Context,
+	hunt_id string, output chan<- *Match) error {
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory,
+		paths.NewHuntPathManager(hunt_id).Clients())
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for row := range reader.Rows(ctx) {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" {
+			continue
+		}
+
+		err := self.searchFlow(ctx, client_id, flow_id, output)
+		if err != nil {
+			return ignoreEnough(err)
+		}
+	}
+
+	return nil
+}
+
+func (self *Searcher) searchFlow(ctx context.Context,
+	client_id, flow_id string, output chan<- *Match) error {
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory,
+		paths.NewFlowPathManager(client_id, flow_id).UploadMetadata())
+	if err != nil {
+		// The collection has no uploads.
+		return nil
+	}
+	defer reader.Close()
+
+	for row := range reader.Rows(ctx) {
+		vfs_path, pres := row.GetString("vfs_path")
+		if !pres {
+			continue
+		}
+
+		match := &Match{
+			ClientId: client_id,
+			FlowId:   flow_id,
+			Upload:   vfs_path,
+		}
+
+		// Newer collections store the filestore components
+		// separately, older ones store the filestore path as
+		// vfs_path.
+		components_any, _ := row.Get("_Components")
+		components := utils.ConvertToStringSlice(components_any)
+		if len(components) == 0 {
+			components = utils.SplitComponents(vfs_path)
+		}
+		match.Path = path_specs.NewUnsafeFilestorePath(components...).
+			SetType(api.PATH_TYPE_FILESTORE_ANY)
+
+		err := self.searchFile(ctx, file_store_factory, match, output)
+		if err != nil {
+			if errors.Is(err, errEnough) || ctx.Err() != nil {
+				return err
+			}
+
+			// The upload may have been deleted - keep going.
+			continue
+		}
+	}
+
+	return ctx.Err()
+}
+
+// Scan a single upload. The template carries the upload's details
+// and is copied for each match.
+func (self *Searcher) searchFile(ctx context.Context,
+	file_store_factory api.FileStore,
+	template *Match, output chan<- *Match) error {
+	fd, err := file_store_factory.ReadFile(template.Path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	buf := make([]byte, buffer_size+overlap)
+
+	// The offset in the file of buf[0]
+	base := int64(0)
+
+	// The number of bytes carried over from the last buffer.
+	carried := 0
+	file_matches := int64(0)
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		to_read := len(buf) - carried
+		if self.options.MaxFileSize > 0 {
+			remaining := self.options.MaxFileSize - base - int64(carried)
+			if remaining < int64(to_read) {
+				to_read = int(remaining)
+			}
+		}
+
+		n, err := io.ReadFull(fd, buf[carried:carried+to_read])
+		data := buf[:carried+n]
+
+		last := errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			(self.options.MaxFileSize > 0 &&
+				base+int64(len(data)) >= self.options.MaxFileSize)
+		if err != nil && !last {
+			return err
+		}
+
+		// Matches starting in the tail are left for the next buffer,
+		// which also contains the data following them.
+		limit := len(data)
+		if !last {
+			limit -= overlap
+		}
+
+		for _, hit := range self.matcher.FindAllIndex(data, -1) {
+			if hit[0] >= limit {
+				break
+			}
+
+			start := hit[0] - self.options.Context
+			if start < 0 {
+				start = 0
+			}
+
+			end := hit[1] + self.options.Context
+			if end > len(data) {
+				end = len(data)
+			}
+
+			match := *template
+			match.Offset = base + int64(hit[0])
+			match.Hit = string(data[hit[0]:hit[1]])
+			match.Context = string(data[start:end])
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case output <- &match:
+			}
+
+			self.count++
+			if self.count >= self.options.MaxMatches {
+				return errEnough
+			}
+
+			file_matches++
+			if self.options.MaxMatchesPerFile > 0 &&
+				file_matches >= self.options.MaxMatchesPerFile {
+				return nil
+			}
+		}
+
+		if last {
+			return nil
+		}
+
+		// Carry the tail over to the start of the buffer.
+		copy(buf, data[limit:])
+		base += int64(limit)
+		carried = len(data) - limit
+	}
+}
+
+func ignoreEnough(err error) error {
+	if errors.Is(err, errEnough) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,158 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+
+	_ "www.velocidex.com/golang/velociraptor/result_sets/simple"
+)
+
+type SearchTestSuite struct {
+	suite.Suite
+
+	config_obj *config_proto.Config
+}
+
+func (self *SearchTestSuite) SetupTest() {
+	self.config_obj = config.GetDefaultConfig()
+	self.config_obj.Datastore.Implementation = "Test"
+	test_utils.GetMemoryFileStore(self.T(), self.config_obj).Clear()
+	test_utils.GetMemoryDataStore(self.T(), self.config_obj).Clear()
+
+	db, err := datastore.GetDB(self.config_obj)
+	require.NoError(self.T(), err)
+
+	// A hunt with two collections.
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.NewHuntPathManager("H.1").Clients(), nil,
+		func() {}, result_sets.TruncateMode)
+	require.NoError(self.T(), err)
+
+	for _, client_id := range []string{"C.1", "C.2"} {
+		err = db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(client_id, "F.1").Path(),
+			&flows_proto.ArtifactCollectorContext{
+				ClientId:  client_id,
+				SessionId: "F.1",
+			})
+		require.NoError(self.T(), err)
+
+		writer.Write(ordereddict.NewDict().
+			Set("HuntId", "H.1").
+			Set("ClientId", client_id).
+			Set("FlowId", "F.1"))
+	}
+	writer.Close()
+}
+
+func (self *SearchTestSuite) addUpload(
+	client_id, flow_id, client_path, data string) {
+	upload := paths.NewFlowPathManager(client_id, flow_id).
+		GetUploadsFile("file", client_path)
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+
+	fd, err := file_store_factory.WriteFile(upload.Path())
+	require.NoError(self.T(), err)
+	_, err = fd.Write([]byte(data))
+	require.NoError(self.T(), err)
+	fd.Close()
+
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.NewFlowPathManager(client_id, flow_id).UploadMetadata(),
+		nil, func() {}, result_sets.AppendMode)
+	require.NoError(self.T(), err)
+
+	writer.Write(ordereddict.NewDict().
+		Set("vfs_path", client_path).
+		Set("_Components", upload.Path().Components()).
+		Set("file_size", len(data)))
+	writer.Close()
+}
+
+func (self *SearchTestSuite) search(options Options,
+	cb func(searcher *Searcher, output chan<- *Match) error) []*Match {
+	searcher, err := NewSearcher(self.config_obj, options)
+	require.NoError(self.T(), err)
+
+	output := make(chan *Match)
+	go func() {
+		defer close(output)
+		assert.NoError(self.T(), cb(searcher, output))
+	}()
+
+	result := []*Match{}
+	for match := range output {
+		result = append(result, match)
+	}
+	return result
+}
+
+func (self *SearchTestSuite) TestSearchHunt() {
+	self.addUpload("C.1", "F.1", "/etc/hosts", "127.0.0.1 evil.example.com")
+	self.addUpload("C.1", "F.1", "/etc/passwd", "root:x:0:0")
+	self.addUpload("C.2", "F.1", "/tmp/log", "connect to EVIL.example.com")
+
+	matches := self.search(Options{
+		Keywords: []string{"evil.example.com"},
+		NoCase:   true,
+	}, func(searcher *Searcher, output chan<- *Match) error {
+		return searcher.SearchHunt(context.Background(), "H.1", output)
+	})
+
+	require.Equal(self.T(), 2, len(matches))
+	assert.Equal(self.T(), "C.1", matches[0].ClientId)
+	assert.Equal(self.T(), "/etc/hosts", matches[0].Upload)
+	assert.Equal(self.T(), int64(10), matches[0].Offset)
+
+	assert.Equal(self.T(), "C.2", matches[1].ClientId)
+	assert.Equal(self.T(), "EVIL.example.com", matches[1].Hit)
+	assert.Equal(self.T(), int64(11), matches[1].Offset)
+}
+
+func (self *SearchTestSuite) TestLimits() {
+	// A match spanning the buffer boundary is found once.
+	data := strings.Repeat("A", buffer_size+overlap-2) + "evil" +
+		strings.Repeat("A", 100) + "evil"
+	self.addUpload("C.1", "F.1", "/big", data)
+
+	matches := self.search(Options{Regex: "ev[il]+"},
+		func(searcher *Searcher, output chan<- *Match) error {
+			return searcher.SearchFlow(context.Background(), "C.1", "F.1", output)
+		})
+	require.Equal(self.T(), 2, len(matches))
+	assert.Equal(self.T(), int64(buffer_size+overlap-2), matches[0].Offset)
+	assert.Equal(self.T(), int64(buffer_size+overlap+102), matches[1].Offset)
+
+	// Stop after the first match.
+	matches = self.search(Options{Regex: "evil", MaxMatches: 1},
+		func(searcher *Searcher, output chan<- *Match) error {
+			return searcher.SearchClient(context.Background(), "C.1", output)
+		})
+	assert.Equal(self.T(), 1, len(matches))
+
+	// Only search the start of the file.
+	matches = self.search(Options{Regex: "evil", MaxFileSize: 1024},
+		func(searcher *Searcher, output chan<- *Match) error {
+			return searcher.SearchFlow(context.Background(), "C.1", "F.1", output)
+		})
+	assert.Equal(self.T(), 0, len(matches))
+}
+
+func TestSearch(t *testing.T) {
+	suite.Run(t, &SearchTestSuite{})
+}
@@ -78,6 +78,9 @@ type: INTERNAL
 name: Server.Internal.ClientTasks
 type: INTERNAL
 `, `
+name: Server.Internal.CanaryTriggered
+type: INTERNAL
+`, `
 name: Generic.Client.Info
 type: CLIENT
 sources:
@@ -0,0 +1,460 @@
+// This is a file store which moves old files to a cold tier.
+
+// Result sets and uploads are usually only accessed for a short
+// time after they are collected, but servers keep them around for a
+// long time. The tiered file store wraps the regular file store (the
+// hot tier) and periodically moves files under the configured
+// prefixes which were not modified for max_age_sec to the cold
+// directory (e.g. a cheap network mount).
+
+// Each moved file is recorded in the datastore. When a file is not
+// found in the hot tier we consult the record to read it from the
+// cold tier, so callers (e.g. the GUI) do not need to know where the
+// file is. Checking the record first means files that do not exist
+// at all do not cost a round trip to a slow cold tier.
+
+// Writing to a moved file moves it back to the hot tier first so it
+// can be appended to.
+
+package tiered
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	defaultPrefixes = []string{"clients", "hunts"}
+)
+
+type TieredFileStore struct {
+	// Protects the writers and migrating maps and serializes
+	// recalling files from the cold tier.
+	mu sync.Mutex
+
+	config_obj *config_proto.Config
+	hot        api.FileStore
+	cold       *directory.DirectoryFileStore
+
+	max_age  time.Duration
+	period   time.Duration
+	prefixes []string
+
+	// Files with open writers are never moved. Keyed by client
+	// path.
+	writers map[string]int
+
+	// Files being copied to the cold tier. Files are copied without
+	// holding the lock, so opening, deleting or moving a file while
+	// it is copied sets its entry to false to abandon the move.
+	migrating map[string]bool
+
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+
+	Clock utils.Clock
+}
+
+func IsEnabled(config_obj *config_proto.Config) bool {
+	return config_obj.Datastore != nil &&
+		config_obj.Datastore.Tiering != nil &&
+		config_obj.Datastore.Tiering.ColdDirectory != ""
+}
+
+func NewTieredFileStore(
+	config_obj *config_proto.Config, hot api.FileStore) *TieredFileStore {
+	result := newTieredFileStore(config_obj, hot)
+
+	result.wg.Add(1)
+	go result.migrateLoop()
+
+	return result
+}
+
+func newTieredFileStore(
+	config_obj *config_proto.Config, hot api.FileStore) *TieredFileStore {
+	tiering := config_obj.Datastore.Tiering
+
+	cold_config := proto.Clone(config_obj).(*config_proto.Config)
+	cold_config.Datastore.FilestoreDirectory = tiering.ColdDirectory
+
+	max_age := time.Duration(tiering.MaxAgeSec) * time.Second
+	if max_age == 0 {
+		max_age = 30 * 24 * time.Hour
+	}
+
+	period := time.Duration(tiering.ScanPeriodSec) * time.Second
+	if period == 0 {
+		period = time.Hour
+	}
+
+	prefixes := tiering.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = defaultPrefixes
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TieredFileStore{
+		config_obj: config_obj,
+		hot:        hot,
+		cold:       directory.NewDirectoryFileStore(cold_config),
+		max_age:    max_age,
+		period:     period,
+		prefixes:   prefixes,
+		writers:    make(map[string]int),
+		migrating:  make(map[string]bool),
+		ctx:        ctx,
+		cancel:     cancel,
+		Clock:      &utils.RealClock{},
+	}
+}
+
+func (self *TieredFileStore) migrateLoop() {
+	defer self.wg.Done()
+
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+
+		case <-self.Clock.After(self.period):
+			err := self.Migrate(self.ctx)
+			if err != nil {
+				logger := logging.GetLogger(
+					self.config_obj, &logging.FrontendComponent)
+				logger.Error("TieredFileStore: %v", err)
+			}
+		}
+	}
+}
+
+// Move all old files to the cold tier.
+func (self *TieredFileStore) Migrate(ctx context.Context) error {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	now := self.Clock.Now()
+	count := 0
+
+	for _, prefix := range self.prefixes {
+		err := api.Walk(self.hot, path_specs.NewUnsafeFilestorePath(prefix),
+			func(path api.FSPathSpec, info os.FileInfo) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+
+				if now.Sub(info.ModTime()) < self.max_age {
+					return nil
+				}
+
+				err := self.migrateFile(path, info)
+				if err != nil {
+					logger.Error("TieredFileStore: Unable to move %v: %v",
+						path.AsClientPath(), err)
+					return nil
+				}
+				count++
+				return nil
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	if count > 0 {
+		logger.Info("TieredFileStore: Moved %v files to the cold tier", count)
+	}
+	return nil
+}
+
+func (self *TieredFileStore) migrateFile(
+	path api.FSPathSpec, info os.FileInfo) error {
+	key := path.AsClientPath()
+
+	self.mu.Lock()
+	if self.writers[key] > 0 || self.migrating[key] {
+		self.mu.Unlock()
+		return nil
+	}
+	self.migrating[key] = true
+	self.mu.Unlock()
+
+	err := copyFile(self.hot, self.cold, path)
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	ok := self.migrating[key]
+	delete(self.migrating, key)
+
+	// The file changed while we copied it - leave it in the hot
+	// tier for now.
+	if err != nil || !ok {
+		_ = self.cold.Delete(path)
+		return err
+	}
+
+	// Record the file before removing it from the hot tier so
+	// readers can always find it.
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	record := &api_proto.FilestoreTierRecord{
+		Components:   path.Components(),
+		PathType:     int64(path.Type()),
+		Size:         info.Size(),
+		ModTime:      info.ModTime().Unix(),
+		MigratedTime: self.Clock.Now().Unix(),
+	}
+	err = db.SetSubject(self.config_obj, paths.FilestoreTierRecord(path), record)
+	if err != nil {
+		return err
+	}
+
+	return self.hot.Delete(path)
+}
+
+// Move a file back from the cold tier. Called with the lock held.
+func (self *TieredFileStore) recall(path api.FSPathSpec) error {
+	if !self.isCold(path) {
+		return nil
+	}
+
+	err := copyFile(self.cold, self.hot, path)
+	if err != nil {
+		return err
+	}
+
+	err = self.removeRecord(path)
+	if err != nil {
+		return err
+	}
+
+	return self.cold.Delete(path)
+}
+
+func (self *TieredFileStore) isCold(path api.FSPathSpec) bool {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return false
+	}
+
+	record := &api_proto.FilestoreTierRecord{}
+	err = db.GetSubject(self.config_obj, paths.FilestoreTierRecord(path), record)
+	return err == nil
+}
+
+func (self *TieredFileStore) removeRecord(path api.FSPathSpec) error {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+	return db.DeleteSubject(self.config_obj, paths.FilestoreTierRecord(path))
+}
+
+func (self *TieredFileStore) ReadFile(path api.FSPathSpec) (api.FileReader, error) {
+	reader, err := self.hot.ReadFile(path)
+	if err == nil || !self.isCold(path) {
+		return reader, err
+	}
+	return self.cold.ReadFile(path)
+}
+
+func (self *TieredFileStore) StatFile(path api.FSPathSpec) (api.FileInfo, error) {
+	info, err := self.hot.StatFile(path)
+	if err == nil || !self.isCold(path) {
+		return info, err
+	}
+	return self.cold.StatFile(path)
+}
+
+func (self *TieredFileStore) WriteFile(path api.FSPathSpec) (api.FileWriter, error) {
+	return self.WriteFileWithCompletion(path, utils.BackgroundWriter)
+}
+
+func (self *TieredFileStore) WriteFileWithCompletion(
+	path api.FSPathSpec, completion func()) (api.FileWriter, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	err := self.recall(path)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := self.hot.WriteFileWithCompletion(path, completion)
+	if err != nil {
+		return nil, err
+	}
+
+	key := path.AsClientPath()
+	self.writers[key]++
+	self.abandonMigration(path)
+
+	return &TieredFileWriter{
+		FileWriter: writer,
+		store:      self,
+		key:        key,
+	}, nil
+}
+
+// Called with the lock held.
+func (self *TieredFileStore) abandonMigration(path api.FSPathSpec) {
+	key := path.AsClientPath()
+	if self.migrating[key] {
+		self.migrating[key] = false
+	}
+}
+
+func (self *TieredFileStore) release(key string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.writers[key]--
+	if self.writers[key] <= 0 {
+		delete(self.writers, key)
+	}
+}
+
+// Lists both tiers. Files in the hot tier take precedence.
+func (self *TieredFileStore) ListDirectory(dirname api.FSPathSpec) (
+	[]api.FileInfo, error) {
+	hot_infos, hot_err := self.hot.ListDirectory(dirname)
+	cold_infos, cold_err := self.cold.ListDirectory(dirname)
+	if hot_err != nil && cold_err != nil {
+		return nil, hot_err
+	}
+
+	seen := make(map[string]bool)
+	result := make([]api.FileInfo, 0, len(hot_infos)+len(cold_infos))
+	for _, infos := range [][]api.FileInfo{hot_infos, cold_infos} {
+		for _, info := range infos {
+			key := info.PathSpec().AsClientPath()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, info)
+		}
+	}
+
+	return result, nil
+}
+
+func (self *TieredFileStore) Delete(path api.FSPathSpec) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.abandonMigration(path)
+
+	if !self.isCold(path) {
+		return self.hot.Delete(path)
+	}
+
+	err := self.cold.Delete(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return self.removeRecord(path)
+}
+
+func (self *TieredFileStore) Move(src, dest api.FSPathSpec) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.abandonMigration(src)
+	self.abandonMigration(dest)
+
+	err := self.recall(src)
+	if err != nil {
+		return err
+	}
+
+	// The destination is replaced by the source.
+	if self.isCold(dest) {
+		err = self.cold.Delete(dest)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		err = self.removeRecord(dest)
+		if err != nil {
+			return err
+		}
+	}
+
+	return self.hot.Move(src, dest)
+}
+
+func (self *TieredFileStore) Flush() {
+	flusher, ok := self.hot.(api.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+func (self *TieredFileStore) Close() error {
+	self.cancel()
+	self.wg.Wait()
+
+	return self.hot.Close()
+}
+
+type TieredFileWriter struct {
+	api.FileWriter
+
+	store *TieredFileStore
+	key   string
+}
+
+func (self *TieredFileWriter) Close() error {
+	err := self.FileWriter.Close()
+	self.store.release(self.key)
+	return err
+}
+
+func copyFile(src, dest api.FileStore, path api.FSPathSpec) error {
+	reader, err := src.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer, err := dest.WriteFileWithCompletion(path, utils.SyncCompleter)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Truncate()
+	if err != nil {
+		writer.Close()
+		return err
+	}
+
+	_, err = io.Copy(writer, reader)
+	if err != nil {
+		writer.Close()
+		return err
+	}
+
+	return writer.Close()
+}
@@ -0,0 +1,169 @@
+package tiered
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/directory"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/tests"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+func newTestConfig(t *testing.T) *config_proto.Config {
+	dir, err := ioutil.TempDir("", "tiered_file_store_test")
+	assert.NoError(t, err)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.Implementation = "Test"
+	config_obj.Datastore.Location = dir
+	config_obj.Datastore.FilestoreDirectory = filepath.Join(dir, "hot")
+	config_obj.Datastore.Tiering = &config_proto.FilestoreTieringConfig{
+		ColdDirectory: filepath.Join(dir, "cold"),
+		MaxAgeSec:     3600,
+	}
+	return config_obj
+}
+
+type TieredTestSuite struct {
+	suite.Suite
+
+	config_obj *config_proto.Config
+	file_store *TieredFileStore
+}
+
+func (self *TieredTestSuite) SetupTest() {
+	self.config_obj = newTestConfig(self.T())
+	self.file_store = newTieredFileStore(self.config_obj,
+		directory.NewDirectoryFileStore(self.config_obj))
+}
+
+func (self *TieredTestSuite) TearDownTest() {
+	self.file_store.Close()
+	os.RemoveAll(self.config_obj.Datastore.Location)
+}
+
+func (self *TieredTestSuite) writeFile(path_spec api.FSPathSpec, data string) {
+	fd, err := self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte(data))
+	assert.NoError(self.T(), err)
+	fd.Close()
+}
+
+func (self *TieredTestSuite) readAll(path_spec api.FSPathSpec) string {
+	reader, err := self.file_store.ReadFile(path_spec)
+	assert.NoError(self.T(), err)
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(self.T(), err)
+	return string(data)
+}
+
+func (self *TieredTestSuite) TestMigrate() {
+	old_file := path_specs.NewSafeFilestorePath("clients", "C.123", "old")
+	new_file := path_specs.NewSafeFilestorePath("clients", "C.123", "new")
+	other_file := path_specs.NewSafeFilestorePath("downloads", "old")
+
+	self.writeFile(old_file, "Hello")
+	self.writeFile(other_file, "Hello")
+	self.writeFile(new_file, "New")
+
+	// Make the old files a day old.
+	for _, path_spec := range []api.FSPathSpec{old_file, other_file} {
+		filename := path_spec.AsFilestoreFilename(self.config_obj)
+		past := time.Now().Add(-24 * time.Hour)
+		assert.NoError(self.T(), os.Chtimes(filename, past, past))
+	}
+
+	err := self.file_store.Migrate(context.Background())
+	assert.NoError(self.T(), err)
+
+	// Only the old file under the clients prefix was moved.
+	assert.True(self.T(), self.file_store.isCold(old_file))
+	assert.False(self.T(), self.file_store.isCold(new_file))
+	assert.False(self.T(), self.file_store.isCold(other_file))
+
+	_, err = self.file_store.hot.StatFile(old_file)
+	assert.True(self.T(), os.IsNotExist(err))
+
+	// It is still readable and listed.
+	assert.Equal(self.T(), "Hello", self.readAll(old_file))
+
+	info, err := self.file_store.StatFile(old_file)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), int64(5), info.Size())
+
+	infos, err := self.file_store.ListDirectory(old_file.Dir())
+	assert.NoError(self.T(), err)
+	names := []string{}
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	assert.ElementsMatch(self.T(), []string{"old", "new"}, names)
+
+	// Appending moves the file back to the hot tier.
+	self.writeFile(old_file, " World")
+	assert.False(self.T(), self.file_store.isCold(old_file))
+	assert.Equal(self.T(), "Hello World", self.readAll(old_file))
+
+	_, err = self.file_store.cold.StatFile(old_file)
+	assert.True(self.T(), os.IsNotExist(err))
+}
+
+func (self *TieredTestSuite) TestDeleteColdFile() {
+	path_spec := path_specs.NewSafeFilestorePath("hunts", "H.123", "old")
+	self.writeFile(path_spec, "Hello")
+
+	self.file_store.Clock = &utils.MockClock{
+		MockNow: time.Now().Add(24 * time.Hour)}
+	assert.NoError(self.T(), self.file_store.Migrate(context.Background()))
+	assert.True(self.T(), self.file_store.isCold(path_spec))
+
+	assert.NoError(self.T(), self.file_store.Delete(path_spec))
+	assert.False(self.T(), self.file_store.isCold(path_spec))
+
+	_, err := self.file_store.ReadFile(path_spec)
+	assert.Error(self.T(), err)
+}
+
+func (self *TieredTestSuite) TestOpenWritersAreNotMoved() {
+	path_spec := path_specs.NewSafeFilestorePath("clients", "C.123", "open")
+
+	fd, err := self.file_store.WriteFile(path_spec)
+	assert.NoError(self.T(), err)
+	defer fd.Close()
+
+	_, err = fd.Write([]byte("Hello"))
+	assert.NoError(self.T(), err)
+
+	self.file_store.Clock = &utils.MockClock{
+		MockNow: time.Now().Add(24 * time.Hour)}
+	assert.NoError(self.T(), self.file_store.Migrate(context.Background()))
+	assert.False(self.T(), self.file_store.isCold(path_spec))
+}
+
+func TestTieredFileStore(t *testing.T) {
+	suite.Run(t, &TieredTestSuite{})
+}
+
+func TestTieredFileStoreAPI(t *testing.T) {
+	config_obj := newTestConfig(t)
+	defer os.RemoveAll(config_obj.Datastore.Location)
+
+	file_store := newTieredFileStore(config_obj,
+		directory.NewDirectoryFileStore(config_obj))
+	defer file_store.Close()
+
+	suite.Run(t, tests.NewFileStoreTestSuite(config_obj, file_store))
+}
@@ -20,6 +20,7 @@ package flows
 import (
 	"bytes"
 	"context"
+	"crypto/rsa"
 	"fmt"
 	"strings"
 	"sync"
@@ -89,6 +90,16 @@ type CollectionContext struct {
 	// only happens once the collection is complete and results are
 	// written. It only happens at most once per collection.
 	send_update bool
+
+	// The client's enrolled key used to verify result signatures.
+	client_key *rsa.PublicKey
+
+	// Columns to redact from each query's results as declared by
+	// the artifact's lifecycle policy.
+	redacted_columns map[string][]string
+
+	// Hashes of the uploads completed since the last flush.
+	upload_hashes []*ordereddict.Dict
 }
 
 func NewCollectionContext(config_obj *config_proto.Config) *CollectionContext {
@@ -240,6 +251,26 @@ func closeContext(
 		}
 	}
 
+	if len(collection_context.Signatures) > 0 {
+		err := flushContextSignatures(
+			config_obj, collection_context, collection_context.completer)
+		if err != nil {
+			collection_context.State = flows_proto.ArtifactCollectorContext_ERROR
+			collection_context.Status = err.Error()
+			collection_context.Dirty = true
+		}
+	}
+
+	if len(collection_context.upload_hashes) > 0 {
+		err := flushContextUploadHashes(
+			config_obj, collection_context, collection_context.completer)
+		if err != nil {
+			collection_context.State = flows_proto.ArtifactCollectorContext_ERROR
+			collection_context.Status = err.Error()
+			collection_context.Dirty = true
+		}
+	}
+
 	if len(collection_context.monitoring_batch) > 0 {
 		err = flushMonitoringLogs(config_obj, collection_context)
 		if err != nil {
@@ -361,8 +392,25 @@ func ArtifactCollectorProcessOneMessage(
 	// Handle the response depending on the RequestId
 	switch message.RequestId {
 	case constants.TransferWellKnownFlowId:
-		return appendUploadDataToFile(
+		err := appendUploadDataToFile(
 			config_obj, collection_context, message)
+		if err != nil {
+			return err
+		}
+
+		// Hash the complete upload so the filestore scrubber has a
+		// baseline taken when it was stored. Signed uploads carry
+		// the signature in the last packet.
+		if message.FileBuffer.Eof || message.FileBuffer.Sha256 != "" {
+			actual_sha256 := addUploadHashes(
+				config_obj, collection_context, message)
+
+			if message.FileBuffer.Sha256 != "" {
+				addUploadSignature(config_obj, collection_context,
+					message, actual_sha256)
+			}
+		}
+		return nil
 
 	case constants.ProcessVQLResponses:
 		response := message.VQLResponse
@@ -402,17 +450,25 @@ func ArtifactCollectorProcessOneMessage(
 			}
 			defer rs_writer.Close()
 
+			// Columns the artifact's lifecycle policy asks us
+			// to redact before the results are stored.
+			redacted := collection_context.redactedColumns(
+				config_obj, response.Query.Name)
+
 			// Support the old clients which send JSON
 			// array responses. We need to decode the JSON
 			// response, then re-encode it into JSONL for
 			// log files.
+			var rows []*ordereddict.Dict
+			jsonl := []byte(response.JSONLResponse)
 			if len(response.Response) > 0 {
-				rows, err := utils.ParseJsonToDicts([]byte(
+				rows, err = utils.ParseJsonToDicts([]byte(
 					response.Response))
 				if err != nil {
 					return err
 				}
 
+				redactRows(rows, redacted)
 				for _, row := range rows {
 					rows_written++
 					rs_writer.Write(row)
@@ -423,13 +479,47 @@ func ArtifactCollectorProcessOneMessage(
 				// as line delimited, so we only need
 				// to append to end of the log file -
 				// much faster!
-			} else if len(response.JSONLResponse) > 0 {
-				rs_writer.WriteJSONL(
-					[]byte(response.JSONLResponse), response.TotalRows)
+			} else if len(jsonl) > 0 {
+				if len(redacted) > 0 {
+					jsonl, err = redactJsonl(jsonl, redacted)
+					if err != nil {
+						return err
+					}
+				}
+
+				rs_writer.WriteJSONL(jsonl, response.TotalRows)
 				rows_written = response.TotalRows
 				rowCounter.Add(float64(response.TotalRows))
 			}
 
+			// Store the column types the client inferred.
+			if len(response.Types) > 0 {
+				column_types := make(map[string]string)
+				for _, t := range response.Types {
+					column_types[t.Column] = t.Type
+				}
+				rs_writer.SetColumnTypes(column_types)
+			}
+
+			err = recordResultSchema(config_obj, file_store_factory,
+				path_manager.Path(), response.Query.Name,
+				responseColumns(rows, jsonl))
+			if err != nil {
+				logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+				logger.Error("ArtifactCollectorProcessOneMessage: %v", err)
+			}
+
+			// Record the client's signature over this part. The
+			// signature covers the data the client sent before
+			// redaction.
+			if collection_context.Request.SignResults ||
+				response.Sha256 != "" {
+				addSignature(config_obj, collection_context,
+					"result", response.Query.Name, response.Part,
+					response.Sha256, response.Signature,
+					sha256Hex([]byte(response.JSONLResponse)))
+			}
+
 			// Update the artifacts with results in the
 			// context.
 			if rows_written > 0 {
@@ -499,7 +589,14 @@ func appendUploadDataToFile(
 		file_buffer.Pathspec.Accessor,
 		file_buffer.Pathspec.Path)
 
-	fd, err := file_store_factory.WriteFile(file_path_manager.Path())
+	// The last packet must be stored before we can hash the upload.
+	completion := utils.BackgroundWriter
+	if file_buffer.Sha256 != "" || file_buffer.Eof {
+		completion = utils.SyncCompleter
+	}
+
+	fd, err := file_store_factory.WriteFileWithCompletion(
+		file_path_manager.Path(), completion)
 	if err != nil {
 		// If we fail to write this one file we keep going -
 		// otherwise the flow will be terminated.
@@ -683,6 +780,18 @@ func (self *FlowRunner) ProcessSingleMessage(
 	ctx context.Context,
 	job *crypto_proto.VeloMessage) {
 
+	// The foreman check in carries the client's load which is
+	// used to schedule hunts on its next check in.
+	if job.ForemanCheckin != nil {
+		err := UpdateClientLoad(ctx, self.config_obj, job.Source,
+			job.ForemanCheckin.Load)
+		if err != nil {
+			logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+			logger.Debug("UpdateClientLoad for client %v: %v", job.Source, err)
+		}
+		return
+	}
+
 	// Only process real flows.
 	if !strings.HasPrefix(job.SessionId, "F.") {
 		return
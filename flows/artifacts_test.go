@@ -342,6 +342,68 @@ func (self *TestSuite) TestResourceLimits() {
 		flows_proto.ArtifactCollectorContext_ERROR)
 }
 
+func (self *TestSuite) TestLifecycleRedaction() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+	repository, err := manager.GetGlobalRepository(
+		self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Redacted
+lifecycle:
+  redact:
+   - Password
+sources:
+- query: SELECT * FROM info()
+`, true, true)
+	assert.NoError(self.T(), err)
+
+	ctx := context.Background()
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	flow_id, err := launcher.ScheduleArtifactCollection(
+		ctx, self.ConfigObj,
+		acl_managers.NullACLManager{},
+		repository, &flows_proto.ArtifactCollectorArgs{
+			ClientId:  self.client_id,
+			Artifacts: []string{"Test.Redacted"},
+		}, nil)
+	assert.NoError(self.T(), err)
+
+	runner := NewFlowRunner(self.ConfigObj)
+	runner.ProcessSingleMessage(ctx, &crypto_proto.VeloMessage{
+		Source:     self.client_id,
+		SessionId:  flow_id,
+		RequestId:  1,
+		ResponseId: 1,
+		VQLResponse: &actions_proto.VQLResponse{
+			JSONLResponse: `{"User":"admin","Password":"hunter2"}` + "\n",
+			TotalRows:     1,
+			Query: &actions_proto.VQLRequest{
+				Name: "Test.Redacted",
+			},
+		},
+	})
+	runner.Close(context.Background())
+
+	path_manager, err := artifacts.NewArtifactPathManager(
+		self.ConfigObj, self.client_id, flow_id, "Test.Redacted")
+	assert.NoError(self.T(), err)
+
+	// The redacted column is never stored.
+	rows := test_utils.FileReadRows(self.T(), self.ConfigObj,
+		path_manager.Path())
+	assert.Equal(self.T(), 1, len(rows))
+
+	user, _ := rows[0].GetString("User")
+	assert.Equal(self.T(), "admin", user)
+
+	password, _ := rows[0].GetString("Password")
+	assert.Equal(self.T(), REDACTED, password)
+}
+
 func (self *TestSuite) TestClientUploaderStoreFile() {
 	resp := responder.TestResponder()
 	uploader := &uploads.VelociraptorUploader{
@@ -89,9 +89,11 @@ func UpdateFlowStats(collection_context *CollectionContext) {
 	collection_context.Status = ""
 	collection_context.Backtrace = ""
 	for _, s := range collection_context.QueryStats {
-		// Get the first errored query.
+		// Get the first errored query. Skipped queries were never
+		// sent to the client so they are not errors.
 		if collection_context.State == flows_proto.ArtifactCollectorContext_RUNNING &&
-			s.Status != crypto_proto.VeloStatus_OK {
+			s.Status != crypto_proto.VeloStatus_OK &&
+			s.Status != crypto_proto.VeloStatus_SKIPPED {
 			collection_context.State = flows_proto.ArtifactCollectorContext_ERROR
 			collection_context.Status = s.ErrorMessage
 			collection_context.Backtrace = s.Backtrace
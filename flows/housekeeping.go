@@ -20,6 +20,7 @@ package flows
 
 import (
 	"context"
+	"time"
 
 	"github.com/Velocidex/ordereddict"
 	errors "github.com/go-errors/errors"
@@ -28,6 +29,7 @@ import (
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 var (
@@ -35,8 +37,75 @@ var (
 		Name: "client_event_update",
 		Help: "Total number of client Event Table Update messages sent.",
 	})
+
+	huntLoadDeferralCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "hunt_load_deferral",
+		Help: "Total number of times a heavy hunt was deferred on a busy client.",
+	})
+)
+
+const (
+	// Loads reported longer ago than this are ignored.
+	MAX_LOAD_AGE_SEC = 600
 )
 
+// Record the load the client reported in its foreman check in.
+func UpdateClientLoad(ctx context.Context,
+	config_obj *config_proto.Config, client_id string, load uint64) error {
+	client_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return client_manager.UpdateStats(ctx, client_id, &services.Stats{
+		Load:     load,
+		LoadTime: uint64(utils.GetTime().Now().UnixNano() / 1000),
+	})
+}
+
+// Is the client too busy to be scheduled heavy hunts?
+func clientIsBusy(config_obj *config_proto.Config, stats *services.Stats) bool {
+	if config_obj.Defaults == nil || config_obj.Defaults.HuntLoadThreshold == 0 {
+		return false
+	}
+
+	// A client that has not reported its load yet is not busy.
+	now := uint64(utils.GetTime().Now().UnixNano() / 1000)
+	if stats.LoadTime == 0 || stats.LoadTime+MAX_LOAD_AGE_SEC*1000000 < now {
+		return false
+	}
+
+	return stats.Load > config_obj.Defaults.HuntLoadThreshold
+}
+
+// A hunt is heavy if any of its artifacts are marked heavy.
+func huntIsHeavy(config_obj *config_proto.Config,
+	repository services.Repository, hunt *api_proto.Hunt) bool {
+	if hunt.StartRequest == nil {
+		return false
+	}
+
+	for _, name := range hunt.StartRequest.Artifacts {
+		artifact, pres := repository.Get(config_obj, name)
+		if pres && artifact.Resources.GetHeavy() {
+			return true
+		}
+	}
+	return false
+}
+
+// Heavy hunts are only deferred for a limited time, if configured.
+func deferralExpired(config_obj *config_proto.Config, hunt *api_proto.Hunt) bool {
+	max_deferral := config_obj.Defaults.HuntMaxLoadDeferralSec
+	if max_deferral == 0 {
+		return false
+	}
+
+	started := time.Unix(0, int64(hunt.StartTime)*1000)
+	return utils.GetTime().Now().Sub(started) >
+		time.Duration(max_deferral)*time.Second
+}
+
 func CheckClientStatus(
 	ctx context.Context,
 	config_obj *config_proto.Config,
@@ -99,6 +168,25 @@ func CheckClientStatus(
 		return nil
 	}
 
+	// Heavy hunts are deferred while the client is busy and retried
+	// on a later check in.
+	var repository services.Repository
+	busy := clientIsBusy(config_obj, stats)
+	if busy {
+		manager, err := services.GetRepositoryManager(config_obj)
+		if err != nil {
+			return err
+		}
+
+		repository, err = manager.GetGlobalRepository(config_obj)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The earliest start time of the deferred hunts.
+	earliest_deferred := uint64(0)
+
 	// Take a snapshot of the hunts that we need to run on this
 	// client to reduce the time under lock.
 	hunts := make([]*api_proto.Hunt, 0)
@@ -113,6 +201,15 @@ func CheckClientStatus(
 			return nil
 		}
 
+		if busy && huntIsHeavy(config_obj, repository, hunt) &&
+			!deferralExpired(config_obj, hunt) {
+			huntLoadDeferralCounter.Inc()
+			if earliest_deferred == 0 || hunt.StartTime < earliest_deferred {
+				earliest_deferred = hunt.StartTime
+			}
+			return nil
+		}
+
 		// Take a snapshot of the hunt id and start time.
 		hunts = append(hunts, &api_proto.Hunt{
 			HuntId:    hunt.HuntId,
@@ -152,6 +249,14 @@ func CheckClientStatus(
 		}
 	}
 
+	// Do not move the client past the deferred hunts so they are
+	// considered again next time. Hunts started after them may be
+	// sent again but the hunt manager ignores hunts which already
+	// ran on the client.
+	if earliest_deferred > 0 && latest_timestamp >= earliest_deferred {
+		latest_timestamp = earliest_deferred - 1
+	}
+
 	client_manager.UpdateStats(ctx, client_id, &services.Stats{
 		LastHuntTimestamp: latest_timestamp,
 	})
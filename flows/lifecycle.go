@@ -0,0 +1,57 @@
+package flows
+
+// Artifacts may declare lifecycle policies for their results (see
+// ArtifactLifecycle in artifacts/proto/artifact.proto). Redaction is
+// applied here as the results are received so redacted values are
+// never stored on the server. Retention and forwarding are applied
+// by the lifecycle service when the collection completes.
+
+import (
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	REDACTED = "<REDACTED>"
+)
+
+// The columns to redact from the named query. Looking up the
+// artifact is expensive so the result is cached in the context.
+func (self *CollectionContext) redactedColumns(
+	config_obj *config_proto.Config, name string) []string {
+	if self.redacted_columns == nil {
+		self.redacted_columns = make(map[string][]string)
+	}
+
+	columns, pres := self.redacted_columns[name]
+	if !pres {
+		columns = services.GetArtifactLifecycle(config_obj, name).GetRedact()
+		self.redacted_columns[name] = columns
+	}
+	return columns
+}
+
+func redactRows(rows []*ordereddict.Dict, columns []string) {
+	for _, row := range rows {
+		for _, column := range columns {
+			_, pres := row.Get(column)
+			if pres {
+				row.Update(column, REDACTED)
+			}
+		}
+	}
+}
+
+// Redact JSONL encoded rows.
+func redactJsonl(jsonl []byte, columns []string) ([]byte, error) {
+	rows, err := utils.ParseJsonToDicts(jsonl)
+	if err != nil {
+		return nil, err
+	}
+
+	redactRows(rows, columns)
+	return json.MarshalJsonl(rows)
+}
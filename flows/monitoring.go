@@ -59,8 +59,19 @@ func MonitoringProcessMessage(
 		}
 		monitoringRowCounter.Add(float64(response.TotalRows))
 
+		jsonl := []byte(json_response)
+		redacted := collection_context.redactedColumns(
+			config_obj, response.Query.Name)
+		if len(redacted) > 0 {
+			var err error
+			jsonl, err = redactJsonl(jsonl, redacted)
+			if err != nil {
+				return err
+			}
+		}
+
 		new_json_response := json.AppendJsonlItem(
-			[]byte(json_response), "ClientId", message.Source)
+			jsonl, "ClientId", message.Source)
 
 		// Batch the rows to send together.
 		collection_context.batchRows(response.Query.Name, new_json_response)
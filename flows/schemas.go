@@ -0,0 +1,102 @@
+package flows
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const maxResultSchemas = 10000
+
+var (
+	// The schema last written for each result set so we only
+	// update it when new columns appear. The mutex only protects
+	// the maps - the result set's own lock is held while reading
+	// and writing its schema.
+	result_schemas_mu    sync.Mutex
+	result_schemas       = make(map[string]*api_proto.ArtifactSchema)
+	result_schemas_locks = make(map[string]*schemaLock)
+)
+
+type schemaLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func lockResultSchema(key string) func() {
+	result_schemas_mu.Lock()
+	lock, pres := result_schemas_locks[key]
+	if !pres {
+		lock = &schemaLock{}
+		result_schemas_locks[key] = lock
+	}
+	lock.refs++
+	result_schemas_mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		result_schemas_mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(result_schemas_locks, key)
+		}
+		result_schemas_mu.Unlock()
+	}
+}
+
+func getCachedSchema(key string) (*api_proto.ArtifactSchema, bool) {
+	result_schemas_mu.Lock()
+	defer result_schemas_mu.Unlock()
+
+	schema, pres := result_schemas[key]
+	return schema, pres
+}
+
+func setCachedSchema(key string, schema *api_proto.ArtifactSchema) {
+	key := config_obj.OrgId + log_path.AsClientPath()
+	unlock := lockResultSchema(key)
+	defer unlock()
+
+	existing, pres := getCachedSchema(key)
+	if !pres {
+		existing, _ = result_sets.ReadSchema(file_store_factory, log_path)
+	}
+
+	if existing != nil {
+		merged := utils.MergeStringSlices(existing.Columns, columns)
+		if len(merged) == len(existing.Columns) {
+			setCachedSchema(key, existing)
+			return nil
+		}
+		columns = merged
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	schema, err := manager.RecordResultSchema(config_obj, name, columns)
+	if err != nil {
+		return err
+	}
+
+	err = result_sets.WriteSchema(file_store_factory, log_path, schema)
+	if err != nil {
+		return err
+	}
+
+	setCachedSchema(key, schema)
+
+	return nil
+}
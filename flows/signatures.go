@@ -0,0 +1,189 @@
+package flows
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	errors "github.com/go-errors/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	signatureFailureCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "result_signature_failures",
+		Help: "Total number of client result signatures that failed to verify.",
+	})
+)
+
+// Record a signature the client sent over a result set part or an
+// upload. Signatures are verified against the key the client
+// enrolled with, and the hash the client claims must match the hash
+// of the data we actually stored (actual_sha256).
+func addSignature(
+	config_obj *config_proto.Config,
+	collection_context *CollectionContext,
+	signature_type, name string, part uint64,
+	sha256_hex string, signature []byte, actual_sha256 string) {
+
+	record := &flows_proto.ResultSignature{
+		Type:      signature_type,
+		Name:      name,
+		Part:      part,
+		Sha256:    sha256_hex,
+		Signature: signature,
+		Timestamp: uint64(time.Now().UTC().Unix()),
+	}
+
+	err := verifySignature(
+		config_obj, collection_context, record, actual_sha256)
+	if err != nil {
+		signatureFailureCounter.Inc()
+		record.Error = err.Error()
+		Log(config_obj, collection_context, fmt.Sprintf(
+			"Unable to verify client signature for %v: %v", name, err))
+	} else {
+		record.Verified = true
+	}
+
+	collection_context.Signatures = append(
+		collection_context.Signatures, record)
+	collection_context.Dirty = true
+}
+
+func verifySignature(
+	config_obj *config_proto.Config,
+	collection_context *CollectionContext,
+	record *flows_proto.ResultSignature, actual_sha256 string) error {
+
+	if len(record.Signature) == 0 {
+		return errors.New("Client did not sign the data")
+	}
+
+	if actual_sha256 != record.Sha256 {
+		return errors.New("Hash does not match the data")
+	}
+
+	key, err := collection_context.getClientKey(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return crypto_utils.VerifySha256(key, record.Sha256, record.Signature)
+}
+
+// The last packet of a signed upload carries the signature. By the
+// time it arrives the upload is stored so we compare the signed hash
+// to the hash of the stored data rather than trusting the hash the
+// client claims.
+func addUploadSignature(
+	config_obj *config_proto.Config,
+	collection_context *CollectionContext,
+	message *crypto_proto.VeloMessage, actual_sha256 string) {
+	file_buffer := message.FileBuffer
+
+	file_path_manager := paths.NewFlowPathManager(
+		message.Source, collection_context.SessionId).GetUploadsFile(
+		file_buffer.Pathspec.Accessor, file_buffer.Pathspec.Path)
+
+	addSignature(config_obj, collection_context,
+		"upload", file_path_manager.VisibleVFSPath(), 0,
+		file_buffer.Sha256, file_buffer.Signature, actual_sha256)
+}
+
+func hashStoredFile(
+	file_store_factory api.FileStore, path api.FSPathSpec) (string, error) {
+	reader, err := file_store_factory.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	sha_sum := sha256.New()
+	_, err = utils.Copy(context.Background(), sha_sum, reader)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sha_sum.Sum(nil)), nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// The client's enrolled public key is cached for the life of the
+// context.
+func (self *CollectionContext) getClientKey(
+	config_obj *config_proto.Config) (*rsa.PublicKey, error) {
+	if self.client_key != nil {
+		return self.client_key, nil
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	pem := &crypto_proto.PublicKey{}
+	err = db.GetSubject(config_obj,
+		paths.NewClientPathManager(self.ClientId).Key(), pem)
+	if err != nil {
+		return nil, err
+	}
+
+	self.client_key, err = crypto_utils.PemToPublicKey(pem.Pem)
+	return self.client_key, err
+}
+
+func flushContextSignatures(
+	config_obj *config_proto.Config,
+	collection_context *CollectionContext,
+	completion *utils.Completer) error {
+
+	flow_path_manager := paths.NewFlowPathManager(
+		collection_context.ClientId,
+		collection_context.SessionId).Signatures()
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	rs_writer, err := result_sets.NewResultSetWriter(
+		file_store_factory, flow_path_manager,
+		nil, /* opts */
+		completion.GetCompletionFunc(),
+		false /* truncate */)
+	if err != nil {
+		return err
+	}
+	defer rs_writer.Close()
+
+	for _, record := range collection_context.Signatures {
+		rs_writer.Write(ordereddict.NewDict().
+			Set("Timestamp", record.Timestamp).
+			Set("Type", record.Type).
+			Set("Name", record.Name).
+			Set("Part", record.Part).
+			Set("Sha256", record.Sha256).
+			Set("Signature", hex.EncodeToString(record.Signature)).
+			Set("Verified", record.Verified).
+			Set("Error", record.Error))
+	}
+
+	// Clear the signatures from the flow object.
+	collection_context.Signatures = nil
+	return nil
+}
@@ -0,0 +1,118 @@
+package flows
+
+// The hash of each upload is recorded when the upload completes. The
+// filestore scrubber later compares the stored files to these hashes
+// so corruption after the upload was stored can be detected.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Hash the stored upload and its index (for sparse uploads). Returns
+// the hash of the upload or "" if it could not be hashed.
+func addUploadHashes(
+	config_obj *config_proto.Config,
+	collection_context *CollectionContext,
+	message *crypto_proto.VeloMessage) string {
+	file_buffer := message.FileBuffer
+	if file_buffer == nil || file_buffer.Pathspec == nil {
+		return ""
+	}
+
+	file_path_manager := paths.NewFlowPathManager(
+		message.Source, collection_context.SessionId).GetUploadsFile(
+		file_buffer.Pathspec.Accessor, file_buffer.Pathspec.Path)
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	actual_sha256, size, err := hashUpload(
+		file_store_factory, file_path_manager.Path())
+	if err != nil {
+		Log(config_obj, collection_context, fmt.Sprintf(
+			"Unable to hash upload %v: %v",
+			file_path_manager.VisibleVFSPath(), err))
+		return ""
+	}
+
+	key := utils.JoinComponents(file_path_manager.Path().Components(), "/")
+	collection_context.addUploadHash(key, actual_sha256, size)
+
+	if file_buffer.IsSparse {
+		index_sha256, index_size, err := hashUpload(
+			file_store_factory, file_path_manager.IndexPath())
+		if err == nil {
+			collection_context.addUploadHash(key+".idx", index_sha256, index_size)
+		}
+	}
+
+	return actual_sha256
+}
+
+// Hashes the data as the scrubber reads it back.
+func hashUpload(file_store_factory api.FileStore,
+	path api.FSPathSpec) (string, int64, error) {
+	reader, err := file_store_factory.ReadFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer reader.Close()
+
+	sha_sum := sha256.New()
+	n, err := utils.Copy(context.Background(), sha_sum, reader)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(sha_sum.Sum(nil)), int64(n), nil
+}
+
+// Rows use the same format the scrubber writes.
+func (self *CollectionContext) addUploadHash(key, hash string, size int64) {
+	self.upload_hashes = append(self.upload_hashes, ordereddict.NewDict().
+		Set("Path", key).
+		Set("Sha256", hash).
+		Set("Size", size).
+		Set("Recorded", time.Now().UTC().Unix()).
+		Set("Verified", int64(0)).
+		Set("Corrupted", false))
+	self.Dirty = true
+}
+
+func flushContextUploadHashes(
+	config_obj *config_proto.Config,
+	collection_context *CollectionContext,
+	completion *utils.Completer) error {
+
+	flow_path_manager := paths.NewFlowPathManager(
+		collection_context.ClientId,
+		collection_context.SessionId).UploadHashes()
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	rs_writer, err := result_sets.NewResultSetWriter(
+		file_store_factory, flow_path_manager,
+		nil, /* opts */
+		completion.GetCompletionFunc(),
+		false /* truncate */)
+	if err != nil {
+		return err
+	}
+	defer rs_writer.Close()
+
+	for _, row := range collection_context.upload_hashes {
+		rs_writer.Write(row)
+	}
+
+	collection_context.upload_hashes = nil
+	return nil
+}
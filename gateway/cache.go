@@ -0,0 +1,210 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	default_max_cache_size = 1024 * 1024 * 1024
+	default_cache_expiry   = 3600
+)
+
+type fetcher func(ctx context.Context, uri string) (*http.Response, error)
+
+// Caches tools downloaded from the server's public directory. Clients
+// verify the hash of every tool they download so the cache does not
+// need to be trusted. Clients which set Client.fetch_tools_via_frontend
+// include the tool's hash in the URL, so new versions of a tool are
+// fetched straight away, otherwise cached tools expire after a while.
+type ToolCache struct {
+	directory string
+	max_size  int64
+	expiry    time.Duration
+
+	// One lock per cached URL so many clients asking for the same
+	// tool only cause a single download. Locks are removed once
+	// nobody holds or waits for them.
+	mu    sync.Mutex
+	locks map[string]*keyLock
+
+	clock utils.Clock
+}
+
+func NewToolCache(config_obj *config_proto.GatewayConfig) (*ToolCache, error) {
+	directory := os.ExpandEnv(config_obj.CacheDirectory)
+	err := os.MkdirAll(directory, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	max_size := int64(config_obj.MaxCacheSize)
+	if max_size == 0 {
+		max_size = default_max_cache_size
+	}
+
+	expiry := config_obj.CacheExpiry
+	if expiry == 0 {
+		expiry = default_cache_expiry
+	}
+
+	return &ToolCache{
+		directory: directory,
+		max_size:  max_size,
+		expiry:    time.Duration(expiry) * time.Second,
+		locks:     make(map[string]*keyLock),
+		clock:     utils.RealClock{},
+	}, nil
+}
+
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (self *ToolCache) lock(key string) func() {
+	self.mu.Lock()
+	lock, pres := self.locks[key]
+	if !pres {
+		lock = &keyLock{}
+		self.locks[key] = lock
+	}
+	lock.refs++
+	self.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		self.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(self.locks, key)
+		}
+		self.mu.Unlock()
+	}
+}
+
+// Serve the tool from the cache, fetching it first if needed.
+func (self *ToolCache) Serve(
+	w http.ResponseWriter, r *http.Request, fetch fetcher) {
+	uri := r.URL.RequestURI()
+	hash := sha256.Sum256([]byte(uri))
+	key := hex.EncodeToString(hash[:])
+	path := filepath.Join(self.directory, key)
+
+	unlock := self.lock(key)
+	stat, err := os.Stat(path)
+	if err != nil || self.clock.Now().Sub(stat.ModTime()) > self.expiry {
+		err = self.fetch(r.Context(), w, uri, path, fetch)
+		if err != nil {
+			unlock()
+			return
+		}
+	}
+	unlock()
+
+	fd, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	defer fd.Close()
+
+	stat, err = fd.Stat()
+	if err != nil {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "binary/octet-stream")
+	http.ServeContent(w, r, "", stat.ModTime(), fd)
+}
+
+// Download the tool into the cache. If the frontend does not have the
+// tool we pass its response on to the client.
+func (self *ToolCache) fetch(ctx context.Context, w http.ResponseWriter,
+	uri, path string, fetch fetcher) error {
+	resp, err := fetch(ctx, uri)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+		return os.ErrNotExist
+	}
+
+	tmpfile, err := ioutil.TempFile(self.directory, "tmp")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+	defer os.Remove(tmpfile.Name())
+
+	_, err = utils.Copy(ctx, tmpfile, resp.Body)
+	tmpfile.Close()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return err
+	}
+
+	err = os.Rename(tmpfile.Name(), path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	self.expire(path)
+	return nil
+}
+
+// Remove the least recently fetched tools until the cache fits in
+// max_size. The file we just fetched is always kept.
+func (self *ToolCache) expire(keep string) {
+	files, err := ioutil.ReadDir(self.directory)
+	if err != nil {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+
+	total := int64(0)
+	for _, file := range files {
+		total += file.Size()
+	}
+
+	for _, file := range files {
+		if total <= self.max_size {
+			return
+		}
+
+		path := filepath.Join(self.directory, file.Name())
+		if path == keep || strings.HasPrefix(file.Name(), "tmp") {
+			continue
+		}
+
+		if os.Remove(path) == nil {
+			total -= file.Size()
+		}
+	}
+}
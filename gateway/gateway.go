@@ -0,0 +1,342 @@
+// A relay for clients in restricted networks.
+
+// Networks such as OT or retail sites often do not allow endpoints to
+// connect to the internet. The gateway runs inside the restricted
+// network: clients connect to it as if it was a frontend and it
+// relays their requests to the real frontends. Client messages are
+// encrypted end to end so the gateway does not hold any of the
+// server's keys.
+
+// All requests are relayed over HTTP/2 where the frontend supports
+// it, so the gateway only keeps a single outbound connection open to
+// the frontend regardless of how many clients it serves. Tools
+// downloaded from the server's public directory are cached on disk so
+// they are only transferred once for the whole network.
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/crypto"
+	"www.velocidex.com/golang/velociraptor/logging"
+)
+
+type Gateway struct {
+	config_obj *config_proto.Config
+	logger     *logging.LogContext
+
+	// The frontends we relay to. We stick with the current
+	// frontend until it fails.
+	mu        sync.Mutex
+	upstreams []*url.URL
+	current   int
+
+	transport *http.Transport
+	proxy     *httputil.ReverseProxy
+	cache     *ToolCache
+
+	// The scheme clients use to reach us.
+	scheme string
+}
+
+func NewGateway(config_obj *config_proto.Config) (*Gateway, error) {
+	if config_obj.Client == nil || len(config_obj.Client.ServerUrls) == 0 {
+		return nil, errors.New("Gateway requires Client.server_urls")
+	}
+
+	if config_obj.Gateway == nil {
+		return nil, errors.New("Gateway not configured")
+	}
+
+	self := &Gateway{
+		config_obj: config_obj,
+		logger:     logging.GetLogger(config_obj, &logging.ClientComponent),
+		scheme:     "http",
+	}
+
+	if config_obj.Gateway.Certificate != "" {
+		self.scheme = "https"
+	}
+
+	for _, server_url := range config_obj.Client.ServerUrls {
+		parsed, err := url.Parse(server_url)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid server url %v: %w", server_url, err)
+		}
+		self.upstreams = append(self.upstreams, parsed)
+	}
+
+	// Verify the frontends the same way clients do.
+	CA_Pool := x509.NewCertPool()
+	err := crypto.AddDefaultCerts(config_obj.Client, CA_Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	tls_config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    CA_Pool,
+	}
+
+	if config_obj.Client.UseSelfSignedSsl {
+		tls_config.ServerName = config_obj.Client.PinnedServerName
+	} else {
+		crypto.AddPublicRoots(tls_config.RootCAs)
+	}
+
+	timeout := config_obj.Client.ConnectionTimeout
+	if timeout == 0 {
+		timeout = 300
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   time.Duration(timeout) * time.Second,
+		KeepAlive: time.Duration(timeout) * time.Second,
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if config_obj.Client.Proxy != "" {
+		proxy_url, err := url.Parse(config_obj.Client.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid proxy %v: %w",
+				config_obj.Client.Proxy, err)
+		}
+		proxy = http.ProxyURL(proxy_url)
+	}
+
+	self.transport = &http.Transport{
+		DialContext:         dialer.DialContext,
+		Proxy:               proxy,
+		TLSClientConfig:     tls_config,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     time.Duration(timeout) * time.Second,
+		TLSHandshakeTimeout: time.Duration(timeout) * time.Second,
+	}
+
+	self.proxy = &httputil.ReverseProxy{
+		Director:       self.director,
+		Transport:      self.transport,
+		ModifyResponse: self.modifyResponse,
+		ErrorHandler:   self.errorHandler,
+
+		// The reader handler trickles data to keep the connection
+		// alive so responses must be passed on immediately.
+		FlushInterval: -1,
+	}
+
+	if config_obj.Gateway.CacheDirectory != "" {
+		self.cache, err = NewToolCache(config_obj.Gateway)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return self, nil
+}
+
+func (self *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if self.cache != nil && r.Method == http.MethodGet &&
+		strings.HasPrefix(r.URL.Path, "/public/") {
+		self.cache.Serve(w, r, self.fetchTool)
+		return
+	}
+
+	self.proxy.ServeHTTP(w, r)
+}
+
+// Listen for clients until the context is done.
+func (self *Gateway) Start(ctx context.Context, wg *sync.WaitGroup) error {
+	gateway_config := self.config_obj.Gateway
+	server := &http.Server{
+		Addr: fmt.Sprintf("%s:%d",
+			gateway_config.BindAddress, gateway_config.BindPort),
+		Handler: self,
+	}
+
+	if self.scheme == "https" {
+		cert, err := tls.X509KeyPair(
+			[]byte(gateway_config.Certificate),
+			[]byte(gateway_config.PrivateKey))
+		if err != nil {
+			return err
+		}
+
+		server.TLSConfig = &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		}
+	}
+
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+
+	self.logger.Info("Gateway listening on %v://%v relaying to %v",
+		self.scheme, server.Addr, self.config_obj.Client.ServerUrls)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		var err error
+		if self.scheme == "https" {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			self.logger.Error("Gateway: %v", err)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		time_ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := server.Shutdown(time_ctx)
+		if err != nil {
+			self.logger.Error("Gateway shutdown: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (self *Gateway) currentUpstream() *url.URL {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.upstreams[self.current]
+}
+
+// Move to the next frontend after the current one failed.
+func (self *Gateway) advanceUpstream(failed *url.URL) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	// Another request may have already moved on.
+	if self.upstreams[self.current] != failed {
+		return
+	}
+
+	self.current = (self.current + 1) % len(self.upstreams)
+	self.logger.Info("Gateway: switching to frontend %v",
+		self.upstreams[self.current])
+}
+
+// Switch to the frontend we were redirected to.
+func (self *Gateway) setUpstream(location string) {
+	parsed, err := url.Parse(location)
+	if err != nil || parsed.Host == "" {
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for idx, upstream := range self.upstreams {
+		if upstream.String() == parsed.String() {
+			self.current = idx
+			return
+		}
+	}
+
+	// Frontends may redirect to new frontends we did not know
+	// about.
+	self.upstreams = append(self.upstreams, parsed)
+	self.current = len(self.upstreams) - 1
+}
+
+func (self *Gateway) director(req *http.Request) {
+	upstream := self.currentUpstream()
+
+	// Remember how the client reached us so we can rewrite
+	// redirects.
+	req.Header.Set("X-Forwarded-Host", req.Host)
+
+	req.URL.Scheme = upstream.Scheme
+	req.URL.Host = upstream.Host
+	req.URL.Path = joinPath(upstream.Path, req.URL.Path)
+	req.Host = upstream.Host
+}
+
+// Frontends redirect clients to other frontends. The client should
+// keep talking to us so we follow the redirect ourselves and point
+// the client back at the gateway.
+func (self *Gateway) modifyResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusMovedPermanently {
+		return nil
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil
+	}
+
+	self.setUpstream(location)
+	resp.Header.Set("Location", fmt.Sprintf("%s://%s/", self.scheme,
+		resp.Request.Header.Get("X-Forwarded-Host")))
+	return nil
+}
+
+func (self *Gateway) errorHandler(
+	w http.ResponseWriter, r *http.Request, err error) {
+	if r.Context().Err() != nil {
+		return
+	}
+
+	upstream := self.currentUpstream()
+	self.logger.Info("Gateway: request to %v failed: %v", upstream, err)
+	self.advanceUpstream(upstream)
+
+	// The client will retry the request.
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+// Fetch a tool from the current frontend.
+func (self *Gateway) fetchTool(
+	ctx context.Context, uri string) (*http.Response, error) {
+	upstream := self.currentUpstream()
+	target := *upstream
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	target.Path = joinPath(upstream.Path, parsed.Path)
+	target.RawQuery = parsed.RawQuery
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := self.transport.RoundTrip(req)
+	if err != nil {
+		self.advanceUpstream(upstream)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func joinPath(base, path string) string {
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+func TestGateway(t *testing.T) {
+	var tool_fetches int32
+	var redirected_to string
+
+	frontend := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/control":
+				body, _ := ioutil.ReadAll(r.Body)
+				w.Write(append([]byte("echo "), body...))
+
+			case "/reader":
+				w.Header().Set("Location", redirected_to)
+				w.WriteHeader(http.StatusMovedPermanently)
+
+			case "/public/tool":
+				atomic.AddInt32(&tool_fetches, 1)
+				w.Write([]byte("tool data"))
+
+			default:
+				http.Error(w, "Not Found", http.StatusNotFound)
+			}
+		}))
+	defer frontend.Close()
+
+	redirected_to = frontend.URL + "/"
+
+	cache_dir, err := ioutil.TempDir("", "gateway")
+	require.NoError(t, err)
+	defer os.RemoveAll(cache_dir)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Client.ServerUrls = []string{frontend.URL + "/"}
+	config_obj.Gateway = &config_proto.GatewayConfig{
+		CacheDirectory: cache_dir,
+	}
+
+	gw, err := NewGateway(config_obj)
+	require.NoError(t, err)
+
+	gateway_server := httptest.NewServer(gw)
+	defer gateway_server.Close()
+
+	// Client messages are relayed as is.
+	resp, err := http.Post(gateway_server.URL+"/control",
+		"application/binary", bytes.NewReader([]byte("hello")))
+	require.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "echo hello", string(body))
+
+	// Redirects point the client back at the gateway.
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err = client.Post(gateway_server.URL+"/reader",
+		"application/binary", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, gateway_server.URL+"/", resp.Header.Get("Location"))
+
+	// Tools are only fetched from the frontend once.
+	for i := 0; i < 3; i++ {
+		resp, err = http.Get(gateway_server.URL + "/public/tool?sha256=1234")
+		require.NoError(t, err)
+		body, _ = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "tool data", string(body))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tool_fetches))
+
+	// Missing tools are not cached.
+	resp, err = http.Get(gateway_server.URL + "/public/missing")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	files, err := ioutil.ReadDir(cache_dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(files))
+
+	// Locks are not kept after the requests are done.
+	gw.cache.mu.Lock()
+	assert.Equal(t, 0, len(gw.cache.locks))
+	gw.cache.mu.Unlock()
+}
@@ -25,6 +25,8 @@ import api from '../core/api-service.jsx';
 import VeloTimestamp from "../utils/time.jsx";
 import ClientLink from '../clients/client-link.jsx';
 import HexView from '../utils/hex.jsx';
+import NumberFormatter from '../utils/number.jsx';
+import PathspecRenderer from '../utils/pathspec.jsx';
 import TableTransformDialog from './table-transform-dialog.jsx';
 import T from '../i8n/i8n.jsx';
 import UserConfig from '../core/user.jsx';
@@ -203,6 +205,7 @@ class VeloPagedTable extends Component {
                 let type = column_types[i].type;
                 switch (type) {
                 case "base64":
+                case "bytes":
                     return (cell, row, rowIndex)=>{
                         let decoded = cell.slice(0,1000);
                         try {
@@ -217,6 +220,16 @@ class VeloPagedTable extends Component {
                 case "client_id":
                     return (cell, row, rowIndex)=><ClientLink client_id={cell}/>;
 
+                case "integer":
+                case "float":
+                    return (cell, row, rowIndex)=><NumberFormatter value={cell}/>;
+
+                case "pathspec":
+                    return (cell, row, rowIndex)=><PathspecRenderer
+                                                    path={cell}
+                                                    client_id={this.props.params &&
+                                                               this.props.params.client_id}/>;
+
                 default:
                     return this.defaultFormatter;
                 }
@@ -417,7 +430,7 @@ class VeloPagedTable extends Component {
             rows[j]["_id"] = j;
         }
 
-        columns = formatColumns(columns);
+        columns = formatColumns(columns, this.props.params);
 
         let total_size = this.state.total_size || 0;
         if (total_size < 0 && !_.isEmpty(this.state.rows)) {
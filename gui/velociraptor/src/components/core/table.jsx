@@ -26,6 +26,7 @@ import VeloValueRenderer from '../utils/value.jsx';
 import api from '../core/api-service.jsx';
 import { NavLink } from "react-router-dom";
 import ClientLink from '../clients/client-link.jsx';
+import UploadLink from '../utils/upload.jsx';
 import { HexViewPopup } from '../utils/hex.jsx';
 import OverlayTrigger from 'react-bootstrap/OverlayTrigger';
 import Tooltip from 'react-bootstrap/Tooltip';
@@ -411,7 +412,11 @@ export function sortCaret(order, column) {
     return null;
 }
 
-export function formatColumns(columns) {
+// The env provides the context of the table (e.g. the client_id and
+// flow_id of a collection's results) so cells can link to it when the
+// row itself does not have a ClientId or FlowId column.
+export function formatColumns(columns, env) {
+    env = env || {};
     _.each(columns, (x) => {
         x.headerFormatter=headerFormatter;
         if (x.sort) {
@@ -525,7 +530,7 @@ export function formatColumns(columns) {
 
         case "flow":
             x.formatter = (cell, row) => {
-                let client_id = row["ClientId"];
+                let client_id = row["ClientId"] || env.client_id;
                 if (!client_id) {
                     return cell;
                 };
@@ -547,6 +552,7 @@ export function formatColumns(columns) {
             break;
 
         case "hex":
+        case "hexdump":
             x.formatter = (cell, row) => {
                 return <HexViewPopup data={cell}/>;
             };
@@ -566,6 +572,17 @@ export function formatColumns(columns) {
             x.type = null;
             break;
 
+            // The response from the upload() function.
+        case "upload":
+        case "download":
+            x.formatter = (cell, row) => {
+                return <UploadLink upload={cell}
+                                   client_id={row["ClientId"] || env.client_id}
+                                   flow_id={row["FlowId"] || env.flow_id}/>;
+            };
+            x.type = null;
+            break;
+
 
             // Types supported by the underlying BootstrapTable - just
             // pass them on.
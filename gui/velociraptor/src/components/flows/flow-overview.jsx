@@ -114,6 +114,11 @@ export default class FlowOverview extends React.Component {
 
         let artifacts_with_results = flow.artifacts_with_results || [];
         let uploaded_files = flow.uploaded_files || [];
+
+        // Artifacts which were not sent because the client lacks a
+        // required capability.
+        let skipped = _.filter(flow.query_stats || [],
+                               x=>x.status === "SKIPPED");
         let lock_password = this.context.traits &&
             this.context.traits.default_password;
 
@@ -174,6 +179,17 @@ export default class FlowOverview extends React.Component {
                       </Fragment>
                     }
 
+                    { !_.isEmpty(skipped) &&
+                      <Fragment>
+                        <dt className="col-4">{T("Skipped")}</dt>
+                        <dd className="col-8">
+                          { _.map(skipped, function(v, idx) {
+                              return <div key={idx}>{v.error_message}</div>;
+                          })}
+                        </dd>
+                      </Fragment>
+                    }
+
                     <dt className="col-4">{T("Ops/Sec")}</dt>
                     <dd className="col-8"> {flow.request.ops_per_second || T('Unlimited')} </dd>
                     <dt className="col-4">{T("CPU Limit")}</dt>
@@ -14,23 +14,9 @@ import OverlayTrigger from 'react-bootstrap/OverlayTrigger';
 import Tooltip from 'react-bootstrap/Tooltip';
 
 import api from '../core/api-service.jsx';
-const MAX_ROWS_PER_TABLE = 500;
-
-// Older collections had the upload includes the full filestore path
-// to the file, but this is un necessary because the file must reside
-// int he client's upload directory. Handle both cases here.
-const normalizeComponentList = (components, client_id, flow_id)=>{
-    if (!components) {
-        return components;
-    }
-
-    if (components[0] === "clients") {
-        return components;
-    }
-
-    return ["clients", client_id, "collections", flow_id].concat(components);
-};
+import { normalizeComponentList } from '../utils/upload.jsx';
 
+const MAX_ROWS_PER_TABLE = 500;
 
 export default class FlowUploads extends React.Component {
     static propTypes = {
@@ -99,6 +99,54 @@ class ParameterSuggestion extends React.Component {
 
 
 
+// Mirrors the depends_on expressions evaluated by the server
+// (artifacts/parameters.go).
+const isParameterSet = value => {
+    return !["", "N", "NO", "FALSE"].includes(
+        String(value || "").toUpperCase());
+};
+
+const isParameterRelevant = (param, values) => {
+    if (!param.depends_on) {
+        return true;
+    }
+
+    let conditions = param.depends_on.split(/&&|\s+and\s+/i);
+    return _.every(conditions, condition=>{
+        let match = condition.match(
+            /^\s*(!)?\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(?:(==|!=|=)\s*(.*?))?\s*$/);
+        if (!match) {
+            return true;
+        }
+
+        let value = values[match[2].toLowerCase()];
+        let expected = match[4] || "";
+        if (/^(".*"|'.*')$/.test(expected)) {
+            expected = expected.slice(1, -1);
+        }
+
+        switch(match[3]) {
+        case "=":
+        case "==":
+            return value === expected;
+        case "!=":
+            return value !== expected;
+        default:
+            return match[1] ? !isParameterSet(value) : isParameterSet(value);
+        }
+    });
+};
+
+// Is another parameter in the same exclusive group already set?
+const exclusiveGroupIsSet = (param, parameters, values) => {
+    if (!param.exclusive_group) {
+        return false;
+    }
+    return _.some(parameters, x=>x.name !== param.name &&
+                  x.exclusive_group === param.exclusive_group &&
+                  isParameterSet(values[x.name.toLowerCase()]));
+};
+
 // Remove the named artifact from the list of artifacts
 const remove_artifact = (artifacts, name) => {
     return _.filter(artifacts, (x) => x.name !== name);
@@ -135,8 +183,24 @@ export default class NewCollectionConfigParameters extends React.Component {
             this.props.parameters &&
                 this.props.parameters[artifact.name]) || {};
 
+        // The current value of all parameters for evaluating
+        // dependencies.
+        let values = {};
+        _.each(artifact.parameters, param=>{
+            let value = artifact_parameters[param.name];
+            if (_.isUndefined(value)) {
+                value = param.default || "";
+            }
+            values[param.name.toLowerCase()] = value;
+        });
+
         let suggestions = [];
         let form_parameters = _.map(artifact.parameters, (param, idx) => {
+            if (!isParameterRelevant(param, values) ||
+                exclusiveGroupIsSet(param, artifact.parameters, values)) {
+                return <div key={idx}></div>;
+            }
+
             suggestions.push({name: param.name, key: idx});
 
             let filter = this.state.filters[artifact.name] || "";
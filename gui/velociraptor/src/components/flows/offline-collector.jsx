@@ -126,6 +126,7 @@ class OfflineCollectorParameters  extends React.Component {
                         <option value="GCS">{T("Google Cloud Bucket")}</option>
                         <option value="S3">{T("AWS Bucket")}</option>
                         <option value="SFTP">{T("SFTP Upload")}</option>
+                        <option value="Azure">{T("Azure SAS URL")}</option>
                       </Form.Control>
                     </Col>
                   </Form.Group>
@@ -269,6 +270,24 @@ class OfflineCollectorParameters  extends React.Component {
                     </>
                   }
 
+                  { this.props.parameters.target === "Azure" && <>
+                    <Form.Group as={Row}>
+                      <Form.Label column sm="3">{T("SAS URL")}</Form.Label>
+                      <Col sm="8">
+                        <Form.Control as="textarea" rows={3}
+                                      placeholder={T("SAS URL")}
+                                      spellCheck="false"
+                                      value={this.props.parameters.target_args.sas_url}
+                                      onChange={e => {
+                                          this.props.parameters.target_args.sas_url = e.target.value;
+                                          this.props.setParameters(this.props.parameters);
+                                      }}
+                        />
+                      </Col>
+                    </Form.Group>
+                    </>
+                  }
+
                   { this.props.parameters.target === "SFTP" && <>
                     <Form.Group as={Row}>
                     <Form.Label column sm="3">{T("Upload Path")}</Form.Label>
@@ -572,6 +591,9 @@ export default class OfflineCollectorWizard extends React.Component {
                 region: "",
                 endpoint: "",
                 serverSideEncryption: "",
+
+                // For Azure containers.
+                sas_url: "",
             },
             password: "",
             pubkey: "",
@@ -261,6 +261,7 @@ const Deutsch = {
     "Google Cloud Bucket":"Google Cloud Bucket",
     "AWS Bucket":"AWS Bucket",
     "SFTP Upload":"SFTP Upload",
+    "Azure SAS URL":"Azure SAS URL",
     "Velociraptor Binary":"Velociraptor Binary",
     "Temp directory":"Temp-Verzeichnis",
     "Temp location":"Temp Ort",
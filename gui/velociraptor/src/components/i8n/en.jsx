@@ -223,6 +223,10 @@ const English = {
     "Perm_MACHINE_STATE" : "Machine State",
     "Perm_PREPARE_RESULTS" : "Prepare Results",
     "Perm_DATASTORE_ACCESS" : "Datastore Access",
+    "Perm_RESPONSE_KILL_PROCESS" : "Response: Kill Process",
+    "Perm_RESPONSE_DELETE_FILE" : "Response: Delete File",
+    "Perm_RESPONSE_BLOCK_HASH" : "Response: Block Hash",
+    "Perm_RESPONSE_DISABLE_USER" : "Response: Disable User",
 
 
     "ToolPerm_ALL_QUERY" : "Issue all queries without restriction",
@@ -244,6 +248,10 @@ const English = {
     "ToolPerm_MACHINE_STATE" : "Allowed to collect state information from machines (e.g. pslist())",
     "ToolPerm_PREPARE_RESULTS" : "Allowed to create zip files",
     "ToolPerm_DATASTORE_ACCESS" : " Allowed raw datastore access",
+    "ToolPerm_RESPONSE_KILL_PROCESS" : "Allowed to kill processes on endpoints from the response console",
+    "ToolPerm_RESPONSE_DELETE_FILE" : "Allowed to delete files on endpoints from the response console",
+    "ToolPerm_RESPONSE_BLOCK_HASH" : "Allowed to block executables by hash on endpoints from the response console",
+    "ToolPerm_RESPONSE_DISABLE_USER" : "Allowed to disable user accounts on endpoints from the response console",
 
 
 
@@ -260,6 +260,7 @@ const Spanish = {
     "Google Cloud Bucket":"Bucket de Google Cloud",
     "AWS Bucket":"Bucket de AWS",
     "SFTP Upload":"Subida mediante SFTP",
+    "Azure SAS URL":"URL SAS de Azure",
     "Velociraptor Binary":"Ejecutable de Velociraptor",
     "Temp directory":"Directorio temporal",
     "Temp location":"Ubicación temporal",
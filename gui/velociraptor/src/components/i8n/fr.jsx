@@ -263,6 +263,7 @@ const French = {
     "Google Cloud Bucket":"Google Cloud Bucket",
     "AWS Bucket":"AWS Bucket",
     "SFTP Upload":"Téléversement SFTP",
+    "Azure SAS URL":"URL SAS Azure",
     "Velociraptor Binary":"Binaire Vélociraptor",
     "Temp directory":"Répertoire temporaire",
     "Temp location":"Emplacement temporaire",
@@ -255,6 +255,7 @@ const Japanese = {
     "Google Cloud Bucket":"Google Cloudバケット",
     "AWS Bucket":"AWSバケット",
     "SFTP Upload":"SFTPアップロード",
+    "Azure SAS URL":"Azure SAS URL",
     "Velociraptor Binary":"Velociraptorバイナリ",
     "Temp directory":"一時的なディレクトリ",
     "Temp location":"一時的なロケーション",
@@ -261,6 +261,7 @@ const Portuguese = {
     "Google Cloud Bucket":"Google Cloud Bucket",
     "AWS Bucket":"Balde da AWS",
     "SFTP Upload":"Carregamento SFTP",
+    "Azure SAS URL":"URL SAS do Azure",
     "Velociraptor Binary":"Binário do Velociraptor",
     "Temp directory":"Diretório temporário",
     "Temp location":"Localização temporária",
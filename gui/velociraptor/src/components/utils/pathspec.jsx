@@ -0,0 +1,35 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+import _ from 'lodash';
+import { Link } from "react-router-dom";
+
+import { SplitPathComponents, Join, EncodePathInURL } from './paths.jsx';
+
+// Renders a path produced by a VQL query. If the path belongs to a
+// client we link to the file in the client's VFS.
+export default class PathspecRenderer extends React.Component {
+    static propTypes = {
+        path: PropTypes.any,
+        client_id: PropTypes.string,
+    };
+
+    render() {
+        let path = this.props.path;
+        if (!_.isString(path)) {
+            return <></>;
+        }
+
+        let client_id = this.props.client_id;
+        if (!client_id || !client_id.startsWith("C.")) {
+            return <span className="pathspec">{path}</span>;
+        }
+
+        let components = ["auto"].concat(SplitPathComponents(path));
+        return (
+            <Link className="pathspec"
+                  to={"/vfs/" + client_id + EncodePathInURL(Join(components))}>
+              {path}
+            </Link>
+        );
+    }
+};
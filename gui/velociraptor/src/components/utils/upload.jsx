@@ -0,0 +1,55 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+import _ from 'lodash';
+import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
+
+import api from '../core/api-service.jsx';
+
+// Older collections had the upload includes the full filestore path
+// to the file, but this is un necessary because the file must reside
+// int he client's upload directory. Handle both cases here.
+export const normalizeComponentList = (components, client_id, flow_id)=>{
+    if (!components) {
+        return components;
+    }
+
+    if (components[0] === "clients") {
+        return components;
+    }
+
+    return ["clients", client_id, "collections", flow_id].concat(components);
+};
+
+// Renders the response of the upload() function as a link to
+// download the uploaded file from the collection.
+export default class UploadLink extends React.Component {
+    static propTypes = {
+        upload: PropTypes.any,
+        client_id: PropTypes.string,
+        flow_id: PropTypes.string,
+    };
+
+    render() {
+        let upload = this.props.upload;
+        if (!_.isObject(upload)) {
+            return <>{upload}</>;
+        }
+
+        let name = upload.Path || upload.StoredName;
+        if (upload.Error || _.isEmpty(upload.Components) ||
+            !this.props.client_id || !this.props.flow_id) {
+            return <>{name}</>;
+        }
+
+        return <a className="upload-link"
+                  target="_blank" rel="noopener noreferrer"
+                  href={api.href("/api/v1/DownloadVFSFile", {
+                      client_id: this.props.client_id,
+                      fs_components: normalizeComponentList(
+                          upload.Components, this.props.client_id,
+                          this.props.flow_id),
+                      vfs_path: name}, {arrayFormat: 'brackets'})}>
+                 <FontAwesomeIcon icon="download"/> {name}
+               </a>;
+    }
+};
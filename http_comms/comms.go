@@ -137,6 +137,14 @@ type HTTPConnector struct {
 	last_success_idx int
 	urls             []string
 
+	// When set, urls are in order of preference: we start with the
+	// first and fail back to it after failback_period.
+	ordered         bool
+	failback_period time.Duration
+
+	// When we last failed over to another frontend.
+	last_failover time.Time
+
 	client *http.Client
 
 	// Obtained from the server's Cert CommonName.
@@ -205,49 +213,112 @@ func NewHTTPConnector(
 		timeout = 300 // 5 Min default
 	}
 
+	dialer := &net.Dialer{
+		Timeout:   time.Duration(timeout) * time.Second,
+		KeepAlive: time.Duration(timeout) * time.Second,
+		DualStack: true,
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		Proxy:                 proxyHandler,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       time.Duration(timeout) * time.Second,
+		TLSHandshakeTimeout:   time.Duration(timeout) * time.Second,
+		ExpectContinueTimeout: time.Duration(timeout) * time.Second,
+		ResponseHeaderTimeout: time.Duration(timeout) * time.Second,
+		TLSClientConfig:       tls_config,
+	}
+
+	// The proxy dialer handles proxy selection and authentication
+	// itself so the transport must connect through it directly.
+	if config_obj.Client.ProxyConfig != nil {
+		proxy_dialer, err := newProxyDialer(config_obj.Client, dialer, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("Using proxy configuration for client comms.")
+		transport.Proxy = nil
+		transport.DialContext = proxy_dialer.DialContext
+	}
+
+	failback_period := config_obj.Client.FailbackPeriod
+	if failback_period == 0 {
+		failback_period = 600
+	}
+
 	self := &HTTPConnector{
 		config_obj: config_obj,
 		manager:    manager,
 		logger:     logger,
 		clock:      clock,
 
-		// Start with a random URL from the set of
-		// preconfigured URLs. This should distribute clients
-		// randomly to all frontends.
-		current_url_idx: GetRand()(len(urls)),
+		ordered:         config_obj.Client.OrderedServerUrls,
+		failback_period: time.Duration(failback_period) * time.Second,
 
 		minPoll:    time.Duration(1) * time.Second,
 		maxPoll:    time.Duration(max_poll) * time.Second,
 		maxPollDev: maxPollDev,
 
-		urls: urls,
-
 		client: &http.Client{
 			// Let us handle redirect ourselves.
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse
 			},
-			Transport: &http.Transport{
-				DialContext: (&net.Dialer{
-					Timeout:   time.Duration(timeout) * time.Second,
-					KeepAlive: time.Duration(timeout) * time.Second,
-					DualStack: true,
-				}).DialContext,
-				Proxy:                 proxyHandler,
-				MaxIdleConns:          100,
-				IdleConnTimeout:       time.Duration(timeout) * time.Second,
-				TLSHandshakeTimeout:   time.Duration(timeout) * time.Second,
-				ExpectContinueTimeout: time.Duration(timeout) * time.Second,
-				ResponseHeaderTimeout: time.Duration(timeout) * time.Second,
-				TLSClientConfig:       tls_config,
-			},
+			Transport: transport,
 		},
 	}
 
+	err = self.SetUrls(urls)
+	if err != nil {
+		return nil, err
+	}
+
 	return self, nil
 
 }
 
+// Replace the list of frontends. This happens when the server pushes
+// a new list so the client can follow frontends to their new
+// addresses. If the current frontend is still in the list we keep
+// using it, otherwise we rekey with one of the new frontends.
+func (self *HTTPConnector) SetUrls(urls []string) error {
+	if len(urls) == 0 {
+		return errors.New("No server URLs configured")
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	current := ""
+	if len(self.urls) > 0 {
+		current = self.urls[self.current_url_idx]
+	}
+
+	self.urls = append([]string{}, urls...)
+	for idx, url := range self.urls {
+		if url == current {
+			self.current_url_idx = idx
+			self.last_success_idx = idx
+			return nil
+		}
+	}
+
+	// Ordered lists start with the preferred frontend, otherwise
+	// start with a random URL. This should distribute clients
+	// randomly to all frontends.
+	self.current_url_idx = 0
+	if !self.ordered {
+		self.current_url_idx = GetRand()(len(self.urls))
+	}
+	self.last_success_idx = self.current_url_idx
+	self.redirect_to_server = 0
+	self.server_name = ""
+
+	return nil
+}
+
 func (self *HTTPConnector) GetCurrentUrl(handler string) string {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -385,6 +456,7 @@ func (self *HTTPConnector) Post(
 		// Remember the last successful index.
 		self.mu.Lock()
 		self.last_success_idx = self.current_url_idx
+		self.maybeFailBack()
 		self.mu.Unlock()
 
 		return encrypted, nil
@@ -415,6 +487,7 @@ func (self *HTTPConnector) advanceToNextServer(ctx context.Context) {
 	self.current_url_idx = ((self.current_url_idx + 1) % len(self.urls))
 	self.redirect_to_server = 0
 	self.server_name = ""
+	self.last_failover = self.clock.Now()
 
 	// We are cycling through all our frontend's PEM keys
 	// in this loop. Once we go all the way around we
@@ -443,6 +516,26 @@ func (self *HTTPConnector) advanceToNextServer(ctx context.Context) {
 	}
 }
 
+// With an ordered list of frontends we return to the preferred
+// frontend once it had some time to recover. If it is still down we
+// simply fail over again. Must be called with the lock held.
+func (self *HTTPConnector) maybeFailBack() {
+	if !self.ordered || self.current_url_idx == 0 ||
+		self.clock.Now().Sub(self.last_failover) < self.failback_period {
+		return
+	}
+
+	self.logger.Info("Failing back to preferred frontend %v", self.urls[0])
+
+	// Clearing the server name forces a rekey with the preferred
+	// frontend. Treat it as the start of the loop so we do not back
+	// off if we need to fail over again.
+	self.current_url_idx = 0
+	self.last_success_idx = 0
+	self.redirect_to_server = 0
+	self.server_name = ""
+}
+
 func GetRand() func(int) int {
 	mu.Lock()
 	defer mu.Unlock()
@@ -819,6 +912,7 @@ func (self *NotificationReader) GetMessageList() *crypto_proto.MessageList {
 			SessionId: constants.FOREMAN_WELL_KNOWN_FLOW,
 			ForemanCheckin: &actions_proto.ForemanCheckin{
 				LastEventTableVersion: actions.GlobalEventTableVersion(),
+				Load:                  actions.CurrentLoad(),
 			}},
 		},
 	}
@@ -838,12 +932,20 @@ type HTTPCommunicator struct {
 	// Sends results back to the server.
 	sender *Sender
 
+	connector *HTTPConnector
+
 	// Will be called when we exit the communicator.
 	on_exit func()
 
 	Manager crypto.ICryptoManager
 }
 
+// Switch to a new list of frontends.
+func (self *HTTPCommunicator) SetServerUrls(urls []string) error {
+	self.logger.Info("Updating server urls to %v", urls)
+	return self.connector.SetUrls(urls)
+}
+
 func (self *HTTPCommunicator) SetPause(is_paused bool) {
 	value := int32(0)
 	if is_paused {
@@ -862,6 +964,9 @@ func (self *HTTPCommunicator) Run(
 	self.receiver.Start(ctx, wg)
 	self.sender.Start(ctx, wg)
 
+	wg.Add(1)
+	go self.uploadCrashReports(ctx, wg)
+
 	<-ctx.Done()
 }
 
@@ -960,10 +1065,11 @@ func NewHTTPCommunicator(
 			logger:     logger,
 			clock:      clock,
 		},
-		on_exit:  on_exit,
-		sender:   sender,
-		receiver: receiver,
-		Manager:  manager,
+		on_exit:   on_exit,
+		sender:    sender,
+		receiver:  receiver,
+		connector: connector,
+		Manager:   manager,
 	}
 
 	return result, nil
@@ -37,6 +37,7 @@ import (
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
 	crypto_test "www.velocidex.com/golang/velociraptor/crypto/testing"
 	"www.velocidex.com/golang/velociraptor/executor"
+	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vtesting"
 )
@@ -812,6 +813,58 @@ func (self *CommsTestSuite) TestMultiRedirects() {
 	})
 }
 
+// With ordered urls the client prefers the first frontend and fails
+// back to it some time after failing over.
+func (self *CommsTestSuite) TestOrderedServerUrls() {
+	self.config_obj.Client.OrderedServerUrls = true
+	self.config_obj.Client.FailbackPeriod = 60
+
+	clock := &utils.MockClock{MockNow: time.Now()}
+	logger := logging.GetLogger(self.config_obj, &logging.ClientComponent)
+	connector, err := NewHTTPConnector(self.config_obj,
+		&crypto_test.NullCryptoManager{}, logger,
+		[]string{"https://primary/", "https://secondary/"}, clock)
+	require.NoError(self.T(), err)
+
+	assert.Equal(self.T(), "https://primary/reader",
+		connector.GetCurrentUrl("reader"))
+
+	// Fail over to the next frontend.
+	connector.advanceToNextServer(context.Background())
+	assert.Equal(self.T(), "https://secondary/reader",
+		connector.GetCurrentUrl("reader"))
+
+	// Too soon to fail back.
+	connector.mu.Lock()
+	connector.maybeFailBack()
+	connector.mu.Unlock()
+	assert.Equal(self.T(), "https://secondary/reader",
+		connector.GetCurrentUrl("reader"))
+
+	clock.Sleep(2 * time.Minute)
+	connector.mu.Lock()
+	connector.maybeFailBack()
+	connector.mu.Unlock()
+	assert.Equal(self.T(), "https://primary/reader",
+		connector.GetCurrentUrl("reader"))
+
+	// A pushed list keeps the current frontend if it is still
+	// present.
+	require.NoError(self.T(), connector.SetUrls(
+		[]string{"https://new/", "https://primary/"}))
+	assert.Equal(self.T(), "https://primary/reader",
+		connector.GetCurrentUrl("reader"))
+
+	// Otherwise we switch to the preferred new frontend.
+	require.NoError(self.T(), connector.SetUrls(
+		[]string{"https://new2/", "https://new3/"}))
+	assert.Equal(self.T(), "https://new2/reader",
+		connector.GetCurrentUrl("reader"))
+	assert.Equal(self.T(), "", connector.ServerName())
+
+	assert.Error(self.T(), connector.SetUrls(nil))
+}
+
 func checkResponses(t *testing.T, expected []string, seen []string) {
 	for idx, seen_line := range seen {
 		assert.Contains(t, expected[idx], seen_line)
@@ -0,0 +1,97 @@
+package http_comms
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/crashes"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Upload any crash reports left behind by previous runs of the
+// client. Reports are removed once the server accepted them,
+// otherwise we keep retrying in case the server is unreachable.
+func (self *HTTPCommunicator) uploadCrashReports(
+	ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if self.config_obj.Client == nil ||
+		self.config_obj.Client.DisableCrashReports {
+		return
+	}
+
+	crash_directory, err := crashes.CrashDirectory(self.config_obj)
+	if err != nil {
+		return
+	}
+
+	pending, err := crashes.PendingCrashReports(crash_directory)
+	if err != nil || len(pending) == 0 {
+		return
+	}
+
+	self.logger.Info("Uploading %v crash reports from %v",
+		len(pending), crash_directory)
+
+	reader := self.receiver
+	for _, item := range pending {
+		for {
+			err := self.sendCrashReport(ctx, item.Report)
+			if err == nil {
+				os.Remove(item.Path)
+				break
+			}
+
+			wait := reader.maxPoll + time.Duration(
+				GetRand()(int(reader.maxPollDev)))*time.Second
+			self.logger.Info("Failed to upload crash report %v: %v. "+
+				"Retrying in %v", item.Report.CrashId, err, wait)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-reader.clock.After(wait):
+			}
+		}
+	}
+}
+
+func (self *HTTPCommunicator) sendCrashReport(
+	ctx context.Context, report *crypto_proto.CrashReport) error {
+	connector := self.receiver.connector
+	if connector.ServerName() == "" {
+		connector.ReKeyNextServer(ctx)
+	}
+
+	// The client id may not have been known when the report was
+	// written - the server uses the authenticated source anyway.
+	serialized, err := proto.Marshal(&crypto_proto.MessageList{
+		Job: []*crypto_proto.VeloMessage{{
+			CrashReport: report,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	compressed, err := utils.Compress(serialized)
+	if err != nil {
+		return err
+	}
+
+	cipher_text, err := self.Manager.Encrypt([][]byte{compressed},
+		crypto_proto.PackedMessageList_ZCOMPRESSION,
+		self.config_obj.Client.Nonce,
+		connector.ServerName())
+	if err != nil {
+		return err
+	}
+
+	_, err = connector.Post(ctx, "CrashReport", "crash_report",
+		cipher_text, !URGENT)
+	return err
+}
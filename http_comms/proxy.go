@@ -0,0 +1,446 @@
+package http_comms
+
+// Enterprise endpoints often can only reach the internet through
+// authenticated proxies, which are selected by a proxy auto-config
+// (PAC) file or depend on the network the endpoint is currently
+// on. Go's http.Transport only supports a single proxy with static
+// credentials, so when Client.proxy_config is set, the connector
+// dials through the proxyDialer instead. The dialer selects a chain
+// of proxies for each connection, tries them in turn and establishes
+// a CONNECT tunnel, performing any authentication handshake on the
+// tunnel's connection (NTLM and Negotiate are connection oriented).
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+)
+
+const (
+	// Connection oriented schemes need a few round trips.
+	maxProxyAuthRounds = 3
+)
+
+var (
+	proxyAuthenticationError = errors.New("Proxy authentication failed")
+)
+
+// Produces the tokens sent in the Proxy-Authorization header.
+type proxyAuthenticator interface {
+	Scheme() string
+
+	// The token for the next request. The challenge is nil for the
+	// first request.
+	Next(challenge []byte) ([]byte, error)
+	Close()
+}
+
+type basicAuthenticator struct {
+	username, password string
+}
+
+func (self *basicAuthenticator) Scheme() string { return "Basic" }
+func (self *basicAuthenticator) Close()         {}
+
+func (self *basicAuthenticator) Next(challenge []byte) ([]byte, error) {
+	// Being challenged again means the credentials were rejected.
+	if challenge != nil {
+		return nil, proxyAuthenticationError
+	}
+	return []byte(self.username + ":" + self.password), nil
+}
+
+type ntlmAuthenticator struct {
+	username, password, domain string
+	round                      int
+}
+
+func (self *ntlmAuthenticator) Scheme() string { return "NTLM" }
+func (self *ntlmAuthenticator) Close()         {}
+
+func (self *ntlmAuthenticator) Next(challenge []byte) ([]byte, error) {
+	self.round++
+	switch self.round {
+	case 1:
+		return ntlmNegotiateMessage(), nil
+
+	case 2:
+		parsed, err := parseNTLMChallenge(challenge)
+		if err != nil {
+			return nil, err
+		}
+		return ntlmAuthenticateMessage(
+			parsed, self.username, self.password, self.domain)
+
+	default:
+		return nil, proxyAuthenticationError
+	}
+}
+
+func newProxyAuthenticator(
+	config_obj *config_proto.ProxyConfig,
+	proxy_host string) (proxyAuthenticator, error) {
+
+	username := config_obj.Username
+	domain := config_obj.Domain
+
+	// Accept DOMAIN\user as well.
+	if domain == "" {
+		parts := strings.SplitN(username, "\\", 2)
+		if len(parts) == 2 {
+			domain, username = parts[0], parts[1]
+		}
+	}
+
+	switch strings.ToLower(config_obj.AuthScheme) {
+	case "":
+		return nil, nil
+
+	case "basic":
+		return &basicAuthenticator{
+			username: config_obj.Username,
+			password: config_obj.Password,
+		}, nil
+
+	case "ntlm":
+		if username == "" {
+			return newSSPIAuthenticator("NTLM", proxy_host)
+		}
+		return &ntlmAuthenticator{
+			username: username,
+			password: config_obj.Password,
+			domain:   domain,
+		}, nil
+
+	case "negotiate":
+		return newSSPIAuthenticator("Negotiate", proxy_host)
+
+	default:
+		return nil, fmt.Errorf("Unsupported proxy auth scheme %v",
+			config_obj.AuthScheme)
+	}
+}
+
+type networkRule struct {
+	networks []*net.IPNet
+	proxies  []string
+	pac      *pacResolver
+}
+
+type proxyDialer struct {
+	config_obj *config_proto.ProxyConfig
+	dialer     *net.Dialer
+	logger     *logging.LogContext
+
+	// Used when no other proxies are configured.
+	default_proxy string
+
+	rules []*networkRule
+	pac   *pacResolver
+}
+
+func newProxyDialer(
+	config_obj *config_proto.ClientConfig,
+	dialer *net.Dialer,
+	logger *logging.LogContext) (*proxyDialer, error) {
+
+	proxy_config := config_obj.ProxyConfig
+	refresh := time.Duration(proxy_config.PacRefreshSec) * time.Second
+
+	result := &proxyDialer{
+		config_obj:    proxy_config,
+		dialer:        dialer,
+		logger:        logger,
+		default_proxy: config_obj.Proxy,
+	}
+
+	if proxy_config.PacUrl != "" {
+		result.pac = newPACResolver(proxy_config.PacUrl, refresh)
+	}
+
+	for _, rule := range proxy_config.Networks {
+		network_rule := &networkRule{proxies: rule.Proxies}
+		for _, network := range rule.Networks {
+			_, ip_net, err := net.ParseCIDR(network)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid proxy network %v: %w",
+					network, err)
+			}
+			network_rule.networks = append(network_rule.networks, ip_net)
+		}
+
+		if rule.PacUrl != "" {
+			network_rule.pac = newPACResolver(rule.PacUrl, refresh)
+		}
+		result.rules = append(result.rules, network_rule)
+	}
+
+	// Check the auth settings early.
+	auth, err := newProxyAuthenticator(proxy_config, "")
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil {
+		auth.Close()
+	}
+
+	return result, nil
+}
+
+// The chain of proxies to try for a connection to addr.
+func (self *proxyDialer) proxiesFor(
+	ctx context.Context, addr string) []string {
+
+	rule := self.matchNetwork(localAddresses())
+	if rule != nil {
+		if rule.pac != nil {
+			return self.resolvePAC(ctx, rule.pac, addr, rule.proxies)
+		}
+		return rule.proxies
+	}
+
+	if self.pac != nil {
+		return self.resolvePAC(ctx, self.pac, addr, self.config_obj.Proxies)
+	}
+
+	if len(self.config_obj.Proxies) > 0 {
+		return self.config_obj.Proxies
+	}
+
+	if self.default_proxy != "" {
+		return []string{self.default_proxy}
+	}
+
+	return []string{"DIRECT"}
+}
+
+func (self *proxyDialer) matchNetwork(addresses []net.IP) *networkRule {
+	for _, rule := range self.rules {
+		for _, network := range rule.networks {
+			for _, addr := range addresses {
+				if network.Contains(addr) {
+					return rule
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// If the PAC file can not be evaluated we fall back to the
+// configured proxies.
+func (self *proxyDialer) resolvePAC(ctx context.Context,
+	pac *pacResolver, addr string, fallback []string) []string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	scheme := "https"
+	if port == "80" {
+		scheme = "http"
+	}
+
+	proxies, err := pac.ProxiesForURL(ctx, &url.URL{
+		Scheme: scheme,
+		Host:   addr,
+		Path:   "/",
+	})
+	if err != nil || len(proxies) == 0 {
+		if err != nil {
+			self.logger.Info("Unable to resolve proxy for %v: %v", host, err)
+		}
+		if len(fallback) > 0 {
+			return fallback
+		}
+		return []string{"DIRECT"}
+	}
+	return proxies
+}
+
+func (self *proxyDialer) DialContext(
+	ctx context.Context, network, addr string) (net.Conn, error) {
+	var last_err error
+
+	for _, proxy := range self.proxiesFor(ctx, addr) {
+		conn, err := self.dialVia(ctx, proxy, network, addr)
+		if err == nil {
+			return conn, nil
+		}
+
+		self.logger.Info("Connecting to %v via %v failed: %v",
+			addr, proxy, err)
+		last_err = err
+	}
+
+	if last_err == nil {
+		last_err = fmt.Errorf("No proxies available for %v", addr)
+	}
+	return nil, last_err
+}
+
+func (self *proxyDialer) dialVia(ctx context.Context,
+	proxy, network, addr string) (net.Conn, error) {
+	if strings.ToUpper(proxy) == "DIRECT" {
+		return self.dialer.DialContext(ctx, network, addr)
+	}
+
+	proxy_url, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy_addr := proxy_url.Host
+	if proxy_url.Port() == "" {
+		port := "80"
+		if proxy_url.Scheme == "https" {
+			port = "443"
+		}
+		proxy_addr = net.JoinHostPort(proxy_url.Hostname(), port)
+	}
+
+	conn, err := self.dialer.DialContext(ctx, "tcp", proxy_addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxy_url.Scheme == "https" {
+		tls_conn := tls.Client(conn, &tls.Config{
+			ServerName: proxy_url.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		})
+		err = tls_conn.HandshakeContext(ctx)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tls_conn
+	}
+
+	tunnel, err := self.connect(ctx, conn, proxy_url, addr)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return tunnel, nil
+}
+
+// The proxy may send data from the server right after the CONNECT
+// response, which the reader has already buffered.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (self *bufferedConn) Read(b []byte) (int, error) {
+	return self.reader.Read(b)
+}
+
+// Establish a CONNECT tunnel through the proxy, authenticating if
+// needed. Returns the connection to use for the tunnel.
+func (self *proxyDialer) connect(ctx context.Context,
+	conn net.Conn, proxy_url *url.URL, addr string) (net.Conn, error) {
+
+	auth, err := newProxyAuthenticator(self.config_obj, proxy_url.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil {
+		defer auth.Close()
+	}
+
+	deadline, ok := ctx.Deadline()
+	if ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	reader := bufio.NewReader(conn)
+	var challenge []byte
+
+	for round := 0; round < maxProxyAuthRounds; round++ {
+		req := &http.Request{
+			Method: "CONNECT",
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: make(http.Header),
+		}
+		req.Header.Set("Proxy-Connection", "Keep-Alive")
+
+		if auth != nil {
+			token, err := auth.Next(challenge)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Proxy-Authorization",
+				auth.Scheme()+" "+base64.StdEncoding.EncodeToString(token))
+		}
+
+		err = req.Write(conn)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := http.ReadResponse(reader, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			resp.Body.Close()
+			if reader.Buffered() > 0 {
+				return &bufferedConn{Conn: conn, reader: reader}, nil
+			}
+			return conn, nil
+		}
+
+		// Drain the body so we can reuse the connection for the
+		// next round.
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusProxyAuthRequired {
+			return nil, fmt.Errorf("Proxy returned %v", resp.Status)
+		}
+
+		if auth == nil || resp.Close {
+			return nil, proxyAuthenticationError
+		}
+
+		challenge = getProxyChallenge(resp.Header, auth.Scheme())
+		if challenge == nil {
+			return nil, proxyAuthenticationError
+		}
+	}
+
+	return nil, proxyAuthenticationError
+}
+
+func getProxyChallenge(header http.Header, scheme string) []byte {
+	for _, value := range header.Values("Proxy-Authenticate") {
+		fields := strings.Fields(value)
+		if len(fields) != 2 || !strings.EqualFold(fields[0], scheme) {
+			continue
+		}
+
+		challenge, err := base64.StdEncoding.DecodeString(fields[1])
+		if err == nil {
+			return challenge
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package http_comms
+
+// A minimal NTLMv2 client (MS-NLMP) for authenticating to proxies
+// with explicit credentials. Only authentication is implemented -
+// proxies do not sign or seal the tunnel so we never need the
+// session key.
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+)
+
+const (
+	ntlmNegotiateUnicode                 = 0x00000001
+	ntlmNegotiateOEM                     = 0x00000002
+	ntlmRequestTarget                    = 0x00000004
+	ntlmNegotiateNTLM                    = 0x00000200
+	ntlmNegotiateAlwaysSign              = 0x00008000
+	ntlmNegotiateExtendedSessionSecurity = 0x00080000
+	ntlmNegotiateTargetInfo              = 0x00800000
+	ntlmNegotiate128                     = 0x20000000
+	ntlmNegotiate56                      = 0x80000000
+
+	ntlmNegotiateFlags = ntlmNegotiateUnicode | ntlmNegotiateOEM |
+		ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign |
+		ntlmNegotiateExtendedSessionSecurity | ntlmNegotiate128 |
+		ntlmNegotiate56
+
+	// AV pair ids in the challenge's target info.
+	ntlmAvEOL       = 0
+	ntlmAvTimestamp = 7
+)
+
+var (
+	ntlmSignature = []byte("NTLMSSP\x00")
+
+	invalidChallengeError = errors.New("NTLM: Invalid challenge message")
+)
+
+type ntlmChallenge struct {
+	flags            uint32
+	server_challenge []byte
+	target_info      []byte
+}
+
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmNegotiateFlags)
+
+	// Domain and workstation are empty security buffers pointing at
+	// the end of the message.
+	binary.LittleEndian.PutUint32(msg[20:], 32)
+	binary.LittleEndian.PutUint32(msg[28:], 32)
+	return msg
+}
+
+func parseNTLMChallenge(msg []byte) (*ntlmChallenge, error) {
+	if len(msg) < 48 || !bytes.Equal(msg[:8], ntlmSignature) ||
+		binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return nil, invalidChallengeError
+	}
+
+	result := &ntlmChallenge{
+		flags:            binary.LittleEndian.Uint32(msg[20:]),
+		server_challenge: msg[24:32],
+	}
+
+	length := int(binary.LittleEndian.Uint16(msg[40:]))
+	offset := int(binary.LittleEndian.Uint32(msg[44:]))
+	if offset+length > len(msg) {
+		return nil, invalidChallengeError
+	}
+	result.target_info = msg[offset : offset+length]
+
+	return result, nil
+}
+
+// Returns the timestamp from the target info if the server sent one.
+func (self *ntlmChallenge) timestamp() []byte {
+	info := self.target_info
+	for len(info) >= 4 {
+		id := binary.LittleEndian.Uint16(info)
+		length := int(binary.LittleEndian.Uint16(info[2:]))
+		if id == ntlmAvEOL || len(info) < 4+length {
+			break
+		}
+
+		if id == ntlmAvTimestamp && length == 8 {
+			return info[4:12]
+		}
+		info = info[4+length:]
+	}
+	return nil
+}
+
+func ntowfv2(user, password, domain string) []byte {
+	hash := md4.New()
+	hash.Write(toUnicode(password))
+
+	mac := hmac.New(md5.New, hash.Sum(nil))
+	mac.Write(toUnicode(strings.ToUpper(user) + domain))
+	return mac.Sum(nil)
+}
+
+func ntlmv2Response(key, server_challenge, client_challenge,
+	timestamp, target_info []byte) (nt_response, lm_response []byte) {
+
+	temp := &bytes.Buffer{}
+	temp.Write([]byte{1, 1, 0, 0, 0, 0, 0, 0})
+	temp.Write(timestamp)
+	temp.Write(client_challenge)
+	temp.Write([]byte{0, 0, 0, 0})
+	temp.Write(target_info)
+	temp.Write([]byte{0, 0, 0, 0})
+
+	mac := hmac.New(md5.New, key)
+	mac.Write(server_challenge)
+	mac.Write(temp.Bytes())
+	nt_response = append(mac.Sum(nil), temp.Bytes()...)
+
+	mac = hmac.New(md5.New, key)
+	mac.Write(server_challenge)
+	mac.Write(client_challenge)
+	lm_response = append(mac.Sum(nil), client_challenge...)
+
+	return nt_response, lm_response
+}
+
+func ntlmAuthenticateMessage(
+	challenge *ntlmChallenge, user, password, domain string) ([]byte, error) {
+
+	client_challenge := make([]byte, 8)
+	_, err := rand.Read(client_challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := challenge.timestamp()
+	if timestamp == nil {
+		timestamp = make([]byte, 8)
+		binary.LittleEndian.PutUint64(timestamp, toFileTime(time.Now()))
+	}
+
+	nt_response, lm_response := ntlmv2Response(
+		ntowfv2(user, password, domain),
+		challenge.server_challenge, client_challenge,
+		timestamp, challenge.target_info)
+
+	// Header: signature, type, 6 security buffers and the flags.
+	const header_size = 64
+	payload := &bytes.Buffer{}
+	msg := make([]byte, header_size)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+
+	add := func(offset int, data []byte) {
+		binary.LittleEndian.PutUint16(msg[offset:], uint16(len(data)))
+		binary.LittleEndian.PutUint16(msg[offset+2:], uint16(len(data)))
+		binary.LittleEndian.PutUint32(msg[offset+4:],
+			uint32(header_size+payload.Len()))
+		payload.Write(data)
+	}
+
+	add(12, lm_response)
+	add(20, nt_response)
+	add(28, toUnicode(domain))
+	add(36, toUnicode(user))
+	add(44, nil) // Workstation
+	add(52, nil) // Session key
+
+	flags := challenge.flags & ntlmNegotiateFlags
+	binary.LittleEndian.PutUint32(msg[60:], flags)
+
+	return append(msg, payload.Bytes()...), nil
+}
+
+func toUnicode(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	result := make([]byte, 2*len(encoded))
+	for i, c := range encoded {
+		binary.LittleEndian.PutUint16(result[2*i:], c)
+	}
+	return result
+}
+
+// Windows FILETIME: 100ns intervals since 1601.
+func toFileTime(t time.Time) uint64 {
+	return uint64(t.UnixNano()/100) + 116444736000000000
+}
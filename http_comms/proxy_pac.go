@@ -0,0 +1,372 @@
+package http_comms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robertkrimen/otto"
+)
+
+const (
+	// A PAC file which runs longer than this is abandoned so it can
+	// not hang the client's comms.
+	pacTimeout = 5 * time.Second
+)
+
+var (
+	pacHalt         = errors.New("Halt")
+	pacTimeoutError = errors.New("PAC evaluation timed out")
+)
+
+// The standard PAC helper functions which can be written in
+// Javascript. Functions which need the network are implemented in
+// Go below.
+const pacPrelude = `
+function isPlainHostName(host) {
+  return host.indexOf('.') < 0;
+}
+
+function dnsDomainIs(host, domain) {
+  return host.length >= domain.length &&
+    host.substring(host.length - domain.length) == domain;
+}
+
+function localHostOrDomainIs(host, hostdom) {
+  return host == hostdom || hostdom.lastIndexOf(host + '.', 0) == 0;
+}
+
+function dnsDomainLevels(host) {
+  return host.split('.').length - 1;
+}
+
+function isResolvable(host) {
+  return dnsResolve(host) != null;
+}
+
+function convert_addr(ipchars) {
+  var bytes = ipchars.split('.');
+  return ((bytes[0] & 0xff) << 24) | ((bytes[1] & 0xff) << 16) |
+    ((bytes[2] & 0xff) << 8) | (bytes[3] & 0xff);
+}
+
+function isInNet(host, pattern, mask) {
+  var addr = /^\d+\.\d+\.\d+\.\d+$/.test(host) ? host : dnsResolve(host);
+  if (addr == null) {
+    return false;
+  }
+  var m = convert_addr(mask);
+  return (convert_addr(addr) & m) == (convert_addr(pattern) & m);
+}
+
+var pacDays = ['SUN', 'MON', 'TUE', 'WED', 'THU', 'FRI', 'SAT'];
+
+function weekdayRange(wd1, wd2, gmt) {
+  if (wd2 == 'GMT') {
+    gmt = wd2;
+    wd2 = undefined;
+  }
+  var now = new Date();
+  var day = gmt == 'GMT' ? now.getUTCDay() : now.getDay();
+  var first = pacDays.indexOf(wd1);
+  var last = wd2 === undefined ? first : pacDays.indexOf(wd2);
+  if (first <= last) {
+    return first <= day && day <= last;
+  }
+  return day >= first || day <= last;
+}
+
+function timeRange(h1, h2, gmt) {
+  if (h2 == 'GMT') {
+    gmt = h2;
+    h2 = undefined;
+  }
+  var now = new Date();
+  var hour = gmt == 'GMT' ? now.getUTCHours() : now.getHours();
+  if (h2 === undefined) {
+    return hour == h1;
+  }
+  if (h1 <= h2) {
+    return h1 <= hour && hour < h2;
+  }
+  return hour >= h1 || hour < h2;
+}
+`
+
+// Evaluates a proxy auto-config file. The file is reloaded
+// periodically so changes to the enterprise's proxy configuration
+// are picked up.
+type pacResolver struct {
+	mu sync.Mutex
+
+	url     string
+	refresh time.Duration
+
+	vm          *otto.Otto
+	last_loaded time.Time
+}
+
+func newPACResolver(pac_url string, refresh time.Duration) *pacResolver {
+	if refresh == 0 {
+		refresh = time.Hour
+	}
+	return &pacResolver{url: pac_url, refresh: refresh}
+}
+
+// Returns the proxies the PAC file selects for the url in the same
+// format as the ProxyConfig proxies.
+func (self *pacResolver) ProxiesForURL(
+	ctx context.Context, target *url.URL) ([]string, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.vm == nil || time.Now().Sub(self.last_loaded) > self.refresh {
+		err := self.load(ctx)
+		// Keep using the old script if we can not reload it.
+		if err != nil && self.vm == nil {
+			return nil, err
+		}
+		self.last_loaded = time.Now()
+	}
+
+	var value otto.Value
+	err := runWithTimeout(ctx, self.vm, func() (err error) {
+		value, err = self.vm.Call("FindProxyForURL", nil,
+			target.String(), target.Hostname())
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PAC %v: %w", self.url, err)
+	}
+
+	return parsePACResult(value.String()), nil
+}
+
+func (self *pacResolver) load(ctx context.Context) error {
+	script, err := fetchPAC(ctx, self.url)
+	if err != nil {
+		return fmt.Errorf("Unable to load PAC %v: %w", self.url, err)
+	}
+
+	vm := otto.New()
+	err = vm.Set("dnsResolve", func(call otto.FunctionCall) otto.Value {
+		// The script can not be interrupted while we resolve. The
+		// function outlives the context the script was loaded with.
+		sub_ctx, cancel := context.WithTimeout(
+			context.Background(), pacTimeout)
+		defer cancel()
+
+		addrs, err := net.DefaultResolver.LookupIPAddr(
+			sub_ctx, call.Argument(0).String())
+		if err == nil {
+			for _, addr := range addrs {
+				if addr.IP.To4() != nil {
+					value, _ := vm.ToValue(addr.IP.String())
+					return value
+				}
+			}
+		}
+		return otto.NullValue()
+	})
+	if err != nil {
+		return err
+	}
+
+	err = vm.Set("shExpMatch", func(call otto.FunctionCall) otto.Value {
+		value, _ := vm.ToValue(shExpMatch(
+			call.Argument(0).String(), call.Argument(1).String()))
+		return value
+	})
+	if err != nil {
+		return err
+	}
+
+	err = vm.Set("myIpAddress", func(call otto.FunctionCall) otto.Value {
+		for _, addr := range localAddresses() {
+			if addr.To4() != nil && !addr.IsLoopback() {
+				value, _ := vm.ToValue(addr.String())
+				return value
+			}
+		}
+		value, _ := vm.ToValue("127.0.0.1")
+		return value
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = vm.Run(pacPrelude)
+	if err != nil {
+		return err
+	}
+
+	err = runWithTimeout(ctx, vm, func() error {
+		_, err := vm.Run(script)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Invalid PAC %v: %w", self.url, err)
+	}
+
+	self.vm = vm
+	return nil
+}
+
+// Run the function in the vm, interrupting it if it takes too long
+// or the context is done.
+func runWithTimeout(ctx context.Context,
+	vm *otto.Otto, fn func() error) (err error) {
+	sub_ctx, cancel := context.WithTimeout(ctx, pacTimeout)
+	defer cancel()
+
+	// A new channel for each run so an interrupt that arrives after
+	// the run completed can not halt the next run.
+	interrupt := make(chan func(), 1)
+	vm.Interrupt = interrupt
+
+	done := make(chan bool)
+	defer close(done)
+
+	go func() {
+		select {
+		case <-done:
+		case <-sub_ctx.Done():
+			interrupt <- func() {
+				panic(pacHalt)
+			}
+		}
+	}()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if r == pacHalt {
+			err = pacTimeoutError
+			return
+		}
+		panic(r)
+	}()
+
+	return fn()
+}
+
+func fetchPAC(ctx context.Context, pac_url string) (string, error) {
+	parsed, err := url.Parse(pac_url)
+	if err != nil {
+		return "", err
+	}
+
+	switch parsed.Scheme {
+	case "http", "https":
+		// The PAC file itself must be fetched without a proxy.
+		req, err := http.NewRequestWithContext(ctx, "GET", pac_url, nil)
+		if err != nil {
+			return "", err
+		}
+
+		client := &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{Proxy: nil},
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("HTTP status %v", resp.StatusCode)
+		}
+
+		data, err := ioutil.ReadAll(resp.Body)
+		return string(data), err
+
+	case "file":
+		data, err := os.ReadFile(parsed.Path)
+		return string(data), err
+
+	case "":
+		data, err := os.ReadFile(pac_url)
+		return string(data), err
+
+	default:
+		return "", fmt.Errorf("Unsupported PAC url %v", pac_url)
+	}
+}
+
+// Shell expressions only support * and ? wildcards.
+func shExpMatch(str, shexp string) bool {
+	re := strings.Builder{}
+	re.WriteString("^")
+	for _, c := range shexp {
+		switch c {
+		case '*':
+			re.WriteString(".*")
+		case '?':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+
+	matched, _ := regexp.MatchString(re.String(), str)
+	return matched
+}
+
+// PAC files return strings like "PROXY a:8080; SOCKS b:1080; DIRECT"
+func parsePACResult(result string) []string {
+	proxies := []string{}
+	for _, item := range strings.Split(result, ";") {
+		fields := strings.Fields(item)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "DIRECT":
+			proxies = append(proxies, "DIRECT")
+
+		case "PROXY", "HTTP":
+			if len(fields) > 1 {
+				proxies = append(proxies, "http://"+fields[1])
+			}
+
+		case "HTTPS":
+			if len(fields) > 1 {
+				proxies = append(proxies, "https://"+fields[1])
+			}
+
+			// SOCKS proxies are not supported.
+		}
+	}
+	return proxies
+}
+
+func localAddresses() []net.IP {
+	var result []net.IP
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		ip_net, ok := addr.(*net.IPNet)
+		if ok {
+			result = append(result, ip_net.IP)
+		}
+	}
+	return result
+}
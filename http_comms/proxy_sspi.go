@@ -0,0 +1,13 @@
+// +build !windows
+
+package http_comms
+
+import (
+	"fmt"
+)
+
+func newSSPIAuthenticator(
+	scheme, proxy_host string) (proxyAuthenticator, error) {
+	return nil, fmt.Errorf(
+		"Proxy auth scheme %v requires a username on this platform", scheme)
+}
@@ -0,0 +1,164 @@
+// +build windows
+
+package http_comms
+
+// Windows clients authenticate to NTLM and Kerberos proxies using
+// SSPI with the credentials of the account the client runs as (for
+// the service this is the machine account).
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	SECPKG_CRED_OUTBOUND = 2
+
+	ISC_REQ_ALLOCATE_MEMORY = 0x00000100
+	ISC_REQ_CONNECTION      = 0x00000800
+
+	SECURITY_NATIVE_DREP = 0x00000010
+
+	SECBUFFER_VERSION = 0
+	SECBUFFER_TOKEN   = 2
+
+	SEC_E_OK              = 0
+	SEC_I_CONTINUE_NEEDED = 0x00090312
+)
+
+var (
+	modsecur32 = windows.NewLazySystemDLL("secur32.dll")
+
+	procAcquireCredentialsHandleW  = modsecur32.NewProc("AcquireCredentialsHandleW")
+	procInitializeSecurityContextW = modsecur32.NewProc("InitializeSecurityContextW")
+	procDeleteSecurityContext      = modsecur32.NewProc("DeleteSecurityContext")
+	procFreeCredentialsHandle      = modsecur32.NewProc("FreeCredentialsHandle")
+	procFreeContextBuffer          = modsecur32.NewProc("FreeContextBuffer")
+)
+
+type secHandle struct {
+	Lower uintptr
+	Upper uintptr
+}
+
+type secBuffer struct {
+	BufferSize uint32
+	BufferType uint32
+	Buffer     *byte
+}
+
+type secBufferDesc struct {
+	Version    uint32
+	BufferSize uint32
+	Buffers    *secBuffer
+}
+
+type sspiAuthenticator struct {
+	scheme string
+	target *uint16
+
+	credentials secHandle
+	context     secHandle
+	has_context bool
+}
+
+func newSSPIAuthenticator(
+	scheme, proxy_host string) (proxyAuthenticator, error) {
+	package_name, err := syscall.UTF16PtrFromString(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := syscall.UTF16PtrFromString("HTTP/" + proxy_host)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &sspiAuthenticator{scheme: scheme, target: target}
+
+	var expiry int64
+	ret, _, _ := procAcquireCredentialsHandleW.Call(
+		0, uintptr(unsafe.Pointer(package_name)),
+		SECPKG_CRED_OUTBOUND, 0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&result.credentials)),
+		uintptr(unsafe.Pointer(&expiry)))
+	if ret != SEC_E_OK {
+		return nil, fmt.Errorf("AcquireCredentialsHandle: 0x%08x", ret)
+	}
+
+	return result, nil
+}
+
+func (self *sspiAuthenticator) Scheme() string {
+	return self.scheme
+}
+
+func (self *sspiAuthenticator) Next(challenge []byte) ([]byte, error) {
+	if challenge == nil && self.has_context {
+		return nil, proxyAuthenticationError
+	}
+
+	var input_desc *secBufferDesc
+	if challenge != nil {
+		input := &secBuffer{
+			BufferSize: uint32(len(challenge)),
+			BufferType: SECBUFFER_TOKEN,
+			Buffer:     &challenge[0],
+		}
+		input_desc = &secBufferDesc{
+			Version:    SECBUFFER_VERSION,
+			BufferSize: 1,
+			Buffers:    input,
+		}
+	}
+
+	output := &secBuffer{BufferType: SECBUFFER_TOKEN}
+	output_desc := &secBufferDesc{
+		Version:    SECBUFFER_VERSION,
+		BufferSize: 1,
+		Buffers:    output,
+	}
+
+	var context_ptr uintptr
+	if self.has_context {
+		context_ptr = uintptr(unsafe.Pointer(&self.context))
+	}
+
+	var attributes uint32
+	var expiry int64
+	ret, _, _ := procInitializeSecurityContextW.Call(
+		uintptr(unsafe.Pointer(&self.credentials)),
+		context_ptr,
+		uintptr(unsafe.Pointer(self.target)),
+		ISC_REQ_ALLOCATE_MEMORY|ISC_REQ_CONNECTION,
+		0, SECURITY_NATIVE_DREP,
+		uintptr(unsafe.Pointer(input_desc)), 0,
+		uintptr(unsafe.Pointer(&self.context)),
+		uintptr(unsafe.Pointer(output_desc)),
+		uintptr(unsafe.Pointer(&attributes)),
+		uintptr(unsafe.Pointer(&expiry)))
+	if ret != SEC_E_OK && ret != SEC_I_CONTINUE_NEEDED {
+		return nil, fmt.Errorf("InitializeSecurityContext: 0x%08x", ret)
+	}
+	self.has_context = true
+
+	if output.Buffer == nil {
+		return nil, nil
+	}
+	defer procFreeContextBuffer.Call(uintptr(unsafe.Pointer(output.Buffer)))
+
+	token := make([]byte, output.BufferSize)
+	copy(token, unsafe.Slice(output.Buffer, output.BufferSize))
+	return token, nil
+}
+
+func (self *sspiAuthenticator) Close() {
+	if self.has_context {
+		procDeleteSecurityContext.Call(uintptr(unsafe.Pointer(&self.context)))
+		self.has_context = false
+	}
+	procFreeCredentialsHandle.Call(uintptr(unsafe.Pointer(&self.credentials)))
+}
@@ -0,0 +1,242 @@
+package http_comms
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+)
+
+// Test vector from MS-NLMP 4.2.4.1.1
+func TestNTOWFv2(t *testing.T) {
+	assert.Equal(t, "0c868a403bfd7a93a3001ef22ef02e3f",
+		hex.EncodeToString(ntowfv2("User", "Password", "Domain")))
+}
+
+func TestParsePACResult(t *testing.T) {
+	assert.Equal(t, []string{
+		"http://proxy1:8080", "https://proxy2:443", "DIRECT"},
+		parsePACResult("PROXY proxy1:8080; HTTPS proxy2:443; "+
+			"SOCKS proxy3:1080;DIRECT"))
+
+	assert.Equal(t, []string{}, parsePACResult(""))
+}
+
+func TestPACResolver(t *testing.T) {
+	pac_file := filepath.Join(t.TempDir(), "proxy.pac")
+	err := os.WriteFile(pac_file, []byte(`
+function FindProxyForURL(url, host) {
+  if (isPlainHostName(host) || dnsDomainIs(host, ".internal.com")) {
+    return "DIRECT";
+  }
+  if (shExpMatch(host, "*.example.com")) {
+    return "PROXY proxy1:8080; PROXY proxy2:8080";
+  }
+  return "PROXY default:3128";
+}
+`), 0600)
+	require.NoError(t, err)
+
+	resolver := newPACResolver(pac_file, 0)
+	ctx := context.Background()
+
+	for _, testcase := range []struct {
+		addr     string
+		expected []string
+	}{
+		{"server:443", []string{"DIRECT"}},
+		{"www.internal.com:443", []string{"DIRECT"}},
+		{"www.example.com:443", []string{
+			"http://proxy1:8080", "http://proxy2:8080"}},
+		{"www.velocidex.com:8000", []string{"http://default:3128"}},
+	} {
+		dialer := &proxyDialer{config_obj: &config_proto.ProxyConfig{}}
+		assert.Equal(t, testcase.expected,
+			dialer.resolvePAC(ctx, resolver, testcase.addr, nil),
+			testcase.addr)
+	}
+}
+
+// A minimal CONNECT proxy requiring basic auth.
+func startConnectProxy(t *testing.T, username, password string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	expected := "Basic " + base64.StdEncoding.EncodeToString(
+		[]byte(username+":"+password))
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				reader := bufio.NewReader(conn)
+				for {
+					req, err := http.ReadRequest(reader)
+					if err != nil {
+						return
+					}
+
+					if req.Header.Get("Proxy-Authorization") != expected {
+						io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n"+
+							"Proxy-Authenticate: Basic realm=\"test\"\r\n"+
+							"Content-Length: 0\r\n\r\n")
+						continue
+					}
+
+					target, err := net.Dial("tcp", req.Host)
+					if err != nil {
+						io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+						return
+					}
+					defer target.Close()
+
+					io.WriteString(conn, "HTTP/1.1 200 OK\r\n\r\n")
+					go io.Copy(target, reader)
+					io.Copy(conn, target)
+					return
+				}
+			}()
+		}
+	}()
+
+	return "http://" + listener.Addr().String()
+}
+
+func TestProxyFallbackChain(t *testing.T) {
+	// The target just echoes a greeting.
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer target.Close()
+
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			io.WriteString(conn, "hello")
+			conn.Close()
+		}
+	}()
+
+	// Nothing listens on this port.
+	dead, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dead_proxy := "http://" + dead.Addr().String()
+	dead.Close()
+
+	proxy := startConnectProxy(t, "user", "secret")
+
+	config_obj := config.GetDefaultConfig()
+	logger := logging.GetLogger(config_obj, &logging.ClientComponent)
+	client_config := &config_proto.ClientConfig{
+		ProxyConfig: &config_proto.ProxyConfig{
+			Proxies:    []string{dead_proxy, proxy},
+			AuthScheme: "basic",
+			Username:   "user",
+			Password:   "secret",
+		},
+	}
+
+	dialer, err := newProxyDialer(client_config,
+		&net.Dialer{Timeout: 5 * time.Second}, logger)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", target.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	data, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	// Wrong credentials are rejected.
+	client_config.ProxyConfig.Password = "wrong"
+	dialer, err = newProxyDialer(client_config,
+		&net.Dialer{Timeout: 5 * time.Second}, logger)
+	require.NoError(t, err)
+
+	_, err = dialer.DialContext(ctx, "tcp", target.Addr().String())
+	assert.Error(t, err)
+}
+
+// A PAC file which never returns falls back to the configured
+// proxies.
+func TestPACTimeout(t *testing.T) {
+	pac_file := filepath.Join(t.TempDir(), "proxy.pac")
+	err := os.WriteFile(pac_file, []byte(`
+function FindProxyForURL(url, host) {
+  while (true) {}
+}
+`), 0600)
+	require.NoError(t, err)
+
+	resolver := newPACResolver(pac_file, 0)
+	dialer := &proxyDialer{
+		config_obj: &config_proto.ProxyConfig{},
+		logger: logging.GetLogger(
+			config.GetDefaultConfig(), &logging.ClientComponent),
+	}
+
+	start := time.Now()
+	assert.Equal(t, []string{"http://fallback:3128"},
+		dialer.resolvePAC(context.Background(), resolver,
+			"www.example.com:443", []string{"http://fallback:3128"}))
+	assert.True(t, time.Now().Sub(start) < 2*pacTimeout)
+}
+
+// Data the proxy sends with the CONNECT response is not lost.
+func TestConnectBufferedData(t *testing.T) {
+	proxy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer proxy.Close()
+
+	go func() {
+		conn, err := proxy.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = http.ReadRequest(bufio.NewReader(conn))
+		io.WriteString(conn, "HTTP/1.1 200 OK\r\n\r\nhello")
+	}()
+
+	conn, err := net.Dial("tcp", proxy.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	dialer := &proxyDialer{config_obj: &config_proto.ProxyConfig{}}
+	tunnel, err := dialer.connect(context.Background(), conn,
+		&url.URL{Scheme: "http", Host: proxy.Addr().String()},
+		"www.example.com:443")
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(tunnel)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
@@ -35,13 +35,20 @@ func StartHttpCommunicatorService(
 		return nil, err
 	}
 
+	// URLs pushed by the server take precedence over the ones the
+	// client was deployed with.
+	urls := config_obj.Client.ServerUrls
+	if len(writeback.ServerUrls) > 0 {
+		urls = writeback.ServerUrls
+	}
+
 	// Now start the communicator so we can talk with the server.
 	comm, err := NewHTTPCommunicator(
 		ctx,
 		config_obj,
 		manager,
 		exe,
-		config_obj.Client.ServerUrls,
+		urls,
 		func() { on_error(ctx, config_obj) },
 		utils.RealClock{},
 	)
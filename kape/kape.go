@@ -0,0 +1,86 @@
+// Convert KAPE targets and modules to Velociraptor artifacts.
+
+// KAPE (https://github.com/EricZimmerman/KapeFiles) describes the
+// files to collect in Targets (.tkape files) and the tools used to
+// process them in Modules (.mkape files). Targets are converted to
+// artifacts which collect the target's globs using
+// Generic.Collectors.File. Modules are converted to artifacts which
+// run the module's processors, with the module binary declared as a
+// tool, and upload the processor output.
+
+// Targets and modules may refer to other targets and modules. These
+// are converted to calls to the artifacts converted from the referred
+// files so a whole KapeFiles tree should be converted together.
+
+package kape
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Velocidex/yaml/v2"
+	"www.velocidex.com/golang/velociraptor/artifacts/generate"
+)
+
+var (
+	UnsupportedError = errors.New("Unsupported KAPE file")
+
+	sanitize_regex = regexp.MustCompile("[^a-zA-Z0-9]+")
+)
+
+type Options struct {
+	// A prefix for the artifact names (e.g. Custom.)
+	Prefix string
+}
+
+// Convert a .tkape or .mkape file.
+func Convert(filename string, data []byte,
+	options Options) (*generate.Artifact, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".tkape":
+		target := &Target{}
+		err := yaml.Unmarshal(data, target)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", filename, err)
+		}
+		return convertTarget(filename, target, options)
+
+	case ".mkape":
+		module := &Module{}
+		err := yaml.Unmarshal(data, module)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", filename, err)
+		}
+		return convertModule(filename, module, options)
+	}
+
+	return nil, fmt.Errorf("%w: %v", UnsupportedError, filename)
+}
+
+// Names the artifact converted from a KAPE file. Names like
+// !SANS_Triage.tkape become Kape.Targets.SANS_Triage
+func ArtifactName(filename string, options Options) string {
+	base := path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	extension := path.Ext(base)
+
+	kind := "Targets"
+	if strings.EqualFold(extension, ".mkape") {
+		kind = "Modules"
+	}
+
+	name := sanitize_regex.ReplaceAllString(
+		strings.TrimSuffix(base, extension), "_")
+	return options.Prefix + "Kape." + kind + "." + strings.Trim(name, "_")
+}
+
+func formatDescription(description, author, filename string) string {
+	result := strings.TrimSpace(description)
+	if author != "" {
+		result += fmt.Sprintf(" (by %v)", author)
+	}
+	return result + fmt.Sprintf("\n\nConverted from the KAPE file %v.\n",
+		path.Base(strings.ReplaceAll(filename, `\`, "/")))
+}
@@ -0,0 +1,136 @@
+package kape
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Velocidex/yaml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/artifacts/generate"
+)
+
+var prefetchTarget = `
+Description: Prefetch
+Author: Eric Zimmerman
+Version: 1.0
+Id: 9c67f1b4-5d2b-4b8a-9a36-3a2f8e2d3f10
+RecreateDirectories: true
+Targets:
+    -
+        Name: Prefetch
+        Category: Prefetch
+        Path: C:\Windows\prefetch\
+        FileMask: '*.pf'
+    -
+        Name: $MFT
+        Category: FileSystem
+        Path: C:\
+        FileMask: $MFT
+    -
+        Name: Jump Lists
+        Category: FileFolderAccess
+        Path: C:\Users\%user%\AppData\Roaming\Microsoft\Windows\Recent\
+        Recursive: true
+    -
+        Name: Event logs
+        Category: EventLogs
+        Path: EventLogs.tkape
+`
+
+var pecmdModule = `
+Description: 'PECmd: process prefetch files'
+Category: ProgramExecution
+Author: Eric Zimmerman
+Version: 1.0
+Id: 1f0e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b
+BinaryUrl: https://example.com/PECmd.zip
+ExportFormat: csv
+Processors:
+    -
+        Executable: PECmd.exe
+        CommandLine: -d "%sourceDirectory%" --csv %destinationDirectory% -q
+        ExportFormat: csv
+    -
+        Executable: PECmd.exe
+        CommandLine: -d %sourceDirectory% --json %destinationDirectory%\json
+        ExportFormat: json
+`
+
+type parsedArtifact struct {
+	Name       string
+	Author     string
+	Tools      []struct{ Name, Url string }
+	Parameters []struct{ Name, Default string }
+	Sources    []struct{ Query string }
+}
+
+func parseArtifact(t *testing.T, artifact *generate.Artifact) *parsedArtifact {
+	result := &parsedArtifact{}
+	require.NoError(t, yaml.Unmarshal([]byte(artifact.Yaml), result))
+	require.Equal(t, 1, len(result.Sources))
+	return result
+}
+
+func TestConvertTarget(t *testing.T) {
+	artifact, err := Convert("Targets/Windows/Prefetch.tkape",
+		[]byte(prefetchTarget), Options{Prefix: "Custom."})
+	require.NoError(t, err)
+	assert.Equal(t, "Custom.Kape.Targets.Prefetch", artifact.Name)
+
+	parsed := parseArtifact(t, artifact)
+	assert.Equal(t, artifact.Name, parsed.Name)
+	assert.Equal(t, "Eric Zimmerman", parsed.Author)
+
+	require.Equal(t, 3, len(parsed.Parameters))
+	assert.Equal(t, "collectionSpec", parsed.Parameters[2].Name)
+	assert.Equal(t, `Glob,Accessor,Name
+Windows\prefetch\*.pf,auto,Prefetch
+$MFT,ntfs,$MFT
+Users\*\AppData\Roaming\Microsoft\Windows\Recent\**10,auto,Jump Lists
+`, parsed.Parameters[2].Default)
+
+	// Compound targets call the converted dependency.
+	assert.Contains(t, parsed.Sources[0].Query,
+		"SELECT * FROM Artifact.Custom.Kape.Targets.EventLogs(")
+}
+
+func TestConvertModule(t *testing.T) {
+	artifact, err := Convert("Modules/ProgramExecution/PECmd.mkape",
+		[]byte(pecmdModule), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "Kape.Modules.PECmd", artifact.Name)
+
+	parsed := parseArtifact(t, artifact)
+	require.Equal(t, 1, len(parsed.Tools))
+	assert.Equal(t, "Kape_PECmd", parsed.Tools[0].Name)
+	assert.Equal(t, "https://example.com/PECmd.zip", parsed.Tools[0].Url)
+	assert.Equal(t, "csv", parsed.Parameters[1].Default)
+
+	query := parsed.Sources[0].Query
+	assert.Contains(t, query, "ToolName='Kape_PECmd', IsExecutable=FALSE")
+	assert.Contains(t, query, "globs='**/PECmd.exe', root=ToolDir")
+	assert.Contains(t, query, "condition=ExportFormat =~ '^csv$'")
+	assert.Contains(t, query, "execve(argv=[Executable0[0].OSPath, "+
+		"'-d', SourceDirectory, '--csv', OutputDir, '-q']")
+	assert.Contains(t, query, "'--json', format(format='%v%v', "+
+		`args=[OutputDir, '\\json'])]`)
+}
+
+func TestCommandLine(t *testing.T) {
+	assert.Equal(t, []string{"-f", "C:\\Program Files\\x", "--csv", ""},
+		splitCommandLine(`-f "C:\Program Files\x"  --csv ""`))
+
+	_, err := argumentExpression("%unknownVariable%")
+	assert.True(t, errors.Is(err, UnsupportedError))
+}
+
+func TestArtifactName(t *testing.T) {
+	assert.Equal(t, "Kape.Targets.SANS_Triage",
+		ArtifactName(`Targets\Compound\!SANS_Triage.tkape`, Options{}))
+	assert.Equal(t, "Kape.Modules.EZParser",
+		ArtifactName("!EZParser.mkape", Options{}))
+
+	_, err := Convert("readme.md", nil, Options{})
+	assert.True(t, errors.Is(err, UnsupportedError))
+}
@@ -0,0 +1,251 @@
+package kape
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/artifacts/generate"
+)
+
+type Module struct {
+	Description  string       `json:"Description"`
+	Category     string       `json:"Category"`
+	Author       string       `json:"Author"`
+	Version      string       `json:"Version"`
+	Id           string       `json:"Id"`
+	BinaryUrl    string       `json:"BinaryUrl"`
+	ExportFormat string       `json:"ExportFormat"`
+	Processors   []*Processor `json:"Processors"`
+}
+
+type Processor struct {
+	Executable   string `json:"Executable"`
+	CommandLine  string `json:"CommandLine"`
+	ExportFormat string `json:"ExportFormat"`
+}
+
+// KAPE variables and the VQL expressions replacing them.
+var moduleVariables = map[string]string{
+	"sourcedirectory":      "SourceDirectory",
+	"sourcefile":           "SourceDirectory",
+	"destinationdirectory": "OutputDir",
+	"kapedirectory":        "ToolDir",
+}
+
+// Split a command line into arguments. Double quotes group
+// arguments containing spaces.
+func splitCommandLine(command string) []string {
+	result := []string{}
+	current := &strings.Builder{}
+	in_quote := false
+	has_arg := false
+
+	for _, c := range command {
+		switch {
+		case c == '"':
+			in_quote = !in_quote
+			has_arg = true
+		case (c == ' ' || c == '\t') && !in_quote:
+			if has_arg {
+				result = append(result, current.String())
+				current.Reset()
+				has_arg = false
+			}
+		default:
+			current.WriteRune(c)
+			has_arg = true
+		}
+	}
+
+	if has_arg {
+		result = append(result, current.String())
+	}
+	return result
+}
+
+// Convert an argument to a VQL expression substituting the KAPE
+// variables.
+func argumentExpression(argument string) (string, error) {
+	terms := []string{}
+	last := 0
+	for _, match := range variable_regex.FindAllStringIndex(argument, -1) {
+		if match[0] > last {
+			terms = append(terms, generate.QuoteString(argument[last:match[0]]))
+		}
+
+		variable := argument[match[0]+1 : match[1]-1]
+		expression, pres := moduleVariables[strings.ToLower(variable)]
+		if !pres {
+			return "", fmt.Errorf("%w: unknown variable %%%v%%",
+				UnsupportedError, variable)
+		}
+		terms = append(terms, expression)
+		last = match[1]
+	}
+
+	if last < len(argument) || len(terms) == 0 {
+		terms = append(terms, generate.QuoteString(argument[last:]))
+	}
+
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return "format(format='" + strings.Repeat("%v", len(terms)) +
+		"', args=[" + strings.Join(terms, ", ") + "])", nil
+}
+
+func toolName(executable string) string {
+	return "Kape_" + strings.Trim(sanitize_regex.ReplaceAllString(
+		strings.TrimSuffix(executable, path.Ext(executable)), "_"), "_")
+}
+
+func convertModule(filename string, module *Module,
+	options Options) (*generate.Artifact, error) {
+	if len(module.Processors) == 0 {
+		return nil, fmt.Errorf("%w: %v has no processors",
+			UnsupportedError, filename)
+	}
+
+	export_format := module.ExportFormat
+	if export_format == "" {
+		export_format = module.Processors[0].ExportFormat
+	}
+
+	// Modules without a binary URL use binaries built into Windows.
+	tool := ""
+	is_zip := false
+	if module.BinaryUrl != "" {
+		tool = toolName(module.Processors[0].Executable)
+		is_zip = strings.HasSuffix(strings.ToLower(module.BinaryUrl), ".zip")
+	}
+
+	query := &strings.Builder{}
+	query.WriteString("LET ToolDir <= tempdir()\n\nLET OutputDir <= tempdir()\n")
+
+	if tool != "" {
+		fmt.Fprintf(query, `
+LET Tool <= SELECT * FROM Artifact.Generic.Utils.FetchBinary(
+  ToolName=%v, IsExecutable=%v)
+`, generate.QuoteString(tool), strings.ToUpper(fmt.Sprintf("%v", !is_zip)))
+	}
+
+	if is_zip {
+		query.WriteString(`
+LET Unpacked <= SELECT * FROM unzip(
+  filename=Tool[0].FullPath, output_directory=ToolDir)
+`)
+	}
+
+	runs := []string{}
+	executables := make(map[string]string)
+	for i, processor := range module.Processors {
+		// Compound modules run other modules.
+		if strings.HasSuffix(strings.ToLower(processor.Executable), ".mkape") {
+			runs = append(runs, fmt.Sprintf(`
+  run%d={
+    SELECT * FROM Artifact.%v(
+      SourceDirectory=SourceDirectory, ExportFormat=ExportFormat)
+  }`, i, ArtifactName(processor.Executable, options)))
+			continue
+		}
+
+		executable := generate.QuoteString(processor.Executable)
+		if is_zip {
+			// Find the executable in the unpacked zip.
+			binary := path.Base(
+				strings.ReplaceAll(processor.Executable, `\`, "/"))
+			variable, pres := executables[binary]
+			if !pres {
+				variable = fmt.Sprintf("Executable%d", len(executables))
+				executables[binary] = variable
+				fmt.Fprintf(query, `
+LET %v <= SELECT OSPath FROM glob(
+  globs=%v, root=ToolDir)
+`, variable, generate.QuoteString("**/"+binary))
+			}
+			executable = variable + "[0].OSPath"
+
+		} else if tool != "" {
+			executable = "Tool[0].FullPath"
+		}
+
+		argv := []string{executable}
+		for _, argument := range splitCommandLine(processor.CommandLine) {
+			expression, err := argumentExpression(argument)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", filename, err)
+			}
+			argv = append(argv, expression)
+		}
+
+		runs = append(runs, fmt.Sprintf(`
+  run%d={
+    SELECT * FROM if(
+      condition=%v,
+      then={
+        SELECT * FROM execve(argv=[%v],
+          length=10000000)
+      })
+  }`, i, formatCondition(processor.ExportFormat),
+			strings.Join(argv, ", ")))
+	}
+
+	fmt.Fprintf(query, `
+SELECT * FROM chain(%v,
+  uploads={
+    SELECT OSPath.Basename AS Name, Size,
+           upload(file=OSPath, name=OSPath.Basename) AS Upload
+    FROM glob(globs="**", root=OutputDir)
+    WHERE NOT IsDir
+  })
+`, strings.Join(runs, ","))
+
+	name := ArtifactName(filename, options)
+
+	result := &strings.Builder{}
+	fmt.Fprintf(result, "name: %v\n", name)
+	fmt.Fprintf(result, "description: |\n%v", generate.Indent(formatDescription(
+		module.Description, module.Author, filename), 2))
+	if module.Author != "" {
+		fmt.Fprintf(result, "author: %v\n", generate.QuoteYaml(module.Author))
+	}
+
+	result.WriteString(`
+reference:
+  - https://github.com/EricZimmerman/KapeFiles
+
+precondition: SELECT OS From info() where OS = 'windows'
+
+required_permissions:
+  - EXECVE
+`)
+
+	if tool != "" {
+		fmt.Fprintf(result, "\ntools:\n  - name: %v\n    url: %v\n",
+			tool, generate.QuoteYaml(module.BinaryUrl))
+	}
+
+	fmt.Fprintf(result, `
+parameters:
+  - name: SourceDirectory
+    description: The directory the module processes.
+    default: "C:\\"
+  - name: ExportFormat
+    description: Only run the processors producing this format.
+    default: %v
+`, generate.QuoteYaml(export_format))
+
+	result.WriteString("\nsources:\n  - query: |\n")
+	result.WriteString(generate.Indent(query.String(), 6))
+
+	return &generate.Artifact{Name: name, Yaml: result.String()}, nil
+}
+
+// Processors without an export format always run.
+func formatCondition(format string) string {
+	if format == "" {
+		return "TRUE"
+	}
+	return fmt.Sprintf("ExportFormat =~ %v", generate.QuoteString("^"+format+"$"))
+}
@@ -0,0 +1,164 @@
+package kape
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/artifacts/generate"
+)
+
+type Target struct {
+	Description         string         `json:"Description"`
+	Author              string         `json:"Author"`
+	Version             string         `json:"Version"`
+	Id                  string         `json:"Id"`
+	RecreateDirectories bool           `json:"RecreateDirectories"`
+	Targets             []*TargetEntry `json:"Targets"`
+}
+
+type TargetEntry struct {
+	Name      string `json:"Name"`
+	Category  string `json:"Category"`
+	Path      string `json:"Path"`
+	FileMask  string `json:"FileMask"`
+	Recursive bool   `json:"Recursive"`
+	Comment   string `json:"Comment"`
+}
+
+var (
+	drive_regex    = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+	variable_regex = regexp.MustCompile(`%[a-zA-Z]+%`)
+)
+
+// Convert the target's path to a glob relative to the device. This
+// follows scripts/kape_files.py which generates
+// Windows.KapeFiles.Targets.
+func targetGlob(entry *TargetEntry) string {
+	glob := strings.ReplaceAll(entry.Path, "/", `\`)
+
+	if entry.Recursive {
+		glob = strings.TrimRight(glob, `\`) + `\**10`
+	}
+
+	if entry.FileMask != "" {
+		glob = strings.TrimRight(glob, `\`) + `\` + entry.FileMask
+	}
+
+	// A trailing separator means all the files in the directory.
+	if strings.HasSuffix(glob, `\`) {
+		glob += "*"
+	}
+
+	glob = drive_regex.ReplaceAllString(glob, "")
+
+	// Variables like %user% match any directory.
+	return variable_regex.ReplaceAllString(glob, "*")
+}
+
+// Special NTFS files need the ntfs accessor. Other files are read
+// with the faster auto accessor.
+func targetAccessor(glob string) string {
+	if strings.Contains(glob, "$Recycle.Bin") {
+		return "auto"
+	}
+
+	if strings.Contains(glob, ":") || strings.Contains(glob, "$") {
+		return "ntfs"
+	}
+	return "auto"
+}
+
+func convertTarget(filename string, target *Target,
+	options Options) (*generate.Artifact, error) {
+	if len(target.Targets) == 0 {
+		return nil, fmt.Errorf("%w: %v has no targets",
+			UnsupportedError, filename)
+	}
+
+	spec := &bytes.Buffer{}
+	writer := csv.NewWriter(spec)
+	_ = writer.Write([]string{"Glob", "Accessor", "Name"})
+
+	dependencies := []string{}
+	for _, entry := range target.Targets {
+		// Compound targets refer to other targets.
+		if strings.HasSuffix(strings.ToLower(entry.Path), ".tkape") {
+			dependencies = append(dependencies,
+				ArtifactName(entry.Path, options))
+			continue
+		}
+
+		if entry.Path == "" {
+			continue
+		}
+
+		glob := targetGlob(entry)
+		_ = writer.Write([]string{glob, targetAccessor(glob), entry.Name})
+	}
+	writer.Flush()
+
+	name := ArtifactName(filename, options)
+
+	result := &strings.Builder{}
+	fmt.Fprintf(result, "name: %v\n", name)
+	fmt.Fprintf(result, "description: |\n%v", generate.Indent(formatDescription(
+		target.Description, target.Author, filename), 2))
+	if target.Author != "" {
+		fmt.Fprintf(result, "author: %v\n", generate.QuoteYaml(target.Author))
+	}
+
+	result.WriteString(`
+reference:
+  - https://github.com/EricZimmerman/KapeFiles
+
+precondition: SELECT OS From info() where OS = 'windows'
+
+parameters:
+  - name: Device
+    description: Name of the drive letter to search.
+    default: "C:"
+  - name: DontBeLazy
+    description: Use the ntfs accessor for all files instead of the faster auto accessor.
+    type: bool
+  - name: collectionSpec
+    description: The globs to collect.
+    type: csv
+    default: |
+`)
+	result.WriteString(generate.Indent(spec.String(), 6))
+
+	query := `LET ntfs_specs = SELECT Glob FROM collectionSpec
+  WHERE Accessor = 'ntfs'
+
+LET auto_specs = SELECT Glob FROM collectionSpec
+  WHERE Accessor != 'ntfs'
+
+SELECT * FROM chain(
+  ntfs={
+    SELECT * FROM Artifact.Generic.Collectors.File(
+      Root=Device, Accessor="ntfs", collectionSpec=ntfs_specs)
+  },
+  auto={
+    SELECT * FROM Artifact.Generic.Collectors.File(
+      Root=Device,
+      Accessor=if(condition=DontBeLazy, then="ntfs", else="auto"),
+      collectionSpec=auto_specs)
+  }`
+
+	for i, dependency := range dependencies {
+		query += fmt.Sprintf(`,
+  target%d={
+    SELECT * FROM Artifact.%v(
+      Device=Device, DontBeLazy=DontBeLazy)
+  }`, i, dependency)
+	}
+	query += ")\n"
+
+	result.WriteString("\nsources:\n  - query: |\n")
+	result.WriteString(generate.Indent(query, 6))
+
+	return &generate.Artifact{Name: name, Yaml: result.String()}, nil
+}
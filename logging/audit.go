@@ -1,20 +1,145 @@
 package logging
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 // A wrapper around audit logging. Audit events need to have more
 // structure than the other events, so they can be easily
 // searched. This wrapper ensures the minimal amount of information is
-// included in the event.
+// included in the event. Events are tagged with the org they
+// happened in so they can be read back per org.
 func LogAudit(
 	config_obj *config_proto.Config,
 	principal, operation string,
 	details logrus.Fields) {
 
 	details["principal"] = principal
+	details["org_id"] = utils.NormalizedOrgId(config_obj.OrgId)
 	logger := GetLogger(config_obj, &Audit)
 	logger.WithFields(details).Info(operation)
 }
+
+// An audit event read back from the audit log.
+type AuditEvent struct {
+	Time      time.Time              `json:"time"`
+	Principal string                 `json:"principal"`
+	OrgId     string                 `json:"org_id"`
+	Operation string                 `json:"operation"`
+	Details   map[string]interface{} `json:"details"`
+}
+
+// Read the audit events logged between start and end from the
+// audit log files. Logs of all the nodes sharing the logging
+// directory are included but only events of the config's org are
+// returned.
+func ReadAuditLog(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	start, end time.Time,
+	cb func(event *AuditEvent)) error {
+
+	if config_obj.Logging == nil || config_obj.Logging.OutputDirectory == "" {
+		return errors.New("Audit log is not stored - set Logging.output_directory")
+	}
+
+	var files []string
+	base_directory := config_obj.Logging.OutputDirectory
+	for _, pattern := range []string{
+		filepath.Join(base_directory, Audit+"_info.log.*"),
+		filepath.Join(base_directory, "*", Audit+"_info.log.*"),
+	} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	for _, filename := range files {
+		// Skip rotated files last written before the start.
+		stat, err := os.Stat(filename)
+		if err != nil || stat.ModTime().Before(start) {
+			continue
+		}
+
+		err = readAuditFile(ctx, filename, config_obj.OrgId, start, end, cb)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readAuditFile(ctx context.Context, filename, org_id string,
+	start, end time.Time, cb func(event *AuditEvent)) error {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		details := make(map[string]interface{})
+		err := json.Unmarshal(scanner.Bytes(), &details)
+		if err != nil {
+			continue
+		}
+
+		principal, _ := details["principal"].(string)
+		operation, _ := details["msg"].(string)
+		timestamp, _ := details["time"].(string)
+
+		// Not an audit event (e.g. the log header).
+		if principal == "" {
+			continue
+		}
+
+		// Events logged before they were tagged with an org are
+		// treated as belonging to the root org.
+		event_org_id, _ := details["org_id"].(string)
+		if !utils.CompareOrgIds(event_org_id, org_id) {
+			continue
+		}
+
+		event_time, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil || event_time.Before(start) || event_time.After(end) {
+			continue
+		}
+
+		for _, k := range []string{
+			"principal", "org_id", "msg", "time", "level"} {
+			delete(details, k)
+		}
+
+		cb(&AuditEvent{
+			Time:      event_time,
+			Principal: principal,
+			OrgId:     utils.NormalizedOrgId(event_org_id),
+			Operation: operation,
+			Details:   details,
+		})
+	}
+
+	return scanner.Err()
+}
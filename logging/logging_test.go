@@ -1,6 +1,7 @@
 package logging_test
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -11,7 +12,9 @@ import (
 	"github.com/Velocidex/ordereddict"
 	"github.com/sebdah/goldie"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
 	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vtesting/assert"
@@ -62,3 +65,77 @@ func TestAuditLog(t *testing.T) {
 
 	goldie.Assert(t, "TestAuditLog", data)
 }
+
+func TestReadAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_store_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clock := &utils.MockClock{MockNow: time.Unix(1602103388, 0)}
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Logging.OutputDirectory = dir
+
+	err = logging.InitLogging(config_obj)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		clock.MockNow = time.Unix(1602103388+int64(i)*100, 0)
+		logging.LogAudit(config_obj, "Principal", "ViewFlow",
+			logrus.Fields{
+				"client_id": "C.1234",
+				"index":     i,
+			})
+	}
+
+	var events []*logging.AuditEvent
+	err = logging.ReadAuditLog(context.Background(), config_obj,
+		time.Unix(1602103388+50, 0), time.Unix(1602103388+500, 0),
+		func(event *logging.AuditEvent) {
+			events = append(events, event)
+		})
+	assert.NoError(t, err)
+
+	// The first event is before the start.
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, "root", events[0].OrgId)
+	assert.Equal(t, "Principal", events[0].Principal)
+	assert.Equal(t, "ViewFlow", events[0].Operation)
+	assert.Equal(t, "C.1234", events[0].Details["client_id"])
+	assert.Equal(t, float64(1), events[0].Details["index"])
+}
+
+func TestReadAuditLogOrgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_store_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config_obj := config.GetDefaultConfig()
+	config_obj.Logging.OutputDirectory = dir
+
+	err = logging.InitLogging(config_obj)
+	assert.NoError(t, err)
+
+	org_config_obj := proto.Clone(config_obj).(*config_proto.Config)
+	org_config_obj.OrgId = "O123"
+
+	logging.LogAudit(config_obj, "Root", "ViewFlow",
+		logrus.Fields{"client_id": "C.1234"})
+	logging.LogAudit(org_config_obj, "Tenant", "ViewFlow",
+		logrus.Fields{"client_id": "C.1234"})
+
+	// Each org only sees its own events.
+	for _, c := range []*config_proto.Config{config_obj, org_config_obj} {
+		var events []*logging.AuditEvent
+		err = logging.ReadAuditLog(context.Background(), c,
+			time.Unix(0, 0), time.Now().Add(time.Hour),
+			func(event *logging.AuditEvent) {
+				events = append(events, event)
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(events))
+		assert.Equal(t, utils.NormalizedOrgId(c.OrgId), events[0].OrgId)
+	}
+}
@@ -1,6 +1,7 @@
 package paths
 
 import (
+	"strconv"
 	"strings"
 
 	"www.velocidex.com/golang/velociraptor/file_store/api"
@@ -11,3 +12,54 @@ func GetArtifactDefintionPath(name string) api.FSPathSpec {
 	return ARTIFACT_DEFINITION_PREFIX.
 		AddUnsafeChild(strings.Split(name, ".")...)
 }
+
+type ArtifactVersionPathManager struct {
+	name string
+}
+
+func NewArtifactVersionPathManager(name string) *ArtifactVersionPathManager {
+	return &ArtifactVersionPathManager{name: name}
+}
+
+// Stores a single version of the artifact. The repository manager
+// validates the name but we escape it anyway.
+func (self *ArtifactVersionPathManager) Version(version uint64) api.DSPathSpec {
+	return ARTIFACT_VERSIONS_ROOT.AddUnsafeChild(
+		self.name, strconv.FormatUint(version, 10)).
+		SetTag("ArtifactVersion")
+}
+
+// Contains all the versions of the artifact.
+func (self *ArtifactVersionPathManager) Directory() api.DSPathSpec {
+	return ARTIFACT_VERSIONS_ROOT.AddUnsafeChild(self.name)
+}
+
+// The staging record of the artifact while it is staged. The
+// repository manager validates the name but we escape it anyway.
+func (self *ArtifactVersionPathManager) Staging() api.DSPathSpec {
+	return ARTIFACT_STAGING_ROOT.AddUnsafeChild(self.name).
+		SetTag("ArtifactStaging")
+}
+
+type ArtifactSchemaPathManager struct {
+	name string
+}
+
+func NewArtifactSchemaPathManager(name string) *ArtifactSchemaPathManager {
+	return &ArtifactSchemaPathManager{name: name}
+}
+
+// Stores a single version of the artifact's output schema. Names
+// may refer to a source (Artifact/Source) which is stored in a
+// subdirectory. The repository manager validates the name but we
+// escape it anyway.
+func (self *ArtifactSchemaPathManager) Version(version uint64) api.DSPathSpec {
+	return self.Directory().AddUnsafeChild(strconv.FormatUint(version, 10)).
+		SetTag("ArtifactSchema")
+}
+
+// Contains all the schema versions of the artifact.
+func (self *ArtifactSchemaPathManager) Directory() api.DSPathSpec {
+	return ARTIFACT_SCHEMAS_ROOT.AddUnsafeChild(
+		strings.SplitN(self.name, "/", 2)...)
+}
@@ -0,0 +1,22 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+type CanaryPathManager struct {
+	token_id string
+}
+
+func NewCanaryPathManager(token_id string) *CanaryPathManager {
+	return &CanaryPathManager{token_id: token_id}
+}
+
+// Stores the token record.
+func (self *CanaryPathManager) Path() api.DSPathSpec {
+	return CANARY_ROOT.AddChild(self.token_id).SetTag("CanaryToken")
+}
+
+func (self *CanaryPathManager) Directory() api.DSPathSpec {
+	return CANARY_ROOT
+}
@@ -46,6 +46,13 @@ func (self ClientPathManager) Metadata() api.DSPathSpec {
 		SetType(api.PATH_TYPE_DATASTORE_JSON)
 }
 
+// The capabilities the client reported during interrogation.
+func (self ClientPathManager) Capabilities() api.DSPathSpec {
+	return self.root.AddChild("capabilities").
+		SetType(api.PATH_TYPE_DATASTORE_JSON).
+		SetTag("ClientCapabilities")
+}
+
 // Store each client's public key so we can communicate with it.
 func (self ClientPathManager) Key() api.DSPathSpec {
 	return self.root.AddChild("key").
@@ -48,11 +48,70 @@ var (
 	ORGS_ROOT = path_specs.NewSafeDatastorePath("orgs").
 			SetType(api.PATH_TYPE_DATASTORE_JSON)
 
+	JOBS_ROOT = path_specs.NewSafeDatastorePath("server_jobs").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	CANARY_ROOT = path_specs.NewSafeDatastorePath("canary_tokens").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	SESSIONS_ROOT = path_specs.NewSafeDatastorePath("gui_sessions").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	ARTIFACT_VERSIONS_ROOT = path_specs.NewSafeDatastorePath("artifact_versions").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Staged artifacts by name.
+	ARTIFACT_STAGING_ROOT = path_specs.NewSafeDatastorePath("artifact_staging").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	ARTIFACT_SCHEMAS_ROOT = path_specs.NewSafeDatastorePath("artifact_schemas").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	WEBAUTHN_ROOT = path_specs.NewUnsafeDatastorePath("webauthn").
+			SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Crashing clients indexed by the version they run.
+	CRASH_INDEX_ROOT = path_specs.NewUnsafeDatastorePath("crash_index").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Deduplicated uploads are stored here by their sha256 hash.
+	DEDUP_CONTENT_ROOT = path_specs.NewSafeFilestorePath("content").
+				SetType(api.PATH_TYPE_FILESTORE_ANY)
+
+	// Links to stored copies are staged here before they replace
+	// an upload.
+	DEDUP_TMP_ROOT = path_specs.NewSafeFilestorePath("content", "tmp").
+			SetType(api.PATH_TYPE_FILESTORE_ANY)
+
+	// Records filestore files moved to the cold tier.
+	FILESTORE_TIER_ROOT = path_specs.NewSafeDatastorePath("filestore_tiers").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
+	// Collections scheduled for deletion by the retention policy
+	// of their artifacts.
+	RETENTION_SCHEDULE = path_specs.NewSafeFilestorePath(
+		"config", "retention_schedule").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+
+	// Bytes of uploads stored by each client and hunt.
+	FILESTORE_QUOTA_ROOT = path_specs.NewUnsafeDatastorePath("filestore_quota").
+				SetType(api.PATH_TYPE_DATASTORE_JSON)
+
 	// The public directory is exported without authentication and
 	// is used to distribute the client binaries.
 	PUBLIC_ROOT = path_specs.NewUnsafeFilestorePath("public").
 			SetType(api.PATH_TYPE_FILESTORE_ANY)
 
+	// The manifest of the last update bundle applied to the server.
+	BUNDLE_STATE = path_specs.NewSafeFilestorePath("bundles", "last_applied").
+			SetType(api.PATH_TYPE_FILESTORE_JSON)
+
+	// The manifest of the last bundle loaded from the remote
+	// artifact repository.
+	REMOTE_REPOSITORY_STATE = path_specs.NewSafeFilestorePath(
+		"bundles", "remote_repository").
+		SetType(api.PATH_TYPE_FILESTORE_JSON)
+
 	// Timelines
 	TIMELINE_URN = path_specs.NewSafeDatastorePath("timelines").
 			SetType(api.PATH_TYPE_DATASTORE_JSON)
@@ -75,6 +134,10 @@ var (
 	ClientMonitoringFlowURN = path_specs.NewSafeDatastorePath(
 		"config", "client_monitoring").SetType(api.PATH_TYPE_DATASTORE_JSON)
 
+	// Snapshots of the client monitoring table each time it changes.
+	ClientMonitoringHistory = path_specs.NewSafeFilestorePath(
+		"config", "client_monitoring_history")
+
 	ThirdPartyInventory = path_specs.NewSafeDatastorePath(
 		"config", "inventory").SetType(api.PATH_TYPE_DATASTORE_JSON)
 )
@@ -0,0 +1,38 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	CONTENT_PACKS_ROOT = path_specs.NewUnsafeFilestorePath("content_packs").
+		SetType(api.PATH_TYPE_FILESTORE_ANY)
+)
+
+type ContentPackPathManager struct {
+	name string
+}
+
+func NewContentPackPathManager(name string) *ContentPackPathManager {
+	return &ContentPackPathManager{name: name}
+}
+
+// Records the installed version of the pack.
+func (self *ContentPackPathManager) Record() api.DSPathSpec {
+	return CONFIG_ROOT.AddChild("content_packs").AddUnsafeChild(self.name).
+		SetTag("ContentPack")
+}
+
+func (self *ContentPackPathManager) RecordDirectory() api.DSPathSpec {
+	return CONFIG_ROOT.AddChild("content_packs")
+}
+
+// Rule files (e.g. yara or sigma rules) shipped with the pack. These
+// are not stored in the public directory as they may not be meant
+// for distribution.
+func (self *ContentPackPathManager) File(name string) api.FSPathSpec {
+	return CONTENT_PACKS_ROOT.AddChild(self.name).AddChild(
+		utils.SplitComponents(name)...)
+}
@@ -0,0 +1,31 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+type CrashPathManager struct {
+	client_id string
+}
+
+func NewCrashPathManager(client_id string) *CrashPathManager {
+	return &CrashPathManager{client_id: client_id}
+}
+
+// All the crash reports uploaded by the client.
+func (self *CrashPathManager) Directory() api.DSPathSpec {
+	return CLIENTS_ROOT.AddChild(self.client_id, "crashes").
+		SetType(api.PATH_TYPE_DATASTORE_JSON)
+}
+
+// Stores the full crash report. The crash id is chosen by the client
+// so it is not safe.
+func (self *CrashPathManager) Report(crash_id string) api.DSPathSpec {
+	return self.Directory().AddUnsafeChild(crash_id).SetTag("CrashReport")
+}
+
+// The index entry of the client for a version.
+func (self *CrashPathManager) IndexRecord(version string) api.DSPathSpec {
+	return CRASH_INDEX_ROOT.AddChild(version, self.client_id).
+		SetTag("CrashIndex")
+}
@@ -85,6 +85,17 @@ func (self FlowPathManager) UploadMetadata() api.FSPathSpec {
 	return self.Path().AddChild("uploads").AsFilestorePath()
 }
 
+// Client signatures over the flow's result sets and uploads.
+func (self FlowPathManager) Signatures() api.FSPathSpec {
+	return self.Path().AddChild("signatures").AsFilestorePath()
+}
+
+// The hashes of the flow's uploads recorded by the filestore
+// scrubber.
+func (self FlowPathManager) UploadHashes() api.FSPathSpec {
+	return self.Path().AddChild("upload_hashes").AsFilestorePath()
+}
+
 func (self FlowPathManager) UploadContainer() api.FSPathSpec {
 	return self.Path().AddUnsafeChild("uploads").
 		AsFilestorePath().
@@ -137,17 +148,20 @@ func (self FlowPathManager) GetUploadsFile(
 		accessor = "file"
 	}
 
-	base_path := CLIENTS_ROOT.AddUnsafeChild(self.client_id, "collections",
-		self.flow_id, "uploads").AsFilestorePath().
-		SetType(api.PATH_TYPE_FILESTORE_ANY)
-
 	return &UploadFile{
 		client_path: client_path,
-		path: base_path.AddUnsafeChild(accessor).
+		path: self.UploadsDirectory().AddUnsafeChild(accessor).
 			AddChild(ExtractClientPathComponents(client_path)...),
 	}
 }
 
+// All the collection's uploads are stored under this directory.
+func (self FlowPathManager) UploadsDirectory() api.FSPathSpec {
+	return CLIENTS_ROOT.AddUnsafeChild(self.client_id, "collections",
+		self.flow_id, "uploads").AsFilestorePath().
+		SetType(api.PATH_TYPE_FILESTORE_ANY)
+}
+
 // The manager for the flow's notebook
 func (self FlowPathManager) Notebook() *NotebookPathManager {
 	notebook_id := fmt.Sprintf("N.%v-%v", self.flow_id, self.client_id)
@@ -0,0 +1,22 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+type JobPathManager struct {
+	job_id string
+}
+
+func NewJobPathManager(job_id string) *JobPathManager {
+	return &JobPathManager{job_id: job_id}
+}
+
+// Stores the state of the job.
+func (self *JobPathManager) Path() api.DSPathSpec {
+	return JOBS_ROOT.AddUnsafeChild(self.job_id).SetTag("ServerJob")
+}
+
+func (self *JobPathManager) Directory() api.DSPathSpec {
+	return JOBS_ROOT
+}
@@ -0,0 +1,11 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// The upload usage of a client or a hunt. The type is either
+// "client" or "hunt".
+func FilestoreQuotaRecord(quota_type, name string) api.DSPathSpec {
+	return FILESTORE_QUOTA_ROOT.AddChild(quota_type, name)
+}
@@ -12,3 +12,9 @@ func (self *ServerStatePathManager) Install() api.DSPathSpec {
 	return CONFIG_ROOT.AddChild("install_time").
 		SetTag("ServerState")
 }
+
+// The persisted token bucket state of a named rate limiter.
+func (self *ServerStatePathManager) RateLimiter(name string) api.DSPathSpec {
+	return CONFIG_ROOT.AddChild("rate_limiters").AddUnsafeChild(name).
+		SetTag("RateLimiter")
+}
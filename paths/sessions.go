@@ -0,0 +1,23 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+type SessionPathManager struct {
+	session_id string
+}
+
+func NewSessionPathManager(session_id string) *SessionPathManager {
+	return &SessionPathManager{session_id: session_id}
+}
+
+// Stores the session record. The session id is validated by the
+// session manager but comes from the request so it is not safe.
+func (self *SessionPathManager) Path() api.DSPathSpec {
+	return SESSIONS_ROOT.AddUnsafeChild(self.session_id).SetTag("GUISession")
+}
+
+func (self *SessionPathManager) Directory() api.DSPathSpec {
+	return SESSIONS_ROOT
+}
@@ -0,0 +1,20 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// Snapshots of server objects are appended to these result sets each
+// time the object changes so we can tell what the object looked like
+// at any point in time.
+
+// The history of the client's labels.
+func (self ClientPathManager) LabelHistory() api.FSPathSpec {
+	return CLIENTS_ROOT.AddUnsafeChild(self.client_id, "history", "labels").
+		AsFilestorePath()
+}
+
+// The history of the hunt's state.
+func (self HuntPathManager) History() api.FSPathSpec {
+	return HUNTS_ROOT.AddChild(self.hunt_id, "history").AsFilestorePath()
+}
@@ -0,0 +1,20 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// The datastore record for a filestore file that was moved to the
+// cold tier. The file's extension is part of the name because files
+// of different types share the same components (e.g. a result set
+// and its index).
+func FilestoreTierRecord(path api.FSPathSpec) api.DSPathSpec {
+	components := append([]string{}, path.Components()...)
+	if len(components) == 0 {
+		return FILESTORE_TIER_ROOT
+	}
+
+	last := len(components) - 1
+	components[last] += api.GetExtensionForFilestore(path)
+	return FILESTORE_TIER_ROOT.AddUnsafeChild(components...)
+}
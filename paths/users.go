@@ -30,6 +30,19 @@ func (self UserPathManager) GUIOptions() api.DSPathSpec {
 		api.PATH_TYPE_DATASTORE_JSON)
 }
 
+// Where we store the user's TOTP enrollment for step-up
+// authentication.
+func (self UserPathManager) MFA() api.DSPathSpec {
+	return USERS_ROOT.AddChild("mfa", self.Name).SetType(
+		api.PATH_TYPE_DATASTORE_JSON)
+}
+
+// Where we store the user's unused recovery codes.
+func (self UserPathManager) RecoveryCodes() api.DSPathSpec {
+	return USERS_ROOT.AddChild("recovery", self.Name).SetType(
+		api.PATH_TYPE_DATASTORE_JSON)
+}
+
 // Where we store the user's MRU clients
 func (self UserPathManager) MRUClient(client_id string) api.DSPathSpec {
 	return USERS_ROOT.AddChild(self.Name, "mru", client_id).SetType(
@@ -0,0 +1,23 @@
+package paths
+
+import (
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+)
+
+// WebAuthn credentials are stored by credential id so a passkey
+// login can find the user from the credential alone.
+type WebAuthnPathManager struct {
+	credential_id string
+}
+
+func NewWebAuthnPathManager(credential_id string) *WebAuthnPathManager {
+	return &WebAuthnPathManager{credential_id: credential_id}
+}
+
+func (self *WebAuthnPathManager) Path() api.DSPathSpec {
+	return WEBAUTHN_ROOT.AddChild(self.credential_id).SetTag("WebAuthnCredential")
+}
+
+func (self *WebAuthnPathManager) Directory() api.DSPathSpec {
+	return WEBAUTHN_ROOT
+}
@@ -0,0 +1,258 @@
+// Triage profiles.
+
+// A triage profile is a single self-describing file which holds a
+// set of artifacts, the parameters to collect them with and the
+// definitions of the artifacts and all their dependencies. The same
+// profile can be built into an offline collector or used to schedule
+// a hunt, so exactly the same triage definition is collected in both
+// modes.
+
+// Definitions are carried with their sha256 hash. When a profile is
+// used on a server the server's definitions must match the profile,
+// otherwise the hunt would collect something different.
+
+package profiles
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	PROFILE_FORMAT = "velociraptor/triage-profile/v1"
+)
+
+var (
+	InvalidProfileError = errors.New("InvalidProfile")
+)
+
+type Definition struct {
+	Name   string `json:"name"`
+	Sha256 string `json:"sha256"`
+	Raw    string `json:"raw"`
+}
+
+type Resources struct {
+	Timeout  uint64  `json:"timeout,omitempty"`
+	CpuLimit float64 `json:"cpu_limit,omitempty"`
+	MaxRows  uint64  `json:"max_rows,omitempty"`
+	MaxBytes uint64  `json:"max_bytes,omitempty"`
+}
+
+type Profile struct {
+	Format      string `json:"format"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Created     int64  `json:"created"`
+
+	// The Velociraptor version which compiled the profile.
+	Version string `json:"version"`
+
+	// The artifacts to collect and their parameters, by artifact
+	// name.
+	Artifacts  []string                     `json:"artifacts"`
+	Parameters map[string]map[string]string `json:"parameters"`
+
+	Resources Resources `json:"resources"`
+
+	// The collected artifacts and all their dependencies.
+	Definitions []*Definition `json:"definitions"`
+}
+
+type CompileOptions struct {
+	Name        string
+	Description string
+	Artifacts   []string
+
+	// Parameters by artifact name.
+	Parameters map[string]map[string]string
+	Resources  Resources
+}
+
+func hashDefinition(raw string) string {
+	hash := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(hash[:])
+}
+
+func collectDependencies(node *launcher.ArtifactDependency,
+	names map[string]bool) {
+	names[node.Name] = true
+	for _, dep := range node.Dependencies {
+		collectDependencies(dep, names)
+	}
+}
+
+// Compile the artifacts from the repository into a profile.
+// Parameters which the artifacts do not declare are rejected so
+// typos do not silently collect with the defaults.
+func Compile(config_obj *config_proto.Config,
+	repository services.Repository,
+	options CompileOptions) (*Profile, error) {
+	if len(options.Artifacts) == 0 {
+		return nil, fmt.Errorf("%w: No artifacts specified",
+			InvalidProfileError)
+	}
+
+	profile := &Profile{
+		Format:      PROFILE_FORMAT,
+		Name:        options.Name,
+		Description: options.Description,
+		Created:     utils.GetTime().Now().Unix(),
+		Version:     constants.VERSION,
+		Artifacts:   options.Artifacts,
+		Parameters:  make(map[string]map[string]string),
+		Resources:   options.Resources,
+	}
+
+	names := make(map[string]bool)
+	for _, name := range options.Artifacts {
+		definition, pres := repository.Get(config_obj, name)
+		if !pres {
+			return nil, fmt.Errorf("%w: Artifact %v not found",
+				InvalidProfileError, name)
+		}
+
+		parameters := options.Parameters[name]
+		for key := range parameters {
+			declared := false
+			for _, p := range definition.Parameters {
+				if p.Name == key {
+					declared = true
+					break
+				}
+			}
+			if !declared {
+				return nil, fmt.Errorf("%w: Artifact %v has no parameter %v",
+					InvalidProfileError, name, key)
+			}
+		}
+		if len(parameters) > 0 {
+			profile.Parameters[name] = parameters
+		}
+
+		tree, err := launcher.GetArtifactDependencyTree(
+			config_obj, repository, name)
+		if err != nil {
+			return nil, err
+		}
+		collectDependencies(tree, names)
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		definition, pres := repository.Get(config_obj, name)
+		if !pres {
+			return nil, fmt.Errorf("%w: Artifact %v not found",
+				InvalidProfileError, name)
+		}
+
+		profile.Definitions = append(profile.Definitions, &Definition{
+			Name:   name,
+			Sha256: hashDefinition(definition.Raw),
+			Raw:    definition.Raw,
+		})
+	}
+
+	return profile, nil
+}
+
+// Parse a profile and check it is intact.
+func Parse(data []byte) (*Profile, error) {
+	profile := &Profile{}
+	err := json.Unmarshal(data, profile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidProfileError, err)
+	}
+
+	if profile.Format != PROFILE_FORMAT {
+		return nil, fmt.Errorf("%w: Unsupported format %v",
+			InvalidProfileError, profile.Format)
+	}
+
+	if len(profile.Artifacts) == 0 {
+		return nil, fmt.Errorf("%w: No artifacts specified",
+			InvalidProfileError)
+	}
+
+	for _, definition := range profile.Definitions {
+		if hashDefinition(definition.Raw) != definition.Sha256 {
+			return nil, fmt.Errorf("%w: Definition of %v was modified",
+				InvalidProfileError, definition.Name)
+		}
+	}
+
+	return profile, nil
+}
+
+func (self *Profile) Marshal() ([]byte, error) {
+	return json.MarshalIndent(self)
+}
+
+// The artifact parameters in the form used by the collector and
+// hunt() spec arguments.
+func (self *Profile) Spec() *ordereddict.Dict {
+	spec := ordereddict.NewDict()
+	for _, name := range self.Artifacts {
+		parameters := ordereddict.NewDict()
+
+		keys := make([]string, 0, len(self.Parameters[name]))
+		for k := range self.Parameters[name] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			parameters.Set(k, self.Parameters[name][k])
+		}
+		spec.Set(name, parameters)
+	}
+	return spec
+}
+
+// Load the profile's definitions into the repository, replacing
+// any existing ones. Only use this on a private repository (e.g.
+// when building an offline collector).
+func (self *Profile) LoadInto(repository services.Repository) error {
+	for _, definition := range self.Definitions {
+		_, err := repository.LoadYaml(definition.Raw,
+			true /* validate */, false /* built_in */)
+		if err != nil {
+			return fmt.Errorf("Loading %v: %w", definition.Name, err)
+		}
+	}
+	return nil
+}
+
+// Check the repository has the same definitions as the profile.
+func (self *Profile) Verify(config_obj *config_proto.Config,
+	repository services.Repository) error {
+	for _, definition := range self.Definitions {
+		existing, pres := repository.Get(config_obj, definition.Name)
+		if !pres {
+			return fmt.Errorf("%w: Artifact %v is not known on this server",
+				InvalidProfileError, definition.Name)
+		}
+
+		if hashDefinition(existing.Raw) != definition.Sha256 {
+			return fmt.Errorf("%w: Artifact %v differs from the profile",
+				InvalidProfileError, definition.Name)
+		}
+	}
+	return nil
+}
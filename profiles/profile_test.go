@@ -0,0 +1,109 @@
+package profiles_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/profiles"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var profileArtifacts = []string{`
+name: Test.Triage
+parameters:
+  - name: Glob
+    default: C:/*
+sources:
+- query: SELECT * FROM Artifact.Test.Helper(Glob=Glob)
+`, `
+name: Test.Helper
+parameters:
+  - name: Glob
+sources:
+- query: SELECT * FROM glob(globs=Glob)
+`}
+
+type ProfileTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *ProfileTestSuite) loadRepository() services.Repository {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	repository := manager.NewRepository()
+	for _, definition := range profileArtifacts {
+		_, err := repository.LoadYaml(definition, true, false)
+		require.NoError(self.T(), err)
+	}
+	return repository
+}
+
+func (self *ProfileTestSuite) TestCompile() {
+	repository := self.loadRepository()
+
+	profile, err := profiles.Compile(self.ConfigObj, repository,
+		profiles.CompileOptions{
+			Name:      "Triage",
+			Artifacts: []string{"Test.Triage"},
+			Parameters: map[string]map[string]string{
+				"Test.Triage": {"Glob": "D:/*"},
+			},
+		})
+	require.NoError(self.T(), err)
+
+	// Dependencies are included.
+	assert.Equal(self.T(), 2, len(profile.Definitions))
+	assert.Equal(self.T(), "Test.Helper", profile.Definitions[0].Name)
+
+	data, err := profile.Marshal()
+	require.NoError(self.T(), err)
+
+	parsed, err := profiles.Parse(data)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{"Test.Triage"}, parsed.Artifacts)
+
+	params_any, _ := parsed.Spec().Get("Test.Triage")
+	params, ok := params_any.(*ordereddict.Dict)
+	require.True(self.T(), ok)
+	glob, _ := params.GetString("Glob")
+	assert.Equal(self.T(), "D:/*", glob)
+
+	// The profile matches the repository it was compiled from.
+	assert.NoError(self.T(), parsed.Verify(self.ConfigObj, repository))
+
+	// Unknown parameters are rejected.
+	_, err = profiles.Compile(self.ConfigObj, repository,
+		profiles.CompileOptions{
+			Artifacts: []string{"Test.Triage"},
+			Parameters: map[string]map[string]string{
+				"Test.Triage": {"Glb": "D:/*"},
+			},
+		})
+	assert.True(self.T(), errors.Is(err, profiles.InvalidProfileError))
+
+	// Edited definitions are detected.
+	tampered := strings.Replace(string(data), "glob(globs=Glob)",
+		"glob(globs='/*')", 1)
+	_, err = profiles.Parse([]byte(tampered))
+	assert.True(self.T(), errors.Is(err, profiles.InvalidProfileError))
+
+	// A server with a different definition can not use the profile.
+	_, err = repository.LoadYaml(`
+name: Test.Helper
+sources:
+- query: SELECT * FROM info()
+`, true, false)
+	require.NoError(self.T(), err)
+	assert.Error(self.T(), parsed.Verify(self.ConfigObj, repository))
+}
+
+func TestProfiles(t *testing.T) {
+	suite.Run(t, &ProfileTestSuite{})
+}
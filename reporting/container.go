@@ -46,6 +46,9 @@ const (
 	ContainerFormatCSV        ContainerFormat = 2
 	ContainerFormatCSVAndJson ContainerFormat = ContainerFormatJson |
 		ContainerFormatCSV
+
+	// Write a single SQLite file instead of a zip.
+	ContainerFormatSQLite ContainerFormat = 4
 )
 
 func GetContainerFormat(format string) (ContainerFormat, error) {
@@ -59,10 +62,13 @@ func GetContainerFormat(format string) (ContainerFormat, error) {
 	case "csv_only":
 		return ContainerFormatCSV, nil
 
+	case "sqlite":
+		return ContainerFormatSQLite, nil
+
 	default:
 	}
 	return 0, fmt.Errorf(
-		"Unknown format parameter %v either 'json', 'jsonl', 'cvs' or 'csv_only'.",
+		"Unknown format parameter %v either 'json', 'jsonl', 'cvs', 'csv_only' or 'sqlite'.",
 		format)
 }
 
@@ -115,6 +121,9 @@ type Container struct {
 	delegate_zip *zip.Writer
 	delegate_fd  io.Writer
 
+	// If set we write into a SQLite file instead of the zip.
+	sqlite *sqliteContainer
+
 	// manage orderly shutdown of the container.
 	mu sync.Mutex
 
@@ -136,6 +145,14 @@ func (self *Container) Create(name string, mtime time.Time) (io.WriteCloser, err
 	name = strings.TrimPrefix(name, "/")
 
 	self.writer_wg.Add(1)
+	if self.sqlite != nil {
+		return &MemberWriter{
+			WriteCloser: self.sqlite.Create(name, mtime),
+			writer_wg:   &self.writer_wg,
+			owner:       self,
+		}, nil
+	}
+
 	header := &concurrent_zip.FileHeader{
 		Name:     name,
 		Method:   concurrent_zip.Deflate,
@@ -188,6 +205,12 @@ func (self *Container) StoreArtifact(
 		return total_rows, nil
 	}
 
+	// Each artifact source gets its own table.
+	if self.sqlite != nil {
+		return self.sqlite.WriteTable(subctx, artifact_name,
+			vql.Eval(subctx, scope))
+	}
+
 	// The name to use in the zip file to store results from this artifact
 	dest := prefix.AddChild(artifact_name).AsClientPath()
 	return self.WriteResultSet(subctx, config_obj, scope, format,
@@ -200,6 +223,11 @@ func (self *Container) WriteResultSet(
 	scope vfilter.Scope, format ContainerFormat,
 	dest string, in <-chan vfilter.Row) (total_rows int, err error) {
 
+	if self.sqlite != nil {
+		table := strings.TrimSuffix(strings.TrimPrefix(dest, "/"), ".json")
+		return self.sqlite.WriteTable(ctx, table, in)
+	}
+
 	var result_set_writer *ContainerResultSetWriter
 
 	// Only create JSON files if required.
@@ -355,6 +383,24 @@ func (self *Container) Upload(
 	scope.Log("Collecting file %s into %s (%v bytes)",
 		formatFilename(filename, accessor), result.StoredName, expected_size)
 
+	if self.sqlite != nil {
+		err = self.sqlite.Upload(ctx, result, accessor,
+			mtime, atime, ctime, btime, reader)
+		if err != nil {
+			return result, err
+		}
+
+		self.mu.Lock()
+		self.uploads = append(self.uploads, result)
+		self.mu.Unlock()
+
+		self.stats_mu.Lock()
+		self.stats.TotalUploadedBytes += result.Size
+		self.stats_mu.Unlock()
+
+		return result, nil
+	}
+
 	// Try to collect sparse files if possible
 	err = self.maybeCollectSparseFile(ctx, scope, reader, result, mtime)
 	if err == nil {
@@ -508,6 +554,56 @@ func (self *Container) maybeCollectSparseFile(
 	return nil
 }
 
+// Copy the members accepted by filter from another zip container
+// (e.g. to resume an interrupted collection).
+func (self *Container) CopyMembers(
+	reader *zip.Reader, filter func(name string) bool) error {
+	for _, member := range reader.File {
+		if !filter(member.Name) {
+			continue
+		}
+
+		err := self.copyMember(member)
+		if err != nil {
+			return fmt.Errorf("Copying %v: %w", member.Name, err)
+		}
+	}
+	return nil
+}
+
+func (self *Container) copyMember(member *zip.File) error {
+	in, err := member.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := self.Create(member.Name, member.ModTime())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// Record uploads which were copied from another container so they
+// are listed in uploads.json.
+func (self *Container) AddUploads(records []*uploads.UploadResponse) {
+	self.mu.Lock()
+	self.uploads = append(self.uploads, records...)
+	self.mu.Unlock()
+
+	self.stats_mu.Lock()
+	for _, record := range records {
+		if record.Type != "idx" {
+			self.stats.TotalUploadedBytes += record.Size
+		}
+	}
+	self.stats_mu.Unlock()
+}
+
 func (self *Container) IsClosed() bool {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -526,6 +622,10 @@ func (self *Container) Close() error {
 	}
 	self.closed = true
 
+	if self.sqlite != nil {
+		return self.closeSqlite()
+	}
+
 	if len(self.uploads) > 0 {
 		result_set_writer, err := NewResultSetWriter(self, "uploads.json")
 		if err == nil {
@@ -571,6 +671,27 @@ func (self *Container) Close() error {
 	return self.fd.Close()
 }
 
+// The uploads table already describes all the uploads so we only
+// need to wait for outstanding members.
+func (self *Container) closeSqlite() error {
+	self.writer_wg.Wait()
+
+	hash, size, err := self.sqlite.Close()
+	if err != nil {
+		return err
+	}
+
+	self.stats_mu.Lock()
+	self.stats.Hash = hash
+	self.stats_mu.Unlock()
+
+	if size > 0 {
+		logger := logging.GetLogger(self.config_obj, &logging.GUIComponent)
+		logger.Info("Container hash %v", hash)
+	}
+	return nil
+}
+
 func (self *Container) increaseUncompressedBytes(len int) {
 	self.stats_mu.Lock()
 	defer self.stats_mu.Unlock()
@@ -586,6 +707,9 @@ func (self *Container) Stats() *api_proto.ContainerStats {
 
 	stats.TotalUploadedFiles = uint64(len(self.uploads))
 	stats.TotalCompressedBytes = uint64(self.writer.Count())
+	if self.sqlite != nil {
+		stats.TotalCompressedBytes = uint64(self.sqlite.Size())
+	}
 	stats.TotalDuration = uint64(Clock.Now().Unix()) - stats.Timestamp
 
 	return stats
@@ -603,6 +727,40 @@ func NewContainer(
 	return NewContainerFromWriter(config_obj, fd, password, level, metadata)
 }
 
+// Create a container which stores the collection in a SQLite file.
+func NewSQLiteContainer(
+	config_obj *config_proto.Config,
+	path string, metadata []vfilter.Row) (*Container, error) {
+	sqlite, err := newSqliteContainer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Container{
+		config_obj: config_obj,
+		sha_sum:    sha256.New(),
+		writer:     utils.NewTee(),
+		sqlite:     sqlite,
+	}
+
+	result.stats.Timestamp = uint64(Clock.Now().Unix())
+
+	if len(metadata) > 0 {
+		rows := make(chan vfilter.Row, len(metadata))
+		for _, row := range metadata {
+			rows <- row
+		}
+		close(rows)
+
+		_, err = sqlite.WriteTable(context.Background(), "metadata", rows)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
 func NewContainerFromWriter(
 	config_obj *config_proto.Config, fd io.WriteCloser,
 	password string, level int64, metadata []vfilter.Row) (*Container, error) {
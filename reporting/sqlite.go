@@ -0,0 +1,457 @@
+package reporting
+
+// A container which writes the collection into a single SQLite file
+// instead of a zip. Each query's results are stored in their own
+// table (named after the artifact source), uploaded files are stored
+// in the uploads and upload_data tables and any other members of the
+// collection (e.g. the collection log) are stored in the files table.
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// Rows are inserted in transactions of this many rows.
+	sqliteBatchSize = 1000
+
+	// Uploaded files are split into chunks of this size in the
+	// upload_data table.
+	sqliteChunkSize = 1024 * 1024
+)
+
+var sqlitePragmas = []string{
+	// Speed up writing - the collection is useless if it is
+	// interrupted anyway.
+	"PRAGMA journal_mode = MEMORY",
+	"PRAGMA synchronous = OFF",
+}
+
+var sqliteSchema = []string{
+	`CREATE TABLE files (name TEXT PRIMARY KEY, mtime INTEGER, data BLOB)`,
+	`CREATE TABLE uploads (
+        stored_name TEXT PRIMARY KEY, vfs_path TEXT, accessor TEXT,
+        type TEXT, file_size INTEGER, uploaded_size INTEGER,
+        sha256 TEXT, md5 TEXT, mtime TEXT, atime TEXT, ctime TEXT,
+        btime TEXT, error TEXT)`,
+	`CREATE TABLE upload_data (stored_name TEXT, offset INTEGER, data BLOB)`,
+	`CREATE INDEX upload_data_idx ON upload_data (stored_name, offset)`,
+}
+
+type sqliteContainer struct {
+	path string
+	db   *sqlx.DB
+
+	// The columns of each result table we created so far (lower
+	// cased as sqlite column names are case insensitive).
+	mu     sync.Mutex
+	tables map[string]map[string]bool
+}
+
+func newSqliteContainer(path string) (*sqliteContainer, error) {
+	return openSqliteDatabase(path, append(sqlitePragmas, sqliteSchema...))
+}
+
+func openSqliteDatabase(
+	path string, schema []string) (*sqliteContainer, error) {
+	// Do not add tables to an existing database.
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	db, err := sqlx.Connect("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Writers from concurrent queries are serialized over a single
+	// connection to avoid locking errors.
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range schema {
+		_, err = db.Exec(stmt)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("Creating sqlite database: %w", err)
+		}
+	}
+
+	return &sqliteContainer{
+		path:   path,
+		db:     db,
+		tables: make(map[string]map[string]bool),
+	}, nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Convert a value into something sqlite can store. Complex values
+// are stored as JSON.
+func sqliteValue(value interface{}) interface{} {
+	switch t := value.(type) {
+	case nil, string, bool, int64, float64:
+		return t
+
+	case uint64:
+		if t > math.MaxInt64 {
+			return strconv.FormatUint(t, 10)
+		}
+		return int64(t)
+
+	case time.Time:
+		return t.UTC().Format(time.RFC3339Nano)
+
+	default:
+		return json.MustMarshalString(t)
+	}
+}
+
+func (self *sqliteContainer) insertRows(
+	table string, rows []*ordereddict.Dict) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	tx, err := self.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var columns, placeholders []string
+		var values []interface{}
+		seen := make(map[string]bool)
+
+		for _, key := range row.Keys() {
+			if seen[strings.ToLower(key)] {
+				continue
+			}
+			seen[strings.ToLower(key)] = true
+
+			value, _ := row.Get(key)
+			columns = append(columns, key)
+			placeholders = append(placeholders, "?")
+			values = append(values, sqliteValue(value))
+		}
+
+		if len(columns) == 0 {
+			continue
+		}
+
+		err = self.ensureColumns(tx, table, columns)
+		if err != nil {
+			_ = tx.Rollback()
+			self.resetTable(table)
+			return err
+		}
+
+		_, err = tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			quoteIdentifier(table), strings.Join(quoteAll(columns), ", "),
+			strings.Join(placeholders, ", ")), values...)
+		if err != nil {
+			_ = tx.Rollback()
+			self.resetTable(table)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Make sure the table exists and has all the columns. Schema changes
+// must happen in the same transaction as the insert since there is
+// only a single connection.
+func (self *sqliteContainer) ensureColumns(
+	tx *sqlx.Tx, table string, columns []string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	existing, pres := self.tables[table]
+	if !pres {
+		_, err := tx.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)",
+			quoteIdentifier(table), strings.Join(quoteAll(columns), ", ")))
+		if err != nil {
+			return err
+		}
+
+		existing = make(map[string]bool)
+		var names []string
+		err = tx.Select(&names, "SELECT name FROM pragma_table_info(?)", table)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			existing[strings.ToLower(name)] = true
+		}
+		self.tables[table] = existing
+	}
+
+	// Later rows may have more columns than the first row.
+	for _, column := range columns {
+		if existing[strings.ToLower(column)] {
+			continue
+		}
+
+		_, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s",
+			quoteIdentifier(table), quoteIdentifier(column)))
+		if err != nil {
+			return err
+		}
+		existing[strings.ToLower(column)] = true
+	}
+
+	return nil
+}
+
+func quoteAll(names []string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, quoteIdentifier(name))
+	}
+	return result
+}
+
+// Forget what we know about the table after a failed transaction
+// since its schema changes were rolled back.
+func (self *sqliteContainer) resetTable(table string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	delete(self.tables, table)
+}
+
+// Write the rows into the table, creating columns as needed.
+func (self *sqliteContainer) WriteTable(
+	ctx context.Context, table string,
+	in <-chan vfilter.Row) (total_rows int, err error) {
+
+	batch := make([]*ordereddict.Dict, 0, sqliteBatchSize)
+	for row := range in {
+		total_rows++
+
+		select {
+		case <-ctx.Done():
+			return total_rows, self.insertRows(table, batch)
+		default:
+		}
+
+		// Normalize the row so all values are simple types.
+		serialized, err := json.Marshal(row)
+		if err != nil {
+			continue
+		}
+
+		dicts, err := utils.ParseJsonToDicts(serialized)
+		if err != nil || len(dicts) != 1 {
+			continue
+		}
+
+		batch = append(batch, dicts[0])
+		if len(batch) >= sqliteBatchSize {
+			err = self.insertRows(table, batch)
+			if err != nil {
+				return total_rows, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	return total_rows, self.insertRows(table, batch)
+}
+
+// Members are buffered in memory and written into the files table
+// when they are closed.
+type sqliteMemberWriter struct {
+	bytes.Buffer
+
+	owner *sqliteContainer
+	name  string
+	mtime time.Time
+}
+
+func (self *sqliteMemberWriter) Close() error {
+	_, err := self.owner.db.Exec(
+		"INSERT OR REPLACE INTO files (name, mtime, data) VALUES (?, ?, ?)",
+		self.name, self.mtime.Unix(), self.Bytes())
+	return err
+}
+
+func (self *sqliteContainer) Create(name string, mtime time.Time) io.WriteCloser {
+	return &sqliteMemberWriter{
+		owner: self,
+		name:  name,
+		mtime: mtime,
+	}
+}
+
+// Uploaded data is split into chunks in the upload_data table.
+type sqliteUploadWriter struct {
+	owner       *sqliteContainer
+	stored_name string
+	offset      int64
+	buffer      []byte
+}
+
+func (self *sqliteUploadWriter) Write(buff []byte) (int, error) {
+	self.buffer = append(self.buffer, buff...)
+	for len(self.buffer) >= sqliteChunkSize {
+		err := self.flush(self.buffer[:sqliteChunkSize])
+		if err != nil {
+			return 0, err
+		}
+		self.buffer = self.buffer[sqliteChunkSize:]
+	}
+	return len(buff), nil
+}
+
+func (self *sqliteUploadWriter) flush(chunk []byte) error {
+	_, err := self.owner.db.Exec(
+		"INSERT INTO upload_data (stored_name, offset, data) VALUES (?, ?, ?)",
+		self.stored_name, self.offset, chunk)
+	self.offset += int64(len(chunk))
+	return err
+}
+
+func (self *sqliteUploadWriter) Close() error {
+	if len(self.buffer) == 0 {
+		return nil
+	}
+	return self.flush(self.buffer)
+}
+
+func formatUploadTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func (self *sqliteContainer) Upload(
+	ctx context.Context,
+	result *uploads.UploadResponse,
+	accessor string,
+	mtime, atime, ctime, btime time.Time,
+	reader io.Reader) error {
+
+	// Replace any previous upload with the same name.
+	_, err := self.db.Exec(
+		"DELETE FROM upload_data WHERE stored_name = ?", result.StoredName)
+	if err != nil {
+		return err
+	}
+
+	writer := &sqliteUploadWriter{
+		owner:       self,
+		stored_name: result.StoredName,
+	}
+
+	sha_sum := sha256.New()
+	md5_sum := md5.New()
+
+	count, err := utils.Copy(ctx, utils.NewTee(writer, sha_sum, md5_sum), reader)
+	if err == nil {
+		err = writer.Close()
+	}
+
+	result.StoredSize = uint64(count)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Sha256 = hex.EncodeToString(sha_sum.Sum(nil))
+		result.Md5 = hex.EncodeToString(md5_sum.Sum(nil))
+	}
+
+	_, err_ := self.db.Exec(`INSERT OR REPLACE INTO uploads (
+        stored_name, vfs_path, accessor, type, file_size, uploaded_size,
+        sha256, md5, mtime, atime, ctime, btime, error)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		result.StoredName, result.Path, accessor, result.Type,
+		int64(result.Size), int64(result.StoredSize),
+		result.Sha256, result.Md5,
+		formatUploadTime(mtime), formatUploadTime(atime),
+		formatUploadTime(ctime), formatUploadTime(btime),
+		result.Error)
+	if err != nil {
+		return err
+	}
+	return err_
+}
+
+// Close the database and return the hash and size of the final
+// file.
+func (self *sqliteContainer) Close() (string, int64, error) {
+	err := self.db.Close()
+	if err != nil {
+		return "", 0, err
+	}
+
+	fd, err := os.Open(self.path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer fd.Close()
+
+	sha_sum := sha256.New()
+	size, err := io.Copy(sha_sum, fd)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(sha_sum.Sum(nil)), size, nil
+}
+
+func (self *sqliteContainer) Size() int64 {
+	stat, err := os.Stat(self.path)
+	if err != nil {
+		return 0
+	}
+	return stat.Size()
+}
+
+// Writes query results into a plain SQLite database without the
+// collection tables (e.g. for sqlite_write()).
+type SQLiteWriter struct {
+	container *sqliteContainer
+}
+
+func NewSQLiteWriter(path string) (*SQLiteWriter, error) {
+	container, err := openSqliteDatabase(path, sqlitePragmas)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteWriter{container: container}, nil
+}
+
+// Write the rows into the table, creating the table and its columns
+// as needed. Returns the number of rows written.
+func (self *SQLiteWriter) WriteTable(
+	ctx context.Context, table string, in <-chan vfilter.Row) (int, error) {
+	return self.container.WriteTable(ctx, table, in)
+}
+
+func (self *SQLiteWriter) Close() error {
+	_, _, err := self.container.Close()
+	return err
+}
@@ -0,0 +1,95 @@
+//+build cgo
+
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/alecthomas/assert"
+	"github.com/jmoiron/sqlx"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/vfilter"
+)
+
+func TestSQLiteContainer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite_container")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collection.sqlite")
+	config_obj := config.GetDefaultConfig()
+	scope := vfilter.NewScope()
+	ctx := context.Background()
+
+	container, err := NewSQLiteContainer(config_obj, path, nil)
+	assert.NoError(t, err)
+
+	// Later rows may add columns.
+	rows := make(chan vfilter.Row, 2)
+	rows <- ordereddict.NewDict().Set("Name", "foo").Set("Size", 10)
+	rows <- ordereddict.NewDict().Set("Name", "bar").
+		Set("Data", ordereddict.NewDict().Set("X", 1))
+	close(rows)
+
+	total, err := container.WriteResultSet(ctx, config_obj, scope,
+		ContainerFormatSQLite, "/results/Test.Artifact/Source.json", rows)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	data := bytes.Repeat([]byte("hello"), sqliteChunkSize/4)
+	result, err := container.Upload(ctx, scope,
+		accessors.MustNewLinuxOSPath("/etc/hello.txt"), "file", nil,
+		int64(len(data)), time.Unix(10, 0), time.Time{}, time.Time{},
+		time.Time{}, bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(data)), result.StoredSize)
+
+	assert.NoError(t, container.WriteJSON("info.json", "hello"))
+	assert.NoError(t, container.Close())
+	assert.NotEqual(t, "", container.Stats().Hash)
+
+	db, err := sqlx.Connect("sqlite3", path)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var names []string
+	assert.NoError(t, db.Select(&names,
+		`SELECT Name FROM "results/Test.Artifact/Source" ORDER BY Name`))
+	assert.Equal(t, []string{"bar", "foo"}, names)
+
+	var encoded string
+	assert.NoError(t, db.Get(&encoded,
+		`SELECT Data FROM "results/Test.Artifact/Source" WHERE Name = 'bar'`))
+	assert.Equal(t, `{"X":1}`, encoded)
+
+	var upload struct {
+		VFSPath string `db:"vfs_path"`
+		Size    int64  `db:"file_size"`
+		Mtime   string `db:"mtime"`
+	}
+	assert.NoError(t, db.Get(&upload,
+		`SELECT vfs_path, file_size, mtime FROM uploads`))
+	assert.Equal(t, "/etc/hello.txt", upload.VFSPath)
+	assert.Equal(t, int64(len(data)), upload.Size)
+	assert.Equal(t, "1970-01-01T00:00:10Z", upload.Mtime)
+
+	// The upload is split into chunks.
+	var chunks [][]byte
+	assert.NoError(t, db.Select(&chunks,
+		`SELECT data FROM upload_data ORDER BY offset`))
+	assert.Equal(t, 2, len(chunks))
+	assert.Equal(t, data, bytes.Join(chunks, nil))
+
+	var info string
+	assert.NoError(t, db.Get(&info,
+		`SELECT data FROM files WHERE name = 'info.json'`))
+	assert.Equal(t, `"hello"`, info)
+}
@@ -73,6 +73,10 @@ type Responder struct {
 	// A context that is shared between all queries from the same
 	// collection.
 	flow_context *FlowContext
+
+	// If set, responses are sent through the scheduler instead of
+	// directly to the output channel.
+	scheduler *Scheduler
 }
 
 // A Responder manages responses for a single query. A collection (or
@@ -122,6 +126,19 @@ func NewResponder(
 	return result
 }
 
+// A responder sending its responses through the scheduler so
+// collections running at the same time share the outbound channel
+// fairly.
+func NewScheduledResponder(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	request *crypto_proto.VeloMessage,
+	scheduler *Scheduler) *Responder {
+	result := NewResponder(ctx, config_obj, request, nil)
+	result.scheduler = scheduler
+	return result
+}
+
 // Flush any outstanding responses.
 func (self *Responder) Close(ctx context.Context) {
 	self.flushLogMessages(ctx)
@@ -133,6 +150,7 @@ func (self *Responder) Copy() *Responder {
 		output:     self.output,
 		logger:     self.logger,
 		start_time: time.Now().UnixNano(),
+		scheduler:  self.scheduler,
 	}
 }
 
@@ -195,6 +213,7 @@ func (self *Responder) AddResponse(
 	ctx context.Context, message *crypto_proto.VeloMessage) {
 	self.Lock()
 	output := self.output
+	scheduler := self.scheduler
 	self.updateStats(message)
 	self.Unlock()
 
@@ -207,6 +226,11 @@ func (self *Responder) AddResponse(
 	}
 	message.TaskId = self.request.TaskId
 
+	if scheduler != nil {
+		scheduler.Send(ctx, message)
+		return
+	}
+
 	if output != nil {
 		select {
 		case <-ctx.Done():
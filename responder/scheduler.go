@@ -0,0 +1,186 @@
+package responder
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+)
+
+// The scheduler shares the client's outbound channel between the
+// collections running on the client.
+
+// Without the scheduler, responses are sent in the order they are
+// produced, so a bulk collection uploading large files fills the
+// outbound queue and delays the responses of every other collection
+// on the host. The scheduler keeps a queue for each collection and
+// sends from the queues using deficit round robin: each collection
+// may send SCHEDULER_QUANTUM bytes in its turn, so collections get
+// an equal share of the bandwidth regardless of the size of their
+// messages. Urgent collections are always sent before other
+// collections.
+
+// Each queue holds a limited number of messages, so a collection
+// producing responses faster than they can be sent only blocks
+// itself.
+
+const (
+	// Bytes each collection may send in its turn.
+	SCHEDULER_QUANTUM = 64 * 1024
+
+	// Messages queued for each collection before it blocks.
+	SCHEDULER_QUEUE_LENGTH = 10
+)
+
+type flowQueue struct {
+	session_id string
+	messages   []*crypto_proto.VeloMessage
+
+	// Bytes the queue may still send in its turn.
+	deficit int
+
+	// Closed when a message is removed from a full queue.
+	space chan bool
+}
+
+type Scheduler struct {
+	mu sync.Mutex
+
+	output chan *crypto_proto.VeloMessage
+
+	// Queues with pending messages by session id.
+	queues map[string]*flowQueue
+
+	// The queues with pending messages in round robin order.
+	urgent []*flowQueue
+	normal []*flowQueue
+
+	// Wakes the dispatcher when a message is queued.
+	wake chan bool
+}
+
+func NewScheduler(ctx context.Context,
+	output chan *crypto_proto.VeloMessage) *Scheduler {
+	result := &Scheduler{
+		output: output,
+		queues: make(map[string]*flowQueue),
+		wake:   make(chan bool, 1),
+	}
+
+	go result.dispatch(ctx)
+
+	return result
+}
+
+// Queue the message for sending. Blocks while the collection's queue
+// is full.
+func (self *Scheduler) Send(
+	ctx context.Context, message *crypto_proto.VeloMessage) {
+	for {
+		self.mu.Lock()
+		queue, pres := self.queues[message.SessionId]
+		if !pres {
+			queue = &flowQueue{
+				session_id: message.SessionId,
+				space:      make(chan bool),
+			}
+			self.queues[message.SessionId] = queue
+
+			if message.Urgent {
+				self.urgent = append(self.urgent, queue)
+			} else {
+				self.normal = append(self.normal, queue)
+			}
+		}
+
+		if len(queue.messages) < SCHEDULER_QUEUE_LENGTH {
+			queue.messages = append(queue.messages, message)
+			self.mu.Unlock()
+
+			select {
+			case self.wake <- true:
+			default:
+			}
+			return
+		}
+
+		space := queue.space
+		self.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-space:
+		}
+	}
+}
+
+// Pick the next message to send or nil if there are none.
+func (self *Scheduler) next() *crypto_proto.VeloMessage {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	var message *crypto_proto.VeloMessage
+	if len(self.urgent) > 0 {
+		message, self.urgent = self.pop(self.urgent)
+	} else if len(self.normal) > 0 {
+		message, self.normal = self.pop(self.normal)
+	}
+	return message
+}
+
+// Take the next message from the active queues using deficit round
+// robin. Returns the message and the remaining active queues.
+func (self *Scheduler) pop(active []*flowQueue) (
+	*crypto_proto.VeloMessage, []*flowQueue) {
+	for {
+		queue := active[0]
+
+		// The queue used up its turn: give it another quantum and
+		// move it to the back.
+		if queue.deficit <= 0 {
+			queue.deficit += SCHEDULER_QUANTUM
+			active = append(active[1:], queue)
+			continue
+		}
+
+		message := queue.messages[0]
+		queue.messages = queue.messages[1:]
+		queue.deficit -= proto.Size(message)
+
+		// Wake senders waiting for room in the queue.
+		if len(queue.messages) == SCHEDULER_QUEUE_LENGTH-1 {
+			close(queue.space)
+			queue.space = make(chan bool)
+		}
+
+		// Idle queues do not keep their turn.
+		if len(queue.messages) == 0 {
+			delete(self.queues, queue.session_id)
+			active = active[1:]
+		}
+
+		return message, active
+	}
+}
+
+func (self *Scheduler) dispatch(ctx context.Context) {
+	for {
+		message := self.next()
+		if message == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-self.wake:
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case self.output <- message:
+		}
+	}
+}
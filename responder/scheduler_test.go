@@ -0,0 +1,119 @@
+package responder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+)
+
+func makeUpload(session_id string, size int) *crypto_proto.VeloMessage {
+	return &crypto_proto.VeloMessage{
+		SessionId: session_id,
+		FileBuffer: &actions_proto.FileBuffer{
+			Data: make([]byte, size),
+		},
+	}
+}
+
+// Queue the messages before the dispatcher runs so the order only
+// depends on the scheduling.
+func newTestScheduler(output chan *crypto_proto.VeloMessage) *Scheduler {
+	return &Scheduler{
+		output: output,
+		queues: make(map[string]*flowQueue),
+		wake:   make(chan bool, 1),
+	}
+}
+
+func drain(scheduler *Scheduler) []string {
+	result := []string{}
+	for {
+		message := scheduler.next()
+		if message == nil {
+			return result
+		}
+		result = append(result, message.SessionId)
+	}
+}
+
+func TestSchedulerFairness(t *testing.T) {
+	ctx := context.Background()
+	scheduler := newTestScheduler(nil)
+
+	// A bulk collection uploads large buffers while another
+	// collection sends small rows.
+	for i := 0; i < 4; i++ {
+		scheduler.Send(ctx, makeUpload("F.Bulk", 2*SCHEDULER_QUANTUM))
+	}
+	for i := 0; i < 8; i++ {
+		scheduler.Send(ctx, makeUpload("F.Small", SCHEDULER_QUANTUM/4))
+	}
+
+	// The small collection sends a quantum worth of rows for each
+	// large upload.
+	assert.Equal(t, []string{
+		"F.Bulk",
+		"F.Small", "F.Small", "F.Small", "F.Small",
+		"F.Small", "F.Small", "F.Small", "F.Small",
+		"F.Bulk", "F.Bulk", "F.Bulk",
+	}, drain(scheduler))
+
+	// Idle queues are removed.
+	assert.Equal(t, 0, len(scheduler.queues))
+}
+
+func TestSchedulerUrgent(t *testing.T) {
+	ctx := context.Background()
+	scheduler := newTestScheduler(nil)
+
+	scheduler.Send(ctx, makeUpload("F.Bulk", 100))
+	scheduler.Send(ctx, makeUpload("F.Bulk", 100))
+
+	urgent := makeUpload("F.Urgent", 100)
+	urgent.Urgent = true
+	scheduler.Send(ctx, urgent)
+
+	assert.Equal(t, []string{"F.Urgent", "F.Bulk", "F.Bulk"},
+		drain(scheduler))
+}
+
+func TestSchedulerBlocksFullQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	output := make(chan *crypto_proto.VeloMessage)
+	scheduler := NewScheduler(ctx, output)
+
+	for i := 0; i < SCHEDULER_QUEUE_LENGTH+1; i++ {
+		scheduler.Send(ctx, makeUpload("F.Bulk", 100))
+	}
+
+	// The dispatcher holds one message and the queue is full so
+	// the bulk collection blocks.
+	sent := make(chan bool)
+	go func() {
+		scheduler.Send(ctx, makeUpload("F.Bulk", 100))
+		close(sent)
+	}()
+
+	// Other collections are not blocked.
+	scheduler.Send(ctx, makeUpload("F.Other", 100))
+
+	select {
+	case <-sent:
+		t.Fatalf("Send should block while the queue is full")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	<-output
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatalf("Send should unblock when the queue has room")
+	}
+}
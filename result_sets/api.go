@@ -32,6 +32,11 @@ type ResultSetWriter interface {
 	// Ensures that results are flushed to storage as soon as the
 	// writer is closed.
 	SetSync()
+
+	// Column types are inferred from rows passed to Write() but
+	// must be provided for rows written with WriteJSONL() (column
+	// name -> type).
+	SetColumnTypes(types map[string]string)
 }
 
 type TimedResultSetWriter interface {
@@ -0,0 +1,59 @@
+package result_sets
+
+import (
+	"io/ioutil"
+
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// The column types of a result set are stored in a small JSON file
+// next to it. The file maps column names to the types inferred
+// while the rows were written (see vql.ColumnTypeTracker).
+func ReadColumnTypes(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec) map[string]string {
+	result := make(map[string]string)
+
+	fd, err := file_store_factory.ReadFile(
+		log_path.SetType(api.PATH_TYPE_FILESTORE_JSON_TYPES))
+	if err != nil {
+		return result
+	}
+	defer fd.Close()
+
+	serialized, err := ioutil.ReadAll(fd)
+	if err != nil {
+		return result
+	}
+
+	_ = json.Unmarshal(serialized, &result)
+	return result
+}
+
+func WriteColumnTypes(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec, types map[string]string) error {
+
+	serialized, err := json.Marshal(types)
+	if err != nil {
+		return err
+	}
+
+	fd, err := file_store_factory.WriteFileWithCompletion(
+		log_path.SetType(api.PATH_TYPE_FILESTORE_JSON_TYPES),
+		utils.SyncCompleter)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return err
+	}
+
+	_, err = fd.Write(serialized)
+	return err
+}
@@ -0,0 +1,62 @@
+package result_sets
+
+import (
+	"io/ioutil"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// The schema version of the rows in a result set is stored in a
+// small JSON file next to it (see RepositoryManager.RecordResultSchema).
+// Result sets written before schemas were tracked have no schema.
+func ReadSchema(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec) (*api_proto.ArtifactSchema, error) {
+	fd, err := file_store_factory.ReadFile(
+		log_path.SetType(api.PATH_TYPE_FILESTORE_JSON_SCHEMA))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	serialized, err := ioutil.ReadAll(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.ArtifactSchema{}
+	err = json.Unmarshal(serialized, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func WriteSchema(
+	file_store_factory api.FileStore,
+	log_path api.FSPathSpec, schema *api_proto.ArtifactSchema) error {
+
+	serialized, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+
+	fd, err := file_store_factory.WriteFileWithCompletion(
+		log_path.SetType(api.PATH_TYPE_FILESTORE_JSON_SCHEMA),
+		utils.SyncCompleter)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return err
+	}
+
+	_, err = fd.Write(serialized)
+	return err
+}
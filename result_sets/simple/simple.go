@@ -31,22 +31,45 @@ import (
 	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/Velocidex/json"
 	"github.com/Velocidex/ordereddict"
+	"github.com/Velocidex/ttlcache/v2"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 	vjson "www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/result_sets"
 	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 )
 
 const (
 	offset_mask = 1<<40 - 1
 )
 
+// Result sets are typically appended to many times (e.g. once per
+// client response) so remember the column types we last stored for
+// each result set instead of reading them back on every Close().
+var column_types_cache = newColumnTypesCache()
+
+func newColumnTypesCache() *ttlcache.Cache {
+	result := ttlcache.NewCache()
+	result.SetCacheSizeLimit(1000)
+	result.SetTTL(10 * time.Minute)
+	return result
+}
+
+// Different orgs use different file stores for the same paths.
+func columnTypesKey(
+	file_store_factory api.FileStore, log_path api.FSPathSpec) string {
+	return fmt.Sprintf("%p:%v", file_store_factory, log_path.AsClientPath())
+}
+
 type ResultSetWriterImpl struct {
 	mu       sync.Mutex
 	rows     [][]byte
@@ -54,12 +77,33 @@ type ResultSetWriterImpl struct {
 	fd       api.FileWriter
 	index_fd api.FileWriter
 
+	// Track the column types so they can be stored with the
+	// result set.
+	file_store_factory api.FileStore
+	log_path           api.FSPathSpec
+	types              *vql_subsystem.ColumnTypeTracker
+	declared_types     map[string]string
+	truncate           result_sets.WriteMode
+
 	sync bool
 }
 
 // Noop for file based result set writers.
 func (self *ResultSetWriterImpl) SetStartRow(i int64) {}
 
+// Declared types take precedence over the types inferred from rows
+// passed to Write() because those rows may have already lost their
+// type by being serialized (e.g. when transforming another result
+// set).
+func (self *ResultSetWriterImpl) SetColumnTypes(types map[string]string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for column, column_type := range types {
+		self.declared_types[column] = column_type
+	}
+}
+
 func (self *ResultSetWriterImpl) SetSync() {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -116,6 +160,8 @@ func (self *ResultSetWriterImpl) Write(row *ordereddict.Dict) {
 		return
 	}
 
+	self.types.Observe(row)
+
 	self.rows = append(self.rows, serialized)
 	if len(self.rows) > 10000 {
 		self._Flush()
@@ -171,6 +217,48 @@ func (self *ResultSetWriterImpl) Close() {
 		self.fd.Flush()
 		self.index_fd.Flush()
 	}
+
+	self.writeColumnTypes()
+}
+
+// Merge the column types we saw with the types already stored and
+// write them back if they changed.
+func (self *ResultSetWriterImpl) writeColumnTypes() {
+	self.mu.Lock()
+	types := self.types.Types()
+	for column, column_type := range self.declared_types {
+		types[column] = column_type
+	}
+	self.mu.Unlock()
+
+	if len(types) == 0 {
+		return
+	}
+
+	key := columnTypesKey(self.file_store_factory, self.log_path)
+	merged := vql_subsystem.NewColumnTypeTracker()
+	existing := make(map[string]string)
+	if !self.truncate {
+		cached, err := column_types_cache.Get(key)
+		if err == nil {
+			existing = cached.(map[string]string)
+		} else {
+			existing = result_sets.ReadColumnTypes(
+				self.file_store_factory, self.log_path)
+		}
+		merged.Merge(existing)
+	}
+	merged.Merge(types)
+
+	new_types := merged.Types()
+	_ = column_types_cache.Set(key, new_types)
+
+	if reflect.DeepEqual(existing, new_types) {
+		return
+	}
+
+	_ = result_sets.WriteColumnTypes(
+		self.file_store_factory, self.log_path, new_types)
 }
 
 type ResultSetFactory struct{}
@@ -182,7 +270,14 @@ func (self ResultSetFactory) NewResultSetWriter(
 	completion func(),
 	truncate result_sets.WriteMode) (result_sets.ResultSetWriter, error) {
 
-	result := &ResultSetWriterImpl{opts: opts}
+	result := &ResultSetWriterImpl{
+		opts:               opts,
+		file_store_factory: file_store_factory,
+		log_path:           log_path,
+		types:              vql_subsystem.NewColumnTypeTracker(),
+		declared_types:     make(map[string]string),
+		truncate:           truncate,
+	}
 
 	// If no path is provided, we are just a log sink
 	if utils.IsNil(log_path) {
@@ -217,6 +312,13 @@ func (self ResultSetFactory) NewResultSetWriter(
 			return nil, err
 		}
 
+		// The old column types and schema no longer apply.
+		_ = column_types_cache.Remove(
+			columnTypesKey(file_store_factory, log_path))
+		_ = file_store_factory.Delete(log_path.
+			SetType(api.PATH_TYPE_FILESTORE_JSON_TYPES))
+		_ = file_store_factory.Delete(log_path.
+			SetType(api.PATH_TYPE_FILESTORE_JSON_SCHEMA))
 	}
 
 	result.fd = fd
@@ -262,6 +262,54 @@ func (self *ResultSetTestSuite) TestResultSetWriterWriteJSONL() {
 	assert.Equal(self.T(), value, int64(3))
 }
 
+func (self *ResultSetTestSuite) TestResultSetColumnTypes() {
+	self.client_id = "C.12314"
+
+	path_manager := paths.NewFlowPathManager(self.client_id, self.flow_id).Log()
+	rs, err := result_sets.NewResultSetWriter(self.file_store, path_manager,
+		json.NoEncOpts, utils.SyncCompleter, result_sets.TruncateMode)
+	assert.NoError(self.T(), err)
+
+	// The first timestamp is earlier but sorts later as a string.
+	rs.Write(ordereddict.NewDict().
+		Set("Time", time.Date(2020, 1, 1, 10, 0, 0, 0,
+			time.FixedZone("X", 5*3600))).
+		Set("Count", 1).
+		Set("Name", "First"))
+	rs.Write(ordereddict.NewDict().
+		Set("Time", time.Date(2020, 1, 1, 6, 0, 0, 0, time.UTC)).
+		Set("Count", 2.5).
+		Set("Name", "Second"))
+	rs.Close()
+
+	assert.Equal(self.T(), map[string]string{
+		"Time":  "timestamp",
+		"Count": "float",
+		"Name":  "",
+	}, result_sets.ReadColumnTypes(self.file_store, path_manager))
+
+	// Sorting uses the time value and keeps the column types.
+	ctx := context.Background()
+	rs_reader, err := result_sets.NewResultSetReaderWithOptions(
+		ctx, self.ConfigObj, self.file_store, path_manager,
+		result_sets.ResultSetOptions{
+			SortColumn: "Time",
+			SortAsc:    true,
+		})
+	assert.NoError(self.T(), err)
+
+	rows := simple.GetAllResults(rs_reader)
+	assert.Equal(self.T(), 2, len(rows))
+
+	name, _ := rows[0].GetString("Name")
+	assert.Equal(self.T(), "First", name)
+	assert.Equal(self.T(), []string{"Time", "Count", "Name"}, rows[0].Keys())
+
+	sorted_path := path_manager.AddUnsafeChild("sorted", "Time", "asc")
+	assert.Equal(self.T(), "timestamp", result_sets.ReadColumnTypes(
+		self.file_store, sorted_path)["Time"])
+}
+
 func TestResultSets(t *testing.T) {
 	suite.Run(t, &ResultSetTestSuite{})
 }
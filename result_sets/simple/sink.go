@@ -7,9 +7,10 @@ type NullResultSetWriter struct{}
 func (self NullResultSetWriter) WriteJSONL(
 	serialized []byte, total_rows uint64) {
 }
-func (self NullResultSetWriter) SetStartRow(int64)           {}
-func (self NullResultSetWriter) Write(row *ordereddict.Dict) {}
-func (self NullResultSetWriter) Flush()                      {}
-func (self NullResultSetWriter) Close()                      {}
-func (self NullResultSetWriter) SetCompletion(f func())      {}
-func (self NullResultSetWriter) SetSync()                    {}
+func (self NullResultSetWriter) SetStartRow(int64)                {}
+func (self NullResultSetWriter) Write(row *ordereddict.Dict)      {}
+func (self NullResultSetWriter) Flush()                           {}
+func (self NullResultSetWriter) Close()                           {}
+func (self NullResultSetWriter) SetCompletion(f func())           {}
+func (self NullResultSetWriter) SetSync()                         {}
+func (self NullResultSetWriter) SetColumnTypes(map[string]string) {}
@@ -3,6 +3,7 @@ package simple
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/Velocidex/ordereddict"
@@ -81,6 +82,11 @@ func (self ResultSetFactory) getFilteredReader(
 		return nil, err
 	}
 
+	// The rows we read have lost their types so carry the types
+	// over from the original table.
+	writer.SetColumnTypes(
+		result_sets.ReadColumnTypes(file_store_factory, log_path))
+
 	sub_ctx, sub_cancel := context.WithTimeout(ctx, getExpiry(config_obj))
 	defer sub_cancel()
 
@@ -182,10 +188,26 @@ func (self ResultSetFactory) getSortedReader(
 		return nil, err
 	}
 
+	column_types := result_sets.ReadColumnTypes(file_store_factory, log_path)
+	writer.SetColumnTypes(column_types)
+
+	// Timestamps are stored as strings which do not always sort
+	// correctly (e.g. different timezones or precision) so sort
+	// them by their epoch value instead.
+	sort_column := options.SortColumn
+	sort_by_time := column_types[sort_column] == vql_subsystem.COLUMN_TYPE_TIMESTAMP
+	if sort_by_time {
+		sort_column = TIME_SORT_COLUMN
+	}
+
 	sorter_input_chan := make(chan vfilter.Row)
-	sorted_chan := sorter.MergeSorter{10000}.Sort(
+	tmpdir, chunk_size := sorter.GetSpillOptions(config_obj)
+	sorted_chan := sorter.MergeSorter{
+		ChunkSize: chunk_size,
+		TempDir:   tmpdir,
+	}.Sort(
 		ctx, scope, sorter_input_chan,
-		options.SortColumn, options.SortAsc)
+		sort_column, options.SortAsc)
 
 	sub_ctx, sub_cancel := context.WithTimeout(ctx, getExpiry(config_obj))
 	defer sub_cancel()
@@ -204,6 +226,10 @@ func (self ResultSetFactory) getSortedReader(
 				if !ok {
 					return
 				}
+				if sort_by_time {
+					value, _ := row.Get(options.SortColumn)
+					row.Set(TIME_SORT_COLUMN, timeSortKey(value))
+				}
 				sorter_input_chan <- row
 			}
 		}
@@ -212,6 +238,9 @@ func (self ResultSetFactory) getSortedReader(
 	for row := range sorted_chan {
 		row_dict, ok := row.(*ordereddict.Dict)
 		if ok {
+			if sort_by_time {
+				row_dict.Delete(TIME_SORT_COLUMN)
+			}
 			writer.Write(row_dict)
 		}
 	}
@@ -222,6 +251,33 @@ func (self ResultSetFactory) getSortedReader(
 	return self.NewResultSetReader(file_store_factory, transformed_path)
 }
 
+// A hidden column used to sort timestamp columns.
+const TIME_SORT_COLUMN = "_TimeSortKey"
+
+// Returns the epoch time in nanoseconds of the timestamp. Values
+// which are not timestamps sort first.
+func timeSortKey(value interface{}) int64 {
+	switch t := value.(type) {
+	case time.Time:
+		return t.UnixNano()
+
+	case string:
+		ts, err := time.Parse(time.RFC3339Nano, t)
+		if err == nil {
+			return ts.UnixNano()
+		}
+
+	default:
+		// Timestamps may also be stored as epoch seconds.
+		epoch, ok := utils.ToInt64(value)
+		if ok {
+			return epoch * 1000000000
+		}
+	}
+
+	return math.MinInt64
+}
+
 func getExpiry(config_obj *config_proto.Config) time.Duration {
 	// Default is 10 min to filter the file.
 	if config_obj.Defaults != nil &&
@@ -0,0 +1,261 @@
+package server
+
+// Track client connections by client version and OS. During staged
+// upgrades this makes it easy to spot client builds which have
+// trouble talking to the frontend.
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"www.velocidex.com/golang/velociraptor/crypto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+const (
+	// Versions and OS are reported by the clients so we bound the
+	// number of label values we export.
+	max_label_sets = 100
+
+	other_label = "other"
+)
+
+var (
+	clientVersionRegex = regexp.MustCompile(
+		`^[0-9]{1,4}\.[0-9]{1,4}(\.[0-9]{1,4})?(-[a-z0-9]{1,10})?$`)
+
+	knownOS = map[string]bool{
+		"windows": true,
+		"linux":   true,
+		"darwin":  true,
+		"freebsd": true,
+	}
+
+	clientConnectionsGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "frontend_client_connections",
+			Help: "Number of currently connected clients by version and OS.",
+		},
+		[]string{"version", "os"},
+	)
+
+	clientRequestCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "frontend_client_requests",
+			Help: "Number of POST requests received by client version and OS.",
+		},
+		[]string{"version", "os"},
+	)
+
+	clientErrorCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "frontend_client_errors",
+			Help: "Number of failed client requests by client version and OS.",
+		},
+		[]string{"version", "os"},
+	)
+
+	clientLatencyHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "frontend_client_poll_latency",
+			Help:    "Latency to process client data in seconds by client version and OS.",
+			Buckets: prometheus.LinearBuckets(0.1, 1, 10),
+		},
+		[]string{"version", "os"},
+	)
+
+	clientMetrics = newClientMetricsTracker()
+)
+
+type ClientVersionStats struct {
+	Version        string  `json:"version"`
+	OS             string  `json:"os"`
+	Connections    int64   `json:"connections"`
+	Requests       uint64  `json:"requests"`
+	Errors         uint64  `json:"errors"`
+	ErrorRate      float64 `json:"error_rate"`
+	AverageLatency float64 `json:"average_latency"`
+
+	total_latency time.Duration
+}
+
+type clientMetricsKey struct {
+	version, os string
+}
+
+// Prometheus metrics can not be read back so we keep our own
+// totals for the summary.
+type clientMetricsTracker struct {
+	mu    sync.Mutex
+	stats map[clientMetricsKey]*ClientVersionStats
+}
+
+func newClientMetricsTracker() *clientMetricsTracker {
+	return &clientMetricsTracker{
+		stats: make(map[clientMetricsKey]*ClientVersionStats),
+	}
+}
+
+// Returns the labels to use for the version and OS. Once we track
+// max_label_sets combinations, new ones are counted as other.
+func (self *clientMetricsTracker) labels(version, os string) (string, string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	key := clientMetricsKey{version: version, os: os}
+	_, pres := self.stats[key]
+	if pres || len(self.stats) < max_label_sets {
+		return version, os
+	}
+	return other_label, other_label
+}
+
+func (self *clientMetricsTracker) get(version, os string) *ClientVersionStats {
+	key := clientMetricsKey{version: version, os: os}
+	stats, pres := self.stats[key]
+	if !pres {
+		stats = &ClientVersionStats{Version: version, OS: os}
+		self.stats[key] = stats
+	}
+	return stats
+}
+
+// Record a connected client. The returned function must be called
+// when the client disconnects.
+func (self *clientMetricsTracker) Connect(version, os string) func() {
+	version, os = self.labels(version, os)
+	clientConnectionsGauge.WithLabelValues(version, os).Inc()
+
+	self.mu.Lock()
+	self.get(version, os).Connections++
+	self.mu.Unlock()
+
+	return func() {
+		clientConnectionsGauge.WithLabelValues(version, os).Dec()
+
+		self.mu.Lock()
+		self.get(version, os).Connections--
+		self.mu.Unlock()
+	}
+}
+
+// Record a request from the client and how long it took to process.
+func (self *clientMetricsTracker) Request(
+	version, os string, latency time.Duration, err error) {
+	version, os = self.labels(version, os)
+	clientRequestCounter.WithLabelValues(version, os).Inc()
+	clientLatencyHistogram.WithLabelValues(version, os).
+		Observe(latency.Seconds())
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	stats := self.get(version, os)
+	stats.Requests++
+	stats.total_latency += latency
+	if err != nil {
+		clientErrorCounter.WithLabelValues(version, os).Inc()
+		stats.Errors++
+	}
+}
+
+// Record an error which is not related to a request.
+func (self *clientMetricsTracker) Error(version, os string) {
+	version, os = self.labels(version, os)
+	clientErrorCounter.WithLabelValues(version, os).Inc()
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.get(version, os).Errors++
+}
+
+func (self *clientMetricsTracker) Summary() []*ClientVersionStats {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := make([]*ClientVersionStats, 0, len(self.stats))
+	for _, stats := range self.stats {
+		item := *stats
+		if item.Requests > 0 {
+			item.ErrorRate = float64(item.Errors) / float64(item.Requests)
+			item.AverageLatency = item.total_latency.Seconds() /
+				float64(item.Requests)
+		}
+		result = append(result, &item)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Version != result[j].Version {
+			return result[i].Version < result[j].Version
+		}
+		return result[i].OS < result[j].OS
+	})
+
+	return result
+}
+
+// A summary of the connection metrics of this frontend.
+func GetClientVersionStats() []*ClientVersionStats {
+	return clientMetrics.Summary()
+}
+
+// Serves the summary as JSON on the monitoring port.
+func ClientMetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(json.MustMarshalIndent(GetClientVersionStats()))
+	})
+}
+
+func clientInfoLabels(client_info *services.ClientInfo) (string, string) {
+	version := client_info.ClientVersion
+	if version == "" {
+		version = "unknown"
+	} else if !clientVersionRegex.MatchString(version) {
+		version = other_label
+	}
+
+	os := client_info.System
+	if os == "" {
+		os = "unknown"
+	} else if !knownOS[os] {
+		os = other_label
+	}
+
+	return version, os
+}
+
+// Find the version and OS of the client which sent the message. The
+// client info is normally cached so this is quick.
+func clientLabels(
+	ctx context.Context, message_info *crypto.MessageInfo) (string, string) {
+	org_manager, err := services.GetOrgManager()
+	if err != nil {
+		return "unknown", "unknown"
+	}
+
+	org_config_obj, err := org_manager.GetOrgConfig(message_info.OrgId)
+	if err != nil {
+		return "unknown", "unknown"
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(org_config_obj)
+	if err != nil {
+		return "unknown", "unknown"
+	}
+
+	client_info, err := client_info_manager.Get(ctx, message_info.Source)
+	if err != nil {
+		return "unknown", "unknown"
+	}
+
+	return clientInfoLabels(client_info)
+}
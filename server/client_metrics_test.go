@@ -0,0 +1,71 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+func TestClientMetricsSummary(t *testing.T) {
+	tracker := newClientMetricsTracker()
+
+	disconnect := tracker.Connect("0.6.8", "windows")
+	tracker.Connect("0.6.8", "windows")
+	tracker.Connect("0.6.9", "linux")
+	disconnect()
+
+	tracker.Request("0.6.8", "windows", time.Second, nil)
+	tracker.Request("0.6.8", "windows", 3*time.Second, errors.New("failed"))
+	tracker.Error("0.6.9", "linux")
+
+	summary := tracker.Summary()
+	assert.Equal(t, 2, len(summary))
+
+	assert.Equal(t, "0.6.8", summary[0].Version)
+	assert.Equal(t, "windows", summary[0].OS)
+	assert.Equal(t, int64(1), summary[0].Connections)
+	assert.Equal(t, uint64(2), summary[0].Requests)
+	assert.Equal(t, uint64(1), summary[0].Errors)
+	assert.Equal(t, 0.5, summary[0].ErrorRate)
+	assert.Equal(t, 2.0, summary[0].AverageLatency)
+
+	// Errors outside of requests do not have a rate.
+	assert.Equal(t, "0.6.9", summary[1].Version)
+	assert.Equal(t, uint64(1), summary[1].Errors)
+	assert.Equal(t, 0.0, summary[1].ErrorRate)
+}
+
+func TestClientMetricsCardinality(t *testing.T) {
+	version, os := clientInfoLabels(&services.ClientInfo{
+		ClientInfo: actions_proto.ClientInfo{
+			ClientVersion: "0.6.8-rc1",
+			System:        "windows",
+		}})
+	assert.Equal(t, "0.6.8-rc1", version)
+	assert.Equal(t, "windows", os)
+
+	// Arbitrary values reported by the client are not exported.
+	version, os = clientInfoLabels(&services.ClientInfo{
+		ClientInfo: actions_proto.ClientInfo{
+			ClientVersion: "0.6.8 <script>",
+			System:        "plan9",
+		}})
+	assert.Equal(t, "other", version)
+	assert.Equal(t, "other", os)
+
+	// The number of label combinations is bounded.
+	tracker := newClientMetricsTracker()
+	for i := 0; i < max_label_sets+10; i++ {
+		tracker.Error(fmt.Sprintf("0.6.%d", i), "linux")
+	}
+
+	summary := tracker.Summary()
+	assert.Equal(t, max_label_sets+1, len(summary))
+	assert.Equal(t, "other", summary[len(summary)-1].Version)
+	assert.Equal(t, uint64(10), summary[len(summary)-1].Errors)
+}
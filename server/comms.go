@@ -30,6 +30,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"www.velocidex.com/golang/velociraptor/crashes"
 	"www.velocidex.com/golang/velociraptor/crypto"
 	"www.velocidex.com/golang/velociraptor/file_store"
 	"www.velocidex.com/golang/velociraptor/file_store/api"
@@ -134,6 +135,17 @@ func PrepareFrontendMux(
 	router.Handle(base+"/healthz", healthz(server_obj))
 	router.Handle(base+"/server.pem", server_pem(config_obj))
 
+	// Used by clients to measure their link quality.
+	router.Handle(base+"/speedtest", speedtest(config_obj))
+
+	// Canary tokens are triggered by fetching this URL.
+	router.Handle(base+"/canary/", http.StripPrefix(base+"/canary/",
+		canary(config_obj)))
+
+	// Clients upload crash reports from previous runs here.
+	router.Handle(base+"/crash_report", RecordHTTPStats(
+		crash_report(config_obj, server_obj)))
+
 	// DEPRECATED: These are the old handler names - not great
 	// but here for backwards compatibility.
 	router.Handle(base+"/control", RecordHTTPStats(control(config_obj, server_obj)))
@@ -176,6 +188,92 @@ func server_pem(config_obj *config_proto.Config) http.Handler {
 	})
 }
 
+func canary(config_obj *config_proto.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never reveal to the caller that they hit a canary.
+		defer w.WriteHeader(http.StatusNotFound)
+
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			return
+		}
+
+		org_config_obj, err := org_manager.GetOrgConfig(
+			r.URL.Query().Get("org"))
+		if err != nil {
+			return
+		}
+
+		canary_manager, err := services.GetCanaryManager(org_config_obj)
+		if err != nil {
+			return
+		}
+
+		details := ordereddict.NewDict().
+			Set("Method", r.Method).
+			Set("UserAgent", r.UserAgent()).
+			Set("Referer", r.Referer()).
+			Set("ForwardedFor", r.Header.Get("X-Forwarded-For"))
+
+		err = canary_manager.TriggerToken(r.Context(), r.URL.Path,
+			r.RemoteAddr, details)
+		if err != nil {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Debug("canary: %v from %v: %v", r.URL.Path, r.RemoteAddr, err)
+		}
+	})
+}
+
+// Receives crash reports from clients. Reports are encrypted just
+// like regular client messages so we only accept them from
+// authenticated clients.
+func crash_report(
+	config_obj *config_proto.Config, server_obj *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		message_info, err := readWithLimits(
+			req.Context(), config_obj, server_obj, req)
+		if err != nil || !message_info.Authenticated {
+			http.Error(w, "", http.StatusForbidden)
+			return
+		}
+
+		org_manager, err := services.GetOrgManager()
+		if err != nil {
+			http.Error(w, "", http.StatusServiceUnavailable)
+			return
+		}
+
+		err = message_info.IterateJobs(req.Context(), config_obj,
+			func(ctx context.Context, msg *crypto_proto.VeloMessage) {
+				if msg.CrashReport == nil {
+					return
+				}
+
+				org_config_obj, err := org_manager.GetOrgConfig(msg.OrgId)
+				if err != nil {
+					return
+				}
+
+				err = crashes.StoreCrashReport(
+					org_config_obj, msg.Source, msg.CrashReport)
+				if err != nil {
+					server_obj.Error("crash_report: %v", err)
+				}
+			})
+		if err != nil {
+			http.Error(w, "", http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
 // Redirect client to another active frontend.
 /* Experimental code disabled for now.
 func maybeRedirectFrontend(handler string, w http.ResponseWriter, r *http.Request) bool {
@@ -321,6 +419,7 @@ func control(
 		}
 
 		// Measure the latency from this point on.
+		start := time.Now()
 		timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
 			concurrencyHistorgram.Observe(v)
 		}))
@@ -386,6 +485,8 @@ func control(
 		w.WriteHeader(http.StatusOK)
 		flusher.Flush()
 
+		client_version, client_os := clientLabels(req.Context(), message_info)
+
 		sync := make(chan []byte)
 		go func() {
 			defer close(sync)
@@ -401,6 +502,8 @@ func control(
 			response, _, err := server_obj.Process(subctx, message_info,
 				false, // drain_requests_for_client
 			)
+			clientMetrics.Request(client_version, client_os,
+				time.Since(start), err)
 			if err != nil {
 				server_obj.Error("Error: %v", err)
 				return
@@ -525,7 +628,7 @@ func reader(server_obj *Server) http.Handler {
 		// can still verify the comms as authenticated. NOTE: this
 		// check should be very quick since it is just a lookup in the
 		// client info manager's LRU.
-		_, err = client_info_manager.Get(ctx, source)
+		client_info, err := client_info_manager.Get(ctx, source)
 		if err != nil {
 			journal, err := services.GetJournal(org_config_obj)
 			if err != nil {
@@ -554,8 +657,11 @@ func reader(server_obj *Server) http.Handler {
 			return
 		}
 
+		client_version, client_os := clientInfoLabels(client_info)
+
 		// Check for conflicting clients
 		if notifier.IsClientDirectlyConnected(source) {
+			clientMetrics.Error(client_version, client_os)
 
 			// Send a message that there is a client conflict.
 			journal, err := services.GetJournal(org_config_obj)
@@ -575,6 +681,8 @@ func reader(server_obj *Server) http.Handler {
 		notification, cancel := notifier.ListenForNotification(source)
 		defer cancel()
 
+		defer clientMetrics.Connect(client_version, client_os)()
+
 		// Deadlines are designed to ensure that connections
 		// are not blocked for too long (maybe several
 		// minutes). This helps to expire connections when the
@@ -600,6 +708,7 @@ func reader(server_obj *Server) http.Handler {
 			true, // drain_requests_for_client
 		)
 		if err != nil {
+			clientMetrics.Error(client_version, client_os)
 			server_obj.Error("Error: %v", err)
 			return
 		}
@@ -634,6 +743,7 @@ func reader(server_obj *Server) http.Handler {
 					true, // drain_requests_for_client
 				)
 				if err != nil {
+					clientMetrics.Error(client_version, client_os)
 					server_obj.Error("Error: %v", err)
 					return
 				}
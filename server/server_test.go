@@ -30,6 +30,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/paths/artifacts"
 	"www.velocidex.com/golang/velociraptor/server"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
 	"www.velocidex.com/golang/velociraptor/vtesting/assert"
 
@@ -268,6 +269,85 @@ func (self *ServerTestSuite) TestForeman() {
 	assert.Equal(t, stats.LastHuntTimestamp, hunt.StartTime)
 }
 
+// Hunts collecting heavy artifacts are deferred while the client
+// reports a high load and scheduled once the load drops.
+func (self *ServerTestSuite) TestForemanDefersHeavyHunts() {
+	t := self.T()
+	self.ConfigObj.Defaults.HuntLoadThreshold = 50
+	defer func() {
+		self.ConfigObj.Defaults.HuntLoadThreshold = 0
+	}()
+
+	now := time.Now()
+	closer := utils.MockTime(&utils.MockClock{MockNow: now})
+	defer closer()
+
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(t, err)
+
+	repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	require.NoError(t, err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Heavy
+resources:
+  heavy: true
+sources:
+- query: SELECT * FROM info()
+`, true, true)
+	require.NoError(t, err)
+
+	hunt_dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	require.NoError(t, err)
+
+	_, err = hunt_dispatcher.CreateHunt(
+		context.Background(), self.ConfigObj,
+		acl_managers.NullACLManager{},
+		&api_proto.Hunt{
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: api.MakeCollectorRequest(
+				self.client_id, "Test.Heavy"),
+		})
+	require.NoError(t, err)
+
+	runner := flows.NewFlowRunner(self.ConfigObj)
+	defer runner.Close(context.Background())
+
+	checkin := func(load uint64) *services.Stats {
+		runner.ProcessSingleMessage(context.Background(),
+			&crypto_proto.VeloMessage{
+				Source:    self.client_id,
+				SessionId: constants.FOREMAN_WELL_KNOWN_FLOW,
+				ForemanCheckin: &actions_proto.ForemanCheckin{
+					Load: load,
+				},
+			})
+
+		runner.ProcessMessages(context.Background(), &crypto.MessageInfo{
+			Source: self.client_id,
+		})
+
+		client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+		require.NoError(t, err)
+
+		stats, err := client_info_manager.GetStats(
+			context.Background(), self.client_id)
+		require.NoError(t, err)
+		return stats
+	}
+
+	// The client is busy so the hunt is not scheduled.
+	stats := checkin(90)
+	assert.Equal(t, uint64(90), stats.Load)
+	assert.True(t, stats.LastHuntTimestamp < hunt_dispatcher.GetLastTimestamp())
+
+	// Once the load drops the hunt is scheduled.
+	closer = utils.MockTime(&utils.MockClock{MockNow: now.Add(time.Second)})
+	stats = checkin(10)
+	assert.Equal(t, uint64(10), stats.Load)
+	assert.Equal(t, hunt_dispatcher.GetLastTimestamp(), stats.LastHuntTimestamp)
+}
+
 func (self *ServerTestSuite) RequiredFilestoreContains(
 	filename file_store_api.FSPathSpec, regex string) {
 
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ttlcache/v2"
+	"golang.org/x/time/rate"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// The speedtest endpoint is reachable before the client
+// authenticates, so each source may only make a few requests per
+// second and the bytes transferred by all sources are limited.
+type speedtestLimiter struct {
+	mu sync.Mutex
+
+	// Limits the total bytes transferred.
+	bandwidth *rate.Limiter
+
+	// Request limiters keyed by source address.
+	sources *ttlcache.Cache
+}
+
+func newSpeedtestLimiter() *speedtestLimiter {
+	result := &speedtestLimiter{
+		bandwidth: rate.NewLimiter(
+			rate.Limit(constants.SPEEDTEST_BYTES_PER_SECOND),
+			constants.MAX_SPEEDTEST_SIZE),
+		sources: ttlcache.NewCache(),
+	}
+	result.sources.SetCacheSizeLimit(10000)
+	result.sources.SetTTL(time.Minute)
+	return result
+}
+
+// Allow a request from the source to transfer size bytes.
+func (self *speedtestLimiter) Allow(source string, size int64) bool {
+	self.mu.Lock()
+	var limiter *rate.Limiter
+	cached, err := self.sources.Get(source)
+	if err == nil {
+		limiter = cached.(*rate.Limiter)
+	} else {
+		// A probe makes a handful of requests in quick succession.
+		limiter = rate.NewLimiter(rate.Limit(1), 10)
+		_ = self.sources.Set(source, limiter)
+	}
+	self.mu.Unlock()
+
+	if !limiter.Allow() {
+		return false
+	}
+
+	return self.bandwidth.AllowN(time.Now(), int(size))
+}
+
+func speedtestSource(config_obj *config_proto.Config, r *http.Request) string {
+	addr := utils.RemoteAddr(r, config_obj.Frontend.GetProxyHeader())
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// Clients download (GET) or upload (POST) a payload of the
+// requested size to measure the latency and bandwidth of their link
+// to the frontend. The size is capped and requests are rate limited
+// so the handler can not be abused.
+func speedtest(config_obj *config_proto.Config) http.Handler {
+	payload := make([]byte, 64*1024)
+	_, _ = rand.Read(payload)
+
+	limiter := newSpeedtestLimiter()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		source := speedtestSource(config_obj, r)
+
+		switch r.Method {
+		case http.MethodGet:
+			size, _ := strconv.ParseInt(r.URL.Query().Get("size"), 10, 64)
+			if size < 0 || size > constants.MAX_SPEEDTEST_SIZE {
+				size = constants.MAX_SPEEDTEST_SIZE
+			}
+
+			if !limiter.Allow(source, size) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", size))
+			w.WriteHeader(http.StatusOK)
+
+			for size > 0 {
+				to_write := int64(len(payload))
+				if to_write > size {
+					to_write = size
+				}
+				_, err := w.Write(payload[:to_write])
+				if err != nil {
+					return
+				}
+				size -= to_write
+			}
+
+		case http.MethodPost:
+			size := r.ContentLength
+			if size < 0 || size > constants.MAX_SPEEDTEST_SIZE {
+				size = constants.MAX_SPEEDTEST_SIZE
+			}
+
+			if !limiter.Allow(source, size) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(r.Body, size))
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -163,6 +163,18 @@ func CheckAccessWithToken(
 	case acls.DATASTORE_ACCESS:
 		return token.DatastoreAccess, nil
 
+	case acls.RESPONSE_KILL_PROCESS:
+		return token.ResponseKillProcess, nil
+
+	case acls.RESPONSE_DELETE_FILE:
+		return token.ResponseDeleteFile, nil
+
+	case acls.RESPONSE_BLOCK_HASH:
+		return token.ResponseBlockHash, nil
+
+	case acls.RESPONSE_DISABLE_USER:
+		return token.ResponseDisableUser, nil
+
 	}
 
 	return false, nil
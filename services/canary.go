@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+const (
+	CANARY_TYPE_HTTP = "http"
+	CANARY_TYPE_DNS  = "dns"
+)
+
+func GetCanaryManager(config_obj *config_proto.Config) (CanaryManager, error) {
+	org_manager, err := GetOrgManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return org_manager.Services(config_obj.OrgId).CanaryManager()
+}
+
+// The canary manager issues canary tokens (URLs or hostnames) which
+// may be planted on endpoints (e.g. in documents or config
+// files). Legitimate users have no reason to access the tokens, so
+// when a token is triggered the manager records it and emits an
+// event on the Server.Internal.CanaryTriggered queue. Alert routing
+// is done by server event artifacts watching this queue.
+type CanaryManager interface {
+	// Issue a new token of the specified type.
+	CreateToken(ctx context.Context,
+		principal, token_type, memo, client_id string) (
+		*api_proto.CanaryToken, error)
+
+	GetToken(ctx context.Context,
+		token_id string) (*api_proto.CanaryToken, error)
+
+	ListTokens(ctx context.Context) ([]*api_proto.CanaryToken, error)
+
+	DeleteToken(ctx context.Context, token_id string) error
+
+	// Record that the token was accessed. The source describes who
+	// accessed it (e.g. the remote address) and details carries any
+	// additional context (e.g. the request headers).
+	TriggerToken(ctx context.Context,
+		token_id, source string, details *ordereddict.Dict) error
+}
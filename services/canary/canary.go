@@ -0,0 +1,255 @@
+// Canary tokens integrate deception into collection workflows.
+
+// A canary token is a unique URL or hostname which is planted on an
+// endpoint (e.g. inside a decoy document, a fake credentials file or
+// a browser bookmark). Nothing legitimate should ever access it, so
+// when the token is fetched or resolved the server records the
+// trigger and forwards it to the Server.Internal.CanaryTriggered
+// event queue where alerting artifacts can pick it up.
+
+package canary
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	tokenNotFoundError = errors.New("Canary token not found")
+)
+
+// Token ids are used as DNS labels so they must be lower case
+// alphanumeric.
+func NewTokenId() string {
+	buf := make([]byte, 10)
+	_, _ = rand.Read(buf)
+
+	return "ct" + strings.ToLower(base32.HexEncoding.EncodeToString(buf))
+}
+
+type CanaryManager struct {
+	// Serializes updates to the token records.
+	mu sync.Mutex
+
+	config_obj *config_proto.Config
+
+	Clock utils.Clock
+}
+
+func (self *CanaryManager) CreateToken(ctx context.Context,
+	principal, token_type, memo, client_id string) (
+	*api_proto.CanaryToken, error) {
+
+	record := &api_proto.CanaryToken{
+		TokenId:   NewTokenId(),
+		Type:      token_type,
+		Memo:      memo,
+		ClientId:  client_id,
+		Principal: principal,
+		Created:   uint64(self.Clock.Now().Unix()),
+	}
+
+	switch token_type {
+	case services.CANARY_TYPE_HTTP:
+		if self.config_obj.Client == nil ||
+			len(self.config_obj.Client.ServerUrls) == 0 {
+			return nil, errors.New("No server URLs configured!")
+		}
+
+		record.Url = self.config_obj.Client.ServerUrls[0] +
+			"canary/" + record.TokenId
+
+		// The frontend needs to know which org the token belongs to.
+		if !utils.IsRootOrg(self.config_obj.OrgId) {
+			record.Url += "?org=" + self.config_obj.OrgId
+		}
+
+	case services.CANARY_TYPE_DNS:
+		if self.config_obj.Defaults == nil ||
+			self.config_obj.Defaults.CanaryDnsDomain == "" {
+			return nil, errors.New(
+				"DNS canary tokens require Defaults.canary_dns_domain")
+		}
+
+		record.Hostname = record.TokenId + "." +
+			strings.TrimPrefix(self.config_obj.Defaults.CanaryDnsDomain, ".")
+
+	default:
+		return nil, fmt.Errorf("Unsupported canary token type %v", token_type)
+	}
+
+	err := self.persist(record)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Info("<green>CanaryManager</>: %v created %v canary token %v: %v",
+		principal, token_type, record.TokenId, memo)
+
+	return record, nil
+}
+
+func (self *CanaryManager) persist(record *api_proto.CanaryToken) error {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSubject(self.config_obj,
+		paths.NewCanaryPathManager(record.TokenId).Path(), record)
+}
+
+func (self *CanaryManager) GetToken(ctx context.Context,
+	token_id string) (*api_proto.CanaryToken, error) {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &api_proto.CanaryToken{}
+	err = db.GetSubject(self.config_obj,
+		paths.NewCanaryPathManager(token_id).Path(), record)
+	if errors.Is(err, os.ErrNotExist) || record.TokenId == "" {
+		return nil, tokenNotFoundError
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (self *CanaryManager) ListTokens(
+	ctx context.Context) ([]*api_proto.CanaryToken, error) {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(self.config_obj,
+		paths.NewCanaryPathManager("").Directory())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*api_proto.CanaryToken, 0, len(children))
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		record, err := self.GetToken(ctx, child.Base())
+		if err != nil {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Created > result[j].Created
+	})
+
+	return result, nil
+}
+
+func (self *CanaryManager) DeleteToken(
+	ctx context.Context, token_id string) error {
+	_, err := self.GetToken(ctx, token_id)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubject(self.config_obj,
+		paths.NewCanaryPathManager(token_id).Path())
+}
+
+func (self *CanaryManager) TriggerToken(ctx context.Context,
+	token_id, source string, details *ordereddict.Dict) error {
+
+	// DNS tokens may be reported with the full hostname.
+	token_id = strings.ToLower(strings.SplitN(token_id, ".", 2)[0])
+
+	self.mu.Lock()
+	record, err := self.GetToken(ctx, token_id)
+	if err != nil {
+		self.mu.Unlock()
+		return err
+	}
+
+	record.TriggerCount++
+	record.LastTriggered = uint64(self.Clock.Now().Unix())
+	record.LastSource = source
+	err = self.persist(record)
+	self.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Warn("<red>CanaryManager</>: Canary token %v (%v) triggered by %v",
+		record.TokenId, record.Memo, source)
+
+	if details == nil {
+		details = ordereddict.NewDict()
+	}
+
+	journal, err := services.GetJournal(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	// Alerting artifacts watch this queue to route the alert.
+	journal.PushRowsToArtifactAsync(self.config_obj,
+		ordereddict.NewDict().
+			Set("TokenId", record.TokenId).
+			Set("Type", record.Type).
+			Set("Memo", record.Memo).
+			Set("ClientId", record.ClientId).
+			Set("Source", source).
+			Set("TriggerCount", record.TriggerCount).
+			Set("Details", details),
+		"Server.Internal.CanaryTriggered")
+
+	return nil
+}
+
+func NewCanaryManager(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) (services.CanaryManager, error) {
+
+	result := &CanaryManager{
+		config_obj: config_obj,
+		Clock:      &utils.RealClock{},
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Canary Manager for %v",
+		services.GetOrgName(config_obj))
+
+	return result, nil
+}
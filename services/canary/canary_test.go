@@ -0,0 +1,108 @@
+package canary_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+type CanaryTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *CanaryTestSuite) SetupTest() {
+	self.ConfigObj = self.LoadConfig()
+	self.ConfigObj.Frontend.ServerServices.CanaryManager = true
+
+	self.TestSuite.SetupTest()
+}
+
+func (self *CanaryTestSuite) TestCanaryTokens() {
+	var mu sync.Mutex
+	alerts := []*ordereddict.Dict{}
+
+	err := journal.WatchQueueWithCB(self.Sm.Ctx, self.ConfigObj, self.Sm.Wg,
+		"Server.Internal.CanaryTriggered", "CanaryTestSuite", func(
+			ctx context.Context,
+			config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			mu.Lock()
+			defer mu.Unlock()
+			alerts = append(alerts, row)
+			return nil
+		})
+	require.NoError(self.T(), err)
+
+	canary_manager, err := services.GetCanaryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	// HTTP tokens are served from the frontend.
+	token, err := canary_manager.CreateToken(self.Ctx, "admin",
+		services.CANARY_TYPE_HTTP, "Decoy document", "C.1234")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(),
+		"https://localhost:8000/canary/"+token.TokenId, token.Url)
+
+	// DNS tokens need a canary domain.
+	_, err = canary_manager.CreateToken(self.Ctx, "admin",
+		services.CANARY_TYPE_DNS, "Fake credentials", "C.1234")
+	assert.Error(self.T(), err)
+
+	self.ConfigObj.Defaults.CanaryDnsDomain = "canary.example.com"
+	dns_token, err := canary_manager.CreateToken(self.Ctx, "admin",
+		services.CANARY_TYPE_DNS, "Fake credentials", "C.1234")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), dns_token.TokenId+".canary.example.com",
+		dns_token.Hostname)
+
+	tokens, err := canary_manager.ListTokens(self.Ctx)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(tokens))
+
+	// Trigger the tokens - DNS tokens may be reported by hostname.
+	err = canary_manager.TriggerToken(self.Ctx, token.TokenId,
+		"10.0.0.1:1234", nil)
+	require.NoError(self.T(), err)
+
+	err = canary_manager.TriggerToken(self.Ctx, dns_token.Hostname,
+		"8.8.8.8", ordereddict.NewDict().Set("QueryType", "A"))
+	require.NoError(self.T(), err)
+
+	// Unknown tokens are rejected.
+	err = canary_manager.TriggerToken(self.Ctx, "ctnosuchtoken", "", nil)
+	assert.Error(self.T(), err)
+
+	triggered, err := canary_manager.GetToken(self.Ctx, token.TokenId)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), triggered.TriggerCount)
+	assert.Equal(self.T(), "10.0.0.1:1234", triggered.LastSource)
+
+	// Both triggers are routed to the alert queue.
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(alerts) == 2
+	})
+
+	err = canary_manager.DeleteToken(self.Ctx, token.TokenId)
+	require.NoError(self.T(), err)
+
+	tokens, err = canary_manager.ListTokens(self.Ctx)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(tokens))
+}
+
+func TestCanaryManager(t *testing.T) {
+	suite.Run(t, &CanaryTestSuite{})
+}
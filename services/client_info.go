@@ -26,6 +26,12 @@ type Stats struct {
 	LastHuntTimestamp     uint64 `json:"LastHuntTimestamp,omitempty"`
 	LastEventTableVersion uint64 `json:"LastEventTableVersion,omitempty"`
 	IpAddress             string `json:"IpAddress,omitempty"`
+
+	// The load the client reported when it last checked in and
+	// when (in microseconds). This is only kept in memory on the
+	// frontend the client is connected to.
+	Load     uint64 `json:"Load,omitempty"`
+	LoadTime uint64 `json:"LoadTime,omitempty"`
 }
 
 func GetClientInfoManager(config_obj *config_proto.Config) (ClientInfoManager, error) {
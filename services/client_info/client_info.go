@@ -15,6 +15,11 @@
    - LastEventTableVersion - the version of the client event table the
      client currently has.
 
+   - Load - the CPU load the client reported when it last checked
+     in. The hunt dispatcher uses it to defer heavy hunts. This is
+     only needed on the frontend the client is connected to so it is
+     not synced or written to storage.
+
    While client stats are needed on both the master and minion nodes
    our goal is to minimize IO to the filestore.
 
@@ -87,6 +92,10 @@ type CachedInfo struct {
 	last_flush uint64
 
 	is_master bool
+
+	// The last reported load and when it was reported.
+	load      uint64
+	load_time uint64
 }
 
 func (self *CachedInfo) SetHasTasks(status TASKS_AVAILABLE_STATUS) {
@@ -113,6 +122,8 @@ func (self *CachedInfo) GetStats() *services.Stats {
 		IpAddress:             self.record.IpAddress,
 		LastHuntTimestamp:     self.record.LastHuntTimestamp,
 		LastEventTableVersion: self.record.LastEventTableVersion,
+		Load:                  self.load,
+		LoadTime:              self.load_time,
 	}
 }
 
@@ -163,6 +174,12 @@ func (self *CachedInfo) _UpdateStats(
 		}
 		self.record.LastEventTableVersion = stats.LastEventTableVersion
 	}
+
+	// The load is not flushed so does not make the record dirty.
+	if stats.LoadTime > self.load_time {
+		self.load = stats.Load
+		self.load_time = stats.LoadTime
+	}
 }
 
 // Write ping record to data store if it is dirty.
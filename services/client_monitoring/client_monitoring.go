@@ -23,21 +23,32 @@ import (
 
 	"github.com/Velocidex/ordereddict"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/proto"
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/constants"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/snapshots"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
 )
 
+var (
+	signEventTableErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "client_event_table_sign_errors",
+		Help: "Number of client event tables sent without a signature.",
+	})
+)
+
 type ClientEventTable struct {
 	mu sync.Mutex
 
@@ -140,7 +151,17 @@ func (self *ClientEventTable) SetClientMonitoringState(
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
-	return self.setClientMonitoringState(ctx, config_obj, principal, state)
+	err := self.setClientMonitoringState(ctx, config_obj, principal, state)
+	if err != nil {
+		return err
+	}
+
+	// Keep a history of the table without the compiled VQL which
+	// can be rebuilt from the artifacts.
+	snapshot := proto.Clone(self.state).(*flows_proto.ClientEventTable)
+	clear_caches(snapshot)
+
+	return snapshots.Record(config_obj, paths.ClientMonitoringHistory, snapshot)
 }
 
 func (self *ClientEventTable) compileArtifactCollectorArgs(
@@ -333,6 +354,16 @@ func (self *ClientEventTable) GetClientUpdateEventTableMessage(
 		event.Timeout = 99999999
 	}
 
+	// Sign the table so the client can trust the copy it caches
+	// when it starts before it reaches the server.
+	err := crypto_utils.SignEventTable(config_obj, result)
+	if err != nil {
+		signEventTableErrors.Inc()
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("GetClientUpdateEventTableMessage: Sending "+
+			"unsigned event table to %v: %v", client_id, err)
+	}
+
 	return &crypto_proto.VeloMessage{
 		UpdateEventTable: result,
 		SessionId:        constants.MONITORING_WELL_KNOWN_FLOW,
@@ -468,6 +499,14 @@ func NewClientMonitoringService(
 	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
 	logger.Info("<green>Starting</> Client Monitoring Service for %v",
 		services.GetOrgName(config_obj))
+
+	// Clients can not detect tampering with cached tables which are
+	// not signed so make it obvious early if we can not sign them.
+	err := crypto_utils.SignEventTable(config_obj, &actions_proto.VQLEventTable{})
+	if err != nil {
+		logger.Error("<red>Client event tables will not be signed</>: %v", err)
+	}
+
 	journal, err := services.GetJournal(config_obj)
 	if err != nil {
 		return nil, err
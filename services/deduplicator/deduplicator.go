@@ -0,0 +1,151 @@
+/*
+  The upload deduplicator stores identical uploads once.
+
+  When Datastore.dedup_uploads is set, the service watches for
+  completed collections and deduplicates their uploads (see the
+  file_store/dedup package). Uploads which are still queued in the
+  filestore's write cache when the collection completes may be
+  missed - these are picked up by the `velociraptor filestore dedup`
+  maintenance command.
+*/
+
+package deduplicator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/dedup"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	// Give the filestore time to flush the uploads to disk.
+	processingDelay = 10 * time.Second
+	queueSize       = 1000
+)
+
+type pendingFlow struct {
+	client_id, flow_id string
+	due                time.Time
+}
+
+type UploadDeduplicator struct {
+	config_obj *config_proto.Config
+	options    dedup.Options
+	queue      chan *pendingFlow
+
+	Clock utils.Clock
+}
+
+func (self *UploadDeduplicator) ProcessFlowCompletion(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	row *ordereddict.Dict) error {
+
+	flow := &flows_proto.ArtifactCollectorContext{}
+	flow_any, _ := row.Get("Flow")
+	err := utils.ParseIntoProtobuf(flow_any, flow)
+	if err != nil {
+		return err
+	}
+
+	if flow.TotalUploadedFiles == 0 {
+		return nil
+	}
+
+	client_id, _ := row.GetString("ClientId")
+	pending := &pendingFlow{
+		client_id: client_id,
+		flow_id:   flow.SessionId,
+		due:       self.Clock.Now().Add(processingDelay),
+	}
+
+	// Never block the journal - the maintenance command can catch
+	// up on anything we drop.
+	select {
+	case self.queue <- pending:
+	default:
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Debug("UploadDeduplicator: Queue full, skipping %v/%v",
+			client_id, flow.SessionId)
+	}
+
+	return nil
+}
+
+func (self *UploadDeduplicator) processQueue(ctx context.Context) {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case pending := <-self.queue:
+			delay := pending.due.Sub(self.Clock.Now())
+			if delay > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-self.Clock.After(delay):
+				}
+			}
+
+			report, err := dedup.DedupFlow(ctx, self.config_obj,
+				pending.client_id, pending.flow_id, self.options)
+			if err != nil {
+				logger.Error("UploadDeduplicator: %v", err)
+				continue
+			}
+
+			if report.Deduplicated > 0 {
+				logger.Info("UploadDeduplicator: Deduplicated %v uploads "+
+					"(%v bytes) in %v/%v", report.Deduplicated,
+					report.BytesSaved, pending.client_id, pending.flow_id)
+			}
+
+			for _, e := range report.Errors {
+				logger.Error("UploadDeduplicator: %v", e)
+			}
+		}
+	}
+}
+
+func NewUploadDeduplicator(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if config_obj.Datastore == nil || !config_obj.Datastore.DedupUploads {
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Upload Deduplicator for %v.",
+		services.GetOrgName(config_obj))
+
+	self := &UploadDeduplicator{
+		config_obj: config_obj,
+		options:    dedup.DefaultOptions(config_obj),
+		queue:      make(chan *pendingFlow, queueSize),
+		Clock:      &utils.RealClock{},
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		self.processQueue(ctx)
+	}()
+
+	return journal.WatchQueueWithCB(ctx, config_obj, wg,
+		"System.Flow.Completion", "UploadDeduplicator",
+		self.ProcessFlowCompletion)
+}
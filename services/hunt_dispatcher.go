@@ -55,6 +55,37 @@ const (
 	HuntFlushToDatastoreAsync
 )
 
+// The state of a client in a hunt participation report.
+const (
+	HUNT_CLIENT_SCHEDULED  = "SCHEDULED"
+	HUNT_CLIENT_COMPLETED  = "COMPLETED"
+	HUNT_CLIENT_ERROR      = "ERROR"
+	HUNT_CLIENT_NEVER_SEEN = "NEVER_SEEN"
+)
+
+// The participation of a single client in a hunt.
+type HuntParticipant struct {
+	ClientId string
+	Hostname string
+	FlowId   string
+
+	// One of the HUNT_CLIENT_* states above.
+	State string
+
+	// Times in seconds since epoch (0 if not known).
+	ScheduledTime int64
+	CompletedTime int64
+	LastSeen      int64
+
+	Error string
+}
+
+// Stragglers are clients which did not (yet) complete the hunt
+// successfully.
+func (self *HuntParticipant) IsStraggler() bool {
+	return self.State != HUNT_CLIENT_COMPLETED
+}
+
 type IHuntDispatcher interface {
 	// Applies the function on all the hunts. Functions may not
 	// modify the hunt but will have read only access to the hunt
@@ -98,6 +129,13 @@ type IHuntDispatcher interface {
 		config_obj *config_proto.Config,
 		in *api_proto.ListHuntsRequest) (*api_proto.ListHuntsResponse, error)
 
+	// Report on all the clients targeted by the hunt: which clients
+	// were scheduled, completed or errored and which clients match
+	// the hunt condition but never picked up the hunt.
+	GetParticipation(ctx context.Context,
+		config_obj *config_proto.Config,
+		hunt_id string) ([]*HuntParticipant, error)
+
 	// Send a mutation to a hunt object.
 	MutateHunt(config_obj *config_proto.Config,
 		mutation *api_proto.HuntMutation) error
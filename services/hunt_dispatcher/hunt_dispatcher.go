@@ -67,6 +67,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/snapshots"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 )
@@ -525,6 +526,14 @@ func (self *HuntDispatcher) CreateHunt(
 		return "", err
 	}
 
+	staged := limitStagedHunt(config_obj, manager, hunt)
+	if len(staged) > 0 {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Info("CreateHunt: Hunt %v collects staged artifacts %v - "+
+			"limiting it to clients labeled %v", hunt.HuntId, staged,
+			hunt.Condition.StagingLabels.Label)
+	}
+
 	// Compile the start request and store it in the hunt. We will
 	// use this compiled version to launch all other flows from
 	// this hunt rather than re-compile the artifact each
@@ -581,6 +590,11 @@ func (self *HuntDispatcher) CreateHunt(
 		return "", err
 	}
 
+	err = snapshots.RecordHunt(config_obj, hunt)
+	if err != nil {
+		return "", err
+	}
+
 	// Trigger a refresh of the hunt dispatcher. This guarantees
 	// that fresh data will be read in subsequent ListHunt()
 	// calls.
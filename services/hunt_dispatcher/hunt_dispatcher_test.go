@@ -6,7 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Velocidex/ordereddict"
 	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
@@ -168,6 +170,79 @@ func (self *HuntDispatcherTestSuite) TestModifyingHuntPropagateChanges() {
 	})
 }
 
+func (self *HuntDispatcherTestSuite) TestGetParticipation() {
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	indexer, err := services.GetIndexer(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	for i := 1; i <= 4; i++ {
+		client_id := fmt.Sprintf("C.%d", i)
+		assert.NoError(self.T(), indexer.SetIndex(client_id, "all"))
+		assert.NoError(self.T(), db.SetSubject(self.ConfigObj,
+			paths.NewClientPathManager(client_id).Path(),
+			&actions_proto.ClientInfo{
+				ClientId: client_id,
+				Hostname: "Host" + client_id,
+			}))
+	}
+
+	journal, err := services.GetJournal(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// C.3 was rescheduled after its first flow failed.
+	hunt_path_manager := paths.NewHuntPathManager("H.1")
+	err = journal.AppendToResultSet(self.ConfigObj, hunt_path_manager.Clients(),
+		[]*ordereddict.Dict{
+			ordereddict.NewDict().Set("ClientId", "C.1").
+				Set("FlowId", "F.1").Set("Timestamp", 100),
+			ordereddict.NewDict().Set("ClientId", "C.2").
+				Set("FlowId", "F.2").Set("Timestamp", 100),
+			ordereddict.NewDict().Set("ClientId", "C.3").
+				Set("FlowId", "F.3").Set("Timestamp", 100),
+			ordereddict.NewDict().Set("ClientId", "C.3").
+				Set("FlowId", "F.4").Set("Timestamp", 200),
+		})
+	assert.NoError(self.T(), err)
+
+	err = journal.AppendToResultSet(self.ConfigObj, hunt_path_manager.ClientErrors(),
+		[]*ordereddict.Dict{
+			ordereddict.NewDict().Set("ClientId", "C.1").
+				Set("FlowId", "F.1").Set("EndTime", time.Unix(150, 0)).
+				Set("Status", "FINISHED"),
+			ordereddict.NewDict().Set("ClientId", "C.2").
+				Set("FlowId", "F.2").Set("EndTime", time.Unix(160, 0)).
+				Set("Status", "ERROR").Set("Error", "Timeout"),
+			ordereddict.NewDict().Set("ClientId", "C.3").
+				Set("FlowId", "F.3").Set("EndTime", time.Unix(170, 0)).
+				Set("Status", "ERROR").Set("Error", "Timeout"),
+		})
+	assert.NoError(self.T(), err)
+
+	participants, err := self.master_dispatcher.GetParticipation(
+		self.Ctx, self.ConfigObj, "H.1")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 4, len(participants))
+
+	assert.Equal(self.T(), services.HUNT_CLIENT_COMPLETED, participants[0].State)
+	assert.Equal(self.T(), int64(150), participants[0].CompletedTime)
+	assert.False(self.T(), participants[0].IsStraggler())
+
+	assert.Equal(self.T(), services.HUNT_CLIENT_ERROR, participants[1].State)
+	assert.Equal(self.T(), "Timeout", participants[1].Error)
+
+	// The stale error from the first flow is ignored.
+	assert.Equal(self.T(), services.HUNT_CLIENT_SCHEDULED, participants[2].State)
+	assert.Equal(self.T(), "F.4", participants[2].FlowId)
+	assert.Equal(self.T(), int64(200), participants[2].ScheduledTime)
+
+	// C.4 matches the hunt but was never scheduled.
+	assert.Equal(self.T(), services.HUNT_CLIENT_NEVER_SEEN, participants[3].State)
+	assert.Equal(self.T(), "HostC.4", participants[3].Hostname)
+	assert.True(self.T(), participants[3].IsStraggler())
+}
+
 func (self *HuntDispatcherTestSuite) getAllHunts() []*api_proto.Hunt {
 	// Get the list of all hunts
 	hunts := []*api_proto.Hunt{}
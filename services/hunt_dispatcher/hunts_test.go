@@ -105,6 +105,72 @@ sources:
 		[]string{"TestArtifact_Arg1", "AnotherTestArtifact_Arg1"})
 }
 
+func (self *HuntTestSuite) TestStagedArtifactHunt() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	repository.LoadYaml(`
+name: System.Hunt.Creation
+type: SERVER_EVENT`, true, true)
+
+	_, err = manager.SetArtifactFile(self.ConfigObj, "admin", `
+name: Custom.Staged
+sources:
+- query: SELECT * FROM info()
+`, "")
+	assert.NoError(self.T(), err)
+
+	_, err = manager.StageArtifact(
+		self.ConfigObj, "admin", "Custom.Staged", "Canary", 3600)
+	assert.NoError(self.T(), err)
+
+	hunt_dispatcher, err := services.GetHuntDispatcher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	create := func() *api_proto.Hunt {
+		request := &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Custom.Staged"},
+			},
+			Condition: &api_proto.HuntCondition{
+				UnionField: &api_proto.HuntCondition_Os{
+					Os: &api_proto.HuntOsCondition{
+						Os: api_proto.HuntOsCondition_WINDOWS,
+					},
+				},
+				ExcludedLabels: &api_proto.HuntLabelCondition{
+					Label: []string{"Excluded"},
+				},
+			},
+		}
+		_, err := hunt_dispatcher.CreateHunt(
+			self.Ctx, self.ConfigObj, acl_managers.NullACLManager{}, request)
+		assert.NoError(self.T(), err)
+		return request
+	}
+
+	// While staged the hunt only targets the canary clients but
+	// keeps its own condition.
+	hunt_obj := create()
+	assert.Equal(self.T(), []string{"Canary"},
+		hunt_obj.Condition.StagingLabels.Label)
+	assert.Equal(self.T(), api_proto.HuntOsCondition_WINDOWS,
+		hunt_obj.Condition.GetOs().Os)
+	assert.Equal(self.T(), []string{"Excluded"},
+		hunt_obj.Condition.ExcludedLabels.Label)
+
+	// Once promoted the hunt condition is kept.
+	err = manager.PromoteArtifact(self.ConfigObj, "admin", "Custom.Staged")
+	assert.NoError(self.T(), err)
+
+	hunt_obj = create()
+	assert.Nil(self.T(), hunt_obj.Condition.StagingLabels)
+	assert.NotNil(self.T(), hunt_obj.Condition.GetOs())
+}
+
 func TestHunts(t *testing.T) {
 	suite.Run(t, &HuntTestSuite{})
 }
@@ -0,0 +1,166 @@
+package hunt_dispatcher
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
+)
+
+// Build the participation report from the hunt's result sets: The
+// clients result set records each client the hunt was scheduled on
+// and the client errors result set records each completed
+// collection. Clients that match the hunt conditions but do not
+// appear in either were never seen by the hunt.
+func (self *HuntDispatcher) GetParticipation(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string) ([]*services.HuntParticipant, error) {
+
+	hunt_obj, pres := self.GetHunt(hunt_id)
+	if !pres {
+		return nil, fmt.Errorf("Hunt %v not known", hunt_id)
+	}
+
+	participants := make(map[string]*services.HuntParticipant)
+	path_manager := paths.NewHuntPathManager(hunt_id)
+
+	// A client may be rescheduled so the latest flow wins.
+	err := readHuntResultSet(ctx, config_obj, path_manager.Clients(),
+		func(row *ordereddict.Dict) {
+			client_id, _ := row.GetString("ClientId")
+			flow_id, _ := row.GetString("FlowId")
+			timestamp, _ := row.GetInt64("Timestamp")
+
+			participants[client_id] = &services.HuntParticipant{
+				ClientId:      client_id,
+				FlowId:        flow_id,
+				State:         services.HUNT_CLIENT_SCHEDULED,
+				ScheduledTime: timestamp,
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readHuntResultSet(ctx, config_obj, path_manager.ClientErrors(),
+		func(row *ordereddict.Dict) {
+			client_id, _ := row.GetString("ClientId")
+			flow_id, _ := row.GetString("FlowId")
+
+			// Ignore completions of flows that were since
+			// rescheduled.
+			participant, pres := participants[client_id]
+			if !pres || participant.FlowId != flow_id {
+				return
+			}
+
+			participant.State = services.HUNT_CLIENT_COMPLETED
+			status, _ := row.GetString("Status")
+			if status == "ERROR" {
+				participant.State = services.HUNT_CLIENT_ERROR
+				participant.Error, _ = row.GetString("Error")
+			}
+
+			end_time, _ := row.Get("EndTime")
+			participant.CompletedTime = getTimestamp(end_time)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients that were not scheduled at all only count once the
+	// hunt was started.
+	if hunt_obj.StartTime > 0 {
+		indexer, err := services.GetIndexer(config_obj)
+		if err != nil {
+			return nil, err
+		}
+
+		for hit := range indexer.SearchIndexWithPrefix(ctx, config_obj, "all") {
+			_, pres := participants[hit.Entity]
+			if pres {
+				continue
+			}
+
+			client_info, err := client_info_manager.Get(ctx, hit.Entity)
+			if err != nil {
+				continue
+			}
+
+			if hunt_manager.HuntMatchesClient(
+				ctx, config_obj, hunt_obj, client_info) {
+				participants[hit.Entity] = &services.HuntParticipant{
+					ClientId: hit.Entity,
+					State:    services.HUNT_CLIENT_NEVER_SEEN,
+				}
+			}
+		}
+	}
+
+	result := make([]*services.HuntParticipant, 0, len(participants))
+	for _, participant := range participants {
+		client_info, err := client_info_manager.Get(ctx, participant.ClientId)
+		if err == nil {
+			participant.Hostname = client_info.Hostname
+
+			// Ping is in microseconds.
+			participant.LastSeen = int64(client_info.Ping / 1000000)
+		}
+		result = append(result, participant)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ClientId < result[j].ClientId
+	})
+
+	return result, nil
+}
+
+func readHuntResultSet(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	path api.FSPathSpec, cb func(row *ordereddict.Dict)) error {
+	file_store_factory := file_store.GetFileStore(config_obj)
+	rs_reader, err := result_sets.NewResultSetReader(
+		file_store_factory, path)
+	if err != nil {
+		return err
+	}
+	defer rs_reader.Close()
+
+	for row := range rs_reader.Rows(ctx) {
+		cb(row)
+	}
+	return nil
+}
+
+// Times are written as time.Time but read back from the result set
+// as strings.
+func getTimestamp(value interface{}) int64 {
+	switch t := value.(type) {
+	case time.Time:
+		return t.Unix()
+
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err == nil {
+			return parsed.Unix()
+		}
+	}
+	return 0
+}
@@ -1,8 +1,10 @@
 package hunt_dispatcher
 
 import (
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 func GetArtifactSources(
@@ -25,3 +27,35 @@ func GetArtifactSources(
 	}
 	return result
 }
+
+// Hunts collecting staged artifacts only target the canary clients
+// carrying the staging label. The rest of the hunt condition still
+// applies. Returns the staged artifacts.
+func limitStagedHunt(config_obj *config_proto.Config,
+	manager services.RepositoryManager, hunt *api_proto.Hunt) []string {
+	staged := []string{}
+	labels := []string{}
+	for _, artifact := range hunt.StartRequest.Artifacts {
+		record, err := manager.GetArtifactStaging(config_obj, artifact)
+		if err != nil {
+			continue
+		}
+		staged = append(staged, artifact)
+		if !utils.InString(labels, record.Label) {
+			labels = append(labels, record.Label)
+		}
+	}
+
+	if len(staged) == 0 {
+		return nil
+	}
+
+	if hunt.Condition == nil {
+		hunt.Condition = &api_proto.HuntCondition{}
+	}
+	hunt.Condition.StagingLabels = &api_proto.HuntLabelCondition{
+		Label: labels,
+	}
+
+	return staged
+}
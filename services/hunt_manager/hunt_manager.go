@@ -65,6 +65,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/snapshots"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
 	"www.velocidex.com/golang/vfilter"
@@ -85,6 +86,10 @@ type ParticipationRecord struct {
 	Timestamp uint64 `vfilter:"optional,field=Timestamp"`
 	TS        uint64 `vfilter:"optional,field=_ts"`
 
+	// Schedule the hunt again even if it already ran on the client
+	// (e.g. to retry a failed collection).
+	Reschedule bool `vfilter:"optional,field=Reschedule"`
+
 	// Deprecated
 	Participate bool `vfilter:"optional,field=Participate"`
 }
@@ -176,7 +181,7 @@ func (self *HuntManager) processMutation(
 		return err
 	}
 
-	dispatcher.ModifyHuntObject(mutation.HuntId,
+	modification := dispatcher.ModifyHuntObject(mutation.HuntId,
 		func(hunt_obj *api_proto.Hunt) services.HuntModificationAction {
 			modification := services.HuntUnmodified
 
@@ -253,6 +258,16 @@ func (self *HuntManager) processMutation(
 
 			return modification
 		})
+
+	// Only changes to the hunt's state are kept in the history,
+	// not the frequent updates to its stats.
+	if modification == services.HuntPropagateChanges ||
+		modification == services.HuntTriggerParticipation {
+		hunt_obj, pres := dispatcher.GetHunt(mutation.HuntId)
+		if pres {
+			return snapshots.RecordHunt(config_obj, hunt_obj)
+		}
+	}
 	return nil
 }
 
@@ -423,7 +438,8 @@ func (self *HuntManager) ProcessLabelChange(
 		// include label conditions.
 		func(hunt *api_proto.Hunt) bool {
 			return hunt.Condition != nil &&
-				hunt.Condition.GetLabels() != nil
+				(hunt.Condition.GetLabels() != nil ||
+					hunt.Condition.StagingLabels != nil)
 		})
 }
 
@@ -491,7 +507,7 @@ func (self *HuntManager) ProcessParticipation(
 	// frontends.
 	err = checkHuntRanOnClient(config_obj, participation_row.ClientId,
 		participation_row.HuntId)
-	if err != nil {
+	if err != nil && !participation_row.Reschedule {
 		return nil
 		return fmt.Errorf("hunt_manager: %v already ran on client %v",
 			participation_row.HuntId, participation_row.ClientId)
@@ -509,7 +525,7 @@ func (self *HuntManager) ProcessParticipation(
 	}
 
 	// The event may override the regular hunt logic.
-	if participation_row.Override {
+	if participation_row.Override || participation_row.Reschedule {
 		return scheduleHuntOnClient(ctx, config_obj,
 			hunt_obj, participation_row.ClientId)
 	}
@@ -585,23 +601,37 @@ func huntHasLabel(
 	ctx context.Context,
 	config_obj *config_proto.Config,
 	hunt_obj *api_proto.Hunt, client_id string) bool {
-	labeler := services.GetLabeler(config_obj)
-
 	if hunt_obj.Condition == nil {
 		return true
 	}
 
+	// Hunts collecting staged artifacts only run on the canary
+	// clients.
+	staging_labels := hunt_obj.Condition.StagingLabels
+	if staging_labels != nil && !hasAnyLabel(ctx, config_obj,
+		client_id, staging_labels.Label) {
+		return false
+	}
+
 	label_condition := hunt_obj.Condition.GetLabels()
-	if label_condition == nil {
-		return huntHasExcludeLabel(ctx, config_obj, hunt_obj, client_id)
+	if label_condition != nil && !hasAnyLabel(ctx, config_obj,
+		client_id, label_condition.Label) {
+		return false
 	}
 
-	for _, label := range label_condition.Label {
+	return huntHasExcludeLabel(ctx, config_obj, hunt_obj, client_id)
+}
+
+func hasAnyLabel(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id string, labels []string) bool {
+	labeler := services.GetLabeler(config_obj)
+	for _, label := range labels {
 		if labeler.IsLabelSet(ctx, config_obj, client_id, label) {
-			return huntHasExcludeLabel(ctx, config_obj, hunt_obj, client_id)
+			return true
 		}
 	}
-
 	return false
 }
 
@@ -629,6 +659,15 @@ func huntHasExcludeLabel(
 	return true
 }
 
+// Check if the hunt's conditions select the client.
+func HuntMatchesClient(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_obj *api_proto.Hunt, client_info *services.ClientInfo) bool {
+	return huntMatchesOS(hunt_obj, client_info) &&
+		huntHasLabel(ctx, config_obj, hunt_obj, client_info.ClientId)
+}
+
 func huntMatchesOS(hunt_obj *api_proto.Hunt, client_info *services.ClientInfo) bool {
 	if hunt_obj.Condition == nil {
 		return true
@@ -787,6 +787,43 @@ func (self *HuntTestSuite) TestHuntManagerErrors() {
 	})
 }
 
+// Hunts collecting staged artifacts only match canary clients
+// which also satisfy the rest of the condition.
+func (self *HuntTestSuite) TestHuntMatchesStagingLabels() {
+	hunt_obj := &api_proto.Hunt{
+		Condition: &api_proto.HuntCondition{
+			UnionField: &api_proto.HuntCondition_Os{
+				Os: &api_proto.HuntOsCondition{
+					Os: api_proto.HuntOsCondition_WINDOWS,
+				},
+			},
+			StagingLabels: &api_proto.HuntLabelCondition{
+				Label: []string{"Canary"},
+			},
+		},
+	}
+
+	client_info := &services.ClientInfo{}
+	client_info.ClientId = self.client_id
+	client_info.System = "windows"
+
+	assert.False(self.T(), hunt_manager.HuntMatchesClient(
+		self.Ctx, self.ConfigObj, hunt_obj, client_info))
+
+	labeler := services.GetLabeler(self.ConfigObj)
+	err := labeler.SetClientLabel(
+		self.Ctx, self.ConfigObj, self.client_id, "Canary")
+	assert.NoError(self.T(), err)
+
+	assert.True(self.T(), hunt_manager.HuntMatchesClient(
+		self.Ctx, self.ConfigObj, hunt_obj, client_info))
+
+	// The OS condition still applies to canary clients.
+	client_info.System = "linux"
+	assert.False(self.T(), hunt_manager.HuntMatchesClient(
+		self.Ctx, self.ConfigObj, hunt_obj, client_info))
+}
+
 func TestHuntTestSuite(t *testing.T) {
 	suite.Run(t, &HuntTestSuite{
 		client_id: "C.234",
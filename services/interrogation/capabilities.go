@@ -0,0 +1,65 @@
+package interrogation
+
+import (
+	"context"
+	"fmt"
+
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+)
+
+const (
+	CAPABILITIES_SOURCE = "Generic.Client.Info/Capabilities"
+)
+
+// Store the capabilities the client advertised so the launcher can
+// check artifact requirements against them.
+func (self *EnrollmentService) ProcessCapabilitiesResults(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id, flow_id, artifact string) error {
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	path_manager, err := artifacts.NewArtifactPathManager(config_obj,
+		client_id, flow_id, artifact)
+	if err != nil {
+		return err
+	}
+
+	rs_reader, err := result_sets.NewResultSetReader(
+		file_store_factory, path_manager.Path())
+	if err != nil {
+		return err
+	}
+	defer rs_reader.Close()
+
+	var capabilities *artifacts_proto.Capabilities
+
+	// Should return only one row
+	for row := range rs_reader.Rows(ctx) {
+		capabilities = &artifacts_proto.Capabilities{FlowId: flow_id}
+		capabilities.Plugins, _ = row.GetStrings("Plugins")
+		capabilities.Functions, _ = row.GetStrings("Functions")
+		capabilities.Accessors, _ = row.GetStrings("Accessors")
+		capabilities.Features, _ = row.GetStrings("Features")
+		break
+	}
+
+	if capabilities == nil {
+		return fmt.Errorf("No %v results", artifact)
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	client_path_manager := paths.NewClientPathManager(client_id)
+	return db.SetSubject(config_obj,
+		client_path_manager.Capabilities(), capabilities)
+}
@@ -89,6 +89,29 @@ func (self *EnrollmentService) Start(
 		return err
 	}
 
+	for _, source := range []string{
+		CAPABILITIES_SOURCE, "Custom." + CAPABILITIES_SOURCE} {
+		source := source
+		err = journal.WatchForCollectionWithCB(ctx, config_obj, wg,
+			source, "InterrogationService",
+			func(ctx context.Context,
+				config_obj *config_proto.Config,
+				client_id, flow_id string) error {
+				return self.ProcessCapabilitiesResults(
+					ctx, config_obj, client_id, flow_id, source)
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = journal.WatchForCollectionWithCB(ctx, config_obj, wg,
+		LINK_QUALITY_ARTIFACT, "InterrogationService",
+		self.ProcessLinkQualityResults)
+	if err != nil {
+		return err
+	}
+
 	return journal.WatchQueueWithCB(ctx, config_obj, wg,
 		"Server.Internal.Enrollment", "InterrogationService",
 		self.ProcessEnrollment)
@@ -175,6 +175,42 @@ func (self *ServicesTestSuite) TestEnrollService() {
 		client_info.LastInterrogateFlowId)
 }
 
+func (self *ServicesTestSuite) TestLinkQuality() {
+	self.EmulateCollection(
+		"Generic.Client.LinkQuality", []*ordereddict.Dict{
+			ordereddict.NewDict().
+				Set("Url", "https://frontend1/").
+				Set("LatencyMs", 250.5).
+				Set("DownloadBps", 1000).
+				Set("UploadBps", 500).
+				Set("Error", ""),
+			ordereddict.NewDict().
+				Set("Url", "https://frontend2/").
+				Set("Error", "Connection refused"),
+		})
+
+	// The slow client is labeled.
+	labeler := services.GetLabeler(self.ConfigObj)
+	vtesting.WaitUntil(2*time.Second, self.T(), func() bool {
+		return labeler.IsLabelSet(context.Background(),
+			self.ConfigObj, self.client_id, "LowBandwidth")
+	})
+
+	// The measurements are stored in the client metadata.
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	metadata, err := client_info_manager.GetMetadata(
+		context.Background(), self.client_id)
+	assert.NoError(self.T(), err)
+
+	download_bps, _ := metadata.GetString("LinkDownloadBps")
+	assert.Equal(self.T(), "1000", download_bps)
+
+	latency, _ := metadata.GetString("LinkLatencyMs")
+	assert.Equal(self.T(), "250.5", latency)
+}
+
 func TestInterrogationService(t *testing.T) {
 	suite.Run(t, &ServicesTestSuite{})
 }
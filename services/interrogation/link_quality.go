@@ -0,0 +1,106 @@
+package interrogation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+const (
+	LINK_QUALITY_ARTIFACT = "Generic.Client.LinkQuality"
+
+	// Set on clients with a slow link so hunts can exclude them.
+	LOW_BANDWIDTH_LABEL = "LowBandwidth"
+
+	// 128kb/s
+	DEFAULT_LOW_BANDWIDTH_THRESHOLD = 128 * 1024
+)
+
+// Store the results of the link quality probe in the client's
+// metadata and label clients with slow links.
+func (self *EnrollmentService) ProcessLinkQualityResults(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id, flow_id string) error {
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	path_manager, err := artifacts.NewArtifactPathManager(config_obj,
+		client_id, flow_id, LINK_QUALITY_ARTIFACT)
+	if err != nil {
+		return err
+	}
+
+	rs_reader, err := result_sets.NewResultSetReader(
+		file_store_factory, path_manager.Path())
+	if err != nil {
+		return err
+	}
+	defer rs_reader.Close()
+
+	// There is a row for each frontend - keep the best one since
+	// the client will use any of them.
+	var best *ordereddict.Dict
+	var best_bps uint64
+	for row := range rs_reader.Rows(ctx) {
+		error_message, _ := row.GetString("Error")
+		if error_message != "" {
+			continue
+		}
+
+		bps, _ := row.GetInt64("DownloadBps")
+		if best == nil || uint64(bps) > best_bps {
+			best = row
+			best_bps = uint64(bps)
+		}
+	}
+
+	if best == nil {
+		return fmt.Errorf("%v: No successful measurements for %v",
+			LINK_QUALITY_ARTIFACT, client_id)
+	}
+
+	getter := func(field string) string {
+		value, _ := best.Get(field)
+		return fmt.Sprintf("%v", value)
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	err = client_info_manager.SetMetadata(ctx, client_id,
+		ordereddict.NewDict().
+			Set("LinkLatencyMs", getter("LatencyMs")).
+			Set("LinkDownloadBps", getter("DownloadBps")).
+			Set("LinkUploadBps", getter("UploadBps")).
+			Set("LinkQualityTime", time.Now().UTC().Format(time.RFC3339)))
+	if err != nil {
+		return err
+	}
+
+	threshold := uint64(DEFAULT_LOW_BANDWIDTH_THRESHOLD)
+	if config_obj.Defaults != nil &&
+		config_obj.Defaults.LowBandwidthThreshold > 0 {
+		threshold = config_obj.Defaults.LowBandwidthThreshold
+	}
+
+	labeler := services.GetLabeler(config_obj)
+	if best_bps < threshold {
+		return labeler.SetClientLabel(ctx, config_obj, client_id,
+			LOW_BANDWIDTH_LABEL)
+	}
+
+	if labeler.IsLabelSet(ctx, config_obj, client_id, LOW_BANDWIDTH_LABEL) {
+		return labeler.RemoveClientLabel(ctx, config_obj, client_id,
+			LOW_BANDWIDTH_LABEL)
+	}
+	return nil
+}
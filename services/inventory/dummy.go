@@ -312,6 +312,7 @@ func (self *Dummy) RemoveTool(
 	}
 
 	self.binaries.Tools = tools
+	self.binaries.Version = uint64(self.Clock.Now().UnixNano())
 
 	return nil
 }
@@ -266,6 +266,7 @@ func (self *InventoryService) RemoveTool(
 	}
 
 	self.binaries.Tools = tools
+	self.binaries.Version = uint64(self.Clock.Now().UnixNano())
 
 	db, err := datastore.GetDB(config_obj)
 	if err != nil {
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+const (
+	JOB_RUNNING   = "RUNNING"
+	JOB_FINISHED  = "FINISHED"
+	JOB_ERROR     = "ERROR"
+	JOB_CANCELLED = "CANCELLED"
+)
+
+func GetJobManager(config_obj *config_proto.Config) (JobManager, error) {
+	org_manager, err := GetOrgManager()
+	if err != nil {
+		return nil, err
+	}
+
+	return org_manager.Services(config_obj.OrgId).JobManager()
+}
+
+// Passed to a running job so it can report its progress.
+type JobReporter interface {
+	JobId() string
+
+	// Report that completed out of total units of work are done.
+	SetProgress(completed, total uint64)
+
+	// Describe the current stage of the job.
+	SetStatus(status string)
+}
+
+type JobFunc func(ctx context.Context, reporter JobReporter) error
+
+// The job manager runs long running server operations (e.g. exports,
+// imports, migrations or bulk operations) in the background and
+// keeps their state in the datastore so users can follow their
+// progress and see past jobs.
+type JobManager interface {
+	// Start the job in the background. The job's context is
+	// cancelled when the job is cancelled or the server shuts
+	// down. Returns the initial job record.
+	StartJob(ctx context.Context,
+		principal, job_type, description string,
+		job JobFunc) (*api_proto.ServerJob, error)
+
+	// Cancel a running job.
+	CancelJob(ctx context.Context, job_id string) error
+
+	GetJob(ctx context.Context, job_id string) (*api_proto.ServerJob, error)
+
+	// List all current and past jobs, most recent first.
+	ListJobs(ctx context.Context) ([]*api_proto.ServerJob, error)
+}
@@ -0,0 +1,333 @@
+// A framework for long running server jobs.
+
+// Some server operations (e.g. exporting the datastore, migrations or
+// bulk operations on many clients) take a long time to complete. The
+// job manager runs them in the background, tracks their progress and
+// stores their state in the datastore so users can see what is
+// running, cancel jobs and review past jobs.
+
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	// Progress updates are written to the datastore at most this
+	// often.
+	persistInterval = 5 * time.Second
+
+	jobNotRunningError = errors.New("Job is not running")
+	jobNotFoundError   = errors.New("Job not found")
+)
+
+func NewJobId() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+
+	binary.BigEndian.PutUint32(buf, uint32(time.Now().Unix()))
+	result := base32.HexEncoding.EncodeToString(buf)[:13]
+
+	return "J." + result
+}
+
+type runningJob struct {
+	mu sync.Mutex
+
+	manager   *JobManager
+	record    *api_proto.ServerJob
+	cancel    func()
+	cancelled bool
+
+	last_write time.Time
+}
+
+func (self *runningJob) JobId() string {
+	return self.record.JobId
+}
+
+func (self *runningJob) SetProgress(completed, total uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.record.Completed = completed
+	self.record.Total = total
+	self.maybePersist()
+}
+
+func (self *runningJob) SetStatus(status string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.record.Status = status
+	self.maybePersist()
+}
+
+// Called with the lock held.
+func (self *runningJob) maybePersist() {
+	now := self.manager.Clock.Now()
+	if now.Sub(self.last_write) < persistInterval {
+		return
+	}
+	self.last_write = now
+	self.manager.persist(self.record)
+}
+
+func (self *runningJob) copy() *api_proto.ServerJob {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return proto.Clone(self.record).(*api_proto.ServerJob)
+}
+
+type JobManager struct {
+	mu sync.Mutex
+
+	ctx        context.Context
+	wg         *sync.WaitGroup
+	config_obj *config_proto.Config
+
+	Clock utils.Clock
+
+	// Currently running jobs by job id.
+	running map[string]*runningJob
+}
+
+func (self *JobManager) StartJob(ctx context.Context,
+	principal, job_type, description string,
+	job services.JobFunc) (*api_proto.ServerJob, error) {
+
+	record := &api_proto.ServerJob{
+		JobId:       NewJobId(),
+		Type:        job_type,
+		Description: description,
+		Principal:   principal,
+		State:       services.JOB_RUNNING,
+		StartTime:   uint64(self.Clock.Now().Unix()),
+	}
+
+	err := self.persist(record)
+	if err != nil {
+		return nil, err
+	}
+
+	// The job outlives the request that started it so it is bound
+	// to the service's context instead.
+	sub_ctx, cancel := context.WithCancel(self.ctx)
+
+	running := &runningJob{
+		manager:    self,
+		record:     record,
+		cancel:     cancel,
+		last_write: self.Clock.Now(),
+	}
+
+	self.mu.Lock()
+	self.running[record.JobId] = running
+	self.mu.Unlock()
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Info("<green>JobManager</>: %v started %v job %v: %v",
+		principal, job_type, record.JobId, description)
+
+	result := running.copy()
+
+	self.wg.Add(1)
+	go func() {
+		defer self.wg.Done()
+		defer cancel()
+
+		err := job(sub_ctx, running)
+		self.finish(running, err)
+	}()
+
+	return result, nil
+}
+
+func (self *JobManager) finish(running *runningJob, err error) {
+	self.mu.Lock()
+	delete(self.running, running.record.JobId)
+	self.mu.Unlock()
+
+	running.mu.Lock()
+	defer running.mu.Unlock()
+
+	record := running.record
+	record.EndTime = uint64(self.Clock.Now().Unix())
+
+	switch {
+	case running.cancelled:
+		record.State = services.JOB_CANCELLED
+
+	case err != nil:
+		record.State = services.JOB_ERROR
+		record.Error = err.Error()
+
+	default:
+		record.State = services.JOB_FINISHED
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Info("<green>JobManager</>: Job %v %v", record.JobId, record.State)
+
+	self.persist(record)
+}
+
+func (self *JobManager) persist(record *api_proto.ServerJob) error {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSubject(self.config_obj,
+		paths.NewJobPathManager(record.JobId).Path(), record)
+}
+
+func (self *JobManager) CancelJob(ctx context.Context, job_id string) error {
+	if !constants.JobIdRegex.MatchString(job_id) {
+		return jobNotFoundError
+	}
+
+	self.mu.Lock()
+	running, pres := self.running[job_id]
+	self.mu.Unlock()
+
+	if !pres {
+		return jobNotRunningError
+	}
+
+	running.mu.Lock()
+	running.cancelled = true
+	running.mu.Unlock()
+
+	running.cancel()
+	return nil
+}
+
+func (self *JobManager) GetJob(
+	ctx context.Context, job_id string) (*api_proto.ServerJob, error) {
+	if !constants.JobIdRegex.MatchString(job_id) {
+		return nil, jobNotFoundError
+	}
+
+	self.mu.Lock()
+	running, pres := self.running[job_id]
+	self.mu.Unlock()
+
+	if pres {
+		return running.copy(), nil
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &api_proto.ServerJob{}
+	err = db.GetSubject(self.config_obj,
+		paths.NewJobPathManager(job_id).Path(), record)
+	if errors.Is(err, os.ErrNotExist) || record.JobId == "" {
+		return nil, jobNotFoundError
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (self *JobManager) ListJobs(
+	ctx context.Context) ([]*api_proto.ServerJob, error) {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(self.config_obj,
+		paths.NewJobPathManager("").Directory())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*api_proto.ServerJob, 0, len(children))
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		record, err := self.GetJob(ctx, child.Base())
+		if err != nil {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartTime > result[j].StartTime
+	})
+
+	return result, nil
+}
+
+// Jobs that were running when the server went down can never
+// complete - mark them as failed.
+func (self *JobManager) markAbortedJobs(ctx context.Context) error {
+	jobs, err := self.ListJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range jobs {
+		if record.State != services.JOB_RUNNING {
+			continue
+		}
+
+		record.State = services.JOB_ERROR
+		record.Error = "Server restarted while the job was running"
+		record.EndTime = uint64(self.Clock.Now().Unix())
+		err = self.persist(record)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewJobManager(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) (services.JobManager, error) {
+
+	result := &JobManager{
+		ctx:        ctx,
+		wg:         wg,
+		config_obj: config_obj,
+		Clock:      &utils.RealClock{},
+		running:    make(map[string]*runningJob),
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Job Manager for %v",
+		services.GetOrgName(config_obj))
+
+	return result, result.markAbortedJobs(ctx)
+}
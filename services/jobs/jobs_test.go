@@ -0,0 +1,102 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+type JobsTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *JobsTestSuite) SetupTest() {
+	self.ConfigObj = self.LoadConfig()
+	self.ConfigObj.Frontend.ServerServices.JobManager = true
+
+	self.TestSuite.SetupTest()
+}
+
+func (self *JobsTestSuite) waitForState(
+	job_manager services.JobManager, job_id, state string) *api_proto.ServerJob {
+	var job *api_proto.ServerJob
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		var err error
+		job, err = job_manager.GetJob(self.Ctx, job_id)
+		require.NoError(self.T(), err)
+		return job.State == state
+	})
+	return job
+}
+
+func (self *JobsTestSuite) TestJobLifecycle() {
+	job_manager, err := services.GetJobManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	// A job that completes successfully.
+	job, err := job_manager.StartJob(self.Ctx, "admin", "test", "A test job",
+		func(ctx context.Context, reporter services.JobReporter) error {
+			reporter.SetStatus("Working")
+			reporter.SetProgress(5, 10)
+			return nil
+		})
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), services.JOB_RUNNING, job.State)
+
+	finished := self.waitForState(job_manager, job.JobId, services.JOB_FINISHED)
+	assert.Equal(self.T(), uint64(5), finished.Completed)
+	assert.Equal(self.T(), uint64(10), finished.Total)
+	assert.Equal(self.T(), "Working", finished.Status)
+	assert.Equal(self.T(), "admin", finished.Principal)
+
+	// A job that fails.
+	job, err = job_manager.StartJob(self.Ctx, "admin", "test", "A failing job",
+		func(ctx context.Context, reporter services.JobReporter) error {
+			return errors.New("Failed!")
+		})
+	require.NoError(self.T(), err)
+
+	failed := self.waitForState(job_manager, job.JobId, services.JOB_ERROR)
+	assert.Equal(self.T(), "Failed!", failed.Error)
+
+	// A job that runs until cancelled.
+	job, err = job_manager.StartJob(self.Ctx, "admin", "test", "A long job",
+		func(ctx context.Context, reporter services.JobReporter) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	require.NoError(self.T(), err)
+
+	err = job_manager.CancelJob(self.Ctx, job.JobId)
+	require.NoError(self.T(), err)
+	self.waitForState(job_manager, job.JobId, services.JOB_CANCELLED)
+
+	// Cancelling a job which is not running is an error.
+	err = job_manager.CancelJob(self.Ctx, job.JobId)
+	assert.Error(self.T(), err)
+
+	// All jobs are kept in the history.
+	jobs, err := job_manager.ListJobs(self.Ctx)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(jobs))
+
+	// Job ids which are not of the form J.XXXX are rejected.
+	_, err = job_manager.GetJob(self.Ctx, "../users/admin")
+	assert.Error(self.T(), err)
+
+	err = job_manager.CancelJob(self.Ctx, "J.1/../../foo")
+	assert.Error(self.T(), err)
+}
+
+func TestJobManager(t *testing.T) {
+	suite.Run(t, &JobsTestSuite{})
+}
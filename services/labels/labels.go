@@ -15,6 +15,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/snapshots"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
@@ -231,6 +232,12 @@ func (self *Labeler) SetClientLabel(
 	// Cache the new record.
 	self.lru.Set(client_id, cached)
 
+	err = snapshots.Record(config_obj, client_path_manager.LabelHistory(),
+		cached.record.Label)
+	if err != nil {
+		return err
+	}
+
 	err = self.notifyClient(config_obj, client_id, new_label, "Add")
 	if err != nil {
 		return err
@@ -286,6 +293,12 @@ func (self *Labeler) RemoveClientLabel(
 	// Cache the new record.
 	self.lru.Set(client_id, cached)
 
+	err = snapshots.Record(config_obj, client_path_manager.LabelHistory(),
+		cached.record.Label)
+	if err != nil {
+		return err
+	}
+
 	err = self.notifyClient(config_obj, client_id, new_label, "Remove")
 	if err != nil {
 		return err
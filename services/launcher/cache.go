@@ -0,0 +1,111 @@
+package launcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	metricCompilationCache = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "launcher_compilation_cache",
+			Help: "Lookups in the compiled artifact cache",
+		},
+		[]string{"result"},
+	)
+)
+
+// Compiling an artifact only depends on the artifact definition, the
+// definitions of its dependencies, the tools in the inventory, the
+// parameters in the spec and the compiler options. The key captures
+// all of these so a cached compilation is never stale: Modifying any
+// artifact changes the repository version and modifying the
+// inventory changes its version.
+func compilationCacheKey(
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	artifact *artifacts_proto.Artifact,
+	spec *flows_proto.ArtifactSpec,
+	options services.CompilerOptions) (string, error) {
+
+	inventory, err := services.GetInventory(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	marshal := proto.MarshalOptions{Deterministic: true}
+	serialized_artifact, err := marshal.Marshal(artifact)
+	if err != nil {
+		return "", err
+	}
+
+	serialized_spec, err := marshal.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%d\n%+v\n", repository.Version(),
+		inventory.Get().Version, options)
+	hash.Write(serialized_artifact)
+	hash.Write(serialized_spec)
+
+	return artifact.Name + ":" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Compile the requests for the artifact (one for each expanded
+// artifact), reusing a previous compilation if possible.
+func (self *Launcher) getCompiledArtifact(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	artifact *artifacts_proto.Artifact,
+	spec *flows_proto.ArtifactSpec,
+	options services.CompilerOptions) ([]*actions_proto.VQLCollectorArgs, error) {
+
+	// Caching is an optimization - without a repository manager
+	// (e.g. on the client) we just compile the artifact.
+	manager, _ := services.GetRepositoryManager(config_obj)
+
+	key := ""
+	if manager != nil {
+		key, _ = compilationCacheKey(
+			config_obj, repository, artifact, spec, options)
+		if key != "" {
+			cached, pres := manager.GetCompiledArtifact(key)
+			if pres {
+				metricCompilationCache.WithLabelValues("hit").Inc()
+				return cached, nil
+			}
+			metricCompilationCache.WithLabelValues("miss").Inc()
+		}
+	}
+
+	result := []*actions_proto.VQLCollectorArgs{}
+	for _, expanded_artifact := range expandArtifacts(artifact) {
+		vql_collector_args, err := self.GetVQLCollectorArgs(
+			ctx, config_obj, repository, expanded_artifact,
+			spec, options)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vql_collector_args)
+	}
+
+	if key != "" {
+		manager.SetCompiledArtifact(key, result)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package launcher_test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/assert"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+var cachedArtifacts = []string{`
+name: Test.Cached
+sources:
+- query: SELECT * FROM Artifact.Test.CachedDep()
+`, `
+name: Test.CachedDep
+sources:
+- query: SELECT "Version1" AS Version FROM scope()
+`}
+
+func (self *LauncherTestSuite) TestCompilationCache() {
+	repository := self.LoadArtifacts(cachedArtifacts)
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	compile := func() string {
+		compiled, err := launcher.CompileCollectorArgs(
+			context.Background(), self.ConfigObj,
+			acl_managers.NullACLManager{}, repository,
+			services.CompilerOptions{},
+			&flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Test.Cached"},
+			})
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), 1, len(compiled))
+		assert.Equal(self.T(), 1, len(compiled[0].Artifacts))
+
+		// Callers are free to modify the result.
+		result := compiled[0].Artifacts[0].Sources[0].Queries[0]
+		compiled[0].Artifacts[0].Sources[0].Queries[0] = "Modified"
+
+		return result
+	}
+
+	assert.Contains(self.T(), compile(), "Version1")
+
+	// The second compilation comes from the cache and is not
+	// affected by the modification above.
+	assert.Contains(self.T(), compile(), "Version1")
+
+	// Updating a dependency invalidates the cache.
+	_, err = repository.LoadYaml(`
+name: Test.CachedDep
+sources:
+- query: SELECT "Version2" AS Version FROM scope()
+`, true, true)
+	assert.NoError(self.T(), err)
+
+	assert.Contains(self.T(), compile(), "Version2")
+}
@@ -0,0 +1,287 @@
+package launcher
+
+// Artifacts may declare the client capabilities they need
+// (required_capabilities) and clients advertise their capabilities
+// when they are interrogated. Before scheduling a collection the
+// launcher compares the two and skips any artifact the client is
+// unable to collect. The collection records a SKIPPED status for the
+// artifact rather than failing with a VQL error on the client.
+
+// Clients which never reported their capabilities (e.g. older
+// clients) are assumed to support everything. So are clients which
+// were interrogated again since they last reported them (e.g. after
+// an upgrade) until the new interrogation reports them again.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"google.golang.org/protobuf/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type skippedArtifact struct {
+	Artifact string
+
+	// The name of the artifact the request resolved to (e.g. a
+	// Custom. override).
+	Resolved string
+
+	// The capabilities the client lacks (e.g. plugin:ebpf_events)
+	Missing []string
+}
+
+// Returns nil if the client has not reported its capabilities or
+// they are stale.
+func getClientCapabilities(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	client_id string) *artifacts_proto.Capabilities {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil
+	}
+
+	result := &artifacts_proto.Capabilities{}
+	client_path_manager := paths.NewClientPathManager(client_id)
+	err = db.GetSubject(config_obj, client_path_manager.Capabilities(), result)
+	if err != nil || proto.Equal(result, &artifacts_proto.Capabilities{}) {
+		return nil
+	}
+
+	// Capabilities are only trusted if they came from the client's
+	// last interrogation. The client may have changed since.
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return nil
+	}
+
+	client_info, err := client_info_manager.Get(ctx, client_id)
+	if err != nil || result.FlowId == "" ||
+		client_info.LastInterrogateFlowId != result.FlowId {
+		return nil
+	}
+
+	return result
+}
+
+// Lists the required capabilities which are not available.
+func missingCapabilities(
+	required, available *artifacts_proto.Capabilities) []string {
+	if required == nil || available == nil {
+		return nil
+	}
+
+	result := []string{}
+	check := func(kind string, required, available []string) {
+		for _, name := range required {
+			if !utils.InString(available, name) {
+				result = append(result, kind+":"+name)
+			}
+		}
+	}
+
+	check("plugin", required.Plugins, available.Plugins)
+	check("function", required.Functions, available.Functions)
+	check("accessor", required.Accessors, available.Accessors)
+	check("feature", required.Features, available.Features)
+
+	return result
+}
+
+// Find the artifacts in the request which the client is unable to
+// collect. Unknown artifacts are left for the compiler to report.
+func getSkippedArtifacts(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	collector_request *flows_proto.ArtifactCollectorArgs) []*skippedArtifact {
+
+	client_id := collector_request.ClientId
+	if client_id == "" || client_id == "server" {
+		return nil
+	}
+
+	available := getClientCapabilities(ctx, config_obj, client_id)
+	if available == nil {
+		return nil
+	}
+
+	var result []*skippedArtifact
+	for _, spec := range getCollectorSpecs(collector_request) {
+		artifact, pres := services.ResolveArtifact(config_obj, repository,
+			spec.Artifact, collector_request.AllowCustomOverrides)
+		if !pres {
+			continue
+		}
+
+		missing := missingCapabilities(artifact.RequiredCapabilities, available)
+		if len(missing) > 0 {
+			result = append(result, &skippedArtifact{
+				Artifact: spec.Artifact,
+				Resolved: artifact.Name,
+				Missing:  missing,
+			})
+		}
+	}
+
+	return result
+}
+
+// Returns a copy of the request without the skipped artifacts. The
+// request may be shared (e.g. by a hunt) so we must not modify it.
+func removeSkippedArtifacts(
+	config_obj *config_proto.Config,
+	collector_request *flows_proto.ArtifactCollectorArgs,
+	skipped []*skippedArtifact) *flows_proto.ArtifactCollectorArgs {
+
+	is_skipped := func(name string) bool {
+		for _, item := range skipped {
+			if item.Artifact == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := proto.Clone(collector_request).(*flows_proto.ArtifactCollectorArgs)
+	result.Artifacts = nil
+	result.Specs = nil
+
+	if collector_request.CompiledCollectorArgs != nil {
+		result.CompiledCollectorArgs = removeSkippedCompiledArgs(
+			config_obj, result.CompiledCollectorArgs, skipped)
+	}
+
+	for _, name := range collector_request.Artifacts {
+		if !is_skipped(name) {
+			result.Artifacts = append(result.Artifacts, name)
+		}
+	}
+
+	for _, spec := range collector_request.Specs {
+		if !is_skipped(spec.Artifact) {
+			result.Specs = append(result.Specs, spec)
+		}
+	}
+
+	return result
+}
+
+// Each compiled args holds a single artifact source. Its named
+// queries carry the (possibly obfuscated) name of the source.
+func removeSkippedCompiledArgs(
+	config_obj *config_proto.Config,
+	compiled []*actions_proto.VQLCollectorArgs,
+	skipped []*skippedArtifact) []*actions_proto.VQLCollectorArgs {
+
+	is_skipped := func(vql_collector_args *actions_proto.VQLCollectorArgs) bool {
+		for _, query := range vql_collector_args.Query {
+			if query.Name == "" {
+				continue
+			}
+
+			artifact_name, _ := paths.SplitFullSourceName(
+				artifacts.DeobfuscateString(config_obj, query.Name))
+			for _, item := range skipped {
+				if item.Resolved == artifact_name {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	result := []*actions_proto.VQLCollectorArgs{}
+	for _, vql_collector_args := range compiled {
+		if !is_skipped(vql_collector_args) {
+			result = append(result, vql_collector_args)
+		}
+	}
+
+	for idx, item := range result {
+		item.QueryId = int64(idx + 1)
+		item.TotalQueries = int64(len(result))
+	}
+
+	return result
+}
+
+// Build the statuses recorded in the collection for the skipped
+// artifacts. Their query ids follow those of the queries actually
+// sent to the client so they never collide.
+func getSkippedStatuses(
+	skipped []*skippedArtifact, scheduled int) []*crypto_proto.VeloStatus {
+	total := int64(scheduled + len(skipped))
+
+	result := make([]*crypto_proto.VeloStatus, 0, len(skipped))
+	for idx, item := range skipped {
+		result = append(result, &crypto_proto.VeloStatus{
+			Status:   crypto_proto.VeloStatus_SKIPPED,
+			Artifact: item.Artifact,
+			ErrorMessage: fmt.Sprintf(
+				"Artifact %v not supported on this client: missing %v",
+				item.Artifact, strings.Join(item.Missing, ", ")),
+			QueryId:      int64(scheduled + idx + 1),
+			TotalQueries: total,
+		})
+	}
+	return result
+}
+
+// Store a collection in which every artifact was skipped. No
+// responses will arrive from the client so we notify listeners that
+// the collection is complete right away.
+func completeSkippedCollection(
+	config_obj *config_proto.Config,
+	collection_context *flows_proto.ArtifactCollectorContext) (string, error) {
+
+	now := uint64(time.Now().UnixNano() / 1000)
+	collection_context.State = flows_proto.ArtifactCollectorContext_FINISHED
+	collection_context.StartTime = now
+	collection_context.ActiveTime = now
+	collection_context.UserNotified = true
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	journal, err := services.GetJournal(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	flow_path_manager := paths.NewFlowPathManager(
+		collection_context.ClientId, collection_context.SessionId)
+	err = db.SetSubjectWithCompletion(config_obj,
+		flow_path_manager.Path(),
+		collection_context,
+
+		func() {
+			journal.PushRowsToArtifactAsync(config_obj,
+				ordereddict.NewDict().
+					Set("Timestamp", time.Now().UTC().Unix()).
+					Set("Flow", proto.Clone(collection_context)).
+					Set("FlowId", collection_context.SessionId).
+					Set("ClientId", collection_context.ClientId),
+				"System.Flow.Completion")
+		})
+	if err != nil {
+		return "", err
+	}
+
+	return collection_context.SessionId, nil
+}
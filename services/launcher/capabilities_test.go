@@ -0,0 +1,206 @@
+package launcher_test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/assert"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+var capabilityArtifacts = []string{`
+name: Test.NeedsEBPF
+required_capabilities:
+  plugins:
+    - ebpf_events
+  features:
+    - ebpf
+sources:
+- query: SELECT * FROM ebpf_events()
+`, `
+name: Test.Basic
+sources:
+- query: SELECT * FROM info()
+`}
+
+func (self *LauncherTestSuite) scheduleForCapabilities(
+	repository services.Repository, client_id string,
+	artifacts ...string) *flows_proto.ArtifactCollectorContext {
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	flow_id, err := launcher.ScheduleArtifactCollection(context.Background(),
+		self.ConfigObj, acl_managers.NullACLManager{}, repository,
+		&flows_proto.ArtifactCollectorArgs{
+			ClientId:  client_id,
+			Artifacts: artifacts,
+		}, nil)
+	assert.NoError(self.T(), err)
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	result := &flows_proto.ArtifactCollectorContext{}
+	err = db.GetSubject(self.ConfigObj,
+		paths.NewFlowPathManager(client_id, flow_id).Path(), result)
+	assert.NoError(self.T(), err)
+
+	return result
+}
+
+// Record the capabilities as reported by an interrogation flow and
+// the client's last interrogation.
+func (self *LauncherTestSuite) setCapabilities(client_id string,
+	capabilities *artifacts_proto.Capabilities, last_interrogation string) {
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = db.SetSubject(self.ConfigObj,
+		paths.NewClientPathManager(client_id).Capabilities(), capabilities)
+	assert.NoError(self.T(), err)
+
+	client_info_manager, err := services.GetClientInfoManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	err = client_info_manager.Set(context.Background(), &services.ClientInfo{
+		actions_proto.ClientInfo{
+			ClientId:              client_id,
+			LastInterrogateFlowId: last_interrogation,
+		}})
+	assert.NoError(self.T(), err)
+}
+
+func (self *LauncherTestSuite) TestCapabilitySkipping() {
+	repository := self.LoadArtifacts(capabilityArtifacts)
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// This client has the plugin but does not run as root.
+	client_id := "C.1234"
+	self.setCapabilities(client_id, &artifacts_proto.Capabilities{
+		Plugins:  []string{"info", "ebpf_events"},
+		Features: []string{},
+		FlowId:   "F.1",
+	}, "F.1")
+
+	flow := self.scheduleForCapabilities(repository, client_id,
+		"Test.NeedsEBPF", "Test.Basic")
+
+	// Only Test.Basic is sent to the client.
+	assert.Equal(self.T(), flows_proto.ArtifactCollectorContext_RUNNING,
+		flow.State)
+	assert.Equal(self.T(), int64(2), flow.TotalRequests)
+	assert.Equal(self.T(), int64(1), flow.OutstandingRequests)
+	assert.Equal(self.T(), []string{"Test.NeedsEBPF", "Test.Basic"},
+		flow.Request.Artifacts)
+
+	assert.Equal(self.T(), 1, len(flow.QueryStats))
+	assert.Equal(self.T(), crypto_proto.VeloStatus_SKIPPED,
+		flow.QueryStats[0].Status)
+	assert.Equal(self.T(), "Test.NeedsEBPF", flow.QueryStats[0].Artifact)
+	assert.Contains(self.T(), flow.QueryStats[0].ErrorMessage, "feature:ebpf")
+
+	tasks := &api_proto.ApiFlowRequestDetails{}
+	err = db.GetSubject(self.ConfigObj,
+		paths.NewFlowPathManager(client_id, flow.SessionId).Task(), tasks)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(tasks.Items))
+
+	// When nothing is left to collect the collection is complete.
+	flow = self.scheduleForCapabilities(repository, client_id,
+		"Test.NeedsEBPF")
+	assert.Equal(self.T(), flows_proto.ArtifactCollectorContext_FINISHED,
+		flow.State)
+	assert.Equal(self.T(), int64(0), flow.OutstandingRequests)
+
+	// Clients which never reported capabilities collect everything.
+	flow = self.scheduleForCapabilities(repository, "C.5678",
+		"Test.NeedsEBPF", "Test.Basic")
+	assert.Equal(self.T(), int64(2), flow.OutstandingRequests)
+	assert.Equal(self.T(), 0, len(flow.QueryStats))
+}
+
+func (self *LauncherTestSuite) TestCapabilitySkippingPrecompiled() {
+	repository := self.LoadArtifacts(capabilityArtifacts)
+
+	client_id := "C.1234"
+	self.setCapabilities(client_id, &artifacts_proto.Capabilities{
+		Plugins: []string{"info"},
+		FlowId:  "F.1",
+	}, "F.1")
+
+	launcher, err := services.GetLauncher(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Hunts compile the request once and share it between clients.
+	request := &flows_proto.ArtifactCollectorArgs{
+		ClientId:  client_id,
+		Artifacts: []string{"Test.NeedsEBPF", "Test.Basic"},
+	}
+	compiled, err := launcher.CompileCollectorArgs(context.Background(),
+		self.ConfigObj, acl_managers.NullACLManager{}, repository,
+		services.CompilerOptions{ObfuscateNames: true}, request)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(compiled))
+	request.CompiledCollectorArgs = compiled
+
+	flow_id, err := launcher.ScheduleArtifactCollection(context.Background(),
+		self.ConfigObj, acl_managers.NullACLManager{}, repository,
+		request, nil)
+	assert.NoError(self.T(), err)
+
+	// The shared request is not modified.
+	assert.Equal(self.T(), 2, len(request.CompiledCollectorArgs))
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	// Only the precompiled query for Test.Basic is sent.
+	tasks := &api_proto.ApiFlowRequestDetails{}
+	err = db.GetSubject(self.ConfigObj,
+		paths.NewFlowPathManager(client_id, flow_id).Task(), tasks)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(tasks.Items))
+
+	args := tasks.Items[0].VQLClientAction
+	assert.Equal(self.T(), "Test.Basic", args.Query[len(args.Query)-1].Name)
+	assert.Equal(self.T(), int64(1), args.QueryId)
+	assert.Equal(self.T(), int64(1), args.TotalQueries)
+}
+
+func (self *LauncherTestSuite) TestCapabilitiesStale() {
+	repository := self.LoadArtifacts(capabilityArtifacts)
+
+	// The client was interrogated again since it reported its
+	// capabilities (e.g. after an upgrade) so they are not trusted.
+	client_id := "C.1234"
+	self.setCapabilities(client_id, &artifacts_proto.Capabilities{
+		Plugins: []string{"info"},
+		FlowId:  "F.1",
+	}, "F.2")
+
+	flow := self.scheduleForCapabilities(repository, client_id,
+		"Test.NeedsEBPF", "Test.Basic")
+	assert.Equal(self.T(), int64(2), flow.OutstandingRequests)
+	assert.Equal(self.T(), 0, len(flow.QueryStats))
+
+	// Capabilities stored before they recorded their interrogation
+	// are not trusted either.
+	self.setCapabilities(client_id, &artifacts_proto.Capabilities{
+		Plugins: []string{"info"},
+	}, "F.2")
+
+	flow = self.scheduleForCapabilities(repository, client_id,
+		"Test.NeedsEBPF", "Test.Basic")
+	assert.Equal(self.T(), int64(2), flow.OutstandingRequests)
+	assert.Equal(self.T(), 0, len(flow.QueryStats))
+}
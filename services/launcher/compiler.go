@@ -173,6 +173,7 @@ LET %v <= if(
 		result.OpsPerSecond = artifact.Resources.OpsPerSecond
 		result.CpuLimit = artifact.Resources.CpuLimit
 		result.IopsLimit = artifact.Resources.IopsLimit
+		result.MaxMemory = artifact.Resources.MaxMemory
 	}
 
 	err := resolveImports(config_obj, artifact, result)
@@ -180,9 +181,40 @@ LET %v <= if(
 		return err
 	}
 
+	err = compileCleanup(artifact, result)
+	if err != nil {
+		return err
+	}
+
 	return mergeSources(config_obj, options, artifact, result)
 }
 
+// The cleanup section is run by the client if the collection is
+// cancelled or times out.
+func compileCleanup(artifact *artifacts_proto.Artifact,
+	result *actions_proto.VQLCollectorArgs) error {
+	if artifact.Cleanup == "" {
+		return nil
+	}
+
+	scope := vql_subsystem.MakeScope()
+	queries, err := vfilter.MultiParse(artifact.Cleanup)
+	if err != nil {
+		return fmt.Errorf("While parsing cleanup in %s: %w",
+			artifact.Name, err)
+	}
+
+	for _, q := range queries {
+		result.Cleanup = append(result.Cleanup,
+			&actions_proto.VQLRequest{
+				Name: artifact.Name,
+				VQL:  vfilter.FormatToString(scope, q),
+			})
+	}
+
+	return nil
+}
+
 func resolveImports(config_obj *config_proto.Config,
 	artifact *artifacts_proto.Artifact,
 	result *actions_proto.VQLCollectorArgs) error {
@@ -68,6 +68,16 @@ func (self *Launcher) DeleteFlow(
 	r.emit_fs("UploadMetadataIndex", upload_metadata_path.
 		SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
 
+	signatures_path := flow_path_manager.Signatures()
+	r.emit_fs("Signatures", signatures_path)
+	r.emit_fs("SignaturesIndex", signatures_path.
+		SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+
+	upload_hashes_path := flow_path_manager.UploadHashes()
+	r.emit_fs("UploadHashes", upload_hashes_path)
+	r.emit_fs("UploadHashesIndex", upload_hashes_path.
+		SetType(api.PATH_TYPE_FILESTORE_JSON_INDEX))
+
 	// Remove all result sets from artifacts.
 	for _, artifact_name := range collection_context.ArtifactsWithResults {
 		path_manager, err := artifact_paths.NewArtifactPathManager(
@@ -0,0 +1,121 @@
+package launcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+const (
+	DEPENDENCY_ARTIFACT = "artifact"
+	DEPENDENCY_IMPORT   = "import"
+)
+
+// A node in an artifact's dependency tree. Unlike
+// GetDependentArtifacts() which only collects the names, the tree
+// keeps track of how each artifact is reached so circular
+// dependencies can be reported.
+type ArtifactDependency struct {
+	Name string `json:"name"`
+
+	// How the parent refers to this artifact (Artifact() call or
+	// import).
+	Kind string `json:"kind"`
+
+	Tools        []string              `json:"tools,omitempty"`
+	Dependencies []*ArtifactDependency `json:"dependencies,omitempty"`
+}
+
+// Resolve all the artifacts the named artifact calls or imports
+// (recursively) and the tools they need. Missing artifacts and
+// circular dependencies are errors.
+func GetArtifactDependencyTree(
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	name string) (*ArtifactDependency, error) {
+	return getDependencyTree(config_obj, repository,
+		name, DEPENDENCY_ARTIFACT, nil)
+}
+
+func getDependencyTree(
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	name, kind string, path []string) (*ArtifactDependency, error) {
+
+	for idx, item := range path {
+		if item == name {
+			cycle := append(append([]string{}, path[idx:]...), name)
+			return nil, fmt.Errorf("Circular dependency: %v",
+				strings.Join(cycle, " -> "))
+		}
+	}
+
+	artifact, pres := repository.Get(config_obj, name)
+	if !pres {
+		if len(path) == 0 {
+			return nil, fmt.Errorf("Artifact %v not found", name)
+		}
+		return nil, fmt.Errorf("Artifact %v depends on unknown artifact %v",
+			path[len(path)-1], name)
+	}
+
+	result := &ArtifactDependency{
+		Name: artifact.Name,
+		Kind: kind,
+	}
+
+	for _, tool := range artifact.Tools {
+		result.Tools = append(result.Tools, tool.Name)
+	}
+	sort.Strings(result.Tools)
+
+	// Copy the path so siblings do not share the backing array.
+	path = append(append([]string{}, path...), artifact.Name)
+
+	for _, imported := range artifact.Imports {
+		dep, err := getDependencyTree(config_obj, repository,
+			imported, DEPENDENCY_IMPORT, path)
+		if err != nil {
+			return nil, err
+		}
+		result.Dependencies = append(result.Dependencies, dep)
+	}
+
+	for _, called := range calledArtifacts(artifact) {
+		dep, err := getDependencyTree(config_obj, repository,
+			called, DEPENDENCY_ARTIFACT, path)
+		if err != nil {
+			return nil, err
+		}
+		result.Dependencies = append(result.Dependencies, dep)
+	}
+
+	return result, nil
+}
+
+// The names of all the artifacts called from the artifact's queries
+// in the order they first appear.
+func calledArtifacts(artifact *artifacts_proto.Artifact) []string {
+	queries := []string{artifact.Precondition, artifact.Export}
+	for _, source := range artifact.Sources {
+		queries = append(queries, source.Precondition, source.Query)
+		queries = append(queries, source.Queries...)
+	}
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, query := range queries {
+		for _, hit := range artifact_in_query_regex.FindAllStringSubmatch(
+			query, -1) {
+			if !seen[hit[1]] {
+				seen[hit[1]] = true
+				result = append(result, hit[1])
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package launcher_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"www.velocidex.com/golang/velociraptor/services/launcher"
+)
+
+var dependencyTreeArtifacts = []string{`
+name: Test.Library
+export: |
+  LET X <= SELECT * FROM Artifact.Test.Leaf()
+`, `
+name: Test.Leaf
+tools:
+  - name: LeafTool
+sources:
+- query: SELECT * FROM info()
+`, `
+name: Test.Root
+imports:
+  - Test.Library
+sources:
+- query: |
+    SELECT * FROM chain(
+      a={ SELECT * FROM Artifact.Test.Leaf() },
+      b={ SELECT * FROM Artifact.Test.Leaf() })
+`, `
+name: Test.Cycle1
+sources:
+- query: SELECT * FROM Artifact.Test.Cycle2()
+`, `
+name: Test.Cycle2
+sources:
+- query: SELECT * FROM Artifact.Test.Cycle1()
+`, `
+name: Test.Missing
+sources:
+- query: SELECT * FROM Artifact.Test.DoesNotExist()
+`}
+
+func (self *LauncherTestSuite) TestGetArtifactDependencyTree() {
+	repository := self.LoadArtifacts(dependencyTreeArtifacts)
+
+	tree, err := launcher.GetArtifactDependencyTree(
+		self.ConfigObj, repository, "Test.Root")
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), 2, len(tree.Dependencies))
+	assert.Equal(self.T(), "Test.Library", tree.Dependencies[0].Name)
+	assert.Equal(self.T(), launcher.DEPENDENCY_IMPORT, tree.Dependencies[0].Kind)
+	assert.Equal(self.T(), "Test.Leaf",
+		tree.Dependencies[0].Dependencies[0].Name)
+
+	// Repeated calls are only listed once.
+	assert.Equal(self.T(), "Test.Leaf", tree.Dependencies[1].Name)
+	assert.Equal(self.T(), []string{"LeafTool"}, tree.Dependencies[1].Tools)
+
+	_, err = launcher.GetArtifactDependencyTree(
+		self.ConfigObj, repository, "Test.Cycle1")
+	assert.ErrorContains(self.T(), err,
+		"Test.Cycle1 -> Test.Cycle2 -> Test.Cycle1")
+
+	_, err = launcher.GetArtifactDependencyTree(
+		self.ConfigObj, repository, "Test.Missing")
+	assert.ErrorContains(self.T(), err, "Test.DoesNotExist")
+}
@@ -175,7 +175,20 @@ func getCollectorSpecs(
 	return result
 }
 
-type Launcher struct{}
+// The parameters explicitly given in the spec.
+func getSpecParameters(spec *flows_proto.ArtifactSpec) map[string]string {
+	result := make(map[string]string)
+	if spec.Parameters != nil {
+		for _, item := range spec.Parameters.Env {
+			result[item.Key] = item.Value
+		}
+	}
+	return result
+}
+
+type Launcher struct {
+	tool_cache toolCache
+}
 
 func (self *Launcher) CompileCollectorArgs(
 	ctx context.Context,
@@ -197,17 +210,9 @@ func (self *Launcher) CompileCollectorArgs(
 	var ops_per_sec, cpu_limit, iops_limit float32
 
 	for _, spec := range getCollectorSpecs(collector_request) {
-		var artifact *artifacts_proto.Artifact = nil
-
-		if collector_request.AllowCustomOverrides {
-			artifact, _ = repository.Get(config_obj, "Custom."+spec.Artifact)
-		}
-
-		if artifact == nil {
-			artifact, _ = repository.Get(config_obj, spec.Artifact)
-		}
-
-		if artifact == nil {
+		artifact, pres := services.ResolveArtifact(config_obj, repository,
+			spec.Artifact, collector_request.AllowCustomOverrides)
+		if !pres {
 			// We have not found the artifact, should we ignore the error?
 			if options.IgnoreMissingArtifacts {
 				logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
@@ -223,6 +228,12 @@ func (self *Launcher) CompileCollectorArgs(
 			return nil, err
 		}
 
+		err = artifacts.CheckParameterDependencies(
+			artifact, getSpecParameters(spec))
+		if err != nil {
+			return nil, err
+		}
+
 		// Adjust collection wide resources to be the maximum
 		// number of all default
 		if artifact.Resources != nil {
@@ -235,14 +246,13 @@ func (self *Launcher) CompileCollectorArgs(
 			}
 		}
 
-		for _, expanded_artifact := range expandArtifacts(artifact) {
-			vql_collector_args, err := self.GetVQLCollectorArgs(
-				ctx, config_obj, repository, expanded_artifact,
-				spec, options)
-			if err != nil {
-				return nil, err
-			}
+		compiled, err := self.getCompiledArtifact(
+			ctx, config_obj, repository, artifact, spec, options)
+		if err != nil {
+			return nil, err
+		}
 
+		for _, vql_collector_args := range compiled {
 			// If the request specifies resource controls
 			// they override the defaults.
 			if collector_request.OpsPerSecond > 0 {
@@ -265,6 +275,8 @@ func (self *Launcher) CompileCollectorArgs(
 				vql_collector_args.Timeout = collector_request.Timeout
 			}
 
+			vql_collector_args.SignResults = collector_request.SignResults
+
 			vql_collector_args.MaxRow = 1000
 
 			timeout = vql_collector_args.Timeout
@@ -505,7 +517,15 @@ func (self *Launcher) ScheduleArtifactCollection(
 	collector_request *flows_proto.ArtifactCollectorArgs,
 	completion func()) (string, error) {
 
-	args := collector_request.CompiledCollectorArgs
+	// Artifacts the client is unable to collect are not sent to it.
+	request := collector_request
+	skipped := getSkippedArtifacts(
+		ctx, config_obj, repository, collector_request)
+	if len(skipped) > 0 {
+		request = removeSkippedArtifacts(config_obj, collector_request, skipped)
+	}
+
+	args := request.CompiledCollectorArgs
 	if args == nil {
 		// Compile and cache the compilation for next time
 		// just in case this request is reused.
@@ -516,15 +536,21 @@ func (self *Launcher) ScheduleArtifactCollection(
 			ctx, config_obj, acl_manager, repository,
 			services.CompilerOptions{
 				ObfuscateNames: true,
-			}, collector_request)
+			}, request)
 		if err != nil {
 			return "", err
 		}
 		args = append(args, compiled...)
 	}
 
-	return self.ScheduleArtifactCollectionFromCollectorArgs(
-		ctx, config_obj, collector_request, args, completion)
+	// The collection still records all the requested artifacts.
+	if len(skipped) > 0 {
+		request.Artifacts = collector_request.Artifacts
+		request.Specs = collector_request.Specs
+	}
+
+	return self.scheduleArtifactCollection(ctx, config_obj, request, args,
+		getSkippedStatuses(skipped, len(args)))
 }
 
 func (self *Launcher) ScheduleArtifactCollectionFromCollectorArgs(
@@ -533,6 +559,18 @@ func (self *Launcher) ScheduleArtifactCollectionFromCollectorArgs(
 	collector_request *flows_proto.ArtifactCollectorArgs,
 	vql_collector_args []*actions_proto.VQLCollectorArgs,
 	completion func()) (string, error) {
+	return self.scheduleArtifactCollection(
+		ctx, config_obj, collector_request, vql_collector_args, nil)
+}
+
+// The skipped statuses are recorded in the collection for artifacts
+// which were not sent to the client.
+func (self *Launcher) scheduleArtifactCollection(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	collector_request *flows_proto.ArtifactCollectorArgs,
+	vql_collector_args []*actions_proto.VQLCollectorArgs,
+	skipped []*crypto_proto.VeloStatus) (string, error) {
 
 	client_id := collector_request.ClientId
 	if client_id == "" {
@@ -551,10 +589,26 @@ func (self *Launcher) ScheduleArtifactCollectionFromCollectorArgs(
 
 	session_id := NewFlowId(client_id)
 
+	// Point the client at a tool cache peer in its site.
+	tool_cache_url := ""
+	if client_id != "server" && needsTools(vql_collector_args) {
+		tool_cache_url = self.tool_cache.GetPeerUrl(ctx, config_obj, client_id)
+	}
+
 	// Compile all the requests into specific tasks to be sent to the
 	// client.
 	tasks := []*crypto_proto.VeloMessage{}
 	for id, arg := range vql_collector_args {
+		// The args may be shared with other collections (e.g. in a
+		// hunt) so we must not modify them.
+		if tool_cache_url != "" {
+			arg = proto.Clone(arg).(*actions_proto.VQLCollectorArgs)
+			arg.Env = append(arg.Env, &actions_proto.VQLEnv{
+				Key:   constants.TOOL_CACHE_URL,
+				Value: tool_cache_url,
+			})
+		}
+
 		// If sending to the server record who actually launched this.
 		if client_id == "server" {
 			arg.Principal = collector_request.Creator
@@ -586,28 +640,36 @@ func (self *Launcher) ScheduleArtifactCollectionFromCollectorArgs(
 		State:               flows_proto.ArtifactCollectorContext_RUNNING,
 		Request:             collector_request,
 		ClientId:            client_id,
-		TotalRequests:       int64(len(tasks)),
+		TotalRequests:       int64(len(tasks) + len(skipped)),
 		OutstandingRequests: int64(len(tasks)),
+		QueryStats:          skipped,
 	}
 
-	// Store the collection_context first, then queue all the tasks.
-	err = db.SetSubjectWithCompletion(config_obj,
-		flow_path_manager.Path(),
-		collection_context,
+	// Nothing is left to send to the client so the collection is
+	// already complete.
+	if len(tasks) == 0 && len(skipped) > 0 {
+		return completeSkippedCollection(config_obj, collection_context)
+	}
 
-		func() {
-			// Queue and notify the client about the new tasks
-			client_manager.QueueMessagesForClient(
-				ctx, client_id, tasks, true /* notify */)
-		})
+	// Store the collection_context and the tasks (for provenance of
+	// what we actually did) together, then queue all the tasks.
+	txn := datastore.NewTxn(config_obj, db)
+	err = txn.Set(flow_path_manager.Path(), collection_context)
 	if err != nil {
 		return "", err
 	}
 
-	// Record the tasks for provenance of what we actually did.
-	err = db.SetSubjectWithCompletion(config_obj,
-		flow_path_manager.Task(),
-		&api_proto.ApiFlowRequestDetails{Items: tasks}, nil)
+	err = txn.Set(flow_path_manager.Task(),
+		&api_proto.ApiFlowRequestDetails{Items: tasks})
+	if err != nil {
+		return "", err
+	}
+
+	err = txn.CommitWithCompletion(func() {
+		// Queue and notify the client about the new tasks
+		client_manager.QueueMessagesForClient(
+			ctx, client_id, tasks, true /* notify */)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,174 @@
+package launcher
+
+// Tool cache peers are clients which serve the tools they already
+// downloaded to the other clients in their site. During tool heavy
+// hunts most clients then fetch the tools over the LAN instead of
+// from the frontend or the tool's upstream URL.
+
+// A client becomes a peer by adding the ToolCache label, collecting
+// the Generic.Client.ToolCache event artifact and setting the
+// ToolCacheUrl client metadata to the URL the artifact serves on
+// (e.g. http://10.1.1.5:8765/). Clients sharing any other label with
+// the peer are given its URL in the TOOL_CACHE_URL variable when a
+// collection needs tools. Generic.Utils.FetchBinary tries the peer
+// first and verifies the download against the tool's hash, falling
+// back to the tool's URL.
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	TOOL_CACHE_LABEL    = "ToolCache"
+	TOOL_CACHE_METADATA = "ToolCacheUrl"
+
+	// How often to look for new peers.
+	TOOL_CACHE_REFRESH = time.Minute
+)
+
+type toolCachePeer struct {
+	client_id string
+	url       string
+
+	// The labels of the sites the peer serves.
+	labels []string
+}
+
+type toolCache struct {
+	mu      sync.Mutex
+	peers   []*toolCachePeer
+	expires time.Time
+}
+
+// Find the clients labeled as tool cache peers.
+func (self *toolCache) getPeers(
+	ctx context.Context, config_obj *config_proto.Config) []*toolCachePeer {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := utils.GetTime().Now()
+	if now.Before(self.expires) {
+		return self.peers
+	}
+	self.expires = now.Add(TOOL_CACHE_REFRESH)
+	self.peers = nil
+
+	indexer, err := services.GetIndexer(config_obj)
+	if err != nil {
+		return nil
+	}
+
+	client_info_manager, err := services.GetClientInfoManager(config_obj)
+	if err != nil {
+		return nil
+	}
+
+	labeler := services.GetLabeler(config_obj)
+	if labeler == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for hit := range indexer.SearchIndexWithPrefix(
+		ctx, config_obj, "label:"+TOOL_CACHE_LABEL) {
+		client_id := hit.Entity
+		if seen[client_id] ||
+			!labeler.IsLabelSet(ctx, config_obj, client_id, TOOL_CACHE_LABEL) {
+			continue
+		}
+		seen[client_id] = true
+
+		metadata, err := client_info_manager.GetMetadata(ctx, client_id)
+		if err != nil {
+			continue
+		}
+
+		url, _ := metadata.GetString(TOOL_CACHE_METADATA)
+		if url == "" {
+			continue
+		}
+		if !strings.HasSuffix(url, "/") {
+			url += "/"
+		}
+
+		peer := &toolCachePeer{
+			client_id: client_id,
+			url:       url,
+		}
+		for _, label := range labeler.GetClientLabels(
+			ctx, config_obj, client_id) {
+			if !strings.EqualFold(label, TOOL_CACHE_LABEL) {
+				peer.labels = append(peer.labels, strings.ToLower(label))
+			}
+		}
+		self.peers = append(self.peers, peer)
+	}
+
+	return self.peers
+}
+
+// Find a peer sharing a label with the client. When a site has
+// several peers clients are spread between them.
+func (self *toolCache) GetPeerUrl(
+	ctx context.Context, config_obj *config_proto.Config,
+	client_id string) string {
+	peers := self.getPeers(ctx, config_obj)
+	if len(peers) == 0 {
+		return ""
+	}
+
+	labeler := services.GetLabeler(config_obj)
+	if labeler == nil {
+		return ""
+	}
+
+	client_labels := make(map[string]bool)
+	for _, label := range labeler.GetClientLabels(ctx, config_obj, client_id) {
+		client_labels[strings.ToLower(label)] = true
+	}
+
+	candidates := []string{}
+	for _, peer := range peers {
+		// Peers fetch their tools from the tool's URL.
+		if peer.client_id == client_id {
+			continue
+		}
+
+		for _, label := range peer.labels {
+			if client_labels[label] {
+				candidates = append(candidates, peer.url)
+				break
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(client_id))
+	return candidates[hash.Sum32()%uint32(len(candidates))]
+}
+
+// Only collections which download tools need a peer.
+func needsTools(vql_collector_args []*actions_proto.VQLCollectorArgs) bool {
+	for _, arg := range vql_collector_args {
+		for _, env := range arg.Env {
+			if strings.HasPrefix(env.Key, "Tool_") &&
+				strings.HasSuffix(env.Key, "_URL") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,404 @@
+/*
+  The lifecycle service applies the retention and forwarding policies
+  artifacts declare in their `lifecycle` section. Redaction is
+  applied as results are received (see flows/lifecycle.go).
+
+  When a collection completes:
+
+  - The results of artifacts with `forward_to` are pushed to the
+    listed server event artifacts with the ClientId, FlowId and
+    Artifact columns added. Server event artifacts watching these
+    queues can then export the results (e.g. to a SIEM or a bucket).
+
+  - If every artifact in the collection declares `retention_days`,
+    the collection is scheduled for deletion after the longest
+    retention. The schedule is kept in a result set in the filestore
+    and expired collections are deleted hourly.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	sweepPeriod = time.Hour
+
+	// Rows are forwarded in batches of this size.
+	forwardBatchSize = 1000
+)
+
+// A collection scheduled for deletion.
+type retentionRecord struct {
+	ClientId string
+	FlowId   string
+
+	// Unix time after which the collection is deleted.
+	Expires int64
+}
+
+type LifecycleService struct {
+	// Protects the retention schedule.
+	mu sync.Mutex
+
+	config_obj *config_proto.Config
+
+	Clock utils.Clock
+}
+
+func (self *LifecycleService) ProcessFlowCompletion(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	row *ordereddict.Dict) error {
+
+	flow := &flows_proto.ArtifactCollectorContext{}
+	flow_any, _ := row.Get("Flow")
+	err := utils.ParseIntoProtobuf(flow_any, flow)
+	if err != nil {
+		return err
+	}
+
+	client_id, _ := row.GetString("ClientId")
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+
+	for _, name := range flow.ArtifactsWithResults {
+		forward_to := services.GetArtifactLifecycle(config_obj, name).GetForwardTo()
+		if len(forward_to) == 0 {
+			continue
+		}
+
+		err := self.forwardResults(ctx, config_obj,
+			client_id, flow.SessionId, name, forward_to)
+		if err != nil {
+			logger.Error("LifecycleService: Forwarding %v from %v/%v: %v",
+				name, client_id, flow.SessionId, err)
+		}
+	}
+
+	retention_days := getRetentionDays(config_obj, flow.Request)
+	if retention_days == 0 {
+		return nil
+	}
+
+	return self.scheduleDeletion(config_obj, &retentionRecord{
+		ClientId: client_id,
+		FlowId:   flow.SessionId,
+		Expires: self.Clock.Now().Add(
+			time.Duration(retention_days) * 24 * time.Hour).Unix(),
+	})
+}
+
+// A collection is only deleted when all its artifacts declare a
+// retention. Returns the longest retention or 0 to keep the
+// collection.
+func getRetentionDays(config_obj *config_proto.Config,
+	request *flows_proto.ArtifactCollectorArgs) uint64 {
+	if request == nil || len(request.Artifacts) == 0 {
+		return 0
+	}
+
+	result := uint64(0)
+	for _, name := range request.Artifacts {
+		days := services.GetArtifactLifecycle(config_obj, name).GetRetentionDays()
+		if days == 0 {
+			return 0
+		}
+
+		if days > result {
+			result = days
+		}
+	}
+	return result
+}
+
+func (self *LifecycleService) forwardResults(
+	ctx context.Context, config_obj *config_proto.Config,
+	client_id, flow_id, name string, forward_to []string) error {
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	targets := []string{}
+	for _, target := range forward_to {
+		err := checkForwardTarget(config_obj, target)
+		if err != nil {
+			logger.Error("LifecycleService: Not forwarding %v: %v", name, err)
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	journal, err := services.GetJournal(config_obj)
+	if err != nil {
+		return err
+	}
+
+	path_manager, err := artifact_paths.NewArtifactPathManager(
+		config_obj, client_id, flow_id, name)
+	if err != nil {
+		return err
+	}
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	reader, err := result_sets.NewResultSetReader(
+		file_store_factory, path_manager.Path())
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	push := func(rows []*ordereddict.Dict) error {
+		for _, target := range targets {
+			err := journal.PushRowsToArtifact(
+				config_obj, rows, target, "server", "")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	rows := make([]*ordereddict.Dict, 0, forwardBatchSize)
+	for row := range reader.Rows(ctx) {
+		// The columns are set again after merging the row so the
+		// results can not spoof them.
+		forwarded := ordereddict.NewDict().
+			Set("ClientId", client_id).
+			Set("FlowId", flow_id).
+			Set("Artifact", name)
+		forwarded.MergeFrom(row)
+		forwarded.Set("ClientId", client_id).
+			Set("FlowId", flow_id).
+			Set("Artifact", name)
+		rows = append(rows, forwarded)
+
+		if len(rows) >= forwardBatchSize {
+			err := push(rows)
+			if err != nil {
+				return err
+			}
+			rows = make([]*ordereddict.Dict, 0, forwardBatchSize)
+		}
+	}
+
+	if len(rows) > 0 {
+		return push(rows)
+	}
+	return nil
+}
+
+// Results may only be forwarded to existing server event artifacts
+// other than the internal queues.
+func checkForwardTarget(config_obj *config_proto.Config, target string) error {
+	if strings.HasPrefix(target, "System.") ||
+		strings.HasPrefix(target, "Server.Internal.") {
+		return fmt.Errorf("%v is an internal artifact", target)
+	}
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return err
+	}
+
+	artifact, pres := repository.Get(config_obj, target)
+	if !pres {
+		return fmt.Errorf("Artifact %v not found", target)
+	}
+
+	if artifact.Type != "server_event" {
+		return fmt.Errorf("%v is not a server event artifact", target)
+	}
+	return nil
+}
+
+func (self *LifecycleService) scheduleDeletion(
+	config_obj *config_proto.Config, record *retentionRecord) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.RETENTION_SCHEDULE, nil, utils.SyncCompleter,
+		result_sets.AppendMode)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	writer.Write(ordereddict.NewDict().
+		Set("ClientId", record.ClientId).
+		Set("FlowId", record.FlowId).
+		Set("Expires", record.Expires))
+
+	return nil
+}
+
+func (self *LifecycleService) loadSchedule(
+	ctx context.Context) []*retentionRecord {
+	result := []*retentionRecord{}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(
+		file_store_factory, paths.RETENTION_SCHEDULE)
+	if err != nil {
+		return result
+	}
+	defer reader.Close()
+
+	for row := range reader.Rows(ctx) {
+		record := &retentionRecord{}
+		record.ClientId, _ = row.GetString("ClientId")
+		record.FlowId, _ = row.GetString("FlowId")
+		record.Expires, _ = utils.ToInt64(utils.GetAny(row, "Expires"))
+		if record.ClientId != "" && record.FlowId != "" {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// Delete the expired collections and remove them from the schedule.
+// Returns the number of deleted collections.
+func (self *LifecycleService) Sweep(ctx context.Context) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	launcher, err := services.GetLauncher(self.config_obj)
+	if err != nil {
+		return 0, err
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	now := self.Clock.Now().Unix()
+	remaining := []*retentionRecord{}
+	deleted := 0
+
+	schedule := self.loadSchedule(ctx)
+	for _, record := range schedule {
+		if record.Expires > now {
+			remaining = append(remaining, record)
+			continue
+		}
+
+		responses, err := launcher.DeleteFlow(ctx, self.config_obj,
+			record.ClientId, record.FlowId, true /* really_do_it */)
+		// The collection was already deleted.
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			// Try again on the next sweep.
+			logger.Error("LifecycleService: Deleting %v/%v: %v",
+				record.ClientId, record.FlowId, err)
+			remaining = append(remaining, record)
+			continue
+		}
+
+		for _, response := range responses {
+			if response.Error != "" {
+				logger.Debug("LifecycleService: %v", response.Error)
+			}
+		}
+		deleted++
+	}
+
+	// Nothing changed.
+	if len(remaining) == len(schedule) {
+		return 0, nil
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.RETENTION_SCHEDULE, nil, utils.SyncCompleter,
+		result_sets.TruncateMode)
+	if err != nil {
+		return deleted, err
+	}
+	defer writer.Close()
+
+	for _, record := range remaining {
+		writer.Write(ordereddict.NewDict().
+			Set("ClientId", record.ClientId).
+			Set("FlowId", record.FlowId).
+			Set("Expires", record.Expires))
+	}
+
+	return deleted, nil
+}
+
+func (self *LifecycleService) run(ctx context.Context) {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-self.Clock.After(sweepPeriod):
+		}
+
+		deleted, err := self.Sweep(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("LifecycleService: %v", err)
+		}
+
+		if deleted > 0 {
+			logger.Info("LifecycleService: Deleted %v expired collections",
+				deleted)
+		}
+	}
+}
+
+func NewLifecycleService(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Artifact Lifecycle Service for %v.",
+		services.GetOrgName(config_obj))
+
+	self := &LifecycleService{
+		config_obj: config_obj,
+		Clock:      &utils.RealClock{},
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		self.run(ctx)
+	}()
+
+	return journal.WatchQueueWithCB(ctx, config_obj, wg,
+		"System.Flow.Completion", "LifecycleService",
+		self.ProcessFlowCompletion)
+}
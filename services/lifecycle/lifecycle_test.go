@@ -0,0 +1,237 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+var lifecycleDefinitions = []string{`
+name: Test.Retained
+lifecycle:
+  retention_days: 2
+sources:
+- query: SELECT * FROM info()
+`, `
+name: Test.Kept
+sources:
+- query: SELECT * FROM info()
+`, `
+name: Test.Forwarded
+lifecycle:
+  forward_to:
+  - Test.Sink
+  - Test.Kept
+sources:
+- query: SELECT * FROM info()
+`, `
+name: Test.Sink
+type: SERVER_EVENT
+`}
+
+type LifecycleTestSuite struct {
+	test_utils.TestSuite
+
+	clock *utils.MockClock
+}
+
+func (self *LifecycleTestSuite) SetupTest() {
+	self.ConfigObj = self.LoadConfig()
+	self.LoadArtifacts(lifecycleDefinitions)
+	self.TestSuite.SetupTest()
+
+	self.clock = &utils.MockClock{MockNow: time.Unix(1600000000, 0)}
+}
+
+func (self *LifecycleTestSuite) completeFlow(
+	service *LifecycleService, flow_id string, artifacts ...string) {
+	flow := &flows_proto.ArtifactCollectorContext{
+		ClientId:  "C.1",
+		SessionId: flow_id,
+		Request: &flows_proto.ArtifactCollectorArgs{
+			ClientId:  "C.1",
+			Artifacts: artifacts,
+		},
+		State: flows_proto.ArtifactCollectorContext_FINISHED,
+	}
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	err = db.SetSubject(self.ConfigObj,
+		paths.NewFlowPathManager("C.1", flow_id).Path(), flow)
+	require.NoError(self.T(), err)
+
+	err = service.ProcessFlowCompletion(self.Ctx, self.ConfigObj,
+		ordereddict.NewDict().
+			Set("Flow", flow).
+			Set("ClientId", "C.1"))
+	require.NoError(self.T(), err)
+}
+
+func (self *LifecycleTestSuite) TestRetention() {
+	service := &LifecycleService{
+		config_obj: self.ConfigObj,
+		Clock:      self.clock,
+	}
+
+	// Only collections where all artifacts declare a retention are
+	// scheduled.
+	self.completeFlow(service, "F.1", "Test.Retained")
+	self.completeFlow(service, "F.2", "Test.Retained", "Test.Kept")
+	self.completeFlow(service, "F.3", "Test.Kept")
+
+	schedule := service.loadSchedule(self.Ctx)
+	require.Equal(self.T(), 1, len(schedule))
+	assert.Equal(self.T(), "F.1", schedule[0].FlowId)
+	assert.Equal(self.T(), self.clock.MockNow.Add(48*time.Hour).Unix(),
+		schedule[0].Expires)
+
+	// Nothing is expired yet.
+	self.clock.MockNow = self.clock.MockNow.Add(24 * time.Hour)
+	deleted, err := service.Sweep(self.Ctx)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, deleted)
+	assert.Equal(self.T(), 1, len(service.loadSchedule(self.Ctx)))
+
+	// Expired collections are deleted and removed from the schedule.
+	self.clock.MockNow = self.clock.MockNow.Add(25 * time.Hour)
+	deleted, err = service.Sweep(self.Ctx)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, deleted)
+	assert.Equal(self.T(), 0, len(service.loadSchedule(self.Ctx)))
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	flow := &flows_proto.ArtifactCollectorContext{}
+	err = db.GetSubject(self.ConfigObj,
+		paths.NewFlowPathManager("C.1", "F.1").Path(), flow)
+	assert.Error(self.T(), err)
+}
+
+// Collections which can not be deleted stay in the schedule.
+func (self *LifecycleTestSuite) TestRetentionDeleteFailed() {
+	service := &LifecycleService{
+		config_obj: self.ConfigObj,
+		Clock:      self.clock,
+	}
+
+	self.completeFlow(service, "F.1", "Test.Retained")
+
+	// The collection record is corrupted so it can not be loaded.
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	err = db.SetSubject(self.ConfigObj,
+		paths.NewFlowPathManager("C.1", "F.1").Path(),
+		&flows_proto.ArtifactCollectorContext{ClientId: "C.1"})
+	require.NoError(self.T(), err)
+
+	// A collection which is already gone is dropped.
+	err = service.scheduleDeletion(self.ConfigObj, &retentionRecord{
+		ClientId: "C.1",
+		FlowId:   "F.2",
+		Expires:  self.clock.MockNow.Unix(),
+	})
+	require.NoError(self.T(), err)
+
+	self.clock.MockNow = self.clock.MockNow.Add(49 * time.Hour)
+	deleted, err := service.Sweep(self.Ctx)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, deleted)
+
+	schedule := service.loadSchedule(self.Ctx)
+	require.Equal(self.T(), 1, len(schedule))
+	assert.Equal(self.T(), "F.1", schedule[0].FlowId)
+}
+
+// Results are only forwarded to server event artifacts and the
+// added columns can not be spoofed.
+func (self *LifecycleTestSuite) TestForwarding() {
+	var mu sync.Mutex
+	forwarded := []*ordereddict.Dict{}
+
+	err := journal.WatchQueueWithCB(self.Sm.Ctx, self.ConfigObj, self.Sm.Wg,
+		"Test.Sink", "LifecycleTestSuite", func(
+			ctx context.Context,
+			config_obj *config_proto.Config,
+			row *ordereddict.Dict) error {
+			mu.Lock()
+			defer mu.Unlock()
+			forwarded = append(forwarded, row)
+			return nil
+		})
+	require.NoError(self.T(), err)
+
+	path_manager, err := artifact_paths.NewArtifactPathManager(
+		self.ConfigObj, "C.1", "F.1", "Test.Forwarded")
+	require.NoError(self.T(), err)
+
+	writer, err := result_sets.NewResultSetWriter(
+		file_store.GetFileStore(self.ConfigObj), path_manager.Path(),
+		nil, utils.SyncCompleter, result_sets.TruncateMode)
+	require.NoError(self.T(), err)
+
+	writer.Write(ordereddict.NewDict().
+		Set("ClientId", "C.Spoofed").
+		Set("Value", 1))
+	writer.Close()
+
+	service := &LifecycleService{
+		config_obj: self.ConfigObj,
+		Clock:      self.clock,
+	}
+
+	flow := &flows_proto.ArtifactCollectorContext{
+		ClientId:             "C.1",
+		SessionId:            "F.1",
+		ArtifactsWithResults: []string{"Test.Forwarded"},
+	}
+	err = service.ProcessFlowCompletion(self.Ctx, self.ConfigObj,
+		ordereddict.NewDict().
+			Set("Flow", flow).
+			Set("ClientId", "C.1"))
+	require.NoError(self.T(), err)
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(forwarded) == 1
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	client_id, _ := forwarded[0].GetString("ClientId")
+	assert.Equal(self.T(), "C.1", client_id)
+
+	artifact, _ := forwarded[0].GetString("Artifact")
+	assert.Equal(self.T(), "Test.Forwarded", artifact)
+
+	assert.Error(self.T(), checkForwardTarget(self.ConfigObj, "Test.Kept"))
+	assert.Error(self.T(), checkForwardTarget(self.ConfigObj, "Test.Missing"))
+	assert.Error(self.T(), checkForwardTarget(
+		self.ConfigObj, "Server.Internal.ArtifactModification"))
+}
+
+func TestLifecycleService(t *testing.T) {
+	suite.Run(t, &LifecycleTestSuite{})
+}
@@ -15,6 +15,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/reporting"
 	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
 )
 
@@ -67,6 +68,19 @@ func (self *NotebookManager) UpdateNotebookCell(
 
 	tmpl.SetEnv("NotebookId", in.NotebookId)
 
+	// Abort the cell if its queries hold too much memory.
+	max_memory := in.MaxMemory
+	if max_memory == 0 && self.config_obj.Defaults != nil {
+		max_memory = self.config_obj.Defaults.NotebookCellMaxMemory
+	}
+	if max_memory > 0 {
+		vql_subsystem.InstallMemoryBudget(tmpl.Scope,
+			vql_subsystem.NewMemoryBudget(max_memory, func(err error) {
+				tmpl.Scope.Log("ERROR:%v", err)
+				query_cancel()
+			}))
+	}
+
 	// Register a progress reporter so we can monitor how the
 	// template rendering is going.
 	tmpl.Progress = &progressReporter{
@@ -59,6 +59,8 @@ type ServiceContainer interface {
 	ServerEventManager() (ServerEventManager, error)
 	Notifier() (Notifier, error)
 	ACLManager() (ACLManager, error)
+	JobManager() (JobManager, error)
+	CanaryManager() (CanaryManager, error)
 }
 
 // The org manager manages multi-tenancies.
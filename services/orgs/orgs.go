@@ -188,6 +188,17 @@ func (self *OrgManager) makeNewConfigObj(
 			result.Datastore.Location, "orgs", record.Id)
 		result.Datastore.FilestoreDirectory = filepath.Join(
 			result.Datastore.FilestoreDirectory, "orgs", record.Id)
+
+		for idx, location := range result.Datastore.ShardLocations {
+			result.Datastore.ShardLocations[idx] = filepath.Join(
+				location, "orgs", record.Id)
+		}
+
+		tiering := result.Datastore.Tiering
+		if tiering != nil && tiering.ColdDirectory != "" {
+			tiering.ColdDirectory = filepath.Join(
+				tiering.ColdDirectory, "orgs", record.Id)
+		}
 	}
 
 	return result
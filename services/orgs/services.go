@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
@@ -13,24 +14,30 @@ import (
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/acl_manager"
 	"www.velocidex.com/golang/velociraptor/services/broadcast"
+	"www.velocidex.com/golang/velociraptor/services/canary"
 	"www.velocidex.com/golang/velociraptor/services/client_info"
 	"www.velocidex.com/golang/velociraptor/services/client_monitoring"
 	"www.velocidex.com/golang/velociraptor/services/ddclient"
+	"www.velocidex.com/golang/velociraptor/services/deduplicator"
 	"www.velocidex.com/golang/velociraptor/services/frontend"
 	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
 	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
 	"www.velocidex.com/golang/velociraptor/services/indexing"
 	"www.velocidex.com/golang/velociraptor/services/interrogation"
 	"www.velocidex.com/golang/velociraptor/services/inventory"
+	"www.velocidex.com/golang/velociraptor/services/jobs"
 	"www.velocidex.com/golang/velociraptor/services/journal"
 	"www.velocidex.com/golang/velociraptor/services/labels"
 	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/services/lifecycle"
 	"www.velocidex.com/golang/velociraptor/services/notebook"
 	"www.velocidex.com/golang/velociraptor/services/notifications"
 	"www.velocidex.com/golang/velociraptor/services/repository"
 	"www.velocidex.com/golang/velociraptor/services/sanity"
+	"www.velocidex.com/golang/velociraptor/services/scrubber"
 	"www.velocidex.com/golang/velociraptor/services/server_artifacts"
 	"www.velocidex.com/golang/velociraptor/services/server_monitoring"
+	"www.velocidex.com/golang/velociraptor/services/sessions"
 	"www.velocidex.com/golang/velociraptor/services/users"
 	"www.velocidex.com/golang/velociraptor/services/vfs_service"
 	"www.velocidex.com/golang/velociraptor/utils"
@@ -55,6 +62,8 @@ type ServiceContainer struct {
 	server_event_manager services.ServerEventManager
 	notifier             services.Notifier
 	acl_manager          services.ACLManager
+	job_manager          services.JobManager
+	canary_manager       services.CanaryManager
 }
 
 func (self *ServiceContainer) MockFrontendManager(svc services.FrontendManager) {
@@ -118,6 +127,28 @@ func (self *ServiceContainer) NotebookManager() (services.NotebookManager, error
 	return self.notebook_manager, nil
 }
 
+func (self *ServiceContainer) JobManager() (services.JobManager, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.job_manager == nil {
+		return nil, errors.New("Job Manager service not initialized")
+	}
+
+	return self.job_manager, nil
+}
+
+func (self *ServiceContainer) CanaryManager() (services.CanaryManager, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.canary_manager == nil {
+		return nil, errors.New("Canary Manager service not initialized")
+	}
+
+	return self.canary_manager, nil
+}
+
 func (self *ServiceContainer) Launcher() (services.Launcher, error) {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -291,12 +322,17 @@ func (self *OrgManager) startRootOrgServices(
 		service_container.mu.Unlock()
 	}
 
-	// The user manager is global across all orgs.
+	// The user and session managers are global across all orgs.
 	if spec.UserManager {
 		err := users.StartUserManager(ctx, wg, org_config)
 		if err != nil {
 			return err
 		}
+
+		err = sessions.StartSessionManager(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = ddclient.StartDynDNSService(
@@ -417,13 +453,15 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 			return err
 		}
 
-		err = repository.LoadArtifactsFromConfig(repo_manager, org_config)
-		if err != nil {
-			return err
-		}
-
-		// The Root org will contain all the built in artifacts
+		// The Root org will contain all the built in artifacts. Other
+		// orgs inherit them from the root org's repository and may
+		// override them without affecting other orgs.
 		if org_id == "" {
+			err = repository.LoadArtifactsFromConfig(repo_manager, org_config)
+			if err != nil {
+				return err
+			}
+
 			// Assume the built in artifacts are OK so we dont need to
 			// validate them at runtime.
 			err = repository.LoadBuiltInArtifacts(ctx, org_config,
@@ -438,6 +476,21 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 			if err != nil {
 				return err
 			}
+
+			if org_config.Frontend != nil &&
+				org_config.Frontend.ArtifactRepositoryUrl != "" {
+				global_repository, err := repo_manager.GetGlobalRepository(
+					org_config)
+				if err != nil {
+					return err
+				}
+
+				err = repository.StartRemoteRepositorySync(
+					ctx, wg, org_config, global_repository)
+				if err != nil {
+					return err
+				}
+			}
 		} else {
 			root_org_config, _ := self.GetOrgConfig("")
 			root_repo_manager, _ := self.Services("").RepositoryManager()
@@ -501,6 +554,27 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 		}
 	}
 
+	if spec.UploadDeduplicator {
+		err = deduplicator.NewUploadDeduplicator(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	if spec.FilestoreScrubber {
+		err = scrubber.NewFilestoreScrubber(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	if spec.ArtifactLifecycle {
+		err = lifecycle.NewLifecycleService(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+	}
+
 	if spec.ClientInfo {
 		c := client_info.NewClientInfoManager(org_config)
 		err = c.Start(ctx, org_config, wg)
@@ -559,6 +633,28 @@ func (self *OrgManager) startOrgFromContext(org_ctx *OrgContext) (err error) {
 		service_container.mu.Unlock()
 	}
 
+	if spec.JobManager {
+		job_manager, err := jobs.NewJobManager(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+
+		service_container.mu.Lock()
+		service_container.job_manager = job_manager
+		service_container.mu.Unlock()
+	}
+
+	if spec.CanaryManager {
+		canary_manager, err := canary.NewCanaryManager(ctx, wg, org_config)
+		if err != nil {
+			return err
+		}
+
+		service_container.mu.Lock()
+		service_container.canary_manager = canary_manager
+		service_container.mu.Unlock()
+	}
+
 	if spec.ServerArtifacts {
 		err = server_artifacts.NewServerArtifactService(ctx, wg, org_config)
 		if err != nil {
@@ -632,14 +728,27 @@ func maybeFlushFilesOnClose(
 		return err
 	}
 
-	flusher, ok = db.(Flusher)
+	db_flusher, ok := db.(DatastoreFlusher)
 	if ok {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			<-ctx.Done()
-			if flusher != nil {
-				flusher.Flush()
+
+			// The org context is already done so give the flush its
+			// own deadline.
+			timeout := org_config.Datastore.FlushTimeoutSec
+			if timeout == 0 {
+				timeout = 60
+			}
+			flush_ctx, cancel := context.WithTimeout(context.Background(),
+				time.Duration(timeout)*time.Second)
+			defer cancel()
+
+			err := db_flusher.Flush(flush_ctx)
+			if err != nil {
+				logger := logging.GetLogger(org_config, &logging.FrontendComponent)
+				logger.Error("Flushing datastore on shutdown: %v", err)
 			}
 		}()
 	}
@@ -661,3 +770,8 @@ func (self *OrgManager) Services(org_id string) services.ServiceContainer {
 type Flusher interface {
 	Flush()
 }
+
+// Datastores that delay writes flush them within the deadline.
+type DatastoreFlusher interface {
+	Flush(ctx context.Context) error
+}
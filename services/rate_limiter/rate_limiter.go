@@ -0,0 +1,222 @@
+// Named rate limiters shared between all queries on the server.
+
+// Some VQL plugins call out to external services (e.g. http_client()
+// querying a third party API or elastic_upload()). These services
+// often impose a global limit on the number of requests we may make,
+// but each notebook or artifact runs its own independent queries
+// which do not know about each other.
+
+// Rate limiters are defined in the config file under
+// Defaults.rate_limiters and are referred to by name from VQL. Each
+// limiter is a token bucket: Tokens are replenished at `rate` per
+// second up to `burst` and each request consumes a single token. The
+// bucket state is persisted in the datastore so a restart does not
+// reset the bucket to full.
+
+package rate_limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	mu       sync.Mutex
+	limiters = make(map[string]*RateLimiter)
+
+	// Do not write the bucket state to the datastore more often
+	// than this.
+	persistInterval = 10 * time.Second
+)
+
+type RateLimiter struct {
+	mu sync.Mutex
+
+	name  string
+	rate  float64
+	burst float64
+
+	tokens float64
+	last   time.Time
+
+	// When the state was last persisted.
+	last_persisted time.Time
+
+	config_obj *config_proto.Config
+}
+
+// Take a single token from the bucket, waiting until one becomes
+// available or the context is cancelled.
+func (self *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := self.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-utils.GetTime().After(delay):
+		}
+	}
+}
+
+// Try to take a token. Returns 0 if a token was taken or the time to
+// wait until the next token becomes available.
+func (self *RateLimiter) reserve() time.Duration {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := utils.GetTime().Now()
+	self.refill(now)
+
+	if self.tokens >= 1 {
+		self.tokens--
+		self.maybePersist(now)
+		return 0
+	}
+
+	missing := 1 - self.tokens
+	return time.Duration(missing / self.rate * float64(time.Second))
+}
+
+func (self *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(self.last).Seconds()
+	if elapsed > 0 {
+		self.tokens = math.Min(self.burst, self.tokens+elapsed*self.rate)
+		self.last = now
+	}
+}
+
+func (self *RateLimiter) maybePersist(now time.Time) {
+	if now.Sub(self.last_persisted) < persistInterval {
+		return
+	}
+	self.last_persisted = now
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return
+	}
+
+	path_manager := paths.ServerStatePathManager{}
+	_ = db.SetSubjectWithCompletion(self.config_obj,
+		path_manager.RateLimiter(self.name),
+		&api_proto.RateLimiterState{
+			Name:      self.name,
+			Tokens:    self.tokens,
+			Timestamp: now.UnixNano() / 1000,
+		}, nil)
+}
+
+// Restore the previous bucket state from the datastore if possible.
+func (self *RateLimiter) load() {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return
+	}
+
+	path_manager := paths.ServerStatePathManager{}
+	state := &api_proto.RateLimiterState{}
+	err = db.GetSubject(self.config_obj,
+		path_manager.RateLimiter(self.name), state)
+	if err != nil || state.Timestamp == 0 {
+		return
+	}
+
+	self.tokens = math.Min(self.burst, state.Tokens)
+	self.last = time.Unix(0, state.Timestamp*1000)
+	self.refill(utils.GetTime().Now())
+}
+
+func newRateLimiter(
+	config_obj *config_proto.Config,
+	definition *config_proto.RateLimiterConfig) (*RateLimiter, error) {
+	if definition.Rate <= 0 {
+		return nil, fmt.Errorf("Rate limiter %v: rate must be positive",
+			definition.Name)
+	}
+
+	burst := float64(definition.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	now := utils.GetTime().Now()
+	result := &RateLimiter{
+		name:           definition.Name,
+		rate:           definition.Rate,
+		burst:          burst,
+		tokens:         burst,
+		last:           now,
+		last_persisted: now,
+		config_obj:     config_obj,
+	}
+	result.load()
+
+	return result, nil
+}
+
+// Get the named rate limiter from the config. Limiters are shared
+// across all callers in the same org.
+func GetRateLimiter(
+	config_obj *config_proto.Config, name string) (*RateLimiter, error) {
+	if config_obj == nil {
+		return nil, errors.New("Rate limiters are only available on the server")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := utils.NormalizedOrgId(config_obj.OrgId) + "/" + name
+	result, pres := limiters[key]
+	if pres {
+		return result, nil
+	}
+
+	if config_obj.Defaults != nil {
+		for _, definition := range config_obj.Defaults.RateLimiters {
+			if definition.Name != name {
+				continue
+			}
+
+			result, err := newRateLimiter(config_obj, definition)
+			if err != nil {
+				return nil, err
+			}
+			limiters[key] = result
+			return result, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Rate limiter %v is not defined", name)
+}
+
+// Block until the named rate limiter allows another request.
+func Wait(ctx context.Context,
+	config_obj *config_proto.Config, name string) error {
+	limiter, err := GetRateLimiter(config_obj, name)
+	if err != nil {
+		return err
+	}
+	return limiter.Wait(ctx)
+}
+
+// Forget all limiters - used in tests.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	limiters = make(map[string]*RateLimiter)
+}
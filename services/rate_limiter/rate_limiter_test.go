@@ -0,0 +1,60 @@
+package rate_limiter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services/rate_limiter"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type RateLimiterTestSuite struct {
+	test_utils.TestSuite
+	clock *utils.MockClock
+}
+
+func (self *RateLimiterTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.ConfigObj.Defaults.RateLimiters = []*config_proto.RateLimiterConfig{{
+		Name:  "test",
+		Rate:  1,
+		Burst: 2,
+	}}
+	self.clock = &utils.MockClock{MockNow: time.Unix(100, 0)}
+	rate_limiter.Reset()
+}
+
+func (self *RateLimiterTestSuite) TestTokenBucket() {
+	closer := utils.MockTime(self.clock)
+	defer closer()
+
+	limiter, err := rate_limiter.GetRateLimiter(self.ConfigObj, "test")
+	require.NoError(self.T(), err)
+
+	ctx := context.Background()
+
+	// The bucket starts full so the burst goes through immediately.
+	for i := 0; i < 2; i++ {
+		assert.NoError(self.T(), limiter.Wait(ctx))
+	}
+	assert.Equal(self.T(), int64(100), self.clock.Now().Unix())
+
+	// The next call has to wait for a token to be replenished.
+	assert.NoError(self.T(), limiter.Wait(ctx))
+	assert.Equal(self.T(), int64(101), self.clock.Now().Unix())
+
+	// Undefined limiters are an error.
+	_, err = rate_limiter.GetRateLimiter(self.ConfigObj, "undefined")
+	assert.Error(self.T(), err)
+}
+
+func TestRateLimiter(t *testing.T) {
+	suite.Run(t, &RateLimiterTestSuite{})
+}
@@ -27,13 +27,18 @@ package services
 
 import (
 	"context"
+	"errors"
 	"log"
+	"strings"
 
 	"github.com/Velocidex/ordereddict"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/artifacts"
 	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/uploads"
+	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/vfilter"
 )
@@ -43,6 +48,12 @@ const (
 	ArtifactIsBuiltIn = true
 )
 
+var (
+	ArtifactVersionNotFoundError = errors.New("Artifact version not found")
+	ArtifactNotStagedError       = errors.New("Artifact is not staged")
+	InvalidArtifactNameError     = errors.New("Invalid artifact name")
+)
+
 func GetRepositoryManager(config_obj *config_proto.Config) (RepositoryManager, error) {
 	org_manager, err := GetOrgManager()
 	if err != nil {
@@ -98,8 +109,17 @@ type Repository interface {
 	// Remove a named artifact from the repository.
 	Del(name string)
 
+	// Is the artifact defined by the org itself? Org repositories
+	// are layered over the root org's repository so the org's own
+	// definitions override the global ones for that org only.
+	IsOrgArtifact(name string) bool
+
 	// List
 	List(ctx context.Context, config_obj *config_proto.Config) ([]string, error)
+
+	// An opaque version that changes whenever the repository is
+	// modified.
+	Version() string
 }
 
 // Manages the global artifact repository
@@ -140,6 +160,63 @@ type RepositoryManager interface {
 	// Delete the file from the global repository and the data store.
 	DeleteArtifactFile(config_obj *config_proto.Config,
 		principal, name string) error
+
+	// Every change made by SetArtifactFile and DeleteArtifactFile
+	// is recorded in the artifact's version history. Versions are
+	// listed oldest first.
+	ListArtifactVersions(config_obj *config_proto.Config,
+		name string) ([]*api_proto.ArtifactVersion, error)
+
+	GetArtifactVersion(config_obj *config_proto.Config,
+		name string, version uint64) (*api_proto.ArtifactVersion, error)
+
+	// Restore the artifact to a previous version. This records a
+	// new version so the rollback itself may be undone.
+	RollbackArtifact(config_obj *config_proto.Config,
+		principal, name string, version uint64) (*artifacts_proto.Artifact, error)
+
+	// Stage the latest version of the artifact: hunts collecting it
+	// are limited to clients with the staging label until the bake
+	// period passes. Empty label and 0 bake_sec use the defaults
+	// from the config.
+	StageArtifact(config_obj *config_proto.Config,
+		principal, name, label string, bake_sec uint64) (
+		*api_proto.ArtifactStaging, error)
+
+	// Returns ArtifactNotStagedError if the artifact is not staged
+	// or its bake period has passed.
+	GetArtifactStaging(config_obj *config_proto.Config,
+		name string) (*api_proto.ArtifactStaging, error)
+
+	// Lists artifacts which are still baking.
+	ListStagedArtifacts(config_obj *config_proto.Config) (
+		[]*api_proto.ArtifactStaging, error)
+
+	// Make a staged artifact available to the whole fleet now.
+	PromoteArtifact(config_obj *config_proto.Config,
+		principal, name string) error
+
+	// Undo the staged change, restoring the version before it (or
+	// deleting the artifact if it was new).
+	RollbackStagedArtifact(config_obj *config_proto.Config,
+		principal, name string) error
+
+	// A cache of compiled artifacts. Compiling an artifact is a
+	// pure function of the key so the launcher can reuse the
+	// result (e.g. when scheduling a hunt on many clients). The
+	// cache returns copies which callers may modify.
+	GetCompiledArtifact(key string) ([]*actions_proto.VQLCollectorArgs, bool)
+	SetCompiledArtifact(key string, compiled []*actions_proto.VQLCollectorArgs)
+
+	// Each distinct set of columns produced by an artifact source
+	// is recorded as a new schema version. Returns the version
+	// matching the columns.
+	RecordResultSchema(config_obj *config_proto.Config,
+		name string, columns []string) (*api_proto.ArtifactSchema, error)
+
+	// Schema versions are listed oldest first.
+	ListResultSchemas(config_obj *config_proto.Config,
+		name string) ([]*api_proto.ArtifactSchema, error)
 }
 
 type MockablePlugin interface {
@@ -171,3 +248,67 @@ func ScopeBuilderFromScope(scope vfilter.Scope) ScopeBuilder {
 
 	return result
 }
+
+// Does the artifact cover the MITRE ATT&CK technique? A technique
+// (e.g. T1003) also matches its sub-techniques (e.g. T1003.001).
+func HasAttackTechnique(
+	artifact *artifacts_proto.Artifact, technique string) bool {
+	technique = strings.ToUpper(strings.TrimSpace(technique))
+	for _, item := range artifact.AttackTechniques {
+		if item == technique || strings.HasPrefix(item, technique+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve the artifact to use for name. When custom overrides are
+// allowed the resolution order is: the org's own definition, then a
+// Custom. override and finally the built in artifact.
+func ResolveArtifact(config_obj *config_proto.Config,
+	repository Repository, name string,
+	allow_custom_overrides bool) (*artifacts_proto.Artifact, bool) {
+	if allow_custom_overrides && !repository.IsOrgArtifact(name) {
+		artifact, pres := repository.Get(config_obj, "Custom."+name)
+		if pres {
+			return artifact, true
+		}
+	}
+
+	return repository.Get(config_obj, name)
+}
+
+// Get the lifecycle policy of the artifact which produced the named
+// query (e.g. Artifact/Source). Returns nil if the artifact declares
+// no policy.
+func GetArtifactLifecycle(config_obj *config_proto.Config,
+	name string) *artifacts_proto.ArtifactLifecycle {
+	manager, err := GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil
+	}
+
+	artifact_name := strings.SplitN(name, "/", 2)[0]
+	artifact, pres := repository.Get(config_obj, artifact_name)
+	if !pres {
+		return nil
+	}
+	return artifact.Lifecycle
+}
+
+// Merge the columns of all the schema versions of an artifact so
+// results from any version can be shown together. The newest
+// version's columns come first, followed by columns which only older
+// versions produced.
+func MergeSchemaColumns(schemas []*api_proto.ArtifactSchema) []string {
+	result := []string{}
+	for i := len(schemas) - 1; i >= 0; i-- {
+		result = utils.MergeStringSlices(result, schemas[i].Columns)
+	}
+	return result
+}
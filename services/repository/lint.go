@@ -0,0 +1,406 @@
+package repository
+
+// Static checks for artifact definitions. These complement the
+// validation performed when loading an artifact (YAML and VQL syntax)
+// with checks on the artifact's parameters which are otherwise only
+// discovered when the artifact is collected.
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	LINT_ERROR   = "error"
+	LINT_WARNING = "warning"
+)
+
+var (
+	// All the parameter types understood by the compiler and the
+	// GUI.
+	knownParameterTypes = map[string]bool{
+		"": true, "string": true, "regex": true, "yara": true,
+		"upload": true, "upload_file": true, "int": true,
+		"int64": true, "integer": true, "float": true,
+		"timestamp": true, "starlark": true, "csv": true,
+		"artifactset": true, "json": true, "json_array": true,
+		"regex_array": true, "xml": true, "yaml": true, "bool": true,
+		"choices": true, "multichoice": true, "hidden": true,
+		"server_metadata": true, "redacted": true,
+	}
+
+	// Symbols which are always available to artifact queries.
+	builtinSymbols = map[string]bool{
+		"clientid": true, "flowid": true, "huntid": true,
+		"artifactname": true, "true": true, "false": true,
+		"null": true, "_value": true, "_key": true, "_": true,
+		"scope": true, "config": true, "orgid": true,
+	}
+
+	vqlKeywords = map[string]bool{
+		"select": true, "from": true, "where": true, "let": true,
+		"as": true, "and": true, "or": true, "not": true, "in": true,
+		"group": true, "by": true, "order": true, "limit": true,
+		"desc": true, "explain": true,
+	}
+
+	aliasRegex = regexp.MustCompile(`(?i)\bAS\s+(` + "`[^`]+`" +
+		`|[a-zA-Z_][a-zA-Z0-9_]*)`)
+)
+
+type LintIssue struct {
+	File     string `json:"file,omitempty"`
+	Artifact string `json:"artifact"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Check the artifact's parameters: their types and defaults must be
+// valid, every parameter should be used by the artifact's queries
+// and arguments should not refer to symbols which are not defined.
+func LintArtifact(artifact *artifacts_proto.Artifact) []*LintIssue {
+	var result []*LintIssue
+	report := func(severity, format string, args ...interface{}) {
+		result = append(result, &LintIssue{
+			Artifact: artifact.Name,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	parameters := make(map[string]bool)
+	for _, parameter := range artifact.Parameters {
+		if parameter.Name == "" {
+			report(LINT_ERROR, "Parameter with no name")
+			continue
+		}
+
+		if parameters[strings.ToLower(parameter.Name)] {
+			report(LINT_ERROR, "Parameter %v is defined more than once",
+				parameter.Name)
+		}
+		parameters[strings.ToLower(parameter.Name)] = true
+
+		if !knownParameterTypes[parameter.Type] {
+			report(LINT_ERROR, "Parameter %v has unknown type %v",
+				parameter.Name, parameter.Type)
+			continue
+		}
+
+		err := checkParameterDefault(parameter)
+		if err != nil {
+			report(LINT_ERROR, "Parameter %v has an invalid default: %v",
+				parameter.Name, err)
+		}
+	}
+
+	err := artifacts.ValidateParameterDependencies(artifact)
+	if err != nil {
+		report(LINT_ERROR, "%v", err)
+	}
+
+	// Gather all the VQL in the artifact.
+	var queries []string
+	for _, query := range []string{
+		artifact.Precondition, artifact.Export, artifact.Cleanup} {
+		if query != "" {
+			queries = append(queries, query)
+		}
+	}
+
+	for _, source := range artifact.Sources {
+		for _, query := range []string{source.Precondition, source.Query} {
+			if query != "" {
+				queries = append(queries, query)
+			}
+		}
+		queries = append(queries, source.Queries...)
+	}
+
+	// Symbols defined by the queries themselves.
+	defined := make(map[string]bool)
+	var tokens [][]vqlToken
+	for _, query := range queries {
+		vqls, err := vfilter.MultiParse(query)
+		if err != nil {
+			report(LINT_ERROR, "Invalid VQL: %v", err)
+			continue
+		}
+
+		for _, vql := range vqls {
+			if vql.Let != "" {
+				defined[strings.ToLower(vql.Let)] = true
+			}
+		}
+
+		for _, match := range aliasRegex.FindAllStringSubmatch(query, -1) {
+			defined[strings.ToLower(strings.Trim(match[1], "`"))] = true
+		}
+
+		query_tokens := tokenizeVQL(query)
+		for _, column := range selectedColumns(query_tokens) {
+			defined[strings.ToLower(column)] = true
+		}
+		tokens = append(tokens, query_tokens)
+	}
+
+	referenced := make(map[string]bool)
+	undefined := make(map[string]bool)
+	for _, query_tokens := range tokens {
+		for idx, token := range query_tokens {
+			if !token.isIdent() || vqlKeywords[strings.ToLower(token.text)] {
+				continue
+			}
+
+			// Field access (x.Field) or calls (func()).
+			if idx > 0 && query_tokens[idx-1].text == "." ||
+				idx < len(query_tokens)-1 && query_tokens[idx+1].text == "(" {
+				continue
+			}
+
+			name := strings.ToLower(strings.Trim(token.text, "`"))
+			referenced[name] = true
+
+			// Only arguments passed by keyword (arg=Symbol) are
+			// checked because other symbols may be columns.
+			if idx < 2 || query_tokens[idx-1].text != "=" ||
+				!query_tokens[idx-2].isIdent() || !token.inCall {
+				continue
+			}
+
+			if idx < len(query_tokens)-1 {
+				next := query_tokens[idx+1].text
+				if next != "," && next != ")" {
+					continue
+				}
+			}
+
+			if !parameters[name] && !defined[name] && !builtinSymbols[name] &&
+				!strings.HasPrefix(name, "tool_") {
+				undefined[token.text] = true
+			}
+		}
+	}
+
+	for _, parameter := range artifact.Parameters {
+		if parameter.Name != "" &&
+			!referenced[strings.ToLower(parameter.Name)] {
+			report(LINT_WARNING, "Parameter %v is not used by any query",
+				parameter.Name)
+		}
+	}
+
+	var undefined_names []string
+	for name := range undefined {
+		undefined_names = append(undefined_names, name)
+	}
+	sort.Strings(undefined_names)
+
+	for _, name := range undefined_names {
+		report(LINT_WARNING,
+			"Symbol %v is passed as an argument but is not a parameter "+
+				"or defined by a LET", name)
+	}
+
+	return result
+}
+
+func checkParameterDefault(parameter *artifacts_proto.ArtifactParameter) error {
+	value := parameter.Default
+	if value == "" {
+		return nil
+	}
+
+	switch parameter.Type {
+	case "int", "int64", "integer":
+		_, err := strconv.ParseInt(value, 0, 64)
+		return err
+
+	case "float":
+		_, err := strconv.ParseFloat(value, 64)
+		return err
+
+	case "bool":
+		switch strings.ToUpper(value) {
+		case "Y", "N", "TRUE", "FALSE", "YES", "NO", "OK":
+			return nil
+		}
+		return fmt.Errorf("%v is not a boolean", value)
+
+	case "regex":
+		_, err := regexp.Compile(value)
+		return err
+
+	case "json", "json_array", "regex_array":
+		var result interface{}
+		return json.Unmarshal([]byte(value), &result)
+
+	case "choices":
+		for _, choice := range parameter.Choices {
+			if choice == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not one of the choices", value)
+	}
+
+	return nil
+}
+
+type vqlToken struct {
+	text string
+
+	// The token is directly inside the parentheses of a call.
+	inCall bool
+}
+
+func (self vqlToken) isIdent() bool {
+	if self.text == "" {
+		return false
+	}
+
+	c := self.text[0]
+	return c == '`' || c == '_' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// A simple tokenizer which is good enough to find symbol
+// references. String literals and comments are dropped.
+func tokenizeVQL(query string) []vqlToken {
+	var result []vqlToken
+
+	// For each open bracket whether it is a call.
+	var stack []bool
+	inCall := func() bool {
+		return len(stack) > 0 && stack[len(stack)-1]
+	}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case strings.HasPrefix(query[i:], "//") ||
+			strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return result
+			}
+			i += end
+
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i:], "*/")
+			if end < 0 {
+				return result
+			}
+			i += end + 2
+
+		case strings.HasPrefix(query[i:], "'''"):
+			end := strings.Index(query[i+3:], "'''")
+			if end < 0 {
+				return result
+			}
+			i += end + 6
+			result = append(result, vqlToken{text: "''", inCall: inCall()})
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(query) && query[j] != c {
+				if query[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			i = j + 1
+			result = append(result, vqlToken{text: "''", inCall: inCall()})
+
+		case c == '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				return result
+			}
+			result = append(result, vqlToken{
+				text: query[i : i+end+2], inCall: inCall()})
+			i += end + 2
+
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9'):
+			j := i
+			for j < len(query) && (query[j] == '_' ||
+				(query[j] >= 'a' && query[j] <= 'z') ||
+				(query[j] >= 'A' && query[j] <= 'Z') ||
+				(query[j] >= '0' && query[j] <= '9')) {
+				j++
+			}
+			result = append(result, vqlToken{
+				text: query[i:j], inCall: inCall()})
+			i = j
+
+		default:
+			switch c {
+			case '(':
+				is_call := false
+				if len(result) > 0 {
+					last := result[len(result)-1]
+					is_call = last.isIdent() &&
+						!vqlKeywords[strings.ToLower(last.text)]
+				}
+				stack = append(stack, is_call)
+			case '{', '[':
+				stack = append(stack, false)
+			case ')', '}', ']':
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			}
+			result = append(result, vqlToken{
+				text: string(c), inCall: inCall()})
+			i++
+		}
+	}
+
+	return result
+}
+
+// Bare symbols in a SELECT clause name columns of the plugin which
+// may be referred to later (e.g. in a foreach query).
+func selectedColumns(tokens []vqlToken) []string {
+	var result []string
+	in_select := false
+	for idx, token := range tokens {
+		switch strings.ToUpper(token.text) {
+		case "SELECT":
+			in_select = true
+			continue
+		case "FROM":
+			in_select = false
+			continue
+		}
+
+		if !in_select || !token.isIdent() {
+			continue
+		}
+
+		if idx+1 < len(tokens) {
+			next := tokens[idx+1].text
+			if next != "," && !strings.EqualFold(next, "FROM") {
+				continue
+			}
+		}
+
+		if idx > 0 && tokens[idx-1].text == "." {
+			continue
+		}
+		result = append(result, strings.Trim(token.text, "`"))
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/Velocidex/yaml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+)
+
+var lintTestArtifact = `
+name: Custom.Lint
+parameters:
+- name: Glob
+  default: /etc/*
+- name: MaxSize
+  type: int
+  default: ten
+- name: Unused
+- name: Mode
+  type: choices
+  default: Slow
+  choices:
+    - Fast
+- name: Weird
+  type: strange
+sources:
+- query: |
+    LET Files = SELECT OSPath FROM glob(globs=Glob)
+
+    // Accessor is not defined. Regex is not checked since it may
+    // be a column.
+    SELECT * FROM foreach(row=Files, query={
+       SELECT OSPath, Size AS FileSize
+       FROM stat(filename=OSPath, accessor=Accessor)
+       WHERE Size < MaxSize AND OSPath =~ Regex AND Mode = "Fast"
+    })
+`
+
+func TestLintArtifact(t *testing.T) {
+	artifact := &artifacts_proto.Artifact{}
+	err := yaml.UnmarshalStrict([]byte(lintTestArtifact), artifact)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, issue := range LintArtifact(artifact) {
+		messages = append(messages, issue.Severity+": "+issue.Message)
+	}
+
+	assert.Equal(t, []string{
+		"error: Parameter MaxSize has an invalid default: strconv.ParseInt: parsing \"ten\": invalid syntax",
+		"error: Parameter Mode has an invalid default: Slow is not one of the choices",
+		"error: Parameter Weird has unknown type strange",
+		"warning: Parameter Unused is not used by any query",
+		"warning: Parameter Weird is not used by any query",
+		"warning: Symbol Accessor is passed as an argument but is not a parameter or defined by a LET",
+	}, messages)
+}
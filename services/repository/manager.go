@@ -10,6 +10,9 @@ import (
 	"time"
 
 	"github.com/Velocidex/ordereddict"
+	"google.golang.org/protobuf/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/artifacts/assets"
 	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
@@ -26,11 +29,68 @@ type RepositoryManager struct {
 	global_repository *Repository
 	wg                *sync.WaitGroup
 	config_obj        *config_proto.Config
+
+	// Verifies artifact signatures when signed artifacts are required.
+	verifier *ArtifactVerifier
+
+	// Compiled artifacts keyed by the launcher's cache key.
+	compiled_cache map[string][]*actions_proto.VQLCollectorArgs
+
+	// Known schema versions keyed by artifact and fingerprint.
+	// Recording a new version reads and writes the datastore so it
+	// is serialized by its own lock rather than mu.
+	schema_mu    sync.Mutex
+	schema_cache map[string]*api_proto.ArtifactSchema
+
+	// The latest recorded version of each artifact.
+	latest_versions map[string]*api_proto.ArtifactVersion
+}
+
+// Keys include the repository version so entries for older versions
+// are never used again. Rather than tracking them we just start again
+// when the cache is full.
+const maxCompiledCacheSize = 1000
+
+func (self *RepositoryManager) GetCompiledArtifact(key string) (
+	[]*actions_proto.VQLCollectorArgs, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	cached, pres := self.compiled_cache[key]
+	if !pres {
+		return nil, false
+	}
+
+	return copyCollectorArgs(cached), true
+}
+
+func (self *RepositoryManager) SetCompiledArtifact(
+	key string, compiled []*actions_proto.VQLCollectorArgs) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.compiled_cache == nil ||
+		len(self.compiled_cache) >= maxCompiledCacheSize {
+		self.compiled_cache = make(map[string][]*actions_proto.VQLCollectorArgs)
+	}
+
+	self.compiled_cache[key] = copyCollectorArgs(compiled)
+}
+
+func copyCollectorArgs(
+	in []*actions_proto.VQLCollectorArgs) []*actions_proto.VQLCollectorArgs {
+	result := make([]*actions_proto.VQLCollectorArgs, 0, len(in))
+	for _, item := range in {
+		result = append(result,
+			proto.Clone(item).(*actions_proto.VQLCollectorArgs))
+	}
+	return result
 }
 
 func (self *RepositoryManager) NewRepository() services.Repository {
 	return &Repository{
-		Data: make(map[string]*artifacts_proto.Artifact),
+		Data:     make(map[string]*artifacts_proto.Artifact),
+		verifier: self.verifier,
 	}
 }
 
@@ -136,6 +196,28 @@ func (self *RepositoryManager) SetGlobalRepositoryForTests(
 func (self *RepositoryManager) SetArtifactFile(
 	config_obj *config_proto.Config, principal, definition, required_prefix string) (
 	*artifacts_proto.Artifact, error) {
+	artifact, err := self.setArtifactFile(config_obj, principal, definition,
+		required_prefix, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Only client artifacts are collected by hunts.
+	if config_obj.Defaults != nil &&
+		config_obj.Defaults.StageCustomArtifacts &&
+		artifact.Type == "client" {
+		_, err = self.StageArtifact(config_obj, principal, artifact.Name, "", 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return artifact, nil
+}
+
+func (self *RepositoryManager) setArtifactFile(
+	config_obj *config_proto.Config, principal, definition,
+	required_prefix, comment string) (*artifacts_proto.Artifact, error) {
 
 	// Use regexes to force the artifact into the correct prefix.
 	if required_prefix != "" {
@@ -195,6 +277,12 @@ func (self *RepositoryManager) SetArtifactFile(
 		}
 	}
 
+	err = self.recordArtifactVersion(config_obj, principal,
+		artifact.Name, ARTIFACT_VERSION_SET, definition, comment)
+	if err != nil {
+		return nil, err
+	}
+
 	// Tell interested parties that we modified this artifact.
 	journal, err := services.GetJournal(config_obj)
 	if err != nil {
@@ -226,8 +314,17 @@ func (self *RepositoryManager) DeleteArtifactFile(
 		return err
 	}
 
-	// If not there nothing to do...
-	_, pres := global_repository.Get(config_obj, name)
+	// If not there nothing to do. Artifacts inherited from the root
+	// org can not be deleted from an org - deleting the org's
+	// override restores the global artifact.
+	repository, ok := global_repository.(*Repository)
+	if !ok {
+		return errors.New("DeleteArtifactFile: unexpected repository type")
+	}
+
+	repository.mu.Lock()
+	pres := repository.has(name)
+	repository.mu.Unlock()
 	if !pres {
 		return nil
 	}
@@ -235,6 +332,12 @@ func (self *RepositoryManager) DeleteArtifactFile(
 	// Remove the artifact from the repository.
 	global_repository.Del(name)
 
+	err = self.recordArtifactVersion(config_obj, principal,
+		name, ARTIFACT_VERSION_DELETE, "", "")
+	if err != nil {
+		return err
+	}
+
 	// Now let interested parties know it is removed.
 	journal, err := services.GetJournal(config_obj)
 	if err != nil {
@@ -265,7 +368,10 @@ func (self *RepositoryManager) DeleteArtifactFile(
 func NewRepositoryManagerForTest(
 	ctx context.Context, wg *sync.WaitGroup,
 	config_obj *config_proto.Config) (services.RepositoryManager, error) {
-	self := _newRepositoryManager(config_obj, wg)
+	self, err := _newRepositoryManager(config_obj, wg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Load some artifacts via the autoexec mechanism.
 	if config_obj.Autoexec != nil {
@@ -282,15 +388,22 @@ func NewRepositoryManagerForTest(
 
 func _newRepositoryManager(
 	config_obj *config_proto.Config,
-	wg *sync.WaitGroup) *RepositoryManager {
+	wg *sync.WaitGroup) (*RepositoryManager, error) {
+	verifier, err := NewArtifactVerifier(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
 	return &RepositoryManager{
 		wg:         wg,
 		id:         utils.GetId(),
 		config_obj: config_obj,
+		verifier:   verifier,
 		global_repository: &Repository{
-			Data: make(map[string]*artifacts_proto.Artifact),
+			Data:     make(map[string]*artifacts_proto.Artifact),
+			verifier: verifier,
 		},
-	}
+	}, nil
 }
 
 func NewRepositoryManager(ctx context.Context, wg *sync.WaitGroup,
@@ -300,7 +413,10 @@ func NewRepositoryManager(ctx context.Context, wg *sync.WaitGroup,
 	logger.Info("Starting repository manager for %v", services.GetOrgName(config_obj))
 
 	// Load all the artifacts in the repository and compile them in the background.
-	self := _newRepositoryManager(config_obj, wg)
+	self, err := _newRepositoryManager(config_obj, wg)
+	if err != nil {
+		return nil, err
+	}
 
 	return self, self.StartWatchingForUpdates(ctx, wg, config_obj)
 }
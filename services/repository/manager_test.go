@@ -225,6 +225,129 @@ built_in: true
 	assert.NoError(self.T(), err)
 }
 
+func (self *ManagerTestSuite) TestAttackTechniques() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	artifact, err := manager.SetArtifactFile(self.ConfigObj, "User", `
+name: Custom.Credentials
+reference:
+- https://attack.mitre.org/techniques/T1003/
+attack_techniques:
+- t1003.001
+- T1059
+`, "" /* required_prefix */)
+	assert.NoError(self.T(), err)
+
+	// Technique ids are normalized.
+	assert.Equal(self.T(), []string{"T1003.001", "T1059"},
+		artifact.AttackTechniques)
+
+	// Techniques match their sub-techniques.
+	assert.True(self.T(), services.HasAttackTechnique(artifact, "T1003"))
+	assert.True(self.T(), services.HasAttackTechnique(artifact, "t1003.001"))
+	assert.False(self.T(), services.HasAttackTechnique(artifact, "T1003.002"))
+	assert.False(self.T(), services.HasAttackTechnique(artifact, "T1059.001"))
+
+	_, err = manager.SetArtifactFile(self.ConfigObj, "User", `
+name: Custom.Credentials
+attack_techniques:
+- Credential Dumping
+`, "" /* required_prefix */)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "Invalid ATT&CK technique")
+}
+
+func (self *ManagerTestSuite) TestOrgOverride() {
+	org_manager, err := services.GetOrgManager()
+	assert.NoError(self.T(), err)
+
+	repositories := make(map[string]services.Repository)
+	for _, org_id := range []string{"O1", "O2"} {
+		_, err = org_manager.CreateNewOrg(org_id, org_id)
+		assert.NoError(self.T(), err)
+	}
+
+	org1_config, err := org_manager.GetOrgConfig("O1")
+	assert.NoError(self.T(), err)
+
+	org1_manager, err := services.GetRepositoryManager(org1_config)
+	assert.NoError(self.T(), err)
+
+	// The org may override the built in artifact.
+	_, err = org1_manager.SetArtifactFile(org1_config, "User", `
+name: Generic.Client.Info
+description: Org override
+`, "" /* required_prefix */)
+	assert.NoError(self.T(), err)
+
+	for _, org_id := range []string{"root", "O1", "O2"} {
+		org_config, err := org_manager.GetOrgConfig(org_id)
+		assert.NoError(self.T(), err)
+
+		manager, err := services.GetRepositoryManager(org_config)
+		assert.NoError(self.T(), err)
+
+		repositories[org_id], err = manager.GetGlobalRepository(org_config)
+		assert.NoError(self.T(), err)
+	}
+
+	// Other orgs are not affected.
+	artifact, pres := repositories["O1"].Get(org1_config, "Generic.Client.Info")
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "Org override", artifact.Description)
+
+	artifact, pres = repositories["O2"].Get(self.ConfigObj, "Generic.Client.Info")
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "", artifact.Description)
+
+	artifact, pres = repositories["root"].Get(self.ConfigObj, "Generic.Client.Info")
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "", artifact.Description)
+
+	// The org's override takes precedence over a Custom. override.
+	root_manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	_, err = root_manager.SetArtifactFile(self.ConfigObj, "User", `
+name: Custom.Generic.Client.Info
+description: Custom override
+`, "" /* required_prefix */)
+	assert.NoError(self.T(), err)
+
+	artifact, pres = services.ResolveArtifact(org1_config,
+		repositories["O1"], "Generic.Client.Info", true)
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "Org override", artifact.Description)
+
+	artifact, pres = services.ResolveArtifact(self.ConfigObj,
+		repositories["O2"], "Generic.Client.Info", true)
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "Custom override", artifact.Description)
+
+	artifact, pres = services.ResolveArtifact(self.ConfigObj,
+		repositories["O2"], "Generic.Client.Info", false)
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "", artifact.Description)
+
+	// Deleting the override restores the built in artifact.
+	err = org1_manager.DeleteArtifactFile(
+		org1_config, "User", "Generic.Client.Info")
+	assert.NoError(self.T(), err)
+
+	artifact, pres = repositories["O1"].Get(org1_config, "Generic.Client.Info")
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), "", artifact.Description)
+
+	// Global artifacts can not be deleted from an org.
+	err = org1_manager.DeleteArtifactFile(
+		org1_config, "User", "Custom.Generic.Client.Info")
+	assert.NoError(self.T(), err)
+
+	_, pres = repositories["root"].Get(self.ConfigObj, "Custom.Generic.Client.Info")
+	assert.True(self.T(), pres)
+}
+
 func TestManager(t *testing.T) {
 	suite.Run(t, &ManagerTestSuite{})
 }
@@ -45,6 +45,9 @@ import (
 
 var (
 	artifactNameRegex = regexp.MustCompile("^[a-zA-Z0-9_.]+$")
+
+	// A MITRE ATT&CK technique or sub-technique id.
+	attackTechniqueRegex = regexp.MustCompile(`^T\d{4}(\.\d{3})?$`)
 )
 
 // Holds multiple artifact definitions.
@@ -53,10 +56,16 @@ type Repository struct {
 	Data        map[string]*artifacts_proto.Artifact
 	loaded_dirs []string
 
+	// Changes every time the repository is modified.
+	version uint64
+
 	// Each repository may have a parent - we search for the artifact
 	// in our parents as well.
 	parent            services.Repository
 	parent_config_obj *config_proto.Config
+
+	// If set, custom artifacts must be signed to be loaded.
+	verifier *ArtifactVerifier
 }
 
 func (self *Repository) SetParent(
@@ -66,6 +75,25 @@ func (self *Repository) SetParent(
 
 	self.parent = parent
 	self.parent_config_obj = parent_config_obj
+	self.version = utils.GetId()
+}
+
+// The version changes whenever an artifact is added or removed from
+// this repository or any of its parents. Compiled artifacts may be
+// cached for as long as the version remains the same.
+func (self *Repository) Version() string {
+	self.mu.Lock()
+	if self.version == 0 {
+		self.version = utils.GetId()
+	}
+	version := fmt.Sprintf("%d", self.version)
+	parent := self.parent
+	self.mu.Unlock()
+
+	if parent != nil {
+		return version + "/" + parent.Version()
+	}
+	return version
 }
 
 func (self *Repository) Copy() services.Repository {
@@ -76,6 +104,8 @@ func (self *Repository) Copy() services.Repository {
 		Data:              make(map[string]*artifacts_proto.Artifact),
 		parent:            self.parent,
 		parent_config_obj: self.parent_config_obj,
+		version:           self.version,
+		verifier:          self.verifier,
 	}
 	for k, v := range self.Data {
 		result.Data[k] = v
@@ -188,6 +218,13 @@ func (self *Repository) LoadProto(artifact *artifacts_proto.Artifact, validate b
 			"Invalid artifact name. Can only contain characted in this set 'a-zA-Z0-9_.'")
 	}
 
+	if self.verifier != nil {
+		err := self.verifier.Verify(artifact)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate the artifact.
 	for _, report := range artifact.Reports {
 		report.Type = strings.ToLower(report.Type)
@@ -226,6 +263,36 @@ func (self *Repository) LoadProto(artifact *artifacts_proto.Artifact, validate b
 		return nil, errors.New("Artifact type invalid.")
 	}
 
+	// Normalize ATT&CK technique ids (e.g. t1003 -> T1003).
+	for idx, technique := range artifact.AttackTechniques {
+		technique = strings.ToUpper(strings.TrimSpace(technique))
+		if !attackTechniqueRegex.MatchString(technique) {
+			return nil, fmt.Errorf("%s: Invalid ATT&CK technique %v",
+				artifact.Name, technique)
+		}
+		artifact.AttackTechniques[idx] = technique
+	}
+
+	// Results can not be forwarded back to the same artifact or to
+	// the internal queues the server relies on. The lifecycle
+	// service also checks the targets are server event artifacts
+	// when it forwards the results.
+	for _, target := range artifact.Lifecycle.GetForwardTo() {
+		if target == artifact.Name || !artifactNameRegex.MatchString(target) ||
+			strings.HasPrefix(target, "System.") ||
+			strings.HasPrefix(target, "Server.Internal.") {
+			return nil, fmt.Errorf("%s: Invalid lifecycle forward_to artifact %v",
+				artifact.Name, target)
+		}
+	}
+
+	for _, column := range artifact.Lifecycle.GetRedact() {
+		if column == "" {
+			return nil, fmt.Errorf("%s: Empty lifecycle redact column",
+				artifact.Name)
+		}
+	}
+
 	// Normalize the artifact by converting the deprecated Queries
 	// field to the Query field.
 	for _, source := range artifact.Sources {
@@ -355,6 +422,7 @@ func (self *Repository) LoadProto(artifact *artifacts_proto.Artifact, validate b
 		artifact_copy.IsAlias = true
 		self.Data[alias] = artifact_copy
 	}
+	self.version = utils.GetId()
 	self.mu.Unlock()
 
 	return artifact, nil
@@ -463,7 +531,25 @@ func (self *Repository) Del(name string) {
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
-	delete(self.Data, name)
+	_, pres := self.Data[name]
+	if pres {
+		delete(self.Data, name)
+		self.version = utils.GetId()
+	}
+}
+
+func (self *Repository) IsOrgArtifact(name string) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	// Only org repositories have a parent.
+	return self.parent != nil && self.has(name)
+}
+
+func (self *Repository) has(name string) bool {
+	artifact_name, _ := paths.SplitFullSourceName(name)
+	_, pres := self.Data[artifact_name]
+	return pres
 }
 
 func (self *Repository) List(ctx context.Context,
@@ -0,0 +1,180 @@
+package repository
+
+// Tracks the output schema of artifacts.
+
+// The columns an artifact produces change as the artifact is
+// developed. Each distinct set of columns seen in a result set is
+// recorded as a schema version so results collected by different
+// versions of the artifact (e.g. an old and a new hunt) can be read
+// back with the same columns.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Entries are never invalidated since schema versions are never
+// modified.
+const maxSchemaCacheSize = 10000
+
+// Column order does not matter - rows are dicts so reordering
+// columns does not lose anything.
+func schemaFingerprint(columns []string) string {
+	sorted := append([]string{}, columns...)
+	sort.Strings(sorted)
+
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Schemas are recorded for each query which is named by the
+// artifact and optionally its source (Artifact/Source). Names come
+// from client responses so they must be checked before they are used
+// in paths.
+func checkSchemaName(name string) error {
+	parts := strings.SplitN(name, "/", 2)
+	if !artifactNameRegex.MatchString(parts[0]) {
+		return fmt.Errorf("%w: %v", services.InvalidArtifactNameError, name)
+	}
+
+	if len(parts) == 2 {
+		source := parts[1]
+		if source == "" || source == "." || source == ".." ||
+			strings.ContainsAny(source, "/\\") {
+			return fmt.Errorf("%w: %v", services.InvalidArtifactNameError, name)
+		}
+	}
+	return nil
+}
+
+func (self *RepositoryManager) ListResultSchemas(
+	config_obj *config_proto.Config,
+	name string) ([]*api_proto.ArtifactSchema, error) {
+	err := checkSchemaName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(config_obj,
+		paths.NewArtifactSchemaPathManager(name).Directory())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*api_proto.ArtifactSchema, 0, len(children))
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		version, err := strconv.ParseUint(child.Base(), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		record := &api_proto.ArtifactSchema{}
+		err = db.GetSubject(config_obj,
+			paths.NewArtifactSchemaPathManager(name).Version(version), record)
+		if err != nil || record.Version == 0 {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+func (self *RepositoryManager) RecordResultSchema(
+	config_obj *config_proto.Config,
+	name string, columns []string) (*api_proto.ArtifactSchema, error) {
+	if name == "" || len(columns) == 0 {
+		return nil, errors.New("RecordResultSchema: no columns")
+	}
+
+	err := checkSchemaName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := schemaFingerprint(columns)
+	key := name + ":" + fingerprint
+
+	self.schema_mu.Lock()
+	defer self.schema_mu.Unlock()
+
+	cached, pres := self.schema_cache[key]
+	if pres {
+		return proto.Clone(cached).(*api_proto.ArtifactSchema), nil
+	}
+
+	schemas, err := self.ListResultSchemas(config_obj, name)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	var record *api_proto.ArtifactSchema
+	for _, schema := range schemas {
+		if schema.Fingerprint == fingerprint {
+			record = schema
+			break
+		}
+	}
+
+	if record == nil {
+		next_version := uint64(1)
+		if len(schemas) > 0 {
+			next_version = schemas[len(schemas)-1].Version + 1
+		}
+
+		record = &api_proto.ArtifactSchema{
+			Artifact:    name,
+			Version:     next_version,
+			Columns:     columns,
+			Fingerprint: fingerprint,
+			FirstSeen:   uint64(utils.GetTime().Now().Unix()),
+		}
+
+		db, err := datastore.GetDB(config_obj)
+		if err != nil {
+			return nil, err
+		}
+
+		err = db.SetSubject(config_obj,
+			paths.NewArtifactSchemaPathManager(name).Version(next_version),
+			record)
+		if err != nil {
+			return nil, fmt.Errorf("RecordResultSchema: %w", err)
+		}
+	}
+
+	if self.schema_cache == nil ||
+		len(self.schema_cache) >= maxSchemaCacheSize {
+		self.schema_cache = make(map[string]*api_proto.ArtifactSchema)
+	}
+	self.schema_cache[key] = record
+
+	return proto.Clone(record).(*api_proto.ArtifactSchema), nil
+}
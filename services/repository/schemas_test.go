@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+type SchemasTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *SchemasTestSuite) TestResultSchemas() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	name := "Custom.Schema/Source"
+	v1, err := manager.RecordResultSchema(
+		self.ConfigObj, name, []string{"Pid", "Name"})
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), v1.Version)
+
+	// Reordering the columns does not make a new version.
+	same, err := manager.RecordResultSchema(
+		self.ConfigObj, name, []string{"Name", "Pid"})
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), same.Version)
+
+	// The new version renamed a column.
+	v2, err := manager.RecordResultSchema(
+		self.ConfigObj, name, []string{"Pid", "Exe", "CommandLine"})
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), v2.Version)
+
+	schemas, err := manager.ListResultSchemas(self.ConfigObj, name)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(schemas))
+
+	// No columns are lost when merging the versions.
+	assert.Equal(self.T(), []string{"Pid", "Exe", "CommandLine", "Name"},
+		services.MergeSchemaColumns(schemas))
+
+	// Names come from client responses and must not escape the
+	// schema directory.
+	for _, name := range []string{
+		"../../etc", "Custom.Schema/../../x", "Custom.Schema/"} {
+		_, err = manager.RecordResultSchema(
+			self.ConfigObj, name, []string{"Pid"})
+		assert.True(self.T(), errors.Is(err, services.InvalidArtifactNameError))
+	}
+}
+
+func TestResultSchemas(t *testing.T) {
+	suite.Run(t, &SchemasTestSuite{})
+}
@@ -78,8 +78,9 @@ func _build(self services.ScopeBuilder, from_scratch bool) vfilter.Scope {
 	}
 
 	// Use our own sorter
-	scope.SetSorter(sorter.MergeSorter{ChunkSize: 10000})
-	scope.SetGrouper(grouper.NewMergeSortGrouperFactory(self.Config, 10000))
+	tmpdir, chunk_size := sorter.GetSpillOptions(self.Config)
+	scope.SetSorter(sorter.MergeSorter{ChunkSize: chunk_size, TempDir: tmpdir})
+	scope.SetGrouper(grouper.NewMergeSortGrouperFactory(self.Config, chunk_size))
 
 	artifact_plugin := NewArtifactRepositoryPlugin(self.Repository, self.Config)
 	env.Set("Artifact", artifact_plugin)
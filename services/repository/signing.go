@@ -0,0 +1,153 @@
+package repository
+
+// Custom artifacts may be signed with an operator key. When
+// Frontend.require_signed_artifacts is set the server refuses to load
+// custom artifacts which are not signed by one of
+// Frontend.artifact_signing_keys (by default the CA certificate). As
+// unsigned artifacts never make it into the repository they can not
+// be collected either. Built in artifacts are not affected.
+
+// The signature is kept in a comment on the last line so a signed
+// artifact is still a valid artifact definition:
+//
+//   #signature: <base64 RSA signature over the sha256 of the rest>
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	"www.velocidex.com/golang/velociraptor/bundles"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+)
+
+const (
+	SIGNATURE_PREFIX = "#signature: "
+)
+
+var (
+	NotSignedError        = errors.New("Artifact is not signed")
+	InvalidSignatureError = errors.New("Artifact signature is invalid")
+)
+
+// Split the definition into the signed content and the signature.
+func splitSignature(definition string) (string, string) {
+	signature := ""
+	lines := []string{}
+	for _, line := range strings.Split(definition, "\n") {
+		if strings.HasPrefix(line, SIGNATURE_PREFIX) {
+			signature = strings.TrimSpace(
+				strings.TrimPrefix(line, SIGNATURE_PREFIX))
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	content := strings.TrimRight(strings.Join(lines, "\n"), " \t\r\n") + "\n"
+	return content, signature
+}
+
+func hashContent(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+// Sign the definition, replacing any previous signature.
+func SignArtifact(definition string, key *rsa.PrivateKey) (string, error) {
+	content, _ := splitSignature(definition)
+	signature, err := crypto_utils.SignSha256(key, hashContent(content))
+	if err != nil {
+		return "", err
+	}
+
+	return content + SIGNATURE_PREFIX +
+		base64.StdEncoding.EncodeToString(signature) + "\n", nil
+}
+
+// Check the definition is signed by one of the keys.
+func VerifyArtifact(definition string, keys []*rsa.PublicKey) error {
+	content, signature := splitSignature(definition)
+	if signature == "" {
+		return NotSignedError
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return InvalidSignatureError
+	}
+
+	hash := hashContent(content)
+	for _, key := range keys {
+		if crypto_utils.VerifySha256(key, hash, decoded) == nil {
+			return nil
+		}
+	}
+	return InvalidSignatureError
+}
+
+// The keys which may sign artifacts for this server.
+func GetArtifactSigningKeys(
+	config_obj *config_proto.Config) ([]*rsa.PublicKey, error) {
+	var pem_keys []string
+	if config_obj.Frontend != nil {
+		pem_keys = config_obj.Frontend.ArtifactSigningKeys
+	}
+
+	if len(pem_keys) == 0 && config_obj.Client != nil &&
+		config_obj.Client.CaCertificate != "" {
+		pem_keys = []string{config_obj.Client.CaCertificate}
+	}
+
+	result := []*rsa.PublicKey{}
+	for _, pem_key := range pem_keys {
+		key, err := bundles.ParsePublicKey([]byte(pem_key))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid artifact signing key: %w", err)
+		}
+		result = append(result, key)
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("No artifact signing keys configured")
+	}
+
+	return result, nil
+}
+
+type ArtifactVerifier struct {
+	keys []*rsa.PublicKey
+}
+
+// Returns nil if the server does not require signed artifacts.
+func NewArtifactVerifier(
+	config_obj *config_proto.Config) (*ArtifactVerifier, error) {
+	if config_obj.Frontend == nil ||
+		!config_obj.Frontend.RequireSignedArtifacts {
+		return nil, nil
+	}
+
+	keys, err := GetArtifactSigningKeys(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArtifactVerifier{keys: keys}, nil
+}
+
+func (self *ArtifactVerifier) Verify(artifact *artifacts_proto.Artifact) error {
+	if artifact.BuiltIn {
+		return nil
+	}
+
+	err := VerifyArtifact(artifact.Raw, self.keys)
+	if err != nil {
+		return fmt.Errorf("%v: %w", artifact.Name, err)
+	}
+	return nil
+}
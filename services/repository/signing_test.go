@@ -0,0 +1,115 @@
+package repository_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/repository"
+)
+
+var signedArtifactDefinition = `
+name: Custom.Signed
+sources:
+- query: SELECT * FROM info()
+`
+
+func TestSignArtifact(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	other_key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := []*rsa.PublicKey{&other_key.PublicKey, &key.PublicKey}
+
+	assert.True(t, errors.Is(repository.VerifyArtifact(
+		signedArtifactDefinition, keys), repository.NotSignedError))
+
+	signed, err := repository.SignArtifact(signedArtifactDefinition, key)
+	require.NoError(t, err)
+	assert.NoError(t, repository.VerifyArtifact(signed, keys))
+
+	// Signing again replaces the old signature.
+	resigned, err := repository.SignArtifact(signed, key)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(resigned, repository.SIGNATURE_PREFIX))
+	assert.NoError(t, repository.VerifyArtifact(resigned, keys))
+
+	// Any change to the definition invalidates the signature.
+	tampered := strings.Replace(signed, "info()", "execve()", 1)
+	assert.True(t, errors.Is(repository.VerifyArtifact(
+		tampered, keys), repository.InvalidSignatureError))
+
+	// So does a signature from an unknown key.
+	assert.True(t, errors.Is(repository.VerifyArtifact(
+		signed, []*rsa.PublicKey{&other_key.PublicKey}),
+		repository.InvalidSignatureError))
+}
+
+type SignedArtifactsTestSuite struct {
+	test_utils.TestSuite
+
+	key *rsa.PrivateKey
+}
+
+func (self *SignedArtifactsTestSuite) SetupTest() {
+	var err error
+	self.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(self.T(), err)
+
+	self.ConfigObj = self.LoadConfig()
+	self.ConfigObj.Frontend.RequireSignedArtifacts = true
+	self.ConfigObj.Frontend.ArtifactSigningKeys = []string{
+		string(crypto_utils.PublicKeyToPem(&self.key.PublicKey)),
+	}
+
+	self.TestSuite.SetupTest()
+}
+
+func (self *SignedArtifactsTestSuite) TestRequireSignedArtifacts() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	global_repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	// Built in artifacts do not need a signature.
+	_, err = global_repository.LoadYaml(signedArtifactDefinition,
+		services.ValidateArtifact, services.ArtifactIsBuiltIn)
+	assert.NoError(self.T(), err)
+
+	// Custom artifacts do.
+	_, err = global_repository.LoadYaml(signedArtifactDefinition,
+		services.ValidateArtifact, !services.ArtifactIsBuiltIn)
+	assert.True(self.T(), errors.Is(err, repository.NotSignedError))
+
+	_, err = manager.SetArtifactFile(self.ConfigObj, "admin",
+		signedArtifactDefinition, "")
+	assert.Error(self.T(), err)
+
+	signed, err := repository.SignArtifact(signedArtifactDefinition, self.key)
+	require.NoError(self.T(), err)
+
+	artifact, err := global_repository.LoadYaml(signed,
+		services.ValidateArtifact, !services.ArtifactIsBuiltIn)
+	assert.NoError(self.T(), err)
+	assert.False(self.T(), artifact.BuiltIn)
+
+	// New repositories inherit the requirement.
+	_, err = manager.NewRepository().LoadYaml(signedArtifactDefinition,
+		services.ValidateArtifact, !services.ArtifactIsBuiltIn)
+	assert.True(self.T(), errors.Is(err, repository.NotSignedError))
+}
+
+func TestSignedArtifacts(t *testing.T) {
+	suite.Run(t, &SignedArtifactsTestSuite{})
+}
@@ -0,0 +1,204 @@
+package repository
+
+// Staged rollout of artifacts.
+
+// A staged artifact is only collected by hunts on canary clients
+// (those carrying the staging label) until its bake period passes.
+// After that it is available to the whole fleet as usual. Staged
+// changes may be promoted early or rolled back to the version before
+// the change.
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	DEFAULT_STAGING_LABEL    = "Canary"
+	DEFAULT_STAGING_BAKE_SEC = 24 * 60 * 60
+)
+
+func (self *RepositoryManager) StageArtifact(
+	config_obj *config_proto.Config,
+	principal, name, label string, bake_sec uint64) (
+	*api_proto.ArtifactStaging, error) {
+	err := checkArtifactName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := self.ListArtifactVersions(config_obj, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only custom artifacts have versions.
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %v has no custom versions",
+			services.ArtifactVersionNotFoundError, name)
+	}
+
+	last := versions[len(versions)-1]
+	if last.Op != ARTIFACT_VERSION_SET {
+		return nil, fmt.Errorf("Artifact %v is deleted", name)
+	}
+
+	if label == "" && config_obj.Defaults != nil {
+		label = config_obj.Defaults.ArtifactStagingLabel
+	}
+	if label == "" {
+		label = DEFAULT_STAGING_LABEL
+	}
+
+	if bake_sec == 0 && config_obj.Defaults != nil {
+		bake_sec = config_obj.Defaults.ArtifactStagingBakeSec
+	}
+	if bake_sec == 0 {
+		bake_sec = DEFAULT_STAGING_BAKE_SEC
+	}
+
+	record := &api_proto.ArtifactStaging{
+		Name:       name,
+		Label:      label,
+		Principal:  principal,
+		StagedTime: uint64(utils.GetTime().Now().Unix()),
+		BakeSec:    bake_sec,
+		Version:    last.Version,
+	}
+	if len(versions) > 1 {
+		record.PreviousVersion = versions[len(versions)-2].Version
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.SetSubject(config_obj,
+		paths.NewArtifactVersionPathManager(name).Staging(), record)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (self *RepositoryManager) GetArtifactStaging(
+	config_obj *config_proto.Config,
+	name string) (*api_proto.ArtifactStaging, error) {
+	err := checkArtifactName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &api_proto.ArtifactStaging{}
+	err = db.GetSubject(config_obj,
+		paths.NewArtifactVersionPathManager(name).Staging(), record)
+	if errors.Is(err, os.ErrNotExist) || record.Name == "" {
+		return nil, fmt.Errorf("%w: %v", services.ArtifactNotStagedError, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Once the bake period passes the artifact is promoted
+	// implicitly.
+	now := uint64(utils.GetTime().Now().Unix())
+	if record.StagedTime+record.BakeSec <= now {
+		return nil, fmt.Errorf("%w: %v", services.ArtifactNotStagedError, name)
+	}
+
+	return record, nil
+}
+
+func (self *RepositoryManager) ListStagedArtifacts(
+	config_obj *config_proto.Config) ([]*api_proto.ArtifactStaging, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(config_obj, paths.ARTIFACT_STAGING_ROOT)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*api_proto.ArtifactStaging{}
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		record, err := self.GetArtifactStaging(config_obj, child.Base())
+		if err != nil {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	return result, nil
+}
+
+func (self *RepositoryManager) PromoteArtifact(
+	config_obj *config_proto.Config, principal, name string) error {
+	_, err := self.GetArtifactStaging(config_obj, name)
+	if err != nil {
+		return err
+	}
+
+	return self.clearStaging(config_obj, name)
+}
+
+func (self *RepositoryManager) RollbackStagedArtifact(
+	config_obj *config_proto.Config, principal, name string) error {
+	record, err := self.GetArtifactStaging(config_obj, name)
+	if err != nil {
+		return err
+	}
+
+	previous := &api_proto.ArtifactVersion{}
+	if record.PreviousVersion > 0 {
+		previous, err = self.GetArtifactVersion(
+			config_obj, name, record.PreviousVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The artifact did not exist before the staged change.
+	if previous.Op != ARTIFACT_VERSION_SET {
+		err = self.DeleteArtifactFile(config_obj, principal, name)
+	} else {
+		_, err = self.RollbackArtifact(
+			config_obj, principal, name, record.PreviousVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	return self.clearStaging(config_obj, name)
+}
+
+func (self *RepositoryManager) clearStaging(
+	config_obj *config_proto.Config, name string) error {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubject(config_obj,
+		paths.NewArtifactVersionPathManager(name).Staging())
+}
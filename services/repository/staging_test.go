@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type StagingTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *StagingTestSuite) TestStagingBakePeriod() {
+	clock := &utils.MockClock{MockNow: time.Unix(1000, 0)}
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	self.ConfigObj.Defaults.StageCustomArtifacts = true
+	self.ConfigObj.Defaults.ArtifactStagingBakeSec = 100
+
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	// Saving a client artifact stages it automatically.
+	_, err = manager.SetArtifactFile(
+		self.ConfigObj, "alice", versionedArtifactV1, "")
+	require.NoError(self.T(), err)
+
+	record, err := manager.GetArtifactStaging(
+		self.ConfigObj, "Custom.Versioned")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), "Canary", record.Label)
+	assert.Equal(self.T(), uint64(1), record.Version)
+	assert.Equal(self.T(), uint64(0), record.PreviousVersion)
+
+	staged, err := manager.ListStagedArtifacts(self.ConfigObj)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(staged))
+
+	// After the bake period the artifact is no longer staged.
+	clock.MockNow = time.Unix(1100, 0)
+
+	_, err = manager.GetArtifactStaging(self.ConfigObj, "Custom.Versioned")
+	assert.True(self.T(), errors.Is(err, services.ArtifactNotStagedError))
+
+	err = manager.PromoteArtifact(self.ConfigObj, "admin", "Custom.Versioned")
+	assert.True(self.T(), errors.Is(err, services.ArtifactNotStagedError))
+}
+
+func (self *StagingTestSuite) TestRollbackStagedArtifact() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	global_repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	_, err = manager.SetArtifactFile(
+		self.ConfigObj, "alice", versionedArtifactV1, "")
+	require.NoError(self.T(), err)
+
+	// Staging a new artifact and rolling it back deletes it.
+	_, err = manager.StageArtifact(
+		self.ConfigObj, "alice", "Custom.Versioned", "", 0)
+	require.NoError(self.T(), err)
+
+	err = manager.RollbackStagedArtifact(
+		self.ConfigObj, "admin", "Custom.Versioned")
+	require.NoError(self.T(), err)
+
+	_, pres := global_repository.Get(self.ConfigObj, "Custom.Versioned")
+	assert.False(self.T(), pres)
+
+	// Deleted artifacts can not be staged.
+	_, err = manager.StageArtifact(
+		self.ConfigObj, "alice", "Custom.Versioned", "", 0)
+	assert.Error(self.T(), err)
+
+	// Staging a modification and rolling it back restores the
+	// previous definition.
+	_, err = manager.SetArtifactFile(
+		self.ConfigObj, "alice", versionedArtifactV1, "")
+	require.NoError(self.T(), err)
+
+	_, err = manager.SetArtifactFile(
+		self.ConfigObj, "bob", versionedArtifactV2, "")
+	require.NoError(self.T(), err)
+
+	record, err := manager.StageArtifact(
+		self.ConfigObj, "bob", "Custom.Versioned", "Testing", 0)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), "Testing", record.Label)
+
+	err = manager.RollbackStagedArtifact(
+		self.ConfigObj, "admin", "Custom.Versioned")
+	require.NoError(self.T(), err)
+
+	artifact, pres := global_repository.Get(self.ConfigObj, "Custom.Versioned")
+	require.True(self.T(), pres)
+	assert.Equal(self.T(), versionedArtifactV1, artifact.Raw)
+
+	_, err = manager.GetArtifactStaging(self.ConfigObj, "Custom.Versioned")
+	assert.True(self.T(), errors.Is(err, services.ArtifactNotStagedError))
+}
+
+// Names are used as path components.
+func (self *StagingTestSuite) TestInvalidStagingNames() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	for _, name := range []string{"../../config", "Custom/Foo", ""} {
+		_, err = manager.StageArtifact(
+			self.ConfigObj, "admin", name, "Canary", 100)
+		assert.True(self.T(), errors.Is(
+			err, services.ArtifactVersionNotFoundError), name)
+
+		_, err = manager.GetArtifactStaging(self.ConfigObj, name)
+		assert.True(self.T(), errors.Is(
+			err, services.ArtifactVersionNotFoundError), name)
+	}
+}
+
+func TestArtifactStaging(t *testing.T) {
+	suite.Run(t, &StagingTestSuite{})
+}
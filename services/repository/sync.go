@@ -0,0 +1,287 @@
+package repository
+
+// Keeps the global repository in sync with a remote artifact
+// repository configured in Frontend.artifact_repository_url.
+
+// The remote repository publishes a signed bundle (see `velociraptor
+// bundle make-update`), e.g. as a release asset of a Git
+// repository. The bundle is fetched periodically and only used when
+// its signature verifies against Frontend.artifact_repository_public_key
+// (by default the CA certificate). The artifacts in the bundle are
+// loaded into the running repository so no restart is needed, and
+// artifacts which were dropped from the bundle are removed again.
+// Like update bundles, a remote bundle older than the last one we
+// loaded is refused so an attacker can not roll back the repository
+// by serving an old (validly signed) bundle.
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/bundles"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+const (
+	DEFAULT_SYNC_PERIOD = 3600
+
+	// Refuse to download larger bundles.
+	MAX_REMOTE_BUNDLE_SIZE = 100 * 1024 * 1024
+)
+
+type RemoteRepository struct {
+	mu sync.Mutex
+
+	config_obj *config_proto.Config
+	repository services.Repository
+	url        string
+	key        *rsa.PublicKey
+
+	// Used to fetch the bundle.
+	Client *http.Client
+
+	// The sha256 of the last bundle we loaded.
+	hash string
+
+	// The definitions we loaded by artifact name.
+	loaded map[string]string
+}
+
+func NewRemoteRepository(
+	config_obj *config_proto.Config,
+	repository services.Repository) (*RemoteRepository, error) {
+
+	if config_obj.Frontend == nil ||
+		config_obj.Frontend.ArtifactRepositoryUrl == "" {
+		return nil, fmt.Errorf("Frontend.artifact_repository_url is not set")
+	}
+
+	url := config_obj.Frontend.ArtifactRepositoryUrl
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf(
+			"Remote artifact repository %v must use https", url)
+	}
+
+	key_data := config_obj.Frontend.ArtifactRepositoryPublicKey
+	if key_data == "" && config_obj.Client != nil {
+		key_data = config_obj.Client.CaCertificate
+	}
+
+	key, err := bundles.ParsePublicKey([]byte(key_data))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Unable to parse the remote artifact repository key: %w", err)
+	}
+
+	return &RemoteRepository{
+		config_obj: config_obj,
+		repository: repository,
+		url:        url,
+		key:        key,
+		Client: &http.Client{
+			Timeout: 10 * time.Minute,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+			},
+		},
+		loaded: make(map[string]string),
+	}, nil
+}
+
+func (self *RemoteRepository) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", self.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := self.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fetching %v: %v", self.url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MAX_REMOTE_BUNDLE_SIZE+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MAX_REMOTE_BUNDLE_SIZE {
+		return nil, fmt.Errorf("Bundle at %v is too large", self.url)
+	}
+
+	return data, nil
+}
+
+// Fetch the bundle and load any changed artifacts.
+func (self *RemoteRepository) Sync(ctx context.Context) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	data, err := self.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(data)
+	hash_hex := hex.EncodeToString(hash[:])
+	if hash_hex == self.hash {
+		return nil
+	}
+
+	// Nothing in the bundle is used unless the signature is valid.
+	bundle, err := bundles.Open(bytes.NewReader(data), int64(len(data)), self.key)
+	if err != nil {
+		return fmt.Errorf("Remote artifact repository %v: %w", self.url, err)
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	err = bundles.CheckNotReplayed(file_store_factory,
+		paths.REMOTE_REPOSITORY_STATE, bundle.Manifest, false)
+	if err != nil {
+		return fmt.Errorf("Remote artifact repository %v: %w", self.url, err)
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	loaded := make(map[string]string)
+	updated := 0
+	for _, member := range bundle.Manifest.Members {
+		if member.Type != bundles.MEMBER_ARTIFACT {
+			continue
+		}
+
+		definition, err := readBundleMember(bundle, member)
+		if err != nil {
+			logger.Error("Remote artifact repository: %v: %v", member.Name, err)
+			continue
+		}
+
+		artifact, err := self.repository.LoadYaml(definition,
+			services.ValidateArtifact, !services.ArtifactIsBuiltIn)
+		if err != nil {
+			logger.Error("Remote artifact repository: Unable to load %v: %v",
+				member.Name, err)
+			continue
+		}
+
+		if self.loaded[artifact.Name] != definition {
+			updated++
+		}
+		loaded[artifact.Name] = definition
+	}
+
+	// Remove the artifacts which are no longer in the bundle, unless
+	// they were replaced since we loaded them.
+	removed := 0
+	for name, definition := range self.loaded {
+		_, pres := loaded[name]
+		if pres {
+			continue
+		}
+
+		artifact, pres := self.repository.Get(self.config_obj, name)
+		if pres && artifact.Raw == definition {
+			self.repository.Del(name)
+			removed++
+		}
+	}
+
+	self.loaded = loaded
+	self.hash = hash_hex
+
+	err = bundles.RecordApplied(file_store_factory,
+		paths.REMOTE_REPOSITORY_STATE, bundle.Manifest)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Remote artifact repository %v: %v artifacts "+
+		"(%v updated, %v removed)", self.url, len(loaded), updated, removed)
+
+	return nil
+}
+
+// The names of the artifacts currently loaded from the remote
+// repository.
+func (self *RemoteRepository) Artifacts() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := make([]string, 0, len(self.loaded))
+	for name := range self.loaded {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func readBundleMember(bundle *bundles.Bundle, member *bundles.Member) (string, error) {
+	fd, err := bundle.Open(member)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+
+	data, err := io.ReadAll(fd)
+	return string(data), err
+}
+
+// Sync the repository with the remote repository periodically.
+func StartRemoteRepositorySync(
+	ctx context.Context, wg *sync.WaitGroup,
+	config_obj *config_proto.Config,
+	repository services.Repository) error {
+
+	remote, err := NewRemoteRepository(config_obj, repository)
+	if err != nil {
+		return err
+	}
+
+	period := config_obj.Frontend.ArtifactRepositorySyncPeriod
+	if period == 0 {
+		period = DEFAULT_SYNC_PERIOD
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("Syncing artifacts from %v every %v seconds",
+		remote.url, period)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			err := remote.Sync(ctx)
+			if err != nil {
+				logger.Error("Remote artifact repository: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(time.Duration(period) * time.Second):
+			}
+		}
+	}()
+
+	return nil
+}
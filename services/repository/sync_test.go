@@ -0,0 +1,163 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/bundles"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/repository"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type RemoteRepositoryTestSuite struct {
+	test_utils.TestSuite
+
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	bundle []byte
+	server *httptest.Server
+}
+
+func (self *RemoteRepositoryTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	var err error
+	self.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(self.T(), err)
+
+	self.server = httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			self.mu.Lock()
+			defer self.mu.Unlock()
+			_, _ = w.Write(self.bundle)
+		}))
+
+	self.ConfigObj.Frontend.ArtifactRepositoryUrl = self.server.URL
+	self.ConfigObj.Frontend.ArtifactRepositoryPublicKey = string(
+		crypto_utils.PublicKeyToPem(&self.key.PublicKey))
+}
+
+func (self *RemoteRepositoryTestSuite) TearDownTest() {
+	self.server.Close()
+	self.TestSuite.TearDownTest()
+}
+
+func (self *RemoteRepositoryTestSuite) publish(
+	key *rsa.PrivateKey, artifacts ...string) {
+	buffer := &bytes.Buffer{}
+	builder := bundles.NewBuilder(buffer, key, "Remote repository")
+	for _, name := range artifacts {
+		require.NoError(self.T(), builder.AddArtifact(name+".yaml", []byte(`
+name: `+name+`
+sources:
+- query: SELECT * FROM info()
+`)))
+	}
+	require.NoError(self.T(), builder.Close())
+
+	self.mu.Lock()
+	self.bundle = buffer.Bytes()
+	self.mu.Unlock()
+}
+
+func (self *RemoteRepositoryTestSuite) TestSync() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	global_repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	remote, err := repository.NewRemoteRepository(
+		self.ConfigObj, global_repository)
+	require.NoError(self.T(), err)
+	remote.Client = self.server.Client()
+
+	ctx := context.Background()
+
+	self.publish(self.key, "Custom.Remote.One", "Custom.Remote.Two")
+	require.NoError(self.T(), remote.Sync(ctx))
+	assert.Equal(self.T(), []string{"Custom.Remote.One", "Custom.Remote.Two"},
+		remote.Artifacts())
+
+	_, pres := global_repository.Get(self.ConfigObj, "Custom.Remote.Two")
+	assert.True(self.T(), pres)
+
+	// Artifacts dropped from the bundle are removed.
+	self.publish(self.key, "Custom.Remote.One")
+	require.NoError(self.T(), remote.Sync(ctx))
+
+	_, pres = global_repository.Get(self.ConfigObj, "Custom.Remote.Two")
+	assert.False(self.T(), pres)
+
+	// A bundle signed with another key is rejected and nothing
+	// changes.
+	other_key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(self.T(), err)
+
+	self.publish(other_key, "Custom.Remote.Evil")
+	assert.Error(self.T(), remote.Sync(ctx))
+
+	_, pres = global_repository.Get(self.ConfigObj, "Custom.Remote.Evil")
+	assert.False(self.T(), pres)
+	assert.Equal(self.T(), []string{"Custom.Remote.One"}, remote.Artifacts())
+}
+
+func (self *RemoteRepositoryTestSuite) TestRollback() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	global_repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	remote, err := repository.NewRemoteRepository(
+		self.ConfigObj, global_repository)
+	require.NoError(self.T(), err)
+	remote.Client = self.server.Client()
+
+	ctx := context.Background()
+
+	closer := utils.MockTime(&utils.MockClock{MockNow: time.Unix(2000, 0)})
+	self.publish(self.key, "Custom.Remote.New")
+	closer()
+	require.NoError(self.T(), remote.Sync(ctx))
+
+	// An older bundle is refused even though it is validly signed.
+	closer = utils.MockTime(&utils.MockClock{MockNow: time.Unix(1000, 0)})
+	self.publish(self.key, "Custom.Remote.Old")
+	closer()
+
+	err = remote.Sync(ctx)
+	assert.True(self.T(), errors.Is(err, bundles.BundleReplayError))
+	assert.Equal(self.T(), []string{"Custom.Remote.New"}, remote.Artifacts())
+
+	// The state survives a restart.
+	remote, err = repository.NewRemoteRepository(
+		self.ConfigObj, global_repository)
+	require.NoError(self.T(), err)
+	remote.Client = self.server.Client()
+
+	err = remote.Sync(ctx)
+	assert.True(self.T(), errors.Is(err, bundles.BundleReplayError))
+
+	_, pres := global_repository.Get(self.ConfigObj, "Custom.Remote.Old")
+	assert.False(self.T(), pres)
+}
+
+func TestRemoteRepository(t *testing.T) {
+	suite.Run(t, &RemoteRepositoryTestSuite{})
+}
@@ -0,0 +1,246 @@
+package repository
+
+// Keeps a version history of custom artifacts.
+
+// Each time a custom artifact is saved or deleted through the
+// repository manager the change is stored as a new version in the
+// datastore. Versions are never modified so the history doubles as
+// a record of who changed the artifact and when. Rolling back to an
+// old version saves it again as a new version.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	ARTIFACT_VERSION_SET    = "set"
+	ARTIFACT_VERSION_DELETE = "delete"
+
+	maxLatestVersionsCacheSize = 1000
+)
+
+// Names are used as path components so only valid artifact names
+// may be looked up.
+func checkArtifactName(name string) error {
+	if !artifactNameRegex.MatchString(name) {
+		return fmt.Errorf("%w: invalid artifact name %v",
+			services.ArtifactVersionNotFoundError, name)
+	}
+	return nil
+}
+
+// The version numbers stored for the artifact, in no particular
+// order.
+func listVersionNumbers(
+	config_obj *config_proto.Config, name string) ([]uint64, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(config_obj,
+		paths.NewArtifactVersionPathManager(name).Directory())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]uint64, 0, len(children))
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		version, err := strconv.ParseUint(child.Base(), 10, 64)
+		if err != nil {
+			continue
+		}
+		result = append(result, version)
+	}
+	return result, nil
+}
+
+func (self *RepositoryManager) ListArtifactVersions(
+	config_obj *config_proto.Config,
+	name string) ([]*api_proto.ArtifactVersion, error) {
+	err := checkArtifactName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := listVersionNumbers(config_obj, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*api_proto.ArtifactVersion, 0, len(versions))
+	for _, version := range versions {
+		record, err := self.GetArtifactVersion(config_obj, name, version)
+		if err != nil {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+func (self *RepositoryManager) GetArtifactVersion(
+	config_obj *config_proto.Config,
+	name string, version uint64) (*api_proto.ArtifactVersion, error) {
+	err := checkArtifactName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &api_proto.ArtifactVersion{}
+	err = db.GetSubject(config_obj,
+		paths.NewArtifactVersionPathManager(name).Version(version), record)
+	if errors.Is(err, os.ErrNotExist) || record.Version == 0 {
+		return nil, fmt.Errorf("%w: %v version %v",
+			services.ArtifactVersionNotFoundError, name, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// The most recent version of the artifact or nil if it has no
+// versions. Called with the lock held.
+func (self *RepositoryManager) getLatestVersion(
+	config_obj *config_proto.Config,
+	name string) (*api_proto.ArtifactVersion, error) {
+	latest, pres := self.latest_versions[name]
+	if pres {
+		return latest, nil
+	}
+
+	versions, err := listVersionNumbers(config_obj, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the last version needs to be read.
+	last := uint64(0)
+	for _, version := range versions {
+		if version > last {
+			last = version
+		}
+	}
+
+	if last == 0 {
+		return nil, nil
+	}
+	return self.GetArtifactVersion(config_obj, name, last)
+}
+
+// Called with the lock held.
+func (self *RepositoryManager) setLatestVersion(
+	record *api_proto.ArtifactVersion) {
+	if self.latest_versions == nil ||
+		len(self.latest_versions) >= maxLatestVersionsCacheSize {
+		self.latest_versions = make(map[string]*api_proto.ArtifactVersion)
+	}
+	self.latest_versions[record.Name] = record
+}
+
+// Store a new version unless the artifact is unchanged since the
+// last version.
+func (self *RepositoryManager) recordArtifactVersion(
+	config_obj *config_proto.Config,
+	principal, name, op, definition, comment string) error {
+	err := checkArtifactName(name)
+	if err != nil {
+		return err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	last, err := self.getLatestVersion(config_obj, name)
+	if err != nil {
+		return err
+	}
+
+	next_version := uint64(1)
+	if last != nil {
+		if last.Op == op && last.Definition == definition {
+			return nil
+		}
+		next_version = last.Version + 1
+	}
+
+	hash := ""
+	if definition != "" {
+		sum := sha256.Sum256([]byte(definition))
+		hash = hex.EncodeToString(sum[:])
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	record := &api_proto.ArtifactVersion{
+		Name:       name,
+		Version:    next_version,
+		Op:         op,
+		Principal:  principal,
+		Timestamp:  uint64(utils.GetTime().Now().Unix()),
+		Hash:       hash,
+		Comment:    comment,
+		Definition: definition,
+	}
+
+	err = db.SetSubject(config_obj,
+		paths.NewArtifactVersionPathManager(name).Version(next_version),
+		record)
+	if err != nil {
+		return err
+	}
+
+	self.setLatestVersion(record)
+	return nil
+}
+
+func (self *RepositoryManager) RollbackArtifact(
+	config_obj *config_proto.Config,
+	principal, name string, version uint64) (*artifacts_proto.Artifact, error) {
+	record, err := self.GetArtifactVersion(config_obj, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Op != ARTIFACT_VERSION_SET || record.Definition == "" {
+		return nil, fmt.Errorf(
+			"Version %v of %v deleted the artifact - roll back to "+
+				"an earlier version instead", version, name)
+	}
+
+	return self.setArtifactFile(config_obj, principal, record.Definition,
+		"", fmt.Sprintf("Rolled back to version %v", version))
+}
@@ -0,0 +1,99 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/repository"
+)
+
+var (
+	versionedArtifactV1 = `
+name: Custom.Versioned
+sources:
+- query: SELECT * FROM info()
+`
+	versionedArtifactV2 = `
+name: Custom.Versioned
+sources:
+- query: SELECT * FROM pslist()
+`
+)
+
+type VersionsTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *VersionsTestSuite) TestArtifactVersions() {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	_, err = manager.SetArtifactFile(
+		self.ConfigObj, "alice", versionedArtifactV1, "")
+	require.NoError(self.T(), err)
+
+	_, err = manager.SetArtifactFile(
+		self.ConfigObj, "bob", versionedArtifactV2, "")
+	require.NoError(self.T(), err)
+
+	// Saving the same definition again does not add a version.
+	_, err = manager.SetArtifactFile(
+		self.ConfigObj, "bob", versionedArtifactV2, "")
+	require.NoError(self.T(), err)
+
+	err = manager.DeleteArtifactFile(self.ConfigObj, "bob", "Custom.Versioned")
+	require.NoError(self.T(), err)
+
+	versions, err := manager.ListArtifactVersions(
+		self.ConfigObj, "Custom.Versioned")
+	require.NoError(self.T(), err)
+	require.Equal(self.T(), 3, len(versions))
+
+	assert.Equal(self.T(), uint64(1), versions[0].Version)
+	assert.Equal(self.T(), "alice", versions[0].Principal)
+	assert.Equal(self.T(), versionedArtifactV1, versions[0].Definition)
+	assert.Equal(self.T(), repository.ARTIFACT_VERSION_SET, versions[1].Op)
+	assert.Equal(self.T(), repository.ARTIFACT_VERSION_DELETE, versions[2].Op)
+
+	// Can not roll back to a deletion.
+	_, err = manager.RollbackArtifact(
+		self.ConfigObj, "admin", "Custom.Versioned", 3)
+	assert.Error(self.T(), err)
+
+	_, err = manager.RollbackArtifact(
+		self.ConfigObj, "admin", "Custom.Versioned", 10)
+	assert.True(self.T(), errors.Is(err, services.ArtifactVersionNotFoundError))
+
+	// Rolling back restores a deleted artifact as a new version.
+	artifact, err := manager.RollbackArtifact(
+		self.ConfigObj, "admin", "Custom.Versioned", 1)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), versionedArtifactV1, artifact.Raw)
+
+	global_repository, err := manager.GetGlobalRepository(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	artifact, pres := global_repository.Get(self.ConfigObj, "Custom.Versioned")
+	require.True(self.T(), pres)
+	assert.Equal(self.T(), versionedArtifactV1, artifact.Raw)
+
+	version, err := manager.GetArtifactVersion(
+		self.ConfigObj, "Custom.Versioned", 4)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), "admin", version.Principal)
+	assert.Equal(self.T(), "Rolled back to version 1", version.Comment)
+	assert.Equal(self.T(), versions[0].Hash, version.Hash)
+
+	// Names which are not artifact names are never looked up.
+	_, err = manager.ListArtifactVersions(self.ConfigObj, "../../users/admin")
+	assert.True(self.T(), errors.Is(err, services.ArtifactVersionNotFoundError))
+}
+
+func TestArtifactVersions(t *testing.T) {
+	suite.Run(t, &VersionsTestSuite{})
+}
@@ -0,0 +1,519 @@
+/*
+  The filestore scrubber detects bit-rot in stored uploads.
+
+  When Datastore.scrub_uploads is set, the service slowly re-reads
+  every upload in the filestore (limited to scrub_bytes_per_second)
+  and computes its sha256 hash. The hash is compared to the one
+  recorded in the collection's upload_hashes result set when the
+  upload was stored, or the hash the client signed. Uploads stored
+  before hashes were recorded can not be verified - their current
+  hash is never taken as the baseline since it may already be
+  corrupted.
+
+  Corrupted uploads are reported to the
+  Server.Internal.FilestoreCorruption queue. If scrub_recollect is
+  set and the client is online, the file is collected again.
+*/
+
+package scrubber
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"golang.org/x/time/rate"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+)
+
+const (
+	chunkSize = 64 * 1024
+
+	defaultBytesPerSecond = 1024 * 1024
+	defaultPeriod         = 7 * 24 * time.Hour
+)
+
+type ScrubReport struct {
+	Files       int      `json:"files"`
+	Bytes       int64    `json:"bytes"`
+	Unverified  int      `json:"unverified"`
+	Corrupted   int      `json:"corrupted"`
+	Recollected int      `json:"recollected"`
+	Errors      []string `json:"errors"`
+}
+
+func (self *ScrubReport) addError(path string, err error) {
+	self.Errors = append(self.Errors, fmt.Sprintf("%v: %v", path, err))
+}
+
+func NewScrubReport() *ScrubReport {
+	return &ScrubReport{Errors: []string{}}
+}
+
+// The hash of an upload as recorded in the upload_hashes result set.
+type hashRecord struct {
+	Path      string
+	Sha256    string
+	Size      int64
+	Recorded  int64
+	Verified  int64
+	Corrupted bool
+}
+
+type Scrubber struct {
+	config_obj *config_proto.Config
+	limiter    *rate.Limiter
+	period     time.Duration
+	recollect  bool
+
+	Clock utils.Clock
+}
+
+func NewScrubber(config_obj *config_proto.Config) *Scrubber {
+	bytes_per_second := config_obj.Datastore.ScrubBytesPerSecond
+	if bytes_per_second == 0 {
+		bytes_per_second = defaultBytesPerSecond
+	}
+
+	period := time.Duration(config_obj.Datastore.ScrubPeriodSec) * time.Second
+	if period == 0 {
+		period = defaultPeriod
+	}
+
+	return &Scrubber{
+		config_obj: config_obj,
+		limiter:    rate.NewLimiter(rate.Limit(bytes_per_second), chunkSize),
+		period:     period,
+		recollect:  config_obj.Datastore.ScrubRecollect,
+		Clock:      &utils.RealClock{},
+	}
+}
+
+// Scrub the uploads of all collections.
+func (self *Scrubber) ScrubAll(ctx context.Context) (*ScrubReport, error) {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(self.config_obj, paths.CLIENTS_ROOT)
+	if err != nil {
+		return nil, err
+	}
+
+	report := NewScrubReport()
+	for _, child := range children {
+		client_id := child.Base()
+		if child.IsDir() || !strings.HasPrefix(client_id, "C.") {
+			continue
+		}
+
+		flows, err := db.ListChildren(self.config_obj,
+			paths.NewFlowPathManager(client_id, "").ContainerPath())
+		if err != nil {
+			continue
+		}
+
+		for _, flow := range flows {
+			if flow.IsDir() {
+				continue
+			}
+
+			err := self.ScrubFlow(ctx, client_id, flow.Base(), report)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Scrub the uploads of a single collection. Errors reading
+// individual uploads are added to the report.
+func (self *Scrubber) ScrubFlow(ctx context.Context,
+	client_id, flow_id string, report *ScrubReport) error {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	// Uploads may still be written while the collection runs.
+	flow_path_manager := paths.NewFlowPathManager(client_id, flow_id)
+	collection_context := &flows_proto.ArtifactCollectorContext{}
+	err = db.GetSubject(self.config_obj, flow_path_manager.Path(),
+		collection_context)
+	if err != nil || collection_context.State ==
+		flows_proto.ArtifactCollectorContext_RUNNING {
+		return nil
+	}
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory,
+		flow_path_manager.UploadMetadata())
+	if err != nil {
+		// The collection has no uploads.
+		return nil
+	}
+	defer reader.Close()
+
+	records := self.loadHashes(ctx, flow_path_manager)
+	signatures := self.loadSignatures(ctx, flow_path_manager)
+	dirty := false
+
+	for row := range reader.Rows(ctx) {
+		vfs_path, _ := row.GetString("vfs_path")
+		components, _ := row.GetStrings("_Components")
+		if len(components) == 0 {
+			continue
+		}
+
+		pathspec := path_specs.NewUnsafeFilestorePath(components...).
+			SetType(api.PATH_TYPE_FILESTORE_ANY)
+		is_index := strings.HasSuffix(vfs_path, ".idx")
+		if is_index {
+			pathspec = pathspec.SetType(api.PATH_TYPE_FILESTORE_SPARSE_IDX)
+		}
+		key := utils.JoinComponents(components, "/")
+		if is_index {
+			key += ".idx"
+		}
+
+		hash, size, err := self.hashFile(ctx, file_store_factory, pathspec)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			report.addError(key, err)
+			continue
+		}
+
+		report.Files++
+		report.Bytes += size
+		now := self.Clock.Now().Unix()
+
+		record, pres := records[key]
+		if !pres {
+			// Fall back to the hash signed by the client if the
+			// upload is complete.
+			file_size, _ := utils.ToInt64(utils.GetAny(row, "file_size"))
+			uploaded_size, _ := utils.ToInt64(utils.GetAny(row, "uploaded_size"))
+			signed, pres := signatures[vfs_path]
+			if !pres || is_index || file_size != uploaded_size {
+				report.Unverified++
+				continue
+			}
+
+			record = &hashRecord{
+				Path:     key,
+				Sha256:   signed,
+				Size:     file_size,
+				Recorded: now,
+			}
+			records[key] = record
+			dirty = true
+		}
+
+		if record.Sha256 == hash && record.Size == size {
+			record.Verified = now
+			record.Corrupted = false
+			dirty = true
+			continue
+		}
+
+		// Only report each corruption once.
+		if record.Corrupted {
+			continue
+		}
+		record.Corrupted = true
+		dirty = true
+		report.Corrupted++
+
+		event := ordereddict.NewDict().
+			Set("ClientId", client_id).
+			Set("FlowId", flow_id).
+			Set("Upload", vfs_path).
+			Set("Path", key).
+			Set("ExpectedSha256", record.Sha256).
+			Set("Sha256", hash).
+			Set("ExpectedSize", record.Size).
+			Set("Size", size)
+
+		if self.recollect && !is_index && len(components) > 6 {
+			new_flow_id, err := self.recollectUpload(ctx, client_id,
+				components[5], components[6:])
+			if err != nil {
+				report.addError(key, err)
+			}
+			if new_flow_id != "" {
+				report.Recollected++
+			}
+			event.Set("RecollectFlowId", new_flow_id)
+		}
+
+		journal, err := services.GetJournal(self.config_obj)
+		if err != nil {
+			return err
+		}
+
+		err = journal.PushRowsToArtifact(self.config_obj,
+			[]*ordereddict.Dict{event},
+			"Server.Internal.FilestoreCorruption", "server", "")
+		if err != nil {
+			report.addError(key, err)
+		}
+	}
+
+	if !dirty {
+		return nil
+	}
+
+	return self.writeHashes(flow_path_manager, records)
+}
+
+// Hash the file while limiting the read rate.
+func (self *Scrubber) hashFile(ctx context.Context,
+	file_store_factory api.FileStore,
+	pathspec api.FSPathSpec) (string, int64, error) {
+	fd, err := file_store_factory.ReadFile(pathspec)
+	if err != nil {
+		return "", 0, err
+	}
+	defer fd.Close()
+
+	hasher := sha256.New()
+	buf := make([]byte, chunkSize)
+	size := int64(0)
+
+	for {
+		err := self.limiter.WaitN(ctx, len(buf))
+		if err != nil {
+			return "", 0, err
+		}
+
+		n, err := fd.Read(buf)
+		if n > 0 {
+			hasher.Write(buf[:n])
+			size += int64(n)
+		}
+
+		if errors.Is(err, io.EOF) || n == 0 && err == nil {
+			break
+		}
+
+		if err != nil {
+			return "", 0, err
+		}
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+func (self *Scrubber) loadHashes(ctx context.Context,
+	flow_path_manager *paths.FlowPathManager) map[string]*hashRecord {
+	result := make(map[string]*hashRecord)
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory,
+		flow_path_manager.UploadHashes())
+	if err != nil {
+		return result
+	}
+	defer reader.Close()
+
+	for row := range reader.Rows(ctx) {
+		record := &hashRecord{}
+		record.Path, _ = row.GetString("Path")
+		record.Sha256, _ = row.GetString("Sha256")
+		record.Size, _ = utils.ToInt64(utils.GetAny(row, "Size"))
+		record.Recorded, _ = utils.ToInt64(utils.GetAny(row, "Recorded"))
+		record.Verified, _ = utils.ToInt64(utils.GetAny(row, "Verified"))
+		record.Corrupted, _ = row.GetBool("Corrupted")
+		if record.Path == "" {
+			continue
+		}
+		result[record.Path] = record
+	}
+
+	return result
+}
+
+// The hashes of uploads signed by the client, by vfs path.
+func (self *Scrubber) loadSignatures(ctx context.Context,
+	flow_path_manager *paths.FlowPathManager) map[string]string {
+	result := make(map[string]string)
+
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory,
+		flow_path_manager.Signatures())
+	if err != nil {
+		return result
+	}
+	defer reader.Close()
+
+	for row := range reader.Rows(ctx) {
+		item_type, _ := row.GetString("Type")
+		name, _ := row.GetString("Name")
+		hash, _ := row.GetString("Sha256")
+		if item_type == "upload" && hash != "" {
+			result[name] = strings.ToLower(hash)
+		}
+	}
+
+	return result
+}
+
+func (self *Scrubber) writeHashes(flow_path_manager *paths.FlowPathManager,
+	records map[string]*hashRecord) error {
+	file_store_factory := file_store.GetFileStore(self.config_obj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		flow_path_manager.UploadHashes(), nil, /* opts */
+		utils.SyncCompleter, result_sets.TruncateMode)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		record := records[key]
+		writer.Write(ordereddict.NewDict().
+			Set("Path", record.Path).
+			Set("Sha256", record.Sha256).
+			Set("Size", record.Size).
+			Set("Recorded", record.Recorded).
+			Set("Verified", record.Verified).
+			Set("Corrupted", record.Corrupted))
+	}
+
+	return nil
+}
+
+// Collect the file again if the client is online.
+func (self *Scrubber) recollectUpload(ctx context.Context,
+	client_id, accessor string, components []string) (string, error) {
+	notifier, err := services.GetNotifier(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	if !notifier.IsClientConnected(ctx, self.config_obj, client_id, 2) {
+		return "", nil
+	}
+
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	launcher, err := services.GetLauncher(self.config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	return launcher.ScheduleArtifactCollection(
+		ctx, self.config_obj, acl_managers.NullACLManager{},
+		repository,
+		&flows_proto.ArtifactCollectorArgs{
+			Creator:   "FilestoreScrubber",
+			ClientId:  client_id,
+			Artifacts: []string{"System.VFS.DownloadFile"},
+			Specs: []*flows_proto.ArtifactSpec{{
+				Artifact: "System.VFS.DownloadFile",
+				Parameters: &flows_proto.ArtifactParameters{
+					Env: []*actions_proto.VQLEnv{
+						{Key: "Components", Value: json.MustMarshalString(components)},
+						{Key: "Accessor", Value: accessor},
+					},
+				},
+			}},
+		}, func() {
+			notifier.NotifyListener(self.config_obj, client_id, "Scrubber")
+		})
+}
+
+func (self *Scrubber) run(ctx context.Context) {
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+
+	for {
+		report, err := self.ScrubAll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("FilestoreScrubber: %v", err)
+		}
+
+		if report != nil {
+			logger.Info("FilestoreScrubber: Verified %v uploads (%v bytes), "+
+				"%v corrupted, %v recollected, %v without a recorded hash",
+				report.Files, report.Bytes, report.Corrupted,
+				report.Recollected, report.Unverified)
+
+			for _, e := range report.Errors {
+				logger.Debug("FilestoreScrubber: %v", e)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-self.Clock.After(self.period):
+		}
+	}
+}
+
+func NewFilestoreScrubber(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	if config_obj.Datastore == nil || !config_obj.Datastore.ScrubUploads {
+		return nil
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> Filestore Scrubber for %v.",
+		services.GetOrgName(config_obj))
+
+	self := NewScrubber(config_obj)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		self.run(ctx)
+	}()
+
+	return nil
+}
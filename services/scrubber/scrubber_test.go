@@ -0,0 +1,130 @@
+package scrubber_test
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services/scrubber"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type ScrubberTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *ScrubberTestSuite) writeUpload(data string) {
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	upload := paths.NewFlowPathManager("C.1", "F.1").
+		GetUploadsFile("file", "C:/Windows/notepad.exe")
+
+	fd, err := file_store_factory.WriteFile(upload.Path())
+	require.NoError(self.T(), err)
+	require.NoError(self.T(), fd.Truncate())
+	_, err = fd.Write([]byte(data))
+	require.NoError(self.T(), err)
+	fd.Close()
+}
+
+func (self *ScrubberTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	flow_path_manager := paths.NewFlowPathManager("C.1", "F.1")
+	err = db.SetSubject(self.ConfigObj, flow_path_manager.Path(),
+		&flows_proto.ArtifactCollectorContext{
+			ClientId:  "C.1",
+			SessionId: "F.1",
+			State:     flows_proto.ArtifactCollectorContext_FINISHED,
+		})
+	require.NoError(self.T(), err)
+
+	self.writeUpload("hello world")
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		flow_path_manager.UploadMetadata(), nil, func() {},
+		result_sets.TruncateMode)
+	require.NoError(self.T(), err)
+
+	upload := flow_path_manager.GetUploadsFile("file", "C:/Windows/notepad.exe")
+	writer.Write(ordereddict.NewDict().
+		Set("vfs_path", "C:/Windows/notepad.exe").
+		Set("_Components", upload.Path().Components()).
+		Set("file_size", 11).
+		Set("uploaded_size", 11))
+	writer.Close()
+
+	// The hash recorded when the upload was stored.
+	writer, err = result_sets.NewResultSetWriter(file_store_factory,
+		flow_path_manager.UploadHashes(), nil, func() {},
+		result_sets.TruncateMode)
+	require.NoError(self.T(), err)
+
+	writer.Write(ordereddict.NewDict().
+		Set("Path", utils.JoinComponents(upload.Path().Components(), "/")).
+		Set("Sha256", "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9").
+		Set("Size", 11))
+	writer.Close()
+}
+
+func (self *ScrubberTestSuite) TestScrubFlow() {
+	s := scrubber.NewScrubber(self.ConfigObj)
+
+	// The upload matches the hash recorded when it was stored.
+	report := scrubber.NewScrubReport()
+	require.NoError(self.T(), s.ScrubFlow(self.Ctx, "C.1", "F.1", report))
+	assert.Equal(self.T(), 1, report.Files)
+	assert.Equal(self.T(), 0, report.Unverified)
+	assert.Equal(self.T(), 0, report.Corrupted)
+
+	// A changed upload is detected once.
+	self.writeUpload("hello wOrld")
+
+	report = scrubber.NewScrubReport()
+	require.NoError(self.T(), s.ScrubFlow(self.Ctx, "C.1", "F.1", report))
+	assert.Equal(self.T(), 1, report.Corrupted)
+
+	report = scrubber.NewScrubReport()
+	require.NoError(self.T(), s.ScrubFlow(self.Ctx, "C.1", "F.1", report))
+	assert.Equal(self.T(), 0, report.Corrupted)
+}
+
+// Uploads without a recorded hash are never trusted on first use.
+func (self *ScrubberTestSuite) TestNoRecordedHash() {
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	flow_path_manager := paths.NewFlowPathManager("C.1", "F.1")
+	require.NoError(self.T(), file_store_factory.Delete(
+		flow_path_manager.UploadHashes()))
+
+	// The upload is already corrupted when first scrubbed.
+	self.writeUpload("hello wOrld")
+
+	s := scrubber.NewScrubber(self.ConfigObj)
+	for i := 0; i < 2; i++ {
+		report := scrubber.NewScrubReport()
+		require.NoError(self.T(), s.ScrubFlow(self.Ctx, "C.1", "F.1", report))
+		assert.Equal(self.T(), 1, report.Unverified)
+		assert.Equal(self.T(), 0, report.Corrupted)
+	}
+
+	// The current hash was not recorded as the baseline.
+	self.writeUpload("hello world")
+	report := scrubber.NewScrubReport()
+	require.NoError(self.T(), s.ScrubFlow(self.Ctx, "C.1", "F.1", report))
+	assert.Equal(self.T(), 1, report.Unverified)
+}
+
+func TestScrubber(t *testing.T) {
+	suite.Run(t, &ScrubberTestSuite{})
+}
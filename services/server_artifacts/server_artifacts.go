@@ -275,6 +275,16 @@ func (self *ServerArtifactsRunner) runQuery(
 		}
 	}()
 
+	// Abort the query if it holds too much memory.
+	var budget *vql_subsystem.MemoryBudget
+	if arg.MaxMemory > 0 {
+		budget = vql_subsystem.NewMemoryBudget(arg.MaxMemory, func(err error) {
+			scope.Log("ERROR:%v", err)
+			cancel()
+		})
+		vql_subsystem.InstallMemoryBudget(scope, budget)
+	}
+
 	scope.Log("<green>Starting</> query execution.")
 
 	// All the queries will use the same scope. This allows one
@@ -366,6 +376,10 @@ func (self *ServerArtifactsRunner) runQuery(
 		if query.Name != "" {
 			scope.Log("Query %v: Emitted %v rows", query.Name, row_idx)
 		}
+
+		if budget != nil && budget.Err() != nil {
+			return budget.Err()
+		}
 	}
 
 	return nil
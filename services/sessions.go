@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+)
+
+var (
+	global_session_manager SessionManager
+
+	SessionRevokedError  = errors.New("Session has been revoked")
+	SessionNotFoundError = errors.New("Session not found")
+)
+
+// Information about the request which uses a session.
+type SessionRequest struct {
+	// Derived from the session credentials.
+	SessionId     string
+	Username      string
+	RemoteAddress string
+	UserAgent     string
+	Authenticator string
+
+	// Time in seconds since epoch the credentials expire. If 0 the
+	// session expires when it is idle for too long.
+	Expires uint64
+}
+
+// The session manager tracks authenticated GUI and API sessions so
+// administrators can see who is logged in and revoke sessions
+// (e.g. after an analyst's credentials were compromised). Like the
+// user manager it is global across all orgs.
+type SessionManager interface {
+	// Called by the authenticators on every authenticated
+	// request. Returns SessionRevokedError if the session may not be
+	// used any more.
+	TouchSession(ctx context.Context, request *SessionRequest) error
+
+	// List the active sessions for the user (or all users if
+	// username is empty).
+	ListSessions(ctx context.Context,
+		username string) ([]*api_proto.GUISession, error)
+
+	GetSession(ctx context.Context,
+		session_id string) (*api_proto.GUISession, error)
+
+	// Revoke a session immediately - subsequent requests using it
+	// are rejected.
+	RevokeSession(ctx context.Context, principal, session_id string) error
+
+	// Revoke all the user's sessions, returning the number revoked.
+	RevokeUserSessions(ctx context.Context,
+		principal, username string) (int, error)
+
+	// Record that the user completed a step-up authentication in
+	// this session.
+	StepUpSession(ctx context.Context, session_id string) error
+}
+
+func RegisterSessionManager(manager SessionManager) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	global_session_manager = manager
+}
+
+// May return nil if session tracking is not running (e.g. in tools).
+func GetSessionManager() SessionManager {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return global_session_manager
+}
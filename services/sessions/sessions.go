@@ -0,0 +1,503 @@
+// Tracks authenticated GUI and API sessions.
+
+// Every authenticated request reports its session to the session
+// manager. A session is identified by a hash of its credentials
+// (e.g. the signed auth cookie) so the credentials themselves are
+// never stored. Sessions are persisted in the datastore so
+// revocations survive a server restart.
+
+// Sessions backed by a signed cookie expire with the cookie. Other
+// sessions (e.g. basic auth) expire when they have been idle for
+// SESSION_IDLE_TIMEOUT. Note that revoking a basic auth session
+// rejects the current credentials, but the user's password should
+// still be changed.
+
+package sessions
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	SESSION_IDLE_TIMEOUT = 24 * time.Hour
+
+	// Only write the last activity time to the datastore this often.
+	PERSIST_INTERVAL = 60 * time.Second
+
+	// Sessions may be revoked by another frontend so we reload
+	// cached sessions from the datastore this often.
+	SESSION_CACHE_TTL = 60 * time.Second
+
+	// Bound the number of cached sessions.
+	MAX_CACHED_SESSIONS = 10000
+
+	HOUSEKEEPING_INTERVAL = 10 * time.Minute
+)
+
+type cachedSession struct {
+	record *api_proto.GUISession
+
+	// When the record was read from the datastore.
+	loaded time.Time
+
+	// When the record was last written to the datastore.
+	persisted time.Time
+}
+
+type SessionManager struct {
+	mu         sync.Mutex
+	config_obj *config_proto.Config
+
+	// Serializes writes to the datastore.
+	write_mu sync.Mutex
+
+	// Cache of sessions by session id.
+	sessions map[string]*cachedSession
+}
+
+func NewSessionManager(config_obj *config_proto.Config) *SessionManager {
+	return &SessionManager{
+		config_obj: config_obj,
+		sessions:   make(map[string]*cachedSession),
+	}
+}
+
+// Write the current state of the session to the datastore. Called
+// without the lock so requests are not held up by the datastore.
+// Writes are serialized and always write the latest state so an older
+// copy of the record can not overwrite a newer one (e.g. a
+// revocation).
+func (self *SessionManager) persist(session *cachedSession) error {
+	self.write_mu.Lock()
+	defer self.write_mu.Unlock()
+
+	self.mu.Lock()
+	session.persisted = utils.GetTime().Now()
+	record := proto.Clone(session.record).(*api_proto.GUISession)
+	self.mu.Unlock()
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSubject(self.config_obj,
+		paths.NewSessionPathManager(record.SessionId).Path(), record)
+}
+
+// Read the session from the datastore.
+func (self *SessionManager) loadSession(
+	session_id string) (*api_proto.GUISession, error) {
+	if !constants.SessionIdRegex.MatchString(session_id) {
+		return nil, services.SessionNotFoundError
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &api_proto.GUISession{}
+	err = db.GetSubject(self.config_obj,
+		paths.NewSessionPathManager(session_id).Path(), record)
+	if errors.Is(err, os.ErrNotExist) || record.SessionId == "" {
+		return nil, services.SessionNotFoundError
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Add the session to the cache. Called with the lock held.
+func (self *SessionManager) cache(
+	record *api_proto.GUISession) *cachedSession {
+	if len(self.sessions) > MAX_CACHED_SESSIONS {
+		self.sessions = make(map[string]*cachedSession)
+	}
+
+	// The record may have been changed since we last wrote it so
+	// the next activity is written straight away.
+	session := &cachedSession{
+		record: record,
+		loaded: utils.GetTime().Now(),
+	}
+	self.sessions[record.SessionId] = session
+	return session
+}
+
+// Get the session from the cache or the datastore. Called with the
+// lock held.
+func (self *SessionManager) getSession(session_id string) (*cachedSession, error) {
+	session, pres := self.sessions[session_id]
+	if pres && utils.GetTime().Now().Sub(session.loaded) < SESSION_CACHE_TTL {
+		return session, nil
+	}
+
+	record, err := self.loadSession(session_id)
+	if err != nil {
+		delete(self.sessions, session_id)
+		return nil, err
+	}
+
+	return self.cache(record), nil
+}
+
+func isExpired(record *api_proto.GUISession, now time.Time) bool {
+	return record.Expires > 0 && record.Expires < uint64(now.Unix())
+}
+
+func (self *SessionManager) TouchSession(
+	ctx context.Context, request *services.SessionRequest) error {
+	session, err := self.touchSession(request)
+	if session == nil {
+		return err
+	}
+
+	persist_err := self.persist(session)
+
+	// Persisting a revoked session is best effort.
+	if err != nil {
+		return err
+	}
+	return persist_err
+}
+
+// Update the cached session. Returns the session if it needs to be
+// written to the datastore.
+func (self *SessionManager) touchSession(
+	request *services.SessionRequest) (*cachedSession, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	now := utils.GetTime().Now()
+	expires := request.Expires
+	if expires == 0 {
+		expires = uint64(now.Add(SESSION_IDLE_TIMEOUT).Unix())
+	}
+
+	session, err := self.getSession(request.SessionId)
+	if errors.Is(err, services.SessionNotFoundError) {
+		session = self.cache(&api_proto.GUISession{
+			SessionId:     request.SessionId,
+			Username:      request.Username,
+			RemoteAddress: request.RemoteAddress,
+			UserAgent:     request.UserAgent,
+			Authenticator: request.Authenticator,
+			Created:       uint64(now.Unix()),
+			LastActive:    uint64(now.Unix()),
+			Expires:       expires,
+		})
+
+		logging.LogAudit(self.config_obj, request.Username, "SessionStarted",
+			logrus.Fields{
+				"session_id": request.SessionId,
+				"remote":     request.RemoteAddress,
+				"user_agent": request.UserAgent,
+			})
+
+		// Other requests should not write the session again.
+		session.persisted = now
+		return session, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	record := session.record
+	if record.Revoked || record.Username != request.Username {
+		// Keep revoked idle sessions around while they are still
+		// being used so their credentials stay rejected.
+		if request.Expires == 0 && record.Expires < expires &&
+			now.Sub(session.persisted) > PERSIST_INTERVAL {
+			record.Expires = expires
+			session.persisted = now
+			return session, services.SessionRevokedError
+		}
+		return nil, services.SessionRevokedError
+	}
+
+	record.LastActive = uint64(now.Unix())
+	record.RemoteAddress = request.RemoteAddress
+	record.UserAgent = request.UserAgent
+	record.Expires = expires
+
+	if now.Sub(session.persisted) > PERSIST_INTERVAL {
+		session.persisted = now
+		return session, nil
+	}
+	return nil, nil
+}
+
+func (self *SessionManager) GetSession(ctx context.Context,
+	session_id string) (*api_proto.GUISession, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	session, err := self.getSession(session_id)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(session.record).(*api_proto.GUISession), nil
+}
+
+// A copy of all the sessions in the datastore. Sessions we have
+// cached are more recent than the datastore. Scanning the datastore
+// may take a while so we do not hold the lock while doing so.
+func (self *SessionManager) allSessions() ([]*api_proto.GUISession, error) {
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(self.config_obj,
+		paths.NewSessionPathManager("").Directory())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*api_proto.GUISession, 0, len(children))
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		session_id := child.Base()
+		self.mu.Lock()
+		session, pres := self.sessions[session_id]
+		if pres {
+			result = append(result,
+				proto.Clone(session.record).(*api_proto.GUISession))
+		}
+		self.mu.Unlock()
+		if pres {
+			continue
+		}
+
+		record, err := self.loadSession(session_id)
+		if err != nil {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	return result, nil
+}
+
+func (self *SessionManager) ListSessions(ctx context.Context,
+	username string) ([]*api_proto.GUISession, error) {
+	sessions, err := self.allSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.GetTime().Now()
+	result := []*api_proto.GUISession{}
+	for _, record := range sessions {
+		if record.Revoked || isExpired(record, now) {
+			continue
+		}
+
+		if username != "" && record.Username != username {
+			continue
+		}
+
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastActive > result[j].LastActive
+	})
+
+	return result, nil
+}
+
+// Called with the lock held.
+func (self *SessionManager) revoke(
+	principal string, session *cachedSession) {
+	record := session.record
+	record.Revoked = true
+	record.RevokedBy = principal
+	record.RevokedTime = uint64(utils.GetTime().Now().Unix())
+}
+
+// Called without the lock once the revocation is persisted.
+func (self *SessionManager) auditRevocation(
+	principal string, session *cachedSession) {
+	self.mu.Lock()
+	fields := logrus.Fields{
+		"session_id": session.record.SessionId,
+		"username":   session.record.Username,
+		"remote":     session.record.RemoteAddress,
+	}
+	self.mu.Unlock()
+
+	logging.LogAudit(self.config_obj, principal, "RevokeSession", fields)
+}
+
+func (self *SessionManager) RevokeSession(ctx context.Context,
+	principal, session_id string) error {
+	self.mu.Lock()
+	session, err := self.getSession(session_id)
+	if err != nil {
+		self.mu.Unlock()
+		return err
+	}
+
+	if session.record.Revoked {
+		self.mu.Unlock()
+		return nil
+	}
+
+	self.revoke(principal, session)
+	self.mu.Unlock()
+
+	err = self.persist(session)
+	if err != nil {
+		return err
+	}
+
+	self.auditRevocation(principal, session)
+	return nil
+}
+
+func (self *SessionManager) RevokeUserSessions(ctx context.Context,
+	principal, username string) (int, error) {
+	sessions, err := self.allSessions()
+	if err != nil {
+		return 0, err
+	}
+
+	self.mu.Lock()
+	now := utils.GetTime().Now()
+	revoked := []*cachedSession{}
+	for _, record := range sessions {
+		if record.Username != username || record.Revoked ||
+			isExpired(record, now) {
+			continue
+		}
+
+		session, err := self.getSession(record.SessionId)
+		if err != nil || session.record.Revoked {
+			continue
+		}
+
+		self.revoke(principal, session)
+		revoked = append(revoked, session)
+	}
+	self.mu.Unlock()
+
+	count := 0
+	for _, session := range revoked {
+		err = self.persist(session)
+		if err != nil {
+			return count, err
+		}
+		self.auditRevocation(principal, session)
+		count++
+	}
+
+	return count, nil
+}
+
+func (self *SessionManager) StepUpSession(ctx context.Context,
+	session_id string) error {
+	self.mu.Lock()
+	session, err := self.getSession(session_id)
+	if err != nil {
+		self.mu.Unlock()
+		return err
+	}
+
+	if session.record.Revoked {
+		self.mu.Unlock()
+		return services.SessionRevokedError
+	}
+
+	session.record.StepUpTime = uint64(utils.GetTime().Now().Unix())
+	self.mu.Unlock()
+
+	// Always persist so the step-up is visible immediately.
+	return self.persist(session)
+}
+
+// Remove expired sessions from the datastore.
+func (self *SessionManager) Housekeeping() error {
+	sessions, err := self.allSessions()
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetTime().Now()
+	for _, record := range sessions {
+		if !isExpired(record, now) {
+			continue
+		}
+
+		self.mu.Lock()
+		delete(self.sessions, record.SessionId)
+		self.mu.Unlock()
+
+		err := db.DeleteSubject(self.config_obj,
+			paths.NewSessionPathManager(record.SessionId).Path())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func StartSessionManager(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> session manager service")
+
+	service := NewSessionManager(config_obj)
+	services.RegisterSessionManager(service)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(HOUSEKEEPING_INTERVAL):
+				err := service.Housekeeping()
+				if err != nil {
+					logger.Error("SessionManager: housekeeping: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
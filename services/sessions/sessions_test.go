@@ -0,0 +1,178 @@
+package sessions_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/sessions"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	session1 = "00000000000000000000000000000001"
+	session2 = "00000000000000000000000000000002"
+)
+
+type SessionsTestSuite struct {
+	test_utils.TestSuite
+
+	clock  *utils.MockClock
+	closer func()
+}
+
+func (self *SessionsTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.clock = &utils.MockClock{MockNow: time.Unix(1700000000, 0)}
+	self.closer = utils.MockTime(self.clock)
+}
+
+func (self *SessionsTestSuite) TearDownTest() {
+	self.closer()
+	self.TestSuite.TearDownTest()
+}
+
+func (self *SessionsTestSuite) TestSessions() {
+	ctx := context.Background()
+	manager := sessions.NewSessionManager(self.ConfigObj)
+
+	cookie_session := &services.SessionRequest{
+		SessionId:     session1,
+		Username:      "bob",
+		RemoteAddress: "10.0.0.1:1234",
+		UserAgent:     "Firefox",
+		Authenticator: "oauth",
+		Expires:       uint64(self.clock.MockNow.Add(time.Hour).Unix()),
+	}
+
+	basic_session := &services.SessionRequest{
+		SessionId:     session2,
+		Username:      "bob",
+		RemoteAddress: "10.0.0.2:1234",
+		UserAgent:     "curl",
+		Authenticator: "basic",
+	}
+
+	require.NoError(self.T(), manager.TouchSession(ctx, cookie_session))
+	require.NoError(self.T(), manager.TouchSession(ctx, basic_session))
+
+	// Activity is tracked.
+	self.clock.MockNow = self.clock.MockNow.Add(time.Minute)
+	cookie_session.RemoteAddress = "10.0.0.3:1234"
+	require.NoError(self.T(), manager.TouchSession(ctx, cookie_session))
+
+	list, err := manager.ListSessions(ctx, "bob")
+	require.NoError(self.T(), err)
+	require.Equal(self.T(), 2, len(list))
+	assert.Equal(self.T(), session1, list[0].SessionId)
+	assert.Equal(self.T(), "10.0.0.3:1234", list[0].RemoteAddress)
+	assert.Equal(self.T(), uint64(self.clock.MockNow.Unix()), list[0].LastActive)
+
+	list, err = manager.ListSessions(ctx, "alice")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(list))
+
+	// Revoke a session - it is rejected from now on.
+	require.NoError(self.T(), manager.RevokeSession(ctx, "admin", session1))
+	err = manager.TouchSession(ctx, cookie_session)
+	assert.True(self.T(), errors.Is(err, services.SessionRevokedError))
+
+	// Revocation survives a restart.
+	manager = sessions.NewSessionManager(self.ConfigObj)
+	err = manager.TouchSession(ctx, cookie_session)
+	assert.True(self.T(), errors.Is(err, services.SessionRevokedError))
+
+	session, err := manager.GetSession(ctx, session1)
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), "admin", session.RevokedBy)
+
+	list, err = manager.ListSessions(ctx, "")
+	require.NoError(self.T(), err)
+	require.Equal(self.T(), 1, len(list))
+	assert.Equal(self.T(), session2, list[0].SessionId)
+
+	// Revoke all bob's sessions.
+	count, err := manager.RevokeUserSessions(ctx, "admin", "bob")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, count)
+
+	err = manager.TouchSession(ctx, basic_session)
+	assert.True(self.T(), errors.Is(err, services.SessionRevokedError))
+
+	// Expired sessions are removed.
+	self.clock.MockNow = self.clock.MockNow.Add(sessions.SESSION_IDLE_TIMEOUT * 2)
+	require.NoError(self.T(), manager.Housekeeping())
+
+	_, err = manager.GetSession(ctx, session1)
+	assert.True(self.T(), errors.Is(err, services.SessionNotFoundError))
+}
+
+// Sessions revoked by another frontend are rejected once our cache
+// expires.
+func (self *SessionsTestSuite) TestRevokedElsewhere() {
+	ctx := context.Background()
+	manager := sessions.NewSessionManager(self.ConfigObj)
+	other_frontend := sessions.NewSessionManager(self.ConfigObj)
+
+	request := &services.SessionRequest{
+		SessionId: session1,
+		Username:  "bob",
+	}
+	require.NoError(self.T(), manager.TouchSession(ctx, request))
+	require.NoError(self.T(), other_frontend.RevokeSession(ctx, "admin", session1))
+
+	self.clock.MockNow = self.clock.MockNow.Add(sessions.SESSION_CACHE_TTL)
+	err := manager.TouchSession(ctx, request)
+	assert.True(self.T(), errors.Is(err, services.SessionRevokedError))
+
+	// Our activity does not undo the revocation.
+	self.clock.MockNow = self.clock.MockNow.Add(sessions.SESSION_CACHE_TTL)
+	err = other_frontend.TouchSession(ctx, request)
+	assert.True(self.T(), errors.Is(err, services.SessionRevokedError))
+
+	// Invalid session ids are never looked up.
+	_, err = manager.GetSession(ctx, "../../users/admin")
+	assert.True(self.T(), errors.Is(err, services.SessionNotFoundError))
+}
+
+// Writes from concurrent requests never undo a revocation.
+func (self *SessionsTestSuite) TestConcurrentRevocation() {
+	ctx := context.Background()
+	manager := sessions.NewSessionManager(self.ConfigObj)
+
+	request := &services.SessionRequest{
+		SessionId: session1,
+		Username:  "bob",
+	}
+	require.NoError(self.T(), manager.TouchSession(ctx, request))
+
+	// The touches below all want to write the session.
+	self.clock.MockNow = self.clock.MockNow.Add(2 * sessions.PERSIST_INTERVAL)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = manager.TouchSession(ctx, request)
+		}()
+	}
+	require.NoError(self.T(), manager.RevokeSession(ctx, "admin", session1))
+	wg.Wait()
+
+	manager = sessions.NewSessionManager(self.ConfigObj)
+	err := manager.TouchSession(ctx, request)
+	assert.True(self.T(), errors.Is(err, services.SessionRevokedError))
+}
+
+func TestSessions(t *testing.T) {
+	suite.Run(t, &SessionsTestSuite{})
+}
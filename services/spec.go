@@ -79,5 +79,10 @@ func AllServerServicesSpec() *config_proto.ServerServicesConfig {
 		Label:               true,
 		Launcher:            true,
 		NotebookService:     true,
+		JobManager:          true,
+		CanaryManager:       true,
+		UploadDeduplicator:  true,
+		FilestoreScrubber:   true,
+		ArtifactLifecycle:   true,
 	}
 }
@@ -26,6 +26,8 @@ func (self UserManager) GetUserFromContext(ctx context.Context) (
 	}
 
 	user_record.CurrentOrg = grpc_user_info.CurrentOrg
+	user_record.RemoteAddress = grpc_user_info.RemoteAddress
+	user_record.SessionId = grpc_user_info.SessionId
 
 	// Fetch the appropriate config file fro the org manager.
 	org_manager, err := services.GetOrgManager()
@@ -34,7 +36,17 @@ func (self UserManager) GetUserFromContext(ctx context.Context) (
 	}
 
 	org_config_obj, err := org_manager.GetOrgConfig(user_record.CurrentOrg)
-	return user_record, org_config_obj, err
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = CheckNetworkAccess(org_config_obj,
+		user_record.Name, user_record.RemoteAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user_record, org_config_obj, nil
 }
 
 // GetGRPCUserInfo: Extracts user information from GRPC context.
@@ -61,6 +73,9 @@ func GetGRPCUserInfo(
 
 				result.Name = crypto_utils.GetSubjectName(
 					tlsInfo.State.PeerCertificates[0])
+				if peer.Addr != nil {
+					result.RemoteAddress = peer.Addr.String()
+				}
 
 				// Calls from the gRPC gateway are allowed to
 				// embed the authenticated web user in the
@@ -0,0 +1,273 @@
+package users
+
+// TOTP (RFC 6238) enrollment and verification used for step-up
+// authentication before sensitive operations.
+
+// Users enroll by generating a shared secret which they load into
+// their authenticator app. The enrollment is confirmed by the first
+// valid code. Records are always kept in the root org since users
+// are global.
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	TOTP_PERIOD = 30
+	TOTP_DIGITS = 6
+
+	// Accept codes one period either side of now to allow for
+	// clock drift.
+	TOTP_SKEW = 1
+
+	TOTP_ISSUER = "Velociraptor"
+
+	// After this many consecutive invalid codes the user may not
+	// verify again until the lockout expires.
+	MAX_TOTP_FAILURES = 5
+	TOTP_LOCKOUT      = 15 * time.Minute
+
+	// Bound the number of users we track failures for.
+	MAX_TRACKED_FAILURES = 10000
+)
+
+var (
+	MFANotEnrolledError     = errors.New("MFANotEnrolled")
+	MFAAlreadyEnrolledError = errors.New("MFAAlreadyEnrolled")
+	InvalidTOTPCodeError    = errors.New("InvalidTOTPCode")
+	MFALockedError          = errors.New("MFALocked")
+
+	// Serialize verification so a code can not be replayed by
+	// concurrent requests.
+	mfa_mu sync.Mutex
+
+	// Consecutive failed verifications by username. Protected by
+	// mfa_mu.
+	totp_failures = make(map[string]*totpFailures)
+)
+
+type totpFailures struct {
+	count        int
+	locked_until time.Time
+}
+
+// Must be called with mfa_mu held.
+func checkLockout(username string) error {
+	record, pres := totp_failures[username]
+	if !pres {
+		return nil
+	}
+
+	if utils.GetTime().Now().Before(record.locked_until) {
+		return fmt.Errorf("%w: %v", MFALockedError, username)
+	}
+	return nil
+}
+
+// Must be called with mfa_mu held.
+func recordFailure(username string) {
+	record, pres := totp_failures[username]
+	if !pres {
+		if len(totp_failures) >= MAX_TRACKED_FAILURES {
+			// Only expired lockouts may be forgotten.
+			now := utils.GetTime().Now()
+			for k, v := range totp_failures {
+				if v.count < MAX_TOTP_FAILURES || now.After(v.locked_until) {
+					delete(totp_failures, k)
+				}
+			}
+		}
+		record = &totpFailures{}
+		totp_failures[username] = record
+	}
+
+	// The lockout expired - the user gets a fresh set of attempts.
+	if record.count >= MAX_TOTP_FAILURES {
+		record.count = 0
+	}
+
+	record.count++
+	if record.count >= MAX_TOTP_FAILURES {
+		record.locked_until = utils.GetTime().Now().Add(TOTP_LOCKOUT)
+	}
+}
+
+// Compute the HOTP (RFC 4226) value for the time step.
+func generateTOTP(secret []byte, step uint64) string {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, step)
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(buf)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(
+		strings.ToUpper(secret))
+}
+
+func GetMFA(ctx context.Context, config_obj *config_proto.Config,
+	username string) (*api_proto.UserMFA, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &api_proto.UserMFA{}
+	err = db.GetSubject(config_obj,
+		paths.NewUserPathManager(username).MFA(), record)
+	if errors.Is(err, os.ErrNotExist) || record.TotpSecret == "" {
+		return nil, fmt.Errorf("%w: %v", MFANotEnrolledError, username)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Start a new TOTP enrollment for the user. An existing confirmed
+// enrollment must be reset first.
+func EnrollTOTP(ctx context.Context, config_obj *config_proto.Config,
+	username string) (*api_proto.UserMFA, error) {
+	mfa_mu.Lock()
+	defer mfa_mu.Unlock()
+
+	existing, err := GetMFA(ctx, config_obj, username)
+	if err == nil && existing.Confirmed {
+		return nil, fmt.Errorf("%w: %v", MFAAlreadyEnrolledError, username)
+	}
+
+	secret := make([]byte, 20)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &api_proto.UserMFA{
+		Name: username,
+		TotpSecret: base32.StdEncoding.WithPadding(
+			base32.NoPadding).EncodeToString(secret),
+		Created: uint64(utils.GetTime().Now().Unix()),
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.SetSubject(config_obj,
+		paths.NewUserPathManager(username).MFA(), record)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// The provisioning URL authenticator apps accept (usually as a QR
+// code).
+func GetTOTPUrl(record *api_proto.UserMFA) string {
+	label := url.PathEscape(TOTP_ISSUER + ":" + record.Name)
+	params := url.Values{}
+	params.Set("secret", record.TotpSecret)
+	params.Set("issuer", TOTP_ISSUER)
+	params.Set("digits", fmt.Sprintf("%d", TOTP_DIGITS))
+	params.Set("period", fmt.Sprintf("%d", TOTP_PERIOD))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Verify a TOTP code for the user. The first valid code confirms a
+// pending enrollment. Each time step may only be used once and
+// repeated failures lock the user out for TOTP_LOCKOUT.
+func VerifyTOTP(ctx context.Context, config_obj *config_proto.Config,
+	username, code string) error {
+	mfa_mu.Lock()
+	defer mfa_mu.Unlock()
+
+	err := checkLockout(username)
+	if err != nil {
+		return err
+	}
+
+	record, err := GetMFA(ctx, config_obj, username)
+	if err != nil {
+		return err
+	}
+
+	secret, err := decodeSecret(record.TotpSecret)
+	if err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+	now_step := uint64(utils.GetTime().Now().Unix()) / TOTP_PERIOD
+
+	for step := now_step - TOTP_SKEW; step <= now_step+TOTP_SKEW; step++ {
+		if step <= record.LastStep {
+			continue
+		}
+
+		expected := generateTOTP(secret, step)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+
+		delete(totp_failures, username)
+
+		record.LastStep = step
+		record.Confirmed = true
+
+		db, err := datastore.GetDB(config_obj)
+		if err != nil {
+			return err
+		}
+
+		return db.SetSubject(config_obj,
+			paths.NewUserPathManager(username).MFA(), record)
+	}
+
+	recordFailure(username)
+	return InvalidTOTPCodeError
+}
+
+// Remove the user's enrollment (e.g. when they lose their device).
+func ResetMFA(ctx context.Context, config_obj *config_proto.Config,
+	username string) error {
+	mfa_mu.Lock()
+	defer mfa_mu.Unlock()
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubject(config_obj,
+		paths.NewUserPathManager(username).MFA())
+}
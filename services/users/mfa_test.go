@@ -0,0 +1,141 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/users"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// The RFC 6238 test secret "12345678901234567890" in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+type MFATestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *MFATestSuite) TestTOTP() {
+	ctx := context.Background()
+
+	_, err := users.GetMFA(ctx, self.ConfigObj, "bob")
+	assert.True(self.T(), errors.Is(err, users.MFANotEnrolledError))
+
+	record, err := users.EnrollTOTP(ctx, self.ConfigObj, "bob")
+	require.NoError(self.T(), err)
+	assert.False(self.T(), record.Confirmed)
+	assert.True(self.T(), strings.Contains(
+		users.GetTOTPUrl(record), "secret="+record.TotpSecret))
+
+	// Replace the secret with the RFC test vector.
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	record.TotpSecret = rfcSecret
+	err = db.SetSubject(self.ConfigObj,
+		paths.NewUserPathManager("bob").MFA(), record)
+	require.NoError(self.T(), err)
+
+	closer := utils.MockTime(&utils.MockClock{MockNow: time.Unix(59, 0)})
+	defer closer()
+
+	err = users.VerifyTOTP(ctx, self.ConfigObj, "bob", "000000")
+	assert.True(self.T(), errors.Is(err, users.InvalidTOTPCodeError))
+
+	// The first valid code confirms the enrollment.
+	require.NoError(self.T(), users.VerifyTOTP(ctx, self.ConfigObj, "bob", "287082"))
+
+	record, err = users.GetMFA(ctx, self.ConfigObj, "bob")
+	require.NoError(self.T(), err)
+	assert.True(self.T(), record.Confirmed)
+
+	// Codes can not be replayed.
+	err = users.VerifyTOTP(ctx, self.ConfigObj, "bob", "287082")
+	assert.True(self.T(), errors.Is(err, users.InvalidTOTPCodeError))
+
+	// A confirmed enrollment must be reset before enrolling again.
+	_, err = users.EnrollTOTP(ctx, self.ConfigObj, "bob")
+	assert.True(self.T(), errors.Is(err, users.MFAAlreadyEnrolledError))
+
+	require.NoError(self.T(), users.ResetMFA(ctx, self.ConfigObj, "bob"))
+	_, err = users.GetMFA(ctx, self.ConfigObj, "bob")
+	assert.True(self.T(), errors.Is(err, users.MFANotEnrolledError))
+}
+
+func (self *MFATestSuite) TestLockout() {
+	ctx := context.Background()
+
+	record, err := users.EnrollTOTP(ctx, self.ConfigObj, "alice")
+	require.NoError(self.T(), err)
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	record.TotpSecret = rfcSecret
+	err = db.SetSubject(self.ConfigObj,
+		paths.NewUserPathManager("alice").MFA(), record)
+	require.NoError(self.T(), err)
+
+	clock := &utils.MockClock{MockNow: time.Unix(59, 0)}
+	closer := utils.MockTime(clock)
+	defer closer()
+
+	for i := 0; i < users.MAX_TOTP_FAILURES; i++ {
+		err = users.VerifyTOTP(ctx, self.ConfigObj, "alice", "000000")
+		assert.True(self.T(), errors.Is(err, users.InvalidTOTPCodeError))
+	}
+
+	// Even the correct code is refused while locked out.
+	err = users.VerifyTOTP(ctx, self.ConfigObj, "alice", "287082")
+	assert.True(self.T(), errors.Is(err, users.MFALockedError))
+
+	// Once the lockout expires the user may try again.
+	clock.MockNow = time.Unix(59, 0).Add(users.TOTP_LOCKOUT + time.Second)
+	err = users.VerifyTOTP(ctx, self.ConfigObj, "alice", "000000")
+	assert.True(self.T(), errors.Is(err, users.InvalidTOTPCodeError))
+}
+
+func (self *MFATestSuite) TestNetworkRestrictions() {
+	self.ConfigObj.GUI.RoleNetworkRestrictions = []string{
+		"administrator=10.0.0.0/8, 192.168.1.0/24",
+	}
+
+	err := services.GrantRoles(self.ConfigObj, "admin",
+		[]string{"administrator"})
+	require.NoError(self.T(), err)
+
+	err = services.GrantRoles(self.ConfigObj, "reader",
+		[]string{"reader"})
+	require.NoError(self.T(), err)
+
+	assert.NoError(self.T(), users.CheckNetworkAccess(
+		self.ConfigObj, "admin", "10.1.2.3:443"))
+	assert.NoError(self.T(), users.CheckNetworkAccess(
+		self.ConfigObj, "admin", "192.168.1.5"))
+
+	err = users.CheckNetworkAccess(self.ConfigObj, "admin", "172.16.0.1:443")
+	assert.True(self.T(), errors.Is(err, acls.PermissionDenied))
+
+	// Unrestricted roles may connect from anywhere.
+	assert.NoError(self.T(), users.CheckNetworkAccess(
+		self.ConfigObj, "reader", "172.16.0.1:443"))
+
+	self.ConfigObj.GUI.RoleNetworkRestrictions = []string{"administrator"}
+	_, err = users.GetRoleNetworkRestrictions(self.ConfigObj)
+	assert.Error(self.T(), err)
+}
+
+func TestMFA(t *testing.T) {
+	suite.Run(t, &MFATestSuite{})
+}
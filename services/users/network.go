@@ -0,0 +1,110 @@
+package users
+
+// Restrict the networks users may connect from based on their roles.
+
+// Restrictions are configured in GUI.role_network_restrictions as
+// entries of the form "role=cidr,cidr". A user must connect from an
+// allowed network of every restricted role they hold in the org,
+// so privileged roles may be limited to e.g. an admin VLAN while
+// other roles remain unrestricted.
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"www.velocidex.com/golang/velociraptor/acls"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Parse the role restrictions from the config into a map of role
+// to allowed networks.
+func GetRoleNetworkRestrictions(
+	config_obj *config_proto.Config) (map[string][]*net.IPNet, error) {
+	result := make(map[string][]*net.IPNet)
+	if config_obj.GUI == nil {
+		return result, nil
+	}
+
+	for _, entry := range config_obj.GUI.RoleNetworkRestrictions {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf(
+				"Invalid role network restriction %v: should be role=cidr,cidr",
+				entry)
+		}
+
+		role := strings.TrimSpace(parts[0])
+		for _, cidr := range strings.Split(parts[1], ",") {
+			cidr = strings.TrimSpace(cidr)
+			if cidr == "" {
+				continue
+			}
+
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Invalid role network restriction %v: %w", entry, err)
+			}
+			result[role] = append(result[role], network)
+		}
+
+		// A role with no networks may not connect at all.
+		if _, pres := result[role]; !pres {
+			result[role] = nil
+		}
+	}
+
+	return result, nil
+}
+
+// Parse an address of the form ip:port or ip.
+func parseRemoteIP(remote_address string) net.IP {
+	host, _, err := net.SplitHostPort(remote_address)
+	if err != nil {
+		host = remote_address
+	}
+	return net.ParseIP(strings.Trim(host, "[]"))
+}
+
+// Check that the principal may connect from the remote address
+// given the roles they hold in the org.
+func CheckNetworkAccess(config_obj *config_proto.Config,
+	principal, remote_address string) error {
+	restrictions, err := GetRoleNetworkRestrictions(config_obj)
+	if err != nil || len(restrictions) == 0 {
+		return err
+	}
+
+	policy, err := services.GetPolicy(config_obj, principal)
+	if err != nil {
+		// Users without a policy in this org hold no roles.
+		return nil
+	}
+
+	ip := parseRemoteIP(remote_address)
+
+	for _, role := range policy.Roles {
+		networks, pres := restrictions[role]
+		if !pres {
+			continue
+		}
+
+		allowed := false
+		for _, network := range networks {
+			if ip != nil && network.Contains(ip) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return fmt.Errorf(
+				"%w: Role %v may not be used from %v",
+				acls.PermissionDenied, role, remote_address)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package users
+
+// Storage for the WebAuthn credentials and recovery codes used by
+// the basic authenticator. Like other user records these always
+// live in the root org.
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	RECOVERY_CODE_COUNT = 10
+)
+
+var (
+	WebAuthnCredentialNotFoundError = errors.New("WebAuthnCredentialNotFound")
+	InvalidRecoveryCodeError        = errors.New("InvalidRecoveryCode")
+
+	webauthn_mu sync.Mutex
+)
+
+func GetWebAuthnCredential(ctx context.Context,
+	config_obj *config_proto.Config,
+	credential_id string) (*api_proto.WebAuthnCredential, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &api_proto.WebAuthnCredential{}
+	err = db.GetSubject(config_obj,
+		paths.NewWebAuthnPathManager(credential_id).Path(), record)
+	if errors.Is(err, os.ErrNotExist) || record.CredentialId == "" {
+		return nil, fmt.Errorf("%w: %v",
+			WebAuthnCredentialNotFoundError, credential_id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// List the credentials registered by the user, oldest first.
+func ListWebAuthnCredentials(ctx context.Context,
+	config_obj *config_proto.Config,
+	username string) ([]*api_proto.WebAuthnCredential, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.ListChildren(config_obj,
+		paths.NewWebAuthnPathManager("").Directory())
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*api_proto.WebAuthnCredential{}
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		record, err := GetWebAuthnCredential(ctx, config_obj, child.Base())
+		if err != nil || record.Name != username {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Created < result[j].Created
+	})
+
+	return result, nil
+}
+
+func SetWebAuthnCredential(ctx context.Context,
+	config_obj *config_proto.Config,
+	record *api_proto.WebAuthnCredential) error {
+	webauthn_mu.Lock()
+	defer webauthn_mu.Unlock()
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	// Credential ids are chosen by the authenticator - never let
+	// one user overwrite another user's credential.
+	existing, err := GetWebAuthnCredential(ctx, config_obj, record.CredentialId)
+	if err == nil && existing.Name != record.Name {
+		return fmt.Errorf("Credential %v is already registered",
+			record.CredentialId)
+	}
+
+	return db.SetSubject(config_obj,
+		paths.NewWebAuthnPathManager(record.CredentialId).Path(), record)
+}
+
+func DeleteWebAuthnCredential(ctx context.Context,
+	config_obj *config_proto.Config,
+	username, credential_id string) error {
+	webauthn_mu.Lock()
+	defer webauthn_mu.Unlock()
+
+	record, err := GetWebAuthnCredential(ctx, config_obj, credential_id)
+	if err != nil {
+		return err
+	}
+
+	if record.Name != username {
+		return fmt.Errorf("%w: %v",
+			WebAuthnCredentialNotFoundError, credential_id)
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubject(config_obj,
+		paths.NewWebAuthnPathManager(credential_id).Path())
+}
+
+// Remove all the user's credentials and recovery codes so they can
+// log in with their password and register a new key.
+func ResetWebAuthn(ctx context.Context,
+	config_obj *config_proto.Config, username string) error {
+	credentials, err := ListWebAuthnCredentials(ctx, config_obj, username)
+	if err != nil {
+		return err
+	}
+
+	for _, credential := range credentials {
+		err = DeleteWebAuthnCredential(ctx, config_obj,
+			username, credential.CredentialId)
+		if err != nil {
+			return err
+		}
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubject(config_obj,
+		paths.NewUserPathManager(username).RecoveryCodes())
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func hashRecoveryCode(code string) string {
+	hash := sha256.Sum256([]byte(normalizeRecoveryCode(code)))
+	return hex.EncodeToString(hash[:])
+}
+
+// Generate a new set of recovery codes replacing any old ones. Only
+// the hashes are stored so the codes must be shown to the user now.
+func NewRecoveryCodes(ctx context.Context,
+	config_obj *config_proto.Config, username string) ([]string, error) {
+	webauthn_mu.Lock()
+	defer webauthn_mu.Unlock()
+
+	record := &api_proto.UserRecoveryCodes{
+		Name:    username,
+		Created: uint64(utils.GetTime().Now().Unix()),
+	}
+
+	codes := []string{}
+	for i := 0; i < RECOVERY_CODE_COUNT; i++ {
+		buf := make([]byte, 5)
+		_, err := rand.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		code = code[:4] + "-" + code[4:]
+		codes = append(codes, code)
+		record.CodeHashes = append(record.CodeHashes, hashRecoveryCode(code))
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.SetSubject(config_obj,
+		paths.NewUserPathManager(username).RecoveryCodes(), record)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Consume a recovery code. Each code may only be used once.
+func UseRecoveryCode(ctx context.Context,
+	config_obj *config_proto.Config, username, code string) error {
+	webauthn_mu.Lock()
+	defer webauthn_mu.Unlock()
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	path := paths.NewUserPathManager(username).RecoveryCodes()
+	record := &api_proto.UserRecoveryCodes{}
+	err = db.GetSubject(config_obj, path, record)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	hash := hashRecoveryCode(code)
+	for idx, item := range record.CodeHashes {
+		if item != hash {
+			continue
+		}
+
+		record.CodeHashes = append(record.CodeHashes[:idx],
+			record.CodeHashes[idx+1:]...)
+		return db.SetSubject(config_obj, path, record)
+	}
+
+	return InvalidRecoveryCodeError
+}
+
+// How many unused recovery codes the user has.
+func CountRecoveryCodes(ctx context.Context,
+	config_obj *config_proto.Config, username string) int {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return 0
+	}
+
+	record := &api_proto.UserRecoveryCodes{}
+	err = db.GetSubject(config_obj,
+		paths.NewUserPathManager(username).RecoveryCodes(), record)
+	if err != nil {
+		return 0
+	}
+	return len(record.CodeHashes)
+}
@@ -0,0 +1,92 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/services/users"
+)
+
+type WebAuthnTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *WebAuthnTestSuite) TestCredentials() {
+	ctx := context.Background()
+
+	for idx, id := range []string{"key1", "key2"} {
+		require.NoError(self.T(), users.SetWebAuthnCredential(
+			ctx, self.ConfigObj, &api_proto.WebAuthnCredential{
+				Name:         "bob",
+				CredentialId: id,
+				Created:      uint64(idx),
+			}))
+	}
+
+	// Another user can not take over a credential id.
+	err := users.SetWebAuthnCredential(ctx, self.ConfigObj,
+		&api_proto.WebAuthnCredential{Name: "mallory", CredentialId: "key1"})
+	assert.Error(self.T(), err)
+
+	credentials, err := users.ListWebAuthnCredentials(ctx, self.ConfigObj, "bob")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(credentials))
+	assert.Equal(self.T(), "key1", credentials[0].CredentialId)
+
+	// Users can only delete their own credentials.
+	err = users.DeleteWebAuthnCredential(ctx, self.ConfigObj, "mallory", "key1")
+	assert.True(self.T(), errors.Is(err, users.WebAuthnCredentialNotFoundError))
+
+	require.NoError(self.T(), users.DeleteWebAuthnCredential(
+		ctx, self.ConfigObj, "bob", "key1"))
+
+	credentials, err = users.ListWebAuthnCredentials(ctx, self.ConfigObj, "bob")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(credentials))
+}
+
+func (self *WebAuthnTestSuite) TestRecoveryCodes() {
+	ctx := context.Background()
+
+	codes, err := users.NewRecoveryCodes(ctx, self.ConfigObj, "bob")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), users.RECOVERY_CODE_COUNT, len(codes))
+
+	// Codes are accepted regardless of case and dashes, only once.
+	code := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+	require.NoError(self.T(), users.UseRecoveryCode(
+		ctx, self.ConfigObj, "bob", code))
+
+	err = users.UseRecoveryCode(ctx, self.ConfigObj, "bob", codes[0])
+	assert.True(self.T(), errors.Is(err, users.InvalidRecoveryCodeError))
+
+	err = users.UseRecoveryCode(ctx, self.ConfigObj, "alice", codes[1])
+	assert.True(self.T(), errors.Is(err, users.InvalidRecoveryCodeError))
+
+	assert.Equal(self.T(), users.RECOVERY_CODE_COUNT-1,
+		users.CountRecoveryCodes(ctx, self.ConfigObj, "bob"))
+
+	// Resetting removes the codes and the keys.
+	require.NoError(self.T(), users.SetWebAuthnCredential(
+		ctx, self.ConfigObj, &api_proto.WebAuthnCredential{
+			Name:         "bob",
+			CredentialId: "key3",
+		}))
+	require.NoError(self.T(), users.ResetWebAuthn(ctx, self.ConfigObj, "bob"))
+
+	assert.Equal(self.T(), 0, users.CountRecoveryCodes(ctx, self.ConfigObj, "bob"))
+	credentials, err := users.ListWebAuthnCredentials(ctx, self.ConfigObj, "bob")
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(credentials))
+}
+
+func TestWebAuthn(t *testing.T) {
+	suite.Run(t, &WebAuthnTestSuite{})
+}
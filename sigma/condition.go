@@ -0,0 +1,195 @@
+package sigma
+
+// Parse the Sigma condition expression into a VQL expression. The
+// grammar is:
+
+// expr     := and ("or" and)*
+// and      := not ("and" not)*
+// not      := "not" not | primary
+// primary  := "(" expr ")" | quantity "of" (pattern | "them") | name
+// quantity := "1" | "any" | "all"
+
+// Aggregations (introduced by a "|") are not supported.
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+type conditionParser struct {
+	tokens []string
+	pos    int
+
+	// Compiled VQL expressions for each selection.
+	selections map[string]string
+
+	// The selection names in the order they appear in the rule.
+	names []string
+}
+
+func tokenizeCondition(condition string) []string {
+	condition = strings.NewReplacer(
+		"(", " ( ", ")", " ) ").Replace(condition)
+	return strings.Fields(condition)
+}
+
+func (self *conditionParser) peek() string {
+	if self.pos >= len(self.tokens) {
+		return ""
+	}
+	return self.tokens[self.pos]
+}
+
+func (self *conditionParser) next() string {
+	result := self.peek()
+	self.pos++
+	return result
+}
+
+func (self *conditionParser) parse(condition string) (string, error) {
+	if strings.Contains(condition, "|") {
+		return "", fmt.Errorf("%w: aggregations are not supported: %v",
+			UnsupportedError, condition)
+	}
+
+	self.tokens = tokenizeCondition(condition)
+	self.pos = 0
+
+	result, err := self.parseOr()
+	if err != nil {
+		return "", err
+	}
+
+	if self.pos < len(self.tokens) {
+		return "", fmt.Errorf("%w: unexpected %v in condition %v",
+			UnsupportedError, self.peek(), condition)
+	}
+	return result, nil
+}
+
+func (self *conditionParser) parseOr() (string, error) {
+	result, err := self.parseAnd()
+	if err != nil {
+		return "", err
+	}
+
+	terms := []string{result}
+	for strings.EqualFold(self.peek(), "or") {
+		self.next()
+		term, err := self.parseAnd()
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, term)
+	}
+	return joinTerms(terms, "OR"), nil
+}
+
+func (self *conditionParser) parseAnd() (string, error) {
+	result, err := self.parseNot()
+	if err != nil {
+		return "", err
+	}
+
+	terms := []string{result}
+	for strings.EqualFold(self.peek(), "and") {
+		self.next()
+		term, err := self.parseNot()
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, term)
+	}
+	return joinTerms(terms, "AND"), nil
+}
+
+func (self *conditionParser) parseNot() (string, error) {
+	if strings.EqualFold(self.peek(), "not") {
+		self.next()
+		result, err := self.parseNot()
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + result, nil
+	}
+	return self.parsePrimary()
+}
+
+func (self *conditionParser) parsePrimary() (string, error) {
+	token := self.next()
+	switch strings.ToLower(token) {
+	case "":
+		return "", fmt.Errorf("%w: unexpected end of condition",
+			UnsupportedError)
+
+	case "(":
+		result, err := self.parseOr()
+		if err != nil {
+			return "", err
+		}
+		if self.next() != ")" {
+			return "", fmt.Errorf("%w: unbalanced parentheses",
+				UnsupportedError)
+		}
+		return result, nil
+
+	case "1", "any", "all":
+		if !strings.EqualFold(self.peek(), "of") {
+			break
+		}
+		self.next()
+
+		operator := "OR"
+		if strings.EqualFold(token, "all") {
+			operator = "AND"
+		}
+		return self.parseQuantifier(self.next(), operator)
+	}
+
+	result, pres := self.selections[token]
+	if !pres {
+		return "", fmt.Errorf("%w: unknown selection %v",
+			UnsupportedError, token)
+	}
+	return result, nil
+}
+
+// Combine all selections matching the pattern. "them" refers to all
+// selections except those starting with _
+func (self *conditionParser) parseQuantifier(
+	pattern, operator string) (string, error) {
+	terms := []string{}
+	for _, name := range self.names {
+		if strings.EqualFold(pattern, "them") {
+			if strings.HasPrefix(name, "_") {
+				continue
+			}
+		} else {
+			matched, err := path.Match(pattern, name)
+			if err != nil {
+				return "", fmt.Errorf("%w: invalid pattern %v",
+					UnsupportedError, pattern)
+			}
+			if !matched {
+				continue
+			}
+		}
+		terms = append(terms, self.selections[name])
+	}
+
+	if len(terms) == 0 {
+		return "", fmt.Errorf("%w: no selections match %v",
+			UnsupportedError, pattern)
+	}
+	return joinTerms(terms, operator), nil
+}
+
+// VQL's OR binds more tightly than AND so groups are always
+// parenthesized.
+func joinTerms(terms []string, operator string) string {
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return "(" + strings.Join(terms, " "+operator+" ") + ")"
+}
@@ -0,0 +1,317 @@
+package sigma
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Velocidex/yaml/v2"
+	"www.velocidex.com/golang/velociraptor/artifacts/generate"
+)
+
+func Convert(rule *Rule, profile *Profile) (*generate.Artifact, error) {
+	source, err := profile.findSource(rule.LogSource)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &conditionParser{
+		selections: make(map[string]string),
+	}
+
+	for _, item := range rule.selections() {
+		name, _ := item.Key.(string)
+		expression, err := compileSelection(profile, source, item.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+		parser.selections[name] = expression
+		parser.names = append(parser.names, name)
+	}
+
+	conditions, err := rule.conditions()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := []string{}
+	for _, condition := range conditions {
+		term, err := parser.parse(condition)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	name := profile.ArtifactPrefix + artifactName(rule.Title)
+	return &generate.Artifact{
+		Name: name,
+		Yaml: formatArtifact(name, rule, profile, source,
+			joinTerms(terms, "OR")),
+	}, nil
+}
+
+// A selection is either a map of fields to values (all must match),
+// a list of such maps (any must match) or a list of keywords.
+func compileSelection(profile *Profile, source *SourceMapping,
+	selection interface{}) (string, error) {
+	switch t := selection.(type) {
+	case yaml.MapSlice:
+		terms := []string{}
+		for _, item := range t {
+			key, ok := item.Key.(string)
+			if !ok {
+				return "", fmt.Errorf("%w: invalid field %v",
+					UnsupportedError, item.Key)
+			}
+
+			term, err := compileField(profile, source, key, item.Value)
+			if err != nil {
+				return "", err
+			}
+			terms = append(terms, term)
+		}
+		if len(terms) == 0 {
+			return "", fmt.Errorf("%w: empty selection", UnsupportedError)
+		}
+		return joinTerms(terms, "AND"), nil
+
+	case []interface{}:
+		terms := []string{}
+		for _, item := range t {
+			var term string
+			var err error
+
+			if _, ok := item.(yaml.MapSlice); ok {
+				term, err = compileSelection(profile, source, item)
+			} else {
+				term, err = compileValue(
+					profile.keywordExpression(), []string{"contains"}, item)
+			}
+			if err != nil {
+				return "", err
+			}
+			terms = append(terms, term)
+		}
+		if len(terms) == 0 {
+			return "", fmt.Errorf("%w: empty selection", UnsupportedError)
+		}
+		return joinTerms(terms, "OR"), nil
+
+	case string:
+		return compileValue(profile.keywordExpression(), []string{"contains"}, t)
+	}
+
+	return "", fmt.Errorf("%w: invalid selection %v", UnsupportedError, selection)
+}
+
+// Fields are written as Field|modifier|modifier
+func compileField(profile *Profile, source *SourceMapping,
+	key string, value interface{}) (string, error) {
+	parts := strings.Split(key, "|")
+	field, err := profile.fieldExpression(source, parts[0])
+	if err != nil {
+		return "", err
+	}
+	modifiers := parts[1:]
+
+	operator := "OR"
+	for _, modifier := range modifiers {
+		if modifier == "all" {
+			operator = "AND"
+		}
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+
+	terms := []string{}
+	for _, item := range values {
+		term, err := compileValue(field, modifiers, item)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return "", fmt.Errorf("%w: no values for %v", UnsupportedError, key)
+	}
+
+	return joinTerms(terms, operator), nil
+}
+
+func compileValue(field string,
+	modifiers []string, value interface{}) (string, error) {
+	switch t := value.(type) {
+	case nil:
+		return "NOT " + field, nil
+
+	case int, int64, uint64, float64:
+		if len(modifiers) == 0 {
+			return fmt.Sprintf("%v = %v", field, t), nil
+		}
+		return compileValue(field, modifiers, fmt.Sprintf("%v", t))
+
+	case bool:
+		if t {
+			return field + " = TRUE", nil
+		}
+		return field + " = FALSE", nil
+
+	case string:
+		pattern, err := compilePattern(modifiers, t)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%v =~ %v", field, generate.QuoteString(pattern)), nil
+	}
+
+	return "", fmt.Errorf("%w: invalid value %v", UnsupportedError, value)
+}
+
+// VQL's regex operator is case insensitive which matches Sigma's
+// string comparisons.
+func compilePattern(modifiers []string, value string) (string, error) {
+	prefix := "^"
+	suffix := "$"
+	is_regex := false
+	case_sensitive := false
+
+	for _, modifier := range modifiers {
+		switch modifier {
+		case "all":
+		case "contains":
+			prefix, suffix = "", ""
+		case "startswith":
+			suffix = ""
+		case "endswith":
+			prefix = ""
+		case "re":
+			is_regex = true
+
+			// Sigma regular expressions are case sensitive unless
+			// the i modifier is given.
+			case_sensitive = true
+		case "i":
+			case_sensitive = false
+		case "cased":
+			case_sensitive = true
+		default:
+			return "", fmt.Errorf("%w: modifier %v",
+				UnsupportedError, modifier)
+		}
+	}
+
+	if is_regex {
+		_, err := regexp.Compile(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid regex %v",
+				UnsupportedError, value)
+		}
+
+		value = "(?:" + value + ")"
+	} else {
+		value = prefix + wildcardToRegex(value) + suffix
+	}
+
+	if case_sensitive {
+		return "(?-i)" + value, nil
+	}
+	return value, nil
+}
+
+// Sigma values may contain the wildcards * and ? which may be escaped
+// with a backslash.
+func wildcardToRegex(value string) string {
+	result := &strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch c {
+		case '*':
+			result.WriteString(".*")
+		case '?':
+			result.WriteString(".")
+		case '\\':
+			if i+1 < len(value) && strings.IndexByte(`*?\`, value[i+1]) >= 0 {
+				i++
+				c = value[i]
+			}
+			result.WriteString(regexp.QuoteMeta(string(c)))
+		default:
+			result.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return result.String()
+}
+
+// Artifact names are the rule title in CamelCase.
+func artifactName(title string) string {
+	result := &strings.Builder{}
+	for _, word := range strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		result.WriteRune(unicode.ToUpper(runes[0]))
+		result.WriteString(string(runes[1:]))
+	}
+	return result.String()
+}
+
+func formatArtifact(name string, rule *Rule, profile *Profile,
+	source *SourceMapping, condition string) string {
+	description := rule.Description
+	if description == "" {
+		description = rule.Title
+	}
+	description = strings.TrimSpace(description) + "\n\n"
+
+	if rule.Id != "" {
+		description += fmt.Sprintf("Converted from Sigma rule %v (%v).\n",
+			rule.Title, rule.Id)
+	}
+	if rule.Level != "" {
+		description += fmt.Sprintf("\nLevel: %v\n", rule.Level)
+	}
+	if len(rule.FalsePositives) > 0 {
+		description += "\nFalse positives:\n"
+		for _, item := range rule.FalsePositives {
+			description += fmt.Sprintf("- %v\n", item)
+		}
+	}
+
+	result := &strings.Builder{}
+	fmt.Fprintf(result, "name: %v\n", generate.QuoteYaml(name))
+	fmt.Fprintf(result, "description: |\n%v", generate.Indent(description, 2))
+	if rule.Author != "" {
+		fmt.Fprintf(result, "author: %v\n", generate.QuoteYaml(rule.Author))
+	}
+
+	if len(rule.References) > 0 {
+		result.WriteString("reference:\n")
+		for _, item := range rule.References {
+			fmt.Fprintf(result, "  - %v\n", generate.QuoteYaml(item))
+		}
+	}
+
+	result.WriteString("type: CLIENT_EVENT\n")
+	if profile.Precondition != "" {
+		fmt.Fprintf(result, "precondition: %v\n",
+			generate.QuoteYaml(profile.Precondition))
+	}
+
+	query := fmt.Sprintf(`LET events = %v
+
+SELECT %v AS SigmaTitle, %v AS SigmaLevel, *
+FROM events
+WHERE %v
+`, strings.TrimSpace(source.Query),
+		generate.QuoteString(rule.Title), generate.QuoteString(rule.Level), condition)
+
+	result.WriteString("\nsources:\n  - query: |\n")
+	result.WriteString(generate.Indent(query, 6))
+	return result.String()
+}
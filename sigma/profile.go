@@ -0,0 +1,249 @@
+package sigma
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Velocidex/yaml/v2"
+)
+
+var (
+	identifierRegex = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+)
+
+// A field mapping profile describes how Sigma log sources and fields
+// map to VQL.
+type Profile struct {
+	Name string `json:"name"`
+
+	// Prefix for the names of the generated artifacts.
+	ArtifactPrefix string `json:"artifact_prefix"`
+
+	Precondition string `json:"precondition"`
+
+	// Maps Sigma field names to VQL expressions over the event.
+	Fields map[string]string `json:"fields"`
+
+	// Fields not in the Fields map are formatted with this format
+	// string (e.g. "EventData.%s").
+	DefaultField string `json:"default_field"`
+
+	// An expression producing the text searched by keyword
+	// selections.
+	Keywords string `json:"keywords"`
+
+	Sources []*SourceMapping `json:"sources"`
+}
+
+// Maps a Sigma log source to a VQL query producing the events.
+type SourceMapping struct {
+	LogSource LogSource `json:"logsource"`
+
+	// A VQL query producing the events of this log source, e.g. a
+	// watch_evtx() or watch_etw() query.
+	Query string `json:"query"`
+
+	// Field mappings specific to this source. These override the
+	// profile's fields.
+	Fields map[string]string `json:"fields"`
+}
+
+// How well does the mapping match the log source? Returns -1 if it
+// does not match at all. Otherwise a higher score is a more specific
+// match.
+func (self *SourceMapping) match(source LogSource) int {
+	score := 0
+	for _, item := range []struct{ want, have string }{
+		{self.LogSource.Product, source.Product},
+		{self.LogSource.Service, source.Service},
+		{self.LogSource.Category, source.Category},
+	} {
+		if item.want == "" {
+			continue
+		}
+
+		if !strings.EqualFold(item.want, item.have) {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+func ParseProfile(data []byte) (*Profile, error) {
+	result := &Profile{}
+	err := yaml.UnmarshalStrict(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Sources) == 0 {
+		return nil, fmt.Errorf("Profile %v has no sources", result.Name)
+	}
+
+	for _, source := range result.Sources {
+		if source.Query == "" {
+			return nil, fmt.Errorf("Profile %v: no query for log source %v",
+				result.Name, source.LogSource)
+		}
+	}
+
+	return result, nil
+}
+
+func (self *Profile) findSource(source LogSource) (*SourceMapping, error) {
+	var result *SourceMapping
+	best := 0
+	for _, mapping := range self.Sources {
+		score := mapping.match(source)
+		if score > best {
+			result = mapping
+			best = score
+		}
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("%w: profile %v does not map log source %v",
+			UnsupportedError, self.Name, source)
+	}
+	return result, nil
+}
+
+func (self *Profile) fieldExpression(
+	source *SourceMapping, field string) (string, error) {
+	expression, pres := source.Fields[field]
+	if pres {
+		return expression, nil
+	}
+
+	expression, pres = self.Fields[field]
+	if pres {
+		return expression, nil
+	}
+
+	identifier, err := quoteIdentifier(field)
+	if err != nil {
+		return "", err
+	}
+
+	format := self.DefaultField
+	if format == "" {
+		format = "EventData.%s"
+	}
+	return fmt.Sprintf(format, identifier), nil
+}
+
+// Field names come from the rule so they must not be able to inject
+// VQL. Names which are not plain identifiers are quoted with
+// backticks, which can not themselves be escaped.
+func quoteIdentifier(field string) (string, error) {
+	if identifierRegex.MatchString(field) {
+		return field, nil
+	}
+
+	if field == "" || strings.ContainsAny(field, "`\r\n") {
+		return "", fmt.Errorf("%w: invalid field name %q",
+			UnsupportedError, field)
+	}
+	return "`" + field + "`", nil
+}
+
+func (self *Profile) keywordExpression() string {
+	if self.Keywords != "" {
+		return self.Keywords
+	}
+	return "serialize(item=EventData)"
+}
+
+func DefaultProfile() *Profile {
+	result, err := ParseProfile([]byte(defaultWindowsProfile))
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// Maps the common Windows log sources to event logs and ETW
+// providers.
+const defaultWindowsProfile = `
+name: Windows
+artifact_prefix: Sigma.Windows.
+precondition: SELECT OS FROM info() WHERE OS = 'windows'
+default_field: EventData.%s
+keywords: serialize(item=EventData)
+fields:
+  EventID: System.EventID.Value
+  Provider_Name: System.Provider.Name
+  Channel: System.Channel
+  Computer: System.Computer
+sources:
+- logsource:
+    product: windows
+    service: security
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Security.evtx')
+- logsource:
+    product: windows
+    service: system
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/System.evtx')
+- logsource:
+    product: windows
+    service: application
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Application.evtx')
+- logsource:
+    product: windows
+    service: sysmon
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Microsoft-Windows-Sysmon%4Operational.evtx')
+- logsource:
+    product: windows
+    service: powershell
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Microsoft-Windows-PowerShell%4Operational.evtx')
+- logsource:
+    product: windows
+    service: taskscheduler
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Microsoft-Windows-TaskScheduler%4Operational.evtx')
+- logsource:
+    product: windows
+    category: process_creation
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Microsoft-Windows-Sysmon%4Operational.evtx')
+    WHERE System.EventID.Value = 1
+- logsource:
+    product: windows
+    category: network_connection
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Microsoft-Windows-Sysmon%4Operational.evtx')
+    WHERE System.EventID.Value = 3
+- logsource:
+    product: windows
+    category: image_load
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Microsoft-Windows-Sysmon%4Operational.evtx')
+    WHERE System.EventID.Value = 7
+- logsource:
+    product: windows
+    category: registry_set
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Microsoft-Windows-Sysmon%4Operational.evtx')
+    WHERE System.EventID.Value = 13
+- logsource:
+    product: windows
+    category: ps_script
+  query: |
+    SELECT * FROM watch_evtx(filename='C:/Windows/System32/winevt/Logs/Microsoft-Windows-PowerShell%4Operational.evtx')
+    WHERE System.EventID.Value = 4104
+- logsource:
+    product: windows
+    category: dns_query
+  query: |
+    SELECT * FROM watch_etw(guid='Microsoft-Windows-DNS-Client')
+    WHERE System.ID = 3008
+  fields:
+    EventID: System.ID
+`
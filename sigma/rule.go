@@ -0,0 +1,113 @@
+// Convert Sigma rules to Velociraptor artifacts.
+
+// Sigma (https://github.com/SigmaHQ/sigma) is a generic format for
+// log based detections. A rule describes the log source it applies
+// to and a set of named selections which are combined by a condition
+// expression. We translate the rule into a CLIENT_EVENT artifact
+// which watches the log source (using watch_evtx() or watch_etw())
+// and filters the events with an equivalent VQL expression.
+
+// The mapping from Sigma log sources and field names to VQL sources
+// and expressions is given by a Profile.
+
+package sigma
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Velocidex/yaml/v2"
+)
+
+var (
+	// Returned for rules using Sigma features we can not translate
+	// to VQL. Callers converting rules in bulk should skip these.
+	UnsupportedError = errors.New("Unsupported Sigma rule")
+)
+
+type LogSource struct {
+	Product  string `json:"product,omitempty"`
+	Service  string `json:"service,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+func (self LogSource) String() string {
+	return fmt.Sprintf("product=%v service=%v category=%v",
+		self.Product, self.Service, self.Category)
+}
+
+type Rule struct {
+	Title          string        `json:"title"`
+	Id             string        `json:"id"`
+	Status         string        `json:"status"`
+	Description    string        `json:"description"`
+	Author         string        `json:"author"`
+	References     []string      `json:"references"`
+	Tags           []string      `json:"tags"`
+	Level          string        `json:"level"`
+	FalsePositives []string      `json:"falsepositives"`
+	LogSource      LogSource     `json:"logsource"`
+	Detection      yaml.MapSlice `json:"detection"`
+}
+
+func ParseRule(data []byte) (*Rule, error) {
+	result := &Rule{}
+	err := yaml.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Title == "" {
+		return nil, errors.New("Sigma rule has no title")
+	}
+
+	if len(result.Detection) == 0 {
+		return nil, fmt.Errorf("Sigma rule %v has no detection", result.Title)
+	}
+
+	return result, nil
+}
+
+// The condition may be a single expression or a list of expressions
+// which are alternatives.
+func (self *Rule) conditions() ([]string, error) {
+	for _, item := range self.Detection {
+		if item.Key != "condition" {
+			continue
+		}
+
+		switch t := item.Value.(type) {
+		case string:
+			return []string{t}, nil
+
+		case []interface{}:
+			result := []string{}
+			for _, i := range t {
+				condition, ok := i.(string)
+				if !ok {
+					return nil, fmt.Errorf("%w: invalid condition %v",
+						UnsupportedError, i)
+				}
+				result = append(result, condition)
+			}
+			return result, nil
+		}
+		return nil, fmt.Errorf("%w: invalid condition %v",
+			UnsupportedError, item.Value)
+	}
+
+	return nil, fmt.Errorf("Sigma rule %v has no condition", self.Title)
+}
+
+// The named selections in the order they appear in the rule.
+func (self *Rule) selections() yaml.MapSlice {
+	result := yaml.MapSlice{}
+	for _, item := range self.Detection {
+		name, ok := item.Key.(string)
+		if !ok || name == "condition" || name == "timeframe" {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+package sigma
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/Velocidex/yaml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var processCreationRule = `
+title: Suspicious Encoded PowerShell
+id: 1b4c6d2e-0000-4000-8000-000000000001
+description: Detects PowerShell started with an encoded command.
+author: Test Author
+level: high
+references:
+  - https://example.com/encoded
+falsepositives:
+  - Admin scripts
+logsource:
+  product: windows
+  category: process_creation
+detection:
+  selection_img:
+    Image|endswith:
+      - '\powershell.exe'
+      - '\pwsh.exe'
+  selection_cli:
+    CommandLine|contains|all:
+      - ' -enc'
+      - 'AAAA'
+  filter:
+    ParentImage|startswith: 'C:\Program Files\Admin\'
+  condition: all of selection_* and not filter
+`
+
+func TestConvertRule(t *testing.T) {
+	rule, err := ParseRule([]byte(processCreationRule))
+	require.NoError(t, err)
+
+	artifact, err := Convert(rule, DefaultProfile())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Sigma.Windows.SuspiciousEncodedPowerShell", artifact.Name)
+
+	// The output must be a valid artifact definition.
+	parsed := &struct {
+		Name    string
+		Type    string
+		Author  string
+		Sources []struct{ Query string }
+	}{}
+	require.NoError(t, yaml.Unmarshal([]byte(artifact.Yaml), parsed))
+	assert.Equal(t, artifact.Name, parsed.Name)
+	assert.Equal(t, "CLIENT_EVENT", parsed.Type)
+	assert.Equal(t, "Test Author", parsed.Author)
+	require.Equal(t, 1, len(parsed.Sources))
+
+	query := parsed.Sources[0].Query
+	assert.Contains(t, query, "Microsoft-Windows-Sysmon%4Operational.evtx")
+	assert.Contains(t, query, "WHERE System.EventID.Value = 1")
+	assert.Contains(t, query,
+		`WHERE (((EventData.Image =~ '\\\\powershell\\.exe$' OR `+
+			`EventData.Image =~ '\\\\pwsh\\.exe$') AND `+
+			`(EventData.CommandLine =~ ' -enc' AND EventData.CommandLine =~ 'AAAA')) `+
+			`AND NOT EventData.ParentImage =~ '^C:\\\\Program Files\\\\Admin\\\\')`)
+}
+
+func TestConditions(t *testing.T) {
+	parser := &conditionParser{
+		selections: map[string]string{
+			"sel1":    "A",
+			"sel2":    "B",
+			"_helper": "C",
+			"filter":  "D",
+		},
+		names: []string{"sel1", "sel2", "_helper", "filter"},
+	}
+
+	for _, testcase := range []struct {
+		condition, expected string
+	}{
+		{"sel1", "A"},
+		{"sel1 or sel2 and not filter", "(A OR (B AND NOT D))"},
+		{"(sel1 or sel2) and not filter", "((A OR B) AND NOT D)"},
+		{"1 of sel*", "(A OR B)"},
+		{"all of them", "(A AND B AND D)"},
+		{"any of them and not 1 of filter", "((A OR B OR D) AND NOT D)"},
+	} {
+		result, err := parser.parse(testcase.condition)
+		require.NoError(t, err, testcase.condition)
+		assert.Equal(t, testcase.expected, result, testcase.condition)
+	}
+
+	for _, condition := range []string{
+		"sel1 | count() > 5",
+		"unknown",
+		"(sel1 or sel2",
+		"sel1 sel2",
+		"1 of nothing*",
+	} {
+		_, err := parser.parse(condition)
+		assert.True(t, errors.Is(err, UnsupportedError), condition)
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	for _, testcase := range []struct {
+		modifiers      []string
+		value          string
+		match, nomatch string
+	}{
+		{nil, `*\cmd.exe`, `C:\Windows\CMD.EXE`, `C:\Windows\cmd.exe.bak`},
+		{[]string{"contains"}, `a?c`, `xxabcxx`, `xxacxx`},
+		{[]string{"startswith"}, `\*literal`, `*literal star`, `xliteral`},
+		{[]string{"re"}, `^Evil[0-9]+$`, `Evil123`, `evil123`},
+		{[]string{"re", "i"}, `^Evil[0-9]+$`, `evil123`, `evil`},
+		{[]string{"cased"}, `Evil`, `Evil`, `evil`},
+	} {
+		pattern, err := compilePattern(testcase.modifiers, testcase.value)
+		require.NoError(t, err)
+
+		// VQL's =~ operator is case insensitive.
+		re := regexp.MustCompile("(?i)" + pattern)
+		assert.True(t, re.MatchString(testcase.match), pattern)
+		assert.False(t, re.MatchString(testcase.nomatch), pattern)
+	}
+
+	_, err := compilePattern([]string{"base64offset"}, "x")
+	assert.True(t, errors.Is(err, UnsupportedError))
+}
+
+func TestUnmappedLogSource(t *testing.T) {
+	rule, err := ParseRule([]byte(`
+title: Linux Rule
+logsource:
+  product: linux
+  service: auditd
+detection:
+  selection:
+    type: EXECVE
+  condition: selection
+`))
+	require.NoError(t, err)
+
+	_, err = Convert(rule, DefaultProfile())
+	assert.True(t, errors.Is(err, UnsupportedError))
+}
+
+func TestKeywordsAndETW(t *testing.T) {
+	rule, err := ParseRule([]byte(`
+title: Suspicious DNS Query
+logsource:
+  product: windows
+  category: dns_query
+detection:
+  selection:
+    EventID: 3008
+    QueryName|endswith: '.evil.com'
+  keywords:
+    - "mimikatz"
+  condition: selection or keywords
+`))
+	require.NoError(t, err)
+
+	artifact, err := Convert(rule, DefaultProfile())
+	require.NoError(t, err)
+
+	assert.Contains(t, artifact.Yaml, "watch_etw(guid='Microsoft-Windows-DNS-Client')")
+	assert.Contains(t, artifact.Yaml,
+		`WHERE ((System.ID = 3008 AND EventData.QueryName =~ '\\.evil\\.com$') OR `+
+			`serialize(item=EventData) =~ 'mimikatz')`)
+}
+
+func TestFieldNames(t *testing.T) {
+	profile := DefaultProfile()
+	source := profile.Sources[0]
+
+	for _, item := range []struct{ field, expected string }{
+		{"Image", "EventData.Image"},
+		{"Event ID", "EventData.`Event ID`"},
+		{"x) OR (1 = 1", "EventData.`x) OR (1 = 1`"},
+	} {
+		expression, err := profile.fieldExpression(source, item.field)
+		require.NoError(t, err)
+		assert.Equal(t, item.expected, expression)
+	}
+
+	for _, field := range []string{"x` OR `y", "a\nb", ""} {
+		_, err := profile.fieldExpression(source, field)
+		assert.True(t, errors.Is(err, UnsupportedError), field)
+	}
+}
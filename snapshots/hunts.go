@@ -0,0 +1,20 @@
+package snapshots
+
+import (
+	"google.golang.org/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+// Record the hunt's state. The compiled VQL is large and does not
+// change over the life of the hunt so it is not kept in the history.
+func RecordHunt(config_obj *config_proto.Config, hunt *api_proto.Hunt) error {
+	snapshot := proto.Clone(hunt).(*api_proto.Hunt)
+	if snapshot.StartRequest != nil {
+		snapshot.StartRequest.CompiledCollectorArgs = nil
+	}
+
+	path_manager := paths.NewHuntPathManager(hunt.HuntId)
+	return Record(config_obj, path_manager.History(), snapshot)
+}
@@ -0,0 +1,82 @@
+// Point in time views of server objects.
+
+// Objects such as the client's labels, the hunts and the client
+// monitoring table are stored as a single record which is overwritten
+// each time the object changes. For post incident reviews we need to
+// know what these objects looked like at the time, so each change
+// also appends a snapshot of the new state to a history result set
+// next to the object.
+package snapshots
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"google.golang.org/protobuf/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/result_sets"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+var (
+	Clock utils.Clock = &utils.RealClock{}
+)
+
+// Append a snapshot of the object to its history.
+func Record(config_obj *config_proto.Config,
+	path api.FSPathSpec, snapshot interface{}) error {
+	message, ok := snapshot.(proto.Message)
+	if ok {
+		snapshot = json.ConvertProtoToOrderedDict(message)
+	}
+
+	journal, err := services.GetJournal(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return journal.AppendToResultSet(config_obj, path,
+		[]*ordereddict.Dict{
+			ordereddict.NewDict().
+				Set("Timestamp", Clock.Now().Unix()).
+				Set("Snapshot", snapshot),
+		})
+}
+
+// Get the snapshots in the history. If at is specified we only return
+// the snapshot which was current at that time (if any).
+func GetHistory(ctx context.Context,
+	config_obj *config_proto.Config,
+	path api.FSPathSpec, at time.Time) ([]*ordereddict.Dict, error) {
+	file_store_factory := file_store.GetFileStore(config_obj)
+	reader, err := result_sets.NewResultSetReader(file_store_factory, path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var result []*ordereddict.Dict
+	for row := range reader.Rows(ctx) {
+		if at.IsZero() {
+			result = append(result, row)
+			continue
+		}
+
+		timestamp_any, _ := row.Get("Timestamp")
+		timestamp, _ := utils.ToInt64(timestamp_any)
+
+		// Snapshots are stored in time order so we are done once we
+		// pass the time.
+		if timestamp > at.Unix() {
+			break
+		}
+		result = []*ordereddict.Dict{row}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package snapshots_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/snapshots"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+type SnapshotsTestSuite struct {
+	test_utils.TestSuite
+	clock *utils.MockClock
+}
+
+func (self *SnapshotsTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	self.clock = &utils.MockClock{}
+	snapshots.Clock = self.clock
+}
+
+func (self *SnapshotsTestSuite) TearDownTest() {
+	snapshots.Clock = &utils.RealClock{}
+	self.TestSuite.TearDownTest()
+}
+
+func (self *SnapshotsTestSuite) TestLabelHistory() {
+	ctx := context.Background()
+	client_id := "C.1234"
+	labeler := services.GetLabeler(self.ConfigObj)
+
+	self.clock.MockNow = time.Unix(10, 0)
+	err := labeler.SetClientLabel(ctx, self.ConfigObj, client_id, "Label1")
+	assert.NoError(self.T(), err)
+
+	self.clock.MockNow = time.Unix(20, 0)
+	err = labeler.SetClientLabel(ctx, self.ConfigObj, client_id, "Label2")
+	assert.NoError(self.T(), err)
+
+	self.clock.MockNow = time.Unix(30, 0)
+	err = labeler.RemoveClientLabel(ctx, self.ConfigObj, client_id, "Label1")
+	assert.NoError(self.T(), err)
+
+	path := paths.NewClientPathManager(client_id).LabelHistory()
+
+	// The full history.
+	rows, err := snapshots.GetHistory(ctx, self.ConfigObj, path, time.Time{})
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(rows))
+
+	// The labels as they were in between the changes.
+	rows, err = snapshots.GetHistory(ctx, self.ConfigObj, path, time.Unix(25, 0))
+	require.NoError(self.T(), err)
+	require.Equal(self.T(), 1, len(rows))
+
+	labels, _ := rows[0].Get("Snapshot")
+	assert.Equal(self.T(), []interface{}{"Label1", "Label2"}, labels)
+
+	// Nothing is known before the first change.
+	rows, err = snapshots.GetHistory(ctx, self.ConfigObj, path, time.Unix(5, 0))
+	require.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(rows))
+}
+
+func TestSnapshots(t *testing.T) {
+	suite.Run(t, &SnapshotsTestSuite{})
+}
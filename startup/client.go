@@ -6,8 +6,10 @@ import (
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/executor"
 	"www.velocidex.com/golang/velociraptor/http_comms"
+	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/orgs"
+	"www.velocidex.com/golang/velociraptor/vql/tools"
 )
 
 // StartClientServices starts the various services needed by the
@@ -43,12 +45,34 @@ func StartClientServices(
 		return sm, err
 	}
 
-	_, err = http_comms.StartHttpCommunicatorService(
+	comm, err := http_comms.StartHttpCommunicatorService(
 		ctx, sm.Wg, config_obj, exe, on_error)
 	if err != nil {
 		return sm, err
 	}
 
+	// Follow the frontends when the server moves them.
+	if comm != nil {
+		sm.Wg.Add(1)
+		go func() {
+			defer sm.Wg.Done()
+
+			logger := logging.GetLogger(config_obj, &logging.ClientComponent)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case urls := <-tools.ServerUrlsUpdate:
+					err := comm.SetServerUrls(urls)
+					if err != nil {
+						logger.Error("SetServerUrls: %v", err)
+					}
+				}
+			}
+		}()
+	}
+
 	err = executor.StartEventTableService(
 		ctx, sm.Wg, config_obj, exe.Outbound)
 
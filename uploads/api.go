@@ -26,6 +26,12 @@ type UploadResponse struct {
 	Type string `json:"Type,omitempty"`
 }
 
+// The components of the stored file. Allows the column type of the
+// response to be inferred without importing this package.
+func (self *UploadResponse) UploadComponents() []string {
+	return self.Components
+}
+
 // Provide an uploader capable of uploading any reader object.
 type Uploader interface {
 	Upload(ctx context.Context,
@@ -3,9 +3,11 @@ package uploads
 import (
 	"context"
 	"crypto/md5"
+	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"hash"
 	"io"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	"www.velocidex.com/golang/velociraptor/constants"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
 	"www.velocidex.com/golang/velociraptor/responder"
 	"www.velocidex.com/golang/vfilter"
 )
@@ -25,6 +28,27 @@ var (
 type VelociraptorUploader struct {
 	Responder *responder.Responder
 	Count     int
+
+	// If set, the last packet of each upload carries the file hash
+	// signed with this key.
+	SigningKey *rsa.PrivateKey
+}
+
+// Attach the hash of the uploaded data and its signature to the last
+// packet of the upload.
+func (self *VelociraptorUploader) signPacket(
+	packet *actions_proto.FileBuffer, sha_sum hash.Hash) error {
+	if self.SigningKey == nil {
+		return nil
+	}
+
+	packet.Sha256 = hex.EncodeToString(sha_sum.Sum(nil))
+	signature, err := crypto_utils.SignSha256(self.SigningKey, packet.Sha256)
+	if err != nil {
+		return err
+	}
+	packet.Signature = signature
+	return nil
 }
 
 func (self *VelociraptorUploader) Upload(
@@ -99,6 +123,14 @@ func (self *VelociraptorUploader) Upload(
 			Data:       data,
 		}
 
+		// The last packet is always empty.
+		if read_bytes == 0 {
+			err = self.signPacket(packet, sha_sum)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		select {
 		case <-ctx.Done():
 			return nil, errors.New("Cancelled!")
@@ -207,20 +239,27 @@ func (self *VelociraptorUploader) maybeUploadSparse(
 			index = nil
 		}
 
-		self.Responder.AddResponse(ctx, &crypto_proto.VeloMessage{
-			RequestId: constants.TransferWellKnownFlowId,
-			FileBuffer: &actions_proto.FileBuffer{
-				Pathspec: &actions_proto.PathSpec{
-					Path:     store_as_name.String(),
-					Accessor: accessor,
-				},
-				Size:       uint64(real_size),
-				StoredSize: 0,
-				IsSparse:   is_sparse,
-				Index:      index,
-				Mtime:      mtime.UnixNano(),
-				Eof:        true,
+		packet := &actions_proto.FileBuffer{
+			Pathspec: &actions_proto.PathSpec{
+				Path:     store_as_name.String(),
+				Accessor: accessor,
 			},
+			Size:       uint64(real_size),
+			StoredSize: 0,
+			IsSparse:   is_sparse,
+			Index:      index,
+			Mtime:      mtime.UnixNano(),
+			Eof:        true,
+		}
+
+		err := self.signPacket(packet, sha_sum)
+		if err != nil {
+			return nil, err
+		}
+
+		self.Responder.AddResponse(ctx, &crypto_proto.VeloMessage{
+			RequestId:  constants.TransferWellKnownFlowId,
+			FileBuffer: packet,
 		})
 
 		result.Size = uint64(real_size)
@@ -315,20 +354,27 @@ func (self *VelociraptorUploader) maybeUploadSparse(
 	// Send an EOF as the last packet with no data. If the file
 	// was sparse, also include the index in this packet. NOTE:
 	// There should be only one EOF packet.
-	self.Responder.AddResponse(ctx, &crypto_proto.VeloMessage{
-		RequestId: constants.TransferWellKnownFlowId,
-		FileBuffer: &actions_proto.FileBuffer{
-			Pathspec: &actions_proto.PathSpec{
-				Path:     store_as_name.String(),
-				Accessor: accessor,
-			},
-			Size:       uint64(real_size),
-			StoredSize: uint64(expected_size),
-			IsSparse:   is_sparse,
-			Offset:     uint64(write_offset),
-			Index:      index,
-			Eof:        true,
+	packet := &actions_proto.FileBuffer{
+		Pathspec: &actions_proto.PathSpec{
+			Path:     store_as_name.String(),
+			Accessor: accessor,
 		},
+		Size:       uint64(real_size),
+		StoredSize: uint64(expected_size),
+		IsSparse:   is_sparse,
+		Offset:     uint64(write_offset),
+		Index:      index,
+		Eof:        true,
+	}
+
+	err := self.signPacket(packet, sha_sum)
+	if err != nil {
+		return nil, err
+	}
+
+	self.Responder.AddResponse(ctx, &crypto_proto.VeloMessage{
+		RequestId:  constants.TransferWellKnownFlowId,
+		FileBuffer: packet,
 	})
 
 	result.Size = uint64(real_size)
@@ -46,3 +46,73 @@ func Uncompress(
 
 	return result.Bytes(), nil
 }
+
+// Decompress a raw LZ4 block (as written by LZ4_compress_default()).
+func LZ4DecompressBlock(src []byte, size int) ([]byte, error) {
+	dst := make([]byte, 0, size)
+	corrupt := errors.New("Corrupted LZ4 block")
+
+	readLength := func(i int, length int) (int, int, error) {
+		if length != 15 {
+			return i, length, nil
+		}
+		for {
+			if i >= len(src) {
+				return 0, 0, corrupt
+			}
+			b := src[i]
+			i++
+			length += int(b)
+			if b != 255 {
+				return i, length, nil
+			}
+		}
+	}
+
+	var literals, match_length int
+	var err error
+
+	i := 0
+	for i < len(src) {
+		token := src[i]
+		i++
+
+		// Literals
+		i, literals, err = readLength(i, int(token>>4))
+		if err != nil || i+literals > len(src) {
+			return nil, corrupt
+		}
+		dst = append(dst, src[i:i+literals]...)
+		i += literals
+
+		// The last sequence only contains literals.
+		if i >= len(src) {
+			break
+		}
+
+		// Match
+		if i+2 > len(src) {
+			return nil, corrupt
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+
+		i, match_length, err = readLength(i, int(token&15))
+		if err != nil || offset == 0 || offset > len(dst) {
+			return nil, corrupt
+		}
+		match_length += 4
+
+		if len(dst)+match_length > size {
+			return nil, corrupt
+		}
+
+		// Matches may overlap the output so copy byte by byte.
+		start := len(dst) - offset
+		for j := 0; j < match_length; j++ {
+			dst = append(dst, dst[start+j])
+		}
+	}
+
+	return dst, nil
+}
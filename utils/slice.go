@@ -31,3 +31,14 @@ func ConvertToStringSlice(a interface{}) []string {
 	}
 	return result
 }
+
+// The items of a followed by the items of b which are not in a.
+func MergeStringSlices(a, b []string) []string {
+	result := append([]string{}, a...)
+	for _, item := range b {
+		if !InString(result, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+// Infer column types from the values produced by a query.
+
+// Once rows are serialized to JSON much of the type information is
+// lost (e.g. timestamps become strings). We record the type of each
+// column as the rows are produced so the type can be stored with the
+// result set and passed to the GUI.
+
+package vql
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	COLUMN_TYPE_TIMESTAMP = "timestamp"
+	COLUMN_TYPE_INTEGER   = "integer"
+	COLUMN_TYPE_FLOAT     = "float"
+	COLUMN_TYPE_PATHSPEC  = "pathspec"
+	COLUMN_TYPE_BYTES     = "bytes"
+
+	// The response from upload(). Rendered as a download link.
+	COLUMN_TYPE_UPLOAD = "upload"
+
+	// Columns with values of different types.
+	COLUMN_TYPE_ANY = "any"
+)
+
+// Implemented by *accessors.OSPath (we can not import accessors
+// here).
+type pathspecLike interface {
+	DelegatePath() string
+	DelegateAccessor() string
+}
+
+// Implemented by *uploads.UploadResponse
+type uploadLike interface {
+	UploadComponents() []string
+}
+
+// Returns the column type of the value or "" if it has no special
+// type.
+func InferColumnType(value interface{}) string {
+	switch value.(type) {
+	case time.Time, *time.Time:
+		return COLUMN_TYPE_TIMESTAMP
+
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return COLUMN_TYPE_INTEGER
+
+	case float32, float64:
+		return COLUMN_TYPE_FLOAT
+
+	case []byte:
+		return COLUMN_TYPE_BYTES
+
+	case pathspecLike:
+		return COLUMN_TYPE_PATHSPEC
+
+	case uploadLike:
+		return COLUMN_TYPE_UPLOAD
+	}
+
+	return ""
+}
+
+// Tracks the types of columns over many rows.
+type ColumnTypeTracker struct {
+	mu    sync.Mutex
+	types map[string]string
+}
+
+func (self *ColumnTypeTracker) Observe(row *ordereddict.Dict) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for _, k := range row.Keys() {
+		v, _ := row.Get(k)
+		self.observe(k, v)
+	}
+}
+
+func (self *ColumnTypeTracker) observe(column string, value interface{}) {
+	// Nulls do not tell us anything about the column.
+	switch t := value.(type) {
+	case nil, vfilter.Null, *vfilter.Null:
+		return
+	case string:
+		if t == "" {
+			return
+		}
+	}
+
+	new_type := InferColumnType(value)
+	old_type, pres := self.types[column]
+	if pres {
+		new_type = combineTypes(old_type, new_type)
+	}
+	self.types[column] = new_type
+}
+
+func combineTypes(old_type, new_type string) string {
+	if old_type == new_type {
+		return old_type
+	}
+
+	// Integers and floats are both numbers.
+	if (old_type == COLUMN_TYPE_INTEGER && new_type == COLUMN_TYPE_FLOAT) ||
+		(old_type == COLUMN_TYPE_FLOAT && new_type == COLUMN_TYPE_INTEGER) {
+		return COLUMN_TYPE_FLOAT
+	}
+	return COLUMN_TYPE_ANY
+}
+
+// Merge types recorded elsewhere (e.g. previously stored with the
+// result set).
+func (self *ColumnTypeTracker) Merge(types map[string]string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for column, new_type := range types {
+		old_type, pres := self.types[column]
+		if pres {
+			new_type = combineTypes(old_type, new_type)
+		}
+		self.types[column] = new_type
+	}
+}
+
+// Returns the type of each column seen so far. Columns without a
+// special type are "" and columns with mixed types are
+// COLUMN_TYPE_ANY.
+func (self *ColumnTypeTracker) Types() map[string]string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := make(map[string]string)
+	for column, t := range self.types {
+		result[column] = t
+	}
+	return result
+}
+
+func NewColumnTypeTracker() *ColumnTypeTracker {
+	return &ColumnTypeTracker{
+		types: make(map[string]string),
+	}
+}
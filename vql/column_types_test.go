@@ -0,0 +1,44 @@
+package vql_test
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+func TestUploadColumnType(t *testing.T) {
+	upload := &uploads.UploadResponse{
+		Path:       "/etc/passwd",
+		Size:       10,
+		Components: []string{"clients", "C.1", "uploads", "passwd"},
+	}
+
+	assert.Equal(t, vql_subsystem.COLUMN_TYPE_UPLOAD,
+		vql_subsystem.InferColumnType(upload))
+
+	// A dict with the same fields is not an upload.
+	lookalike := ordereddict.NewDict().
+		Set("Path", "/etc/passwd").
+		Set("Size", 10).
+		Set("Components", []string{"clients", "C.1", "uploads", "passwd"})
+	assert.Equal(t, "", vql_subsystem.InferColumnType(lookalike))
+
+	tracker := vql_subsystem.NewColumnTypeTracker()
+	tracker.Observe(ordereddict.NewDict().
+		Set("Upload", upload).
+		Set("Dict", lookalike).
+		Set("Mixed", upload))
+	tracker.Observe(ordereddict.NewDict().
+		Set("Upload", upload).
+		Set("Dict", ordereddict.NewDict()).
+		Set("Mixed", lookalike))
+
+	assert.Equal(t, map[string]string{
+		"Upload": vql_subsystem.COLUMN_TYPE_UPLOAD,
+		"Dict":   "",
+		"Mixed":  vql_subsystem.COLUMN_TYPE_ANY,
+	}, tracker.Types())
+}
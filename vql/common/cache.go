@@ -26,6 +26,28 @@ type _CacheObj struct {
 	ctx        context.Context
 	key        string
 	cache      map[string]vfilter.Any
+
+	// Memory charged to the query's memory budget.
+	charged uint64
+}
+
+// Drop all the cached items. Must be called with the lock held.
+func (self *_CacheObj) reset() {
+	self.cache = make(map[string]vfilter.Any)
+	vql_subsystem.ReleaseMemory(self.scope, self.charged)
+	self.charged = 0
+}
+
+// Account for the item in the memory budget. Must be called with the
+// lock held.
+func (self *_CacheObj) charge(key string, value vfilter.Any) error {
+	size := vql_subsystem.EstimateSize(key) + vql_subsystem.EstimateSize(value)
+	err := vql_subsystem.ChargeMemory(self.scope, "cache", size)
+	if err != nil {
+		return err
+	}
+	self.charged += size
+	return nil
 }
 
 func (self *_CacheObj) Get(key string) (vfilter.Any, bool) {
@@ -34,7 +56,7 @@ func (self *_CacheObj) Get(key string) (vfilter.Any, bool) {
 
 	// Expire the entire cache when it gets too old.
 	if time.Now().After(self.expires) {
-		self.cache = make(map[string]vfilter.Any)
+		self.reset()
 		self.expires = time.Now().Add(self.period)
 		return nil, false
 	}
@@ -47,6 +69,12 @@ func (self *_CacheObj) Set(key string, value vfilter.Any) {
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
+	err := self.charge(key, value)
+	if err != nil {
+		self.scope.Log("cache: %v", err)
+		return
+	}
+
 	self.cache[key] = value
 }
 
@@ -54,12 +82,17 @@ func (self *_CacheObj) Materialize() {
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
-	self.cache = make(map[string]vfilter.Any)
+	self.reset()
 	stored_query := arg_parser.ToStoredQuery(self.ctx, self.expression)
 	for row_item := range stored_query.Eval(self.ctx, self.scope) {
 		key, pres := self.scope.Associative(row_item, self.key)
 		if pres {
 			key_str := json.StringIndent(key)
+			err := self.charge(key_str, row_item)
+			if err != nil {
+				self.scope.Log("memoize: %v", err)
+				return
+			}
 			self.cache[key_str] = row_item
 		}
 	}
@@ -0,0 +1,80 @@
+package common
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+	"www.velocidex.com/golang/vfilter/types"
+)
+
+type CapabilitiesPlugin struct{}
+
+func (self CapabilitiesPlugin) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("capabilities: %v", err)
+			return
+		}
+
+		arg := &vfilter.Empty{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("capabilities: %v", err)
+			return
+		}
+
+		info := scope.Describe(types.NewTypeMap())
+
+		plugins := make([]string, 0, len(info.Plugins))
+		for _, plugin := range info.Plugins {
+			plugins = append(plugins, plugin.Name)
+		}
+		sort.Strings(plugins)
+
+		functions := make([]string, 0, len(info.Functions))
+		for _, function := range info.Functions {
+			functions = append(functions, function.Name)
+		}
+		sort.Strings(functions)
+
+		accessor_names := accessors.DescribeAccessors().Keys()
+		sort.Strings(accessor_names)
+
+		select {
+		case <-ctx.Done():
+		case output_chan <- ordereddict.NewDict().
+			Set("Plugins", plugins).
+			Set("Functions", functions).
+			Set("Accessors", accessor_names).
+			Set("Features", vql_subsystem.GetFeatures()):
+		}
+	}()
+
+	return output_chan
+}
+
+func (self CapabilitiesPlugin) Info(
+	scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name: "capabilities",
+		Doc:  "Report the plugins, functions, accessors and features this binary supports.",
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&CapabilitiesPlugin{})
+}
@@ -302,7 +302,7 @@ func (self *scanReporter) scanRange(start, end uint64, f accessors.ReadSeekClose
 		_, _ = f.Seek(int64(self.base_offset), 0)
 
 		// Only read up to the end of the range
-		to_read := end - start
+		to_read := end - self.base_offset
 		if to_read > self.blocksize {
 			to_read = self.blocksize
 		}
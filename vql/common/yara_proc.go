@@ -0,0 +1,185 @@
+// +build cgo,yara
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	yara "github.com/Velocidex/go-yara"
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/accessors/process"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type YaraProcRegionPluginArgs struct {
+	Rules         string            `vfilter:"required,field=rules,doc=Yara rules"`
+	Pid           uint64            `vfilter:"required,field=pid,doc=The pid to scan"`
+	Types         []string          `vfilter:"optional,field=types,doc=Only scan regions of these types (private, image, mapped)"`
+	Protection    string            `vfilter:"optional,field=protection,doc=Only scan regions with all these permissions (e.g. x or rw)"`
+	MinSize       uint64            `vfilter:"optional,field=min_size,doc=Skip regions smaller than this"`
+	MaxSize       uint64            `vfilter:"optional,field=max_size,doc=Skip regions larger than this"`
+	Context       int               `vfilter:"optional,field=context,doc=Return this many bytes either side of a hit"`
+	Key           string            `vfilter:"optional,field=key,doc=If set use this key to cache the  yara rules."`
+	Namespace     string            `vfilter:"optional,field=namespace,doc=The Yara namespece to use."`
+	YaraVariables *ordereddict.Dict `vfilter:"optional,field=vars,doc=The Yara variables to use."`
+	NumberOfHits  int64             `vfilter:"optional,field=number,doc=Stop after this many hits (1)."`
+	Blocksize     uint64            `vfilter:"optional,field=blocksize,doc=Blocksize for scanning (1mb)."`
+}
+
+// Scans only the selected memory regions of a process. Unlike
+// proc_yara() each region is scanned separately so hits can be
+// reported with the region they were found in.
+type YaraProcRegionPlugin struct{}
+
+func (self YaraProcRegionPlugin) Info(
+	scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "yara_proc",
+		Doc:     "Scan selected memory regions of a process using yara rules.",
+		ArgType: type_map.AddType(scope, &YaraProcRegionPluginArgs{}),
+	}
+}
+
+func (self YaraProcRegionPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("yara_proc: %v", err)
+			return
+		}
+
+		arg := &YaraProcRegionPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("yara_proc: %v", err)
+			return
+		}
+
+		if arg.NumberOfHits == 0 {
+			arg.NumberOfHits = 1
+		}
+
+		if arg.Blocksize == 0 {
+			arg.Blocksize = 1024 * 1024
+		}
+
+		rules, err := getYaraRules(arg.Key, arg.Namespace,
+			arg.Rules, arg.YaraVariables, scope)
+		if err != nil {
+			scope.Log("yara_proc: %v", err)
+			return
+		}
+
+		regions, err := process.GetRegions(arg.Pid)
+		if err != nil {
+			scope.Log("yara_proc: pid %v: %v", arg.Pid, err)
+			return
+		}
+
+		accessor, err := accessors.GetAccessor("process", scope)
+		if err != nil {
+			scope.Log("yara_proc: %v", err)
+			return
+		}
+
+		path, err := accessor.ParsePath(fmt.Sprintf("/%d", arg.Pid))
+		if err != nil {
+			scope.Log("yara_proc: %v", err)
+			return
+		}
+
+		fd, err := accessor.OpenWithOSPath(path)
+		if err != nil {
+			scope.Log("yara_proc: pid %v: %v", arg.Pid, err)
+			return
+		}
+		defer fd.Close()
+
+		yara_flag := yara.ScanFlags(0)
+		if arg.NumberOfHits == 1 {
+			yara_flag = yara.ScanFlagsFastMode
+		}
+
+		matcher := &scanReporter{
+			number_of_hits: arg.NumberOfHits,
+			blocksize:      arg.Blocksize,
+			context:        arg.Context,
+			ctx:            ctx,
+
+			rules:     rules,
+			scope:     scope,
+			yara_flag: yara_flag,
+		}
+
+		filter := process.RegionFilter{
+			Types:      arg.Types,
+			Protection: arg.Protection,
+			MinSize:    arg.MinSize,
+			MaxSize:    arg.MaxSize,
+		}
+
+		for _, region := range regions {
+			if !filter.Matches(region) {
+				continue
+			}
+
+			if matcher.number_of_hits <= 0 || ctx.Err() != nil {
+				return
+			}
+
+			hits := make(chan vfilter.Row)
+			matcher.output_chan = hits
+
+			go func() {
+				defer close(hits)
+				matcher.scanRange(region.Address,
+					region.Address+region.Size, fd)
+			}()
+
+			// Always drain the hits so the scanner is done with
+			// the process before the next region.
+			for hit := range hits {
+				res, ok := hit.(*YaraResult)
+				if !ok {
+					continue
+				}
+
+				row := ordereddict.NewDict().
+					Set("Pid", arg.Pid).
+					Set("Rule", res.Rule).
+					Set("Tags", res.Tags).
+					Set("Meta", res.Meta).
+					Set("String", res.String).
+					Set("Region", region)
+
+				if res.String != nil {
+					row.Set("RegionOffset", res.String.Offset-region.Address)
+				}
+
+				select {
+				case <-ctx.Done():
+				case output_chan <- row:
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&YaraProcRegionPlugin{})
+}
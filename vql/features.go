@@ -0,0 +1,46 @@
+package vql
+
+import (
+	"sort"
+	"sync"
+)
+
+// Features describe the client's environment beyond the plugins,
+// functions and accessors compiled into it. For example a plugin may
+// be present but only work when the client runs elevated. Each
+// feature is probed when the client's capabilities are reported.
+var (
+	features_mu      sync.Mutex
+	exportedFeatures = make(map[string]func() bool)
+)
+
+func RegisterFeature(name string, probe func() bool) {
+	features_mu.Lock()
+	defer features_mu.Unlock()
+
+	_, pres := exportedFeatures[name]
+	if pres {
+		panic("Multiple features defined")
+	}
+
+	exportedFeatures[name] = probe
+}
+
+// Returns the sorted names of features available right now.
+func GetFeatures() []string {
+	features_mu.Lock()
+	defer features_mu.Unlock()
+
+	result := make([]string, 0, len(exportedFeatures))
+	for name, probe := range exportedFeatures {
+		if probe() {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func init() {
+	RegisterFeature("admin", IsAdmin)
+}
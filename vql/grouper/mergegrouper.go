@@ -92,7 +92,11 @@ func (self *MergeSortGrouper) groupWithSorting(
 	output_chan chan types.Row,
 	actor types.GroupbyActor) {
 
-	group_sorter := &sorter.MergeSorter{ChunkSize: self.ChunkSize}
+	tmpdir, _ := sorter.GetSpillOptions(self.config_obj)
+	group_sorter := &sorter.MergeSorter{
+		ChunkSize: self.ChunkSize,
+		TempDir:   tmpdir,
+	}
 	row_chan := make(chan types.Row)
 
 	sorted_rows := group_sorter.Sort(ctx, scope, row_chan, GROUPBY_COLUMN, false)
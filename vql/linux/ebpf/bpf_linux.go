@@ -0,0 +1,261 @@
+// +build linux
+
+package ebpf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Registers
+const (
+	R0  = 0
+	R1  = 1
+	R2  = 2
+	R3  = 3
+	R4  = 4
+	R5  = 5
+	R6  = 6
+	R10 = 10
+)
+
+// Helper function ids from include/uapi/linux/bpf.h
+const (
+	FN_probe_read           = 4
+	FN_get_current_pid_tgid = 14
+	FN_get_current_uid_gid  = 15
+	FN_get_current_comm     = 16
+	FN_perf_event_output    = 25
+	FN_probe_read_str       = 45
+	FN_probe_read_user      = 112
+	FN_probe_read_user_str  = 114
+)
+
+const (
+	BPF_PSEUDO_MAP_FD = 1
+
+	verifierLogSize = 64 * 1024
+
+	// Only report the end of the verifier log, which has the
+	// reason the program was rejected.
+	maxVerifierLogInError = 1024
+)
+
+// A single eBPF instruction (struct bpf_insn).
+type instruction struct {
+	Code uint8
+	Regs uint8
+	Off  int16
+	Imm  int32
+}
+
+func insn(code uint8, dst, src uint8, off int16, imm int32) instruction {
+	return instruction{Code: code, Regs: dst | src<<4, Off: off, Imm: imm}
+}
+
+func mov64Reg(dst, src uint8) instruction { return insn(0xbf, dst, src, 0, 0) }
+func mov64Imm(dst uint8, imm int32) instruction {
+	return insn(0xb7, dst, 0, 0, imm)
+}
+
+// 32 bit moves zero the upper half of the register.
+func mov32Imm(dst uint8, imm int32) instruction {
+	return insn(0xb4, dst, 0, 0, imm)
+}
+func add64Imm(dst uint8, imm int32) instruction {
+	return insn(0x07, dst, 0, 0, imm)
+}
+func ldxDW(dst, src uint8, off int16) instruction {
+	return insn(0x79, dst, src, off, 0)
+}
+func stxDW(dst, src uint8, off int16) instruction {
+	return insn(0x7b, dst, src, off, 0)
+}
+func stxW(dst, src uint8, off int16) instruction {
+	return insn(0x63, dst, src, off, 0)
+}
+func stDW(dst uint8, off int16, imm int32) instruction {
+	return insn(0x7a, dst, 0, off, imm)
+}
+func call(fn int32) instruction { return insn(0x85, 0, 0, 0, fn) }
+func exit() instruction         { return insn(0x95, 0, 0, 0, 0) }
+
+// Loading a map fd takes two instructions.
+func ldMapFd(dst uint8, fd int) []instruction {
+	return []instruction{
+		insn(0x18, dst, BPF_PSEUDO_MAP_FD, 0, int32(fd)),
+		insn(0, 0, 0, 0, 0),
+	}
+}
+
+func bpf(cmd int, attr unsafe.Pointer, size uintptr) (int, error) {
+	fd, _, errno := unix.Syscall(unix.SYS_BPF, uintptr(cmd),
+		uintptr(attr), size)
+	if errno != 0 {
+		return -1, errno
+	}
+	return int(fd), nil
+}
+
+type mapCreateAttr struct {
+	MapType    uint32
+	KeySize    uint32
+	ValueSize  uint32
+	MaxEntries uint32
+	Flags      uint32
+}
+
+func createPerfEventArray(max_entries int) (int, error) {
+	attr := mapCreateAttr{
+		MapType:    unix.BPF_MAP_TYPE_PERF_EVENT_ARRAY,
+		KeySize:    4,
+		ValueSize:  4,
+		MaxEntries: uint32(max_entries),
+	}
+	fd, err := bpf(unix.BPF_MAP_CREATE, unsafe.Pointer(&attr),
+		unsafe.Sizeof(attr))
+	if err != nil {
+		return -1, fmt.Errorf("Creating perf event array: %w", err)
+	}
+	return fd, nil
+}
+
+type mapUpdateAttr struct {
+	MapFd uint32
+	_     uint32
+	Key   uint64
+	Value uint64
+	Flags uint64
+}
+
+func updateMap(map_fd int, key, value uint32) error {
+	attr := mapUpdateAttr{
+		MapFd: uint32(map_fd),
+		Key:   uint64(uintptr(unsafe.Pointer(&key))),
+		Value: uint64(uintptr(unsafe.Pointer(&value))),
+	}
+	_, err := bpf(unix.BPF_MAP_UPDATE_ELEM, unsafe.Pointer(&attr),
+		unsafe.Sizeof(attr))
+	runtime.KeepAlive(&key)
+	runtime.KeepAlive(&value)
+	return err
+}
+
+type progLoadAttr struct {
+	ProgType    uint32
+	InsnCnt     uint32
+	Insns       uint64
+	License     uint64
+	LogLevel    uint32
+	LogSize     uint32
+	LogBuf      uint64
+	KernVersion uint32
+	ProgFlags   uint32
+}
+
+func loadProgram(insns []instruction) (int, error) {
+	code := &bytes.Buffer{}
+	err := binary.Write(code, binary.LittleEndian, insns)
+	if err != nil {
+		return -1, err
+	}
+	code_bytes := code.Bytes()
+	license := []byte("GPL\x00")
+
+	attr := progLoadAttr{
+		ProgType: unix.BPF_PROG_TYPE_TRACEPOINT,
+		InsnCnt:  uint32(len(insns)),
+		Insns:    uint64(uintptr(unsafe.Pointer(&code_bytes[0]))),
+		License:  uint64(uintptr(unsafe.Pointer(&license[0]))),
+	}
+
+	fd, err := bpf(unix.BPF_PROG_LOAD, unsafe.Pointer(&attr),
+		unsafe.Sizeof(attr))
+	if err == nil {
+		runtime.KeepAlive(code_bytes)
+		runtime.KeepAlive(license)
+		return fd, nil
+	}
+
+	// Load again with the verifier log to report why it failed.
+	log := make([]byte, verifierLogSize)
+	attr.LogLevel = 1
+	attr.LogSize = uint32(len(log))
+	attr.LogBuf = uint64(uintptr(unsafe.Pointer(&log[0])))
+	fd, log_err := bpf(unix.BPF_PROG_LOAD, unsafe.Pointer(&attr),
+		unsafe.Sizeof(attr))
+	runtime.KeepAlive(code_bytes)
+	runtime.KeepAlive(license)
+	runtime.KeepAlive(log)
+
+	if log_err == nil {
+		return fd, nil
+	}
+
+	verifier_log := strings.TrimSpace(cString(log))
+	if len(verifier_log) > maxVerifierLogInError {
+		verifier_log = verifier_log[len(verifier_log)-maxVerifierLogInError:]
+	}
+	return -1, fmt.Errorf("Loading program: %w: %v", err, verifier_log)
+}
+
+// Tracefs may be mounted in either place.
+func tracefsRoot() (string, error) {
+	for _, root := range []string{
+		"/sys/kernel/tracing", "/sys/kernel/debug/tracing"} {
+		_, err := os.ReadFile(filepath.Join(root, "events/header_page"))
+		if err == nil {
+			return root, nil
+		}
+	}
+	return "", fmt.Errorf("tracefs is not mounted")
+}
+
+func getFormat(root, category, name string) (*Format, error) {
+	data, err := os.ReadFile(
+		filepath.Join(root, "events", category, name, "format"))
+	if err != nil {
+		return nil, err
+	}
+	return parseFormat(string(data))
+}
+
+// Attach the program to the tracepoint. The program runs for the
+// tracepoint on all CPUs.
+func attachTracepoint(format *Format, prog_fd int) (int, error) {
+	attr := &unix.PerfEventAttr{
+		Type:        unix.PERF_TYPE_TRACEPOINT,
+		Size:        uint32(unsafe.Sizeof(unix.PerfEventAttr{})),
+		Config:      format.ID,
+		Sample_type: unix.PERF_SAMPLE_RAW,
+		Sample:      1,
+		Wakeup:      1,
+	}
+
+	fd, err := unix.PerfEventOpen(attr, -1, 0, -1, unix.PERF_FLAG_FD_CLOEXEC)
+	if err != nil {
+		return -1, fmt.Errorf("perf_event_open: %w", err)
+	}
+
+	err = unix.IoctlSetInt(fd, unix.PERF_EVENT_IOC_SET_BPF, prog_fd)
+	if err != nil {
+		unix.Close(fd)
+		return -1, fmt.Errorf("Attaching program: %w", err)
+	}
+
+	err = unix.IoctlSetInt(fd, unix.PERF_EVENT_IOC_ENABLE, 0)
+	if err != nil {
+		unix.Close(fd)
+		return -1, fmt.Errorf("Enabling tracepoint: %w", err)
+	}
+
+	return fd, nil
+}
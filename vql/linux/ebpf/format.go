@@ -0,0 +1,221 @@
+// eBPF based process monitoring for Linux.
+
+// Small eBPF programs are attached to syscall tracepoints and send
+// a fixed size record to user space through a perf event array. The
+// tracepoint field offsets are read from tracefs rather than using
+// BTF relocations, so the programs load on kernels built without
+// BTF.
+
+package ebpf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+const (
+	EVENT_EXEC    = 1
+	EVENT_CONNECT = 2
+	EVENT_OPEN    = 3
+
+	// The record is a fixed header followed by the payload.
+	headerSize  = 40
+	payloadSize = 256
+	recordSize  = headerSize + payloadSize
+
+	// Enough for a sockaddr_in6
+	sockaddrSize = 28
+)
+
+var (
+	fieldRegex = regexp.MustCompile(
+		`field:([^;]+);\s*offset:(\d+);\s*size:(\d+);`)
+)
+
+type Field struct {
+	Offset int
+	Size   int
+}
+
+// The format of a tracepoint as described by its tracefs format
+// file.
+type Format struct {
+	ID     uint64
+	Fields map[string]Field
+}
+
+func parseFormat(data string) (*Format, error) {
+	result := &Format{Fields: make(map[string]Field)}
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "ID:") {
+			id, err := strconv.ParseUint(
+				strings.TrimSpace(line[3:]), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			result.ID = id
+			continue
+		}
+
+		match := fieldRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		// The declaration is something like "const char * filename"
+		// or "char comm[16]". The name is the last word.
+		decl := strings.Fields(match[1])
+		if len(decl) == 0 {
+			continue
+		}
+		name := decl[len(decl)-1]
+		if idx := strings.Index(name, "["); idx > 0 {
+			name = name[:idx]
+		}
+		name = strings.TrimLeft(name, "*")
+
+		offset, _ := strconv.Atoi(match[2])
+		size, _ := strconv.Atoi(match[3])
+		result.Fields[name] = Field{Offset: offset, Size: size}
+	}
+
+	if result.ID == 0 {
+		return nil, errors.New("Tracepoint format has no ID")
+	}
+
+	return result, nil
+}
+
+func (self *Format) Offset(name string) (int, error) {
+	field, pres := self.Fields[name]
+	if !pres {
+		return 0, fmt.Errorf("Tracepoint has no field %v", name)
+	}
+	if field.Size != 8 {
+		return 0, fmt.Errorf("Tracepoint field %v has size %v",
+			name, field.Size)
+	}
+	return field.Offset, nil
+}
+
+func cString(data []byte) string {
+	idx := bytes.IndexByte(data, 0)
+	if idx >= 0 {
+		data = data[:idx]
+	}
+	return string(data)
+}
+
+// Decode a record sent by one of the programs. The record is in
+// host byte order - all supported architectures are little endian.
+func decodeRecord(data []byte) (*ordereddict.Dict, error) {
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("Record too short (%v bytes)", len(data))
+	}
+
+	event_type := binary.LittleEndian.Uint32(data[0:])
+	arg := binary.LittleEndian.Uint32(data[4:])
+	pid_tgid := binary.LittleEndian.Uint64(data[8:])
+	uid_gid := binary.LittleEndian.Uint64(data[16:])
+	payload := data[headerSize:]
+
+	result := ordereddict.NewDict().
+		Set("Type", "").
+		Set("Pid", pid_tgid>>32).
+		Set("Tid", pid_tgid&0xffffffff).
+		Set("Uid", uid_gid&0xffffffff).
+		Set("Gid", uid_gid>>32).
+		Set("Comm", cString(data[24:40]))
+
+	switch event_type {
+	case EVENT_EXEC:
+		result.Update("Type", "exec").
+			Set("Filename", cString(payload))
+
+	case EVENT_OPEN:
+		result.Update("Type", "open").
+			Set("Filename", cString(payload)).
+			Set("Flags", arg)
+
+	case EVENT_CONNECT:
+		result.Update("Type", "connect").
+			Set("Fd", int32(arg))
+		decodeSockaddr(payload, result)
+
+	default:
+		return nil, fmt.Errorf("Unknown event type %v", event_type)
+	}
+
+	return result, nil
+}
+
+func decodeSockaddr(data []byte, result *ordereddict.Dict) {
+	if len(data) < 2 {
+		return
+	}
+
+	family := binary.LittleEndian.Uint16(data)
+	switch family {
+	case 1:
+		result.Set("Family", "unix").
+			Set("Address", cString(data[2:]))
+
+	case 2:
+		if len(data) < 8 {
+			return
+		}
+		result.Set("Family", "ipv4").
+			Set("Address", net.IP(data[4:8]).String()).
+			Set("Port", binary.BigEndian.Uint16(data[2:]))
+
+	case 10:
+		if len(data) < 24 {
+			return
+		}
+		result.Set("Family", "ipv6").
+			Set("Address", net.IP(data[8:24]).String()).
+			Set("Port", binary.BigEndian.Uint16(data[2:]))
+
+	default:
+		result.Set("Family", family)
+	}
+}
+
+// Parse a cpu list like "0-3,5"
+func parseCPUList(data string) ([]int, error) {
+	var result []int
+	for _, item := range strings.Split(strings.TrimSpace(data), ",") {
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, "-", 2)
+		first, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		last := first
+		if len(parts) == 2 {
+			last, err = strconv.Atoi(parts[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for i := first; i <= last; i++ {
+			result = append(result, i)
+		}
+	}
+	return result, nil
+}
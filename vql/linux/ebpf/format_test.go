@@ -0,0 +1,110 @@
+package ebpf
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var openatFormat = `name: sys_enter_openat
+ID: 633
+format:
+	field:unsigned short common_type;	offset:0;	size:2;	signed:0;
+	field:unsigned char common_flags;	offset:2;	size:1;	signed:0;
+	field:unsigned char common_preempt_count;	offset:3;	size:1;	signed:0;
+	field:int common_pid;	offset:4;	size:4;	signed:1;
+
+	field:int __syscall_nr;	offset:8;	size:4;	signed:1;
+	field:int dfd;	offset:16;	size:8;	signed:0;
+	field:const char * filename;	offset:24;	size:8;	signed:0;
+	field:int flags;	offset:32;	size:8;	signed:0;
+	field:umode_t mode;	offset:40;	size:8;	signed:0;
+
+print fmt: "dfd: 0x%08lx, filename: 0x%08lx", ((unsigned long)(REC->dfd)), ((unsigned long)(REC->filename))
+`
+
+func TestParseFormat(t *testing.T) {
+	format, err := parseFormat(openatFormat)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(633), format.ID)
+
+	offset, err := format.Offset("filename")
+	require.NoError(t, err)
+	assert.Equal(t, 24, offset)
+
+	offset, err = format.Offset("flags")
+	require.NoError(t, err)
+	assert.Equal(t, 32, offset)
+
+	// Only 8 byte fields can be read by the programs.
+	_, err = format.Offset("__syscall_nr")
+	assert.Error(t, err)
+
+	_, err = format.Offset("missing")
+	assert.Error(t, err)
+
+	_, err = parseFormat("name: foo\n")
+	assert.Error(t, err)
+}
+
+func makeRecord(event_type, arg uint32, payload []byte) []byte {
+	record := make([]byte, headerSize, headerSize+len(payload))
+	binary.LittleEndian.PutUint32(record[0:], event_type)
+	binary.LittleEndian.PutUint32(record[4:], arg)
+	binary.LittleEndian.PutUint64(record[8:], 1234<<32|1235)
+	binary.LittleEndian.PutUint64(record[16:], 100<<32|1000)
+	copy(record[24:], "bash\x00")
+	return append(record, payload...)
+}
+
+func TestDecodeRecord(t *testing.T) {
+	event, err := decodeRecord(makeRecord(EVENT_EXEC, 0,
+		[]byte("/usr/bin/id\x00garbage")))
+	require.NoError(t, err)
+
+	for k, v := range map[string]interface{}{
+		"Type":     "exec",
+		"Pid":      uint64(1234),
+		"Tid":      uint64(1235),
+		"Uid":      uint64(1000),
+		"Gid":      uint64(100),
+		"Comm":     "bash",
+		"Filename": "/usr/bin/id",
+	} {
+		value, _ := event.Get(k)
+		assert.Equal(t, v, value, k)
+	}
+
+	// sockaddr_in for 10.1.2.3:443
+	sockaddr := []byte{2, 0, 0x01, 0xbb, 10, 1, 2, 3, 0, 0, 0, 0, 0, 0, 0, 0}
+	event, err = decodeRecord(makeRecord(EVENT_CONNECT, 3, sockaddr))
+	require.NoError(t, err)
+
+	for k, v := range map[string]interface{}{
+		"Type":    "connect",
+		"Fd":      int32(3),
+		"Family":  "ipv4",
+		"Address": "10.1.2.3",
+		"Port":    uint16(443),
+	} {
+		value, _ := event.Get(k)
+		assert.Equal(t, v, value, k)
+	}
+
+	_, err = decodeRecord(makeRecord(99, 0, nil))
+	assert.Error(t, err)
+
+	_, err = decodeRecord([]byte{1, 0, 0, 0})
+	assert.Error(t, err)
+}
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := parseCPUList("0-3,6\n")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 6}, cpus)
+
+	_, err = parseCPUList("a-b")
+	assert.Error(t, err)
+}
@@ -0,0 +1,125 @@
+// +build linux
+
+package ebpf
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// The loaded programs and their ring buffers.
+type monitor struct {
+	map_fd int
+	fds    []int
+	rings  []*perfRing
+}
+
+func newMonitor(events []string, pages int) (_ *monitor, err error) {
+	self := &monitor{map_fd: -1}
+	defer func() {
+		if err != nil {
+			self.Close()
+		}
+	}()
+
+	tracefs, err := tracefsRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	cpus, err := onlineCPUs()
+	if err != nil || len(cpus) == 0 {
+		return nil, fmt.Errorf("Unable to list cpus: %v", err)
+	}
+
+	// Before 5.11 maps and programs are charged against the
+	// memlock limit which is small by default.
+	_ = unix.Setrlimit(unix.RLIMIT_MEMLOCK, &unix.Rlimit{
+		Cur: unix.RLIM_INFINITY, Max: unix.RLIM_INFINITY})
+
+	self.map_fd, err = createPerfEventArray(cpus[len(cpus)-1] + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cpu := range cpus {
+		ring, err := newPerfRing(cpu, pages)
+		if err != nil {
+			return nil, err
+		}
+		self.rings = append(self.rings, ring)
+
+		err = updateMap(self.map_fd, uint32(cpu), uint32(ring.fd))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item := range probes {
+		if !wanted(events, item.Name) {
+			continue
+		}
+
+		fds, err := item.attach(tracefs, self.map_fd)
+		if err != nil {
+			return nil, err
+		}
+		self.fds = append(self.fds, fds...)
+	}
+
+	return self, nil
+}
+
+func wanted(events []string, name string) bool {
+	for _, e := range events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Wait for samples and pass them to the callback until the context
+// is done.
+func (self *monitor) Run(ctx context.Context,
+	cb func(sample []byte), lost func(count uint64)) error {
+	poll_fds := make([]unix.PollFd, 0, len(self.rings))
+	for _, ring := range self.rings {
+		poll_fds = append(poll_fds, unix.PollFd{
+			Fd: int32(ring.fd), Events: unix.POLLIN})
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Wake up periodically to check the context.
+		_, err := unix.Poll(poll_fds, 500)
+		if err != nil && err != unix.EINTR {
+			return err
+		}
+
+		for _, ring := range self.rings {
+			count := ring.Drain(cb)
+			if count > 0 {
+				lost(count)
+			}
+		}
+	}
+}
+
+func (self *monitor) Close() {
+	// Closing the perf events detaches the programs.
+	for _, fd := range self.fds {
+		unix.Close(fd)
+	}
+	for _, ring := range self.rings {
+		ring.Close()
+	}
+	if self.map_fd >= 0 {
+		unix.Close(self.map_fd)
+	}
+}
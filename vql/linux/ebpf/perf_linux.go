@@ -0,0 +1,117 @@
+// +build linux
+
+package ebpf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// A ring buffer shared with the kernel for one CPU.
+type perfRing struct {
+	fd   int
+	mem  []byte
+	meta *unix.PerfEventMmapPage
+	data []byte
+}
+
+func newPerfRing(cpu, pages int) (*perfRing, error) {
+	attr := &unix.PerfEventAttr{
+		Type:        unix.PERF_TYPE_SOFTWARE,
+		Size:        uint32(unsafe.Sizeof(unix.PerfEventAttr{})),
+		Config:      unix.PERF_COUNT_SW_BPF_OUTPUT,
+		Sample_type: unix.PERF_SAMPLE_RAW,
+		Sample:      1,
+		Wakeup:      1,
+	}
+
+	fd, err := unix.PerfEventOpen(attr, -1, cpu, -1, unix.PERF_FLAG_FD_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("perf_event_open on cpu %v: %w", cpu, err)
+	}
+
+	// One metadata page followed by a power of 2 data pages.
+	page_size := os.Getpagesize()
+	mem, err := unix.Mmap(fd, 0, (pages+1)*page_size,
+		unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	if err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("mmap on cpu %v: %w", cpu, err)
+	}
+
+	err = unix.IoctlSetInt(fd, unix.PERF_EVENT_IOC_ENABLE, 0)
+	if err != nil {
+		unix.Munmap(mem)
+		unix.Close(fd)
+		return nil, err
+	}
+
+	return &perfRing{
+		fd:   fd,
+		mem:  mem,
+		meta: (*unix.PerfEventMmapPage)(unsafe.Pointer(&mem[0])),
+		data: mem[page_size:],
+	}, nil
+}
+
+func (self *perfRing) Close() {
+	unix.Munmap(self.mem)
+	unix.Close(self.fd)
+}
+
+// Copy out of the ring handling wrap around.
+func (self *perfRing) read(offset uint64, length int) []byte {
+	result := make([]byte, length)
+	size := uint64(len(self.data))
+	for i := range result {
+		result[i] = self.data[(offset+uint64(i))%size]
+	}
+	return result
+}
+
+// Read all the available samples. Returns the number of samples the
+// kernel dropped because the ring was full.
+func (self *perfRing) Drain(cb func(sample []byte)) (lost uint64) {
+	head := atomic.LoadUint64(&self.meta.Data_head)
+	tail := atomic.LoadUint64(&self.meta.Data_tail)
+
+	for tail < head {
+		// struct perf_event_header
+		header := self.read(tail, 8)
+		record_type := binary.LittleEndian.Uint32(header)
+		size := binary.LittleEndian.Uint16(header[6:])
+		if size < 8 {
+			break
+		}
+
+		switch record_type {
+		case unix.PERF_RECORD_SAMPLE:
+			// A u32 size followed by the raw data.
+			raw_size := binary.LittleEndian.Uint32(self.read(tail+8, 4))
+			if raw_size <= uint32(size)-12 {
+				cb(self.read(tail+12, int(raw_size)))
+			}
+
+		case unix.PERF_RECORD_LOST:
+			lost += binary.LittleEndian.Uint64(self.read(tail+16, 8))
+		}
+
+		tail += uint64(size)
+	}
+
+	atomic.StoreUint64(&self.meta.Data_tail, tail)
+	return lost
+}
+
+func onlineCPUs() ([]int, error) {
+	data, err := os.ReadFile("/sys/devices/system/cpu/online")
+	if err != nil {
+		return nil, err
+	}
+	return parseCPUList(string(data))
+}
@@ -0,0 +1,218 @@
+// +build linux
+
+package ebpf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type EBPFEventsArgs struct {
+	Events      []string `vfilter:"optional,field=events,doc=The events to watch: exec, connect, open (default exec and connect)."`
+	BufferPages int64    `vfilter:"optional,field=buffer_pages,doc=Pages of ring buffer per cpu, a power of 2 (default 64)."`
+	NoFallback  bool     `vfilter:"optional,field=no_fallback,doc=Do not poll /proc for new processes when eBPF is not available."`
+	Period      int64    `vfilter:"optional,field=period,doc=How often to poll /proc in the fallback mode in seconds (default 1)."`
+}
+
+type EBPFEventsPlugin struct{}
+
+func (self EBPFEventsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "ebpf_events",
+		Doc:      "Watch process exec, connect and open events using eBPF.",
+		ArgType:  type_map.AddType(scope, &EBPFEventsArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func (self EBPFEventsPlugin) Call(
+	ctx context.Context, scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("ebpf_events: %s", err)
+			return
+		}
+
+		arg := &EBPFEventsArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("ebpf_events: %v", err)
+			return
+		}
+
+		if len(arg.Events) == 0 {
+			arg.Events = []string{"exec", "connect"}
+		}
+
+		for _, e := range arg.Events {
+			if !isKnownEvent(e) {
+				scope.Log("ebpf_events: Unknown event %v", e)
+				return
+			}
+		}
+
+		if arg.BufferPages <= 0 || arg.BufferPages&(arg.BufferPages-1) != 0 {
+			arg.BufferPages = 64
+		}
+
+		monitor, err := newMonitor(arg.Events, int(arg.BufferPages))
+		if err != nil {
+			scope.Log("ebpf_events: Unable to load eBPF programs: %v", err)
+			if !arg.NoFallback {
+				pollProcesses(ctx, scope, arg, output_chan)
+			}
+			return
+		}
+		defer monitor.Close()
+
+		scope.Log("ebpf_events: Watching %v", strings.Join(arg.Events, ", "))
+
+		err = monitor.Run(ctx, func(sample []byte) {
+			event, err := decodeRecord(sample)
+			if err != nil {
+				return
+			}
+
+			event.Set("Time", time.Now().UTC()).
+				Set("Source", "ebpf")
+
+			select {
+			case <-ctx.Done():
+			case output_chan <- event:
+			}
+		}, func(count uint64) {
+			scope.Log("ebpf_events: Lost %v events", count)
+		})
+		if err != nil {
+			scope.Log("ebpf_events: %v", err)
+		}
+	}()
+
+	return output_chan
+}
+
+func isKnownEvent(name string) bool {
+	for _, item := range probes {
+		if item.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func listPids() map[uint64]bool {
+	result := make(map[uint64]bool)
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return result
+	}
+
+	for _, entry := range entries {
+		pid, err := strconv.ParseUint(entry.Name(), 10, 64)
+		if err == nil {
+			result[pid] = true
+		}
+	}
+	return result
+}
+
+// Without eBPF we can only see processes which are still running
+// when /proc is polled, and only exec events.
+func pollProcesses(ctx context.Context, scope vfilter.Scope,
+	arg *EBPFEventsArgs, output_chan chan vfilter.Row) {
+	if !wanted(arg.Events, "exec") {
+		scope.Log("ebpf_events: Only exec events are available by polling")
+		return
+	}
+
+	if arg.Period <= 0 {
+		arg.Period = 1
+	}
+
+	scope.Log("ebpf_events: Polling /proc for new processes every %v seconds",
+		arg.Period)
+
+	known := listPids()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(arg.Period) * time.Second):
+		}
+
+		current := listPids()
+		for pid := range current {
+			if known[pid] {
+				continue
+			}
+
+			event := procEvent(pid)
+			if event == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- event:
+			}
+		}
+		known = current
+	}
+}
+
+func procEvent(pid uint64) *ordereddict.Dict {
+	proc := filepath.Join("/proc", strconv.FormatUint(pid, 10))
+	stat, err := os.Stat(proc)
+	if err != nil {
+		return nil
+	}
+
+	result := ordereddict.NewDict().
+		Set("Type", "exec").
+		Set("Pid", pid).
+		Set("Tid", pid)
+
+	sys, ok := stat.Sys().(*syscall.Stat_t)
+	if ok {
+		result.Set("Uid", uint64(sys.Uid)).
+			Set("Gid", uint64(sys.Gid))
+	}
+
+	comm, _ := os.ReadFile(filepath.Join(proc, "comm"))
+	filename, _ := os.Readlink(filepath.Join(proc, "exe"))
+
+	return result.Set("Comm", strings.TrimSpace(string(comm))).
+		Set("Filename", filename).
+		Set("Time", time.Now().UTC()).
+		Set("Source", "proc")
+}
+
+// Loading programs needs root and a mounted tracefs.
+func ebpfAvailable() bool {
+	_, err := tracefsRoot()
+	return err == nil && vql_subsystem.IsAdmin()
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&EBPFEventsPlugin{})
+	vql_subsystem.RegisterFeature("ebpf", ebpfAvailable)
+}
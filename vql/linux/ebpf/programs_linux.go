@@ -0,0 +1,167 @@
+// +build linux
+
+package ebpf
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+type probe struct {
+	Name       string
+	Tracepoint string
+	EventType  int32
+
+	// The tracepoint field holding the user space pointer to read
+	// into the payload, and an optional integer field which is
+	// stored in the record header.
+	PointerField string
+	ArgField     string
+
+	// Strings are read up to the payload size, otherwise exactly
+	// ReadSize bytes are read.
+	ReadString bool
+	ReadSize   int32
+}
+
+var probes = []probe{{
+	Name:         "exec",
+	Tracepoint:   "sys_enter_execve",
+	EventType:    EVENT_EXEC,
+	PointerField: "filename",
+	ReadString:   true,
+	ReadSize:     payloadSize,
+}, {
+	Name:         "connect",
+	Tracepoint:   "sys_enter_connect",
+	EventType:    EVENT_CONNECT,
+	PointerField: "uservaddr",
+	ArgField:     "fd",
+	ReadSize:     sockaddrSize,
+}, {
+	Name:         "open",
+	Tracepoint:   "sys_enter_openat",
+	EventType:    EVENT_OPEN,
+	PointerField: "filename",
+	ArgField:     "flags",
+	ReadString:   true,
+	ReadSize:     payloadSize,
+}}
+
+// Build the program for a probe. The record is assembled on the
+// stack:
+//
+//	 0  u32 event type
+//	 4  u32 ArgField
+//	 8  u64 pid_tgid
+//	16  u64 uid_gid
+//	24  char comm[16]
+//	40  payload
+//
+// Older kernels only have the bpf_probe_read() helpers which read
+// both kernel and user memory, newer ones have dedicated user space
+// helpers.
+func (self probe) build(format *Format, map_fd int, legacy bool) (
+	[]instruction, error) {
+	ptr_offset, err := format.Offset(self.PointerField)
+	if err != nil {
+		return nil, err
+	}
+
+	read_helper := int32(FN_probe_read_user)
+	if self.ReadString {
+		read_helper = FN_probe_read_user_str
+	}
+	if legacy {
+		read_helper = FN_probe_read
+		if self.ReadString {
+			read_helper = FN_probe_read_str
+		}
+	}
+
+	base := int16(-recordSize)
+	result := []instruction{
+		// r6 = ctx
+		mov64Reg(R6, R1),
+		stDW(R10, base, self.EventType),
+	}
+
+	if self.ArgField != "" {
+		arg_offset, err := format.Offset(self.ArgField)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result,
+			ldxDW(R1, R6, int16(arg_offset)),
+			stxW(R10, R1, base+4))
+	}
+
+	result = append(result,
+		call(FN_get_current_pid_tgid),
+		stxDW(R10, R0, base+8),
+		call(FN_get_current_uid_gid),
+		stxDW(R10, R0, base+16),
+
+		mov64Reg(R1, R10),
+		add64Imm(R1, int32(base+24)),
+		mov64Imm(R2, 16),
+		call(FN_get_current_comm),
+
+		mov64Reg(R1, R10),
+		add64Imm(R1, int32(base+headerSize)),
+		mov64Imm(R2, self.ReadSize),
+		ldxDW(R3, R6, int16(ptr_offset)),
+		call(read_helper),
+
+		mov64Reg(R1, R6))
+	result = append(result, ldMapFd(R2, map_fd)...)
+	result = append(result,
+		mov32Imm(R3, -1), // BPF_F_CURRENT_CPU
+		mov64Reg(R4, R10),
+		add64Imm(R4, int32(base)),
+		mov64Imm(R5, headerSize+self.ReadSize),
+		call(FN_perf_event_output),
+
+		mov64Imm(R0, 0),
+		exit())
+
+	return result, nil
+}
+
+// Load the probe's program and attach it to its tracepoint. Returns
+// the program and perf event fds.
+func (self probe) attach(tracefs string, map_fd int) ([]int, error) {
+	format, err := getFormat(tracefs, "syscalls", self.Tracepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var prog_fd int
+	for _, legacy := range []bool{false, true} {
+		insns, err := self.build(format, map_fd, legacy)
+		if err != nil {
+			return nil, err
+		}
+
+		prog_fd, err = loadProgram(insns)
+		if err == nil {
+			break
+		}
+
+		// Only retry if the kernel does not know the newer
+		// helpers.
+		if legacy || !errors.Is(err, unix.EINVAL) {
+			return nil, fmt.Errorf("%v: %w", self.Name, err)
+		}
+	}
+
+	event_fd, err := attachTracepoint(format, prog_fd)
+	if err != nil {
+		unix.Close(prog_fd)
+		return nil, fmt.Errorf("%v: %w", self.Name, err)
+	}
+
+	return []int{prog_fd, event_fd}, nil
+}
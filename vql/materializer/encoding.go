@@ -0,0 +1,241 @@
+package materializer
+
+// Spilled rows are read back by other queries which may compare or
+// format their values, so they must come back with the same types
+// they had in memory. Plain JSON loses this (e.g. integers come back
+// as uint64, timestamps as strings and strings which look like
+// timestamps as time.Time) so each value is wrapped in a small
+// envelope recording its type.
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	vjson "www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	TYPE_NULL   = "n"
+	TYPE_BOOL   = "b"
+	TYPE_STRING = "s"
+	TYPE_INT    = "i"
+	TYPE_UINT   = "u"
+	TYPE_FLOAT  = "f"
+	TYPE_TIME   = "t"
+	TYPE_BYTES  = "x"
+	TYPE_DICT   = "d"
+	TYPE_ARRAY  = "a"
+
+	// Anything else is stored as plain JSON.
+	TYPE_JSON = "j"
+)
+
+type typedValue struct {
+	Type  string      `json:"T"`
+	Value interface{} `json:"V,omitempty"`
+}
+
+func encodeValue(value interface{}) (*typedValue, error) {
+	switch t := value.(type) {
+	case nil, vfilter.Null, *vfilter.Null:
+		return &typedValue{Type: TYPE_NULL}, nil
+
+	case bool:
+		return &typedValue{Type: TYPE_BOOL, Value: t}, nil
+
+	case string:
+		return &typedValue{Type: TYPE_STRING, Value: t}, nil
+
+	case int, int8, int16, int32, int64:
+		return &typedValue{Type: TYPE_INT,
+			Value: strconv.FormatInt(reflect.ValueOf(t).Int(), 10)}, nil
+
+	case uint, uint8, uint16, uint32, uint64:
+		return &typedValue{Type: TYPE_UINT,
+			Value: strconv.FormatUint(reflect.ValueOf(t).Uint(), 10)}, nil
+
+	// Floats are stored as strings so NaN and Inf survive.
+	case float32:
+		return &typedValue{Type: TYPE_FLOAT,
+			Value: strconv.FormatFloat(float64(t), 'g', -1, 32)}, nil
+
+	case float64:
+		return &typedValue{Type: TYPE_FLOAT,
+			Value: strconv.FormatFloat(t, 'g', -1, 64)}, nil
+
+	case time.Time:
+		return &typedValue{Type: TYPE_TIME,
+			Value: t.Format(time.RFC3339Nano)}, nil
+
+	case *time.Time:
+		return encodeValue(*t)
+
+	case []byte:
+		return &typedValue{Type: TYPE_BYTES,
+			Value: base64.StdEncoding.EncodeToString(t)}, nil
+
+	case *ordereddict.Dict:
+		// Stored as a list of key/value pairs to keep the order.
+		items := make([][]interface{}, 0, t.Len())
+		for _, k := range t.Keys() {
+			v, _ := t.Get(k)
+			encoded, err := encodeValue(v)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, []interface{}{k, encoded})
+		}
+		return &typedValue{Type: TYPE_DICT, Value: items}, nil
+
+	case map[string]interface{}:
+		dict := ordereddict.NewDict()
+		for k, v := range t {
+			dict.Set(k, v)
+		}
+		return encodeValue(dict)
+	}
+
+	// Any other slice is stored as an array of typed values.
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		items := make([]*typedValue, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			encoded, err := encodeValue(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, encoded)
+		}
+		return &typedValue{Type: TYPE_ARRAY, Value: items}, nil
+	}
+
+	serialized, err := vjson.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return &typedValue{Type: TYPE_JSON, Value: string(serialized)}, nil
+}
+
+// The envelope as decoded by encoding/json with UseNumber().
+func decodeValue(value interface{}) (interface{}, error) {
+	envelope, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("materialize: invalid spilled value %T", value)
+	}
+
+	type_name, _ := envelope["T"].(string)
+	raw := envelope["V"]
+	str, _ := raw.(string)
+
+	switch type_name {
+	case TYPE_NULL:
+		return nil, nil
+
+	case TYPE_BOOL:
+		b, _ := raw.(bool)
+		return b, nil
+
+	case TYPE_STRING:
+		return str, nil
+
+	case TYPE_INT:
+		return strconv.ParseInt(str, 10, 64)
+
+	case TYPE_UINT:
+		return strconv.ParseUint(str, 10, 64)
+
+	case TYPE_FLOAT:
+		return strconv.ParseFloat(str, 64)
+
+	case TYPE_TIME:
+		return time.Parse(time.RFC3339Nano, str)
+
+	case TYPE_BYTES:
+		return base64.StdEncoding.DecodeString(str)
+
+	case TYPE_DICT:
+		items, _ := raw.([]interface{})
+		result := ordereddict.NewDict()
+		for _, item := range items {
+			pair, ok := item.([]interface{})
+			if !ok || len(pair) != 2 {
+				return nil, fmt.Errorf("materialize: invalid spilled dict")
+			}
+			key, _ := pair[0].(string)
+			v, err := decodeValue(pair[1])
+			if err != nil {
+				return nil, err
+			}
+			result.Set(key, v)
+		}
+		return result, nil
+
+	case TYPE_ARRAY:
+		items, _ := raw.([]interface{})
+		result := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			v, err := decodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, v)
+		}
+		return result, nil
+
+	case TYPE_JSON:
+		result := ordereddict.NewDict()
+		err := result.UnmarshalJSON([]byte(str))
+		if err == nil {
+			return result, nil
+		}
+
+		var generic interface{}
+		err = json.Unmarshal([]byte(str), &generic)
+		return generic, err
+	}
+
+	return nil, fmt.Errorf("materialize: unknown spilled type %v", type_name)
+}
+
+// Rows are stored one per line.
+func encodeRow(row *ordereddict.Dict) ([]byte, error) {
+	encoded, err := encodeValue(row)
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, err := json.Marshal(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return append(serialized, '\n'), nil
+}
+
+func decodeRow(line []byte) (*ordereddict.Dict, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	var envelope interface{}
+	err := decoder.Decode(&envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decodeValue(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	row, ok := value.(*ordereddict.Dict)
+	if !ok {
+		return nil, fmt.Errorf("materialize: spilled row is not a dict")
+	}
+	return row, nil
+}
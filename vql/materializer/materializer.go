@@ -0,0 +1,291 @@
+// Materialize large queries to disk.
+
+// A LET X <= SELECT ... statement keeps all the rows in memory which
+// may exhaust the server's memory with large result sets
+// (e.g. joining the results of a large hunt). The materialize()
+// function stores the first rows in memory and automatically spills
+// the rest into a temporary file in the scratch directory once
+// either the number of rows or their estimated size exceeds a
+// threshold. The result may be selected from multiple times:
+
+// LET X <= materialize(query={ SELECT ... })
+// SELECT * FROM X()
+
+package materializer
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/sorter"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+	"www.velocidex.com/golang/vfilter/types"
+)
+
+const (
+	DEFAULT_MAX_MEMORY = 100 * 1024 * 1024
+)
+
+// Holds the materialized rows. Rows which do not fit in memory are
+// written as JSONL to the spill file.
+type SpilledQuery struct {
+	mu sync.Mutex
+
+	name       string
+	max_rows   int
+	max_memory uint64
+	tmpdir     string
+
+	rows []vfilter.Row
+
+	// Estimated size of the rows held in memory.
+	memory uint64
+
+	fd          *os.File
+	writer      *bufio.Writer
+	spill_count int
+
+	// Rows held in memory are charged to the query's memory
+	// budget (if any).
+	budget  *vql_subsystem.MemoryBudget
+	charged uint64
+}
+
+func (self *SpilledQuery) add(row *ordereddict.Dict) error {
+	// Once we start spilling all further rows go to the spill file
+	// to preserve their order.
+	if self.fd == nil {
+		size := vql_subsystem.EstimateSize(row)
+		if len(self.rows) < self.max_rows &&
+			self.memory+size <= self.max_memory {
+			if self.budget != nil {
+				err := self.budget.Charge("materialize", size)
+				if err != nil {
+					return err
+				}
+				self.charged += size
+			}
+			self.memory += size
+			self.rows = append(self.rows, row)
+			return nil
+		}
+	}
+
+	if self.fd == nil {
+		fd, err := ioutil.TempFile(self.tmpdir, "vql*.jsonl")
+		if err != nil {
+			return err
+		}
+		self.fd = fd
+		self.writer = bufio.NewWriter(fd)
+	}
+
+	serialized, err := encodeRow(row)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.writer.Write(serialized)
+	if err != nil {
+		return err
+	}
+	self.spill_count++
+
+	return nil
+}
+
+// Flush the spill file so it can be read.
+func (self *SpilledQuery) flush() error {
+	if self.writer == nil {
+		return nil
+	}
+	return self.writer.Flush()
+}
+
+func (self *SpilledQuery) Close() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.fd != nil {
+		self.fd.Close()
+		os.Remove(self.fd.Name())
+		self.fd = nil
+		self.writer = nil
+	}
+
+	if self.budget != nil {
+		self.budget.Release(self.charged)
+		self.charged = 0
+	}
+}
+
+// Total number of rows.
+func (self *SpilledQuery) Len() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return len(self.rows) + self.spill_count
+}
+
+// Read the rows back - first from memory then from the spill file.
+func (self *SpilledQuery) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		self.mu.Lock()
+		rows := self.rows
+		var filename string
+		if self.fd != nil {
+			filename = self.fd.Name()
+		}
+		self.mu.Unlock()
+
+		for _, row := range rows {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+
+		if filename == "" {
+			return
+		}
+
+		fd, err := os.Open(filename)
+		if err != nil {
+			scope.Log("materialize: %v", err)
+			return
+		}
+		defer fd.Close()
+
+		reader := bufio.NewReader(fd)
+		for {
+			row_data, err := reader.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+
+			item, err := decodeRow(row_data)
+			if err != nil {
+				scope.Log("materialize: %v", err)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- item:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self *SpilledQuery) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name: self.name,
+		Doc:  "A materialized query",
+	}
+}
+
+type MaterializeFunctionArgs struct {
+	Query     types.StoredQuery `vfilter:"required,field=query,doc=The query to materialize."`
+	MaxRows   int64             `vfilter:"optional,field=max_rows,doc=Number of rows to keep in memory before spilling to disk (default Defaults.max_in_memory_rows)."`
+	MaxMemory uint64            `vfilter:"optional,field=max_memory,doc=Estimated size in bytes of the rows kept in memory before spilling to disk (default 100mb)."`
+	Name      string            `vfilter:"optional,field=name,doc=A name for the result (used in logs)."`
+}
+
+type MaterializeFunction struct{}
+
+func (self *MaterializeFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+	arg := &MaterializeFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("materialize: %v", err)
+		return vfilter.Null{}
+	}
+
+	// Only the server config specifies a scratch directory.
+	config_obj, _ := vql_subsystem.GetServerConfig(scope)
+	tmpdir, max_rows := sorter.GetSpillOptions(config_obj)
+	if arg.MaxRows > 0 {
+		max_rows = int(arg.MaxRows)
+	}
+
+	max_memory := uint64(DEFAULT_MAX_MEMORY)
+	if arg.MaxMemory > 0 {
+		max_memory = arg.MaxMemory
+	}
+
+	result := &SpilledQuery{
+		name:       arg.Name,
+		max_rows:   max_rows,
+		max_memory: max_memory,
+		tmpdir:     tmpdir,
+		budget:     vql_subsystem.GetMemoryBudget(scope),
+	}
+
+	// Remove the spill file when the query is done.
+	err = scope.AddDestructor(result.Close)
+	if err != nil {
+		scope.Log("materialize: %v", err)
+		return vfilter.Null{}
+	}
+
+	subscope := scope.Copy()
+	defer subscope.Close()
+
+	result.mu.Lock()
+	defer result.mu.Unlock()
+
+	for row := range arg.Query.Eval(ctx, subscope) {
+		err := result.add(vfilter.RowToDict(ctx, subscope, row))
+		if err != nil {
+			scope.Log("materialize: %v", err)
+			return vfilter.Null{}
+		}
+	}
+
+	err = result.flush()
+	if err != nil {
+		scope.Log("materialize: %v", err)
+		return vfilter.Null{}
+	}
+
+	if result.spill_count > 0 {
+		scope.Log("materialize: Spilled %v rows to %v",
+			result.spill_count, result.fd.Name())
+	}
+
+	return result
+}
+
+func (self *MaterializeFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name: "materialize",
+		Doc: "Materialize a query into memory and a temporary file. " +
+			"Select from the result by calling it (e.g. SELECT * FROM X()).",
+		ArgType: type_map.AddType(scope, &MaterializeFunctionArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&MaterializeFunction{})
+}
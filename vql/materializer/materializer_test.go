@@ -0,0 +1,214 @@
+package materializer
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+func TestMaterialize(t *testing.T) {
+	rows := []*ordereddict.Dict{}
+	for i := 0; i < 10; i++ {
+		rows = append(rows, ordereddict.NewDict().Set("X", i))
+	}
+
+	scope := vql_subsystem.MakeScope().AppendVars(
+		ordereddict.NewDict().Set("rows", rows))
+	ctx := context.Background()
+
+	queries, err := vfilter.MultiParse(`
+LET X <= materialize(query={ SELECT X FROM rows }, max_rows=3)
+SELECT * FROM X()
+`)
+	require.NoError(t, err)
+
+	var result []vfilter.Row
+	for _, vql := range queries {
+		for row := range vql.Eval(ctx, scope) {
+			result = append(result, row)
+		}
+	}
+
+	// The rows are returned in order from memory and the spill
+	// file.
+	require.Equal(t, 10, len(result))
+	for i, row := range result {
+		value, _ := scope.Associative(row, "X")
+		x, _ := utils.ToInt64(value)
+		assert.Equal(t, int64(i), x)
+	}
+
+	x_any, pres := scope.Resolve("X")
+	require.True(t, pres)
+
+	spilled, ok := x_any.(*SpilledQuery)
+	require.True(t, ok)
+	assert.Equal(t, 10, spilled.Len())
+	assert.Equal(t, 7, spilled.spill_count)
+
+	// The result can be read more than once.
+	count := 0
+	for range spilled.Call(ctx, scope, ordereddict.NewDict()) {
+		count++
+	}
+	assert.Equal(t, 10, count)
+
+	// The spill file is removed when the scope is closed.
+	filename := spilled.fd.Name()
+	scope.Close()
+
+	_, err = os.Stat(filename)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMaterializeMemoryBudget(t *testing.T) {
+	rows := []*ordereddict.Dict{}
+	for i := 0; i < 100; i++ {
+		rows = append(rows, ordereddict.NewDict().
+			Set("Data", strings.Repeat("X", 1000)))
+	}
+
+	scope := vql_subsystem.MakeScope().AppendVars(
+		ordereddict.NewDict().
+			Set("rows", rows).
+			Set(vql_subsystem.CACHE_VAR, vql_subsystem.NewScopeCache()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var budget_err error
+	budget := vql_subsystem.NewMemoryBudget(10000, func(err error) {
+		budget_err = err
+		cancel()
+	})
+	vql_subsystem.InstallMemoryBudget(scope, budget)
+
+	queries, err := vfilter.MultiParse(`
+LET X <= materialize(query={ SELECT Data FROM rows }, max_rows=1000)
+`)
+	require.NoError(t, err)
+
+	for _, vql := range queries {
+		for range vql.Eval(ctx, scope) {
+		}
+	}
+
+	// The query is aborted with a structured error.
+	require.Error(t, budget_err)
+	exceeded, ok := budget_err.(*vql_subsystem.MemoryBudgetExceededError)
+	require.True(t, ok)
+	assert.Equal(t, "materialize", exceeded.Source)
+	assert.Equal(t, uint64(10000), exceeded.Limit)
+	assert.Equal(t, budget_err, budget.Err())
+
+	// Memory is returned when the scope is closed.
+	scope.Close()
+	used, peak, _ := budget.Stats()
+	assert.Equal(t, uint64(0), used)
+	assert.True(t, peak > 10000)
+}
+
+func TestMaterializeKeepsTypes(t *testing.T) {
+	timestamp := time.Date(2022, 10, 1, 12, 0, 0, 123, time.UTC)
+	row := ordereddict.NewDict().
+		Set("Int", int64(-5)).
+		Set("Uint", uint64(5)).
+		Set("Float", 2.0).
+		Set("Bool", true).
+		Set("Time", timestamp).
+		Set("TimeLike", "2022-10-01T12:00:00Z").
+		Set("Bytes", []byte("hello")).
+		Set("Null", nil).
+		Set("List", []interface{}{int64(1), "a"}).
+		Set("Dict", ordereddict.NewDict().Set("B", 1).Set("A", 2))
+
+	serialized, err := encodeRow(row)
+	require.NoError(t, err)
+
+	decoded, err := decodeRow(serialized)
+	require.NoError(t, err)
+
+	assert.Equal(t, row.Keys(), decoded.Keys())
+
+	value, _ := decoded.Get("Int")
+	assert.Equal(t, int64(-5), value)
+
+	value, _ = decoded.Get("Uint")
+	assert.Equal(t, uint64(5), value)
+
+	value, _ = decoded.Get("Float")
+	assert.Equal(t, 2.0, value)
+
+	value, _ = decoded.Get("Bool")
+	assert.Equal(t, true, value)
+
+	value, _ = decoded.Get("Time")
+	assert.True(t, timestamp.Equal(value.(time.Time)))
+
+	// Strings stay strings even if they look like times.
+	value, _ = decoded.Get("TimeLike")
+	assert.Equal(t, "2022-10-01T12:00:00Z", value)
+
+	value, _ = decoded.Get("Bytes")
+	assert.Equal(t, []byte("hello"), value)
+
+	value, _ = decoded.Get("List")
+	assert.Equal(t, []interface{}{int64(1), "a"}, value)
+
+	value, _ = decoded.Get("Dict")
+	assert.Equal(t, []string{"B", "A"}, value.(*ordereddict.Dict).Keys())
+}
+
+func TestMaterializeSpillsBySize(t *testing.T) {
+	rows := []*ordereddict.Dict{}
+	for i := 0; i < 10; i++ {
+		rows = append(rows, ordereddict.NewDict().
+			Set("X", i).
+			Set("Data", strings.Repeat("X", 1000)))
+	}
+
+	scope := vql_subsystem.MakeScope().AppendVars(
+		ordereddict.NewDict().Set("rows", rows))
+	defer scope.Close()
+
+	ctx := context.Background()
+
+	// Only a few rows fit in max_memory so the rest are spilled
+	// even though max_rows is not reached.
+	queries, err := vfilter.MultiParse(`
+LET X <= materialize(query={ SELECT X, Data FROM rows }, max_memory=3500)
+`)
+	require.NoError(t, err)
+
+	for _, vql := range queries {
+		for range vql.Eval(ctx, scope) {
+		}
+	}
+
+	x_any, pres := scope.Resolve("X")
+	require.True(t, pres)
+
+	spilled, ok := x_any.(*SpilledQuery)
+	require.True(t, ok)
+	assert.Equal(t, 10, spilled.Len())
+	assert.True(t, spilled.spill_count > 0)
+	assert.True(t, spilled.memory <= 3500)
+
+	// Rows come back in order with their types intact.
+	idx := int64(0)
+	for row := range spilled.Call(ctx, scope, ordereddict.NewDict()) {
+		value, _ := scope.Associative(row, "X")
+		assert.Equal(t, idx, value)
+		idx++
+	}
+	assert.Equal(t, int64(10), idx)
+}
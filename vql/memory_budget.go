@@ -0,0 +1,214 @@
+// Enforce a memory budget on a query.
+
+// Some VQL constructs hold data in memory for the life of the query
+// (materialized queries, caches, sort buffers). A runaway query can
+// exhaust the memory of the server or the endpoint. A MemoryBudget
+// is installed in the root scope and these constructs charge an
+// estimate of the memory they hold against it. When the budget is
+// exceeded the query is cancelled and the error is reported.
+
+package vql
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Velocidex/ordereddict"
+	humanize "github.com/dustin/go-humanize"
+	"www.velocidex.com/golang/velociraptor/constants"
+	vjson "www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Returned when a query exceeds its memory budget.
+type MemoryBudgetExceededError struct {
+	// The construct which requested the memory.
+	Source string
+	Limit  uint64
+	Used   uint64
+}
+
+func (self *MemoryBudgetExceededError) Error() string {
+	return fmt.Sprintf("Memory budget exceeded by %v: %v used, limit is %v",
+		self.Source, humanize.IBytes(self.Used), humanize.IBytes(self.Limit))
+}
+
+type MemoryBudget struct {
+	mu sync.Mutex
+
+	limit uint64
+	used  uint64
+	peak  uint64
+
+	// Set once the budget is exceeded.
+	err *MemoryBudgetExceededError
+
+	// Called once to abort the query.
+	cancel func(err error)
+}
+
+// Charge size bytes against the budget. If the budget is exceeded
+// the charge is rejected, the query is cancelled and an error is
+// returned.
+func (self *MemoryBudget) Charge(source string, size uint64) error {
+	self.mu.Lock()
+
+	if self.err != nil {
+		self.mu.Unlock()
+		return self.err
+	}
+
+	used := self.used + size
+	if used > self.peak {
+		self.peak = used
+	}
+
+	if self.limit == 0 || used <= self.limit {
+		self.used = used
+		self.mu.Unlock()
+		return nil
+	}
+
+	err := &MemoryBudgetExceededError{
+		Source: source,
+		Limit:  self.limit,
+		Used:   used,
+	}
+	self.err = err
+	self.mu.Unlock()
+
+	// Call outside the lock so the callback may inspect the
+	// budget.
+	if self.cancel != nil {
+		self.cancel(err)
+	}
+	return err
+}
+
+// Return memory to the budget when it is no longer held.
+func (self *MemoryBudget) Release(size uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if size > self.used {
+		size = self.used
+	}
+	self.used -= size
+}
+
+// The error which aborted the query or nil.
+func (self *MemoryBudget) Err() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.err == nil {
+		return nil
+	}
+	return self.err
+}
+
+func (self *MemoryBudget) Stats() (used, peak, limit uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.used, self.peak, self.limit
+}
+
+func NewMemoryBudget(limit uint64, cancel func(err error)) *MemoryBudget {
+	return &MemoryBudget{
+		limit:  limit,
+		cancel: cancel,
+	}
+}
+
+// Install the budget in the scope. All subscopes and artifacts
+// called from this scope share the budget.
+func InstallMemoryBudget(scope vfilter.Scope, budget *MemoryBudget) {
+	CacheSet(scope, constants.SCOPE_MEMORY_BUDGET, budget)
+}
+
+func GetMemoryBudget(scope vfilter.Scope) *MemoryBudget {
+	budget, _ := CacheGet(scope, constants.SCOPE_MEMORY_BUDGET).(*MemoryBudget)
+	return budget
+}
+
+// Charge memory against the scope's budget (if any).
+func ChargeMemory(scope vfilter.Scope, source string, size uint64) error {
+	budget := GetMemoryBudget(scope)
+	if budget == nil {
+		return nil
+	}
+	return budget.Charge(source, size)
+}
+
+func ReleaseMemory(scope vfilter.Scope, size uint64) {
+	budget := GetMemoryBudget(scope)
+	if budget != nil {
+		budget.Release(size)
+	}
+}
+
+// A rough estimate of the memory held by a value. This does not
+// need to be exact - it only needs to grow with the data.
+func EstimateSize(value interface{}) uint64 {
+	switch t := value.(type) {
+	case nil, vfilter.Null, *vfilter.Null:
+		return 8
+
+	case string:
+		return uint64(len(t)) + 16
+
+	case []byte:
+		return uint64(len(t)) + 24
+
+	case bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return 8
+
+	case *ordereddict.Dict:
+		if t == nil {
+			return 8
+		}
+		result := uint64(64)
+		for _, k := range t.Keys() {
+			v, _ := t.Get(k)
+			result += EstimateSize(k) + EstimateSize(v)
+		}
+		return result
+
+	case map[string]interface{}:
+		result := uint64(64)
+		for k, v := range t {
+			result += EstimateSize(k) + EstimateSize(v)
+		}
+		return result
+
+	case []interface{}:
+		result := uint64(24)
+		for _, v := range t {
+			result += EstimateSize(v)
+		}
+		return result
+
+	case []vfilter.Row:
+		result := uint64(24)
+		for _, v := range t {
+			result += EstimateSize(v)
+		}
+		return result
+
+	case []string:
+		result := uint64(24)
+		for _, v := range t {
+			result += EstimateSize(v)
+		}
+		return result
+	}
+
+	// Fall back to the size of the serialized value.
+	serialized, err := vjson.Marshal(value)
+	if err != nil {
+		return 64
+	}
+	return uint64(len(serialized))
+}
@@ -19,8 +19,10 @@ package networking
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -40,6 +42,7 @@ import (
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	constants "www.velocidex.com/golang/velociraptor/constants"
 	"www.velocidex.com/golang/velociraptor/crypto"
+	"www.velocidex.com/golang/velociraptor/services/rate_limiter"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	vfilter "www.velocidex.com/golang/vfilter"
@@ -63,8 +66,15 @@ type HTTPClientCache struct {
 	cache map[string]*http.Client
 }
 
-func (self *HTTPClientCache) getCacheKey(url *url.URL) string {
-	return url.Scheme + ":" + url.Hostname()
+// Calls with different TLS or proxy settings need their own client.
+func (self *HTTPClientCache) getCacheKey(
+	url *url.URL, arg *HttpPluginRequest) string {
+	options := sha256.Sum256([]byte(strings.Join([]string{
+		arg.RootCerts, arg.ClientCert, arg.ClientKey, arg.Proxy,
+		fmt.Sprintf("%v", arg.DisableSSLSecurity)}, "\x00")))
+
+	return url.Scheme + ":" + url.Hostname() + ":" +
+		hex.EncodeToString(options[:8])
 }
 
 type HttpPluginRequest struct {
@@ -80,6 +90,12 @@ type HttpPluginRequest struct {
 	TempfileExtension  string `vfilter:"optional,field=tempfile_extension,doc=If specified we write to a tempfile. The content field will contain the full path to the tempfile."`
 	RemoveLast         bool   `vfilter:"optional,field=remove_last,doc=If set we delay removal as much as possible."`
 	RootCerts          string `vfilter:"optional,field=root_ca,doc=As a better alternative to disable_ssl_security, allows root ca certs to be added here."`
+	RateLimiter        string `vfilter:"optional,field=rate_limiter,doc=The name of a rate limiter defined in the server config to throttle requests with."`
+
+	// Mutual TLS and proxy settings for this call.
+	ClientCert string `vfilter:"optional,field=client_cert,doc=A PEM encoded client certificate to present to the server (mutual TLS)."`
+	ClientKey  string `vfilter:"optional,field=client_key,doc=The PEM encoded private key of the client certificate. If not set the key is read from client_cert."`
+	Proxy      string `vfilter:"optional,field=proxy,doc=A proxy URL (http, https or socks5) to use for this call instead of the proxy from the environment."`
 }
 
 type _HttpPluginResponse struct {
@@ -118,7 +134,7 @@ func (self *HTTPClientCache) GetHttpClient(
 		return nil, err
 	}
 
-	key := self.getCacheKey(url_obj)
+	key := self.getCacheKey(url_obj, arg)
 	result, pres := self.cache[key]
 	if pres {
 		return result, nil
@@ -161,19 +177,63 @@ func (self *HTTPClientCache) GetHttpClient(
 				},
 			},
 		}
-		self.cache[key] = result
-		return result, nil
+
+	} else {
+		result, err = GetDefaultHTTPClient(config_obj, arg.RootCerts)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	result, err = GetDefaultHTTPClient(config_obj, arg.RootCerts)
-	if err != nil {
-		return nil, err
+	transport, ok := result.Transport.(*http.Transport)
+	if ok {
+		err = configureTransport(transport, arg)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	self.cache[key] = result
 	return result, nil
 }
 
+// Apply the client certificate and proxy of the call to the
+// transport.
+func configureTransport(transport *http.Transport, arg *HttpPluginRequest) error {
+	if arg.ClientCert != "" {
+		// The certificate and key may be in the same PEM bundle.
+		key := arg.ClientKey
+		if key == "" {
+			key = arg.ClientCert
+		}
+
+		cert, err := tls.X509KeyPair([]byte(arg.ClientCert), []byte(key))
+		if err != nil {
+			return fmt.Errorf("Unable to load client certificate: %w", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+
+	} else if arg.ClientKey != "" {
+		return errors.New("client_key requires client_cert")
+	}
+
+	if arg.Proxy != "" {
+		proxy_url, err := url.Parse(arg.Proxy)
+		if err != nil {
+			return fmt.Errorf("Invalid proxy %v: %w", arg.Proxy, err)
+		}
+
+		switch proxy_url.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("Unsupported proxy scheme %v", proxy_url.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxy_url)
+	}
+
+	return nil
+}
+
 // If we deployed Velociraptor using self signed certificates we want
 // to be able to trust our own server. Our own server is signed by our
 // own CA and also may have a different common name (not related to
@@ -414,6 +474,15 @@ func (self *_HttpPlugin) Call(
 			}
 		}
 
+		if arg.RateLimiter != "" {
+			server_config_obj, _ := vql_subsystem.GetServerConfig(scope)
+			err = rate_limiter.Wait(ctx, server_config_obj, arg.RateLimiter)
+			if err != nil {
+				scope.Log("http_client: %v", err)
+				return
+			}
+		}
+
 		http_resp, err := client.Do(req)
 		if http_resp != nil {
 			defer http_resp.Body.Close()
@@ -0,0 +1,99 @@
+package networking
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/crypto"
+)
+
+func TestHttpClientCertificate(t *testing.T) {
+	bundle, err := crypto.GenerateCACert(2048)
+	require.NoError(t, err)
+
+	// The server requires a client certificate.
+	server := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%v", len(r.TLS.PeerCertificates))
+		}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	cache := &HTTPClientCache{cache: make(map[string]*http.Client)}
+	arg := &HttpPluginRequest{
+		Url:                server.URL,
+		DisableSSLSecurity: true,
+		ClientCert:         bundle.Cert,
+		ClientKey:          bundle.PrivateKey,
+	}
+
+	client, err := cache.GetHttpClient(nil, arg)
+	require.NoError(t, err)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(body))
+
+	// Calls without the certificate do not reuse the client.
+	plain, err := cache.GetHttpClient(nil, &HttpPluginRequest{
+		Url:                server.URL,
+		DisableSSLSecurity: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, plain != client)
+
+	_, err = plain.Get(server.URL)
+	assert.Error(t, err)
+}
+
+func TestConfigureTransport(t *testing.T) {
+	bundle, err := crypto.GenerateCACert(2048)
+	require.NoError(t, err)
+
+	// The key may be in the same PEM bundle as the certificate.
+	transport := &http.Transport{TLSClientConfig: &tls.Config{}}
+	err = configureTransport(transport, &HttpPluginRequest{
+		ClientCert: bundle.Cert + bundle.PrivateKey,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(transport.TLSClientConfig.Certificates))
+
+	err = configureTransport(transport, &HttpPluginRequest{
+		ClientKey: bundle.PrivateKey,
+	})
+	assert.Error(t, err)
+
+	err = configureTransport(transport, &HttpPluginRequest{
+		ClientCert: bundle.Cert,
+	})
+	assert.Error(t, err)
+
+	// Proxies are set per call.
+	err = configureTransport(transport, &HttpPluginRequest{
+		Proxy: "socks5://127.0.0.1:1080",
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest("GET", "https://www.example.com/", nil)
+	require.NoError(t, err)
+
+	proxy_url, err := transport.Proxy(request)
+	require.NoError(t, err)
+	assert.Equal(t, "socks5://127.0.0.1:1080", proxy_url.String())
+
+	err = configureTransport(transport, &HttpPluginRequest{
+		Proxy: "ftp://127.0.0.1/",
+	})
+	assert.Error(t, err)
+}
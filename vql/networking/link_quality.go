@@ -0,0 +1,195 @@
+package networking
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/constants"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type LinkQualityArgs struct {
+	Samples int64 `vfilter:"optional,field=samples,doc=Number of round trips used to measure latency (default 5)."`
+	Size    int64 `vfilter:"optional,field=size,doc=Bytes to transfer when measuring bandwidth (default 1Mb)."`
+}
+
+type LinkQualityPlugin struct{}
+
+func (self LinkQualityPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("link_quality: %s", err)
+			return
+		}
+
+		arg := &LinkQualityArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("link_quality: %s", err)
+			return
+		}
+
+		if arg.Samples <= 0 {
+			arg.Samples = 5
+		}
+
+		if arg.Size <= 0 {
+			arg.Size = 1024 * 1024
+		}
+
+		if arg.Size > constants.MAX_SPEEDTEST_SIZE {
+			arg.Size = constants.MAX_SPEEDTEST_SIZE
+		}
+
+		config_obj, ok := artifacts.GetConfig(scope)
+		if !ok || len(config_obj.ServerUrls) == 0 {
+			scope.Log("link_quality: No server URLs configured")
+			return
+		}
+
+		client, err := GetDefaultHTTPClient(config_obj, "")
+		if err != nil {
+			scope.Log("link_quality: %v", err)
+			return
+		}
+
+		for _, server_url := range config_obj.ServerUrls {
+			row := measureLinkQuality(ctx, client, server_url, arg)
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+// Measure the link to a single frontend.
+func measureLinkQuality(ctx context.Context,
+	client *http.Client, server_url string,
+	arg *LinkQualityArgs) *ordereddict.Dict {
+	url := strings.TrimSuffix(server_url, "/") + "/speedtest"
+	result := ordereddict.NewDict().
+		Set("Url", server_url).
+		Set("LatencyMs", 0.0).
+		Set("MinLatencyMs", 0.0).
+		Set("DownloadBps", uint64(0)).
+		Set("UploadBps", uint64(0)).
+		Set("Error", "")
+
+	// The first request sets up the connection so is not counted.
+	_, err := timeRequest(ctx, client, "GET", url+"?size=0", nil)
+	if err != nil {
+		return result.Set("Error", err.Error())
+	}
+
+	var total, min_elapsed time.Duration
+	for i := int64(0); i < arg.Samples; i++ {
+		elapsed, err := timeRequest(ctx, client, "GET", url+"?size=0", nil)
+		if err != nil {
+			return result.Set("Error", err.Error())
+		}
+		total += elapsed
+		if min_elapsed == 0 || elapsed < min_elapsed {
+			min_elapsed = elapsed
+		}
+	}
+	result.Set("LatencyMs", durationToMs(total/time.Duration(arg.Samples))).
+		Set("MinLatencyMs", durationToMs(min_elapsed))
+
+	elapsed, err := timeRequest(ctx, client, "GET",
+		fmt.Sprintf("%s?size=%d", url, arg.Size), nil)
+	if err != nil {
+		return result.Set("Error", err.Error())
+	}
+	result.Set("DownloadBps", bytesPerSecond(arg.Size, elapsed))
+
+	elapsed, err = timeRequest(ctx, client, "POST", url,
+		make([]byte, arg.Size))
+	if err != nil {
+		return result.Set("Error", err.Error())
+	}
+	result.Set("UploadBps", bytesPerSecond(arg.Size, elapsed))
+
+	return result
+}
+
+// Time a request until the entire response is read.
+func timeRequest(ctx context.Context, client *http.Client,
+	method, url string, body []byte) (time.Duration, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", constants.USER_AGENT)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Now().Sub(start)
+
+	if resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("Server returned %v", resp.Status)
+	}
+
+	return elapsed, nil
+}
+
+func durationToMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func bytesPerSecond(size int64, elapsed time.Duration) uint64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return uint64(float64(size) / elapsed.Seconds())
+}
+
+func (self LinkQualityPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "link_quality",
+		Doc:     "Measure the latency and bandwidth of the link to each frontend.",
+		ArgType: type_map.AddType(scope, &LinkQualityArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&LinkQualityPlugin{})
+}
@@ -0,0 +1,285 @@
+/* Plugin watch_network.
+
+Clients move between networks (e.g. a laptop joining a hotel WiFi or a
+VPN coming up). The watch_network() plugin periodically fingerprints
+the active network - the default gateways, DNS servers and active
+interfaces - and emits a row whenever the fingerprint changes. The
+first fingerprint is the baseline and is not emitted.
+
+Monitoring artifacts can use these events to adjust their collection
+or to alert when the client joins an unexpected network.
+*/
+
+package networking
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+// Interfaces with these name prefixes are considered VPN tunnels.
+var vpnInterfacePrefixes = []string{
+	"tun", "tap", "utun", "ppp", "wg", "ipsec", "gpd", "vpn",
+}
+
+type networkFingerprint struct {
+	DefaultGateways []string
+	DNSServers      []string
+
+	// Active interfaces in the form name=address,address
+	Interfaces    []string
+	VPNInterfaces []string
+}
+
+func (self *networkFingerprint) ToDict() *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("DefaultGateways", self.DefaultGateways).
+		Set("DNSServers", self.DNSServers).
+		Set("Interfaces", self.Interfaces).
+		Set("VPNInterfaces", self.VPNInterfaces)
+}
+
+// Describe what changed between the old and new fingerprint. An
+// empty list means the network is the same.
+func compareFingerprints(old, new *networkFingerprint) []string {
+	var changes []string
+
+	if !utils.StringSliceEq(old.DefaultGateways, new.DefaultGateways) {
+		changes = append(changes, "DefaultGateway")
+	}
+
+	if !utils.StringSliceEq(old.DNSServers, new.DNSServers) {
+		changes = append(changes, "DNSServers")
+	}
+
+	if len(old.VPNInterfaces) == 0 && len(new.VPNInterfaces) > 0 {
+		changes = append(changes, "VPNUp")
+	} else if len(old.VPNInterfaces) > 0 && len(new.VPNInterfaces) == 0 {
+		changes = append(changes, "VPNDown")
+	} else if !utils.StringSliceEq(old.VPNInterfaces, new.VPNInterfaces) {
+		changes = append(changes, "VPNChanged")
+	}
+
+	if !utils.StringSliceEq(old.Interfaces, new.Interfaces) {
+		changes = append(changes, "Interfaces")
+	}
+
+	return changes
+}
+
+func isVPNInterface(iface *net.Interface) bool {
+	if iface.Flags&net.FlagPointToPoint != 0 {
+		return true
+	}
+
+	name := strings.ToLower(iface.Name)
+	for _, prefix := range vpnInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func getNetworkFingerprint() (*networkFingerprint, error) {
+	result := &networkFingerprint{
+		DefaultGateways: getDefaultGateways(),
+		DNSServers:      getDNSServers(),
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 ||
+			iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil || len(addrs) == 0 {
+			continue
+		}
+
+		addresses := make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			addresses = append(addresses, addr.String())
+		}
+		sort.Strings(addresses)
+
+		result.Interfaces = append(result.Interfaces,
+			iface.Name+"="+strings.Join(addresses, ","))
+
+		local_iface := iface
+		if isVPNInterface(&local_iface) {
+			result.VPNInterfaces = append(result.VPNInterfaces, iface.Name)
+		}
+	}
+
+	sort.Strings(result.Interfaces)
+	sort.Strings(result.VPNInterfaces)
+
+	return result, nil
+}
+
+// Parse the nameserver lines from a resolv.conf file.
+func parseResolvConf(reader io.Reader) []string {
+	var result []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			result = append(result, fields[1])
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+func getDNSServersFromResolvConf() []string {
+	fd, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return nil
+	}
+	defer fd.Close()
+
+	return parseResolvConf(fd)
+}
+
+// Parse the default gateways from /proc/net/route. Addresses are
+// stored as little endian hex.
+func parseProcNetRoute(reader io.Reader) []string {
+	var result []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+
+		gateway, err := hex.DecodeString(fields[2])
+		if err != nil || len(gateway) != 4 {
+			continue
+		}
+
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(gateway))
+		if ip.IsUnspecified() {
+			continue
+		}
+
+		result = append(result, fmt.Sprintf("%v via %v", ip, fields[0]))
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+type WatchNetworkArgs struct {
+	Period int64 `vfilter:"optional,field=period,doc=Seconds between checks of the network (default 10)."`
+}
+
+type WatchNetworkPlugin struct{}
+
+func (self WatchNetworkPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("watch_network: %s", err)
+			return
+		}
+
+		arg := &WatchNetworkArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("watch_network: %s", err)
+			return
+		}
+
+		if arg.Period <= 0 {
+			arg.Period = 10
+		}
+
+		last, err := getNetworkFingerprint()
+		if err != nil {
+			scope.Log("watch_network: %s", err)
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(time.Duration(arg.Period) * time.Second):
+			}
+
+			current, err := getNetworkFingerprint()
+			if err != nil {
+				scope.Log("watch_network: %s", err)
+				continue
+			}
+
+			changes := compareFingerprints(last, current)
+			if len(changes) == 0 {
+				continue
+			}
+
+			row := current.ToDict().
+				Set("Time", utils.GetTime().Now()).
+				Set("Changes", changes).
+				Set("Previous", last.ToDict())
+			last = current
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self WatchNetworkPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "watch_network",
+		Doc:     "Emit an event when the active network changes (default gateway, DNS servers, VPN or interfaces).",
+		ArgType: type_map.AddType(scope, &WatchNetworkArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WatchNetworkPlugin{})
+}
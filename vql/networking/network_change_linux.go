@@ -0,0 +1,19 @@
+// +build linux
+
+package networking
+
+import "os"
+
+func getDefaultGateways() []string {
+	fd, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil
+	}
+	defer fd.Close()
+
+	return parseProcNetRoute(fd)
+}
+
+func getDNSServers() []string {
+	return getDNSServersFromResolvConf()
+}
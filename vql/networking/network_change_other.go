@@ -0,0 +1,13 @@
+// +build !linux,!windows
+
+package networking
+
+// There is no simple way to read the routing table on this platform
+// so only DNS and interface changes are detected.
+func getDefaultGateways() []string {
+	return nil
+}
+
+func getDNSServers() []string {
+	return getDNSServersFromResolvConf()
+}
@@ -0,0 +1,48 @@
+package networking
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProcNetRoute(t *testing.T) {
+	route := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	0101A8C0	0003	0	0	100	00000000	0	0	0
+eth0	0001A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+`
+	assert.Equal(t, []string{"192.168.1.1 via eth0"},
+		parseProcNetRoute(strings.NewReader(route)))
+}
+
+func TestParseResolvConf(t *testing.T) {
+	resolv_conf := `# Generated by NetworkManager
+search example.com
+nameserver 8.8.8.8
+nameserver 1.1.1.1
+`
+	assert.Equal(t, []string{"1.1.1.1", "8.8.8.8"},
+		parseResolvConf(strings.NewReader(resolv_conf)))
+}
+
+func TestCompareFingerprints(t *testing.T) {
+	home := &networkFingerprint{
+		DefaultGateways: []string{"192.168.1.1 via eth0"},
+		DNSServers:      []string{"192.168.1.1"},
+		Interfaces:      []string{"eth0=192.168.1.10/24"},
+	}
+	assert.Empty(t, compareFingerprints(home, home))
+
+	vpn := &networkFingerprint{
+		DefaultGateways: []string{"10.8.0.1 via tun0"},
+		DNSServers:      []string{"192.168.1.1"},
+		Interfaces: []string{
+			"eth0=192.168.1.10/24", "tun0=10.8.0.2/24"},
+		VPNInterfaces: []string{"tun0"},
+	}
+	assert.Equal(t, []string{"DefaultGateway", "VPNUp", "Interfaces"},
+		compareFingerprints(home, vpn))
+	assert.Equal(t, []string{"DefaultGateway", "VPNDown", "Interfaces"},
+		compareFingerprints(vpn, home))
+}
@@ -0,0 +1,86 @@
+// +build windows
+
+package networking
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+const tcpipInterfacesKey = `SYSTEM\CurrentControlSet\Services\Tcpip\Parameters\Interfaces`
+
+// Read the values from all the TCP/IP interface keys. Static values
+// take precedence over DHCP assigned values.
+func readInterfaceValues(static_name, dhcp_name string) []string {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		tcpipInterfacesKey, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil
+	}
+	defer key.Close()
+
+	subkeys, err := key.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, subkey_name := range subkeys {
+		subkey, err := registry.OpenKey(key, subkey_name, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+
+		values := readRegistryList(subkey, static_name)
+		if len(values) == 0 {
+			values = readRegistryList(subkey, dhcp_name)
+		}
+		subkey.Close()
+
+		for _, value := range values {
+			seen[value] = true
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for value := range seen {
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Values are either REG_MULTI_SZ or REG_SZ separated by spaces or
+// commas.
+func readRegistryList(key registry.Key, name string) []string {
+	var result []string
+
+	values, _, err := key.GetStringsValue(name)
+	if err != nil {
+		value, _, err := key.GetStringValue(name)
+		if err != nil {
+			return nil
+		}
+		values = strings.FieldsFunc(value, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+	}
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && value != "0.0.0.0" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func getDefaultGateways() []string {
+	return readInterfaceValues("DefaultGateway", "DhcpDefaultGateway")
+}
+
+func getDNSServers() []string {
+	return readInterfaceValues("NameServer", "DhcpNameServer")
+}
@@ -0,0 +1,244 @@
+package networking
+
+// Serve cached tools to other clients in the same site. See
+// services/launcher/tool_cache.go
+
+// Tools are requested as /<filename>?hash=<sha256>. A file is only
+// served if its hash matches the requested hash, so the server only
+// ever hands out files the caller could already identify (i.e. tools
+// declared in the inventory) and never other files in the directory.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type ToolCacheServerArgs struct {
+	Directory string `vfilter:"required,field=directory,doc=The directory holding the cached tools."`
+	Bind      string `vfilter:"optional,field=bind,doc=The address to listen on (default 0.0.0.0:8765)."`
+}
+
+type cachedHash struct {
+	size  int64
+	mtime time.Time
+	hash  string
+}
+
+type toolCacheHandler struct {
+	mu        sync.Mutex
+	directory string
+
+	// Hashes of the files keyed by filename so files are only
+	// hashed again when they change.
+	hashes map[string]*cachedHash
+
+	ctx         context.Context
+	output_chan chan vfilter.Row
+
+	// Requests in flight which may still send rows.
+	wg sync.WaitGroup
+}
+
+func (self *toolCacheHandler) getHash(
+	filename string, stat os.FileInfo) (string, error) {
+	self.mu.Lock()
+	cached, pres := self.hashes[filename]
+	self.mu.Unlock()
+
+	if pres && cached.size == stat.Size() && cached.mtime.Equal(stat.ModTime()) {
+		return cached.hash, nil
+	}
+
+	fd, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+
+	sha_sum := sha256.New()
+	_, err = io.Copy(sha_sum, fd)
+	if err != nil {
+		return "", err
+	}
+
+	result := hex.EncodeToString(sha_sum.Sum(nil))
+
+	self.mu.Lock()
+	self.hashes[filename] = &cachedHash{
+		size:  stat.Size(),
+		mtime: stat.ModTime(),
+		hash:  result,
+	}
+	self.mu.Unlock()
+
+	return result, nil
+}
+
+// Open the requested tool if it is in the directory and has the
+// requested hash.
+func (self *toolCacheHandler) openTool(r *http.Request) (
+	*os.File, os.FileInfo, error) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return nil, nil, errors.New("Method not allowed")
+	}
+
+	// Only files directly in the directory are served.
+	name := path.Base(r.URL.Path)
+	if name == "/" || name == "." || name == ".." ||
+		strings.ContainsAny(name, `/\:`) {
+		return nil, nil, os.ErrNotExist
+	}
+
+	expected := strings.ToLower(r.URL.Query().Get("hash"))
+	if expected == "" {
+		return nil, nil, os.ErrNotExist
+	}
+
+	filename := filepath.Join(self.directory, name)
+	stat, err := os.Stat(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !stat.Mode().IsRegular() {
+		return nil, nil, os.ErrNotExist
+	}
+
+	hash, err := self.getHash(filename, stat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if hash != expected {
+		return nil, nil, os.ErrNotExist
+	}
+
+	fd, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fd, stat, nil
+}
+
+func (self *toolCacheHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.wg.Add(1)
+	defer self.wg.Done()
+
+	row := ordereddict.NewDict().
+		Set("Time", utils.GetTime().Now()).
+		Set("RemoteAddr", r.RemoteAddr).
+		Set("Filename", path.Base(r.URL.Path))
+
+	fd, stat, err := self.openTool(r)
+	if err != nil {
+		// Do not reveal which files exist.
+		http.NotFound(w, r)
+		row.Set("Status", http.StatusNotFound).Set("Size", 0)
+
+	} else {
+		defer fd.Close()
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, stat.Name(), stat.ModTime(), fd)
+		row.Set("Status", http.StatusOK).Set("Size", stat.Size())
+	}
+
+	select {
+	case <-self.ctx.Done():
+	case self.output_chan <- row:
+	}
+}
+
+type ToolCacheServerPlugin struct{}
+
+func (self ToolCacheServerPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_READ)
+		if err != nil {
+			scope.Log("tool_cache_server: %s", err)
+			return
+		}
+
+		arg := &ToolCacheServerArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("tool_cache_server: %s", err)
+			return
+		}
+
+		if arg.Bind == "" {
+			arg.Bind = "0.0.0.0:8765"
+		}
+
+		listener, err := net.Listen("tcp", arg.Bind)
+		if err != nil {
+			scope.Log("tool_cache_server: %s", err)
+			return
+		}
+
+		handler := &toolCacheHandler{
+			directory:   arg.Directory,
+			hashes:      make(map[string]*cachedHash),
+			ctx:         ctx,
+			output_chan: output_chan,
+		}
+		defer handler.wg.Wait()
+
+		server := &http.Server{
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			<-ctx.Done()
+			server.Close()
+		}()
+
+		scope.Log("tool_cache_server: Serving tools from %v on %v",
+			arg.Directory, listener.Addr())
+
+		err = server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			scope.Log("tool_cache_server: %s", err)
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ToolCacheServerPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "tool_cache_server",
+		Doc:     "Serve cached tools to other clients over HTTP.",
+		ArgType: type_map.AddType(scope, &ToolCacheServerArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ToolCacheServerPlugin{})
+}
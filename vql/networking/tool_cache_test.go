@@ -0,0 +1,81 @@
+package networking
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	vfilter "www.velocidex.com/golang/vfilter"
+)
+
+func TestToolCacheServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tool_cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cache_dir := filepath.Join(dir, "cache")
+	require.NoError(t, os.Mkdir(cache_dir, 0700))
+
+	content := []byte("tool binary")
+	err = ioutil.WriteFile(filepath.Join(cache_dir, "tool.exe"), content, 0600)
+	require.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(cache_dir, "secret.txt"), []byte("x"), 0600)
+	require.NoError(t, err)
+
+	// A file outside the cache directory.
+	err = ioutil.WriteFile(filepath.Join(dir, "outside.exe"), content, 0600)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	output_chan := make(chan vfilter.Row, 10)
+	server := httptest.NewServer(&toolCacheHandler{
+		directory:   cache_dir,
+		hashes:      make(map[string]*cachedHash),
+		ctx:         ctx,
+		output_chan: output_chan,
+	})
+	defer server.Close()
+
+	get := func(url string) (int, string) {
+		resp, err := http.Get(server.URL + url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	// The tool is served when the hash matches.
+	status, data := get("/tool.exe?hash=" + hash)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, string(content), data)
+
+	// Files are not served without the right hash.
+	status, _ = get("/tool.exe?hash=0000")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = get("/secret.txt")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// Files outside the directory are never served.
+	status, _ = get("/..%2Foutside.exe?hash=" + hash)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// Each request is reported.
+	assert.Equal(t, 4, len(output_chan))
+}
@@ -0,0 +1,175 @@
+package parsers
+
+// Parse the Amcache.hve hive into a normalized set of rows. Windows
+// 10 stores files in Root/InventoryApplicationFile with named values
+// while older versions use Root/File/<volume guid>/<file reference>
+// with numbered values.
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	// Output columns and the value names they come from in each
+	// format.
+	amcacheColumns = []struct {
+		Column, Inventory, Legacy string
+	}{
+		{"Path", "LowerCaseLongPath", "15"},
+		{"Name", "Name", ""},
+		{"SHA1", "FileId", "101"},
+		{"Size", "Size", "6"},
+		{"ProductName", "ProductName", "0"},
+		{"Publisher", "Publisher", "1"},
+		{"Version", "Version", "5"},
+		{"LinkDate", "LinkDate", ""},
+		{"ProgramId", "ProgramId", "100"},
+	}
+)
+
+// The file id is the SHA1 hash prefixed by 0000.
+func amcacheSHA1(file_id interface{}) string {
+	result, _ := file_id.(string)
+	if len(result) == 44 {
+		result = strings.TrimPrefix(result, "0000")
+	}
+	return strings.ToLower(result)
+}
+
+func amcacheRecord(source, key string, values *ordereddict.Dict) *ordereddict.Dict {
+	row := ordereddict.NewDict().
+		Set("Source", source).
+		Set("Key", key)
+
+	for _, column := range amcacheColumns {
+		name := column.Inventory
+		if source == "File" {
+			name = column.Legacy
+		}
+
+		var value interface{}
+		if name != "" {
+			value, _ = values.Get(name)
+		}
+
+		switch column.Column {
+		case "SHA1":
+			value = amcacheSHA1(value)
+
+		case "Name":
+			// The legacy format has no name so use the path.
+			if value == nil {
+				path, _ := row.GetString("Path")
+				value = path
+				idx := strings.LastIndex(path, "\\")
+				if idx >= 0 {
+					value = path[idx+1:]
+				}
+			}
+		}
+
+		row.Set(column.Column, value)
+	}
+
+	return row
+}
+
+type AmcachePluginArgs struct {
+	Filename *accessors.OSPath `vfilter:"required,field=filename,doc=The Amcache.hve hive to parse."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+}
+
+type AmcachePlugin struct{}
+
+func (self AmcachePlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer utils.RecoverVQL(scope)
+
+		arg := &AmcachePluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("parse_amcache: %v", err)
+			return
+		}
+
+		hive, err := openRawHive(scope, arg.Filename, arg.Accessor)
+		if err != nil {
+			scope.Log("parse_amcache: %v", err)
+			return
+		}
+
+		emit := func(source string, key accessors.FileInfo,
+			components ...string) bool {
+			components = append(components, key.Name())
+			values, err := hive.Values(components...)
+			if err != nil {
+				return true
+			}
+
+			row := amcacheRecord(source,
+				strings.Join(components, "\\"), values).
+				Set("LastModified", key.ModTime())
+
+			select {
+			case <-ctx.Done():
+				return false
+			case output_chan <- row:
+			}
+			return true
+		}
+
+		// Windows 10 and later
+		files, _ := hive.ReadDir("Root", "InventoryApplicationFile")
+		for _, file := range files {
+			if file.IsDir() &&
+				!emit("InventoryApplicationFile", file,
+					"Root", "InventoryApplicationFile") {
+				return
+			}
+		}
+
+		// Windows 7 and 8
+		volumes, _ := hive.ReadDir("Root", "File")
+		for _, volume := range volumes {
+			if !volume.IsDir() {
+				continue
+			}
+
+			files, _ := hive.ReadDir("Root", "File", volume.Name())
+			for _, file := range files {
+				if file.IsDir() &&
+					!emit("File", file, "Root", "File", volume.Name()) {
+					return
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self AmcachePlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "parse_amcache",
+		Doc:     "Parses the file entries from a raw Amcache.hve hive.",
+		ArgType: type_map.AddType(scope, &AmcachePluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&AmcachePlugin{})
+}
@@ -0,0 +1,45 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmcacheRecord(t *testing.T) {
+	row := amcacheRecord("InventoryApplicationFile",
+		"Root\\InventoryApplicationFile\\7z.exe|1e434041bd08abf9",
+		ordereddict.NewDict().
+			Set("LowerCaseLongPath", "c:\\program files\\7-zip\\7z.exe").
+			Set("Name", "7z.exe").
+			Set("FileId", "0000DF22612647E9404A515D48EBAD490349685250DE").
+			Set("Publisher", "igor pavlov"))
+
+	sha1, _ := row.Get("SHA1")
+	assert.Equal(t, "df22612647e9404a515d48ebad490349685250de", sha1)
+
+	name, _ := row.Get("Name")
+	assert.Equal(t, "7z.exe", name)
+
+	publisher, _ := row.Get("Publisher")
+	assert.Equal(t, "igor pavlov", publisher)
+
+	// Older versions use numbered values and have no name.
+	row = amcacheRecord("File", "Root\\File\\{guid}\\4800002f315",
+		ordereddict.NewDict().
+			Set("15", "C:\\Windows\\System32\\cmd.exe").
+			Set("101", "0000df22612647e9404a515d48ebad490349685250de").
+			Set("6", uint64(1024)))
+
+	path, _ := row.Get("Path")
+	assert.Equal(t, "C:\\Windows\\System32\\cmd.exe", path)
+
+	name, _ = row.Get("Name")
+	assert.Equal(t, "cmd.exe", name)
+
+	size, _ := row.Get("Size")
+	assert.Equal(t, uint64(1024), size)
+
+	assert.Equal(t, "ControlSet001", fmtControlSet(1))
+}
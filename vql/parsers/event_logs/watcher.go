@@ -13,6 +13,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/services/repository"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/parsers/watchers"
 	"www.velocidex.com/golang/vfilter"
 )
 
@@ -98,15 +99,16 @@ func (self *EventLogWatcherService) StartMonitoring(
 		frequency = 15
 	}
 
+	health := watchers.GetWatcherHealth(
+		"watch_evtx", filename.String(), accessor_name)
+	defer watchers.RemoveWatcherHealth(
+		"watch_evtx", filename.String(), accessor_name)
+
 	// A resolver for messages
 	resolver, _ := evtx.GetNativeResolver()
-	accessor, err := accessors.GetAccessor(accessor_name, scope)
-	if err != nil {
-		scope.Log("Registering watcher error: %v", err)
-		return
-	}
 
-	last_event := self.findLastEvent(scope, filename, accessor)
+	var accessor accessors.FileSystemAccessor
+	last_event := -1
 	key := filename.String() + accessor_name
 	for {
 		self.mu.Lock()
@@ -117,9 +119,27 @@ func (self *EventLogWatcherService) StartMonitoring(
 		if !pres || len(registration) == 0 {
 			return
 		}
+		health.SetListeners(len(registration))
+
+		// Keep trying to get the accessor rather than giving up on
+		// the first error.
+		if accessor == nil {
+			var err error
+			accessor, err = accessors.GetAccessor(accessor_name, scope)
+			if err != nil {
+				scope.Log("Registering watcher error: %v", err)
+				health.SetError(err)
+			}
+		}
+
+		if accessor != nil {
+			if last_event < 0 {
+				last_event = self.findLastEvent(scope, filename, accessor)
+			}
 
-		last_event = self.monitorOnce(
-			filename, accessor_name, accessor, last_event, resolver)
+			last_event = self.monitorOnce(
+				filename, accessor_name, accessor, last_event, resolver, health)
+		}
 
 		time.Sleep(time.Duration(frequency) * time.Second)
 	}
@@ -192,7 +212,8 @@ func (self *EventLogWatcherService) monitorOnce(
 	accessor_name string,
 	accessor accessors.FileSystemAccessor,
 	last_event int,
-	resolver evtx.MessageResolver) int {
+	resolver evtx.MessageResolver,
+	health *watchers.WatcherHealth) int {
 
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -200,22 +221,41 @@ func (self *EventLogWatcherService) monitorOnce(
 	key := filename.String() + accessor_name
 	handles := self.getActiveHandles(key)
 	if len(handles) == 0 {
-		return 0
+		return last_event
 	}
 
+	// If we fail to open the file (e.g. it is temporarily locked or
+	// its volume is unavailable) we keep our position so we do not
+	// replay old events when it becomes available again.
 	fd, err := accessor.OpenWithOSPath(filename)
 	if err != nil {
 		for _, handle := range handles {
 			handle.scope.Log("Unable to open file %s: %v",
 				filename, err)
 		}
-		return 0
+		health.SetState(watchers.STATE_MISSING)
+		return last_event
 	}
 	defer fd.Close()
 
 	chunks, err := evtx.GetChunks(fd)
 	if err != nil {
-		return 0
+		health.SetError(err)
+		return last_event
+	}
+	health.SetState(watchers.STATE_OK)
+
+	// If the log was cleared the record ids start again from 1 -
+	// resubscribe from the start of the new log.
+	max_record_id := 0
+	for _, c := range chunks {
+		if c != nil && int(c.Header.LastEventRecID) > max_record_id {
+			max_record_id = int(c.Header.LastEventRecID)
+		}
+	}
+	if max_record_id < last_event {
+		health.Restarted()
+		last_event = 0
 	}
 
 	new_last_event := last_event
@@ -255,6 +295,7 @@ func (self *EventLogWatcherService) monitorOnce(
 					new_handles = append(new_handles, handle)
 				}
 			}
+			health.EventSent()
 
 			// No more listeners - we dont care any more.
 			if len(new_handles) == 0 {
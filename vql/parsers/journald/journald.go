@@ -0,0 +1,179 @@
+package journald
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/functions"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type JournalPluginArgs struct {
+	Filenames []*accessors.OSPath `vfilter:"required,field=filename,doc=A list of journal files to parse."`
+	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	StartTime vfilter.Any         `vfilter:"optional,field=start_time,doc=Only show entries after this time."`
+	EndTime   vfilter.Any         `vfilter:"optional,field=end_time,doc=Only show entries before this time."`
+	Raw       bool                `vfilter:"optional,field=raw,doc=Emit all the fields as they appear in the journal."`
+}
+
+type JournalPlugin struct{}
+
+func (self JournalPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &JournalPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("parse_journald: %v", err)
+			return
+		}
+
+		var start_time, end_time time.Time
+		if !utils.IsNil(arg.StartTime) {
+			start_time, err = functions.TimeFromAny(scope, arg.StartTime)
+			if err != nil {
+				scope.Log("parse_journald: start_time: %v", err)
+				return
+			}
+		}
+
+		if !utils.IsNil(arg.EndTime) {
+			end_time, err = functions.TimeFromAny(scope, arg.EndTime)
+			if err != nil {
+				scope.Log("parse_journald: end_time: %v", err)
+				return
+			}
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("parse_journald: %v", err)
+			return
+		}
+
+		accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+		if err != nil {
+			scope.Log("parse_journald: %v", err)
+			return
+		}
+
+		for _, filename := range arg.Filenames {
+			func() {
+				defer utils.RecoverVQL(scope)
+
+				fd, err := accessor.OpenWithOSPath(filename)
+				if err != nil {
+					scope.Log("parse_journald: Unable to open file %s: %v",
+						filename, err)
+					return
+				}
+				defer fd.Close()
+
+				reader, ok := fd.(io.ReaderAt)
+				if !ok {
+					reader = utils.MakeReaderAtter(fd)
+				}
+
+				journal, err := NewJournalReader(reader)
+				if err != nil {
+					scope.Log("parse_journald: Unable to parse file %s: %v",
+						filename, err)
+					return
+				}
+
+				for entry := range journal.Entries(ctx) {
+					if !start_time.IsZero() && entry.Realtime.Before(start_time) {
+						continue
+					}
+
+					if !end_time.IsZero() && entry.Realtime.After(end_time) {
+						continue
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case output_chan <- makeRow(entry, arg.Raw):
+					}
+				}
+			}()
+		}
+	}()
+
+	return output_chan
+}
+
+// Decode the commonly used fields into a more convenient form. See
+// systemd.journal-fields(7)
+func makeRow(entry *Entry, raw bool) *ordereddict.Dict {
+	if raw {
+		return ordereddict.NewDict().
+			Set("Timestamp", entry.Realtime).
+			Set("Seqnum", entry.Seqnum).
+			Set("Monotonic", entry.Monotonic).
+			Set("BootId", entry.BootId).
+			Set("Fields", entry.Fields)
+	}
+
+	getString := func(name string) string {
+		value, _ := entry.Fields.GetString(name)
+		return value
+	}
+
+	getInt := func(name string) int64 {
+		value, err := strconv.ParseInt(getString(name), 0, 64)
+		if err != nil {
+			return -1
+		}
+		return value
+	}
+
+	// Prefer the time the message was logged by the source if
+	// available.
+	timestamp := entry.Realtime
+	source_time := getInt("_SOURCE_REALTIME_TIMESTAMP")
+	if source_time > 0 {
+		timestamp = time.Unix(source_time/1000000,
+			(source_time%1000000)*1000).UTC()
+	}
+
+	return ordereddict.NewDict().
+		Set("Timestamp", timestamp).
+		Set("Seqnum", entry.Seqnum).
+		Set("BootId", entry.BootId).
+		Set("Hostname", getString("_HOSTNAME")).
+		Set("Priority", getInt("PRIORITY")).
+		Set("Unit", getString("_SYSTEMD_UNIT")).
+		Set("Identifier", getString("SYSLOG_IDENTIFIER")).
+		Set("Pid", getInt("_PID")).
+		Set("Uid", getInt("_UID")).
+		Set("Comm", getString("_COMM")).
+		Set("Exe", getString("_EXE")).
+		Set("Message", getString("MESSAGE")).
+		Set("Fields", entry.Fields)
+}
+
+func (self JournalPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "parse_journald",
+		Doc:     "Parse a systemd journal file.",
+		ArgType: type_map.AddType(scope, &JournalPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&JournalPlugin{})
+}
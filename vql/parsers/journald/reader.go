@@ -0,0 +1,340 @@
+// A parser for systemd journal files.
+
+// The format is documented in
+// https://systemd.io/JOURNAL_FILE_FORMAT/
+
+// The journal file consists of a header followed by a sequence of
+// 8 byte aligned objects. Each log entry is an ENTRY object which
+// refers to a set of DATA objects containing the fields in the form
+// KEY=VALUE. Data objects are shared between entries and may be
+// compressed.
+
+// Rather than following the entry arrays we walk the objects in file
+// order. This finds all entries even when the file was not closed
+// cleanly (e.g. a journal collected from a live system or a dead
+// disk image) and the entry arrays are incomplete.
+
+package journald
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	HEADER_SIGNATURE = "LPKSHHRH"
+
+	// Incompatible flags
+	HEADER_INCOMPATIBLE_COMPRESSED_XZ   = 1 << 0
+	HEADER_INCOMPATIBLE_COMPRESSED_LZ4  = 1 << 1
+	HEADER_INCOMPATIBLE_KEYED_HASH      = 1 << 2
+	HEADER_INCOMPATIBLE_COMPRESSED_ZSTD = 1 << 3
+	HEADER_INCOMPATIBLE_COMPACT         = 1 << 4
+
+	OBJECT_DATA            = 1
+	OBJECT_ENTRY           = 3
+	OBJECT_HEADER_SIZE     = 16
+	OBJECT_COMPRESSED_XZ   = 1 << 0
+	OBJECT_COMPRESSED_LZ4  = 1 << 1
+	OBJECT_COMPRESSED_ZSTD = 1 << 2
+
+	// The minimum header size we need (up to and including
+	// tail_entry_monotonic).
+	MIN_HEADER_SIZE = 208
+
+	// Objects larger than this are considered corrupt.
+	MAX_OBJECT_SIZE = 64 * 1024 * 1024
+
+	// Maximum number of data objects to cache.
+	MAX_DATA_CACHE = 10000
+)
+
+var (
+	invalidSignatureError = errors.New("Invalid journal signature")
+)
+
+type Header struct {
+	CompatibleFlags   uint32
+	IncompatibleFlags uint32
+	State             uint8
+	FileId            string
+	MachineId         string
+	BootId            string
+	HeaderSize        uint64
+	ArenaSize         uint64
+	TailObjectOffset  uint64
+	NObjects          uint64
+	NEntries          uint64
+	HeadEntryRealtime time.Time
+	TailEntryRealtime time.Time
+}
+
+type Entry struct {
+	Offset    uint64
+	Seqnum    uint64
+	Realtime  time.Time
+	Monotonic uint64
+	BootId    string
+
+	// Field name -> value in the order they appear in the entry.
+	Fields *ordereddict.Dict
+}
+
+type field struct {
+	key, value string
+}
+
+type JournalReader struct {
+	reader io.ReaderAt
+	Header *Header
+
+	compact bool
+
+	// Data objects are shared between many entries so we cache
+	// the decoded fields by offset.
+	data_cache map[uint64]*field
+}
+
+func NewJournalReader(reader io.ReaderAt) (*JournalReader, error) {
+	buf := make([]byte, MIN_HEADER_SIZE)
+	_, err := reader.ReadAt(buf, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(buf[:8]) != HEADER_SIGNATURE {
+		return nil, invalidSignatureError
+	}
+
+	header := &Header{
+		CompatibleFlags:   binary.LittleEndian.Uint32(buf[8:]),
+		IncompatibleFlags: binary.LittleEndian.Uint32(buf[12:]),
+		State:             buf[16],
+		FileId:            formatId(buf[24:40]),
+		MachineId:         formatId(buf[40:56]),
+		BootId:            formatId(buf[56:72]),
+		HeaderSize:        binary.LittleEndian.Uint64(buf[88:]),
+		ArenaSize:         binary.LittleEndian.Uint64(buf[96:]),
+		TailObjectOffset:  binary.LittleEndian.Uint64(buf[136:]),
+		NObjects:          binary.LittleEndian.Uint64(buf[144:]),
+		NEntries:          binary.LittleEndian.Uint64(buf[152:]),
+		HeadEntryRealtime: usecToTime(binary.LittleEndian.Uint64(buf[184:])),
+		TailEntryRealtime: usecToTime(binary.LittleEndian.Uint64(buf[192:])),
+	}
+
+	if header.HeaderSize < MIN_HEADER_SIZE {
+		return nil, fmt.Errorf("Invalid journal header size %v",
+			header.HeaderSize)
+	}
+
+	return &JournalReader{
+		reader:     reader,
+		Header:     header,
+		compact:    header.IncompatibleFlags&HEADER_INCOMPATIBLE_COMPACT != 0,
+		data_cache: make(map[uint64]*field),
+	}, nil
+}
+
+// Walk all the objects in the file and emit the entries.
+func (self *JournalReader) Entries(ctx context.Context) <-chan *Entry {
+	output_chan := make(chan *Entry)
+
+	go func() {
+		defer close(output_chan)
+
+		end := self.Header.HeaderSize + self.Header.ArenaSize
+		offset := self.Header.HeaderSize
+		for offset+OBJECT_HEADER_SIZE <= end {
+			object_type, _, size, err := self.readObjectHeader(offset)
+			if err != nil || size < OBJECT_HEADER_SIZE || size > MAX_OBJECT_SIZE {
+				// Unused space at the end of the file or
+				// corruption - there is nothing more we can
+				// read.
+				return
+			}
+
+			if object_type == OBJECT_ENTRY {
+				entry, err := self.readEntry(offset, size)
+				if err == nil {
+					select {
+					case <-ctx.Done():
+						return
+					case output_chan <- entry:
+					}
+				}
+			}
+
+			offset += align8(size)
+		}
+	}()
+
+	return output_chan
+}
+
+func (self *JournalReader) readObjectHeader(offset uint64) (
+	object_type uint8, flags uint8, size uint64, err error) {
+	buf := make([]byte, OBJECT_HEADER_SIZE)
+	_, err = self.reader.ReadAt(buf, int64(offset))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return buf[0], buf[1], binary.LittleEndian.Uint64(buf[8:]), nil
+}
+
+func (self *JournalReader) readObject(offset, size uint64) ([]byte, error) {
+	buf := make([]byte, size)
+	n, err := self.reader.ReadAt(buf, int64(offset))
+	if err != nil && !(errors.Is(err, io.EOF) && uint64(n) == size) {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (self *JournalReader) readEntry(offset, size uint64) (*Entry, error) {
+	if size < 64 {
+		return nil, fmt.Errorf("Entry object too small at %#x", offset)
+	}
+
+	buf, err := self.readObject(offset, size)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Offset:    offset,
+		Seqnum:    binary.LittleEndian.Uint64(buf[16:]),
+		Realtime:  usecToTime(binary.LittleEndian.Uint64(buf[24:])),
+		Monotonic: binary.LittleEndian.Uint64(buf[32:]),
+		BootId:    formatId(buf[40:56]),
+		Fields:    ordereddict.NewDict(),
+	}
+
+	// Each item refers to a data object.
+	items := buf[64:]
+	item_size := 16
+	if self.compact {
+		item_size = 4
+	}
+
+	for i := 0; i+item_size <= len(items); i += item_size {
+		var data_offset uint64
+		if self.compact {
+			data_offset = uint64(binary.LittleEndian.Uint32(items[i:]))
+		} else {
+			data_offset = binary.LittleEndian.Uint64(items[i:])
+		}
+
+		field, err := self.readData(data_offset)
+		if err != nil {
+			continue
+		}
+		entry.Fields.Set(field.key, field.value)
+	}
+
+	return entry, nil
+}
+
+func (self *JournalReader) readData(offset uint64) (*field, error) {
+	cached, pres := self.data_cache[offset]
+	if pres {
+		return cached, nil
+	}
+
+	object_type, flags, size, err := self.readObjectHeader(offset)
+	if err != nil {
+		return nil, err
+	}
+
+	payload_offset := uint64(64)
+	if self.compact {
+		payload_offset = 72
+	}
+
+	if object_type != OBJECT_DATA || size < payload_offset ||
+		size > MAX_OBJECT_SIZE {
+		return nil, fmt.Errorf("Invalid data object at %#x", offset)
+	}
+
+	buf, err := self.readObject(offset, size)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := decompress(flags, buf[payload_offset:])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &field{}
+	idx := bytes.IndexByte(payload, '=')
+	if idx < 0 {
+		result.key = string(payload)
+	} else {
+		result.key = string(payload[:idx])
+		result.value = string(payload[idx+1:])
+	}
+
+	if len(self.data_cache) > MAX_DATA_CACHE {
+		self.data_cache = make(map[uint64]*field)
+	}
+	self.data_cache[offset] = result
+
+	return result, nil
+}
+
+func decompress(flags uint8, payload []byte) ([]byte, error) {
+	switch {
+	case flags&OBJECT_COMPRESSED_XZ != 0:
+		reader, err := xz.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(io.LimitReader(reader, MAX_OBJECT_SIZE))
+
+	case flags&OBJECT_COMPRESSED_LZ4 != 0:
+		// The uncompressed size is stored before the LZ4 block.
+		if len(payload) < 8 {
+			return nil, errors.New("Invalid LZ4 payload")
+		}
+		size := binary.LittleEndian.Uint64(payload)
+		if size > MAX_OBJECT_SIZE {
+			return nil, errors.New("LZ4 payload too large")
+		}
+		return utils.LZ4DecompressBlock(payload[8:], int(size))
+
+	case flags&OBJECT_COMPRESSED_ZSTD != 0:
+		decoder, err := zstd.NewReader(nil,
+			zstd.WithDecoderMaxMemory(MAX_OBJECT_SIZE))
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+		return decoder.DecodeAll(payload, nil)
+	}
+
+	return payload, nil
+}
+
+func align8(size uint64) uint64 {
+	return (size + 7) &^ 7
+}
+
+func usecToTime(usec uint64) time.Time {
+	return time.Unix(int64(usec/1000000), int64(usec%1000000)*1000).UTC()
+}
+
+func formatId(id []byte) string {
+	return hex.EncodeToString(id)
+}
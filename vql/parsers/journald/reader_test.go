@@ -0,0 +1,137 @@
+package journald
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHeaderSize = 256
+
+// Builds a minimal journal file in memory.
+type journalBuilder struct {
+	compact bool
+	objects []byte
+}
+
+func (self *journalBuilder) offset() uint64 {
+	return uint64(testHeaderSize + len(self.objects))
+}
+
+func (self *journalBuilder) addObject(object_type, flags uint8, body []byte) uint64 {
+	offset := self.offset()
+
+	header := make([]byte, OBJECT_HEADER_SIZE)
+	header[0] = object_type
+	header[1] = flags
+	binary.LittleEndian.PutUint64(header[8:], uint64(len(body)+OBJECT_HEADER_SIZE))
+
+	self.objects = append(self.objects, header...)
+	self.objects = append(self.objects, body...)
+	for len(self.objects)%8 != 0 {
+		self.objects = append(self.objects, 0)
+	}
+	return offset
+}
+
+func (self *journalBuilder) addData(flags uint8, payload []byte) uint64 {
+	// hash, next_hash_offset, next_field_offset, entry_offset,
+	// entry_array_offset, n_entries
+	body := make([]byte, 48)
+	if self.compact {
+		body = append(body, make([]byte, 8)...)
+	}
+	return self.addObject(OBJECT_DATA, flags, append(body, payload...))
+}
+
+func (self *journalBuilder) addEntry(seqnum uint64, ts time.Time, data ...uint64) {
+	body := make([]byte, 48)
+	binary.LittleEndian.PutUint64(body[0:], seqnum)
+	binary.LittleEndian.PutUint64(body[8:], uint64(ts.UnixNano()/1000))
+	binary.LittleEndian.PutUint64(body[16:], seqnum*1000)
+	copy(body[24:40], []byte("0123456789abcdef"))
+
+	for _, offset := range data {
+		if self.compact {
+			item := make([]byte, 4)
+			binary.LittleEndian.PutUint32(item, uint32(offset))
+			body = append(body, item...)
+		} else {
+			item := make([]byte, 16)
+			binary.LittleEndian.PutUint64(item, offset)
+			body = append(body, item...)
+		}
+	}
+	self.addObject(OBJECT_ENTRY, 0, body)
+}
+
+func (self *journalBuilder) Bytes() []byte {
+	header := make([]byte, testHeaderSize)
+	copy(header, HEADER_SIGNATURE)
+	if self.compact {
+		binary.LittleEndian.PutUint32(header[12:], HEADER_INCOMPATIBLE_COMPACT)
+	}
+	binary.LittleEndian.PutUint64(header[88:], testHeaderSize)
+	binary.LittleEndian.PutUint64(header[96:], uint64(len(self.objects)))
+
+	// Simulate unused space at the end of the arena.
+	return append(append(header, self.objects...), make([]byte, 64)...)
+}
+
+func buildTestJournal(compact bool) []byte {
+	builder := &journalBuilder{compact: compact}
+
+	hostname := builder.addData(0, []byte("_HOSTNAME=test"))
+	message1 := builder.addData(0, []byte("MESSAGE=First message"))
+
+	// An LZ4 compressed MESSAGE=abababab! using a back reference.
+	lz4 := make([]byte, 8)
+	binary.LittleEndian.PutUint64(lz4, 17)
+	lz4 = append(lz4, 10<<4|2)
+	lz4 = append(lz4, []byte("MESSAGE=ab")...)
+	lz4 = append(lz4, 2, 0)
+	lz4 = append(lz4, 1<<4, '!')
+	message2 := builder.addData(OBJECT_COMPRESSED_LZ4, lz4)
+
+	builder.addEntry(1, time.Unix(1600000000, 0), hostname, message1)
+	builder.addEntry(2, time.Unix(1600000100, 0), hostname, message2)
+
+	return builder.Bytes()
+}
+
+func TestJournalReader(t *testing.T) {
+	for _, compact := range []bool{false, true} {
+		journal, err := NewJournalReader(
+			bytes.NewReader(buildTestJournal(compact)))
+		require.NoError(t, err)
+
+		entries := []*Entry{}
+		for entry := range journal.Entries(context.Background()) {
+			entries = append(entries, entry)
+		}
+
+		require.Equal(t, 2, len(entries))
+		assert.Equal(t, uint64(1), entries[0].Seqnum)
+		assert.Equal(t, int64(1600000000), entries[0].Realtime.Unix())
+		assert.Equal(t, "30313233343536373839616263646566", entries[0].BootId)
+
+		message, _ := entries[0].Fields.GetString("MESSAGE")
+		assert.Equal(t, "First message", message)
+
+		hostname, _ := entries[1].Fields.GetString("_HOSTNAME")
+		assert.Equal(t, "test", hostname)
+
+		message, _ = entries[1].Fields.GetString("MESSAGE")
+		assert.Equal(t, "abababab!", message)
+	}
+}
+
+func TestJournalReaderInvalid(t *testing.T) {
+	_, err := NewJournalReader(bytes.NewReader(make([]byte, 512)))
+	assert.Error(t, err)
+}
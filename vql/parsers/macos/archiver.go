@@ -0,0 +1,224 @@
+// Parsers for macOS artifacts.
+
+// Many macOS applications store their state as NSKeyedArchiver
+// archives inside binary plists. An archive is a flat list of objects
+// which refer to each other by UID, so it is very hard to read in its
+// raw form. We resolve the references into a nested object instead.
+package macos
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"howett.net/plist"
+)
+
+const (
+	// Archives may be deeply nested or even contain cycles.
+	MAX_ARCHIVE_DEPTH = 50
+)
+
+var (
+	// NSDate is stored as seconds since this time.
+	cocoaEpoch = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Resolve any NSKeyedArchiver archives and nested binary plists in
+// the decoded plist. Other values are returned as they are.
+func Unarchive(value interface{}) interface{} {
+	return unarchive(value, 0)
+}
+
+func unarchive(value interface{}, depth int) interface{} {
+	if depth > MAX_ARCHIVE_DEPTH {
+		return value
+	}
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+		archiver, _ := t["$archiver"].(string)
+		if archiver == "NSKeyedArchiver" {
+			return unarchiveKeyedArchive(t, depth)
+		}
+
+		result := ordereddict.NewDict()
+		for _, k := range sortedKeys(t) {
+			result.Set(k, unarchive(t[k], depth+1))
+		}
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, 0, len(t))
+		for _, item := range t {
+			result = append(result, unarchive(item, depth+1))
+		}
+		return result
+
+	case []byte:
+		// Binary plists are often embedded as data.
+		if bytes.HasPrefix(t, []byte("bplist00")) {
+			var nested interface{}
+			_, err := plist.Unmarshal(t, &nested)
+			if err == nil {
+				return unarchive(nested, depth+1)
+			}
+		}
+		return t
+	}
+
+	return value
+}
+
+type keyedArchive struct {
+	objects []interface{}
+}
+
+func unarchiveKeyedArchive(
+	archive map[string]interface{}, depth int) interface{} {
+	objects, _ := archive["$objects"].([]interface{})
+	top, _ := archive["$top"].(map[string]interface{})
+
+	self := &keyedArchive{objects: objects}
+
+	// Usually the archive has a single root object.
+	root, pres := top["root"]
+	if pres && len(top) == 1 {
+		return self.resolve(root, depth+1)
+	}
+
+	result := ordereddict.NewDict()
+	for _, k := range sortedKeys(top) {
+		result.Set(k, self.resolve(top[k], depth+1))
+	}
+	return result
+}
+
+// Follow a UID reference to the object it refers to.
+func (self *keyedArchive) resolve(value interface{}, depth int) interface{} {
+	if depth > MAX_ARCHIVE_DEPTH {
+		return nil
+	}
+
+	uid, ok := value.(plist.UID)
+	if !ok {
+		return self.decode(value, depth)
+	}
+
+	if int(uid) >= len(self.objects) {
+		return nil
+	}
+
+	return self.decode(self.objects[uid], depth)
+}
+
+// Convert an archived object to its natural form based on the well
+// known Foundation classes.
+func (self *keyedArchive) decode(value interface{}, depth int) interface{} {
+	switch t := value.(type) {
+	case string:
+		if t == "$null" {
+			return nil
+		}
+		return t
+
+	case []interface{}:
+		result := make([]interface{}, 0, len(t))
+		for _, item := range t {
+			result = append(result, self.resolve(item, depth+1))
+		}
+		return result
+
+	case []byte:
+		return unarchive(t, depth+1)
+
+	case map[string]interface{}:
+		// NSDictionary
+		keys, has_keys := t["NS.keys"].([]interface{})
+		objects, has_objects := t["NS.objects"].([]interface{})
+		if has_keys && has_objects {
+			result := ordereddict.NewDict()
+			for idx, key := range keys {
+				if idx >= len(objects) {
+					break
+				}
+				key_str, ok := self.resolve(key, depth+1).(string)
+				if !ok {
+					continue
+				}
+				result.Set(key_str, self.resolve(objects[idx], depth+1))
+			}
+			return result
+		}
+
+		// NSArray and NSSet
+		if has_objects {
+			return self.decode(objects, depth)
+		}
+
+		// NSString and NSMutableString
+		str, pres := t["NS.string"]
+		if pres {
+			return self.resolve(str, depth+1)
+		}
+
+		// NSData and NSMutableData
+		for _, field := range []string{"NS.bytes", "NS.data"} {
+			data, pres := t[field]
+			if pres {
+				return self.resolve(data, depth+1)
+			}
+		}
+
+		// NSDate
+		seconds, pres := t["NS.time"].(float64)
+		if pres {
+			return cocoaEpoch.Add(time.Duration(seconds * float64(time.Second)))
+		}
+
+		// NSURL
+		relative, pres := t["NS.relative"]
+		if pres {
+			base, _ := self.resolve(t["NS.base"], depth+1).(string)
+			relative_str, _ := self.resolve(relative, depth+1).(string)
+			return base + relative_str
+		}
+
+		// NSUUID
+		uuid, pres := t["NS.uuidbytes"].([]byte)
+		if pres && len(uuid) == 16 {
+			return formatUUID(uuid)
+		}
+
+		// Any other object is decoded field by field.
+		result := ordereddict.NewDict()
+		for _, k := range sortedKeys(t) {
+			if k == "$class" {
+				class_name := self.className(t[k])
+				if class_name != "" {
+					result.Set("$class", class_name)
+				}
+				continue
+			}
+			result.Set(k, self.resolve(t[k], depth+1))
+		}
+		return result
+	}
+
+	return value
+}
+
+func (self *keyedArchive) className(value interface{}) string {
+	uid, ok := value.(plist.UID)
+	if !ok || int(uid) >= len(self.objects) {
+		return ""
+	}
+
+	class, ok := self.objects[uid].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	name, _ := class["$classname"].(string)
+	return name
+}
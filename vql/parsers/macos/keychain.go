@@ -0,0 +1,330 @@
+package macos
+
+// A parser for the metadata of macOS keychain files
+// (login.keychain-db). The keychain is a database of tables, each
+// holding records with a fixed set of attributes. Secrets are stored
+// encrypted within the records and are never decoded here - we only
+// report which items exist, who they belong to and when they were
+// changed.
+
+// The layout follows the Apple CSSM DL source (AppleDatabase.cpp).
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+const (
+	KEYCHAIN_MAGIC = "kych"
+
+	// Size of the database header: magic, version, header size,
+	// schema offset and auth offset.
+	keychainHeaderSize = 20
+
+	// Size of the table header.
+	keychainTableHeaderSize = 28
+
+	// Size of the fixed part of a record before the attribute
+	// offsets.
+	keychainRecordHeaderSize = 24
+
+	// Refuse to read unreasonable structures from corrupt files.
+	MAX_KEYCHAIN_TABLES  = 1000
+	MAX_KEYCHAIN_RECORDS = 100000
+	MAX_ATTRIBUTE_SIZE   = 1024 * 1024
+)
+
+// CSSM record types of the tables we understand.
+const (
+	CSSM_DL_DB_RECORD_GENERIC_PASSWORD    = 0x80000000
+	CSSM_DL_DB_RECORD_INTERNET_PASSWORD   = 0x80000001
+	CSSM_DL_DB_RECORD_APPLESHARE_PASSWORD = 0x80000002
+)
+
+type attributeType int
+
+const (
+	attrString attributeType = iota
+	attrTime
+	attrFourCC
+	attrUint32
+)
+
+type keychainAttribute struct {
+	name string
+	kind attributeType
+}
+
+var (
+	// The attributes of each table in the order they appear in the
+	// record header.
+	genericPasswordAttributes = []keychainAttribute{
+		{"Created", attrTime},
+		{"Modified", attrTime},
+		{"Description", attrString},
+		{"Comment", attrString},
+		{"Creator", attrFourCC},
+		{"Type", attrFourCC},
+		{"ScriptCode", attrUint32},
+		{"PrintName", attrString},
+		{"Alias", attrString},
+		{"Invisible", attrUint32},
+		{"Negative", attrUint32},
+		{"CustomIcon", attrUint32},
+		{"Protected", attrString},
+		{"Account", attrString},
+		{"Service", attrString},
+		{"Generic", attrString},
+	}
+
+	internetPasswordAttributes = []keychainAttribute{
+		{"Created", attrTime},
+		{"Modified", attrTime},
+		{"Description", attrString},
+		{"Comment", attrString},
+		{"Creator", attrFourCC},
+		{"Type", attrFourCC},
+		{"PrintName", attrString},
+		{"Alias", attrString},
+		{"Protected", attrString},
+		{"Account", attrString},
+		{"SecurityDomain", attrString},
+		{"Server", attrString},
+		{"Protocol", attrFourCC},
+		{"AuthType", attrFourCC},
+		{"Port", attrUint32},
+		{"Path", attrString},
+	}
+
+	keychainTables = map[uint32]struct {
+		name       string
+		attributes []keychainAttribute
+	}{
+		CSSM_DL_DB_RECORD_GENERIC_PASSWORD: {
+			"GenericPassword", genericPasswordAttributes},
+		CSSM_DL_DB_RECORD_INTERNET_PASSWORD: {
+			"InternetPassword", internetPasswordAttributes},
+		CSSM_DL_DB_RECORD_APPLESHARE_PASSWORD: {
+			"AppleSharePassword", internetPasswordAttributes},
+	}
+
+	// Fields which are only meaningful when set.
+	optionalKeychainFields = map[string]bool{
+		"ScriptCode": true, "Invisible": true, "Negative": true,
+		"CustomIcon": true, "Protected": true, "Generic": true,
+	}
+)
+
+type KeychainParser struct {
+	reader io.ReaderAt
+}
+
+func NewKeychainParser(reader io.ReaderAt) (*KeychainParser, error) {
+	magic := make([]byte, 4)
+	_, err := reader.ReadAt(magic, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(magic) != KEYCHAIN_MAGIC {
+		return nil, errors.New("Not a keychain file")
+	}
+
+	return &KeychainParser{reader: reader}, nil
+}
+
+func (self *KeychainParser) uint32At(offset int64) (uint32, error) {
+	buf := make([]byte, 4)
+	_, err := self.reader.ReadAt(buf, offset)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(buf), nil
+}
+
+// Offsets of all the tables in the file.
+func (self *KeychainParser) tableOffsets() ([]int64, error) {
+	schema_offset, err := self.uint32At(12)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := keychainHeaderSize + int64(schema_offset)
+	count, err := self.uint32At(schema + 4)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > MAX_KEYCHAIN_TABLES {
+		return nil, errors.New("Too many tables in keychain")
+	}
+
+	var result []int64
+	for i := int64(0); i < int64(count); i++ {
+		offset, err := self.uint32At(schema + 8 + i*4)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, keychainHeaderSize+int64(offset))
+	}
+
+	return result, nil
+}
+
+// Call the callback with the metadata of each password item in the
+// keychain until it returns false.
+func (self *KeychainParser) Records(cb func(row *ordereddict.Dict) bool) error {
+	tables, err := self.tableOffsets()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		table_id, err := self.uint32At(table + 4)
+		if err != nil {
+			return err
+		}
+
+		definition, pres := keychainTables[table_id]
+		if !pres {
+			continue
+		}
+
+		record_count, err := self.uint32At(table + 8)
+		if err != nil {
+			return err
+		}
+
+		if record_count > MAX_KEYCHAIN_RECORDS {
+			return errors.New("Too many records in keychain table")
+		}
+
+		// The record offsets array may contain free slots (zero or
+		// unaligned offsets) which are skipped.
+		found := uint32(0)
+		for i := int64(0); found < record_count; i++ {
+			if i > MAX_KEYCHAIN_RECORDS {
+				break
+			}
+
+			record_offset, err := self.uint32At(
+				table + keychainTableHeaderSize + i*4)
+			if err != nil {
+				return err
+			}
+
+			if record_offset == 0 || record_offset%4 != 0 {
+				continue
+			}
+			found++
+
+			row, err := self.parseRecord(table+int64(record_offset),
+				definition.attributes)
+			if err != nil {
+				continue
+			}
+
+			result := ordereddict.NewDict().Set("Table", definition.name)
+			result.MergeFrom(row)
+			if !cb(result) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *KeychainParser) parseRecord(
+	record int64, attributes []keychainAttribute) (*ordereddict.Dict, error) {
+	record_size, err := self.uint32At(record)
+	if err != nil {
+		return nil, err
+	}
+
+	record_number, err := self.uint32At(record + 4)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ordereddict.NewDict().Set("RecordNumber", record_number)
+
+	for idx, attribute := range attributes {
+		offset, err := self.uint32At(
+			record + keychainRecordHeaderSize + int64(idx)*4)
+		if err != nil {
+			return nil, err
+		}
+
+		// The low bit marks the attribute as present.
+		offset &^= 1
+		if offset == 0 || offset >= record_size {
+			if !optionalKeychainFields[attribute.name] {
+				result.Set(attribute.name, nil)
+			}
+			continue
+		}
+
+		value, err := self.readAttribute(
+			record+int64(offset), attribute.kind)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Set(attribute.name, value)
+	}
+
+	return result, nil
+}
+
+func (self *KeychainParser) readAttribute(
+	offset int64, kind attributeType) (interface{}, error) {
+	switch kind {
+	case attrUint32:
+		return self.uint32At(offset)
+
+	case attrFourCC:
+		buf := make([]byte, 4)
+		_, err := self.reader.ReadAt(buf, offset)
+		if err != nil {
+			return nil, err
+		}
+		return strings.TrimRight(string(buf), "\x00 "), nil
+
+	case attrTime:
+		// Times are stored as a 16 byte string YYYYMMDDhhmmssZ
+		buf := make([]byte, 16)
+		_, err := self.reader.ReadAt(buf, offset)
+		if err != nil {
+			return nil, err
+		}
+		value := strings.TrimRight(string(buf), "\x00")
+		parsed, err := time.Parse("20060102150405Z", value)
+		if err != nil {
+			return value, nil
+		}
+		return parsed, nil
+
+	default:
+		length, err := self.uint32At(offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if length > MAX_ATTRIBUTE_SIZE {
+			return nil, errors.New("Attribute too large")
+		}
+
+		buf := make([]byte, length)
+		_, err = self.reader.ReadAt(buf, offset+4)
+		if err != nil {
+			return nil, err
+		}
+		return strings.TrimRight(string(buf), "\x00"), nil
+	}
+}
@@ -0,0 +1,357 @@
+package macos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"howett.net/plist"
+)
+
+func TestUnarchive(t *testing.T) {
+	archive := map[string]interface{}{
+		"$archiver": "NSKeyedArchiver",
+		"$version":  100000,
+		"$top": map[string]interface{}{
+			"root": plist.UID(1),
+		},
+		"$objects": []interface{}{
+			"$null",
+			// NSDictionary
+			map[string]interface{}{
+				"NS.keys":    []interface{}{plist.UID(2), plist.UID(3)},
+				"NS.objects": []interface{}{plist.UID(4), plist.UID(5)},
+				"$class":     plist.UID(8),
+			},
+			"Name",
+			"Updated",
+			// NSString
+			map[string]interface{}{
+				"NS.string": "Hello",
+				"$class":    plist.UID(9),
+			},
+			// NSDate
+			map[string]interface{}{
+				"NS.time": 86400.0,
+				"$class":  plist.UID(10),
+			},
+			"unused", "unused",
+			map[string]interface{}{"$classname": "NSDictionary"},
+			map[string]interface{}{"$classname": "NSString"},
+			map[string]interface{}{"$classname": "NSDate"},
+		},
+	}
+
+	serialized, err := plist.Marshal(archive, plist.BinaryFormat)
+	require.NoError(t, err)
+
+	// The archive is nested as data in another plist.
+	outer, err := plist.Marshal(map[string]interface{}{
+		"State": serialized,
+	}, plist.BinaryFormat)
+	require.NoError(t, err)
+
+	var decoded interface{}
+	_, err = plist.Unmarshal(outer, &decoded)
+	require.NoError(t, err)
+
+	result, ok := Unarchive(decoded).(*ordereddict.Dict)
+	require.True(t, ok)
+
+	state, ok := result.Get("State")
+	require.True(t, ok)
+
+	state_dict, ok := state.(*ordereddict.Dict)
+	require.True(t, ok)
+
+	name, _ := state_dict.Get("Name")
+	assert.Equal(t, "Hello", name)
+
+	updated, _ := state_dict.Get("Updated")
+	assert.Equal(t, time.Date(2001, 1, 2, 0, 0, 0, 0, time.UTC), updated)
+}
+
+type keychainBuilder struct {
+	bytes.Buffer
+}
+
+func (self *keychainBuilder) u32(values ...uint32) {
+	for _, v := range values {
+		_ = binary.Write(&self.Buffer, binary.BigEndian, v)
+	}
+}
+
+func TestKeychain(t *testing.T) {
+	// Attribute data follows the record header and the 16 attribute
+	// offsets.
+	record := &keychainBuilder{}
+	data_start := uint32(keychainRecordHeaderSize + 16*4)
+
+	data := &keychainBuilder{}
+	created := data_start + uint32(data.Len())
+	data.WriteString("20210102030405Z\x00")
+
+	print_name := data_start + uint32(data.Len())
+	data.u32(5)
+	data.WriteString("hello\x00\x00\x00")
+
+	account := data_start + uint32(data.Len())
+	data.u32(5)
+	data.WriteString("alice\x00\x00\x00")
+
+	service := data_start + uint32(data.Len())
+	data.u32(3)
+	data.WriteString("svc\x00")
+
+	creator := data_start + uint32(data.Len())
+	data.WriteString("aapl")
+
+	record_size := data_start + uint32(data.Len())
+
+	// RecordSize, RecordNumber, 2 unknowns, SSGPArea, unknown
+	record.u32(record_size, 7, 0, 0, 0, 0)
+
+	// Offsets of the generic password attributes - the low bit
+	// marks the attribute as present.
+	offsets := make([]uint32, len(genericPasswordAttributes))
+	offsets[0] = created | 1
+	offsets[4] = creator | 1
+	offsets[7] = print_name | 1
+	offsets[13] = account | 1
+	offsets[14] = service | 1
+	record.u32(offsets...)
+	record.Write(data.Bytes())
+
+	// The table has a free slot before the record.
+	table := &keychainBuilder{}
+	record_offset := uint32(keychainTableHeaderSize + 8)
+	table.u32(record_offset+uint32(record.Len()),
+		CSSM_DL_DB_RECORD_GENERIC_PASSWORD, 1, 0, 0, 0, 0)
+	table.u32(0, record_offset)
+	table.Write(record.Bytes())
+
+	file := &keychainBuilder{}
+	file.WriteString(KEYCHAIN_MAGIC)
+	file.u32(0x10000, 0x10, 0, 0)
+
+	// Schema: size, table count and table offsets relative to the
+	// end of the header.
+	file.u32(12, 1, 12)
+	file.Write(table.Bytes())
+
+	parser, err := NewKeychainParser(bytes.NewReader(file.Bytes()))
+	require.NoError(t, err)
+
+	var rows []*ordereddict.Dict
+	err = parser.Records(func(row *ordereddict.Dict) bool {
+		rows = append(rows, row)
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rows))
+
+	row := rows[0]
+	for k, v := range map[string]interface{}{
+		"Table":        "GenericPassword",
+		"RecordNumber": uint32(7),
+		"PrintName":    "hello",
+		"Account":      "alice",
+		"Service":      "svc",
+		"Creator":      "aapl",
+		"Created":      time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		"Description":  nil,
+	} {
+		value, pres := row.Get(k)
+		assert.True(t, pres, k)
+		assert.Equal(t, v, value, k)
+	}
+
+	// Unset optional fields are not reported.
+	_, pres := row.Get("Generic")
+	assert.False(t, pres)
+
+	// Other files are rejected.
+	_, err = NewKeychainParser(bytes.NewReader([]byte("bplist00")))
+	assert.Error(t, err)
+}
+
+type logBuilder struct {
+	bytes.Buffer
+}
+
+func (self *logBuilder) le(values ...interface{}) *logBuilder {
+	for _, v := range values {
+		_ = binary.Write(&self.Buffer, binary.LittleEndian, v)
+	}
+	return self
+}
+
+func (self *logBuilder) pad() *logBuilder {
+	for self.Len()%8 != 0 {
+		self.WriteByte(0)
+	}
+	return self
+}
+
+func (self *logBuilder) chunk(tag uint32, data []byte) *logBuilder {
+	self.le(tag, uint32(0), uint64(len(data)))
+	self.Write(data)
+	return self.pad()
+}
+
+// An LZ4 block containing only literals.
+func lz4Literals(data []byte) []byte {
+	result := []byte{0xf0}
+	length := len(data) - 15
+	for ; length >= 255; length -= 255 {
+		result = append(result, 255)
+	}
+	result = append(result, byte(length))
+	return append(result, data...)
+}
+
+func buildTestUnifiedLog() []byte {
+	header := &logBuilder{}
+	header.le(uint32(1), uint32(1), uint64(1000), uint64(1600000000),
+		uint32(0))
+	header.Write(make([]byte, 128-header.Len()))
+	header.Write(bytes.Repeat([]byte{0xab}, 16))
+	header.Write(make([]byte, 208-header.Len()))
+
+	catalog := &logBuilder{}
+	catalog.le(uint16(16), uint16(32), uint16(1), uint16(0), uint16(0))
+	catalog.Write(make([]byte, 6))
+	catalog.le(uint64(0))
+	catalog.Write(bytes.Repeat([]byte{0x11}, 16))
+	catalog.WriteString("com.test\x00cat\x00\x00\x00\x00")
+
+	// Index, unknown, main UUID index, DSC UUID index, proc ids,
+	// pid, euid, unknown, UUID count and unknown.
+	catalog.le(uint16(0), uint16(0), uint16(0), uint16(0),
+		uint64(1), uint32(2), uint32(123), uint32(501), uint32(0),
+		uint32(0), uint32(0))
+
+	// One subsystem with id 5.
+	catalog.le(uint32(1), uint32(0), uint16(5), uint16(0), uint16(9))
+	catalog.pad()
+
+	entries := &logBuilder{}
+
+	// An error from the main executable with a subsystem, a string
+	// and a number argument.
+	data := &logBuilder{}
+	data.le(uint16(5), uint8(0x2), uint8(2),
+		uint8(0x22), uint8(4), uint16(0), uint16(6),
+		uint8(0x0), uint8(4), uint32(42))
+	data.WriteString("world\x00")
+	entries.le(uint8(FIREHOSE_NONACTIVITY), uint8(0x10),
+		uint16(FIREHOSE_MAIN_EXE|FIREHOSE_HAS_SUBSYSTEM), uint32(0x10),
+		uint64(77), uint32(500), uint16(0), uint16(data.Len()))
+	entries.Write(data.Bytes())
+	entries.pad()
+
+	// The format string is in the shared cache.
+	data = &logBuilder{}
+	data.le(uint8(0x2), uint8(1), uint8(0x0), uint8(4), uint32(7))
+	entries.le(uint8(FIREHOSE_NONACTIVITY), uint8(0),
+		uint16(FIREHOSE_SHARED_CACHE), uint32(0x1234),
+		uint64(78), uint32(1000), uint16(0), uint16(data.Len()))
+	entries.Write(data.Bytes())
+	entries.pad()
+
+	firehose := &logBuilder{}
+	firehose.le(uint64(1), uint32(2), uint8(0), uint8(0), uint16(0),
+		uint16(16+entries.Len()), uint16(FIREHOSE_PRIVATE_END),
+		uint16(0), uint16(0), uint64(2000))
+	firehose.Write(entries.Bytes())
+
+	chunks := (&logBuilder{}).chunk(TRACEV3_FIREHOSE, firehose.Bytes())
+	chunkset := &logBuilder{}
+	chunkset.WriteString(CHUNKSET_LZ4)
+	block := lz4Literals(chunks.Bytes())
+	chunkset.le(uint32(chunks.Len()), uint32(len(block)))
+	chunkset.Write(block)
+	chunkset.WriteString(CHUNKSET_END)
+
+	return (&logBuilder{}).
+		chunk(TRACEV3_HEADER, header.Bytes()).
+		chunk(TRACEV3_CATALOG, catalog.Bytes()).
+		chunk(TRACEV3_CHUNKSET, chunkset.Bytes()).
+		Bytes()
+}
+
+func buildTestUUIDText() []byte {
+	format := "hello %{public}s %d\x00"
+	result := &logBuilder{}
+	result.le(uint32(UUIDTEXT_MAGIC), uint32(2), uint32(1), uint32(1),
+		uint32(0x10), uint32(len(format)))
+	result.WriteString(format)
+	result.WriteString("/usr/bin/test\x00")
+	return result.Bytes()
+}
+
+func TestUnifiedLog(t *testing.T) {
+	resolver := NewUUIDTextResolver(
+		func(dirname, filename string) (io.ReadCloser, error) {
+			if dirname+filename != strings.Repeat("11", 16) {
+				return nil, os.ErrNotExist
+			}
+			return io.NopCloser(bytes.NewReader(buildTestUUIDText())), nil
+		})
+
+	parser, err := NewUnifiedLogParser(
+		bytes.NewReader(buildTestUnifiedLog()), resolver)
+	require.NoError(t, err)
+	assert.Equal(t, "ABABABAB-ABAB-ABAB-ABAB-ABABABABABAB", parser.Header.BootUUID)
+
+	var rows []*ordereddict.Dict
+	err = parser.Entries(context.Background(), func(row *ordereddict.Dict) bool {
+		rows = append(rows, row)
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(rows))
+
+	for k, v := range map[string]interface{}{
+		"Time":         time.Unix(1600000000, 1500).UTC(),
+		"Pid":          uint32(123),
+		"Euid":         uint32(501),
+		"ThreadId":     uint64(77),
+		"ActivityType": "Log",
+		"LogType":      "Error",
+		"Subsystem":    "com.test",
+		"Category":     "cat",
+		"ImagePath":    "/usr/bin/test",
+		"FormatString": "hello %{public}s %d",
+		"Message":      "hello world 42",
+	} {
+		value, _ := rows[0].Get(k)
+		assert.Equal(t, v, value, k)
+	}
+
+	// Format strings in the shared cache are not resolved.
+	message, _ := rows[1].GetString("Message")
+	assert.Equal(t, "7", message)
+
+	_, err = NewUnifiedLogParser(bytes.NewReader([]byte("bplist00")), nil)
+	assert.Error(t, err)
+}
+
+func TestFormatLogMessage(t *testing.T) {
+	args := []logArg{
+		{Value: uint64(0xffffffff), Size: 4},
+		{Value: uint64(3), Size: 4, Precision: true},
+		{Value: "abcdef"},
+		{Value: uint64(255), Size: 4},
+	}
+	assert.Equal(t, "-1 abc ff 100% <decode: missing data>",
+		formatLogMessage("%d %.*s %x 100%% %{public}@", args))
+}
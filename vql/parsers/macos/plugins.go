@@ -0,0 +1,224 @@
+package macos
+
+import (
+	"context"
+	"io"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type KeychainPluginArgs struct {
+	Filenames []*accessors.OSPath `vfilter:"required,field=filename,doc=A list of keychain files to parse."`
+	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to use."`
+}
+
+type KeychainPlugin struct{}
+
+func (self KeychainPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &KeychainPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("parse_keychain: %v", err)
+			return
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("parse_keychain: %v", err)
+			return
+		}
+
+		accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+		if err != nil {
+			scope.Log("parse_keychain: %v", err)
+			return
+		}
+
+		for _, filename := range arg.Filenames {
+			func() {
+				defer utils.RecoverVQL(scope)
+
+				fd, err := accessor.OpenWithOSPath(filename)
+				if err != nil {
+					scope.Log("parse_keychain: Unable to open file %s: %v",
+						filename, err)
+					return
+				}
+				defer fd.Close()
+
+				parser, err := NewKeychainParser(utils.MakeReaderAtter(fd))
+				if err != nil {
+					scope.Log("parse_keychain: %s: %v", filename, err)
+					return
+				}
+
+				err = parser.Records(func(row *ordereddict.Dict) bool {
+					row.Set("_Source", filename)
+					select {
+					case <-ctx.Done():
+						return false
+					case output_chan <- row:
+						return true
+					}
+				})
+				if err != nil {
+					scope.Log("parse_keychain: %s: %v", filename, err)
+				}
+			}()
+		}
+	}()
+
+	return output_chan
+}
+
+func (self KeychainPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "parse_keychain",
+		Doc:     "Parse the metadata of password items in a macOS keychain. Secrets are not decoded.",
+		ArgType: type_map.AddType(scope, &KeychainPluginArgs{}),
+	}
+}
+
+type UnifiedLogPluginArgs struct {
+	Filenames []*accessors.OSPath `vfilter:"required,field=filename,doc=A list of tracev3 files to parse."`
+	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	UUIDText  *accessors.OSPath   `vfilter:"optional,field=uuidtext,doc=The uuidtext directory holding format strings (default the uuidtext directory next to the diagnostics directory)."`
+}
+
+type UnifiedLogPlugin struct{}
+
+func (self UnifiedLogPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &UnifiedLogPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("parse_unified_log: %v", err)
+			return
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("parse_unified_log: %v", err)
+			return
+		}
+
+		accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+		if err != nil {
+			scope.Log("parse_unified_log: %v", err)
+			return
+		}
+
+		// Format strings are shared by all the log files so cache
+		// them across files.
+		resolvers := make(map[string]*UUIDTextResolver)
+		getResolver := func(filename *accessors.OSPath) *UUIDTextResolver {
+			root := arg.UUIDText
+			if root == nil {
+				root = defaultUUIDTextPath(filename)
+			}
+			if root == nil {
+				return nil
+			}
+
+			key := root.String()
+			resolver, pres := resolvers[key]
+			if !pres {
+				resolver = NewUUIDTextResolver(
+					func(dirname, filename string) (io.ReadCloser, error) {
+						return accessor.OpenWithOSPath(
+							root.Append(dirname, filename))
+					})
+				resolvers[key] = resolver
+			}
+			return resolver
+		}
+
+		for _, filename := range arg.Filenames {
+			func() {
+				defer utils.RecoverVQL(scope)
+
+				fd, err := accessor.OpenWithOSPath(filename)
+				if err != nil {
+					scope.Log("parse_unified_log: Unable to open file %s: %v",
+						filename, err)
+					return
+				}
+				defer fd.Close()
+
+				var resolver FormatStringResolver
+				uuidtext_resolver := getResolver(filename)
+				if uuidtext_resolver != nil {
+					resolver = uuidtext_resolver
+				}
+
+				parser, err := NewUnifiedLogParser(
+					utils.MakeReaderAtter(fd), resolver)
+				if err != nil {
+					scope.Log("parse_unified_log: %s: %v", filename, err)
+					return
+				}
+
+				err = parser.Entries(ctx, func(row *ordereddict.Dict) bool {
+					row.Set("_Source", filename)
+					select {
+					case <-ctx.Done():
+						return false
+					case output_chan <- row:
+						return true
+					}
+				})
+				if err != nil {
+					scope.Log("parse_unified_log: %s: %v", filename, err)
+				}
+			}()
+		}
+	}()
+
+	return output_chan
+}
+
+// Log files are stored in /private/var/db/diagnostics/ and the
+// format strings in /private/var/db/uuidtext/
+func defaultUUIDTextPath(filename *accessors.OSPath) *accessors.OSPath {
+	for dirname := filename.Dirname(); len(dirname.Components) > 0; dirname = dirname.Dirname() {
+		if dirname.Basename() == "diagnostics" {
+			return dirname.Dirname().Append("uuidtext")
+		}
+	}
+	return nil
+}
+
+func (self UnifiedLogPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "parse_unified_log",
+		Doc:     "Parse macOS unified log (tracev3) files.",
+		ArgType: type_map.AddType(scope, &UnifiedLogPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&KeychainPlugin{})
+	vql_subsystem.RegisterPlugin(&UnifiedLogPlugin{})
+}
@@ -0,0 +1,809 @@
+package macos
+
+// A parser for macOS unified log files (tracev3).
+
+// Unified logs are stored in /private/var/db/diagnostics as a
+// sequence of chunks, each with a 16 byte preamble (tag, sub tag and
+// data size) and padded to 8 bytes:
+//
+// - The header chunk describes the boot session and the timebase
+//   used to convert continuous (mach) time into wall time.
+// - Catalog chunks describe the processes logging into the following
+//   chunksets, their image UUIDs and subsystems.
+// - Chunksets hold LZ4 compressed chunks. Firehose chunks within
+//   them contain the actual log entries.
+//
+// Log entries do not contain the message itself but only the
+// arguments and a reference to the format string. Format strings are
+// stored in the uuidtext files of the image which logged the
+// message, so those need to be available to rebuild the message.
+// Format strings in the dyld shared cache are not resolved - the
+// arguments are reported instead.
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+const (
+	TRACEV3_HEADER     = 0x1000
+	TRACEV3_CATALOG    = 0x600b
+	TRACEV3_CHUNKSET   = 0x600d
+	TRACEV3_FIREHOSE   = 0x6001
+	TRACEV3_OVERSIZE   = 0x6002
+	TRACEV3_STATEDUMP  = 0x6003
+	TRACEV3_SIMPLEDUMP = 0x6004
+
+	// Chunkset block signatures
+	CHUNKSET_LZ4          = "bv41"
+	CHUNKSET_UNCOMPRESSED = "bv4-"
+	CHUNKSET_END          = "bv4$"
+
+	// Firehose activity types
+	FIREHOSE_ACTIVITY    = 0x2
+	FIREHOSE_TRACE       = 0x3
+	FIREHOSE_NONACTIVITY = 0x4
+	FIREHOSE_SIGNPOST    = 0x6
+	FIREHOSE_LOSS        = 0x7
+
+	// Firehose entry flags
+	FIREHOSE_HAS_CURRENT_AID    = 0x1
+	FIREHOSE_FORMATTER_MASK     = 0xe
+	FIREHOSE_HAS_LARGE_OFFSET   = 0x20
+	FIREHOSE_HAS_PRIVATE_DATA   = 0x100
+	FIREHOSE_HAS_SUBSYSTEM      = 0x200
+	FIREHOSE_HAS_RULES          = 0x400
+	FIREHOSE_HAS_DATA_REF       = 0x800
+	FIREHOSE_MAIN_EXE           = 0x2
+	FIREHOSE_SHARED_CACHE       = 0x4
+	FIREHOSE_ABSOLUTE           = 0x8
+	FIREHOSE_UUID_RELATIVE      = 0xa
+	FIREHOSE_LARGE_SHARED_CACHE = 0xc
+
+	// Format strings with this bit set are dynamic (i.e. "%s").
+	FIREHOSE_DYNAMIC_FORMAT = 0x80000000
+
+	// The private data area of a firehose chunk ends at this
+	// virtual offset.
+	FIREHOSE_PRIVATE_END = 0x1000
+
+	chunkPreambleSize    = 16
+	firehosePreambleSize = 32
+	firehoseEntrySize    = 24
+
+	// Chunks larger than this are considered corrupt.
+	MAX_CHUNK_SIZE = 64 * 1024 * 1024
+)
+
+var (
+	invalidTraceV3Error = errors.New("Not a tracev3 file")
+
+	activityTypes = map[uint8]string{
+		FIREHOSE_ACTIVITY:    "Activity",
+		FIREHOSE_TRACE:       "Trace",
+		FIREHOSE_NONACTIVITY: "Log",
+		FIREHOSE_SIGNPOST:    "Signpost",
+		FIREHOSE_LOSS:        "Loss",
+	}
+
+	logTypes = map[uint8]string{
+		0x0:  "Default",
+		0x1:  "Info",
+		0x2:  "Debug",
+		0x10: "Error",
+		0x11: "Fault",
+	}
+)
+
+type TraceV3Header struct {
+	TimebaseNumer  uint32
+	TimebaseDenom  uint32
+	ContinuousTime uint64
+	WallTime       time.Time
+	BootUUID       string
+}
+
+type logSubsystem struct {
+	Subsystem string
+	Category  string
+}
+
+type catalogProcess struct {
+	Pid        uint32
+	Euid       uint32
+	MainUUID   string
+	subsystems map[uint16]logSubsystem
+}
+
+type processKey struct {
+	first  uint64
+	second uint32
+}
+
+type catalog struct {
+	uuids     []string
+	processes map[processKey]*catalogProcess
+}
+
+// Resolves format strings from the uuidtext files.
+type FormatStringResolver interface {
+	// Returns the format string at offset and the path of the
+	// image.
+	FormatString(uuid string, offset uint32) (string, string, bool)
+}
+
+type UnifiedLogParser struct {
+	reader   io.ReaderAt
+	resolver FormatStringResolver
+
+	Header *TraceV3Header
+}
+
+func NewUnifiedLogParser(reader io.ReaderAt,
+	resolver FormatStringResolver) (*UnifiedLogParser, error) {
+	self := &UnifiedLogParser{
+		reader:   reader,
+		resolver: resolver,
+	}
+
+	tag, data, _, err := self.readChunk(0)
+	if err != nil || tag != TRACEV3_HEADER {
+		return nil, invalidTraceV3Error
+	}
+
+	self.Header, err = parseTraceV3Header(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return self, nil
+}
+
+// Read the chunk at offset and return its tag, data and the offset
+// of the next chunk.
+func (self *UnifiedLogParser) readChunk(offset int64) (
+	uint32, []byte, int64, error) {
+	preamble := make([]byte, chunkPreambleSize)
+	_, err := self.reader.ReadAt(preamble, offset)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	tag := binary.LittleEndian.Uint32(preamble)
+	size := binary.LittleEndian.Uint64(preamble[8:])
+	if size > MAX_CHUNK_SIZE {
+		return 0, nil, 0, fmt.Errorf("Chunk at %#x is too large", offset)
+	}
+
+	data := make([]byte, size)
+	n, err := self.reader.ReadAt(data, offset+chunkPreambleSize)
+	if err != nil && !(errors.Is(err, io.EOF) && uint64(n) == size) {
+		return 0, nil, 0, err
+	}
+
+	next := offset + chunkPreambleSize + int64(align8(size))
+	return tag, data, next, nil
+}
+
+func (self *UnifiedLogParser) Entries(
+	ctx context.Context, cb func(row *ordereddict.Dict) bool) error {
+	var current *catalog
+
+	offset := int64(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		tag, data, next, err := self.readChunk(offset)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		offset = next
+
+		switch tag {
+		case TRACEV3_CATALOG:
+			current, err = parseCatalog(data)
+			if err != nil {
+				return fmt.Errorf("Catalog at %#x: %w", offset, err)
+			}
+
+		case TRACEV3_CHUNKSET:
+			if current == nil {
+				continue
+			}
+
+			decompressed, err := decompressChunkset(data)
+			if err != nil {
+				return fmt.Errorf("Chunkset at %#x: %w", offset, err)
+			}
+
+			if !self.parseChunkset(current, decompressed, cb) {
+				return nil
+			}
+		}
+	}
+}
+
+func (self *UnifiedLogParser) parseChunkset(
+	current *catalog, data []byte, cb func(row *ordereddict.Dict) bool) bool {
+	for len(data) >= chunkPreambleSize {
+		tag := binary.LittleEndian.Uint32(data)
+		size := binary.LittleEndian.Uint64(data[8:])
+		if size > uint64(len(data)-chunkPreambleSize) {
+			return true
+		}
+
+		chunk := data[chunkPreambleSize : chunkPreambleSize+size]
+		if tag == TRACEV3_FIREHOSE {
+			if !self.parseFirehose(current, chunk, cb) {
+				return false
+			}
+		}
+
+		next := chunkPreambleSize + align8(size)
+		if next >= uint64(len(data)) {
+			break
+		}
+		data = data[next:]
+	}
+	return true
+}
+
+// Convert continuous time (mach ticks since boot) to wall time.
+func (self *UnifiedLogParser) wallTime(continuous_time uint64) time.Time {
+	numer, denom := uint64(self.Header.TimebaseNumer), uint64(self.Header.TimebaseDenom)
+	if numer == 0 || denom == 0 {
+		numer, denom = 1, 1
+	}
+
+	delta := int64(continuous_time) - int64(self.Header.ContinuousTime)
+	return self.Header.WallTime.Add(
+		time.Duration(delta * int64(numer) / int64(denom))).UTC()
+}
+
+func (self *UnifiedLogParser) parseFirehose(current *catalog,
+	chunk []byte, cb func(row *ordereddict.Dict) bool) bool {
+	if len(chunk) < firehosePreambleSize {
+		return true
+	}
+
+	key := processKey{
+		first:  binary.LittleEndian.Uint64(chunk),
+		second: binary.LittleEndian.Uint32(chunk[8:]),
+	}
+	public_size := int(binary.LittleEndian.Uint16(chunk[16:]))
+	private_offset := int(binary.LittleEndian.Uint16(chunk[18:]))
+	base_time := binary.LittleEndian.Uint64(chunk[24:])
+
+	// The public data size includes the end of the preamble.
+	public_end := 16 + public_size
+	if public_end > len(chunk) || public_end < firehosePreambleSize {
+		return true
+	}
+	public := chunk[firehosePreambleSize:public_end]
+
+	// The private data is stored at the end of the chunk.
+	var private []byte
+	if private_offset < FIREHOSE_PRIVATE_END {
+		size := FIREHOSE_PRIVATE_END - private_offset
+		if size <= len(chunk)-public_end {
+			private = chunk[len(chunk)-size:]
+		}
+	}
+
+	process, pres := current.processes[key]
+	if !pres {
+		process = &catalogProcess{}
+	}
+
+	for len(public) >= firehoseEntrySize {
+		activity_type := public[0]
+		if activity_type == 0 {
+			break
+		}
+
+		data_size := int(binary.LittleEndian.Uint16(public[22:]))
+		if firehoseEntrySize+data_size > len(public) {
+			break
+		}
+
+		entry := &firehoseEntry{
+			ActivityType: activity_type,
+			LogType:      public[1],
+			Flags:        binary.LittleEndian.Uint16(public[2:]),
+			FormatOffset: binary.LittleEndian.Uint32(public[4:]),
+			ThreadId:     binary.LittleEndian.Uint64(public[8:]),
+			ContinuousTime: base_time +
+				(uint64(binary.LittleEndian.Uint32(public[16:])) |
+					uint64(binary.LittleEndian.Uint16(public[20:]))<<32),
+		}
+
+		row := self.makeRow(current, process, entry,
+			public[firehoseEntrySize:firehoseEntrySize+data_size], private,
+			private_offset)
+		if !cb(row) {
+			return false
+		}
+
+		next := firehoseEntrySize + int(align8(uint64(data_size)))
+		if next >= len(public) {
+			break
+		}
+		public = public[next:]
+	}
+
+	return true
+}
+
+type firehoseEntry struct {
+	ActivityType   uint8
+	LogType        uint8
+	Flags          uint16
+	FormatOffset   uint32
+	ThreadId       uint64
+	ContinuousTime uint64
+}
+
+func (self *UnifiedLogParser) makeRow(
+	current *catalog, process *catalogProcess, entry *firehoseEntry,
+	data, private []byte, private_offset int) *ordereddict.Dict {
+	activity_type, pres := activityTypes[entry.ActivityType]
+	if !pres {
+		activity_type = fmt.Sprintf("%#x", entry.ActivityType)
+	}
+
+	log_type := ""
+	if entry.ActivityType == FIREHOSE_NONACTIVITY {
+		log_type, pres = logTypes[entry.LogType]
+		if !pres {
+			log_type = fmt.Sprintf("%#x", entry.LogType)
+		}
+	}
+
+	row := ordereddict.NewDict().
+		Set("Time", self.wallTime(entry.ContinuousTime)).
+		Set("ContinuousTime", entry.ContinuousTime).
+		Set("Pid", process.Pid).
+		Set("Euid", process.Euid).
+		Set("ThreadId", entry.ThreadId).
+		Set("ActivityType", activity_type).
+		Set("LogType", log_type)
+
+	subsystem := logSubsystem{}
+	message := ""
+	format := ""
+	image := ""
+	image_uuid := ""
+
+	// Only log messages are decoded.
+	if entry.ActivityType == FIREHOSE_NONACTIVITY {
+		decoded := parseNonActivity(entry, data, private, private_offset)
+		if decoded.HasSubsystem {
+			subsystem = process.subsystems[decoded.Subsystem]
+		}
+
+		switch entry.Flags & FIREHOSE_FORMATTER_MASK {
+		case FIREHOSE_MAIN_EXE:
+			image_uuid = process.MainUUID
+		case FIREHOSE_UUID_RELATIVE:
+			image_uuid = decoded.UUID
+		case FIREHOSE_ABSOLUTE:
+			if int(decoded.UUIDIndex) < len(current.uuids) {
+				image_uuid = current.uuids[decoded.UUIDIndex]
+			}
+		}
+
+		var ok bool
+		if entry.FormatOffset&FIREHOSE_DYNAMIC_FORMAT != 0 {
+			format, ok = "%s", true
+		} else if image_uuid != "" && self.resolver != nil {
+			format, image, ok = self.resolver.FormatString(
+				image_uuid, entry.FormatOffset)
+		}
+
+		if ok {
+			message = formatLogMessage(format, decoded.Args)
+		} else {
+			message = joinLogArgs(decoded.Args)
+		}
+	}
+
+	return row.Set("Subsystem", subsystem.Subsystem).
+		Set("Category", subsystem.Category).
+		Set("ImageUUID", image_uuid).
+		Set("ImagePath", image).
+		Set("FormatString", format).
+		Set("Message", message).
+		Set("BootUUID", self.Header.BootUUID)
+}
+
+type logArg struct {
+	Value     interface{}
+	Size      int
+	Precision bool
+}
+
+type nonActivity struct {
+	HasSubsystem bool
+	Subsystem    uint16
+	UUID         string
+	UUIDIndex    uint16
+	Args         []logArg
+}
+
+func parseNonActivity(entry *firehoseEntry,
+	data, private []byte, private_offset int) *nonActivity {
+	result := &nonActivity{}
+	reader := &byteReader{data: data}
+
+	flags := entry.Flags
+	if flags&FIREHOSE_HAS_CURRENT_AID != 0 {
+		// Activity id and sentinel
+		reader.skip(8)
+	}
+
+	private_strings := []byte{}
+	if flags&FIREHOSE_HAS_PRIVATE_DATA != 0 {
+		offset := int(reader.u16()) - private_offset
+		size := int(reader.u16())
+		if offset >= 0 && offset+size <= len(private) {
+			private_strings = private[offset : offset+size]
+		}
+	}
+
+	switch flags & FIREHOSE_FORMATTER_MASK {
+	case FIREHOSE_ABSOLUTE:
+		result.UUIDIndex = reader.u16()
+	case FIREHOSE_UUID_RELATIVE:
+		result.UUID = strings.ToUpper(fmt.Sprintf("%x", reader.bytes(16)))
+	case FIREHOSE_LARGE_SHARED_CACHE:
+		if flags&FIREHOSE_HAS_LARGE_OFFSET != 0 {
+			reader.skip(2)
+		}
+		reader.skip(2)
+	default:
+		if flags&FIREHOSE_HAS_LARGE_OFFSET != 0 {
+			reader.skip(2)
+		}
+	}
+
+	if flags&FIREHOSE_HAS_SUBSYSTEM != 0 {
+		result.HasSubsystem = true
+		result.Subsystem = reader.u16()
+	}
+
+	if flags&FIREHOSE_HAS_RULES != 0 {
+		reader.skip(1)
+	}
+
+	if flags&FIREHOSE_HAS_DATA_REF != 0 {
+		// The arguments are stored in an oversize chunk.
+		reader.skip(2)
+		return result
+	}
+
+	result.Args = parseLogItems(reader, private_strings)
+	return result
+}
+
+func parseLogItems(reader *byteReader, private []byte) []logArg {
+	// Unknown byte and number of items
+	reader.skip(1)
+	count := int(reader.u8())
+
+	type stringRef struct {
+		index, offset, size int
+		private             bool
+		binary              bool
+	}
+
+	result := []logArg{}
+	refs := []stringRef{}
+	for i := 0; i < count && !reader.eof(); i++ {
+		item_type := reader.u8()
+		item_size := int(reader.u8())
+
+		switch item_type {
+		// Numbers and precision (e.g. %.*s)
+		case 0x0, 0x2, 0x10, 0x12:
+			result = append(result, logArg{
+				Value:     reader.uint(item_size),
+				Size:      item_size,
+				Precision: item_type&0x10 != 0,
+			})
+
+		// Private numbers are not stored.
+		case 0x1:
+			result = append(result, logArg{Value: "<private>"})
+
+		// Strings and objects stored after the items.
+		case 0x20, 0x22, 0x40, 0x42, 0x30, 0x32, 0xf2,
+			0x21, 0x25, 0x31, 0x35, 0x41, 0x45, 0x81, 0xf1:
+			offset := int(reader.u16())
+			size := int(reader.u16())
+			refs = append(refs, stringRef{
+				index:   len(result),
+				offset:  offset,
+				size:    size,
+				private: item_type&0x1 != 0,
+				binary:  item_type&0xf0 == 0x30 || item_type&0xf0 == 0xf0,
+			})
+			result = append(result, logArg{Value: "<private>"})
+
+		default:
+			reader.skip(item_size)
+			result = append(result, logArg{
+				Value: fmt.Sprintf("<unknown item %#x>", item_type)})
+		}
+	}
+
+	// String data follows the items.
+	public := reader.rest()
+	for _, ref := range refs {
+		data := public
+		if ref.private {
+			data = private
+		}
+
+		if ref.size == 0 || ref.offset+ref.size > len(data) {
+			continue
+		}
+
+		value := data[ref.offset : ref.offset+ref.size]
+		if ref.binary {
+			result[ref.index].Value = base64.StdEncoding.EncodeToString(value)
+		} else {
+			result[ref.index].Value = cString(value)
+		}
+	}
+
+	return result
+}
+
+func joinLogArgs(args []logArg) string {
+	result := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg.Precision {
+			continue
+		}
+		result = append(result, fmt.Sprintf("%v", arg.Value))
+	}
+	return strings.Join(result, " ")
+}
+
+func parseTraceV3Header(data []byte) (*TraceV3Header, error) {
+	if len(data) < 144 {
+		return nil, invalidTraceV3Error
+	}
+
+	seconds := binary.LittleEndian.Uint64(data[16:])
+	micros := binary.LittleEndian.Uint32(data[24:])
+
+	return &TraceV3Header{
+		TimebaseNumer:  binary.LittleEndian.Uint32(data),
+		TimebaseDenom:  binary.LittleEndian.Uint32(data[4:]),
+		ContinuousTime: binary.LittleEndian.Uint64(data[8:]),
+		WallTime: time.Unix(int64(seconds),
+			int64(micros)*1000).UTC(),
+		BootUUID: formatUUID(data[128:144]),
+	}, nil
+}
+
+func parseCatalog(data []byte) (*catalog, error) {
+	if len(data) < 24 {
+		return nil, errors.New("Catalog too short")
+	}
+
+	strings_offset := int(binary.LittleEndian.Uint16(data))
+	process_offset := int(binary.LittleEndian.Uint16(data[2:]))
+	process_count := int(binary.LittleEndian.Uint16(data[4:]))
+
+	// Offsets are relative to the UUID array.
+	body := data[24:]
+	if strings_offset > process_offset || process_offset > len(body) {
+		return nil, errors.New("Invalid catalog offsets")
+	}
+
+	result := &catalog{
+		processes: make(map[processKey]*catalogProcess),
+	}
+	for i := 0; i+16 <= strings_offset; i += 16 {
+		result.uuids = append(result.uuids,
+			strings.ToUpper(fmt.Sprintf("%x", body[i:i+16])))
+	}
+
+	subsystem_strings := body[strings_offset:process_offset]
+	getString := func(offset uint16) string {
+		if int(offset) >= len(subsystem_strings) {
+			return ""
+		}
+		return cString(subsystem_strings[offset:])
+	}
+
+	reader := &byteReader{data: body[process_offset:]}
+	for i := 0; i < process_count && !reader.eof(); i++ {
+		// Index and an unknown field
+		reader.skip(4)
+		main_uuid_index := int(reader.u16())
+
+		// DSC UUID index
+		reader.skip(2)
+
+		key := processKey{first: reader.u64(), second: reader.u32()}
+		process := &catalogProcess{
+			Pid:        reader.u32(),
+			Euid:       reader.u32(),
+			subsystems: make(map[uint16]logSubsystem),
+		}
+		if main_uuid_index < len(result.uuids) {
+			process.MainUUID = result.uuids[main_uuid_index]
+		}
+
+		reader.skip(4)
+		uuid_count := int(reader.u32())
+		reader.skip(4)
+
+		// Size, unknown, UUID index and load address.
+		reader.skip(16 * uuid_count)
+
+		subsystem_count := int(reader.u32())
+		reader.skip(4)
+		for j := 0; j < subsystem_count; j++ {
+			id := reader.u16()
+			process.subsystems[id] = logSubsystem{
+				Subsystem: getString(reader.u16()),
+				Category:  getString(reader.u16()),
+			}
+		}
+		reader.skip(int(align8(uint64(6*subsystem_count))) - 6*subsystem_count)
+
+		// The entry was truncated.
+		if reader.past {
+			break
+		}
+		result.processes[key] = process
+	}
+
+	return result, nil
+}
+
+// Chunksets consist of LZ4 compressed (or stored) blocks.
+func decompressChunkset(data []byte) ([]byte, error) {
+	result := []byte{}
+	reader := &byteReader{data: data}
+	for !reader.eof() {
+		switch string(reader.bytes(4)) {
+		case CHUNKSET_END:
+			return result, nil
+
+		case CHUNKSET_LZ4:
+			size := int(reader.u32())
+			compressed := reader.bytes(int(reader.u32()))
+			if compressed == nil || size > MAX_CHUNK_SIZE {
+				return nil, errors.New("Invalid LZ4 block")
+			}
+
+			block, err := utils.LZ4DecompressBlock(compressed, size)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, block...)
+
+		case CHUNKSET_UNCOMPRESSED:
+			block := reader.bytes(int(reader.u32()))
+			if block == nil {
+				return nil, errors.New("Invalid block")
+			}
+			result = append(result, block...)
+
+		default:
+			return nil, errors.New("Invalid chunkset signature")
+		}
+	}
+	return result, nil
+}
+
+// A bounds checked little endian reader. Reading past the end
+// returns zero values and sets eof.
+type byteReader struct {
+	data   []byte
+	offset int
+	past   bool
+}
+
+func (self *byteReader) eof() bool {
+	return self.past || self.offset >= len(self.data)
+}
+
+func (self *byteReader) bytes(size int) []byte {
+	if size < 0 || self.offset+size > len(self.data) {
+		self.past = true
+		self.offset = len(self.data)
+		return nil
+	}
+	result := self.data[self.offset : self.offset+size]
+	self.offset += size
+	return result
+}
+
+func (self *byteReader) skip(size int) {
+	self.bytes(size)
+}
+
+func (self *byteReader) rest() []byte {
+	return self.data[self.offset:]
+}
+
+func (self *byteReader) u8() uint8 {
+	b := self.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (self *byteReader) u16() uint16 {
+	b := self.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+func (self *byteReader) u32() uint32 {
+	b := self.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (self *byteReader) u64() uint64 {
+	b := self.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// Read an unsigned integer of any size up to 8 bytes.
+func (self *byteReader) uint(size int) uint64 {
+	b := self.bytes(size)
+	if len(b) > 8 {
+		b = b[:8]
+	}
+
+	result := uint64(0)
+	for i := len(b) - 1; i >= 0; i-- {
+		result = result<<8 | uint64(b[i])
+	}
+	return result
+}
+
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+func align8(size uint64) uint64 {
+	return (size + 7) &^ 7
+}
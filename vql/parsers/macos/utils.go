@@ -0,0 +1,22 @@
+package macos
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Plist dictionaries are decoded into maps so we sort the keys to
+// get a stable output.
+func sortedKeys(in map[string]interface{}) []string {
+	result := make([]string, 0, len(in))
+	for k := range in {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%X-%X-%X-%X-%X", b[0:4], b[4:6], b[6:8],
+		b[8:10], b[10:16])
+}
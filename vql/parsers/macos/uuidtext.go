@@ -0,0 +1,317 @@
+package macos
+
+// The uuidtext files contain the format strings of an image (an
+// executable or library), keyed by the image's UUID. They are stored
+// in /private/var/db/uuidtext/XX/YYYY... where XX are the first two
+// hex digits of the UUID.
+
+// The file starts with a header followed by a list of ranges. Each
+// range maps a range of format string offsets (as referenced by log
+// entries) to a block of strings following the range list. The
+// image path is stored after the last block.
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	UUIDTEXT_MAGIC = 0x66778899
+
+	uuidTextHeaderSize = 16
+	uuidTextRangeSize  = 8
+
+	// uuidtext files larger than this are considered corrupt.
+	MAX_UUIDTEXT_SIZE = 64 * 1024 * 1024
+)
+
+type uuidTextRange struct {
+	start, size uint32
+
+	// Offset of the range's strings in the file.
+	data_offset int
+}
+
+type UUIDText struct {
+	data   []byte
+	ranges []uuidTextRange
+
+	ImagePath string
+}
+
+func ParseUUIDText(data []byte) (*UUIDText, error) {
+	reader := &byteReader{data: data}
+	if reader.u32() != UUIDTEXT_MAGIC {
+		return nil, errors.New("Not a uuidtext file")
+	}
+
+	// Major and minor versions
+	reader.skip(8)
+	count := int(reader.u32())
+	if count*uuidTextRangeSize > len(data) {
+		return nil, errors.New("Invalid uuidtext range count")
+	}
+
+	result := &UUIDText{data: data}
+	data_offset := uuidTextHeaderSize + count*uuidTextRangeSize
+	for i := 0; i < count; i++ {
+		item := uuidTextRange{
+			start:       reader.u32(),
+			size:        reader.u32(),
+			data_offset: data_offset,
+		}
+		result.ranges = append(result.ranges, item)
+		data_offset += int(item.size)
+	}
+
+	if data_offset < len(data) {
+		result.ImagePath = cString(data[data_offset:])
+	}
+
+	return result, nil
+}
+
+func (self *UUIDText) FormatString(offset uint32) (string, bool) {
+	for _, item := range self.ranges {
+		if offset < item.start || offset-item.start >= item.size {
+			continue
+		}
+
+		start := item.data_offset + int(offset-item.start)
+		if start >= len(self.data) {
+			return "", false
+		}
+		return cString(self.data[start:]), true
+	}
+	return "", false
+}
+
+// Resolves format strings from a uuidtext directory. Parsed files
+// are cached.
+type UUIDTextResolver struct {
+	mu    sync.Mutex
+	cache map[string]*UUIDText
+
+	// Opens the uuidtext file for the UUID.
+	open func(dirname, filename string) (io.ReadCloser, error)
+}
+
+func NewUUIDTextResolver(
+	open func(dirname, filename string) (io.ReadCloser, error)) *UUIDTextResolver {
+	return &UUIDTextResolver{
+		cache: make(map[string]*UUIDText),
+		open:  open,
+	}
+}
+
+func (self *UUIDTextResolver) FormatString(
+	uuid string, offset uint32) (string, string, bool) {
+	uuid_text := self.get(uuid)
+	if uuid_text == nil {
+		return "", "", false
+	}
+
+	format, ok := uuid_text.FormatString(offset)
+	return format, uuid_text.ImagePath, ok
+}
+
+func (self *UUIDTextResolver) get(uuid string) *UUIDText {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result, pres := self.cache[uuid]
+	if pres {
+		return result
+	}
+
+	// Missing files are cached as nil.
+	self.cache[uuid] = nil
+	if len(uuid) != 32 {
+		return nil
+	}
+
+	fd, err := self.open(uuid[:2], uuid[2:])
+	if err != nil {
+		return nil
+	}
+	defer fd.Close()
+
+	data, err := io.ReadAll(io.LimitReader(fd, MAX_UUIDTEXT_SIZE))
+	if err != nil {
+		return nil
+	}
+
+	result, err = ParseUUIDText(data)
+	if err != nil {
+		return nil
+	}
+
+	self.cache[uuid] = result
+	return result
+}
+
+// Expand an os_log format string. Besides the printf conversions
+// os_log supports annotations like %{public}s and %@ for objects
+// which are decoded to strings by the parser.
+func formatLogMessage(format string, args []logArg) string {
+	result := &strings.Builder{}
+
+	nextArg := func() (logArg, bool) {
+		if len(args) == 0 {
+			return logArg{}, false
+		}
+		arg := args[0]
+		args = args[1:]
+		return arg, true
+	}
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			result.WriteByte(c)
+			continue
+		}
+
+		spec, end := parseFormatSpec(format, i+1)
+		if end < 0 {
+			result.WriteString(format[i:])
+			break
+		}
+		i = end
+
+		if spec.conversion == '%' {
+			result.WriteByte('%')
+			continue
+		}
+
+		// Precision and width given as arguments are stored as
+		// separate items.
+		if spec.star_width {
+			arg, _ := nextArg()
+			spec.width = fmt.Sprintf("%v", arg.Value)
+		}
+		if spec.star_precision {
+			arg, _ := nextArg()
+			spec.precision = fmt.Sprintf(".%v", arg.Value)
+		}
+
+		arg, ok := nextArg()
+		if !ok {
+			result.WriteString("<decode: missing data>")
+			continue
+		}
+		result.WriteString(spec.format(arg))
+	}
+
+	return result.String()
+}
+
+type formatSpec struct {
+	flags, width, precision string
+	star_width              bool
+	star_precision          bool
+	conversion              byte
+}
+
+// Parse a conversion specification starting after the % and return
+// the offset of the conversion character.
+func parseFormatSpec(format string, i int) (*formatSpec, int) {
+	result := &formatSpec{}
+
+	// Annotations like {public} or {private, mask.hash}
+	if i < len(format) && format[i] == '{' {
+		end := strings.IndexByte(format[i:], '}')
+		if end < 0 {
+			return nil, -1
+		}
+		i += end + 1
+	}
+
+	start := i
+	for i < len(format) && strings.IndexByte("-+ #0'", format[i]) >= 0 {
+		i++
+	}
+	result.flags = strings.ReplaceAll(format[start:i], "'", "")
+
+	if i < len(format) && format[i] == '*' {
+		result.star_width = true
+		i++
+	} else {
+		start = i
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		result.width = format[start:i]
+	}
+
+	if i < len(format) && format[i] == '.' {
+		i++
+		if i < len(format) && format[i] == '*' {
+			result.star_precision = true
+			i++
+		} else {
+			start = i
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+			result.precision = "." + format[start:i]
+		}
+	}
+
+	// Length modifiers do not matter as the items carry their size.
+	for i < len(format) && strings.IndexByte("hlqjztL", format[i]) >= 0 {
+		i++
+	}
+
+	if i >= len(format) {
+		return nil, -1
+	}
+	result.conversion = format[i]
+	return result, i
+}
+
+func (self *formatSpec) format(arg logArg) string {
+	number, is_number := arg.Value.(uint64)
+	if !is_number {
+		return fmt.Sprintf("%"+self.flags+self.width+self.precision+"v",
+			arg.Value)
+	}
+
+	prefix := "%" + self.flags + self.width + self.precision
+	switch self.conversion {
+	case 'd', 'i':
+		return fmt.Sprintf(prefix+"d", signExtend(number, arg.Size))
+	case 'u':
+		return fmt.Sprintf(prefix+"d", number)
+	case 'x', 'X', 'o':
+		return fmt.Sprintf(prefix+string(self.conversion), number)
+	case 'p':
+		return "0x" + strconv.FormatUint(number, 16)
+	case 'c':
+		return string(rune(number))
+	case 'f', 'F', 'e', 'E', 'g', 'G':
+		value := float64(math.Float32frombits(uint32(number)))
+		if arg.Size == 8 {
+			value = math.Float64frombits(number)
+		}
+		return fmt.Sprintf(prefix+string(self.conversion), value)
+	}
+	return fmt.Sprintf("%v", number)
+}
+
+func signExtend(value uint64, size int) int64 {
+	switch size {
+	case 1:
+		return int64(int8(value))
+	case 2:
+		return int64(int16(value))
+	case 4:
+		return int64(int32(value))
+	}
+	return int64(value)
+}
@@ -0,0 +1,171 @@
+package parsers
+
+// Triage process minidumps. Each minidump holds a single process and
+// the modules it loaded. Raw physical memory images (e.g. /dev/mem)
+// require kernel symbols to walk the process list so they are not
+// supported here - they can still be scanned through the memory
+// accessor.
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/accessors/memory"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type MinidumpPluginArgs struct {
+	Filenames []*accessors.OSPath `vfilter:"required,field=filename,doc=A list of minidumps to parse."`
+	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to use."`
+}
+
+// Open each minidump and pass it to the callback.
+func parseMinidumps(
+	ctx context.Context, scope vfilter.Scope, args *ordereddict.Dict,
+	name string, cb func(filename *accessors.OSPath, dump *memory.Minidump)) {
+	arg := &MinidumpPluginArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("%v: %v", name, err)
+		return
+	}
+
+	err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+	if err != nil {
+		scope.Log("%v: %v", name, err)
+		return
+	}
+
+	accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+	if err != nil {
+		scope.Log("%v: %v", name, err)
+		return
+	}
+
+	for _, filename := range arg.Filenames {
+		func() {
+			defer utils.RecoverVQL(scope)
+
+			fd, err := accessor.OpenWithOSPath(filename)
+			if err != nil {
+				scope.Log("%v: Unable to open file %s: %v",
+					name, filename, err)
+				return
+			}
+			defer fd.Close()
+
+			dump, err := memory.ParseMinidump(utils.MakeReaderAtter(fd))
+			if errors.Is(err, memory.NotMinidumpError) {
+				scope.Log("%v: %s is not a minidump. Raw memory images "+
+					"can only be scanned with the memory accessor.",
+					name, filename)
+				return
+			}
+			if err != nil {
+				scope.Log("%v: %s: %v", name, filename, err)
+				return
+			}
+
+			cb(filename, dump)
+		}()
+	}
+}
+
+type MinidumpProcessesPlugin struct{}
+
+func (self MinidumpProcessesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		parseMinidumps(ctx, scope, args, "minidump_processes",
+			func(filename *accessors.OSPath, dump *memory.Minidump) {
+				image := dump.ImagePath()
+				name := ""
+				if image != "" {
+					name = accessors.MustNewWindowsOSPath(image).Basename()
+				}
+
+				select {
+				case <-ctx.Done():
+				case output_chan <- ordereddict.NewDict().
+					Set("Pid", dump.ProcessId).
+					Set("Name", name).
+					Set("ImagePath", image).
+					Set("CreateTime", dump.ProcessCreateTime).
+					Set("Architecture", dump.Architecture).
+					Set("OSVersion", dump.OSVersion).
+					Set("Threads", dump.Threads).
+					Set("ModuleCount", len(dump.Modules)).
+					Set("_Source", filename):
+				}
+			})
+	}()
+
+	return output_chan
+}
+
+func (self MinidumpProcessesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "minidump_processes",
+		Doc:     "List the processes stored in minidumps.",
+		ArgType: type_map.AddType(scope, &MinidumpPluginArgs{}),
+	}
+}
+
+type MinidumpModulesPlugin struct{}
+
+func (self MinidumpModulesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		parseMinidumps(ctx, scope, args, "minidump_modules",
+			func(filename *accessors.OSPath, dump *memory.Minidump) {
+				for _, module := range dump.Modules {
+					select {
+					case <-ctx.Done():
+						return
+					case output_chan <- ordereddict.NewDict().
+						Set("Pid", dump.ProcessId).
+						Set("ModuleName", module.Name).
+						Set("BaseAddress", module.BaseAddress).
+						Set("Size", module.Size).
+						Set("Checksum", module.Checksum).
+						Set("TimeDateStamp", module.TimeDateStamp).
+						Set("_Source", filename):
+					}
+				}
+			})
+	}()
+
+	return output_chan
+}
+
+func (self MinidumpModulesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "minidump_modules",
+		Doc:     "List the modules loaded by the processes stored in minidumps.",
+		ArgType: type_map.AddType(scope, &MinidumpPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&MinidumpProcessesPlugin{})
+	vql_subsystem.RegisterPlugin(&MinidumpModulesPlugin{})
+}
@@ -9,6 +9,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/json"
 	utils "www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/parsers/macos"
 	vfilter "www.velocidex.com/golang/vfilter"
 	"www.velocidex.com/golang/vfilter/arg_parser"
 )
@@ -29,8 +30,9 @@ import (
 */
 
 type _PlistFunctionArgs struct {
-	Filename *accessors.OSPath `vfilter:"required,field=file,doc=A list of files to parse."`
-	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	Filename  *accessors.OSPath `vfilter:"required,field=file,doc=A list of files to parse."`
+	Accessor  string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	Unarchive bool              `vfilter:"optional,field=unarchive,doc=Resolve NSKeyedArchiver archives and nested binary plists."`
 }
 
 type PlistFunction struct{}
@@ -80,6 +82,10 @@ func (self *PlistFunction) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
+	if arg.Unarchive {
+		val = macos.Unarchive(val)
+	}
+
 	// Force the results into dicts
 	serialized, err := json.Marshal(val)
 	if err != nil {
@@ -105,6 +111,7 @@ func (self *PlistFunction) Call(ctx context.Context,
 type _PlistPluginArgs struct {
 	Filenames []*accessors.OSPath `vfilter:"required,field=file,doc=A list of files to parse."`
 	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	Unarchive bool                `vfilter:"optional,field=unarchive,doc=Resolve NSKeyedArchiver archives and nested binary plists."`
 }
 
 type _PlistPlugin struct{}
@@ -166,6 +173,10 @@ func (self _PlistPlugin) Call(
 						filename, err)
 				}
 
+				if arg.Unarchive {
+					val = macos.Unarchive(val)
+				}
+
 				select {
 				case <-ctx.Done():
 					return
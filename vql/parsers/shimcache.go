@@ -0,0 +1,206 @@
+package parsers
+
+// Parse the shimcache (AppCompatCache) directly from a raw SYSTEM
+// hive. The hive is opened through the raw_reg accessor so it may be
+// read from a raw NTFS volume, an image or a collection.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/regparser/appcompatcache"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	registryValueNotFound = errors.New("Value not found")
+
+	// Windows XP stores the cache in a different key.
+	shimcacheKeys = [][]string{
+		{"Control", "Session Manager", "AppCompatCache", "AppCompatCache"},
+		{"Control", "Session Manager", "AppCompatibility", "AppCompatCache"},
+	}
+)
+
+// A raw hive file opened with the raw_reg accessor.
+type rawHive struct {
+	accessor accessors.FileSystemAccessor
+	root     *accessors.OSPath
+}
+
+func openRawHive(scope vfilter.Scope,
+	filename *accessors.OSPath, accessor string) (*rawHive, error) {
+	err := vql_subsystem.CheckFilesystemAccess(scope, accessor)
+	if err != nil {
+		return nil, err
+	}
+
+	reg_accessor, err := accessors.GetAccessor("raw_reg", scope)
+	if err != nil {
+		return nil, err
+	}
+
+	pathspec := &accessors.PathSpec{
+		DelegateAccessor: accessor,
+		DelegatePath:     filename.String(),
+	}
+
+	root, err := reg_accessor.ParsePath(pathspec.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &rawHive{accessor: reg_accessor, root: root}, nil
+}
+
+func (self *rawHive) ReadDir(components ...string) ([]accessors.FileInfo, error) {
+	return self.accessor.ReadDirWithOSPath(self.root.Append(components...))
+}
+
+// The values of a key keyed by value name.
+func (self *rawHive) Values(
+	components ...string) (*ordereddict.Dict, error) {
+	children, err := self.ReadDir(components...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ordereddict.NewDict()
+	for _, child := range children {
+		if child.IsDir() {
+			continue
+		}
+
+		value, _ := child.Data().Get("value")
+		result.Set(child.Name(), value)
+	}
+	return result, nil
+}
+
+func (self *rawHive) ReadValue(components ...string) ([]byte, error) {
+	fd, err := self.accessor.OpenWithOSPath(self.root.Append(components...))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	return ioutil.ReadAll(fd)
+}
+
+type ShimcachePluginArgs struct {
+	Filename *accessors.OSPath `vfilter:"required,field=filename,doc=The SYSTEM hive to parse."`
+	Accessor string            `vfilter:"optional,field=accessor,doc=The accessor to use."`
+}
+
+type ShimcachePlugin struct{}
+
+func (self ShimcachePlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+		defer utils.RecoverVQL(scope)
+
+		arg := &ShimcachePluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("parse_shimcache: %v", err)
+			return
+		}
+
+		hive, err := openRawHive(scope, arg.Filename, arg.Accessor)
+		if err != nil {
+			scope.Log("parse_shimcache: %v", err)
+			return
+		}
+
+		current_control_set := ""
+		selected, err := hive.Values("Select")
+		if err == nil {
+			current, _ := selected.Get("Current")
+			current_int, ok := current.(uint64)
+			if ok {
+				current_control_set = fmtControlSet(current_int)
+			}
+		}
+
+		keys, err := hive.ReadDir()
+		if err != nil {
+			scope.Log("parse_shimcache: %v", err)
+			return
+		}
+
+		for _, key := range keys {
+			control_set := key.Name()
+			if !key.IsDir() || !strings.HasPrefix(control_set, "ControlSet") {
+				continue
+			}
+
+			data, err := readShimcache(hive, control_set)
+			if err != nil {
+				scope.Log("parse_shimcache: %v: %v", control_set, err)
+				continue
+			}
+
+			// Entries are ordered most recent first.
+			for idx, entry := range appcompatcache.ParseValueData(data) {
+				row := ordereddict.NewDict().
+					Set("ControlSet", control_set).
+					Set("Current", control_set == current_control_set).
+					Set("Position", idx)
+
+				entry_dict := vfilter.RowToDict(ctx, scope, entry)
+				for _, k := range entry_dict.Keys() {
+					v, _ := entry_dict.Get(k)
+					row.Set(k, v)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- row:
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func fmtControlSet(number uint64) string {
+	return fmt.Sprintf("ControlSet%03d", number)
+}
+
+func readShimcache(hive *rawHive, control_set string) ([]byte, error) {
+	for _, components := range shimcacheKeys {
+		data, err := hive.ReadValue(append(
+			[]string{control_set}, components...)...)
+		if err == nil && len(data) > 0 {
+			return data, nil
+		}
+	}
+	return nil, registryValueNotFound
+}
+
+func (self ShimcachePlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "parse_shimcache",
+		Doc:     "Parses the shimcache (AppCompatCache) from a raw SYSTEM hive.",
+		ArgType: type_map.AddType(scope, &ShimcachePluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ShimcachePlugin{})
+}
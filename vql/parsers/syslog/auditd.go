@@ -1,7 +1,9 @@
 // Parse auditd log files.
 
 // Auditd writes multiple lines for the same event. We therefore need
-// to group the lines and emit a single event row.
+// to group the lines by their serial number and emit a single event
+// row. Log files are grouped by the auditReassembler while watched
+// logs use libaudit's time based reassembler.
 
 package syslog
 
@@ -35,30 +37,19 @@ func (self AuditdPlugin) Call(
 	go func() {
 		defer close(output_chan)
 
-		reassembler, err := libaudit.NewReassembler(5, 2*time.Second,
-			&streamHandler{scope: scope, ctx: ctx, output_chan: output_chan})
-		if err != nil {
-			scope.Log("parse_auditd: %v", err)
-			return
-		}
-		defer reassembler.Close()
+		reassembler := newAuditReassembler(
+			func(msgs []*auparse.AuditMessage) {
+				event, err := aucoalesce.CoalesceMessages(msgs)
+				if err != nil {
+					scope.Log("parse_auditd: %v", err)
+					return
+				}
 
-		// Start goroutine to periodically purge timed-out events.
-		go func() {
-			t := time.NewTicker(500 * time.Millisecond)
-			defer t.Stop()
-			for {
 				select {
 				case <-ctx.Done():
-					return
-
-				case <-t.C:
-					if reassembler.Maintain() != nil {
-						return
-					}
+				case output_chan <- event:
 				}
-			}
-		}()
+			})
 
 		scanner := ScannerPlugin{}
 		for row := range scanner.Call(ctx, scope, args) {
@@ -67,11 +58,13 @@ func (self AuditdPlugin) Call(
 				continue
 			}
 
-			auditMsg, err := auparse.ParseLogLine(line.(string))
+			auditMsg, err := parseAuditLine(line.(string))
 			if err == nil {
-				reassembler.PushMessage(auditMsg)
+				reassembler.Push(auditMsg)
 			}
 		}
+
+		reassembler.Flush()
 	}()
 
 	return output_chan
@@ -155,7 +148,7 @@ func (self WatchAuditdPlugin) Call(
 				continue
 			}
 
-			auditMsg, err := auparse.ParseLogLine(line.(string))
+			auditMsg, err := parseAuditLine(line.(string))
 			if err == nil {
 				reassembler.PushMessage(auditMsg)
 			}
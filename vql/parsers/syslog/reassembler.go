@@ -0,0 +1,139 @@
+package syslog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/elastic/go-libaudit/auparse"
+)
+
+const (
+	// Incomplete events are emitted once the log has moved on this
+	// far past them.
+	AUDIT_EVENT_TIMEOUT = 2 * time.Second
+
+	// Never hold more than this many incomplete events.
+	AUDIT_MAX_IN_FLIGHT = 1000
+)
+
+// Records belong to the same event when they share the timestamp and
+// serial number of the msg=audit(timestamp:serial) field. The serial
+// alone is reset on reboot.
+type auditEventKey struct {
+	timestamp int64
+	serial    uint32
+}
+
+// Groups auditd log records into events by their serial number.
+
+// libaudit's Reassembler expires incomplete events on a wall clock
+// timer which suits a live stream but not a log file: reading a large
+// file is much faster than it was written and records of concurrent
+// events are interleaved. Here events are completed by their own
+// records (EOE) and expired by the log's timestamps, so parsing a
+// file always produces the same events however fast it is read.
+type auditReassembler struct {
+	events map[auditEventKey][]*auparse.AuditMessage
+
+	// Incomplete events in the order their first record was seen.
+	order []auditEventKey
+
+	emit func(msgs []*auparse.AuditMessage)
+}
+
+func newAuditReassembler(
+	emit func(msgs []*auparse.AuditMessage)) *auditReassembler {
+	return &auditReassembler{
+		events: make(map[auditEventKey][]*auparse.AuditMessage),
+		emit:   emit,
+	}
+}
+
+func (self *auditReassembler) Push(msg *auparse.AuditMessage) {
+	key := auditEventKey{
+		timestamp: msg.Timestamp.UnixNano(),
+		serial:    msg.Sequence,
+	}
+
+	msgs, pres := self.events[key]
+	if !pres {
+		self.order = append(self.order, key)
+	}
+	self.events[key] = append(msgs, msg)
+
+	if isLastAuditRecord(msg) {
+		self.complete(key)
+	}
+
+	self.expire(msg.Timestamp)
+}
+
+// Emit all the remaining events at the end of the log.
+func (self *auditReassembler) Flush() {
+	for len(self.order) > 0 {
+		self.complete(self.order[0])
+	}
+}
+
+func (self *auditReassembler) expire(now time.Time) {
+	for len(self.order) > 0 {
+		key := self.order[0]
+		if len(self.order) <= AUDIT_MAX_IN_FLIGHT &&
+			now.Sub(time.Unix(0, key.timestamp)) < AUDIT_EVENT_TIMEOUT {
+			return
+		}
+		self.complete(key)
+	}
+}
+
+func (self *auditReassembler) complete(key auditEventKey) {
+	msgs := self.events[key]
+	delete(self.events, key)
+
+	for idx, item := range self.order {
+		if item == key {
+			self.order = append(self.order[:idx], self.order[idx+1:]...)
+			break
+		}
+	}
+
+	// The EOE record only marks the end of the event.
+	result := make([]*auparse.AuditMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.RecordType != auparse.AUDIT_EOE {
+			result = append(result, msg)
+		}
+	}
+
+	if len(result) > 0 {
+		self.emit(result)
+	}
+}
+
+// Kernel events end with an EOE record but messages from user space
+// programs (e.g. USER_LOGIN) are always a single record.
+func isLastAuditRecord(msg *auparse.AuditMessage) bool {
+	switch {
+	case msg.RecordType == auparse.AUDIT_EOE:
+		return true
+
+	case msg.RecordType >= auparse.AUDIT_FIRST_USER_MSG &&
+		msg.RecordType <= auparse.AUDIT_LAST_USER_MSG:
+		return true
+
+	case msg.RecordType >= auparse.AUDIT_FIRST_USER_MSG2 &&
+		msg.RecordType <= auparse.AUDIT_LAST_USER_MSG2:
+		return true
+	}
+	return false
+}
+
+// Logs written with log_format = ENRICHED append the resolved names
+// after a group separator. These do not parse as audit fields.
+func parseAuditLine(line string) (*auparse.AuditMessage, error) {
+	idx := strings.IndexByte(line, '\x1d')
+	if idx >= 0 {
+		line = line[:idx]
+	}
+	return auparse.ParseLogLine(line)
+}
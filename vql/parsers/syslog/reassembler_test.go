@@ -0,0 +1,182 @@
+package syslog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-libaudit/auparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditTestTime = time.Unix(1364481363, 243000000)
+
+func auditRecord(record_type auparse.AuditMessageType,
+	offset time.Duration, serial uint32) *auparse.AuditMessage {
+	return &auparse.AuditMessage{
+		RecordType: record_type,
+		Timestamp:  auditTestTime.Add(offset),
+		Sequence:   serial,
+	}
+}
+
+// Collects the emitted events as lists of TYPE:serial
+type auditEvents struct {
+	events [][]string
+}
+
+func (self *auditEvents) emit(msgs []*auparse.AuditMessage) {
+	event := []string{}
+	for _, msg := range msgs {
+		event = append(event, fmt.Sprintf("%v:%v", msg.RecordType, msg.Sequence))
+	}
+	self.events = append(self.events, event)
+}
+
+func TestAuditReassemblerInterleaved(t *testing.T) {
+	result := &auditEvents{}
+	reassembler := newAuditReassembler(result.emit)
+
+	for _, msg := range []*auparse.AuditMessage{
+		auditRecord(auparse.AUDIT_SYSCALL, 0, 100),
+		auditRecord(auparse.AUDIT_SYSCALL, 0, 101),
+		auditRecord(auparse.AUDIT_EXECVE, 0, 100),
+		auditRecord(auparse.AUDIT_PATH, 0, 101),
+		auditRecord(auparse.AUDIT_PATH, 0, 100),
+	} {
+		reassembler.Push(msg)
+	}
+
+	// Nothing is complete yet.
+	assert.Equal(t, 0, len(result.events))
+
+	// Events are emitted as soon as their EOE record arrives,
+	// without the EOE record.
+	reassembler.Push(auditRecord(auparse.AUDIT_EOE, 0, 101))
+	assert.Equal(t, [][]string{
+		{"SYSCALL:101", "PATH:101"},
+	}, result.events)
+
+	reassembler.Push(auditRecord(auparse.AUDIT_EOE, 0, 100))
+	assert.Equal(t, [][]string{
+		{"SYSCALL:101", "PATH:101"},
+		{"SYSCALL:100", "EXECVE:100", "PATH:100"},
+	}, result.events)
+
+	reassembler.Flush()
+	assert.Equal(t, 2, len(result.events))
+}
+
+// The serial is reset on reboot so records with the same serial but
+// a different timestamp are separate events.
+func TestAuditReassemblerSerialReuse(t *testing.T) {
+	result := &auditEvents{}
+	reassembler := newAuditReassembler(result.emit)
+
+	reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL, 0, 5))
+	reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL, time.Second, 5))
+	reassembler.Push(auditRecord(auparse.AUDIT_EOE, time.Second, 5))
+	reassembler.Flush()
+
+	assert.Equal(t, [][]string{
+		{"SYSCALL:5"},
+		{"SYSCALL:5"},
+	}, result.events)
+}
+
+// Messages from user space are a single record without an EOE.
+func TestAuditReassemblerUserMessages(t *testing.T) {
+	result := &auditEvents{}
+	reassembler := newAuditReassembler(result.emit)
+
+	reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL, 0, 1))
+	reassembler.Push(auditRecord(auparse.AUDIT_USER_LOGIN, 0, 2))
+	reassembler.Push(auditRecord(auparse.AUDIT_USER_AUTH, 0, 3))
+
+	assert.Equal(t, [][]string{
+		{"USER_LOGIN:2"},
+		{"USER_AUTH:3"},
+	}, result.events)
+
+	// The incomplete kernel event is emitted at the end of the log.
+	reassembler.Flush()
+	assert.Equal(t, []string{"SYSCALL:1"}, result.events[2])
+}
+
+// Incomplete events expire based on the log's timestamps, not the
+// wall clock.
+func TestAuditReassemblerTimeout(t *testing.T) {
+	result := &auditEvents{}
+	reassembler := newAuditReassembler(result.emit)
+
+	reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL, 0, 1))
+	reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL, time.Second, 2))
+	assert.Equal(t, 0, len(result.events))
+
+	// Records keep being added to an event until it expires.
+	reassembler.Push(auditRecord(auparse.AUDIT_PATH, 0, 1))
+	assert.Equal(t, 0, len(result.events))
+
+	reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL,
+		AUDIT_EVENT_TIMEOUT, 3))
+	assert.Equal(t, [][]string{
+		{"SYSCALL:1", "PATH:1"},
+	}, result.events)
+
+	reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL,
+		AUDIT_EVENT_TIMEOUT+time.Second, 4))
+	assert.Equal(t, [][]string{
+		{"SYSCALL:1", "PATH:1"},
+		{"SYSCALL:2"},
+	}, result.events)
+
+	// The remaining events are emitted in order.
+	reassembler.Flush()
+	assert.Equal(t, [][]string{
+		{"SYSCALL:1", "PATH:1"},
+		{"SYSCALL:2"},
+		{"SYSCALL:3"},
+		{"SYSCALL:4"},
+	}, result.events)
+}
+
+func TestAuditReassemblerMaxInFlight(t *testing.T) {
+	result := &auditEvents{}
+	reassembler := newAuditReassembler(result.emit)
+
+	for i := 0; i < AUDIT_MAX_IN_FLIGHT; i++ {
+		reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL, 0, uint32(i)))
+	}
+	assert.Equal(t, 0, len(result.events))
+
+	// The oldest event is emitted to make room.
+	reassembler.Push(auditRecord(auparse.AUDIT_SYSCALL, 0,
+		AUDIT_MAX_IN_FLIGHT))
+	assert.Equal(t, [][]string{{"SYSCALL:0"}}, result.events)
+}
+
+func TestParseEnrichedAuditLine(t *testing.T) {
+	line := `type=USER_LOGIN msg=audit(1364481363.243:24287): ` +
+		`pid=1234 uid=0 auid=1000 ses=2 msg='op=login id=1000 ` +
+		`exe="/usr/sbin/sshd" hostname=? addr=10.0.0.1 terminal=sshd ` +
+		`res=success'` + "\x1d" + `UID="root" AUID="user"`
+
+	msg, err := parseAuditLine(line)
+	require.NoError(t, err)
+
+	assert.Equal(t, auparse.AUDIT_USER_LOGIN, msg.RecordType)
+	assert.Equal(t, uint32(24287), msg.Sequence)
+
+	// The enriched names are removed.
+	assert.False(t, strings.Contains(msg.RawData, "\x1d"))
+	assert.False(t, strings.Contains(msg.RawData, `UID="root"`))
+	assert.True(t, strings.HasSuffix(msg.RawData, "res=success'"))
+
+	// Plain lines still parse.
+	msg, err = parseAuditLine(
+		`type=EOE msg=audit(1364481363.243:24287): `)
+	require.NoError(t, err)
+	assert.Equal(t, auparse.AUDIT_EOE, msg.RecordType)
+}
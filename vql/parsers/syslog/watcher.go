@@ -3,6 +3,7 @@ package syslog
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"sync"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/parsers/watchers"
 	"www.velocidex.com/golang/vfilter"
 )
 
@@ -94,16 +96,15 @@ func (self *SyslogWatcherService) StartMonitoring(
 
 	defer utils.CheckForPanic("StartMonitoring")
 
+	health := watchers.GetWatcherHealth(
+		"watch_syslog", filename.String(), accessor_name)
+	defer watchers.RemoveWatcherHealth(
+		"watch_syslog", filename.String(), accessor_name)
+
 	scope := vql_subsystem.MakeScope()
 	defer scope.Close()
 
-	accessor, err := accessors.GetAccessor(accessor_name, scope)
-	if err != nil {
-		//scope.Log("Registering watcher error: %v", err)
-		return
-	}
-
-	cursor := self.findLastLineOffset(filename, accessor)
+	var cursor *Cursor
 	key := filename.String() + accessor_name
 	for {
 		self.mu.Lock()
@@ -114,13 +115,50 @@ func (self *SyslogWatcherService) StartMonitoring(
 		if !pres || len(registration) == 0 {
 			return
 		}
+		health.SetListeners(len(registration))
 
-		cursor = self.monitorOnce(filename, accessor_name, accessor, cursor)
+		cursor = self.monitorWithRecovery(
+			scope, filename, accessor_name, cursor, health)
 
 		time.Sleep(FREQUENCY)
 	}
 }
 
+// Poll the file once. Errors are recorded in the watcher's health
+// and the watch is resubscribed on the next poll instead of the
+// watcher silently exiting.
+func (self *SyslogWatcherService) monitorWithRecovery(
+	scope vfilter.Scope,
+	filename *accessors.OSPath,
+	accessor_name string,
+	cursor *Cursor,
+	health *watchers.WatcherHealth) (result *Cursor) {
+
+	result = cursor
+	defer func() {
+		r := recover()
+		if r != nil {
+			health.SetError(fmt.Errorf("Panic: %v", r))
+			health.Restarted()
+
+			// Resume from the end of the file on the next poll.
+			result = nil
+		}
+	}()
+
+	accessor, err := accessors.GetAccessor(accessor_name, scope)
+	if err != nil {
+		health.SetError(err)
+		return cursor
+	}
+
+	if cursor == nil {
+		cursor = self.findLastLineOffset(filename, accessor)
+	}
+
+	return self.monitorOnce(filename, accessor_name, accessor, cursor, health)
+}
+
 func (self *SyslogWatcherService) findLastLineOffset(
 	filename *accessors.OSPath,
 	accessor accessors.FileSystemAccessor) *Cursor {
@@ -129,8 +167,10 @@ func (self *SyslogWatcherService) findLastLineOffset(
 
 	stat, err := accessor.LstatWithOSPath(filename)
 	if err != nil {
+		cursor.missing = true
 		return cursor
 	}
+	cursor.btime = stat.Btime()
 
 	fd, err := accessor.OpenWithOSPath(filename)
 	if err != nil {
@@ -170,18 +210,36 @@ func (self *SyslogWatcherService) monitorOnce(
 	filename *accessors.OSPath,
 	accessor_name string,
 	accessor accessors.FileSystemAccessor,
-	cursor *Cursor) *Cursor {
+	cursor *Cursor,
+	health *watchers.WatcherHealth) *Cursor {
 
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
 	stat, err := accessor.LstatWithOSPath(filename)
 	if err != nil {
+		// The file was renamed away or its volume unmounted - keep
+		// polling until it comes back.
+		cursor.missing = true
+		health.SetState(watchers.STATE_MISSING)
 		return cursor
 	}
 
+	// The file reappeared or was replaced by a new file (e.g. log
+	// rotation): start reading the new file from the beginning.
+	if cursor.missing || isNewFile(cursor, stat) {
+		if cursor.file_size > 0 || cursor.missing {
+			health.Restarted()
+		}
+		cursor.missing = false
+		cursor.file_size = 0
+		cursor.last_line_offset = 0
+		cursor.btime = stat.Btime()
+	}
+
 	// Nothing to do - file size is not changed since last time.
 	if stat.Size() == cursor.file_size {
+		health.SetState(watchers.STATE_OK)
 		return cursor
 	}
 
@@ -192,6 +250,7 @@ func (self *SyslogWatcherService) monitorOnce(
 		logger.Info("File size (%v) is smaller than last know size (%v) - assuming file was truncated. Will start reading at the start again.",
 			stat.Size(), cursor.file_size)
 		cursor.last_line_offset = 0
+		health.Restarted()
 	}
 
 	cursor.file_size = stat.Size()
@@ -202,8 +261,11 @@ func (self *SyslogWatcherService) monitorOnce(
 		return cursor
 	}
 
+	// Opening may fail transiently (e.g. we ran out of file
+	// descriptors) - we will try again on the next poll.
 	fd, err := accessor.OpenWithOSPath(filename)
 	if err != nil {
+		health.SetError(err)
 		return cursor
 	}
 	defer fd.Close()
@@ -211,6 +273,7 @@ func (self *SyslogWatcherService) monitorOnce(
 	// File must be seekable
 	pos, err := fd.Seek(cursor.last_line_offset, 0)
 	if err != nil {
+		health.SetError(err)
 		return cursor
 	}
 
@@ -223,8 +286,10 @@ func (self *SyslogWatcherService) monitorOnce(
 	buff := make([]byte, BUFFER_SIZE)
 	n, err := fd.Read(buff)
 	if err != nil && err != io.EOF {
+		health.SetError(err)
 		return cursor
 	}
+	health.SetState(watchers.STATE_OK)
 
 	buff = buff[:n]
 	offset := 0
@@ -235,6 +300,7 @@ func (self *SyslogWatcherService) monitorOnce(
 		if new_lf > 0 {
 			new_handles := self.distributeLine(
 				string(buff[offset:offset+new_lf]), filename, key, handles)
+			health.EventSent()
 
 			// No more listeners - we dont care any more.
 			if len(new_handles) == 0 {
@@ -287,6 +353,19 @@ func (self *SyslogWatcherService) distributeLine(
 type Cursor struct {
 	file_size        int64
 	last_line_offset int64
+
+	// Used to detect when the file is replaced.
+	btime   time.Time
+	missing bool
+}
+
+// Detect if the file was replaced since we last looked at it. Not all
+// accessors report a birth time so this is only a best effort -
+// truncation is also detected by the file shrinking.
+func isNewFile(cursor *Cursor, stat accessors.FileInfo) bool {
+	btime := stat.Btime()
+	return !cursor.btime.IsZero() && !btime.IsZero() &&
+		!btime.Equal(cursor.btime)
 }
 
 // A handle is given for each interested party. We write the event on
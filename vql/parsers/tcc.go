@@ -0,0 +1,215 @@
+package parsers
+
+// TCC (Transparency, Consent, and Control) databases record which
+// applications were granted access to protected resources on
+// macOS. The schema changed over macOS versions, so we normalize the
+// records here rather than in each artifact.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Velocidex/ordereddict"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	tccAuthValues = map[int64]string{
+		0: "Denied",
+		1: "Unknown",
+		2: "Allowed",
+		3: "Limited",
+	}
+
+	tccAuthReasons = map[int64]string{
+		1:  "Error",
+		2:  "User Consent",
+		3:  "User Set",
+		4:  "System Set",
+		5:  "Service Policy",
+		6:  "MDM Policy",
+		7:  "Override Policy",
+		8:  "Missing Usage String",
+		9:  "Prompt Timeout",
+		10: "Preflight Unknown",
+		11: "Entitled",
+		12: "App Type Policy",
+	}
+
+	tccClientTypes = map[int64]string{
+		0: "Bundle Identifier",
+		1: "Absolute Path",
+	}
+)
+
+type TCCPluginArgs struct {
+	Filenames []string `vfilter:"required,field=file,doc=A list of TCC.db files to parse."`
+	Accessor  string   `vfilter:"optional,field=accessor,doc=The accessor to use."`
+}
+
+type TCCPlugin struct{}
+
+func (self TCCPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &TCCPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("parse_tcc: %v", err)
+			return
+		}
+
+		if arg.Accessor == "" {
+			arg.Accessor = "file"
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("parse_tcc: %v", err)
+			return
+		}
+
+		for _, filename := range arg.Filenames {
+			func() {
+				defer utils.RecoverVQL(scope)
+
+				handle, err := GetHandleSqlite(ctx, &SQLPluginArgs{
+					Filename: filename,
+					Accessor: arg.Accessor,
+				}, scope)
+				if err != nil {
+					scope.Log("parse_tcc: %v", err)
+					return
+				}
+
+				rows, err := handle.Queryx("SELECT * FROM access")
+				if err != nil {
+					scope.Log("parse_tcc: %v: %v", filename, err)
+					return
+				}
+				defer rows.Close()
+
+				for rows.Next() {
+					record := make(map[string]interface{})
+					err := rows.MapScan(record)
+					if err != nil {
+						scope.Log("parse_tcc: %v: %v", filename, err)
+						return
+					}
+
+					row := tccRecord(record)
+					row.Set("_Source", filename)
+
+					select {
+					case <-ctx.Done():
+						return
+					case output_chan <- row:
+					}
+				}
+			}()
+		}
+	}()
+
+	return output_chan
+}
+
+// Convert a row from the access table into a normalized record.
+func tccRecord(record map[string]interface{}) *ordereddict.Dict {
+	get_int := func(name string) (int64, bool) {
+		value, pres := record[name]
+		if !pres {
+			return 0, false
+		}
+		return utils.ToInt64(value)
+	}
+
+	get_string := func(name string) string {
+		switch t := record[name].(type) {
+		case []byte:
+			return string(t)
+		case nil:
+			return ""
+		default:
+			return fmt.Sprintf("%v", t)
+		}
+	}
+
+	lookup := func(names map[int64]string, value int64) string {
+		name, pres := names[value]
+		if !pres {
+			return fmt.Sprintf("Unknown (%d)", value)
+		}
+		return name
+	}
+
+	result := ordereddict.NewDict().
+		Set("Service", get_string("service")).
+		Set("Client", get_string("client"))
+
+	client_type, _ := get_int("client_type")
+	result.Set("ClientType", lookup(tccClientTypes, client_type))
+
+	// Big Sur and later record the authorization in auth_value,
+	// older versions only have the allowed flag.
+	auth_value, pres := get_int("auth_value")
+	if !pres {
+		allowed, _ := get_int("allowed")
+		auth_value = 0
+		if allowed != 0 {
+			auth_value = 2
+		}
+	}
+	result.Set("Authorization", lookup(tccAuthValues, auth_value)).
+		Set("Allowed", auth_value == 2 || auth_value == 3)
+
+	auth_reason, pres := get_int("auth_reason")
+	if pres {
+		result.Set("AuthReason", lookup(tccAuthReasons, auth_reason))
+	} else {
+		result.Set("AuthReason", nil)
+	}
+
+	prompt_count, pres := get_int("prompt_count")
+	if pres {
+		result.Set("PromptCount", prompt_count)
+	} else {
+		result.Set("PromptCount", nil)
+	}
+
+	last_modified, pres := get_int("last_modified")
+	if pres {
+		result.Set("LastModified", utils.ParseTimeFromInt64(last_modified))
+	} else {
+		result.Set("LastModified", nil)
+	}
+
+	result.Set("IndirectObject", get_string("indirect_object_identifier"))
+
+	// The code signing requirement is kept as is.
+	csreq, _ := record["csreq"].([]byte)
+	result.Set("_CSReq", csreq)
+
+	return result
+}
+
+func (self TCCPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "parse_tcc",
+		Doc:     "Parse the permission records in a macOS TCC database.",
+		ArgType: type_map.AddType(scope, &TCCPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&TCCPlugin{})
+}
@@ -0,0 +1,49 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCCRecord(t *testing.T) {
+	// Big Sur and later.
+	row := tccRecord(map[string]interface{}{
+		"service":       "kTCCServiceCamera",
+		"client":        "com.example.app",
+		"client_type":   int64(0),
+		"auth_value":    int64(2),
+		"auth_reason":   int64(3),
+		"last_modified": int64(1600000000),
+	})
+
+	authorization, _ := row.Get("Authorization")
+	assert.Equal(t, "Allowed", authorization)
+
+	reason, _ := row.Get("AuthReason")
+	assert.Equal(t, "User Set", reason)
+
+	client_type, _ := row.Get("ClientType")
+	assert.Equal(t, "Bundle Identifier", client_type)
+
+	// Catalina and earlier only have the allowed flag.
+	row = tccRecord(map[string]interface{}{
+		"service":      []byte("kTCCServiceMicrophone"),
+		"client":       "/usr/bin/evil",
+		"client_type":  int64(1),
+		"allowed":      int64(0),
+		"prompt_count": int64(1),
+	})
+
+	service, _ := row.Get("Service")
+	assert.Equal(t, "kTCCServiceMicrophone", service)
+
+	allowed, _ := row.Get("Allowed")
+	assert.Equal(t, false, allowed)
+
+	reason, _ = row.Get("AuthReason")
+	assert.Nil(t, reason)
+
+	client_type, _ = row.Get("ClientType")
+	assert.Equal(t, "Absolute Path", client_type)
+}
@@ -0,0 +1,249 @@
+// Checkpoints allow watch_usn() to resume after a restart.
+
+// The checkpoint file records the USN of the last record the watcher
+// emitted. When the watcher starts again with the same checkpoint it
+// emits the records written since then instead of only new
+// records. The checkpoint is updated after each poll of the journal
+// so a crash may replay the records of the last poll.
+//
+// Watchers with a checkpoint read the journal independently of the
+// shared watcher for the device since each one resumes from a
+// different point.
+
+package usn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	ntfs "www.velocidex.com/golang/go-ntfs/parser"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/accessors/ntfs/readers"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type usnCheckpoint struct {
+	Device string `json:"device"`
+	Usn    int64  `json:"usn"`
+}
+
+// Read the last USN emitted for the device or -1 if there is no
+// checkpoint yet.
+func readUSNCheckpoint(path, device string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return -1, nil
+		}
+		return 0, err
+	}
+
+	record := &usnCheckpoint{}
+	err = json.Unmarshal(data, record)
+	if err != nil {
+		return 0, fmt.Errorf("Corrupted checkpoint %v: %w", path, err)
+	}
+
+	if record.Device != device {
+		return 0, fmt.Errorf(
+			"Checkpoint %v belongs to device %v", path, record.Device)
+	}
+
+	return record.Usn, nil
+}
+
+// Replace the checkpoint atomically so it is never left half written.
+func writeUSNCheckpoint(path, device string, usn int64) error {
+	serialized, err := json.Marshal(&usnCheckpoint{
+		Device: device,
+		Usn:    usn,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp_path := path + ".tmp"
+	err = os.WriteFile(tmp_path, serialized, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp_path, path)
+}
+
+// A USN journal record. The USN is the offset of the record in the
+// journal.
+type usnRecord interface {
+	Usn() uint64
+}
+
+// Tracks the position of a watcher in the journal.
+type usnCheckpointWatcher struct {
+	scope           vfilter.Scope
+	device          string
+	checkpoint_path string
+
+	// Returns the records at or after the offset in the journal.
+	parse    func(ctx context.Context, start int64) <-chan usnRecord
+	make_row func(record usnRecord) vfilter.Row
+
+	// The USN of the last record emitted and the USN stored in
+	// the checkpoint.
+	last  int64
+	saved int64
+
+	// Without a checkpoint the first poll only finds the end of the
+	// journal, like the shared watcher.
+	emitting bool
+}
+
+// Emit the records written since the last poll.
+func (self *usnCheckpointWatcher) poll(
+	ctx context.Context, output_chan chan vfilter.Row) error {
+	start := self.last
+	if start < 0 {
+		start = 0
+	}
+
+	count := 0
+	for item := range self.parse(ctx, start) {
+		count++
+
+		usn := int64(item.Usn())
+		if usn <= self.last {
+			continue
+		}
+
+		// The record at the checkpoint is always returned first
+		// unless the journal wrapped and the record was
+		// deallocated. We continue from the oldest record left.
+		if count == 1 && self.last >= 0 {
+			self.scope.Log("watch_usn: The journal of %v wrapped - records "+
+				"from USN %v to %v were lost", self.device, self.last, usn)
+		}
+
+		if self.emitting {
+			select {
+			case <-ctx.Done():
+				return nil
+			case output_chan <- self.make_row(item):
+			}
+		}
+		self.last = usn
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	// The journal was deleted and recreated so its USNs start again
+	// and all its records are new.
+	if count == 0 && self.last >= 0 && self.hasRecords(ctx) {
+		self.scope.Log("watch_usn: USN %v is no longer in the journal of %v, "+
+			"starting from the beginning", self.last, self.device)
+		self.last = -1
+		self.emitting = true
+		return self.poll(ctx, output_chan)
+	}
+	self.emitting = true
+
+	return self.save()
+}
+
+// A journal which can not be read returns no records either, so
+// check there are records before the checkpoint before starting over.
+func (self *usnCheckpointWatcher) hasRecords(ctx context.Context) bool {
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for range self.parse(sub_ctx, 0) {
+		return true
+	}
+	return false
+}
+
+func (self *usnCheckpointWatcher) save() error {
+	if self.last == self.saved {
+		return nil
+	}
+
+	err := writeUSNCheckpoint(self.checkpoint_path, self.device, self.last)
+	if err != nil {
+		return err
+	}
+	self.saved = self.last
+	return nil
+}
+
+// Poll the journal every frequency seconds and emit the records
+// after the checkpoint.
+func watchUSNWithCheckpoint(
+	ctx context.Context, scope vfilter.Scope,
+	device *accessors.OSPath, checkpoint_path string, frequency uint64,
+	output_chan chan vfilter.Row) error {
+
+	key := device.String()
+	last, err := readUSNCheckpoint(checkpoint_path, key)
+	if err != nil {
+		return err
+	}
+
+	ntfs_ctx, err := readers.GetNTFSContext(scope, device, "ntfs")
+	if err != nil {
+		return fmt.Errorf("while opening device %v: %w", device, err)
+	}
+	defer ntfs_ctx.Close()
+
+	watcher := &usnCheckpointWatcher{
+		scope:           scope,
+		device:          key,
+		checkpoint_path: checkpoint_path,
+		parse: func(ctx context.Context, start int64) <-chan usnRecord {
+			output := make(chan usnRecord)
+			go func() {
+				defer close(output)
+
+				for item := range ntfs.ParseUSN(ctx, ntfs_ctx, start) {
+					select {
+					case <-ctx.Done():
+						return
+					case output <- item:
+					}
+				}
+			}()
+			return output
+		},
+		make_row: func(record usnRecord) vfilter.Row {
+			return makeUSNRecord(record.(*ntfs.USN_RECORD))
+		},
+		last:     last,
+		saved:    last,
+		emitting: last >= 0,
+	}
+
+	if watcher.emitting {
+		scope.Log("watch_usn: Resuming %v from USN %v", device, last)
+	}
+	defer func() {
+		_ = watcher.save()
+	}()
+
+	for {
+		ntfs_ctx.Purge()
+
+		err := watcher.poll(ctx, output_chan)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Duration(frequency) * time.Second):
+		}
+	}
+}
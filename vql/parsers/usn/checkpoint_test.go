@@ -0,0 +1,146 @@
+package usn
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"path/filepath"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type testUSNRecord uint64
+
+func (self testUSNRecord) Usn() uint64 {
+	return uint64(self)
+}
+
+type testJournal struct {
+	records []uint64
+}
+
+func (self *testJournal) parse(
+	ctx context.Context, start int64) <-chan usnRecord {
+	output := make(chan usnRecord)
+	go func() {
+		defer close(output)
+
+		for _, usn := range self.records {
+			if int64(usn) < start {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output <- testUSNRecord(usn):
+			}
+		}
+	}()
+	return output
+}
+
+func newTestWatcher(t *testing.T, journal *testJournal,
+	last int64) (*usnCheckpointWatcher, *bytes.Buffer) {
+	log_buffer := &bytes.Buffer{}
+	scope := vql_subsystem.MakeScope()
+	scope.SetLogger(log.New(log_buffer, "", 0))
+
+	return &usnCheckpointWatcher{
+		scope:           scope,
+		device:          `\\.\C:`,
+		checkpoint_path: filepath.Join(t.TempDir(), "usn.json"),
+		parse:           journal.parse,
+		make_row: func(record usnRecord) vfilter.Row {
+			return ordereddict.NewDict().Set("Usn", record.Usn())
+		},
+		last:     last,
+		saved:    last,
+		emitting: last >= 0,
+	}, log_buffer
+}
+
+// Poll the journal and collect the USNs emitted.
+func pollUSNs(t *testing.T, watcher *usnCheckpointWatcher) []uint64 {
+	output_chan := make(chan vfilter.Row)
+	result := []uint64{}
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		for row := range output_chan {
+			usn, _ := row.(*ordereddict.Dict).Get("Usn")
+			result = append(result, usn.(uint64))
+		}
+	}()
+
+	err := watcher.poll(context.Background(), output_chan)
+	close(output_chan)
+	<-done
+
+	require.NoError(t, err)
+	return result
+}
+
+func TestUSNCheckpointResume(t *testing.T) {
+	journal := &testJournal{records: []uint64{100, 200, 300}}
+
+	// Without a checkpoint only new records are emitted.
+	watcher, _ := newTestWatcher(t, journal, -1)
+	assert.Equal(t, []uint64{}, pollUSNs(t, watcher))
+
+	journal.records = append(journal.records, 400)
+	assert.Equal(t, []uint64{400}, pollUSNs(t, watcher))
+
+	last, err := readUSNCheckpoint(watcher.checkpoint_path, watcher.device)
+	require.NoError(t, err)
+	assert.Equal(t, int64(400), last)
+
+	// A new watcher resumes after the checkpoint.
+	journal.records = append(journal.records, 500)
+	resumed, _ := newTestWatcher(t, journal, last)
+	assert.Equal(t, []uint64{500}, pollUSNs(t, resumed))
+}
+
+func TestUSNCheckpointWrapped(t *testing.T) {
+	// The records up to 300 were deallocated.
+	journal := &testJournal{records: []uint64{300, 400}}
+
+	watcher, log_buffer := newTestWatcher(t, journal, 200)
+	assert.Equal(t, []uint64{300, 400}, pollUSNs(t, watcher))
+	assert.Contains(t, log_buffer.String(),
+		"records from USN 200 to 300 were lost")
+}
+
+func TestUSNCheckpointRecreated(t *testing.T) {
+	// The journal was recreated so its USNs start again.
+	journal := &testJournal{records: []uint64{10, 20}}
+
+	watcher, log_buffer := newTestWatcher(t, journal, 200)
+	assert.Equal(t, []uint64{10, 20}, pollUSNs(t, watcher))
+	assert.Contains(t, log_buffer.String(), "starting from the beginning")
+
+	// An unreadable journal does not reset the checkpoint.
+	journal.records = nil
+	watcher, _ = newTestWatcher(t, journal, 200)
+	assert.Equal(t, []uint64{}, pollUSNs(t, watcher))
+	assert.Equal(t, int64(200), watcher.last)
+}
+
+func TestUSNCheckpointCancelled(t *testing.T) {
+	journal := &testJournal{records: []uint64{100, 200, 300}}
+	watcher, _ := newTestWatcher(t, journal, 100)
+
+	// Nobody reads the records so the first send is abandoned and
+	// the checkpoint is not advanced past it.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := watcher.poll(ctx, make(chan vfilter.Row))
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), watcher.last)
+}
@@ -7,6 +7,7 @@ import (
 	ntfs "www.velocidex.com/golang/go-ntfs/parser"
 	"www.velocidex.com/golang/velociraptor/accessors"
 	"www.velocidex.com/golang/velociraptor/accessors/ntfs/readers"
+	"www.velocidex.com/golang/velociraptor/acls"
 	utils "www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	vfilter "www.velocidex.com/golang/vfilter"
@@ -84,7 +85,8 @@ func (self USNPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfil
 }
 
 type WatchUSNPluginArgs struct {
-	Device string `vfilter:"required,field=device,doc=The device file to open (as an NTFS device)."`
+	Device     string `vfilter:"required,field=device,doc=The device file to open (as an NTFS device)."`
+	Checkpoint string `vfilter:"optional,field=checkpoint,doc=Record the last USN emitted in this file. Watching again with the same checkpoint resumes from that USN instead of only emitting new events."`
 }
 
 type WatchUSNPlugin struct{}
@@ -123,6 +125,21 @@ func (self WatchUSNPlugin) Call(
 			return
 		}
 
+		if arg.Checkpoint != "" {
+			err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_WRITE)
+			if err != nil {
+				scope.Log("watch_usn: %v", err)
+				return
+			}
+
+			err = watchUSNWithCheckpoint(ctx, scope, ntfs_device,
+				arg.Checkpoint, getFrequency(scope), output_chan)
+			if err != nil {
+				scope.Log("watch_usn: %v", err)
+			}
+			return
+		}
+
 		// Register our interest in the log.
 		cancel, err := GlobalEventLogService.Register(
 			ntfs_device, "ntfs", ctx, config_obj, scope, event_channel)
@@ -72,11 +72,7 @@ func (self *USNWatcherService) Register(
 		output_chan: output_chan,
 		scope:       scope}
 
-	frequency := vql_subsystem.GetIntFromRow(scope, scope,
-		constants.USN_FREQUENCY)
-	if frequency == 0 {
-		frequency = FREQUENCY
-	}
+	frequency := getFrequency(scope)
 
 	scope.Log("Registering USN log watcher for %v with handle %v and frequency %v seconds",
 		device, handle.id, frequency)
@@ -118,6 +114,15 @@ func (self *USNWatcherService) Register(
 	}, nil
 }
 
+func getFrequency(scope vfilter.Scope) uint64 {
+	frequency := vql_subsystem.GetIntFromRow(scope, scope,
+		constants.USN_FREQUENCY)
+	if frequency == 0 {
+		frequency = FREQUENCY
+	}
+	return frequency
+}
+
 // Distribute the event to all interested listeners.
 func (self *USNWatcherService) distributeEvent(
 	event *parser.USN_RECORD, key string) {
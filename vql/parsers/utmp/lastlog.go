@@ -0,0 +1,94 @@
+package utmp
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	// glibc always uses a 32 bit ll_time.
+	LASTLOG_GLIBC_SIZE = 4 + UT_LINESIZE + UT_HOSTSIZE
+
+	// musl on 64 bit architectures uses a native time_t.
+	LASTLOG_MUSL_SIZE = 8 + UT_LINESIZE + UT_HOSTSIZE
+
+	// Read this many records at a time so we can quickly skip the
+	// (usually sparse) empty regions of the file.
+	LASTLOG_CHUNK = 1024
+
+	// By default only consider regular and system uids. The file
+	// is sparse and may be very large when high uids
+	// (e.g. nfsnobody) have logged in.
+	DEFAULT_MAX_UID = 65536
+)
+
+type LastlogRecord struct {
+	Uid      uint64
+	Time     time.Time
+	Terminal string
+	Host     string
+}
+
+func lastlogRecordSize(layout string, size int64) int64 {
+	switch layout {
+	case LAYOUT_GLIBC:
+		return LASTLOG_GLIBC_SIZE
+	case LAYOUT_MUSL:
+		return LASTLOG_MUSL_SIZE
+	}
+
+	if size%LASTLOG_GLIBC_SIZE != 0 && size%LASTLOG_MUSL_SIZE == 0 {
+		return LASTLOG_MUSL_SIZE
+	}
+	return LASTLOG_GLIBC_SIZE
+}
+
+// Read the lastlog records for all uids below max_uid. Users which
+// never logged in are skipped.
+func readLastlog(reader io.ReaderAt, record_size int64, max_uid uint64,
+	callback func(record *LastlogRecord) bool) error {
+	time_size := record_size - UT_LINESIZE - UT_HOSTSIZE
+	buf := make([]byte, record_size*LASTLOG_CHUNK)
+
+	for uid := uint64(0); uid < max_uid; uid += LASTLOG_CHUNK {
+		n, err := reader.ReadAt(buf, int64(uid)*record_size)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		for i := int64(0); (i+1)*record_size <= int64(n); i++ {
+			if uid+uint64(i) >= max_uid {
+				return nil
+			}
+
+			record := buf[i*record_size : (i+1)*record_size]
+			var ll_time int64
+			if time_size == 4 {
+				ll_time = int64(int32(binary.LittleEndian.Uint32(record)))
+			} else {
+				ll_time = int64(binary.LittleEndian.Uint64(record))
+			}
+
+			if ll_time == 0 {
+				continue
+			}
+
+			if !callback(&LastlogRecord{
+				Uid:      uid + uint64(i),
+				Time:     time.Unix(ll_time, 0).UTC(),
+				Terminal: cString(record[time_size : time_size+UT_LINESIZE]),
+				Host:     cString(record[time_size+UT_LINESIZE:]),
+			}) {
+				return nil
+			}
+		}
+
+		if int64(n) < int64(len(buf)) {
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package utmp
+
+import (
+	"context"
+	"io"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	utils "www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type UtmpPluginArgs struct {
+	Filenames []*accessors.OSPath `vfilter:"required,field=filename,doc=A list of utmp, wtmp or btmp files to parse."`
+	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	Layout    string              `vfilter:"optional,field=layout,doc=The record layout (glibc or musl). Detected automatically by default."`
+	Sessions  bool                `vfilter:"optional,field=sessions,doc=Pair login and logout records into sessions."`
+}
+
+type UtmpPlugin struct{}
+
+func (self UtmpPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &UtmpPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("parse_utmp: %v", err)
+			return
+		}
+
+		var layout *utmpLayout
+		if arg.Layout != LAYOUT_AUTO {
+			layout, err = getLayout(arg.Layout)
+			if err != nil {
+				scope.Log("parse_utmp: %v", err)
+				return
+			}
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("parse_utmp: %v", err)
+			return
+		}
+
+		accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+		if err != nil {
+			scope.Log("parse_utmp: %v", err)
+			return
+		}
+
+		emit := func(row vfilter.Row) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case output_chan <- row:
+				return true
+			}
+		}
+
+		for _, filename := range arg.Filenames {
+			func() {
+				defer utils.RecoverVQL(scope)
+
+				reader, size, err := openFile(accessor, filename)
+				if err != nil {
+					scope.Log("parse_utmp: Unable to open file %s: %v",
+						filename, err)
+					return
+				}
+				defer reader.Close()
+
+				file_layout := layout
+				if file_layout == nil {
+					file_layout = detectLayout(reader, size)
+				}
+
+				tracker := newSessionTracker()
+				err = readRecords(reader, file_layout, func(record *UtmpRecord) bool {
+					if !arg.Sessions {
+						if record.Type == "EMPTY" {
+							return true
+						}
+						return emit(recordRow(filename, file_layout, record))
+					}
+
+					for _, session := range tracker.Add(record) {
+						if !emit(sessionRow(filename, session)) {
+							return false
+						}
+					}
+					return true
+				})
+				if err != nil {
+					scope.Log("parse_utmp: %v", err)
+					return
+				}
+
+				if arg.Sessions {
+					for _, session := range tracker.Remaining() {
+						if !emit(sessionRow(filename, session)) {
+							return
+						}
+					}
+				}
+			}()
+		}
+	}()
+
+	return output_chan
+}
+
+func (self UtmpPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "parse_utmp",
+		Doc:     "Parse utmp, wtmp and btmp login accounting files.",
+		ArgType: type_map.AddType(scope, &UtmpPluginArgs{}),
+	}
+}
+
+type LastlogPluginArgs struct {
+	Filenames []*accessors.OSPath `vfilter:"required,field=filename,doc=A list of lastlog files to parse."`
+	Accessor  string              `vfilter:"optional,field=accessor,doc=The accessor to use."`
+	Layout    string              `vfilter:"optional,field=layout,doc=The record layout (glibc or musl). Detected automatically by default."`
+	MaxUid    uint64              `vfilter:"optional,field=max_uid,doc=Only report uids below this (default 65536)."`
+}
+
+type LastlogPlugin struct{}
+
+func (self LastlogPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &LastlogPluginArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("parse_lastlog: %v", err)
+			return
+		}
+
+		if arg.Layout != LAYOUT_AUTO {
+			_, err = getLayout(arg.Layout)
+			if err != nil {
+				scope.Log("parse_lastlog: %v", err)
+				return
+			}
+		}
+
+		if arg.MaxUid == 0 {
+			arg.MaxUid = DEFAULT_MAX_UID
+		}
+
+		err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+		if err != nil {
+			scope.Log("parse_lastlog: %v", err)
+			return
+		}
+
+		accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+		if err != nil {
+			scope.Log("parse_lastlog: %v", err)
+			return
+		}
+
+		for _, filename := range arg.Filenames {
+			func() {
+				defer utils.RecoverVQL(scope)
+
+				reader, size, err := openFile(accessor, filename)
+				if err != nil {
+					scope.Log("parse_lastlog: Unable to open file %s: %v",
+						filename, err)
+					return
+				}
+				defer reader.Close()
+
+				err = readLastlog(reader, lastlogRecordSize(arg.Layout, size),
+					arg.MaxUid, func(record *LastlogRecord) bool {
+						select {
+						case <-ctx.Done():
+							return false
+						case output_chan <- ordereddict.NewDict().
+							Set("FullPath", filename).
+							Set("Uid", record.Uid).
+							Set("LastLogin", record.Time).
+							Set("Terminal", record.Terminal).
+							Set("Host", record.Host):
+							return true
+						}
+					})
+				if err != nil {
+					scope.Log("parse_lastlog: %v", err)
+				}
+			}()
+		}
+	}()
+
+	return output_chan
+}
+
+func (self LastlogPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "parse_lastlog",
+		Doc:     "Parse the lastlog file.",
+		ArgType: type_map.AddType(scope, &LastlogPluginArgs{}),
+	}
+}
+
+type readerAtCloser struct {
+	io.ReaderAt
+	io.Closer
+}
+
+func openFile(accessor accessors.FileSystemAccessor,
+	filename *accessors.OSPath) (*readerAtCloser, int64, error) {
+	size := int64(-1)
+	stat, err := accessor.LstatWithOSPath(filename)
+	if err == nil {
+		size = stat.Size()
+	}
+
+	fd, err := accessor.OpenWithOSPath(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	reader, ok := fd.(io.ReaderAt)
+	if !ok {
+		reader = utils.MakeReaderAtter(fd)
+	}
+
+	return &readerAtCloser{ReaderAt: reader, Closer: fd}, size, nil
+}
+
+func recordRow(filename *accessors.OSPath, layout *utmpLayout,
+	record *UtmpRecord) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("FullPath", filename).
+		Set("Offset", record.Offset).
+		Set("Layout", layout.name).
+		Set("Type", record.Type).
+		Set("ID", record.ID).
+		Set("PID", record.Pid).
+		Set("Host", record.Host).
+		Set("User", record.User).
+		Set("IpAddr", record.IpAddr).
+		Set("Terminal", record.Terminal).
+		Set("Session", record.Session).
+		Set("Termination", record.Termination).
+		Set("Exit", record.Exit).
+		Set("Time", record.Time)
+}
+
+func sessionRow(filename *accessors.OSPath, session *Session) *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("FullPath", filename).
+		Set("User", session.User).
+		Set("Terminal", session.Terminal).
+		Set("Host", session.Host).
+		Set("IpAddr", session.IpAddr).
+		Set("PID", session.Pid).
+		Set("LoginTime", session.LoginTime).
+		Set("LogoutTime", session.LogoutTime).
+		Set("Duration", int64(session.Duration.Seconds())).
+		Set("Status", session.Status)
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&UtmpPlugin{})
+	vql_subsystem.RegisterPlugin(&LastlogPlugin{})
+}
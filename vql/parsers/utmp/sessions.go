@@ -0,0 +1,119 @@
+package utmp
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	SESSION_LOGGED_OUT = "logged out"
+	SESSION_ACTIVE     = "still logged in"
+	SESSION_CRASH      = "crash"
+	SESSION_DOWN       = "down"
+	SESSION_GONE       = "gone"
+)
+
+type Session struct {
+	User       string
+	Terminal   string
+	Host       string
+	IpAddr     string
+	Pid        int32
+	LoginTime  time.Time
+	LogoutTime time.Time
+	Duration   time.Duration
+	Status     string
+}
+
+// Pairs the login and logout records in a wtmp file into sessions
+// in the same way as last(1). Records must be fed in file order.
+type sessionTracker struct {
+	// Open sessions by terminal.
+	open map[string]*Session
+}
+
+func (self *sessionTracker) close(session *Session, t time.Time, status string) *Session {
+	delete(self.open, session.Terminal)
+	session.LogoutTime = t
+	session.Status = status
+	if t.After(session.LoginTime) {
+		session.Duration = t.Sub(session.LoginTime)
+	}
+	return session
+}
+
+func (self *sessionTracker) closeAll(t time.Time, status string) []*Session {
+	result := []*Session{}
+	for _, session := range self.open {
+		result = append(result, self.close(session, t, status))
+	}
+	sortSessions(result)
+	return result
+}
+
+// Process the record and return any sessions it closes.
+func (self *sessionTracker) Add(record *UtmpRecord) []*Session {
+	switch record.Type {
+	case "USER_PROCESS":
+		var result []*Session
+
+		// A new login on the same terminal means we missed
+		// the logout.
+		previous, pres := self.open[record.Terminal]
+		if pres {
+			result = append(result, self.close(
+				previous, record.Time, SESSION_GONE))
+		}
+
+		self.open[record.Terminal] = &Session{
+			User:      record.User,
+			Terminal:  record.Terminal,
+			Host:      record.Host,
+			IpAddr:    record.IpAddr,
+			Pid:       record.Pid,
+			LoginTime: record.Time,
+		}
+		return result
+
+	case "DEAD_PROCESS":
+		session, pres := self.open[record.Terminal]
+		if pres {
+			return []*Session{self.close(
+				session, record.Time, SESSION_LOGGED_OUT)}
+		}
+
+	case "BOOT_TIME":
+		// The system rebooted without logging the users out.
+		return self.closeAll(record.Time, SESSION_CRASH)
+
+	case "RUN_LVL":
+		if record.User == "shutdown" {
+			return self.closeAll(record.Time, SESSION_DOWN)
+		}
+	}
+
+	return nil
+}
+
+// Sessions which are still open at the end of the file.
+func (self *sessionTracker) Remaining() []*Session {
+	result := []*Session{}
+	for _, session := range self.open {
+		session.Status = SESSION_ACTIVE
+		result = append(result, session)
+	}
+	sortSessions(result)
+	return result
+}
+
+func sortSessions(sessions []*Session) {
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LoginTime.Before(sessions[j].LoginTime)
+	})
+}
+
+func newSessionTracker() *sessionTracker {
+	return &sessionTracker{
+		open: make(map[string]*Session),
+	}
+}
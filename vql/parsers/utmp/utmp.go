@@ -0,0 +1,264 @@
+// Parsers for the Linux login accounting files.
+
+// The utmp, wtmp and btmp files are a sequence of fixed size struct
+// utmp records (see utmp(5)). The layout of the record depends on
+// the libc which wrote it:
+
+// - glibc uses 32 bit timestamps on all architectures so the record
+//   is always 384 bytes.
+// - musl on 64 bit architectures uses a native struct timeval and
+//   long session id so the record is 400 bytes.
+
+// The lastlog file is a sparse array of struct lastlog records
+// indexed by uid.
+
+package utmp
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"time"
+)
+
+const (
+	LAYOUT_AUTO  = ""
+	LAYOUT_GLIBC = "glibc"
+	LAYOUT_MUSL  = "musl"
+
+	UT_LINESIZE = 32
+	UT_NAMESIZE = 32
+	UT_HOSTSIZE = 256
+
+	// Number of records to examine when guessing the layout.
+	DETECT_RECORDS = 20
+)
+
+var (
+	invalidLayoutError = errors.New("Unknown utmp layout")
+
+	utmpTypes = map[int16]string{
+		0: "EMPTY",
+		1: "RUN_LVL",
+		2: "BOOT_TIME",
+		3: "NEW_TIME",
+		4: "OLD_TIME",
+		5: "INIT_PROCESS",
+		6: "LOGIN_PROCESS",
+		7: "USER_PROCESS",
+		8: "DEAD_PROCESS",
+		9: "ACCOUNTING",
+	}
+)
+
+type utmpLayout struct {
+	name         string
+	size         int64
+	session      int
+	session_size int
+	tv_sec       int
+	tv_usec      int
+	time_size    int
+	addr         int
+}
+
+var (
+	glibcLayout = &utmpLayout{
+		name: LAYOUT_GLIBC, size: 384,
+		session: 336, session_size: 4,
+		tv_sec: 340, tv_usec: 344, time_size: 4,
+		addr: 348,
+	}
+
+	muslLayout = &utmpLayout{
+		name: LAYOUT_MUSL, size: 400,
+		session: 336, session_size: 8,
+		tv_sec: 344, tv_usec: 352, time_size: 8,
+		addr: 360,
+	}
+)
+
+type UtmpRecord struct {
+	Offset      int64
+	Type        string
+	Pid         int32
+	Terminal    string
+	ID          string
+	User        string
+	Host        string
+	Termination int16
+	Exit        int16
+	Session     int64
+	Time        time.Time
+	IpAddr      string
+}
+
+func parseRecord(layout *utmpLayout, buf []byte, offset int64) *UtmpRecord {
+	ut_type := int16(binary.LittleEndian.Uint16(buf[0:]))
+	type_name, pres := utmpTypes[ut_type]
+	if !pres {
+		type_name = "UNKNOWN"
+	}
+
+	result := &UtmpRecord{
+		Offset:      offset,
+		Type:        type_name,
+		Pid:         int32(binary.LittleEndian.Uint32(buf[4:])),
+		Terminal:    cString(buf[8 : 8+UT_LINESIZE]),
+		ID:          cString(buf[40:44]),
+		User:        cString(buf[44 : 44+UT_NAMESIZE]),
+		Host:        cString(buf[76 : 76+UT_HOSTSIZE]),
+		Termination: int16(binary.LittleEndian.Uint16(buf[332:])),
+		Exit:        int16(binary.LittleEndian.Uint16(buf[334:])),
+		IpAddr:      formatAddress(buf[layout.addr : layout.addr+16]),
+	}
+
+	var sec, usec int64
+	if layout.time_size == 4 {
+		sec = int64(int32(binary.LittleEndian.Uint32(buf[layout.tv_sec:])))
+		usec = int64(int32(binary.LittleEndian.Uint32(buf[layout.tv_usec:])))
+	} else {
+		sec = int64(binary.LittleEndian.Uint64(buf[layout.tv_sec:]))
+		usec = int64(binary.LittleEndian.Uint64(buf[layout.tv_usec:]))
+	}
+	if usec < 0 || usec >= 1000000 {
+		usec = 0
+	}
+	result.Time = time.Unix(sec, usec*1000).UTC()
+
+	if layout.session_size == 4 {
+		result.Session = int64(int32(binary.LittleEndian.Uint32(buf[layout.session:])))
+	} else {
+		result.Session = int64(binary.LittleEndian.Uint64(buf[layout.session:]))
+	}
+
+	return result
+}
+
+// Score how plausible the record is for the layout. Valid records
+// have a known type and a timestamp within a sensible range.
+func scoreRecord(layout *utmpLayout, buf []byte) int {
+	ut_type := int16(binary.LittleEndian.Uint16(buf[0:]))
+	if ut_type == 0 {
+		return 0
+	}
+
+	_, pres := utmpTypes[ut_type]
+	if !pres {
+		return -1
+	}
+
+	record := parseRecord(layout, buf, 0)
+	year := record.Time.Year()
+	if year < 1990 || year > 2100 {
+		return -1
+	}
+
+	usec := record.Time.Nanosecond() / 1000
+	if layout.time_size == 8 && binary.LittleEndian.Uint64(buf[layout.tv_usec:]) != uint64(usec) {
+		return -1
+	}
+
+	return 1
+}
+
+// Guess the layout from the file size and the content of the first
+// few records.
+func detectLayout(reader io.ReaderAt, size int64) *utmpLayout {
+	candidates := []*utmpLayout{}
+	for _, layout := range []*utmpLayout{glibcLayout, muslLayout} {
+		if size >= 0 && size%layout.size == 0 {
+			candidates = append(candidates, layout)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		// Possibly a truncated file - consider all layouts.
+		candidates = []*utmpLayout{glibcLayout, muslLayout}
+	case 1:
+		return candidates[0]
+	}
+
+	var best *utmpLayout
+	best_score := 0
+	for _, layout := range candidates {
+		score := 0
+		buf := make([]byte, layout.size)
+		for i := int64(0); i < DETECT_RECORDS; i++ {
+			n, _ := reader.ReadAt(buf, i*layout.size)
+			if int64(n) < layout.size {
+				break
+			}
+			score += scoreRecord(layout, buf)
+		}
+
+		if best == nil || score > best_score {
+			best = layout
+			best_score = score
+		}
+	}
+
+	return best
+}
+
+func getLayout(name string) (*utmpLayout, error) {
+	switch name {
+	case LAYOUT_GLIBC:
+		return glibcLayout, nil
+	case LAYOUT_MUSL:
+		return muslLayout, nil
+	}
+	return nil, invalidLayoutError
+}
+
+// Read all the records from the file. Partial records at the end of
+// the file are ignored.
+func readRecords(reader io.ReaderAt, layout *utmpLayout,
+	callback func(record *UtmpRecord) bool) error {
+	buf := make([]byte, layout.size)
+	for offset := int64(0); ; offset += layout.size {
+		n, err := reader.ReadAt(buf, offset)
+		if int64(n) < layout.size {
+			if err == nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if !callback(parseRecord(layout, buf, offset)) {
+			return nil
+		}
+	}
+}
+
+// The address is stored as 4 32 bit words in network order. IPv4
+// addresses only use the first word.
+func formatAddress(addr []byte) string {
+	is_v4 := true
+	for _, b := range addr[4:] {
+		if b != 0 {
+			is_v4 = false
+			break
+		}
+	}
+
+	if is_v4 {
+		if binary.LittleEndian.Uint32(addr) == 0 {
+			return ""
+		}
+		return net.IP(addr[:4]).String()
+	}
+
+	return net.IP(addr).String()
+}
+
+func cString(buf []byte) string {
+	for i, b := range buf {
+		if b == 0 {
+			return string(buf[:i])
+		}
+	}
+	return string(buf)
+}
@@ -0,0 +1,187 @@
+package utmp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRecord struct {
+	ut_type  int16
+	pid      int32
+	terminal string
+	user     string
+	host     string
+	ip       string
+	time     int64
+}
+
+func buildRecord(layout *utmpLayout, record testRecord) []byte {
+	buf := make([]byte, layout.size)
+	binary.LittleEndian.PutUint16(buf[0:], uint16(record.ut_type))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(record.pid))
+	copy(buf[8:8+UT_LINESIZE], record.terminal)
+	copy(buf[44:44+UT_NAMESIZE], record.user)
+	copy(buf[76:76+UT_HOSTSIZE], record.host)
+
+	if layout.time_size == 4 {
+		binary.LittleEndian.PutUint32(buf[layout.tv_sec:], uint32(record.time))
+	} else {
+		binary.LittleEndian.PutUint64(buf[layout.tv_sec:], uint64(record.time))
+	}
+
+	ip := net.ParseIP(record.ip)
+	if ip.To4() != nil {
+		copy(buf[layout.addr:], ip.To4())
+	} else if ip != nil {
+		copy(buf[layout.addr:], ip)
+	}
+
+	return buf
+}
+
+func buildWtmp(layout *utmpLayout) []byte {
+	result := []byte{}
+	for _, record := range []testRecord{
+		{ut_type: 2, terminal: "~", user: "reboot", time: 1600000000},
+		{ut_type: 7, pid: 100, terminal: "pts/0", user: "mike",
+			host: "10.1.1.1", ip: "10.1.1.1", time: 1600000100},
+		{ut_type: 7, pid: 200, terminal: "pts/1", user: "root",
+			host: "fe80::1", ip: "fe80::1", time: 1600000200},
+		{ut_type: 8, pid: 100, terminal: "pts/0", time: 1600000400},
+		{ut_type: 7, pid: 300, terminal: "pts/0", user: "mike",
+			time: 1600000500},
+	} {
+		result = append(result, buildRecord(layout, record)...)
+	}
+	return result
+}
+
+func TestUtmpLayouts(t *testing.T) {
+	for _, layout := range []*utmpLayout{glibcLayout, muslLayout} {
+		data := buildWtmp(layout)
+		reader := bytes.NewReader(data)
+
+		detected := detectLayout(reader, int64(len(data)))
+		assert.Equal(t, layout.name, detected.name)
+
+		records := []*UtmpRecord{}
+		err := readRecords(reader, detected, func(record *UtmpRecord) bool {
+			records = append(records, record)
+			return true
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, len(records))
+
+		assert.Equal(t, "BOOT_TIME", records[0].Type)
+		assert.Equal(t, "USER_PROCESS", records[1].Type)
+		assert.Equal(t, "mike", records[1].User)
+		assert.Equal(t, "pts/0", records[1].Terminal)
+		assert.Equal(t, "10.1.1.1", records[1].IpAddr)
+		assert.Equal(t, int32(100), records[1].Pid)
+		assert.Equal(t, int64(1600000100), records[1].Time.Unix())
+		assert.Equal(t, "fe80::1", records[2].IpAddr)
+		assert.Equal(t, "", records[4].IpAddr)
+	}
+}
+
+func TestUtmpDetectAmbiguousSize(t *testing.T) {
+	// 25 glibc records are the same size as 24 musl records so
+	// the layout must be detected from the content.
+	data := []byte{}
+	for i := 0; i < 25; i++ {
+		data = append(data, buildRecord(glibcLayout, testRecord{
+			ut_type: 7, terminal: "tty1", user: "root",
+			time: 1600000000 + int64(i)})...)
+	}
+	assert.Equal(t, LAYOUT_GLIBC,
+		detectLayout(bytes.NewReader(data), int64(len(data))).name)
+}
+
+func TestUtmpSessions(t *testing.T) {
+	data := buildWtmp(glibcLayout)
+	tracker := newSessionTracker()
+	sessions := []*Session{}
+
+	// Simulate a crash after the last login.
+	data = append(data, buildRecord(glibcLayout, testRecord{
+		ut_type: 2, terminal: "~", user: "reboot", time: 1600001000})...)
+	data = append(data, buildRecord(glibcLayout, testRecord{
+		ut_type: 7, terminal: "tty1", user: "root", time: 1600001100})...)
+
+	err := readRecords(bytes.NewReader(data), glibcLayout,
+		func(record *UtmpRecord) bool {
+			sessions = append(sessions, tracker.Add(record)...)
+			return true
+		})
+	require.NoError(t, err)
+	sessions = append(sessions, tracker.Remaining()...)
+
+	require.Equal(t, 4, len(sessions))
+
+	assert.Equal(t, "mike", sessions[0].User)
+	assert.Equal(t, "10.1.1.1", sessions[0].IpAddr)
+	assert.Equal(t, SESSION_LOGGED_OUT, sessions[0].Status)
+	assert.Equal(t, 300*time.Second, sessions[0].Duration)
+
+	// Both sessions were open when the system rebooted.
+	assert.Equal(t, "root", sessions[1].User)
+	assert.Equal(t, SESSION_CRASH, sessions[1].Status)
+	assert.Equal(t, "mike", sessions[2].User)
+	assert.Equal(t, SESSION_CRASH, sessions[2].Status)
+	assert.Equal(t, int64(1600001000), sessions[2].LogoutTime.Unix())
+
+	assert.Equal(t, "tty1", sessions[3].Terminal)
+	assert.Equal(t, SESSION_ACTIVE, sessions[3].Status)
+}
+
+func TestLastlog(t *testing.T) {
+	for _, record_size := range []int64{LASTLOG_GLIBC_SIZE, LASTLOG_MUSL_SIZE} {
+		time_size := record_size - UT_LINESIZE - UT_HOSTSIZE
+
+		// uid 0 and uid 1500 logged in.
+		data := make([]byte, record_size*1501)
+		for _, uid := range []int64{0, 1500} {
+			record := data[uid*record_size:]
+			if time_size == 4 {
+				binary.LittleEndian.PutUint32(record, uint32(1600000000+uid))
+			} else {
+				binary.LittleEndian.PutUint64(record, uint64(1600000000+uid))
+			}
+			copy(record[time_size:], "pts/0")
+			copy(record[time_size+UT_LINESIZE:], "10.1.1.1")
+		}
+
+		size := lastlogRecordSize(LAYOUT_AUTO, int64(len(data)))
+		assert.Equal(t, record_size, size)
+
+		records := []*LastlogRecord{}
+		err := readLastlog(bytes.NewReader(data), size, DEFAULT_MAX_UID,
+			func(record *LastlogRecord) bool {
+				records = append(records, record)
+				return true
+			})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(records))
+
+		assert.Equal(t, uint64(1500), records[1].Uid)
+		assert.Equal(t, int64(1600001500), records[1].Time.Unix())
+		assert.Equal(t, "pts/0", records[1].Terminal)
+		assert.Equal(t, "10.1.1.1", records[1].Host)
+
+		// Uids above max_uid are not reported.
+		records = nil
+		err = readLastlog(bytes.NewReader(data), size, 1000,
+			func(record *LastlogRecord) bool {
+				records = append(records, record)
+				return true
+			})
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(records))
+	}
+}
@@ -0,0 +1,208 @@
+// Health tracking for the file watching event sources.
+
+// Event plugins like watch_syslog() and watch_evtx() run for the
+// lifetime of the client's event table. When the watched file
+// disappears (e.g. rotated or on an unmounted volume) or the watcher
+// encounters an error, the query keeps running but no events are
+// produced. This registry records the state of each active watch so
+// it can be queried with watcher_health() and forwarded to the
+// server by the Generic.Client.WatcherHealth monitoring artifact.
+
+package watchers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	STATE_STARTING = "STARTING"
+	STATE_OK       = "OK"
+
+	// The file can not be found - it may have been renamed or the
+	// volume was unmounted. We keep polling for it to reappear.
+	STATE_MISSING = "MISSING"
+
+	// The watcher encountered an error and will resubscribe.
+	STATE_ERROR = "ERROR"
+)
+
+var (
+	mu       sync.Mutex
+	registry = make(map[string]*WatcherHealth)
+)
+
+type WatcherHealth struct {
+	mu sync.Mutex
+
+	Plugin    string
+	Filename  string
+	Accessor  string
+	State     string
+	LastError string
+
+	// Time of the last error or state change.
+	LastChange time.Time
+
+	// Time we last sent an event to listeners.
+	LastEvent time.Time
+	Events    uint64
+
+	// Number of times the watcher restarted after an error, file
+	// rotation or the file reappearing.
+	Restarts uint64
+
+	Listeners int
+}
+
+func (self *WatcherHealth) SetState(state string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.State != state {
+		self.State = state
+		self.LastChange = utils.GetTime().Now()
+	}
+	if state == STATE_OK {
+		self.LastError = ""
+	}
+}
+
+func (self *WatcherHealth) SetError(err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.State = STATE_ERROR
+	self.LastError = err.Error()
+	self.LastChange = utils.GetTime().Now()
+}
+
+func (self *WatcherHealth) Restarted() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.Restarts++
+}
+
+func (self *WatcherHealth) EventSent() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.Events++
+	self.LastEvent = utils.GetTime().Now()
+}
+
+func (self *WatcherHealth) SetListeners(count int) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.Listeners = count
+}
+
+func (self *WatcherHealth) ToDict() *ordereddict.Dict {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return ordereddict.NewDict().
+		Set("Plugin", self.Plugin).
+		Set("Filename", self.Filename).
+		Set("Accessor", self.Accessor).
+		Set("State", self.State).
+		Set("LastError", self.LastError).
+		Set("LastChange", self.LastChange).
+		Set("LastEvent", self.LastEvent).
+		Set("Events", self.Events).
+		Set("Restarts", self.Restarts).
+		Set("Listeners", self.Listeners)
+}
+
+// Get the health record for a watch, creating it if needed.
+func GetWatcherHealth(
+	plugin, filename, accessor string) *WatcherHealth {
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := plugin + ":" + accessor + ":" + filename
+	result, pres := registry[key]
+	if !pres {
+		result = &WatcherHealth{
+			Plugin:     plugin,
+			Filename:   filename,
+			Accessor:   accessor,
+			State:      STATE_STARTING,
+			LastChange: utils.GetTime().Now(),
+		}
+		registry[key] = result
+	}
+	return result
+}
+
+// Remove the health record when the watch is no longer needed.
+func RemoveWatcherHealth(plugin, filename, accessor string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(registry, plugin+":"+accessor+":"+filename)
+}
+
+func GetAllWatcherHealth() []*ordereddict.Dict {
+	mu.Lock()
+	keys := make([]string, 0, len(registry))
+	for k := range registry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	records := make([]*WatcherHealth, 0, len(keys))
+	for _, k := range keys {
+		records = append(records, registry[k])
+	}
+	mu.Unlock()
+
+	result := make([]*ordereddict.Dict, 0, len(records))
+	for _, r := range records {
+		result = append(result, r.ToDict())
+	}
+	return result
+}
+
+type WatcherHealthPlugin struct{}
+
+func (self WatcherHealthPlugin) Call(
+	ctx context.Context, scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		for _, row := range GetAllWatcherHealth() {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self WatcherHealthPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name: "watcher_health",
+		Doc:  "Report the health of all active file watchers (e.g. watch_syslog(), watch_evtx()).",
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WatcherHealthPlugin{})
+}
@@ -0,0 +1,39 @@
+package watchers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcherHealth(t *testing.T) {
+	health := GetWatcherHealth("watch_syslog", "/var/log/syslog", "file")
+	assert.Equal(t, STATE_STARTING, health.State)
+
+	// Getting the same watch returns the same record.
+	assert.Equal(t, health,
+		GetWatcherHealth("watch_syslog", "/var/log/syslog", "file"))
+
+	health.SetError(errors.New("Too many open files"))
+	health.Restarted()
+
+	records := GetAllWatcherHealth()
+	assert.Equal(t, 1, len(records))
+
+	state, _ := records[0].GetString("State")
+	assert.Equal(t, STATE_ERROR, state)
+
+	last_error, _ := records[0].GetString("LastError")
+	assert.Equal(t, "Too many open files", last_error)
+
+	// Recovering clears the error.
+	health.SetState(STATE_OK)
+	health.EventSent()
+	assert.Equal(t, "", health.LastError)
+	assert.Equal(t, uint64(1), health.Events)
+	assert.Equal(t, uint64(1), health.Restarts)
+
+	RemoveWatcherHealth("watch_syslog", "/var/log/syslog", "file")
+	assert.Equal(t, 0, len(GetAllWatcherHealth()))
+}
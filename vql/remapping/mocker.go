@@ -82,6 +82,16 @@ func NewMockerPlugin(name string, results []*ordereddict.Dict) *MockerPlugin {
 	return result
 }
 
+// A mock plugin which emits all the rows each time it is called.
+func NewMockerPluginFromRows(name string, rows []*ordereddict.Dict) *MockerPlugin {
+	return &MockerPlugin{
+		name: name,
+		ctx: &_MockerCtx{
+			results: []types.Any{rows},
+		},
+	}
+}
+
 func (self MockerPlugin) Call(ctx context.Context,
 	scope types.Scope, args *ordereddict.Dict) <-chan types.Row {
 	output_chan := make(chan types.Row)
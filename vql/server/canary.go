@@ -0,0 +1,307 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type CanaryTokenFunctionArgs struct {
+	Type     string `vfilter:"optional,field=type,doc=The type of token to create (http or dns, default http)."`
+	Memo     string `vfilter:"optional,field=memo,doc=A note describing where the token will be planted."`
+	ClientId string `vfilter:"optional,field=client_id,doc=The client the token will be planted on."`
+}
+
+type CanaryTokenFunction struct{}
+
+func (self *CanaryTokenFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("canarytoken: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CanaryTokenFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("canarytoken: %v", err)
+		return vfilter.Null{}
+	}
+
+	if arg.Type == "" {
+		arg.Type = services.CANARY_TYPE_HTTP
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("canarytoken: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	canary_manager, err := services.GetCanaryManager(config_obj)
+	if err != nil {
+		scope.Log("canarytoken: %v", err)
+		return vfilter.Null{}
+	}
+
+	principal := vql_subsystem.GetPrincipal(scope)
+	token, err := canary_manager.CreateToken(ctx, principal,
+		arg.Type, arg.Memo, arg.ClientId)
+	if err != nil {
+		scope.Log("canarytoken: %v", err)
+		return vfilter.Null{}
+	}
+
+	logging.LogAudit(config_obj, principal, "canarytoken",
+		logrus.Fields{
+			"token_id":  token.TokenId,
+			"type":      token.Type,
+			"memo":      token.Memo,
+			"client_id": token.ClientId,
+		})
+
+	return canaryToRow(token)
+}
+
+func (self CanaryTokenFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "canarytoken",
+		Doc:     "Create a new canary token.",
+		ArgType: type_map.AddType(scope, &CanaryTokenFunctionArgs{}),
+	}
+}
+
+type CanaryTokensPluginArgs struct {
+	TokenId string `vfilter:"optional,field=token_id,doc=Only show this token."`
+}
+
+type CanaryTokensPlugin struct{}
+
+func (self CanaryTokensPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("canary_tokens: %v", err)
+			return
+		}
+
+		arg := &CanaryTokensPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("canary_tokens: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("canary_tokens: Command can only run on the server")
+			return
+		}
+
+		canary_manager, err := services.GetCanaryManager(config_obj)
+		if err != nil {
+			scope.Log("canary_tokens: %v", err)
+			return
+		}
+
+		var tokens []*api_proto.CanaryToken
+		if arg.TokenId != "" {
+			token, err := canary_manager.GetToken(ctx, arg.TokenId)
+			if err != nil {
+				scope.Log("canary_tokens: %v", err)
+				return
+			}
+			tokens = append(tokens, token)
+
+		} else {
+			tokens, err = canary_manager.ListTokens(ctx)
+			if err != nil {
+				scope.Log("canary_tokens: %v", err)
+				return
+			}
+		}
+
+		for _, token := range tokens {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- canaryToRow(token):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self CanaryTokensPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "canary_tokens",
+		Doc:     "List the canary tokens issued by the server.",
+		ArgType: type_map.AddType(scope, &CanaryTokensPluginArgs{}),
+	}
+}
+
+func canaryToRow(token *api_proto.CanaryToken) *ordereddict.Dict {
+	result := ordereddict.NewDict().
+		Set("TokenId", token.TokenId).
+		Set("Type", token.Type).
+		Set("Memo", token.Memo).
+		Set("ClientId", token.ClientId).
+		Set("Principal", token.Principal).
+		Set("Created", time.Unix(int64(token.Created), 0)).
+		Set("Url", token.Url).
+		Set("Hostname", token.Hostname).
+		Set("TriggerCount", token.TriggerCount).
+		Set("LastTriggered", nil).
+		Set("LastSource", token.LastSource)
+
+	if token.LastTriggered > 0 {
+		result.Set("LastTriggered", time.Unix(int64(token.LastTriggered), 0))
+	}
+
+	return result
+}
+
+type CanaryTriggerFunctionArgs struct {
+	Token   string            `vfilter:"required,field=token,doc=The token id or DNS hostname which was accessed."`
+	Source  string            `vfilter:"optional,field=source,doc=Who accessed the token (e.g. the resolver address)."`
+	Details *ordereddict.Dict `vfilter:"optional,field=details,doc=Additional context to include in the alert."`
+}
+
+// Used to report triggers that the server can not observe itself -
+// for example by parsing the DNS query logs of the canary domain.
+type CanaryTriggerFunction struct{}
+
+func (self *CanaryTriggerFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("canary_trigger: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CanaryTriggerFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("canary_trigger: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("canary_trigger: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	canary_manager, err := services.GetCanaryManager(config_obj)
+	if err != nil {
+		scope.Log("canary_trigger: %v", err)
+		return vfilter.Null{}
+	}
+
+	err = canary_manager.TriggerToken(ctx, arg.Token, arg.Source, arg.Details)
+	if err != nil {
+		scope.Log("canary_trigger: %v", err)
+		return vfilter.Null{}
+	}
+
+	return arg.Token
+}
+
+func (self CanaryTriggerFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "canary_trigger",
+		Doc:     "Report that a canary token was accessed.",
+		ArgType: type_map.AddType(scope, &CanaryTriggerFunctionArgs{}),
+	}
+}
+
+type CanaryDeleteFunctionArgs struct {
+	TokenId string `vfilter:"required,field=token_id,doc=The token to delete."`
+}
+
+type CanaryDeleteFunction struct{}
+
+func (self *CanaryDeleteFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("canary_delete: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CanaryDeleteFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("canary_delete: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("canary_delete: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	canary_manager, err := services.GetCanaryManager(config_obj)
+	if err != nil {
+		scope.Log("canary_delete: %v", err)
+		return vfilter.Null{}
+	}
+
+	principal := vql_subsystem.GetPrincipal(scope)
+	logging.LogAudit(config_obj, principal, "canary_delete",
+		logrus.Fields{
+			"token_id": arg.TokenId,
+		})
+
+	err = canary_manager.DeleteToken(ctx, arg.TokenId)
+	if err != nil {
+		scope.Log("canary_delete: %v", err)
+		return vfilter.Null{}
+	}
+
+	return arg.TokenId
+}
+
+func (self CanaryDeleteFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "canary_delete",
+		Doc:     "Delete a canary token.",
+		ArgType: type_map.AddType(scope, &CanaryDeleteFunctionArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&CanaryTokenFunction{})
+	vql_subsystem.RegisterFunction(&CanaryTriggerFunction{})
+	vql_subsystem.RegisterFunction(&CanaryDeleteFunction{})
+	vql_subsystem.RegisterPlugin(&CanaryTokensPlugin{})
+}
@@ -0,0 +1,102 @@
+// +build server_vql
+
+package clients
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/crashes"
+	"www.velocidex.com/golang/velociraptor/json"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type ClientCrashesPluginArgs struct {
+	Version  string `vfilter:"optional,field=version,doc=Only list clients crashing with this version."`
+	ClientId string `vfilter:"optional,field=client_id,doc=If specified, list the full crash reports uploaded by this client."`
+}
+
+type ClientCrashesPlugin struct{}
+
+func (self ClientCrashesPlugin) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("client_crashes: %s", err)
+			return
+		}
+
+		arg := &ClientCrashesPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("client_crashes: %s", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		if arg.ClientId != "" {
+			reports, err := crashes.ListClientCrashReports(
+				config_obj, arg.ClientId)
+			if err != nil {
+				scope.Log("client_crashes: %s", err)
+				return
+			}
+
+			for _, report := range reports {
+				if arg.Version != "" && report.Version != arg.Version {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- json.ConvertProtoToOrderedDict(report):
+				}
+			}
+			return
+		}
+
+		records, err := crashes.ListCrashingClients(config_obj, arg.Version)
+		if err != nil {
+			scope.Log("client_crashes: %s", err)
+			return
+		}
+
+		for _, record := range records {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- json.ConvertProtoToOrderedDict(record):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ClientCrashesPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "client_crashes",
+		Doc:     "List clients which reported crashes, grouped by the version they run.",
+		ArgType: type_map.AddType(scope, &ClientCrashesPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ClientCrashesPlugin{})
+}
@@ -62,114 +62,154 @@ func (self DeleteClientPlugin) Call(ctx context.Context,
 			return
 		}
 
-		db, err := datastore.GetDB(config_obj)
+		err = deleteClient(ctx, config_obj, scope, arg,
+			func(row *ordereddict.Dict) bool {
+				select {
+				case <-ctx.Done():
+					return false
+				case output_chan <- row:
+					return true
+				}
+			})
 		if err != nil {
-			return
+			scope.Log("client_delete: %s", err)
 		}
+	}()
 
-		file_store_factory := file_store.GetFileStore(config_obj)
-		client_path_manager := paths.NewClientPathManager(arg.ClientId)
+	return output_chan
+}
 
-		// Indiscriminately delete all the client's datastore files.
-		err = datastore.Walk(config_obj, db, client_path_manager.Path(),
-			datastore.WalkWithoutDirectories,
-			func(filename api.DSPathSpec) error {
-				select {
-				case <-ctx.Done():
-					return nil
+// DeleteClient removes all the client's datastore and filestore
+// records. Unlike the client_delete() plugin, which only logs
+// failures, it returns the first error encountered.
+func DeleteClient(ctx context.Context,
+	config_obj *config_proto.Config, scope vfilter.Scope,
+	client_id string) error {
+	if !constants.ClientIdRegex.MatchString(client_id) {
+		return errors.New("Client Id should be of the form C.XXXX")
+	}
 
-				case output_chan <- ordereddict.NewDict().
-					Set("client_id", arg.ClientId).
-					Set("type", "Datastore").
-					Set("vfs_path", filename.AsClientPath()).
-					Set("really_do_it", arg.ReallyDoIt):
-				}
+	return deleteClient(ctx, config_obj, scope, &DeleteClientArgs{
+		ClientId:   client_id,
+		ReallyDoIt: true,
+	}, func(row *ordereddict.Dict) bool { return true })
+}
 
-				if arg.ReallyDoIt {
-					err := db.DeleteSubject(config_obj, filename)
-					if err != nil && errors.Is(err, os.ErrNotExist) {
-						scope.Log("client_delete: while deleting %v: %s",
-							filename, err)
-					}
-				}
-				return nil
-			})
-		if err != nil {
-			scope.Log("client_delete: %s", err.Error())
-			return
+// Each deleted record is passed to emit, which returns false if the
+// caller is no longer interested. Failures to delete individual
+// records are logged and the first one is returned after all the
+// other records were processed.
+func deleteClient(ctx context.Context,
+	config_obj *config_proto.Config, scope vfilter.Scope,
+	arg *DeleteClientArgs, emit func(row *ordereddict.Dict) bool) error {
+
+	var first_err error
+	record_error := func(err error) {
+		if first_err == nil {
+			first_err = err
 		}
+	}
 
-		// Delete the filestore files.
-		err = api.Walk(file_store_factory,
-			client_path_manager.Path().AsFilestorePath(),
-			func(filename api.FSPathSpec, info os.FileInfo) error {
-				select {
-				case <-ctx.Done():
-					return nil
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
 
-				case output_chan <- ordereddict.NewDict().
-					Set("client_id", arg.ClientId).
-					Set("type", "Filestore").
-					Set("vfs_path", filename.AsClientPath()).
-					Set("really_do_it", arg.ReallyDoIt):
-				}
+	file_store_factory := file_store.GetFileStore(config_obj)
+	client_path_manager := paths.NewClientPathManager(arg.ClientId)
 
-				if arg.ReallyDoIt {
-					err := file_store_factory.Delete(filename)
-					if err != nil {
-						scope.Log("client_delete: while deleting %v: %s",
-							filename, err)
-					}
-				}
+	// Indiscriminately delete all the client's datastore files.
+	err = datastore.Walk(config_obj, db, client_path_manager.Path(),
+		datastore.WalkWithoutDirectories,
+		func(filename api.DSPathSpec) error {
+			if !emit(ordereddict.NewDict().
+				Set("client_id", arg.ClientId).
+				Set("type", "Datastore").
+				Set("vfs_path", filename.AsClientPath()).
+				Set("really_do_it", arg.ReallyDoIt)) {
 				return nil
-			})
-		if err != nil {
-			scope.Log("client_delete: %s", err)
-			return
-		}
+			}
 
-		// Remove the empty directories
-		err = datastore.Walk(config_obj, db, client_path_manager.Path(),
-			datastore.WalkWithDirectories,
-			func(filename api.DSPathSpec) error {
+			if arg.ReallyDoIt {
 				err := db.DeleteSubject(config_obj, filename)
-				if err != nil {
-					scope.Log("client_delete: Removig directory %v: %v",
-						filename.AsClientPath(), err)
+				if err != nil && !errors.Is(err, os.ErrNotExist) {
+					scope.Log("client_delete: while deleting %v: %s",
+						filename, err)
+					record_error(err)
 				}
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	// Delete the filestore files.
+	err = api.Walk(file_store_factory,
+		client_path_manager.Path().AsFilestorePath(),
+		func(filename api.FSPathSpec, info os.FileInfo) error {
+			if !emit(ordereddict.NewDict().
+				Set("client_id", arg.ClientId).
+				Set("type", "Filestore").
+				Set("vfs_path", filename.AsClientPath()).
+				Set("really_do_it", arg.ReallyDoIt)) {
 				return nil
-			})
+			}
 
-		// Delete the actual client record.
-		if arg.ReallyDoIt {
-			err = reallyDeleteClient(ctx, config_obj, scope, db, arg)
-			if err != nil {
-				scope.Log("client_delete: %s", err)
-				return
+			if arg.ReallyDoIt {
+				err := file_store_factory.Delete(filename)
+				if err != nil {
+					scope.Log("client_delete: while deleting %v: %s",
+						filename, err)
+					record_error(err)
+				}
 			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
 
-			// Finally remove the containing directory
-			err = db.DeleteSubject(
-				config_obj,
-				paths.NewClientPathManager(arg.ClientId).Path().SetDir())
+	// Remove the empty directories
+	_ = datastore.Walk(config_obj, db, client_path_manager.Path(),
+		datastore.WalkWithDirectories,
+		func(filename api.DSPathSpec) error {
+			err := db.DeleteSubject(config_obj, filename)
 			if err != nil {
-				scope.Log("client_delete: %s", err)
+				scope.Log("client_delete: Removig directory %v: %v",
+					filename.AsClientPath(), err)
 			}
+			return nil
+		})
+
+	// Delete the actual client record.
+	if arg.ReallyDoIt {
+		err = reallyDeleteClient(ctx, config_obj, scope, db, arg)
+		if err != nil {
+			return err
 		}
 
-		// Notify the client to force it to disconnect in case
-		// it is already up.
-		notifier, err := services.GetNotifier(config_obj)
-		if err == nil {
-			err = notifier.NotifyListener(
-				config_obj, arg.ClientId, "DeleteClient")
-			if err != nil {
-				scope.Log("client_delete: %s", err)
-			}
+		// Finally remove the containing directory
+		err = db.DeleteSubject(
+			config_obj,
+			paths.NewClientPathManager(arg.ClientId).Path().SetDir())
+		if err != nil {
+			scope.Log("client_delete: %s", err)
 		}
-	}()
+	}
 
-	return output_chan
+	// Notify the client to force it to disconnect in case
+	// it is already up.
+	notifier, err := services.GetNotifier(config_obj)
+	if err == nil {
+		err = notifier.NotifyListener(
+			config_obj, arg.ClientId, "DeleteClient")
+		if err != nil {
+			scope.Log("client_delete: %s", err)
+		}
+	}
+
+	return first_err
 }
 
 func reallyDeleteClient(ctx context.Context,
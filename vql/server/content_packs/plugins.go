@@ -0,0 +1,476 @@
+package content_packs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/kape"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+// Describes the event artifacts a pack wants enabled.
+type monitoringConfig struct {
+	Client []string `json:"client"`
+	Server []string `json:"server"`
+}
+
+func getInstalledRecord(config_obj *config_proto.Config,
+	name string) *api_proto.ContentPackRecord {
+	record := &api_proto.ContentPackRecord{}
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return record
+	}
+
+	path_manager := paths.NewContentPackPathManager(name)
+	_ = db.GetSubject(config_obj, path_manager.Record(), record)
+	return record
+}
+
+type ContentPacksPluginArgs struct {
+	Registry string `vfilter:"optional,field=registry,doc=Only list packs from this registry (default all configured registries)."`
+}
+
+type ContentPacksPlugin struct{}
+
+func (self ContentPacksPlugin) Call(
+	ctx context.Context, scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("content_packs: %v", err)
+			return
+		}
+
+		arg := &ContentPacksPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("content_packs: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		for _, registry := range getRegistries(config_obj) {
+			if arg.Registry != "" && arg.Registry != registry.Name {
+				continue
+			}
+
+			index, err := FetchIndex(ctx, config_obj, registry)
+			if err != nil {
+				scope.Log("content_packs: %v", err)
+				continue
+			}
+
+			for _, pack := range index.Packs {
+				installed := getInstalledRecord(config_obj, pack.Name)
+				update_available := installed.Version != "" &&
+					installed.Registry == registry.Name &&
+					installed.Version != pack.Version
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- ordereddict.NewDict().
+					Set("Registry", registry.Name).
+					Set("Name", pack.Name).
+					Set("Version", pack.Version).
+					Set("Description", pack.Description).
+					Set("Author", pack.Author).
+					Set("Signed", pack.Signature != "").
+					Set("InstalledVersion", installed.Version).
+					Set("UpdateAvailable", update_available):
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ContentPacksPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "content_packs",
+		Doc:     "List content packs available from the configured registries.",
+		ArgType: type_map.AddType(scope, &ContentPacksPluginArgs{}),
+	}
+}
+
+type ContentPackArgs struct {
+	Registry      string `vfilter:"required,field=registry,doc=The name of the registry."`
+	Name          string `vfilter:"required,field=name,doc=The name of the content pack."`
+	AllowUnsigned bool   `vfilter:"optional,field=allow_unsigned,doc=Allow packs without a signature (the hash is still checked)."`
+}
+
+func fetchContentPack(ctx context.Context,
+	config_obj *config_proto.Config, arg *ContentPackArgs) (
+	*PackDescriptor, *config_proto.ContentRegistryConfig, *zip.Reader, error) {
+	registry, err := getRegistry(config_obj, arg.Registry)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	index, err := FetchIndex(ctx, config_obj, registry)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pack, err := FindPack(index, arg.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	data, err := FetchPack(ctx, config_obj, registry, pack, arg.AllowUnsigned)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return pack, registry, reader, nil
+}
+
+func readMember(member *zip.File) ([]byte, error) {
+	fd, err := member.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	return io.ReadAll(fd)
+}
+
+// Classify a zip member by where it will be installed.
+func memberType(name string) string {
+	switch {
+	case name == "monitoring.json":
+		return "monitoring"
+	case strings.HasPrefix(name, "artifacts/") &&
+		(strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")):
+		return "artifact"
+	case strings.HasPrefix(name, "rules/") && !strings.HasSuffix(name, "/"):
+		return "rule"
+	case strings.HasPrefix(name, "kape/") &&
+		(strings.HasSuffix(strings.ToLower(name), ".tkape") ||
+			strings.HasSuffix(strings.ToLower(name), ".mkape")):
+		return "kape"
+	}
+	return ""
+}
+
+type ContentPackPreviewPlugin struct{}
+
+func (self ContentPackPreviewPlugin) Call(
+	ctx context.Context, scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("content_pack_preview: %v", err)
+			return
+		}
+
+		arg := &ContentPackArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("content_pack_preview: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		_, _, reader, err := fetchContentPack(ctx, config_obj, arg)
+		if err != nil {
+			scope.Log("content_pack_preview: %v", err)
+			return
+		}
+
+		for _, member := range reader.File {
+			member_type := memberType(member.Name)
+			if member_type == "" {
+				continue
+			}
+
+			row := ordereddict.NewDict().
+				Set("Name", member.Name).
+				Set("Type", member_type).
+				Set("Size", member.UncompressedSize64)
+
+			// Show the content of definitions so they can be
+			// reviewed before installing.
+			if member_type != "rule" {
+				data, err := readMember(member)
+				if err != nil {
+					scope.Log("content_pack_preview: %v", err)
+					continue
+				}
+				row.Set("Content", string(data))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ContentPackPreviewPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "content_pack_preview",
+		Doc:     "Download and verify a content pack and list its contents without installing it.",
+		ArgType: type_map.AddType(scope, &ContentPackArgs{}),
+	}
+}
+
+type ContentPackInstallFunction struct{}
+
+func (self ContentPackInstallFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("content_pack_install: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &ContentPackArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("content_pack_install: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	pack, registry, reader, err := fetchContentPack(ctx, config_obj, arg)
+	if err != nil {
+		scope.Log("content_pack_install: %v", err)
+		return vfilter.Null{}
+	}
+
+	principal := vql_subsystem.GetPrincipal(scope)
+	record := &api_proto.ContentPackRecord{
+		Name:          pack.Name,
+		Registry:      registry.Name,
+		Version:       pack.Version,
+		InstalledTime: uint64(utils.GetTime().Now().Unix()),
+		Principal:     principal,
+	}
+
+	err = installPack(ctx, config_obj, principal, registry, reader, record)
+	if err != nil {
+		scope.Log("content_pack_install: %v", err)
+		return vfilter.Null{}
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		scope.Log("content_pack_install: %v", err)
+		return vfilter.Null{}
+	}
+
+	path_manager := paths.NewContentPackPathManager(pack.Name)
+	err = db.SetSubject(config_obj, path_manager.Record(), record)
+	if err != nil {
+		scope.Log("content_pack_install: %v", err)
+		return vfilter.Null{}
+	}
+
+	return record
+}
+
+func installPack(ctx context.Context,
+	config_obj *config_proto.Config, principal string,
+	registry *config_proto.ContentRegistryConfig,
+	reader *zip.Reader, record *api_proto.ContentPackRecord) error {
+
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return err
+	}
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	path_manager := paths.NewContentPackPathManager(record.Name)
+
+	monitoring := &monitoringConfig{}
+
+	for _, member := range reader.File {
+		member_type := memberType(member.Name)
+		if member_type == "" {
+			continue
+		}
+
+		data, err := readMember(member)
+		if err != nil {
+			return err
+		}
+
+		switch member_type {
+		case "artifact":
+			definition, err := manager.SetArtifactFile(
+				config_obj, principal, string(data), registry.Prefix)
+			if err != nil {
+				return err
+			}
+			record.Artifacts = append(record.Artifacts, definition.Name)
+
+		// KAPE targets and modules are converted to artifacts so
+		// updating the pack updates the converted artifacts.
+		case "kape":
+			artifact, err := kape.Convert(member.Name, data,
+				kape.Options{Prefix: registry.Prefix})
+			if err != nil {
+				return err
+			}
+
+			definition, err := manager.SetArtifactFile(
+				config_obj, principal, artifact.Yaml, registry.Prefix)
+			if err != nil {
+				return err
+			}
+			record.Artifacts = append(record.Artifacts, definition.Name)
+
+		case "rule":
+			name := strings.TrimPrefix(path.Clean(member.Name), "rules/")
+			writer, err := file_store_factory.WriteFile(path_manager.File(name))
+			if err != nil {
+				return err
+			}
+			_ = writer.Truncate()
+			_, err = writer.Write(data)
+			writer.Close()
+			if err != nil {
+				return err
+			}
+			record.Files = append(record.Files, name)
+
+		case "monitoring":
+			err = json.Unmarshal(data, monitoring)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return enableMonitoring(ctx, config_obj, principal, monitoring)
+}
+
+func addArtifact(config *flows_proto.ArtifactCollectorArgs, name string) {
+	if utils.InString(config.Artifacts, name) {
+		return
+	}
+
+	config.Artifacts = append(config.Artifacts, name)
+	config.Specs = append(config.Specs, &flows_proto.ArtifactSpec{
+		Artifact:   name,
+		Parameters: &flows_proto.ArtifactParameters{},
+	})
+}
+
+// Add the pack's event artifacts to the monitoring tables.
+func enableMonitoring(ctx context.Context,
+	config_obj *config_proto.Config, principal string,
+	monitoring *monitoringConfig) error {
+	if len(monitoring.Client) > 0 {
+		client_event_manager, err := services.ClientEventManager(config_obj)
+		if err != nil {
+			return err
+		}
+
+		event_config := client_event_manager.GetClientMonitoringState()
+		if event_config.Artifacts == nil {
+			event_config.Artifacts = &flows_proto.ArtifactCollectorArgs{}
+		}
+		for _, name := range monitoring.Client {
+			addArtifact(event_config.Artifacts, name)
+		}
+
+		err = client_event_manager.SetClientMonitoringState(
+			ctx, config_obj, principal, event_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(monitoring.Server) > 0 {
+		server_event_manager, err := services.GetServerEventManager(config_obj)
+		if err != nil {
+			return err
+		}
+
+		event_config := server_event_manager.Get()
+		for _, name := range monitoring.Server {
+			addArtifact(event_config, name)
+		}
+
+		err = server_event_manager.Update(config_obj, principal, event_config)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (self ContentPackInstallFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "content_pack_install",
+		Doc:     "Download, verify and install a content pack from a registry.",
+		ArgType: type_map.AddType(scope, &ContentPackArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ContentPacksPlugin{})
+	vql_subsystem.RegisterPlugin(&ContentPackPreviewPlugin{})
+	vql_subsystem.RegisterFunction(&ContentPackInstallFunction{})
+}
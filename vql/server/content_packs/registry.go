@@ -0,0 +1,224 @@
+// A client for curated content pack registries.
+
+// A registry is a plain HTTP(S) server configured under
+// Defaults.content_registries. It serves an index.json at its base
+// URL listing the available packs:
+
+// {"packs": [{"name": "...", "version": "...", "description": "...",
+//             "author": "...", "url": "packs/foo.zip",
+//             "sha256": "<hex>", "signature": "<base64>"}]}
+
+// Each pack is a zip file containing any of:
+//   artifacts/*.yaml  - artifact definitions (including notebook templates)
+//   rules/*           - yara or sigma rule sets stored in the filestore
+//   monitoring.json   - {"client": ["Artifact.Name"], "server": [...]}
+//                       event artifacts to enable on install.
+
+// The signature is an RSA PKCS1v15 signature over the SHA256 of the
+// zip file made with the registry's private key. Packs are only
+// installed when the signature verifies against the registry's
+// configured public key.
+
+package content_packs
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	crypto_utils "www.velocidex.com/golang/velociraptor/crypto/utils"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+)
+
+const (
+	// Packs are small - refuse to download anything larger.
+	MAX_PACK_SIZE = 50 * 1024 * 1024
+)
+
+type PackDescriptor struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+	URL         string `json:"url"`
+	Sha256      string `json:"sha256"`
+	Signature   string `json:"signature"`
+}
+
+type RegistryIndex struct {
+	Packs []*PackDescriptor `json:"packs"`
+}
+
+func getRegistry(config_obj *config_proto.Config,
+	name string) (*config_proto.ContentRegistryConfig, error) {
+	if config_obj.Defaults != nil {
+		for _, registry := range config_obj.Defaults.ContentRegistries {
+			if registry.Name == name {
+				return registry, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Content registry %v is not defined", name)
+}
+
+func getRegistries(
+	config_obj *config_proto.Config) []*config_proto.ContentRegistryConfig {
+	if config_obj.Defaults == nil {
+		return nil
+	}
+	return config_obj.Defaults.ContentRegistries
+}
+
+func resolveURL(registry *config_proto.ContentRegistryConfig,
+	ref string) (string, error) {
+	base, err := url.Parse(strings.TrimSuffix(registry.Url, "/") + "/")
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(rel).String(), nil
+}
+
+func fetch(ctx context.Context, config_obj *config_proto.Config,
+	target string) ([]byte, error) {
+	client, err := networking.GetDefaultHTTPClient(config_obj.Client, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fetching %v: %v", target, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MAX_PACK_SIZE+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MAX_PACK_SIZE {
+		return nil, fmt.Errorf("Fetching %v: response too large", target)
+	}
+
+	return data, nil
+}
+
+func FetchIndex(ctx context.Context, config_obj *config_proto.Config,
+	registry *config_proto.ContentRegistryConfig) (*RegistryIndex, error) {
+	target, err := resolveURL(registry, "index.json")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fetch(ctx, config_obj, target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RegistryIndex{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, fmt.Errorf("Registry %v: invalid index: %w",
+			registry.Name, err)
+	}
+
+	return result, nil
+}
+
+func FindPack(index *RegistryIndex, name string) (*PackDescriptor, error) {
+	for _, pack := range index.Packs {
+		if pack.Name == name {
+			return pack, nil
+		}
+	}
+	return nil, fmt.Errorf("Content pack %v not found", name)
+}
+
+// Download the pack and verify its hash and signature. If
+// allow_unsigned is set, the signature is not required but the hash
+// must still match.
+func FetchPack(ctx context.Context, config_obj *config_proto.Config,
+	registry *config_proto.ContentRegistryConfig,
+	pack *PackDescriptor, allow_unsigned bool) ([]byte, error) {
+	target, err := resolveURL(registry, pack.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fetch(ctx, config_obj, target)
+	if err != nil {
+		return nil, err
+	}
+
+	err = VerifyPack(registry, pack, data, allow_unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func VerifyPack(registry *config_proto.ContentRegistryConfig,
+	pack *PackDescriptor, data []byte, allow_unsigned bool) error {
+	hash := sha256.Sum256(data)
+	if pack.Sha256 != "" {
+		expected, err := hex.DecodeString(pack.Sha256)
+		if err != nil || !bytes.Equal(expected, hash[:]) {
+			return fmt.Errorf("Content pack %v: hash mismatch", pack.Name)
+		}
+	}
+
+	if registry.PublicKey == "" || pack.Signature == "" {
+		if allow_unsigned {
+			return nil
+		}
+		return fmt.Errorf("Content pack %v: pack is not signed", pack.Name)
+	}
+
+	public_key, err := crypto_utils.PemToPublicKey([]byte(registry.PublicKey))
+	if err != nil {
+		return fmt.Errorf("Registry %v: invalid public key: %w",
+			registry.Name, err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(pack.Signature)
+	if err != nil {
+		return fmt.Errorf("Content pack %v: invalid signature: %w",
+			pack.Name, err)
+	}
+
+	err = rsa.VerifyPKCS1v15(public_key, crypto.SHA256, hash[:], signature)
+	if err != nil {
+		return errors.New("Content pack " + pack.Name +
+			": signature verification failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package content_packs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+)
+
+func TestVerifyPack(t *testing.T) {
+	private_key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	public_pem := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(&private_key.PublicKey),
+	})
+
+	registry := &config_proto.ContentRegistryConfig{
+		Name:      "test",
+		PublicKey: string(public_pem),
+	}
+
+	data := []byte("pack data")
+	hash := sha256.Sum256(data)
+	signature, err := rsa.SignPKCS1v15(
+		rand.Reader, private_key, crypto.SHA256, hash[:])
+	require.NoError(t, err)
+
+	pack := &PackDescriptor{
+		Name:      "Pack",
+		Sha256:    hex.EncodeToString(hash[:]),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	}
+
+	assert.NoError(t, VerifyPack(registry, pack, data, false))
+
+	// Tampered data fails the hash check.
+	assert.Error(t, VerifyPack(registry, pack, []byte("tampered"), false))
+
+	// Without a hash the signature still protects the data.
+	pack.Sha256 = ""
+	assert.Error(t, VerifyPack(registry, pack, []byte("tampered"), false))
+
+	// Unsigned packs require allow_unsigned.
+	pack.Signature = ""
+	assert.Error(t, VerifyPack(registry, pack, data, false))
+	assert.NoError(t, VerifyPack(registry, pack, data, true))
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type DatastorePluginArgs struct {
+	Path    string `vfilter:"optional,field=path,doc=The datastore path to list or read (default the root of the org's datastore)."`
+	Recurse bool   `vfilter:"optional,field=recurse,doc=Also list the records in all subdirectories."`
+	Read    bool   `vfilter:"optional,field=read,doc=Also read the data of the records."`
+}
+
+type DatastorePlugin struct{}
+
+func (self DatastorePlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+		if err != nil {
+			scope.Log("datastore: %v", err)
+			return
+		}
+
+		arg := &DatastorePluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("datastore: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		db, err := datastore.GetDB(config_obj)
+		if err != nil {
+			scope.Log("datastore: %v", err)
+			return
+		}
+
+		// Records may contain sensitive server state.
+		principal := vql_subsystem.GetPrincipal(scope)
+		logging.LogAudit(config_obj, principal, "datastore",
+			logrus.Fields{
+				"path":    arg.Path,
+				"recurse": arg.Recurse,
+				"read":    arg.Read,
+			})
+
+		emit := func(urn api.DSPathSpec) error {
+			row := ordereddict.NewDict().
+				Set("Path", urn.AsClientPath()).
+				Set("Name", urn.Base()).
+				Set("IsDir", urn.IsDir()).
+				Set("Type", datastoreRecordType(urn))
+
+			if arg.Read && !urn.IsDir() {
+				row.Set("Data", readDatastoreRecord(config_obj, db, urn))
+			}
+
+			select {
+			case <-ctx.Done():
+				return datastore.StopIteration
+			case output_chan <- row:
+			}
+			return nil
+		}
+
+		// Paths with a datastore extension refer to a single
+		// record, otherwise they are directories.
+		urn := paths.DSPathSpecFromClientPath(arg.Path)
+		if len(urn.Components()) > 0 {
+			switch urn.Type() {
+			case api.PATH_TYPE_DATASTORE_JSON, api.PATH_TYPE_DATASTORE_PROTO:
+				_ = emit(urn)
+				return
+			}
+		}
+
+		if arg.Recurse {
+			err = datastore.Walk(config_obj, db, urn, true, emit)
+			if err != nil {
+				scope.Log("datastore: %v", err)
+			}
+			return
+		}
+
+		children, err := db.ListChildren(config_obj, urn)
+		if err != nil {
+			scope.Log("datastore: %v", err)
+			return
+		}
+
+		for _, child := range children {
+			if emit(child) != nil {
+				return
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func datastoreRecordType(urn api.DSPathSpec) string {
+	if urn.IsDir() {
+		return "directory"
+	}
+
+	switch urn.Type() {
+	case api.PATH_TYPE_DATASTORE_JSON:
+		return "json"
+	case api.PATH_TYPE_DATASTORE_PROTO:
+		return "proto"
+	}
+	return ""
+}
+
+// JSON records are decoded. Protobuf records can not be decoded
+// without knowing their type so they are returned as raw bytes.
+func readDatastoreRecord(config_obj *config_proto.Config,
+	db datastore.DataStore, urn api.DSPathSpec) vfilter.Any {
+	raw_db, ok := db.(datastore.RawDataStore)
+	if !ok {
+		return vfilter.Null{}
+	}
+
+	data, err := raw_db.GetBuffer(config_obj, urn)
+	if err != nil {
+		return vfilter.Null{}
+	}
+
+	if urn.Type() == api.PATH_TYPE_DATASTORE_JSON {
+		result := ordereddict.NewDict()
+		err = json.Unmarshal(data, result)
+		if err == nil {
+			return result
+		}
+	}
+
+	return data
+}
+
+func (self DatastorePlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "datastore",
+		Doc:     "List and read the raw records in the server's datastore.",
+		ArgType: type_map.AddType(scope, &DatastorePluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&DatastorePlugin{})
+}
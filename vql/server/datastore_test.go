@@ -0,0 +1,103 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vql/server"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type DatastoreTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *DatastoreTestSuite) SetupTest() {
+	self.TestSuite.SetupTest()
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	for _, name := range []string{"a", "b"} {
+		require.NoError(self.T(), db.SetSubject(self.ConfigObj,
+			path_specs.NewUnsafeDatastorePath("datastore_test", name).
+				SetType(api.PATH_TYPE_DATASTORE_JSON),
+			&actions_proto.ClientInfo{ClientId: "C." + name}))
+	}
+}
+
+func (self *DatastoreTestSuite) runPlugin(
+	acl_manager vql_subsystem.ACLManager, args *ordereddict.Dict) []vfilter.Row {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_manager,
+		Logger: logging.NewPlainLogger(self.ConfigObj,
+			&logging.FrontendComponent),
+		Env: ordereddict.NewDict(),
+	})
+	defer scope.Close()
+
+	return vtesting.RunPlugin(server.DatastorePlugin{}.Call(
+		self.Ctx, scope, args))
+}
+
+func (self *DatastoreTestSuite) TestListAndRead() {
+	rows := self.runPlugin(acl_managers.NullACLManager{},
+		ordereddict.NewDict().
+			Set("path", "/datastore_test").
+			Set("read", true))
+	require.Equal(self.T(), 2, len(rows))
+
+	records := make(map[string]*ordereddict.Dict)
+	for _, row := range rows {
+		name, _ := row.(*ordereddict.Dict).GetString("Name")
+		records[name] = row.(*ordereddict.Dict)
+	}
+
+	row, pres := records["a"]
+	require.True(self.T(), pres)
+
+	record_type, _ := row.GetString("Type")
+	assert.Equal(self.T(), "json", record_type)
+
+	// JSON records are decoded.
+	data, _ := row.Get("Data")
+	client_id, _ := data.(*ordereddict.Dict).GetString("clientId")
+	assert.Equal(self.T(), "C.a", client_id)
+
+	// A single record is read directly.
+	rows = self.runPlugin(acl_managers.NullACLManager{},
+		ordereddict.NewDict().
+			Set("path", "/datastore_test/b.json.db"))
+	require.Equal(self.T(), 1, len(rows))
+
+	name, _ := rows[0].(*ordereddict.Dict).GetString("Name")
+	assert.Equal(self.T(), "b", name)
+}
+
+func (self *DatastoreTestSuite) TestRequiresServerAdmin() {
+	rows := self.runPlugin(
+		acl_managers.NewRoleACLManager(self.ConfigObj, "reader"),
+		ordereddict.NewDict().Set("path", "/datastore_test"))
+	assert.Equal(self.T(), 0, len(rows))
+}
+
+func TestDatastorePlugin(t *testing.T) {
+	suite.Run(t, &DatastoreTestSuite{})
+}
@@ -0,0 +1,130 @@
+package downloads
+
+// Materialize query results into an SQLite database. The database is
+// stored with the scope's uploader - in a notebook this is the cell's
+// uploads and in a server artifact the collection's uploads - so it
+// can be downloaded and used by other tools.
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/reporting"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type SQLiteWriteArgs struct {
+	Query vfilter.StoredQuery `vfilter:"required,field=query,doc=Source for rows to write."`
+	Table string              `vfilter:"optional,field=table,doc=The table to write the rows into (default results)."`
+	Name  string              `vfilter:"optional,field=name,doc=The name to store the database as (default results.sqlite)."`
+}
+
+type SQLiteWriteFunction struct{}
+
+func (self SQLiteWriteFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+	err := vql_subsystem.CheckAccess(scope, acls.PREPARE_RESULTS)
+	if err != nil {
+		scope.Log("sqlite_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &SQLiteWriteArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("sqlite_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	if arg.Table == "" {
+		arg.Table = "results"
+	}
+
+	if arg.Name == "" {
+		arg.Name = "results.sqlite"
+	}
+
+	uploader, ok := artifacts.GetUploader(scope)
+	if !ok {
+		scope.Log("sqlite_write: Uploader not configured.")
+		return vfilter.Null{}
+	}
+
+	// The sqlite library needs a real file so build the database
+	// in a temp file first.
+	tmpfile, err := ioutil.TempFile("", "tmp*.sqlite")
+	if err != nil {
+		scope.Log("sqlite_write: %v", err)
+		return vfilter.Null{}
+	}
+	tmpfile.Close()
+	defer os.Remove(tmpfile.Name())
+
+	writer, err := reporting.NewSQLiteWriter(tmpfile.Name())
+	if err != nil {
+		scope.Log("sqlite_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	total_rows, err := writer.WriteTable(ctx, arg.Table, arg.Query.Eval(ctx, scope))
+	if err != nil {
+		writer.Close()
+		scope.Log("sqlite_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		scope.Log("sqlite_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	fd, err := os.Open(tmpfile.Name())
+	if err != nil {
+		scope.Log("sqlite_write: %v", err)
+		return vfilter.Null{}
+	}
+	defer fd.Close()
+
+	stat, err := fd.Stat()
+	if err != nil {
+		scope.Log("sqlite_write: %v", err)
+		return vfilter.Null{}
+	}
+
+	name := accessors.MustNewGenericOSPath(arg.Name)
+	now := Clock.Now()
+	upload_response, err := uploader.Upload(ctx, scope,
+		name, "", name, stat.Size(), now, now, now, now, fd)
+	if err != nil {
+		return &uploads.UploadResponse{
+			Error: err.Error(),
+		}
+	}
+
+	scope.Log("sqlite_write: Wrote %v rows into table %v of %v",
+		total_rows, arg.Table, arg.Name)
+	return upload_response
+}
+
+func (self SQLiteWriteFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name: "sqlite_write",
+		Doc: "Write the results of a query into an SQLite database and " +
+			"upload it (e.g. into the notebook cell).",
+		ArgType: type_map.AddType(scope, &SQLiteWriteArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&SQLiteWriteFunction{})
+}
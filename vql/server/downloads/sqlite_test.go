@@ -0,0 +1,84 @@
+package downloads
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/jmoiron/sqlx"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/path_specs"
+	"www.velocidex.com/golang/velociraptor/file_store/uploader"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vtesting/assert"
+	"www.velocidex.com/golang/vfilter"
+)
+
+func (self *TestSuite) TestSQLiteWrite() {
+	manager, _ := services.GetRepositoryManager(self.ConfigObj)
+
+	file_store_factory := file_store.GetFileStore(self.ConfigObj)
+	root_path := path_specs.NewUnsafeFilestorePath("test")
+
+	builder := services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger:     logging.NewPlainLogger(self.ConfigObj, &logging.FrontendComponent),
+		Env: ordereddict.NewDict().
+			Set(constants.SCOPE_UPLOADER, uploader.NewFileStoreUploader(
+				self.ConfigObj, file_store_factory, root_path)).
+			Set("Rows", []*ordereddict.Dict{
+				ordereddict.NewDict().Set("A", 1).Set("B", "Hello"),
+				ordereddict.NewDict().Set("A", 2).Set("B", "World"),
+			}),
+	}
+
+	ctx := context.Background()
+	scope := manager.BuildScope(builder)
+	defer scope.Close()
+
+	vql, err := vfilter.Parse(`
+SELECT sqlite_write(name="test.sqlite", table="Test",
+   query={ SELECT * FROM foreach(row=Rows) }) AS Upload
+FROM scope()`)
+	assert.NoError(self.T(), err)
+
+	var response *uploads.UploadResponse
+	for row := range vql.Eval(ctx, scope) {
+		upload, _ := scope.Associative(row, "Upload")
+		response, _ = upload.(*uploads.UploadResponse)
+	}
+	assert.NotNil(self.T(), response)
+	assert.Equal(self.T(), "", response.Error)
+	assert.True(self.T(), response.Size > 0)
+
+	// Copy the database out of the filestore and check the rows.
+	fd, err := file_store_factory.ReadFile(root_path.AddUnsafeChild("test.sqlite"))
+	assert.NoError(self.T(), err)
+	defer fd.Close()
+
+	data, err := ioutil.ReadAll(fd)
+	assert.NoError(self.T(), err)
+
+	tmpfile, err := ioutil.TempFile("", "tmp*.sqlite")
+	assert.NoError(self.T(), err)
+	defer os.Remove(tmpfile.Name())
+
+	_, err = tmpfile.Write(data)
+	assert.NoError(self.T(), err)
+	tmpfile.Close()
+
+	db, err := sqlx.Connect("sqlite3", tmpfile.Name())
+	assert.NoError(self.T(), err)
+	defer db.Close()
+
+	var values []string
+	err = db.Select(&values, "SELECT B FROM Test ORDER BY A")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{"Hello", "World"}, values)
+}
@@ -58,6 +58,7 @@ import (
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/crypto"
 	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services/rate_limiter"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	vfilter "www.velocidex.com/golang/vfilter"
 	"www.velocidex.com/golang/vfilter/arg_parser"
@@ -78,6 +79,7 @@ type _ElasticPluginArgs struct {
 	PipeLine           string              `vfilter:"optional,field=pipeline,doc=Pipeline for uploads"`
 	DisableSSLSecurity bool                `vfilter:"optional,field=disable_ssl_security,doc=Disable ssl certificate verifications."`
 	RootCerts          string              `vfilter:"optional,field=root_ca,doc=As a better alternative to disable_ssl_security, allows root ca certs to be added here."`
+	RateLimiter        string              `vfilter:"optional,field=rate_limiter,doc=The name of a rate limiter defined in the server config to throttle bulk uploads with."`
 }
 
 type _ElasticPlugin struct{}
@@ -190,7 +192,7 @@ func upload_rows(
 	next_send_time := time.After(wait_time)
 
 	// Flush any remaining rows
-	defer send_to_elastic(ctx, scope, output_chan, client, &buf)
+	defer send_to_elastic(ctx, scope, output_chan, client, &buf, arg)
 
 	opts := vql_subsystem.EncOptsFromScope(scope)
 
@@ -215,14 +217,14 @@ func upload_rows(
 
 			if id > next_send_id {
 				send_to_elastic(ctx, scope, output_chan,
-					client, &buf)
+					client, &buf, arg)
 				next_send_id = id + arg.ChunkSize
 				next_send_time = time.After(wait_time)
 			}
 
 		case <-next_send_time:
 			send_to_elastic(ctx, scope, output_chan,
-				client, &buf)
+				client, &buf, arg)
 			next_send_id = id + arg.ChunkSize
 			next_send_time = time.After(wait_time)
 		}
@@ -272,12 +274,22 @@ func send_to_elastic(
 	ctx context.Context,
 	scope vfilter.Scope,
 	output_chan chan vfilter.Row,
-	client *elasticsearch.Client, buf *bytes.Buffer) {
+	client *elasticsearch.Client, buf *bytes.Buffer,
+	arg *_ElasticPluginArgs) {
 	b := buf.Bytes()
 	if len(b) == 0 {
 		return
 	}
 
+	if arg.RateLimiter != "" {
+		config_obj, _ := vql_subsystem.GetServerConfig(scope)
+		err := rate_limiter.Wait(ctx, config_obj, arg.RateLimiter)
+		if err != nil {
+			scope.Log("elastic: %v", err)
+			return
+		}
+	}
+
 	res, err := client.Bulk(bytes.NewReader(b))
 	if err != nil && !errors.Is(err, io.EOF) {
 		scope.Log("elastic: %v", err)
@@ -0,0 +1,604 @@
+/*
+  Plugin elastic_bulk.
+
+  A more robust alternative to elastic_upload() for large volumes
+  (e.g. the results of a big hunt):
+
+  - Rows are batched into bulk requests limited both by the number of
+    documents and by size.
+
+  - Batches are queued for a pool of senders. When the queue is full
+    the query is paused, so a slow cluster throttles the query rather
+    than rows accumulating in memory.
+
+  - Requests failing with a 429 or 5xx status, and documents rejected
+    individually with 429, are retried with exponential backoff.
+
+  - Documents which are still rejected are appended to a dead letter
+    file as JSONL so they can be inspected and replayed later.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	elasticsearch "github.com/Velocidex/go-elasticsearch/v7"
+	"github.com/Velocidex/ordereddict"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/crypto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services/rate_limiter"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	// Never wait longer than this between retries.
+	elasticBulkMaxBackoff = time.Minute
+)
+
+var (
+	metricElasticBulkQueueDepth = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "elastic_bulk_queue_depth",
+			Help: "Number of documents queued by elastic_bulk() waiting to be sent",
+		})
+
+	metricElasticBulkDocuments = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "elastic_bulk_documents",
+			Help: "Number of documents processed by elastic_bulk() by outcome",
+		}, []string{"status"})
+)
+
+type _ElasticBulkPluginArgs struct {
+	Query              vfilter.StoredQuery `vfilter:"required,field=query,doc=Source for rows to upload."`
+	Index              string              `vfilter:"optional,field=index,doc=The name of the index to upload to. If not specified ensure a column is named '_index'."`
+	Addresses          []string            `vfilter:"optional,field=addresses,doc=A list of Elasticsearch nodes to use."`
+	Username           string              `vfilter:"optional,field=username,doc=Username for HTTP Basic Authentication."`
+	Password           string              `vfilter:"optional,field=password,doc=Password for HTTP Basic Authentication."`
+	CloudID            string              `vfilter:"optional,field=cloud_id,doc=Endpoint for the Elastic Service (https://elastic.co/cloud)."`
+	APIKey             string              `vfilter:"optional,field=api_key,doc=Base64-encoded token for authorization; if set, overrides username and password."`
+	PipeLine           string              `vfilter:"optional,field=pipeline,doc=Pipeline for uploads"`
+	DisableSSLSecurity bool                `vfilter:"optional,field=disable_ssl_security,doc=Disable ssl certificate verifications."`
+	RootCerts          string              `vfilter:"optional,field=root_ca,doc=As a better alternative to disable_ssl_security, allows root ca certs to be added here."`
+	ChunkSize          int64               `vfilter:"optional,field=chunk_size,doc=The maximum number of documents in each bulk request (default 1000)."`
+	MaxBytes           int64               `vfilter:"optional,field=max_bytes,doc=The maximum size of each bulk request (default 5mb)."`
+	WaitTime           int64               `vfilter:"optional,field=wait_time,doc=Send a partial batch after this many seconds (default 2)."`
+	Threads            int64               `vfilter:"optional,field=threads,doc=How many bulk requests to send concurrently (default 1)."`
+	QueueSize          int64               `vfilter:"optional,field=queue_size,doc=How many batches may wait to be sent. The query is paused while the queue is full (default 10)."`
+	MaxRetries         int64               `vfilter:"optional,field=max_retries,doc=How many times to retry a failed batch or document (default 5)."`
+	RetryDelay         int64               `vfilter:"optional,field=retry_delay,doc=Seconds to wait before the first retry. The delay doubles with each retry (default 1)."`
+	DeadLetter         string              `vfilter:"optional,field=dead_letter,doc=A file on the server to append rejected documents to (as JSONL)."`
+	RateLimiter        string              `vfilter:"optional,field=rate_limiter,doc=The name of a rate limiter defined in the server config to throttle bulk requests with."`
+}
+
+// A document ready to send: the bulk action line and the source,
+// each terminated with a newline.
+type elasticBulkDocument struct {
+	index string
+	meta  []byte
+	data  []byte
+}
+
+type elasticBulkBatch struct {
+	docs []*elasticBulkDocument
+	size int
+}
+
+func (self *elasticBulkBatch) add(doc *elasticBulkDocument) {
+	self.docs = append(self.docs, doc)
+	self.size += len(doc.meta) + len(doc.data)
+}
+
+func elasticBulkBody(docs []*elasticBulkDocument) []byte {
+	buf := &bytes.Buffer{}
+	for _, doc := range docs {
+		buf.Write(doc.meta)
+		buf.Write(doc.data)
+	}
+	return buf.Bytes()
+}
+
+// Sends a bulk request. Returns the HTTP status and the response body.
+type elasticBulkFunc func(ctx context.Context, body []byte) (int, []byte, error)
+
+type elasticBulkItem struct {
+	Status int         `json:"status"`
+	Error  interface{} `json:"error"`
+}
+
+type elasticBulkResponse struct {
+	// Each item is keyed by the action (e.g. "index").
+	Items []map[string]*elasticBulkItem `json:"items"`
+}
+
+// Returns the result of each document in the order they were sent.
+func parseElasticBulkResponse(body []byte) ([]*elasticBulkItem, error) {
+	response := &elasticBulkResponse{}
+	err := json.Unmarshal(body, response)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*elasticBulkItem, 0, len(response.Items))
+	for _, item := range response.Items {
+		for _, v := range item {
+			if v == nil {
+				v = &elasticBulkItem{}
+			}
+			result = append(result, v)
+			break
+		}
+	}
+	return result, nil
+}
+
+// Records the documents which could not be indexed.
+type elasticDeadLetter struct {
+	mu sync.Mutex
+	fd io.Writer
+}
+
+func (self *elasticDeadLetter) Write(
+	doc *elasticBulkDocument, status int, reason interface{}) {
+	if self == nil || self.fd == nil {
+		return
+	}
+
+	serialized, err := json.Marshal(ordereddict.NewDict().
+		Set("Time", utils.GetTime().Now().UTC()).
+		Set("Index", doc.index).
+		Set("Status", status).
+		Set("Error", reason).
+		Set("Document", json.RawMessage(bytes.TrimSpace(doc.data))))
+	if err != nil {
+		return
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	_, _ = self.fd.Write(serialized)
+	_, _ = self.fd.Write([]byte("\n"))
+}
+
+type elasticBulkSender struct {
+	bulk        elasticBulkFunc
+	dead_letter *elasticDeadLetter
+	max_retries int
+	retry_delay time.Duration
+
+	// Called before each request (e.g. to rate limit).
+	wait func(ctx context.Context) error
+}
+
+type elasticBulkResult struct {
+	Indexed  int
+	Rejected int
+	Retries  int
+	Error    string
+}
+
+func (self *elasticBulkSender) rejectAll(
+	docs []*elasticBulkDocument, status int, reason interface{}) {
+	for _, doc := range docs {
+		self.dead_letter.Write(doc, status, reason)
+	}
+	metricElasticBulkDocuments.WithLabelValues("rejected").Add(float64(len(docs)))
+}
+
+// Send the batch, retrying failed requests and documents rejected
+// because the cluster is overloaded.
+func (self *elasticBulkSender) Send(
+	ctx context.Context, batch *elasticBulkBatch) *elasticBulkResult {
+	result := &elasticBulkResult{}
+	pending := batch.docs
+	delay := self.retry_delay
+	var last_status int
+	var last_error interface{}
+
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			if attempt > self.max_retries {
+				break
+			}
+
+			result.Retries++
+			metricElasticBulkDocuments.WithLabelValues("retried").
+				Add(float64(len(pending)))
+
+			select {
+			case <-ctx.Done():
+				result.Error = ctx.Err().Error()
+				return result
+			case <-time.After(delay):
+			}
+
+			delay *= 2
+			if delay > elasticBulkMaxBackoff {
+				delay = elasticBulkMaxBackoff
+			}
+		}
+
+		if self.wait != nil {
+			err := self.wait(ctx)
+			if err != nil {
+				result.Error = err.Error()
+				return result
+			}
+		}
+
+		status, body, err := self.bulk(ctx, elasticBulkBody(pending))
+		last_status = status
+		if err != nil {
+			last_error = err.Error()
+			continue
+		}
+
+		// The cluster is overloaded or unavailable - try again
+		// later.
+		if status == http.StatusTooManyRequests || status >= 500 {
+			last_error = string(body)
+			continue
+		}
+
+		// The entire request was rejected - retrying will not help.
+		if status >= 400 {
+			result.Rejected += len(pending)
+			self.rejectAll(pending, status, string(body))
+			result.Error = fmt.Sprintf("Bulk request failed with status %v", status)
+			return result
+		}
+
+		items, err := parseElasticBulkResponse(body)
+		if err == nil && len(items) != len(pending) {
+			err = errors.New("Bulk response does not match the request")
+		}
+		if err != nil {
+			last_error = err.Error()
+			continue
+		}
+
+		var retry []*elasticBulkDocument
+		for idx, item := range items {
+			switch {
+			case item.Status >= 200 && item.Status < 300:
+				result.Indexed++
+				metricElasticBulkDocuments.WithLabelValues("indexed").Inc()
+
+			case item.Status == http.StatusTooManyRequests:
+				retry = append(retry, pending[idx])
+
+			default:
+				result.Rejected++
+				self.rejectAll(pending[idx:idx+1], item.Status, item.Error)
+			}
+		}
+		pending = retry
+		last_status = http.StatusTooManyRequests
+		last_error = "Too many requests"
+	}
+
+	// Out of retries.
+	if len(pending) > 0 {
+		result.Rejected += len(pending)
+		self.rejectAll(pending, last_status, last_error)
+		result.Error = fmt.Sprintf("Giving up after %v retries: %v",
+			self.max_retries, last_error)
+	}
+
+	return result
+}
+
+type _ElasticBulkPlugin struct{}
+
+func (self _ElasticBulkPlugin) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.COLLECT_SERVER)
+		if err != nil {
+			scope.Log("elastic_bulk: %v", err)
+			return
+		}
+
+		arg := &_ElasticBulkPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("elastic_bulk: %v", err)
+			return
+		}
+
+		if arg.ChunkSize <= 0 {
+			arg.ChunkSize = 1000
+		}
+
+		if arg.MaxBytes <= 0 {
+			arg.MaxBytes = 5 * 1024 * 1024
+		}
+
+		if arg.WaitTime <= 0 {
+			arg.WaitTime = 2
+		}
+
+		if arg.Threads <= 0 {
+			arg.Threads = 1
+		}
+
+		if arg.QueueSize <= 0 {
+			arg.QueueSize = 10
+		}
+
+		if arg.MaxRetries <= 0 {
+			arg.MaxRetries = 5
+		}
+
+		if arg.RetryDelay <= 0 {
+			arg.RetryDelay = 1
+		}
+
+		client, err := newElasticBulkClient(scope, arg)
+		if err != nil {
+			scope.Log("elastic_bulk: %v", err)
+			return
+		}
+
+		sender := &elasticBulkSender{
+			bulk: func(ctx context.Context, body []byte) (int, []byte, error) {
+				res, err := client.Bulk(bytes.NewReader(body),
+					client.Bulk.WithContext(ctx))
+				if err != nil {
+					return 0, nil, err
+				}
+				defer res.Body.Close()
+
+				response, err := ioutil.ReadAll(res.Body)
+				return res.StatusCode, response, err
+			},
+			max_retries: int(arg.MaxRetries),
+			retry_delay: time.Duration(arg.RetryDelay) * time.Second,
+		}
+
+		if arg.RateLimiter != "" {
+			config_obj, _ := vql_subsystem.GetServerConfig(scope)
+			sender.wait = func(ctx context.Context) error {
+				return rate_limiter.Wait(ctx, config_obj, arg.RateLimiter)
+			}
+		}
+
+		if arg.DeadLetter != "" {
+			err := vql_subsystem.CheckAccess(scope, acls.FILESYSTEM_WRITE)
+			if err != nil {
+				scope.Log("elastic_bulk: %v", err)
+				return
+			}
+
+			fd, err := os.OpenFile(arg.DeadLetter,
+				os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+			if err != nil {
+				scope.Log("elastic_bulk: Unable to open dead letter file %v: %v",
+					arg.DeadLetter, err)
+				return
+			}
+			defer fd.Close()
+
+			sender.dead_letter = &elasticDeadLetter{fd: fd}
+		}
+
+		queue := make(chan *elasticBulkBatch, arg.QueueSize)
+
+		wg := &sync.WaitGroup{}
+		for i := 0; i < int(arg.Threads); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for batch := range queue {
+					metricElasticBulkQueueDepth.Sub(float64(len(batch.docs)))
+
+					result := sender.Send(ctx, batch)
+					if result.Error != "" {
+						scope.Log("elastic_bulk: %v", result.Error)
+					}
+
+					select {
+					case <-ctx.Done():
+					case output_chan <- ordereddict.NewDict().
+						Set("Indexed", result.Indexed).
+						Set("Rejected", result.Rejected).
+						Set("Retries", result.Retries).
+						Set("Error", result.Error):
+					}
+				}
+			}()
+		}
+
+		queueElasticBatches(ctx, scope, arg, queue)
+		close(queue)
+		wg.Wait()
+	}()
+
+	return output_chan
+}
+
+// Read rows from the query into batches and push them onto the
+// queue. Blocks while the queue is full.
+func queueElasticBatches(
+	ctx context.Context, scope vfilter.Scope,
+	arg *_ElasticBulkPluginArgs, queue chan *elasticBulkBatch) {
+
+	opts := vql_subsystem.EncOptsFromScope(scope)
+	wait_time := time.Duration(arg.WaitTime) * time.Second
+	next_send_time := time.After(wait_time)
+	batch := &elasticBulkBatch{}
+
+	push := func() bool {
+		if len(batch.docs) == 0 {
+			return true
+		}
+
+		metricElasticBulkQueueDepth.Add(float64(len(batch.docs)))
+		select {
+		case <-ctx.Done():
+			metricElasticBulkQueueDepth.Sub(float64(len(batch.docs)))
+			return false
+		case queue <- batch:
+		}
+
+		batch = &elasticBulkBatch{}
+		return true
+	}
+
+	row_chan := arg.Query.Eval(ctx, scope)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case row, ok := <-row_chan:
+			if !ok {
+				push()
+				return
+			}
+
+			doc, err := newElasticBulkDocument(ctx, scope, row, arg, opts)
+			if err != nil {
+				scope.Log("elastic_bulk: %v", err)
+				continue
+			}
+
+			// Do not let the request exceed max_bytes
+			if len(batch.docs) > 0 &&
+				int64(batch.size+len(doc.meta)+len(doc.data)) > arg.MaxBytes {
+				if !push() {
+					return
+				}
+				next_send_time = time.After(wait_time)
+			}
+
+			batch.add(doc)
+			if int64(len(batch.docs)) >= arg.ChunkSize {
+				if !push() {
+					return
+				}
+				next_send_time = time.After(wait_time)
+			}
+
+		case <-next_send_time:
+			if !push() {
+				return
+			}
+			next_send_time = time.After(wait_time)
+		}
+	}
+}
+
+func newElasticBulkDocument(
+	ctx context.Context, scope vfilter.Scope, row vfilter.Row,
+	arg *_ElasticBulkPluginArgs, opts *json.EncOpts) (*elasticBulkDocument, error) {
+
+	row_dict := vfilter.RowToDict(ctx, scope, row)
+	index := arg.Index
+	index_any, pres := row_dict.Get("_index")
+	if pres {
+		index = sanitize_index(fmt.Sprintf("%v", index_any))
+		row_dict.Delete("_index")
+	}
+
+	if index == "" {
+		return nil, errors.New("No index specified")
+	}
+
+	// Let elastic assign document ids so retried documents are
+	// not rejected as duplicates.
+	action := ordereddict.NewDict().Set("_index", index)
+	if arg.PipeLine != "" {
+		action.Set("pipeline", arg.PipeLine)
+	}
+
+	meta, err := json.Marshal(ordereddict.NewDict().Set("index", action))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalWithOptions(row_dict, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &elasticBulkDocument{
+		index: index,
+		meta:  append(meta, '\n'),
+		data:  append(data, '\n'),
+	}, nil
+}
+
+func newElasticBulkClient(scope vfilter.Scope,
+	arg *_ElasticBulkPluginArgs) (*elasticsearch.Client, error) {
+	CA_Pool := x509.NewCertPool()
+	crypto.AddPublicRoots(CA_Pool)
+
+	config_obj, _ := artifacts.GetConfig(scope)
+	err := crypto.AddDefaultCerts(config_obj, CA_Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	if arg.RootCerts != "" &&
+		!CA_Pool.AppendCertsFromPEM([]byte(arg.RootCerts)) {
+		return nil, errors.New("Unable to add root certs")
+	}
+
+	return elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: arg.Addresses,
+		Username:  arg.Username,
+		Password:  arg.Password,
+		CloudID:   arg.CloudID,
+		APIKey:    arg.APIKey,
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost:   10,
+			ResponseHeaderTimeout: 100 * time.Second,
+			TLSClientConfig: &tls.Config{
+				ClientSessionCache: tls.NewLRUClientSessionCache(100),
+				RootCAs:            CA_Pool,
+				InsecureSkipVerify: arg.DisableSSLSecurity,
+			},
+		},
+	})
+}
+
+func (self _ElasticBulkPlugin) Info(
+	scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "elastic_bulk",
+		Doc:     "Upload rows to elastic using the bulk API with retries and backpressure.",
+		ArgType: type_map.AddType(scope, &_ElasticBulkPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&_ElasticBulkPlugin{})
+}
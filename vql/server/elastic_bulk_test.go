@@ -0,0 +1,221 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+// A fake bulk endpoint which answers each request with the next
+// scripted response and records the documents it was sent.
+type fakeElasticBulk struct {
+	mu        sync.Mutex
+	requests  [][]string
+	responses []func(docs []string) (int, []byte)
+}
+
+func (self *fakeElasticBulk) Bulk(
+	ctx context.Context, body []byte) (int, []byte, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	// Every second line is a document source.
+	var docs []string
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	for i := 1; i < len(lines); i += 2 {
+		docs = append(docs, lines[i])
+	}
+
+	idx := len(self.requests)
+	self.requests = append(self.requests, docs)
+	if idx >= len(self.responses) {
+		idx = len(self.responses) - 1
+	}
+
+	status, response := self.responses[idx](docs)
+	return status, response, nil
+}
+
+func elasticStatus(status int) func(docs []string) (int, []byte) {
+	return func(docs []string) (int, []byte) {
+		return status, []byte(fmt.Sprintf(`{"error": "status %v"}`, status))
+	}
+}
+
+// Respond to each document with the status given by the function.
+func elasticItems(
+	item_status func(doc string) int) func(docs []string) (int, []byte) {
+	return func(docs []string) (int, []byte) {
+		items := []interface{}{}
+		for _, doc := range docs {
+			item := ordereddict.NewDict().Set("status", item_status(doc))
+			if item_status(doc) >= 300 {
+				item.Set("error", "Rejected "+doc)
+			}
+			items = append(items, ordereddict.NewDict().Set("index", item))
+		}
+
+		serialized, _ := json.Marshal(ordereddict.NewDict().
+			Set("errors", false).
+			Set("items", items))
+		return http.StatusOK, serialized
+	}
+}
+
+func elasticTestBatch(count int) *elasticBulkBatch {
+	batch := &elasticBulkBatch{}
+	for i := 0; i < count; i++ {
+		batch.add(&elasticBulkDocument{
+			index: "test",
+			meta:  []byte(`{"index":{"_index":"test"}}` + "\n"),
+			data:  []byte(fmt.Sprintf(`{"Row":%d}`, i) + "\n"),
+		})
+	}
+	return batch
+}
+
+func newTestElasticBulkSender(
+	fake *fakeElasticBulk, dead_letter *bytes.Buffer) *elasticBulkSender {
+	return &elasticBulkSender{
+		bulk:        fake.Bulk,
+		dead_letter: &elasticDeadLetter{fd: dead_letter},
+		max_retries: 2,
+		retry_delay: time.Millisecond,
+	}
+}
+
+func deadLetters(t *testing.T, dead_letter *bytes.Buffer) []*ordereddict.Dict {
+	var result []*ordereddict.Dict
+	for _, line := range strings.Split(
+		strings.TrimSpace(dead_letter.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		item := ordereddict.NewDict()
+		require.NoError(t, item.UnmarshalJSON([]byte(line)))
+		result = append(result, item)
+	}
+	return result
+}
+
+func TestElasticBulkRetriesFailedRequests(t *testing.T) {
+	fake := &fakeElasticBulk{
+		responses: []func(docs []string) (int, []byte){
+			elasticStatus(http.StatusServiceUnavailable),
+			elasticStatus(http.StatusTooManyRequests),
+			elasticItems(func(doc string) int { return 201 }),
+		},
+	}
+	dead_letter := &bytes.Buffer{}
+
+	result := newTestElasticBulkSender(fake, dead_letter).Send(
+		context.Background(), elasticTestBatch(3))
+	assert.Equal(t, &elasticBulkResult{Indexed: 3, Retries: 2}, result)
+
+	// The whole batch is sent each time.
+	require.Equal(t, 3, len(fake.requests))
+	for _, request := range fake.requests {
+		assert.Equal(t, 3, len(request))
+	}
+	assert.Equal(t, 0, dead_letter.Len())
+}
+
+func TestElasticBulkRetriesRejectedDocuments(t *testing.T) {
+	fake := &fakeElasticBulk{
+		responses: []func(docs []string) (int, []byte){
+			elasticItems(func(doc string) int {
+				switch doc {
+				case `{"Row":1}`:
+					return http.StatusTooManyRequests
+				case `{"Row":2}`:
+					return http.StatusBadRequest
+				}
+				return 201
+			}),
+			elasticItems(func(doc string) int { return 201 }),
+		},
+	}
+	dead_letter := &bytes.Buffer{}
+
+	result := newTestElasticBulkSender(fake, dead_letter).Send(
+		context.Background(), elasticTestBatch(3))
+	assert.Equal(t, &elasticBulkResult{
+		Indexed: 2, Rejected: 1, Retries: 1}, result)
+
+	// Only the document rejected with 429 is sent again.
+	require.Equal(t, 2, len(fake.requests))
+	assert.Equal(t, []string{`{"Row":1}`}, fake.requests[1])
+
+	// The document rejected for another reason is not retried.
+	letters := deadLetters(t, dead_letter)
+	require.Equal(t, 1, len(letters))
+	letters[0].Delete("Time")
+	assert.Equal(t, `{"Index":"test","Status":400,"Error":"Rejected {\"Row\":2}","Document":{"Row":2}}`,
+		json.MustMarshalString(letters[0]))
+}
+
+func TestElasticBulkDeadLetter(t *testing.T) {
+	fake := &fakeElasticBulk{
+		responses: []func(docs []string) (int, []byte){
+			elasticItems(func(doc string) int {
+				if doc == `{"Row":0}` {
+					return 201
+				}
+				return http.StatusTooManyRequests
+			}),
+		},
+	}
+	dead_letter := &bytes.Buffer{}
+
+	result := newTestElasticBulkSender(fake, dead_letter).Send(
+		context.Background(), elasticTestBatch(3))
+	assert.Equal(t, 1, result.Indexed)
+	assert.Equal(t, 2, result.Rejected)
+	assert.Equal(t, 2, result.Retries)
+	assert.Contains(t, result.Error, "Giving up after 2 retries")
+
+	// The first attempt and two retries.
+	assert.Equal(t, 3, len(fake.requests))
+
+	// Documents still rejected after all retries are written to the
+	// dead letter file.
+	letters := deadLetters(t, dead_letter)
+	require.Equal(t, 2, len(letters))
+	for idx, letter := range letters {
+		status, _ := letter.Get("Status")
+		assert.Equal(t, uint64(http.StatusTooManyRequests), status)
+
+		document, _ := letter.Get("Document")
+		assert.Equal(t, fmt.Sprintf(`{"Row":%d}`, idx+1),
+			json.MustMarshalString(document))
+	}
+}
+
+func TestElasticBulkRejectedRequest(t *testing.T) {
+	fake := &fakeElasticBulk{
+		responses: []func(docs []string) (int, []byte){
+			elasticStatus(http.StatusBadRequest),
+		},
+	}
+	dead_letter := &bytes.Buffer{}
+
+	result := newTestElasticBulkSender(fake, dead_letter).Send(
+		context.Background(), elasticTestBatch(2))
+	assert.Equal(t, 2, result.Rejected)
+	assert.Equal(t, 0, result.Retries)
+	assert.Contains(t, result.Error, "status 400")
+
+	// A request rejected as a whole is not retried.
+	assert.Equal(t, 1, len(fake.requests))
+	assert.Equal(t, 2, len(deadLetters(t, dead_letter)))
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/file_store/quota"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type FilestoreQuotaPluginArgs struct {
+	Type  string `vfilter:"optional,field=type,doc=Only show this type of consumer (client or hunt)."`
+	Limit int64  `vfilter:"optional,field=limit,doc=Only show this many of the top consumers."`
+}
+
+type FilestoreQuotaPlugin struct{}
+
+func (self FilestoreQuotaPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("filestore_quota: %v", err)
+			return
+		}
+
+		arg := &FilestoreQuotaPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("filestore_quota: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("filestore_quota: Command can only run on the server")
+			return
+		}
+
+		records, err := quota.ListUsage(config_obj)
+		if err != nil {
+			scope.Log("filestore_quota: %v", err)
+			return
+		}
+
+		count := int64(0)
+		for _, record := range records {
+			if arg.Type != "" && record.Type != arg.Type {
+				continue
+			}
+
+			count++
+			if arg.Limit > 0 && count > arg.Limit {
+				return
+			}
+
+			limit := uint64(0)
+			if config_obj.Datastore != nil {
+				switch record.Type {
+				case quota.QUOTA_TYPE_CLIENT:
+					limit = config_obj.Datastore.ClientUploadQuota
+				case quota.QUOTA_TYPE_HUNT:
+					limit = config_obj.Datastore.HuntUploadQuota
+				}
+			}
+
+			percent := float64(0)
+			if limit > 0 {
+				percent = float64(record.Bytes) * 100 / float64(limit)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case output_chan <- ordereddict.NewDict().
+				Set("Type", record.Type).
+				Set("Name", record.Name).
+				Set("Bytes", record.Bytes).
+				Set("Quota", limit).
+				Set("Percent", percent).
+				Set("RefusedBytes", record.RefusedBytes).
+				Set("Updated", time.Unix(record.Updated, 0).UTC()):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self FilestoreQuotaPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "filestore_quota",
+		Doc:     "Show the bytes of uploads stored by each client and hunt, largest first.",
+		ArgType: type_map.AddType(scope, &FilestoreQuotaPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&FilestoreQuotaPlugin{})
+}
@@ -0,0 +1,366 @@
+package flows
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Velocidex/ordereddict"
+	"google.golang.org/protobuf/proto"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vql/server/clients"
+	"www.velocidex.com/golang/velociraptor/vql/tools/collector"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	BULK_OP_LABEL   = "label"
+	BULK_OP_UNLABEL = "unlabel"
+	BULK_OP_DELETE  = "delete"
+	BULK_OP_COLLECT = "collect"
+)
+
+type BulkClientOperationArgs struct {
+	Search     string      `vfilter:"required,field=search,doc=A client search expression (as used in the GUI search box, e.g. label:foo or host:bar)."`
+	Operation  string      `vfilter:"required,field=operation,doc=One of label, unlabel, delete or collect."`
+	Labels     []string    `vfilter:"optional,field=labels,doc=The labels to add or remove."`
+	Artifacts  []string    `vfilter:"optional,field=artifacts,doc=The artifacts to collect."`
+	Spec       vfilter.Any `vfilter:"optional,field=spec,doc=Parameters to apply to the collected artifacts."`
+	ReallyDoIt bool        `vfilter:"optional,field=really_do_it,doc=Actually apply the operation (otherwise just list the matching clients)."`
+}
+
+type BulkClientOperationPlugin struct{}
+
+func (self BulkClientOperationPlugin) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &BulkClientOperationArgs{}
+		err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("bulk_client_operation: %v", err)
+			return
+		}
+
+		var permission acls.ACL_PERMISSION
+		switch arg.Operation {
+		case BULK_OP_LABEL, BULK_OP_UNLABEL:
+			permission = acls.LABEL_CLIENT
+			if len(arg.Labels) == 0 {
+				scope.Log("bulk_client_operation: no labels specified")
+				return
+			}
+
+		case BULK_OP_DELETE:
+			permission = acls.SERVER_ADMIN
+
+		case BULK_OP_COLLECT:
+			permission = acls.COLLECT_CLIENT
+			if len(arg.Artifacts) == 0 {
+				scope.Log("bulk_client_operation: no artifacts to collect")
+				return
+			}
+
+		default:
+			scope.Log("bulk_client_operation: unsupported operation %v",
+				arg.Operation)
+			return
+		}
+
+		err = vql_subsystem.CheckAccess(scope, permission)
+		if err != nil {
+			scope.Log("bulk_client_operation: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		// Prepare the collection request once for all clients.
+		var request *flows_proto.ArtifactCollectorArgs
+		if arg.Operation == BULK_OP_COLLECT {
+			request, err = prepareBulkCollection(config_obj, scope, arg)
+			if err != nil {
+				scope.Log("bulk_client_operation: %v", err)
+				return
+			}
+		}
+
+		indexer, err := services.GetIndexer(config_obj)
+		if err != nil {
+			scope.Log("bulk_client_operation: %v", err)
+			return
+		}
+
+		principal := vql_subsystem.GetPrincipal(scope)
+		client_chan, err := indexer.SearchClientsChan(
+			ctx, scope, config_obj, arg.Search, principal)
+		if err != nil {
+			scope.Log("bulk_client_operation: %v", err)
+			return
+		}
+
+		// Collect the matching clients first - some operations
+		// change the index we are iterating over.
+		matching := []*api_proto.ApiClient{}
+		for client_info := range client_chan {
+			matching = append(matching, client_info)
+		}
+
+		if !arg.ReallyDoIt {
+			for _, client_info := range matching {
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- ordereddict.NewDict().
+					Set("ClientId", client_info.ClientId).
+					Set("Hostname", getHostname(client_info)).
+					Set("Operation", arg.Operation).
+					Set("Status", "DRY_RUN").
+					Set("Error", ""):
+				}
+			}
+			return
+		}
+
+		job_manager, err := services.GetJobManager(config_obj)
+		if err != nil {
+			scope.Log("bulk_client_operation: %v", err)
+			return
+		}
+
+		// The operation runs as a server job so it shows up in
+		// server_jobs() and may be cancelled with
+		// cancel_server_job(). Rows are relayed back to this query
+		// as each client is processed.
+		row_chan := make(chan *ordereddict.Dict)
+		job, err := job_manager.StartJob(ctx, principal,
+			"BulkClientOperation",
+			fmt.Sprintf("%v on %v clients matching %v",
+				arg.Operation, len(matching), arg.Search),
+			func(job_ctx context.Context, reporter services.JobReporter) error {
+				defer close(row_chan)
+
+				return runBulkOperation(job_ctx, config_obj, scope,
+					arg, request, matching, reporter, row_chan)
+			})
+		if err != nil {
+			scope.Log("bulk_client_operation: %v", err)
+			return
+		}
+
+		scope.Log("bulk_client_operation: Started job %v: %v on %v clients matching %v",
+			job.JobId, arg.Operation, len(matching), arg.Search)
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Nobody is reading our rows any more.
+				_ = job_manager.CancelJob(context.Background(), job.JobId)
+				return
+
+			case row, ok := <-row_chan:
+				if !ok {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					_ = job_manager.CancelJob(context.Background(), job.JobId)
+					return
+				case output_chan <- row:
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+// Applies the operation to each client in turn, reporting progress
+// to the job manager. Returns an error if the operation failed on
+// any client.
+func runBulkOperation(
+	ctx context.Context,
+	config_obj *config_proto.Config, scope vfilter.Scope,
+	arg *BulkClientOperationArgs,
+	request *flows_proto.ArtifactCollectorArgs,
+	matching []*api_proto.ApiClient,
+	reporter services.JobReporter,
+	row_chan chan<- *ordereddict.Dict) error {
+
+	total := uint64(len(matching))
+	error_count := 0
+	for idx, client_info := range matching {
+		row := ordereddict.NewDict().
+			Set("ClientId", client_info.ClientId).
+			Set("Hostname", getHostname(client_info)).
+			Set("Operation", arg.Operation)
+
+		flow_id, err := applyBulkOperation(
+			ctx, config_obj, scope, arg, request, client_info.ClientId)
+		if err != nil {
+			error_count++
+			row.Set("Status", "ERROR").Set("Error", err.Error())
+		} else {
+			row.Set("Status", "OK").Set("Error", "")
+		}
+		if arg.Operation == BULK_OP_COLLECT {
+			row.Set("FlowId", flow_id)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case row_chan <- row:
+		}
+
+		reporter.SetProgress(uint64(idx+1), total)
+		reporter.SetStatus(fmt.Sprintf("Processed %v/%v clients (%v errors)",
+			idx+1, total, error_count))
+
+		// Report progress periodically.
+		if (idx+1)%100 == 0 {
+			scope.Log("bulk_client_operation: Processed %v/%v clients (%v errors)",
+				idx+1, total, error_count)
+		}
+	}
+
+	scope.Log("bulk_client_operation: Completed %v clients with %v errors",
+		total, error_count)
+
+	if error_count > 0 {
+		return fmt.Errorf("Operation failed on %v of %v clients",
+			error_count, total)
+	}
+	return nil
+}
+
+func getHostname(client_info *api_proto.ApiClient) string {
+	if client_info.OsInfo != nil {
+		return client_info.OsInfo.Hostname
+	}
+	return ""
+}
+
+func prepareBulkCollection(
+	config_obj *config_proto.Config, scope vfilter.Scope,
+	arg *BulkClientOperationArgs) (*flows_proto.ArtifactCollectorArgs, error) {
+	manager, err := services.GetRepositoryManager(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		Artifacts: arg.Artifacts,
+		Creator:   vql_subsystem.GetPrincipal(scope),
+	}
+
+	spec := arg.Spec
+	if spec == nil {
+		spec = ordereddict.NewDict()
+	}
+
+	err = collector.AddSpecProtobuf(config_obj, repository, scope,
+		spec, request)
+	return request, err
+}
+
+// Apply the operation to a single client. Returns the flow id for
+// collections.
+func applyBulkOperation(
+	ctx context.Context,
+	config_obj *config_proto.Config, scope vfilter.Scope,
+	arg *BulkClientOperationArgs,
+	request *flows_proto.ArtifactCollectorArgs,
+	client_id string) (string, error) {
+
+	switch arg.Operation {
+	case BULK_OP_LABEL, BULK_OP_UNLABEL:
+		labeler := services.GetLabeler(config_obj)
+		for _, label := range arg.Labels {
+			var err error
+			if arg.Operation == BULK_OP_LABEL {
+				err = labeler.SetClientLabel(ctx, config_obj, client_id, label)
+			} else {
+				err = labeler.RemoveClientLabel(ctx, config_obj, client_id, label)
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		return "", nil
+
+	case BULK_OP_DELETE:
+		return "", clients.DeleteClient(ctx, config_obj, scope, client_id)
+
+	case BULK_OP_COLLECT:
+		manager, err := services.GetRepositoryManager(config_obj)
+		if err != nil {
+			return "", err
+		}
+
+		repository, err := manager.GetGlobalRepository(config_obj)
+		if err != nil {
+			return "", err
+		}
+
+		acl_manager, ok := artifacts.GetACLManager(scope)
+		if !ok {
+			acl_manager = acl_managers.NullACLManager{}
+		}
+
+		launcher, err := services.GetLauncher(config_obj)
+		if err != nil {
+			return "", err
+		}
+
+		client_request := proto.Clone(request).(*flows_proto.ArtifactCollectorArgs)
+		client_request.ClientId = client_id
+
+		return launcher.ScheduleArtifactCollection(
+			ctx, config_obj, acl_manager, repository, client_request,
+			func() {
+				notifier, err := services.GetNotifier(config_obj)
+				if err == nil {
+					notifier.NotifyListener(
+						config_obj, client_id, "bulk_client_operation")
+				}
+			})
+	}
+
+	return "", fmt.Errorf("Unsupported operation %v", arg.Operation)
+}
+
+func (self BulkClientOperationPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "bulk_client_operation",
+		Doc:     "Apply labels, delete or schedule a collection on all clients matching a search.",
+		ArgType: type_map.AddType(scope, &BulkClientOperationArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&BulkClientOperationPlugin{})
+}
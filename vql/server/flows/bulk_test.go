@@ -0,0 +1,151 @@
+package flows_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
+	"www.velocidex.com/golang/velociraptor/vql/server/flows"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type BulkTestSuite struct {
+	test_utils.TestSuite
+}
+
+func (self *BulkTestSuite) SetupTest() {
+	self.ConfigObj = self.LoadConfig()
+	self.ConfigObj.Frontend.ServerServices.JobManager = true
+
+	self.TestSuite.SetupTest()
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	indexer, err := services.GetIndexer(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	for i := 1; i <= 3; i++ {
+		client_id := fmt.Sprintf("C.%d", i)
+		require.NoError(self.T(), indexer.SetIndex(client_id, "all"))
+		require.NoError(self.T(), db.SetSubject(self.ConfigObj,
+			paths.NewClientPathManager(client_id).Path(),
+			&actions_proto.ClientInfo{
+				ClientId: client_id,
+				Hostname: "Host" + client_id,
+			}))
+	}
+}
+
+func (self *BulkTestSuite) runBulk(args *ordereddict.Dict) []vfilter.Row {
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger: logging.NewPlainLogger(self.ConfigObj,
+			&logging.FrontendComponent),
+		Env: ordereddict.NewDict(),
+	})
+	defer scope.Close()
+
+	return vtesting.RunPlugin(flows.BulkClientOperationPlugin{}.Call(
+		self.Ctx, scope, args))
+}
+
+func (self *BulkTestSuite) listJobs() []*api_proto.ServerJob {
+	job_manager, err := services.GetJobManager(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	jobs, err := job_manager.ListJobs(self.Ctx)
+	require.NoError(self.T(), err)
+	return jobs
+}
+
+func (self *BulkTestSuite) TestDryRun() {
+	rows := self.runBulk(ordereddict.NewDict().
+		Set("search", "all").
+		Set("operation", "label").
+		Set("labels", []string{"Foo"}))
+	assert.Equal(self.T(), 3, len(rows))
+
+	for _, row := range rows {
+		status, _ := row.(*ordereddict.Dict).GetString("Status")
+		assert.Equal(self.T(), "DRY_RUN", status)
+	}
+
+	// A dry run does not start a job.
+	assert.Equal(self.T(), 0, len(self.listJobs()))
+
+	labeler := services.GetLabeler(self.ConfigObj)
+	assert.False(self.T(), labeler.IsLabelSet(
+		self.Ctx, self.ConfigObj, "C.1", "Foo"))
+}
+
+func (self *BulkTestSuite) TestLabelJob() {
+	rows := self.runBulk(ordereddict.NewDict().
+		Set("search", "all").
+		Set("operation", "label").
+		Set("labels", []string{"Foo"}).
+		Set("really_do_it", true))
+	assert.Equal(self.T(), 3, len(rows))
+
+	labeler := services.GetLabeler(self.ConfigObj)
+	for _, row := range rows {
+		client_id, _ := row.(*ordereddict.Dict).GetString("ClientId")
+		status, _ := row.(*ordereddict.Dict).GetString("Status")
+		assert.Equal(self.T(), "OK", status)
+		assert.True(self.T(), labeler.IsLabelSet(
+			self.Ctx, self.ConfigObj, client_id, "Foo"))
+	}
+
+	// The operation is tracked as a server job.
+	var jobs []*api_proto.ServerJob
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		jobs = self.listJobs()
+		return len(jobs) == 1 && jobs[0].State == services.JOB_FINISHED
+	})
+	assert.Equal(self.T(), "BulkClientOperation", jobs[0].Type)
+	assert.Equal(self.T(), uint64(3), jobs[0].Completed)
+	assert.Equal(self.T(), uint64(3), jobs[0].Total)
+}
+
+func (self *BulkTestSuite) TestDeleteJob() {
+	rows := self.runBulk(ordereddict.NewDict().
+		Set("search", "all").
+		Set("operation", "delete").
+		Set("really_do_it", true))
+	require.Equal(self.T(), 3, len(rows))
+
+	db, err := datastore.GetDB(self.ConfigObj)
+	require.NoError(self.T(), err)
+
+	for _, row := range rows {
+		client_id, _ := row.(*ordereddict.Dict).GetString("ClientId")
+		status, _ := row.(*ordereddict.Dict).GetString("Status")
+		assert.Equal(self.T(), "OK", status)
+
+		client_info := &actions_proto.ClientInfo{}
+		_ = db.GetSubject(self.ConfigObj,
+			paths.NewClientPathManager(client_id).Path(), client_info)
+		assert.Equal(self.T(), "", client_info.ClientId)
+	}
+}
+
+func TestBulkClientOperation(t *testing.T) {
+	suite.Run(t, &BulkTestSuite{})
+}
@@ -47,6 +47,7 @@ type ScheduleCollectionFunctionArg struct {
 	MaxBytes     uint64      `vfilter:"optional,field=max_bytes,doc=Max number of bytes to upload"`
 	Urgent       bool        `vfilter:"optional,field=urgent,doc=Set the collection as urgent - skips other queues collections on the client."`
 	OrgId        string      `vfilter:"optional,field=org_id,doc=If set the collection will be started in the specified org."`
+	SignResults  bool        `vfilter:"optional,field=sign_results,doc=If set the client signs the hashes of its results and uploads with its client key."`
 }
 
 type ScheduleCollectionFunction struct{}
@@ -136,6 +137,7 @@ func (self *ScheduleCollectionFunction) Call(ctx context.Context,
 		MaxRows:        arg.MaxRows,
 		MaxUploadBytes: arg.MaxBytes,
 		Urgent:         arg.Urgent,
+		SignResults:    arg.SignResults,
 	}
 
 	if arg.Spec == nil {
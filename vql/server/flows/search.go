@@ -0,0 +1,130 @@
+package flows
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/file_store/search"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type SearchUploadsPluginArgs struct {
+	ClientId          string   `vfilter:"optional,field=client_id,doc=Search the uploads of this client."`
+	FlowId            string   `vfilter:"optional,field=flow_id,doc=Only search the uploads of this collection (requires client_id)."`
+	HuntId            string   `vfilter:"optional,field=hunt_id,doc=Search the uploads of all collections in this hunt."`
+	Regex             string   `vfilter:"optional,field=regex,doc=A regex to search for."`
+	Keywords          []string `vfilter:"optional,field=keywords,doc=Keywords to search for."`
+	NoCase            bool     `vfilter:"optional,field=nocase,doc=Match case insensitively."`
+	Context           int      `vfilter:"optional,field=context,doc=Bytes of context to return either side of each match (default 40)."`
+	MaxMatches        int64    `vfilter:"optional,field=max_matches,doc=Stop after this many matches (default 1000)."`
+	MaxMatchesPerFile int64    `vfilter:"optional,field=max_matches_per_file,doc=Only report this many matches from each upload."`
+	MaxFileSize       int64    `vfilter:"optional,field=max_file_size,doc=Only search this many bytes at the start of each upload."`
+}
+
+type SearchUploadsPlugin struct{}
+
+func (self SearchUploadsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("search_uploads: %v", err)
+			return
+		}
+
+		arg := &SearchUploadsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("search_uploads: %v", err)
+			return
+		}
+
+		if arg.HuntId == "" && arg.ClientId == "" {
+			scope.Log("search_uploads: One of client_id or hunt_id must be specified")
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("search_uploads: Command can only run on the server")
+			return
+		}
+
+		searcher, err := search.NewSearcher(config_obj, search.Options{
+			Regex:             arg.Regex,
+			Keywords:          arg.Keywords,
+			NoCase:            arg.NoCase,
+			Context:           arg.Context,
+			MaxMatches:        arg.MaxMatches,
+			MaxMatchesPerFile: arg.MaxMatchesPerFile,
+			MaxFileSize:       arg.MaxFileSize,
+		})
+		if err != nil {
+			scope.Log("search_uploads: %v", err)
+			return
+		}
+
+		sub_ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		matches := make(chan *search.Match)
+		go func() {
+			defer close(matches)
+
+			var err error
+			switch {
+			case arg.HuntId != "":
+				err = searcher.SearchHunt(sub_ctx, arg.HuntId, matches)
+			case arg.FlowId != "":
+				err = searcher.SearchFlow(
+					sub_ctx, arg.ClientId, arg.FlowId, matches)
+			default:
+				err = searcher.SearchClient(sub_ctx, arg.ClientId, matches)
+			}
+
+			if err != nil && sub_ctx.Err() == nil {
+				scope.Log("search_uploads: %v", err)
+			}
+		}()
+
+		for match := range matches {
+			select {
+			case <-ctx.Done():
+				return
+
+			case output_chan <- ordereddict.NewDict().
+				Set("ClientId", match.ClientId).
+				Set("FlowId", match.FlowId).
+				Set("Upload", match.Upload).
+				Set("VFSPath", match.Path).
+				Set("Offset", match.Offset).
+				Set("Hit", match.Hit).
+				Set("Context", match.Context):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self SearchUploadsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "search_uploads",
+		Doc:     "Search the contents of uploads collected from a client or a hunt.",
+		ArgType: type_map.AddType(scope, &SearchUploadsPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&SearchUploadsPlugin{})
+}
@@ -28,6 +28,7 @@ import (
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/profiles"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
@@ -41,7 +42,8 @@ import (
 
 type ScheduleHuntFunctionArg struct {
 	Description   string           `vfilter:"optional,field=description,doc=Description of the hunt"`
-	Artifacts     []string         `vfilter:"required,field=artifacts,doc=A list of artifacts to collect"`
+	Artifacts     []string         `vfilter:"optional,field=artifacts,doc=A list of artifacts to collect"`
+	Profile       string           `vfilter:"optional,field=profile,doc=A triage profile to take the artifacts and parameters from"`
 	Expires       vfilter.LazyExpr `vfilter:"optional,field=expires,doc=A time for expiry (e.g. now() + 1800)"`
 	Spec          vfilter.Any      `vfilter:"optional,field=spec,doc=Parameters to apply to the artifacts"`
 	Timeout       uint64           `vfilter:"optional,field=timeout,doc=Set query timeout (default 10 min)"`
@@ -123,6 +125,51 @@ func (self *ScheduleHuntFunction) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
+	// The profile's definitions must match the server's so the hunt
+	// collects exactly what the profile describes.
+	if arg.Profile != "" {
+		if len(arg.Artifacts) > 0 || !utils.IsNil(arg.Spec) {
+			scope.Log("hunt: Use either a profile or artifacts and spec")
+			return vfilter.Null{}
+		}
+
+		profile, err := profiles.Parse([]byte(arg.Profile))
+		if err != nil {
+			scope.Log("hunt: %v", err)
+			return vfilter.Null{}
+		}
+
+		err = profile.Verify(config_obj, repository)
+		if err != nil {
+			scope.Log("hunt: %v", err)
+			return vfilter.Null{}
+		}
+
+		arg.Artifacts = profile.Artifacts
+		arg.Spec = profile.Spec()
+
+		if arg.Description == "" {
+			arg.Description = profile.Name
+		}
+		if arg.Timeout == 0 {
+			arg.Timeout = profile.Resources.Timeout
+		}
+		if arg.CpuLimit == 0 {
+			arg.CpuLimit = profile.Resources.CpuLimit
+		}
+		if arg.MaxRows == 0 {
+			arg.MaxRows = profile.Resources.MaxRows
+		}
+		if arg.MaxBytes == 0 {
+			arg.MaxBytes = profile.Resources.MaxBytes
+		}
+	}
+
+	if len(arg.Artifacts) == 0 {
+		scope.Log("hunt: No artifacts specified")
+		return vfilter.Null{}
+	}
+
 	request := &flows_proto.ArtifactCollectorArgs{
 		Creator:        vql_subsystem.GetPrincipal(scope),
 		Artifacts:      arg.Artifacts,
@@ -278,6 +325,8 @@ type AddToHuntFunctionArg struct {
 	ClientId string `vfilter:"required,field=client_id"`
 	HuntId   string `vfilter:"required,field=hunt_id"`
 	FlowId   string `vfilter:"optional,field=flow_id,doc=If a flow id is specified we do not create a new flow, but instead add this flow_id to the hunt."`
+
+	Reschedule bool `vfilter:"optional,field=reschedule,doc=Schedule the hunt again even if it already ran on the client (e.g. to retry stragglers)."`
 }
 
 type AddToHuntFunction struct{}
@@ -328,7 +377,8 @@ func (self *AddToHuntFunction) Call(ctx context.Context,
 			[]*ordereddict.Dict{ordereddict.NewDict().
 				Set("HuntId", arg.HuntId).
 				Set("ClientId", arg.ClientId).
-				Set("Override", true)},
+				Set("Override", true).
+				Set("Reschedule", arg.Reschedule)},
 			"System.Hunt.Participation", arg.ClientId, "")
 	}
 
@@ -21,6 +21,7 @@ package hunts
 
 import (
 	"context"
+	"time"
 
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/velociraptor/acls"
@@ -361,7 +362,110 @@ func (self HuntFlowsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap)
 	}
 }
 
+type HuntParticipationPluginArgs struct {
+	HuntId     string `vfilter:"required,field=hunt_id,doc=The hunt id to inspect."`
+	Stragglers bool   `vfilter:"optional,field=stragglers,doc=Only show clients which did not complete the hunt."`
+}
+
+type HuntParticipationPlugin struct{}
+
+func (self HuntParticipationPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("hunt_participation: %s", err)
+			return
+		}
+
+		arg := &HuntParticipationPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("hunt_participation: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		hunt_dispatcher, err := services.GetHuntDispatcher(config_obj)
+		if err != nil {
+			scope.Log("hunt_participation: %v", err)
+			return
+		}
+
+		participants, err := hunt_dispatcher.GetParticipation(
+			ctx, config_obj, arg.HuntId)
+		if err != nil {
+			scope.Log("hunt_participation: %v", err)
+			return
+		}
+
+		now := time.Now().Unix()
+		for _, participant := range participants {
+			if arg.Stragglers && !participant.IsStraggler() {
+				continue
+			}
+
+			// How long the client took to complete the hunt, or
+			// how long it is outstanding for.
+			var duration int64
+			if participant.ScheduledTime > 0 {
+				end_time := participant.CompletedTime
+				if end_time == 0 {
+					end_time = now
+				}
+				duration = end_time - participant.ScheduledTime
+			}
+
+			result := ordereddict.NewDict().
+				Set("HuntId", arg.HuntId).
+				Set("ClientId", participant.ClientId).
+				Set("Hostname", participant.Hostname).
+				Set("State", participant.State).
+				Set("FlowId", participant.FlowId).
+				Set("ScheduledTime", secondsToTime(participant.ScheduledTime)).
+				Set("CompletedTime", secondsToTime(participant.CompletedTime)).
+				Set("Duration", duration).
+				Set("LastSeen", secondsToTime(participant.LastSeen)).
+				Set("Error", participant.Error)
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- result:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func secondsToTime(sec int64) vfilter.Any {
+	if sec == 0 {
+		return vfilter.Null{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func (self HuntParticipationPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "hunt_participation",
+		Doc:     "Report which clients were scheduled, completed, errored or were never seen by a hunt.",
+		ArgType: type_map.AddType(scope, &HuntParticipationPluginArgs{}),
+	}
+}
+
 func init() {
+	vql_subsystem.RegisterPlugin(&HuntParticipationPlugin{})
 	vql_subsystem.RegisterPlugin(&HuntsPlugin{})
 	vql_subsystem.RegisterPlugin(&HuntResultsPlugin{})
 	vql_subsystem.RegisterPlugin(&HuntFlowsPlugin{})
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/sirupsen/logrus"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type ServerJobsPluginArgs struct {
+	JobId string `vfilter:"optional,field=job_id,doc=Only show this job."`
+}
+
+type ServerJobsPlugin struct{}
+
+func (self ServerJobsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("server_jobs: %v", err)
+			return
+		}
+
+		arg := &ServerJobsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("server_jobs: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("server_jobs: Command can only run on the server")
+			return
+		}
+
+		job_manager, err := services.GetJobManager(config_obj)
+		if err != nil {
+			scope.Log("server_jobs: %v", err)
+			return
+		}
+
+		var jobs []*api_proto.ServerJob
+		if arg.JobId != "" {
+			job, err := job_manager.GetJob(ctx, arg.JobId)
+			if err != nil {
+				scope.Log("server_jobs: %v", err)
+				return
+			}
+			jobs = append(jobs, job)
+
+		} else {
+			jobs, err = job_manager.ListJobs(ctx)
+			if err != nil {
+				scope.Log("server_jobs: %v", err)
+				return
+			}
+		}
+
+		for _, job := range jobs {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- jobToRow(job):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func jobToRow(job *api_proto.ServerJob) *ordereddict.Dict {
+	progress := 0.0
+	if job.Total > 0 {
+		progress = float64(job.Completed) * 100 / float64(job.Total)
+	}
+
+	if job.State == services.JOB_FINISHED {
+		progress = 100
+	}
+
+	result := ordereddict.NewDict().
+		Set("JobId", job.JobId).
+		Set("Type", job.Type).
+		Set("Description", job.Description).
+		Set("Principal", job.Principal).
+		Set("State", job.State).
+		Set("Progress", progress).
+		Set("Status", job.Status).
+		Set("StartTime", time.Unix(int64(job.StartTime), 0)).
+		Set("EndTime", nil).
+		Set("Error", job.Error)
+
+	if job.EndTime > 0 {
+		result.Set("EndTime", time.Unix(int64(job.EndTime), 0))
+	}
+
+	return result
+}
+
+func (self ServerJobsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "server_jobs",
+		Doc:     "List current and past long running server jobs.",
+		ArgType: type_map.AddType(scope, &ServerJobsPluginArgs{}),
+	}
+}
+
+type CancelServerJobFunctionArgs struct {
+	JobId string `vfilter:"required,field=job_id,doc=The job to cancel."`
+}
+
+type CancelServerJobFunction struct{}
+
+func (self *CancelServerJobFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.SERVER_ADMIN)
+	if err != nil {
+		scope.Log("cancel_server_job: %v", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CancelServerJobFunctionArgs{}
+	err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("cancel_server_job: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("cancel_server_job: Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	job_manager, err := services.GetJobManager(config_obj)
+	if err != nil {
+		scope.Log("cancel_server_job: %v", err)
+		return vfilter.Null{}
+	}
+
+	principal := vql_subsystem.GetPrincipal(scope)
+	logging.LogAudit(config_obj, principal, "cancel_server_job",
+		logrus.Fields{
+			"job_id": arg.JobId,
+		})
+
+	err = job_manager.CancelJob(ctx, arg.JobId)
+	if err != nil {
+		scope.Log("cancel_server_job: %v", err)
+		return vfilter.Null{}
+	}
+
+	return arg.JobId
+}
+
+func (self CancelServerJobFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "cancel_server_job",
+		Doc:     "Cancel a running server job.",
+		ArgType: type_map.AddType(scope, &CancelServerJobFunctionArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ServerJobsPlugin{})
+	vql_subsystem.RegisterFunction(&CancelServerJobFunction{})
+}
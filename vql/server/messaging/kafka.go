@@ -0,0 +1,221 @@
+/*
+  Plugins to stream rows into message bus pipelines.
+
+  Server monitoring artifacts can use these to forward alerts and
+  flow completions to existing Kafka or AMQP consumers without
+  needing a custom forwarder.
+*/
+
+package messaging
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/crypto"
+	"www.velocidex.com/golang/velociraptor/json"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type SendKafkaArgs struct {
+	Rows       vfilter.StoredQuery `vfilter:"required,field=rows,doc=Source for rows to send."`
+	Topic      string              `vfilter:"required,field=topic,doc=The topic to send the rows to."`
+	Addresses  []string            `vfilter:"required,field=addresses,doc=A list of Kafka brokers (host:port) to bootstrap from."`
+	KeyField   string              `vfilter:"optional,field=key_field,doc=A column to use as the message key. Rows with the same key are sent to the same partition."`
+	Username   string              `vfilter:"optional,field=username,doc=Username for SASL PLAIN authentication."`
+	Password   string              `vfilter:"optional,field=password,doc=Password for SASL PLAIN authentication."`
+	UseTLS     bool                `vfilter:"optional,field=tls,doc=Connect to the brokers using TLS."`
+	SkipVerify bool                `vfilter:"optional,field=skip_verify,doc=Skip SSL verification(default: False)."`
+	RootCerts  string              `vfilter:"optional,field=root_ca,doc=As a better alternative to skip_verify, allows root ca certs to be added here."`
+	Acks       int64               `vfilter:"optional,field=acks,doc=Set to -1 to wait for all in sync replicas to acknowledge each batch (default 1 waits for the leader only)."`
+	ChunkSize  int64               `vfilter:"optional,field=chunk_size,doc=The number of rows to send at the time (default 1000)."`
+	WaitTime   int64               `vfilter:"optional,field=wait_time,doc=Batch rows for this long before sending (default 2 sec)."`
+}
+
+type SendKafkaPlugin struct{}
+
+func (self SendKafkaPlugin) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.COLLECT_SERVER)
+		if err != nil {
+			scope.Log("send_kafka: %v", err)
+			return
+		}
+
+		arg := &SendKafkaArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("send_kafka: %v", err)
+			return
+		}
+
+		if arg.Acks != 0 && arg.Acks != 1 && arg.Acks != -1 {
+			scope.Log("send_kafka: acks must be 1 or -1")
+			return
+		}
+
+		options := &kafkaOptions{
+			Username: arg.Username,
+			Password: arg.Password,
+			Acks:     int16(arg.Acks),
+		}
+
+		if arg.UseTLS {
+			options.TLSConfig, err = getTLSConfig(
+				scope, arg.SkipVerify, arg.RootCerts)
+			if err != nil {
+				scope.Log("send_kafka: %v", err)
+				return
+			}
+		}
+
+		producer, err := newKafkaProducer(
+			ctx, arg.Addresses, arg.Topic, options)
+		if err != nil {
+			scope.Log("send_kafka: %v", err)
+			return
+		}
+		defer producer.Close()
+
+		opts := vql_subsystem.EncOptsFromScope(scope)
+		send := func(rows []vfilter.Row) {
+			if len(rows) == 0 {
+				return
+			}
+
+			records := make([]kafkaRecord, 0, len(rows))
+			for _, row := range rows {
+				row_dict := vfilter.RowToDict(ctx, scope, row)
+				value, err := json.MarshalWithOptions(row_dict, opts)
+				if err != nil {
+					scope.Log("send_kafka: %v", err)
+					continue
+				}
+
+				record := kafkaRecord{Value: value}
+				if arg.KeyField != "" {
+					key, pres := row_dict.Get(arg.KeyField)
+					if pres {
+						record.Key = []byte(fmt.Sprintf("%v", key))
+					}
+				}
+				records = append(records, record)
+			}
+
+			result := ordereddict.NewDict().
+				Set("Topic", arg.Topic).
+				Set("Count", len(records)).
+				Set("Error", nil)
+
+			err := producer.Send(ctx, records)
+			if err != nil {
+				scope.Log("send_kafka: %v", err)
+				result.Update("Count", 0).Update("Error", err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+			case output_chan <- result:
+			}
+		}
+
+		batchRows(ctx, arg.Rows.Eval(ctx, scope),
+			arg.ChunkSize, arg.WaitTime, send)
+	}()
+
+	return output_chan
+}
+
+// Collect rows from row_chan into batches and pass them to send:
+// Either when we get to chunk_size or wait_time whichever comes
+// first.
+func batchRows(ctx context.Context, row_chan <-chan vfilter.Row,
+	chunk_size, wait_time_sec int64, send func(rows []vfilter.Row)) {
+	if chunk_size == 0 {
+		chunk_size = 1000
+	}
+
+	if wait_time_sec == 0 {
+		wait_time_sec = 2
+	}
+
+	wait_time := time.Duration(wait_time_sec) * time.Second
+	next_send_time := time.After(wait_time)
+
+	buf := make([]vfilter.Row, 0, chunk_size)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case row, ok := <-row_chan:
+			if !ok {
+				// Flush any remaining rows
+				send(buf)
+				return
+			}
+
+			buf = append(buf, row)
+			if int64(len(buf)) >= chunk_size {
+				send(buf)
+				buf = make([]vfilter.Row, 0, chunk_size)
+				next_send_time = time.After(wait_time)
+			}
+
+		case <-next_send_time:
+			send(buf)
+			buf = make([]vfilter.Row, 0, chunk_size)
+			next_send_time = time.After(wait_time)
+		}
+	}
+}
+
+func getTLSConfig(scope vfilter.Scope,
+	skip_verify bool, root_certs string) (*tls.Config, error) {
+	CA_Pool := x509.NewCertPool()
+	crypto.AddPublicRoots(CA_Pool)
+
+	config_obj, _ := artifacts.GetConfig(scope)
+	err := crypto.AddDefaultCerts(config_obj, CA_Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	if root_certs != "" &&
+		!CA_Pool.AppendCertsFromPEM([]byte(root_certs)) {
+		return nil, fmt.Errorf("Unable to add root certs")
+	}
+
+	return &tls.Config{
+		RootCAs:            CA_Pool,
+		InsecureSkipVerify: skip_verify,
+	}, nil
+}
+
+func (self SendKafkaPlugin) Info(
+	scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "send_kafka",
+		Doc:     "Send rows to a Kafka topic as JSON messages.",
+		ArgType: type_map.AddType(scope, &SendKafkaArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&SendKafkaPlugin{})
+}
@@ -0,0 +1,822 @@
+package messaging
+
+// A minimal Kafka producer. The available client libraries implement
+// the entire protocol, including consumer groups and all compression
+// codecs, which adds a lot to the binary size. We only ever produce
+// uncompressed record batches, so here we implement just the
+// ApiVersions, Metadata, Produce and SASL PLAIN requests.
+
+// Each connection first asks the broker which request versions it
+// supports and uses the highest version both sides understand.
+
+// See https://kafka.apache.org/protocol for the wire format.
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	kafkaApiProduce          = 0
+	kafkaApiMetadata         = 3
+	kafkaApiSaslHandshake    = 17
+	kafkaApiApiVersions      = 18
+	kafkaApiSaslAuthenticate = 36
+
+	kafkaClientId        = "velociraptor"
+	kafkaDefaultTimeout  = 30 * time.Second
+	kafkaMaxResponseSize = 64 * 1024 * 1024
+
+	kafkaErrorUnknownPartition   = 3
+	kafkaErrorUnsupportedVersion = 35
+)
+
+var (
+	kafkaCrcTable = crc32.MakeTable(crc32.Castagnoli)
+
+	// The range of versions we can encode and decode for each
+	// request. Produce v3 is the first to carry v2 record batches and
+	// v8 changes the response format. Metadata v6 and later add
+	// fields we do not parse yet.
+	kafkaSupportedVersions = map[int16][2]int16{
+		kafkaApiProduce:          {3, 7},
+		kafkaApiMetadata:         {1, 5},
+		kafkaApiSaslHandshake:    {1, 1},
+		kafkaApiSaslAuthenticate: {0, 1},
+	}
+
+	kafkaApiNames = map[int16]string{
+		kafkaApiProduce:          "Produce",
+		kafkaApiMetadata:         "Metadata",
+		kafkaApiSaslHandshake:    "SaslHandshake",
+		kafkaApiSaslAuthenticate: "SaslAuthenticate",
+	}
+
+	kafkaErrors = map[int16]string{
+		1:  "OFFSET_OUT_OF_RANGE",
+		2:  "CORRUPT_MESSAGE",
+		3:  "UNKNOWN_TOPIC_OR_PARTITION",
+		5:  "LEADER_NOT_AVAILABLE",
+		6:  "NOT_LEADER_OR_FOLLOWER",
+		7:  "REQUEST_TIMED_OUT",
+		10: "MESSAGE_TOO_LARGE",
+		17: "INVALID_TOPIC_EXCEPTION",
+		19: "NOT_ENOUGH_REPLICAS",
+		29: "TOPIC_AUTHORIZATION_FAILED",
+		31: "CLUSTER_AUTHORIZATION_FAILED",
+		33: "UNSUPPORTED_SASL_MECHANISM",
+		34: "ILLEGAL_SASL_STATE",
+		35: "UNSUPPORTED_VERSION",
+		58: "SASL_AUTHENTICATION_FAILED",
+	}
+)
+
+type KafkaError struct {
+	Code int16
+}
+
+func (self KafkaError) Error() string {
+	name, pres := kafkaErrors[self.Code]
+	if !pres {
+		name = "error " + strconv.Itoa(int(self.Code))
+	}
+	return "Kafka: " + name
+}
+
+func kafkaError(code int16) error {
+	if code == 0 {
+		return nil
+	}
+	return KafkaError{Code: code}
+}
+
+type kafkaOptions struct {
+	// If set connect to the brokers using TLS.
+	TLSConfig *tls.Config
+
+	// If set authenticate using SASL PLAIN.
+	Username string
+	Password string
+
+	// 1 waits for the leader, -1 for all in sync replicas.
+	Acks int16
+
+	Timeout time.Duration
+}
+
+type kafkaRecord struct {
+	Key   []byte
+	Value []byte
+}
+
+// Kafka requests are built up from big endian integers, length
+// prefixed strings and arrays and zigzag encoded varints.
+type kafkaEncoder struct {
+	bytes.Buffer
+}
+
+func (self *kafkaEncoder) putInt8(v int8) {
+	self.WriteByte(byte(v))
+}
+
+func (self *kafkaEncoder) putInt16(v int16) {
+	_ = binary.Write(self, binary.BigEndian, v)
+}
+
+func (self *kafkaEncoder) putInt32(v int32) {
+	_ = binary.Write(self, binary.BigEndian, v)
+}
+
+func (self *kafkaEncoder) putInt64(v int64) {
+	_ = binary.Write(self, binary.BigEndian, v)
+}
+
+func (self *kafkaEncoder) putVarint(v int64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutVarint(buf, v)
+	self.Write(buf[:n])
+}
+
+func (self *kafkaEncoder) putString(v string) {
+	self.putInt16(int16(len(v)))
+	self.WriteString(v)
+}
+
+func (self *kafkaEncoder) putNullString() {
+	self.putInt16(-1)
+}
+
+func (self *kafkaEncoder) putBytes(v []byte) {
+	self.putInt32(int32(len(v)))
+	self.Write(v)
+}
+
+type kafkaDecoder struct {
+	data []byte
+	err  error
+}
+
+func (self *kafkaDecoder) next(length int) []byte {
+	if self.err != nil {
+		return nil
+	}
+
+	if length < 0 || length > len(self.data) {
+		self.err = io.ErrUnexpectedEOF
+		return nil
+	}
+
+	result := self.data[:length]
+	self.data = self.data[length:]
+	return result
+}
+
+func (self *kafkaDecoder) getInt8() int8 {
+	data := self.next(1)
+	if data == nil {
+		return 0
+	}
+	return int8(data[0])
+}
+
+func (self *kafkaDecoder) getInt16() int16 {
+	data := self.next(2)
+	if data == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(data))
+}
+
+func (self *kafkaDecoder) getInt32() int32 {
+	data := self.next(4)
+	if data == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(data))
+}
+
+func (self *kafkaDecoder) getInt64() int64 {
+	data := self.next(8)
+	if data == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(data))
+}
+
+// Reads a nullable string. Null strings are returned as "".
+func (self *kafkaDecoder) getString() string {
+	length := self.getInt16()
+	if length < 0 {
+		return ""
+	}
+	return string(self.next(int(length)))
+}
+
+func (self *kafkaDecoder) getBytes() []byte {
+	length := self.getInt32()
+	if length < 0 {
+		return nil
+	}
+	return self.next(int(length))
+}
+
+// Encode the records into a v2 record batch. All records share the
+// same timestamp.
+func encodeRecordBatch(records []kafkaRecord, now time.Time) []byte {
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+
+	// The part of the batch covered by the crc.
+	body := &kafkaEncoder{}
+	body.putInt16(0) // Attributes: No compression
+	body.putInt32(int32(len(records) - 1))
+	body.putInt64(timestamp) // First timestamp
+	body.putInt64(timestamp) // Max timestamp
+	body.putInt64(-1)        // Producer Id
+	body.putInt16(-1)        // Producer Epoch
+	body.putInt32(-1)        // Base Sequence
+	body.putInt32(int32(len(records)))
+
+	for idx, record := range records {
+		encoded := &kafkaEncoder{}
+		encoded.putInt8(0)   // Attributes
+		encoded.putVarint(0) // Timestamp delta
+		encoded.putVarint(int64(idx))
+		if record.Key == nil {
+			encoded.putVarint(-1)
+		} else {
+			encoded.putVarint(int64(len(record.Key)))
+			encoded.Write(record.Key)
+		}
+		encoded.putVarint(int64(len(record.Value)))
+		encoded.Write(record.Value)
+		encoded.putVarint(0) // Headers
+
+		body.putVarint(int64(encoded.Len()))
+		body.Write(encoded.Bytes())
+	}
+
+	batch := &kafkaEncoder{}
+	batch.putInt64(0) // Base offset
+
+	// The length covers the leader epoch, magic and crc.
+	batch.putInt32(int32(4 + 1 + 4 + body.Len()))
+	batch.putInt32(-1) // Partition leader epoch
+	batch.putInt8(2)   // Magic
+	batch.putInt32(int32(crc32.Checksum(body.Bytes(), kafkaCrcTable)))
+	batch.Write(body.Bytes())
+
+	return batch.Bytes()
+}
+
+// A connection to a single broker.
+type kafkaConn struct {
+	conn           net.Conn
+	correlation_id int32
+	timeout        time.Duration
+
+	// The negotiated version of each request.
+	versions map[int16]int16
+}
+
+func dialKafka(ctx context.Context,
+	address string, options *kafkaOptions) (*kafkaConn, error) {
+	dialer := &net.Dialer{Timeout: options.Timeout}
+
+	var conn net.Conn
+	var err error
+
+	if options.TLSConfig != nil {
+		tls_dialer := &tls.Dialer{
+			NetDialer: dialer,
+			Config:    options.TLSConfig,
+		}
+		conn, err = tls_dialer.DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &kafkaConn{
+		conn:    conn,
+		timeout: options.Timeout,
+	}
+
+	err = result.negotiateVersions()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if options.Username != "" {
+		err = result.saslPlain(options.Username, options.Password)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (self *kafkaConn) Close() error {
+	return self.conn.Close()
+}
+
+// Send a request and wait for its response. Returns the response
+// body after the header.
+func (self *kafkaConn) roundTrip(
+	api_key, api_version int16, body []byte) ([]byte, error) {
+	self.correlation_id++
+
+	request := &kafkaEncoder{}
+	request.putInt32(0) // Size - filled in below.
+	request.putInt16(api_key)
+	request.putInt16(api_version)
+	request.putInt32(self.correlation_id)
+	request.putString(kafkaClientId)
+	request.Write(body)
+
+	serialized := request.Bytes()
+	binary.BigEndian.PutUint32(serialized, uint32(len(serialized)-4))
+
+	err := self.conn.SetDeadline(time.Now().Add(self.timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = self.conn.Write(serialized)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 4)
+	_, err = io.ReadFull(self.conn, header)
+	if err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size < 4 || size > kafkaMaxResponseSize {
+		return nil, fmt.Errorf("Kafka: Invalid response size %v", size)
+	}
+
+	response := make([]byte, size)
+	_, err = io.ReadFull(self.conn, response)
+	if err != nil {
+		return nil, err
+	}
+
+	correlation_id := int32(binary.BigEndian.Uint32(response))
+	if correlation_id != self.correlation_id {
+		return nil, fmt.Errorf("Kafka: Unexpected correlation id %v",
+			correlation_id)
+	}
+
+	return response[4:], nil
+}
+
+// ApiVersions v0 is understood by all brokers which can accept v2
+// record batches, even before SASL authentication.
+func (self *kafkaConn) negotiateVersions() error {
+	response, err := self.roundTrip(kafkaApiApiVersions, 0, nil)
+	if err != nil {
+		return err
+	}
+
+	decoder := &kafkaDecoder{data: response}
+	code := decoder.getInt16()
+
+	self.versions = make(map[int16]int16)
+	for i := decoder.getInt32(); i > 0 && decoder.err == nil; i-- {
+		api_key := decoder.getInt16()
+		broker_min := decoder.getInt16()
+		broker_max := decoder.getInt16()
+
+		supported, pres := kafkaSupportedVersions[api_key]
+		if !pres {
+			continue
+		}
+
+		version := supported[1]
+		if broker_max < version {
+			version = broker_max
+		}
+
+		if version >= supported[0] && version >= broker_min {
+			self.versions[api_key] = version
+		}
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	err = kafkaError(code)
+	if err != nil {
+		return err
+	}
+
+	// Every connection needs at least these.
+	for _, api_key := range []int16{kafkaApiMetadata, kafkaApiProduce} {
+		_, err := self.version(api_key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (self *kafkaConn) version(api_key int16) (int16, error) {
+	version, pres := self.versions[api_key]
+	if !pres {
+		supported := kafkaSupportedVersions[api_key]
+		return 0, fmt.Errorf(
+			"%w: Broker does not support %v versions %v to %v",
+			kafkaError(kafkaErrorUnsupportedVersion),
+			kafkaApiNames[api_key], supported[0], supported[1])
+	}
+	return version, nil
+}
+
+func (self *kafkaConn) saslPlain(username, password string) error {
+	handshake_version, err := self.version(kafkaApiSaslHandshake)
+	if err != nil {
+		return err
+	}
+
+	authenticate_version, err := self.version(kafkaApiSaslAuthenticate)
+	if err != nil {
+		return err
+	}
+
+	request := &kafkaEncoder{}
+	request.putString("PLAIN")
+
+	response, err := self.roundTrip(
+		kafkaApiSaslHandshake, handshake_version, request.Bytes())
+	if err != nil {
+		return err
+	}
+
+	decoder := &kafkaDecoder{data: response}
+	err = kafkaError(decoder.getInt16())
+	if err != nil {
+		return err
+	}
+
+	request = &kafkaEncoder{}
+	request.putBytes([]byte("\x00" + username + "\x00" + password))
+
+	response, err = self.roundTrip(
+		kafkaApiSaslAuthenticate, authenticate_version, request.Bytes())
+	if err != nil {
+		return err
+	}
+
+	decoder = &kafkaDecoder{data: response}
+	code := decoder.getInt16()
+	message := decoder.getString()
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("%w: %v", kafkaError(code), message)
+	}
+	return nil
+}
+
+type kafkaMetadata struct {
+	// Broker addresses by node id.
+	brokers map[int32]string
+
+	// The leader node id of each partition.
+	leaders map[int32]int32
+
+	// Sorted partition ids.
+	partitions []int32
+}
+
+func (self *kafkaConn) metadata(topic string) (*kafkaMetadata, error) {
+	version, err := self.version(kafkaApiMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &kafkaEncoder{}
+	request.putInt32(1)
+	request.putString(topic)
+
+	// Earlier versions leave topic creation to the broker config.
+	if version >= 4 {
+		request.putInt8(1) // Allow auto topic creation
+	}
+
+	response, err := self.roundTrip(kafkaApiMetadata, version, request.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &kafkaMetadata{
+		brokers: make(map[int32]string),
+		leaders: make(map[int32]int32),
+	}
+
+	decoder := &kafkaDecoder{data: response}
+	if version >= 3 {
+		decoder.getInt32() // Throttle time
+	}
+
+	for i := decoder.getInt32(); i > 0 && decoder.err == nil; i-- {
+		node_id := decoder.getInt32()
+		host := decoder.getString()
+		port := decoder.getInt32()
+		decoder.getString() // Rack
+
+		result.brokers[node_id] = net.JoinHostPort(
+			host, strconv.Itoa(int(port)))
+	}
+
+	if version >= 2 {
+		decoder.getString() // Cluster Id
+	}
+
+	decoder.getInt32() // Controller Id
+
+	for i := decoder.getInt32(); i > 0 && decoder.err == nil; i-- {
+		topic_error := decoder.getInt16()
+		name := decoder.getString()
+		decoder.getInt8() // Is Internal
+
+		for j := decoder.getInt32(); j > 0 && decoder.err == nil; j-- {
+			decoder.getInt16() // Partition error
+			partition := decoder.getInt32()
+			leader := decoder.getInt32()
+
+			// Replicas, in sync replicas and from v5 offline replicas.
+			replica_lists := 2
+			if version >= 5 {
+				replica_lists = 3
+			}
+
+			for k := 0; k < replica_lists; k++ {
+				for l := decoder.getInt32(); l > 0 && decoder.err == nil; l-- {
+					decoder.getInt32()
+				}
+			}
+
+			if name == topic {
+				result.leaders[partition] = leader
+				result.partitions = append(result.partitions, partition)
+			}
+		}
+
+		if name == topic && topic_error != 0 {
+			return nil, fmt.Errorf("%w: %v", kafkaError(topic_error), topic)
+		}
+	}
+
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+
+	if len(result.partitions) == 0 {
+		return nil, fmt.Errorf("Kafka: No partitions for topic %v", topic)
+	}
+
+	sort.Slice(result.partitions, func(i, j int) bool {
+		return result.partitions[i] < result.partitions[j]
+	})
+
+	return result, nil
+}
+
+// Returns the base offset of the written records.
+func (self *kafkaConn) produce(topic string, partition int32,
+	records []kafkaRecord, acks int16) (int64, error) {
+	version, err := self.version(kafkaApiProduce)
+	if err != nil {
+		return 0, err
+	}
+
+	// The request is the same for all supported versions.
+	request := &kafkaEncoder{}
+	request.putNullString() // Transactional Id
+	request.putInt16(acks)
+	request.putInt32(int32(self.timeout / time.Millisecond))
+	request.putInt32(1)
+	request.putString(topic)
+	request.putInt32(1)
+	request.putInt32(partition)
+	request.putBytes(encodeRecordBatch(records, time.Now()))
+
+	response, err := self.roundTrip(kafkaApiProduce, version, request.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	decoder := &kafkaDecoder{data: response}
+	for i := decoder.getInt32(); i > 0 && decoder.err == nil; i-- {
+		decoder.getString() // Topic
+		for j := decoder.getInt32(); j > 0 && decoder.err == nil; j-- {
+			decoder.getInt32() // Partition
+			code := decoder.getInt16()
+			offset := decoder.getInt64()
+			decoder.getInt64() // Log append time
+			if version >= 5 {
+				decoder.getInt64() // Log start offset
+			}
+
+			if decoder.err == nil {
+				return offset, kafkaError(code)
+			}
+		}
+	}
+
+	if decoder.err != nil {
+		return 0, decoder.err
+	}
+	return 0, errors.New("Kafka: Empty produce response")
+}
+
+type kafkaProducer struct {
+	addresses []string
+	topic     string
+	options   *kafkaOptions
+
+	metadata *kafkaMetadata
+
+	// Connections to the brokers by node id.
+	conns map[int32]*kafkaConn
+
+	// Records without a key are sent to partitions in turn.
+	next_partition int
+}
+
+func newKafkaProducer(ctx context.Context, addresses []string,
+	topic string, options *kafkaOptions) (*kafkaProducer, error) {
+	if len(addresses) == 0 {
+		return nil, errors.New("Kafka: No broker addresses specified")
+	}
+
+	if options.Acks == 0 {
+		options.Acks = 1
+	}
+
+	if options.Timeout == 0 {
+		options.Timeout = kafkaDefaultTimeout
+	}
+
+	result := &kafkaProducer{
+		addresses: addresses,
+		topic:     topic,
+		options:   options,
+		conns:     make(map[int32]*kafkaConn),
+	}
+
+	err := result.refreshMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Fetch the topic's partition leaders from the first bootstrap broker
+// that answers.
+func (self *kafkaProducer) refreshMetadata(ctx context.Context) error {
+	var last_err error
+
+	for _, address := range self.addresses {
+		conn, err := dialKafka(ctx, address, self.options)
+		if err != nil {
+			last_err = err
+			continue
+		}
+
+		metadata, err := conn.metadata(self.topic)
+		conn.Close()
+		if err != nil {
+			last_err = err
+			continue
+		}
+
+		self.metadata = metadata
+		return nil
+	}
+
+	return last_err
+}
+
+func (self *kafkaProducer) getConn(
+	ctx context.Context, node_id int32) (*kafkaConn, error) {
+	conn, pres := self.conns[node_id]
+	if pres {
+		return conn, nil
+	}
+
+	address, pres := self.metadata.brokers[node_id]
+	if !pres {
+		return nil, fmt.Errorf("Kafka: Unknown broker %v", node_id)
+	}
+
+	conn, err := dialKafka(ctx, address, self.options)
+	if err != nil {
+		return nil, err
+	}
+
+	self.conns[node_id] = conn
+	return conn, nil
+}
+
+func (self *kafkaProducer) closeConn(node_id int32) {
+	conn, pres := self.conns[node_id]
+	if pres {
+		conn.Close()
+		delete(self.conns, node_id)
+	}
+}
+
+func (self *kafkaProducer) produce(ctx context.Context,
+	partition int32, records []kafkaRecord) error {
+	leader, pres := self.metadata.leaders[partition]
+	if !pres {
+		return kafkaError(kafkaErrorUnknownPartition)
+	}
+
+	conn, err := self.getConn(ctx, leader)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.produce(self.topic, partition, records, self.options.Acks)
+	if err != nil {
+		// The connection may be in an inconsistent state.
+		self.closeConn(leader)
+	}
+	return err
+}
+
+// Partition the records and send them to the partition leaders. When
+// leadership moves the metadata is refreshed and the send retried
+// once.
+func (self *kafkaProducer) Send(
+	ctx context.Context, records []kafkaRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	partitions := self.metadata.partitions
+	default_partition := partitions[self.next_partition%len(partitions)]
+	self.next_partition++
+
+	by_partition := make(map[int32][]kafkaRecord)
+	for _, record := range records {
+		partition := default_partition
+		if record.Key != nil {
+			hash := fnv.New32a()
+			_, _ = hash.Write(record.Key)
+			partition = partitions[hash.Sum32()%uint32(len(partitions))]
+		}
+		by_partition[partition] = append(by_partition[partition], record)
+	}
+
+	for partition, partition_records := range by_partition {
+		err := self.produce(ctx, partition, partition_records)
+		if err == nil {
+			continue
+		}
+
+		refresh_err := self.refreshMetadata(ctx)
+		if refresh_err != nil {
+			return err
+		}
+
+		err = self.produce(ctx, partition, partition_records)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (self *kafkaProducer) Close() {
+	for node_id := range self.conns {
+		self.closeConn(node_id)
+	}
+}
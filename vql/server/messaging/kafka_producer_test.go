@@ -0,0 +1,377 @@
+package messaging
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The versions advertised by a recent broker.
+var recentBrokerVersions = map[int16][2]int16{
+	kafkaApiProduce:          {0, 9},
+	kafkaApiMetadata:         {0, 12},
+	kafkaApiSaslHandshake:    {0, 1},
+	kafkaApiApiVersions:      {0, 3},
+	kafkaApiSaslAuthenticate: {0, 2},
+}
+
+// A fake broker which leads a single partition of one topic and
+// records the produced messages.
+type fakeBroker struct {
+	t        *testing.T
+	listener net.Listener
+	topic    string
+
+	// The request versions the broker advertises.
+	versions map[int16][2]int16
+
+	mu       sync.Mutex
+	messages []kafkaRecord
+	sasl     []string
+
+	// The version of each request received.
+	requests map[int16]int16
+}
+
+func newFakeBroker(t *testing.T, topic string,
+	versions map[int16][2]int16) *fakeBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	self := &fakeBroker{
+		t:        t,
+		listener: listener,
+		topic:    topic,
+		versions: versions,
+		requests: make(map[int16]int16),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go self.serve(conn)
+		}
+	}()
+
+	return self
+}
+
+func (self *fakeBroker) Close() {
+	self.listener.Close()
+}
+
+func (self *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		header := make([]byte, 4)
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return
+		}
+
+		request := make([]byte, binary.BigEndian.Uint32(header))
+		_, err = io.ReadFull(conn, request)
+		if err != nil {
+			return
+		}
+
+		decoder := &kafkaDecoder{data: request}
+		api_key := decoder.getInt16()
+		version := decoder.getInt16()
+		correlation_id := decoder.getInt32()
+		decoder.getString() // Client Id
+
+		self.mu.Lock()
+		self.requests[api_key] = version
+		self.mu.Unlock()
+
+		supported, pres := self.versions[api_key]
+		if !pres || version < supported[0] || version > supported[1] {
+			self.t.Errorf("Unsupported version %v of api key %v",
+				version, api_key)
+			return
+		}
+
+		response := &kafkaEncoder{}
+		response.putInt32(0)
+		response.putInt32(correlation_id)
+
+		switch api_key {
+		case kafkaApiApiVersions:
+			response.putInt16(0)
+			response.putInt32(int32(len(self.versions)))
+			for api_key, supported := range self.versions {
+				response.putInt16(api_key)
+				response.putInt16(supported[0])
+				response.putInt16(supported[1])
+			}
+		case kafkaApiMetadata:
+			self.metadata(version, response)
+		case kafkaApiProduce:
+			self.produce(decoder, version, response)
+		case kafkaApiSaslHandshake:
+			response.putInt16(0)
+			response.putInt32(1)
+			response.putString("PLAIN")
+		case kafkaApiSaslAuthenticate:
+			self.mu.Lock()
+			self.sasl = append(self.sasl, string(decoder.getBytes()))
+			self.mu.Unlock()
+			response.putInt16(0)
+			response.putNullString()
+			response.putBytes(nil)
+			if version >= 1 {
+				response.putInt64(0) // Session lifetime
+			}
+		default:
+			self.t.Errorf("Unexpected api key %v", api_key)
+			return
+		}
+
+		serialized := response.Bytes()
+		binary.BigEndian.PutUint32(serialized, uint32(len(serialized)-4))
+		_, err = conn.Write(serialized)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (self *fakeBroker) metadata(version int16, response *kafkaEncoder) {
+	host, port, _ := net.SplitHostPort(self.listener.Addr().String())
+	port_int, _ := strconv.Atoi(port)
+
+	if version >= 3 {
+		response.putInt32(0) // Throttle time
+	}
+
+	// Brokers
+	response.putInt32(1)
+	response.putInt32(7) // Node id
+	response.putString(host)
+	response.putInt32(int32(port_int))
+	response.putNullString()
+
+	if version >= 2 {
+		response.putString("cluster")
+	}
+
+	response.putInt32(7) // Controller Id
+
+	// Topics
+	response.putInt32(1)
+	response.putInt16(0)
+	response.putString(self.topic)
+	response.putInt8(0)
+
+	// Partitions
+	response.putInt32(1)
+	response.putInt16(0)
+	response.putInt32(0) // Partition
+	response.putInt32(7) // Leader
+	response.putInt32(1) // Replicas
+	response.putInt32(7)
+	response.putInt32(1) // ISR
+	response.putInt32(7)
+	if version >= 5 {
+		response.putInt32(0) // Offline replicas
+	}
+}
+
+func (self *fakeBroker) produce(decoder *kafkaDecoder,
+	version int16, response *kafkaEncoder) {
+	decoder.getString() // Transactional Id
+	assert.Equal(self.t, int16(1), decoder.getInt16())
+	decoder.getInt32() // Timeout
+	assert.Equal(self.t, int32(1), decoder.getInt32())
+	topic := decoder.getString()
+	assert.Equal(self.t, int32(1), decoder.getInt32())
+	partition := decoder.getInt32()
+	batch := &kafkaDecoder{data: decoder.getBytes()}
+	require.NoError(self.t, decoder.err)
+
+	batch.getInt64() // Base offset
+	length := batch.getInt32()
+	assert.Equal(self.t, int(length), len(batch.data))
+	batch.getInt32() // Leader epoch
+	assert.Equal(self.t, int8(2), batch.getInt8())
+	crc := uint32(batch.getInt32())
+	assert.Equal(self.t, crc32.Checksum(batch.data, kafkaCrcTable), crc)
+
+	batch.next(2 + 4 + 8 + 8 + 8 + 2 + 4)
+	count := batch.getInt32()
+
+	self.mu.Lock()
+	offset := int64(len(self.messages))
+	for i := int32(0); i < count; i++ {
+		length, n := binary.Varint(batch.data)
+		batch.next(n)
+		record := &kafkaDecoder{data: batch.next(int(length))}
+		record.getInt8()
+
+		// Timestamp and offset deltas
+		for j := 0; j < 2; j++ {
+			_, n := binary.Varint(record.data)
+			record.next(n)
+		}
+
+		message := kafkaRecord{}
+		key_length, n := binary.Varint(record.data)
+		record.next(n)
+		if key_length >= 0 {
+			message.Key = record.next(int(key_length))
+		}
+
+		value_length, n := binary.Varint(record.data)
+		record.next(n)
+		message.Value = record.next(int(value_length))
+		require.NoError(self.t, record.err)
+
+		self.messages = append(self.messages, message)
+	}
+	self.mu.Unlock()
+
+	response.putInt32(1)
+	response.putString(topic)
+	response.putInt32(1)
+	response.putInt32(partition)
+	response.putInt16(0)
+	response.putInt64(offset)
+	response.putInt64(-1)
+	if version >= 5 {
+		response.putInt64(0) // Log start offset
+	}
+	response.putInt32(0) // Throttle time
+}
+
+func TestKafkaProducer(t *testing.T) {
+	broker := newFakeBroker(t, "alerts", recentBrokerVersions)
+	defer broker.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	producer, err := newKafkaProducer(ctx,
+		[]string{broker.listener.Addr().String()}, "alerts",
+		&kafkaOptions{Username: "user", Password: "secret"})
+	require.NoError(t, err)
+	defer producer.Close()
+
+	err = producer.Send(ctx, []kafkaRecord{
+		{Value: []byte(`{"Alert":1}`)},
+		{Key: []byte("C.1"), Value: []byte(`{"Alert":2}`)},
+	})
+	require.NoError(t, err)
+
+	err = producer.Send(ctx, []kafkaRecord{
+		{Value: []byte(`{"Alert":3}`)},
+	})
+	require.NoError(t, err)
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	require.Equal(t, 3, len(broker.messages))
+	assert.Nil(t, broker.messages[0].Key)
+	assert.Equal(t, `{"Alert":1}`, string(broker.messages[0].Value))
+	assert.Equal(t, "C.1", string(broker.messages[1].Key))
+	assert.Equal(t, `{"Alert":2}`, string(broker.messages[1].Value))
+	assert.Equal(t, `{"Alert":3}`, string(broker.messages[2].Value))
+
+	// Both the metadata and producer connections authenticate.
+	assert.Equal(t, []string{"\x00user\x00secret", "\x00user\x00secret"},
+		broker.sasl)
+
+	// The highest versions both sides support are used.
+	assert.Equal(t, map[int16]int16{
+		kafkaApiApiVersions:      0,
+		kafkaApiSaslHandshake:    1,
+		kafkaApiSaslAuthenticate: 1,
+		kafkaApiMetadata:         5,
+		kafkaApiProduce:          7,
+	}, broker.requests)
+}
+
+// A 0.11 broker only supports the oldest versions we can use.
+func TestKafkaOldBroker(t *testing.T) {
+	broker := newFakeBroker(t, "alerts", map[int16][2]int16{
+		kafkaApiProduce:     {0, 3},
+		kafkaApiMetadata:    {0, 4},
+		kafkaApiApiVersions: {0, 1},
+	})
+	defer broker.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	producer, err := newKafkaProducer(ctx,
+		[]string{broker.listener.Addr().String()}, "alerts",
+		&kafkaOptions{})
+	require.NoError(t, err)
+	defer producer.Close()
+
+	err = producer.Send(ctx, []kafkaRecord{{Value: []byte(`{"Alert":1}`)}})
+	require.NoError(t, err)
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	require.Equal(t, 1, len(broker.messages))
+	assert.Equal(t, int16(4), broker.requests[kafkaApiMetadata])
+	assert.Equal(t, int16(3), broker.requests[kafkaApiProduce])
+}
+
+func TestKafkaUnsupportedBroker(t *testing.T) {
+	// This broker can not accept v2 record batches.
+	broker := newFakeBroker(t, "alerts", map[int16][2]int16{
+		kafkaApiProduce:     {0, 2},
+		kafkaApiMetadata:    {0, 2},
+		kafkaApiApiVersions: {0, 0},
+	})
+	defer broker.Close()
+
+	_, err := newKafkaProducer(context.Background(),
+		[]string{broker.listener.Addr().String()}, "alerts",
+		&kafkaOptions{})
+	assert.True(t, errors.Is(err,
+		KafkaError{Code: kafkaErrorUnsupportedVersion}))
+	assert.Contains(t, err.Error(), "Produce")
+
+	// SASL needs the handshake and authenticate requests as well.
+	broker = newFakeBroker(t, "alerts", map[int16][2]int16{
+		kafkaApiProduce:     {0, 3},
+		kafkaApiMetadata:    {0, 1},
+		kafkaApiApiVersions: {0, 0},
+	})
+	defer broker.Close()
+
+	_, err = newKafkaProducer(context.Background(),
+		[]string{broker.listener.Addr().String()}, "alerts",
+		&kafkaOptions{Username: "user", Password: "secret"})
+	assert.True(t, errors.Is(err,
+		KafkaError{Code: kafkaErrorUnsupportedVersion}))
+	assert.Contains(t, err.Error(), "SaslHandshake")
+}
+
+func TestKafkaUnknownTopic(t *testing.T) {
+	broker := newFakeBroker(t, "alerts", recentBrokerVersions)
+	defer broker.Close()
+
+	_, err := newKafkaProducer(context.Background(),
+		[]string{broker.listener.Addr().String()}, "missing",
+		&kafkaOptions{})
+	assert.Error(t, err)
+}
@@ -52,7 +52,7 @@ func (self OrgDeleteFunction) Call(
 	principal := vql_subsystem.GetPrincipal(scope)
 	logging.LogAudit(config_obj, principal, "org_delete",
 		logrus.Fields{
-			"org_id": arg.OrgId,
+			"deleted_org_id": arg.OrgId,
 		})
 
 	err = org_manager.DeleteOrg(ctx, arg.OrgId)
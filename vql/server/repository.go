@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/velociraptor/acls"
@@ -295,8 +296,87 @@ func (self ArtifactsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap)
 	}
 }
 
+type ArtifactVersionsPluginArgs struct {
+	Name    string `vfilter:"required,field=name,doc=The artifact to show the history for"`
+	Version uint64 `vfilter:"optional,field=version,doc=Only show this version"`
+}
+
+type ArtifactVersionsPlugin struct{}
+
+func (self ArtifactVersionsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("artifact_versions: %v", err)
+			return
+		}
+
+		arg := &ArtifactVersionsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("artifact_versions: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("artifact_versions: Command can only run on the server")
+			return
+		}
+
+		manager, err := services.GetRepositoryManager(config_obj)
+		if err != nil {
+			scope.Log("artifact_versions: %v", err)
+			return
+		}
+
+		versions, err := manager.ListArtifactVersions(config_obj, arg.Name)
+		if err != nil {
+			scope.Log("artifact_versions: %v", err)
+			return
+		}
+
+		for _, version := range versions {
+			if arg.Version > 0 && version.Version != arg.Version {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Name", version.Name).
+				Set("Version", version.Version).
+				Set("Op", version.Op).
+				Set("Principal", version.Principal).
+				Set("Timestamp", time.Unix(int64(version.Timestamp), 0).UTC()).
+				Set("Hash", version.Hash).
+				Set("Comment", version.Comment).
+				Set("Definition", version.Definition):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self ArtifactVersionsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "artifact_versions",
+		Doc:     "Show the version history of a custom artifact.",
+		ArgType: type_map.AddType(scope, &ArtifactVersionsPluginArgs{}),
+	}
+}
+
 func init() {
 	vql_subsystem.RegisterPlugin(&ArtifactsPlugin{})
+	vql_subsystem.RegisterPlugin(&ArtifactVersionsPlugin{})
 	vql_subsystem.RegisterFunction(&ArtifactSetFunction{})
 	vql_subsystem.RegisterFunction(&ArtifactDeleteFunction{})
 }
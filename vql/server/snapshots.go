@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/snapshots"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/functions"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type SnapshotsPluginArgs struct {
+	ClientId         string      `vfilter:"optional,field=client_id,doc=Show the history of this client's labels."`
+	HuntId           string      `vfilter:"optional,field=hunt_id,doc=Show the history of this hunt."`
+	ClientMonitoring bool        `vfilter:"optional,field=client_monitoring,doc=Show the history of the client monitoring table."`
+	At               vfilter.Any `vfilter:"optional,field=at,doc=Only show the snapshot which was current at this time."`
+}
+
+type SnapshotsPlugin struct{}
+
+func (self SnapshotsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("snapshots: %v", err)
+			return
+		}
+
+		arg := &SnapshotsPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("snapshots: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		var path api.FSPathSpec
+		switch {
+		case arg.ClientId != "":
+			path = paths.NewClientPathManager(arg.ClientId).LabelHistory()
+		case arg.HuntId != "":
+			path = paths.NewHuntPathManager(arg.HuntId).History()
+		case arg.ClientMonitoring:
+			path = paths.ClientMonitoringHistory
+		default:
+			scope.Log("snapshots: One of client_id, hunt_id or client_monitoring must be specified")
+			return
+		}
+
+		var at time.Time
+		if !utils.IsNil(arg.At) {
+			at, err = functions.TimeFromAny(scope, arg.At)
+			if err != nil {
+				scope.Log("snapshots: %v", err)
+				return
+			}
+		}
+
+		rows, err := snapshots.GetHistory(ctx, config_obj, path, at)
+		if err != nil {
+			scope.Log("snapshots: %v", err)
+			return
+		}
+
+		for _, row := range rows {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self SnapshotsPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "snapshots",
+		Doc:     "Show how a client's labels, a hunt or the client monitoring table changed over time, or what they looked like at a point in time.",
+		ArgType: type_map.AddType(scope, &SnapshotsPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&SnapshotsPlugin{})
+}
@@ -23,9 +23,13 @@
 package server
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"time"
@@ -36,6 +40,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/artifacts"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/crypto"
+	"www.velocidex.com/golang/velociraptor/json"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/functions"
 	"www.velocidex.com/golang/velociraptor/vql/networking"
@@ -58,6 +63,7 @@ type _SplunkPluginArgs struct {
 	Hostname       string              `vfilter:"optional,field=hostname,doc=Hostname for Splunk Events. Defaults to server hostname."`
 	TimestampField string              `vfilter:"optional,field=timestamp_field,doc=Field to use as event timestamp."`
 	HostnameField  string              `vfilter:"optional,field=hostname_field,doc=Field to use as event hostname. Overrides hostname param."`
+	Gzip           bool                `vfilter:"optional,field=gzip,doc=Compress each batch with gzip before sending it."`
 }
 
 type _SplunkPlugin struct{}
@@ -147,18 +153,20 @@ func _upload_rows(
 		return
 	}
 
-	client := splunk.NewClient(
-		&http.Client{
-			Timeout: time.Second * 20,
-			Transport: &http.Transport{
-				Proxy: networking.GetProxy(),
-				TLSClientConfig: &tls.Config{
-					ClientSessionCache: tls.NewLRUClientSessionCache(100),
-					RootCAs:            CA_Pool,
-					InsecureSkipVerify: arg.SkipVerify,
-				},
+	http_client := &http.Client{
+		Timeout: time.Second * 20,
+		Transport: &http.Transport{
+			Proxy: networking.GetProxy(),
+			TLSClientConfig: &tls.Config{
+				ClientSessionCache: tls.NewLRUClientSessionCache(100),
+				RootCAs:            CA_Pool,
+				InsecureSkipVerify: arg.SkipVerify,
 			},
-		}, // Optional HTTP Client objects
+		},
+	}
+
+	client := splunk.NewClient(
+		http_client, // Optional HTTP Client objects
 		arg.URL,
 		arg.Token,
 		arg.Source,
@@ -177,19 +185,19 @@ func _upload_rows(
 		case row, ok := <-row_chan:
 			if !ok {
 				// Flush any remaining rows
-				send_to_splunk(ctx, scope, output_chan, client, buf, arg)
+				send_to_splunk(ctx, scope, output_chan, http_client, client, buf, arg)
 				return
 			}
 			buf = append(buf, row)
 
 			// Do not allow the buffer to get too large.
 			if int64(len(buf)) > arg.ChunkSize {
-				send_to_splunk(ctx, scope, output_chan, client, buf, arg)
+				send_to_splunk(ctx, scope, output_chan, http_client, client, buf, arg)
 				buf = buf[:0]
 			}
 
 		case <-next_send_time:
-			send_to_splunk(ctx, scope, output_chan, client, buf, arg)
+			send_to_splunk(ctx, scope, output_chan, http_client, client, buf, arg)
 			buf = buf[:0]
 			next_send_time = time.After(wait_time)
 		}
@@ -199,7 +207,7 @@ func _upload_rows(
 func send_to_splunk(
 	ctx context.Context,
 	scope vfilter.Scope,
-	output_chan chan vfilter.Row,
+	output_chan chan vfilter.Row, http_client *http.Client,
 	client *splunk.Client, buf []vfilter.Row, arg *_SplunkPluginArgs) {
 
 	if len(buf) == 0 {
@@ -258,7 +266,12 @@ func send_to_splunk(
 		}
 	}
 
-	err := client.LogEvents(events)
+	var err error
+	if arg.Gzip {
+		err = logEventsCompressed(ctx, http_client, events, arg)
+	} else {
+		err = client.LogEvents(events)
+	}
 
 	if err != nil {
 		select {
@@ -277,6 +290,54 @@ func send_to_splunk(
 	}
 }
 
+// The Event Collector accepts a gzip compressed body of concatenated
+// events. Rows are very repetitive so this greatly reduces the size
+// of each batch.
+func logEventsCompressed(
+	ctx context.Context, http_client *http.Client,
+	events []*splunk.Event, arg *_SplunkPluginArgs) error {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	for _, event := range events {
+		serialized, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		_, err = writer.Write(append(serialized, '\n'))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := writer.Close()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", arg.URL, buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("Authorization", "Splunk "+arg.Token)
+
+	resp, err := http_client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%v: %v", resp.Status, string(body))
+	}
+
+	return nil
+}
+
 func (self _SplunkPlugin) Info(
 	scope vfilter.Scope,
 	type_map *vfilter.TypeMap) *vfilter.PluginInfo {
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/clayscode/Go-Splunk-HTTP/splunk/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+func TestSplunkCompressedEvents(t *testing.T) {
+	var headers http.Header
+	var events []*ordereddict.Dict
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header.Clone()
+
+			reader, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+
+			scanner := bufio.NewScanner(reader)
+			for scanner.Scan() {
+				event := ordereddict.NewDict()
+				require.NoError(t, event.UnmarshalJSON(scanner.Bytes()))
+				events = append(events, event)
+			}
+			require.NoError(t, scanner.Err())
+
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer server.Close()
+
+	arg := &_SplunkPluginArgs{
+		URL:   server.URL + "/services/collector",
+		Token: "secret",
+	}
+
+	client := splunk.NewClient(server.Client(), arg.URL, arg.Token,
+		"velociraptor", "vql", "main", "server")
+
+	err := logEventsCompressed(context.Background(), server.Client(),
+		[]*splunk.Event{
+			client.NewEvent(ordereddict.NewDict().Set("Pid", 1),
+				"velociraptor", "vql", "main", "host1"),
+			client.NewEvent(ordereddict.NewDict().Set("Pid", 2),
+				"velociraptor", "vql", "main", "host2"),
+		}, arg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "gzip", headers.Get("Content-Encoding"))
+	assert.Equal(t, "Splunk secret", headers.Get("Authorization"))
+
+	// Each event is sent as a separate JSON object.
+	require.Equal(t, 2, len(events))
+	for idx, event := range events {
+		host, _ := event.GetString("host")
+		assert.Equal(t, []string{"host1", "host2"}[idx], host)
+
+		index, _ := event.GetString("index")
+		assert.Equal(t, "main", index)
+
+		data, _ := event.Get("event")
+		assert.Equal(t, []string{`{"Pid":1}`, `{"Pid":2}`}[idx],
+			json.MustMarshalString(data))
+	}
+}
+
+func TestSplunkCompressedEventsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"text":"Invalid token","code":4}`))
+		}))
+	defer server.Close()
+
+	arg := &_SplunkPluginArgs{URL: server.URL, Token: "wrong"}
+	client := splunk.NewClient(server.Client(), arg.URL, arg.Token,
+		"velociraptor", "vql", "main", "server")
+
+	err := logEventsCompressed(context.Background(), server.Client(),
+		[]*splunk.Event{
+			client.NewEvent(ordereddict.NewDict(),
+				"velociraptor", "vql", "main", "host1"),
+		}, arg)
+	require.Error(t, err)
+
+	// The status and the server's message are reported.
+	assert.Contains(t, err.Error(), "403 Forbidden")
+	assert.Contains(t, err.Error(), "Invalid token")
+}
@@ -86,7 +86,8 @@ func (self *AddTimelineFunction) Call(ctx context.Context,
 
 	// Timelines have to be sorted, so we force them to be sorted
 	// by the key.
-	sorter := sorter.MergeSorter{10000}
+	tmpdir, chunk_size := sorter.GetSpillOptions(config_obj)
+	sorter := sorter.MergeSorter{ChunkSize: chunk_size, TempDir: tmpdir}
 	sorted_chan := sorter.Sort(sub_ctx, subscope, arg.Query.Eval(sub_ctx, subscope),
 		arg.Key, false /* desc */)
 
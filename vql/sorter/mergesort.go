@@ -9,18 +9,40 @@ import (
 	"sync"
 
 	"github.com/Velocidex/ordereddict"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/vfilter"
 	vsort "www.velocidex.com/golang/vfilter/sort"
 	"www.velocidex.com/golang/vfilter/types"
 )
 
+const (
+	DEFAULT_CHUNK_SIZE = 10000
+)
+
 // Implements a file based merge sort algorithm. This is important to
 // limit memory use with large data sets and ORDER BY queries
 
 type MergeSorter struct {
 	ChunkSize int
+
+	// Where to write the chunks (default system temp dir).
+	TempDir string
+}
+
+// Sort and materialize options from the config file.
+func GetSpillOptions(config_obj *config_proto.Config) (
+	tmpdir string, chunk_size int) {
+	chunk_size = DEFAULT_CHUNK_SIZE
+	if config_obj != nil && config_obj.Defaults != nil {
+		tmpdir = config_obj.Defaults.VqlScratchDir
+		if config_obj.Defaults.MaxInMemoryRows > 0 {
+			chunk_size = int(config_obj.Defaults.MaxInMemoryRows)
+		}
+	}
+	return tmpdir, chunk_size
 }
 
 func (self MergeSorter) Sort(ctx context.Context,
@@ -47,6 +69,8 @@ func (self MergeSorter) sortWithCtx(ctx context.Context,
 			Desc:    desc,
 		},
 		ChunkSize: self.ChunkSize,
+		TempDir:   self.TempDir,
+		budget:    vql_subsystem.GetMemoryBudget(scope),
 	}
 
 	go func() {
@@ -85,7 +109,13 @@ type MergeSorterCtx struct {
 
 	// Fallback size to files
 	ChunkSize int
+	TempDir   string
 	idx       int
+
+	// The in memory chunk is charged to the query's memory budget
+	// (if any).
+	budget  *vql_subsystem.MemoryBudget
+	charged uint64
 }
 
 func (self *MergeSorterCtx) addProvider(p provider) {
@@ -100,7 +130,22 @@ func (self *MergeSorterCtx) Feed(row types.Row) {
 
 	self.memory_sorter.Items = append(self.memory_sorter.Items, row)
 
+	// If the budget is exceeded the query is cancelled so we stop
+	// being fed.
+	if self.budget != nil {
+		size := vql_subsystem.EstimateSize(row)
+		if self.budget.Charge("sort", size) == nil {
+			self.charged += size
+		}
+	}
+
 	if len(self.memory_sorter.Items) >= self.ChunkSize {
+		// The chunk is about to be written to disk.
+		if self.budget != nil {
+			self.budget.Release(self.charged)
+			self.charged = 0
+		}
+
 		// Replace the embedded sorter context.
 		memory_sorter := self.memory_sorter
 		sort.Sort(memory_sorter)
@@ -122,7 +167,7 @@ func (self *MergeSorterCtx) Feed(row types.Row) {
 			new_data_file := newDataFile(
 				memory_sorter.Scope,
 				memory_sorter.Items,
-				memory_sorter.OrderBy)
+				memory_sorter.OrderBy, self.TempDir)
 
 			self.addProvider(new_data_file)
 		}()
@@ -158,6 +203,11 @@ func (self *MergeSorterCtx) Merge(ctx context.Context, output_chan chan types.Ro
 		for _, provider := range self.merge_files {
 			provider.Close()
 		}
+
+		if self.budget != nil {
+			self.budget.Release(self.charged)
+			self.charged = 0
+		}
 	}()
 
 	// Wait for all the chunks to be ready.
@@ -297,13 +347,14 @@ func (self *dataFile) Consume() {
 	self.lastValue = item
 }
 
-func newDataFile(scope types.Scope, items []types.Row, key string) *dataFile {
+func newDataFile(scope types.Scope, items []types.Row,
+	key, tmpdir string) *dataFile {
 	result := &dataFile{
 		scope: scope,
 		key:   key,
 	}
 
-	tmpfile, err := ioutil.TempFile("", "vql")
+	tmpfile, err := ioutil.TempFile(tmpdir, "vql")
 	if err != nil {
 		scope.Log("Unable to create tempfile: %v", err)
 		return result
@@ -24,7 +24,7 @@ func TestDataFile(t *testing.T) {
 		ordereddict.NewDict().Set("X", 2),
 	}
 
-	data_file := newDataFile(scope, rows, "X")
+	data_file := newDataFile(scope, rows, "X", "")
 	defer data_file.Close()
 
 	// Check the content of the backing file.
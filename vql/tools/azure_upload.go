@@ -0,0 +1,319 @@
+//+build extras
+
+package tools
+
+// Upload to Azure blob storage using the Blob REST API. Credentials
+// are given as a container SAS URL, so collectors only need a token
+// with create/write permission on the container rather than the
+// storage account key.
+
+// Files are uploaded as a block blob with Put Block and committed
+// with Put Block List so large collections do not need to be
+// buffered in memory.
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vql/networking"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+const (
+	AZURE_API_VERSION = "2020-10-02"
+
+	// Size of each uploaded block. A blob may have at most 50000
+	// blocks so this allows blobs up to about 400GB.
+	AZURE_BLOCK_SIZE = 8 * 1024 * 1024
+)
+
+type AzureUploadArgs struct {
+	File         *accessors.OSPath `vfilter:"required,field=file,doc=The file to upload"`
+	Name         string            `vfilter:"optional,field=name,doc=The name of the file that should be stored on the server"`
+	Accessor     string            `vfilter:"optional,field=accessor,doc=The accessor to use"`
+	SasUrl       string            `vfilter:"required,field=sas_url,doc=A SAS URL for the container to upload to (e.g. https://account.blob.core.windows.net/container?sv=...)"`
+	NoVerifyCert bool              `vfilter:"optional,field=noverifycert,doc=Skip TLS Verification"`
+}
+
+type AzureUploadFunction struct{}
+
+func (self *AzureUploadFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &AzureUploadArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("upload_azure: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	err = vql_subsystem.CheckFilesystemAccess(scope, arg.Accessor)
+	if err != nil {
+		scope.Log("upload_azure: %s", err)
+		return vfilter.Null{}
+	}
+
+	accessor, err := accessors.GetAccessor(arg.Accessor, scope)
+	if err != nil {
+		scope.Log("upload_azure: %v", err)
+		return vfilter.Null{}
+	}
+
+	file, err := accessor.OpenWithOSPath(arg.File)
+	if err != nil {
+		scope.Log("upload_azure: Unable to open %s: %s",
+			arg.File, err.Error())
+		return &vfilter.Null{}
+	}
+	defer file.Close()
+
+	if arg.Name == "" {
+		arg.Name = arg.File.String()
+	}
+
+	stat, err := accessor.LstatWithOSPath(arg.File)
+	if err != nil {
+		scope.Log("upload_azure: Unable to stat %s: %v",
+			arg.File, err)
+	} else if !stat.IsDir() {
+		// Abort uploading when the scope is destroyed.
+		sub_ctx, cancel := context.WithCancel(ctx)
+		_ = scope.AddDestructor(cancel)
+
+		upload_response, err := upload_azure(
+			sub_ctx, scope, file, arg.Name, arg.SasUrl, arg.NoVerifyCert)
+		if err != nil {
+			scope.Log("upload_azure: %v", err)
+			return vfilter.Null{}
+		}
+		return upload_response
+	}
+
+	return vfilter.Null{}
+}
+
+// Build the url of the blob from the container's SAS url. The SAS
+// token is kept in the query string.
+func getAzureBlobUrl(sas_url, name string) (*url.URL, error) {
+	parsed, err := url.Parse(sas_url)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("Invalid SAS url %v", redactSasUrl(parsed))
+	}
+
+	if parsed.Query().Get("sig") == "" {
+		return nil, fmt.Errorf("SAS url does not contain a signature")
+	}
+
+	components := []string{}
+	for _, component := range strings.Split(name, "/") {
+		if component != "" {
+			components = append(components, component)
+		}
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("Invalid blob name %v", name)
+	}
+
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/" +
+		strings.Join(components, "/")
+	parsed.RawPath = ""
+
+	return parsed, nil
+}
+
+// Never log the SAS token.
+func redactSasUrl(blob_url *url.URL) string {
+	redacted := *blob_url
+	redacted.RawQuery = ""
+	redacted.User = nil
+	return redacted.String()
+}
+
+func azureRequest(ctx context.Context, client *http.Client,
+	blob_url *url.URL, params url.Values, body []byte,
+	headers map[string]string) error {
+	request_url := *blob_url
+	query := request_url.Query()
+	for k, v := range params {
+		query[k] = v
+	}
+	request_url.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut,
+		request_url.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = int64(len(body))
+	req.Header.Set("x-ms-version", AZURE_API_VERSION)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error contains the url with the SAS token.
+		url_err := &url.Error{}
+		if errors.As(err, &url_err) {
+			return fmt.Errorf("%v %v: %w", url_err.Op,
+				redactSasUrl(blob_url), url_err.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		// The error body is a short XML document describing the
+		// failure.
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("Azure returned HTTP status %v: %v",
+			resp.StatusCode, string(message))
+	}
+	return nil
+}
+
+func upload_azure(ctx context.Context, scope vfilter.Scope,
+	reader io.Reader,
+	name string,
+	sas_url string,
+	NoVerifyCert bool) (
+	*uploads.UploadResponse, error) {
+
+	blob_url, err := getAzureBlobUrl(sas_url, name)
+	if err != nil {
+		return &uploads.UploadResponse{
+			Error: err.Error(),
+		}, err
+	}
+
+	scope.Log("upload_azure: Uploading %v to %v", name, redactSasUrl(blob_url))
+
+	tlsConfig := &tls.Config{}
+	if NoVerifyCert {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy: networking.GetProxy(),
+			DialContext: (&net.Dialer{
+				Timeout: 30 * time.Second, // TCP connect timeout
+			}).DialContext,
+			TLSHandshakeTimeout: 30 * time.Second,
+			TLSClientConfig:     tlsConfig,
+		},
+	}
+
+	sha_sum := sha256.New()
+	md5_sum := md5.New()
+	log_writer := &vql_subsystem.LogWriter{
+		Scope:   scope,
+		Message: "upload_azure " + name}
+
+	block_list := &bytes.Buffer{}
+	block_list.WriteString(`<?xml version="1.0" encoding="utf-8"?><BlockList>`)
+
+	buffer := make([]byte, AZURE_BLOCK_SIZE)
+	var offset uint64
+
+	for idx := 0; ; idx++ {
+		n, err := io.ReadFull(reader, buffer)
+		if n > 0 {
+			block := buffer[:n]
+
+			// Block ids must all have the same length.
+			block_id := base64.StdEncoding.EncodeToString(
+				[]byte(fmt.Sprintf("%08d", idx)))
+
+			err := azureRequest(ctx, client, blob_url, url.Values{
+				"comp":    []string{"block"},
+				"blockid": []string{block_id},
+			}, block, nil)
+			if err != nil {
+				return &uploads.UploadResponse{
+					Error: err.Error(),
+				}, err
+			}
+
+			_, _ = sha_sum.Write(block)
+			_, _ = md5_sum.Write(block)
+			_, _ = log_writer.Write(block)
+
+			fmt.Fprintf(block_list, "<Latest>%s</Latest>", block_id)
+			offset += uint64(n)
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return &uploads.UploadResponse{
+				Error: err.Error(),
+			}, err
+		}
+	}
+
+	block_list.WriteString("</BlockList>")
+
+	// Committing the block list creates the blob.
+	err = azureRequest(ctx, client, blob_url, url.Values{
+		"comp": []string{"blocklist"},
+	}, block_list.Bytes(), map[string]string{
+		"Content-Type":           "application/xml",
+		"x-ms-blob-content-type": "application/octet-stream",
+		"x-ms-blob-content-md5": base64.StdEncoding.EncodeToString(
+			md5_sum.Sum(nil)),
+	})
+	if err != nil {
+		return &uploads.UploadResponse{
+			Error: err.Error(),
+		}, err
+	}
+
+	scope.Log("upload_azure: SUCCESS writing %v bytes to %v",
+		offset, redactSasUrl(blob_url))
+
+	return &uploads.UploadResponse{
+		Path:   name,
+		Size:   offset,
+		Sha256: hex.EncodeToString(sha_sum.Sum(nil)),
+		Md5:    hex.EncodeToString(md5_sum.Sum(nil)),
+	}, nil
+}
+
+func (self AzureUploadFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "upload_azure",
+		Doc:     "Upload files to Azure blob storage.",
+		ArgType: type_map.AddType(scope, &AzureUploadArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&AzureUploadFunction{})
+}
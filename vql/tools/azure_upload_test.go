@@ -0,0 +1,104 @@
+//+build extras
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+// Emulates the Put Block and Put Block List calls.
+type testAzureServer struct {
+	mu     sync.Mutex
+	blocks map[string][]byte
+	blobs  map[string][]byte
+	md5    string
+}
+
+func (self *testAzureServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if r.URL.Query().Get("sig") != "secret" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	switch r.URL.Query().Get("comp") {
+	case "block":
+		self.blocks[r.URL.Query().Get("blockid")] = body
+
+	case "blocklist":
+		blob := &bytes.Buffer{}
+		for _, item := range strings.Split(string(body), "<Latest>")[1:] {
+			block_id := strings.Split(item, "</Latest>")[0]
+			blob.Write(self.blocks[block_id])
+		}
+		self.blobs[r.URL.Path] = blob.Bytes()
+		self.md5 = r.Header.Get("x-ms-blob-content-md5")
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestAzureUpload(t *testing.T) {
+	azure := &testAzureServer{
+		blocks: make(map[string][]byte),
+		blobs:  make(map[string][]byte),
+	}
+	server := httptest.NewServer(azure)
+	defer server.Close()
+
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	data := bytes.Repeat([]byte("hello"), AZURE_BLOCK_SIZE/4)
+	response, err := upload_azure(context.Background(), scope,
+		bytes.NewReader(data), "/dir/file.txt",
+		server.URL+"/container?sv=2020&sig=secret", false)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(data)), response.Size)
+
+	// The file was split into blocks and committed.
+	assert.Equal(t, 2, len(azure.blocks))
+	assert.Equal(t, data, azure.blobs["/container/dir/file.txt"])
+
+	md5_sum := md5.Sum(data)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(md5_sum[:]), azure.md5)
+}
+
+func TestAzureUploadRedactsSasToken(t *testing.T) {
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	// Nothing is listening on the server.
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	_, err := upload_azure(context.Background(), scope,
+		bytes.NewReader([]byte("hello")), "file.txt",
+		server.URL+"/container?sv=2020&sig=secret", false)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+	assert.Contains(t, err.Error(), "/container/file.txt")
+
+	// SAS urls must be signed.
+	_, err = getAzureBlobUrl("https://account/container?sv=2020", "file.txt")
+	assert.Error(t, err)
+}
@@ -0,0 +1,356 @@
+// Checkpoints allow an interrupted collection to be resumed.
+
+// The checkpoint file records the queries which completed and the
+// files which were uploaded as JSON lines. Each line is synced as
+// soon as it is written. When the same collection is started again
+// with the checkpoint, the completed results and uploads are copied
+// from the previous container and only the remaining work is done.
+//
+// The previous container must have been closed properly (e.g. the
+// collection was cancelled or timed out) - if it can not be read the
+// collection starts over. The checkpoint is removed once the
+// collection completes.
+
+package collector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/uploads"
+	"www.velocidex.com/golang/velociraptor/utils"
+	"www.velocidex.com/golang/vfilter"
+)
+
+const (
+	CHECKPOINT_HEADER = "collection"
+	CHECKPOINT_QUERY  = "query"
+	CHECKPOINT_UPLOAD = "upload"
+)
+
+type checkpointRecord struct {
+	Type string `json:"type"`
+
+	// Describes the collection in the header record.
+	Output    string   `json:"output,omitempty"`
+	Artifacts []string `json:"artifacts,omitempty"`
+	Format    string   `json:"format,omitempty"`
+
+	// A completed query.
+	Name string `json:"name,omitempty"`
+	Rows int    `json:"rows,omitempty"`
+
+	// A completed upload.
+	Key    string                  `json:"key,omitempty"`
+	Upload *uploads.UploadResponse `json:"upload,omitempty"`
+}
+
+type checkpoint struct {
+	mu sync.Mutex
+
+	path   string
+	header *checkpointRecord
+	fd     *os.File
+
+	// Rows collected by each completed query.
+	queries map[string]int
+
+	// Uploads by key, and the keys in the order they were uploaded.
+	uploads     map[string]*uploads.UploadResponse
+	upload_keys []string
+}
+
+// Open the checkpoint at path, loading the progress of a previous
+// run of the same collection.
+func openCheckpoint(path string, header *checkpointRecord) (*checkpoint, error) {
+	result := &checkpoint{
+		path:    path,
+		header:  header,
+		queries: make(map[string]int),
+		uploads: make(map[string]*uploads.UploadResponse),
+	}
+
+	records, size, err := readCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 {
+		previous := records[0]
+		if previous.Type != CHECKPOINT_HEADER ||
+			previous.Output != header.Output ||
+			previous.Format != header.Format ||
+			!utils.StringSliceEq(previous.Artifacts, header.Artifacts) {
+			return nil, fmt.Errorf(
+				"Checkpoint %v belongs to a different collection", path)
+		}
+
+		for _, record := range records[1:] {
+			switch record.Type {
+			case CHECKPOINT_QUERY:
+				result.queries[record.Name] = record.Rows
+
+			case CHECKPOINT_UPLOAD:
+				if record.Upload != nil {
+					result.addUpload(record.Key, record.Upload)
+				}
+			}
+		}
+	}
+
+	result.fd, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop any partially written last line.
+	err = result.fd.Truncate(size)
+	if err != nil {
+		result.fd.Close()
+		return nil, err
+	}
+
+	_, err = result.fd.Seek(size, io.SeekStart)
+	if err != nil {
+		result.fd.Close()
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		err = result.write(header)
+		if err != nil {
+			result.fd.Close()
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Read the records in the checkpoint and the size of the complete
+// lines.
+func readCheckpoint(path string) ([]*checkpointRecord, int64, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	defer fd.Close()
+
+	var result []*checkpointRecord
+	var size int64
+
+	reader := bufio.NewReader(fd)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return result, size, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		record := &checkpointRecord{}
+		err = json.Unmarshal(line, record)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Corrupted checkpoint %v: %w", path, err)
+		}
+		result = append(result, record)
+		size += int64(len(line))
+	}
+}
+
+func (self *checkpoint) write(record *checkpointRecord) error {
+	serialized, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.fd.Write(append(serialized, '\n'))
+	if err != nil {
+		return err
+	}
+	return self.fd.Sync()
+}
+
+func (self *checkpoint) addUpload(key string, upload *uploads.UploadResponse) {
+	_, pres := self.uploads[key]
+	if !pres {
+		self.upload_keys = append(self.upload_keys, key)
+	}
+	self.uploads[key] = upload
+}
+
+// Is there any progress from a previous run?
+func (self *checkpoint) IsResuming() bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return len(self.queries) > 0 || len(self.uploads) > 0
+}
+
+// Forget all previous progress and start over.
+func (self *checkpoint) Reset() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.queries = make(map[string]int)
+	self.uploads = make(map[string]*uploads.UploadResponse)
+	self.upload_keys = nil
+
+	err := self.fd.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = self.fd.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	return self.write(self.header)
+}
+
+// The names of the completed queries.
+func (self *checkpoint) Queries() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := make([]string, 0, len(self.queries))
+	for name := range self.queries {
+		result = append(result, name)
+	}
+	return result
+}
+
+func (self *checkpoint) QueryRows(name string) (int, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	rows, pres := self.queries[name]
+	return rows, pres
+}
+
+func (self *checkpoint) AddQuery(name string, rows int) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.queries[name] = rows
+	return self.write(&checkpointRecord{
+		Type: CHECKPOINT_QUERY,
+		Name: name,
+		Rows: rows,
+	})
+}
+
+// The keys of the completed uploads in the order they were made.
+func (self *checkpoint) UploadKeys() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return utils.CopySlice(self.upload_keys)
+}
+
+func (self *checkpoint) GetUpload(key string) (*uploads.UploadResponse, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	upload, pres := self.uploads[key]
+	return upload, pres
+}
+
+func (self *checkpoint) AddUpload(key string, upload *uploads.UploadResponse) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.addUpload(key, upload)
+	return self.write(&checkpointRecord{
+		Type:   CHECKPOINT_UPLOAD,
+		Key:    key,
+		Upload: upload,
+	})
+}
+
+// Forget an upload which is missing from the previous container so
+// it is uploaded again.
+func (self *checkpoint) DropUpload(key string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	delete(self.uploads, key)
+
+	keys := make([]string, 0, len(self.upload_keys))
+	for _, k := range self.upload_keys {
+		if k != key {
+			keys = append(keys, k)
+		}
+	}
+	self.upload_keys = keys
+}
+
+func (self *checkpoint) Close() error {
+	return self.fd.Close()
+}
+
+// The collection is complete - the checkpoint is no longer needed.
+func (self *checkpoint) Remove() error {
+	self.fd.Close()
+	return os.Remove(self.path)
+}
+
+// Wraps the container to skip files which were uploaded by a
+// previous run and to record new uploads in the checkpoint.
+type checkpointUploader struct {
+	uploader   uploads.Uploader
+	checkpoint *checkpoint
+}
+
+func (self *checkpointUploader) Upload(
+	ctx context.Context,
+	scope vfilter.Scope,
+	filename *accessors.OSPath,
+	accessor string,
+	store_as_name *accessors.OSPath,
+	expected_size int64,
+	mtime time.Time,
+	atime time.Time,
+	ctime time.Time,
+	btime time.Time,
+	reader io.Reader) (*uploads.UploadResponse, error) {
+
+	name := store_as_name
+	if name == nil {
+		name = filename
+	}
+	key := accessor + ":" + name.String()
+
+	previous, pres := self.checkpoint.GetUpload(key)
+	if pres {
+		scope.Log("Skipping %v: already collected into %v",
+			previous.Path, previous.StoredName)
+		return previous, nil
+	}
+
+	result, err := self.uploader.Upload(ctx, scope, filename, accessor,
+		store_as_name, expected_size, mtime, atime, ctime, btime, reader)
+	if err != nil || result.Error != "" || ctx.Err() != nil {
+		return result, err
+	}
+
+	err = self.checkpoint.AddUpload(key, result)
+	if err != nil {
+		scope.Log("collect: Unable to update checkpoint: %v", err)
+	}
+
+	return result, nil
+}
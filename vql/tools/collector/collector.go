@@ -35,7 +35,7 @@ type CollectPluginArgs struct {
 	Report              string              `vfilter:"optional,field=report,doc=A path to write the report on (deprecated and ignored)."`
 	Args                vfilter.Any         `vfilter:"optional,field=args,doc=Optional parameters."`
 	Password            string              `vfilter:"optional,field=password,doc=An optional password to encrypt the collection zip."`
-	Format              string              `vfilter:"optional,field=format,doc=Output format (csv, jsonl, csv_only)."`
+	Format              string              `vfilter:"optional,field=format,doc=Output format (csv, jsonl, csv_only, sqlite)."`
 	ArtifactDefinitions vfilter.Any         `vfilter:"optional,field=artifact_definitions,doc=Optional additional custom artifacts."`
 	Template            string              `vfilter:"optional,field=template,doc=The name of a template artifact (i.e. one which has report of type HTML)."`
 	Level               int64               `vfilter:"optional,field=level,doc=Compression level between 0 (no compression) and 9."`
@@ -45,6 +45,8 @@ type CollectPluginArgs struct {
 	ProgressTimeout     float64             `vfilter:"optional,field=progress_timeout,doc=If no progress is detected in this many seconds, we terminate the query and output debugging information"`
 	Timeout             float64             `vfilter:"optional,field=timeout,doc=Total amount of time in seconds, this collection will take. Collection is cancelled when timeout is exceeded."`
 	Metadata            vfilter.StoredQuery `vfilter:"optional,field=metadata,doc=Metadata to store in the zip archive. Outputs to metadata.json in top level of zip file."`
+	Workers             int64               `vfilter:"optional,field=workers,doc=Collect up to this many artifacts concurrently (default 1)."`
+	Checkpoint          string              `vfilter:"optional,field=checkpoint,doc=Record the progress in this file. If the collection is interrupted, collecting again with the same checkpoint resumes it."`
 }
 
 type CollectPlugin struct{}
@@ -128,10 +130,16 @@ func (self CollectPlugin) configureCollection(
 		manager.SetTimeout(arg.Timeout * 1e9)
 	}
 
+	manager.SetWorkers(arg.Workers)
+
 	// Apply a throttler if needed.
 	manager.AddThrottler(float64(arg.OpsPerSecond), arg.CpuLimit,
 		arg.IopsLimit, arg.ProgressTimeout)
 
+	if arg.Checkpoint != "" && arg.Output == "" {
+		return nil, errors.New("A checkpoint requires an output container")
+	}
+
 	// If required create an output container
 	if arg.Output != "" {
 
@@ -140,6 +148,23 @@ func (self CollectPlugin) configureCollection(
 			manager.SetMetadata(arg.Metadata)
 		}
 
+		if arg.Checkpoint != "" {
+			if arg.Password != "" || format == reporting.ContainerFormatSQLite {
+				return nil, errors.New(
+					"Checkpoints can not be used with password protected or SQLite containers")
+			}
+
+			err = manager.SetCheckpoint(arg.Checkpoint, &checkpointRecord{
+				Type:      CHECKPOINT_HEADER,
+				Output:    arg.Output,
+				Artifacts: arg.Artifacts,
+				Format:    arg.Format,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		// Build the container to receive the output from the
 		// queries. The container may be password protected.
 		err = manager.MakeContainer(arg.Output, arg.Password, arg.Level)
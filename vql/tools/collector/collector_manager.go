@@ -2,11 +2,15 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"log"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/Velocidex/ordereddict"
+	"github.com/alexmullins/zip"
 	"github.com/shirou/gopsutil/v3/host"
 	"www.velocidex.com/golang/velociraptor/actions"
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
@@ -24,6 +28,7 @@ import (
 	"www.velocidex.com/golang/velociraptor/reporting"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/uploads"
 	"www.velocidex.com/golang/velociraptor/utils"
 	"www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vql/acl_managers"
@@ -45,10 +50,17 @@ type collectionManager struct {
 	Output           string
 	log_file         *reporting.ContainerResultSetWriter
 
-	start_time         time.Time
+	start_time time.Time
+
+	// Protects the collection_context when artifacts are collected
+	// concurrently.
+	stats_mu           sync.Mutex
 	collection_context *flows.CollectionContext
 	logger             *logWriter
 
+	// How many artifacts to collect at the same time.
+	workers int
+
 	// The VQL requests we actuall collected. We store those in the
 	// container for provenance.
 	requests api_proto.ApiFlowRequestDetails
@@ -60,6 +72,17 @@ type collectionManager struct {
 	format reporting.ContainerFormat
 
 	scope vfilter.Scope
+
+	// Installed into each artifact's scope so the limits apply to
+	// all queries in the collection.
+	throttler types.Throttler
+
+	// Records the progress so an interrupted collection can be
+	// resumed.
+	checkpoint *checkpoint
+
+	// Set when all the artifacts were collected.
+	completed bool
 }
 
 func (self *collectionManager) GetRepository(extra_artifacts vfilter.Any) (err error) {
@@ -170,6 +193,7 @@ func (self *collectionManager) AddThrottler(
 	}
 
 	self.scope.SetThrottler(throttler)
+	self.throttler = throttler
 }
 
 func (self *collectionManager) SetMetadata(metadata vfilter.StoredQuery) {
@@ -248,11 +272,28 @@ func (self *collectionManager) collectQuery(
 	defer func() {
 		status.Duration = Clock.Now().UnixNano() - query_start_time.UnixNano()
 
+		self.stats_mu.Lock()
 		self.collection_context.QueryStats = append(
 			self.collection_context.QueryStats, status)
 		self.collection_context.TotalCollectedRows += uint64(status.ResultRows)
+		self.stats_mu.Unlock()
 	}()
 
+	// This query was completed by a previous run.
+	if self.checkpoint != nil && query.Name != "" {
+		rows, pres := self.checkpoint.QueryRows(query.Name)
+		if pres {
+			subscope.Log("Skipping %s: already collected %v rows",
+				query.Name, rows)
+			status.ResultRows = int64(rows)
+			if rows > 0 {
+				status.NamesWithResponse = append(
+					status.NamesWithResponse, query.Name)
+			}
+			return nil
+		}
+	}
+
 	// Useful to know what is going on with the collection.
 	if query.Name != "" {
 		subscope.Log("Starting collection of %s", query.Name)
@@ -303,6 +344,14 @@ func (self *collectionManager) collectQuery(
 		subscope.Log("Collected %v rows for %s", total_rows, query.Name)
 	}
 
+	// Results of a cancelled query are incomplete.
+	if self.checkpoint != nil && query.Name != "" && self.ctx.Err() == nil {
+		err = self.checkpoint.AddQuery(query.Name, total_rows)
+		if err != nil {
+			subscope.Log("collect: Unable to update checkpoint: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -317,6 +366,12 @@ func (self *collectionManager) Collect(request *flows_proto.ArtifactCollectorArg
 	// existing scope but override the uploader with the container.
 	builder := services.ScopeBuilderFromScope(self.scope)
 	builder.Uploader = self.container
+	if self.checkpoint != nil {
+		builder.Uploader = &checkpointUploader{
+			uploader:   self.container,
+			checkpoint: self.checkpoint,
+		}
+	}
 
 	if self.log_file != nil {
 		self.logger = &logWriter{
@@ -346,47 +401,184 @@ func (self *collectionManager) Collect(request *flows_proto.ArtifactCollectorArg
 		return err
 	}
 
-	// Run each collection separately, one after the other.
+	// Emulate the same type of requests a client would receive so
+	// the import is smoother.
 	for request_number, vql_request := range vql_requests {
-
-		// Emulate the same type of requests a client would receive so
-		// the import is smoother.
 		self.requests.Items = append(self.requests.Items,
 			&crypto_proto.VeloMessage{
 				SessionId:       self.collection_context.SessionId,
 				RequestId:       uint64(request_number + 1),
 				VQLClientAction: vql_request})
+	}
 
-		// Make a new scope for each artifact.
-		manager, err := services.GetRepositoryManager(self.config_obj)
+	if self.workers <= 1 {
+		// Run each collection separately, one after the other.
+		for _, vql_request := range vql_requests {
+			err := self.collectRequest(builder, vql_request)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		err = self.collectConcurrently(builder, vql_requests)
 		if err != nil {
 			return err
 		}
+	}
+
+	self.completed = self.ctx.Err() == nil
+	return nil
+}
 
-		// Create a new environment for each request.
-		env := ordereddict.NewDict()
-		for _, env_spec := range vql_request.Env {
-			env.Set(env_spec.Key, env_spec.Value)
+// Collect the artifacts in a bounded pool of workers. The queries of
+// each artifact still run in order.
+func (self *collectionManager) collectConcurrently(
+	builder services.ScopeBuilder,
+	vql_requests []*actions_proto.VQLCollectorArgs) error {
+
+	var mu sync.Mutex
+	var first_err error
+	completed := 0
+
+	wg := &sync.WaitGroup{}
+	pool := make(chan bool, self.workers)
+
+	for _, vql_request := range vql_requests {
+		// Stop scheduling artifacts after the first error, like
+		// the sequential collection does.
+		mu.Lock()
+		failed := first_err != nil
+		mu.Unlock()
+		if failed {
+			break
 		}
 
-		subscope := manager.BuildScope(builder)
-		subscope.AppendVars(env)
-		defer subscope.Close()
+		select {
+		case <-self.ctx.Done():
+			break
+		case pool <- true:
+		}
 
-		self.collection_context.TotalRequests = int64(len(vql_request.Query))
+		if self.ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(vql_request *actions_proto.VQLCollectorArgs) {
+			defer wg.Done()
+			defer func() { <-pool }()
+
+			name := requestName(vql_request)
+			start := Clock.Now()
+			err := self.collectRequest(builder, vql_request)
+
+			mu.Lock()
+			defer mu.Unlock()
 
-		// Run each query and store the results in the container
-		for _, query := range vql_request.Query {
-			err := self.collectQuery(subscope, query)
+			completed++
 			if err != nil {
-				return err
+				if first_err == nil {
+					first_err = err
+				}
+				self.scope.Log("collect: [%v/%v] <red>Failed</> %v: %v",
+					completed, len(vql_requests), name, err)
+				return
 			}
+
+			self.scope.Log("collect: [%v/%v] Completed %v in %v",
+				completed, len(vql_requests), name,
+				Clock.Now().Sub(start).Round(time.Millisecond))
+		}(vql_request)
+	}
+
+	wg.Wait()
+
+	return first_err
+}
+
+// Run all the queries of a single artifact in order.
+func (self *collectionManager) collectRequest(
+	builder services.ScopeBuilder,
+	vql_request *actions_proto.VQLCollectorArgs) error {
+
+	// Make a new scope for each artifact.
+	manager, err := services.GetRepositoryManager(self.config_obj)
+	if err != nil {
+		return err
+	}
+
+	if self.isRequestCollected(vql_request) {
+		self.scope.Log("collect: Skipping %v: already collected",
+			requestName(vql_request))
+		return nil
+	}
+
+	// Create a new environment for each request.
+	env := ordereddict.NewDict()
+	for _, env_spec := range vql_request.Env {
+		env.Set(env_spec.Key, env_spec.Value)
+	}
+
+	subscope := manager.BuildScope(builder)
+	subscope.AppendVars(env)
+	defer subscope.Close()
+
+	// The new scope does not inherit the throttler from our scope.
+	if self.throttler != nil {
+		subscope.SetThrottler(self.throttler)
+	}
+
+	self.stats_mu.Lock()
+	self.collection_context.TotalRequests = int64(len(vql_request.Query))
+	self.stats_mu.Unlock()
+
+	// Run each query and store the results in the container
+	for _, query := range vql_request.Query {
+		err := self.collectQuery(subscope, query)
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// Were all the sources of the artifact collected by a previous run?
+func (self *collectionManager) isRequestCollected(
+	vql_request *actions_proto.VQLCollectorArgs) bool {
+	if self.checkpoint == nil {
+		return false
+	}
+
+	named := 0
+	for _, query := range vql_request.Query {
+		if query.Name == "" {
+			continue
+		}
+
+		named++
+		_, pres := self.checkpoint.QueryRows(query.Name)
+		if !pres {
+			return false
+		}
+	}
+	return named > 0
+}
+
+// The name of the artifact collected by the request.
+func requestName(vql_request *actions_proto.VQLCollectorArgs) string {
+	for _, query := range vql_request.Query {
+		if query.Name != "" {
+			return strings.SplitN(query.Name, "/", 2)[0]
+		}
+	}
+	return "query"
+}
+
+func (self *collectionManager) SetWorkers(workers int64) {
+	self.workers = int(workers)
+}
+
 func (self *collectionManager) SetTimeout(ns float64) {
 	go func() {
 		start := Clock.Now()
@@ -433,8 +625,18 @@ func (self *collectionManager) MakeContainer(filename, password string, level in
 	self.scope.Log("Setting compression level to %v", level)
 
 	self.Output = filename
-	self.container, err = reporting.NewContainer(
-		self.config_obj, filename, password, level, self.metadata)
+	if self.format == reporting.ContainerFormatSQLite {
+		if password != "" {
+			return errors.New("SQLite containers can not be password protected")
+		}
+		self.container, err = reporting.NewSQLiteContainer(
+			self.config_obj, filename, self.metadata)
+	} else if self.checkpoint != nil && self.checkpoint.IsResuming() {
+		self.container, err = self.resumeContainer(filename, level)
+	} else {
+		self.container, err = reporting.NewContainer(
+			self.config_obj, filename, password, level, self.metadata)
+	}
 	if err != nil {
 		return err
 	}
@@ -447,6 +649,116 @@ func (self *collectionManager) MakeContainer(filename, password string, level in
 	return err
 }
 
+// Record the collection progress in the checkpoint file.
+func (self *collectionManager) SetCheckpoint(
+	filename string, header *checkpointRecord) (err error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.checkpoint, err = openCheckpoint(filename, header)
+	return err
+}
+
+// Start a new container with the results and uploads completed by
+// the previous run copied from the old container. Checkpoints can
+// not be used with password protected containers.
+func (self *collectionManager) resumeContainer(
+	filename string, level int64) (*reporting.Container, error) {
+
+	previous := filename + ".partial"
+	err := os.Rename(filename, previous)
+	if err != nil {
+		return self.restartContainer(filename, level, err)
+	}
+	defer os.Remove(previous)
+
+	reader, err := zip.OpenReader(previous)
+	if err != nil {
+		return self.restartContainer(filename, level, err)
+	}
+	defer reader.Close()
+
+	members := make(map[string]*zip.File)
+	for _, member := range reader.File {
+		members[member.Name] = member
+	}
+
+	// Copy the result sets of all completed queries.
+	wanted := make(map[string]bool)
+	for _, name := range self.checkpoint.Queries() {
+		dest := strings.TrimPrefix(path_specs.NewUnsafeFilestorePath(
+			"results").AddChild(name).AsClientPath(), "/")
+		wanted[dest] = true
+		wanted[dest+".index"] = true
+		wanted[strings.TrimSuffix(dest, ".json")+".csv"] = true
+	}
+
+	// Copy the uploads which made it into the container.
+	var restored []*uploads.UploadResponse
+	for _, key := range self.checkpoint.UploadKeys() {
+		upload, _ := self.checkpoint.GetUpload(key)
+		name := strings.TrimPrefix(upload.StoredName, "/")
+		_, pres := members[name]
+		if !pres {
+			self.checkpoint.DropUpload(key)
+			continue
+		}
+		wanted[name] = true
+		restored = append(restored, upload)
+
+		// Sparse files also have an index.
+		idx, pres := members[name+".idx"]
+		if pres {
+			wanted[idx.Name] = true
+			restored = append(restored, &uploads.UploadResponse{
+				Components: utils.CopySlice(upload.Components),
+				StoredName: upload.StoredName + ".idx",
+				Path:       upload.Path + ".idx",
+				Reference:  upload.Reference,
+				Type:       "idx",
+				Size:       idx.UncompressedSize64,
+				StoredSize: idx.UncompressedSize64,
+			})
+		}
+	}
+
+	container, err := reporting.NewContainer(
+		self.config_obj, filename, "", level, self.metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	err = container.CopyMembers(&reader.Reader, func(name string) bool {
+		return wanted[name]
+	})
+	if err != nil {
+		container.Close()
+		return nil, err
+	}
+	container.AddUploads(restored)
+
+	self.scope.Log("collect: Resuming collection from %v with %v "+
+		"queries already collected", self.checkpoint.path,
+		len(self.checkpoint.Queries()))
+
+	return container, nil
+}
+
+// The previous container is unusable so start the collection over.
+func (self *collectionManager) restartContainer(
+	filename string, level int64, reason error) (*reporting.Container, error) {
+	self.scope.Log("collect: Unable to resume from %v: %v - starting over",
+		filename, reason)
+
+	err := self.checkpoint.Reset()
+	if err != nil {
+		return nil, err
+	}
+
+	return reporting.NewContainer(
+		self.config_obj, filename, "", level, self.metadata)
+}
+
 func (self *collectionManager) Close() error {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -491,6 +803,14 @@ func (self *collectionManager) Close() error {
 	// Finalize the container now.
 	err = self.container.Close()
 
+	if self.checkpoint != nil {
+		if self.completed && err == nil {
+			self.checkpoint.Remove()
+		} else {
+			self.checkpoint.Close()
+		}
+	}
+
 	// Emit the result set for consumption by the
 	// rest of the query.
 	select {
@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -337,6 +338,220 @@ func (self *TestSuite) TestCollectionWithUpload() {
 		json.MustMarshalIndent(golden))
 }
 
+func (self *TestSuite) TestCollectionWithWorkers() {
+	output_file, err := ioutil.TempFile(os.TempDir(), "zip")
+	assert.NoError(self.T(), err)
+	output_file.Close()
+	defer os.Remove(output_file.Name())
+
+	builder := services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger:     logging.NewPlainLogger(self.ConfigObj, &logging.FrontendComponent),
+		Env:        ordereddict.NewDict(),
+	}
+
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	scope := manager.BuildScope(builder)
+	defer scope.Close()
+
+	// Both artifacts are collected at the same time into the same
+	// container.
+	args := ordereddict.NewDict().
+		Set("artifacts", []string{
+			"CollectionWithTypes", "Custom.TestArtifactDependent"}).
+		Set("artifact_definitions", CustomTestArtifactDependent).
+		Set("output", output_file.Name()).
+		Set("workers", 2)
+
+	for range (CollectPlugin{}).Call(context.Background(), scope, args) {
+	}
+
+	zip_contents, err := openZipFile(output_file.Name())
+	assert.NoError(self.T(), err)
+
+	for _, name := range []string{
+		"results/CollectionWithTypes.json",
+		"results/Custom.TestArtifactDependent.json"} {
+		rows, pres := zip_contents.Get(name)
+		assert.True(self.T(), pres, name)
+		assert.Equal(self.T(), 1, len(rows.([]*ordereddict.Dict)), name)
+	}
+
+	collection_context := &flows_proto.ArtifactCollectorContext{}
+	serialized, _ := zip_contents.GetString("collection_context.json")
+	err = json.Unmarshal([]byte(serialized), collection_context)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), collection_context.TotalCollectedRows)
+}
+
+// Counts the operations charged to the collection.
+type countingThrottler struct {
+	mu  sync.Mutex
+	ops int
+}
+
+func (self *countingThrottler) ChargeOp() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.ops++
+}
+
+func (self *countingThrottler) Close() {}
+
+func (self *countingThrottler) Count() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.ops
+}
+
+// The collection's throttler applies to the artifact queries: their
+// rows count as progress so the progress timeout does not fire.
+func (self *TestSuite) TestCollectionThrottler() {
+	output_file, err := ioutil.TempFile(os.TempDir(), "zip")
+	assert.NoError(self.T(), err)
+	output_file.Close()
+	defer os.Remove(output_file.Name())
+
+	builder := services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger:     logging.NewPlainLogger(self.ConfigObj, &logging.FrontendComponent),
+		Env:        ordereddict.NewDict(),
+	}
+
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	scope := manager.BuildScope(builder)
+	defer scope.Close()
+
+	output_chan := make(chan vfilter.Row)
+	go func() {
+		for range output_chan {
+		}
+	}()
+	defer close(output_chan)
+
+	collection_manager := newCollectionManager(
+		context.Background(), self.ConfigObj, output_chan, scope)
+
+	request, err := CollectPlugin{}.configureCollection(
+		collection_manager, &CollectPluginArgs{
+			Artifacts: []string{"Custom.TestCountedArtifact"},
+			ArtifactDefinitions: `
+name: Custom.TestCountedArtifact
+sources:
+- query: |
+    SELECT _value AS Value FROM range(end=10)
+`,
+			Output: output_file.Name(),
+		})
+	assert.NoError(self.T(), err)
+
+	throttler := &countingThrottler{}
+	collection_manager.throttler = throttler
+
+	assert.NoError(self.T(), collection_manager.Collect(request))
+	assert.NoError(self.T(), collection_manager.Close())
+
+	// Each row of the artifact query is charged to the collection.
+	assert.True(self.T(), throttler.Count() >= 10)
+}
+
+func (self *TestSuite) TestCollectionWithCheckpoint() {
+	output_file, err := ioutil.TempFile(os.TempDir(), "zip")
+	assert.NoError(self.T(), err)
+	output_file.Close()
+	defer os.Remove(output_file.Name())
+
+	checkpoint_path := output_file.Name() + ".checkpoint"
+	defer os.Remove(checkpoint_path)
+
+	builder := services.ScopeBuilder{
+		Config:     self.ConfigObj,
+		ACLManager: acl_managers.NullACLManager{},
+		Logger:     logging.NewPlainLogger(self.ConfigObj, &logging.FrontendComponent),
+		Env:        ordereddict.NewDict(),
+	}
+
+	manager, err := services.GetRepositoryManager(self.ConfigObj)
+	assert.NoError(self.T(), err)
+
+	scope := manager.BuildScope(builder)
+	defer scope.Close()
+
+	artifacts := []string{
+		"CollectionWithTypes", "Custom.TestArtifactDependent"}
+	collect := func(foo_var string) {
+		args := ordereddict.NewDict().
+			Set("artifacts", artifacts).
+			Set("artifact_definitions", CustomTestArtifactDependent).
+			Set("args", ordereddict.NewDict().
+				Set("Custom.TestArtifactDependent", ordereddict.NewDict().
+					Set("FooVar", foo_var))).
+			Set("output", output_file.Name()).
+			Set("checkpoint", checkpoint_path)
+
+		for range (CollectPlugin{}).Call(context.Background(), scope, args) {
+		}
+	}
+
+	// A complete collection removes the checkpoint.
+	collect("First")
+	_, err = os.Stat(checkpoint_path)
+	assert.True(self.T(), os.IsNotExist(err))
+
+	// Pretend the collection was interrupted after collecting
+	// Custom.TestArtifactDependent.
+	checkpoint, err := openCheckpoint(checkpoint_path, &checkpointRecord{
+		Type:      CHECKPOINT_HEADER,
+		Output:    output_file.Name(),
+		Artifacts: artifacts,
+	})
+	assert.NoError(self.T(), err)
+	assert.NoError(self.T(),
+		checkpoint.AddQuery("Custom.TestArtifactDependent", 1))
+	checkpoint.Close()
+
+	// Resuming keeps the previous results instead of collecting
+	// the artifact again.
+	collect("Second")
+	_, err = os.Stat(checkpoint_path)
+	assert.True(self.T(), os.IsNotExist(err))
+
+	zip_contents, err := openZipFile(output_file.Name())
+	assert.NoError(self.T(), err)
+
+	rows, pres := zip_contents.Get("results/Custom.TestArtifactDependent.json")
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), 1, len(rows.([]*ordereddict.Dict)))
+	foo_var, _ := rows.([]*ordereddict.Dict)[0].GetString("FooVar")
+	assert.Equal(self.T(), "First", foo_var)
+
+	rows, pres = zip_contents.Get("results/CollectionWithTypes.json")
+	assert.True(self.T(), pres)
+	assert.Equal(self.T(), 1, len(rows.([]*ordereddict.Dict)))
+
+	// A checkpoint for a different collection is rejected.
+	checkpoint, err = openCheckpoint(checkpoint_path, &checkpointRecord{
+		Type:      CHECKPOINT_HEADER,
+		Output:    output_file.Name(),
+		Artifacts: []string{"Other"},
+	})
+	assert.NoError(self.T(), err)
+	checkpoint.Close()
+
+	_, err = openCheckpoint(checkpoint_path, &checkpointRecord{
+		Type:      CHECKPOINT_HEADER,
+		Output:    output_file.Name(),
+		Artifacts: artifacts,
+	})
+	assert.Error(self.T(), err)
+}
+
 func readImportedFile(ctx context.Context,
 	scope vfilter.Scope,
 	config_obj *config_proto.Config,
@@ -0,0 +1,201 @@
+package memscan
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/accessors"
+	"www.velocidex.com/golang/velociraptor/accessors/process"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+type ProcScanPluginArgs struct {
+	Pids       []uint64 `vfilter:"required,field=pid,doc=The pids to scan."`
+	Rules      string   `vfilter:"required,field=rules,doc=A rule pack in yaml (e.g. read from a tool)."`
+	Types      []string `vfilter:"optional,field=types,doc=Only scan regions of these types (private, image, mapped)."`
+	Protection string   `vfilter:"optional,field=protection,doc=Only scan regions with all these permissions (e.g. x or rw)."`
+	MaxSize    uint64   `vfilter:"optional,field=max_region_size,doc=Skip regions larger than this (default 100mb)."`
+	Context    int      `vfilter:"optional,field=context,doc=How many bytes to include around each hit (default 64)."`
+	MaxHits    int      `vfilter:"optional,field=number,doc=Stop scanning a process after this many hits (default 100)."`
+	MaxBytes   uint64   `vfilter:"optional,field=max_bytes,doc=Stop scanning a process after this many bytes (default 1gb)."`
+	Timeout    uint64   `vfilter:"optional,field=timeout,doc=Stop scanning a process after this many seconds (default 60)."`
+}
+
+type ProcScanPlugin struct{}
+
+func (self ProcScanPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("proc_scan: %v", err)
+			return
+		}
+
+		arg := &ProcScanPluginArgs{}
+		err = arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+		if err != nil {
+			scope.Log("proc_scan: %v", err)
+			return
+		}
+
+		pack, err := ParseRulePack(arg.Rules)
+		if err != nil {
+			scope.Log("proc_scan: %v", err)
+			return
+		}
+
+		if arg.MaxSize == 0 {
+			arg.MaxSize = 100 * 1024 * 1024
+		}
+
+		if arg.Context == 0 {
+			arg.Context = 64
+		}
+
+		if arg.MaxHits == 0 {
+			arg.MaxHits = 100
+		}
+
+		if arg.MaxBytes == 0 {
+			arg.MaxBytes = 1024 * 1024 * 1024
+		}
+
+		if arg.Timeout == 0 {
+			arg.Timeout = 60
+		}
+
+		filter := process.RegionFilter{
+			Types:      arg.Types,
+			Protection: arg.Protection,
+			MaxSize:    arg.MaxSize,
+		}
+
+		for _, pid := range arg.Pids {
+			if ctx.Err() != nil {
+				return
+			}
+
+			err := scanProcess(ctx, scope, pid, pack, filter, arg, output_chan)
+			if err != nil {
+				scope.Log("proc_scan: pid %v: %v", pid, err)
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+// Each process is scanned with its own time and byte budget so a
+// single large process can not starve the others.
+func scanProcess(ctx context.Context, scope vfilter.Scope,
+	pid uint64, pack *RulePack, filter process.RegionFilter,
+	arg *ProcScanPluginArgs, output_chan chan vfilter.Row) error {
+	sub_ctx, cancel := context.WithTimeout(ctx,
+		time.Duration(arg.Timeout)*time.Second)
+	defer cancel()
+
+	regions, err := process.GetRegions(pid)
+	if err != nil {
+		return err
+	}
+
+	accessor, err := accessors.GetAccessor("process", scope)
+	if err != nil {
+		return err
+	}
+
+	path, err := accessor.ParsePath(fmt.Sprintf("/%d", pid))
+	if err != nil {
+		return err
+	}
+
+	fd, err := accessor.OpenWithOSPath(path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	reader := utils.MakeReaderAtter(fd)
+	scanner := NewScanner(pack, Options{
+		Context:  arg.Context,
+		MaxHits:  arg.MaxHits,
+		MaxBytes: arg.MaxBytes,
+	})
+
+	for _, region := range regions {
+		if !filter.Matches(region) {
+			continue
+		}
+
+		err := scanner.ScanRange(sub_ctx, reader,
+			region.Address, region.Address+region.Size,
+			func(hit *Hit) bool {
+				row := ordereddict.NewDict().
+					Set("Pid", pid).
+					Set("Rule", hit.Rule).
+					Set("Category", hit.Category).
+					Set("Address", hit.Address).
+					Set("RegionOffset", hit.Address-region.Address).
+					Set("Data", string(hit.Data)).
+					Set("ContextAddress", hit.ContextAddress).
+					Set("HexContext", strings.Split(hex.Dump(hit.Context), "\n")).
+					Set("Region", region)
+
+				select {
+				case <-ctx.Done():
+					return false
+				case output_chan <- row:
+				}
+				return true
+			})
+
+		if errors.Is(err, budgetExceededError) {
+			scope.Log("proc_scan: pid %v: %v", pid, err)
+			return nil
+		}
+
+		// Scanning timed out but the query continues with the
+		// next process.
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			scope.Log("proc_scan: pid %v: Timed out after %v seconds",
+				pid, arg.Timeout)
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (self ProcScanPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:     "proc_scan",
+		Doc:      "Scan process memory for patterns from a rule pack.",
+		ArgType:  type_map.AddType(scope, &ProcScanPluginArgs{}),
+		Metadata: vql_subsystem.VQLMetadata().Permissions(acls.MACHINE_STATE).Build(),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&ProcScanPlugin{})
+}
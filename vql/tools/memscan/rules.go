@@ -0,0 +1,240 @@
+// Rule packs for the proc_scan() plugin.
+
+// A rule pack is a yaml file which is usually distributed to the
+// endpoint as a tool. Each rule is one of a regex, a string or a hex
+// pattern (with ?? wildcards):
+
+// name: Credentials
+// rules:
+//   - name: AWSAccessKey
+//     category: credential
+//     regex: 'AKIA[0-9A-Z]{16}'
+//   - name: DOSStub
+//     category: injected_pe
+//     string: This program cannot be run in DOS mode
+//   - name: CobaltStrikeConfig
+//     category: cobaltstrike
+//     hex: "00 01 00 01 00 02 ?? ?? 00 02 00 01 00 02"
+
+package memscan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/yaml/v2"
+)
+
+type RuleDefinition struct {
+	Name     string `yaml:"name"`
+	Category string `yaml:"category,omitempty"`
+
+	// Exactly one of these must be set.
+	Regex  string `yaml:"regex,omitempty"`
+	String string `yaml:"string,omitempty"`
+	Hex    string `yaml:"hex,omitempty"`
+
+	// Modifiers for string rules.
+	Wide   bool `yaml:"wide,omitempty"`
+	Nocase bool `yaml:"nocase,omitempty"`
+}
+
+type RulePackDefinition struct {
+	Name  string            `yaml:"name"`
+	Rules []*RuleDefinition `yaml:"rules"`
+}
+
+type Rule struct {
+	Name     string
+	Category string
+
+	// Returns the start and end offsets of all matches.
+	find func(buf []byte) [][2]int
+}
+
+type RulePack struct {
+	Name  string
+	Rules []*Rule
+}
+
+func ParseRulePack(data string) (*RulePack, error) {
+	definition := &RulePackDefinition{}
+	err := yaml.UnmarshalStrict([]byte(data), definition)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(definition.Rules) == 0 {
+		return nil, errors.New("Rule pack has no rules")
+	}
+
+	result := &RulePack{Name: definition.Name}
+	for idx, rule := range definition.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("Rule%d", idx)
+		}
+
+		find, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("Rule %v: %w", rule.Name, err)
+		}
+
+		result.Rules = append(result.Rules, &Rule{
+			Name:     rule.Name,
+			Category: rule.Category,
+			find:     find,
+		})
+	}
+
+	return result, nil
+}
+
+func compileRule(rule *RuleDefinition) (func(buf []byte) [][2]int, error) {
+	set := 0
+	for _, item := range []string{rule.Regex, rule.String, rule.Hex} {
+		if item != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, errors.New("Exactly one of regex, string or hex must be set")
+	}
+
+	switch {
+	case rule.Regex != "":
+		// Go regular expressions match UTF-8 text so they are
+		// only useful for ASCII patterns. Use hex rules for
+		// binary data.
+		re, err := regexp.Compile("(?s)" + rule.Regex)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(buf []byte) [][2]int {
+			var result [][2]int
+			for _, m := range re.FindAllIndex(buf, -1) {
+				result = append(result, [2]int{m[0], m[1]})
+			}
+			return result
+		}, nil
+
+	case rule.String != "":
+		pattern := &bytePattern{nocase: rule.Nocase}
+		for _, c := range []byte(rule.String) {
+			pattern.add(c, false)
+			if rule.Wide {
+				pattern.add(0, false)
+			}
+		}
+		return pattern.find, nil
+
+	default:
+		pattern, err := compileHex(rule.Hex)
+		if err != nil {
+			return nil, err
+		}
+		return pattern.find, nil
+	}
+}
+
+// Hex patterns are bytes separated by spaces with ?? matching any
+// byte.
+func compileHex(hex string) (*bytePattern, error) {
+	pattern := &bytePattern{}
+	for _, item := range strings.Fields(hex) {
+		if item == "??" {
+			pattern.add(0, true)
+			continue
+		}
+
+		value, err := strconv.ParseUint(item, 16, 8)
+		if err != nil || len(item) != 2 {
+			return nil, fmt.Errorf("Invalid hex byte %v", item)
+		}
+		pattern.add(byte(value), false)
+	}
+
+	if len(pattern.data) == 0 || pattern.anchor < 0 {
+		return nil, errors.New("Hex pattern must have at least one byte")
+	}
+
+	return pattern, nil
+}
+
+// A byte pattern with wildcards.
+type bytePattern struct {
+	data     []byte
+	wildcard []bool
+	nocase   bool
+
+	// The index of the first byte which is not a wildcard.
+	anchor int
+}
+
+func (self *bytePattern) add(c byte, wildcard bool) {
+	if len(self.data) == 0 {
+		self.anchor = -1
+	}
+	if !wildcard && self.anchor < 0 {
+		self.anchor = len(self.data)
+	}
+	self.data = append(self.data, c)
+	self.wildcard = append(self.wildcard, wildcard)
+}
+
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+func (self *bytePattern) matchAt(buf []byte, start int) bool {
+	for i, c := range self.data {
+		if self.wildcard[i] {
+			continue
+		}
+
+		other := buf[start+i]
+		if self.nocase {
+			c, other = lower(c), lower(other)
+		}
+		if c != other {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *bytePattern) find(buf []byte) [][2]int {
+	var result [][2]int
+
+	length := len(self.data)
+	for start := 0; start+length <= len(buf); {
+		// Skip quickly to the next candidate.
+		if !self.nocase {
+			idx := bytes.IndexByte(buf[start+self.anchor:],
+				self.data[self.anchor])
+			if idx < 0 {
+				break
+			}
+			start += idx
+			if start+length > len(buf) {
+				break
+			}
+		}
+
+		if self.matchAt(buf, start) {
+			result = append(result, [2]int{start, start + length})
+			start += length
+			continue
+		}
+		start++
+	}
+
+	return result
+}
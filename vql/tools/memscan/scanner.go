@@ -0,0 +1,133 @@
+package memscan
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+const (
+	// Matches may span block boundaries. Blocks overlap by this
+	// much so matches shorter than this are always found.
+	overlap = 4096
+)
+
+var (
+	budgetExceededError = errors.New("Scan budget exceeded")
+)
+
+type Hit struct {
+	Rule     string
+	Category string
+
+	// The address of the match in the process.
+	Address uint64
+	Data    []byte
+
+	// The data around the match and its address.
+	ContextAddress uint64
+	Context        []byte
+}
+
+type Options struct {
+	Blocksize uint64
+
+	// How many bytes to include around each hit.
+	Context int
+
+	// Stop after this many hits or bytes scanned. Zero means no
+	// limit.
+	MaxHits  int
+	MaxBytes uint64
+}
+
+type Scanner struct {
+	pack    *RulePack
+	options Options
+
+	scanned uint64
+	hits    int
+}
+
+func NewScanner(pack *RulePack, options Options) *Scanner {
+	if options.Blocksize == 0 {
+		options.Blocksize = 1024 * 1024
+	}
+	return &Scanner{pack: pack, options: options}
+}
+
+// Scan the range [start, end) of the reader. The callback may return
+// false to stop the scan.
+func (self *Scanner) ScanRange(ctx context.Context,
+	reader io.ReaderAt, start, end uint64, cb func(hit *Hit) bool) error {
+	buf := make([]byte, self.options.Blocksize+overlap)
+
+	for offset := start; offset < end; offset += self.options.Blocksize {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if self.options.MaxBytes > 0 && self.scanned >= self.options.MaxBytes {
+			return budgetExceededError
+		}
+
+		to_read := uint64(len(buf))
+		if offset+to_read > end {
+			to_read = end - offset
+		}
+
+		// Unreadable blocks (e.g. guard pages) are skipped so the
+		// rest of the range is still scanned.
+		n, _ := reader.ReadAt(buf[:to_read], int64(offset))
+		if n == 0 {
+			continue
+		}
+		data := buf[:n]
+		self.scanned += uint64(n)
+
+		// Matches starting in the overlap are found by the next
+		// block.
+		last := offset+uint64(n) >= end
+		for _, rule := range self.pack.Rules {
+			for _, m := range rule.find(data) {
+				if !last && uint64(m[0]) >= self.options.Blocksize {
+					continue
+				}
+
+				if !cb(self.makeHit(rule, data, offset, m)) {
+					return nil
+				}
+
+				self.hits++
+				if self.options.MaxHits > 0 && self.hits >= self.options.MaxHits {
+					return budgetExceededError
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *Scanner) makeHit(rule *Rule, data []byte,
+	offset uint64, m [2]int) *Hit {
+	context_start := m[0] - self.options.Context
+	if context_start < 0 {
+		context_start = 0
+	}
+
+	context_end := m[1] + self.options.Context
+	if context_end > len(data) {
+		context_end = len(data)
+	}
+
+	// Make copies as the buffer is reused.
+	return &Hit{
+		Rule:           rule.Name,
+		Category:       rule.Category,
+		Address:        offset + uint64(m[0]),
+		Data:           append([]byte{}, data[m[0]:m[1]]...),
+		ContextAddress: offset + uint64(context_start),
+		Context:        append([]byte{}, data[context_start:context_end]...),
+	}
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/artifacts"
+	"www.velocidex.com/golang/velociraptor/config"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+	"www.velocidex.com/golang/vfilter/arg_parser"
+)
+
+var (
+	// The client's communicator switches to the new frontends
+	// when they are sent on this channel.
+	ServerUrlsUpdate = make(chan []string)
+)
+
+type UpdateServerUrlsFunctionArgs struct {
+	Urls  []string `vfilter:"optional,field=urls,doc=The new list of frontend URLs (in order of preference if Client.ordered_server_urls is set)."`
+	Reset bool     `vfilter:"optional,field=reset,doc=Forget any previously pushed URLs and go back to the URLs in the client config."`
+}
+
+type UpdateServerUrlsFunction struct{}
+
+func (self *UpdateServerUrlsFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	arg := &UpdateServerUrlsFunctionArgs{}
+	err := arg_parser.ExtractArgsWithContext(ctx, scope, args, arg)
+	if err != nil {
+		scope.Log("update_server_urls: %v", err)
+		return vfilter.Null{}
+	}
+
+	// Pointing the client at another server is as powerful as
+	// running arbitrary code on it.
+	err = vql_subsystem.CheckAccess(scope, acls.EXECVE)
+	if err != nil {
+		scope.Log("update_server_urls: %v", err)
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := artifacts.GetConfig(scope)
+	if !ok || config_obj == nil {
+		scope.Log("update_server_urls: Must be running on a client")
+		return vfilter.Null{}
+	}
+
+	urls := arg.Urls
+	if arg.Reset {
+		urls = config_obj.ServerUrls
+	} else {
+		if len(urls) == 0 {
+			scope.Log("update_server_urls: No urls specified")
+			return vfilter.Null{}
+		}
+
+		for _, server_url := range urls {
+			parsed, err := url.Parse(server_url)
+			if err != nil || parsed.Host == "" ||
+				(parsed.Scheme != "https" && parsed.Scheme != "http") {
+				scope.Log("update_server_urls: Invalid url %v", server_url)
+				return vfilter.Null{}
+			}
+
+			if !strings.HasSuffix(server_url, "/") {
+				scope.Log("update_server_urls: Url %v must end with /",
+					server_url)
+				return vfilter.Null{}
+			}
+		}
+	}
+
+	writeback, err := config.GetWriteback(config_obj)
+	if err != nil {
+		scope.Log("update_server_urls: %v", err)
+		return vfilter.Null{}
+	}
+
+	// Persist the list so it survives a client restart.
+	writeback.ServerUrls = nil
+	if !arg.Reset {
+		writeback.ServerUrls = urls
+	}
+
+	err = config.UpdateWriteback(config_obj, writeback)
+	if err != nil {
+		scope.Log("update_server_urls: %v", err)
+		return vfilter.Null{}
+	}
+
+	// Switch the running client over. The response to this
+	// collection is sent to the new frontends. If the communicator
+	// is not running the new list is used when the client starts.
+	select {
+	case ServerUrlsUpdate <- urls:
+	default:
+	}
+
+	return urls
+}
+
+func (self UpdateServerUrlsFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "update_server_urls",
+		Doc:     "Update the list of frontends the client connects to. The new list is stored in the writeback file and takes precedence over the client config.",
+		ArgType: type_map.AddType(scope, &UpdateServerUrlsFunctionArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&UpdateServerUrlsFunction{})
+}
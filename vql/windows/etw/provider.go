@@ -0,0 +1,38 @@
+package etw
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	guidRegex = regexp.MustCompile(
+		`^\{?([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+			`[0-9a-fA-F]{4}-[0-9a-fA-F]{12})\}?$`)
+
+	invalidProviderError = errors.New("Invalid provider")
+)
+
+// Providers may be given either by GUID or by their registered name
+// (e.g. Microsoft-Windows-Kernel-Process). Returns the GUID in the
+// braced form if the provider is a GUID, otherwise the name to look
+// up.
+func parseProvider(provider string) (guid string, name string, err error) {
+	provider = strings.TrimSpace(provider)
+	if provider == "" {
+		return "", "", invalidProviderError
+	}
+
+	match := guidRegex.FindStringSubmatch(provider)
+	if match != nil {
+		return "{" + strings.ToUpper(match[1]) + "}", "", nil
+	}
+
+	// Names never contain braces so this is a malformed GUID.
+	if strings.ContainsAny(provider, "{}") {
+		return "", "", invalidProviderError
+	}
+
+	return "", provider, nil
+}
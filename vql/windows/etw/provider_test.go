@@ -0,0 +1,41 @@
+package etw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProvider(t *testing.T) {
+	for _, test_case := range []struct {
+		provider, guid, name string
+		err                  bool
+	}{
+		{provider: "{2A576B87-09A7-520E-C21A-4942F0271D67}",
+			guid: "{2A576B87-09A7-520E-C21A-4942F0271D67}"},
+
+		// Braces are optional and case is normalized.
+		{provider: " 2a576b87-09a7-520e-c21a-4942f0271d67 ",
+			guid: "{2A576B87-09A7-520E-C21A-4942F0271D67}"},
+
+		{provider: "Microsoft-Windows-Kernel-Process",
+			name: "Microsoft-Windows-Kernel-Process"},
+		{provider: " Microsoft-Antimalware-Scan-Interface ",
+			name: "Microsoft-Antimalware-Scan-Interface"},
+
+		// Malformed GUIDs are not looked up by name.
+		{provider: "{2A576B87-09A7-520E-C21A}", err: true},
+		{provider: "", err: true},
+		{provider: "   ", err: true},
+	} {
+		guid, name, err := parseProvider(test_case.provider)
+		if test_case.err {
+			assert.Error(t, err, test_case.provider)
+			continue
+		}
+
+		assert.NoError(t, err, test_case.provider)
+		assert.Equal(t, test_case.guid, guid, test_case.provider)
+		assert.Equal(t, test_case.name, name, test_case.provider)
+	}
+}
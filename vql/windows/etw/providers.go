@@ -0,0 +1,83 @@
+// +build windows,cgo
+
+package etw
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modtdh                    = windows.NewLazySystemDLL("tdh.dll")
+	procTdhEnumerateProviders = modtdh.NewProc("TdhEnumerateProviders")
+)
+
+// TRACE_PROVIDER_INFO
+type traceProviderInfo struct {
+	ProviderGuid       windows.GUID
+	SchemaSource       uint32
+	ProviderNameOffset uint32
+}
+
+// Resolve a provider GUID. Provider names are looked up in the
+// providers known to the system.
+func resolveProvider(provider string) (windows.GUID, error) {
+	guid, name, err := parseProvider(provider)
+	if err != nil {
+		return windows.GUID{}, fmt.Errorf("%w %v", err, provider)
+	}
+
+	if guid != "" {
+		return windows.GUIDFromString(guid)
+	}
+
+	buffer, err := enumerateProviders()
+	if err != nil {
+		return windows.GUID{}, err
+	}
+
+	// PROVIDER_ENUMERATION_INFO starts with the number of providers
+	// followed by a reserved ULONG and the array.
+	count := *(*uint32)(unsafe.Pointer(&buffer[0]))
+	info_size := unsafe.Sizeof(traceProviderInfo{})
+	for i := uintptr(0); i < uintptr(count); i++ {
+		offset := 8 + i*info_size
+		if offset+info_size > uintptr(len(buffer)) {
+			break
+		}
+
+		info := (*traceProviderInfo)(unsafe.Pointer(&buffer[offset]))
+		if uintptr(info.ProviderNameOffset) >= uintptr(len(buffer)) {
+			continue
+		}
+
+		provider_name := windows.UTF16PtrToString(
+			(*uint16)(unsafe.Pointer(&buffer[info.ProviderNameOffset])))
+		if strings.EqualFold(provider_name, name) {
+			return info.ProviderGuid, nil
+		}
+	}
+
+	return windows.GUID{}, fmt.Errorf("Unknown provider %v", name)
+}
+
+func enumerateProviders() ([]byte, error) {
+	size := uint32(64 * 1024)
+	for {
+		buffer := make([]byte, size)
+		r1, _, _ := procTdhEnumerateProviders.Call(
+			uintptr(unsafe.Pointer(&buffer[0])),
+			uintptr(unsafe.Pointer(&size)))
+		switch windows.Errno(r1) {
+		case windows.ERROR_SUCCESS:
+			return buffer[:size], nil
+		case windows.ERROR_INSUFFICIENT_BUFFER:
+			continue
+		default:
+			return nil, fmt.Errorf("TdhEnumerateProviders: %w", windows.Errno(r1))
+		}
+	}
+}
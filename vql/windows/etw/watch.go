@@ -24,7 +24,7 @@ var (
 
 type WatchETWArgs struct {
 	Name        string `vfilter:"optional,field=name,doc=A session name "`
-	Provider    string `vfilter:"required,field=guid,doc=A Provider GUID to watch "`
+	Provider    string `vfilter:"required,field=guid,doc=A Provider GUID or provider name to watch "`
 	AnyKeywords uint64 `vfilter:"optional,field=any,doc=Any Keywords "`
 	AllKeywords uint64 `vfilter:"optional,field=all,doc=All Keywords "`
 	Level       int64  `vfilter:"optional,field=level,doc=Log level (0-5)"`
@@ -59,7 +59,7 @@ func (self WatchETWPlugin) Call(
 			arg.Name = fmt.Sprintf("Velociraptor-%v", new_id)
 		}
 
-		guid, err := windows.GUIDFromString(arg.Provider)
+		guid, err := resolveProvider(arg.Provider)
 		if err != nil {
 			scope.Log("watch_etw: %s", err.Error())
 			return
@@ -6,6 +6,7 @@ import (
 	_ "www.velocidex.com/golang/velociraptor/accessors/data"
 	_ "www.velocidex.com/golang/velociraptor/accessors/file"
 	_ "www.velocidex.com/golang/velociraptor/accessors/file_store"
+	_ "www.velocidex.com/golang/velociraptor/accessors/memory"
 	_ "www.velocidex.com/golang/velociraptor/accessors/ntfs"
 	_ "www.velocidex.com/golang/velociraptor/accessors/offset"
 	_ "www.velocidex.com/golang/velociraptor/accessors/pipe"
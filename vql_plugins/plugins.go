@@ -27,6 +27,7 @@ import (
 	_ "www.velocidex.com/golang/velociraptor/vql/filesystem"
 	_ "www.velocidex.com/golang/velociraptor/vql/functions"
 	_ "www.velocidex.com/golang/velociraptor/vql/golang"
+	_ "www.velocidex.com/golang/velociraptor/vql/materializer"
 	_ "www.velocidex.com/golang/velociraptor/vql/networking"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/authenticode"
@@ -34,10 +35,15 @@ import (
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/csv"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/ese"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/event_logs"
+	_ "www.velocidex.com/golang/velociraptor/vql/parsers/journald"
+	_ "www.velocidex.com/golang/velociraptor/vql/parsers/macos"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/syslog"
 	_ "www.velocidex.com/golang/velociraptor/vql/parsers/usn"
+	_ "www.velocidex.com/golang/velociraptor/vql/parsers/utmp"
+	_ "www.velocidex.com/golang/velociraptor/vql/parsers/watchers"
 	_ "www.velocidex.com/golang/velociraptor/vql/protocols"
 	_ "www.velocidex.com/golang/velociraptor/vql/tools"
 	_ "www.velocidex.com/golang/velociraptor/vql/tools/collector"
+	_ "www.velocidex.com/golang/velociraptor/vql/tools/memscan"
 	_ "www.velocidex.com/golang/velociraptor/vql/tools/process"
 )
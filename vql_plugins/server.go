@@ -26,10 +26,12 @@ package plugins
 import (
 	_ "www.velocidex.com/golang/velociraptor/vql/server"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/clients"
+	_ "www.velocidex.com/golang/velociraptor/vql/server/content_packs"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/downloads"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/favorites"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/flows"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/hunts"
+	_ "www.velocidex.com/golang/velociraptor/vql/server/messaging"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/monitoring"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/notebooks"
 	_ "www.velocidex.com/golang/velociraptor/vql/server/orgs"